@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/bench"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	benchSynthetic string
+	benchDirs      string
+	benchDist      string
+	benchSeed      int64
+	benchRuns      int
+	benchDir       string
+	benchKeep      bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [path]",
+	Short: "Measure scan and index throughput",
+	Long: `Measure scanner and daemon indexing throughput on a directory tree.
+
+With --synthetic, a reproducible tree of sparse files is generated first, so
+results can be compared across releases and machines. Without it, the given
+path is benchmarked as is.
+
+Each benchmark runs --runs times; the median and fastest runs are reported.
+Use -o json to save results for later comparison.
+
+Examples:
+  sweep bench --synthetic 100k                # 100,000 files with mixed sizes
+  sweep bench --synthetic 1M --dist small     # 1,000,000 small files
+  sweep bench --runs 5 ~/Projects             # Benchmark an existing tree
+  sweep bench --synthetic 1M -o json > v1.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&benchSynthetic, "synthetic", "", "generate a synthetic tree with this many files (e.g., 100k, 1M)")
+	benchCmd.Flags().StringVar(&benchDirs, "dirs", "", "directories in the synthetic tree (default: one per 50 files)")
+	benchCmd.Flags().StringVar(&benchDist, "dist", bench.DefaultDistribution, "synthetic file size distribution (mixed, small, large)")
+	benchCmd.Flags().Int64Var(&benchSeed, "seed", 1, "random seed for the synthetic tree")
+	benchCmd.Flags().IntVar(&benchRuns, "runs", 3, "runs per benchmark")
+	benchCmd.Flags().StringVar(&benchDir, "dir", "", "directory to generate the synthetic tree in (default: the temporary directory)")
+	benchCmd.Flags().BoolVar(&benchKeep, "keep", false, "keep the synthetic tree afterwards")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// runBench runs the benchmarks and writes a report.
+func runBench(_ *cobra.Command, args []string) error {
+	if (len(args) == 0) == (benchSynthetic == "") {
+		return fmt.Errorf("give either a path or --synthetic <files>")
+	}
+	if benchSynthetic != "" && getReadOnly() {
+		return fmt.Errorf("--synthetic writes a tree of files: %w", errReadOnly)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report := bench.Report{
+		Version:  version,
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+		CPUs:     runtime.NumCPU(),
+	}
+
+	if benchSynthetic != "" {
+		root, cleanup, err := generateBenchTree(ctx, &report)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		report.Root = root
+	} else {
+		root, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		report.Root = root
+	}
+
+	benchmarks := append([]bench.Benchmark{scannerBenchmark()}, daemonBenchmarks()...)
+	for _, b := range benchmarks {
+		if !getQuiet() {
+			fmt.Fprintf(os.Stderr, "Running %s (%d runs)...\n", b.Name, max(benchRuns, 1))
+		}
+		result, err := bench.Measure(ctx, b, report.Root, benchRuns)
+		if err != nil {
+			return fmt.Errorf("%s: %w", b.Name, err)
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return bench.Write(os.Stdout, viper.GetString("output"), report)
+}
+
+// generateBenchTree creates the synthetic tree and returns its root and a
+// function that removes it unless --keep is set.
+func generateBenchTree(ctx context.Context, report *bench.Report) (string, func(), error) {
+	files, err := bench.ParseCount(benchSynthetic)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid --synthetic: %w", err)
+	}
+	var dirs int
+	if benchDirs != "" {
+		if dirs, err = bench.ParseCount(benchDirs); err != nil {
+			return "", nil, fmt.Errorf("invalid --dirs: %w", err)
+		}
+	}
+
+	// The tree goes in a directory of its own, so cleaning up never removes
+	// anything already in --dir
+	parent := benchDir
+	if parent != "" {
+		if parent, err = config.ExpandPath(parent); err != nil {
+			return "", nil, err
+		}
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return "", nil, err
+		}
+	}
+	root, err := os.MkdirTemp(parent, "sweep-bench-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		if benchKeep {
+			printInfo("Kept synthetic tree at %s", root)
+			return
+		}
+		_ = os.RemoveAll(root)
+	}
+
+	if !getQuiet() {
+		fmt.Fprintf(os.Stderr, "Generating %d files in %s...\n", files, root)
+	}
+	start := time.Now()
+	tree, err := bench.Generate(ctx, root, bench.Spec{
+		Files:        files,
+		Dirs:         dirs,
+		Distribution: benchDist,
+		Seed:         benchSeed,
+	})
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("generate synthetic tree: %w", err)
+	}
+	report.Tree = tree
+	report.Generate = time.Since(start)
+	return root, cleanup, nil
+}
+
+// scannerBenchmark measures a direct scan, as with --no-daemon.
+func scannerBenchmark() bench.Benchmark {
+	return bench.Benchmark{
+		Name: "scanner",
+		Run: func(ctx context.Context, root string) (bench.Counts, error) {
+			s := scanner.New(scanner.Options{
+				Root:        root,
+				MinSize:     10 * types.MiB, // The daemon's default large file threshold
+				DirWorkers:  config.DefaultDirWorkers,
+				FileWorkers: config.DefaultFileWorkers,
+			})
+			result, err := s.Scan(ctx)
+			if err != nil {
+				return bench.Counts{}, err
+			}
+			return bench.Counts{Files: result.FilesScanned, Dirs: result.DirsScanned}, nil
+		},
+	}
+}
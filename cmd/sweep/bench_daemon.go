@@ -0,0 +1,40 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/bench"
+)
+
+// daemonBenchmarks measures daemon indexing into a fresh store per run,
+// without a running daemon.
+func daemonBenchmarks() []bench.Benchmark {
+	return []bench.Benchmark{{
+		Name: "daemon-index",
+		Run: func(ctx context.Context, root string) (bench.Counts, error) {
+			dir, err := os.MkdirTemp("", "sweep-bench-index-")
+			if err != nil {
+				return bench.Counts{}, err
+			}
+			defer os.RemoveAll(dir)
+
+			st, err := store.Open(filepath.Join(dir, "index.db"))
+			if err != nil {
+				return bench.Counts{}, err
+			}
+			defer st.Close()
+
+			result, err := indexer.New(st).Index(ctx, root, nil)
+			if err != nil {
+				return bench.Counts{}, err
+			}
+			return bench.Counts{Files: result.FilesIndexed, Dirs: result.DirsIndexed}, nil
+		},
+	}}
+}
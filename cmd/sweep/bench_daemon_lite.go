@@ -0,0 +1,10 @@
+//go:build lite
+
+package main
+
+import "github.com/jamesainslie/sweep/pkg/sweep/bench"
+
+// daemonBenchmarks is empty in lite builds, which have no daemon.
+func daemonBenchmarks() []bench.Benchmark {
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/bench"
+)
+
+func TestGenerateBenchTreeKeepsDirContents(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(existing, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldSynthetic, oldDir, oldKeep := benchSynthetic, benchDir, benchKeep
+	t.Cleanup(func() { benchSynthetic, benchDir, benchKeep = oldSynthetic, oldDir, oldKeep })
+	benchSynthetic, benchDir, benchKeep = "20", dir, false
+
+	root, cleanup, err := generateBenchTree(context.Background(), &bench.Report{})
+	if err != nil {
+		t.Fatalf("generateBenchTree() error = %v", err)
+	}
+	if filepath.Dir(root) != dir {
+		t.Errorf("tree generated at %s, want a new directory in %s", root, dir)
+	}
+
+	cleanup()
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("synthetic tree %s still exists after cleanup", root)
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "keep me" {
+		t.Errorf("existing file in --dir = %q, %v; want it untouched", data, err)
+	}
+}
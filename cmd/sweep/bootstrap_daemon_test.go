@@ -0,0 +1,66 @@
+//go:build !lite
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+func TestEnsureDaemonAlreadyRunning(t *testing.T) {
+	// Create a temporary PID file with current process ID
+	tempDir := t.TempDir()
+	pidPath := filepath.Join(tempDir, "sweep.pid")
+
+	// Write current process PID to simulate running daemon
+	currentPID := os.Getpid()
+	err := os.WriteFile(pidPath, []byte(strconv.Itoa(currentPID)), 0644)
+	if err != nil {
+		t.Fatalf("failed to write PID file: %v", err)
+	}
+
+	// Should not return error when daemon is already running (idempotent)
+	paths := client.DaemonPaths{PID: pidPath}
+	err = client.EnsureDaemon(paths)
+	if err != nil {
+		t.Errorf("EnsureDaemon() returned error when daemon is running: %v", err)
+	}
+}
+
+func TestEnsureDaemonNoPIDFile(t *testing.T) {
+	// Create config with non-existent PID path
+	tempDir := t.TempDir()
+	pidPath := filepath.Join(tempDir, "nonexistent.pid")
+	socketPath := filepath.Join(tempDir, "sweep.sock")
+
+	paths := client.DaemonPaths{
+		PID:    pidPath,
+		Socket: socketPath,
+	}
+
+	// Should attempt to start daemon (but fail since sweepd isn't available in tests)
+	err := client.EnsureDaemon(paths)
+	// We expect an error since sweepd binary won't be found in test environment
+	if err == nil {
+		t.Log("EnsureDaemon() succeeded - sweepd binary was found")
+	} else {
+		t.Logf("EnsureDaemon() returned expected error (sweepd not found): %v", err)
+	}
+}
+
+func TestEnsureDaemonUsesDefaults(t *testing.T) {
+	// Create paths with empty values (should use defaults)
+	paths := client.DaemonPaths{}
+
+	// Should use default paths
+	err := client.EnsureDaemon(paths)
+	// We just want to ensure it doesn't panic and handles defaults correctly
+	// Error or success both acceptable depending on environment
+	if err != nil {
+		t.Logf("EnsureDaemon() returned error (expected in test environment): %v", err)
+	}
+}
@@ -1,14 +1,11 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/adrg/xdg"
-	"github.com/jamesainslie/sweep/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -49,18 +46,7 @@ The next scan will perform a full directory traversal.`,
 			}
 		}
 
-		if client.IsDaemonRunning(client.DefaultPIDPath()) {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			daemonClient, err := client.ConnectWithContext(ctx, client.DefaultSocketPath())
-			if err == nil {
-				defer daemonClient.Close()
-				if _, err := daemonClient.ClearCache(ctx, clearPath); err == nil {
-					clearedDaemon = true
-				}
-			}
-		}
+		clearedDaemon = clearDaemonCache(clearPath)
 
 		if clearedLocal || clearedDaemon {
 			if clearPath != "" {
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/budget"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	checkMaxSize  string
+	checkMaxFiles int64
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path...]",
+	Short: "Check directories against storage budgets",
+	Long: `Check directories against storage budgets and exit non-zero if any is exceeded.
+
+With paths, each path is checked against --max-size and --max-files.
+Without paths, the budgets defined under 'budgets' in the config file are checked.
+
+Reports can be written in formats understood by CI systems with -o:
+  text   Human-readable table (default)
+  json   JSON array of results
+  junit  JUnit XML; each budget is a test case
+  sarif  SARIF 2.1.0; each violation is an annotation
+
+Examples:
+  sweep check --max-size 500M dist            # Fail if dist/ exceeds 500MB
+  sweep check -o junit > budgets.xml          # Config budgets as a JUnit report
+  sweep check -o sarif --max-files 10000 out  # File count budget as SARIF`,
+	RunE: runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&checkMaxSize, "max-size", "", "maximum total size per path (e.g., 500M, 2G)")
+	checkCmd.Flags().Int64Var(&checkMaxFiles, "max-files", 0, "maximum number of files per path (0 for unlimited)")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// runCheck evaluates storage budgets and writes a report.
+func runCheck(cmd *cobra.Command, args []string) error {
+	budgets, err := checkBudgets(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	results := budget.Check(ctx, budgets)
+
+	format := viper.GetString("output")
+	if err := budget.Write(os.Stdout, format, results); err != nil {
+		return err
+	}
+
+	if failed := budget.Failures(results); failed > 0 {
+		return fmt.Errorf("%d of %d storage budget(s) exceeded", failed, len(results))
+	}
+	return nil
+}
+
+// checkBudgets builds budgets from the command line, or from the config file
+// when no paths are given.
+func checkBudgets(args []string) ([]budget.Budget, error) {
+	if len(args) > 0 {
+		if checkMaxSize == "" && checkMaxFiles == 0 {
+			return nil, fmt.Errorf("--max-size or --max-files is required when paths are given")
+		}
+		var maxSize int64
+		if checkMaxSize != "" {
+			size, err := types.ParseSize(checkMaxSize)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max-size %q: %w", checkMaxSize, err)
+			}
+			maxSize = size
+		}
+		budgets := make([]budget.Budget, 0, len(args))
+		for _, arg := range args {
+			path, err := config.ExpandPath(arg)
+			if err != nil {
+				return nil, err
+			}
+			budgets = append(budgets, budget.Budget{
+				Path:     filepath.Clean(path),
+				MaxSize:  maxSize,
+				MaxFiles: checkMaxFiles,
+			})
+		}
+		return budgets, nil
+	}
+
+	var configured []config.BudgetConfig
+	if err := viper.UnmarshalKey("budgets", &configured); err != nil {
+		return nil, fmt.Errorf("invalid budgets in config: %w", err)
+	}
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no paths given and no budgets configured (see 'budgets' in the config file)")
+	}
+
+	budgets := make([]budget.Budget, 0, len(configured))
+	for _, bc := range configured {
+		b := budget.Budget{Name: bc.Name, MaxFiles: bc.MaxFiles}
+		if bc.Path != "" {
+			path, err := config.ExpandPath(bc.Path)
+			if err != nil {
+				return nil, err
+			}
+			b.Path = filepath.Clean(path)
+		}
+		if bc.MaxSize != "" {
+			size, err := types.ParseSize(bc.MaxSize)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max_size %q for budget %q: %w", bc.MaxSize, b.DisplayName(), err)
+			}
+			b.MaxSize = size
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCheckBudgetsFromArgs(t *testing.T) {
+	checkMaxSize, checkMaxFiles = "1M", 10
+	t.Cleanup(func() { checkMaxSize, checkMaxFiles = "", 0 })
+
+	budgets, err := checkBudgets([]string{"dist/", "out"})
+	if err != nil {
+		t.Fatalf("checkBudgets() error = %v", err)
+	}
+	if len(budgets) != 2 || budgets[0].Path != "dist" || budgets[0].MaxSize != 1<<20 || budgets[1].MaxFiles != 10 {
+		t.Errorf("unexpected budgets: %+v", budgets)
+	}
+}
+
+func TestCheckBudgetsRequiresLimit(t *testing.T) {
+	if _, err := checkBudgets([]string{"dist"}); err == nil {
+		t.Error("expected error without --max-size or --max-files")
+	}
+}
+
+func TestCheckBudgetsFromConfig(t *testing.T) {
+	viper.Set("budgets", []map[string]interface{}{
+		{"name": "artifacts", "path": "build", "max_size": "2G"},
+		{"path": "cache", "max_files": 5},
+	})
+	t.Cleanup(func() { viper.Set("budgets", nil) })
+
+	budgets, err := checkBudgets(nil)
+	if err != nil {
+		t.Fatalf("checkBudgets() error = %v", err)
+	}
+	if len(budgets) != 2 || budgets[0].Name != "artifacts" || budgets[0].MaxSize != 2<<30 || budgets[1].MaxFiles != 5 {
+		t.Errorf("unexpected budgets: %+v", budgets)
+	}
+}
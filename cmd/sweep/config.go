@@ -50,8 +50,11 @@ If the config file doesn't exist, a default one will be created first.`,
 var configInitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Create default configuration file",
-	Long:  `Create a default configuration file if one doesn't exist.`,
-	RunE:  runConfigInit,
+	Long: `Create a default configuration file if one doesn't exist.
+
+With --interactive, ask which directories to index, suggest exclusions, and
+choose whether the daemon starts automatically, as on first run.`,
+	RunE: runConfigInit,
 }
 
 var configPathCmd = &cobra.Command{
@@ -66,6 +69,8 @@ func init() {
 	configCmd.AddCommand(configEditCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configPathCmd)
+
+	configInitCmd.Flags().BoolP("interactive", "i", false, "answer setup questions instead of writing defaults")
 	rootCmd.AddCommand(configCmd)
 }
 
@@ -80,6 +85,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 			MinSize:     config.DefaultMinSize,
 			DefaultPath: config.DefaultPath,
 			Exclude:     config.DefaultExclusions,
+			Symlinks:    "skip",
 		}
 		cfg.Workers.Dir = config.DefaultDirWorkers
 		cfg.Workers.File = config.DefaultFileWorkers
@@ -101,6 +107,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("min_size:             %s\n", cfg.MinSize)
 	fmt.Printf("default_path:         %s\n", cfg.DefaultPath)
 	fmt.Printf("exclude:              %v\n", cfg.Exclude)
+	fmt.Printf("symlinks:             %s\n", cfg.Symlinks)
 	fmt.Printf("workers.dir:          %d\n", cfg.Workers.Dir)
 	fmt.Printf("workers.file:         %d\n", cfg.Workers.File)
 	fmt.Printf("manifest.enabled:     %t\n", cfg.Manifest.Enabled)
@@ -117,6 +124,7 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		{"SWEEP_MIN_SIZE", "min_size"},
 		{"SWEEP_DEFAULT_PATH", "default_path"},
 		{"SWEEP_EXCLUDE", "exclude"},
+		{"SWEEP_SYMLINKS", "symlinks"},
 		{"SWEEP_WORKERS_DIR", "workers.dir"},
 		{"SWEEP_WORKERS_FILE", "workers.file"},
 		{"SWEEP_MANIFEST_ENABLED", "manifest.enabled"},
@@ -191,6 +199,10 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+		return runOnboarding()
+	}
+
 	// Create default config
 	if err := config.WriteDefault(); err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
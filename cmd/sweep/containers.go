@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+)
+
+// defaultContainerRoot is Docker's default storage directory.
+const defaultContainerRoot = "/var/lib/docker"
+
+var containersCmd = &cobra.Command{
+	Use:   "containers [path]",
+	Short: "Summarize container layer and volume usage",
+	Long: `Summarize disk usage of container storage per overlay layer and per volume.
+
+Overlay layers shared by several containers or images are counted once, and
+bind mounts are listed with the source directory they expose. Regular scans
+already skip these duplicate views; this command shows where the space goes.
+
+The path defaults to /var/lib/docker.
+
+Examples:
+  sweep containers                          # Docker storage on this host
+  sweep containers /var/lib/containers      # Podman storage`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runContainers,
+}
+
+func init() {
+	rootCmd.AddCommand(containersCmd)
+}
+
+// layerUsage describes one overlay layer directory.
+type layerUsage struct {
+	Path  string
+	Size  int64
+	Users int // Number of mounted overlays using this layer
+}
+
+// volumeUsage describes one volume or bind-mounted directory.
+type volumeUsage struct {
+	Name   string
+	Source string
+	Size   int64
+}
+
+// runContainers prints per-layer and per-volume usage.
+func runContainers(cmd *cobra.Command, args []string) error {
+	root := defaultContainerRoot
+	if len(args) > 0 {
+		root = args[0]
+	}
+	expanded, err := config.ExpandPath(root)
+	if err != nil {
+		return fmt.Errorf("failed to expand path: %w", err)
+	}
+	root, err = filepath.Abs(expanded)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	table, err := mounts.Load()
+	if err != nil {
+		printVerbose("Failed to read mount table: %v", err)
+	}
+
+	if mounts.InContainer() {
+		printInfo("Running inside a container")
+	}
+
+	layers := collectLayers(root, table)
+	volumes := collectVolumes(root, table)
+
+	if len(layers) == 0 && len(volumes) == 0 {
+		printInfo("No container layers or volumes found under %s", root)
+		return nil
+	}
+
+	var layerTotal int64
+	if len(layers) > 0 {
+		fmt.Printf("\n%-12s  %-6s  %s\n", "SIZE", "USED", "LAYER")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, l := range layers {
+			fmt.Printf("%-12s  %-6d  %s\n", types.FormatSize(l.Size), l.Users, l.Path)
+			layerTotal += l.Size
+		}
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-12s  %d layer(s)\n", types.FormatSize(layerTotal), len(layers))
+	}
+
+	var volumeTotal int64
+	if len(volumes) > 0 {
+		fmt.Printf("\n%-12s  %-30s  %s\n", "SIZE", "VOLUME", "SOURCE")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, v := range volumes {
+			fmt.Printf("%-12s  %-30s  %s\n", types.FormatSize(v.Size), truncateString(v.Name, 30), v.Source)
+			volumeTotal += v.Size
+		}
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-12s  %d volume(s)\n", types.FormatSize(volumeTotal), len(volumes))
+	}
+
+	fmt.Printf("\nTotal: %s (each layer and volume counted once)\n", types.FormatSize(layerTotal+volumeTotal))
+	return nil
+}
+
+// collectLayers gathers overlay layers from mounted overlays and from the
+// overlay2 storage directory under root, keyed by their resolved path so a
+// layer shared by several containers is only measured once.
+func collectLayers(root string, table mounts.Table) []layerUsage {
+	byPath := make(map[string]*layerUsage)
+	add := func(dir string) *layerUsage {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+		l, ok := byPath[dir]
+		if !ok {
+			l = &layerUsage{Path: dir}
+			byPath[dir] = l
+		}
+		return l
+	}
+
+	for _, o := range table.Overlays() {
+		for _, dir := range o.Dirs() {
+			if dir == "" || !isWithin(root, dir) {
+				continue
+			}
+			add(dir).Users++
+		}
+	}
+
+	// Unmounted layers (stopped containers, image layers) still use space.
+	diffs, _ := filepath.Glob(filepath.Join(root, "overlay2", "*", "diff"))
+	for _, dir := range diffs {
+		add(dir)
+	}
+
+	layers := make([]layerUsage, 0, len(byPath))
+	for _, l := range byPath {
+		l.Size = dirUsage(l.Path)
+		layers = append(layers, *l)
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		if layers[i].Size != layers[j].Size {
+			return layers[i].Size > layers[j].Size
+		}
+		return layers[i].Path < layers[j].Path
+	})
+	return layers
+}
+
+// collectVolumes gathers named volumes under root and bind mounts whose
+// source is under root.
+func collectVolumes(root string, table mounts.Table) []volumeUsage {
+	seen := make(map[string]bool)
+	var volumes []volumeUsage
+
+	data, _ := filepath.Glob(filepath.Join(root, "volumes", "*", "_data"))
+	for _, dir := range data {
+		seen[dir] = true
+		volumes = append(volumes, volumeUsage{
+			Name:   filepath.Base(filepath.Dir(dir)),
+			Source: dir,
+			Size:   dirUsage(dir),
+		})
+	}
+
+	for _, m := range table.Binds() {
+		if seen[m.MountPoint] || !isWithin(root, m.MountPoint) {
+			continue
+		}
+		seen[m.MountPoint] = true
+		volumes = append(volumes, volumeUsage{
+			Name:   m.MountPoint,
+			Source: m.Source + ":" + m.Root,
+			Size:   dirUsage(m.MountPoint),
+		})
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		if volumes[i].Size != volumes[j].Size {
+			return volumes[i].Size > volumes[j].Size
+		}
+		return volumes[i].Name < volumes[j].Name
+	})
+	return volumes
+}
+
+// dirUsage returns the total size of regular files under dir.
+// Unreadable entries are skipped.
+func dirUsage(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// isWithin reports whether path is root itself or beneath it.
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+func writeSized(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectLayersCountsSharedLayerOnce(t *testing.T) {
+	root := t.TempDir()
+	writeSized(t, filepath.Join(root, "overlay2", "base", "diff", "bin"), 1000)
+	writeSized(t, filepath.Join(root, "overlay2", "c1", "diff", "log"), 10)
+	writeSized(t, filepath.Join(root, "overlay2", "c2", "diff", "log"), 20)
+
+	// Short symlinked names, as Docker uses for lowerdir.
+	if err := os.MkdirAll(filepath.Join(root, "overlay2", "l"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../base/diff", filepath.Join(root, "overlay2", "l", "BASE")); err != nil {
+		t.Fatal(err)
+	}
+
+	overlay := func(id string) mounts.Mount {
+		return mounts.Mount{
+			Root:       "/",
+			MountPoint: filepath.Join(root, "overlay2", id, "merged"),
+			FSType:     mounts.FSTypeOverlay,
+			Options: map[string]string{
+				"lowerdir": "l/BASE",
+				"upperdir": filepath.Join(root, "overlay2", id, "diff"),
+			},
+		}
+	}
+	table := mounts.Table{overlay("c1"), overlay("c2")}
+
+	layers := collectLayers(root, table)
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %d: %+v", len(layers), layers)
+	}
+	if layers[0].Size != 1000 || layers[0].Users != 2 {
+		t.Errorf("expected shared base layer first with 2 users, got %+v", layers[0])
+	}
+}
+
+func TestCollectVolumes(t *testing.T) {
+	root := t.TempDir()
+	writeSized(t, filepath.Join(root, "volumes", "db", "_data", "table"), 500)
+	writeSized(t, filepath.Join(root, "volumes", "cache", "_data", "blob"), 50)
+
+	volumes := collectVolumes(root, nil)
+	if len(volumes) != 2 {
+		t.Fatalf("expected 2 volumes, got %d", len(volumes))
+	}
+	if volumes[0].Name != "db" || volumes[0].Size != 500 {
+		t.Errorf("unexpected first volume: %+v", volumes[0])
+	}
+}
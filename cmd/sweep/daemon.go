@@ -1,15 +1,20 @@
+//go:build !lite
+
 package main
 
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jamesainslie/sweep/pkg/client"
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // daemonPaths returns DaemonPaths from the current config.
@@ -26,6 +31,31 @@ func daemonPaths() client.DaemonPaths {
 	}
 }
 
+// daemonTarget returns the daemon to talk to: a remote daemon when --remote
+// or remote.address is set, otherwise the local one from the config.
+func daemonTarget() client.Target {
+	paths := daemonPaths()
+	return client.Target{
+		Socket:  paths.Socket,
+		PID:     paths.PID,
+		Remote:  getRemoteConfig(),
+		Timeout: getClientTimeout(),
+	}
+}
+
+// connectDaemon connects to the running daemon.
+func connectDaemon(ctx context.Context) (*client.Client, client.Target, error) {
+	target := daemonTarget()
+	if !target.Running() {
+		return nil, target, errDaemonNotRunning
+	}
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return nil, target, fmt.Errorf("connect to daemon: %w", err)
+	}
+	return daemonClient, target, nil
+}
+
 var daemonCmd = &cobra.Command{
 	Use:   "daemon",
 	Short: "Manage the sweepd daemon",
@@ -57,10 +87,19 @@ var daemonRestartCmd = &cobra.Command{
 }
 
 var daemonStatusCmd = &cobra.Command{
-	Use:   "status",
+	Use:   "status [path]",
 	Short: "Show daemon status",
-	Long:  `Show the current status of the sweepd daemon.`,
-	RunE:  runDaemonStatus,
+	Long: `Show the current status of the sweepd daemon: its version, uptime, the
+roots it indexed with their state, size, and age, the watcher and index
+store, and its recent errors. With a path, also show the state of that
+path's index and the mode it was built in.
+
+With -o json, print the status as JSON for monitoring scripts. Its fields
+may be added to in later versions but are not renamed or removed.`,
+	Example: `  sweep daemon status
+  sweep daemon status -o json | jq '.roots[] | select(.state != "ready")'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDaemonStatus,
 }
 
 var daemonIndexCmd = &cobra.Command{
@@ -79,6 +118,20 @@ var daemonClearCmd = &cobra.Command{
 	RunE:  runDaemonClear,
 }
 
+var daemonLogrotateCmd = &cobra.Command{
+	Use:   "logrotate",
+	Short: "Make the daemon reopen its log file",
+	Long: `Send SIGHUP to the daemon so it reopens its log file.
+
+Use this from a logrotate postrotate script after the log has been moved:
+
+  postrotate
+      sweep daemon logrotate
+  endscript`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonLogrotate,
+}
+
 func init() {
 	rootCmd.AddCommand(daemonCmd)
 	daemonCmd.AddCommand(daemonStartCmd)
@@ -87,6 +140,7 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonIndexCmd)
 	daemonCmd.AddCommand(daemonClearCmd)
+	daemonCmd.AddCommand(daemonLogrotateCmd)
 
 	// Flags for index command
 	daemonIndexCmd.Flags().BoolP("force", "f", false, "Force re-indexing even if already indexed")
@@ -116,29 +170,41 @@ func runDaemonRestart(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
-func runDaemonStatus(_ *cobra.Command, _ []string) error {
-	paths := daemonPaths()
-	pidPath := paths.PID
-	socketPath := paths.Socket
-	if pidPath == "" {
-		pidPath = client.DefaultPIDPath()
+func runDaemonStatus(_ *cobra.Command, args []string) error {
+	format := viper.GetString("output")
+	switch format {
+	case "json", "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
 	}
-	if socketPath == "" {
-		socketPath = client.DefaultSocketPath()
+	target := daemonTarget()
+	report := daemonStatusReport{}
+	if target.IsRemote() {
+		report.Remote = target.Remote.Address
 	}
 
 	// Check if running
-	if !client.IsDaemonRunning(pidPath) {
+	if !target.Running() {
+		if format == "json" {
+			return writeDaemonStatusJSON(os.Stdout, report)
+		}
 		printInfo("Daemon status: not running")
 		return nil
 	}
+	report.Running = true
 
 	// Connect and get status
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	daemonClient, err := client.ConnectWithContext(ctx, socketPath)
+	daemonClient, err := target.Connect(ctx)
 	if err != nil {
+		if target.IsRemote() {
+			return err
+		}
+		if format == "json" {
+			return writeDaemonStatusJSON(os.Stdout, report)
+		}
 		printInfo("Daemon status: running (but not responding)")
 		return nil
 	}
@@ -149,20 +215,135 @@ func runDaemonStatus(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("get daemon status: %w", err)
 	}
 
-	printInfo("Daemon status: running")
+	var idx *client.IndexStatus
+	if len(args) > 0 {
+		path := args[0]
+		if !target.IsRemote() {
+			if path, err = filepath.Abs(path); err != nil {
+				return fmt.Errorf("resolve path: %w", err)
+			}
+		}
+		if idx, err = daemonClient.GetIndexStatus(ctx, path); err != nil {
+			return fmt.Errorf("get index status: %w", err)
+		}
+	}
+
+	if format == "json" {
+		remote := report.Remote
+		report = newDaemonStatusReport(status, time.Now())
+		report.Remote = remote
+		if idx != nil {
+			report.Index = &indexReport{Path: idx.Path, State: idx.State, Mode: idx.Mode, Files: idx.FilesIndexed, Dirs: idx.DirsIndexed}
+		}
+		return writeDaemonStatusJSON(os.Stdout, report)
+	}
+	printDaemonStatus(target, status, time.Now())
+	if idx == nil {
+		return nil
+	}
+	if idx.Mode == "" {
+		printInfo("Index of %s: %s", idx.Path, strings.ReplaceAll(idx.State, "_", " "))
+		return nil
+	}
+	printInfo("Index of %s: %s (%s mode, %d files, %d dirs)", idx.Path, idx.State, idx.Mode, idx.FilesIndexed, idx.DirsIndexed)
+	return nil
+}
+
+// printDaemonStatus prints the status of a daemon that responded, as of now.
+func printDaemonStatus(target client.Target, status *client.DaemonStatus, now time.Time) {
+	running := "running"
+	if target.IsRemote() {
+		running += " on " + target.Remote.Address
+	}
+	if status.Version != "" {
+		running += " (" + status.Version + ")"
+	}
+	printInfo("Daemon status: %s", running)
 	printInfo("  Uptime: %s", formatDuration(time.Duration(status.UptimeSeconds)*time.Second))
 	printInfo("  Memory: %s", types.FormatSize(status.MemoryBytes))
 	printInfo("  Cache size: %s", types.FormatSize(status.CacheSizeBytes))
 	printInfo("  Files indexed: %d", status.TotalFilesIndexed)
+	if status.IndexMode != "" {
+		printInfo("  Index mode: %s", status.IndexMode)
+	}
+	if st := status.Store; st.Backend != "" {
+		store := fmt.Sprintf("%s, schema %d", st.Backend, st.SchemaVersion)
+		if st.Migrating {
+			store += ", migrating"
+		}
+		if !st.LastCompacted.IsZero() {
+			store += ", compacted " + formatDuration(now.Sub(st.LastCompacted)) + " ago"
+		}
+		printInfo("  Store: %s", store)
+	}
+	if w := status.Watcher; w.Directories > 0 || w.Events > 0 {
+		printInfo("  Watcher: %d directories, %d events, %d errors", w.Directories, w.Events, w.Errors)
+	}
+
+	if hw := status.HashWarmer; hw.Enabled {
+		state := "idle"
+		if hw.Active {
+			state = "hashing " + hw.CurrentPath
+		}
+		printInfo("  Hash warmer: %s (%d hashed, %s read, %d queued)",
+			state, hw.FilesHashed, types.FormatSize(hw.BytesHashed), hw.Queued)
+	}
 
-	if len(status.WatchedPaths) > 0 {
+	switch {
+	case len(status.Roots) > 0:
+		printInfo("  Roots:")
+		for _, r := range status.Roots {
+			printInfo("    - %s: %s", r.Path, describeRoot(r, now))
+		}
+	case len(status.WatchedPaths) > 0:
+		// A daemon that predates reporting roots
 		printInfo("  Watched paths:")
 		for _, p := range status.WatchedPaths {
 			printInfo("    - %s", p)
 		}
 	}
 
-	return nil
+	if len(status.RecentErrors) > 0 {
+		printInfo("  Recent errors:")
+		for _, e := range status.RecentErrors[max(len(status.RecentErrors)-maxStatusErrors, 0):] {
+			msg := e.Message
+			if e.Path != "" {
+				msg = e.Path + ": " + msg
+			}
+			printInfo("    - %s ago, %s: %s", formatDuration(now.Sub(e.Time)), e.Component, msg)
+		}
+	}
+}
+
+// maxStatusErrors is how many of the daemon's recent errors 'sweep daemon
+// status' prints; -o json has them all.
+const maxStatusErrors = 5
+
+// describeRoot summarizes a root's state for 'sweep daemon status'.
+func describeRoot(r client.RootStatus, now time.Time) string {
+	desc := strings.ReplaceAll(r.State, "_", " ")
+	if r.State == "indexing" && r.Progress > 0 {
+		desc += fmt.Sprintf(" %.0f%%", r.Progress*100)
+	}
+	if r.Queued {
+		desc += ", queued to index"
+	}
+	if r.FilesIndexed > 0 {
+		desc += fmt.Sprintf(", %d files", r.FilesIndexed)
+	}
+	if r.TotalSize > 0 {
+		desc += ", " + types.FormatSize(r.TotalSize)
+	}
+	if !r.LastIndexed.IsZero() {
+		desc += ", indexed " + formatDuration(now.Sub(r.LastIndexed)) + " ago"
+	}
+	if r.Paused {
+		desc += ", paused"
+	}
+	if r.Error != "" {
+		desc += " (" + r.Error + ")"
+	}
+	return desc
 }
 
 func runDaemonIndex(cmd *cobra.Command, args []string) error {
@@ -244,6 +425,14 @@ func runDaemonClear(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+func runDaemonLogrotate(_ *cobra.Command, _ []string) error {
+	if err := client.ReopenDaemonLogs(daemonPaths()); err != nil {
+		return err
+	}
+	printInfo("Daemon log file reopened")
+	return nil
+}
+
 // formatDuration formats a duration in a human-readable way.
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
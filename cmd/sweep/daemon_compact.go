@@ -0,0 +1,52 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+)
+
+var daemonCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Drop stale records from the daemon's index store",
+	Long: `Drop records the daemon's index store no longer needs, then rewrite the
+store to free the space they took on disk. Stale records are entries left
+by paths no longer indexed, such as removed watches, and cached hashes of
+files that changed or went away since they were hashed.
+
+The daemon also compacts its store every daemon.compact_interval (24h by
+default). Compacting is refused while a path is being indexed.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonCompact,
+}
+
+// compactTimeout bounds 'sweep daemon compact'. Rewriting the store reads
+// all of it, which takes a while on large indexes.
+const compactTimeout = 30 * time.Minute
+
+func init() {
+	daemonCmd.AddCommand(daemonCompactCmd)
+}
+
+func runDaemonCompact(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), compactTimeout)
+	defer cancel()
+	daemonClient, _, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	res, err := daemonClient.CompactStore(ctx)
+	if err != nil {
+		return fmt.Errorf("compact index store: %w", err)
+	}
+	printInfo("Dropped %d stale records; index store %s → %s (%s reclaimed)",
+		res.Pruned, types.FormatSize(res.SizeBefore), types.FormatSize(res.SizeAfter), types.FormatSize(res.Reclaimed()))
+	return nil
+}
@@ -0,0 +1,121 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var daemonHashXXHash bool
+
+var daemonHashCmd = &cobra.Command{
+	Use:   "hash <file>...",
+	Short: "Hash files on the daemon and cache the hashes",
+	Long: `Have the daemon compute the SHA-256 and xxhash of files, printing one line
+per file in the format of sha256sum as each completes.
+
+The daemon hashes daemon.hash_workers files at once (4 by default, or the
+number of CPUs if fewer) and caches the hashes in its index until a file's
+size or modification time changes, so hashing a file again, and finding its
+duplicates, doesn't read it again.
+
+Examples:
+  sweep daemon hash ~/Downloads/*.iso
+  sweep daemon hash --xxhash big.bin     # Print the xxhash instead
+  sweep daemon hash -o json a.bin b.bin`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDaemonHash,
+}
+
+func init() {
+	daemonHashCmd.Flags().BoolVar(&daemonHashXXHash, "xxhash", false, "print the xxhash instead of the SHA-256")
+	daemonCmd.AddCommand(daemonHashCmd)
+}
+
+// hashReport is a file's hashes in 'sweep daemon hash -o json'.
+type hashReport struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	XXHash string `json:"xxhash,omitempty"`
+	Cached bool   `json:"cached"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runDaemonHash(_ *cobra.Command, args []string) error {
+	format := viper.GetString("output")
+	switch format {
+	case "json", "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
+	}
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path, err := config.ExpandPath(arg)
+		if err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	daemonClient, _, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	reports := []hashReport{}
+	failed := 0
+	err = daemonClient.ComputeHashes(ctx, paths, func(h client.FileHash) {
+		r := hashReport{Path: h.Path, Size: h.Size, Cached: h.Cached}
+		if h.Err != nil {
+			r.Error = h.Err.Error()
+			failed++
+		} else {
+			r.SHA256, r.XXHash = hex.EncodeToString(h.SHA256), fmt.Sprintf("%016x", h.XXHash)
+		}
+		if format == "json" {
+			reports = append(reports, r)
+			return
+		}
+		switch {
+		case h.Err != nil:
+			printError("%v", h.Err)
+		case daemonHashXXHash:
+			fmt.Printf("%s  %s\n", r.XXHash, h.Path)
+		default:
+			fmt.Printf("%s  %s\n", r.SHA256, h.Path)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("hash files: %w", err)
+	}
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files could not be hashed", failed, len(paths))
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/service"
+	"github.com/spf13/cobra"
+)
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Run the daemon as a service started at login",
+	Long: `Install sweepd as a user service, so it starts at login and is restarted
+if it crashes: a launchd agent on macOS
+(~/Library/LaunchAgents/io.github.jamesainslie.sweepd.plist), or a systemd
+user unit on Linux (~/.config/systemd/user/sweepd.service). The service is
+loaded right away, replacing a daemon already running.
+
+The service runs the sweepd binary 'sweep daemon start' would, with the
+XDG directory variables set now, so it uses the same config, index, and log
+as sweep. sweepd logs to its usual log file; on macOS its other output goes
+to sweepd.out.log beside it, and on Linux to the journal
+(journalctl --user -u sweepd). Stopping it with 'sweep daemon stop' does
+not make the service restart it.
+
+Run install again after moving sweepd or changing XDG variables.`,
+	Example: `  sweep daemon install
+  sweep daemon install --print > sweepd.service`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop running the daemon as a service",
+	Long: `Unload the sweepd service installed by 'sweep daemon install', which stops
+the daemon, and remove its launchd agent or systemd unit. The daemon's
+index and config are kept.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonUninstall,
+}
+
+// serviceTimeout bounds loading and unloading the service.
+const serviceTimeout = 30 * time.Second
+
+func init() {
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+
+	daemonInstallCmd.Flags().Bool("print", false, "Print the launchd plist or systemd unit instead of installing it")
+}
+
+func runDaemonInstall(cmd *cobra.Command, _ []string) error {
+	svc, err := daemonService()
+	if err != nil {
+		return err
+	}
+
+	if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+		_, err := os.Stdout.Write(svc.Definition())
+		return err
+	}
+	return installDaemonService(svc)
+}
+
+// daemonService returns the service running the sweepd binary 'sweep daemon
+// start' would.
+func daemonService() (service.Service, error) {
+	binary, err := client.FindDaemonBinary(daemonPaths())
+	if err != nil {
+		return service.Service{}, fmt.Errorf("find sweepd: %w", err)
+	}
+	if binary, err = filepath.Abs(binary); err != nil {
+		return service.Service{}, fmt.Errorf("resolve sweepd path: %w", err)
+	}
+	return service.New(binary)
+}
+
+// installDaemonService installs and loads svc, replacing a daemon already
+// running.
+func installDaemonService(svc service.Service) error {
+	// The service's sweepd would exit at once if another one held the lock
+	if err := client.StopDaemon(daemonPaths()); err != nil {
+		return fmt.Errorf("stop running daemon: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	if err := svc.Install(ctx); err != nil {
+		return fmt.Errorf("install %s service: %w", svc.Manager, err)
+	}
+	printInfo("Installed %s", svc.Path)
+	printInfo("sweepd (%s) now runs as a %s service, started at login and restarted if it crashes", svc.Binary, svc.Manager)
+	return nil
+}
+
+func runDaemonUninstall(_ *cobra.Command, _ []string) error {
+	svc, err := service.New("")
+	if err != nil {
+		return err
+	}
+	if !svc.Installed() {
+		printInfo("sweepd is not installed as a service")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceTimeout)
+	defer cancel()
+	if err := svc.Uninstall(ctx); err != nil {
+		return fmt.Errorf("uninstall %s service: %w", svc.Manager, err)
+	}
+	printInfo("Removed %s; sweepd is stopped and no longer starts at login", svc.Path)
+	return nil
+}
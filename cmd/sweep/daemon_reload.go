@@ -0,0 +1,73 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var daemonReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Make the daemon apply changes to the config file",
+	Long: `Make the daemon read its config file again and apply what changed, without
+restarting it: log levels (logging.level and logging.components), the large
+files threshold (daemon.min_index_size), the paths it indexes
+(daemon.index_paths), alerts, and hooks.
+
+The daemon also reloads by itself whenever the config file changes, so this
+is only needed when it can't watch the file. Other settings, such as the
+socket, store backend, and HTTP API, take a restart.
+
+Paths added to daemon.index_paths are indexed; paths removed from it stop
+being watched, unless they are saved watches, and their index is kept.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonReload,
+}
+
+// reloadTimeout bounds 'sweep daemon reload'. Changing min_index_size
+// updates the large files index of every root before the reload returns.
+const reloadTimeout = 5 * time.Minute
+
+func init() {
+	daemonCmd.AddCommand(daemonReloadCmd)
+}
+
+func runDaemonReload(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+	daemonClient, _, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	res, err := daemonClient.ReloadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("reload config: %w", err)
+	}
+	if len(res.Changed) == 0 && len(res.Errors) == 0 {
+		printInfo("Config reloaded; nothing changed")
+		return nil
+	}
+	if len(res.Changed) > 0 {
+		printInfo("Config reloaded; changed %s", strings.Join(res.Changed, ", "))
+	}
+	for _, path := range res.Indexing {
+		printInfo("  Indexing %s", path)
+	}
+	for _, path := range res.Unwatched {
+		printInfo("  No longer watching %s", path)
+	}
+	for _, msg := range res.Errors {
+		printError("%s", msg)
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("%d setting(s) could not be applied", len(res.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+//go:build !lite
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+// daemonStatusReport is the status printed by 'sweep daemon status -o json'.
+// Monitoring scripts parse it, so fields may be added but are never renamed
+// or removed. Times are RFC 3339 and omitted when unknown; ages are whole
+// seconds before the report was made.
+type daemonStatusReport struct {
+	Running    bool   `json:"running"`
+	Responding bool   `json:"responding"`
+	Remote     string `json:"remote,omitempty"` // The remote daemon's address
+
+	*daemonDetails // Set only when the daemon responded
+}
+
+type daemonDetails struct {
+	Version       string             `json:"version"` // Empty for daemons that predate reporting it
+	UptimeSeconds int64              `json:"uptime_seconds"`
+	MemoryBytes   int64              `json:"memory_bytes"`
+	IndexMode     string             `json:"index_mode"`
+	FilesIndexed  int64              `json:"files_indexed"`
+	Roots         []rootStatusReport `json:"roots"`
+	Watcher       watcherReport      `json:"watcher"`
+	Store         storeReport        `json:"store"`
+	HashWarmer    *hashWarmerReport  `json:"hash_warmer,omitempty"` // Set when enabled
+	Errors        []errorReport      `json:"errors"`                // Oldest first
+	Index         *indexReport       `json:"index,omitempty"`       // The path given, if any
+}
+
+type rootStatusReport struct {
+	Path            string     `json:"path"`
+	State           string     `json:"state"` // not_indexed, indexing, ready, or stale
+	Files           int64      `json:"files"`
+	Dirs            int64      `json:"dirs"`
+	SizeBytes       int64      `json:"size_bytes"` // 0 if indexed before the daemon started
+	Mode            string     `json:"mode,omitempty"`
+	Saved           bool       `json:"saved"`
+	Paused          bool       `json:"paused"`
+	LastIndexed     *time.Time `json:"last_indexed,omitempty"`
+	IndexAgeSeconds *int64     `json:"index_age_seconds,omitempty"`
+	LastQueried     *time.Time `json:"last_queried,omitempty"`
+	QueryAgeSeconds *int64     `json:"query_age_seconds,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	Progress        float32    `json:"progress,omitempty"`     // Of an index in progress, from 0 to 1, if known
+	CurrentPath     string     `json:"current_path,omitempty"` // Where an index in progress has got to
+	Queued          bool       `json:"queued"`                 // Waiting to be indexed at daemon startup
+}
+
+type watcherReport struct {
+	Directories     int64      `json:"directories"`
+	Events          int64      `json:"events"`
+	Errors          int64      `json:"errors"`
+	LastEvent       *time.Time `json:"last_event,omitempty"`
+	EventAgeSeconds *int64     `json:"event_age_seconds,omitempty"`
+}
+
+type storeReport struct {
+	Backend       string     `json:"backend"`
+	SizeBytes     int64      `json:"size_bytes"`
+	SchemaVersion int        `json:"schema_version"`
+	Migrating     bool       `json:"migrating"`
+	LastCompacted *time.Time `json:"last_compacted,omitempty"`
+}
+
+type hashWarmerReport struct {
+	Active      bool   `json:"active"`
+	Queued      int64  `json:"queued"`
+	FilesHashed int64  `json:"files_hashed"`
+	BytesHashed int64  `json:"bytes_hashed"`
+	CurrentPath string `json:"current_path,omitempty"`
+}
+
+type errorReport struct {
+	Time      time.Time `json:"time"`
+	Component string    `json:"component"`
+	Path      string    `json:"path,omitempty"`
+	Message   string    `json:"message"`
+}
+
+type indexReport struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+	Mode  string `json:"mode,omitempty"`
+	Files int64  `json:"files"`
+	Dirs  int64  `json:"dirs"`
+}
+
+// newDaemonStatusReport builds the report of a daemon that responded with
+// status, as of now.
+func newDaemonStatusReport(status *client.DaemonStatus, now time.Time) daemonStatusReport {
+	details := &daemonDetails{
+		Version:       status.Version,
+		UptimeSeconds: status.UptimeSeconds,
+		MemoryBytes:   status.MemoryBytes,
+		IndexMode:     status.IndexMode,
+		FilesIndexed:  status.TotalFilesIndexed,
+		Roots:         []rootStatusReport{},
+		Watcher: watcherReport{
+			Directories: status.Watcher.Directories,
+			Events:      status.Watcher.Events,
+			Errors:      status.Watcher.Errors,
+		},
+		Store: storeReport{
+			Backend:       status.Store.Backend,
+			SizeBytes:     status.Store.SizeBytes,
+			SchemaVersion: status.Store.SchemaVersion,
+			Migrating:     status.Store.Migrating,
+			LastCompacted: optionalTime(status.Store.LastCompacted),
+		},
+		Errors: []errorReport{},
+	}
+	details.Watcher.LastEvent, details.Watcher.EventAgeSeconds = timeAndAge(status.Watcher.LastEvent, now)
+	for _, r := range status.Roots {
+		root := rootStatusReport{
+			Path:      r.Path,
+			State:     r.State,
+			Files:     r.FilesIndexed,
+			Dirs:      r.DirsIndexed,
+			SizeBytes: r.TotalSize,
+			Mode:      r.Mode,
+			Saved:     r.Saved,
+			Paused:    r.Paused,
+			Error:     r.Error,
+			Queued:    r.Queued,
+		}
+		if r.State == "indexing" {
+			root.Progress, root.CurrentPath = r.Progress, r.CurrentPath
+		}
+		root.LastIndexed, root.IndexAgeSeconds = timeAndAge(r.LastIndexed, now)
+		root.LastQueried, root.QueryAgeSeconds = timeAndAge(r.LastQueried, now)
+		details.Roots = append(details.Roots, root)
+	}
+	if hw := status.HashWarmer; hw.Enabled {
+		details.HashWarmer = &hashWarmerReport{
+			Active:      hw.Active,
+			Queued:      hw.Queued,
+			FilesHashed: hw.FilesHashed,
+			BytesHashed: hw.BytesHashed,
+			CurrentPath: hw.CurrentPath,
+		}
+	}
+	for _, e := range status.RecentErrors {
+		details.Errors = append(details.Errors, errorReport(e))
+	}
+	return daemonStatusReport{Running: true, Responding: true, daemonDetails: details}
+}
+
+// optionalTime returns t, or nil when it is unknown.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// timeAndAge returns t and how many seconds before now it was, or nils
+// when it is unknown.
+func timeAndAge(t, now time.Time) (*time.Time, *int64) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	age := int64(max(now.Sub(t), 0) / time.Second)
+	return &t, &age
+}
+
+// writeDaemonStatusJSON writes report as indented JSON.
+func writeDaemonStatusJSON(w io.Writer, report daemonStatusReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
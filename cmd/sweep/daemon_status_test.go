@@ -0,0 +1,70 @@
+//go:build !lite
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+func TestDaemonStatusReportJSON(t *testing.T) {
+	now := time.Unix(1700003600, 0)
+	status := &client.DaemonStatus{
+		UptimeSeconds:     3600,
+		TotalFilesIndexed: 4000,
+		Version:           "1.2.3",
+		Roots: []client.RootStatus{{
+			Path:         "/home",
+			State:        "ready",
+			FilesIndexed: 4000,
+			TotalSize:    1 << 30,
+			LastIndexed:  now.Add(-time.Hour),
+		}},
+		Watcher:      client.WatcherStatus{Directories: 120, Events: 42},
+		Store:        client.StoreStatus{Backend: "badger", SizeBytes: 1 << 20, SchemaVersion: 3},
+		RecentErrors: []client.DaemonError{{Time: now.Add(-time.Minute), Component: "watcher", Message: "queue overflow"}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDaemonStatusJSON(&buf, newDaemonStatusReport(status, now)); err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	// Monitoring scripts depend on these names
+	want := []string{"errors", "files_indexed", "index_mode", "memory_bytes", "responding", "roots",
+		"running", "store", "uptime_seconds", "version", "watcher"}
+	if keys := slices.Sorted(maps.Keys(got)); !slices.Equal(keys, want) {
+		t.Errorf("report keys = %v, want %v", keys, want)
+	}
+	root := got["roots"].([]any)[0].(map[string]any)
+	if root["index_age_seconds"] != float64(3600) || root["size_bytes"] != float64(1<<30) || root["state"] != "ready" {
+		t.Errorf("unexpected root %v", root)
+	}
+	if _, ok := root["last_queried"]; ok {
+		t.Error("an unknown time should be left out")
+	}
+	if _, ok := root["progress"]; ok || root["queued"] != false {
+		t.Errorf("a ready root has no progress and isn't queued: %v", root)
+	}
+	if errs := got["errors"].([]any); len(errs) != 1 || errs[0].(map[string]any)["component"] != "watcher" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	// A daemon that isn't running reports only that
+	buf.Reset()
+	if err := writeDaemonStatusJSON(&buf, daemonStatusReport{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "{\n  \"running\": false,\n  \"responding\": false\n}\n" {
+		t.Errorf("not running = %q", got)
+	}
+}
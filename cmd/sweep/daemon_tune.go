@@ -0,0 +1,261 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/indexsize"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var daemonTuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "Choose daemon.min_index_size from what is indexed",
+	Long: `Count the indexed files by size and show, for each min_index_size, how many
+files the large files index would hold and how much store space they take.
+The recommended size is the smallest that keeps the index within 100,000
+files, but no larger than min_size, so scans with the default minimum size
+are answered from the index in full.
+
+Applying a size updates the running daemon at once: smaller files leave the
+large files index, and files now large enough are added from the stored
+entries, or by re-indexing paths indexed in aggregates mode. The size is
+saved as daemon.min_index_size in the config, so the daemon keeps it after
+a restart.
+
+Examples:
+  sweep daemon tune              # Show the analysis, then choose a size
+  sweep daemon tune --apply      # Apply the recommended size
+  sweep daemon tune --size 1MB   # Apply a size of your own
+  sweep daemon tune -o json      # Print the analysis as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonTune,
+}
+
+// tuneTimeout bounds 'sweep daemon tune'. Counting reads every stored
+// entry, which takes a while on large indexes.
+const tuneTimeout = 10 * time.Minute
+
+func init() {
+	daemonCmd.AddCommand(daemonTuneCmd)
+	daemonTuneCmd.Flags().Bool("apply", false, "apply the recommended size without asking")
+	daemonTuneCmd.Flags().String("size", "", "apply this size instead of the recommended one (e.g., 1MB)")
+}
+
+// tuneReport is the analysis printed by 'sweep daemon tune -o json'.
+type tuneReport struct {
+	*indexsize.Histogram
+	Options     []indexsize.Option `json:"options"`
+	Recommended int64              `json:"recommended"`
+}
+
+func runDaemonTune(cmd *cobra.Command, _ []string) error {
+	apply, _ := cmd.Flags().GetBool("apply")
+	sizeFlag, _ := cmd.Flags().GetString("size")
+	var size int64
+	if sizeFlag != "" {
+		parsed, err := types.ParseSize(sizeFlag)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid --size %q", sizeFlag)
+		}
+		size = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tuneTimeout)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	h, err := daemonClient.GetSizeHistogram(ctx)
+	if err != nil {
+		return fmt.Errorf("count indexed files: %w", err)
+	}
+	var minSize int64
+	if s := viper.GetString("min_size"); s != "" {
+		minSize, _ = types.ParseSize(s)
+	}
+	report := tuneReport{Histogram: h, Options: h.Options(), Recommended: h.Recommend(minSize)}
+
+	switch format := viper.GetString("output"); format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	case "text", "", "pretty", "plain":
+		if err := writeTuneReport(os.Stdout, report, minSize); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
+	}
+
+	switch {
+	case size > 0:
+	case apply:
+		size = report.Recommended
+	case isTerminal(os.Stdin) && isTerminal(os.Stdout) && !viper.GetBool("no_interactive") && viper.GetString("output") != "json":
+		if size, err = askTuneSize(report); err != nil || size == 0 {
+			return err
+		}
+	default:
+		if report.Recommended != h.MinIndexSize {
+			printInfo("\nApply it with: sweep daemon tune --apply")
+		}
+		return nil
+	}
+	return applyMinIndexSize(ctx, daemonClient, target, h.MinIndexSize, size)
+}
+
+// askTuneSize asks which size to apply, defaulting to the recommended one.
+// It returns 0 to keep the current size.
+func askTuneSize(report tuneReport) (int64, error) {
+	prompt := newOnboarding(os.Stdin, os.Stdout)
+	fmt.Println()
+	for {
+		answer, err := prompt.ask("min_index_size to apply, or 'keep'", indexsize.ConfigSize(report.Recommended))
+		if err != nil {
+			return 0, err
+		}
+		if strings.EqualFold(answer, "keep") {
+			return 0, nil
+		}
+		size, err := types.ParseSize(answer)
+		if err == nil && size > 0 {
+			return size, nil
+		}
+		fmt.Println("  Please enter a size such as 1MB, or 'keep'.")
+	}
+}
+
+// applyMinIndexSize changes the daemon's threshold and saves it in the
+// config. A remote daemon's config is on its machine, so it is left to the
+// user.
+func applyMinIndexSize(ctx context.Context, daemonClient *client.Client, target client.Target, current, size int64) error {
+	if size == current {
+		printInfo("min_index_size is already %s", types.FormatSize(size))
+		return nil
+	}
+	change, err := daemonClient.SetMinIndexSize(ctx, size)
+	if err != nil {
+		return fmt.Errorf("set min index size: %w", err)
+	}
+
+	switch {
+	case change.Removed > 0:
+		printInfo("min_index_size is now %s: %d files left the large files index", types.FormatSize(size), change.Removed)
+	case len(change.Reindexing) > 0:
+		printInfo("min_index_size is now %s: %d files joined the large files index, and %d paths are being re-indexed to find the rest:",
+			types.FormatSize(size), change.Added, len(change.Reindexing))
+		for _, path := range change.Reindexing {
+			printInfo("  %s", path)
+		}
+		printInfo("Follow the progress with: sweep daemon status")
+	default:
+		printInfo("min_index_size is now %s: %d files joined the large files index", types.FormatSize(size), change.Added)
+	}
+
+	value := indexsize.ConfigSize(size)
+	if target.IsRemote() {
+		printInfo("Set daemon.min_index_size: %s in the daemon's config on %s to keep it after a restart", value, target.Remote.Address)
+		return nil
+	}
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		if path, err = config.Path(); err != nil {
+			return err
+		}
+	}
+	if err := config.SetValue(path, "daemon.min_index_size", value); err != nil {
+		return fmt.Errorf("save daemon.min_index_size: %w", err)
+	}
+	printInfo("Saved daemon.min_index_size: %s in %s", value, path)
+	return nil
+}
+
+// writeTuneReport renders the analysis as tables of the files by size and
+// the index each threshold would give.
+func writeTuneReport(w io.Writer, r tuneReport, minSize int64) error {
+	// Badger measures the store about once a minute, so a new one reads 0
+	if r.StoreSize > 0 {
+		fmt.Fprintf(w, "Indexed files by size (%d files, store %s):\n\n", r.Files(), types.FormatSize(r.StoreSize))
+	} else {
+		fmt.Fprintf(w, "Indexed files by size (%d files):\n\n", r.Files())
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SIZE\tFILES\tTOTAL")
+	for i, b := range r.Buckets {
+		label := fmt.Sprintf("%s to %s", types.FormatSize(b.MinSize), types.FormatSize(nextBound(r.Buckets, i)))
+		switch {
+		case i == 0:
+			label = "under " + types.FormatSize(nextBound(r.Buckets, i))
+		case i == len(r.Buckets)-1:
+			label = types.FormatSize(b.MinSize) + " and up"
+		}
+		files := fmt.Sprintf("%d", b.Files)
+		if r.UnsizedFiles > 0 && b.MinSize < r.MinIndexSize {
+			files += "+"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", label, files, types.FormatSize(b.Bytes))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MIN_INDEX_SIZE\tINDEXED FILES\tCOVERING\tINDEX SIZE\t")
+	for _, o := range r.Options {
+		files, covering, indexSize := fmt.Sprintf("%d", o.Files), types.FormatSize(o.Bytes), types.FormatSize(o.IndexBytes)
+		if o.Partial {
+			files, covering, indexSize = files+"+", covering+"+", indexSize+"+"
+		}
+		var notes []string
+		if o.Threshold == r.MinIndexSize {
+			notes = append(notes, "current")
+		}
+		if o.Threshold == r.Recommended {
+			notes = append(notes, "recommended")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", types.FormatSize(o.Threshold), files, covering, indexSize, strings.Join(notes, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if r.UnsizedFiles > 0 {
+		fmt.Fprintf(w, "\n+ %d files in paths indexed in aggregates mode are smaller than the current\n  min_index_size and weren't stored, so the counts leave them out.\n", r.UnsizedFiles)
+	}
+	reason := fmt.Sprintf("the smallest that keeps the index within %d files", indexsize.MaxIndexFiles)
+	for _, o := range r.Options {
+		if o.Threshold == r.Recommended && (o.Partial || o.Files > indexsize.MaxIndexFiles) {
+			reason = fmt.Sprintf("the largest no bigger than min_size (%s)", types.FormatSize(minSize))
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nRecommended min_index_size: %s, %s.\n", types.FormatSize(r.Recommended), reason)
+	return err
+}
+
+// nextBound returns the lower bound of the bucket after bucket i.
+func nextBound(buckets []indexsize.Bucket, i int) int64 {
+	if i+1 < len(buckets) {
+		return buckets[i+1].MinSize
+	}
+	return 0
+}
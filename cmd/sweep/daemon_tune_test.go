@@ -0,0 +1,52 @@
+//go:build !lite
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/indexsize"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestWriteTuneReport(t *testing.T) {
+	h := &indexsize.Histogram{MinIndexSize: 10 * types.MiB, UnsizedFiles: 500}
+	for _, bound := range indexsize.Bounds {
+		b := indexsize.Bucket{MinSize: bound}
+		if bound == 10*types.MiB {
+			b.Files, b.Bytes, b.IndexBytes = 4, 80*types.MiB, 200
+		}
+		h.Buckets = append(h.Buckets, b)
+	}
+	report := tuneReport{Histogram: h, Options: h.Options(), Recommended: h.Recommend(100 * types.MiB)}
+
+	var buf bytes.Buffer
+	if err := writeTuneReport(&buf, report, 100*types.MiB); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"Indexed files by size (504 files):",
+		"under 64 KiB",
+		"1.0 GiB and up",
+		"500 files in paths indexed in aggregates mode",
+		"Recommended min_index_size: 10 MiB, the smallest that keeps the index within 100000 files.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report is missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, " to ") {
+			continue // The files by size
+		}
+		if strings.HasPrefix(line, "10 MiB ") && !strings.Contains(line, "current, recommended") {
+			t.Errorf("10 MiB row = %q, want it marked current and recommended", line)
+		}
+		if strings.HasPrefix(line, "64 KiB ") && !strings.Contains(line, "4+") {
+			t.Errorf("64 KiB row = %q, want a partial count", line)
+		}
+	}
+}
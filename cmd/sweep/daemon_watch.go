@@ -0,0 +1,244 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var daemonWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Manage the directories the daemon watches",
+	Long: `Add, remove, and list the directories the daemon indexes and watches.
+
+Directories added here are saved in the daemon's index, so the daemon indexes
+and watches them again each time it starts, like daemon.index_paths in the
+config.`,
+}
+
+var daemonWatchAddCmd = &cobra.Command{
+	Use:   "add <path>...",
+	Short: "Index and watch directories",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDaemonWatchAdd,
+}
+
+var daemonWatchRemoveCmd = &cobra.Command{
+	Use:   "remove <path>...",
+	Short: "Stop watching directories",
+	Long: `Stop watching directories and forget them. Their index is kept, as of
+the last change seen, unless --clear is given.`,
+	Aliases: []string{"rm"},
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runDaemonWatchRemove,
+}
+
+var daemonWatchPauseCmd = &cobra.Command{
+	Use:   "pause <path>...",
+	Short: "Stop applying changes to watched directories for a while",
+	Long: `Stop applying changes under watched directories until they are resumed,
+so heavy operations like large builds or media imports don't flood the daemon
+with events. The daemon remembers which directories changed, and catches up on
+them when the directories are resumed. Pauses last until resumed or the daemon
+restarts.
+
+Examples:
+  sweep daemon watch pause ~/src     # Before a large build
+  sweep daemon watch resume ~/src    # After it`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDaemonWatchPause,
+}
+
+var daemonWatchResumeCmd = &cobra.Command{
+	Use:   "resume <path>...",
+	Short: "Apply changes to paused directories again",
+	Long: `Apply changes under paused directories again. The directories that changed
+while paused are compared with the index and brought up to date; if too many
+changed, the directory is indexed again instead.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDaemonWatchResume,
+}
+
+var daemonWatchListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List watched directories",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runDaemonWatchList,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonWatchCmd)
+	daemonWatchCmd.AddCommand(daemonWatchAddCmd)
+	daemonWatchCmd.AddCommand(daemonWatchRemoveCmd)
+	daemonWatchCmd.AddCommand(daemonWatchListCmd)
+	daemonWatchCmd.AddCommand(daemonWatchPauseCmd)
+	daemonWatchCmd.AddCommand(daemonWatchResumeCmd)
+
+	daemonWatchRemoveCmd.Flags().Bool("clear", false, "Also drop the directories from the index")
+}
+
+// watchPath resolves a path argument. Paths for a remote daemon are on its
+// machine, so they are passed as given.
+func watchPath(target client.Target, path string) (string, error) {
+	if target.IsRemote() {
+		return path, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve path: %w", err)
+	}
+	return abs, nil
+}
+
+func runDaemonWatchAdd(_ *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	for _, arg := range args {
+		path, err := watchPath(target, arg)
+		if err != nil {
+			return err
+		}
+		started, err := daemonClient.AddWatch(ctx, path)
+		if err != nil {
+			return fmt.Errorf("watch %s: %w", path, err)
+		}
+		if started {
+			printInfo("Watching %s (indexing started)", path)
+		} else {
+			printInfo("Watching %s", path)
+		}
+	}
+	return nil
+}
+
+func runDaemonWatchRemove(cmd *cobra.Command, args []string) error {
+	clearIndex, _ := cmd.Flags().GetBool("clear")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	for _, arg := range args {
+		path, err := watchPath(target, arg)
+		if err != nil {
+			return err
+		}
+		cleared, err := daemonClient.RemoveWatch(ctx, path, clearIndex)
+		if err != nil {
+			return fmt.Errorf("unwatch %s: %w", path, err)
+		}
+		if clearIndex {
+			printInfo("Stopped watching %s and cleared it from the index (%d entries)", path, cleared)
+		} else {
+			printInfo("Stopped watching %s", path)
+		}
+	}
+	return nil
+}
+
+func runDaemonWatchPause(_ *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	for _, arg := range args {
+		path, err := watchPath(target, arg)
+		if err != nil {
+			return err
+		}
+		already, err := daemonClient.PauseWatch(ctx, path)
+		if err != nil {
+			return fmt.Errorf("pause %s: %w", path, err)
+		}
+		if already {
+			printInfo("%s is already paused", path)
+		} else {
+			printInfo("Paused %s; resume with: sweep daemon watch resume %s", path, arg)
+		}
+	}
+	return nil
+}
+
+func runDaemonWatchResume(_ *cobra.Command, args []string) error {
+	// Catching up reads every directory that changed while paused
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	for _, arg := range args {
+		path, err := watchPath(target, arg)
+		if err != nil {
+			return err
+		}
+		resumed, err := daemonClient.ResumeWatch(ctx, path)
+		if err != nil {
+			return fmt.Errorf("resume %s: %w", path, err)
+		}
+		if resumed.Reindexing {
+			printInfo("Resumed %s; too much changed while paused, so it is being indexed again", path)
+		} else {
+			printInfo("Resumed %s (%d changed directories caught up)", path, resumed.DirsReconciled)
+		}
+	}
+	return nil
+}
+
+func runDaemonWatchList(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	daemonClient, _, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	roots, err := daemonClient.ListWatches(ctx)
+	if err != nil {
+		return fmt.Errorf("list watches: %w", err)
+	}
+	if len(roots) == 0 {
+		printInfo("No watched directories. Add one with: sweep daemon watch add <path>")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tSTATE\tFILES\tSAVED")
+	for _, r := range roots {
+		saved := "no"
+		if r.Saved {
+			saved = "yes"
+		}
+		state := r.State
+		if r.Paused {
+			state += " (paused)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", r.Path, state, r.FilesIndexed, saved)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	diffSince string
+	diffDepth int
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [path]",
+	Short: "Show which directories and files grew or shrank",
+	Long: `Compare the disk usage under a path now with a snapshot from the past,
+listing the directories and large files whose size changed, largest change
+first.
+
+The daemon saves a snapshot of each indexed path every
+daemon.snapshot_interval (default 6h) and keeps them for
+daemon.snapshot_retention (default 90d). --since picks the latest snapshot at
+least that old, or the oldest there is. Directories under 1 MiB at both times
+aren't listed.
+
+Examples:
+  sweep diff                      # The current directory over the last week
+  sweep diff ~ --since 30d        # The home directory over the last month
+  sweep diff / --depth 2 -l 20    # The 20 largest changes two levels down
+  sweep diff -o json ~ > growth.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffSince, "since", "7d", "compare with the snapshot from this long ago (e.g., 24h, 7d, 4w)")
+	diffCmd.Flags().IntVar(&diffDepth, "depth", 0, "directory levels below the path to compare (0 for unlimited)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff prints the size changes under a path since a daemon snapshot.
+func runDiff(_ *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	since, err := filter.ParseDuration(diffSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since %q: %w", diffSince, err)
+	}
+	if diffDepth < 0 {
+		return fmt.Errorf("--depth must not be negative")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report, err := daemonSizeDiff(ctx, path, since, diffDepth, viper.GetInt("limit"))
+	if errors.Is(err, errDaemonNotRunning) {
+		return fmt.Errorf("sweep diff compares the daemon's snapshots: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	return sizediff.Write(os.Stdout, viper.GetString("output"), *report)
+}
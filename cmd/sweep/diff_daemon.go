@@ -0,0 +1,36 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
+)
+
+// daemonSizeDiff asks the daemon how the disk usage under root changed
+// since its snapshot from since ago.
+func daemonSizeDiff(ctx context.Context, root string, since time.Duration, depth, limit int) (*sizediff.Report, error) {
+	target := daemonTarget()
+	if !target.Running() {
+		return nil, errDaemonNotRunning
+	}
+
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer daemonClient.Close()
+
+	report, err := daemonClient.GetSizeDiff(ctx, root, since, depth, limit)
+	if status.Code(err) == codes.FailedPrecondition {
+		// Not indexed, or no snapshot yet: the daemon's message says which
+		return nil, errors.New(status.Convert(err).Message())
+	}
+	return report, err
+}
@@ -0,0 +1,15 @@
+//go:build lite
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
+)
+
+// daemonSizeDiff has no daemon to ask in lite builds.
+func daemonSizeDiff(_ context.Context, _ string, _ time.Duration, _, _ int) (*sizediff.Report, error) {
+	return nil, errDaemonNotRunning
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/jamesainslie/sweep/cmd/sweep/tui"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var doctorQuick bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with the config and the daemon",
+	Long: `Check sweep's setup and print a fix for each problem found:
+
+  - the config file parses and its values are valid
+  - the daemon's socket is in place and only its owner can connect
+  - the daemon is running and responding, and it and the installed sweepd
+    binary are the same version as sweep
+  - indexed roots are up to date
+  - the daemon has file descriptors to spare, its watcher keeps up, and
+    every record in its index store reads back
+
+Checking the index store reads all of it, which takes a while on a large
+index; --quick skips it. Exits non-zero if any check fails.
+
+Examples:
+  sweep doctor           # Check everything
+  sweep doctor --quick   # Skip the index store check
+  sweep doctor -o json   # Checks as a JSON array`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorQuick, "quick", false, "skip reading the whole index store")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// Outcomes of a doctor check.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn" // Works, but not as well as it could
+	doctorFail = "fail" // Broken
+	doctorSkip = "skip" // Couldn't be checked
+)
+
+// doctorCheck is one check 'sweep doctor' made. It is printed as JSON with
+// -o json, so fields may be added but are never renamed or removed.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warn, fail, or skip
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"` // A command or step that fixes the problem
+}
+
+func runDoctor(_ *cobra.Command, _ []string) error {
+	format := viper.GetString("output")
+	switch format {
+	case "json", "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
+	}
+
+	path := cfgFile
+	if path == "" {
+		var err error
+		if path, err = config.Path(); err != nil {
+			return err
+		}
+	}
+	checks := configChecks(path)
+	checks = append(checks, daemonChecks(!doctorQuick)...)
+
+	if format == "json" {
+		if err := writeDoctorJSON(os.Stdout, checks); err != nil {
+			return err
+		}
+	} else {
+		printDoctorChecks(os.Stdout, checks)
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// configChecks checks the config file at path, which need not exist.
+func configChecks(path string) []doctorCheck {
+	file := doctorCheck{Name: "config file", Status: doctorOK, Detail: path}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		file.Detail = "none at " + path + "; using defaults"
+		file.Fix = "sweep config init"
+		return []doctorCheck{file}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	var cfg config.Config
+	_, err := config.ReadInConfig(v)
+	if err == nil {
+		err = v.Unmarshal(&cfg)
+	}
+	if err != nil {
+		file.Status = doctorFail
+		file.Detail = err.Error()
+		file.Fix = "fix the file with 'sweep config edit', or move it aside and run 'sweep config init'"
+		return []doctorCheck{file}
+	}
+
+	values := doctorCheck{Name: "config values", Status: doctorOK, Detail: "valid"}
+	if problems := configProblems(&cfg); len(problems) > 0 {
+		values.Status = doctorFail
+		values.Detail = strings.Join(problems, "; ")
+		values.Fix = "sweep config edit"
+	}
+	return []doctorCheck{file, values}
+}
+
+// configProblems lists the values in cfg that sweep or sweepd would reject
+// or ignore.
+func configProblems(cfg *config.Config) []string {
+	var problems []string
+	if cfg.MinSize != "" {
+		if _, err := types.ParseSize(cfg.MinSize); err != nil {
+			problems = append(problems, fmt.Sprintf("min_size: %v", err))
+		}
+	}
+	if cfg.Symlinks != "" {
+		if _, err := types.ParseSymlinkPolicy(cfg.Symlinks); err != nil {
+			problems = append(problems, fmt.Sprintf("symlinks: %v", err))
+		}
+	}
+	if _, err := tui.NewColumnLayout(cfg.UI.Columns, cfg.UI.Widths, cfg.UI.Units); err != nil {
+		problems = append(problems, fmt.Sprintf("ui: %v", err))
+	}
+	switch cfg.UI.Resume {
+	case "", resumeAsk, resumeAlways, resumeNever:
+	default:
+		problems = append(problems, fmt.Sprintf("ui.resume: unknown value %q (available: %s, %s, %s)", cfg.UI.Resume, resumeAsk, resumeAlways, resumeNever))
+	}
+	return append(problems, daemonConfigProblems(cfg)...)
+}
+
+// doctorMarks mark each check's outcome in text output.
+var doctorMarks = map[string]string{
+	doctorOK:   "✓",
+	doctorWarn: "!",
+	doctorFail: "✗",
+	doctorSkip: "-",
+}
+
+// printDoctorChecks prints checks as an aligned list, with the fix for
+// each problem beneath it.
+func printDoctorChecks(w io.Writer, checks []doctorCheck) {
+	width := 0
+	for _, c := range checks {
+		width = max(width, len(c.Name))
+	}
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s %-*s  %s\n", doctorMarks[c.Status], width, c.Name, c.Detail)
+		if c.Fix != "" && (c.Status == doctorWarn || c.Status == doctorFail) {
+			fmt.Fprintf(w, "  %*s  fix: %s\n", width, "", c.Fix)
+		}
+	}
+}
+
+// writeDoctorJSON writes checks as an indented JSON array.
+func writeDoctorJSON(w io.Writer, checks []doctorCheck) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(checks)
+}
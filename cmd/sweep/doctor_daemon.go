@@ -0,0 +1,267 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+const (
+	// doctorTimeout bounds the doctor's requests to the daemon and sweepd.
+	doctorTimeout = 10 * time.Second
+	// storeCheckTimeout bounds the index store check, which reads the
+	// whole store.
+	storeCheckTimeout = 30 * time.Minute
+	// fdWarnRatio is the share of its open file limit past which the
+	// daemon is warned to be running out.
+	fdWarnRatio = 0.8
+)
+
+// daemonConfigProblems lists the daemon settings in cfg sweepd would
+// reject or ignore.
+func daemonConfigProblems(cfg *config.Config) []string {
+	var problems []string
+	if cfg.Daemon.IndexMode != "" {
+		if _, err := indexer.ParseMode(cfg.Daemon.IndexMode); err != nil {
+			problems = append(problems, fmt.Sprintf("daemon.index_mode: %v", err))
+		}
+	}
+	switch cfg.Daemon.StoreBackend {
+	case "", store.BackendBadger, store.BackendSQLite:
+	default:
+		problems = append(problems, fmt.Sprintf("daemon.store_backend: unknown backend %q (available: %s, %s)",
+			cfg.Daemon.StoreBackend, store.BackendBadger, store.BackendSQLite))
+	}
+	return problems
+}
+
+// daemonChecks checks the daemon, reading its whole index store too if
+// checkStore is set.
+func daemonChecks(checkStore bool) []doctorCheck {
+	target := daemonTarget()
+	var checks []doctorCheck
+	running := target.Running()
+	if !target.IsRemote() {
+		checks = append(checks, sweepdBinaryCheck(daemonPaths()))
+		if runtime.GOOS != "windows" {
+			checks = append(checks, socketCheck(target.Socket, running))
+		}
+	}
+	if !running {
+		return append(checks, doctorCheck{
+			Name:   "daemon",
+			Status: doctorWarn,
+			Detail: "not running; sweep scans the filesystem instead of querying an index",
+			Fix:    "sweep daemon start",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	daemonClient, err := target.Connect(ctx)
+	var status *client.DaemonStatus
+	if err == nil {
+		defer daemonClient.Close()
+		status, err = daemonClient.GetDaemonStatus(ctx)
+	}
+	if err != nil {
+		return append(checks, doctorCheck{Name: "daemon", Status: doctorFail, Detail: "not responding: " + err.Error(), Fix: "sweep daemon restart"})
+	}
+	detail := "running"
+	if target.IsRemote() {
+		detail += " on " + target.Remote.Address
+	}
+	checks = append(checks,
+		doctorCheck{Name: "daemon", Status: doctorOK, Detail: detail},
+		versionCheck("daemon version", status.Version, version, "sweep daemon restart"))
+	checks = append(checks, indexChecks(status.Roots)...)
+
+	// The store can't be read while it is migrated
+	checkStore = checkStore && !status.Store.Migrating
+	diagCtx, diagCancel := context.WithTimeout(context.Background(), doctorTimeout)
+	if checkStore {
+		diagCtx, diagCancel = context.WithTimeout(context.Background(), storeCheckTimeout)
+	}
+	defer diagCancel()
+	diag, err := daemonClient.GetDiagnostics(diagCtx, checkStore)
+	switch {
+	case errors.Is(err, client.ErrUnsupported):
+		checks = append(checks, doctorCheck{Name: "diagnostics", Status: doctorSkip, Detail: "the daemon predates them", Fix: "sweep daemon restart"})
+	case err != nil:
+		checks = append(checks, doctorCheck{Name: "diagnostics", Status: doctorFail, Detail: err.Error(), Fix: "sweep daemon restart"})
+	default:
+		checks = append(checks, diagnosticsChecks(status, diag)...)
+	}
+	return append(checks, recentErrorsCheck(status.RecentErrors))
+}
+
+// sweepdBinaryCheck checks that the sweepd binary sweep starts the daemon
+// with exists and is sweep's version.
+func sweepdBinaryCheck(paths client.DaemonPaths) doctorCheck {
+	const name = "sweepd binary"
+	binary, err := client.FindDaemonBinary(paths)
+	if err != nil {
+		return doctorCheck{Name: name, Status: doctorFail, Detail: err.Error(),
+			Fix: "install sweepd next to sweep, or set daemon.binary_path in the config"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	installed, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "sweepd ")
+	if err != nil || !ok {
+		// Older binaries start the daemon rather than print a version
+		return doctorCheck{Name: name, Status: doctorWarn, Detail: binary + " doesn't report its version",
+			Fix: "reinstall sweep and sweepd from the same release"}
+	}
+	check := versionCheck(name, installed, version, "reinstall sweep and sweepd from the same release")
+	check.Detail = binary + ", " + check.Detail
+	return check
+}
+
+// versionCheck compares the version of sweepd, as the daemon or binary
+// reports it, with sweep's. Development builds match any version.
+func versionCheck(name, theirs, ours, fix string) doctorCheck {
+	switch {
+	case theirs == "":
+		return doctorCheck{Name: name, Status: doctorWarn, Detail: "unknown; sweepd predates reporting it", Fix: fix}
+	case theirs == ours || theirs == "dev" || ours == "dev":
+		return doctorCheck{Name: name, Status: doctorOK, Detail: theirs}
+	default:
+		return doctorCheck{Name: name, Status: doctorWarn, Detail: fmt.Sprintf("sweepd %s, sweep %s", theirs, ours), Fix: fix}
+	}
+}
+
+// socketCheck checks the local daemon's socket: that it exists while the
+// daemon runs, and that other users can't connect to it.
+func socketCheck(socket string, running bool) doctorCheck {
+	if socket == "" {
+		socket = client.DefaultSocketPath()
+	}
+	check := doctorCheck{Name: "socket", Status: doctorOK, Detail: socket}
+	info, err := os.Stat(socket)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && running:
+		check.Status, check.Detail, check.Fix = doctorFail, socket+" is missing while the daemon runs", "sweep daemon restart"
+	case errors.Is(err, fs.ErrNotExist):
+		check.Detail = "none; the daemon isn't running"
+	case err != nil:
+		check.Status, check.Detail = doctorFail, err.Error()
+	case info.Mode().Type() != fs.ModeSocket:
+		check.Status, check.Detail, check.Fix = doctorFail, socket+" isn't a socket", "remove it, then run 'sweep daemon start'"
+	case info.Mode().Perm()&0o022 != 0:
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("other users can connect to %s (mode %04o)", socket, info.Mode().Perm())
+		check.Fix = "chmod go-w " + socket
+	case !running:
+		check.Status, check.Detail, check.Fix = doctorWarn, socket+" was left by a daemon that didn't shut down", "sweep daemon start"
+	}
+	return check
+}
+
+// indexChecks reports roots whose index failed or is out of date, or that
+// all are ready.
+func indexChecks(roots []client.RootStatus) []doctorCheck {
+	if len(roots) == 0 {
+		return []doctorCheck{{Name: "index", Status: doctorSkip, Detail: "no roots indexed", Fix: "sweep daemon watch add <path>"}}
+	}
+	var checks []doctorCheck
+	for _, r := range roots {
+		check := doctorCheck{Name: "index of " + r.Path, Fix: "sweep daemon index --force " + r.Path}
+		switch {
+		case r.Error != "":
+			check.Status, check.Detail = doctorFail, "indexing failed: "+r.Error
+		case r.State == "stale":
+			check.Status, check.Detail = doctorWarn, "stale; changes may be missing"
+		case r.State == "not_indexed" && !r.Paused && !r.Queued:
+			check.Status, check.Detail = doctorWarn, "not indexed"
+		default:
+			continue
+		}
+		checks = append(checks, check)
+	}
+	if len(checks) == 0 {
+		return []doctorCheck{{Name: "index", Status: doctorOK, Detail: fmt.Sprintf("%d roots up to date", len(roots))}}
+	}
+	return checks
+}
+
+// diagnosticsChecks checks the daemon's process health.
+func diagnosticsChecks(status *client.DaemonStatus, diag *client.Diagnostics) []doctorCheck {
+	process := doctorCheck{Name: "daemon process", Status: doctorOK,
+		Detail: fmt.Sprintf("pid %d, %d goroutines", diag.PID, diag.Goroutines)}
+
+	fds := doctorCheck{Name: "file descriptors", Status: doctorOK}
+	switch {
+	case diag.OpenFDs < 0 || diag.FDLimit <= 0:
+		fds.Status, fds.Detail = doctorSkip, "not reported on this platform"
+	case float64(diag.OpenFDs) >= fdWarnRatio*float64(diag.FDLimit):
+		fds.Status = doctorWarn
+		fds.Detail = fmt.Sprintf("%d of %d in use", diag.OpenFDs, diag.FDLimit)
+		fds.Fix = "raise the open file limit (ulimit -n) in the daemon's environment, then run 'sweep daemon restart'"
+	default:
+		fds.Detail = fmt.Sprintf("%d of %d in use", diag.OpenFDs, diag.FDLimit)
+	}
+
+	watcher := doctorCheck{Name: "watcher", Status: doctorOK,
+		Detail: fmt.Sprintf("%d directories, %d events queued, %d changes held for paused roots", diag.WatcherDirs, diag.WatcherQueued, diag.WatcherHeld)}
+	if status.Watcher.Errors > 0 {
+		watcher.Status = doctorWarn
+		watcher.Detail = fmt.Sprintf("%d errors reported by the OS; changes may have been missed", status.Watcher.Errors)
+		watcher.Fix = "sweep daemon restart"
+		if runtime.GOOS == "linux" {
+			watcher.Fix = "raise fs.inotify.max_user_watches and fs.inotify.max_queued_events with sysctl, then run 'sweep daemon restart'"
+		}
+	}
+
+	checks := []doctorCheck{process, fds, watcher}
+	return append(checks, storeCheck(status.Store, diag.StoreCheck))
+}
+
+// storeCheck reports the index store check, or why there wasn't one.
+func storeCheck(st client.StoreStatus, res *client.StoreCheck) doctorCheck {
+	check := doctorCheck{Name: "index store", Status: doctorOK}
+	switch {
+	case st.Migrating:
+		check.Status, check.Detail = doctorSkip, "being migrated to a new schema"
+	case res == nil:
+		check.Status, check.Detail = doctorSkip, "not checked with --quick"
+	case res.Damaged > 0:
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%d problems in %d records, e.g. %s", res.Damaged, res.Records, res.Problems[0])
+		check.Fix = "sweep daemon clear, then index the roots again with 'sweep daemon index <path>'"
+	default:
+		check.Detail = fmt.Sprintf("%d records read back (%s)", res.Records, formatDuration(res.Duration))
+	}
+	return check
+}
+
+// recentErrorsCheck reports the daemon's recent errors.
+func recentErrorsCheck(errs []client.DaemonError) doctorCheck {
+	if len(errs) == 0 {
+		return doctorCheck{Name: "recent errors", Status: doctorOK, Detail: "none"}
+	}
+	last := errs[len(errs)-1]
+	msg := last.Message
+	if last.Path != "" {
+		msg = last.Path + ": " + msg
+	}
+	return doctorCheck{
+		Name:   "recent errors",
+		Status: doctorWarn,
+		Detail: fmt.Sprintf("%d, the latest from the %s: %s", len(errs), last.Component, msg),
+		Fix:    "see 'sweep daemon status' and the daemon log",
+	}
+}
@@ -0,0 +1,15 @@
+//go:build lite
+
+package main
+
+import "github.com/jamesainslie/sweep/pkg/sweep/config"
+
+// daemonConfigProblems ignores daemon settings in lite builds.
+func daemonConfigProblems(*config.Config) []string {
+	return nil
+}
+
+// daemonChecks reports that lite builds have no daemon to check.
+func daemonChecks(bool) []doctorCheck {
+	return []doctorCheck{{Name: "daemon", Status: doctorSkip, Detail: "not supported by this lite build"}}
+}
@@ -0,0 +1,130 @@
+//go:build !lite
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+func TestVersionCheck(t *testing.T) {
+	tests := []struct {
+		theirs, ours string
+		want         string
+	}{
+		{"1.2.3", "1.2.3", doctorOK},
+		{"1.2.3", "dev", doctorOK},
+		{"dev", "1.2.3", doctorOK},
+		{"1.2.2", "1.2.3", doctorWarn},
+		{"", "1.2.3", doctorWarn},
+	}
+	for _, tt := range tests {
+		if got := versionCheck("daemon version", tt.theirs, tt.ours, "fix"); got.Status != tt.want {
+			t.Errorf("versionCheck(%q, %q) = %+v, want %s", tt.theirs, tt.ours, got, tt.want)
+		}
+	}
+}
+
+func TestSocketCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the daemon listens on a named pipe")
+	}
+	// Short, for the limit on socket path lengths
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "s.sock")
+
+	if c := socketCheck(socket, false); c.Status != doctorOK {
+		t.Errorf("socketCheck() without a daemon = %+v", c)
+	}
+	if c := socketCheck(socket, true); c.Status != doctorFail {
+		t.Errorf("socketCheck() of a missing socket = %+v, want failed", c)
+	}
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if err := os.Chmod(socket, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if c := socketCheck(socket, true); c.Status != doctorOK {
+		t.Errorf("socketCheck() = %+v, want ok", c)
+	}
+	if c := socketCheck(socket, false); c.Status != doctorWarn {
+		t.Errorf("socketCheck() of a stale socket = %+v, want a warning", c)
+	}
+	if err := os.Chmod(socket, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if c := socketCheck(socket, true); c.Status != doctorWarn || c.Fix != "chmod go-w "+socket {
+		t.Errorf("socketCheck() of a world-writable socket = %+v, want a warning", c)
+	}
+}
+
+func TestIndexChecks(t *testing.T) {
+	if checks := indexChecks(nil); len(checks) != 1 || checks[0].Status != doctorSkip {
+		t.Errorf("indexChecks(nil) = %+v", checks)
+	}
+
+	roots := []client.RootStatus{
+		{Path: "/home", State: "ready"},
+		{Path: "/srv", State: "indexing"},
+	}
+	if checks := indexChecks(roots); len(checks) != 1 || checks[0].Status != doctorOK {
+		t.Errorf("indexChecks() of ready roots = %+v", checks)
+	}
+
+	roots = append(roots,
+		client.RootStatus{Path: "/data", State: "not_indexed", Error: "permission denied"},
+		client.RootStatus{Path: "/tmp", State: "stale"},
+		client.RootStatus{Path: "/mnt", State: "not_indexed", Paused: true},
+		client.RootStatus{Path: "/media", State: "not_indexed", Queued: true},
+	)
+	checks := indexChecks(roots)
+	if len(checks) != 2 || checks[0].Status != doctorFail || checks[1].Status != doctorWarn {
+		t.Fatalf("indexChecks() = %+v, want /data failed and /tmp stale", checks)
+	}
+	if checks[0].Fix != "sweep daemon index --force /data" {
+		t.Errorf("fix = %q", checks[0].Fix)
+	}
+}
+
+func TestDiagnosticsChecks(t *testing.T) {
+	status := &client.DaemonStatus{Watcher: client.WatcherStatus{Errors: 2}}
+	diag := &client.Diagnostics{
+		PID:        42,
+		OpenFDs:    900,
+		FDLimit:    1024,
+		StoreCheck: &client.StoreCheck{Records: 1000, Damaged: 2, Problems: []string{"entry /a doesn't decode"}},
+	}
+	checks := diagnosticsChecks(status, diag)
+	got := map[string]string{}
+	for _, c := range checks {
+		got[c.Name] = c.Status
+	}
+	want := map[string]string{
+		"daemon process":   doctorOK,
+		"file descriptors": doctorWarn,
+		"watcher":          doctorWarn,
+		"index store":      doctorFail,
+	}
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%s check = %q, want %q", name, got[name], status)
+		}
+	}
+
+	if c := storeCheck(client.StoreStatus{}, nil); c.Status != doctorSkip {
+		t.Errorf("storeCheck() with --quick = %+v, want skipped", c)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigChecks(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	checks := configChecks(path)
+	if len(checks) != 1 || checks[0].Status != doctorOK || checks[0].Fix != "sweep config init" {
+		t.Errorf("configChecks() without a file = %+v, want defaults in use", checks)
+	}
+
+	if err := os.WriteFile(path, []byte("min_size: 100MB\nui:\n  columns: [size, path]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checks = configChecks(path)
+	if len(checks) != 2 || checks[0].Status != doctorOK || checks[1].Status != doctorOK {
+		t.Errorf("configChecks() of a valid file = %+v", checks)
+	}
+
+	if err := os.WriteFile(path, []byte("min_size: huge\nsymlinks: sometimes\nui:\n  resume: maybe\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checks = configChecks(path)
+	if len(checks) != 2 || checks[1].Status != doctorFail {
+		t.Fatalf("configChecks() of invalid values = %+v, want them failed", checks)
+	}
+	for _, key := range []string{"min_size", "symlinks", "ui.resume"} {
+		if !strings.Contains(checks[1].Detail, key) {
+			t.Errorf("config values detail %q doesn't mention %s", checks[1].Detail, key)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("min_size: [unclosed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if checks := configChecks(path); len(checks) != 1 || checks[0].Status != doctorFail {
+		t.Errorf("configChecks() of broken YAML = %+v, want the file failed", checks)
+	}
+}
+
+func TestPrintDoctorChecks(t *testing.T) {
+	var buf bytes.Buffer
+	printDoctorChecks(&buf, []doctorCheck{
+		{Name: "config file", Status: doctorOK, Detail: "/etc/sweep.yaml", Fix: "not shown"},
+		{Name: "daemon", Status: doctorWarn, Detail: "not running", Fix: "sweep daemon start"},
+	})
+	want := "✓ config file  /etc/sweep.yaml\n" +
+		"! daemon       not running\n" +
+		"               fix: sweep daemon start\n"
+	if buf.String() != want {
+		t.Errorf("printDoctorChecks() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/tuner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	duDepth     int
+	duThreshold string
+)
+
+// errDaemonNotRunning makes 'sweep du' fall back to scanning.
+var errDaemonNotRunning = errors.New("daemon not running")
+
+var duCmd = &cobra.Command{
+	Use:   "du [path]",
+	Short: "Show the total size of each directory",
+	Long: `Show the total size and file count of each directory under a path,
+counting every file, not just large ones.
+
+Totals come from the daemon's index when the path is indexed, so they are
+instant; otherwise the path is scanned. Directories deeper than --depth are
+counted in their ancestor at that depth. --sort orders by size (default),
+files, entries, inodes, or path; 'sweep inodes' shows the entry counts.
+
+Examples:
+  sweep du                         # Directories in the current directory
+  sweep du ~ --depth 2             # Two levels below the home directory
+  sweep du ~/Projects --sort path  # Alphabetical, like du
+  sweep du / --threshold 1GB -l 20 # The 20 largest directories over 1 GB
+  sweep du -o json ~ > usage.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDu,
+}
+
+func init() {
+	duCmd.Flags().IntVar(&duDepth, "depth", 1, "directory levels below the path to show (0 for unlimited)")
+	duCmd.Flags().StringVar(&duThreshold, "threshold", "", "hide directories smaller than this (e.g., 500MB)")
+	rootCmd.AddCommand(duCmd)
+}
+
+// runDu prints the directory totals under a path.
+func runDu(_ *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if duDepth < 0 {
+		return fmt.Errorf("--depth must not be negative")
+	}
+	var threshold int64
+	if duThreshold != "" {
+		if threshold, err = types.ParseSize(duThreshold); err != nil {
+			return fmt.Errorf("invalid threshold %q: %w", duThreshold, err)
+		}
+	}
+
+	// Check the order before a possibly long scan
+	sortBy := viper.GetString("sort")
+	if err := du.Sort(nil, sortBy); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report := du.Report{Root: path, Source: du.SourceIndex}
+	report.Dirs, err = daemonDirSizes(ctx, path, duDepth)
+	if err != nil {
+		// A remote path can't be scanned here
+		if getRemoteConfig().Address != "" {
+			return fmt.Errorf("remote index unavailable for %s: %w", path, err)
+		}
+		printVerbose("Index unavailable for %s, scanning: %v", path, err)
+		report.Source = du.SourceScan
+		if report.Dirs, err = scanDirSizes(ctx, path, duDepth); err != nil {
+			return err
+		}
+	}
+
+	report.Dirs = du.Filter(report.Dirs, threshold)
+	if err := du.Sort(report.Dirs, sortBy); err != nil {
+		return err
+	}
+	if viper.GetBool("reverse") {
+		slices.Reverse(report.Dirs)
+	}
+	if limit := viper.GetInt("limit"); limit > 0 && len(report.Dirs) > limit {
+		report.Dirs = report.Dirs[:limit]
+	}
+	return du.Write(os.Stdout, viper.GetString("output"), report)
+}
+
+// scanDirSizes totals the directories under root by scanning it.
+func scanDirSizes(ctx context.Context, root string, depth int) ([]du.Dir, error) {
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	symlinks, err := types.ParseSymlinkPolicy(viper.GetString("symlinks"))
+	if err != nil {
+		return nil, err
+	}
+
+	mc, err := mountsConfig()
+	if err != nil {
+		return nil, err
+	}
+	workers, err := forNetwork(tuner.OptimalConfig{
+		DirWorkers:  viper.GetInt("workers.dir"),
+		FileWorkers: viper.GetInt("workers.file"),
+	}, []string{root})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := du.NewTotals(root, depth)
+	s := scanner.New(scanner.Options{
+		Root:        root,
+		MinSize:     math.MaxInt64, // Only totals are needed, not the files
+		Exclude:     viper.GetStringSlice("exclude"),
+		DirWorkers:  workers.DirWorkers,
+		FileWorkers: workers.FileWorkers,
+		Symlinks:    symlinks,
+		OnStat:      totals.AddFile,
+		OnDir:       totals.AddDir,
+
+		IncludeSnapshots: viper.GetBool("include_snapshots"),
+		OneFileSystem:    viper.GetBool("one_file_system"),
+		SkipMounts:       mc.Skip,
+		CrossMounts:      mc.Cross,
+	})
+	if _, err := s.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+	return totals.Dirs(), nil
+}
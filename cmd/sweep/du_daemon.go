@@ -0,0 +1,31 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+)
+
+// daemonDirSizes reads directory totals from the daemon's index. It returns
+// the error when the daemon is unreachable or hasn't indexed root.
+func daemonDirSizes(ctx context.Context, root string, depth int) ([]du.Dir, error) {
+	target := daemonTarget()
+	if !target.Running() {
+		return nil, errDaemonNotRunning
+	}
+
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer daemonClient.Close()
+
+	dirs, mode, err := daemonClient.GetDirSizes(ctx, root, depth)
+	if err != nil {
+		return nil, err
+	}
+	printVerbose("Using daemon index for %s (%s mode)", root, mode)
+	return dirs, nil
+}
@@ -0,0 +1,14 @@
+//go:build lite
+
+package main
+
+import (
+	"context"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+)
+
+// daemonDirSizes always falls back to scanning in lite builds.
+func daemonDirSizes(_ context.Context, _ string, _ int) ([]du.Dir, error) {
+	return nil, errDaemonNotRunning
+}
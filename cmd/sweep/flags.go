@@ -26,6 +26,7 @@ var (
 	maxDepth   int
 	sortBy     string
 	reverse    bool
+	tagNames   string
 
 	// Daemon/cache control
 	maxAge      string
@@ -108,6 +109,24 @@ func buildFilter() (*filter.Filter, error) {
 		opts = append(opts, filter.WithExclude(exclude...))
 	}
 
+	// Tags (restrict to tagged paths)
+	tagStr := viper.GetString("tag")
+	if tagStr != "" {
+		store, err := getTagStore()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tags: %w", err)
+		}
+		var paths []string
+		for _, name := range parseCommaSeparated(tagStr) {
+			tagged := store.Paths(name)
+			if len(tagged) == 0 {
+				return nil, fmt.Errorf("tag not found: %s", name)
+			}
+			paths = append(paths, tagged...)
+		}
+		opts = append(opts, filter.WithPaths(paths...))
+	}
+
 	// Max depth
 	maxDepthVal := viper.GetInt("max_depth")
 	if maxDepthVal > 0 {
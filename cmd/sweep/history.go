@@ -2,12 +2,17 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
 	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var historyCmd = &cobra.Command{
@@ -35,15 +40,46 @@ var historyCleanCmd = &cobra.Command{
 	RunE:  runHistoryClean,
 }
 
+var historyAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Export the audit log of deleted and restored files",
+	Long: `List every file sweep has deleted or restored: when, by which user on
+which host, how (moved to the trash or removed permanently, and by which
+cleanup rule), and its size. The audit log is append-only and, unlike the
+history entries, is never cleaned up.
+
+--since and --until take a date (2026-01-31), a time (RFC 3339), or a
+duration before now (24h, 7d, 3mo). --path keeps only files at or under a
+path. Use -o json or -o csv to export the records for a compliance review.
+
+Examples:
+  sweep history audit --since 7d
+  sweep history audit --since 2026-01-01 --until 2026-04-01 -o csv > q1.csv
+  sweep history audit --path /srv/shared --operation rule -o json`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryAudit,
+}
+
 var (
 	historyLimit int
+
+	auditSince      string
+	auditUntil      string
+	auditPath       string
+	auditOperations []string
 )
 
 func init() {
 	historyCmd.Flags().IntVarP(&historyLimit, "limit", "l", 20, "maximum number of entries to show")
 
+	historyAuditCmd.Flags().StringVar(&auditSince, "since", "", "only files deleted or restored at or after this time (e.g., 7d, 2026-01-31)")
+	historyAuditCmd.Flags().StringVar(&auditUntil, "until", "", "only files deleted or restored before this time (e.g., 24h, 2026-02-01)")
+	historyAuditCmd.Flags().StringVar(&auditPath, "path", "", "only files at or under this path")
+	historyAuditCmd.Flags().StringSliceVar(&auditOperations, "operation", nil, "only these operations (delete, rule, restore)")
+
 	historyCmd.AddCommand(historyShowCmd)
 	historyCmd.AddCommand(historyCleanCmd)
+	historyCmd.AddCommand(historyAuditCmd)
 	rootCmd.AddCommand(historyCmd)
 }
 
@@ -120,6 +156,12 @@ func runHistoryShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("ID:         %s\n", entry.ID)
 	fmt.Printf("Timestamp:  %s\n", entry.Timestamp.Format("2006-01-02 15:04:05 MST"))
 	fmt.Printf("Operation:  %s\n", entry.Operation)
+	if entry.Rule != "" {
+		fmt.Printf("Rule:       %s\n", entry.Rule)
+	}
+	if entry.Restores != "" {
+		fmt.Printf("Restores:   %s\n", entry.Restores)
+	}
 	fmt.Printf("Files:      %d\n", entry.Summary.TotalFiles)
 	fmt.Printf("Total Size: %s\n", types.FormatSize(entry.Summary.TotalBytes))
 
@@ -175,6 +217,67 @@ func runHistoryClean(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runHistoryAudit lists the audit records selected by the flags.
+func runHistoryAudit(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	var q manifest.AuditQuery
+	var err error
+	if auditSince != "" {
+		if q.Since, err = parseHistoryTime(auditSince, now); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if auditUntil != "" {
+		if q.Until, err = parseHistoryTime(auditUntil, now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if auditPath != "" {
+		path, err := config.ExpandPath(auditPath)
+		if err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		if q.Path, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+	}
+	for _, op := range auditOperations {
+		switch o := manifest.OperationType(strings.ToLower(strings.TrimSpace(op))); o {
+		case manifest.OpDelete, manifest.OpRule, manifest.OpRestore:
+			q.Operations = append(q.Operations, o)
+		default:
+			return fmt.Errorf("unknown operation %q (available: delete, rule, restore)", op)
+		}
+	}
+
+	m, err := getManifest()
+	if err != nil {
+		return fmt.Errorf("failed to initialize manifest: %w", err)
+	}
+	records, err := m.Audit(q)
+	if err != nil {
+		return err
+	}
+	return manifest.WriteAudit(os.Stdout, viper.GetString("output"), records)
+}
+
+// parseHistoryTime parses a date, an RFC 3339 time, or a duration before
+// now. Dates are midnight in the local time zone.
+func parseHistoryTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := filter.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date, time, or duration", s)
+	}
+	return now.Add(-d), nil
+}
+
 // truncateString truncates a string to maxLen, adding "..." if truncated.
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
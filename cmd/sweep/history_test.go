@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "7d", want: now.AddDate(0, 0, -7)},
+		{in: "90m", want: now.Add(-90 * time.Minute)},
+		{in: "2026-01-31", want: time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local)},
+		{in: "2026-01-31T08:30:00Z", want: time.Date(2026, 1, 31, 8, 30, 0, 0, time.UTC)},
+		{in: "yesterday", wantErr: true},
+		{in: "-7d", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseHistoryTime(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHistoryTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseHistoryTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+//go:build !lite
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Work with the daemon's index",
+	Long:  `Commands that read the sweepd daemon's index directly.`,
+}
+
+var indexExportCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Export the index to a SQLite database",
+	Long: `Export every file and directory in the daemon's index to a SQLite
+database for ad-hoc SQL analysis. With a path, only what is under it is
+exported.
+
+The database has two tables:
+
+  files(path, dir, name, ext, size, mod_time, owner, type, shared)
+  dirs(path, size, files, mod_time)
+
+mod_time is in Unix seconds, and dirs holds the size and count of the files
+beneath each directory. Indexes built in aggregates mode only hold their
+large files, so only those are in files.
+
+The export is written with the sqlite3 command-line shell, which must be on
+your PATH.
+
+Examples:
+  sweep index export --sqlite index.db
+  sqlite3 index.db "SELECT owner, SUM(size) FROM files GROUP BY owner"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIndexExport,
+}
+
+var (
+	indexExportSQLite string
+	indexExportForce  bool
+)
+
+func init() {
+	rootCmd.AddCommand(indexCmd)
+	indexCmd.AddCommand(indexExportCmd)
+
+	indexExportCmd.Flags().StringVar(&indexExportSQLite, "sqlite", "", "SQLite database to write")
+	indexExportCmd.Flags().BoolVarP(&indexExportForce, "force", "f", false, "Overwrite the database if it exists")
+	_ = indexExportCmd.MarkFlagRequired("sqlite")
+}
+
+func runIndexExport(cmd *cobra.Command, args []string) error {
+	var root string
+	if len(args) > 0 {
+		var err error
+		if root, err = filepath.Abs(args[0]); err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+	}
+
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return errors.New("sqlite3 not found: install the SQLite command-line shell to export the index")
+	}
+	out := indexExportSQLite
+	if _, err := os.Stat(out); err == nil && !indexExportForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", out)
+	}
+
+	target := daemonTarget()
+	if !target.Running() {
+		return errDaemonNotRunning
+	}
+	ctx := cmd.Context()
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	// Write next to the database and move it into place once complete, so
+	// a failed export doesn't leave a partial database behind
+	tmp := out + ".tmp"
+	_ = os.Remove(tmp)
+	counts, err := exportSQLite(ctx, sqlite3, tmp, func(fn func(client.IndexEntry) error) error {
+		return daemonClient.ExportIndex(ctx, root, fn)
+	})
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	printInfo("Exported %d files and %d directories to %s", counts.files, counts.dirs, out)
+	return nil
+}
+
+// exportCounts is what an export wrote.
+type exportCounts struct {
+	files, dirs int
+}
+
+// exportSQLite writes the entries export produces to a new SQLite database
+// at path, using the sqlite3 shell at sqlite3.
+func exportSQLite(ctx context.Context, sqlite3, path string, export func(func(client.IndexEntry) error) error) (exportCounts, error) {
+	var stderr bytes.Buffer
+	sh := exec.CommandContext(ctx, sqlite3, "-batch", "-bail", path)
+	sh.Stderr = &stderr
+	stdin, err := sh.StdinPipe()
+	if err != nil {
+		return exportCounts{}, err
+	}
+	if err := sh.Start(); err != nil {
+		return exportCounts{}, fmt.Errorf("failed to start sqlite3: %w", err)
+	}
+
+	w := newSQLiteWriter(stdin)
+	err = export(w.add)
+	if err == nil {
+		err = w.close()
+	}
+	_ = stdin.Close()
+	waitErr := sh.Wait()
+	if waitErr != nil && stderr.Len() > 0 {
+		waitErr = fmt.Errorf("sqlite3: %s", strings.TrimSpace(stderr.String()))
+	}
+	// sqlite3 failing makes writes fail, so its error says more
+	if waitErr != nil {
+		return exportCounts{}, waitErr
+	}
+	return w.counts, err
+}
+
+// sqliteWriter writes index entries as SQL for the sqlite3 shell.
+type sqliteWriter struct {
+	w      *bufio.Writer
+	counts exportCounts
+	err    error
+}
+
+// sqliteSchema creates the tables entries are inserted into. Indexes are
+// created once the rows are in, which is faster than keeping them up to
+// date.
+const sqliteSchema = `PRAGMA journal_mode = OFF;
+PRAGMA synchronous = OFF;
+BEGIN;
+CREATE TABLE files (
+  path TEXT PRIMARY KEY,
+  dir TEXT NOT NULL,
+  name TEXT NOT NULL,
+  ext TEXT NOT NULL,
+  size INTEGER NOT NULL,
+  mod_time INTEGER NOT NULL,
+  owner TEXT,
+  type TEXT,
+  shared INTEGER NOT NULL
+);
+CREATE TABLE dirs (
+  path TEXT PRIMARY KEY,
+  size INTEGER NOT NULL,
+  files INTEGER NOT NULL,
+  mod_time INTEGER NOT NULL
+);
+`
+
+const sqliteIndexes = `CREATE INDEX files_dir ON files (dir);
+CREATE INDEX files_size ON files (size);
+CREATE INDEX files_type ON files (type);
+CREATE INDEX files_owner ON files (owner);
+COMMIT;
+`
+
+func newSQLiteWriter(w io.Writer) *sqliteWriter {
+	sw := &sqliteWriter{w: bufio.NewWriter(w)}
+	_, sw.err = sw.w.WriteString(sqliteSchema)
+	return sw
+}
+
+// add writes the insert for one entry.
+func (sw *sqliteWriter) add(e client.IndexEntry) error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if e.IsDir {
+		sw.counts.dirs++
+		_, sw.err = fmt.Fprintf(sw.w, "INSERT INTO dirs VALUES (%s, %d, %d, %d);\n",
+			sqlQuote(e.Path), e.Size, e.Files, e.ModTime)
+		return sw.err
+	}
+	sw.counts.files++
+	_, sw.err = fmt.Fprintf(sw.w, "INSERT INTO files VALUES (%s, %s, %s, %s, %d, %d, %s, %s, %s);\n",
+		sqlQuote(e.Path), sqlQuote(filepath.Dir(e.Path)), sqlQuote(filepath.Base(e.Path)),
+		sqlQuote(strings.ToLower(filepath.Ext(e.Path))), e.Size, e.ModTime,
+		sqlNullable(e.Owner), sqlNullable(e.FileType), sqlBool(e.Shared))
+	return sw.err
+}
+
+// close indexes the tables and commits.
+func (sw *sqliteWriter) close() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if _, err := sw.w.WriteString(sqliteIndexes); err != nil {
+		return err
+	}
+	return sw.w.Flush()
+}
+
+// sqlQuote quotes s as an SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlNullable quotes s, or returns NULL when it is empty.
+func sqlNullable(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return sqlQuote(s)
+}
+
+// sqlBool returns b as an SQLite boolean.
+func sqlBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
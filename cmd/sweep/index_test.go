@@ -0,0 +1,84 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+func TestExportSQLite(t *testing.T) {
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	path := filepath.Join(t.TempDir(), "index.db")
+	entries := []client.IndexEntry{
+		{Path: "/data", IsDir: true, Size: 300, Files: 2, ModTime: 1700000000},
+		{Path: "/data/Movie.MP4", Size: 200, ModTime: 1700000001, Owner: "alice", FileType: "Video"},
+		{Path: "/data/it's.bin", Size: 100, ModTime: 1700000002, Shared: true},
+	}
+
+	counts, err := exportSQLite(context.Background(), sqlite3, path, func(fn func(client.IndexEntry) error) error {
+		for _, e := range entries {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("exportSQLite() error = %v", err)
+	}
+	if counts.files != 2 || counts.dirs != 1 {
+		t.Errorf("counts = %+v, want 2 files and 1 directory", counts)
+	}
+
+	query := func(sql string) string {
+		out, err := exec.Command(sqlite3, path, sql).Output()
+		if err != nil {
+			t.Fatalf("query %q: %v", sql, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if got := query("SELECT name, ext, owner, type FROM files WHERE size = 200"); got != "Movie.MP4|.mp4|alice|Video" {
+		t.Errorf("video row = %q", got)
+	}
+	if got := query("SELECT dir, shared, owner IS NULL FROM files WHERE name = 'it''s.bin'"); got != "/data|1|1" {
+		t.Errorf("quoted row = %q", got)
+	}
+	if got := query("SELECT size, files FROM dirs"); got != "300|2" {
+		t.Errorf("dirs row = %q", got)
+	}
+}
+
+func TestExportSQLiteFailedExport(t *testing.T) {
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	path := filepath.Join(t.TempDir(), "index.db")
+	failed := errors.New("connection lost")
+
+	_, err = exportSQLite(context.Background(), sqlite3, path, func(fn func(client.IndexEntry) error) error {
+		_ = fn(client.IndexEntry{Path: "/data/a.bin", Size: 1})
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("exportSQLite() error = %v, want the export's error", err)
+	}
+	// The transaction is never committed
+	out, err := exec.Command(sqlite3, path, "SELECT name FROM sqlite_master").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.TrimSpace(string(out))) != 0 {
+		t.Errorf("tables = %q, want none", out)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/relocate"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move --dest <dir> <file>...",
+	Short: "Move large files to another volume instead of deleting them",
+	Long: `Move files into a directory on another disk, such as an archive drive,
+to free space without deleting them. Files on the same volume as the
+destination are renamed. Others are copied, and the original is only removed
+once the copy is verified, by size or with --verify hash also by SHA-256.
+With --symlink, a symlink to the new location is left at each old path.
+
+Only regular files are moved, under their own names; a file already at the
+destination is never replaced. move.dest, move.verify, and move.symlink in
+the config set the defaults.
+
+Examples:
+  sweep move --dest /Volumes/Archive ~/Movies/*.mov
+  sweep move --dest /mnt/backup --verify hash --symlink disk.img
+  sweep move --dest /Volumes/Archive --dry-run big.iso`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMove,
+}
+
+var (
+	moveDest    string
+	moveVerify  string
+	moveSymlink bool
+)
+
+func init() {
+	moveCmd.Flags().StringVar(&moveDest, "dest", "", "directory to move files into (default move.dest)")
+	moveCmd.Flags().StringVar(&moveVerify, "verify", "", "how copies are verified: size or hash (default move.verify, else size)")
+	moveCmd.Flags().BoolVar(&moveSymlink, "symlink", false, "leave a symlink at each old path (default move.symlink)")
+	rootCmd.AddCommand(moveCmd)
+}
+
+// runMove moves the files given to the destination and writes a report.
+func runMove(cmd *cobra.Command, args []string) error {
+	dryRun := viper.GetBool("dry_run")
+	if getReadOnly() && !dryRun {
+		return errReadOnly
+	}
+
+	var mc config.MoveConfig
+	if err := viper.UnmarshalKey("move", &mc); err != nil {
+		return fmt.Errorf("invalid move settings in config: %w", err)
+	}
+	dest := cmp.Or(moveDest, mc.Dest)
+	if dest == "" {
+		return errors.New("no destination: give --dest or set move.dest in the config")
+	}
+	dest, err := config.ExpandPath(dest)
+	if err != nil {
+		return err
+	}
+	symlink := mc.Symlink
+	if cmd.Flags().Changed("symlink") {
+		symlink = moveSymlink
+	}
+
+	paths := make([]string, len(args))
+	for i, arg := range args {
+		if paths[i], err = filepath.Abs(arg); err != nil {
+			return fmt.Errorf("invalid path %q: %w", arg, err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	opts := relocate.Options{
+		Dest:    dest,
+		Verify:  cmp.Or(moveVerify, mc.Verify),
+		Symlink: symlink,
+		DryRun:  dryRun,
+	}
+	if !getQuiet() && isTerminal(os.Stderr) {
+		var drawn time.Time
+		opts.OnProgress = func(p relocate.Progress) {
+			if now := time.Now(); p.Copied == p.Size || now.Sub(drawn) >= progressInterval {
+				drawn = now
+				fmt.Fprintf(os.Stderr, "\r\033[KCopying %s: %s of %s", filepath.Base(p.Path),
+					types.FormatSize(p.Copied), types.FormatSize(p.Size))
+			}
+		}
+		opts.OnDone = func(relocate.Result) {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	}
+
+	report, err := relocate.Move(ctx, paths, opts)
+	if err != nil {
+		return err
+	}
+	if err := relocate.Write(os.Stdout, viper.GetString("output"), report); err != nil {
+		return err
+	}
+	if n := report.Failed(); n > 0 {
+		return fmt.Errorf("%d file(s) could not be moved", n)
+	}
+	return nil
+}
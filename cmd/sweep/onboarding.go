@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// clutterDirs are directory names that are usually safe to exclude,
+// suggested during first-run setup when found under the chosen directories.
+var clutterDirs = map[string]string{
+	"node_modules": "JavaScript dependencies",
+	".git":         "Git object stores",
+	".cache":       "application caches",
+	"__pycache__":  "Python bytecode",
+	".venv":        "Python virtual environments",
+	".gradle":      "Gradle caches",
+}
+
+const (
+	// clutterMaxDepth is how deep below each directory to look for clutter.
+	clutterMaxDepth = 3
+
+	// clutterMaxDirs bounds the directories read while looking for clutter,
+	// so setup stays quick on large home directories.
+	clutterMaxDirs = 5000
+)
+
+// onboardingSkipCommands never trigger first-run setup.
+var onboardingSkipCommands = map[string]bool{
+	"config":     true,
+	"version":    true,
+	"help":       true,
+	"completion": true,
+	"check":      true,
+	"bench":      true,
+	"daemon":     true,
+	"doctor":     true,
+}
+
+// onboarding runs the interactive first-run setup.
+type onboarding struct {
+	in   *bufio.Reader
+	out  io.Writer
+	home string
+
+	offerService bool // Offer to install sweepd as a user service
+	wantService  bool // The offer was accepted
+}
+
+// newOnboarding creates a setup session reading answers from in.
+func newOnboarding(in io.Reader, out io.Writer) *onboarding {
+	home, _ := os.UserHomeDir()
+	return &onboarding{in: bufio.NewReader(in), out: out, home: home, offerService: canInstallService()}
+}
+
+// needsOnboarding reports whether cmd should run first-run setup: no config
+// file exists yet and sweep is attached to a terminal.
+func needsOnboarding(cmd *cobra.Command) bool {
+	if cfgFile != "" || getQuiet() || viper.GetBool("no_interactive") {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if onboardingSkipCommands[c.Name()] {
+			return false
+		}
+	}
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return false
+	}
+	path, err := config.Path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// runOnboarding asks the first-run questions, writes config.yaml, and loads
+// it for the current command. If asked to, it then installs sweepd as a
+// service, which reads the new config.
+func runOnboarding() error {
+	o := newOnboarding(os.Stdin, os.Stdout)
+	setup, err := o.run()
+	if err != nil {
+		return fmt.Errorf("first-run setup: %w", err)
+	}
+	if err := config.WriteSetup(setup); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	path, _ := config.Path()
+	fmt.Printf("\nWrote %s\nChange it any time with 'sweep config edit'.\n\n", path)
+
+	// Pick up the new file for this run.
+	_, _ = config.ReadInConfig(viper.GetViper())
+
+	if o.wantService {
+		// Setup is still done; the install can be retried on its own
+		if err := installService(); err != nil {
+			printError("%v; run 'sweep daemon install' to try again", err)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// run asks the setup questions and returns the answers.
+// Declining setup returns the defaults so the questions are not asked again.
+func (o *onboarding) run() (config.Setup, error) {
+	setup := config.DefaultSetup()
+
+	fmt.Fprintln(o.out, "Welcome to sweep! No config file was found, so let's create one.")
+	fmt.Fprintln(o.out, "Press Enter to accept the default shown in brackets.")
+	fmt.Fprintln(o.out)
+
+	ok, err := o.confirm("Set up sweep now?", true)
+	if err != nil || !ok {
+		return setup, err
+	}
+
+	dirs, err := o.askDirs()
+	if err != nil {
+		return setup, err
+	}
+	setup.IndexPaths = dirs
+
+	if found := findClutter(dirs, clutterMaxDepth, clutterMaxDirs); len(found) > 0 {
+		names := make([]string, 0, len(found))
+		for name := range found {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(o.out, "\nThese directories are usually safe to skip:")
+		for _, name := range names {
+			fmt.Fprintf(o.out, "  %-14s %s (e.g. %s)\n", name, clutterDirs[name], o.shorten(found[name]))
+		}
+		ok, err := o.confirm("Exclude them from scans?", true)
+		if err != nil {
+			return setup, err
+		}
+		if ok {
+			setup.Exclude = append(append([]string(nil), config.DefaultExclusions...), names...)
+		}
+	}
+
+	question := "Start the sweep daemon automatically for instant repeat scans?"
+	if len(dirs) > 0 {
+		question = "Start the sweep daemon automatically to keep these directories indexed?"
+	}
+	fmt.Fprintln(o.out)
+	setup.AutoStart, err = o.confirm(question, true)
+	if err != nil || !setup.AutoStart || !o.offerService {
+		return setup, err
+	}
+
+	o.wantService, err = o.confirm("Install it as a service, started at login and restarted if it crashes?", false)
+	return setup, err
+}
+
+// askDirs asks which directories the daemon should index and watch.
+func (o *onboarding) askDirs() ([]string, error) {
+	for {
+		answer, err := o.ask("Directories to index and watch (comma-separated, or 'none')", "~")
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(answer, "none") {
+			return nil, nil
+		}
+
+		dirs, err := o.parseDirs(answer)
+		if err == nil {
+			return dirs, nil
+		}
+		fmt.Fprintf(o.out, "  %v\n", err)
+	}
+}
+
+// parseDirs resolves a comma-separated list of directories.
+func (o *onboarding) parseDirs(answer string) ([]string, error) {
+	var dirs []string
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "~" || strings.HasPrefix(part, "~/") {
+			part = filepath.Join(o.home, part[1:])
+		}
+		abs, err := filepath.Abs(part)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", part)
+		}
+		dirs = append(dirs, abs)
+	}
+	return dirs, nil
+}
+
+// ask prints a question and returns the trimmed answer, or def if empty.
+func (o *onboarding) ask(question, def string) (string, error) {
+	fmt.Fprintf(o.out, "%s [%s]: ", question, def)
+	line, err := o.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(o.out)
+		}
+		return def, nil
+	}
+	return line, nil
+}
+
+// confirm asks a yes/no question.
+func (o *onboarding) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := o.ask(question, hint)
+		if err != nil {
+			return false, err
+		}
+		if answer == hint {
+			return def, nil
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(o.out, "  Please answer y or n.")
+	}
+}
+
+// shorten replaces the home directory prefix with ~ for display.
+func (o *onboarding) shorten(path string) string {
+	if o.home != "" && strings.HasPrefix(path, o.home+string(filepath.Separator)) {
+		return "~" + path[len(o.home):]
+	}
+	return path
+}
+
+// findClutter looks for clutterDirs under roots, breadth first, returning
+// the first path found for each name. It reads at most maxDirs directories.
+func findClutter(roots []string, maxDepth, maxDirs int) map[string]string {
+	found := make(map[string]string)
+	type entry struct {
+		path  string
+		depth int
+	}
+	queue := make([]entry, 0, len(roots))
+	for _, root := range roots {
+		queue = append(queue, entry{root, 0})
+	}
+
+	for read := 0; len(queue) > 0 && read < maxDirs; read++ {
+		dir := queue[0]
+		queue = queue[1:]
+
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir.path, e.Name())
+			if _, ok := clutterDirs[e.Name()]; ok {
+				if _, seen := found[e.Name()]; !seen {
+					found[e.Name()] = path
+				}
+				continue // Don't look inside clutter
+			}
+			if dir.depth+1 < maxDepth {
+				queue = append(queue, entry{path, dir.depth + 1})
+			}
+		}
+	}
+	return found
+}
+
+// isTerminal reports whether f is connected to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,26 @@
+//go:build !lite
+
+package main
+
+import (
+	"errors"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/service"
+)
+
+// canInstallService reports whether this system has a service manager
+// sweepd can be installed with.
+func canInstallService() bool {
+	_, err := service.New("")
+	return !errors.Is(err, service.ErrUnsupported)
+}
+
+// installService installs sweepd as a user service, as 'sweep daemon
+// install' does.
+func installService() error {
+	svc, err := daemonService()
+	if err != nil {
+		return err
+	}
+	return installDaemonService(svc)
+}
@@ -0,0 +1,15 @@
+//go:build lite
+
+package main
+
+import "errors"
+
+// canInstallService is false in lite builds, which have no daemon.
+func canInstallService() bool {
+	return false
+}
+
+// installService is unsupported in lite builds.
+func installService() error {
+	return errors.ErrUnsupported
+}
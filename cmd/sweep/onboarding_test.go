@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+func newTestOnboarding(input, home string) (*onboarding, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	o := newOnboarding(strings.NewReader(input), out)
+	o.home = home
+	o.offerService = false
+	return o, out
+}
+
+func TestOnboardingRun(t *testing.T) {
+	home := t.TempDir()
+	projects := filepath.Join(home, "Projects")
+	if err := os.MkdirAll(filepath.Join(projects, "app", "node_modules", "left-pad"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(projects, "tool", ".venv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Accept setup, pick a bad then a good directory, exclude, no daemon.
+	o, out := newTestOnboarding("\n~/missing\n~/Projects\ny\nn\n", home)
+	setup, err := o.run()
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	if want := []string{projects}; !reflect.DeepEqual(setup.IndexPaths, want) {
+		t.Errorf("IndexPaths = %v, want %v", setup.IndexPaths, want)
+	}
+	wantExclude := append(append([]string(nil), config.DefaultExclusions...), ".venv", "node_modules")
+	if !reflect.DeepEqual(setup.Exclude, wantExclude) {
+		t.Errorf("Exclude = %v, want %v", setup.Exclude, wantExclude)
+	}
+	if setup.AutoStart {
+		t.Error("AutoStart = true, want false")
+	}
+	if !strings.Contains(out.String(), "is not a directory") {
+		t.Error("expected an error for the missing directory")
+	}
+	if !strings.Contains(out.String(), "~/Projects/app/node_modules") {
+		t.Errorf("expected the node_modules example in output, got:\n%s", out)
+	}
+}
+
+func TestOnboardingInstallService(t *testing.T) {
+	// Accept setup, no directories, auto-start, install the service.
+	o, out := newTestOnboarding("\nnone\n\ny\n", t.TempDir())
+	o.offerService = true
+	setup, err := o.run()
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !setup.AutoStart || !o.wantService {
+		t.Errorf("AutoStart = %v, wantService = %v, want both true", setup.AutoStart, o.wantService)
+	}
+
+	// Without a service manager, or without auto-start, it isn't offered
+	for name, input := range map[string]string{"unsupported": "\nnone\n\n", "no auto-start": "\nnone\nn\n"} {
+		o, out = newTestOnboarding(input, t.TempDir())
+		o.offerService = name != "unsupported"
+		if _, err := o.run(); err != nil {
+			t.Fatalf("%s: run() error = %v", name, err)
+		}
+		if o.wantService || strings.Contains(out.String(), "service") {
+			t.Errorf("%s: service offered or accepted:\n%s", name, out)
+		}
+	}
+}
+
+func TestOnboardingDeclined(t *testing.T) {
+	o, _ := newTestOnboarding("n\n", t.TempDir())
+	setup, err := o.run()
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !reflect.DeepEqual(setup, config.DefaultSetup()) {
+		t.Errorf("declined setup = %+v, want defaults", setup)
+	}
+}
+
+func TestOnboardingDefaultsAtEOF(t *testing.T) {
+	home := t.TempDir()
+	o, _ := newTestOnboarding("", home)
+	setup, err := o.run()
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if want := []string{home}; !reflect.DeepEqual(setup.IndexPaths, want) {
+		t.Errorf("IndexPaths = %v, want %v", setup.IndexPaths, want)
+	}
+	if !setup.AutoStart {
+		t.Error("AutoStart = false, want true")
+	}
+}
+
+func TestFindClutterDepthLimit(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b", "c", "node_modules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "a", ".cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	found := findClutter([]string{root}, 3, 100)
+	if _, ok := found["node_modules"]; ok {
+		t.Error("node_modules below the depth limit should not be found")
+	}
+	if found[".cache"] != filepath.Join(root, "a", ".cache") {
+		t.Errorf(".cache = %q, want it under a/", found[".cache"])
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// progressInterval is how often the progress line is redrawn.
+const progressInterval = 250 * time.Millisecond
+
+// progressLine shows how far along the direct scans of a command run
+// without the TUI are, on one line redrawn in place, e.g.
+// "Scanned 1,024 dirs, 18,230 files, 42%, about 1m20s left".
+type progressLine struct {
+	w        io.Writer
+	mu       sync.Mutex
+	progress []types.ScanProgress // By root
+	est      scanner.Estimator
+	drawn    time.Time
+	shown    bool
+	done     bool
+}
+
+// newProgressLine returns a progress line for scans of roots roots,
+// written to w, which should be a terminal.
+func newProgressLine(w io.Writer, roots int) *progressLine {
+	return &progressLine{w: w, progress: make([]types.ScanProgress, roots)}
+}
+
+// reporter returns the scanner progress callback for the root at index i,
+// or nil for a nil line.
+func (l *progressLine) reporter(i int) func(types.ScanProgress) {
+	if l == nil {
+		return nil
+	}
+	return func(p types.ScanProgress) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.progress[i] = p
+
+		now := time.Now()
+		var total types.ScanProgress
+		known := true
+		for _, p := range l.progress {
+			total.DirsScanned += p.DirsScanned
+			total.FilesScanned += p.FilesScanned
+			total.EntriesExpected += p.EntriesExpected
+			known = known && p.EntriesExpected > 0
+		}
+		if !known {
+			total.EntriesExpected = 0 // A partial total would overstate progress
+		}
+		est, ok := l.est.Update(total, now)
+		if l.done || now.Sub(l.drawn) < progressInterval {
+			return
+		}
+		l.drawn, l.shown = now, true
+		line := fmt.Sprintf("Scanned %s dirs, %s files", humanize.Comma(total.DirsScanned), humanize.Comma(total.FilesScanned))
+		if ok {
+			line += ", " + est.String()
+		}
+		fmt.Fprint(l.w, "\r\033[K"+line)
+	}
+}
+
+// clear erases the line, before the results are printed.
+func (l *progressLine) clear() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.shown {
+		fmt.Fprint(l.w, "\r\033[K")
+		l.shown = false
+	}
+	l.done = true
+}
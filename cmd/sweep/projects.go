@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/projects"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	projectsUnusedFor      string
+	projectsCleanUnusedFor string
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects [path]",
+	Short: "Find projects and the space their build and dependency directories take",
+	Long: `Find software projects under a path and measure the directories a build
+or install regenerates: node_modules, dist, target, .venv, and similar.
+
+Projects are recognized by a marker file in their root (package.json,
+Cargo.toml, go.mod, pyproject.toml, pom.xml, build.gradle). A project was
+last used when a file outside its build and dependency directories last
+changed, or when it was last committed to or checked out; running a build
+doesn't count.
+
+'sweep projects clean' moves the directories of projects unused for a while
+to the trash; an install or build brings them back.
+
+Examples:
+  sweep projects ~/src                      # Every project, largest first
+  sweep projects ~/src --unused-for 6mo     # Only projects untouched for 6 months
+  sweep projects clean ~/src --dry-run      # Show what clean would free
+  sweep projects clean ~/src --unused-for 1y`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProjects,
+}
+
+var projectsCleanCmd = &cobra.Command{
+	Use:   "clean [path]",
+	Short: "Move the build and dependency directories of unused projects to the trash",
+	Long: `Move the build and dependency directories of projects unused for at least
+--unused-for (90 days by default) to the trash. The project files themselves
+are kept, and each directory is recorded in the history so it can be
+restored with 'sweep restore'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProjectsClean,
+}
+
+func init() {
+	projectsCmd.Flags().StringVar(&projectsUnusedFor, "unused-for", "", "only projects unused for at least this long (e.g., 90d, 6mo)")
+	projectsCleanCmd.Flags().StringVar(&projectsCleanUnusedFor, "unused-for", "90d", "clean projects unused for at least this long (e.g., 90d, 6mo)")
+	projectsCmd.AddCommand(projectsCleanCmd)
+	rootCmd.AddCommand(projectsCmd)
+}
+
+// findProjects returns the projects under the path in args, or the current
+// directory, that were unused for at least unusedFor; an empty unusedFor
+// returns all of them.
+func findProjects(ctx context.Context, args []string, unusedFor string) ([]projects.Project, error) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+
+	var cutoff time.Time
+	if unusedFor != "" {
+		d, err := filter.ParseDuration(unusedFor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --unused-for %q: %w", unusedFor, err)
+		}
+		cutoff = time.Now().Add(-d)
+	}
+
+	if !getQuiet() {
+		fmt.Fprintf(os.Stderr, "Finding projects in %s...\n", path)
+	}
+	found, err := projects.Find(ctx, path, projects.Options{Exclude: viper.GetStringSlice("exclude")})
+	if err != nil {
+		return nil, err
+	}
+	if cutoff.IsZero() {
+		return found, nil
+	}
+	var unused []projects.Project
+	for _, p := range found {
+		if p.UnusedSince(cutoff) {
+			unused = append(unused, p)
+		}
+	}
+	return unused, nil
+}
+
+// runProjects lists the projects under a path.
+func runProjects(_ *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	found, err := findProjects(ctx, args, projectsUnusedFor)
+	if err != nil {
+		return err
+	}
+	if limit := viper.GetInt("limit"); limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	locale, err := getLocale()
+	if err != nil {
+		return err
+	}
+	return projects.Write(os.Stdout, viper.GetString("output"), found, locale, time.Now())
+}
+
+// runProjectsClean moves the artifact directories of unused projects to
+// the trash and records them in the history.
+func runProjectsClean(_ *cobra.Command, args []string) error {
+	dryRun := viper.GetBool("dry_run")
+	if getReadOnly() && !dryRun {
+		return errReadOnly
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	unused, err := findProjects(ctx, args, projectsCleanUnusedFor)
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		printInfo("No projects unused for %s with build or dependency directories.", projectsCleanUnusedFor)
+		return nil
+	}
+
+	if dryRun {
+		var total int64
+		for _, p := range unused {
+			printInfo("Would trash %s in %s (%s)", artifactNames(p.Artifacts), p.Path, types.FormatSize(p.Size))
+			total += p.Size
+		}
+		printInfo("Would move %s from %d project(s) to the trash", types.FormatSize(total), len(unused))
+		return nil
+	}
+
+	backend, err := deleteBackend("")
+	if err != nil {
+		return err
+	}
+	verb := "Trashed"
+	if backend.Name() != trash.BackendTrash {
+		verb = "Deleted (" + backend.Name() + ")"
+	}
+
+	var records []manifest.FileRecord
+	var freed int64
+	var attempted, failed int
+	for _, p := range unused {
+		var trashed []projects.Artifact
+		var size int64
+		for _, a := range p.Artifacts {
+			if ctx.Err() != nil {
+				break
+			}
+			attempted++
+			info, err := os.Stat(a.Path)
+			var location string
+			if err == nil {
+				r := backend.DeleteMany(ctx, []string{a.Path}, nil)[0]
+				err, location = r.Err, r.Location
+			}
+			if err != nil {
+				printError("%v", err)
+				failed++
+				continue
+			}
+			records = append(records, manifest.FileRecord{
+				Path:      a.Path,
+				Size:      a.Size,
+				ModTime:   info.ModTime(),
+				DeletedAt: time.Now().UTC(),
+				Method:    backend.Name(),
+				Location:  location,
+			})
+			trashed = append(trashed, a)
+			size += a.Size
+		}
+		if len(trashed) > 0 {
+			printInfo("%s %s in %s (%s)", verb, artifactNames(trashed), p.Path, types.FormatSize(size))
+			freed += size
+		}
+	}
+
+	if len(records) > 0 && viper.GetBool("manifest.enabled") {
+		m, err := getManifest()
+		if err == nil {
+			err = m.EnsureDir()
+		}
+		if err == nil {
+			_, err = m.LogDelete(records)
+		}
+		if err != nil {
+			printError("Failed to record the deletion in the history: %v", err)
+		}
+	}
+
+	printInfo("Moved %s to the trash", types.FormatSize(freed))
+	if failed > 0 {
+		return fmt.Errorf("failed to move %d of %d directories to the trash", failed, attempted)
+	}
+	return ctx.Err()
+}
+
+// artifactNames lists the base names of artifacts, e.g. "node_modules, dist".
+func artifactNames(artifacts []projects.Artifact) string {
+	names := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		names[i] = filepath.Base(a.Path)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/query"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <query> [path...]",
+	Short: "Find files with a query, such as 'size > 1GB and ext in (.mp4,.mkv)'",
+	Long: `Find files with a small SQL-like query, for power users and scripts.
+A query is a list of conditions joined by "and", then optional "order by"
+and "limit" clauses:
+
+  size   >, >=, <, <=, =   a size, such as 1GB or 500M
+  mtime  >, >=, <, <=      now, now-180d, or a date, such as 2024-01-31
+  ext    =, in (...)       an extension, such as .mp4 or mp4
+  type   =, in (...)       a type group, such as video
+  path   like, not like    a glob matching the whole path, such as '**/cache/**'
+  depth  <, <=             directory levels below the root
+
+  order by size|mtime|path [asc|desc]   ascending unless desc is given
+  limit N                               0 for every match
+
+The files come from the daemon's index when it is ready, as with a scan,
+and the daemon applies the size bound and path exclusions itself. Without
+a size condition, files below --min-size are not listed. Without a limit
+clause, --limit applies.
+
+Examples:
+  sweep query 'size > 1GB and ext in (.mp4,.mkv) and mtime < now-180d order by size desc limit 50'
+  sweep query 'type = archive and path not like "**/Backups/**"' ~/Downloads
+  sweep query 'size > 100M order by mtime limit 0' -o csv > oldest.csv`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+}
+
+// runQuery lists the files under the given paths that match a query.
+func runQuery(_ *cobra.Command, args []string) error {
+	f, err := query.Compile(args[0], time.Now(), filter.WithLimit(viper.GetInt("limit")))
+	if err != nil {
+		return err
+	}
+
+	remote := getRemote()
+	roots, err := resolveScanRoots(scanPathArgs(args[1:]), remote)
+	if err != nil {
+		return err
+	}
+	opts, err := scanOptions(roots, remote)
+	if err != nil {
+		return err
+	}
+
+	// Push the query's size bound and exclusions down, so the daemon and
+	// the scanner leave out what it would filter out anyway
+	if f.MinSize > 0 || f.MaxSize > 0 {
+		opts.MinSize = f.MinSize
+	}
+	opts.Exclude = append(opts.Exclude, f.Exclude...)
+
+	return runFilteredScan(opts, roots, f)
+}
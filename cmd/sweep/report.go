@@ -0,0 +1,102 @@
+//go:build !lite
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/summary"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var reportBy string
+
+var reportCmd = &cobra.Command{
+	Use:   "report [path]",
+	Short: "Summarize indexed files by extension, type, owner, or age",
+	Long: `Total the files in the daemon's index by a grouping, for capacity
+planning: how many files of each kind there are and how much space they
+take. With a path, only what is under it is counted; without one, every
+indexed path is.
+
+--by chooses the grouping:
+  extension   File extension, case-insensitive
+  type        File type, such as Video or Archive
+  owner       The user who owns the file
+  age-bucket  Time since the file was last modified
+
+Groups are listed largest first, except age buckets, which are listed
+youngest first. Indexes built in aggregates mode only hold their large
+files, so only those are counted.
+
+Examples:
+  sweep report --by type                    # Every indexed path, by type
+  sweep report ~ --by extension -l 10       # The ten largest extensions
+  sweep report /srv --by owner -o csv > owners.csv
+  sweep report --by age-bucket -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportBy, "by", summary.ByType, "group files by extension, type, owner, or age-bucket")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// runReport totals the indexed files under a path by a grouping.
+func runReport(cmd *cobra.Command, args []string) error {
+	var root string
+	if len(args) > 0 {
+		path, err := config.ExpandPath(args[0])
+		if err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		if root, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+	}
+
+	totals, err := summary.NewTotals(reportBy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	target := daemonTarget()
+	if !target.Running() {
+		return errDaemonNotRunning
+	}
+	ctx := cmd.Context()
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	err = daemonClient.ExportIndex(ctx, root, func(e client.IndexEntry) error {
+		if !e.IsDir {
+			totals.Add(summary.File{
+				Path:    e.Path,
+				Size:    e.Size,
+				ModTime: time.Unix(e.ModTime, 0),
+				Owner:   e.Owner,
+				Type:    e.FileType,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	report := totals.Report(root)
+	if limit := viper.GetInt("limit"); limit > 0 && len(report.Groups) > limit {
+		report.Groups = report.Groups[:limit]
+	}
+	return summary.Write(os.Stdout, viper.GetString("output"), report)
+}
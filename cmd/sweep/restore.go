@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/restore"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [id] [path...]",
+	Short: "Move deleted files back from the trash",
+	Long: `Restore files that sweep moved to the trash, using the deletions recorded
+in the history. Without an ID, the most recent deletion that has not been
+restored yet is undone. Paths limit the restore to those files.
+
+When a file's original path is occupied, --on-conflict decides what happens:
+  rename     restore next to it as "name (restored).ext" (default)
+  skip       leave the file in the trash
+  overwrite  move the occupying file to the trash, then restore
+
+Examples:
+  sweep restore                                   # Undo the last deletion
+  sweep restore delete-2026-01-02T10-00-00-ab12cd # Restore one operation
+  sweep restore --dry-run                         # Show what would be restored
+  sweep restore ~/Downloads/big.iso               # Restore one file from the last deletion`,
+	RunE: runRestore,
+}
+
+var restoreConflict string
+
+func init() {
+	restoreCmd.Flags().StringVar(&restoreConflict, "on-conflict", "rename", "when the original path exists: skip, rename, or overwrite")
+	rootCmd.AddCommand(restoreCmd)
+}
+
+// runRestore restores a deletion recorded in the manifest.
+func runRestore(_ *cobra.Command, args []string) error {
+	dryRun := viper.GetBool("dry_run")
+	if getReadOnly() && !dryRun {
+		return errReadOnly
+	}
+	conflict, err := restore.ParseConflict(restoreConflict)
+	if err != nil {
+		return err
+	}
+
+	m, err := getManifest()
+	if err != nil {
+		return fmt.Errorf("failed to initialize manifest: %w", err)
+	}
+
+	// History IDs never contain a path separator, so a leading argument
+	// without one names the operation to restore
+	var id string
+	if len(args) > 0 && !strings.ContainsRune(args[0], filepath.Separator) {
+		id, args = args[0], args[1:]
+	}
+	paths := make([]string, len(args))
+	for i, arg := range args {
+		if paths[i], err = filepath.Abs(arg); err != nil {
+			return fmt.Errorf("invalid path %q: %w", arg, err)
+		}
+	}
+
+	var entry *manifest.Entry
+	if id != "" {
+		if entry, err = m.Get(id); err != nil {
+			return fmt.Errorf("failed to get entry: %w", err)
+		}
+	} else {
+		entries, err := m.List(0)
+		if err != nil {
+			return fmt.Errorf("failed to list history: %w", err)
+		}
+		if entry, err = restore.Latest(entries); err != nil {
+			return err
+		}
+	}
+
+	opts := restore.Options{Conflict: conflict, DryRun: dryRun, Paths: paths}
+	if viper.GetBool("manifest.enabled") {
+		opts.Manifest = m
+	}
+	report, err := restore.Entry(entry, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := restore.Write(os.Stdout, viper.GetString("output"), report); err != nil {
+		return err
+	}
+	if n := report.Count(restore.StatusFailed); n > 0 {
+		return fmt.Errorf("%d file(s) could not be restored", n)
+	}
+	return nil
+}
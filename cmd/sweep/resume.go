@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+	"github.com/spf13/viper"
+)
+
+// Values of ui.resume, whether sweep run without paths resumes the last
+// TUI session instead of scanning the default path.
+const (
+	resumeAsk    = "ask" // The default
+	resumeAlways = "always"
+	resumeNever  = "never"
+)
+
+// offerResume returns the roots of the last TUI session when the user
+// chooses to resume it, or roots otherwise. The TUI then picks up the
+// session's view and cursor, and loads the roots from the daemon's index
+// when it has them, rather than scanning from scratch.
+func offerResume(roots []string) ([]string, error) {
+	store, err := session.Open(config.DefaultSessionsPath())
+	if err != nil {
+		logging.Get("client").Warn("saved sessions unavailable", "error", err)
+		return roots, nil
+	}
+	return resumeRoots(store, roots, viper.GetString("ui.resume"), newOnboarding(os.Stdin, os.Stdout), time.Now())
+}
+
+// resumeRoots decides per mode whether to resume the last session in store
+// instead of scanning roots, asking with prompt in resumeAsk mode. A last
+// session on roots themselves, or on roots that no longer exist, isn't
+// offered.
+func resumeRoots(store *session.Store, roots []string, mode string, prompt *onboarding, now time.Time) ([]string, error) {
+	switch mode {
+	case "", resumeAsk, resumeAlways:
+	case resumeNever:
+		return roots, nil
+	default:
+		return nil, fmt.Errorf("invalid ui.resume %q (available: %s, %s, %s)", mode, resumeAsk, resumeAlways, resumeNever)
+	}
+
+	last, ok := store.Last()
+	if !ok || slices.Equal(last.Roots, roots) {
+		return roots, nil
+	}
+	shown := make([]string, len(last.Roots))
+	for i, root := range last.Roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return roots, nil
+		}
+		shown[i] = prompt.shorten(root)
+	}
+	where := fmt.Sprintf("%s (%s view, %s)", strings.Join(shown, ", "), last.View, reltime.English.FormatAgo(last.Saved, now))
+
+	if mode == resumeAlways {
+		fmt.Fprintf(prompt.out, "Resuming %s\n", where)
+		return last.Roots, nil
+	}
+	resume, err := prompt.confirm("Resume where you left off in "+where+"?", true)
+	if err != nil {
+		return nil, err
+	}
+	if !resume {
+		return roots, nil
+	}
+	return last.Roots, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+)
+
+func TestResumeRoots(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	store, err := session.Open(filepath.Join(t.TempDir(), "sessions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := session.State{View: session.ViewTree, Roots: []string{dir}, Saved: now.Add(-2 * time.Hour)}
+	if err := store.Put(session.Key(dir), last); err != nil {
+		t.Fatal(err)
+	}
+	cwd := []string{"/work"}
+
+	tests := []struct {
+		name   string
+		roots  []string
+		mode   string
+		answer string
+		want   []string
+		asked  bool
+	}{
+		{name: "resumes by default", roots: cwd, mode: "", answer: "\n", want: last.Roots, asked: true},
+		{name: "declined", roots: cwd, mode: resumeAsk, answer: "n\n", want: cwd, asked: true},
+		{name: "always", roots: cwd, mode: resumeAlways, want: last.Roots},
+		{name: "never", roots: cwd, mode: resumeNever, want: cwd},
+		{name: "already on the last roots", roots: last.Roots, mode: resumeAsk, want: last.Roots},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			got, err := resumeRoots(store, tt.roots, tt.mode, newOnboarding(strings.NewReader(tt.answer), out), now)
+			if err != nil {
+				t.Fatalf("resumeRoots() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("resumeRoots() = %v, want %v", got, tt.want)
+			}
+			if asked := strings.Contains(out.String(), "Resume where you left off"); asked != tt.asked {
+				t.Errorf("asked = %v, want %v; output %q", asked, tt.asked, out.String())
+			}
+			if tt.asked && !strings.Contains(out.String(), "tree view, 2 h") {
+				t.Errorf("prompt %q should say how the session was left", out.String())
+			}
+		})
+	}
+
+	if _, err := resumeRoots(store, cwd, "sometimes", newOnboarding(strings.NewReader(""), &bytes.Buffer{}), now); err == nil {
+		t.Error("resumeRoots() with an unknown mode succeeded, want an error")
+	}
+
+	// A root that's gone isn't offered
+	gone := session.State{View: session.ViewList, Roots: []string{filepath.Join(dir, "gone")}, Saved: now}
+	if err := store.Put(session.Key(gone.Roots...), gone); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := resumeRoots(store, cwd, resumeAlways, newOnboarding(strings.NewReader(""), &bytes.Buffer{}), now); !slices.Equal(got, cwd) {
+		t.Errorf("resumeRoots() = %v for a removed root, want %v", got, cwd)
+	}
+}
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/jamesainslie/sweep/pkg/client"
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,7 +20,7 @@ import (
 var (
 	cfgFile string
 	rootCmd = &cobra.Command{
-		Use:   "sweep [path]",
+		Use:   "sweep [path...]",
 		Short: "Find large files consuming disk space",
 		Long: `Sweep scans directories for large files and helps you reclaim disk space.
 
@@ -27,14 +30,16 @@ Use --no-interactive or --output for non-interactive output.
 Examples:
   sweep                      # Scan current directory with TUI
   sweep ~/Downloads          # Scan specific directory
+  sweep ~/Downloads ~/Movies # Scan several directories together
   sweep -s 500M .            # Find files larger than 500MB
   sweep -n -o json .         # Non-interactive JSON output
   sweep -n -o pretty .       # Non-interactive pretty table output
   sweep --type video .       # Find video files
   sweep --older-than 30d .   # Find files older than 30 days
+  sweep --tag to-review ~    # Only show paths tagged "to-review"
   sweep config show          # Show configuration
   sweep history              # View operation history`,
-		Args:              cobra.MaximumNArgs(1),
+		Args:              cobra.ArbitraryArgs,
 		SilenceUsage:      true, // Don't show usage on runtime errors
 		PersistentPreRunE: initializeLogging,
 		RunE:              runScan,
@@ -51,13 +56,25 @@ func init() {
 	rootCmd.PersistentFlags().StringSliceP("exclude", "e", nil, "exclude patterns (can be specified multiple times)")
 	rootCmd.PersistentFlags().BoolP("no-interactive", "n", false, "disable TUI, use text output")
 	rootCmd.PersistentFlags().BoolP("dry-run", "d", false, "don't delete files (preview only)")
+	rootCmd.PersistentFlags().Bool("verify-before-delete", false, "skip files whose size or mtime changed since selection")
+	rootCmd.PersistentFlags().Bool("permanent", false, "delete files under trash.permanent_roots permanently instead of trashing them")
+	rootCmd.PersistentFlags().String("backend", "", "how to delete files: trash, permanent, staging, or upload (default trash.backend)")
+	rootCmd.PersistentFlags().Bool("read-only", false, "disable all actions that modify files (for auditing)")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "minimal output")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "debug output")
 	rootCmd.PersistentFlags().Bool("no-cache", false, "bypass cache, perform full scan")
 	rootCmd.PersistentFlags().Bool("no-daemon", false, "bypass daemon, perform direct scan")
+	rootCmd.PersistentFlags().String("remote", "", "browse the index of a sweepd on another machine (host:port, read-only)")
+	rootCmd.PersistentFlags().String("owner", "", "only include files owned by a user (me, a username, or uid:N)")
+	rootCmd.PersistentFlags().Bool("no-mount-dedupe", false, "scan bind mounts and overlay views even if their content is reachable elsewhere")
+	rootCmd.PersistentFlags().Bool("include-snapshots", false, "scan filesystem snapshots (.zfs/snapshot, .snapshots, Time Machine) too")
+	rootCmd.PersistentFlags().Bool("one-file-system", false, "don't scan other file systems mounted under the path, such as network shares (like du -x)")
+	rootCmd.PersistentFlags().Bool("no-owner", false, "don't look up file owners and groups, for the fastest scans")
+	rootCmd.PersistentFlags().String("symlinks", "", "symlinked directories a direct scan follows (skip, within-root, follow)")
+	rootCmd.PersistentFlags().Bool("list-skipped", false, "list each path a direct scan skipped and why (in -v and structured output)")
 
 	// Output format flags
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "pretty", "output format (pretty, plain, json, jsonl, csv, tsv, yaml, paths, markdown, template)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "pretty", "output format (pretty, plain, json, jsonl, csv, parquet, tsv, yaml, paths, markdown, template)")
 	rootCmd.PersistentFlags().StringVar(&templateStr, "template", "", "Go template for template format")
 	rootCmd.PersistentFlags().StringVarP(&columns, "columns", "c", "size,path", "columns to display (comma-separated)")
 
@@ -71,6 +88,7 @@ func init() {
 	rootCmd.PersistentFlags().IntVar(&maxDepth, "max-depth", 0, "max directory depth (0 for unlimited)")
 	rootCmd.PersistentFlags().StringVar(&sortBy, "sort", "size", "sort by: size, age, path")
 	rootCmd.PersistentFlags().BoolVar(&reverse, "reverse", false, "reverse sort order")
+	rootCmd.PersistentFlags().StringVar(&tagNames, "tag", "", "only include paths with these tags (comma-separated)")
 
 	// Daemon/cache control flags
 	rootCmd.PersistentFlags().StringVar(&maxAge, "max-age", "", "max index age before rescan (e.g., 1h, 30m)")
@@ -86,10 +104,22 @@ func init() {
 	_ = viper.BindPFlag("exclude", rootCmd.PersistentFlags().Lookup("exclude"))
 	_ = viper.BindPFlag("no_interactive", rootCmd.PersistentFlags().Lookup("no-interactive"))
 	_ = viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	_ = viper.BindPFlag("verify_before_delete", rootCmd.PersistentFlags().Lookup("verify-before-delete"))
+	_ = viper.BindPFlag("permanent", rootCmd.PersistentFlags().Lookup("permanent"))
+	_ = viper.BindPFlag("backend", rootCmd.PersistentFlags().Lookup("backend"))
+	_ = viper.BindPFlag("read_only", rootCmd.PersistentFlags().Lookup("read-only"))
 	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("no_cache", rootCmd.PersistentFlags().Lookup("no-cache"))
 	_ = viper.BindPFlag("no_daemon", rootCmd.PersistentFlags().Lookup("no-daemon"))
+	_ = viper.BindPFlag("remote.address", rootCmd.PersistentFlags().Lookup("remote"))
+	_ = viper.BindPFlag("owner", rootCmd.PersistentFlags().Lookup("owner"))
+	_ = viper.BindPFlag("no_mount_dedupe", rootCmd.PersistentFlags().Lookup("no-mount-dedupe"))
+	_ = viper.BindPFlag("include_snapshots", rootCmd.PersistentFlags().Lookup("include-snapshots"))
+	_ = viper.BindPFlag("one_file_system", rootCmd.PersistentFlags().Lookup("one-file-system"))
+	_ = viper.BindPFlag("no_owner", rootCmd.PersistentFlags().Lookup("no-owner"))
+	_ = viper.BindPFlag("symlinks", rootCmd.PersistentFlags().Lookup("symlinks"))
+	_ = viper.BindPFlag("list_skipped", rootCmd.PersistentFlags().Lookup("list-skipped"))
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
 	_ = viper.BindPFlag("template", rootCmd.PersistentFlags().Lookup("template"))
 	_ = viper.BindPFlag("columns", rootCmd.PersistentFlags().Lookup("columns"))
@@ -102,6 +132,7 @@ func init() {
 	_ = viper.BindPFlag("max_depth", rootCmd.PersistentFlags().Lookup("max-depth"))
 	_ = viper.BindPFlag("sort", rootCmd.PersistentFlags().Lookup("sort"))
 	_ = viper.BindPFlag("reverse", rootCmd.PersistentFlags().Lookup("reverse"))
+	_ = viper.BindPFlag("tag", rootCmd.PersistentFlags().Lookup("tag"))
 	_ = viper.BindPFlag("max_age", rootCmd.PersistentFlags().Lookup("max-age"))
 	_ = viper.BindPFlag("force_daemon", rootCmd.PersistentFlags().Lookup("force-daemon"))
 	_ = viper.BindPFlag("force_scan", rootCmd.PersistentFlags().Lookup("force-scan"))
@@ -137,19 +168,25 @@ func initConfig() {
 	viper.SetDefault("min_size", config.DefaultMinSize)
 	viper.SetDefault("default_path", config.DefaultPath)
 	viper.SetDefault("exclude", config.DefaultExclusions)
+	viper.SetDefault("symlinks", "skip")
+	viper.SetDefault("network.detect", true)
 	viper.SetDefault("workers.dir", config.DefaultDirWorkers)
 	viper.SetDefault("workers.file", config.DefaultFileWorkers)
 	viper.SetDefault("manifest.enabled", true)
 	viper.SetDefault("manifest.retention_days", config.DefaultRetentionDays)
+	viper.SetDefault("client.timeout", config.DefaultClientTimeout)
 
-	// Read config file (ignore if not found)
-	_ = viper.ReadInConfig()
+	// Read config file (ignore if not found or invalid; config.Load reports
+	// errors)
+	_, _ = config.ReadInConfig(viper.GetViper())
 }
 
 // Execute runs the root command.
 // Logging is initialized in PersistentPreRunE after flag parsing.
 func Execute() error {
+	stopReopen := logging.ReopenOnHangup()
 	defer func() {
+		stopReopen()
 		_ = logging.Close()
 	}()
 	return rootCmd.Execute()
@@ -157,7 +194,9 @@ func Execute() error {
 
 // initializeLogging is called by PersistentPreRunE after flags are parsed.
 // This ensures verbose flag is available when configuring console output.
-func initializeLogging(_ *cobra.Command, _ []string) error {
+// On the first interactive run it also walks the user through creating a
+// config file.
+func initializeLogging(cmd *cobra.Command, _ []string) error {
 	// Ensure XDG directories
 	if err := config.EnsureConfigDir(); err != nil {
 		return fmt.Errorf("creating config dir: %w", err)
@@ -169,6 +208,12 @@ func initializeLogging(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("creating state dir: %w", err)
 	}
 
+	if needsOnboarding(cmd) {
+		if err := runOnboarding(); err != nil {
+			return err
+		}
+	}
+
 	// Build logging configuration
 	logCfg, cfg, err := buildLoggingConfig()
 	if err != nil {
@@ -188,14 +233,10 @@ func initializeLogging(_ *cobra.Command, _ []string) error {
 	log := logging.Get("client")
 	log.Debug("sweep starting", "version", "0.1.0")
 
-	// Auto-start daemon if configured and not bypassed
-	if cfg.Daemon.AutoStart && !viper.GetBool("no_daemon") {
-		paths := client.DaemonPaths{
-			Binary: cfg.Daemon.BinaryPath,
-			Socket: cfg.Daemon.SocketPath,
-			PID:    cfg.Daemon.PIDPath,
-		}
-		if err := client.EnsureDaemon(paths); err != nil {
+	// Auto-start daemon if configured and not bypassed. 'sweep doctor'
+	// reports the daemon as it finds it.
+	if cfg.Daemon.AutoStart && !viper.GetBool("no_daemon") && getRemote() == "" && (cmd == nil || cmd.Name() != "doctor") {
+		if err := autoStartDaemon(cfg); err != nil {
 			log.Warn("failed to auto-start daemon", "error", err)
 			// Continue anyway - not fatal
 		}
@@ -264,6 +305,56 @@ func getQuiet() bool {
 	return viper.GetBool("quiet")
 }
 
+// errReadOnly is returned by actions that would modify files in read-only mode.
+var errReadOnly = errors.New("read-only mode: refusing to modify files")
+
+// getReadOnly returns true if read-only mode is enabled.
+func getReadOnly() bool {
+	return viper.GetBool("read_only")
+}
+
+// getRemote returns the address of the remote daemon to browse, if any.
+func getRemote() string {
+	return viper.GetString("remote.address")
+}
+
+// getRemoteConfig returns the remote daemon settings, with the address
+// taken from --remote when given.
+func getRemoteConfig() config.RemoteConfig {
+	var remote config.RemoteConfig
+	_ = viper.UnmarshalKey("remote", &remote) // Malformed settings fail the TLS setup instead
+	remote.Address = getRemote()
+	return remote
+}
+
+// getClientTimeout returns the deadline for daemon requests from
+// client.timeout, in the form client.Target.Timeout takes.
+func getClientTimeout() time.Duration {
+	value := viper.GetString("client.timeout")
+	d, err := filter.ParseDuration(value)
+	if err != nil {
+		printVerbose("Ignoring invalid client.timeout %q: %v", value, err)
+		return 0
+	}
+	if d == 0 {
+		return -1 // No deadline
+	}
+	return d
+}
+
+// getLocale returns the locale for relative times, from ui.locale or the
+// environment.
+func getLocale() (reltime.Locale, error) {
+	var ui config.UIConfig
+	if err := viper.UnmarshalKey("ui", &ui); err != nil {
+		return reltime.Locale{}, fmt.Errorf("invalid ui settings in config: %w", err)
+	}
+	if ui.Locale != "" {
+		return reltime.Lookup(ui.Locale), nil
+	}
+	return reltime.Detect(), nil
+}
+
 // printVerbose logs a debug message. Console output is handled by the logger
 // when ConsoleLevel is set (via -v flag).
 // Deprecated: prefer using logging.Get("client").Debug() with structured key-value pairs.
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List, preview, and run cleanup rules",
+	Long: `Cleanup rules are defined under 'rules' in the config file. Each rule
+selects files under a path by name pattern, age, and size. The daemon runs
+rules with a schedule automatically, moving matching files to the trash and
+recording them in the history.
+
+A rule with a preset (gradle, maven, or bazel) cleans that tool's cache
+instead, deleting whole dependency versions or output bases by when they
+were last used, and naming the projects that use them.
+
+Examples:
+  sweep rules                     # List rules and when they next run
+  sweep rules presets             # List the cache presets
+  sweep rules test                # Show what every rule would delete
+  sweep rules test "old logs"     # Preview one rule
+  sweep rules run "old logs"      # Run a rule now`,
+	Args: cobra.NoArgs,
+	RunE: runRulesList,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cleanup rules and their next run",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesList,
+}
+
+var rulesTestCmd = &cobra.Command{
+	Use:   "test [rule...]",
+	Short: "Show the files rules would delete, without deleting them",
+	RunE:  runRulesTest,
+}
+
+var rulesRunCmd = &cobra.Command{
+	Use:   "run [rule...]",
+	Short: "Run rules now, moving matching files to the trash",
+	RunE:  runRulesRun,
+}
+
+var rulesPresetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "List the build cache presets rules can use",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesPresets,
+}
+
+func init() {
+	rulesCmd.AddCommand(rulesListCmd)
+	rulesCmd.AddCommand(rulesTestCmd)
+	rulesCmd.AddCommand(rulesRunCmd)
+	rulesCmd.AddCommand(rulesPresetsCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
+
+// configuredRules loads and validates the rules from the config file.
+func configuredRules() ([]rules.Rule, error) {
+	var configured []config.RuleConfig
+	if err := viper.UnmarshalKey("rules", &configured); err != nil {
+		return nil, fmt.Errorf("invalid rules in config: %w", err)
+	}
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no cleanup rules configured (see 'rules' in the config file)")
+	}
+	list, err := rules.FromConfig(configured)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules in config: %w", err)
+	}
+	return list, nil
+}
+
+// ruleSummary is a rule as listed by 'sweep rules list -o json'.
+type ruleSummary struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Preset    string    `json:"preset,omitempty"`
+	Projects  []string  `json:"projects,omitempty"`
+	Include   []string  `json:"include,omitempty"`
+	OlderThan string    `json:"older_than,omitempty"`
+	MinSize   int64     `json:"min_size,omitempty"`
+	Schedule  string    `json:"schedule,omitempty"`
+	NextRun   time.Time `json:"next_run,omitzero"`
+}
+
+// runRulesList lists the configured rules.
+func runRulesList(_ *cobra.Command, _ []string) error {
+	list, err := configuredRules()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return output.WriteReport(os.Stdout, viper.GetString("output"), list, output.ReportWriters[[]rules.Rule]{
+		output.ReportText: func(w io.Writer, list []rules.Rule) error { return writeRulesTable(w, list, now) },
+		output.ReportJSON: func(w io.Writer, list []rules.Rule) error { return writeRulesJSON(w, list, now) },
+	})
+}
+
+// writeRulesJSON writes the rules as JSON, with when each runs next.
+func writeRulesJSON(w io.Writer, list []rules.Rule, now time.Time) error {
+	summaries := make([]ruleSummary, len(list))
+	for i, r := range list {
+		summaries[i] = ruleSummary{
+			Name:     r.Name,
+			Path:     r.Path,
+			Projects: r.Projects,
+			Include:  r.Include,
+			MinSize:  r.MinSize,
+			Schedule: r.Schedule.String(),
+			NextRun:  r.Schedule.Next(now),
+		}
+		if r.OlderThan > 0 {
+			summaries[i].OlderThan = r.OlderThan.String()
+		}
+		if r.Preset != nil {
+			summaries[i].Preset = r.Preset.Name
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(summaries)
+}
+
+// writeRulesTable writes the rules as a table, with when each runs next.
+func writeRulesTable(w io.Writer, list []rules.Rule, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSCHEDULE\tNEXT RUN\tMATCHES\tPATH")
+	for _, r := range list {
+		schedule, next := "on demand", "-"
+		if !r.Schedule.IsZero() {
+			schedule = r.Schedule.String()
+			if t := r.Schedule.Next(now); !t.IsZero() {
+				next = t.Format("2006-01-02 15:04")
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Name, schedule, next, describeRule(r), r.Path)
+	}
+	return tw.Flush()
+}
+
+// describeRule summarizes a rule's criteria, e.g. "*.log, older than 30d".
+func describeRule(r rules.Rule) string {
+	var parts []string
+	if r.Preset != nil {
+		parts = append(parts, r.Preset.Name+" cache")
+	}
+	if len(r.Include) > 0 {
+		parts = append(parts, strings.Join(r.Include, " "))
+	}
+	if r.OlderThan > 0 && r.Preset != nil {
+		parts = append(parts, "unused for "+formatDays(r.OlderThan))
+	} else if r.OlderThan > 0 {
+		parts = append(parts, "older than "+formatDays(r.OlderThan))
+	}
+	if r.MinSize > 0 {
+		parts = append(parts, "at least "+types.FormatSize(r.MinSize))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDays formats whole-day durations as "30d" and others as Go durations.
+func formatDays(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
+
+// runRulesPresets lists the presets with where each looks by default.
+func runRulesPresets(_ *cobra.Command, _ []string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PRESET\tDEFAULT PATH\tCLEANS")
+	for i := range rules.Presets {
+		p := &rules.Presets[i]
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, p.DefaultPath(), p.Description)
+	}
+	return tw.Flush()
+}
+
+// runRulesTest reports what the selected rules would delete.
+func runRulesTest(_ *cobra.Command, args []string) error {
+	return runRules(args, true)
+}
+
+// runRulesRun runs the selected rules now.
+func runRulesRun(_ *cobra.Command, args []string) error {
+	if getReadOnly() {
+		return errReadOnly
+	}
+	return runRules(args, false)
+}
+
+// runRules runs or previews the named rules, or all rules, and writes a report.
+func runRules(names []string, dryRun bool) error {
+	list, err := configuredRules()
+	if err != nil {
+		return err
+	}
+	selected, err := rules.Select(list, names)
+	if err != nil {
+		return err
+	}
+
+	var m *manifest.Manifest
+	if !dryRun && viper.GetBool("manifest.enabled") {
+		if m, err = getManifest(); err != nil {
+			return fmt.Errorf("failed to initialize manifest: %w", err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	reports := make([]rules.Report, 0, len(selected))
+	var failed int
+	for _, r := range selected {
+		backend, err := deleteBackend(r.Backend)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		report, err := rules.Run(ctx, r, rules.RunOptions{DryRun: dryRun, Manifest: m, Backend: backend})
+		if err != nil {
+			return err
+		}
+		failed += len(report.Failed)
+		reports = append(reports, report)
+	}
+
+	if err := rules.Write(os.Stdout, viper.GetString("output"), reports); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be deleted", failed)
+	}
+	return nil
+}
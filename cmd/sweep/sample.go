@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/sample"
+	"github.com/jamesainslie/sweep/pkg/sweep/tuner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	sampleCount int
+	sampleSeed  uint64
+)
+
+var sampleCmd = &cobra.Command{
+	Use:   "sample [path]",
+	Short: "Pick large files at random, weighted by size, for review",
+	Long: `Pick files at random with a probability proportional to their size and
+list them for review. A file twice as large is twice as likely to be picked,
+so a sample shows the kinds of files that take up most of the space, including
+ones a list of the very largest files would miss.
+
+Files come from the daemon's index when the path is indexed; otherwise the
+path is scanned. --min-size and the filter flags (--type, --older-than, ...)
+choose which files can be picked. Each run picks a new sample; --seed
+repeats one.
+
+Examples:
+  sweep sample                        # 50 files from the current directory
+  sweep sample ~ -N 20                # 20 files from the home directory
+  sweep sample / -s 10M --older-than 1y
+  sweep sample ~ --seed 7 -o json     # The same sample every time`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSample,
+}
+
+func init() {
+	// -n is --no-interactive, so the count has its own shorthand
+	sampleCmd.Flags().IntVarP(&sampleCount, "count", "N", 50, "number of files to pick")
+	sampleCmd.Flags().Uint64Var(&sampleSeed, "seed", 0, "repeat the sample picked with this seed (0 for a new sample each run)")
+	rootCmd.AddCommand(sampleCmd)
+}
+
+// runSample picks files under a path at random, weighted by size, and
+// writes them in the --output format.
+func runSample(_ *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	remote := getRemote()
+	path, err := resolveScanPath(path, remote)
+	if err != nil {
+		return err
+	}
+	if sampleCount < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+
+	minSizeStr := viper.GetString("min_size")
+	if minSizeStr == "" {
+		minSizeStr = config.DefaultMinSize
+	}
+	minSize, err := types.ParseSize(minSizeStr)
+	if err != nil {
+		return fmt.Errorf("invalid minimum size %q: %w", minSizeStr, err)
+	}
+	opts := types.ScanOptions{
+		Root:             path,
+		MinSize:          minSize,
+		Exclude:          viper.GetStringSlice("exclude"),
+		IncludeSnapshots: viper.GetBool("include_snapshots"),
+		OneFileSystem:    viper.GetBool("one_file_system"),
+	}
+	if remote == "" {
+		opts.Exclude = append(opts.Exclude, skippedMounts(path)...)
+	}
+	mc, err := mountsConfig()
+	if err != nil {
+		return err
+	}
+	opts.SkipMounts, opts.CrossMounts = mc.Skip, mc.Cross
+	if remote == "" {
+		workers, err := forNetwork(tuner.OptimalConfig{
+			DirWorkers:  config.DefaultDirWorkers,
+			FileWorkers: config.DefaultFileWorkers,
+		}, []string{path})
+		if err != nil {
+			return err
+		}
+		opts.DirWorkers, opts.FileWorkers = workers.DirWorkers, workers.FileWorkers
+	}
+	if spec := viper.GetString("owner"); spec != "" {
+		if remote != "" {
+			return fmt.Errorf("--owner cannot be used with --remote")
+		}
+		if opts.Owner, err = owner.Parse(spec); err != nil {
+			return err
+		}
+	}
+	if opts.Symlinks, err = types.ParseSymlinkPolicy(viper.GetString("symlinks")); err != nil {
+		return err
+	}
+
+	// Every matching file can be picked, so --limit doesn't apply
+	f, err := buildFilter()
+	if err != nil {
+		return fmt.Errorf("failed to build filter: %w", err)
+	}
+	f.Limit = 0
+
+	outFormat := viper.GetString("output")
+	if outFormat == "" {
+		outFormat = "pretty"
+	}
+	formatter, err := outputFormatter(outFormat)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	startTime := time.Now()
+	noDaemon := viper.GetBool("no_daemon") || viper.GetBool("force_scan")
+	if remote != "" && noDaemon {
+		return fmt.Errorf("--remote cannot be combined with --no-daemon or --force-scan")
+	}
+	forceDaemon := remote != "" || viper.GetBool("force_daemon")
+	scanned, usedDaemon, err := scanRoot(ctx, opts, nil, noDaemon, forceDaemon, outFormat, nil)
+	if err != nil {
+		return err
+	}
+	scanned.Elapsed = time.Since(startTime)
+
+	result := convertToOutputResult(scanned, f, path, usedDaemon, false)
+	candidates := len(result.Files)
+	var candidateSize int64
+	for _, file := range result.Files {
+		candidateSize += file.Size
+	}
+	result.Files = sample.Weighted(result.Files, sampleCount, func(file output.FileInfo) int64 {
+		return file.Size
+	}, sample.NewRand(sampleSeed))
+	result.TotalFiles = len(result.Files)
+
+	if !getQuiet() && outFormat == "pretty" {
+		printInfo("Picked %d of %d files (%s), weighted by size", len(result.Files), candidates,
+			types.FormatSize(candidateSize))
+	}
+
+	var buf bytes.Buffer
+	if err := formatter.Format(&buf, result); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Print(buf.String())
+	return nil
+}
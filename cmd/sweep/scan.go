@@ -2,62 +2,122 @@ package main
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/jamesainslie/sweep/cmd/sweep/tui"
-	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/backup"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/dryrun"
 	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
 	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
 	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
 	"github.com/jamesainslie/sweep/pkg/sweep/tuner"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// scanCmd names the scan the root command runs, so that
+// "sweep scan ~/Downloads ~/Movies" works as well as the shorter form.
+var scanCmd = &cobra.Command{
+	Use:   "scan [path...]",
+	Short: "Scan one or more directories for large files (the default command)",
+	Long: `Scan one or more directories for large files. This is what sweep does
+without a command; it takes the same flags.
+
+Several paths are scanned concurrently and their results merged. The TUI
+groups the files under a collapsible section per root ('z' folds the
+section under the cursor), and JSON, YAML, and CSV output give each file's
+root and per-root totals.
+
+Examples:
+  sweep scan ~/Downloads ~/Movies /Volumes/Backup
+  sweep scan -n -o csv ~/Downloads ~/Movies > large.csv`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runScan,
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+}
+
 // runScan is the main scan command handler.
 func runScan(_ *cobra.Command, args []string) error {
-	// Determine scan path
-	scanPath := "."
-	if len(args) > 0 {
-		scanPath = args[0]
-	} else if defaultPath := viper.GetString("default_path"); defaultPath != "" {
-		scanPath = defaultPath
+	remote := getRemote()
+	roots, err := resolveScanRoots(scanPathArgs(args), remote)
+	if err != nil {
+		return err
 	}
 
-	// Expand ~ in path
-	expandedPath, err := config.ExpandPath(scanPath)
-	if err != nil {
-		return fmt.Errorf("failed to expand path: %w", err)
+	// Determine output mode
+	noInteractive := viper.GetBool("no_interactive")
+	outFormat := viper.GetString("output")
+
+	// If output format is explicitly set (not default), force non-interactive mode
+	if outFormat != "" && outFormat != "pretty" {
+		noInteractive = true
 	}
 
-	// Convert to absolute path
-	absPath, err := filepath.Abs(expandedPath)
-	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
+	// Without paths, the TUI can resume the last session instead
+	if len(args) == 0 && !noInteractive && remote == "" && isTerminal(os.Stdin) && isTerminal(os.Stdout) {
+		if roots, err = offerResume(roots); err != nil {
+			return err
+		}
 	}
 
-	// Verify path exists and is accessible
-	info, err := os.Stat(absPath)
+	opts, err := scanOptions(roots, remote)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("path does not exist: %s", absPath)
-		}
-		return fmt.Errorf("cannot access path: %w", err)
+		return err
 	}
-	if !info.IsDir() {
-		return fmt.Errorf("path is not a directory: %s", absPath)
+
+	// Run scan
+	if noInteractive {
+		return runNonInteractiveScan(opts, roots)
 	}
 
+	// Interactive TUI mode
+	return runInteractiveTUI(opts, roots)
+}
+
+// scanPathArgs returns the paths to scan given on the command line, or the
+// configured default path, or the current directory.
+func scanPathArgs(args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+	if defaultPath := viper.GetString("default_path"); defaultPath != "" {
+		return []string{defaultPath}
+	}
+	return []string{"."}
+}
+
+// scanOptions returns the options for scanning roots, from the flags and
+// the configuration.
+func scanOptions(roots []string, remote string) (types.ScanOptions, error) {
+	absPath := roots[0]
+
 	// Parse minimum size
 	minSizeStr := viper.GetString("min_size")
 	if minSizeStr == "" {
@@ -66,7 +126,7 @@ func runScan(_ *cobra.Command, args []string) error {
 
 	minSize, err := types.ParseSize(minSizeStr)
 	if err != nil {
-		return fmt.Errorf("invalid minimum size %q: %w", minSizeStr, err)
+		return types.ScanOptions{}, fmt.Errorf("invalid minimum size %q: %w", minSizeStr, err)
 	}
 
 	// Get worker configuration
@@ -96,6 +156,16 @@ func runScan(_ *cobra.Command, args []string) error {
 		resources.CPUCores,
 		types.FormatSize(resources.TotalRAM),
 		types.FormatSize(resources.AvailableRAM))
+	// Roots are scanned concurrently, so they share the workers
+	if len(roots) > 1 {
+		optConfig.DirWorkers = max(1, optConfig.DirWorkers/len(roots))
+		optConfig.FileWorkers = max(1, optConfig.FileWorkers/len(roots))
+	}
+	if remote == "" {
+		if optConfig, err = forNetwork(optConfig, roots); err != nil {
+			return types.ScanOptions{}, err
+		}
+	}
 	printVerbose("Config: %d dir workers, %d file workers, queue size %d",
 		optConfig.DirWorkers, optConfig.FileWorkers, optConfig.DirQueueSize)
 
@@ -104,36 +174,138 @@ func runScan(_ *cobra.Command, args []string) error {
 
 	// Build scan options
 	opts := types.ScanOptions{
-		Root:        absPath,
-		MinSize:     minSize,
-		Exclude:     exclude,
-		DirWorkers:  optConfig.DirWorkers,
-		FileWorkers: optConfig.FileWorkers,
+		Root:          absPath,
+		MinSize:       minSize,
+		Exclude:       exclude,
+		DirWorkers:    optConfig.DirWorkers,
+		FileWorkers:   optConfig.FileWorkers,
+		RecordSkipped: viper.GetBool("list_skipped"),
 	}
 
-	// Determine output mode
-	noInteractive := viper.GetBool("no_interactive")
-	outFormat := viper.GetString("output")
+	// Skip bind mounts and overlay views whose content is reachable elsewhere
+	// under the scan root, so container storage is not counted twice, and
+	// snapshots, which would count everything in them again.
+	opts.IncludeSnapshots = viper.GetBool("include_snapshots")
+	if remote == "" {
+		for _, root := range roots {
+			opts.SkipMounts = append(opts.SkipMounts, skippedMounts(root)...)
+		}
+	}
 
-	// If output format is explicitly set (not default), force non-interactive mode
-	if outFormat != "" && outFormat != "pretty" {
-		noInteractive = true
+	// Stay off mounts the config names, and other file systems if asked
+	opts.OneFileSystem = viper.GetBool("one_file_system")
+	mc, err := mountsConfig()
+	if err != nil {
+		return opts, err
 	}
+	opts.SkipMounts = append(opts.SkipMounts, mc.Skip...)
+	opts.CrossMounts = mc.Cross
 
-	// Run scan
-	if noInteractive {
-		return runNonInteractiveScan(opts)
+	// Restrict to one user's files
+	if spec := viper.GetString("owner"); spec != "" {
+		if remote != "" {
+			return opts, fmt.Errorf("--owner cannot be used with --remote")
+		}
+		opts.Owner, err = owner.Parse(spec)
+		if err != nil {
+			return opts, err
+		}
+		printVerbose("Only including files owned by %s", opts.Owner)
 	}
 
-	// Interactive TUI mode
-	return runInteractiveTUI(opts)
+	// Follow symlinked directories by policy
+	if opts.Symlinks, err = types.ParseSymlinkPolicy(viper.GetString("symlinks")); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// resolveScanRoots resolves each scan path argument as resolveScanPath
+// does, dropping repeated roots and roots inside another, whose files
+// would otherwise be listed twice.
+func resolveScanRoots(scanPaths []string, remote string) ([]string, error) {
+	resolved := make([]string, 0, len(scanPaths))
+	for _, p := range scanPaths {
+		root, err := resolveScanPath(p, remote)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, root)
+	}
+
+	var roots []string
+	for i, root := range resolved {
+		covered := false
+		for j, other := range resolved {
+			if i != j && isWithin(other, root) && (root != other || j < i) {
+				printVerbose("Skipping %s, already scanned as part of %s", root, other)
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+// resolveScanPath turns the scan path argument into a checked absolute
+// path. Paths on a remote machine can't be checked here, so they must
+// already be absolute.
+func resolveScanPath(scanPath, remote string) (string, error) {
+	if remote != "" {
+		if !filepath.IsAbs(scanPath) {
+			return "", fmt.Errorf("--remote needs an absolute path on %s, got %q", remote, scanPath)
+		}
+		return filepath.Clean(scanPath), nil
+	}
+
+	// Expand ~ in path
+	expandedPath, err := config.ExpandPath(scanPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path: %w", err)
+	}
+
+	// Convert to absolute path
+	absPath, err := filepath.Abs(expandedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	// Verify path exists and is accessible
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("path does not exist: %s", absPath)
+		}
+		return "", fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("path is not a directory: %s", absPath)
+	}
+
+	return absPath, nil
 }
 
 // runInteractiveTUI runs the TUI application.
-func runInteractiveTUI(opts types.ScanOptions) error {
+func runInteractiveTUI(opts types.ScanOptions, roots []string) error {
 	dryRun := viper.GetBool("dry_run")
 	noDaemon := viper.GetBool("no_daemon")
 
+	// A remote session only shows the remote index, so check it before
+	// the TUI takes over the terminal
+	remote := getRemoteConfig()
+	if remote.Address != "" {
+		for _, root := range roots {
+			if err := checkRemoteIndex(root); err != nil {
+				return err
+			}
+		}
+		noDaemon = false
+	}
+
 	// Re-initialize logging for TUI mode (enables log buffer, disables console)
 	if err := initTUILogging(); err != nil {
 		return fmt.Errorf("failed to initialize TUI logging: %w", err)
@@ -145,15 +317,119 @@ func runInteractiveTUI(opts types.ScanOptions) error {
 		return fmt.Errorf("failed to build filter: %w", err)
 	}
 
+	// Tags are optional; the TUI simply disables tagging if the store can't be opened
+	tagStore, err := getTagStore()
+	if err != nil {
+		logging.Get("client").Warn("tags unavailable", "error", err)
+	}
+
+	// Without saved sessions, the TUI starts at the top of the list as usual
+	sessions, err := session.Open(config.DefaultSessionsPath())
+	if err != nil {
+		logging.Get("client").Warn("saved sessions unavailable", "error", err)
+	}
+
+	var ui config.UIConfig
+	if err := viper.UnmarshalKey("ui", &ui); err != nil {
+		return fmt.Errorf("invalid ui settings in config: %w", err)
+	}
+	columns, err := tui.NewColumnLayout(ui.Columns, ui.Widths, ui.Units)
+	if err != nil {
+		return fmt.Errorf("invalid ui settings in config: %w", err)
+	}
+	columns.Locale = reltime.Detect()
+	if ui.Locale != "" {
+		columns.Locale = reltime.Lookup(ui.Locale)
+	}
+	ageColors, err := tui.NewAgeGradient(ui.AgeColors)
+	if err != nil {
+		return fmt.Errorf("invalid ui settings in config: %w", err)
+	}
+
+	quota, err := trashQuota()
+	if err != nil {
+		return err
+	}
+	permanent, err := permanentRoots()
+	if err != nil {
+		return err
+	}
+	backend, err := deleteBackend("")
+	if err != nil {
+		return err
+	}
+	if backend.Name() == trash.BackendPermanent {
+		// Permanent deletes from the TUI stay limited to permanent_roots.
+		logging.Get("client").Warn("the permanent backend trashes files in the TUI; use --permanent with trash.permanent_roots")
+		backend = nil
+	}
+	confirm, err := confirmPolicy()
+	if err != nil {
+		return err
+	}
+	var move config.MoveConfig
+	if err := viper.UnmarshalKey("move", &move); err != nil {
+		return fmt.Errorf("invalid move settings in config: %w", err)
+	}
+
+	backups, err := backupChecker()
+	if err != nil {
+		return err
+	}
+
+	// Deletions are recorded so they can be undone with 'U'
+	var mf *manifest.Manifest
+	if viper.GetBool("manifest.enabled") {
+		if mf, err = getManifest(); err != nil {
+			logging.Get("client").Warn("history unavailable, undo disabled", "error", err)
+		}
+	}
+
+	// 'x' adds exclusions to the config in use, or the one sweep would create
+	configPath := viper.ConfigFileUsed()
+	if configPath == "" {
+		configPath, _ = config.Path()
+	}
+
 	tuiOpts := tui.Options{
 		Root:        opts.Root,
 		MinSize:     opts.MinSize,
-		Exclude:     opts.Exclude,
+		Exclude:     append(slices.Clone(opts.Exclude), opts.SkipMounts...),
+		Symlinks:    opts.Symlinks,
 		DirWorkers:  opts.DirWorkers,
 		FileWorkers: opts.FileWorkers,
 		DryRun:      dryRun,
 		NoDaemon:    noDaemon,
 		Filter:      f,
+		Tags:        tagStore,
+		Owner:       opts.Owner,
+		Columns:     &columns,
+		TrashQuota:  quota,
+		Manifest:    mf,
+		Backups:     backups,
+		AgeColors:   ageColors,
+		Sessions:    sessions,
+		Confirm:     confirm,
+		ConfigPath:  configPath,
+		ReadOnly:    getReadOnly() || remote.Address != "",
+		Remote:      remote,
+		Timeout:     getClientTimeout(),
+		NoOwner:     viper.GetBool("no_owner"),
+
+		VerifyBeforeDelete: viper.GetBool("verify_before_delete"),
+		RecentDays:         viper.GetInt("confirm.recent_days"),
+		IncludeSnapshots:   opts.IncludeSnapshots,
+		OneFileSystem:      opts.OneFileSystem,
+		CrossMounts:        opts.CrossMounts,
+		PermanentRoots:     permanent,
+		Backend:            backend,
+		MoveDest:           move.Dest,
+		MoveVerify:         move.Verify,
+		MoveSymlink:        move.Symlink,
+		Version:            fmt.Sprintf("%s (%s)", version, commit),
+	}
+	if len(roots) > 1 {
+		tuiOpts.Roots = roots
 	}
 
 	return tui.Run(tuiOpts)
@@ -167,6 +443,17 @@ type scanResult struct {
 	TotalSize    int64            `json:"total_size"`
 	Elapsed      time.Duration    `json:"elapsed"`
 	Errors       []scanError      `json:"errors,omitempty"`
+	Truncated    bool             `json:"truncated,omitempty"` // The daemon returned only the largest matches
+
+	// Skipped counts what a direct scan left out by reason, and
+	// SkippedPaths lists it with --list-skipped.
+	Skipped      map[types.SkipReason]int64 `json:"skipped,omitempty"`
+	SkippedPaths []types.SkippedPath        `json:"skipped_paths,omitempty"`
+
+	// Roots holds each root's scan statistics when several were scanned,
+	// and fileRoots the root each file was found under.
+	Roots     []output.RootSummary `json:"roots,omitempty"`
+	fileRoots map[string]string
 }
 
 type scanError struct {
@@ -174,33 +461,29 @@ type scanError struct {
 	Error string `json:"error"`
 }
 
-// runNonInteractiveScan runs the scan in non-interactive mode.
-func runNonInteractiveScan(opts types.ScanOptions) error {
+// runNonInteractiveScan runs the scan of roots in non-interactive mode.
+// opts.Root is ignored in favor of each root.
+func runNonInteractiveScan(opts types.ScanOptions, roots []string) error {
 	// Build filter from CLI flags
 	f, err := buildFilter()
 	if err != nil {
 		return fmt.Errorf("failed to build filter: %w", err)
 	}
+	return runFilteredScan(opts, roots, f)
+}
 
+// runFilteredScan scans roots, through the daemon where it can, and prints
+// the files that pass f.
+func runFilteredScan(opts types.ScanOptions, roots []string, f *filter.Filter) error {
 	// Get output formatter
 	outFormat := viper.GetString("output")
 	if outFormat == "" {
 		outFormat = "pretty"
 	}
 
-	var formatter output.Formatter
-	if outFormat == "template" {
-		// Handle custom template format
-		tmplStr := viper.GetString("template")
-		if tmplStr == "" {
-			return fmt.Errorf("--template is required when using -o template")
-		}
-		formatter = output.NewTemplateFormatter(tmplStr)
-	} else {
-		formatter, err = output.Get(outFormat)
-		if err != nil {
-			return fmt.Errorf("unknown output format %q: available formats are %v", outFormat, output.Available())
-		}
+	formatter, err := outputFormatter(outFormat)
+	if err != nil {
+		return err
 	}
 
 	// Setup context with cancellation for graceful shutdown
@@ -230,41 +513,64 @@ func runNonInteractiveScan(opts types.ScanOptions) error {
 		noDaemon = true
 	}
 
-	var internalResult *scanResult
-	usedDaemon := false
-
-	// Try daemon first if available
-	if !noDaemon {
-		internalResult, usedDaemon = tryDaemonScan(ctx, opts, f)
+	// There is nothing local to fall back to for a remote path
+	remote := getRemote()
+	if remote != "" {
+		if noDaemon {
+			return fmt.Errorf("--remote cannot be combined with --no-daemon or --force-scan")
+		}
+		forceDmn = true
 	}
 
-	// Handle force-daemon failure
-	if forceDmn && !usedDaemon {
-		return fmt.Errorf("daemon unavailable but --force-daemon was specified")
+	// Show how far along direct scans are, unless stderr is redirected
+	var progress *progressLine
+	if !getQuiet() && isTerminal(os.Stderr) {
+		progress = newProgressLine(os.Stderr, len(roots))
 	}
 
-	// Fallback to direct scan if daemon not used
-	if !usedDaemon {
-		if !getQuiet() {
-			printInfo("Scanning %s for files >= %s...", opts.Root, types.FormatSize(opts.MinSize))
-		}
-
-		// Run the scan using the fast scanner
-		internalResult, err = performScan(ctx, opts)
-		if err != nil {
-			if errors.Is(ctx.Err(), context.Canceled) {
-				printInfo("Scan cancelled")
-				return nil
-			}
-			return fmt.Errorf("scan failed: %w", err)
+	// Scan the roots concurrently, each through the daemon when it can
+	results := make([]*scanResult, len(roots))
+	usedDaemon := make([]bool, len(roots))
+	errs := make([]error, len(roots))
+	var wg sync.WaitGroup
+	for i, root := range roots {
+		rootOpts := opts
+		rootOpts.Root = root
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], usedDaemon[i], errs[i] = scanRoot(ctx, rootOpts, f, noDaemon, forceDmn, outFormat, progress.reporter(i))
+		}()
+	}
+	wg.Wait()
+	progress.clear()
+	if err := errors.Join(errs...); err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			printInfo("Scan cancelled")
+			return nil
 		}
+		return err
 	}
 
+	internalResult := results[0]
+	source := roots[0]
+	if len(roots) > 1 {
+		internalResult = mergeRootResults(roots, results)
+		source = strings.Join(roots, ", ")
+	}
 	elapsed := time.Since(startTime)
 	internalResult.Elapsed = elapsed
 
+	logSkipped(internalResult)
+
 	// Convert to output.Result and apply filter
-	result := convertToOutputResult(internalResult, f, opts.Root, usedDaemon, interrupted)
+	result := convertToOutputResult(internalResult, f, source, slices.Contains(usedDaemon, true), interrupted)
+	if !viper.GetBool("no_owner") && remote == "" {
+		fillOwners(ctx, result.Files, opts.FileWorkers)
+	}
+	if viper.GetBool("dry_run") {
+		result.DryRun = projectDelete(result.Files, remote != "")
+	}
 
 	// Output results
 	var buf bytes.Buffer
@@ -276,139 +582,153 @@ func runNonInteractiveScan(opts types.ScanOptions) error {
 	return nil
 }
 
-// tryDaemonScan attempts to use the daemon for scanning.
-// Returns the result and a boolean indicating if the daemon was used.
-func tryDaemonScan(ctx context.Context, opts types.ScanOptions, f *filter.Filter) (*scanResult, bool) {
-	// Check if daemon is running
-	pidPath := client.DefaultPIDPath()
-	if !client.IsDaemonRunning(pidPath) {
-		printVerbose("Daemon not running, using direct scan")
-		return nil, false
+// projectDelete reports what deleting files would do. Online-only cloud
+// files count at what they store locally, and the volumes of a remote
+// index's files aren't measured.
+func projectDelete(files []output.FileInfo, remote bool) *dryrun.Report {
+	projected := make([]dryrun.File, len(files))
+	for i, f := range files {
+		projected[i] = dryrun.File{Path: f.Path, Size: cloud.Local(f.Cloud, f.Size), ModTime: f.ModTime}
 	}
-
-	// Try to connect to daemon
-	socketPath := client.DefaultSocketPath()
-	daemonClient, err := client.ConnectWithContext(ctx, socketPath)
-	if err != nil {
-		printVerbose("Failed to connect to daemon: %v", err)
-		return nil, false
+	opts := dryrun.Options{RecentDays: viper.GetInt("confirm.recent_days")}
+	if remote {
+		opts.Mount = func(string) (string, error) { return "", errRemoteVolume }
 	}
-	defer daemonClient.Close()
+	report := dryrun.Project(projected, opts)
+	return &report
+}
 
-	// Check if index is ready for this path
-	ready, err := daemonClient.IsIndexReady(ctx, opts.Root)
-	if err != nil {
-		printVerbose("Failed to check index status: %v", err)
-		// Trigger indexing in background for next time (uses fresh context)
-		go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
-		return nil, false
-	}
-
-	// Check max-age if specified
-	maxAgeStr := viper.GetString("max_age")
-	if maxAgeStr != "" {
-		maxAgeDur, parseErr := filter.ParseDuration(maxAgeStr)
-		if parseErr == nil {
-			status, _ := daemonClient.GetIndexStatus(ctx, opts.Root)
-			if status != nil && !status.LastUpdated.IsZero() {
-				indexAge := time.Since(status.LastUpdated)
-				if indexAge > maxAgeDur {
-					printVerbose("Index too old (%v > %v), triggering background indexing", indexAge, maxAgeDur)
-					go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
-					return nil, false
-				}
-			}
+// errRemoteVolume is why the volumes of a remote index's files aren't
+// measured.
+var errRemoteVolume = errors.New("volume is on the remote machine")
+
+// fillOwners looks up the owners of files that have none. It runs on the
+// files left after filtering and limiting, so a scan only pays for the
+// files it shows.
+func fillOwners(ctx context.Context, files []output.FileInfo, workers int) {
+	var (
+		idx   []int
+		paths []string
+	)
+	for i, file := range files {
+		if file.Owner == "" {
+			idx = append(idx, i)
+			paths = append(paths, file.Path)
 		}
 	}
-
-	if !ready {
-		printVerbose("Index not ready for %s, triggering background indexing", opts.Root)
-		// Trigger indexing in background for next time (uses fresh context)
-		go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
-		return nil, false
+	for j, o := range scanner.LookupOwners(ctx, paths, workers) {
+		files[idx[j]].Owner = o.Owner
 	}
+}
 
-	// Index is ready, query the daemon
-	printVerbose("Using daemon index for %s", opts.Root)
-	// Pass filter limit to daemon for server-side limiting
-	limit := 0
-	if f != nil && f.Limit > 0 {
-		// Request more than needed since we'll filter client-side
-		// The daemon only filters by min-size and exclude patterns
-		limit = f.Limit * 10 // Request extra for client-side filtering
-		if limit > 10000 {
-			limit = 10000 // Cap at reasonable limit
+// outputFormatter returns the formatter for the --output format.
+func outputFormatter(outFormat string) (output.Formatter, error) {
+	if outFormat == "parquet" && isTerminal(os.Stdout) {
+		return nil, fmt.Errorf("parquet output is binary: redirect it to a file, for example 'sweep -n -o parquet > files.parquet'")
+	}
+	if outFormat == "template" {
+		// Handle custom template format
+		tmplStr := viper.GetString("template")
+		if tmplStr == "" {
+			return nil, fmt.Errorf("--template is required when using -o template")
 		}
+		return output.NewTemplateFormatter(tmplStr), nil
 	}
-	files, err := daemonClient.GetLargeFiles(ctx, opts.Root, opts.MinSize, opts.Exclude, limit)
+	formatter, err := output.Get(outFormat)
 	if err != nil {
-		printVerbose("Failed to query daemon: %v", err)
-		return nil, false
+		return nil, fmt.Errorf("unknown output format %q: available formats are %v", outFormat, output.Available())
 	}
+	return formatter, nil
+}
 
-	// Sort files by size (largest first) - should already be sorted but ensure consistency
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Size > files[j].Size
-	})
-
-	// Calculate total size
-	var totalSize int64
-	for _, file := range files {
-		totalSize += file.Size
+// scanRoot scans opts.Root through the daemon's index when it can, and
+// directly otherwise, passing onProgress, if not nil, the direct scan's
+// progress. It reports whether the daemon was used.
+func scanRoot(ctx context.Context, opts types.ScanOptions, f *filter.Filter, noDaemon, forceDaemon bool, outFormat string, onProgress func(types.ScanProgress)) (*scanResult, bool, error) {
+	// Try daemon first if available
+	if !noDaemon {
+		if result, ok := tryDaemonScan(ctx, opts, f); ok {
+			return result, true, nil
+		}
 	}
 
-	// Get index status for statistics
-	status, err := daemonClient.GetIndexStatus(ctx, opts.Root)
-	if err != nil {
-		printVerbose("Failed to get index status: %v", err)
+	// Handle force-daemon failure
+	if forceDaemon {
+		if remote := getRemote(); remote != "" {
+			return nil, false, fmt.Errorf("no index for %s on %s (run with -v for details)", opts.Root, remote)
+		}
+		return nil, false, fmt.Errorf("daemon unavailable but --force-daemon was specified")
 	}
 
-	result := &scanResult{
-		Files:        files,
-		DirsScanned:  0,
-		FilesScanned: 0,
-		TotalSize:    totalSize,
+	// Fallback to direct scan; other formats are parsed by tools, so
+	// stdout carries only the output
+	if !getQuiet() && outFormat == "pretty" {
+		printInfo("Scanning %s for files >= %s...", opts.Root, types.FormatSize(opts.MinSize))
 	}
 
-	if status != nil {
-		result.DirsScanned = status.DirsIndexed
-		result.FilesScanned = status.FilesIndexed
+	// Run the scan using the fast scanner
+	result, err := performScan(ctx, opts, onProgress)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("scan of %s failed: %w", opts.Root, err)
 	}
-
-	return result, true
+	return result, false, nil
 }
 
-// triggerBackgroundIndexing triggers indexing in the background.
-func triggerBackgroundIndexing(path string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Check if daemon is running
-	pidPath := client.DefaultPIDPath()
-	if !client.IsDaemonRunning(pidPath) {
-		return
-	}
-
-	socketPath := client.DefaultSocketPath()
-	daemonClient, err := client.ConnectWithContext(ctx, socketPath)
-	if err != nil {
-		return
+// mergeRootResults combines the results of scanning each of roots into
+// one, largest files first, keeping each root's statistics.
+func mergeRootResults(roots []string, results []*scanResult) *scanResult {
+	merged := &scanResult{fileRoots: make(map[string]string)}
+	for i, r := range results {
+		merged.Files = append(merged.Files, r.Files...)
+		merged.DirsScanned += r.DirsScanned
+		merged.FilesScanned += r.FilesScanned
+		merged.TotalSize += r.TotalSize
+		merged.Errors = append(merged.Errors, r.Errors...)
+		for reason, n := range r.Skipped {
+			if merged.Skipped == nil {
+				merged.Skipped = make(map[types.SkipReason]int64)
+			}
+			merged.Skipped[reason] += n
+		}
+		merged.SkippedPaths = append(merged.SkippedPaths, r.SkippedPaths...)
+		merged.Truncated = merged.Truncated || r.Truncated
+		merged.Roots = append(merged.Roots, output.RootSummary{
+			Path:         roots[i],
+			DirsScanned:  r.DirsScanned,
+			FilesScanned: r.FilesScanned,
+		})
+		for _, file := range r.Files {
+			merged.fileRoots[file.Path] = roots[i]
+		}
 	}
-	defer daemonClient.Close()
-
-	// Trigger indexing (don't wait for completion)
-	_ = daemonClient.TriggerIndex(ctx, path, false)
+	sort.SliceStable(merged.Files, func(i, j int) bool {
+		return merged.Files[i].Size > merged.Files[j].Size
+	})
+	return merged
 }
 
 // performScan executes the directory scan with the given options using the fast scanner.
-func performScan(ctx context.Context, opts types.ScanOptions) (*scanResult, error) {
+func performScan(ctx context.Context, opts types.ScanOptions, onProgress func(types.ScanProgress)) (*scanResult, error) {
 	// Create scanner with fastwalk-based implementation
 	s := scanner.New(scanner.Options{
-		Root:        opts.Root,
-		MinSize:     opts.MinSize,
-		Exclude:     opts.Exclude,
-		DirWorkers:  opts.DirWorkers,
-		FileWorkers: opts.FileWorkers,
+		Root:             opts.Root,
+		MinSize:          opts.MinSize,
+		Exclude:          opts.Exclude,
+		DirWorkers:       opts.DirWorkers,
+		FileWorkers:      opts.FileWorkers,
+		Owner:            opts.Owner,
+		SkipMounts:       opts.SkipMounts,
+		OneFileSystem:    opts.OneFileSystem,
+		CrossMounts:      opts.CrossMounts,
+		RecordSkipped:    opts.RecordSkipped,
+		IncludeSnapshots: opts.IncludeSnapshots,
+		Symlinks:         opts.Symlinks,
+		OnProgress:       onProgress,
+
+		ExpectedEntries: scanner.LastEntries(config.CacheDir(), opts.Root),
 	})
 
 	// Run the scan
@@ -417,6 +737,13 @@ func performScan(ctx context.Context, opts types.ScanOptions) (*scanResult, erro
 		return nil, err
 	}
 
+	// The next scan of the root estimates its progress from this one
+	if ctx.Err() == nil {
+		if err := scanner.RecordEntries(config.CacheDir(), opts.Root, scanRes.DirsScanned+scanRes.FilesScanned); err != nil {
+			logging.Get("client").Debug("failed to record scan size", "root", opts.Root, "error", err)
+		}
+	}
+
 	// Convert to internal result format
 	result := &scanResult{
 		Files:        scanRes.Files,
@@ -424,6 +751,8 @@ func performScan(ctx context.Context, opts types.ScanOptions) (*scanResult, erro
 		FilesScanned: scanRes.FilesScanned,
 		TotalSize:    scanRes.TotalSize,
 		Errors:       make([]scanError, len(scanRes.Errors)),
+		Skipped:      scanRes.Skipped,
+		SkippedPaths: scanRes.SkippedPaths,
 	}
 
 	for i, e := range scanRes.Errors {
@@ -445,7 +774,15 @@ func performScan(ctx context.Context, opts types.ScanOptions) (*scanResult, erro
 func convertToOutputResult(r *scanResult, f *filter.Filter, source string, daemonUp, interrupted bool) *output.Result {
 	// Convert types.FileInfo to filter.FileInfo for filtering
 	filterFiles := make([]filter.FileInfo, len(r.Files))
+	shared := make(map[string]*sharing.Info)
+	online := make(map[string]*cloud.Info)
 	for i, file := range r.Files {
+		if file.Sharing != nil {
+			shared[file.Path] = file.Sharing
+		}
+		if file.Cloud != nil {
+			online[file.Path] = file.Cloud
+		}
 		filterFiles[i] = filter.FileInfo{
 			Path:    file.Path,
 			Name:    filepath.Base(file.Path),
@@ -457,6 +794,9 @@ func convertToOutputResult(r *scanResult, f *filter.Filter, source string, daemo
 			Owner:   file.Owner,
 			Depth:   calculateDepth(file.Path, source),
 		}
+		if root, ok := r.fileRoots[file.Path]; ok {
+			filterFiles[i].Depth = calculateDepth(file.Path, root)
+		}
 	}
 
 	// Apply filter (match, sort, limit)
@@ -479,6 +819,20 @@ func convertToOutputResult(r *scanResult, f *filter.Filter, source string, daemo
 			Mode:      file.Mode,
 			Owner:     file.Owner,
 			Depth:     file.Depth,
+			Sharing:   shared[file.Path],
+			Cloud:     online[file.Path],
+			Root:      r.fileRoots[file.Path],
+		}
+	}
+
+	// Total the files shown under each root
+	roots := slices.Clone(r.Roots)
+	for i := range roots {
+		for _, file := range outputFiles {
+			if file.Root == roots[i].Path {
+				roots[i].Files++
+				roots[i].TotalSize += file.Size
+			}
 		}
 	}
 
@@ -487,6 +841,22 @@ func convertToOutputResult(r *scanResult, f *filter.Filter, source string, daemo
 	for _, e := range r.Errors {
 		warnings = append(warnings, fmt.Sprintf("%s: %s", e.Path, e.Error))
 	}
+	if r.Truncated && (f.Limit == 0 || len(outputFiles) < f.Limit) {
+		warnings = append(warnings, fmt.Sprintf(
+			"the daemon returned only the largest %d files (daemon.max_results); use --no-daemon to see all", len(r.Files)))
+	}
+
+	var skipped map[string]int64
+	for reason, n := range r.Skipped {
+		if skipped == nil {
+			skipped = make(map[string]int64, len(r.Skipped))
+		}
+		skipped[string(reason)] = n
+	}
+	skippedPaths := make([]output.SkippedPath, len(r.SkippedPaths))
+	for i, p := range r.SkippedPaths {
+		skippedPaths[i] = output.SkippedPath{Path: p.Path, Reason: string(p.Reason)}
+	}
 
 	return &output.Result{
 		Files: outputFiles,
@@ -495,12 +865,32 @@ func convertToOutputResult(r *scanResult, f *filter.Filter, source string, daemo
 			FilesScanned: r.FilesScanned,
 			LargeFiles:   int64(len(r.Files)),
 			Duration:     r.Elapsed,
+			Skipped:      skipped,
 		},
-		Source:      source,
-		DaemonUp:    daemonUp,
-		TotalFiles:  len(outputFiles),
-		Warnings:    warnings,
-		Interrupted: interrupted,
+		SkippedPaths: skippedPaths,
+		Source:       source,
+		DaemonUp:     daemonUp,
+		TotalFiles:   len(outputFiles),
+		Warnings:     warnings,
+		Interrupted:  interrupted,
+		Roots:        roots,
+	}
+}
+
+// logSkipped logs a breakdown of what the scan left out and why, and each
+// skipped path if they were recorded, for -v.
+func logSkipped(r *scanResult) {
+	var parts []string
+	for _, reason := range types.SkipReasons {
+		if n := r.Skipped[reason]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, reason.Describe()))
+		}
+	}
+	if len(parts) > 0 {
+		printVerbose("Skipped: %s", strings.Join(parts, ", "))
+	}
+	for _, p := range r.SkippedPaths {
+		printVerbose("Skipped (%s): %s", p.Reason.Describe(), p.Path)
 	}
 }
 
@@ -519,3 +909,241 @@ func calculateDepth(path, root string) int {
 	}
 	return depth
 }
+
+// skippedMounts returns mount points under root a scan doesn't enter:
+// those that only re-expose content already reachable through another path
+// under root, unless --no-mount-dedupe, and snapshots mounted there, unless
+// --include-snapshots.
+func skippedMounts(root string) []string {
+	dedupe, skipSnapshots := !viper.GetBool("no_mount_dedupe"), !viper.GetBool("include_snapshots")
+	if !dedupe && !skipSnapshots {
+		return nil
+	}
+	table, err := mounts.Load()
+	if err != nil {
+		printVerbose("Failed to read mount table, mount dedupe disabled: %v", err)
+		return nil
+	}
+	var skip []string
+	if dedupe {
+		for _, p := range table.DuplicatePaths(root) {
+			printVerbose("Skipping duplicate mount: %s", p)
+			skip = append(skip, p)
+		}
+	}
+	if skipSnapshots {
+		for _, p := range table.Snapshots(root) {
+			printVerbose("Skipping snapshot mount: %s", p)
+			skip = append(skip, p)
+		}
+	}
+	return skip
+}
+
+// mountsConfig reads the mount points named in the mounts section, with ~
+// expanded.
+func mountsConfig() (config.MountsConfig, error) {
+	var mc config.MountsConfig
+	if err := viper.UnmarshalKey("mounts", &mc); err != nil {
+		return mc, fmt.Errorf("invalid mounts in config: %w", err)
+	}
+	if err := expandMountPoints("mounts", mc.Skip, mc.Cross); err != nil {
+		return mc, err
+	}
+	return mc, nil
+}
+
+// networkConfig reads the network section, with ~ expanded, and what it
+// says is on a network file system.
+func networkConfig() (config.NetworkConfig, mounts.Network, error) {
+	var nc config.NetworkConfig
+	if err := viper.UnmarshalKey("network", &nc); err != nil {
+		return nc, mounts.Network{}, fmt.Errorf("invalid network settings in config: %w", err)
+	}
+	if err := expandMountPoints("network", nc.Paths, nc.Local); err != nil {
+		return nc, mounts.Network{}, err
+	}
+	return nc, mounts.Network{Detect: nc.Detect, Paths: nc.Paths, Local: nc.Local}, nil
+}
+
+// expandMountPoints expands ~ in the mount points of a config section in
+// place. They must be absolute paths.
+func expandMountPoints(section string, lists ...[]string) error {
+	for _, list := range lists {
+		for i, p := range list {
+			expanded, err := config.ExpandPath(p)
+			if err != nil {
+				return err
+			}
+			if !filepath.IsAbs(expanded) {
+				return fmt.Errorf("invalid %s entry %q: must be an absolute path", section, p)
+			}
+			list[i] = filepath.Clean(expanded)
+		}
+	}
+	return nil
+}
+
+// forNetwork lowers the workers in c when one of roots is on a network
+// file system, unless -w sets them, since workers tuned for local disks
+// would flood the server with stats.
+func forNetwork(c tuner.OptimalConfig, roots []string) (tuner.OptimalConfig, error) {
+	if viper.GetInt("workers") > 0 {
+		return c, nil
+	}
+	nc, network, err := networkConfig()
+	if err != nil {
+		return c, err
+	}
+	for _, root := range roots {
+		if mountPoint, fsType, ok := network.Find(root); ok {
+			c = c.ForNetwork(nc.DirWorkers, nc.FileWorkers)
+			printVerbose("%s is on a network file system (%s at %s), using %d dir and %d file workers",
+				root, fsType, mountPoint, c.DirWorkers, c.FileWorkers)
+			return c, nil
+		}
+	}
+	return c, nil
+}
+
+// backupChecker creates a checker for the backup repositories in the config,
+// returning nil when none are configured.
+func backupChecker() (*backup.Checker, error) {
+	var configured []config.BackupConfig
+	if err := viper.UnmarshalKey("backups", &configured); err != nil {
+		return nil, fmt.Errorf("invalid backups in config: %w", err)
+	}
+	if len(configured) == 0 {
+		return nil, nil
+	}
+	repos, err := backup.FromConfig(configured)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backups in config: %w", err)
+	}
+	return backup.NewChecker(repos), nil
+}
+
+// permanentRoots reads where --permanent may delete files permanently from
+// trash.permanent_roots, returning nil without --permanent. The flag does
+// nothing without roots, so that is an error rather than a silent no-op.
+func permanentRoots() (trash.PermanentRoots, error) {
+	if !viper.GetBool("permanent") {
+		return nil, nil
+	}
+	var tc config.TrashConfig
+	if err := viper.UnmarshalKey("trash", &tc); err != nil {
+		return nil, fmt.Errorf("invalid trash settings in config: %w", err)
+	}
+	if len(tc.PermanentRoots) == 0 {
+		return nil, errors.New("--permanent needs trash.permanent_roots in the config to say where files may be deleted permanently")
+	}
+	return expandPermanentRoots(tc.PermanentRoots)
+}
+
+// expandPermanentRoots expands trash.permanent_roots, which must be
+// absolute paths.
+func expandPermanentRoots(configured []string) (trash.PermanentRoots, error) {
+	roots := make([]string, len(configured))
+	for i, root := range configured {
+		expanded, err := config.ExpandPath(root)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(expanded) {
+			return nil, fmt.Errorf("invalid trash.permanent_roots entry %q: must be an absolute path", root)
+		}
+		roots[i] = expanded
+	}
+	return trash.NewPermanentRoots(roots), nil
+}
+
+// deleteBackend returns the backend --backend names, else preferred (a
+// rule's own), else trash.backend, configured from the trash section. The
+// permanent backend deletes only under trash.permanent_roots.
+func deleteBackend(preferred string) (trash.Backend, error) {
+	var tc config.TrashConfig
+	if err := viper.UnmarshalKey("trash", &tc); err != nil {
+		return nil, fmt.Errorf("invalid trash settings in config: %w", err)
+	}
+	name, err := trash.ParseBackend(cmp.Or(viper.GetString("backend"), preferred, tc.Backend))
+	if err != nil {
+		return nil, err
+	}
+	opts := trash.BackendOptions{Upload: trash.Upload{Command: tc.Upload.Command}}
+	// Only the chosen backend's settings are checked, so a bad staging
+	// directory doesn't stop deletes to the trash
+	switch name {
+	case trash.BackendStaging:
+		dir, err := config.ExpandPath(cmp.Or(tc.Staging.Dir, config.DefaultStagingDir()))
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("invalid trash.staging.dir %q: must be an absolute path", tc.Staging.Dir)
+		}
+		opts.Staging = trash.Staging{Dir: dir, Days: tc.Staging.Days}
+	case trash.BackendPermanent:
+		if opts.PermanentRoots, err = expandPermanentRoots(tc.PermanentRoots); err != nil {
+			return nil, err
+		}
+	}
+	return trash.NewBackend(name, opts)
+}
+
+// confirmPolicy reads how deletes are confirmed from the confirm section,
+// classifying the paths of preset rules as caches, and returns nil when it
+// is not enabled.
+func confirmPolicy() (*rules.ConfirmPolicy, error) {
+	var cc config.ConfirmConfig
+	if err := viper.UnmarshalKey("confirm", &cc); err != nil {
+		return nil, fmt.Errorf("invalid confirm settings in config: %w", err)
+	}
+	if !cc.Enabled {
+		return nil, nil
+	}
+	var configured []config.RuleConfig
+	if err := viper.UnmarshalKey("rules", &configured); err != nil {
+		return nil, fmt.Errorf("invalid rules in config: %w", err)
+	}
+	list, err := rules.FromConfig(configured)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules in config: %w", err)
+	}
+	policy, err := rules.ConfirmPolicyFromConfig(cc, list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid confirm settings in config: %w", err)
+	}
+	return policy, nil
+}
+
+// trashQuota reads the per-volume trash quota from the config, returning nil
+// when none is set.
+func trashQuota() (*trash.Quota, error) {
+	var tc config.TrashConfig
+	if err := viper.UnmarshalKey("trash", &tc); err != nil {
+		return nil, fmt.Errorf("invalid trash settings in config: %w", err)
+	}
+	if tc.Quota == "" && len(tc.Volumes) == 0 {
+		return nil, nil
+	}
+
+	var q trash.Quota
+	if tc.Quota != "" {
+		size, err := types.ParseSize(tc.Quota)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash quota %q: %w", tc.Quota, err)
+		}
+		q.Default = size
+	}
+	for mount, limit := range tc.Volumes {
+		size, err := types.ParseSize(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash quota %q for %s: %w", limit, mount, err)
+		}
+		if q.Volumes == nil {
+			q.Volumes = make(map[string]int64)
+		}
+		q.Volumes[mount] = size
+	}
+	return &q, nil
+}
@@ -0,0 +1,215 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/viper"
+)
+
+// autoStartDaemon starts sweepd using the configured paths if it isn't running.
+func autoStartDaemon(cfg *config.Config) error {
+	return client.EnsureDaemon(client.DaemonPaths{
+		Binary: cfg.Daemon.BinaryPath,
+		Socket: cfg.Daemon.SocketPath,
+		PID:    cfg.Daemon.PIDPath,
+	})
+}
+
+// clearDaemonCache asks a running daemon to drop its index for path
+// (or everything when path is empty). Returns true if the daemon cleared it.
+func clearDaemonCache(path string) bool {
+	target := daemonTarget()
+	if !target.Running() {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return false
+	}
+	defer daemonClient.Close()
+
+	_, err = daemonClient.ClearCache(ctx, path)
+	return err == nil
+}
+
+// tryDaemonScan attempts to use the daemon for scanning.
+// Returns the result and a boolean indicating if the daemon was used.
+func tryDaemonScan(ctx context.Context, opts types.ScanOptions, f *filter.Filter) (*scanResult, bool) {
+	// Check if daemon is running
+	target := daemonTarget()
+	if !target.Running() {
+		printVerbose("Daemon not running, using direct scan")
+		return nil, false
+	}
+
+	// Try to connect to daemon
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		printVerbose("Failed to connect to daemon: %v", err)
+		return nil, false
+	}
+	defer daemonClient.Close()
+
+	// Check if index is ready for this path
+	ready, err := daemonClient.IsIndexReady(ctx, opts.Root)
+	if err != nil {
+		printVerbose("Failed to check index status: %v", err)
+		// Trigger indexing in background for next time (uses fresh context)
+		go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
+		return nil, false
+	}
+
+	// Check max-age if specified
+	maxAgeStr := viper.GetString("max_age")
+	if maxAgeStr != "" {
+		maxAgeDur, parseErr := filter.ParseDuration(maxAgeStr)
+		if parseErr == nil {
+			status, _ := daemonClient.GetIndexStatus(ctx, opts.Root)
+			if status != nil && !status.LastUpdated.IsZero() {
+				indexAge := time.Since(status.LastUpdated)
+				if indexAge > maxAgeDur {
+					printVerbose("Index too old (%v > %v), triggering background indexing", indexAge, maxAgeDur)
+					go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
+					return nil, false
+				}
+			}
+		}
+	}
+
+	if !ready {
+		printVerbose("Index not ready for %s, triggering background indexing", opts.Root)
+		// Trigger indexing in background for next time (uses fresh context)
+		go triggerBackgroundIndexing(opts.Root) //nolint:contextcheck // intentionally uses fresh context for background work
+		return nil, false
+	}
+
+	// Index is ready, query the daemon
+	printVerbose("Using daemon index for %s", opts.Root)
+	// Pass filter limit to daemon for server-side limiting
+	limit := 0
+	if f != nil && f.Limit > 0 {
+		// Request more than needed since we'll filter client-side
+		// The daemon only filters by min-size and exclude patterns
+		limit = f.Limit * 10 // Request extra for client-side filtering
+		if limit > 10000 {
+			limit = 10000 // Cap at reasonable limit
+		}
+	}
+	if opts.Owner != nil && limit > 0 {
+		// The index is shared by all users, so ownership is checked here
+		limit = 10000
+	}
+	exclude := append(slices.Clone(opts.Exclude), opts.SkipMounts...)
+	page, err := daemonClient.QueryLargeFiles(ctx, opts.Root, opts.MinSize, exclude, limit)
+	if err != nil {
+		printVerbose("Failed to query daemon: %v", err)
+		return nil, false
+	}
+	files := page.Files
+	if opts.Owner != nil {
+		files = ownedFiles(files, opts.Owner)
+	}
+
+	// Sort files by size (largest first) - should already be sorted but ensure consistency
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+
+	// Calculate total size
+	var totalSize int64
+	for _, file := range files {
+		totalSize += file.Size
+	}
+
+	// Get index status for statistics
+	status, err := daemonClient.GetIndexStatus(ctx, opts.Root)
+	if err != nil {
+		printVerbose("Failed to get index status: %v", err)
+	}
+
+	result := &scanResult{
+		Files:        files,
+		DirsScanned:  0,
+		FilesScanned: 0,
+		TotalSize:    totalSize,
+		Truncated:    page.Truncated,
+	}
+
+	if status != nil {
+		result.DirsScanned = status.DirsIndexed
+		result.FilesScanned = status.FilesIndexed
+	}
+
+	return result, true
+}
+
+// triggerBackgroundIndexing triggers indexing in the background.
+func triggerBackgroundIndexing(path string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Check if daemon is running
+	target := daemonTarget()
+	if !target.Running() {
+		return
+	}
+
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return
+	}
+	defer daemonClient.Close()
+
+	// Trigger indexing (don't wait for completion)
+	_ = daemonClient.TriggerIndex(ctx, path, false)
+}
+
+// checkRemoteIndex verifies that the remote daemon is reachable and has
+// indexed root, so the TUI can fail with a clear error instead of scanning
+// the local machine.
+func checkRemoteIndex(root string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	target := daemonTarget()
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	ready, err := daemonClient.IsIndexReady(ctx, root)
+	if err != nil {
+		return fmt.Errorf("check index on %s: %w", target.Remote.Address, err)
+	}
+	if !ready {
+		return fmt.Errorf("%s has not indexed %s yet", target.Remote.Address, root)
+	}
+	return nil
+}
+
+// ownedFiles keeps the files owned by the filter's user.
+func ownedFiles(files []types.FileInfo, f *owner.Filter) []types.FileInfo {
+	kept := files[:0]
+	for _, file := range files {
+		if f.OwnsPath(file.Path) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,32 @@
+//go:build lite
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// autoStartDaemon is a no-op in lite builds, which have no daemon.
+func autoStartDaemon(_ *config.Config) error {
+	return nil
+}
+
+// clearDaemonCache is a no-op in lite builds.
+func clearDaemonCache(_ string) bool {
+	return false
+}
+
+// tryDaemonScan always reports the daemon as unavailable in lite builds.
+func tryDaemonScan(_ context.Context, _ types.ScanOptions, _ *filter.Filter) (*scanResult, bool) {
+	return nil, false
+}
+
+// checkRemoteIndex fails in lite builds, which cannot reach a remote daemon.
+func checkRemoteIndex(_ string) error {
+	return errors.New("remote daemons are not supported in lite builds")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/viper"
+)
+
+func TestResolveScanRoots(t *testing.T) {
+	dir := t.TempDir()
+	movies := filepath.Join(dir, "movies")
+	downloads := filepath.Join(dir, "downloads")
+	for _, d := range []string{filepath.Join(movies, "2024"), downloads} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	roots, err := resolveScanRoots([]string{filepath.Join(movies, "2024"), downloads, movies, downloads + "/"}, "")
+	if err != nil {
+		t.Fatalf("resolveScanRoots() error = %v", err)
+	}
+	// Nested and repeated roots are scanned once, as part of the outer root
+	if want := []string{downloads, movies}; !reflect.DeepEqual(roots, want) {
+		t.Errorf("resolveScanRoots() = %v, want %v", roots, want)
+	}
+
+	if _, err := resolveScanRoots([]string{downloads, filepath.Join(dir, "missing")}, ""); err == nil {
+		t.Error("resolveScanRoots() accepted a missing path")
+	}
+}
+
+func TestMergeRootResults(t *testing.T) {
+	roots := []string{"/movies", "/downloads"}
+	merged := mergeRootResults(roots, []*scanResult{
+		{
+			Files:       []types.FileInfo{{Path: "/movies/a/film.mkv", Size: 300}, {Path: "/movies/clip.mp4", Size: 100}},
+			DirsScanned: 2, FilesScanned: 10,
+		},
+		{
+			Files:       []types.FileInfo{{Path: "/downloads/setup.dmg", Size: 200}},
+			DirsScanned: 1, FilesScanned: 4, Truncated: true,
+		},
+	})
+	if merged.DirsScanned != 3 || merged.FilesScanned != 14 || !merged.Truncated {
+		t.Errorf("merged stats = %d dirs, %d files, truncated %v", merged.DirsScanned, merged.FilesScanned, merged.Truncated)
+	}
+
+	f := filter.New(filter.WithSortDescending(true), filter.WithLimit(2))
+	result := convertToOutputResult(merged, f, "/movies, /downloads", false, false)
+	var paths []string
+	for _, file := range result.Files {
+		paths = append(paths, file.Root+" "+file.Path)
+	}
+	if want := []string{"/movies /movies/a/film.mkv", "/downloads /downloads/setup.dmg"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("files = %v, want the largest of both roots: %v", paths, want)
+	}
+	if result.Files[0].Depth != 1 {
+		t.Errorf("Depth = %d, want 1 below its own root", result.Files[0].Depth)
+	}
+	want := []output.RootSummary{
+		{Path: "/movies", Files: 1, TotalSize: 300, DirsScanned: 2, FilesScanned: 10},
+		{Path: "/downloads", Files: 1, TotalSize: 200, DirsScanned: 1, FilesScanned: 4},
+	}
+	if !reflect.DeepEqual(result.Roots, want) {
+		t.Errorf("Roots = %+v, want %+v", result.Roots, want)
+	}
+}
+
+func TestDeleteBackendChecksOnlyItsOwnSettings(t *testing.T) {
+	viper.Set("trash.staging.dir", "relative/staging")
+	t.Cleanup(func() { viper.Set("trash.staging.dir", nil) })
+
+	backend, err := deleteBackend("")
+	if err != nil {
+		t.Fatalf("deleteBackend() error = %v, want the trash despite the bad staging dir", err)
+	}
+	if backend.Name() != trash.BackendTrash {
+		t.Errorf("Name() = %q, want %q", backend.Name(), trash.BackendTrash)
+	}
+	if _, err := deleteBackend(trash.BackendStaging); err == nil {
+		t.Error("expected error for the staging backend with a relative trash.staging.dir")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/growth"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	statsSince string
+	statsGraph bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [path]",
+	Short: "Show how indexed paths grew over time",
+	Long: `Show the total size and file count of the daemon's indexed paths over
+time: each path's latest totals and how much they changed, or with --graph,
+sparkline charts of both.
+
+The daemon records the totals of each indexed path whenever it saves a
+snapshot, every daemon.snapshot_interval (default 6h). Unlike snapshots they
+are never pruned. With a path, only the indexed path covering it is shown.
+
+Examples:
+  sweep stats                     # Every indexed path over the last month
+  sweep stats --graph             # The same, charted
+  sweep stats ~ --since 1y -g     # The home directory over the last year
+  sweep stats -o json > stats.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsSince, "since", "30d", "show totals recorded this recently (e.g., 7d, 4w, 1y; 0 for all)")
+	statsCmd.Flags().BoolVarP(&statsGraph, "graph", "g", false, "chart size and file count as sparklines")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// runStats prints the totals the daemon recorded for its indexed paths.
+func runStats(_ *cobra.Command, args []string) error {
+	var path string
+	if len(args) > 0 {
+		var err error
+		if path, err = config.ExpandPath(args[0]); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+	}
+	var since time.Duration
+	if statsSince != "0" {
+		var err error
+		if since, err = filter.ParseDuration(statsSince); err != nil {
+			return fmt.Errorf("invalid --since %q: %w", statsSince, err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	series, err := daemonIndexHistory(ctx, path, since)
+	if errors.Is(err, errDaemonNotRunning) {
+		return fmt.Errorf("sweep stats charts the daemon's index: %w", err)
+	}
+	if err != nil {
+		return err
+	}
+	return growth.Write(os.Stdout, viper.GetString("output"), series, statsGraph)
+}
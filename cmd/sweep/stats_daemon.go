@@ -0,0 +1,37 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/growth"
+)
+
+// daemonIndexHistory asks the daemon for the totals it recorded for the
+// indexed root covering path, or every root if path is empty, over the
+// last since.
+func daemonIndexHistory(ctx context.Context, path string, since time.Duration) ([]growth.Series, error) {
+	target := daemonTarget()
+	if !target.Running() {
+		return nil, errDaemonNotRunning
+	}
+
+	daemonClient, err := target.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer daemonClient.Close()
+
+	series, err := daemonClient.GetIndexHistory(ctx, path, since)
+	if status.Code(err) == codes.FailedPrecondition {
+		// The path isn't indexed: the daemon's message says so
+		return nil, errors.New(status.Convert(err).Message())
+	}
+	return series, err
+}
@@ -0,0 +1,15 @@
+//go:build lite
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/growth"
+)
+
+// daemonIndexHistory has no daemon to ask in lite builds.
+func daemonIndexHistory(_ context.Context, _ string, _ time.Duration) ([]growth.Series, error) {
+	return nil, errDaemonNotRunning
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/tags"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage user-defined tags",
+	Long: `Tags are virtual groupings of files and directories that persist across
+sessions. Tag paths in the TUI with 'T' or with 'sweep tags add', then narrow
+any scan to a tag with --tag.
+
+Examples:
+  sweep tags                              # Summarize all tags
+  sweep tags add to-review ~/Downloads/big.iso
+  sweep tags show to-review               # List paths with a tag
+  sweep --tag to-review ~                 # Scan only tagged paths`,
+	Args: cobra.NoArgs,
+	RunE: runTagsSummary,
+}
+
+var tagsAddCmd = &cobra.Command{
+	Use:   "add <tag> <path>...",
+	Short: "Add a tag to one or more paths",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTagsAdd,
+}
+
+var tagsRemoveCmd = &cobra.Command{
+	Use:   "remove <tag> <path>...",
+	Short: "Remove a tag from one or more paths",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTagsRemove,
+}
+
+var tagsShowCmd = &cobra.Command{
+	Use:   "show <tag>",
+	Short: "List the paths assigned to a tag",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTagsShow,
+}
+
+var tagsDeleteCmd = &cobra.Command{
+	Use:   "delete <tag>",
+	Short: "Delete a tag and all of its assignments",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTagsDelete,
+}
+
+func init() {
+	tagsCmd.AddCommand(tagsAddCmd)
+	tagsCmd.AddCommand(tagsRemoveCmd)
+	tagsCmd.AddCommand(tagsShowCmd)
+	tagsCmd.AddCommand(tagsDeleteCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// getTagStore opens the persisted tag store in the state directory.
+func getTagStore() (*tags.Store, error) {
+	return tags.Open(config.DefaultTagsPath())
+}
+
+// resolveTagPaths converts user-supplied paths to absolute paths.
+func resolveTagPaths(args []string) ([]string, error) {
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		expanded, err := config.ExpandPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		abs, err := filepath.Abs(expanded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path %q: %w", arg, err)
+		}
+		paths = append(paths, abs)
+	}
+	return paths, nil
+}
+
+// runTagsSummary prints every tag with its path count and on-disk size.
+func runTagsSummary(cmd *cobra.Command, args []string) error {
+	store, err := getTagStore()
+	if err != nil {
+		return fmt.Errorf("failed to open tags: %w", err)
+	}
+
+	summary := store.Summary()
+	if len(summary) == 0 {
+		printInfo("No tags defined.")
+		printInfo("Press 'T' in the TUI or run 'sweep tags add <tag> <path>' to create one.")
+		return nil
+	}
+
+	fmt.Printf("\n%-24s  %-8s  %-8s  %-12s\n", "TAG", "PATHS", "MISSING", "SIZE")
+	fmt.Println(strings.Repeat("-", 60))
+
+	for _, s := range summary {
+		var size int64
+		missing := 0
+		for _, p := range s.Paths {
+			info, err := os.Lstat(p)
+			if err != nil {
+				missing++
+				continue
+			}
+			if !info.IsDir() {
+				size += info.Size()
+			}
+		}
+		fmt.Printf("%-24s  %-8d  %-8d  %-12s\n",
+			truncateString(s.Tag, 24), len(s.Paths), missing, types.FormatSize(size))
+	}
+
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Println("Use 'sweep tags show <tag>' to list tagged paths.")
+	return nil
+}
+
+// runTagsAdd assigns a tag to the given paths.
+func runTagsAdd(cmd *cobra.Command, args []string) error {
+	store, err := getTagStore()
+	if err != nil {
+		return fmt.Errorf("failed to open tags: %w", err)
+	}
+
+	paths, err := resolveTagPaths(args[1:])
+	if err != nil {
+		return err
+	}
+
+	if err := store.Add(args[0], paths...); err != nil {
+		return fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	printInfo("Tagged %d path(s) with %q", len(paths), args[0])
+	return nil
+}
+
+// runTagsRemove unassigns a tag from the given paths.
+func runTagsRemove(cmd *cobra.Command, args []string) error {
+	store, err := getTagStore()
+	if err != nil {
+		return fmt.Errorf("failed to open tags: %w", err)
+	}
+
+	paths, err := resolveTagPaths(args[1:])
+	if err != nil {
+		return err
+	}
+
+	if err := store.Remove(args[0], paths...); err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	printInfo("Removed tag %q from %d path(s)", args[0], len(paths))
+	return nil
+}
+
+// runTagsShow lists the paths assigned to a tag.
+func runTagsShow(cmd *cobra.Command, args []string) error {
+	store, err := getTagStore()
+	if err != nil {
+		return fmt.Errorf("failed to open tags: %w", err)
+	}
+
+	paths := store.Paths(args[0])
+	if len(paths) == 0 {
+		return fmt.Errorf("tag not found: %s", args[0])
+	}
+
+	for _, p := range paths {
+		fmt.Println(p)
+	}
+	return nil
+}
+
+// runTagsDelete removes a tag entirely.
+func runTagsDelete(cmd *cobra.Command, args []string) error {
+	store, err := getTagStore()
+	if err != nil {
+		return fmt.Errorf("failed to open tags: %w", err)
+	}
+
+	if err := store.Delete(args[0]); err != nil {
+		return err
+	}
+
+	printInfo("Deleted tag %q", args[0])
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/restore"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, size, and empty the files sweep moved to the trash",
+	Long: `Work with the files sweep moved to the trash. The history records every
+deletion, so sweep finds its own files in each volume's trash and leaves
+everything else there alone. Files restored or already emptied are skipped.
+
+--older-than limits every subcommand to files deleted at least that long ago,
+so 'sweep trash empty --older-than 30d' keeps the last month's deletions
+restorable.
+
+Examples:
+  sweep trash                          # List the files sweep trashed
+  sweep trash size                     # Space they take, per volume
+  sweep trash empty --older-than 30d   # Free the space of older deletions
+  sweep trash empty --dry-run          # Show what would be freed`,
+	Args: cobra.NoArgs,
+	RunE: runTrashList,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the files sweep moved to the trash",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runTrashList,
+}
+
+var trashSizeCmd = &cobra.Command{
+	Use:   "size",
+	Short: "Show how much space sweep's trashed files take",
+	Args:  cobra.NoArgs,
+	RunE:  runTrashSize,
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete the files sweep moved to the trash",
+	Long: `Permanently delete the files sweep moved to the trash, freeing their space.
+They can't be restored afterwards. Other files in the trash are kept.`,
+	Args: cobra.NoArgs,
+	RunE: runTrashEmpty,
+}
+
+var trashOlderThan string
+
+func init() {
+	trashCmd.PersistentFlags().StringVar(&trashOlderThan, "older-than", "", "only files deleted at least this long ago (e.g., 30d, 12h)")
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashSizeCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+	rootCmd.AddCommand(trashCmd)
+}
+
+// trashedItem is a trashed file as listed by 'sweep trash list -o json'.
+type trashedItem struct {
+	Path      string    `json:"path"`
+	TrashPath string    `json:"trash_path"`
+	Size      int64     `json:"size"`
+	Deleted   time.Time `json:"deleted"`
+	Volume    string    `json:"volume"`
+	Entry     string    `json:"entry"`
+}
+
+// trashVolume is the space sweep's trashed files take on one volume.
+type trashVolume struct {
+	Volume string `json:"volume"`
+	Items  int    `json:"items"`
+	Size   int64  `json:"size"`       // Freed by 'sweep trash empty'
+	Trash  int64  `json:"trash_size"` // Everything in the volume's trash
+}
+
+// sweptTrash returns the files sweep moved to the trash that are still
+// there, limited by --older-than, oldest first.
+func sweptTrash() ([]trash.Trashed, error) {
+	var olderThan time.Duration
+	if trashOlderThan != "" {
+		d, err := filter.ParseDuration(trashOlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --older-than: %w", err)
+		}
+		olderThan = d
+	}
+
+	m, err := getManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize manifest: %w", err)
+	}
+	entries, err := m.List(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	items, err := trash.FindTrashed(restore.TrashRecords(entries))
+	if err != nil {
+		return nil, err
+	}
+	return olderItems(items, time.Now().Add(-olderThan)), nil
+}
+
+// olderItems returns the items deleted at or before cutoff.
+func olderItems(items []trash.Trashed, cutoff time.Time) []trash.Trashed {
+	var older []trash.Trashed
+	for _, it := range items {
+		if !it.Deleted.After(cutoff) {
+			older = append(older, it)
+		}
+	}
+	return older
+}
+
+// trashTotal returns the combined size of items.
+func trashTotal(items []trash.Trashed) int64 {
+	var total int64
+	for _, it := range items {
+		total += it.Size
+	}
+	return total
+}
+
+func runTrashList(_ *cobra.Command, _ []string) error {
+	items, err := sweptTrash()
+	if err != nil {
+		return err
+	}
+
+	switch format := viper.GetString("output"); format {
+	case "json":
+		list := make([]trashedItem, len(items))
+		for i, it := range items {
+			list[i] = trashedItem{
+				Path:      it.Original,
+				TrashPath: it.Path,
+				Size:      it.Size,
+				Deleted:   it.Deleted,
+				Volume:    it.Volume.Mount,
+				Entry:     it.Entry,
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	case "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
+	}
+
+	if len(items) == 0 {
+		printInfo("No files sweep moved to the trash are left there.")
+		return nil
+	}
+
+	locale, err := getLocale()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DELETED\tSIZE\tPATH")
+	for _, it := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", locale.FormatAgo(it.Deleted, now), types.FormatSize(it.Size), it.Original)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d files, %s\n", len(items), types.FormatSize(trashTotal(items)))
+	return nil
+}
+
+func runTrashSize(_ *cobra.Command, _ []string) error {
+	items, err := sweptTrash()
+	if err != nil {
+		return err
+	}
+
+	var volumes []trashVolume
+	byDir := make(map[string]int)
+	for _, it := range items {
+		i, ok := byDir[it.Volume.Dir]
+		if !ok {
+			usage, err := trash.Usage(it.Volume)
+			if err != nil {
+				return fmt.Errorf("failed to measure the trash on %s: %w", it.Volume.Mount, err)
+			}
+			i = len(volumes)
+			byDir[it.Volume.Dir] = i
+			volumes = append(volumes, trashVolume{Volume: it.Volume.Mount, Trash: usage})
+		}
+		volumes[i].Items++
+		volumes[i].Size += it.Size
+	}
+
+	switch format := viper.GetString("output"); format {
+	case "json":
+		if volumes == nil {
+			volumes = []trashVolume{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(volumes)
+	case "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, json)", format)
+	}
+
+	if len(volumes) == 0 {
+		printInfo("No files sweep moved to the trash are left there.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VOLUME\tFILES\tRECLAIMABLE\tTRASH TOTAL")
+	for _, v := range volumes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", v.Volume, v.Items, types.FormatSize(v.Size), types.FormatSize(v.Trash))
+	}
+	return tw.Flush()
+}
+
+func runTrashEmpty(_ *cobra.Command, _ []string) error {
+	dryRun := viper.GetBool("dry_run")
+	if getReadOnly() && !dryRun {
+		return errReadOnly
+	}
+	items, err := sweptTrash()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		printInfo("No files sweep moved to the trash are left there.")
+		return nil
+	}
+
+	if dryRun {
+		for _, it := range items {
+			printInfo("Would delete %s (%s)", it.Original, types.FormatSize(it.Size))
+		}
+		printInfo("Would free %s (%d files)", types.FormatSize(trashTotal(items)), len(items))
+		return nil
+	}
+
+	var freed int64
+	var emptied, failed int
+	for _, it := range items {
+		if err := trash.Erase(it.Item); err != nil {
+			printError("%v", err)
+			failed++
+			continue
+		}
+		logging.Get("client").Debug("deleted from trash", "path", it.Original, "size", it.Size)
+		freed += it.Size
+		emptied++
+	}
+	printInfo("Freed %s (%d files)", types.FormatSize(freed), emptied)
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d files from the trash", failed, len(items))
+	}
+	return nil
+}
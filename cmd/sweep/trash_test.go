@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+func TestOlderItems(t *testing.T) {
+	now := time.Now()
+	items := []trash.Trashed{
+		{Item: trash.Item{Name: "old", Deleted: now.Add(-40 * 24 * time.Hour)}},
+		{Item: trash.Item{Name: "cutoff", Deleted: now.Add(-30 * 24 * time.Hour)}},
+		{Item: trash.Item{Name: "new", Deleted: now.Add(-time.Hour)}},
+	}
+	older := olderItems(items, now.Add(-30*24*time.Hour))
+	if len(older) != 2 || older[0].Name != "old" || older[1].Name != "cutoff" {
+		t.Errorf("olderItems = %+v, want old and cutoff", older)
+	}
+	if got := olderItems(items, now); len(got) != 3 {
+		t.Errorf("olderItems(now) kept %d items, want all 3", len(got))
+	}
+}
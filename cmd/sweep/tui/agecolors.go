@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+)
+
+// Age gradient defaults: a week-old file is fresh, a two-year-old one stale.
+const (
+	defaultFreshAge = "7d"
+	defaultStaleAge = "2y"
+)
+
+// defaultAgeColors run from green through yellow to red.
+var defaultAgeColors = []string{"#28A745", "#FFC107", "#DC3545"}
+
+// ageLegendSwatches is the number of color samples in the legend.
+const ageLegendSwatches = 5
+
+// AgeGradient colors file rows by age so years-old files stand out. Ages
+// are placed on a logarithmic scale between Fresh and Stale, so a month
+// and a year are as far apart as a day and a month.
+type AgeGradient struct {
+	fresh, stale           time.Duration
+	freshLabel, staleLabel string
+	colors                 [][3]float64 // RGB stops from fresh to stale
+}
+
+// NewAgeGradient validates the age colors from config. It returns nil when
+// they are disabled.
+func NewAgeGradient(cfg config.AgeColorsConfig) (*AgeGradient, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	g := &AgeGradient{freshLabel: cfg.Fresh, staleLabel: cfg.Stale}
+	if g.freshLabel == "" {
+		g.freshLabel = defaultFreshAge
+	}
+	if g.staleLabel == "" {
+		g.staleLabel = defaultStaleAge
+	}
+
+	var err error
+	if g.fresh, err = filter.ParseDuration(g.freshLabel); err != nil {
+		return nil, fmt.Errorf("invalid age_colors.fresh: %w", err)
+	}
+	if g.stale, err = filter.ParseDuration(g.staleLabel); err != nil {
+		return nil, fmt.Errorf("invalid age_colors.stale: %w", err)
+	}
+	if g.fresh <= 0 || g.stale <= g.fresh {
+		return nil, fmt.Errorf("age_colors.stale (%s) must be older than age_colors.fresh (%s)", g.staleLabel, g.freshLabel)
+	}
+
+	colors := cfg.Colors
+	if len(colors) == 0 {
+		colors = defaultAgeColors
+	}
+	if len(colors) < 2 {
+		return nil, fmt.Errorf("age_colors.colors needs at least two colors, got %d", len(colors))
+	}
+	for _, c := range colors {
+		rgb, err := parseHexColor(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age_colors.colors: %w", err)
+		}
+		g.colors = append(g.colors, rgb)
+	}
+	return g, nil
+}
+
+// parseHexColor parses "#RRGGBB" or "RRGGBB".
+func parseHexColor(s string) ([3]float64, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) != 6 {
+		return [3]float64{}, fmt.Errorf("%q is not a #RRGGBB color", s)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return [3]float64{}, fmt.Errorf("%q is not a #RRGGBB color", s)
+	}
+	return [3]float64{float64(n >> 16 & 0xff), float64(n >> 8 & 0xff), float64(n & 0xff)}, nil
+}
+
+// position places an age between 0 (fresh) and 1 (stale).
+func (g *AgeGradient) position(age time.Duration) float64 {
+	if age <= g.fresh {
+		return 0
+	}
+	if age >= g.stale {
+		return 1
+	}
+	return math.Log(float64(age)/float64(g.fresh)) / math.Log(float64(g.stale)/float64(g.fresh))
+}
+
+// at returns the color at a position between 0 and 1.
+func (g *AgeGradient) at(pos float64) lipgloss.Color {
+	segments := float64(len(g.colors) - 1)
+	i := int(pos * segments)
+	if i >= len(g.colors)-1 {
+		i = len(g.colors) - 2
+	}
+	frac := pos*segments - float64(i)
+	from, to := g.colors[i], g.colors[i+1]
+	var rgb [3]int
+	for c := range rgb {
+		rgb[c] = int(math.Round(from[c] + (to[c]-from[c])*frac))
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", rgb[0], rgb[1], rgb[2]))
+}
+
+// Color returns the color of a file modified at modTime. Files without a
+// modification time are not colored.
+func (g *AgeGradient) Color(modTime, now time.Time) (lipgloss.Color, bool) {
+	if g == nil || modTime.IsZero() {
+		return "", false
+	}
+	return g.at(g.position(now.Sub(modTime))), true
+}
+
+// Render colors text by the age of a file, or leaves it as is.
+func (g *AgeGradient) Render(text string, modTime, now time.Time) string {
+	color, ok := g.Color(modTime, now)
+	if !ok {
+		return text
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(text)
+}
+
+// Legend shows the gradient for the status bar, e.g. "7d ■■■■■ 2y".
+func (g *AgeGradient) Legend() string {
+	if g == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(mutedTextStyle.Render(g.freshLabel + " "))
+	for i := range ageLegendSwatches {
+		pos := float64(i) / float64(ageLegendSwatches-1)
+		b.WriteString(lipgloss.NewStyle().Foreground(g.at(pos)).Render("■"))
+	}
+	b.WriteString(mutedTextStyle.Render(" " + g.staleLabel))
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+func TestNewAgeGradient(t *testing.T) {
+	g, err := NewAgeGradient(config.AgeColorsConfig{})
+	if err != nil || g != nil {
+		t.Fatalf("disabled: got %v, %v; want nil, nil", g, err)
+	}
+
+	invalid := []config.AgeColorsConfig{
+		{Enabled: true, Fresh: "soon"},
+		{Enabled: true, Fresh: "1y", Stale: "30d"},
+		{Enabled: true, Colors: []string{"#FF0000"}},
+		{Enabled: true, Colors: []string{"#FF0000", "red"}},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewAgeGradient(cfg); err == nil {
+			t.Errorf("NewAgeGradient(%+v): expected an error", cfg)
+		}
+	}
+}
+
+func TestAgeGradientColor(t *testing.T) {
+	g, err := NewAgeGradient(config.AgeColorsConfig{Enabled: true, Fresh: "1d", Stale: "100d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	day := 24 * time.Hour
+
+	tests := []struct {
+		age  time.Duration
+		want lipgloss.Color
+	}{
+		{time.Hour, "#28A745"},  // Newer than fresh
+		{day, "#28A745"},        // Fresh
+		{10 * day, "#FFC107"},   // Halfway on the log scale
+		{100 * day, "#DC3545"},  // Stale
+		{1000 * day, "#DC3545"}, // Older than stale
+		{-time.Hour, "#28A745"}, // Clock skew: modified in the future
+	}
+	for _, tt := range tests {
+		got, ok := g.Color(now.Add(-tt.age), now)
+		if !ok || got != tt.want {
+			t.Errorf("Color(age %v) = %q, %v; want %q", tt.age, got, ok, tt.want)
+		}
+	}
+
+	// A quarter of the way blends green and yellow
+	quarter := time.Duration(float64(day) * math.Pow(100, 0.25))
+	if got, _ := g.Color(now.Add(-quarter), now); got != "#94B426" {
+		t.Errorf("Color(quarter) = %q, want #94B426", got)
+	}
+
+	if _, ok := g.Color(time.Time{}, now); ok {
+		t.Error("expected no color without a modification time")
+	}
+}
+
+func TestAgeGradientDisabled(t *testing.T) {
+	var g *AgeGradient
+	if got := g.Render("file.iso", time.Now(), time.Now()); got != "file.iso" {
+		t.Errorf("Render() = %q, want the text unchanged", got)
+	}
+	if g.Legend() != "" {
+		t.Error("expected no legend")
+	}
+}
+
+func TestAgeGradientLegend(t *testing.T) {
+	g, err := NewAgeGradient(config.AgeColorsConfig{Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legend := g.Legend()
+	if !strings.HasPrefix(legend, "7d ") || !strings.HasSuffix(legend, " 2y") {
+		t.Errorf("Legend() = %q, want the default ages at the ends", legend)
+	}
+	if n := strings.Count(legend, "■"); n != ageLegendSwatches {
+		t.Errorf("Legend() has %d swatches, want %d", n, ageLegendSwatches)
+	}
+
+	m := NewResultModel(nil)
+	m.ageColors = g
+	if !strings.Contains(m.renderFooter(120), "7d ") {
+		t.Error("expected the legend in the footer")
+	}
+}
@@ -1,21 +1,37 @@
 package tui
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/jamesainslie/sweep/pkg/client"
 	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/jamesainslie/sweep/pkg/sweep/backup"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/dryrun"
 	"github.com/jamesainslie/sweep/pkg/sweep/filter"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/restore"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
 	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+	"github.com/jamesainslie/sweep/pkg/sweep/tags"
 	"github.com/jamesainslie/sweep/pkg/sweep/trash"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
@@ -26,6 +42,7 @@ type AppState int
 const (
 	StateResults AppState = iota
 	StateConfirm
+	StateQuota // Trash quota would be exceeded; choose purge or permanent delete
 	StateDeleting
 	StateComplete
 )
@@ -33,13 +50,73 @@ const (
 // Options configures the TUI application.
 type Options struct {
 	Root        string
+	Roots       []string // Set to scan several roots together; Root is the first
 	MinSize     int64
 	Exclude     []string
+	Symlinks    types.SymlinkPolicy // Symlinked directories a direct scan follows
 	DirWorkers  int
 	FileWorkers int
 	DryRun      bool
 	NoDaemon    bool
-	Filter      *filter.Filter // Optional filter for pre-filtering views
+	Filter      *filter.Filter       // Optional filter for pre-filtering views
+	Tags        *tags.Store          // Optional tag store; enables tagging with 'T'
+	Owner       *owner.Filter        // Optional; only show files owned by this user
+	Columns     *ColumnLayout        // Optional file list layout; defaults to size and name
+	ReadOnly    bool                 // Disable deleting; for auditing systems that must not change
+	TrashQuota  *trash.Quota         // Optional per-volume trash size limits
+	Manifest    *manifest.Manifest   // Optional; records deletions so 'U' can undo them
+	Backups     *backup.Checker      // Optional; shows whether the selected file is backed up
+	AgeColors   *AgeGradient         // Optional; colors file names by age
+	Sessions    *session.Store       // Optional; resumes where the last session on the roots was left
+	Confirm     *rules.ConfirmPolicy // Optional; how deletes are confirmed by directory class
+	ConfigPath  string               // Optional; the config file 'x' adds exclusions to
+
+	// Version identifies the build in crash reports.
+	Version string
+
+	// Remote, when its address is set, browses the index of a daemon on
+	// another machine instead of the local daemon; Root is a path there.
+	Remote config.RemoteConfig
+
+	// Timeout is the deadline for each daemon request; 0 uses the client's
+	// default and a negative value sets none.
+	Timeout time.Duration
+
+	// VerifyBeforeDelete re-stats each file just before deleting it and
+	// skips files whose size or modification time changed since selection.
+	VerifyBeforeDelete bool
+
+	// RecentDays is how recently a file must have been modified for a dry
+	// run to warn about it; 0 uses dryrun.DefaultRecentDays.
+	RecentDays int
+
+	// IncludeSnapshots has a direct scan enter filesystem snapshots, which
+	// it otherwise skips.
+	IncludeSnapshots bool
+
+	// OneFileSystem keeps a direct scan on the root's file system, except
+	// at the CrossMounts mount points.
+	OneFileSystem bool
+	CrossMounts   []string
+
+	// PermanentRoots, set with --permanent, are where selected files are
+	// deleted permanently instead of trashed, once the deletion is
+	// confirmed by typing a word.
+	PermanentRoots trash.PermanentRoots
+
+	// Backend deletes the files not under PermanentRoots, set with
+	// --backend or trash.backend; nil is the trash.
+	Backend trash.Backend
+
+	// MoveDest, MoveVerify, and MoveSymlink are the defaults of the 'M'
+	// popup, from the move section of the config.
+	MoveDest    string
+	MoveVerify  string
+	MoveSymlink bool
+
+	// NoOwner skips looking up the owners of scanned files, which is done
+	// after a direct scan completes.
+	NoOwner bool
 }
 
 // ScanProgress tracks the progress of a scan for the TUI.
@@ -51,6 +128,21 @@ type ScanProgress struct {
 	// WalkCompleteElapsed is the frozen elapsed time when directory traversal completes.
 	// If non-zero, this is used for display instead of continuing to count from StartTime.
 	WalkCompleteElapsed time.Duration
+
+	// Estimate is how far along a direct scan is, when HasEstimate: a
+	// scan only knows when it has a count to expect.
+	Estimate    scanner.Estimate
+	HasEstimate bool
+	estimator   scanner.Estimator
+}
+
+// estimate formats the progress estimate while the walk is running, and is
+// empty otherwise.
+func (p ScanProgress) estimate() string {
+	if !p.Scanning || p.WalkCompleteElapsed > 0 || !p.HasEstimate {
+		return ""
+	}
+	return p.Estimate.String()
 }
 
 // NotificationType represents the type of notification.
@@ -86,10 +178,17 @@ type Model struct {
 	resultModel ResultModel
 	options     Options
 
+	// Manifest entry of the last delete, restored by 'U'
+	undoEntry string
+
 	// Tree view state
 	treeView *TreeView
 	treeMode bool // true = tree view, false = legacy flat list
 
+	// Treemap state; built from the tree view's data
+	treemap     *Treemap
+	treemapMode bool
+
 	// Scanning state
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -98,13 +197,25 @@ type Model struct {
 	fileChan     chan types.FileInfo
 	progressChan chan types.ScanProgress
 
+	// Paged daemon results, by root
+	nextPages    []string // Fetch the daemon's next page; empty after the last
+	pageFetching []bool   // A DaemonPageMsg is on its way
+	pageFailed   bool     // A page wasn't fetched, so the list stays partial
+
 	// Live file events state
-	liveEventChan <-chan client.FileEvent
-	liveWatching  bool
+	liveEventChan    <-chan fileEvent
+	liveConn         io.Closer // The daemon connection the stream is on
+	liveWatching     bool
+	liveReconnecting bool // The stream dropped and is being restored
+	liveRetries      int  // Failed reconnects since the stream dropped
 
 	// Tree live events state
-	treeEventChan <-chan client.TreeEvent
-	treeWatching  bool
+	treeEventChan    <-chan treeEvent
+	treeConn         io.Closer
+	treeWatching     bool
+	treeReconnecting bool
+	treeRetries      int
+	treeFlushPending bool // A treeFlushMsg is scheduled
 
 	// Notifications for live events
 	notifications []Notification
@@ -118,22 +229,79 @@ type Model struct {
 	logEntryChan     <-chan logging.LogEntry
 
 	// Log viewer pane state
-	logViewer *LogViewerState
+	logViewer     *LogViewerState
+	daemonLogs    bool                    // The daemon's log entries are being read
+	daemonLogChan <-chan logging.LogEntry // Closed when reading them stops
+
+	// Tagging state
+	tagPrompt      tagPromptState
+	tagSummaryOpen bool
+
+	// Directory stats popup ('?' in the tree); nil when closed
+	dirStats *dirStats
+
+	// File size histogram popup ('H')
+	histogramOpen bool
+
+	// Archive entries popup ('i' on a zip or tar); nil when closed
+	archive *archiveView
+
+	// Move to another volume popup ('M'); nil when closed
+	move *moveView
+
+	// Fuzzy search over the list or the tree ('f')
+	search searchState
+
+	// Where the last session on the roots was left, resumed once the list
+	// and the tree have loaded; nil once resumed
+	resumeList *session.State
+	resumeTree *session.State
+
+	// Recently deleted pane ('D'); nil when closed
+	deleted *deletedPane
+	// Manifest entries of the deletes made this session
+	sessionDeletes map[string]bool
+
+	// The daemon's suggestion to keep watching the root; nil when closed.
+	// It is offered at most once a session.
+	watchSuggestion *watchSuggestion
+	watchSuggested  bool
+
+	// Directories hidden with 'x' this session, and the prompt asking how
+	// to hide one; nil when closed
+	hidden     []string
+	hidePrompt *hidePrompt
 
 	// Confirmation dialog state
-	confirmFocused int // 0 = cancel, 1 = delete
+	confirmFocused int    // 0 = cancel, 1 = delete
+	confirmInput   string // Typed to confirm deleting files permanently
+
+	// Paths with a backup lookup in flight
+	backupPending map[string]bool
+
+	// Trash quota state
+	quotaOverages []trash.Overage // Volumes whose trash would exceed quota
+	deletePlan    deletePlan      // How to handle over-quota volumes
 
 	// Deleting state
 	deleteSpinner      spinner.Model
 	deleteProgress     int
 	deleteTotal        int
 	deleteErrors       []string
+	deleteSkipped      []string       // Paths skipped because they changed since selection
+	deleteNotes        []string       // Trash quota actions taken
+	dryRunReport       *dryrun.Report // What the last dry run would have done
 	deleteProgressChan chan deleteProgressMsg
 	lastFreedSize      int64 // Size freed in last delete operation
 
+	diskFree mounts.Usage // Of the volume holding the first root; zero when unknown
+
 	// Window dimensions
 	width  int
 	height int
+
+	// crash reports panics in goroutines the model starts; nil in tests
+	crash *crashHandler
 }
 
 // NewModel creates a new TUI model with the given options.
@@ -150,9 +318,18 @@ func NewModel(opts Options) Model {
 	// Subscribe to log entries for status bar hints
 	logEntryChan := logging.Subscribe()
 
-	return Model{
+	resultModel := newResultModelWithTags(opts.Tags)
+	if opts.Columns != nil {
+		resultModel.columns = *opts.Columns
+	}
+	resultModel.readOnly = opts.ReadOnly
+	resultModel.backups = opts.Backups
+	resultModel.ageColors = opts.AgeColors
+	resultModel.SetRoots(opts.Roots)
+
+	m := Model{
 		state:       StateResults,
-		resultModel: NewResultModel(nil), // Start with empty results
+		resultModel: resultModel,
 		options:     opts,
 		ctx:         ctx,
 		cancel:      cancel,
@@ -168,7 +345,11 @@ func NewModel(opts Options) Model {
 		progressChan:   make(chan types.ScanProgress, 100),
 		logEntryChan:   logEntryChan,
 		logViewer:      NewLogViewerState(),
+		backupPending:  make(map[string]bool),
+		sessionDeletes: make(map[string]bool),
 	}
+	m.loadSession()
+	return m
 }
 
 // Init initializes the model.
@@ -180,6 +361,7 @@ func (m Model) Init() tea.Cmd {
 		m.listenForLogEntries(),
 		m.tickUI(),
 		m.loadTree(), // Attempt to load tree view from daemon
+		m.checkDiskFree(true),
 	)
 }
 
@@ -208,16 +390,28 @@ type DaemonFilesMsg struct {
 	Files        []types.FileInfo
 	DirsScanned  int64
 	FilesScanned int64
+	Truncated    bool     // The daemon returned only the largest matches
+	NextPages    []string // By root, fetches the files after these
+}
+
+// DaemonPageMsg is sent when the daemon returns a further page of files.
+type DaemonPageMsg struct {
+	Root      int // Index of the root the page is of
+	Files     []types.FileInfo
+	Truncated bool
+	NextPage  string
+	Err       error
 }
 
 // LiveFileEventMsg is sent when a live file event is received from the daemon.
 type LiveFileEventMsg struct {
-	Event client.FileEvent
+	Event fileEvent
 }
 
 // LiveWatchStartedMsg is sent when live file watching starts successfully.
 type LiveWatchStartedMsg struct {
-	EventChan <-chan client.FileEvent
+	EventChan <-chan fileEvent
+	Conn      io.Closer // Closed when the stream is replaced; may be nil
 }
 
 // LiveWatchErrorMsg is sent when live file watching encounters an error.
@@ -230,9 +424,24 @@ type LogEntryMsg struct {
 	Entry logging.LogEntry
 }
 
+// daemonLogsMsg is sent when the log viewer starts reading the daemon's log
+// entries from entries.
+type daemonLogsMsg struct {
+	entries <-chan logging.LogEntry
+}
+
+// daemonLogEntryMsg is sent for each log entry read from the daemon.
+type daemonLogEntryMsg struct {
+	entry logging.LogEntry
+}
+
+// daemonLogsEndedMsg is sent when reading the daemon's log entries stops,
+// or couldn't start.
+type daemonLogsEndedMsg struct{}
+
 // TreeLoadedMsg is sent when tree data is loaded from the daemon.
 type TreeLoadedMsg struct {
-	Root *client.TreeNode
+	Root *treeNode
 }
 
 // TreeErrorMsg is sent when tree loading fails.
@@ -242,7 +451,8 @@ type TreeErrorMsg struct {
 
 // TreeWatchStartedMsg is sent when tree watching starts successfully.
 type TreeWatchStartedMsg struct {
-	EventChan <-chan client.TreeEvent
+	EventChan <-chan treeEvent
+	Conn      io.Closer // Closed when the stream is replaced; may be nil
 }
 
 // TreeWatchErrorMsg is sent when tree watching encounters an error.
@@ -252,12 +462,23 @@ type TreeWatchErrorMsg struct {
 
 // TreeEventMsg is sent when a tree event is received from the daemon.
 type TreeEventMsg struct {
-	Event client.TreeEvent
+	Event treeEvent
 }
 
 // TreeWatchEndedMsg is sent when the tree watch stream closes.
 type TreeWatchEndedMsg struct{}
 
+// treeFlushMsg applies tree changes queued since the last flush.
+type treeFlushMsg struct{}
+
+// scheduleTreeFlush returns a command that flushes queued tree changes after
+// the debounce delay, so bursts of events share one aggregate update.
+func scheduleTreeFlush() tea.Cmd {
+	return tea.Tick(tree.DefaultFlushDelay, func(time.Time) tea.Msg {
+		return treeFlushMsg{}
+	})
+}
+
 // Update handles messages.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -289,7 +510,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Keep UI refreshing during scanning, live watching, tree watching, notifications, or status hint
-		if !m.scanDone || m.liveWatching || m.treeWatching || len(m.notifications) > 0 || m.statusHint != nil {
+		if !m.scanDone || m.liveWatching || m.treeWatching || m.liveReconnecting || m.treeReconnecting ||
+			len(m.notifications) > 0 || m.statusHint != nil {
 			return m, m.tickUI()
 		}
 		return m, nil
@@ -297,6 +519,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ProgressMsg:
 		m.scanProgress.DirsScanned = msg.DirsScanned
 		m.scanProgress.FilesScanned = msg.FilesScanned
+		m.scanProgress.Estimate, m.scanProgress.HasEstimate = m.scanProgress.estimator.Update(types.ScanProgress(msg), time.Now())
 		// Freeze elapsed time when walk completes
 		if msg.WalkComplete && m.scanProgress.WalkCompleteElapsed == 0 {
 			m.scanProgress.WalkCompleteElapsed = time.Since(m.scanProgress.StartTime)
@@ -304,6 +527,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Keep listening for more progress
 		return m, m.listenForProgress()
 
+	case backupLookupMsg:
+		return m, m.startBackupLookup(msg.path)
+
+	case backupCheckedMsg:
+		delete(m.backupPending, msg.path)
+		return m, nil
+
 	case FileFoundMsg:
 		// Add file to results as it's found (if it passes the filter)
 		if m.filePassesFilter(msg.File) {
@@ -318,6 +548,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, f := range filteredFiles {
 			m.resultModel.AddFile(f)
 		}
+		m.nextPages = msg.NextPages
+		m.pageFetching = make([]bool, len(msg.NextPages))
+		m.resultModel.SetTruncated(msg.Truncated, m.morePages())
+		m.resumeListSession()
 		// Update progress
 		m.scanProgress.DirsScanned = msg.DirsScanned
 		m.scanProgress.FilesScanned = msg.FilesScanned
@@ -333,16 +567,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		logging.Get("tui").Info("scan completed via daemon",
 			"files", len(filteredFiles),
 			"filtered_from", len(msg.Files),
+			"truncated", msg.Truncated,
 			"elapsed", elapsed.Round(time.Millisecond))
 		// Start live file watching
 		if !m.options.NoDaemon {
-			return m, m.startLiveWatch()
+			return m, tea.Batch(m.startLiveWatch(), m.scheduleBackupLookup(), m.fetchPageNearEnd(), m.checkWatchSuggestion())
+		}
+		return m, tea.Batch(m.scheduleBackupLookup(), m.fetchPageNearEnd())
+
+	case DaemonPageMsg:
+		m.pageFetching[msg.Root] = false
+		if msg.Err != nil {
+			// Keep what's loaded; the notice still says the list is partial
+			logging.Get("tui").Warn("fetching more files from daemon failed", "error", msg.Err)
+			m.nextPages[msg.Root] = ""
+			m.pageFailed = true
+			m.resultModel.SetTruncated(true, m.morePages())
+			return m, nil
 		}
-		return m, nil
+		// Live events may have added some of these files already
+		loaded := make(map[string]bool, len(m.resultModel.files))
+		for _, f := range m.resultModel.files {
+			loaded[f.Path] = true
+		}
+		for _, f := range m.applyFilterToFiles(msg.Files) {
+			if !loaded[f.Path] {
+				m.resultModel.AddFile(f)
+			}
+		}
+		m.nextPages[msg.Root] = msg.NextPage
+		m.resultModel.SetTruncated(msg.Truncated || m.pageFailed || m.morePages(), m.morePages())
+		return m, m.fetchPageNearEnd()
 
 	case ScanDoneMsg:
 		m.scanDone = true
 		m.scanProgress.Scanning = false
+		m.resumeListSession()
+		if msg.Err != nil {
+			logging.Get("tui").Error("scan failed", "error", msg.Err)
+		}
 		elapsed := time.Since(m.scanProgress.StartTime)
 		// Update metrics in result model
 		m.resultModel.metrics = ScanMetrics{
@@ -356,19 +619,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"elapsed", elapsed.Round(time.Millisecond))
 		// Start live file watching if daemon is available
 		if !m.options.NoDaemon {
-			return m, m.startLiveWatch()
+			return m, tea.Batch(m.startLiveWatch(), m.scheduleBackupLookup(), m.lookupOwners())
 		}
+		return m, tea.Batch(m.scheduleBackupLookup(), m.lookupOwners())
+
+	case ownersMsg:
+		m.resultModel.SetOwners(msg.owners)
 		return m, nil
 
 	case LiveWatchStartedMsg:
-		m.liveWatching = true
-		m.liveEventChan = msg.EventChan
-		logging.Get("tui").Debug("live watch started")
-		return m, m.listenForLiveEvents()
+		return m, m.handleLiveWatchStarted(msg)
 
 	case LiveWatchErrorMsg:
-		// Live watching failed, continue without it
-		m.liveWatching = false
+		return m, m.handleLiveWatchError(msg.Err)
+
+	case liveRetryMsg:
+		if !m.liveReconnecting {
+			return m, nil
+		}
+		return m, m.startLiveWatch()
+
+	case daemonResyncMsg:
+		m.applyResync(msg)
 		return m, nil
 
 	case LiveFileEventMsg:
@@ -386,6 +658,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.pendingRename = nil
 		}
 
+		// Files under a directory hidden with 'x' stay out of the list
+		if m.isHidden(msg.Event.Path) && (msg.Event.NewPath == "" || m.isHidden(msg.Event.NewPath)) {
+			return m, m.listenForLiveEvents()
+		}
+
+		// A renamed directory moves its files in one event
+		if msg.Event.Type == "renamed" && msg.Event.NewPath != "" {
+			if m.resultModel.RenameDir(msg.Event.Path, msg.Event.NewPath) > 0 {
+				m.notifications = append(m.notifications, Notification{
+					Type:      NotificationRenamed,
+					Message:   fmt.Sprintf("%s → %s", truncateFilename(msg.Event.Path, 18), truncateFilename(msg.Event.NewPath, 18)),
+					Expires:   now.Add(3 * time.Second),
+					CreatedAt: now,
+				})
+			}
+			return m, m.listenForLiveEvents()
+		}
+
 		// Handle rename correlation
 		if msg.Event.Type == "renamed" {
 			// Store for correlation with upcoming create
@@ -445,18 +735,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Keep listening for more log entries
 		return m, m.listenForLogEntries()
 
+	case daemonLogsMsg:
+		m.daemonLogChan = msg.entries
+		return m, m.listenForDaemonLogs()
+
+	case daemonLogEntryMsg:
+		// The daemon's entries show in the viewer but not as hints
+		m.logViewer.AddEntry(msg.entry)
+		return m, m.listenForDaemonLogs()
+
+	case daemonLogsEndedMsg:
+		// Opening the viewer again tries again
+		m.daemonLogs = false
+		m.daemonLogChan = nil
+		return m, nil
+
 	case TreeLoadedMsg:
 		// Convert client tree to internal tree representation
 		treeRoot := convertClientTreeToNode(msg.Root)
 		if treeRoot != nil {
 			treeRoot.Expanded = true // Expand only the root node
+			prev := m.treeView
 			m.treeView = NewTreeView(treeRoot)
+			m.treeView.ageColors = m.options.AgeColors
+			m.treeView.RestoreState(prev)
+			if m.options.Owner != nil {
+				m.treeView.RemoveUnowned(m.options.Owner)
+			}
 			// Freeze elapsed time - tree is loaded, scan is done
 			if m.scanProgress.WalkCompleteElapsed == 0 && !m.scanProgress.StartTime.IsZero() {
 				m.scanProgress.WalkCompleteElapsed = time.Since(m.scanProgress.StartTime)
 			}
 			m.scanProgress.Scanning = false
-			// Keep treeMode = false, list view is default (press 't' for tree)
+			// The list view is the default (press 't' for tree), unless
+			// the last session was left in the tree
+			m.resumeTreeSession()
 			logging.Get("tui").Info("tree view loaded",
 				"nodes", len(m.treeView.flat),
 				"largeFileSize", types.FormatSize(treeRoot.LargeFileSize))
@@ -468,27 +781,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case TreeErrorMsg:
+		if m.treeReconnecting {
+			// Keep the tree shown and try again later
+			return m, m.retryTreeWatch(msg.Err)
+		}
 		// Tree loading failed, stay in flat list mode
 		logging.Get("tui").Debug("tree view unavailable", "error", msg.Err)
 		m.treeMode = false
 		return m, nil
 
 	case TreeWatchStartedMsg:
-		m.treeWatching = true
-		m.treeEventChan = msg.EventChan
-		logging.Get("tui").Debug("tree watch started")
-		return m, m.listenForTreeEvents()
+		return m, m.handleTreeWatchStarted(msg)
 
 	case TreeWatchErrorMsg:
-		// Tree watching failed, continue without it
-		m.treeWatching = false
-		logging.Get("tui").Debug("tree watch unavailable", "error", msg.Err)
-		return m, nil
+		return m, m.handleTreeWatchError(msg.Err)
 
 	case TreeWatchEndedMsg:
-		m.treeWatching = false
-		logging.Get("tui").Debug("tree watch ended")
-		return m, nil
+		return m, m.handleTreeWatchError(errors.New("tree watch stream closed"))
+
+	case treeRetryMsg:
+		if !m.treeReconnecting {
+			return m, nil
+		}
+		// Reload the tree, which starts the watch again once loaded
+		return m, m.loadTree()
 
 	case TreeEventMsg:
 		if m.treeView == nil {
@@ -498,15 +814,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		now := time.Now()
 		switch msg.Event.Type {
 		case "created":
-			m.treeView.AddFile(msg.Event.Path, msg.Event.Size, msg.Event.ModTime)
+			m.treeView.QueueFile(msg.Event.Path, msg.Event.Size, msg.Event.ModTime)
 			m.notifications = append(m.notifications, Notification{
 				Type:      NotificationAdded,
 				Message:   "New: " + truncateFilename(msg.Event.Path, 30),
 				Expires:   now.Add(3 * time.Second),
 				CreatedAt: now,
 			})
+		case "renamed":
+			m.treeView.QueueRename(msg.Event.Path, msg.Event.NewPath)
+			m.notifications = append(m.notifications, Notification{
+				Type:      NotificationRenamed,
+				Message:   fmt.Sprintf("%s → %s", truncateFilename(msg.Event.Path, 18), truncateFilename(msg.Event.NewPath, 18)),
+				Expires:   now.Add(3 * time.Second),
+				CreatedAt: now,
+			})
 		case "deleted":
-			m.treeView.RemoveFile(msg.Event.Path)
+			m.treeView.QueueRemove(msg.Event.Path)
 			m.notifications = append(m.notifications, Notification{
 				Type:      NotificationRemoved,
 				Message:   "Removed: " + truncateFilename(msg.Event.Path, 30),
@@ -514,7 +838,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				CreatedAt: now,
 			})
 		case "modified":
-			m.treeView.UpdateFile(msg.Event.Path, msg.Event.Size)
+			m.treeView.QueueFile(msg.Event.Path, msg.Event.Size, msg.Event.ModTime)
 			m.notifications = append(m.notifications, Notification{
 				Type:      NotificationModified,
 				Message:   "Modified: " + truncateFilename(msg.Event.Path, 30),
@@ -522,8 +846,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				CreatedAt: now,
 			})
 		}
+		if m.treeView.Pending() && !m.treeFlushPending {
+			m.treeFlushPending = true
+			return m, tea.Batch(m.listenForTreeEvents(), scheduleTreeFlush())
+		}
 		return m, m.listenForTreeEvents()
 
+	case treeFlushMsg:
+		m.treeFlushPending = false
+		if m.treeView != nil {
+			m.treeView.Flush()
+		}
+		return m, nil
+
 	case spinner.TickMsg:
 		if m.state == StateDeleting {
 			var cmd tea.Cmd
@@ -532,14 +867,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case trashQuotaMsg:
+		return m.handleTrashQuota(msg)
+
+	case diskFreeMsg:
+		return m, m.handleDiskFree(msg)
+
+	case dirEntriesMsg:
+		m.handleDirEntries(msg)
+		return m, nil
+
+	case diskFreeTickMsg:
+		return m, m.checkDiskFree(true)
+
+	case undoDoneMsg:
+		m.handleUndoDone(msg)
+		if m.deleted != nil {
+			return m, m.loadDeleted()
+		}
+		return m, nil
+
+	case deletedLoadedMsg:
+		m.handleDeletedLoaded(msg)
+		return m, nil
+
+	case watchSuggestionMsg:
+		m.handleWatchSuggestion(msg)
+		return m, nil
+
+	case watchSuggestionDoneMsg:
+		m.handleWatchSuggestionDone(msg)
+		return m, nil
+
+	case excludeSavedMsg:
+		m.handleExcludeSaved(msg)
+		return m, nil
+
+	case archiveListedMsg:
+		m.handleArchiveListed(msg)
+		return m, nil
+
+	case moveProgressMsg:
+		return m, m.handleMoveProgress(msg)
+
+	case moveDoneMsg:
+		m.handleMoveDone(msg)
+		return m, nil
+
 	case deleteProgressMsg:
 		m.deleteProgress = msg.current
+		if msg.note != "" {
+			m.deleteNotes = append(m.deleteNotes, msg.note)
+		}
 		if msg.err != nil {
 			m.deleteErrors = append(m.deleteErrors, msg.err.Error())
 		}
+		if msg.skipped != "" {
+			m.deleteSkipped = append(m.deleteSkipped, msg.skipped)
+			m.lastFreedSize -= msg.skippedSize
+		}
 		if msg.done {
 			m.state = StateComplete
-			return m, nil
+			if msg.entry != "" {
+				m.undoEntry = msg.entry
+				m.sessionDeletes[msg.entry] = true
+			}
+			m.dryRunReport = msg.projection
+			return m, m.checkDiskFree(false)
 		}
 		// Keep listening for more progress
 		return m, m.listenForDeleteProgress()
@@ -587,24 +981,114 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.deleted != nil {
+			return m.handleDeletedKey(key)
+		}
+
+		// Tag prompt and summary take priority over navigation
+		if m.tagPrompt.Open {
+			return m.handleTagPromptKey(msg)
+		}
+		if m.tagSummaryOpen {
+			switch key {
+			case "esc", "#":
+				m.tagSummaryOpen = false
+			case "q":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.dirStats != nil {
+			switch key {
+			case "esc", "?", "enter":
+				m.dirStats = nil
+			case "q":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.histogramOpen {
+			switch key {
+			case "esc", "H", "enter":
+				m.histogramOpen = false
+			case "q":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		if m.archive != nil {
+			return m.handleArchiveKey(key)
+		}
+		if m.move != nil {
+			return m.handleMoveKey(msg)
+		}
+		if m.hidePrompt != nil {
+			return m.handleHidePromptKey(key)
+		}
+		// Arrives on its own, so waits for any other popup to close
+		if m.watchSuggestion != nil {
+			return m.handleWatchSuggestionKey(key)
+		}
+		if m.search.Open {
+			return m.handleSearchKey(msg)
+		}
+
+		// Treemap key handling
+		if m.treemapMode && m.treemap != nil {
+			return m.handleTreemapKey(key)
+		}
+
+		// Esc ends a visual range, then a search, before it quits
+		if key == "esc" && m.resultModel.visual && !m.treeMode && !m.treemapMode {
+			m.resultModel.endRange()
+			return m, nil
+		}
+		if key == "esc" && m.search.active() && !m.treemapMode {
+			m.clearSearch()
+			return m, nil
+		}
+
 		// Tree mode key handling
 		if m.treeMode && m.treeView != nil {
 			switch key {
 			case "q", "esc":
 				return m, tea.Quit
 			case "L":
-				m.logViewer.Toggle()
+				return m, m.toggleLogViewer()
+			case "T":
+				m.openTagPrompt()
+			case "#":
+				m.tagSummaryOpen = m.options.Tags != nil
+			case "H":
+				m.histogramOpen = true
+			case "i":
+				return m, m.openArchive()
+			case "M":
+				m.openMove()
 			case "up", "k":
 				m.treeView.MoveUp()
 			case "down", "j":
 				m.treeView.MoveDown()
 			case "enter", " ":
 				m.treeView.Toggle()
+			case "P":
+				// Keep the directory expanded across refreshes
+				m.treeView.TogglePin()
+			case "N":
+				// Show or hide each directory's inode count
+				return m, m.toggleEntries()
+			case "?":
+				m.openDirStats()
+			case "U":
+				return m, m.undoDelete()
+			case "D":
+				return m, m.openDeleted()
 			case "d":
 				// Delete selected files
-				if m.treeView.HasSelection() {
-					m.state = StateConfirm
-					m.confirmFocused = 0
+				if m.options.ReadOnly {
+					logReadOnly()
+				} else if m.treeView.HasSelection() && m.deselectReadOnly() {
+					return m.openConfirm()
 				}
 			case "c":
 				// Clear selection
@@ -612,6 +1096,21 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			case "t":
 				// Toggle tree view mode (switch to flat list)
 				m.treeMode = false
+				if m.search.active() {
+					m.updateSearch(false)
+				}
+			case "m":
+				m.openTreemap()
+			case "f":
+				m.openSearch()
+			case "]":
+				m.jumpMatch(1)
+			case "[":
+				m.jumpMatch(-1)
+			case "y":
+				return m, copyPaths(m.cursorPath())
+			case "Y":
+				return m, copyPaths(m.selectedPaths())
 			}
 			return m, nil
 		}
@@ -621,22 +1120,69 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "q", "esc":
 			return m, tea.Quit
 		case "L":
-			m.logViewer.Toggle()
+			return m, m.toggleLogViewer()
+		case "T":
+			m.openTagPrompt()
+		case "#":
+			m.tagSummaryOpen = m.options.Tags != nil
+		case "H":
+			m.histogramOpen = true
+		case "i":
+			return m, m.openArchive()
+		case "M":
+			m.openMove()
+		case "x":
+			m.openHidePrompt()
 		case "enter":
-			if m.resultModel.HasSelection() {
-				m.state = StateConfirm
-				m.confirmFocused = 0 // Default to cancel
+			if m.options.ReadOnly {
+				logReadOnly()
+			} else if m.resultModel.HasSelection() && m.deselectReadOnly() {
+				return m.openConfirm()
 			}
 		case "t":
 			// Toggle to tree view mode if available
 			if m.treeView != nil {
 				m.treeMode = true
+				if m.search.active() {
+					m.updateSearch(false)
+				}
 			}
+		case "m":
+			m.openTreemap()
+		case "U":
+			return m, m.undoDelete()
+		case "D":
+			return m, m.openDeleted()
+		case "1", "2", "3", "4", "5":
+			// Show or hide a column
+			m.resultModel.columns.Toggle(toggleColumns[key[0]-'1'])
+		case "p":
+			// Switch between full paths and file names
+			m.resultModel.columns.TogglePath()
+		case "u":
+			// Cycle size units
+			m.resultModel.columns.CycleUnits()
+		case "f":
+			m.openSearch()
+		case "]":
+			m.jumpMatch(1)
+			return m, tea.Batch(m.scheduleBackupLookup(), m.fetchPageNearEnd())
+		case "[":
+			m.jumpMatch(-1)
+			return m, tea.Batch(m.scheduleBackupLookup(), m.fetchPageNearEnd())
+		case "y":
+			return m, copyPaths(m.cursorPath())
+		case "Y":
+			return m, copyPaths(m.selectedPaths())
 		default:
 			m.resultModel.HandleKey(key)
+			return m, tea.Batch(m.scheduleBackupLookup(), m.fetchPageNearEnd())
 		}
 
 	case StateConfirm:
+		if m.typedConfirm() {
+			return m.handleTypedConfirmKey(msg)
+		}
 		switch key {
 		case "q", "esc", "n":
 			m.state = StateResults
@@ -649,14 +1195,17 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.confirmFocused == 1 {
 				// Delete confirmed
-				return m.startDelete()
+				return m.confirmDelete()
 			}
 			m.state = StateResults
 		case "y":
 			// Shortcut for yes
-			return m.startDelete()
+			return m.confirmDelete()
 		}
 
+	case StateQuota:
+		return m.handleQuotaKey(key)
+
 	case StateDeleting:
 		// No key handling during delete
 
@@ -667,6 +1216,11 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.state = StateResults
 			return m, nil
 		}
+		if key == "U" && m.undoEntry != "" {
+			m.removeDeletedFiles()
+			m.state = StateResults
+			return m, m.undoDelete()
+		}
 		if key == "q" {
 			return m, tea.Quit
 		}
@@ -679,9 +1233,38 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) View() string {
 	switch m.state {
 	case StateResults:
+		if m.tagPrompt.Open {
+			return m.renderTagPrompt(m.renderResultsWithLogViewer())
+		}
+		if m.tagSummaryOpen {
+			return m.renderTagSummary(m.renderResultsWithLogViewer())
+		}
+		if m.dirStats != nil {
+			return m.renderDirStats(m.renderResultsWithLogViewer())
+		}
+		if m.histogramOpen {
+			return m.renderHistogram(m.renderResultsWithLogViewer())
+		}
+		if m.archive != nil {
+			return m.renderArchive(m.renderResultsWithLogViewer())
+		}
+		if m.move != nil {
+			return m.renderMove(m.renderResultsWithLogViewer())
+		}
+		if m.deleted != nil {
+			return m.renderDeleted(m.renderResultsWithLogViewer())
+		}
+		if m.hidePrompt != nil {
+			return m.renderHidePrompt(m.renderResultsWithLogViewer())
+		}
+		if m.watchSuggestion != nil {
+			return m.renderWatchSuggestion(m.renderResultsWithLogViewer())
+		}
 		return m.renderResultsWithLogViewer()
 	case StateConfirm:
 		return m.renderConfirmDialog()
+	case StateQuota:
+		return m.renderQuotaDialog()
 	case StateDeleting:
 		return m.renderDeleting()
 	case StateComplete:
@@ -692,6 +1275,15 @@ func (m Model) View() string {
 
 // renderResultsWithLogViewer renders the results view, optionally with the log viewer pane.
 func (m Model) renderResultsWithLogViewer() string {
+	// Treemap rendering
+	if m.treemapMode && m.treemap != nil {
+		if !m.logViewer.Open {
+			return m.renderTreemapView(m.height)
+		}
+		logViewerHeight := max(m.height/3, 5)
+		return m.renderTreemapView(m.height-logViewerHeight) + "\n" + m.renderLogViewerPane(logViewerHeight)
+	}
+
 	// Tree mode rendering
 	if m.treeMode && m.treeView != nil {
 		if !m.logViewer.Open {
@@ -717,7 +1309,7 @@ func (m Model) renderResultsWithLogViewer() string {
 
 	// Flat list mode rendering
 	if !m.logViewer.Open {
-		return m.resultModel.ViewWithProgressAndNotifications(m.scanProgress, m.notifications, m.liveWatching, m.statusHint)
+		return m.resultModel.ViewWithProgressAndNotifications(m.scanProgress, m.notifications, m.liveState(), m.statusHint)
 	}
 
 	// Calculate heights: log viewer takes bottom 1/3 of screen
@@ -729,7 +1321,7 @@ func (m Model) renderResultsWithLogViewer() string {
 
 	// Render results with reduced height
 	m.resultModel.SetDimensions(m.width, resultsHeight)
-	resultsView := m.resultModel.ViewWithProgressAndNotifications(m.scanProgress, m.notifications, m.liveWatching, m.statusHint)
+	resultsView := m.resultModel.ViewWithProgressAndNotifications(m.scanProgress, m.notifications, m.liveState(), m.statusHint)
 
 	// Render log viewer pane
 	logViewerView := m.renderLogViewerPane(logViewerHeight)
@@ -824,7 +1416,7 @@ func (m Model) renderTreeHeader(_ int) string {
 	// (both have the same filter applied)
 	fileCount := len(m.resultModel.files)
 	totalSize := m.resultModel.TotalSize()
-	return renderAppHeader(fileCount, totalSize, m.lastFreedSize, m.treeWatching)
+	return renderAppHeader(fileCount, totalSize, m.resultModel.ActualSize(), m.lastFreedSize, m.diskFree, m.treeLiveState(), m.options.ReadOnly)
 }
 
 // renderTreeMetrics renders the scan metrics line for tree view mode.
@@ -836,25 +1428,35 @@ func (m Model) renderTreeMetrics() string {
 	} else if !m.scanProgress.StartTime.IsZero() {
 		elapsed = time.Since(m.scanProgress.StartTime)
 	}
-	return renderScanMetrics(m.scanProgress.DirsScanned, m.scanProgress.FilesScanned, elapsed)
+	return renderScanMetrics(m.scanProgress.DirsScanned, m.scanProgress.FilesScanned, elapsed, m.scanProgress.estimate())
 }
 
 // renderTreeHintsBar renders the key hints bar for tree view mode (same as list view).
 func (m Model) renderTreeHintsBar(_ int) string {
+	if m.search.active() && m.search.Tree {
+		return m.renderSearchBar()
+	}
 	hints := []struct {
-		key  string
-		desc string
+		key      string
+		desc     string
+		disabled bool
 	}{
-		{"Space", "Select"},
-		{"Enter", "Expand"},
-		{"d", "Delete"},
-		{"t", "List"},
-		{"q", "Quit"},
+		{"Space", "Select", false},
+		{"Enter", "Expand", false},
+		{"P", "Pin", false},
+		{"?", "Inside", false},
+		{"d", "Delete", m.options.ReadOnly},
+		{"M", "Move", m.options.ReadOnly},
+		{"T", "Tag", false},
+		{"f", "Find", false},
+		{"t", "List", false},
+		{"m", "Map", false},
+		{"q", "Quit", false},
 	}
 
 	var parts []string
 	for _, h := range hints {
-		parts = append(parts, keyStyle.Render("["+h.key+"]")+" "+keyDescStyle.Render(h.desc))
+		parts = append(parts, renderKeyHint(h.key, h.desc, h.disabled))
 	}
 
 	return "  " + strings.Join(parts, "  ")
@@ -874,14 +1476,33 @@ func (m Model) renderTreeHelpBar(width int) string {
 	hints = append(hints, keyStyle.Render("j/k")+" "+keyDescStyle.Render("navigate"))
 	hints = append(hints, keyStyle.Render("enter")+" "+keyDescStyle.Render("toggle"))
 	hints = append(hints, keyStyle.Render("space")+" "+keyDescStyle.Render("select"))
+	hints = append(hints, keyStyle.Render("P")+" "+keyDescStyle.Render("pin"))
+	hints = append(hints, keyStyle.Render("?")+" "+keyDescStyle.Render("inside"))
+	hints = append(hints, keyStyle.Render("H")+" "+keyDescStyle.Render("sizes"))
+	hints = append(hints, keyStyle.Render("N")+" "+keyDescStyle.Render("inodes"))
+	hints = append(hints, keyStyle.Render("i")+" "+keyDescStyle.Render("archive"))
+	if m.options.ReadOnly {
+		hints = append(hints, disabledKeyStyle.Render("M move"))
+	} else {
+		hints = append(hints, keyStyle.Render("M")+" "+keyDescStyle.Render("move"))
+	}
 
 	if m.treeView.HasSelection() {
-		hints = append(hints, keyStyle.Render("d")+" "+keyDescStyle.Render("delete"))
+		if m.options.ReadOnly {
+			hints = append(hints, disabledKeyStyle.Render("d delete"))
+		} else {
+			hints = append(hints, keyStyle.Render("d")+" "+keyDescStyle.Render("delete"))
+		}
 		hints = append(hints, keyStyle.Render("c")+" "+keyDescStyle.Render("clear"))
 	}
+	hints = append(hints, keyStyle.Render("y")+" "+keyDescStyle.Render("copy path"))
 
 	hints = append(hints, keyStyle.Render("t")+" "+keyDescStyle.Render("flat view"))
+	hints = append(hints, keyStyle.Render("m")+" "+keyDescStyle.Render("treemap"))
 	hints = append(hints, keyStyle.Render("q")+" "+keyDescStyle.Render("quit"))
+	if legend := m.options.AgeColors.Legend(); legend != "" {
+		hints = append(hints, legend)
+	}
 
 	return "  " + strings.Join(hints, "  ")
 }
@@ -945,9 +1566,13 @@ func (m Model) renderConfirmDialog() string {
 	}
 
 	dialogContent.WriteString("\n")
+	dialogContent.WriteString(m.renderCloudWarning())
 
-	// Clear button options
-	if m.confirmFocused == 0 {
+	if permanent := m.permanentTargets(); len(permanent) > 0 {
+		dialogContent.WriteString(m.renderPermanentConfirm(permanent, selectedCount))
+	} else if m.typedConfirm() {
+		dialogContent.WriteString(m.renderStrictConfirm())
+	} else if m.confirmFocused == 0 {
 		dialogContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("[n] Cancel"))
 		dialogContent.WriteString("   ")
 		dialogContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("[y] Delete"))
@@ -969,6 +1594,41 @@ func (m Model) renderConfirmDialog() string {
 	return m.overlayDialog(bg, dialog)
 }
 
+// Dry-run summary limits, to keep the completion dialog small.
+const (
+	dryRunDirs   = 3
+	dryRunRecent = 3
+)
+
+// renderDryRunReport renders the directories a dry run would free most,
+// each volume's free space before and after, and the recently modified
+// files among those it would delete.
+func renderDryRunReport(r *dryrun.Report) string {
+	var b strings.Builder
+	for i, d := range r.Dirs {
+		if i == dryRunDirs {
+			b.WriteString("\n" + mutedTextStyle.Render(fmt.Sprintf("  ... %d more directories", len(r.Dirs)-dryRunDirs)))
+			break
+		}
+		b.WriteString("\n" + mutedTextStyle.Render(fmt.Sprintf("  %8s  %s", types.FormatSize(d.Size), truncatePath(d.Path, 50))))
+	}
+	for _, v := range r.Volumes {
+		b.WriteString("\n" + fmt.Sprintf("%s: %.1f%% free → %.1f%%", truncatePath(v.Mount, 30), v.FreePercent, v.FreePercentAfter))
+	}
+	if len(r.Recent) > 0 {
+		warnStyle := lipgloss.NewStyle().Foreground(warningColor)
+		b.WriteString("\n" + warnStyle.Render(fmt.Sprintf("%d modified in the last %d days:", len(r.Recent), r.RecentDays)))
+		for i, f := range r.Recent {
+			if i == dryRunRecent {
+				b.WriteString("\n" + warnStyle.Render(fmt.Sprintf("  ... and %d more", len(r.Recent)-dryRunRecent)))
+				break
+			}
+			b.WriteString("\n" + warnStyle.Render("  "+truncatePath(f.Path, 50)))
+		}
+	}
+	return b.String()
+}
+
 // renderDeleting renders the deletion progress view.
 func (m Model) renderDeleting() string {
 	contentWidth := m.width - 4
@@ -1009,6 +1669,23 @@ func (m Model) renderDeleting() string {
 		}
 	}
 
+	// Skipped (changed since selection)
+	if len(m.deleteSkipped) > 0 {
+		b.WriteString("\n")
+		b.WriteString(mutedTextStyle.Render(fmt.Sprintf("  %d skipped (changed since selection):", len(m.deleteSkipped))))
+		b.WriteString("\n")
+		for _, p := range m.deleteSkipped {
+			b.WriteString(mutedTextStyle.Render("    - " + truncatePath(p, contentWidth-6)))
+			b.WriteString("\n")
+		}
+	}
+
+	// Trash quota actions
+	for _, note := range m.deleteNotes {
+		b.WriteString("\n")
+		b.WriteString(mutedTextStyle.Render("  " + note))
+	}
+
 	return outerBoxStyle.Width(m.width - 2).Render(b.String())
 }
 
@@ -1019,12 +1696,12 @@ func (m Model) renderComplete() string {
 
 	var dialogContent strings.Builder
 
-	deleted := m.deleteProgress - len(m.deleteErrors)
+	deleted := m.deleteProgress - len(m.deleteErrors) - len(m.deleteSkipped)
 	sizeStyle := lipgloss.NewStyle().Foreground(successColor)
 
 	freedSize := sizeStyle.Render(types.FormatSize(m.lastFreedSize))
 	if m.options.DryRun {
-		dialogContent.WriteString(fmt.Sprintf("Would free %s (%d files)", freedSize, m.deleteTotal))
+		dialogContent.WriteString(fmt.Sprintf("Would free %s (%d files)", freedSize, m.deleteTotal-len(m.deleteSkipped)))
 	} else {
 		dialogContent.WriteString(fmt.Sprintf("Freed %s (%d files)", freedSize, deleted))
 	}
@@ -1034,8 +1711,30 @@ func (m Model) renderComplete() string {
 		dialogContent.WriteString(errorStyle.Render(fmt.Sprintf(", %d failed", len(m.deleteErrors))))
 	}
 
+	if len(m.deleteSkipped) > 0 {
+		warnStyle := lipgloss.NewStyle().Foreground(warningColor)
+		dialogContent.WriteString(warnStyle.Render(fmt.Sprintf(", %d skipped (changed since selection)", len(m.deleteSkipped))))
+		for _, p := range m.deleteSkipped {
+			dialogContent.WriteString("\n")
+			dialogContent.WriteString(mutedTextStyle.Render("  " + truncatePath(p, 50)))
+		}
+	}
+
+	for _, note := range m.deleteNotes {
+		dialogContent.WriteString("\n")
+		dialogContent.WriteString(mutedTextStyle.Render(note))
+	}
+
+	if m.dryRunReport != nil {
+		dialogContent.WriteString(renderDryRunReport(m.dryRunReport))
+	}
+
 	dialogContent.WriteString("\n\n")
-	dialogContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("[Enter] Continue  [q] Quit"))
+	hint := "[Enter] Continue  [q] Quit"
+	if m.undoEntry != "" {
+		hint = "[Enter] Continue  [U] Undo  [q] Quit"
+	}
+	dialogContent.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render(hint))
 
 	// Minimal dialog box
 	dialogStyle := lipgloss.NewStyle().
@@ -1098,37 +1797,73 @@ func (m Model) startStreamingScan() tea.Cmd {
 			}
 		}
 
-		// Fall back to direct scan
-		opts := scanner.Options{
-			Root:        m.options.Root,
-			MinSize:     m.options.MinSize,
-			Exclude:     m.options.Exclude,
-			DirWorkers:  m.options.DirWorkers,
-			FileWorkers: m.options.FileWorkers,
-			OnProgress: func(p types.ScanProgress) {
-				select {
-				case progressChan <- p:
-				default:
-					// Channel full, skip this update
-				}
-			},
-			OnFile: func(f types.FileInfo) {
-				select {
-				case fileChan <- f:
-				default:
-					// Channel full, skip this file (shouldn't happen)
+		// A remote root doesn't exist here, so there is nothing to scan
+		if m.options.Remote.Address != "" {
+			close(fileChan)
+			close(progressChan)
+			return ScanDoneMsg{Err: fmt.Errorf("could not load %s from %s", m.options.Root, m.options.Remote.Address)}
+		}
+
+		// Fall back to direct scan, of every root at once; progress is
+		// reported as the total over the roots
+		roots := m.roots()
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			progress = make([]types.ScanProgress, len(roots))
+			errs     = make([]error, len(roots))
+		)
+		for i, root := range roots {
+			opts := scanner.Options{
+				Root:        root,
+				MinSize:     m.options.MinSize,
+				Exclude:     m.options.Exclude,
+				DirWorkers:  m.options.DirWorkers,
+				FileWorkers: m.options.FileWorkers,
+				Owner:       m.options.Owner,
+				Symlinks:    m.options.Symlinks,
+
+				IncludeSnapshots: m.options.IncludeSnapshots,
+				OneFileSystem:    m.options.OneFileSystem,
+				CrossMounts:      m.options.CrossMounts,
+				ExpectedEntries:  scanner.LastEntries(config.CacheDir(), root),
+				OnProgress: func(p types.ScanProgress) {
+					mu.Lock()
+					progress[i] = p
+					total := sumProgress(progress)
+					mu.Unlock()
+					select {
+					case progressChan <- total:
+					default:
+						// Channel full, skip this update
+					}
+				},
+				OnFile: func(f types.FileInfo) {
+					select {
+					case fileChan <- f:
+					default:
+						// Channel full, skip this file (shouldn't happen)
+					}
+				},
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var res *types.ScanResult
+				res, errs[i] = scanner.New(opts).Scan(m.ctx)
+				if errs[i] == nil && m.ctx.Err() == nil {
+					recordEntries(root, res)
 				}
-			},
+			}()
 		}
-
-		s := scanner.New(opts)
-		_, err := s.Scan(m.ctx)
+		wg.Wait()
 
 		// Close channels when scan completes
 		close(fileChan)
 		close(progressChan)
 
-		if err != nil {
+		if err := errors.Join(errs...); err != nil {
 			return ScanDoneMsg{Err: err}
 		}
 
@@ -1136,6 +1871,38 @@ func (m Model) startStreamingScan() tea.Cmd {
 	}
 }
 
+// sumProgress totals the progress of scans of several roots. The walk is
+// complete when every root's is, and the entries to expect are known when
+// every root's are.
+func sumProgress(progress []types.ScanProgress) types.ScanProgress {
+	total := types.ScanProgress{WalkComplete: true}
+	expected := true
+	for _, p := range progress {
+		total.EntriesExpected += p.EntriesExpected
+		expected = expected && p.EntriesExpected > 0
+		total.DirsScanned += p.DirsScanned
+		total.FilesScanned += p.FilesScanned
+		total.LargeFiles += p.LargeFiles
+		total.BytesScanned += p.BytesScanned
+		total.WalkComplete = total.WalkComplete && p.WalkComplete
+		if p.CurrentPath != "" {
+			total.CurrentPath = p.CurrentPath
+		}
+	}
+	if !expected {
+		total.EntriesExpected = 0
+	}
+	return total
+}
+
+// recordEntries remembers how many entries a complete scan of root
+// examined, so the next scan of it can estimate its progress.
+func recordEntries(root string, res *types.ScanResult) {
+	if err := scanner.RecordEntries(config.CacheDir(), root, res.DirsScanned+res.FilesScanned); err != nil {
+		logging.Get("tui").Debug("failed to record scan size", "root", root, "error", err)
+	}
+}
+
 // listenForFiles returns a command that waits for files from the scanner.
 func (m Model) listenForFiles() tea.Cmd {
 	fileChan := m.fileChan
@@ -1149,56 +1916,6 @@ func (m Model) listenForFiles() tea.Cmd {
 	}
 }
 
-// tryDaemonInstantLoad attempts to get all files from the daemon instantly.
-// Returns a DaemonFilesMsg if successful, nil otherwise.
-func (m Model) tryDaemonInstantLoad() *DaemonFilesMsg {
-	// Check if daemon is running
-	pidPath := client.DefaultPIDPath()
-	if !client.IsDaemonRunning(pidPath) {
-		return nil
-	}
-
-	// Try to connect to daemon
-	socketPath := client.DefaultSocketPath()
-	daemonClient, err := client.ConnectWithContext(m.ctx, socketPath)
-	if err != nil {
-		return nil
-	}
-	defer daemonClient.Close()
-
-	// Resolve symlinks in root path to match daemon's indexed paths
-	// (e.g., /Volumes/Development -> /Users/user/Development)
-	root := m.options.Root
-	if resolved, err := filepath.EvalSymlinks(root); err == nil {
-		root = resolved
-	}
-
-	// Check if index is ready for this path
-	ready, err := daemonClient.IsIndexReady(m.ctx, root)
-	if err != nil || !ready {
-		return nil
-	}
-
-	// Query the daemon - get all files at once
-	files, err := daemonClient.GetLargeFiles(m.ctx, root, m.options.MinSize, m.options.Exclude, 0)
-	if err != nil {
-		return nil
-	}
-
-	// Get index status for statistics
-	var dirsIndexed, filesIndexed int64
-	if status, err := daemonClient.GetIndexStatus(m.ctx, root); err == nil && status != nil {
-		dirsIndexed = status.DirsIndexed
-		filesIndexed = status.FilesIndexed
-	}
-
-	return &DaemonFilesMsg{
-		Files:        files,
-		DirsScanned:  dirsIndexed,
-		FilesScanned: filesIndexed,
-	}
-}
-
 // listenForProgress returns a command that waits for progress updates.
 func (m Model) listenForProgress() tea.Cmd {
 	progressChan := m.progressChan
@@ -1228,122 +1945,91 @@ func (m Model) listenForLogEntries() tea.Cmd {
 	}
 }
 
-// startLiveWatch starts watching for live file events from the daemon.
-func (m Model) startLiveWatch() tea.Cmd {
-	ctx := m.ctx
-	root := m.options.Root
-	minSize := m.options.MinSize
-	exclude := m.options.Exclude
-
-	// Resolve symlinks to match daemon's indexed paths
-	// (e.g., /Volumes/Development -> /Users/user/Development)
-	if resolved, err := filepath.EvalSymlinks(root); err == nil {
-		root = resolved
+// toggleLogViewer opens or closes the log viewer. Opening it also starts
+// reading the daemon's log entries into it, unless they're being read or
+// the daemon isn't used.
+func (m *Model) toggleLogViewer() tea.Cmd {
+	m.logViewer.Toggle()
+	if !m.logViewer.Open || m.options.NoDaemon || m.daemonLogs {
+		return nil
 	}
+	m.daemonLogs = true
+	return m.startDaemonLogs()
+}
 
+// listenForDaemonLogs returns a command that waits for the daemon's next
+// log entry.
+func (m Model) listenForDaemonLogs() tea.Cmd {
+	entries := m.daemonLogChan
 	return func() tea.Msg {
-		// Check if daemon is running
-		pidPath := client.DefaultPIDPath()
-		if !client.IsDaemonRunning(pidPath) {
-			return LiveWatchErrorMsg{Err: errors.New("daemon not running")}
-		}
-
-		// Connect to daemon
-		socketPath := client.DefaultSocketPath()
-		daemonClient, err := client.ConnectWithContext(ctx, socketPath)
-		if err != nil {
-			return LiveWatchErrorMsg{Err: err}
+		if entries == nil {
+			return nil
 		}
-
-		// Start watching for file events
-		eventChan, err := daemonClient.WatchLargeFiles(ctx, root, minSize, exclude)
-		if err != nil {
-			daemonClient.Close()
-			return LiveWatchErrorMsg{Err: err}
+		entry, ok := <-entries
+		if !ok {
+			return daemonLogsEndedMsg{}
 		}
-
-		// Note: We don't close daemonClient here because the stream needs it to stay open.
-		// The connection will be closed when the context is cancelled.
-
-		return LiveWatchStartedMsg{EventChan: eventChan}
+		return daemonLogEntryMsg{entry: entry}
 	}
 }
 
 // listenForLiveEvents returns a command that waits for live file events.
 func (m Model) listenForLiveEvents() tea.Cmd {
 	eventChan := m.liveEventChan
+	ownerFilter := m.options.Owner
 	return func() tea.Msg {
 		if eventChan == nil {
 			return nil
 		}
-		event, ok := <-eventChan
-		if !ok {
-			// Channel closed, watching stopped
-			return LiveWatchErrorMsg{Err: errors.New("live watch stream closed")}
-		}
-		return LiveFileEventMsg{Event: event}
-	}
-}
-
-// startTreeWatch starts watching for tree events from the daemon.
-func (m Model) startTreeWatch() tea.Cmd {
-	ctx := m.ctx
-	root := m.options.Root
-	minSize := m.options.MinSize
-
-	// Resolve symlinks to match daemon's indexed paths
-	if resolved, err := filepath.EvalSymlinks(root); err == nil {
-		root = resolved
-	}
-
-	return func() tea.Msg {
-		// Check if daemon is running
-		pidPath := client.DefaultPIDPath()
-		if !client.IsDaemonRunning(pidPath) {
-			return TreeWatchErrorMsg{Err: errors.New("daemon not running")}
-		}
-
-		// Connect to daemon
-		socketPath := client.DefaultSocketPath()
-		daemonClient, err := client.ConnectWithContext(ctx, socketPath)
-		if err != nil {
-			return TreeWatchErrorMsg{Err: err}
-		}
-
-		// Start watching for tree events
-		eventChan, err := daemonClient.WatchTree(ctx, root, minSize)
-		if err != nil {
-			daemonClient.Close()
-			return TreeWatchErrorMsg{Err: err}
+		for {
+			event, ok := <-eventChan
+			if !ok {
+				// Channel closed, watching stopped
+				return LiveWatchErrorMsg{Err: errors.New("live watch stream closed")}
+			}
+			if !ownedEvent(ownerFilter, event.Type, event.Path) {
+				continue
+			}
+			return LiveFileEventMsg{Event: event}
 		}
-
-		// Note: We don't close daemonClient here because the stream needs it to stay open.
-		// The connection will be closed when the context is cancelled.
-
-		return TreeWatchStartedMsg{EventChan: eventChan}
 	}
 }
 
 // listenForTreeEvents returns a command that waits for tree events.
 func (m Model) listenForTreeEvents() tea.Cmd {
 	eventChan := m.treeEventChan
+	ownerFilter := m.options.Owner
 	return func() tea.Msg {
 		if eventChan == nil {
 			return nil
 		}
-		event, ok := <-eventChan
-		if !ok {
-			// Channel closed, watching stopped
-			return TreeWatchEndedMsg{}
+		for {
+			event, ok := <-eventChan
+			if !ok {
+				// Channel closed, watching stopped
+				return TreeWatchEndedMsg{}
+			}
+			if !ownedEvent(ownerFilter, event.Type, event.Path) {
+				continue
+			}
+			return TreeEventMsg{Event: event}
 		}
-		return TreeEventMsg{Event: event}
 	}
 }
 
+// ownedEvent reports whether a live event should be shown when filtering by
+// owner. Removals always pass so stale entries are cleared.
+func ownedEvent(f *owner.Filter, eventType, path string) bool {
+	if f == nil || (eventType != "created" && eventType != "modified") {
+		return true
+	}
+	return f.OwnsPath(path)
+}
+
 // handleLiveFileEvent processes a live file event and updates the results.
 // Returns a notification if one should be shown.
 // If a filter is provided, new/modified files are only added if they pass the filter.
-func handleLiveFileEvent(resultModel *ResultModel, event client.FileEvent, f *filter.Filter) *Notification {
+func handleLiveFileEvent(resultModel *ResultModel, event fileEvent, f *filter.Filter) *Notification {
 	const notificationDuration = 3 * time.Second
 	now := time.Now()
 	expires := now.Add(notificationDuration)
@@ -1419,6 +2105,38 @@ func handleLiveFileEvent(resultModel *ResultModel, event client.FileEvent, f *fi
 	return nil
 }
 
+// daemonPageSize is how many files are fetched from the daemon at a time;
+// the next page is fetched as the cursor nears the end of the list.
+const daemonPageSize = 500
+
+// fetchPageNearEnd fetches the daemon's next page of files when the cursor
+// is within a fifth of a page of the end of the list, or of its root's
+// section of it, so the list grows before the user reaches its end.
+func (m *Model) fetchPageNearEnd() tea.Cmd {
+	root, remaining := m.resultModel.CursorRoot()
+	if root >= len(m.nextPages) || m.nextPages[root] == "" || m.pageFetching[root] {
+		return nil
+	}
+	if remaining > daemonPageSize/5 {
+		return nil
+	}
+	m.pageFetching[root] = true
+	return m.fetchDaemonPage(root, m.nextPages[root])
+}
+
+// morePages reports whether the daemon has more files for any root.
+func (m Model) morePages() bool {
+	return slices.ContainsFunc(m.nextPages, func(token string) bool { return token != "" })
+}
+
+// roots returns the roots scanned.
+func (m Model) roots() []string {
+	if len(m.options.Roots) > 0 {
+		return m.options.Roots
+	}
+	return []string{m.options.Root}
+}
+
 // truncateFilename truncates a filename (not path) to fit within maxLen.
 func truncateFilename(path string, maxLen int) string {
 	// Extract just the filename
@@ -1440,68 +2158,210 @@ func truncateFilename(path string, maxLen int) string {
 
 // deleteProgressMsg reports deletion progress.
 type deleteProgressMsg struct {
-	current int
-	done    bool
-	err     error
+	current     int
+	done        bool
+	err         error
+	skipped     string // Path skipped by verification, if any
+	skippedSize int64
+	note        string // Trash quota action, if any
+	entry       string // Manifest entry recording the delete, with done
+
+	projection *dryrun.Report // What a dry run would have done, with done
 }
 
-// startDelete begins the deletion process.
-func (m Model) startDelete() (tea.Model, tea.Cmd) {
-	m.state = StateDeleting
-	m.deleteProgress = 0
-	m.deleteErrors = nil
-
-	// Get files from the appropriate source based on mode
-	var filePaths []string
+// deleteTargets returns the selected files from the appropriate source
+// based on mode, recording the size and modification time each file had
+// when it was selected.
+func (m Model) deleteTargets() []trash.Snapshot {
+	var targets []trash.Snapshot
 	if m.treeMode && m.treeView != nil {
-		m.deleteTotal = m.treeView.SelectedCount()
-		m.lastFreedSize = m.treeView.SelectedSize()
 		// Get paths from tree selection
 		selectedNodes := m.treeView.GetSelectedFiles()
 		for _, node := range selectedNodes {
-			filePaths = append(filePaths, node.Path)
+			snap := trash.Snapshot{Path: node.Path, Size: node.Size}
+			if node.ModTime > 0 {
+				snap.ModTime = time.Unix(node.ModTime, 0)
+			}
+			targets = append(targets, snap)
 		}
 	} else {
-		m.deleteTotal = m.resultModel.SelectedCount()
-		m.lastFreedSize = m.resultModel.SelectedSize()
 		// Get paths from result model selection
 		files := m.resultModel.SelectedFiles()
 		for _, f := range files {
-			filePaths = append(filePaths, f.Path)
+			targets = append(targets, trash.Snapshot{Path: f.Path, Size: f.Size, ModTime: f.ModTime})
 		}
 	}
+	return targets
+}
+
+// startDelete begins the deletion process, following m.deletePlan for
+// volumes over their trash quota.
+func (m Model) startDelete() (tea.Model, tea.Cmd) {
+	if m.options.ReadOnly {
+		logReadOnly()
+		m.state = StateResults
+		return m, nil
+	}
+	m.state = StateDeleting
+	m.deleteProgress = 0
+	m.deleteErrors = nil
+	m.deleteSkipped = nil
+	m.deleteNotes = nil
+	m.dryRunReport = nil
+
+	targets := m.deleteTargets()
+	if m.treeMode && m.treeView != nil {
+		m.deleteTotal = m.treeView.SelectedCount()
+		m.lastFreedSize = m.treeView.SelectedSize()
+	} else {
+		m.deleteTotal = m.resultModel.SelectedCount()
+		m.lastFreedSize = m.resultModel.SelectedLocalSize()
+	}
 
 	dryRun := m.options.DryRun
+	verify := m.options.VerifyBeforeDelete
+	recentDays := m.options.RecentDays
+	mf := m.options.Manifest
+	plan := m.deletePlan
+	backend := m.deleteBackend()
+	permanent := trash.Permanent{Roots: m.options.PermanentRoots}
+	deleteFiles := m.deleteFiles
+	m.deletePlan = deletePlan{}
 
 	logging.Get("tui").Info("delete started",
 		"count", m.deleteTotal,
 		"size", types.FormatSize(m.lastFreedSize),
-		"dryRun", dryRun)
+		"dryRun", dryRun,
+		"verify", verify,
+		"backend", backend.Name(),
+		"purge", len(plan.purge),
+		"permanent", len(plan.permanent)+len(plan.bypass))
 
 	// Create channel for progress updates
 	m.deleteProgressChan = make(chan deleteProgressMsg, 100)
 	progressChan := m.deleteProgressChan
+	crash := m.crash
 
 	// Start deletion in background
 	go func() {
-		for i, path := range filePaths {
-			var err error
-			if !dryRun {
-				err = trash.MoveToTrash(path)
+		defer crash.recoverExit()
+		var current int
+		var paths, deleted []string
+		methods := make(map[string]string)   // By path, when not the trash
+		locations := make(map[string]string) // By path, where a backend kept the file
+		for _, target := range targets {
+			if verify {
+				if err := trash.Verify(target); errors.Is(err, trash.ErrChanged) {
+					logging.Get("tui").Warn("skipping changed file", "path", target.Path, "error", err)
+					current++
+					// Skips must be reported, so this send blocks.
+					progressChan <- deleteProgressMsg{
+						current:     current,
+						skipped:     target.Path,
+						skippedSize: target.Size,
+					}
+					continue
+				}
 			}
+			paths = append(paths, target.Path)
+		}
 
+		report := func(err error) {
+			current++
+			msg := deleteProgressMsg{current: current, err: err}
+			if err != nil {
+				// Errors must be reported, so this send blocks.
+				progressChan <- msg
+				return
+			}
 			// Send progress update (non-blocking)
 			select {
-			case progressChan <- deleteProgressMsg{current: i + 1, done: false, err: err}:
+			case progressChan <- msg:
 			default:
 				// Channel full, skip this update
 			}
 		}
 
+		var projection *dryrun.Report
+		if dryRun {
+			for range paths {
+				report(nil)
+			}
+			projection = projectDelete(targets, paths, recentDays)
+		} else {
+			// Make room in over-quota trash first; notes must be reported,
+			// so these sends block.
+			for _, o := range plan.purge {
+				freed, err := trash.Purge(o.Volume, o.Excess())
+				note := fmt.Sprintf("Purged %s of old trash on %s", types.FormatSize(freed), o.Volume.Mount)
+				if err != nil {
+					logging.Get("tui").Warn("trash purge failed", "volume", o.Volume.Mount, "error", err)
+					note += " (" + err.Error() + ")"
+				}
+				progressChan <- deleteProgressMsg{current: current, note: note}
+			}
+
+			var trashPaths, bypassPaths []string
+			var removed int
+			for _, path := range paths {
+				if plan.bypass[path] {
+					bypassPaths = append(bypassPaths, path)
+					continue
+				}
+				if plan.permanent[path] {
+					err := trash.Remove(path)
+					if err == nil {
+						deleted = append(deleted, path)
+						methods[path] = manifest.MethodPermanent
+					}
+					report(err)
+					removed++
+					continue
+				}
+				trashPaths = append(trashPaths, path)
+			}
+			if removed > 0 {
+				progressChan <- deleteProgressMsg{
+					current: current,
+					note:    fmt.Sprintf("Deleted %d files permanently (trash quota)", removed),
+				}
+			}
+
+			if len(bypassPaths) > 0 {
+				var bypassed int
+				deleteFiles(bypassPaths, permanent, func(r trash.Result) {
+					if r.Err == nil {
+						deleted = append(deleted, r.Path)
+						methods[r.Path] = manifest.MethodPermanent
+						bypassed++
+					}
+					report(r.Err)
+				})
+				progressChan <- deleteProgressMsg{
+					current: current,
+					note:    fmt.Sprintf("Deleted %d files permanently (trash.permanent_roots)", bypassed),
+				}
+			}
+
+			// Delete the rest with the backend, through the daemon or in
+			// one call per directory for the trash; callbacks are serialized.
+			deleteFiles(trashPaths, backend, func(r trash.Result) {
+				if r.Err == nil {
+					deleted = append(deleted, r.Path)
+					methods[r.Path] = backend.Name()
+					locations[r.Path] = r.Location
+				}
+				report(r.Err)
+			})
+		}
+
 		// Send final completion message
 		progressChan <- deleteProgressMsg{
-			current: len(filePaths),
+			current: len(targets),
 			done:    true,
+			entry:   recordDelete(mf, targets, deleted, methods, locations),
+
+			projection: projection,
 		}
 		close(progressChan)
 	}()
@@ -1509,6 +2369,125 @@ func (m Model) startDelete() (tea.Model, tea.Cmd) {
 	return m, tea.Batch(m.deleteSpinner.Tick, m.listenForDeleteProgress())
 }
 
+// projectDelete reports what deleting the targets with the given paths,
+// those not skipped, would do.
+func projectDelete(targets []trash.Snapshot, paths []string, recentDays int) *dryrun.Report {
+	kept := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		kept[p] = true
+	}
+	var files []dryrun.File
+	for _, t := range targets {
+		if kept[t.Path] {
+			files = append(files, dryrun.File{Path: t.Path, Size: t.Size, ModTime: t.ModTime})
+		}
+	}
+	r := dryrun.Project(files, dryrun.Options{RecentDays: recentDays})
+	return &r
+}
+
+// recordDelete logs the deleted paths in the manifest and returns the
+// entry's ID, or "" if nothing was recorded. methods holds how paths not
+// trashed were deleted, and locations where a backend kept them.
+func recordDelete(mf *manifest.Manifest, targets []trash.Snapshot, deleted []string, methods, locations map[string]string) string {
+	if mf == nil || len(deleted) == 0 {
+		return ""
+	}
+	byPath := make(map[string]trash.Snapshot, len(targets))
+	for _, t := range targets {
+		byPath[t.Path] = t
+	}
+	now := time.Now().UTC()
+	records := make([]manifest.FileRecord, len(deleted))
+	for i, path := range deleted {
+		t := byPath[path]
+		records[i] = manifest.FileRecord{
+			Path:      path,
+			Size:      t.Size,
+			ModTime:   t.ModTime,
+			DeletedAt: now,
+			Method:    cmp.Or(methods[path], manifest.MethodTrash),
+			Location:  locations[path],
+		}
+	}
+
+	log := logging.Get("tui")
+	if err := mf.EnsureDir(); err != nil {
+		log.Warn("delete not recorded in history, undo unavailable", "error", err)
+		return ""
+	}
+	entry, err := mf.LogDelete(records)
+	if err != nil {
+		log.Warn("delete not recorded in history, undo unavailable", "error", err)
+		return ""
+	}
+	return entry.ID
+}
+
+// undoDoneMsg is sent when undoing a delete finishes.
+type undoDoneMsg struct {
+	report restore.Report
+	err    error
+}
+
+// undoDelete restores the files removed by the last delete from the trash.
+// Files whose path has been taken since are restored next to it.
+func (m *Model) undoDelete() tea.Cmd {
+	if m.options.ReadOnly {
+		logReadOnly()
+		return nil
+	}
+	if m.undoEntry == "" || m.options.Manifest == nil {
+		logging.Get("tui").Info("nothing to undo")
+		return nil
+	}
+	id, mf := m.undoEntry, m.options.Manifest
+	m.undoEntry = ""
+	logging.Get("tui").Info("undoing delete", "entry", id)
+	return func() tea.Msg {
+		entry, err := mf.Get(id)
+		if err != nil {
+			return undoDoneMsg{err: err}
+		}
+		report, err := restore.Entry(entry, restore.Options{Conflict: restore.ConflictRename, Manifest: mf})
+		return undoDoneMsg{report: report, err: err}
+	}
+}
+
+// handleUndoDone puts restored files back into the list and tree.
+func (m *Model) handleUndoDone(msg undoDoneMsg) {
+	log := logging.Get("tui")
+	if msg.err != nil {
+		log.Error("undo failed", "error", msg.err)
+		return
+	}
+
+	var restoredSize int64
+	for _, res := range msg.report.Restored() {
+		info, err := os.Stat(res.RestoredTo)
+		if err != nil {
+			continue
+		}
+		restoredSize += info.Size()
+		file := types.FileInfo{Path: res.RestoredTo, Size: info.Size(), ModTime: info.ModTime(), Mode: info.Mode()}
+		if m.filePassesFilter(file) {
+			m.resultModel.UpdateFile(file.Path, file.Size, file.ModTime)
+		}
+		if m.treeView != nil {
+			m.treeView.AddFile(file.Path, file.Size, file.ModTime.Unix())
+		}
+	}
+	m.resultModel.SetLastFreedSize(max(m.resultModel.LastFreedSize()-restoredSize, 0))
+
+	restored := msg.report.Count(restore.StatusRestored)
+	log.Info(fmt.Sprintf("Restored %d of %d files (%s)", restored, len(msg.report.Results), types.FormatSize(restoredSize)))
+	for _, res := range msg.report.Results {
+		if res.Status != restore.StatusRestored {
+			log.Warn("could not restore file", "path", res.Path, "error", res.Error)
+		}
+	}
+}
+
 // listenForDeleteProgress returns a command that waits for delete progress updates.
 func (m Model) listenForDeleteProgress() tea.Cmd {
 	progressChan := m.deleteProgressChan
@@ -1531,6 +2510,9 @@ func (m *Model) removeDeletedFiles() {
 	for _, errPath := range m.deleteErrors {
 		errorPaths[errPath] = true
 	}
+	for _, skipped := range m.deleteSkipped {
+		errorPaths[skipped] = true
+	}
 
 	// Calculate actual freed size (excluding errors)
 	var actualFreedSize int64
@@ -1554,7 +2536,7 @@ func (m *Model) removeDeletedFiles() {
 		files := m.resultModel.SelectedFiles()
 		for _, file := range files {
 			if !errorPaths[file.Path] && !m.options.DryRun {
-				actualFreedSize += file.Size
+				actualFreedSize += cloud.Local(file.Cloud, file.Size)
 				deletedCount++
 				m.resultModel.RemoveFile(file.Path)
 			}
@@ -1565,7 +2547,8 @@ func (m *Model) removeDeletedFiles() {
 	logging.Get("tui").Info("delete completed",
 		"deleted", deletedCount,
 		"freed", types.FormatSize(actualFreedSize),
-		"errors", len(m.deleteErrors))
+		"errors", len(m.deleteErrors),
+		"skipped", len(m.deleteSkipped))
 
 	// Update the freed size (add to any previous freed size)
 	currentFreed := m.resultModel.LastFreedSize()
@@ -1575,6 +2558,9 @@ func (m *Model) removeDeletedFiles() {
 // filePassesFilter checks if a file passes the configured filter.
 // If no filter is configured, it returns true (backward compatibility).
 func (m *Model) filePassesFilter(f types.FileInfo) bool {
+	if m.isHidden(f.Path) {
+		return false
+	}
 	if m.options.Filter == nil {
 		return true
 	}
@@ -1584,6 +2570,9 @@ func (m *Model) filePassesFilter(f types.FileInfo) bool {
 // applyFilterToFiles applies the configured filter to a slice of files.
 // If no filter is configured, it returns the original slice unchanged.
 func (m *Model) applyFilterToFiles(files []types.FileInfo) []types.FileInfo {
+	if len(m.hidden) > 0 {
+		files = slices.DeleteFunc(slices.Clone(files), func(f types.FileInfo) bool { return m.isHidden(f.Path) })
+	}
 	if m.options.Filter == nil {
 		return files
 	}
@@ -1631,45 +2620,8 @@ func fromFilterFileInfo(fi filter.FileInfo) types.FileInfo {
 	}
 }
 
-// loadTree loads the tree view data from the daemon.
-func (m Model) loadTree() tea.Cmd {
-	ctx := m.ctx
-	root := m.options.Root
-	minSize := m.options.MinSize
-	exclude := m.options.Exclude
-
-	// Resolve symlinks to match daemon's indexed paths
-	if resolved, err := filepath.EvalSymlinks(root); err == nil {
-		root = resolved
-	}
-
-	return func() tea.Msg {
-		// Check if daemon is running
-		pidPath := client.DefaultPIDPath()
-		if !client.IsDaemonRunning(pidPath) {
-			return TreeErrorMsg{Err: errors.New("daemon not running")}
-		}
-
-		// Connect to daemon
-		socketPath := client.DefaultSocketPath()
-		daemonClient, err := client.ConnectWithContext(ctx, socketPath)
-		if err != nil {
-			return TreeErrorMsg{Err: err}
-		}
-		defer daemonClient.Close()
-
-		// Get tree data
-		treeData, err := daemonClient.GetTree(ctx, root, minSize, exclude)
-		if err != nil {
-			return TreeErrorMsg{Err: err}
-		}
-
-		return TreeLoadedMsg{Root: treeData}
-	}
-}
-
-// convertClientTreeToNode converts a client.TreeNode to a tree.Node recursively.
-func convertClientTreeToNode(clientNode *client.TreeNode) *tree.Node {
+// convertClientTreeToNode converts a client tree node to a tree.Node recursively.
+func convertClientTreeToNode(clientNode *treeNode) *tree.Node {
 	if clientNode == nil {
 		return nil
 	}
@@ -1698,14 +2650,39 @@ func convertClientTreeToNode(clientNode *client.TreeNode) *tree.Node {
 }
 
 // Run starts the TUI application.
-func Run(opts Options) error {
+//
+// A panic restores the terminal and writes a crash report to the state
+// directory; the error returned, or printed before exiting when the panic
+// was in a command, says where it is.
+func Run(opts Options) (err error) {
+	crash := newCrashHandler(opts)
 	model := NewModel(opts)
+	model.crash = crash
 
-	p := tea.NewProgram(model,
+	p := tea.NewProgram(crashGuard{Model: model, crash: crash},
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithoutCatchPanics(),
 	)
+	crash.program = p
 
-	_, err := p.Run()
+	// Panics in Update and View unwind through p.Run
+	defer func() {
+		if r := recover(); r != nil {
+			err = crash.crash(r, debug.Stack())
+		}
+	}()
+	final, err := p.Run()
+	if g, ok := final.(crashGuard); ok {
+		if m, ok := g.Model.(Model); ok {
+			m.saveSession()
+		}
+	}
 	return err
 }
+
+// logReadOnly explains why a mutating key did nothing; the warning shows in
+// the status bar.
+func logReadOnly() {
+	logging.Get("tui").Warn("read-only mode: deleting is disabled")
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/archive"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// archiveRows is the number of entries the 'i' popup shows at once.
+const archiveRows = 15
+
+// archiveView is the 'i' popup listing the entries of the archive under the
+// cursor, largest first.
+type archiveView struct {
+	path    string
+	listing *archive.Listing // nil while reading
+	err     error
+	offset  int // First entry shown
+}
+
+// archiveListedMsg carries the entries of an archive read for the 'i'
+// popup.
+type archiveListedMsg struct {
+	path    string
+	listing *archive.Listing
+	err     error
+}
+
+// openArchive opens the 'i' popup for the archive under the cursor and
+// reads it in the background, as a large compressed tar takes a while. It
+// does nothing on other files.
+func (m *Model) openArchive() tea.Cmd {
+	paths := m.cursorPath()
+	if len(paths) == 0 || archive.Format(paths[0]) == "" {
+		return nil
+	}
+	if m.options.Remote.Address != "" {
+		logging.Get("tui").Warn("can't inspect archives on a remote daemon")
+		return nil
+	}
+	if file, ok := m.resultModel.current(); ok && !m.treeMode && file.Cloud != nil {
+		logging.Get("tui").Warn("the archive is online only; reading it would download it")
+		return nil
+	}
+	path := paths[0]
+	m.archive = &archiveView{path: path}
+	ctx := m.ctx
+	return func() tea.Msg {
+		listing, err := archive.List(ctx, path)
+		return archiveListedMsg{path: path, listing: listing, err: err}
+	}
+}
+
+// handleArchiveListed shows the entries read for the popup, unless it was
+// closed or moved on to another archive meanwhile.
+func (m *Model) handleArchiveListed(msg archiveListedMsg) {
+	if m.archive == nil || m.archive.path != msg.path {
+		return
+	}
+	m.archive.listing, m.archive.err = msg.listing, msg.err
+}
+
+// handleArchiveKey handles keys while the archive popup is open.
+func (m Model) handleArchiveKey(key string) (tea.Model, tea.Cmd) {
+	a := m.archive
+	last := 0
+	if a.listing != nil {
+		last = max(len(a.listing.Entries)-archiveRows, 0)
+	}
+	switch key {
+	case "esc", "i", "enter":
+		m.archive = nil
+	case "up", "k":
+		a.offset = max(a.offset-1, 0)
+	case "down", "j":
+		a.offset = min(a.offset+1, last)
+	case "pgup":
+		a.offset = max(a.offset-archiveRows, 0)
+	case "pgdown":
+		a.offset = min(a.offset+archiveRows, last)
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderArchive renders the archive popup over bg.
+func (m Model) renderArchive(bg string) string {
+	a := m.archive
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+	b.WriteString(title.Render(truncatePath(a.path, 64)))
+	b.WriteString("\n")
+
+	switch {
+	case a.err != nil:
+		b.WriteString("\n")
+		b.WriteString(errorTextStyle.Render(fmt.Sprintf("Can't read the archive: %v", a.err)))
+		b.WriteString("\n")
+	case a.listing == nil:
+		b.WriteString("\n")
+		b.WriteString(mutedTextStyle.Render("Reading entries..."))
+		b.WriteString("\n")
+	default:
+		l := a.listing
+		b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%s, %d files, %s uncompressed",
+			l.Format, len(l.Entries), types.FormatSize(l.Size))))
+		b.WriteString("\n\n")
+		if len(l.Entries) == 0 {
+			b.WriteString(mutedTextStyle.Render("The archive is empty."))
+			b.WriteString("\n")
+		}
+
+		zipped := l.Format == archive.FormatZip
+		end := min(a.offset+archiveRows, len(l.Entries))
+		for _, e := range l.Entries[a.offset:end] {
+			pct := float64(e.Size) / float64(max(l.Size, 1)) * 100
+			stored := ""
+			if zipped {
+				stored = fmt.Sprintf(" %9s", types.FormatSize(e.Compressed))
+			}
+			b.WriteString(fmt.Sprintf("%9s%s %5.1f%%  %s\n",
+				types.FormatSize(e.Size), stored, pct, truncatePath(e.Name, 44)))
+		}
+		if len(l.Entries) > archiveRows {
+			b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%d–%d of %d", a.offset+1, end, len(l.Entries))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	hint := "Largest first  [Esc] Close"
+	if a.listing != nil && a.listing.Format == archive.FormatZip {
+		hint = "Size, stored size, share  [Esc] Close"
+	}
+	b.WriteString(mutedTextStyle.Render(hint))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
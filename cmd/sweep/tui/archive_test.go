@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestArchiveKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, size := range map[string]int{"photos/huge.raw": 40000, "notes.txt": 100} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(bytes.Repeat([]byte("a"), size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	m := NewModel(Options{Root: dir})
+	m.state = StateResults
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: path, Size: 2 * types.GiB},
+		{Path: filepath.Join(dir, "movie.mkv"), Size: types.GiB},
+	})
+
+	next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = next.(Model)
+	if m.archive == nil || cmd == nil {
+		t.Fatal("expected i to open the archive popup and read the zip")
+	}
+	if view := m.View(); !strings.Contains(view, "Reading entries") {
+		t.Errorf("expected the popup to show it's reading, got:\n%s", view)
+	}
+
+	next, _ = m.Update(cmd())
+	m = next.(Model)
+	view := m.View()
+	huge, notes := strings.Index(view, "photos/huge.raw"), strings.Index(view, "notes.txt")
+	if huge < 0 || notes < 0 || huge > notes {
+		t.Errorf("expected the entries largest first, got:\n%s", view)
+	}
+
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.archive != nil {
+		t.Fatal("expected Esc to close the archive popup")
+	}
+
+	// Other files have nothing to inspect
+	m.resultModel.cursor = 1
+	next, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = next.(Model)
+	if m.archive != nil || cmd != nil {
+		t.Error("expected i to do nothing on a file that isn't an archive")
+	}
+}
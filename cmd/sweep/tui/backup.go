@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/backup"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// backupLookupDelay lets the cursor settle before a file is looked up, so
+// scrolling through the list doesn't start a backup tool per row.
+const backupLookupDelay = 300 * time.Millisecond
+
+// backupLookupTimeout bounds one lookup; remote repositories can be slow.
+const backupLookupTimeout = time.Minute
+
+// backupLookupMsg asks for the file at path to be looked up if the cursor
+// is still on it.
+type backupLookupMsg struct {
+	path string
+}
+
+// backupCheckedMsg reports that a lookup finished. The hints are read from
+// the checker's cache when the detail panel renders.
+type backupCheckedMsg struct {
+	path string
+}
+
+// scheduleBackupLookup looks up the file under the cursor once the cursor
+// has settled.
+func (m Model) scheduleBackupLookup() tea.Cmd {
+	file, ok := m.resultModel.current()
+	if m.options.Backups == nil || !ok {
+		return nil
+	}
+	if _, cached := m.options.Backups.Cached(file.Path, file.Size, file.ModTime); cached {
+		return nil
+	}
+	return tea.Tick(backupLookupDelay, func(time.Time) tea.Msg {
+		return backupLookupMsg{path: file.Path}
+	})
+}
+
+// startBackupLookup runs the lookup of the file under the cursor, unless it
+// moved away, the file was looked up already, or a lookup is in flight.
+func (m Model) startBackupLookup(path string) tea.Cmd {
+	file, ok := m.resultModel.current()
+	checker := m.options.Backups
+	if checker == nil || !ok || file.Path != path || m.backupPending[path] {
+		return nil
+	}
+	if _, cached := checker.Cached(file.Path, file.Size, file.ModTime); cached {
+		return nil
+	}
+	m.backupPending[path] = true
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, backupLookupTimeout)
+		defer cancel()
+		for _, h := range checker.Check(ctx, file.Path, file.Size, file.ModTime) {
+			if h.Err != nil {
+				logging.Get("tui").Warn("backup lookup failed", "backup", h.Repo, "path", file.Path, "error", h.Err)
+			}
+		}
+		return backupCheckedMsg{path: file.Path}
+	}
+}
+
+// backupLine describes the backups of a file for the detail panel.
+func backupLine(checker *backup.Checker, file types.FileInfo) string {
+	hints, ok := checker.Cached(file.Path, file.Size, file.ModTime)
+	switch {
+	case !ok:
+		return "  Backup: checking…"
+	case len(hints) == 0:
+		return "  Backup: not covered by any configured backup"
+	}
+	parts := make([]string, len(hints))
+	for i, h := range hints {
+		parts[i] = h.String()
+	}
+	return "  Backup: " + strings.Join(parts, "  |  ")
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/backup"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestBackupLookupFollowsCursor(t *testing.T) {
+	checker := backup.NewChecker([]backup.Repo{
+		{Name: "nas", Kind: backup.KindRestic, Repository: "/srv/restic", Binary: "/nonexistent/restic", Paths: []string{"/data"}},
+	})
+	m := NewModel(Options{Backups: checker})
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: "/data/a.iso", Size: 2 * types.GiB, ModTime: time.Now()},
+		{Path: "/other/b.iso", Size: 1 * types.GiB, ModTime: time.Now()},
+	})
+	a := m.resultModel.files[0]
+
+	if got := backupLine(checker, a); got != "  Backup: checking…" {
+		t.Errorf("before lookup: got %q", got)
+	}
+	if m.scheduleBackupLookup() == nil {
+		t.Fatal("expected a lookup to be scheduled for the file under the cursor")
+	}
+
+	// The cursor moved on before the delay passed
+	m.resultModel.HandleKey("down")
+	if cmd := m.startBackupLookup(a.Path); cmd != nil {
+		t.Error("expected no lookup once the cursor left the file")
+	}
+
+	m.resultModel.HandleKey("up")
+	cmd := m.startBackupLookup(a.Path)
+	if cmd == nil {
+		t.Fatal("expected a lookup for the file under the cursor")
+	}
+	if m.startBackupLookup(a.Path) != nil {
+		t.Error("expected no second lookup while one is in flight")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if m.backupPending[a.Path] {
+		t.Error("expected the lookup to be done")
+	}
+
+	if got := backupLine(checker, a); got != "  Backup: nas: lookup failed" {
+		t.Errorf("after failed lookup: got %q", got)
+	}
+	if m.scheduleBackupLookup() != nil {
+		t.Error("expected no lookup for a file already looked up")
+	}
+
+	b := m.resultModel.files[1]
+	checker.Check(context.Background(), b.Path, b.Size, b.ModTime)
+	if got := backupLine(checker, b); !strings.Contains(got, "not covered") {
+		t.Errorf("file outside the backed-up paths: got %q", got)
+	}
+}
+
+func TestDetailPanelShowsBackupLine(t *testing.T) {
+	m := NewResultModel([]types.FileInfo{{Path: "/data/a.iso", Size: types.GiB}})
+	rows := m.visibleRows()
+	if strings.Contains(m.renderDetailPanel(m.files[0], 80), "Backup:") {
+		t.Error("expected no backup line without configured backups")
+	}
+
+	m.backups = backup.NewChecker(nil)
+	if !strings.Contains(m.renderDetailPanel(m.files[0], 80), "Backup:") {
+		t.Error("expected a backup line with configured backups")
+	}
+	if m.visibleRows() != rows-1 {
+		t.Errorf("visibleRows() = %d, want %d", m.visibleRows(), rows-1)
+	}
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/clipboard"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// copyPaths copies paths to the clipboard, one per line, and reports the
+// outcome in the status bar.
+func copyPaths(paths []string) tea.Cmd {
+	if len(paths) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		log := logging.Get("tui")
+		method, err := clipboard.Copy(strings.Join(paths, "\n"))
+		switch {
+		case err != nil:
+			log.Warn("copy to clipboard failed", "error", err)
+		case len(paths) == 1:
+			log.Info("copied path to clipboard", "path", paths[0], "method", method)
+		default:
+			log.Info(fmt.Sprintf("copied %d paths to clipboard", len(paths)), "method", method)
+		}
+		return nil
+	}
+}
+
+// cursorPath returns the path of the file or directory under the cursor.
+func (m Model) cursorPath() []string {
+	if m.treeMode && m.treeView != nil {
+		if node := m.treeView.Selected(); node != nil {
+			return []string{node.Path}
+		}
+		return nil
+	}
+	if file, ok := m.resultModel.current(); ok {
+		return []string{file.Path}
+	}
+	return nil
+}
+
+// selectedPaths returns the paths of the selected files and directories.
+func (m Model) selectedPaths() []string {
+	var paths []string
+	if m.treeMode && m.treeView != nil {
+		for _, node := range m.treeView.GetSelectedFiles() {
+			paths = append(paths, node.Path)
+		}
+		return paths
+	}
+	for _, file := range m.resultModel.SelectedFiles() {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestCopyPathKeys(t *testing.T) {
+	m := NewModel(Options{})
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: "/data/a.iso", Size: 2 * types.GiB},
+		{Path: "/data/b.iso", Size: 1 * types.GiB},
+		{Path: "/data/c.iso", Size: 1 * types.MiB},
+	})
+
+	if got := m.cursorPath(); !slices.Equal(got, []string{"/data/a.iso"}) {
+		t.Errorf("cursorPath() = %v", got)
+	}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Error("y should copy the path under the cursor")
+	}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Y")}); cmd != nil {
+		t.Error("Y should do nothing without a selection")
+	}
+
+	m.resultModel.Toggle(0)
+	m.resultModel.Toggle(2)
+	if got := m.selectedPaths(); !slices.Equal(got, []string{"/data/a.iso", "/data/c.iso"}) {
+		t.Errorf("selectedPaths() = %v", got)
+	}
+
+	// The tree view copies its own cursor and selection
+	m.treeView = NewTreeView(createTestTree())
+	m.treeMode = true
+	m.treeView.MoveDown()
+	m.treeView.ToggleSelect()
+	if got := m.cursorPath(); !slices.Equal(got, []string{"/test/dir1"}) {
+		t.Errorf("tree cursorPath() = %v", got)
+	}
+	if got := m.selectedPaths(); !slices.Equal(got, []string{"/test/dir1"}) {
+		t.Errorf("tree selectedPaths() = %v", got)
+	}
+}
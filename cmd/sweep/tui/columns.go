@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// File list columns.
+const (
+	ColumnSize  = "size"  // File size in the configured units
+	ColumnMtime = "mtime" // Modification time
+	ColumnOwner = "owner" // Owning user
+	ColumnType  = "type"  // File type from the content or extension
+	ColumnAge   = "age"   // Time since modification, e.g. "3 mo"
+	ColumnPath  = "path"  // Full path
+	ColumnName  = "name"  // Base name only
+)
+
+// Size units for the size column.
+const (
+	UnitsIEC   = "iec"   // Powers of 1024: KiB, MiB, GiB (default)
+	UnitsSI    = "si"    // Powers of 1000: kB, MB, GB
+	UnitsBytes = "bytes" // Exact byte counts
+)
+
+// toggleColumns maps number keys to the columns they show or hide.
+var toggleColumns = []string{ColumnSize, ColumnMtime, ColumnOwner, ColumnType, ColumnAge}
+
+// defaultColumnWidths are used when ui.widths does not set a width.
+var defaultColumnWidths = map[string]int{
+	ColumnSize:  8,
+	ColumnMtime: 16,
+	ColumnOwner: 10,
+	ColumnType:  10,
+	ColumnAge:   7,
+}
+
+// columnTitles are the header labels for each column.
+var columnTitles = map[string]string{
+	ColumnSize:  "Size",
+	ColumnMtime: "Modified",
+	ColumnOwner: "Owner",
+	ColumnType:  "Type",
+	ColumnAge:   "Age",
+	ColumnPath:  "Path",
+	ColumnName:  "File",
+}
+
+// fileTypes caches the type column, which reads each file's header once.
+var fileTypes filetype.Cache
+
+// ColumnLayout configures the columns of the flat file list.
+// The path or name column always comes last and takes the remaining width.
+type ColumnLayout struct {
+	Columns []string       // Column order
+	Widths  map[string]int // Per-column width overrides
+	Units   string         // Size units
+	Locale  reltime.Locale // Language of the age column
+}
+
+// DefaultColumnLayout returns the classic size and file name layout.
+func DefaultColumnLayout() ColumnLayout {
+	return ColumnLayout{
+		Columns: []string{ColumnSize, ColumnName},
+		Units:   UnitsIEC,
+		Locale:  reltime.English,
+	}
+}
+
+// NewColumnLayout validates a layout from config.
+// Empty columns or units fall back to the defaults.
+func NewColumnLayout(columns []string, widths map[string]int, units string) (ColumnLayout, error) {
+	layout := DefaultColumnLayout()
+
+	if len(columns) > 0 {
+		layout.Columns = nil
+		hasFile := false
+		for _, c := range columns {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if _, ok := columnTitles[c]; !ok {
+				return ColumnLayout{}, fmt.Errorf("unknown column %q (available: size, mtime, age, owner, type, path, name)", c)
+			}
+			if slices.Contains(layout.Columns, c) {
+				continue
+			}
+			if c == ColumnPath || c == ColumnName {
+				if hasFile {
+					return ColumnLayout{}, fmt.Errorf("columns %q and %q cannot both be shown", ColumnPath, ColumnName)
+				}
+				hasFile = true
+			}
+			layout.Columns = append(layout.Columns, c)
+		}
+		if !hasFile {
+			layout.Columns = append(layout.Columns, ColumnName)
+		}
+	}
+
+	for c, w := range widths {
+		c = strings.ToLower(c)
+		if _, ok := defaultColumnWidths[c]; !ok {
+			return ColumnLayout{}, fmt.Errorf("width cannot be set for column %q", c)
+		}
+		if w < 1 {
+			return ColumnLayout{}, fmt.Errorf("width for column %q must be positive, got %d", c, w)
+		}
+		if layout.Widths == nil {
+			layout.Widths = make(map[string]int)
+		}
+		layout.Widths[c] = w
+	}
+
+	if units != "" {
+		units = strings.ToLower(units)
+		switch units {
+		case UnitsIEC, UnitsSI, UnitsBytes:
+			layout.Units = units
+		default:
+			return ColumnLayout{}, fmt.Errorf("unknown size units %q (available: iec, si, bytes)", units)
+		}
+	}
+
+	return layout, nil
+}
+
+// Has reports whether a column is shown.
+func (l ColumnLayout) Has(column string) bool {
+	return slices.Contains(l.Columns, column)
+}
+
+// Toggle shows or hides a fixed-width column.
+func (l *ColumnLayout) Toggle(column string) {
+	if i := slices.Index(l.Columns, column); i >= 0 {
+		l.Columns = slices.Delete(slices.Clone(l.Columns), i, i+1)
+		return
+	}
+	// Insert before the path or name column, which stays last.
+	l.Columns = slices.Insert(slices.Clone(l.Columns), len(l.fixed()), column)
+}
+
+// TogglePath switches between showing the full path and the base name.
+func (l *ColumnLayout) TogglePath() {
+	columns := slices.Clone(l.Columns)
+	for i, c := range columns {
+		switch c {
+		case ColumnPath:
+			columns[i] = ColumnName
+		case ColumnName:
+			columns[i] = ColumnPath
+		}
+	}
+	l.Columns = columns
+}
+
+// CycleUnits switches to the next size unit.
+func (l *ColumnLayout) CycleUnits() {
+	switch l.Units {
+	case UnitsSI:
+		l.Units = UnitsBytes
+	case UnitsBytes:
+		l.Units = UnitsIEC
+	default:
+		l.Units = UnitsSI
+	}
+}
+
+// fixed returns the fixed-width columns in display order.
+func (l ColumnLayout) fixed() []string {
+	var cols []string
+	for _, c := range l.Columns {
+		if c != ColumnPath && c != ColumnName {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// fileColumn returns the flexible last column.
+func (l ColumnLayout) fileColumn() string {
+	if l.Has(ColumnPath) {
+		return ColumnPath
+	}
+	return ColumnName
+}
+
+// width returns the width of a fixed column.
+func (l ColumnLayout) width(column string) int {
+	if w, ok := l.Widths[column]; ok {
+		return w
+	}
+	if column == ColumnSize && l.Units == UnitsBytes {
+		return 14
+	}
+	return defaultColumnWidths[column]
+}
+
+// fixedWidth returns the width used by the checkbox and fixed columns,
+// including the gap after each column.
+func (l ColumnLayout) fixedWidth() int {
+	w := 3 // checkbox
+	for _, c := range l.fixed() {
+		w += l.width(c) + 2
+	}
+	return w
+}
+
+// FormatSize formats a size in the layout's units.
+func (l ColumnLayout) FormatSize(bytes int64) string {
+	switch l.Units {
+	case UnitsSI:
+		return humanize.Bytes(uint64(bytes))
+	case UnitsBytes:
+		return strconv.FormatInt(bytes, 10)
+	default:
+		return types.FormatSize(bytes)
+	}
+}
+
+// header renders the column titles after the checkbox column.
+func (l ColumnLayout) header() string {
+	var b strings.Builder
+	for _, c := range l.fixed() {
+		title := columnTitles[c]
+		if c == ColumnSize || c == ColumnAge {
+			b.WriteString(padLeft(title, l.width(c)))
+		} else {
+			b.WriteString(padRight(title, l.width(c)))
+		}
+		b.WriteString("  ")
+	}
+	b.WriteString(columnTitles[l.fileColumn()])
+	return b.String()
+}
+
+// cells renders the fixed columns for a file, each padded to its width.
+func (l ColumnLayout) cells(file types.FileInfo) []string {
+	now := time.Now()
+	cols := l.fixed()
+	cells := make([]string, len(cols))
+	for i, c := range cols {
+		w := l.width(c)
+		var v string
+		switch c {
+		case ColumnSize:
+			cells[i] = padLeft(truncateCell(l.FormatSize(file.Size), w), w)
+			continue
+		case ColumnMtime:
+			if !file.ModTime.IsZero() {
+				v = file.ModTime.Format("2006-01-02 15:04")
+			}
+		case ColumnAge:
+			if !file.ModTime.IsZero() {
+				cells[i] = padLeft(truncateCell(l.Locale.Format(file.ModTime, now), w), w)
+				continue
+			}
+		case ColumnOwner:
+			v = file.Owner
+			if v == "unknown" {
+				v = ""
+			}
+		case ColumnType:
+			v = fileTypes.Detect(file.Path, file.Size, file.ModTime)
+		}
+		if v == "" {
+			v = "-"
+		}
+		cells[i] = padRight(truncateCell(v, w), w)
+	}
+	return cells
+}
+
+// fileCell renders the path or name column within width.
+// Long paths keep their end; long names keep their start.
+func (l ColumnLayout) fileCell(path string, width int) string {
+	if l.fileColumn() == ColumnPath {
+		if len(path) > width {
+			return "..." + path[len(path)-(width-3):]
+		}
+		return path
+	}
+	name := filepath.Base(path)
+	if len(name) > width {
+		return name[:width-3] + "..."
+	}
+	return name
+}
+
+// truncateCell shortens s to width, marking the cut with "~".
+func truncateCell(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return s[:width]
+	}
+	return s[:width-1] + "~"
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestNewColumnLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		widths  map[string]int
+		units   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "defaults", want: []string{ColumnSize, ColumnName}},
+		{name: "custom order", columns: []string{"mtime", "Size", "path"}, want: []string{ColumnMtime, ColumnSize, ColumnPath}},
+		{name: "adds name column", columns: []string{"size", "owner"}, want: []string{ColumnSize, ColumnOwner, ColumnName}},
+		{name: "drops duplicates", columns: []string{"size", "size", "name"}, want: []string{ColumnSize, ColumnName}},
+		{name: "unknown column", columns: []string{"inode"}, wantErr: true},
+		{name: "path and name", columns: []string{"path", "name"}, wantErr: true},
+		{name: "width for path", widths: map[string]int{"path": 40}, wantErr: true},
+		{name: "zero width", widths: map[string]int{"owner": 0}, wantErr: true},
+		{name: "unknown units", units: "furlongs", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewColumnLayout(tt.columns, tt.widths, tt.units)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(layout.Columns, tt.want) {
+				t.Errorf("Columns = %v, want %v", layout.Columns, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnLayoutToggle(t *testing.T) {
+	layout := DefaultColumnLayout()
+
+	layout.Toggle(ColumnOwner)
+	if want := []string{ColumnSize, ColumnOwner, ColumnName}; !slices.Equal(layout.Columns, want) {
+		t.Errorf("after showing owner: %v, want %v", layout.Columns, want)
+	}
+
+	layout.Toggle(ColumnSize)
+	if want := []string{ColumnOwner, ColumnName}; !slices.Equal(layout.Columns, want) {
+		t.Errorf("after hiding size: %v, want %v", layout.Columns, want)
+	}
+
+	layout.TogglePath()
+	if want := []string{ColumnOwner, ColumnPath}; !slices.Equal(layout.Columns, want) {
+		t.Errorf("after path toggle: %v, want %v", layout.Columns, want)
+	}
+
+	// Toggling must not alias the default layout
+	if d := DefaultColumnLayout(); !slices.Equal(d.Columns, []string{ColumnSize, ColumnName}) {
+		t.Errorf("default layout changed: %v", d.Columns)
+	}
+}
+
+func TestColumnLayoutUnits(t *testing.T) {
+	layout := DefaultColumnLayout()
+	size := int64(1_500_000)
+
+	if got := layout.FormatSize(size); got != "1.4 MiB" {
+		t.Errorf("iec = %q", got)
+	}
+	layout.CycleUnits()
+	if got := layout.FormatSize(size); got != "1.5 MB" {
+		t.Errorf("si = %q", got)
+	}
+	layout.CycleUnits()
+	if got := layout.FormatSize(size); got != "1500000" {
+		t.Errorf("bytes = %q", got)
+	}
+	layout.CycleUnits()
+	if layout.Units != UnitsIEC {
+		t.Errorf("expected units to cycle back to iec, got %q", layout.Units)
+	}
+}
+
+func TestColumnLayoutCells(t *testing.T) {
+	layout, err := NewColumnLayout([]string{"size", "mtime", "owner", "type", "path"}, map[string]int{"owner": 4}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := types.FileInfo{
+		Path:    "/data/videos/movie.mp4",
+		Size:    2 * types.GiB,
+		ModTime: time.Date(2024, 3, 1, 9, 30, 0, 0, time.Local),
+		Owner:   "alice",
+	}
+
+	cells := layout.cells(file)
+	want := []string{" 2.0 GiB", "2024-03-01 09:30", "ali~", "Video     "}
+	if !slices.Equal(cells, want) {
+		t.Errorf("cells = %q, want %q", cells, want)
+	}
+
+	header := layout.header()
+	for _, title := range []string{"Size", "Modified", "Owner", "Type", "Path"} {
+		if !strings.Contains(header, title) {
+			t.Errorf("header %q missing %q", header, title)
+		}
+	}
+
+	if got := layout.fileCell(file.Path, 12); got != "...movie.mp4" {
+		t.Errorf("path cell = %q", got)
+	}
+	layout.TogglePath()
+	if got := layout.fileCell(file.Path, 12); got != "movie.mp4" {
+		t.Errorf("name cell = %q", got)
+	}
+}
+
+func TestColumnLayoutAge(t *testing.T) {
+	layout, err := NewColumnLayout([]string{"age", "name"}, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := types.FileInfo{Path: "/data/old.log", ModTime: time.Now().Add(-95 * 24 * time.Hour)}
+
+	if got := layout.cells(file); !slices.Equal(got, []string{"   3 mo"}) {
+		t.Errorf("cells = %q", got)
+	}
+	layout.Locale = reltime.Lookup("de_DE.UTF-8")
+	if got := layout.cells(file); !slices.Equal(got, []string{" 3 Mon."}) {
+		t.Errorf("cells = %q", got)
+	}
+	if got := layout.cells(types.FileInfo{Path: "/data/new.log"}); !slices.Equal(got, []string{"-      "}) {
+		t.Errorf("cells without mtime = %q", got)
+	}
+}
+
+func TestResultModelRendersConfiguredColumns(t *testing.T) {
+	m := NewResultModel([]types.FileInfo{
+		{Path: "/test/file1.txt", Size: 100 * types.MiB, Owner: "bob"},
+	})
+	m.columns.Toggle(ColumnOwner)
+
+	out := m.renderFileList(100)
+	if !strings.Contains(out, "Owner") || !strings.Contains(out, "bob") {
+		t.Errorf("expected owner column in output:\n%s", out)
+	}
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// confirmLevel returns how deleting the selection is confirmed under the
+// confirm policy, with the directory class asking for it. Without a policy
+// every delete gets the standard dialog.
+func (m Model) confirmLevel() (rules.Confirm, string) {
+	if m.options.Confirm == nil {
+		return rules.ConfirmStandard, ""
+	}
+	targets := m.deleteTargets()
+	paths := make([]string, len(targets))
+	for i, t := range targets {
+		paths[i] = t.Path
+	}
+	return m.options.Confirm.Strictest(paths)
+}
+
+// openConfirm asks to confirm deleting the selection, or deletes it right
+// away when the policy needs no confirmation for any of it. Deleting
+// permanently is always confirmed.
+func (m Model) openConfirm() (tea.Model, tea.Cmd) {
+	m.state = StateConfirm
+	m.confirmFocused = 0 // Default to cancel
+	m.confirmInput = ""
+	if level, class := m.confirmLevel(); level == rules.ConfirmNone && len(m.permanentTargets()) == 0 {
+		logging.Get("tui").Info("deleting without confirmation", "class", class)
+		return m.confirmDelete()
+	}
+	return m, nil
+}
+
+// renderCloudWarning warns that the selection holds online-only cloud
+// files, which free next to nothing here and may be deleted from the
+// cloud too, or returns "" if it holds none.
+func (m Model) renderCloudWarning() string {
+	online := make(map[string]int64)
+	for _, f := range m.resultModel.Files() {
+		if f.Cloud != nil {
+			online[f.Path] = f.Size
+		}
+	}
+	var files int
+	var size int64
+	for _, path := range m.selectedPaths() {
+		if s, ok := online[path]; ok {
+			files++
+			size += s
+		}
+	}
+	if files == 0 {
+		return ""
+	}
+
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFC107"))
+	var b strings.Builder
+	b.WriteString(warn.Render(fmt.Sprintf("%d files (%s) are online only and free little space here.", files, types.FormatSize(size))))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("Deleting them may delete them from the cloud as well."))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// typedConfirm reports whether deleting the selection needs
+// permanentConfirmWord typed, rather than a yes or no: when some of it is
+// deleted permanently, or the policy is strict for some of it.
+func (m Model) typedConfirm() bool {
+	if len(m.permanentTargets()) > 0 {
+		return true
+	}
+	level, _ := m.confirmLevel()
+	return level == rules.ConfirmStrict
+}
+
+// renderStrictConfirm renders the part of the confirm dialog that asks for
+// permanentConfirmWord because the selection is in a strict class.
+func (m Model) renderStrictConfirm() string {
+	_, class := m.confirmLevel()
+	danger := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true)
+
+	var b strings.Builder
+	switch class {
+	case "":
+		b.WriteString(mutedTextStyle.Render("confirm.default is strict."))
+	case rules.ClassHome:
+		b.WriteString(mutedTextStyle.Render("Some files are directly in your home directory."))
+	default:
+		b.WriteString(mutedTextStyle.Render(fmt.Sprintf("Some files are in %s directories.", class)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.renderConfirmWord(danger))
+	return b.String()
+}
+
+// renderConfirmWord renders the prompt for permanentConfirmWord and what
+// has been typed so far.
+func (m Model) renderConfirmWord(danger lipgloss.Style) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Type %s to confirm: ", danger.Render(permanentConfirmWord)))
+	b.WriteString(keyStyle.Render("> "))
+	b.WriteString(m.confirmInput)
+	b.WriteString(keyStyle.Render("█"))
+	b.WriteString("\n\n")
+	b.WriteString(mutedTextStyle.Render("[Enter] Delete  [Esc] Cancel"))
+	return b.String()
+}
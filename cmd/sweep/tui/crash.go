@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// crashHandler turns a panic in the TUI into a crash report. Bubble Tea's
+// own panic handling prints the stack while a goroutine panic still has the
+// terminal in raw mode on the alternate screen, which garbles it, so the
+// program runs without it and the handler restores the terminal first,
+// writes the stack, the recent log, and the options to a file in the state
+// directory, and says where it is.
+type crashHandler struct {
+	program *tea.Program
+	opts    Options
+	dir     string    // Where reports are written
+	out     io.Writer // Where the report's path is printed
+	exit    func(int) // Ends the process after a panic in a goroutine
+
+	once sync.Once
+	err  error // Describes the first crash
+}
+
+// newCrashHandler returns a handler writing reports to the state directory.
+func newCrashHandler(opts Options) *crashHandler {
+	return &crashHandler{
+		opts: opts,
+		dir:  config.StateDir(),
+		out:  os.Stderr,
+		exit: os.Exit,
+	}
+}
+
+// recoverExit reports a panic in a command or another goroutine, which
+// can't return an error to Run, and exits. It must be deferred directly; a
+// nil handler leaves the panic alone.
+func (c *crashHandler) recoverExit() {
+	if c == nil {
+		return
+	}
+	if r := recover(); r != nil {
+		err := c.crash(r, debug.Stack())
+		fmt.Fprintf(c.out, "Error: %v\n", err)
+		c.exit(2)
+	}
+}
+
+// crash restores the terminal and writes the report, once: a panic can
+// bring down other goroutines with it, and the first one is the cause.
+func (c *crashHandler) crash(r any, stack []byte) error {
+	c.once.Do(func() {
+		if c.program != nil {
+			_ = c.program.ReleaseTerminal()
+		}
+		log := logging.Get("tui")
+		log.Error("TUI crashed", "panic", fmt.Sprint(r))
+
+		path, err := c.writeReport(r, stack, time.Now())
+		if err != nil {
+			log.Error("failed to write crash report", "error", err)
+			// The stack is all there is to go on, so print it instead
+			fmt.Fprintf(c.out, "sweep crashed: %v\n\n%s\n", r, stack)
+			c.err = fmt.Errorf("sweep crashed: %v", r)
+			return
+		}
+		c.err = fmt.Errorf("sweep crashed: %v\nA crash report was written to %s; please attach it when reporting the problem", r, path)
+	})
+	return c.err
+}
+
+// writeReport writes a crash report to a new file in c.dir and returns its
+// path.
+func (c *crashHandler) writeReport(r any, stack []byte, now time.Time) (string, error) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(c.dir, "crash-"+now.Format("20060102-150405")+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "sweep crashed at %s\n", now.Format(time.RFC3339))
+	if c.opts.Version != "" {
+		fmt.Fprintf(&b, "Version: %s\n", c.opts.Version)
+	}
+	fmt.Fprintf(&b, "Go: %s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n%s\n", r, stack)
+
+	b.WriteString("Recent log:\n")
+	var entries []logging.LogEntry
+	if buf := logging.GetLogBuffer(); buf != nil {
+		entries = buf.Entries()
+	}
+	if len(entries) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, e := range entries {
+		fmt.Fprintf(&b, "  %s %-5s [%s] %s\n", e.Time.Format("15:04:05.000"), e.Level, e.Component, e.Message)
+	}
+
+	b.WriteString("\nOptions:\n")
+	for _, line := range sanitizedOptions(c.opts) {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// sanitizedOptions describes opts for a crash report, which users attach
+// to bug reports: the home directory is shortened to ~, and certificate and
+// key paths are left out.
+func sanitizedOptions(opts Options) []string {
+	home, _ := os.UserHomeDir()
+	clean := func(path string) string {
+		if home != "" && (path == home || strings.HasPrefix(path, home+string(filepath.Separator))) {
+			return "~" + path[len(home):]
+		}
+		return path
+	}
+	paths := func(list []string) string {
+		cleaned := make([]string, len(list))
+		for i, p := range list {
+			cleaned[i] = clean(p)
+		}
+		return "[" + strings.Join(cleaned, ", ") + "]"
+	}
+
+	return []string{
+		"root: " + clean(opts.Root),
+		fmt.Sprintf("min_size: %d", opts.MinSize),
+		"exclude: " + paths(opts.Exclude),
+		fmt.Sprintf("workers: dir=%d file=%d", opts.DirWorkers, opts.FileWorkers),
+		fmt.Sprintf("dry_run: %t", opts.DryRun),
+		fmt.Sprintf("no_daemon: %t", opts.NoDaemon),
+		fmt.Sprintf("read_only: %t", opts.ReadOnly),
+		fmt.Sprintf("verify_before_delete: %t", opts.VerifyBeforeDelete),
+		fmt.Sprintf("remote: %t", opts.Remote.Address != ""),
+		fmt.Sprintf("filter: %t", opts.Filter != nil),
+		fmt.Sprintf("owner: %t", opts.Owner != nil),
+		fmt.Sprintf("tags: %t", opts.Tags != nil),
+		fmt.Sprintf("trash_quota: %t", opts.TrashQuota != nil),
+		fmt.Sprintf("manifest: %t", opts.Manifest != nil),
+		fmt.Sprintf("backups: %t", opts.Backups != nil),
+		"permanent_roots: " + paths(opts.PermanentRoots),
+	}
+}
+
+// guardCmd runs cmd, and the commands of a batch it returns, under
+// recoverExit: Bubble Tea runs commands in goroutines of their own.
+func (c *crashHandler) guardCmd(cmd tea.Cmd) tea.Cmd {
+	if c == nil || cmd == nil {
+		return cmd
+	}
+	return func() tea.Msg {
+		defer c.recoverExit()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i, cmd := range batch {
+				batch[i] = c.guardCmd(cmd)
+			}
+		}
+		return msg
+	}
+}
+
+// crashGuard wraps the model so the commands it returns are guarded.
+type crashGuard struct {
+	tea.Model
+	crash *crashHandler
+}
+
+// Init guards the model's initial command.
+func (g crashGuard) Init() tea.Cmd {
+	return g.crash.guardCmd(g.Model.Init())
+}
+
+// Update guards the commands the model returns.
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := g.Model.Update(msg)
+	return crashGuard{Model: m, crash: g.crash}, g.crash.guardCmd(cmd)
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+func TestCrashHandlerWritesReport(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	var out bytes.Buffer
+	c := &crashHandler{
+		opts: Options{
+			Root:    filepath.Join(home, "Movies"),
+			DryRun:  true,
+			Version: "1.2.3 (abc123)",
+			Remote:  config.RemoteConfig{Address: "nas:7557", Key: "/secret/client.key"},
+		},
+		dir: t.TempDir(),
+		out: &out,
+	}
+
+	err = c.crash("index out of range", []byte("goroutine 1 [running]:\nmain.main()"))
+	if err == nil {
+		t.Fatal("crash() returned nil, want an error naming the report")
+	}
+	if again := c.crash("second panic", nil); again != err {
+		t.Errorf("a second crash returned %v, want the first crash's error", again)
+	}
+
+	reports, _ := filepath.Glob(filepath.Join(c.dir, "crash-*.txt"))
+	if len(reports) != 1 {
+		t.Fatalf("found %d crash reports, want 1", len(reports))
+	}
+	if !strings.Contains(err.Error(), reports[0]) {
+		t.Errorf("error %q doesn't name the report %s", err, reports[0])
+	}
+	data, err := os.ReadFile(reports[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"Version: 1.2.3 (abc123)",
+		"panic: index out of range",
+		"main.main()",
+		"Recent log:",
+		"root: ~" + string(filepath.Separator) + "Movies",
+		"dry_run: true",
+		"remote: true",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+	for _, secret := range []string{home + string(filepath.Separator), "nas:7557", "client.key", "second panic"} {
+		if strings.Contains(report, secret) {
+			t.Errorf("report contains %q:\n%s", secret, report)
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("printed %q; the path is returned instead", out.String())
+	}
+}
+
+func TestCrashHandlerGuardsCommands(t *testing.T) {
+	var out bytes.Buffer
+	exitCode := -1
+	c := &crashHandler{dir: t.TempDir(), out: &out, exit: func(code int) { exitCode = code }}
+
+	ok := func() tea.Msg { return "ok" }
+	boom := func() tea.Msg { panic("boom") }
+	if msg := c.guardCmd(ok)(); msg != "ok" {
+		t.Errorf("guarded command returned %v, want ok", msg)
+	}
+
+	batch := c.guardCmd(tea.Batch(ok, boom))().(tea.BatchMsg)
+	batch[1]()
+	if exitCode != 2 {
+		t.Errorf("exit code = %d, want 2 after a panic in a batched command", exitCode)
+	}
+	if !strings.Contains(out.String(), "sweep crashed: boom") || !strings.Contains(out.String(), "crash report") {
+		t.Errorf("printed %q, want the panic and the report's path", out.String())
+	}
+
+	var nilHandler *crashHandler
+	if nilHandler.guardCmd(nil) != nil {
+		t.Error("guarding a nil command should return nil")
+	}
+}
@@ -0,0 +1,464 @@
+//go:build !lite
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Daemon event and tree types. Lite builds provide local equivalents so the
+// TUI can be compiled without the gRPC client.
+type (
+	fileEvent = client.FileEvent
+	treeEvent = client.TreeEvent
+	treeNode  = client.TreeNode
+)
+
+// daemonTarget returns the daemon the TUI reads from.
+func (m Model) daemonTarget() client.Target {
+	return client.Target{Remote: m.options.Remote, Timeout: m.options.Timeout}
+}
+
+// daemonRoot returns the root as the daemon indexed it. Symlinks in a local
+// root are resolved to match the daemon's paths (e.g., /Volumes/Development
+// -> /Users/user/Development); a remote root is used as given.
+func (m Model) daemonRoot() string {
+	return m.daemonRoots()[0]
+}
+
+// daemonRoots returns every root scanned as the daemon indexed it, as
+// daemonRoot does.
+func (m Model) daemonRoots() []string {
+	roots := slices.Clone(m.roots())
+	if m.options.Remote.Address != "" {
+		return roots
+	}
+	for i, root := range roots {
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			roots[i] = resolved
+		}
+	}
+	return roots
+}
+
+// tryDaemonInstantLoad attempts to get the first page of files under each
+// root from the daemon instantly. Returns a DaemonFilesMsg if every root is
+// indexed, nil otherwise.
+func (m Model) tryDaemonInstantLoad() *DaemonFilesMsg {
+	// Check if daemon is running
+	target := m.daemonTarget()
+	if !target.Running() {
+		return nil
+	}
+
+	// Try to connect to daemon
+	daemonClient, err := target.Connect(m.ctx)
+	if err != nil {
+		logging.Get("tui").Debug("daemon unavailable", "error", err)
+		return nil
+	}
+	defer daemonClient.Close()
+
+	roots := m.daemonRoots()
+
+	// Check if index is ready for these paths
+	for _, root := range roots {
+		ready, err := daemonClient.IsIndexReady(m.ctx, root)
+		if err != nil || !ready {
+			return nil
+		}
+	}
+
+	msg := &DaemonFilesMsg{NextPages: make([]string, len(roots))}
+	for i, root := range roots {
+		// Query the daemon - get the largest files at once
+		result, err := daemonClient.QueryLargeFilesPage(m.ctx, root, m.options.MinSize, m.options.Exclude, daemonPageSize, "")
+		if err != nil {
+			return nil
+		}
+		msg.Files = append(msg.Files, m.ownedFiles(result.Files)...)
+		msg.Truncated = msg.Truncated || result.Truncated
+		msg.NextPages[i] = result.NextPage
+
+		// Get index status for statistics
+		if status, err := daemonClient.GetIndexStatus(m.ctx, root); err == nil && status != nil {
+			msg.DirsScanned += status.DirsIndexed
+			msg.FilesScanned += status.FilesIndexed
+		}
+	}
+	return msg
+}
+
+// fetchDaemonPage fetches the page of files under the root at index root
+// after those already loaded.
+func (m Model) fetchDaemonPage(rootIndex int, pageToken string) tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+	root := m.daemonRoots()[rootIndex]
+	minSize := m.options.MinSize
+	exclude := m.options.Exclude
+
+	return func() tea.Msg {
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return DaemonPageMsg{Root: rootIndex, Err: err}
+		}
+		defer daemonClient.Close()
+
+		result, err := daemonClient.QueryLargeFilesPage(ctx, root, minSize, exclude, daemonPageSize, pageToken)
+		if err != nil {
+			return DaemonPageMsg{Root: rootIndex, Err: err}
+		}
+		return DaemonPageMsg{
+			Root:      rootIndex,
+			Files:     m.ownedFiles(result.Files),
+			Truncated: result.Truncated,
+			NextPage:  result.NextPage,
+		}
+	}
+}
+
+// resyncFiles reads the first page of files under each root again, for a
+// daemonResyncMsg once the live stream is restored. The pages are nil if
+// the daemon can't be read, leaving the list as it is.
+func (m Model) resyncFiles() tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+	roots := m.daemonRoots()
+	minSize := m.options.MinSize
+	exclude := m.options.Exclude
+
+	return func() tea.Msg {
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			logging.Get("tui").Debug("resync unavailable", "error", err)
+			return daemonResyncMsg{}
+		}
+		defer daemonClient.Close()
+
+		pages := make([]resyncPage, len(roots))
+		for i, root := range roots {
+			result, err := daemonClient.QueryLargeFilesPage(ctx, root, minSize, exclude, daemonPageSize, "")
+			if err != nil {
+				logging.Get("tui").Debug("resync unavailable", "root", root, "error", err)
+				return daemonResyncMsg{}
+			}
+			pages[i].complete = !result.Truncated && result.NextPage == ""
+			if n := len(result.Files); n > 0 {
+				pages[i].minSize = result.Files[n-1].Size
+			}
+			pages[i].files = m.ownedFiles(result.Files)
+		}
+		return daemonResyncMsg{pages: pages}
+	}
+}
+
+// checkWatchSuggestion asks the daemon whether it suggests watching a
+// directory covering a scanned root, one the user looks at often that
+// isn't a saved watch. The answer is a watchSuggestionMsg; an empty path
+// means there is nothing to suggest.
+func (m Model) checkWatchSuggestion() tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+	roots := m.daemonRoots()
+
+	return func() tea.Msg {
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return watchSuggestionMsg{}
+		}
+		defer daemonClient.Close()
+
+		suggestions, err := daemonClient.WatchSuggestions(ctx)
+		if err != nil {
+			logging.Get("tui").Debug("no watch suggestions", "error", err)
+			return watchSuggestionMsg{}
+		}
+		for _, w := range suggestions {
+			for _, root := range roots {
+				if root == w.Path || strings.HasPrefix(root, w.Path+string(filepath.Separator)) {
+					return watchSuggestionMsg{path: w.Path, uses: w.Uses}
+				}
+			}
+		}
+		return watchSuggestionMsg{}
+	}
+}
+
+// answerWatchSuggestion saves the suggested directory as a watch, or asks
+// the daemon not to suggest it again.
+func (m Model) answerWatchSuggestion(path string, watch bool) tea.Cmd {
+	target := m.daemonTarget()
+	return func() tea.Msg {
+		ctx := context.Background()
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return watchSuggestionDoneMsg{path: path, watch: watch, err: err}
+		}
+		defer daemonClient.Close()
+		if watch {
+			_, err = daemonClient.AddWatch(ctx, path)
+		} else {
+			err = daemonClient.DismissWatchSuggestion(ctx, path)
+		}
+		return watchSuggestionDoneMsg{path: path, watch: watch, err: err}
+	}
+}
+
+// ownedFiles drops the files not owned by --owner, if set.
+func (m Model) ownedFiles(files []types.FileInfo) []types.FileInfo {
+	if m.options.Owner == nil {
+		return files
+	}
+	owned := files[:0]
+	for _, f := range files {
+		if m.options.Owner.OwnsPath(f.Path) {
+			owned = append(owned, f)
+		}
+	}
+	return owned
+}
+
+// deleteFiles deletes paths with backend through the daemon when it is
+// running, so its index drops them at once instead of when its watcher
+// catches up, and directly otherwise. onDone is called once per path, and
+// calls are serialized. If a local daemon fails
+// before deleting anything (e.g., it predates DeleteFiles), the paths are
+// deleted directly.
+func (m Model) deleteFiles(paths []string, backend trash.Backend, onDone func(trash.Result)) {
+	ctx := context.Background()
+	deleteLocally := backend.DeleteMany
+	target := m.daemonTarget()
+	if len(paths) == 0 || !target.Running() {
+		deleteLocally(ctx, paths, onDone)
+		return
+	}
+
+	reported := make(map[string]bool, len(paths))
+	err := func() error {
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer daemonClient.Close()
+		return daemonClient.DeleteFiles(ctx, paths, client.DeleteOptions{Backend: backend.Name()}, func(r client.DeleteResult) {
+			reported[r.Path] = true
+			onDone(trash.Result{Path: r.Path, Err: r.Err, Location: r.Location})
+		})
+	}()
+	if err == nil {
+		return
+	}
+	if len(reported) == 0 && !target.IsRemote() {
+		logging.Get("tui").Debug("deleting without the daemon", "error", err)
+		deleteLocally(ctx, paths, onDone)
+		return
+	}
+	for _, path := range paths {
+		if !reported[path] {
+			onDone(trash.Result{Path: path, Err: err})
+		}
+	}
+}
+
+// startLiveWatch starts watching for live file events from the daemon,
+// under every root.
+func (m Model) startLiveWatch() tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+	roots := m.daemonRoots()
+	minSize := m.options.MinSize
+	exclude := m.options.Exclude
+
+	return func() tea.Msg {
+		// Check if daemon is running
+		if !target.Running() {
+			return LiveWatchErrorMsg{Err: errors.New("daemon not running")}
+		}
+
+		// Connect to daemon
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return LiveWatchErrorMsg{Err: err}
+		}
+
+		// Start watching for file events
+		var chans []<-chan fileEvent
+		for _, root := range roots {
+			eventChan, err := daemonClient.WatchLargeFiles(ctx, root, minSize, exclude)
+			if err != nil {
+				daemonClient.Close()
+				return LiveWatchErrorMsg{Err: err}
+			}
+			chans = append(chans, eventChan)
+		}
+
+		// The stream needs the connection open; the model closes it when
+		// the stream drops
+		return LiveWatchStartedMsg{EventChan: mergeEvents(chans), Conn: daemonClient}
+	}
+}
+
+// startDaemonLogs starts reading the daemon's recent log entries, as many
+// as the log viewer keeps, and each new one, for a daemonLogsMsg.
+func (m Model) startDaemonLogs() tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+
+	return func() tea.Msg {
+		if !target.Running() {
+			return daemonLogsEndedMsg{}
+		}
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			logging.Get("tui").Debug("can't read daemon logs", "error", err)
+			return daemonLogsEndedMsg{}
+		}
+
+		entries := make(chan logging.LogEntry, logViewerSize)
+		go func() {
+			defer close(entries)
+			defer daemonClient.Close()
+			query := client.LogQuery{Limit: logViewerSize, Follow: true}
+			err := daemonClient.GetLogs(ctx, query, func(e logging.LogEntry) {
+				select {
+				case entries <- e:
+				case <-ctx.Done():
+				}
+			})
+			if err != nil {
+				logging.Get("tui").Debug("stopped reading daemon logs", "error", err)
+			}
+		}()
+		return daemonLogsMsg{entries: entries}
+	}
+}
+
+// mergeEvents returns a channel with the events of every channel in chans,
+// closed once they all are.
+func mergeEvents(chans []<-chan fileEvent) <-chan fileEvent {
+	if len(chans) == 1 {
+		return chans[0]
+	}
+	merged := make(chan fileEvent)
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range ch {
+				merged <- event
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
+
+// startTreeWatch starts watching for tree events from the daemon.
+func (m Model) startTreeWatch() tea.Cmd {
+	ctx := m.ctx
+	target := m.daemonTarget()
+	root := m.daemonRoot()
+	minSize := m.options.MinSize
+
+	return func() tea.Msg {
+		// Check if daemon is running
+		if !target.Running() {
+			return TreeWatchErrorMsg{Err: errors.New("daemon not running")}
+		}
+
+		// Connect to daemon
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return TreeWatchErrorMsg{Err: err}
+		}
+
+		// Start watching for tree events
+		eventChan, err := daemonClient.WatchTree(ctx, root, minSize)
+		if err != nil {
+			daemonClient.Close()
+			return TreeWatchErrorMsg{Err: err}
+		}
+
+		// The stream needs the connection open; the model closes it when
+		// the stream drops
+		return TreeWatchStartedMsg{EventChan: eventChan, Conn: daemonClient}
+	}
+}
+
+// loadTree loads the tree view data from the daemon. The tree has a
+// single root, so there is none when several roots are scanned.
+func (m Model) loadTree() tea.Cmd {
+	if len(m.roots()) > 1 {
+		return func() tea.Msg {
+			return TreeErrorMsg{Err: errors.New("the tree view shows a single root")}
+		}
+	}
+	ctx := m.ctx
+	target := m.daemonTarget()
+	root := m.daemonRoot()
+	minSize := m.options.MinSize
+	exclude := m.options.Exclude
+
+	return func() tea.Msg {
+		// Check if daemon is running
+		if !target.Running() {
+			return TreeErrorMsg{Err: errors.New("daemon not running")}
+		}
+
+		// Connect to daemon
+		daemonClient, err := target.Connect(ctx)
+		if err != nil {
+			return TreeErrorMsg{Err: err}
+		}
+		defer daemonClient.Close()
+
+		// Get tree data
+		treeData, err := daemonClient.GetTree(ctx, root, minSize, exclude)
+		if err != nil {
+			return TreeErrorMsg{Err: err}
+		}
+
+		return TreeLoadedMsg{Root: treeData}
+	}
+}
+
+// daemonDirEntries reads the entry counts of every directory under the
+// roots from the daemon's index.
+func (m Model) daemonDirEntries() ([]du.Dir, error) {
+	target := m.daemonTarget()
+	if !target.Running() {
+		return nil, errors.New("daemon not running")
+	}
+	daemonClient, err := target.Connect(m.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer daemonClient.Close()
+
+	var dirs []du.Dir
+	for _, root := range m.daemonRoots() {
+		found, _, err := daemonClient.GetDirSizes(m.ctx, root, 0)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, found...)
+	}
+	return dirs, nil
+}
@@ -0,0 +1,111 @@
+//go:build lite
+
+package tui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// errNoDaemonSupport is reported by daemon commands in lite builds.
+var errNoDaemonSupport = errors.New("daemon support not compiled in (lite build)")
+
+// fileEvent mirrors client.FileEvent for builds without the gRPC client.
+type fileEvent struct {
+	Type    string // "created", "modified", "deleted", "renamed"
+	Path    string
+	Size    int64
+	ModTime int64
+	NewPath string
+}
+
+// treeEvent mirrors client.TreeEvent for builds without the gRPC client.
+type treeEvent struct {
+	Type       string // "created", "modified", "deleted", "renamed"
+	Path       string
+	Size       int64
+	ModTime    int64
+	ParentPath string
+	NewPath    string
+}
+
+// treeNode mirrors client.TreeNode for builds without the gRPC client.
+type treeNode struct {
+	Path           string
+	Name           string
+	IsDir          bool
+	Size           int64
+	ModTime        int64
+	FileType       string
+	LargeFileSize  int64
+	LargeFileCount int
+	Children       []*treeNode
+}
+
+// tryDaemonInstantLoad always falls back to a direct scan in lite builds.
+func (m Model) tryDaemonInstantLoad() *DaemonFilesMsg {
+	return nil
+}
+
+// fetchDaemonPage is never needed in lite builds, which load no pages.
+func (m Model) fetchDaemonPage(int, string) tea.Cmd {
+	return nil
+}
+
+// resyncFiles is never needed in lite builds, which have no live stream
+// to restore.
+func (m Model) resyncFiles() tea.Cmd {
+	return nil
+}
+
+// startDaemonLogs has no daemon to read logs from in lite builds.
+func (m Model) startDaemonLogs() tea.Cmd {
+	return nil
+}
+
+// checkWatchSuggestion has nothing to suggest in lite builds.
+func (m Model) checkWatchSuggestion() tea.Cmd {
+	return nil
+}
+
+// answerWatchSuggestion is never needed in lite builds, which suggest
+// nothing.
+func (m Model) answerWatchSuggestion(string, bool) tea.Cmd {
+	return nil
+}
+
+// deleteFiles deletes paths with backend directly in lite builds.
+func (m Model) deleteFiles(paths []string, backend trash.Backend, onDone func(trash.Result)) {
+	backend.DeleteMany(context.Background(), paths, onDone)
+}
+
+// startLiveWatch reports that live watching is unavailable in lite builds.
+func (m Model) startLiveWatch() tea.Cmd {
+	return func() tea.Msg {
+		return LiveWatchErrorMsg{Err: errNoDaemonSupport}
+	}
+}
+
+// startTreeWatch reports that tree watching is unavailable in lite builds.
+func (m Model) startTreeWatch() tea.Cmd {
+	return func() tea.Msg {
+		return TreeWatchErrorMsg{Err: errNoDaemonSupport}
+	}
+}
+
+// loadTree reports that the daemon tree is unavailable in lite builds.
+func (m Model) loadTree() tea.Cmd {
+	return func() tea.Msg {
+		return TreeErrorMsg{Err: errNoDaemonSupport}
+	}
+}
+
+// daemonDirEntries reports that there is no index to count from in lite
+// builds, which count by scanning.
+func (m Model) daemonDirEntries() ([]du.Dir, error) {
+	return nil, errNoDaemonSupport
+}
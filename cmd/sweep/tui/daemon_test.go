@@ -0,0 +1,47 @@
+//go:build !lite
+
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestDaemonPagesLoadNearEnd(t *testing.T) {
+	m := NewModel(Options{})
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: "/test/a.bin", Size: 300 * types.MiB, ModTime: time.Now()},
+		{Path: "/test/b.bin", Size: 200 * types.MiB, ModTime: time.Now()},
+	})
+	m.resultModel.SetTruncated(true, true)
+	m.nextPages = []string{"page2"}
+	m.pageFetching = []bool{false}
+
+	if m.fetchPageNearEnd() == nil {
+		t.Fatal("expected the next page to be fetched near the end of the list")
+	}
+	if m.fetchPageNearEnd() != nil {
+		t.Error("expected no second fetch while one is in flight")
+	}
+
+	// A live event already added b.bin
+	updated, _ := m.Update(DaemonPageMsg{
+		Files: []types.FileInfo{
+			{Path: "/test/b.bin", Size: 200 * types.MiB, ModTime: time.Now()},
+			{Path: "/test/c.bin", Size: 100 * types.MiB, ModTime: time.Now()},
+		},
+	})
+	m = updated.(Model)
+	if got := len(m.resultModel.files); got != 3 {
+		t.Errorf("got %d files after the last page, want 3", got)
+	}
+	if m.nextPages[0] != "" || m.pageFetching[0] {
+		t.Errorf("nextPages = %q, pageFetching = %v after the last page", m.nextPages, m.pageFetching)
+	}
+	if strings.Contains(m.resultModel.renderFooter(120), "Largest") {
+		t.Error("results should not be marked as truncated after the last page")
+	}
+}
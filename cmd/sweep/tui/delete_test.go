@@ -0,0 +1,413 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestStartDeleteVerifySkipsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	stable := filepath.Join(dir, "stable.iso")
+	growing := filepath.Join(dir, "growing.part")
+	if err := os.WriteFile(stable, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(growing, make([]byte, 20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stableInfo, _ := os.Stat(stable)
+	growingInfo, _ := os.Stat(growing)
+
+	m := NewModel(Options{Root: dir, DryRun: true, VerifyBeforeDelete: true})
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: stable, Size: 10, ModTime: stableInfo.ModTime()},
+		// Selected when it was smaller; the download kept writing.
+		{Path: growing, Size: 5, ModTime: growingInfo.ModTime().Add(-time.Minute)},
+	})
+	m.resultModel.SelectAll()
+
+	next, _ := m.startDelete()
+	m = next.(Model)
+	for {
+		msg := m.listenForDeleteProgress()().(deleteProgressMsg)
+		next, _ = m.Update(msg)
+		m = next.(Model)
+		if msg.done {
+			break
+		}
+	}
+
+	if len(m.deleteSkipped) != 1 || m.deleteSkipped[0] != growing {
+		t.Errorf("deleteSkipped = %v, want [%s]", m.deleteSkipped, growing)
+	}
+	if m.lastFreedSize != 10 {
+		t.Errorf("lastFreedSize = %d, want 10", m.lastFreedSize)
+	}
+	if m.state != StateComplete {
+		t.Errorf("state = %v, want StateComplete", m.state)
+	}
+
+	// The dry run's projection leaves out the skipped file
+	r := m.dryRunReport
+	if r == nil || r.Files != 1 || r.Size != 10 || len(r.Dirs) != 1 || r.Dirs[0].Path != dir {
+		t.Fatalf("dryRunReport = %+v, want stable.iso's 10 bytes under %s", r, dir)
+	}
+	if len(r.Recent) != 1 || r.Recent[0].Path != stable {
+		t.Errorf("dryRunReport.Recent = %+v, want the just written %s", r.Recent, stable)
+	}
+	if view := m.renderComplete(); !strings.Contains(view, "1 modified in the last 7 days") {
+		t.Errorf("completion dialog does not warn about the recent file:\n%s", view)
+	}
+}
+
+func TestConfirmDeleteOverQuotaDeletesPermanently(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("per-volume trash detection is tested on Linux")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.iso")
+	if err := os.WriteFile(big, make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Options{Root: dir, TrashQuota: &trash.Quota{Default: 50}})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: big, Size: 100}})
+	m.resultModel.SelectAll()
+	m.state = StateConfirm
+
+	next, cmd := m.confirmDelete()
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatal("confirmDelete should check the quota first")
+	}
+	next, _ = m.Update(cmd())
+	m = next.(Model)
+	if m.state != StateQuota {
+		t.Fatalf("state = %v, want StateQuota", m.state)
+	}
+	if m.canPurge() {
+		t.Error("a selection larger than the quota should not offer purging")
+	}
+	if !strings.Contains(m.renderQuotaDialog(), "Trash quota exceeded") {
+		t.Error("quota dialog should explain the problem")
+	}
+
+	next, _ = m.handleQuotaKey("d")
+	m = next.(Model)
+	for {
+		msg := m.listenForDeleteProgress()().(deleteProgressMsg)
+		next, _ = m.Update(msg)
+		m = next.(Model)
+		if msg.done {
+			break
+		}
+	}
+
+	if _, err := os.Stat(big); !os.IsNotExist(err) {
+		t.Errorf("%s should be deleted", big)
+	}
+	if len(m.deleteErrors) != 0 {
+		t.Errorf("deleteErrors = %v", m.deleteErrors)
+	}
+	if len(m.deleteNotes) != 1 || !strings.Contains(m.deleteNotes[0], "permanently") {
+		t.Errorf("deleteNotes = %v, want a permanent deletion note", m.deleteNotes)
+	}
+}
+
+func TestDeleteSkipsReadOnlyVolumes(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.iso")
+	mounted := filepath.Join(dir, "image", "disc.iso")
+	orig := readOnlyVolumes
+	t.Cleanup(func() { readOnlyVolumes = orig })
+	readOnlyVolumes = func(paths []string) map[string]string {
+		ro := make(map[string]string)
+		for _, p := range paths {
+			if p == mounted {
+				ro[p] = filepath.Join(dir, "image")
+			}
+		}
+		return ro
+	}
+
+	m := NewModel(Options{Root: dir})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: local, Size: 10}, {Path: mounted, Size: 20}})
+	m.resultModel.SelectAll()
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != StateConfirm {
+		t.Fatalf("state = %v, want StateConfirm for the writable file", m.state)
+	}
+	if got := m.resultModel.SelectedFiles(); len(got) != 1 || got[0].Path != local {
+		t.Errorf("selected = %v, want only %s", got, local)
+	}
+
+	// A selection wholly on read-only volumes doesn't get to confirmation
+	m.state = StateResults
+	m.resultModel.SelectAll()
+	m.resultModel.Deselect(local)
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != StateResults || m.resultModel.HasSelection() {
+		t.Errorf("state = %v, selected = %d; want StateResults with nothing selected", m.state, m.resultModel.SelectedCount())
+	}
+}
+
+func TestConfirmPermanentRequiresTypedWord(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("trashing without a desktop is tested on Linux")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	scratch := filepath.Join(dir, "scratch")
+	if err := os.Mkdir(scratch, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	render, photo := filepath.Join(scratch, "render.mov"), filepath.Join(dir, "photo.raw")
+	for _, path := range []string{render, photo} {
+		if err := os.WriteFile(path, make([]byte, 10), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewModel(Options{Root: dir, NoDaemon: true, PermanentRoots: trash.NewPermanentRoots([]string{scratch})})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: render, Size: 10}, {Path: photo, Size: 10}})
+	m.resultModel.SelectAll()
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != StateConfirm {
+		t.Fatalf("state = %v, want StateConfirm", m.state)
+	}
+	if dialog := m.renderConfirmDialog(); !strings.Contains(dialog, "1 files (10 B) will be deleted permanently") {
+		t.Errorf("confirm dialog should warn about the permanent delete:\n%s", dialog)
+	}
+
+	// 'y' is typed rather than confirming, and Enter needs the whole word
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("y")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyBackspace},
+		{Type: tea.KeyRunes, Runes: []rune("delet")},
+		{Type: tea.KeyEnter},
+	} {
+		next, _ = m.handleKey(key)
+		m = next.(Model)
+		if m.state != StateConfirm {
+			t.Fatalf("after %q, state = %v, want StateConfirm", key.String(), m.state)
+		}
+	}
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("e")}, {Type: tea.KeyEnter}} {
+		next, _ = m.handleKey(key)
+		m = next.(Model)
+	}
+	if m.state != StateDeleting {
+		t.Fatalf("state = %v, want StateDeleting", m.state)
+	}
+	for {
+		msg := m.listenForDeleteProgress()().(deleteProgressMsg)
+		next, _ = m.Update(msg)
+		m = next.(Model)
+		if msg.done {
+			break
+		}
+	}
+
+	if len(m.deleteErrors) != 0 {
+		t.Errorf("deleteErrors = %v", m.deleteErrors)
+	}
+	for _, path := range []string{render, photo} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be deleted", path)
+		}
+	}
+	items, err := trash.FindTrashed([]trash.Record{{Path: render, Size: 10}, {Path: photo, Size: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Original != photo {
+		t.Errorf("trashed = %v, want only %s", items, photo)
+	}
+	if len(m.deleteNotes) != 1 || !strings.Contains(m.deleteNotes[0], "permanently") {
+		t.Errorf("deleteNotes = %v, want a permanent deletion note", m.deleteNotes)
+	}
+}
+
+func TestReadOnlyBlocksDelete(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "keep.iso")
+	if err := os.WriteFile(file, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Options{Root: dir, ReadOnly: true})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: file, Size: 10}})
+	m.resultModel.SelectAll()
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != StateResults {
+		t.Errorf("state = %v, want StateResults (no confirmation in read-only mode)", m.state)
+	}
+
+	// Even if reached, deletion must not run.
+	next, cmd := m.startDelete()
+	m = next.(Model)
+	if cmd != nil || m.state != StateResults {
+		t.Error("startDelete should refuse in read-only mode")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("%s should be untouched: %v", file, err)
+	}
+
+	if !strings.Contains(m.resultModel.renderHeader(80), "READ-ONLY") {
+		t.Error("header should show the read-only badge")
+	}
+}
+
+func TestUndoDeleteRestoresFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("restoring from the XDG trash is tested on Linux")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	file := filepath.Join(dir, "old.iso")
+	if err := os.WriteFile(file, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mf, err := manifest.New(filepath.Join(t.TempDir(), "manifest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Options{Root: dir, Manifest: mf})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: file, Size: 10}})
+	m.resultModel.SelectAll()
+
+	next, _ := m.startDelete()
+	m = next.(Model)
+	for {
+		msg := m.listenForDeleteProgress()().(deleteProgressMsg)
+		next, _ = m.Update(msg)
+		m = next.(Model)
+		if msg.done {
+			break
+		}
+	}
+	if m.undoEntry == "" {
+		t.Fatal("delete should be recorded for undo")
+	}
+	if !strings.Contains(m.renderComplete(), "[U] Undo") {
+		t.Error("completion dialog should offer undo")
+	}
+
+	next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatal("U should start the undo")
+	}
+	if len(m.resultModel.Files()) != 0 {
+		t.Errorf("deleted file should leave the list before undo, got %d files", len(m.resultModel.Files()))
+	}
+	next, _ = m.Update(cmd())
+	m = next.(Model)
+
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("%s should be restored: %v", file, err)
+	}
+	if files := m.resultModel.Files(); len(files) != 1 || files[0].Path != file {
+		t.Errorf("restored file should be back in the list, got %v", files)
+	}
+	if m.undoEntry != "" {
+		t.Error("undo should only apply once")
+	}
+}
+
+func TestConfirmPolicyByDirectoryClass(t *testing.T) {
+	policy := &rules.ConfirmPolicy{
+		Classes: []rules.DirClass{
+			{Name: rules.ClassCache, Confirm: rules.ConfirmNone, Dirs: []string{"/home/u/.cache"}},
+			{Name: rules.ClassDocuments, Confirm: rules.ConfirmStrict, Dirs: []string{"/home/u/Documents"}},
+		},
+		Default: rules.ConfirmStandard,
+	}
+	open := func(paths ...string) Model {
+		m := NewModel(Options{Root: "/home/u", NoDaemon: true, DryRun: true, Confirm: policy})
+		files := make([]types.FileInfo, len(paths))
+		for i, path := range paths {
+			files[i] = types.FileInfo{Path: path, Size: 10}
+		}
+		m.resultModel.SetFiles(files)
+		m.resultModel.SelectAll()
+		next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+		return next.(Model)
+	}
+	press := func(m Model, key tea.KeyMsg) Model {
+		next, _ := m.handleKey(key)
+		return next.(Model)
+	}
+	yes := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}
+
+	// Caches are deleted without asking
+	if m := open("/home/u/.cache/go/a.o"); m.state != StateDeleting {
+		t.Errorf("cache: state = %v, want StateDeleting", m.state)
+	}
+
+	// Elsewhere 'y' confirms
+	m := open("/home/u/.cache/go/a.o", "/home/u/src/big.iso")
+	if m.state != StateConfirm {
+		t.Fatalf("standard: state = %v, want StateConfirm", m.state)
+	}
+	if m = press(m, yes); m.state != StateDeleting {
+		t.Errorf("standard: after y, state = %v, want StateDeleting", m.state)
+	}
+
+	// Documents need the word typed, even alongside caches
+	m = open("/home/u/.cache/go/a.o", "/home/u/Documents/thesis.pdf")
+	if dialog := m.renderConfirmDialog(); !strings.Contains(dialog, "documents directories") {
+		t.Errorf("strict confirm dialog should name the class:\n%s", dialog)
+	}
+	if m = press(m, yes); m.state != StateConfirm {
+		t.Fatalf("strict: after y, state = %v, want StateConfirm", m.state)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(permanentConfirmWord)})
+	if m = press(m, tea.KeyMsg{Type: tea.KeyEnter}); m.state != StateDeleting {
+		t.Errorf("strict: after typing the word, state = %v, want StateDeleting", m.state)
+	}
+}
+
+func TestConfirmWarnsAboutCloudPlaceholders(t *testing.T) {
+	m := NewModel(Options{Root: "/data", DryRun: true})
+	m.state = StateResults
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: "/data/OneDrive/deck.pptx", Size: 3 * types.GiB, Cloud: &cloud.Info{Provider: cloud.ProviderOneDrive}},
+		{Path: "/data/local.iso", Size: types.GiB},
+	})
+	m.resultModel.SelectAll()
+
+	next, _ := m.openConfirm()
+	m = next.(Model)
+	if view := m.View(); !strings.Contains(view, "1 files (3.0 GiB) are online only") {
+		t.Errorf("expected the confirm dialog to warn about the placeholder, got:\n%s", view)
+	}
+
+	m.resultModel.Deselect("/data/OneDrive/deck.pptx")
+	if warning := m.renderCloudWarning(); warning != "" {
+		t.Errorf("unexpected warning for a local file: %q", warning)
+	}
+}
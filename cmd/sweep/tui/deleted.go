@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/restore"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// deletedPaneRows is the most files the recently deleted pane shows at once.
+const deletedPaneRows = 12
+
+// deletedPane lists the files sweep moved to the trash that are still
+// there, newest first, so any one can be restored with a key ('D').
+type deletedPane struct {
+	items   []trash.Trashed
+	cursor  int
+	offset  int // First visible row
+	loading bool
+	err     error
+}
+
+// deletedLoadedMsg carries the trashed files found from the history.
+type deletedLoadedMsg struct {
+	items []trash.Trashed
+	err   error
+}
+
+// openDeleted opens the recently deleted pane and starts loading it.
+func (m *Model) openDeleted() tea.Cmd {
+	if m.options.Manifest == nil {
+		logging.Get("tui").Info("deletions aren't recorded (manifest.enabled is false)")
+		return nil
+	}
+	m.deleted = &deletedPane{loading: true}
+	return m.loadDeleted()
+}
+
+// loadDeleted returns a command that finds the files the history says
+// sweep trashed and that are still in the trash. Restored and emptied
+// files are left out.
+func (m Model) loadDeleted() tea.Cmd {
+	mf := m.options.Manifest
+	return func() tea.Msg {
+		entries, err := mf.List(0)
+		if err != nil {
+			return deletedLoadedMsg{err: err}
+		}
+		items, err := trash.FindTrashed(restore.TrashRecords(entries))
+		slices.Reverse(items)
+		return deletedLoadedMsg{items: items, err: err}
+	}
+}
+
+// handleDeletedLoaded fills the pane, keeping the cursor where it was.
+func (m *Model) handleDeletedLoaded(msg deletedLoadedMsg) {
+	if m.deleted == nil {
+		return
+	}
+	p := m.deleted
+	p.loading = false
+	p.items, p.err = msg.items, msg.err
+	p.cursor = min(p.cursor, max(len(p.items)-1, 0))
+	p.scrollToCursor()
+}
+
+// handleDeletedKey handles keys while the pane is open.
+func (m Model) handleDeletedKey(key string) (tea.Model, tea.Cmd) {
+	p := m.deleted
+	switch key {
+	case "esc", "D":
+		m.deleted = nil
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+		p.scrollToCursor()
+	case "down", "j":
+		p.cursor = min(p.cursor+1, max(len(p.items)-1, 0))
+		p.scrollToCursor()
+	case "r", "enter":
+		return m, m.restoreDeleted()
+	}
+	return m, nil
+}
+
+// scrollToCursor keeps the cursor's row visible.
+func (p *deletedPane) scrollToCursor() {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+deletedPaneRows {
+		p.offset = p.cursor - deletedPaneRows + 1
+	}
+}
+
+// restoreDeleted restores the file under the pane's cursor. A file whose
+// path has been taken since is restored next to it. The result arrives as
+// an undoDoneMsg, which puts the file back in the list and reloads the
+// pane.
+func (m *Model) restoreDeleted() tea.Cmd {
+	p := m.deleted
+	if m.options.ReadOnly {
+		logReadOnly()
+		return nil
+	}
+	if p.loading || len(p.items) == 0 {
+		return nil
+	}
+	it, mf := p.items[p.cursor], m.options.Manifest
+	p.loading = true
+	logging.Get("tui").Info("restoring deleted file", "path", it.Original, "entry", it.Entry)
+	return func() tea.Msg {
+		entry, err := mf.Get(it.Entry)
+		if err != nil {
+			return undoDoneMsg{err: err}
+		}
+		report, err := restore.Entry(entry, restore.Options{
+			Conflict: restore.ConflictRename,
+			Paths:    []string{it.Original},
+			Manifest: mf,
+		})
+		return undoDoneMsg{report: report, err: err}
+	}
+}
+
+// renderDeleted renders the recently deleted pane over bg.
+func (m Model) renderDeleted(bg string) string {
+	p := m.deleted
+	var b strings.Builder
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+	pathWidth := max(min(m.width-40, 70), 20)
+
+	b.WriteString(title.Render("Recently Deleted"))
+	b.WriteString("\n")
+	var total int64
+	var session int
+	for _, it := range p.items {
+		total += it.Size
+		if m.sessionDeletes[it.Entry] {
+			session++
+		}
+	}
+	summary := fmt.Sprintf("%d files in the trash, %s", len(p.items), types.FormatSize(total))
+	if session > 0 {
+		summary += fmt.Sprintf("; %d deleted this session (•)", session)
+	}
+	b.WriteString(mutedTextStyle.Render(summary))
+	b.WriteString("\n\n")
+
+	switch {
+	case p.err != nil:
+		b.WriteString(mutedTextStyle.Render("Could not read the trash: " + p.err.Error()))
+		b.WriteString("\n")
+	case p.loading && p.items == nil:
+		b.WriteString(mutedTextStyle.Render("Looking in the trash..."))
+		b.WriteString("\n")
+	case len(p.items) == 0:
+		b.WriteString(mutedTextStyle.Render("Nothing sweep deleted is left in the trash."))
+		b.WriteString("\n")
+	default:
+		now := time.Now()
+		locale := m.resultModel.columns.Locale
+		end := min(p.offset+deletedPaneRows, len(p.items))
+		for i := p.offset; i < end; i++ {
+			it := p.items[i]
+			marker := " "
+			if m.sessionDeletes[it.Entry] {
+				marker = "•"
+			}
+			row := fmt.Sprintf("%s %9s  %-10s  %s", marker, types.FormatSize(it.Size),
+				truncateCell(locale.FormatAgo(it.Deleted, now), 10), truncatePath(it.Original, pathWidth))
+			if i == p.cursor {
+				row = rowHighlightStyle.Render(row)
+			}
+			b.WriteString(row)
+			b.WriteString("\n")
+		}
+		if len(p.items) > deletedPaneRows {
+			b.WriteString(mutedTextStyle.Render(fmt.Sprintf("  %d-%d of %d", p.offset+1, end, len(p.items))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	hint := "[r] Restore  [Esc] Close"
+	if m.options.ReadOnly {
+		hint = "Read-only  [Esc] Close"
+	}
+	b.WriteString(mutedTextStyle.Render(hint))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+func TestDeletedPaneRestoresFile(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("restoring from the XDG trash is tested on Linux")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	mf, err := manifest.New(filepath.Join(t.TempDir(), "manifest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mf.EnsureDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	// One file from an earlier session, and one deleted in this one
+	var records []manifest.FileRecord
+	for _, name := range []string{"earlier.iso", "now.iso"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 10), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := trash.MoveToTrash(path); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, manifest.FileRecord{Path: path, Size: 10, DeletedAt: time.Now().UTC()})
+	}
+	if _, err := mf.LogDelete(records[:1]); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := mf.LogDelete(records[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Options{Root: dir, Manifest: mf})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	m.sessionDeletes[entry.ID] = true
+	press := func(key string) tea.Cmd {
+		next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+		return cmd
+	}
+	update := func(msg tea.Msg) tea.Cmd {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return cmd
+	}
+
+	update(press("D")())
+	if m.deleted == nil || len(m.deleted.items) != 2 {
+		t.Fatalf("D should list the 2 trashed files, got %+v", m.deleted)
+	}
+	view := m.View()
+	if !strings.Contains(view, "earlier.iso") || !strings.Contains(view, "1 deleted this session") {
+		t.Errorf("pane should list trashed files and count this session's:\n%s", view)
+	}
+
+	earlier := filepath.Join(dir, "earlier.iso")
+	for m.deleted.items[m.deleted.cursor].Original != earlier {
+		cursor := m.deleted.cursor
+		press("j")
+		if m.deleted.cursor == cursor {
+			t.Fatal("j should move to the next file")
+		}
+	}
+	cmd := press("r")
+	if cmd == nil {
+		t.Fatal("r should restore the file under the cursor")
+	}
+	update(update(cmd())())
+
+	if _, err := os.Stat(earlier); err != nil {
+		t.Errorf("%s should be restored: %v", earlier, err)
+	}
+	if files := m.resultModel.Files(); len(files) != 1 || files[0].Path != earlier {
+		t.Errorf("restored file should be in the list, got %v", files)
+	}
+	if len(m.deleted.items) != 1 || m.deleted.items[0].Original != filepath.Join(dir, "now.iso") {
+		t.Errorf("pane should reload without the restored file, got %+v", m.deleted.items)
+	}
+	if m.deleted.cursor != 0 {
+		t.Errorf("cursor = %d, want it on the remaining file", m.deleted.cursor)
+	}
+
+	press("esc")
+	if m.deleted != nil {
+		t.Error("esc should close the pane")
+	}
+}
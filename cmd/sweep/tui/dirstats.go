@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+const (
+	// dirStatsTopFiles is the number of largest files the '?' popup lists.
+	dirStatsTopFiles = 5
+
+	// dirStatsTypes is the number of file types the popup lists, including
+	// "Other" for the rest.
+	dirStatsTypes = 5
+)
+
+// typeShare is the part of a directory's files of one type.
+type typeShare struct {
+	name  string
+	files int
+	size  int64
+}
+
+// dirStats summarizes the indexed files under a directory, so the '?'
+// popup can show what's inside without expanding it.
+type dirStats struct {
+	path   string
+	files  int
+	size   int64
+	top    []*tree.Node // Largest files first
+	types  []typeShare  // Largest share first
+	newest *tree.Node
+	oldest *tree.Node
+}
+
+// computeDirStats summarizes the files anywhere under dir.
+func computeDirStats(dir *tree.Node) dirStats {
+	stats := dirStats{path: dir.Path}
+	byType := make(map[string]*typeShare)
+	var files []*tree.Node
+
+	var walk func(n *tree.Node)
+	walk = func(n *tree.Node) {
+		for _, child := range n.Children {
+			if child.IsDir {
+				walk(child)
+				continue
+			}
+			files = append(files, child)
+			stats.size += child.Size
+
+			share := byType[child.FileType]
+			if share == nil {
+				share = &typeShare{name: child.FileType}
+				byType[child.FileType] = share
+			}
+			share.files++
+			share.size += child.Size
+
+			if stats.newest == nil || child.ModTime > stats.newest.ModTime {
+				stats.newest = child
+			}
+			if stats.oldest == nil || child.ModTime < stats.oldest.ModTime {
+				stats.oldest = child
+			}
+		}
+	}
+	walk(dir)
+	stats.files = len(files)
+
+	slices.SortFunc(files, func(a, b *tree.Node) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Path, b.Path))
+	})
+	stats.top = files[:min(dirStatsTopFiles, len(files))]
+
+	// Untyped files and the smallest types are summed as "Other"
+	other := typeShare{name: "Other"}
+	var named []typeShare
+	for typ, share := range byType {
+		if typ == "" {
+			other = typeShare{name: "Other", files: share.files, size: share.size}
+			continue
+		}
+		named = append(named, *share)
+	}
+	slices.SortFunc(named, func(a, b typeShare) int {
+		return cmp.Or(cmp.Compare(b.size, a.size), cmp.Compare(a.name, b.name))
+	})
+	keep := len(named)
+	if other.files > 0 || keep > dirStatsTypes {
+		keep = min(keep, dirStatsTypes-1)
+	}
+	for _, share := range named[keep:] {
+		other.files += share.files
+		other.size += share.size
+	}
+	stats.types = named[:keep]
+	if other.files > 0 {
+		stats.types = append(stats.types, other)
+	}
+	return stats
+}
+
+// openDirStats opens the '?' popup for the directory under the tree
+// cursor. It does nothing on files.
+func (m *Model) openDirStats() {
+	node := m.treeView.Selected()
+	if node == nil || !node.IsDir {
+		return
+	}
+	stats := computeDirStats(node)
+	m.dirStats = &stats
+}
+
+// renderDirStats renders the directory stats popup over bg.
+func (m Model) renderDirStats(bg string) string {
+	s := m.dirStats
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+	b.WriteString(title.Render(truncatePath(s.path, 56)))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%d large files, %s", s.files, types.FormatSize(s.size))))
+	b.WriteString("\n")
+
+	if s.files == 0 {
+		b.WriteString("\n")
+		b.WriteString(mutedTextStyle.Render("No large files in the index here."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString("\n")
+		b.WriteString(title.Render("Largest"))
+		b.WriteString("\n")
+		for _, f := range s.top {
+			b.WriteString(fmt.Sprintf("  %9s  %s\n", types.FormatSize(f.Size), truncatePath(m.relToDir(f.Path), 44)))
+		}
+
+		b.WriteString("\n")
+		b.WriteString(title.Render("Types"))
+		b.WriteString("\n")
+		for _, t := range s.types {
+			pct := float64(t.size) / float64(max(s.size, 1)) * 100
+			b.WriteString(fmt.Sprintf("  %-14s %5d files %9s %5.1f%%\n",
+				truncatePath(t.name, 14), t.files, types.FormatSize(t.size), pct))
+		}
+
+		b.WriteString("\n")
+		now := time.Now()
+		for _, row := range []struct {
+			label string
+			node  *tree.Node
+		}{{"Newest", s.newest}, {"Oldest", s.oldest}} {
+			mod := time.Unix(row.node.ModTime, 0)
+			b.WriteString(fmt.Sprintf("%s  %s (%s)  %s\n", title.Render(row.label),
+				mod.Format("2006-01-02 15:04"), m.resultModel.columns.Locale.FormatAgo(mod, now),
+				mutedTextStyle.Render(truncatePath(m.relToDir(row.node.Path), 30))))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("From the index  [Esc] Close"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
+
+// relToDir returns path relative to the directory shown in the stats
+// popup.
+func (m Model) relToDir(path string) string {
+	if rel, err := filepath.Rel(m.dirStats.path, path); err == nil {
+		return rel
+	}
+	return path
+}
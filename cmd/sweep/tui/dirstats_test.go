@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestComputeDirStats(t *testing.T) {
+	dir := &tree.Node{Path: "/data", Name: "data", IsDir: true}
+	sub := &tree.Node{Path: "/data/sub", Name: "sub", IsDir: true}
+	dir.AddChild(sub)
+	for i, typ := range []string{"Video", "Video", "Audio", "Image", "Archive", "Document", "Code", ""} {
+		parent := dir
+		if i%2 == 1 {
+			parent = sub
+		}
+		parent.AddChild(&tree.Node{
+			Path:     fmt.Sprintf("%s/f%d", parent.Path, i),
+			Size:     int64(i+1) * types.MiB,
+			ModTime:  int64(1000 + i),
+			FileType: typ,
+		})
+	}
+
+	stats := computeDirStats(dir)
+	if stats.files != 8 || stats.size != 36*types.MiB {
+		t.Errorf("stats = %d files, %d bytes; want 8 files, 36 MiB", stats.files, stats.size)
+	}
+	if len(stats.top) != dirStatsTopFiles || stats.top[0].Path != "/data/sub/f7" || stats.top[4].Path != "/data/sub/f3" {
+		var paths []string
+		for _, f := range stats.top {
+			paths = append(paths, f.Path)
+		}
+		t.Errorf("top = %v, want the 5 largest from f7 down", paths)
+	}
+	if stats.newest.Path != "/data/sub/f7" || stats.oldest.Path != "/data/f0" {
+		t.Errorf("newest = %s, oldest = %s", stats.newest.Path, stats.oldest.Path)
+	}
+
+	if len(stats.types) != dirStatsTypes {
+		t.Fatalf("got %d types, want %d", len(stats.types), dirStatsTypes)
+	}
+	if stats.types[0].name != "Code" {
+		t.Errorf("first type = %s, want Code, the largest named type", stats.types[0].name)
+	}
+	// Other holds the untyped file and the types beyond the first four:
+	// Audio, and Video's two files
+	last := stats.types[len(stats.types)-1]
+	if last.name != "Other" || last.files != 4 || last.size != (8+3+1+2)*types.MiB {
+		t.Errorf("last type = %+v, want Other with the rest", last)
+	}
+}
+
+func TestDirStatsKey(t *testing.T) {
+	m := NewModel(Options{})
+	m.treeView = NewTreeView(createTestTree())
+	m.treeMode = true
+	m.state = StateResults
+	m.width, m.height = 100, 40
+	press := func(key string) {
+		next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+	}
+
+	// The root row is a directory
+	press("?")
+	if m.dirStats == nil {
+		t.Fatal("? should open the stats of the directory under the cursor")
+	}
+	if view := m.View(); !strings.Contains(view, "Largest") || !strings.Contains(view, "file1.txt") {
+		t.Error("the popup should list the largest files")
+	}
+	press("j")
+	if m.treeView.Selected().Path != "/test" {
+		t.Error("keys other than close should not reach the tree while the popup is open")
+	}
+	press("?")
+	if m.dirStats != nil {
+		t.Error("? should close the popup")
+	}
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// diskFreeInterval is how often the free space gauge is refreshed. It is
+// also refreshed as soon as a delete completes.
+const diskFreeInterval = 30 * time.Second
+
+// diskGaugeWidth is the number of cells in the free space gauge's bar.
+const diskGaugeWidth = 10
+
+// diskFreeMsg carries the usage of the scanned volume.
+type diskFreeMsg struct {
+	usage mounts.Usage
+	err   error
+	timed bool // Read on the timer, which is then set again
+}
+
+// diskFreeTickMsg asks for the scanned volume's usage to be read again.
+type diskFreeTickMsg struct{}
+
+// checkDiskFree reads the usage of the volume holding the first root, on
+// the timer when timed. A remote daemon's volumes are on another machine,
+// so there is no gauge.
+func (m Model) checkDiskFree(timed bool) tea.Cmd {
+	if m.options.Remote.Address != "" || m.options.Root == "" {
+		return nil
+	}
+	root := m.options.Root
+	return func() tea.Msg {
+		usage, err := mounts.DiskUsage(root)
+		return diskFreeMsg{usage: usage, err: err, timed: timed}
+	}
+}
+
+// scheduleDiskFree returns a command that refreshes the gauge after
+// diskFreeInterval.
+func scheduleDiskFree() tea.Cmd {
+	return tea.Tick(diskFreeInterval, func(time.Time) tea.Msg {
+		return diskFreeTickMsg{}
+	})
+}
+
+// handleDiskFree shows the usage read. Without it, the gauge is hidden and
+// no longer refreshed.
+func (m *Model) handleDiskFree(msg diskFreeMsg) tea.Cmd {
+	if msg.err != nil {
+		logging.Get("tui").Debug("free space unavailable", "root", m.options.Root, "error", msg.err)
+		m.diskFree = mounts.Usage{}
+		m.resultModel.diskFree = m.diskFree
+		return nil
+	}
+	m.diskFree = msg.usage
+	m.resultModel.diskFree = msg.usage
+	if !msg.timed {
+		return nil // A refresh after a delete; the timer is already set
+	}
+	return scheduleDiskFree()
+}
+
+// renderDiskGauge renders the free space of the scanned volume as a bar of
+// the space in use, or "" when it is unknown. The bar turns yellow below
+// 20% free and red below 10%.
+func renderDiskGauge(u mounts.Usage) string {
+	if u.Total <= 0 {
+		return ""
+	}
+	free := u.FreeFraction()
+	color := mutedColor
+	switch {
+	case free < 0.1:
+		color = dangerColor
+	case free < 0.2:
+		color = warningColor
+	}
+	used := min(int((1-free)*diskGaugeWidth+0.5), diskGaugeWidth)
+	bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", used)) +
+		lipgloss.NewStyle().Foreground(subtleColor).Render(strings.Repeat("░", diskGaugeWidth-used))
+	return "  " + bar + mutedTextStyle.Render(fmt.Sprintf(" %s free of %s", types.FormatSize(u.Free), types.FormatSize(u.Total)))
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+func TestDiskFreeGauge(t *testing.T) {
+	m := NewModel(Options{Root: t.TempDir()})
+	m.width, m.height = 140, 30
+
+	cmd := m.checkDiskFree(true)
+	if cmd == nil {
+		t.Fatal("the scanned volume's free space should be read")
+	}
+	msg, ok := cmd().(diskFreeMsg)
+	if !ok {
+		t.Fatalf("checkDiskFree() sent %T, want diskFreeMsg", cmd())
+	}
+	if errors.Is(msg.err, errors.ErrUnsupported) {
+		t.Skip("volume usage isn't read on this platform")
+	}
+
+	msg.usage = mounts.Usage{Total: 500 << 30, Free: 120 << 30}
+	next, tick := m.Update(msg)
+	m = next.(Model)
+	if tick == nil {
+		t.Error("a timed read should set the timer again")
+	}
+	if header := m.resultModel.renderHeader(m.width); !strings.Contains(header, "120 GiB free of 500 GiB") {
+		t.Errorf("header should show the free space, got %q", header)
+	}
+
+	// Refreshed after a delete without setting another timer
+	next, tick = m.Update(diskFreeMsg{usage: mounts.Usage{Total: 500 << 30, Free: 200 << 30}})
+	m = next.(Model)
+	if tick != nil {
+		t.Error("a refresh after a delete shouldn't set another timer")
+	}
+	if header := m.resultModel.renderHeader(m.width); !strings.Contains(header, "200 GiB free") {
+		t.Errorf("header should show the space freed, got %q", header)
+	}
+
+	next, _ = m.Update(diskFreeMsg{err: errors.New("gone"), timed: true})
+	m = next.(Model)
+	if header := m.resultModel.renderHeader(m.width); strings.Contains(header, "free of") {
+		t.Errorf("the gauge should be hidden when the usage can't be read, got %q", header)
+	}
+
+	m.options.Remote = config.RemoteConfig{Address: "nas:7433"}
+	if m.checkDiskFree(true) != nil {
+		t.Error("a remote daemon's volume has no gauge")
+	}
+}
+
+func TestRenderDiskGauge(t *testing.T) {
+	if got := renderDiskGauge(mounts.Usage{}); got != "" {
+		t.Errorf("unknown usage should render nothing, got %q", got)
+	}
+	gauge := renderDiskGauge(mounts.Usage{Total: 100, Free: 30})
+	if n := strings.Count(gauge, "█"); n != 7 {
+		t.Errorf("gauge has %d used cells, want 7: %q", n, gauge)
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+)
+
+// dirEntriesMsg carries the entry counts of the directories under the
+// roots, read for the tree view's inode column.
+type dirEntriesMsg struct {
+	dirs []du.Dir
+	err  error
+}
+
+// toggleEntries shows or hides the inode count of each directory in the
+// tree view ('N'), since a directory of many small files can exhaust a
+// volume's inodes long before its size stands out. The counts are read the
+// first time they are shown.
+func (m *Model) toggleEntries() tea.Cmd {
+	if !m.treeView.ToggleEntries() || m.treeView.HasEntries() {
+		return nil
+	}
+	return m.countEntries()
+}
+
+// countEntries reads the entry counts from the daemon's index, or counts
+// them by scanning the roots when it has none.
+func (m Model) countEntries() tea.Cmd {
+	ctx := m.ctx
+	roots := m.roots()
+	exclude := m.options.Exclude
+	remote := m.options.Remote.Address != ""
+	return func() tea.Msg {
+		dirs, err := m.daemonDirEntries()
+		if err == nil || remote {
+			return dirEntriesMsg{dirs: dirs, err: err}
+		}
+		logging.Get("tui").Debug("index unavailable for entry counts, scanning", "error", err)
+
+		dirs = nil
+		for _, root := range roots {
+			totals := du.NewTotals(root, 0)
+			s := scanner.New(scanner.Options{
+				Root:    root,
+				MinSize: math.MaxInt64, // Only the counts are needed
+				Exclude: exclude,
+				OnStat:  totals.AddFile,
+				OnDir:   totals.AddDir,
+			})
+			if _, err := s.Scan(ctx); err != nil {
+				return dirEntriesMsg{err: err}
+			}
+			dirs = append(dirs, totals.Dirs()...)
+		}
+		return dirEntriesMsg{dirs: dirs}
+	}
+}
+
+// handleDirEntries shows the entry counts read. Without them, the column
+// is hidden again.
+func (m *Model) handleDirEntries(msg dirEntriesMsg) {
+	if m.treeView == nil {
+		return
+	}
+	if msg.err != nil {
+		logging.Get("tui").Warn("can't count directory entries", "error", msg.err)
+		m.treeView.showEntries = false
+		return
+	}
+	m.treeView.SetEntries(msg.dirs)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -14,9 +15,12 @@ import (
 // Parameters:
 //   - fileCount: number of large files found
 //   - totalSize: total size of large files
+//   - actualSize: totalSize with storage shared by hard links and clones counted once
 //   - freedSize: size freed in last delete operation (0 if none)
-//   - liveWatching: whether live file watching is active
-func renderAppHeader(fileCount int, totalSize int64, freedSize int64, liveWatching bool) string {
+//   - disk: usage of the scanned volume, shown as a free space gauge when known
+//   - live: whether live updates are on or reconnecting
+//   - readOnly: whether mutating actions are disabled
+func renderAppHeader(fileCount int, totalSize, actualSize, freedSize int64, disk mounts.Usage, live liveState, readOnly bool) string {
 	// Icon and app name
 	icon := "🧹"
 	appName := titleStyle.Bold(true).Render("SWEEP")
@@ -24,9 +28,13 @@ func renderAppHeader(fileCount int, totalSize int64, freedSize int64, liveWatchi
 	// Stats in muted style
 	fileCountStr := fmt.Sprintf("%d files", fileCount)
 	totalSizeStr := types.FormatSize(totalSize)
+	if actualSize < totalSize {
+		totalSizeStr += fmt.Sprintf(" (%s on disk)", types.FormatSize(actualSize))
+	}
 	stats := mutedTextStyle.Render(fmt.Sprintf("  %s  •  %s", fileCountStr, totalSizeStr))
 
 	header := fmt.Sprintf(" %s %s%s", icon, appName, stats)
+	header += renderDiskGauge(disk)
 
 	// Show freed size if any
 	if freedSize > 0 {
@@ -35,10 +43,17 @@ func renderAppHeader(fileCount int, totalSize int64, freedSize int64, liveWatchi
 		header = header + freed
 	}
 
-	// Show live indicator if watching
-	if liveWatching {
-		liveIndicator := successTextStyle.Render("  ● LIVE")
-		header = header + liveIndicator
+	// Show live indicator if watching, or that the watch is being restored
+	switch live {
+	case liveOn:
+		header = header + successTextStyle.Render("  ● LIVE")
+	case liveReconnecting:
+		header = header + lipgloss.NewStyle().Foreground(warningColor).Render("  ◌ RECONNECTING…")
+	}
+
+	if readOnly {
+		badge := lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("  READ-ONLY")
+		header = header + badge
 	}
 
 	return header
@@ -49,9 +64,10 @@ func renderAppHeader(fileCount int, totalSize int64, freedSize int64, liveWatchi
 //   - dirsScanned: number of directories scanned
 //   - filesScanned: number of files scanned
 //   - elapsed: elapsed time of the scan
+//   - estimate: how far along a running scan is, or empty when it's done
 //
 // Returns an empty string if there are no metrics to display.
-func renderScanMetrics(dirsScanned, filesScanned int64, elapsed time.Duration) string {
+func renderScanMetrics(dirsScanned, filesScanned int64, elapsed time.Duration, estimate string) string {
 	var parts []string
 
 	// Dirs and files scanned
@@ -66,6 +82,10 @@ func renderScanMetrics(dirsScanned, filesScanned int64, elapsed time.Duration) s
 		parts = append(parts, fmt.Sprintf("Time: %v", elapsed.Round(time.Millisecond)))
 	}
 
+	if estimate != "" {
+		parts = append(parts, estimate)
+	}
+
 	if len(parts) == 0 {
 		return ""
 	}
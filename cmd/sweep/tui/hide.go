@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// hidePrompt asks how to hide the directory of the file under the cursor
+// ('x'), so noise can be pruned from the list while exploring it.
+type hidePrompt struct {
+	dir   string
+	files int   // Files in the list under dir
+	size  int64 // Their total size
+}
+
+// excludeSavedMsg is sent when a hidden directory was added to the config
+// file's exclude list.
+type excludeSavedMsg struct {
+	dir  string
+	path string // The config file
+	err  error
+}
+
+// openHidePrompt opens the prompt for the directory of the file under the
+// cursor.
+func (m *Model) openHidePrompt() {
+	file, ok := m.resultModel.current()
+	if !ok {
+		return
+	}
+	p := &hidePrompt{dir: filepath.Dir(file.Path)}
+	for _, f := range m.resultModel.Files() {
+		if underDir(f.Path, p.dir) {
+			p.files++
+			p.size += f.Size
+		}
+	}
+	m.hidePrompt = p
+}
+
+// handleHidePromptKey handles keys while the prompt is open: Enter hides the
+// directory for this session, c also adds it to the config's exclude list
+// unless in read-only mode, and Esc closes the prompt.
+func (m Model) handleHidePromptKey(key string) (tea.Model, tea.Cmd) {
+	dir := m.hidePrompt.dir
+	switch key {
+	case "enter":
+		m.hidePrompt = nil
+		m.hideDir(dir)
+	case "c", "C":
+		if m.options.ConfigPath == "" {
+			return m, nil
+		}
+		if m.options.ReadOnly {
+			logReadOnly()
+			return m, nil
+		}
+		m.hidePrompt = nil
+		m.hideDir(dir)
+		return m, saveExclude(m.options.ConfigPath, dir)
+	case "esc", "x":
+		m.hidePrompt = nil
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// hideDir removes the files under dir from the list and the tree, and keeps
+// any more found there this session out, from scans, daemon pages, and
+// live updates alike.
+func (m *Model) hideDir(dir string) {
+	if !slices.Contains(m.hidden, dir) {
+		m.hidden = append(m.hidden, dir)
+		m.options.Exclude = append(slices.Clone(m.options.Exclude), dir)
+	}
+	removed := m.resultModel.RemoveDir(dir)
+	if m.treeView != nil {
+		m.treeView.RemoveDir(dir)
+	}
+	logging.Get("tui").Info("hid directory for this session", "dir", dir, "files", removed)
+}
+
+// isHidden reports whether path is under a directory hidden with 'x'.
+func (m *Model) isHidden(path string) bool {
+	for _, dir := range m.hidden {
+		if underDir(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveExclude adds dir to the exclude list of the config file at path.
+func saveExclude(path, dir string) tea.Cmd {
+	return func() tea.Msg {
+		err := config.AddListItem(path, "exclude", dir, config.DefaultExclusions)
+		return excludeSavedMsg{dir: dir, path: path, err: err}
+	}
+}
+
+// handleExcludeSaved logs the outcome of saving an exclusion.
+func (m *Model) handleExcludeSaved(msg excludeSavedMsg) {
+	log := logging.Get("tui")
+	if msg.err != nil {
+		log.Warn("failed to add exclusion to config", "dir", msg.dir, "config", msg.path, "error", msg.err)
+		return
+	}
+	log.Info("added exclusion to config", "dir", msg.dir, "config", msg.path)
+}
+
+// renderHidePrompt renders the prompt over bg.
+func (m Model) renderHidePrompt(bg string) string {
+	p := m.hidePrompt
+	var b strings.Builder
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+
+	b.WriteString(title.Render("Hide Directory?"))
+	b.WriteString("\n\n")
+	b.WriteString(truncatePath(p.dir, max(min(m.width-20, 70), 20)))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%d files, %s in the list", p.files, types.FormatSize(p.size))))
+	b.WriteString("\n\n")
+	keys := "[Enter] This session  [c] Also exclude in config  [Esc] Cancel"
+	if m.options.ConfigPath == "" || m.options.ReadOnly {
+		keys = "[Enter] Hide this session  [Esc] Cancel"
+	}
+	b.WriteString(mutedTextStyle.Render(keys))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
+
+// underDir reports whether path is inside dir.
+func underDir(path, dir string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
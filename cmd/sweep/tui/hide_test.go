@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestHideDirectory(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	m := NewModel(Options{Root: "/src", ConfigPath: configPath})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: "/src/app/node_modules/a.bin", Size: 300 * types.MiB},
+		{Path: "/src/app/build.iso", Size: 200 * types.MiB},
+		{Path: "/src/app/node_modules/b.bin", Size: 100 * types.MiB},
+		{Path: "/src/app/node_modules/deep/c.bin", Size: 50 * types.MiB},
+	})
+	press := func(key string) tea.Cmd {
+		next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+		return cmd
+	}
+
+	press("x")
+	if m.hidePrompt == nil || m.hidePrompt.dir != "/src/app/node_modules" || m.hidePrompt.files != 3 {
+		t.Fatalf("hidePrompt = %+v, want the cursor file's directory with 3 files", m.hidePrompt)
+	}
+	if view := m.View(); !strings.Contains(view, "Hide Directory?") {
+		t.Fatalf("prompt should be shown:\n%s", view)
+	}
+	press("esc")
+	if m.hidePrompt != nil || len(m.resultModel.Files()) != 4 {
+		t.Fatal("esc should close the prompt and hide nothing")
+	}
+
+	press("x")
+	cmd := press("c")
+	if files := m.resultModel.Files(); len(files) != 1 || files[0].Path != "/src/app/build.iso" {
+		t.Fatalf("files = %v, want only /src/app/build.iso", files)
+	}
+	if cmd == nil {
+		t.Fatal("c should save the exclusion")
+	}
+	if msg, ok := cmd().(excludeSavedMsg); !ok || msg.err != nil {
+		t.Fatalf("saving the exclusion = %+v", msg)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil || !strings.Contains(string(data), "- /src/app/node_modules") {
+		t.Errorf("config = %q, %v, want the directory excluded", data, err)
+	}
+
+	// Files found there later stay hidden
+	next, _ := m.Update(FileFoundMsg{File: types.FileInfo{Path: "/src/app/node_modules/d.bin", Size: types.GiB}})
+	m = next.(Model)
+	if len(m.resultModel.Files()) != 1 {
+		t.Errorf("a file under the hidden directory was added: %v", m.resultModel.Files())
+	}
+	if !strings.Contains(strings.Join(m.options.Exclude, " "), "/src/app/node_modules") {
+		t.Errorf("Exclude = %v, want the hidden directory for later daemon queries", m.options.Exclude)
+	}
+}
+
+func TestHideDirectoryReadOnly(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	m := NewModel(Options{Root: "/src", ConfigPath: configPath, ReadOnly: true})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	m.resultModel.SetFiles([]types.FileInfo{{Path: "/src/app/node_modules/a.bin", Size: types.MiB}})
+	press := func(key string) tea.Cmd {
+		next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+		return cmd
+	}
+
+	press("x")
+	if view := m.View(); strings.Contains(view, "[c]") {
+		t.Errorf("read-only mode should not offer to exclude in config:\n%s", view)
+	}
+	if cmd := press("c"); cmd != nil || m.hidePrompt == nil {
+		t.Fatal("c should be refused in read-only mode")
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Errorf("config was written in read-only mode: %v", err)
+	}
+}
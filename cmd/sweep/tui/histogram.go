@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// histogramBarWidth is the width of the largest bar in the 'H' popup.
+const histogramBarWidth = 24
+
+// sizeBucket counts the files in the results whose sizes fall in
+// [min, max); max is 0 for the last bucket, which has no upper bound.
+type sizeBucket struct {
+	label    string
+	min, max int64
+	files    int
+	size     int64
+}
+
+// sizeBuckets are the ranges the 'H' popup sorts files into, smallest
+// first.
+var sizeBuckets = []sizeBucket{
+	{label: "< 10 MiB", max: 10 * types.MiB},
+	{label: "10–100 MiB", min: 10 * types.MiB, max: 100 * types.MiB},
+	{label: "100–500 MiB", min: 100 * types.MiB, max: 500 * types.MiB},
+	{label: "500 MiB–1 GiB", min: 500 * types.MiB, max: types.GiB},
+	{label: "1–5 GiB", min: types.GiB, max: 5 * types.GiB},
+	{label: "5–10 GiB", min: 5 * types.GiB, max: 10 * types.GiB},
+	{label: "> 10 GiB", min: 10 * types.GiB},
+}
+
+// sizeHistogram is the distribution of file sizes in the results, so the
+// 'H' popup can show whether the space is in a few huge files or many
+// medium ones.
+type sizeHistogram struct {
+	buckets []sizeBucket // From the smallest with files up
+	files   int
+	size    int64
+}
+
+// buildSizeHistogram sorts files into sizeBuckets.
+func buildSizeHistogram(files []types.FileInfo) sizeHistogram {
+	buckets := make([]sizeBucket, len(sizeBuckets))
+	copy(buckets, sizeBuckets)
+	h := sizeHistogram{files: len(files)}
+	for _, f := range files {
+		i := len(buckets) - 1
+		for i > 0 && f.Size < buckets[i].min {
+			i--
+		}
+		buckets[i].files++
+		buckets[i].size += f.Size
+		h.size += f.Size
+	}
+
+	// Ranges smaller than any file, as below the minimum size, are left
+	// out
+	first := 0
+	for first < len(buckets)-1 && buckets[first].files == 0 {
+		first++
+	}
+	h.buckets = buckets[first:]
+	return h
+}
+
+// half returns the fewest files, taken from the largest bucket down, that
+// make up at least half the space, and the size of the smallest of them.
+func (h sizeHistogram) half() (files int, from int64) {
+	var size int64
+	for i := len(h.buckets) - 1; i >= 0; i-- {
+		b := h.buckets[i]
+		if b.files == 0 {
+			continue
+		}
+		files += b.files
+		size += b.size
+		from = b.min
+		if 2*size >= h.size {
+			break
+		}
+	}
+	return files, from
+}
+
+// renderHistogram renders the file size histogram popup over bg.
+func (m Model) renderHistogram(bg string) string {
+	h := buildSizeHistogram(m.resultModel.Files())
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("File sizes"))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%d files, %s", h.files, types.FormatSize(h.size))))
+	b.WriteString("\n\n")
+
+	if h.files == 0 {
+		b.WriteString(mutedTextStyle.Render("No files in the results."))
+		b.WriteString("\n")
+	} else {
+		var largest int64
+		for _, bucket := range h.buckets {
+			largest = max(largest, bucket.size)
+		}
+		bar := lipgloss.NewStyle().Foreground(sizeColor)
+		for _, bucket := range h.buckets {
+			width := int(float64(bucket.size) / float64(max(largest, 1)) * histogramBarWidth)
+			if width == 0 && bucket.size > 0 {
+				width = 1 // Show that the range isn't empty
+			}
+			pct := float64(bucket.size) / float64(max(h.size, 1)) * 100
+			b.WriteString(fmt.Sprintf("%-13s %s%s %6d files %9s %5.1f%%\n",
+				bucket.label, bar.Render(strings.Repeat("█", width)),
+				strings.Repeat(" ", histogramBarWidth-width),
+				bucket.files, types.FormatSize(bucket.size), pct))
+		}
+
+		files, from := h.half()
+		b.WriteString("\n")
+		if from > 0 {
+			b.WriteString(fmt.Sprintf("Half the space is in %d files of %s or more\n", files, types.FormatSize(from)))
+		} else {
+			b.WriteString(fmt.Sprintf("Half the space is in %d files\n", files))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("Counts the current results only  [Esc] Close"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
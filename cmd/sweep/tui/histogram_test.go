@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestBuildSizeHistogram(t *testing.T) {
+	files := []types.FileInfo{
+		{Path: "/data/a.bin", Size: 150 * types.MiB},
+		{Path: "/data/b.bin", Size: 200 * types.MiB},
+		{Path: "/data/c.bin", Size: 500 * types.MiB}, // Lower bounds are inclusive
+		{Path: "/data/d.iso", Size: 12 * types.GiB},
+	}
+
+	h := buildSizeHistogram(files)
+	if h.files != 4 || h.size != 850*types.MiB+12*types.GiB {
+		t.Errorf("totals = %d files, %d bytes", h.files, h.size)
+	}
+	// Ranges below the smallest file are left out
+	if len(h.buckets) != 5 || h.buckets[0].label != "100–500 MiB" {
+		t.Fatalf("buckets = %+v, want 100–500 MiB up", h.buckets)
+	}
+	want := map[string]int{"100–500 MiB": 2, "500 MiB–1 GiB": 1, "1–5 GiB": 0, "5–10 GiB": 0, "> 10 GiB": 1}
+	for _, b := range h.buckets {
+		if b.files != want[b.label] {
+			t.Errorf("bucket %s has %d files, want %d", b.label, b.files, want[b.label])
+		}
+	}
+
+	n, from := h.half()
+	if n != 1 || from != 10*types.GiB {
+		t.Errorf("half() = %d files from %d, want 1 from 10 GiB", n, from)
+	}
+}
+
+func TestBuildSizeHistogramEmpty(t *testing.T) {
+	h := buildSizeHistogram(nil)
+	if h.files != 0 || len(h.buckets) != 1 {
+		t.Errorf("buildSizeHistogram(nil) = %+v, want only the last bucket", h)
+	}
+}
+
+func TestHistogramKey(t *testing.T) {
+	m := NewModel(Options{Root: "/data"})
+	m.state = StateResults
+	m.resultModel.SetFiles([]types.FileInfo{{Path: "/data/big.iso", Size: 2 * types.GiB}})
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = next.(Model)
+	if !m.histogramOpen {
+		t.Fatal("expected H to open the size histogram")
+	}
+	if view := m.View(); !strings.Contains(view, "1–5 GiB") {
+		t.Errorf("expected the histogram in the view, got:\n%s", view)
+	}
+
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.histogramOpen {
+		t.Error("expected Esc to close the size histogram")
+	}
+}
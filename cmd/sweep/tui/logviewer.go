@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -28,16 +29,21 @@ func newLogRingBuffer(maxEntries int) *logRingBuffer {
 	}
 }
 
-// Add appends an entry to the buffer, evicting the oldest if at capacity.
+// Add inserts an entry in time order, evicting the oldest if at capacity.
+// Entries read from the daemon can arrive after later local ones.
 func (rb *logRingBuffer) Add(entry logging.LogEntry) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
-	if len(rb.entries) >= rb.maxEntries {
+	i := len(rb.entries)
+	for i > 0 && rb.entries[i-1].Time.After(entry.Time) {
+		i--
+	}
+	rb.entries = slices.Insert(rb.entries, i, entry)
+	if len(rb.entries) > rb.maxEntries {
 		// Remove oldest entry (FIFO)
 		rb.entries = rb.entries[1:]
 	}
-	rb.entries = append(rb.entries, entry)
 }
 
 // Entries returns a copy of all entries in chronological order.
@@ -204,18 +210,25 @@ func renderLogViewer(entries []logging.LogEntry, filterLevel logging.Level, scro
 
 // renderLogEntry renders a single log entry.
 func renderLogEntry(entry logging.LogEntry, width int) string {
-	// Format: HH:MM:SS [L] component: message
+	// Format: HH:MM:SS [L] component: message key=value
 	timeStr := entry.Time.Format("15:04:05")
 
 	levelChar := logLevelChar(entry.Level)
 	levelStyle := logLevelStyle(entry.Level)
 
+	// Truncate component if needed; entries from another process, such as
+	// the daemon, are marked with the first letter of their source
+	comp := entry.Component
+	if entry.Source != "" {
+		comp = entry.Source[:1] + ":" + comp
+	}
+	if len(comp) > 10 {
+		comp = comp[:10]
+	}
+
 	// Calculate available width for message
 	// Time(8) + space(1) + [L](3) + space(1) + component(~10) + :(1) + space(1) = ~25
-	componentWidth := 10
-	if len(entry.Component) < componentWidth {
-		componentWidth = len(entry.Component)
-	}
+	componentWidth := len(comp)
 
 	prefixWidth := 8 + 1 + 3 + 1 + componentWidth + 1 + 1 // time [L] comp:
 	msgWidth := width - prefixWidth
@@ -225,16 +238,13 @@ func renderLogEntry(entry logging.LogEntry, width int) string {
 
 	// Truncate message if needed
 	msg := entry.Message
+	for _, f := range entry.Fields {
+		msg += " " + f.Key + "=" + f.Value
+	}
 	if len(msg) > msgWidth {
 		msg = msg[:msgWidth-3] + "..."
 	}
 
-	// Truncate component if needed
-	comp := entry.Component
-	if len(comp) > 10 {
-		comp = comp[:10]
-	}
-
 	// Build the log line
 	line := fmt.Sprintf("%s %s %s: %s",
 		logTimeStyle.Render(timeStr),
@@ -254,11 +264,14 @@ type LogViewerState struct {
 	Subscription <-chan logging.LogEntry
 }
 
+// logViewerSize is how many entries the log viewer keeps.
+const logViewerSize = 100
+
 // NewLogViewerState creates a new log viewer state.
 func NewLogViewerState() *LogViewerState {
 	return &LogViewerState{
 		Open:        false,
-		Buffer:      newLogRingBuffer(100),
+		Buffer:      newLogRingBuffer(logViewerSize),
 		FilterLevel: logging.LevelDebug, // Show all by default
 	}
 }
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
 )
 
@@ -106,6 +108,70 @@ func itoa(i int) string {
 	return string(result)
 }
 
+func TestLogRingBuffer_TimeOrder(t *testing.T) {
+	now := time.Now()
+	rb := newLogRingBuffer(3)
+	rb.Add(logging.LogEntry{Time: now, Message: "local"})
+	rb.Add(logging.LogEntry{Time: now.Add(time.Second), Message: "later"})
+	rb.Add(logging.LogEntry{Time: now.Add(-time.Second), Message: "daemon", Source: "daemon"})
+	rb.Add(logging.LogEntry{Time: now.Add(-time.Minute), Message: "too old"})
+
+	var got []string
+	for _, e := range rb.Entries() {
+		got = append(got, e.Message)
+	}
+	if want := "daemon,local,later"; strings.Join(got, ",") != want {
+		t.Errorf("entries = %v, want %s", got, want)
+	}
+}
+
+func TestRenderLogEntry_DaemonFields(t *testing.T) {
+	entry := logging.LogEntry{
+		Time:      time.Now(),
+		Level:     logging.LevelWarn,
+		Component: "watcher",
+		Message:   "overflow",
+		Fields:    []logging.Field{{Key: "root", Value: "/data"}},
+		Source:    "daemon",
+	}
+	line := renderLogEntry(entry, 120)
+	if !strings.Contains(line, "d:watcher") {
+		t.Errorf("renderLogEntry() = %q, want the daemon's component marked", line)
+	}
+	if !strings.Contains(line, "overflow root=/data") {
+		t.Errorf("renderLogEntry() = %q, want the fields after the message", line)
+	}
+}
+
+func TestDaemonLogsInViewer(t *testing.T) {
+	m := NewModel(Options{Root: "/data", NoDaemon: true})
+	m.state = StateResults
+	update := func(msg tea.Msg) tea.Cmd {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return cmd
+	}
+
+	if cmd := update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")}); cmd != nil || !m.logViewer.Open {
+		t.Fatal("without the daemon the viewer should open without reading its logs")
+	}
+
+	entries := make(chan logging.LogEntry, 1)
+	cmd := update(daemonLogsMsg{entries: entries})
+	entries <- logging.LogEntry{Time: time.Now(), Level: logging.LevelInfo, Component: "indexer", Message: "indexed", Source: "daemon"}
+	update(cmd())
+	if m.logViewer.Buffer.Len() != 1 || m.statusHint != nil {
+		t.Errorf("viewer has %d entries, hint %v; want the daemon's entry and no hint", m.logViewer.Buffer.Len(), m.statusHint)
+	}
+
+	m.daemonLogs = true
+	close(entries)
+	update(m.listenForDaemonLogs()())
+	if m.daemonLogs || m.daemonLogChan != nil {
+		t.Error("reading should stop once the daemon's logs end, so opening the viewer tries again")
+	}
+}
+
 func TestLogRingBuffer_Empty(t *testing.T) {
 	rb := newLogRingBuffer(100)
 	entries := rb.Entries()
@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/relocate"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// moveView is the 'M' popup that moves the selection, or the file under
+// the cursor, to another volume instead of deleting it: first asking for
+// the destination, then showing each file's progress, then the outcome.
+type moveView struct {
+	paths   []string
+	size    int64
+	dest    string // Typed destination directory
+	symlink bool   // Leave a symlink at each old path
+
+	running  bool
+	done     int // Files finished
+	moved    int64
+	progress relocate.Progress // Of the file being copied
+	cancel   context.CancelFunc
+	updates  chan tea.Msg
+
+	report *relocate.Report // Set once finished
+	err    error            // Why the move couldn't start
+}
+
+// moveProgressMsg reports a file's copy progress, or a file finished.
+type moveProgressMsg struct {
+	progress relocate.Progress
+	result   *relocate.Result
+}
+
+// moveDoneMsg carries the outcome of a move.
+type moveDoneMsg struct {
+	report relocate.Report
+	err    error
+}
+
+// openMove opens the move popup for the selection, or the file under the
+// cursor, with the destination from move.dest.
+func (m *Model) openMove() {
+	if m.options.ReadOnly {
+		logReadOnly()
+		return
+	}
+	if m.options.Remote.Address != "" {
+		logging.Get("tui").Warn("can't move files on a remote daemon's machine")
+		return
+	}
+	v := &moveView{dest: m.options.MoveDest, symlink: m.options.MoveSymlink}
+	for _, t := range m.deleteTargets() {
+		v.paths = append(v.paths, t.Path)
+		v.size += t.Size
+	}
+	if len(v.paths) == 0 {
+		// Nothing selected: the file under the cursor
+		if m.treeMode && m.treeView != nil {
+			if node := m.treeView.Selected(); node != nil {
+				v.paths, v.size = []string{node.Path}, node.Size
+			}
+		} else if file, ok := m.resultModel.current(); ok {
+			v.paths, v.size = []string{file.Path}, file.Size
+		}
+	}
+	if len(v.paths) == 0 {
+		return
+	}
+	m.move = v
+}
+
+// handleMoveKey handles keys while the move popup is open: typing edits
+// the destination, Tab toggles the symlink, Enter starts the move or
+// closes the finished popup, and Esc cancels.
+func (m Model) handleMoveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.move
+	switch {
+	case v.report != nil || v.err != nil:
+		switch msg.String() {
+		case "enter", "esc", "M":
+			m.finishMove()
+		case "q":
+			return m, tea.Quit
+		}
+	case v.running:
+		if msg.Type == tea.KeyEsc && v.cancel != nil {
+			v.cancel()
+		}
+	default:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.move = nil
+		case tea.KeyEnter:
+			return m, m.startMove()
+		case tea.KeyTab:
+			v.symlink = !v.symlink
+		case tea.KeyBackspace:
+			if r := []rune(v.dest); len(r) > 0 {
+				v.dest = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			v.dest += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// startMove moves the files in the background, sending progress to the
+// popup as it goes.
+func (m *Model) startMove() tea.Cmd {
+	v := m.move
+	dest, err := config.ExpandPath(strings.TrimSpace(v.dest))
+	if err != nil || dest == "" {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	v.running, v.cancel = true, cancel
+	v.updates = make(chan tea.Msg, 16)
+	updates := v.updates
+	opts := relocate.Options{
+		Dest:    dest,
+		Verify:  m.options.MoveVerify,
+		Symlink: v.symlink,
+		DryRun:  m.options.DryRun,
+		OnProgress: func(p relocate.Progress) {
+			// Progress may be dropped; results may not
+			select {
+			case updates <- moveProgressMsg{progress: p}:
+			default:
+			}
+		},
+		OnDone: func(r relocate.Result) {
+			updates <- moveProgressMsg{result: &r}
+		},
+	}
+	paths := v.paths
+	crash := m.crash
+
+	logging.Get("tui").Info("move started", "count", len(paths), "dest", dest, "symlink", v.symlink, "dryRun", opts.DryRun)
+	go func() {
+		defer crash.recoverExit()
+		defer cancel()
+		report, err := relocate.Move(ctx, paths, opts)
+		updates <- moveDoneMsg{report: report, err: err}
+		close(updates)
+	}()
+	return listenForMove(updates)
+}
+
+// listenForMove waits for the next update of a move.
+func listenForMove(updates chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// handleMoveProgress updates the popup with a move's progress.
+func (m *Model) handleMoveProgress(msg moveProgressMsg) tea.Cmd {
+	v := m.move
+	if v == nil {
+		return nil
+	}
+	if r := msg.result; r != nil {
+		v.done++
+		if r.Moved() {
+			v.moved += r.Size
+		}
+		v.progress = relocate.Progress{}
+	} else {
+		v.progress = msg.progress
+	}
+	return listenForMove(v.updates)
+}
+
+// handleMoveDone shows the outcome of a move in the popup.
+func (m *Model) handleMoveDone(msg moveDoneMsg) {
+	v := m.move
+	if v == nil {
+		return
+	}
+	v.running = false
+	if msg.err != nil {
+		v.err = msg.err
+		logging.Get("tui").Warn("move failed", "error", msg.err)
+		return
+	}
+	v.report = &msg.report
+	n, size := msg.report.Moved()
+	logging.Get("tui").Info("move completed", "moved", n, "size", types.FormatSize(size), "failed", msg.report.Failed())
+	for _, r := range msg.report.Results {
+		if r.Error != "" {
+			logging.Get("tui").Warn("file not moved", "path", r.Path, "error", r.Error)
+		}
+	}
+}
+
+// finishMove closes the popup, removing the files moved from the list and
+// the tree.
+func (m *Model) finishMove() {
+	v := m.move
+	m.move = nil
+	if v.report == nil {
+		return
+	}
+	for _, r := range v.report.Results {
+		if !r.Moved() {
+			continue
+		}
+		m.resultModel.RemoveFile(r.Path)
+		if m.treeView != nil {
+			m.treeView.RemoveFile(r.Path)
+		}
+	}
+	m.resultModel.SelectNone()
+	if m.treeView != nil {
+		m.treeView.ClearSelection()
+	}
+}
+
+// renderMove renders the move popup over bg.
+func (m Model) renderMove(bg string) string {
+	v := m.move
+	var b strings.Builder
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+
+	verb := "Move"
+	if m.options.DryRun {
+		verb = "Dry run: move"
+	}
+	b.WriteString(title.Render(fmt.Sprintf("%s %d item(s), %s", verb, len(v.paths), types.FormatSize(v.size))))
+	b.WriteString("\n\n")
+
+	switch {
+	case v.err != nil:
+		b.WriteString(errorTextStyle.Render(fmt.Sprintf("Can't move: %v", v.err)))
+		b.WriteString("\n\n")
+		b.WriteString(mutedTextStyle.Render("[Enter] Close"))
+	case v.report != nil:
+		n, size := v.report.Moved()
+		if v.report.DryRun {
+			b.WriteString(fmt.Sprintf("Would move %d of %d file(s) to %s", len(v.paths)-v.report.Failed(), len(v.paths), v.report.Dest))
+		} else {
+			b.WriteString(fmt.Sprintf("Moved %d of %d file(s), %s, to %s", n, len(v.paths), types.FormatSize(size), v.report.Dest))
+		}
+		b.WriteString("\n")
+		shown := 0
+		for _, r := range v.report.Results {
+			if r.Error == "" {
+				continue
+			}
+			if shown++; shown > 5 {
+				b.WriteString(mutedTextStyle.Render(fmt.Sprintf("...and %d more in the log", v.report.Failed()-5)))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString(errorTextStyle.Render(truncatePath(filepath.Base(r.Path)+": "+r.Error, 64)))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(mutedTextStyle.Render("[Enter] Close"))
+	case v.running:
+		b.WriteString(fmt.Sprintf("%d of %d done, %s moved", v.done, len(v.paths), types.FormatSize(v.moved)))
+		b.WriteString("\n")
+		if p := v.progress; p.Path != "" {
+			pct := float64(p.Copied) / float64(max(p.Size, 1)) * 100
+			b.WriteString(mutedTextStyle.Render(fmt.Sprintf("Copying %s: %s of %s (%.0f%%)",
+				truncatePath(filepath.Base(p.Path), 32), types.FormatSize(p.Copied), types.FormatSize(p.Size), pct)))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(mutedTextStyle.Render("[Esc] Stop"))
+	default:
+		b.WriteString("To: ")
+		b.WriteString(keyStyle.Render("> "))
+		b.WriteString(v.dest)
+		b.WriteString(keyStyle.Render("█"))
+		b.WriteString("\n\n")
+		link := "[ ]"
+		if v.symlink {
+			link = "[x]"
+		}
+		b.WriteString(link + " Leave a symlink at each old path")
+		b.WriteString("\n\n")
+		b.WriteString(mutedTextStyle.Render("[Enter] Move  [Tab] Symlink  [Esc] Cancel"))
+	}
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
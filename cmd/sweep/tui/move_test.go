@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestMoveToVolume(t *testing.T) {
+	dir := t.TempDir()
+	src, archive := filepath.Join(dir, "src"), filepath.Join(dir, "archive")
+	for _, d := range []string{src, archive} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	big, other := filepath.Join(src, "big.iso"), filepath.Join(src, "other.mov")
+	for _, p := range []string{big, other} {
+		if err := os.WriteFile(p, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewModel(Options{Root: src, MoveDest: filepath.Join(dir, "arch")})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	m.resultModel.SetFiles([]types.FileInfo{
+		{Path: big, Size: 4},
+		{Path: other, Size: 4},
+	})
+	send := func(msg tea.KeyMsg) tea.Cmd {
+		next, cmd := m.handleKey(msg)
+		m = next.(Model)
+		return cmd
+	}
+	press := func(key string) tea.Cmd {
+		return send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	press("M")
+	if m.move == nil || len(m.move.paths) != 1 || m.move.paths[0] != big {
+		t.Fatalf("move = %+v, want the file under the cursor", m.move)
+	}
+	if view := m.View(); !strings.Contains(view, "Move 1 item(s)") || !strings.Contains(view, "arch") {
+		t.Fatalf("prompt should show the default destination:\n%s", view)
+	}
+
+	// Complete the destination and ask for a symlink
+	press("ive")
+	send(tea.KeyMsg{Type: tea.KeyTab})
+	if m.move.dest != archive || !m.move.symlink {
+		t.Fatalf("dest = %q, symlink = %v", m.move.dest, m.move.symlink)
+	}
+
+	cmd := send(tea.KeyMsg{Type: tea.KeyEnter})
+	for cmd != nil {
+		next, c := m.Update(cmd())
+		m = next.(Model)
+		cmd = c
+	}
+	if m.move.report == nil || m.move.report.Failed() != 0 {
+		t.Fatalf("report = %+v, want one file moved", m.move.report)
+	}
+	if view := m.View(); !strings.Contains(view, "Moved 1 of 1 file(s)") {
+		t.Fatalf("outcome should be shown:\n%s", view)
+	}
+	if target, err := os.Readlink(big); err != nil || target != filepath.Join(archive, "big.iso") {
+		t.Errorf("old path links to %q, %v", target, err)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.move != nil {
+		t.Fatal("enter should close the popup")
+	}
+	if files := m.resultModel.Files(); len(files) != 1 || files[0].Path != other {
+		t.Errorf("files = %v, want only the file not moved", files)
+	}
+}
+
+func TestMoveReadOnly(t *testing.T) {
+	m := NewModel(Options{Root: "/src", ReadOnly: true})
+	m.state = StateResults
+	m.resultModel.SetFiles([]types.FileInfo{{Path: "/src/big.iso", Size: types.GiB}})
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	if next.(Model).move != nil {
+		t.Error("M should not open the move popup in read-only mode")
+	}
+}
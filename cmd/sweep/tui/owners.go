@@ -0,0 +1,47 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+)
+
+// ownersMsg carries the owners of listed files, by path.
+type ownersMsg struct {
+	owners map[string]scanner.Ownership
+}
+
+// lookupOwners looks up the owners of listed files that have none. A direct
+// scan leaves them out to scan faster, so they are looked up once it is
+// done, only for the files it listed.
+func (m Model) lookupOwners() tea.Cmd {
+	if m.options.NoOwner || m.options.Remote.Address != "" {
+		return nil
+	}
+	var paths []string
+	for _, f := range m.resultModel.files {
+		if f.Owner == "" {
+			paths = append(paths, f.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	ctx, workers := m.ctx, m.options.FileWorkers
+	return func() tea.Msg {
+		owners := scanner.LookupOwners(ctx, paths, workers)
+		msg := ownersMsg{owners: make(map[string]scanner.Ownership, len(paths))}
+		for i, path := range paths {
+			msg.owners[path] = owners[i]
+		}
+		return msg
+	}
+}
+
+// SetOwners sets the owner and group of listed files that have none.
+func (m *ResultModel) SetOwners(owners map[string]scanner.Ownership) {
+	for i, f := range m.files {
+		if o, ok := owners[f.Path]; ok && f.Owner == "" {
+			m.files[i].Owner, m.files[i].Group = o.Owner, o.Group
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestLookupOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.iso")
+	if err := os.WriteFile(path, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(Options{Root: filepath.Dir(path), NoOwner: true})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: path, Size: 10}})
+	if cmd := m.lookupOwners(); cmd != nil {
+		t.Error("--no-owner should skip looking up owners")
+	}
+
+	m.options.NoOwner = false
+	cmd := m.lookupOwners()
+	if cmd == nil {
+		t.Fatal("owners of a direct scan's files should be looked up")
+	}
+	next, _ := m.Update(cmd())
+	m = next.(Model)
+	if f := m.resultModel.Files()[0]; f.Owner == "" || f.Group == "" {
+		t.Errorf("owner and group should be set, got %q %q", f.Owner, f.Group)
+	}
+	if cmd := m.lookupOwners(); cmd != nil {
+		t.Error("files with owners shouldn't be looked up again")
+	}
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// permanentConfirmWord must be typed in the confirm dialog before files
+// are deleted permanently, so a stray 'y' can't do it.
+const permanentConfirmWord = "delete"
+
+// permanentTargets returns the selected files that --permanent deletes
+// permanently: those under the configured permanent roots.
+func (m Model) permanentTargets() []trash.Snapshot {
+	if len(m.options.PermanentRoots) == 0 {
+		return nil
+	}
+	var targets []trash.Snapshot
+	for _, t := range m.deleteTargets() {
+		if m.options.PermanentRoots.Allows(t.Path) {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// deleteBackend returns how files not under PermanentRoots are deleted.
+func (m Model) deleteBackend() trash.Backend {
+	if m.options.Backend != nil {
+		return m.options.Backend
+	}
+	backend, _ := trash.NewBackend(trash.BackendTrash, trash.BackendOptions{})
+	return backend
+}
+
+// handleTypedConfirmKey handles keys in the confirm dialog when some of
+// the selection will be deleted permanently, or the confirm policy is
+// strict for it: Enter only deletes once permanentConfirmWord has been
+// typed.
+func (m Model) handleTypedConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.confirmInput = ""
+		m.state = StateResults
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.confirmInput) == permanentConfirmWord {
+			m.confirmInput = ""
+			return m.confirmDelete()
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.confirmInput); len(r) > 0 {
+			m.confirmInput = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes:
+		if len(m.confirmInput) < 2*len(permanentConfirmWord) {
+			m.confirmInput += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// renderPermanentConfirm renders the part of the confirm dialog that asks
+// for permanentConfirmWord, given the selection's file count.
+func (m Model) renderPermanentConfirm(permanent []trash.Snapshot, selectedCount int) string {
+	var size int64
+	for _, t := range permanent {
+		size += t.Size
+	}
+	danger := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(danger.Render(fmt.Sprintf("%d files (%s) will be deleted permanently.", len(permanent), types.FormatSize(size))))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("They are under trash.permanent_roots and skip the trash; undo can't restore them."))
+	b.WriteString("\n")
+	if rest := selectedCount - len(permanent); rest > 0 {
+		b.WriteString(mutedTextStyle.Render(fmt.Sprintf("The other %d files go to the trash.", rest)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.renderConfirmWord(danger))
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// deletePlan says how to handle volumes whose trash is over quota, and
+// which paths --permanent deletes.
+type deletePlan struct {
+	purge     []trash.Overage // Purge the oldest trash on these volumes first
+	permanent map[string]bool // Delete these paths instead of trashing them
+	bypass    map[string]bool // Under trash.permanent_roots; deleted permanently, through the daemon if running
+}
+
+// trashQuotaMsg carries the result of a trash quota check.
+type trashQuotaMsg struct {
+	overages []trash.Overage
+	err      error
+}
+
+// confirmDelete starts deletion once confirmed, first checking the trash
+// quota of each volume when one is configured. Files deleted permanently
+// with --permanent, or by a backend other than the trash, don't count
+// toward the quota.
+func (m Model) confirmDelete() (tea.Model, tea.Cmd) {
+	m.deletePlan = deletePlan{}
+	if permanent := m.permanentTargets(); len(permanent) > 0 {
+		m.deletePlan.bypass = make(map[string]bool, len(permanent))
+		for _, t := range permanent {
+			m.deletePlan.bypass[t.Path] = true
+		}
+	}
+
+	quota := m.options.TrashQuota
+	if m.options.DryRun || quota == nil || !quota.Enabled() || m.deleteBackend().Name() != trash.BackendTrash {
+		return m.startDelete()
+	}
+	var targets []trash.Snapshot
+	for _, t := range m.deleteTargets() {
+		if !m.deletePlan.bypass[t.Path] {
+			targets = append(targets, t)
+		}
+	}
+	return m, func() tea.Msg {
+		overages, err := trash.CheckQuota(*quota, targets)
+		return trashQuotaMsg{overages: overages, err: err}
+	}
+}
+
+// handleTrashQuota proceeds with deletion, or asks what to do when a
+// volume's trash would exceed its quota.
+func (m Model) handleTrashQuota(msg trashQuotaMsg) (tea.Model, tea.Cmd) {
+	if m.state != StateConfirm {
+		return m, nil // Cancelled while checking
+	}
+	if msg.err != nil {
+		// The quota is a safeguard, not a reason to refuse deleting.
+		logging.Get("tui").Warn("trash quota check failed", "error", msg.err)
+		return m.startDelete()
+	}
+	if len(msg.overages) == 0 {
+		return m.startDelete()
+	}
+	m.quotaOverages = msg.overages
+	m.state = StateQuota
+	return m, nil
+}
+
+// canPurge reports whether purging old trash makes room on every volume.
+func (m Model) canPurge() bool {
+	for _, o := range m.quotaOverages {
+		if !o.Purgeable() {
+			return false
+		}
+	}
+	return true
+}
+
+// handleQuotaKey handles keys in the trash quota dialog.
+func (m Model) handleQuotaKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "p":
+		if m.canPurge() {
+			m.deletePlan.purge = m.quotaOverages
+			return m.startDelete()
+		}
+	case "d":
+		permanent := make(map[string]bool)
+		for _, o := range m.quotaOverages {
+			for _, path := range o.Paths {
+				permanent[path] = true
+			}
+		}
+		m.deletePlan.permanent = permanent
+		return m.startDelete()
+	case "q", "esc", "n":
+		m.quotaOverages = nil
+		m.state = StateResults
+	}
+	return m, nil
+}
+
+// renderQuotaDialog renders the trash quota dialog.
+func (m Model) renderQuotaDialog() string {
+	var bg string
+	if m.treeMode && m.treeView != nil {
+		bg = m.renderTreeView()
+	} else {
+		bg = m.resultModel.View()
+	}
+
+	bold := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+	warn := lipgloss.NewStyle().Foreground(warningColor)
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+	var content strings.Builder
+	content.WriteString(bold.Render("Trash quota exceeded"))
+	content.WriteString("\n\n")
+	for _, o := range m.quotaOverages {
+		content.WriteString(fmt.Sprintf("%s: %s in trash + %s selected > %s quota",
+			o.Volume.Mount, types.FormatSize(o.Used), types.FormatSize(o.Incoming), types.FormatSize(o.Limit)))
+		content.WriteString("\n")
+		if !o.Purgeable() {
+			content.WriteString(warn.Render("  The selection alone exceeds the quota"))
+			content.WriteString("\n")
+		}
+	}
+	content.WriteString("\n")
+
+	if m.canPurge() {
+		content.WriteString(bold.Render("[p] Purge oldest trash first"))
+		content.WriteString("   ")
+	}
+	content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true).Render("[d] Delete permanently"))
+	content.WriteString("   ")
+	content.WriteString(muted.Render("[n] Cancel"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(warningColor).
+		Padding(1, 3).
+		Render(content.String())
+
+	return m.overlayDialog(bg, dialog)
+}
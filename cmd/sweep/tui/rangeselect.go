@@ -0,0 +1,78 @@
+package tui
+
+import "maps"
+
+// A range selection selects the files between an anchor and the cursor as
+// the cursor moves, on top of what was selected when it started. Shift
+// with an arrow key starts one that ends on the next plain move; v starts
+// one in visual mode, where every move extends it until v or esc.
+
+// rangeActive reports whether a range selection is being made.
+func (m ResultModel) rangeActive() bool {
+	return m.rangeBase != nil
+}
+
+// startRange anchors a range selection at the cursor.
+func (m *ResultModel) startRange(visual bool) {
+	m.anchor = m.cursor
+	m.rangeBase = maps.Clone(m.selected)
+	m.visual = visual
+}
+
+// endRange keeps what the range selected and stops extending it.
+func (m *ResultModel) endRange() {
+	m.rangeBase = nil
+	m.visual = false
+}
+
+// applyRange selects the files from the anchor to the cursor, and
+// deselects those the range no longer covers unless they were selected
+// before it started. Files in folded root sections are left out, as with
+// SelectAll.
+func (m *ResultModel) applyRange() {
+	if !m.rangeActive() || m.onHeader {
+		return
+	}
+	m.selected = maps.Clone(m.rangeBase)
+	lo, hi := min(m.anchor, m.cursor), max(m.anchor, m.cursor)
+	for i := max(lo, 0); i <= hi && i < len(m.files); i++ {
+		if m.sections == nil || !m.sections.collapsed[m.sections.rootOf(m.files[i].Path)] {
+			m.selected[i] = true
+		}
+	}
+}
+
+// SelectMatches adds the files matching the search to the selection, or
+// selects every file when there is no search.
+func (m *ResultModel) SelectMatches() {
+	if m.searchMatches == nil {
+		m.SelectAll()
+		return
+	}
+	for i, f := range m.files {
+		if m.searchMatches[f.Path] {
+			m.selected[i] = true
+		}
+	}
+}
+
+// shiftIndices returns set with the indices from idx on moved by delta, as
+// when a file is inserted (1) or removed (-1) at idx. A removed file's
+// index is dropped.
+func shiftIndices(set map[int]bool, idx, delta int) map[int]bool {
+	if set == nil {
+		return nil
+	}
+	shifted := make(map[int]bool, len(set))
+	for i, ok := range set {
+		switch {
+		case !ok:
+		case i < idx:
+			shifted[i] = true
+		case delta < 0 && i == idx:
+		default:
+			shifted[i+delta] = true
+		}
+	}
+	return shifted
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+// readOnlyVolumes finds the paths on read-only volumes; tests replace it.
+var readOnlyVolumes = mounts.ReadOnlyVolumes
+
+// deselectReadOnly deselects the selected files on read-only volumes, such
+// as mounted disk images and shares mounted ro, which can't be deleted, and
+// says why in the status bar. It reports whether any files are still
+// selected.
+func (m *Model) deselectReadOnly() bool {
+	targets := m.deleteTargets()
+	paths := make([]string, len(targets))
+	for i, t := range targets {
+		paths[i] = t.Path
+	}
+	readOnly := readOnlyVolumes(paths)
+	if len(readOnly) == 0 {
+		return len(targets) > 0
+	}
+
+	var volumes []string
+	for path, mount := range readOnly {
+		if m.treeMode && m.treeView != nil {
+			m.treeView.Deselect(path)
+		} else {
+			m.resultModel.Deselect(path)
+		}
+		if !slices.Contains(volumes, mount) {
+			volumes = append(volumes, mount)
+		}
+	}
+	slices.Sort(volumes)
+
+	on := "read-only volume " + strings.Join(volumes, ", ")
+	if len(volumes) > 1 {
+		on = "read-only volumes " + strings.Join(volumes, ", ")
+	}
+	log := logging.Get("tui")
+	if len(readOnly) == len(targets) {
+		log.Warn(fmt.Sprintf("can't delete: the selection is on %s", on))
+		return false
+	}
+	log.Warn(fmt.Sprintf("deselected %d files on %s, which can't be deleted", len(readOnly), on))
+	return true
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// liveState is what the header says about live updates from the daemon.
+type liveState int
+
+const (
+	liveOff liveState = iota
+	liveOn
+	liveReconnecting // The stream dropped and is being restored
+)
+
+// A dropped watch stream is restored after reconnectMinDelay, doubling
+// after each failed attempt up to reconnectMaxDelay, for as long as the
+// session lasts.
+const (
+	reconnectMinDelay = time.Second
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// liveRetryMsg is sent when it's time to restore the live file stream.
+type liveRetryMsg struct{}
+
+// treeRetryMsg is sent when it's time to reload the tree and restore its
+// stream.
+type treeRetryMsg struct{}
+
+// daemonResyncMsg carries the daemon's largest files under each root once
+// the live stream is restored, to catch up on events missed meanwhile.
+type daemonResyncMsg struct {
+	pages []resyncPage // By root; nil if the daemon couldn't be read
+}
+
+// resyncPage is the daemon's first page of files under one root.
+type resyncPage struct {
+	files    []types.FileInfo
+	complete bool  // Every file under the root is in files
+	minSize  int64 // Size of the smallest file in the page
+}
+
+// reconnectDelay returns how long to wait before reconnect attempt n,
+// counting from 0.
+func reconnectDelay(n int) time.Duration {
+	d := reconnectMinDelay
+	for range n {
+		d *= 2
+		if d >= reconnectMaxDelay {
+			return reconnectMaxDelay
+		}
+	}
+	return d
+}
+
+// liveState returns the list view's live indicator.
+func (m Model) liveState() liveState {
+	switch {
+	case m.liveReconnecting:
+		return liveReconnecting
+	case m.liveWatching:
+		return liveOn
+	}
+	return liveOff
+}
+
+// treeLiveState returns the tree view's live indicator.
+func (m Model) treeLiveState() liveState {
+	switch {
+	case m.treeReconnecting:
+		return liveReconnecting
+	case m.treeWatching:
+		return liveOn
+	}
+	return liveOff
+}
+
+// sessionEnded reports whether the TUI is quitting, so dropped streams
+// aren't restored.
+func (m Model) sessionEnded() bool {
+	return m.ctx != nil && m.ctx.Err() != nil
+}
+
+// handleLiveWatchStarted listens on a new live stream. A restored stream
+// also re-reads the files, since events may have been missed while it was
+// down.
+func (m *Model) handleLiveWatchStarted(msg LiveWatchStartedMsg) tea.Cmd {
+	m.liveWatching = true
+	m.liveEventChan = msg.EventChan
+	m.liveConn = msg.Conn
+	if !m.liveReconnecting {
+		logging.Get("tui").Debug("live watch started")
+		return m.listenForLiveEvents()
+	}
+	logging.Get("tui").Info("live watch reconnected", "attempts", m.liveRetries+1)
+	m.liveReconnecting = false
+	m.liveRetries = 0
+	return tea.Batch(m.listenForLiveEvents(), m.resyncFiles())
+}
+
+// handleLiveWatchError restores a live stream that dropped, or failed to
+// be restored, after a backoff. A stream that never started is given up
+// on, as the daemon isn't there to watch with.
+func (m *Model) handleLiveWatchError(err error) tea.Cmd {
+	wasLive := m.liveWatching || m.liveReconnecting
+	m.liveWatching = false
+	m.liveEventChan = nil
+	if m.liveConn != nil {
+		_ = m.liveConn.Close()
+		m.liveConn = nil
+	}
+	if !wasLive || m.sessionEnded() {
+		m.liveReconnecting = false
+		logging.Get("tui").Debug("live watch unavailable", "error", err)
+		return nil
+	}
+
+	if !m.liveReconnecting {
+		logging.Get("tui").Warn("live watch lost, reconnecting", "error", err)
+		m.liveReconnecting = true
+	} else {
+		m.liveRetries++
+	}
+	delay := reconnectDelay(m.liveRetries)
+	return tea.Tick(delay, func(time.Time) tea.Msg { return liveRetryMsg{} })
+}
+
+// handleTreeWatchStarted listens on a new tree stream. The tree was
+// reloaded before a restored stream started, so it is already current.
+func (m *Model) handleTreeWatchStarted(msg TreeWatchStartedMsg) tea.Cmd {
+	m.treeWatching = true
+	m.treeEventChan = msg.EventChan
+	m.treeConn = msg.Conn
+	if m.treeReconnecting {
+		logging.Get("tui").Info("tree watch reconnected", "attempts", m.treeRetries+1)
+		m.treeReconnecting = false
+		m.treeRetries = 0
+	} else {
+		logging.Get("tui").Debug("tree watch started")
+	}
+	return m.listenForTreeEvents()
+}
+
+// handleTreeWatchError restores a tree stream that dropped, as
+// handleLiveWatchError does for the list.
+func (m *Model) handleTreeWatchError(err error) tea.Cmd {
+	wasLive := m.treeWatching || m.treeReconnecting
+	m.treeWatching = false
+	m.treeEventChan = nil
+	if m.treeConn != nil {
+		_ = m.treeConn.Close()
+		m.treeConn = nil
+	}
+	if !wasLive || m.sessionEnded() {
+		m.treeReconnecting = false
+		logging.Get("tui").Debug("tree watch unavailable", "error", err)
+		return nil
+	}
+	if !m.treeReconnecting {
+		logging.Get("tui").Warn("tree watch lost, reconnecting", "error", err)
+		m.treeReconnecting = true
+		return m.scheduleTreeRetry()
+	}
+	return m.retryTreeWatch(err)
+}
+
+// retryTreeWatch schedules another attempt after a failed one.
+func (m *Model) retryTreeWatch(err error) tea.Cmd {
+	if m.sessionEnded() {
+		m.treeReconnecting = false
+		return nil
+	}
+	logging.Get("tui").Debug("tree watch reconnect failed", "error", err, "attempt", m.treeRetries+1)
+	m.treeRetries++
+	return m.scheduleTreeRetry()
+}
+
+// scheduleTreeRetry sends a treeRetryMsg after the backoff.
+func (m Model) scheduleTreeRetry() tea.Cmd {
+	return tea.Tick(reconnectDelay(m.treeRetries), func(time.Time) tea.Msg { return treeRetryMsg{} })
+}
+
+// applyResync brings the list up to date with the daemon's files: new and
+// changed files are added or updated, and files the daemon no longer has
+// are removed. A loaded file is only known to be gone when the daemon's
+// page would have held it, so smaller files past the page are kept.
+// Selection is kept for files still there.
+func (m *Model) applyResync(msg daemonResyncMsg) {
+	if msg.pages == nil {
+		return
+	}
+
+	fresh := make(map[string]types.FileInfo)
+	for _, page := range msg.pages {
+		for _, f := range m.applyFilterToFiles(page.files) {
+			fresh[f.Path] = f
+		}
+	}
+
+	var added, updated, removed int
+	for _, f := range append([]types.FileInfo(nil), m.resultModel.files...) {
+		if now, ok := fresh[f.Path]; ok {
+			if now.Size != f.Size || !now.ModTime.Equal(f.ModTime) {
+				m.resultModel.UpdateFile(f.Path, now.Size, now.ModTime)
+				updated++
+			}
+			delete(fresh, f.Path)
+			continue
+		}
+		root := 0
+		if m.resultModel.sections != nil {
+			root = m.resultModel.sections.rootOf(f.Path)
+		}
+		if root >= len(msg.pages) {
+			continue
+		}
+		if page := msg.pages[root]; page.complete || f.Size >= page.minSize {
+			m.resultModel.RemoveFile(f.Path)
+			removed++
+		}
+	}
+	for _, f := range fresh {
+		m.resultModel.AddFile(f)
+		added++
+	}
+	logging.Get("tui").Info("resynced files with daemon", "added", added, "updated", updated, "removed", removed)
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for n, d := range want {
+		if got := reconnectDelay(n); got != d {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", n, got, d)
+		}
+	}
+	if got := reconnectDelay(1000); got != reconnectMaxDelay {
+		t.Errorf("reconnectDelay(1000) = %v, want %v", got, reconnectMaxDelay)
+	}
+}
+
+// closer counts Close calls.
+type closer struct{ closed int }
+
+func (c *closer) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestLiveWatchReconnects(t *testing.T) {
+	m := NewModel(Options{Root: "/data", NoDaemon: true})
+	m.state = StateResults
+	m.scanDone = true
+	update := func(msg tea.Msg) tea.Cmd {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return cmd
+	}
+
+	if cmd := update(LiveWatchErrorMsg{Err: errors.New("daemon not running")}); cmd != nil || m.liveState() != liveOff {
+		t.Fatal("a watch that never started should not be retried")
+	}
+
+	conn := &closer{}
+	update(LiveWatchStartedMsg{EventChan: make(chan fileEvent), Conn: conn})
+	if m.liveState() != liveOn {
+		t.Fatalf("liveState() = %v, want liveOn", m.liveState())
+	}
+
+	if cmd := update(LiveWatchErrorMsg{Err: errors.New("live watch stream closed")}); cmd == nil {
+		t.Fatal("a dropped stream should schedule a reconnect")
+	}
+	if m.liveState() != liveReconnecting || conn.closed != 1 {
+		t.Fatalf("liveState() = %v, closed %d times; want liveReconnecting, closed once", m.liveState(), conn.closed)
+	}
+	m.width, m.height = 120, 40
+	m.resultModel.AddFile(types.FileInfo{Path: "/data/a", Size: 10})
+	if view := m.View(); !strings.Contains(view, "RECONNECTING") || strings.Contains(view, "LIVE") {
+		t.Errorf("header should say the watch is reconnecting:\n%s", view)
+	}
+
+	update(LiveWatchErrorMsg{Err: errors.New("connection refused")})
+	update(LiveWatchErrorMsg{Err: errors.New("connection refused")})
+	if m.liveRetries != 2 || !m.liveReconnecting {
+		t.Fatalf("liveRetries = %d, want 2 failed attempts while reconnecting", m.liveRetries)
+	}
+
+	if cmd := update(LiveWatchStartedMsg{EventChan: make(chan fileEvent)}); cmd == nil {
+		t.Fatal("a restored stream should listen and resync")
+	}
+	if m.liveState() != liveOn || m.liveRetries != 0 {
+		t.Errorf("liveState() = %v, liveRetries = %d; want liveOn, 0", m.liveState(), m.liveRetries)
+	}
+
+	m.liveWatching = true
+	m.cancel()
+	if cmd := update(LiveWatchErrorMsg{Err: errors.New("context canceled")}); cmd != nil || m.liveReconnecting {
+		t.Error("streams should not be restored once the session ends")
+	}
+}
+
+func TestTreeWatchReconnects(t *testing.T) {
+	m := NewModel(Options{Root: "/data", NoDaemon: true})
+	update := func(msg tea.Msg) tea.Cmd {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return cmd
+	}
+
+	update(TreeWatchStartedMsg{EventChan: make(chan treeEvent)})
+	if cmd := update(TreeWatchEndedMsg{}); cmd == nil || m.treeLiveState() != liveReconnecting {
+		t.Fatal("a closed tree stream should schedule a reconnect")
+	}
+	m.treeMode = true
+	if cmd := update(TreeErrorMsg{Err: errors.New("connection refused")}); cmd == nil || !m.treeMode || m.treeRetries != 1 {
+		t.Fatal("a failed reload while reconnecting should keep the tree and retry")
+	}
+	update(TreeWatchStartedMsg{EventChan: make(chan treeEvent)})
+	if m.treeLiveState() != liveOn || m.treeRetries != 0 {
+		t.Errorf("treeLiveState() = %v, treeRetries = %d; want liveOn, 0", m.treeLiveState(), m.treeRetries)
+	}
+}
+
+func TestApplyResync(t *testing.T) {
+	m := NewModel(Options{Root: "/data"})
+	mod := time.Unix(1000, 0)
+	for _, f := range []types.FileInfo{
+		{Path: "/data/kept", Size: 500, ModTime: mod},
+		{Path: "/data/grown", Size: 300, ModTime: mod},
+		{Path: "/data/deleted", Size: 200, ModTime: mod},
+		{Path: "/data/small", Size: 50, ModTime: mod},
+	} {
+		m.resultModel.AddFile(f)
+	}
+	m.resultModel.Toggle(0)
+
+	m.applyResync(daemonResyncMsg{pages: []resyncPage{{
+		files: []types.FileInfo{
+			{Path: "/data/grown", Size: 900, ModTime: mod.Add(time.Hour)},
+			{Path: "/data/kept", Size: 500, ModTime: mod},
+			{Path: "/data/new", Size: 400, ModTime: mod},
+			{Path: "/data/edge", Size: 100, ModTime: mod},
+		},
+		minSize: 100,
+	}}})
+
+	var got []string
+	for _, f := range m.resultModel.Files() {
+		got = append(got, f.Path)
+	}
+	want := "/data/grown /data/kept /data/new /data/edge /data/small"
+	if strings.Join(got, " ") != want {
+		t.Errorf("files = %v, want %s (files below the page are kept)", got, want)
+	}
+	if sel := m.resultModel.SelectedFiles(); len(sel) != 1 || sel[0].Path != "/data/kept" {
+		t.Errorf("selection = %v, want /data/kept", sel)
+	}
+
+	m.applyResync(daemonResyncMsg{pages: []resyncPage{{complete: true}}})
+	if n := len(m.resultModel.Files()); n != 0 {
+		t.Errorf("a complete, empty page should clear the list, %d files left", n)
+	}
+
+	m.resultModel.AddFile(types.FileInfo{Path: "/data/a", Size: 1})
+	m.applyResync(daemonResyncMsg{})
+	if n := len(m.resultModel.Files()); n != 1 {
+		t.Error("an unreadable daemon should leave the list as it is")
+	}
+}
@@ -4,13 +4,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/backup"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+	"github.com/jamesainslie/sweep/pkg/sweep/tags"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -30,7 +36,32 @@ type ResultModel struct {
 	width         int
 	height        int
 	metrics       ScanMetrics
-	lastFreedSize int64 // Size freed in last delete operation
+	lastFreedSize int64           // Size freed in last delete operation
+	diskFree      mounts.Usage    // Of the scanned volume; zero when unknown
+	tags          *tags.Store     // Optional tag store for the detail panel
+	columns       ColumnLayout    // File list columns and size units
+	readOnly      bool            // Deleting is disabled
+	backups       *backup.Checker // Optional backup lookups for the detail panel
+	ageColors     *AgeGradient    // Optional; colors file names by age
+	truncated     bool            // The daemon returned only the largest files
+	morePages     bool            // The rest are fetched as the cursor nears the end
+	sort          ListSort        // How the list is ordered
+
+	// A range selection in progress: its anchor file, the selection when
+	// it started, and whether every move extends it (visual mode)
+	anchor    int
+	rangeBase map[int]bool
+	visual    bool
+
+	// Several scan roots group the list into sections; the cursor is then
+	// on a file, or on the header of root headerRoot.
+	sections   *rootSections
+	onHeader   bool
+	headerRoot int
+
+	// A search highlights its matches and replaces the key hints
+	searchMatches map[string]bool
+	searchBar     string
 }
 
 // NewResultModel creates a new result model with the given files.
@@ -42,9 +73,17 @@ func NewResultModel(files []types.FileInfo) ResultModel {
 		offset:   0,
 		width:    80,
 		height:   24,
+		columns:  DefaultColumnLayout(),
 	}
 }
 
+// newResultModelWithTags creates an empty result model that shows tags from store.
+func newResultModelWithTags(store *tags.Store) ResultModel {
+	m := NewResultModel(nil)
+	m.tags = store
+	return m
+}
+
 // NewResultModelWithMetrics creates a new result model with files and scan metrics.
 func NewResultModelWithMetrics(files []types.FileInfo, metrics ScanMetrics) ResultModel {
 	return ResultModel{
@@ -55,6 +94,7 @@ func NewResultModelWithMetrics(files []types.FileInfo, metrics ScanMetrics) Resu
 		width:    80,
 		height:   24,
 		metrics:  metrics,
+		columns:  DefaultColumnLayout(),
 	}
 }
 
@@ -77,54 +117,102 @@ func (m ResultModel) Update(msg tea.Msg) (ResultModel, tea.Cmd) {
 
 // HandleKey handles key input for the result model.
 func (m *ResultModel) HandleKey(key string) tea.Cmd {
+	row, rows := m.cursorRow(), m.rowCount()
+	switch key {
+	case "shift+up", "shift+down":
+		if !m.rangeActive() {
+			m.startRange(false)
+		}
+		if key == "shift+up" && row > 0 {
+			m.setCursorRow(row - 1)
+		} else if key == "shift+down" && row < rows-1 {
+			m.setCursorRow(row + 1)
+		}
+		m.ensureVisible()
+		m.applyRange()
+		return nil
+	case "v":
+		if m.visual {
+			m.endRange()
+		} else {
+			m.startRange(true)
+			m.applyRange()
+		}
+		return nil
+	}
+	// Moves extend a visual range; anything else ends it
+	if !m.visual || !moveKeys[key] {
+		m.endRange()
+	}
+	defer m.applyRange()
+
 	switch key {
 	case "up", "k":
-		if m.cursor > 0 {
-			m.cursor--
+		if row > 0 {
+			m.setCursorRow(row - 1)
 			m.ensureVisible()
 		}
 	case "down", "j":
-		if m.cursor < len(m.files)-1 {
-			m.cursor++
+		if row < rows-1 {
+			m.setCursorRow(row + 1)
 			m.ensureVisible()
 		}
 	case " ":
-		m.Toggle(m.cursor)
+		if m.onHeader {
+			m.toggleSection()
+		} else {
+			m.Toggle(m.cursor)
+		}
+	case "z":
+		m.toggleSection()
 	case "a":
 		m.SelectAll()
+	case "A":
+		m.SelectMatches()
 	case "n":
 		m.SelectNone()
+	case "s":
+		order := m.sort
+		order.Cycle()
+		m.SetSort(order)
+	case "S":
+		order := m.sort
+		order.Flip()
+		m.SetSort(order)
 	case "home", "g":
-		m.cursor = 0
+		m.setCursorRow(0)
 		m.offset = 0
 	case "end", "G":
-		if len(m.files) > 0 {
-			m.cursor = len(m.files) - 1
+		if rows > 0 {
+			m.setCursorRow(rows - 1)
 			m.ensureVisible()
 		}
 	case "pgup":
-		m.cursor -= m.visibleRows()
-		if m.cursor < 0 {
-			m.cursor = 0
-		}
+		m.setCursorRow(max(row-m.visibleRows(), 0))
 		m.ensureVisible()
 	case "pgdown":
-		m.cursor += m.visibleRows()
-		if m.cursor >= len(m.files) {
-			m.cursor = len(m.files) - 1
+		if rows > 0 {
+			m.setCursorRow(min(row+m.visibleRows(), rows-1))
+			m.ensureVisible()
 		}
-		m.ensureVisible()
 	}
 	return nil
 }
 
+// moveKeys are the keys that move the cursor in the list.
+var moveKeys = map[string]bool{
+	"up": true, "k": true, "down": true, "j": true, "home": true, "g": true,
+	"end": true, "G": true, "pgup": true, "pgdown": true,
+}
+
 // ensureVisible adjusts offset to keep cursor visible.
 func (m *ResultModel) ensureVisible() {
 	visible := m.visibleRows()
-	if m.cursor < m.offset {
-		m.offset = m.cursor
-	} else if m.cursor >= m.offset+visible {
-		m.offset = m.cursor - visible + 1
+	row := m.cursorRow()
+	if row < m.offset {
+		m.offset = row
+	} else if row >= m.offset+visible {
+		m.offset = row - visible + 1
 	}
 	if m.offset < 0 {
 		m.offset = 0
@@ -215,30 +303,61 @@ func (m ResultModel) renderEmpty() string {
 
 // renderHeader renders the header.
 func (m ResultModel) renderHeader(_ int) string {
-	return renderAppHeader(len(m.files), m.TotalSize(), m.lastFreedSize, false)
+	return renderAppHeader(len(m.files), m.TotalSize(), m.ActualSize(), m.lastFreedSize, m.diskFree, liveOff, m.readOnly) + m.renderSortLabel()
+}
+
+// renderSortLabel shows how the list is ordered, after the header stats.
+func (m ResultModel) renderSortLabel() string {
+	return mutedTextStyle.Render("  •  by " + m.sort.Label())
 }
 
 // renderMetrics renders the scan metrics line.
 func (m ResultModel) renderMetrics(_ int) string {
-	return renderScanMetrics(m.metrics.DirsScanned, m.metrics.FilesScanned, m.metrics.Elapsed)
+	return renderScanMetrics(m.metrics.DirsScanned, m.metrics.FilesScanned, m.metrics.Elapsed, "")
 }
 
-// renderHelpBar renders the help bar with key hints.
+// renderHelpBar renders the help bar with key hints, or the search bar
+// during a search.
 func (m ResultModel) renderHelpBar(width int) string {
+	if m.searchBar != "" {
+		return m.searchBar
+	}
+	if m.visual {
+		badge := lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render("VISUAL")
+		return "  " + badge + "  " + strings.Join([]string{
+			renderKeyHint("j/k", "Extend", false),
+			renderKeyHint("v/Esc", "Done", false),
+		}, "  ") + mutedTextStyle.Render(fmt.Sprintf("  %d selected", m.SelectedCount()))
+	}
 	hints := []struct {
-		key  string
-		desc string
+		key      string
+		desc     string
+		disabled bool
 	}{
-		{"Space", "Toggle"},
-		{"a", "All"},
-		{"n", "None"},
-		{"Enter", "Delete"},
-		{"q", "Quit"},
+		{"Space", "Toggle", false},
+		{"v", "Range", false},
+		{"a", "All", false},
+		{"n", "None", false},
+		{"T", "Tag", false},
+		{"f", "Find", false},
+		{"s/S", "Sort", false},
+		{"H", "Sizes", false},
+		{"i", "Archive", false},
+		{"1-5", "Columns", false},
+		{"y", "Copy path", false},
+		{"M", "Move", m.readOnly},
+		{"Enter", "Delete", m.readOnly},
+		{"q", "Quit", false},
+	}
+	if m.sections != nil {
+		fold := hints[0]
+		fold.key, fold.desc = "z", "Fold root"
+		hints = slices.Insert(hints, 4, fold)
 	}
 
 	var parts []string
 	for _, h := range hints {
-		parts = append(parts, keyStyle.Render("["+h.key+"]")+" "+keyDescStyle.Render(h.desc))
+		parts = append(parts, renderKeyHint(h.key, h.desc, h.disabled))
 	}
 
 	return "  " + strings.Join(parts, "  ")
@@ -264,36 +383,57 @@ var (
 
 	// Size style - Width/Align applied at render time
 	sizeColor = lipgloss.Color("#00AAFF")
+
+	// Marks files that share storage through hard links or clones
+	sharedMarker = " ⇄"
+
+	// Marks online-only cloud placeholders, which store little locally
+	cloudMarker = " ☁"
 )
 
 // renderFileList renders the scrollable file list with full-width highlighting.
 func (m ResultModel) renderFileList(width int) string {
 	var b strings.Builder
 
-	// Header row - checkbox col (3) + size col (8) + gap (2) + filename
-	header := fmt.Sprintf("%s%s  %s", centerCell("", 3), padLeft("Size", 8), "File")
+	// Header row - checkbox col (3) + fixed columns (each followed by a 2-char gap) + file
+	header := centerCell("", 3) + m.columns.header()
 	b.WriteString(mutedTextStyle.Render(header))
 	b.WriteString("\n")
 	b.WriteString(renderDivider(width))
 	b.WriteString("\n")
 
+	// The path or name column takes whatever the fixed columns leave
+	filenameWidth := width - m.columns.fixedWidth()
+	if filenameWidth < 20 {
+		filenameWidth = 20
+	}
+
+	now := time.Now()
 	visible := m.visibleRows()
-	for i := m.offset; i < m.offset+visible && i < len(m.files); i++ {
+	rows := m.rowCount()
+	for r := m.offset; r < m.offset+visible && r < rows; r++ {
+		row := m.rowAt(r)
+		if row.file < 0 {
+			b.WriteString(m.renderRootHeader(row.root, width, m.onHeader && m.headerRoot == row.root))
+			b.WriteString("\n")
+			continue
+		}
+		i := row.file
 		file := m.files[i]
-		isCursor := i == m.cursor
+		isCursor := i == m.cursor && !m.onHeader
 		isSelected := m.selected[i]
 
-		filename := filepath.Base(file.Path)
-
-		// Calculate available width for filename
-		// Layout: checkbox(3) + size(8) + "  " + filename = 3 + 8 + 2 = 13 chars before filename
-		filenameWidth := width - 13
-		if filenameWidth < 20 {
-			filenameWidth = 20
+		// Files that share storage with others, and cloud placeholders,
+		// are marked after the name
+		var marker string
+		if file.Sharing != nil {
+			marker += sharedMarker
 		}
-		if len(filename) > filenameWidth {
-			filename = filename[:filenameWidth-3] + "..."
+		if file.Cloud != nil {
+			marker += cloudMarker
 		}
+		filename := m.columns.fileCell(file.Path, filenameWidth-lipgloss.Width(marker)) + marker
+		cells := m.columns.cells(file)
 
 		// Determine checkbox character and color
 		var checkChar string
@@ -306,24 +446,39 @@ func (m ResultModel) renderFileList(width int) string {
 			checkColor = checkboxUncheckedColor
 		}
 
-		// Checkbox with space after, then size right-aligned in 8 chars (tighter fit)
+		// Checkbox with space after, then the fixed columns
 		centeredCheck := " " + checkChar + " "
-		sizeStr := padLeft(types.FormatSize(file.Size), 8)
 
 		if isCursor {
 			// Highlighted row - plain text with background
-			row := fmt.Sprintf("%s%s  %s", centeredCheck, sizeStr, filename)
+			row := centeredCheck
+			for _, cell := range cells {
+				row += cell + "  "
+			}
+			row += filename
 			b.WriteString(rowHighlightStyle.Width(width).Render(row))
 		} else {
-			// Normal row - apply colors to pre-centered content
-			styledCheck := lipgloss.NewStyle().
+			// Normal row - apply colors to pre-padded content
+			row := lipgloss.NewStyle().
 				Foreground(checkColor).
 				Bold(isSelected).
 				Render(centeredCheck)
-			styledSize := lipgloss.NewStyle().
-				Foreground(sizeColor).
-				Render(sizeStr)
-			row := styledCheck + styledSize + "  " + filename
+			for j, c := range m.columns.fixed() {
+				switch c {
+				case ColumnSize:
+					row += lipgloss.NewStyle().Foreground(sizeColor).Render(cells[j])
+				case ColumnMtime, ColumnAge:
+					row += m.ageColors.Render(cells[j], file.ModTime, now)
+				default:
+					row += cells[j]
+				}
+				row += "  "
+			}
+			if m.searchMatches[file.Path] {
+				row += searchMatchStyle.Render(filename)
+			} else {
+				row += m.ageColors.Render(filename, file.ModTime, now)
+			}
 			b.WriteString(rowNormalStyle.Width(width).Render(row))
 		}
 		b.WriteString("\n")
@@ -331,15 +486,17 @@ func (m ResultModel) renderFileList(width int) string {
 
 	// Pad remaining rows
 	rendered := m.offset + visible
-	if rendered > len(m.files) {
-		rendered = len(m.files)
+	if rendered > rows {
+		rendered = rows
 	}
 	for i := rendered - m.offset; i < visible; i++ {
 		b.WriteString("\n")
 	}
 
-	// Detail panel for selected file
-	if m.cursor >= 0 && m.cursor < len(m.files) {
+	// Detail panel for selected file, or the root under the cursor
+	if m.onHeader && m.sections != nil {
+		b.WriteString(m.renderRootDetail(m.headerRoot, width))
+	} else if m.cursor >= 0 && m.cursor < len(m.files) {
 		b.WriteString(m.renderDetailPanel(m.files[m.cursor], width))
 	}
 
@@ -376,6 +533,9 @@ func (m ResultModel) renderDetailPanel(file types.FileInfo, width int) string {
 
 	// Metadata line
 	modTime := file.ModTime.Format("2006-01-02 15:04")
+	if !file.ModTime.IsZero() {
+		modTime += " (" + m.columns.Locale.FormatAgo(file.ModTime, time.Now()) + ")"
+	}
 	ext := filepath.Ext(file.Path)
 	if ext == "" {
 		ext = "none"
@@ -387,9 +547,25 @@ func (m ResultModel) renderDetailPanel(file types.FileInfo, width int) string {
 	if file.Owner != "" && file.Owner != "unknown" {
 		metaLine += "  |  Owner: " + file.Owner
 	}
+	if m.tags != nil {
+		if fileTags := m.tags.TagsFor(file.Path); len(fileTags) > 0 {
+			metaLine += "  |  Tags: " + strings.Join(fileTags, ", ")
+		}
+	}
+	if file.Sharing != nil {
+		metaLine += "  |  Shares storage: " + file.Sharing.String()
+	}
+	if file.Cloud != nil {
+		metaLine += fmt.Sprintf("  |  %s: %s stored locally", file.Cloud.String(), types.FormatSize(cloud.Local(file.Cloud, file.Size)))
+	}
 	b.WriteString(mutedTextStyle.Render(metaLine))
 	b.WriteString("\n")
 
+	if m.backups != nil {
+		b.WriteString(mutedTextStyle.Render(backupLine(m.backups, file)))
+		b.WriteString("\n")
+	}
+
 	return b.String()
 }
 
@@ -399,7 +575,11 @@ func (m ResultModel) renderFooter(width int) string {
 	selectedSize := m.SelectedSize()
 
 	left := fmt.Sprintf("  Selected: %d files (%s)", selectedCount, types.FormatSize(selectedSize))
+	left += m.truncatedNotice()
 	right := mutedTextStyle.Render("[↑↓] Navigate")
+	if legend := m.ageColors.Legend(); legend != "" {
+		right = legend + "  " + right
+	}
 
 	spacing := width - lipgloss.Width(left) - lipgloss.Width(right) - 2
 	if spacing < 1 {
@@ -409,6 +589,18 @@ func (m ResultModel) renderFooter(width int) string {
 	return left + strings.Repeat(" ", spacing) + right
 }
 
+// truncatedNotice tells the user when the list holds only the largest
+// files, and whether scrolling down loads more.
+func (m ResultModel) truncatedNotice() string {
+	switch {
+	case m.morePages:
+		return mutedTextStyle.Render(fmt.Sprintf(" | Largest %d loaded, more as you scroll", len(m.files)))
+	case m.truncated:
+		return mutedTextStyle.Render(fmt.Sprintf(" | Largest %d shown", len(m.files)))
+	}
+	return ""
+}
+
 // visibleRows returns the number of visible rows for the file list.
 func (m ResultModel) visibleRows() int {
 	// Outer box uses Height(m.height - 2), giving us m.height - 2 lines of content space.
@@ -421,6 +613,9 @@ func (m ResultModel) visibleRows() int {
 	// Plus outer box border reduction: 2 lines
 	// Available for file rows: m.height - 2 - 13 = m.height - 15
 	available := m.height - 15
+	if m.backups != nil {
+		available-- // Backup line of the detail panel
+	}
 	if available < 3 {
 		available = 3
 	}
@@ -439,10 +634,12 @@ func (m *ResultModel) Toggle(index int) {
 	}
 }
 
-// SelectAll selects all files.
+// SelectAll selects all files, except those in folded root sections.
 func (m *ResultModel) SelectAll() {
-	for i := range m.files {
-		m.selected[i] = true
+	for i, f := range m.files {
+		if m.sections == nil || !m.sections.collapsed[m.sections.rootOf(f.Path)] {
+			m.selected[i] = true
+		}
 	}
 }
 
@@ -451,12 +648,21 @@ func (m *ResultModel) SelectNone() {
 	m.selected = make(map[int]bool)
 }
 
-// SelectedFiles returns the list of selected files.
+// Deselect deselects the file at path.
+func (m *ResultModel) Deselect(path string) {
+	for i, file := range m.files {
+		if file.Path == path {
+			delete(m.selected, i)
+		}
+	}
+}
+
+// SelectedFiles returns the selected files in list order.
 func (m ResultModel) SelectedFiles() []types.FileInfo {
 	var result []types.FileInfo
-	for i, selected := range m.selected {
-		if selected && i < len(m.files) {
-			result = append(result, m.files[i])
+	for i, file := range m.files {
+		if m.selected[i] {
+			result = append(result, file)
 		}
 	}
 	return result
@@ -473,6 +679,18 @@ func (m ResultModel) SelectedSize() int64 {
 	return total
 }
 
+// SelectedLocalSize returns the size the selected files store locally,
+// which is what deleting them frees: cloud placeholders store little.
+func (m ResultModel) SelectedLocalSize() int64 {
+	var total int64
+	for i, selected := range m.selected {
+		if selected && i < len(m.files) {
+			total += cloud.Local(m.files[i].Cloud, m.files[i].Size)
+		}
+	}
+	return total
+}
+
 // SelectedCount returns the number of selected files.
 func (m ResultModel) SelectedCount() int {
 	return len(m.selected)
@@ -487,11 +705,30 @@ func (m ResultModel) TotalSize() int64 {
 	return total
 }
 
+// ActualSize returns the total size of all files with storage shared by
+// hard links and clones counted once, and cloud placeholders at what they
+// store locally.
+func (m ResultModel) ActualSize() int64 {
+	var usage sharing.Counter
+	for _, f := range m.files {
+		usage.Add(f.Sharing, cloud.Local(f.Cloud, f.Size))
+	}
+	return usage.Actual()
+}
+
 // Files returns the list of files.
 func (m ResultModel) Files() []types.FileInfo {
 	return m.files
 }
 
+// current returns the file under the cursor.
+func (m ResultModel) current() (types.FileInfo, bool) {
+	if m.onHeader || m.cursor < 0 || m.cursor >= len(m.files) {
+		return types.FileInfo{}, false
+	}
+	return m.files[m.cursor], true
+}
+
 // Cursor returns the current cursor position.
 func (m ResultModel) Cursor() int {
 	return m.cursor
@@ -513,17 +750,33 @@ func (m *ResultModel) SetLastFreedSize(size int64) {
 	m.lastFreedSize = size
 }
 
+// SetTruncated records whether the daemon left out smaller files that
+// matched, and whether they can still be fetched.
+func (m *ResultModel) SetTruncated(truncated, morePages bool) {
+	m.truncated = truncated
+	m.morePages = morePages
+}
+
 // LastFreedSize returns the size freed in the last delete operation.
 func (m ResultModel) LastFreedSize() int64 {
 	return m.lastFreedSize
 }
 
-// AddFile inserts a file in sorted position (by size descending).
+// less reports whether a sorts before b in the list.
+func (m ResultModel) less(a, b types.FileInfo) bool {
+	if m.sections != nil {
+		return m.sections.less(a, b, m.sort)
+	}
+	return m.sort.less(a, b)
+}
+
+// AddFile inserts a file in sorted position (within its root's section
+// when there are several roots).
 // This method is used for streaming results as files are found.
 func (m *ResultModel) AddFile(file types.FileInfo) {
-	// Find insertion point using binary search (largest first).
+	// Find insertion point using binary search.
 	idx := sort.Search(len(m.files), func(i int) bool {
-		return m.files[i].Size <= file.Size
+		return !m.less(m.files[i], file)
 	})
 
 	// Insert at the found position.
@@ -532,36 +785,64 @@ func (m *ResultModel) AddFile(file types.FileInfo) {
 	m.files[idx] = file
 
 	// Update selected indices for files that shifted.
-	newSelected := make(map[int]bool)
-	for i, selected := range m.selected {
-		if selected {
-			if i >= idx {
-				newSelected[i+1] = true
-			} else {
-				newSelected[i] = true
-			}
-		}
-	}
-	m.selected = newSelected
+	m.selected = shiftIndices(m.selected, idx, 1)
+	m.rangeBase = shiftIndices(m.rangeBase, idx, 1)
 
 	// Keep cursor visible after insertion.
 	if m.cursor >= idx {
 		m.cursor++
 	}
+	if m.anchor >= idx {
+		m.anchor++
+	}
 }
 
-// SetFiles replaces all files at once, sorting by size descending.
+// SetFiles replaces all files at once, sorting them.
 // This is O(n log n) vs O(n²) for calling AddFile repeatedly.
 // Use this for batch loading (e.g., from daemon).
 func (m *ResultModel) SetFiles(files []types.FileInfo) {
-	// Sort by size descending
+	// Sort by root first when there are several
 	sort.Slice(files, func(i, j int) bool {
-		return files[i].Size > files[j].Size
+		return m.less(files[i], files[j])
 	})
 	m.files = files
 	m.selected = make(map[int]bool)
 	m.cursor = 0
 	m.offset = 0
+	m.endRange()
+}
+
+// Sort returns how the list is ordered.
+func (m ResultModel) Sort() ListSort {
+	return m.sort
+}
+
+// SetSort reorders the list, keeping the selected files selected and the
+// cursor on the same file.
+func (m *ResultModel) SetSort(order ListSort) {
+	m.endRange()
+	m.sort = order
+	cursorPath := ""
+	if file, ok := m.current(); ok && !m.onHeader {
+		cursorPath = file.Path
+	}
+	selected := make(map[string]bool, len(m.selected))
+	for i := range m.selected {
+		selected[m.files[i].Path] = true
+	}
+	sort.Slice(m.files, func(i, j int) bool {
+		return m.less(m.files[i], m.files[j])
+	})
+	m.selected = make(map[int]bool, len(selected))
+	for i, f := range m.files {
+		if selected[f.Path] {
+			m.selected[i] = true
+		}
+		if f.Path == cursorPath {
+			m.cursor = i
+		}
+	}
+	m.ensureVisible()
 }
 
 // UpdateFile updates a file's size and mod time, re-sorting if needed.
@@ -587,13 +868,13 @@ func (m *ResultModel) UpdateFile(path string, newSize int64, modTime time.Time)
 		return
 	}
 
-	// Check if size changed significantly enough to require re-sorting.
-	oldSize := m.files[idx].Size
+	// Check if the change can move the file in the list.
+	old := m.files[idx]
 	m.files[idx].Size = newSize
 	m.files[idx].ModTime = modTime
 
-	if oldSize == newSize {
-		return // No size change, no need to re-sort.
+	if !m.sort.changed(old, m.files[idx]) {
+		return // Still in order, no need to re-sort.
 	}
 
 	// Re-sort by removing and re-adding.
@@ -638,6 +919,66 @@ func (m *ResultModel) RemoveFile(path string) {
 	m.removeFileAtIndex(idx)
 }
 
+// RemoveDir removes the files under dir from the results and returns how
+// many there were.
+func (m *ResultModel) RemoveDir(dir string) int {
+	removed := 0
+	for i := len(m.files) - 1; i >= 0; i-- {
+		if underDir(m.files[i].Path, dir) {
+			m.removeFileAtIndex(i)
+			removed++
+		}
+	}
+	m.ensureVisible()
+	return removed
+}
+
+// RenameDir updates the paths of files under a renamed directory.
+// Returns the number of files moved.
+func (m *ResultModel) RenameDir(oldDir, newDir string) int {
+	prefix := oldDir + string(filepath.Separator)
+	moved := 0
+	for i, f := range m.files {
+		if strings.HasPrefix(f.Path, prefix) {
+			m.files[i].Path = newDir + f.Path[len(oldDir):]
+			moved++
+		}
+	}
+	return moved
+}
+
+// MoveCursorTo puts the cursor on the file at path, unfolding its root's
+// section if folded. It reports whether the file is in the list.
+func (m *ResultModel) MoveCursorTo(path string) bool {
+	idx := slices.IndexFunc(m.files, func(f types.FileInfo) bool { return f.Path == path })
+	if idx < 0 {
+		return false
+	}
+	if m.sections != nil {
+		m.sections.collapsed[m.sections.rootOf(path)] = false
+	}
+	m.onHeader = false
+	m.cursor = idx
+	m.ensureVisible()
+	return true
+}
+
+// ScrollTo puts the cursor on the file at path, if it is loaded, and
+// scrolls offset rows down, as far as the cursor stays in view.
+func (m *ResultModel) ScrollTo(path string, offset int) bool {
+	if !m.MoveCursorTo(path) {
+		return false
+	}
+	m.offset = offset
+	m.ensureVisible()
+	return true
+}
+
+// Offset returns how many rows the list is scrolled down.
+func (m ResultModel) Offset() int {
+	return m.offset
+}
+
 // removeFileAtIndex removes a file at the specified index.
 func (m *ResultModel) removeFileAtIndex(idx int) {
 	if idx < 0 || idx >= len(m.files) {
@@ -648,18 +989,11 @@ func (m *ResultModel) removeFileAtIndex(idx int) {
 	m.files = append(m.files[:idx], m.files[idx+1:]...)
 
 	// Update selected indices for files that shifted.
-	newSelected := make(map[int]bool)
-	for i, selected := range m.selected {
-		if selected {
-			if i < idx {
-				newSelected[i] = true
-			} else if i > idx {
-				newSelected[i-1] = true
-			}
-			// If i == idx, it's being removed so don't add to newSelected.
-		}
+	m.selected = shiftIndices(m.selected, idx, -1)
+	m.rangeBase = shiftIndices(m.rangeBase, idx, -1)
+	if m.anchor > idx {
+		m.anchor--
 	}
-	m.selected = newSelected
 
 	// Adjust cursor if needed.
 	if m.cursor > idx {
@@ -671,7 +1005,7 @@ func (m *ResultModel) removeFileAtIndex(idx int) {
 
 // ViewWithProgress renders the results with scan progress information in the footer.
 func (m ResultModel) ViewWithProgress(progress ScanProgress) string {
-	return m.ViewWithProgressAndNotifications(progress, nil, false, nil)
+	return m.ViewWithProgressAndNotifications(progress, nil, liveOff, nil)
 }
 
 // ViewWithProgressAndNotifications renders the results with progress, notifications, live status,
@@ -679,7 +1013,7 @@ func (m ResultModel) ViewWithProgress(progress ScanProgress) string {
 func (m ResultModel) ViewWithProgressAndNotifications(
 	progress ScanProgress,
 	notifications []Notification,
-	liveWatching bool,
+	live liveState,
 	statusHint *logging.LogEntry,
 ) string {
 	// Show empty state only when scan is complete and no files found
@@ -699,7 +1033,7 @@ func (m ResultModel) ViewWithProgressAndNotifications(
 	b.WriteString("\n")
 
 	// Header with live indicator.
-	b.WriteString(m.renderHeaderWithLive(contentWidth, liveWatching))
+	b.WriteString(m.renderHeaderWithLive(contentWidth, live))
 	b.WriteString("\n")
 
 	// Metrics line (if available or scanning).
@@ -749,8 +1083,8 @@ func (m ResultModel) ViewWithProgressAndNotifications(
 }
 
 // renderHeaderWithLive renders the header with an optional live indicator.
-func (m ResultModel) renderHeaderWithLive(_ int, liveWatching bool) string {
-	return renderAppHeader(len(m.files), m.TotalSize(), m.lastFreedSize, liveWatching)
+func (m ResultModel) renderHeaderWithLive(_ int, live liveState) string {
+	return renderAppHeader(len(m.files), m.TotalSize(), m.ActualSize(), m.lastFreedSize, m.diskFree, live, m.readOnly) + m.renderSortLabel()
 }
 
 // Notification icons (Unicode symbols, not emoji).
@@ -836,7 +1170,7 @@ func (m ResultModel) renderMetricsWithProgress(_ int, progress ScanProgress) str
 		elapsed = m.metrics.Elapsed
 	}
 
-	return renderScanMetrics(dirsScanned, filesScanned, elapsed)
+	return renderScanMetrics(dirsScanned, filesScanned, elapsed, progress.estimate())
 }
 
 // renderFooterWithProgressAndHint renders the footer with selection summary, scan status, and status hint.
@@ -852,6 +1186,7 @@ func (m ResultModel) renderFooterWithProgressAndHint(width int, progress ScanPro
 	} else {
 		left = fmt.Sprintf("  Selected: %d files (%s)", selectedCount, types.FormatSize(selectedSize))
 	}
+	left += m.truncatedNotice()
 
 	// If we have a status hint, show it instead of navigation hint
 	var right string
@@ -859,6 +1194,9 @@ func (m ResultModel) renderFooterWithProgressAndHint(width int, progress ScanPro
 		right = renderStatusHint(statusHint, width-lipgloss.Width(left)-4)
 	} else {
 		right = mutedTextStyle.Render("[" + string(rune(0x2191)) + string(rune(0x2193)) + "] Navigate")
+		if legend := m.ageColors.Legend(); legend != "" {
+			right = legend + "  " + right
+		}
 	}
 
 	spacing := width - lipgloss.Width(left) - lipgloss.Width(right) - 2
@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -124,6 +129,81 @@ func TestResultModelTotalSize(t *testing.T) {
 	}
 }
 
+func TestResultModelSharedStorage(t *testing.T) {
+	link := &sharing.Info{Dev: 1, Ino: 42, Links: 2}
+	files := []types.FileInfo{
+		{Path: "/test/disk.img", Size: 300 * types.MiB, ModTime: time.Now(), Sharing: link},
+		{Path: "/test/copy/disk.img", Size: 300 * types.MiB, ModTime: time.Now(), Sharing: link},
+		{Path: "/test/other.bin", Size: 100 * types.MiB, ModTime: time.Now()},
+	}
+
+	m := NewResultModel(files)
+	m.SetDimensions(120, 24)
+
+	if m.TotalSize() != 700*types.MiB {
+		t.Errorf("expected apparent size %d, got %d", 700*types.MiB, m.TotalSize())
+	}
+	if m.ActualSize() != 400*types.MiB {
+		t.Errorf("expected hard links counted once, got %d", m.ActualSize())
+	}
+
+	view := m.View()
+	for _, want := range []string{"(400 MiB on disk)", "disk.img" + sharedMarker, "Shares storage: 2 hard links"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+	if strings.Contains(view, "other.bin"+sharedMarker) {
+		t.Error("unshared file should not be marked")
+	}
+}
+
+func TestResultModelCloudPlaceholders(t *testing.T) {
+	files := []types.FileInfo{
+		{Path: "/test/film.mov", Size: 4 * types.GiB, ModTime: time.Now(), Cloud: &cloud.Info{Provider: cloud.ProviderICloud}},
+		{Path: "/test/local.bin", Size: 100 * types.MiB, ModTime: time.Now()},
+	}
+
+	m := NewResultModel(files)
+	m.SetDimensions(120, 24)
+	if m.ActualSize() != 100*types.MiB {
+		t.Errorf("expected placeholders counted at their local size, got %d", m.ActualSize())
+	}
+	m.SelectAll()
+	if m.SelectedLocalSize() != 100*types.MiB {
+		t.Errorf("SelectedLocalSize() = %d, want only the local file", m.SelectedLocalSize())
+	}
+
+	view := m.View()
+	for _, want := range []string{"(100 MiB on disk)", "film.mov" + cloudMarker, "iCloud, online only: 0 B stored locally"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+	if strings.Contains(view, "local.bin"+cloudMarker) {
+		t.Error("local file should not be marked")
+	}
+}
+
+func TestResultModelRenameDir(t *testing.T) {
+	files := []types.FileInfo{
+		{Path: "/test/photos/a.raw", Size: 300 * types.MiB},
+		{Path: "/test/photos2/b.raw", Size: 200 * types.MiB},
+		{Path: "/test/photos/2024/c.raw", Size: 100 * types.MiB},
+	}
+	m := NewResultModel(files)
+
+	if moved := m.RenameDir("/test/photos", "/test/pictures"); moved != 2 {
+		t.Errorf("expected 2 files moved, got %d", moved)
+	}
+	want := []string{"/test/pictures/a.raw", "/test/photos2/b.raw", "/test/pictures/2024/c.raw"}
+	for i, f := range m.Files() {
+		if f.Path != want[i] {
+			t.Errorf("file %d: got %s, want %s", i, f.Path, want[i])
+		}
+	}
+}
+
 func TestResultModelSelectedFiles(t *testing.T) {
 	files := []types.FileInfo{
 		{Path: "/test/file1.txt", Size: 100 * types.MiB},
@@ -272,3 +352,225 @@ func TestResultModelEmptyView(t *testing.T) {
 		t.Error("expected non-empty view for empty file list")
 	}
 }
+
+func TestResultModelTruncatedFooter(t *testing.T) {
+	files := []types.FileInfo{
+		{Path: "/test/a.bin", Size: 200 * types.MiB, ModTime: time.Now()},
+		{Path: "/test/b.bin", Size: 100 * types.MiB, ModTime: time.Now()},
+	}
+
+	m := NewResultModel(files)
+	m.SetDimensions(120, 24)
+	if strings.Contains(m.renderFooter(120), "Largest") {
+		t.Error("complete results should not be marked as truncated")
+	}
+
+	m.SetTruncated(true, false)
+	for name, footer := range map[string]string{
+		"footer":          m.renderFooter(120),
+		"progress footer": m.renderFooterWithProgressAndHint(120, ScanProgress{}, nil),
+	} {
+		if !strings.Contains(footer, "Largest 2 shown") {
+			t.Errorf("%s = %q, want the truncation notice", name, footer)
+		}
+	}
+
+	m.SetTruncated(true, true)
+	if footer := m.renderFooter(120); !strings.Contains(footer, "more as you scroll") {
+		t.Errorf("footer = %q, want a notice that more files load", footer)
+	}
+}
+
+func TestResultModelRootSections(t *testing.T) {
+	m := NewResultModel(nil)
+	m.SetDimensions(120, 40)
+	m.SetRoots([]string{"/movies", "/downloads"})
+	m.SetFiles([]types.FileInfo{
+		{Path: "/downloads/a.dmg", Size: 300 * types.MiB},
+		{Path: "/movies/b.mkv", Size: 100 * types.MiB},
+		{Path: "/movies/c.mkv", Size: 200 * types.MiB},
+	})
+	m.AddFile(types.FileInfo{Path: "/downloads/d.zip", Size: 50 * types.MiB})
+
+	var order []string
+	for _, f := range m.files {
+		order = append(order, f.Path)
+	}
+	want := []string{"/movies/c.mkv", "/movies/b.mkv", "/downloads/a.dmg", "/downloads/d.zip"}
+	if strings.Join(order, " ") != strings.Join(want, " ") {
+		t.Fatalf("files = %v, want grouped by root then largest first: %v", order, want)
+	}
+	if got := m.rowCount(); got != 6 {
+		t.Errorf("rowCount() = %d, want 4 files and 2 headers", got)
+	}
+
+	// The cursor starts on the first header; moving down reaches its files
+	if _, ok := m.current(); ok {
+		t.Error("expected no current file on a root header")
+	}
+	m.HandleKey("down")
+	if f, _ := m.current(); f.Path != "/movies/c.mkv" {
+		t.Errorf("current() = %s after down, want /movies/c.mkv", f.Path)
+	}
+
+	// Folding a root hides its files and leaves the cursor on its header
+	m.HandleKey("z")
+	if !m.onHeader || m.headerRoot != 0 {
+		t.Fatalf("cursor on header %v of root %d, want root 0", m.onHeader, m.headerRoot)
+	}
+	if got := m.rowCount(); got != 4 {
+		t.Errorf("rowCount() = %d with /movies folded, want 4", got)
+	}
+	m.HandleKey("down")
+	if !m.onHeader || m.headerRoot != 1 {
+		t.Errorf("down from a folded root went to %v/%d, want the next header", m.onHeader, m.headerRoot)
+	}
+	if root, remaining := m.CursorRoot(); root != 1 || remaining != 2 {
+		t.Errorf("CursorRoot() = %d, %d, want 1, 2", root, remaining)
+	}
+
+	// Select all leaves the folded root's files alone
+	m.SelectAll()
+	for _, f := range m.SelectedFiles() {
+		if strings.HasPrefix(f.Path, "/movies/") {
+			t.Errorf("SelectAll() selected %s in a folded root", f.Path)
+		}
+	}
+
+	view := m.View()
+	for _, s := range []string{"▸ /movies", "▾ /downloads", "2 files"} {
+		if !strings.Contains(view, s) {
+			t.Errorf("view is missing %q", s)
+		}
+	}
+}
+
+func TestResultModelRangeSelection(t *testing.T) {
+	var files []types.FileInfo
+	for i := range 6 {
+		files = append(files, types.FileInfo{Path: fmt.Sprintf("/test/file%d.bin", i), Size: int64(600-i*100) * types.MiB})
+	}
+	selected := func(m ResultModel) []int {
+		var idx []int
+		for i := range m.files {
+			if m.selected[i] {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	}
+
+	m := NewResultModel(files)
+	m.Toggle(5)
+	m.HandleKey("shift+down")
+	m.HandleKey("shift+down")
+	if got := selected(m); !slices.Equal(got, []int{0, 1, 2, 5}) {
+		t.Errorf("shift+down twice selected %v, want [0 1 2 5]", got)
+	}
+	m.HandleKey("shift+up")
+	if got := selected(m); !slices.Equal(got, []int{0, 1, 5}) {
+		t.Errorf("shift+up should shrink the range, got %v", got)
+	}
+	m.HandleKey("down") // Ends the range
+	m.HandleKey("down")
+	if got := selected(m); !slices.Equal(got, []int{0, 1, 5}) {
+		t.Errorf("a plain move should keep the range, got %v", got)
+	}
+
+	// Visual mode extends with every move until v
+	m.SelectNone()
+	m.HandleKey("v")
+	m.HandleKey("j")
+	m.HandleKey("j")
+	if got := selected(m); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Errorf("visual mode selected %v, want [3 4 5]", got)
+	}
+	if !strings.Contains(m.renderHelpBar(120), "VISUAL") {
+		t.Error("help bar should show visual mode")
+	}
+	m.HandleKey("v")
+	m.HandleKey("g")
+	if got := selected(m); !slices.Equal(got, []int{3, 4, 5}) || m.visual {
+		t.Errorf("v should end visual mode keeping %v, got %v", []int{3, 4, 5}, got)
+	}
+
+	// A file streamed in shifts the range with the files
+	m.HandleKey("v")
+	m.HandleKey("j")
+	m.AddFile(types.FileInfo{Path: "/test/huge.bin", Size: types.GiB})
+	m.HandleKey("j")
+	if got := selected(m); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("range after an insert = %v, want [1 2 3 4 5 6]", got)
+	}
+
+	// A selects the search's matches
+	m.HandleKey("n")
+	m.searchMatches = map[string]bool{"/test/file1.bin": true, "/test/file4.bin": true}
+	m.HandleKey("A")
+	if got := m.SelectedFiles(); len(got) != 2 || got[0].Path != "/test/file1.bin" || got[1].Path != "/test/file4.bin" {
+		t.Errorf("A selected %v, want the 2 matches", got)
+	}
+}
+
+func TestResultModelSort(t *testing.T) {
+	now := time.Now()
+	m := NewResultModel(nil)
+	m.SetDimensions(120, 40)
+	m.SetFiles([]types.FileInfo{
+		{Path: "/b/zeta.iso", Size: 300 * types.MiB, ModTime: now.Add(-time.Hour)},
+		{Path: "/a/Beta.mkv", Size: 100 * types.MiB, ModTime: now.Add(-72 * time.Hour)},
+		{Path: "/c/alpha.zip", Size: 200 * types.MiB, ModTime: now.Add(-24 * time.Hour)},
+	})
+	paths := func() string {
+		var order []string
+		for _, f := range m.files {
+			order = append(order, f.Path)
+		}
+		return strings.Join(order, " ")
+	}
+	if got, want := paths(), "/b/zeta.iso /c/alpha.zip /a/Beta.mkv"; got != want {
+		t.Fatalf("files = %s, want largest first: %s", got, want)
+	}
+
+	// The selection and cursor follow their files to new places
+	m.HandleKey("down")
+	m.HandleKey(" ")
+
+	tests := []struct {
+		key   string
+		want  string
+		label string
+	}{
+		{"s", "/a/Beta.mkv /c/alpha.zip /b/zeta.iso", "age (oldest first)"},
+		{"S", "/b/zeta.iso /c/alpha.zip /a/Beta.mkv", "age (newest first)"},
+		{"s", "/a/Beta.mkv /b/zeta.iso /c/alpha.zip", "path (A–Z)"},
+		{"s", "/c/alpha.zip /a/Beta.mkv /b/zeta.iso", "name (A–Z)"},
+		{"S", "/b/zeta.iso /a/Beta.mkv /c/alpha.zip", "name (Z–A)"},
+		{"s", "/b/zeta.iso /c/alpha.zip /a/Beta.mkv", "size (largest first)"},
+	}
+	for _, tt := range tests {
+		m.HandleKey(tt.key)
+		if got := paths(); got != tt.want {
+			t.Errorf("after %s files = %s, want %s", tt.key, got, tt.want)
+		}
+		if got := m.Sort().Label(); got != tt.label {
+			t.Errorf("after %s label = %q, want %q", tt.key, got, tt.label)
+		}
+		if f, _ := m.current(); f.Path != "/c/alpha.zip" {
+			t.Errorf("after %s cursor on %s, want /c/alpha.zip", tt.key, f.Path)
+		}
+		if sel := m.SelectedFiles(); len(sel) != 1 || sel[0].Path != "/c/alpha.zip" {
+			t.Errorf("after %s selected = %v, want /c/alpha.zip", tt.key, sel)
+		}
+	}
+	if !strings.Contains(m.View(), "by size (largest first)") {
+		t.Error("view is missing the sort in the header")
+	}
+
+	// Streamed files are inserted in the current order
+	m.SetSort(ListSort{Key: SortName})
+	m.AddFile(types.FileInfo{Path: "/d/gamma.tar", Size: types.MiB})
+	if got, want := paths(), "/c/alpha.zip /a/Beta.mkv /d/gamma.tar /b/zeta.iso"; got != want {
+		t.Errorf("files = %s after AddFile, want %s", got, want)
+	}
+}
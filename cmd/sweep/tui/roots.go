@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// rootSections groups the result list by scan root when several roots are
+// scanned. Files are kept ordered by root, then by the list's sort, and each root has
+// a header row that folds or unfolds its files.
+type rootSections struct {
+	roots     []string
+	prefixes  [][]string // Each root as given and with symlinks resolved
+	collapsed []bool
+}
+
+// newRootSections returns sections for roots, or nil for a single root,
+// whose list has no headers.
+func newRootSections(roots []string) *rootSections {
+	if len(roots) < 2 {
+		return nil
+	}
+	s := &rootSections{
+		roots:     roots,
+		prefixes:  make([][]string, len(roots)),
+		collapsed: make([]bool, len(roots)),
+	}
+	for i, root := range roots {
+		s.prefixes[i] = []string{root}
+		// The daemon reports paths with symlinks resolved
+		if resolved, err := filepath.EvalSymlinks(root); err == nil && resolved != root {
+			s.prefixes[i] = append(s.prefixes[i], resolved)
+		}
+	}
+	return s
+}
+
+// rootOf returns the index of the root path is under, or 0 if none is.
+func (s *rootSections) rootOf(path string) int {
+	for i, prefixes := range s.prefixes {
+		for _, p := range prefixes {
+			if path == p || strings.HasPrefix(path, strings.TrimSuffix(p, string(filepath.Separator))+string(filepath.Separator)) {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// less orders files by root, then by order.
+func (s *rootSections) less(a, b types.FileInfo, order ListSort) bool {
+	ra, rb := s.rootOf(a.Path), s.rootOf(b.Path)
+	if ra != rb {
+		return ra < rb
+	}
+	return order.less(a, b)
+}
+
+// bounds returns where each root's files start in files, which are ordered
+// by root, followed by len(files).
+func (s *rootSections) bounds(files []types.FileInfo) []int {
+	bounds := make([]int, len(s.roots)+1)
+	for i := 1; i < len(s.roots); i++ {
+		bounds[i] = sort.Search(len(files), func(j int) bool {
+			return s.rootOf(files[j].Path) >= i
+		})
+	}
+	bounds[len(s.roots)] = len(files)
+	return bounds
+}
+
+// listRow is a row of the result list: a file, or a root's header.
+type listRow struct {
+	root int
+	file int // Index into the files; -1 for the root's header
+}
+
+// rowCount returns the number of rows in the file list.
+func (m ResultModel) rowCount() int {
+	if m.sections == nil {
+		return len(m.files)
+	}
+	n := len(m.sections.roots)
+	bounds := m.sections.bounds(m.files)
+	for i, collapsed := range m.sections.collapsed {
+		if !collapsed {
+			n += bounds[i+1] - bounds[i]
+		}
+	}
+	return n
+}
+
+// rowAt returns the row at position r of the file list.
+func (m ResultModel) rowAt(r int) listRow {
+	if m.sections == nil {
+		return listRow{file: r}
+	}
+	bounds := m.sections.bounds(m.files)
+	pos := 0
+	for i, collapsed := range m.sections.collapsed {
+		if r == pos {
+			return listRow{root: i, file: -1}
+		}
+		pos++
+		if collapsed {
+			continue
+		}
+		if n := bounds[i+1] - bounds[i]; r < pos+n {
+			return listRow{root: i, file: bounds[i] + r - pos}
+		}
+		pos += bounds[i+1] - bounds[i]
+	}
+	return listRow{root: len(m.sections.roots) - 1, file: -1}
+}
+
+// cursorRow returns the position of the cursor in the file list.
+func (m ResultModel) cursorRow() int {
+	if m.sections == nil {
+		return m.cursor
+	}
+	bounds := m.sections.bounds(m.files)
+	pos := 0
+	for i, collapsed := range m.sections.collapsed {
+		if m.onHeader && m.headerRoot == i {
+			return pos
+		}
+		pos++
+		if collapsed {
+			continue
+		}
+		if !m.onHeader && m.cursor >= bounds[i] && m.cursor < bounds[i+1] {
+			return pos + m.cursor - bounds[i]
+		}
+		pos += bounds[i+1] - bounds[i]
+	}
+	return 0
+}
+
+// setCursorRow moves the cursor to position r of the file list.
+func (m *ResultModel) setCursorRow(r int) {
+	row := m.rowAt(r)
+	if row.file < 0 {
+		m.onHeader = true
+		m.headerRoot = row.root
+		return
+	}
+	m.onHeader = false
+	m.cursor = row.file
+}
+
+// toggleSection folds or unfolds the root under the cursor, leaving the
+// cursor on its header.
+func (m *ResultModel) toggleSection() {
+	if m.sections == nil {
+		return
+	}
+	root := m.headerRoot
+	if !m.onHeader {
+		root = m.sections.rootOf(m.files[min(m.cursor, len(m.files)-1)].Path)
+	}
+	m.sections.collapsed[root] = !m.sections.collapsed[root]
+	m.onHeader = true
+	m.headerRoot = root
+	m.ensureVisible()
+}
+
+// SetRoots groups the list by roots when there are several.
+func (m *ResultModel) SetRoots(roots []string) {
+	m.sections = newRootSections(roots)
+	if m.sections != nil {
+		m.SetFiles(m.files)
+		m.onHeader = true
+	}
+}
+
+// CursorRoot returns the index of the root the cursor is in, and how many
+// files follow the cursor in that root's part of the list.
+func (m ResultModel) CursorRoot() (root, remaining int) {
+	if m.sections == nil {
+		return 0, len(m.files) - m.cursor
+	}
+	root = m.headerRoot
+	if !m.onHeader && len(m.files) > 0 {
+		root = m.sections.rootOf(m.files[m.cursor].Path)
+	}
+	bounds := m.sections.bounds(m.files)
+	if m.onHeader {
+		return root, bounds[root+1] - bounds[root]
+	}
+	return root, bounds[root+1] - m.cursor
+}
+
+// renderRootHeader renders the header row of a root's section.
+func (m ResultModel) renderRootHeader(root int, width int, isCursor bool) string {
+	bounds := m.sections.bounds(m.files)
+	var size int64
+	for _, f := range m.files[bounds[root]:bounds[root+1]] {
+		size += f.Size
+	}
+	marker := "▾"
+	if m.sections.collapsed[root] {
+		marker = "▸"
+	}
+	row := fmt.Sprintf(" %s %s  %d files, %s", marker, m.sections.roots[root],
+		bounds[root+1]-bounds[root], types.FormatSize(size))
+	if isCursor {
+		return rowHighlightStyle.Width(width).Render(row)
+	}
+	return lipgloss.NewStyle().Foreground(sizeColor).Bold(true).Width(width).Render(row)
+}
+
+// renderRootDetail renders the detail panel for a root's header, with as
+// many lines as a file's.
+func (m ResultModel) renderRootDetail(root int, width int) string {
+	var b strings.Builder
+	b.WriteString(renderDivider(width))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("  Root: " + m.sections.roots[root]))
+	b.WriteString("\n")
+	hint := "  [z] or [Space] folds this root's files"
+	if m.sections.collapsed[root] {
+		hint = "  [z] or [Space] shows this root's files"
+	}
+	b.WriteString(mutedTextStyle.Render(hint))
+	b.WriteString("\n")
+	if m.backups != nil {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+)
+
+// maxSearchLength bounds the query typed into the search bar.
+const maxSearchLength = 100
+
+// searchState is an incremental fuzzy search over the loaded files, or
+// the tree's nodes in tree mode. The query is typed into a bar in place of
+// the key hints; once it's closed with enter, the matches stay highlighted
+// and ] and [ jump between them.
+type searchState struct {
+	Open    bool // Typing the query
+	Query   string
+	Matches []string // Matching paths, in display order
+	Current int      // Index in Matches of the match under the cursor
+	Tree    bool     // Matches are of the tree's nodes
+}
+
+// active reports whether there is a search to show.
+func (s searchState) active() bool {
+	return s.Open || s.Query != ""
+}
+
+// openSearch opens the search bar, keeping the last query to refine.
+func (m *Model) openSearch() {
+	m.search.Open = true
+	m.updateSearch(false)
+}
+
+// handleSearchKey handles keys while the search bar is open. Typing
+// searches as you go and jumps to the best match; up and down move
+// between matches; enter keeps the matches and returns to the list; esc
+// ends the search.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.clearSearch()
+	case tea.KeyEnter:
+		m.search.Open = false
+		m.syncSearch()
+	case tea.KeyUp, tea.KeyCtrlP, tea.KeyShiftTab:
+		m.jumpMatch(-1)
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		m.jumpMatch(1)
+	case tea.KeyBackspace:
+		if r := []rune(m.search.Query); len(r) > 0 {
+			m.search.Query = string(r[:len(r)-1])
+			m.updateSearch(true)
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(m.search.Query)) < maxSearchLength {
+			m.search.Query += string(msg.Runes)
+			m.updateSearch(true)
+		}
+	}
+	return m, m.fetchPageNearEnd()
+}
+
+// clearSearch ends the search and removes its highlights.
+func (m *Model) clearSearch() {
+	m.search = searchState{}
+	m.syncSearch()
+}
+
+// updateSearch finds the matches of the query in the current view. With
+// jump set, the cursor moves to the best match.
+func (m *Model) updateSearch(jump bool) {
+	m.search.Tree = m.treeMode && m.treeView != nil
+	m.search.Matches = nil
+	m.search.Current = 0
+
+	if strings.TrimSpace(m.search.Query) != "" {
+		best := -1
+		add := func(path string) {
+			score, ok := fuzzyMatch(m.search.Query, path)
+			if !ok {
+				return
+			}
+			if score > best {
+				best = score
+				m.search.Current = len(m.search.Matches)
+			}
+			m.search.Matches = append(m.search.Matches, path)
+		}
+		if m.search.Tree {
+			walkTree(m.treeView.root, func(n *tree.Node) { add(n.Path) })
+		} else {
+			for _, f := range m.resultModel.files {
+				add(f.Path)
+			}
+		}
+	}
+
+	if jump && len(m.search.Matches) > 0 {
+		m.moveToMatch()
+	}
+	m.syncSearch()
+}
+
+// jumpMatch moves the cursor delta matches on, wrapping around. Matches
+// are found again if the view changed between the list and the tree.
+func (m *Model) jumpMatch(delta int) {
+	if m.search.Tree != (m.treeMode && m.treeView != nil) {
+		m.updateSearch(false)
+	}
+	n := len(m.search.Matches)
+	if n == 0 {
+		return
+	}
+	m.search.Current = ((m.search.Current+delta)%n + n) % n
+	m.moveToMatch()
+	m.syncSearch()
+}
+
+// moveToMatch puts the cursor on the current match.
+func (m *Model) moveToMatch() {
+	path := m.search.Matches[m.search.Current]
+	if m.search.Tree {
+		m.treeView.Reveal(path)
+		return
+	}
+	m.resultModel.MoveCursorTo(path)
+}
+
+// syncSearch passes the matches and the search bar to the views.
+func (m *Model) syncSearch() {
+	var matches map[string]bool
+	if m.search.Query != "" {
+		matches = make(map[string]bool, len(m.search.Matches))
+		for _, path := range m.search.Matches {
+			matches[path] = true
+		}
+	}
+	m.resultModel.searchMatches, m.resultModel.searchBar = nil, ""
+	if m.treeView != nil {
+		m.treeView.matches = nil
+	}
+	if !m.search.active() {
+		return
+	}
+	if m.search.Tree {
+		m.treeView.matches = matches
+	} else {
+		m.resultModel.searchMatches = matches
+		m.resultModel.searchBar = m.renderSearchBar()
+	}
+}
+
+// renderSearchBar renders the query and where the cursor is among the
+// matches, with the keys that apply.
+func (m Model) renderSearchBar() string {
+	var b strings.Builder
+	b.WriteString("  ")
+	b.WriteString(keyStyle.Render("Find: "))
+	b.WriteString(m.search.Query)
+	if m.search.Open {
+		b.WriteString(keyStyle.Render("█"))
+	}
+
+	var count string
+	switch {
+	case strings.TrimSpace(m.search.Query) == "":
+		count = ""
+	case len(m.search.Matches) == 0:
+		count = "no matches"
+	default:
+		count = fmt.Sprintf("%d/%d", m.search.Current+1, len(m.search.Matches))
+	}
+	if count != "" {
+		b.WriteString("  ")
+		b.WriteString(mutedTextStyle.Render(count))
+	}
+
+	hints := []string{renderKeyHint("↑↓", "Matches", false), renderKeyHint("Enter", "Done", false)}
+	if !m.search.Open {
+		hints = []string{renderKeyHint("]", "Next", false), renderKeyHint("[", "Previous", false), renderKeyHint("f", "Edit", false)}
+		if !m.search.Tree {
+			hints = append(hints, renderKeyHint("A", "Select all", false))
+		}
+	}
+	hints = append(hints, renderKeyHint("Esc", "Clear", false))
+	b.WriteString("    ")
+	b.WriteString(strings.Join(hints, "  "))
+	return b.String()
+}
+
+// searchMatchStyle marks the names of matching rows.
+var searchMatchStyle = lipgloss.NewStyle().Foreground(warningColor).Bold(true)
+
+// walkTree calls fn for node and every node beneath it, in display order.
+func walkTree(node *tree.Node, fn func(*tree.Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for _, child := range node.Children {
+		walkTree(child, fn)
+	}
+}
+
+// fuzzyMatch reports whether s matches pattern, and how well. Each
+// space-separated term of pattern must appear in s with its characters in
+// order, ignoring case, though not necessarily next to each other, so
+// "dl iso" matches "~/Downloads/old/ubuntu.iso". Characters that follow
+// one another, start a word, or are in the file name score higher.
+func fuzzyMatch(pattern, s string) (int, bool) {
+	terms := strings.Fields(strings.ToLower(pattern))
+	if len(terms) == 0 {
+		return 0, false
+	}
+	text := []rune(strings.ToLower(s))
+	base := strings.LastIndexAny(s, `/\`) + 1
+	base = len([]rune(s[:base]))
+
+	total := 0
+	for _, term := range terms {
+		score, ok := matchTerm([]rune(term), text, base)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	// Among equal matches, prefer shorter paths
+	return total*1000 - min(len(text), 999), true
+}
+
+// matchTerm scores the best of the two ways to match term in text:
+// starting from the left, or from the left of the file name.
+func matchTerm(term, text []rune, base int) (int, bool) {
+	score, ok := scoreFrom(term, text, 0, base)
+	if inName, nameOK := scoreFrom(term, text, base, base); nameOK && (!ok || inName > score) {
+		return inName, true
+	}
+	return score, ok
+}
+
+// scoreFrom matches the characters of term in order in text from start,
+// each as early as possible, and scores the match.
+func scoreFrom(term, text []rune, start, base int) (int, bool) {
+	score, prev := 0, -2
+	i := start
+	for _, c := range term {
+		for i < len(text) && text[i] != c {
+			i++
+		}
+		if i == len(text) {
+			return 0, false
+		}
+		score++
+		if i == prev+1 {
+			score += 5
+		}
+		if i == 0 || isWordBoundary(text[i-1]) {
+			score += 3
+		}
+		if i >= base {
+			score += 2
+		}
+		prev = i
+		i++
+	}
+	return score, true
+}
+
+// isWordBoundary reports whether r separates words in a path.
+func isWordBoundary(r rune) bool {
+	return r == '/' || r == '\\' || r == '.' || r == '_' || r == '-' || unicode.IsSpace(r)
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"iso", "/home/me/Downloads/old/ubuntu.iso", true},
+		{"dl iso", "/home/me/Downloads/old/ubuntu.iso", true},
+		{"DLISO", "/home/me/Downloads/old/ubuntu.iso", true},
+		{"osi", "/home/me/Downloads/old/ubuntu.iso", true},
+		{"iso dmg", "/home/me/Downloads/old/ubuntu.iso", false},
+		{"zzz", "/home/me/Downloads/old/ubuntu.iso", false},
+		{"  ", "/home/me/a", false},
+	}
+	for _, tt := range tests {
+		if _, got := fuzzyMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+
+	score := func(pattern, path string) int {
+		s, ok := fuzzyMatch(pattern, path)
+		if !ok {
+			t.Fatalf("fuzzyMatch(%q, %q) should match", pattern, path)
+		}
+		return s
+	}
+	if score("iso", "/v/ubuntu.iso") <= score("iso", "/isolated/vm.img") {
+		t.Error("a match in the file name should beat one in a directory")
+	}
+	if score("ubu", "/v/ubuntu.iso") <= score("ubu", "/v/u_b_u.iso") {
+		t.Error("consecutive characters should beat scattered ones")
+	}
+	if score("a.iso", "/d/a.iso") <= score("a.iso", "/deeper/d/a.iso") {
+		t.Error("equal matches should prefer the shorter path")
+	}
+}
+
+func TestSearchList(t *testing.T) {
+	m := NewModel(Options{Root: "/home/me"})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	for _, f := range []types.FileInfo{
+		{Path: "/home/me/Movies/big.mkv", Size: 900},
+		{Path: "/home/me/Downloads/old/ubuntu.iso", Size: 500},
+		{Path: "/home/me/Downloads/debian.iso", Size: 400},
+		{Path: "/home/me/Music/album.flac", Size: 100},
+	} {
+		m.resultModel.AddFile(f)
+	}
+	press := func(msg tea.KeyMsg) {
+		next, _ := m.handleKey(msg)
+		m = next.(Model)
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	cursorPath := func() string { return m.resultModel.files[m.resultModel.Cursor()].Path }
+
+	typeText("f")
+	if !m.search.Open {
+		t.Fatal("f should open the search bar")
+	}
+	typeText("ubu")
+	if got := cursorPath(); got != "/home/me/Downloads/old/ubuntu.iso" {
+		t.Fatalf("cursor on %s, want the best match", got)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("iso")
+	if len(m.search.Matches) != 2 {
+		t.Fatalf("matches = %v, want the two ISOs", m.search.Matches)
+	}
+	if view := m.View(); !strings.Contains(view, "Find: iso") || !strings.Contains(view, "/2") {
+		t.Errorf("search bar should show the query and match count:\n%s", view)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.search.Open || len(m.search.Matches) != 2 {
+		t.Fatal("enter should close the bar and keep the matches")
+	}
+	first := cursorPath()
+	typeText("]")
+	if cursorPath() == first || !strings.HasSuffix(cursorPath(), ".iso") {
+		t.Errorf("] should jump to the other match, cursor on %s", cursorPath())
+	}
+	typeText("]")
+	if cursorPath() != first {
+		t.Error("] should wrap around to the first match")
+	}
+	typeText("[")
+	if cursorPath() == first {
+		t.Error("[ should jump back")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.search.active() || m.resultModel.searchMatches != nil || m.resultModel.searchBar != "" {
+		t.Error("esc should end the search, not quit")
+	}
+}
+
+func TestSearchTree(t *testing.T) {
+	m := NewModel(Options{Root: "/test"})
+	m.state = StateResults
+	m.treeView = NewTreeView(createTestTree())
+	m.treeMode = true
+
+	m.openSearch()
+	for _, r := range "file2" {
+		next, _ := m.handleSearchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(Model)
+	}
+
+	node := m.treeView.Selected()
+	if node == nil || node.Path != "/test/dir1/file2.txt" {
+		t.Fatalf("cursor on %v, want the match inside the collapsed directory", node)
+	}
+	if !m.treeView.matches["/test/dir1/file2.txt"] {
+		t.Error("the match should be highlighted")
+	}
+	if view := m.renderTreeHintsBar(80); !strings.Contains(view, "Find: file2") {
+		t.Errorf("tree hints should show the search bar: %s", view)
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+)
+
+// sessionKey identifies the session by the roots being scanned.
+func (m Model) sessionKey() string {
+	if len(m.options.Roots) > 0 {
+		return session.Key(m.options.Roots...)
+	}
+	return session.Key(m.options.Root)
+}
+
+// loadSession looks up where the last session on the same roots was left,
+// to resume there once the list and the tree have loaded.
+func (m *Model) loadSession() {
+	if m.options.Sessions == nil {
+		return
+	}
+	state, ok := m.options.Sessions.Get(m.sessionKey())
+	if !ok {
+		return
+	}
+	m.resumeList, m.resumeTree = &state, &state
+	// Sorted from the start, so files are listed in order as they load
+	m.resultModel.sort = ListSort{Key: state.ListSort, Reverse: state.ListReverse}
+}
+
+// resumeListSession puts the list's cursor back on the file it was left
+// on, if that file is still listed.
+func (m *Model) resumeListSession() {
+	s := m.resumeList
+	if s == nil {
+		return
+	}
+	m.resumeList = nil
+	if s.ListCursor != "" {
+		m.resultModel.ScrollTo(s.ListCursor, s.ListOffset)
+	}
+}
+
+// resumeTreeSession expands the tree's directories and puts its cursor
+// back as they were left, and returns to the tree or the treemap if the
+// session ended there.
+func (m *Model) resumeTreeSession() {
+	s := m.resumeTree
+	if s == nil || m.treeView == nil {
+		return
+	}
+	m.resumeTree = nil
+	m.treeView.Expand(s.Expanded)
+	if s.TreeCursor != "" {
+		m.treeView.ScrollTo(s.TreeCursor, s.TreeOffset)
+	}
+	switch s.View {
+	case session.ViewTree:
+		m.treeMode = true
+	case session.ViewTreemap:
+		m.openTreemap()
+	}
+}
+
+// saveSession records where the TUI was left, for the next session on the
+// same roots. Whatever hadn't loaded yet keeps its place from the session
+// before, and nothing is recorded before anything is shown.
+func (m Model) saveSession() {
+	if m.options.Sessions == nil || (len(m.resultModel.files) == 0 && m.treeView == nil) {
+		return
+	}
+
+	state := session.State{View: session.ViewList, Roots: m.options.Roots}
+	if len(state.Roots) == 0 {
+		state.Roots = []string{m.options.Root}
+	}
+	switch {
+	case m.treemapMode:
+		state.View = session.ViewTreemap
+	case m.treeMode && m.treeView != nil:
+		state.View = session.ViewTree
+	}
+
+	state.ListSort, state.ListReverse = m.resultModel.sort.Key, m.resultModel.sort.Reverse
+	if s := m.resumeList; s != nil {
+		state.ListCursor, state.ListOffset = s.ListCursor, s.ListOffset
+	} else if file, ok := m.resultModel.current(); ok {
+		state.ListCursor, state.ListOffset = file.Path, m.resultModel.Offset()
+	}
+
+	if s := m.resumeTree; s != nil {
+		state.TreeCursor, state.TreeOffset, state.Expanded = s.TreeCursor, s.TreeOffset, s.Expanded
+		if m.treeView == nil {
+			// The tree never loaded, so the view it was left in still holds
+			state.View = s.View
+		}
+	} else if m.treeView != nil {
+		if node := m.treeView.Selected(); node != nil {
+			state.TreeCursor = node.Path
+		}
+		state.TreeOffset = m.treeView.offset
+		state.Expanded = m.treeView.ExpandedDirs()
+	}
+
+	if err := m.options.Sessions.Put(m.sessionKey(), state); err != nil {
+		logging.Get("tui").Warn("failed to save session", "error", err)
+	}
+}
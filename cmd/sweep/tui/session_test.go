@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/session"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestSessionResumes(t *testing.T) {
+	store, err := session.Open(filepath.Join(t.TempDir(), "sessions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []types.FileInfo{
+		{Path: "/test/dir1/file1.txt", Size: 300},
+		{Path: "/test/dir1/file2.txt", Size: 200},
+		{Path: "/test/dir2/file3.txt", Size: 100},
+	}
+	open := func() Model {
+		m := NewModel(Options{Root: "/test", Sessions: store})
+		for _, f := range files {
+			m.resultModel.AddFile(f)
+		}
+		return m
+	}
+
+	// Nothing is saved before anything is shown
+	NewModel(Options{Root: "/test", Sessions: store}).saveSession()
+	if _, ok := store.Get(session.Key("/test")); ok {
+		t.Fatal("an empty session should not be saved")
+	}
+
+	m := open()
+	m.resultModel.SetSort(ListSort{Key: SortName, Reverse: true})
+	m.resultModel.MoveCursorTo("/test/dir1/file2.txt")
+	m.treeView = NewTreeView(createTestTree())
+	m.treeView.Reveal("/test/dir1/file2.txt")
+	m.treeMode = true
+	m.saveSession()
+
+	m = open()
+	if m.treeMode || m.resumeList == nil || m.resumeTree == nil {
+		t.Fatal("the session should be resumed only once the views load")
+	}
+	next, _ := m.Update(ScanDoneMsg{})
+	m = next.(Model)
+	if got := m.resultModel.files[m.resultModel.Cursor()].Path; got != "/test/dir1/file2.txt" {
+		t.Errorf("list cursor on %s, want where it was left", got)
+	}
+	if got := m.resultModel.Sort(); got != (ListSort{Key: SortName, Reverse: true}) {
+		t.Errorf("list sort = %+v, want the one it was left with", got)
+	}
+	if got := m.resultModel.files[0].Path; got != "/test/dir2/file3.txt" {
+		t.Errorf("first file = %s, want the last by name", got)
+	}
+
+	m.treeView = NewTreeView(createTestTree())
+	m.resumeTreeSession()
+	if !m.treeMode {
+		t.Error("the tree view should be restored")
+	}
+	if node := m.treeView.Selected(); node == nil || node.Path != "/test/dir1/file2.txt" {
+		t.Errorf("tree cursor on %v, want the file inside the expanded directory", node)
+	}
+	if !slices.Contains(m.treeView.ExpandedDirs(), "/test/dir1") {
+		t.Error("expanded directories should be restored")
+	}
+
+	// Quitting before the tree loads keeps the tree's place
+	m = open()
+	m.saveSession()
+	if s, _ := store.Get(session.Key("/test")); s.View != session.ViewTree || s.TreeCursor != "/test/dir1/file2.txt" || !slices.Equal(s.Roots, []string{"/test"}) {
+		t.Errorf("saved %+v, want the tree's place kept", s)
+	}
+}
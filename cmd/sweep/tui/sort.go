@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"cmp"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// List sort keys, in the order s cycles through them.
+const (
+	SortSize = "size" // Largest first
+	SortAge  = "age"  // Oldest first
+	SortPath = "path" // Full path, A to Z
+	SortName = "name" // Base name, A to Z
+)
+
+var sortKeys = []string{SortSize, SortAge, SortPath, SortName}
+
+// ListSort is how the flat result list is ordered. The zero value sorts by
+// size, largest first.
+type ListSort struct {
+	Key     string
+	Reverse bool // Flips the key's natural order
+}
+
+// Cycle switches to the next sort key in its natural order.
+func (s *ListSort) Cycle() {
+	next := SortSize
+	for i, key := range sortKeys {
+		if key == s.key() {
+			next = sortKeys[(i+1)%len(sortKeys)]
+		}
+	}
+	s.Key, s.Reverse = next, false
+}
+
+// Flip reverses the order.
+func (s *ListSort) Flip() {
+	s.Reverse = !s.Reverse
+}
+
+func (s ListSort) key() string {
+	if s.Key == "" {
+		return SortSize
+	}
+	return s.Key
+}
+
+// compare orders a before b when negative. Ties fall back to the path, so
+// files stay in a stable order whichever way they were inserted.
+func (s ListSort) compare(a, b types.FileInfo) int {
+	c := 0
+	switch s.key() {
+	case SortSize:
+		c = cmp.Compare(b.Size, a.Size)
+	case SortAge:
+		c = a.ModTime.Compare(b.ModTime)
+	case SortName:
+		c = strings.Compare(strings.ToLower(filepath.Base(a.Path)), strings.ToLower(filepath.Base(b.Path)))
+	}
+	if c == 0 {
+		c = strings.Compare(a.Path, b.Path)
+	}
+	if s.Reverse {
+		return -c
+	}
+	return c
+}
+
+// less reports whether a sorts before b.
+func (s ListSort) less(a, b types.FileInfo) bool {
+	return s.compare(a, b) < 0
+}
+
+// changed reports whether a file's position may move from old to updated.
+func (s ListSort) changed(old, updated types.FileInfo) bool {
+	switch s.key() {
+	case SortSize:
+		return old.Size != updated.Size
+	case SortAge:
+		return !old.ModTime.Equal(updated.ModTime)
+	}
+	return false
+}
+
+// sortLabels describe each key's natural order, then its reverse.
+var sortLabels = map[string][2]string{
+	SortSize: {"largest first", "smallest first"},
+	SortAge:  {"oldest first", "newest first"},
+	SortPath: {"A–Z", "Z–A"},
+	SortName: {"A–Z", "Z–A"},
+}
+
+// Label describes the order for the header, e.g. "size (largest first)".
+func (s ListSort) Label() string {
+	labels := sortLabels[s.key()]
+	if s.Reverse {
+		return s.key() + " (" + labels[1] + ")"
+	}
+	return s.key() + " (" + labels[0] + ")"
+}
@@ -94,8 +94,21 @@ var (
 	// keyDescStyle for key descriptions.
 	keyDescStyle = lipgloss.NewStyle().
 			Foreground(mutedColor)
+
+	// disabledKeyStyle for hints of actions unavailable in read-only mode.
+	disabledKeyStyle = lipgloss.NewStyle().
+				Foreground(subtleColor).
+				Strikethrough(true)
 )
 
+// renderKeyHint renders a "[key] desc" hint, greyed out when disabled.
+func renderKeyHint(key, desc string, disabled bool) string {
+	if disabled {
+		return disabledKeyStyle.Render("[" + key + "] " + desc)
+	}
+	return keyStyle.Render("["+key+"]") + " " + keyDescStyle.Render(desc)
+}
+
 // renderDivider creates a horizontal divider line.
 func renderDivider(width int) string {
 	return dividerStyle.Render(repeatChar('─', width))
@@ -132,6 +145,14 @@ func padLeft(s string, width int) string {
 	return repeatChar(' ', width-len(s)) + s
 }
 
+// padRight pads a string on the right to the given width.
+func padRight(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + repeatChar(' ', width-len(s))
+}
+
 // center centers a string within the given width (uses byte length).
 func center(s string, width int) string {
 	if len(s) >= width {
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// watchSuggestion is the daemon's suggestion to keep watching a directory
+// the user looks at often, shown as a prompt over the results.
+type watchSuggestion struct {
+	path string
+	uses int
+}
+
+// watchSuggestionMsg carries the directory the daemon suggests watching
+// for the scanned roots; an empty path means none.
+type watchSuggestionMsg struct {
+	path string
+	uses int
+}
+
+// watchSuggestionDoneMsg is sent when the daemon has the user's answer.
+type watchSuggestionDoneMsg struct {
+	path  string
+	watch bool
+	err   error
+}
+
+// handleWatchSuggestion opens the prompt, once per session.
+func (m *Model) handleWatchSuggestion(msg watchSuggestionMsg) {
+	if msg.path == "" || m.watchSuggested {
+		return
+	}
+	m.watchSuggested = true
+	m.watchSuggestion = &watchSuggestion{path: msg.path, uses: msg.uses}
+}
+
+// handleWatchSuggestionKey handles keys while the prompt is open: y saves
+// the directory as a watch, n asks the daemon not to suggest it again, and
+// esc closes the prompt, to be asked again another session.
+func (m Model) handleWatchSuggestionKey(key string) (tea.Model, tea.Cmd) {
+	path := m.watchSuggestion.path
+	switch key {
+	case "y", "Y":
+		m.watchSuggestion = nil
+		return m, m.answerWatchSuggestion(path, true)
+	case "n", "N":
+		m.watchSuggestion = nil
+		return m, m.answerWatchSuggestion(path, false)
+	case "esc":
+		m.watchSuggestion = nil
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// handleWatchSuggestionDone logs the outcome of answering the prompt.
+func (m *Model) handleWatchSuggestionDone(msg watchSuggestionDoneMsg) {
+	log := logging.Get("tui")
+	switch {
+	case msg.err != nil:
+		log.Warn("failed to answer watch suggestion", "path", msg.path, "watch", msg.watch, "error", msg.err)
+	case msg.watch:
+		log.Info("watching suggested directory", "path", msg.path)
+	default:
+		log.Info("watch suggestion dismissed", "path", msg.path)
+	}
+}
+
+// renderWatchSuggestion renders the prompt over bg.
+func (m Model) renderWatchSuggestion(bg string) string {
+	w := m.watchSuggestion
+	var b strings.Builder
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
+
+	b.WriteString(title.Render("Keep Watching?"))
+	b.WriteString("\n\n")
+	b.WriteString(truncatePath(w.path, max(min(m.width-20, 70), 20)))
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render(fmt.Sprintf("Looked at in %d sessions since sweepd started.", w.uses)))
+	b.WriteString("\n\n")
+	b.WriteString("Keep it indexed and watched when sweepd restarts,\n")
+	b.WriteString("so it opens instantly?")
+	b.WriteString("\n\n")
+	b.WriteString(mutedTextStyle.Render("[y] Watch  [n] Don't ask again  [Esc] Not now"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
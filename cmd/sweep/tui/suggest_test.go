@@ -0,0 +1,53 @@
+//go:build !lite
+
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWatchSuggestionPrompt(t *testing.T) {
+	m := NewModel(Options{Root: "/src/app"})
+	m.state = StateResults
+	m.width, m.height = 120, 40
+	update := func(msg tea.Msg) {
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	press := func(key string) tea.Cmd {
+		next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(Model)
+		return cmd
+	}
+
+	update(watchSuggestionMsg{})
+	if m.watchSuggestion != nil {
+		t.Fatal("nothing to suggest should open no prompt")
+	}
+
+	update(watchSuggestionMsg{path: "/src", uses: 3})
+	if view := m.View(); !strings.Contains(view, "Keep Watching?") || !strings.Contains(view, "/src") {
+		t.Fatalf("prompt should show the suggested directory:\n%s", view)
+	}
+	if cmd := press("j"); cmd != nil || m.watchSuggestion == nil {
+		t.Error("other keys should leave the prompt open")
+	}
+	press("esc")
+	if m.watchSuggestion != nil {
+		t.Fatal("esc should close the prompt")
+	}
+
+	update(watchSuggestionMsg{path: "/src", uses: 4})
+	if m.watchSuggestion != nil {
+		t.Error("the prompt should be offered once a session")
+	}
+
+	m.watchSuggested = false
+	update(watchSuggestionMsg{path: "/src", uses: 4})
+	if cmd := press("y"); cmd == nil || m.watchSuggestion != nil {
+		t.Error("y should close the prompt and watch the directory")
+	}
+}
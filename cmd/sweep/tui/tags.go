@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/tags"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// maxTagLength bounds the tag name typed into the prompt.
+const maxTagLength = 40
+
+// tagPromptState holds the state of the tag name input prompt.
+type tagPromptState struct {
+	Open    bool
+	Input   string
+	Targets []string // Paths the tag will be applied to
+}
+
+// tagTargets returns the paths a new tag should apply to: the current
+// selection if there is one, otherwise the item under the cursor.
+func (m Model) tagTargets() []string {
+	var paths []string
+	if m.treeMode && m.treeView != nil {
+		for _, node := range m.treeView.GetSelectedFiles() {
+			paths = append(paths, node.Path)
+		}
+		if len(paths) == 0 {
+			if node := m.treeView.Selected(); node != nil {
+				paths = append(paths, node.Path)
+			}
+		}
+		return paths
+	}
+
+	for _, f := range m.resultModel.SelectedFiles() {
+		paths = append(paths, f.Path)
+	}
+	if len(paths) == 0 {
+		files := m.resultModel.Files()
+		if c := m.resultModel.Cursor(); c >= 0 && c < len(files) {
+			paths = append(paths, files[c].Path)
+		}
+	}
+	return paths
+}
+
+// openTagPrompt opens the tag prompt for the current targets.
+func (m *Model) openTagPrompt() {
+	if m.options.Tags == nil {
+		return
+	}
+	targets := m.tagTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.tagPrompt = tagPromptState{Open: true, Targets: targets}
+}
+
+// handleTagPromptKey handles key input while the tag prompt is open.
+func (m Model) handleTagPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.tagPrompt = tagPromptState{}
+	case tea.KeyEnter:
+		m.applyTag(m.tagPrompt.Input, m.tagPrompt.Targets)
+		m.tagPrompt = tagPromptState{}
+	case tea.KeyBackspace:
+		if r := []rune(m.tagPrompt.Input); len(r) > 0 {
+			m.tagPrompt.Input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		if len([]rune(m.tagPrompt.Input)) < maxTagLength {
+			m.tagPrompt.Input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// applyTag persists tag on paths and surfaces the result as a notification.
+func (m *Model) applyTag(tag string, paths []string) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || len(paths) == 0 {
+		return
+	}
+
+	now := time.Now()
+	if err := m.options.Tags.Add(tag, paths...); err != nil {
+		logging.Get("tui").Error("failed to save tag", "tag", tag, "error", err)
+		m.notifications = append(m.notifications, Notification{
+			Type:      NotificationRemoved,
+			Message:   "Tag failed: " + err.Error(),
+			Expires:   now.Add(3 * time.Second),
+			CreatedAt: now,
+		})
+		return
+	}
+
+	logging.Get("tui").Info("tagged paths", "tag", tag, "count", len(paths))
+	m.notifications = append(m.notifications, Notification{
+		Type:      NotificationModified,
+		Message:   fmt.Sprintf("Tagged %d with %q", len(paths), tag),
+		Expires:   now.Add(3 * time.Second),
+		CreatedAt: now,
+	})
+}
+
+// renderTagPrompt renders the tag name input dialog over bg.
+func (m Model) renderTagPrompt(bg string) string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).
+		Render(fmt.Sprintf("Tag %d item(s)", len(m.tagPrompt.Targets))))
+	b.WriteString("\n\n")
+	b.WriteString(keyStyle.Render("> "))
+	b.WriteString(m.tagPrompt.Input)
+	b.WriteString(keyStyle.Render("█"))
+	b.WriteString("\n\n")
+
+	if existing := m.options.Tags.Names(); len(existing) > 0 {
+		b.WriteString(mutedTextStyle.Render("Existing: " + truncatePath(strings.Join(existing, ", "), 50)))
+		b.WriteString("\n")
+	}
+	b.WriteString(mutedTextStyle.Render("[Enter] Apply  [Esc] Cancel"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
+
+// tagSummaryRow aggregates the current results covered by a tag.
+type tagSummaryRow struct {
+	Tag   string
+	Paths int
+	Files int
+	Size  int64
+}
+
+// buildTagSummary aggregates the current results by tag.
+func buildTagSummary(store *tags.Store, files []types.FileInfo) []tagSummaryRow {
+	if store == nil {
+		return nil
+	}
+
+	summary := store.Summary()
+	rows := make([]tagSummaryRow, 0, len(summary))
+	for _, s := range summary {
+		row := tagSummaryRow{Tag: s.Tag, Paths: len(s.Paths)}
+		for _, f := range files {
+			for _, p := range s.Paths {
+				if tags.Covers(p, f.Path) {
+					row.Files++
+					row.Size += f.Size
+					break
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// renderTagSummary renders the tags summary dialog over bg.
+func (m Model) renderTagSummary(bg string) string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("Tags"))
+	b.WriteString("\n\n")
+
+	rows := buildTagSummary(m.options.Tags, m.resultModel.Files())
+	if len(rows) == 0 {
+		b.WriteString(mutedTextStyle.Render("No tags yet. Press T to tag the current item."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(mutedTextStyle.Render(fmt.Sprintf("%-20s %6s %6s %9s", "Tag", "Paths", "Files", "Size")))
+		b.WriteString("\n")
+		for _, r := range rows {
+			b.WriteString(fmt.Sprintf("%-20s %6d %6d %9s",
+				truncatePath(r.Tag, 20), r.Paths, r.Files, types.FormatSize(r.Size)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedTextStyle.Render("Files/Size count current results only  [Esc] Close"))
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#666666")).
+		Padding(1, 3).
+		Render(b.String())
+
+	return m.overlayDialog(bg, dialog)
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jamesainslie/sweep/pkg/sweep/tags"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestBuildTagSummary(t *testing.T) {
+	store, err := tags.Open(filepath.Join(t.TempDir(), "tags.json"))
+	if err != nil {
+		t.Fatalf("tags.Open() error = %v", err)
+	}
+	_ = store.Add("review", "/data/videos", "/data/one.iso")
+	_ = store.Add("empty", "/nowhere")
+
+	files := []types.FileInfo{
+		{Path: "/data/videos/a.mp4", Size: 100 * types.MiB},
+		{Path: "/data/videos/b.mp4", Size: 50 * types.MiB},
+		{Path: "/data/one.iso", Size: 10 * types.MiB},
+		{Path: "/data/other.bin", Size: 1 * types.MiB},
+	}
+
+	rows := buildTagSummary(store, files)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	// Rows are sorted by tag name
+	if rows[0].Tag != "empty" || rows[0].Files != 0 {
+		t.Errorf("unexpected row for empty tag: %+v", rows[0])
+	}
+	if rows[1].Tag != "review" || rows[1].Files != 3 || rows[1].Size != 160*types.MiB {
+		t.Errorf("unexpected row for review tag: %+v", rows[1])
+	}
+}
+
+func TestTagPromptAppliesTag(t *testing.T) {
+	store, err := tags.Open(filepath.Join(t.TempDir(), "tags.json"))
+	if err != nil {
+		t.Fatalf("tags.Open() error = %v", err)
+	}
+
+	m := NewModel(Options{Root: "/data", Tags: store})
+	m.resultModel.SetFiles([]types.FileInfo{{Path: "/data/big.iso", Size: types.GiB}})
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = next.(Model)
+	if !m.tagPrompt.Open {
+		t.Fatal("expected tag prompt to open")
+	}
+
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("keep")})
+	m = next.(Model)
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+
+	if m.tagPrompt.Open {
+		t.Error("expected tag prompt to close after enter")
+	}
+	if got := store.TagsFor("/data/big.iso"); len(got) != 1 || got[0] != "keep" {
+		t.Errorf("TagsFor() = %v, want [keep]", got)
+	}
+}
@@ -0,0 +1,431 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+)
+
+// treemapMaxItems caps the rectangles drawn per directory; the rest would be
+// too small to see anyway.
+const treemapMaxItems = 200
+
+// cellAspect is the height of a terminal cell relative to its width. The
+// layout accounts for it so rectangles look square rather than tall.
+const cellAspect = 2
+
+// Treemap rectangle colors, cycled so neighbors usually differ.
+var (
+	treemapDirColors = []lipgloss.Color{
+		"#3B4F7D", "#4F3B7D", "#2E6B5E", "#6B4A2E", "#5E2E6B", "#2E5E6B", "#6B2E45", "#4A6B2E",
+	}
+	treemapFileColor   = lipgloss.Color("#3A3A3A")
+	treemapCursorColor = lipgloss.Color("#7D56F4")
+)
+
+// rect is a rectangle of terminal cells.
+type rect struct {
+	x, y, w, h int
+}
+
+// Treemap shows one directory's children as rectangles sized by the bytes
+// under them, like ncdu or WinDirStat. Enter drills into a directory and
+// backspace goes back up.
+type Treemap struct {
+	root     *tree.Node
+	dir      *tree.Node   // Directory being shown
+	children []*tree.Node // Non-empty children, largest first
+	rects    []rect       // Layout from the last render, parallel to children
+	cursor   int
+}
+
+// NewTreemap creates a treemap showing root.
+func NewTreemap(root *tree.Node) *Treemap {
+	tm := &Treemap{root: root, dir: root}
+	tm.refresh()
+	return tm
+}
+
+// nodeSize returns the bytes under a node.
+func nodeSize(n *tree.Node) int64 {
+	if n.IsDir {
+		return n.LargeFileSize
+	}
+	return n.Size
+}
+
+// refresh reloads the current directory's children, keeping the cursor on
+// the same node when it still exists.
+func (tm *Treemap) refresh() {
+	var current *tree.Node
+	if tm.cursor < len(tm.children) {
+		current = tm.children[tm.cursor]
+	}
+
+	tm.children = tm.children[:0]
+	if tm.dir != nil {
+		for _, c := range tm.dir.Children {
+			if nodeSize(c) > 0 {
+				tm.children = append(tm.children, c)
+			}
+		}
+	}
+	sort.SliceStable(tm.children, func(i, j int) bool {
+		return nodeSize(tm.children[i]) > nodeSize(tm.children[j])
+	})
+	if len(tm.children) > treemapMaxItems {
+		tm.children = tm.children[:treemapMaxItems]
+	}
+
+	tm.cursor = 0
+	for i, c := range tm.children {
+		if c == current {
+			tm.cursor = i
+		}
+	}
+}
+
+// Dir returns the directory being shown.
+func (tm *Treemap) Dir() *tree.Node {
+	return tm.dir
+}
+
+// Selected returns the node under the cursor, or nil.
+func (tm *Treemap) Selected() *tree.Node {
+	if tm.cursor < len(tm.children) {
+		return tm.children[tm.cursor]
+	}
+	return nil
+}
+
+// Enter drills into the directory under the cursor.
+func (tm *Treemap) Enter() {
+	n := tm.Selected()
+	if n == nil || !n.IsDir || len(n.Children) == 0 {
+		return
+	}
+	tm.dir = n
+	tm.cursor = 0
+	tm.children = nil
+	tm.refresh()
+}
+
+// Up returns to the parent directory, with the cursor on the directory
+// just left.
+func (tm *Treemap) Up() {
+	if tm.dir == nil || tm.dir == tm.root || tm.dir.Parent == nil {
+		return
+	}
+	left := tm.dir
+	tm.dir = tm.dir.Parent
+	tm.children = nil
+	tm.refresh()
+	for i, c := range tm.children {
+		if c == left {
+			tm.cursor = i
+		}
+	}
+}
+
+// Next moves the cursor to the next rectangle in size order.
+func (tm *Treemap) Next() {
+	if len(tm.children) > 0 {
+		tm.cursor = (tm.cursor + 1) % len(tm.children)
+	}
+}
+
+// Move moves the cursor to the nearest visible rectangle in the direction
+// (dx, dy), using the layout of the last render.
+func (tm *Treemap) Move(dx, dy int) {
+	if tm.cursor >= len(tm.rects) {
+		return
+	}
+	center := func(r rect) (float64, float64) {
+		return float64(r.x) + float64(r.w)/2, (float64(r.y) + float64(r.h)/2) * cellAspect
+	}
+	cx, cy := center(tm.rects[tm.cursor])
+
+	best, bestDist := -1, math.MaxFloat64
+	for i, r := range tm.rects {
+		if i == tm.cursor || r.w == 0 || r.h == 0 {
+			continue
+		}
+		x, y := center(r)
+		along := (x-cx)*float64(dx) + (y-cy)*float64(dy)
+		if along <= 0 {
+			continue
+		}
+		across := math.Abs((x-cx)*float64(dy)) + math.Abs((y-cy)*float64(dx))
+		if d := along + 2*across; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best >= 0 {
+		tm.cursor = best
+	}
+}
+
+// View renders the treemap into a width x height block.
+func (tm *Treemap) View(width, height int) string {
+	tm.refresh()
+	if len(tm.children) == 0 || width < 1 || height < 1 {
+		tm.rects = nil
+		return center(mutedTextStyle.Render("Nothing to show"), width) + strings.Repeat("\n", max(height-1, 0))
+	}
+
+	sizes := make([]int64, len(tm.children))
+	for i, c := range tm.children {
+		sizes[i] = nodeSize(c)
+	}
+	tm.rects = layoutTreemap(sizes, width, height)
+
+	// Map each cell to its rectangle and label character.
+	owner := make([][]int, height)
+	text := make([][]rune, height)
+	for y := range height {
+		owner[y] = make([]int, width)
+		text[y] = []rune(strings.Repeat(" ", width))
+		for x := range owner[y] {
+			owner[y][x] = -1
+		}
+	}
+	for i, r := range tm.rects {
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				owner[y][x] = i
+			}
+		}
+		n := tm.children[i]
+		name := n.Name
+		if n.IsDir {
+			name += "/"
+		}
+		tm.label(text, r, 0, name)
+		tm.label(text, r, 1, formatSize(nodeSize(n)))
+	}
+
+	var b strings.Builder
+	for y := range height {
+		for x := 0; x < width; {
+			i := owner[y][x]
+			end := x
+			for end < width && owner[y][end] == i {
+				end++
+			}
+			b.WriteString(tm.style(i).Render(string(text[y][x:end])))
+			x = end
+		}
+		if y < height-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// label writes s on the given line of r, leaving a one-cell margin.
+func (tm *Treemap) label(text [][]rune, r rect, line int, s string) {
+	if r.w < 3 || line >= r.h {
+		return
+	}
+	runes := []rune(s)
+	if len(runes) > r.w-1 {
+		runes = runes[:r.w-1]
+	}
+	copy(text[r.y+line][r.x+1:], runes)
+}
+
+// style returns the style of rectangle i; -1 is uncovered space.
+func (tm *Treemap) style(i int) lipgloss.Style {
+	s := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	switch {
+	case i < 0:
+		return s
+	case i == tm.cursor:
+		return s.Background(treemapCursorColor).Bold(true)
+	case !tm.children[i].IsDir:
+		return s.Background(treemapFileColor)
+	default:
+		return s.Background(treemapDirColors[i%len(treemapDirColors)])
+	}
+}
+
+// layoutTreemap lays out sizes, sorted largest first, as squarified
+// rectangles filling a width x height grid of cells. Items too small for a
+// cell get an empty rectangle.
+func layoutTreemap(sizes []int64, width, height int) []rect {
+	rects := make([]rect, len(sizes))
+	var total float64
+	for _, s := range sizes {
+		total += float64(s)
+	}
+	if total <= 0 || width <= 0 || height <= 0 {
+		return rects
+	}
+
+	// Lay out in units where cells are cellAspect times taller than wide,
+	// so squares in layout space look square on screen.
+	w, h := float64(width), float64(height*cellAspect)
+	scale := w * h / total
+	areas := make([]float64, len(sizes))
+	for i, s := range sizes {
+		areas[i] = float64(s) * scale
+	}
+
+	type frect struct{ x, y, w, h float64 }
+	out := make([]frect, 0, len(areas))
+	var x, y float64
+	for start := 0; start < len(areas); {
+		short := min(w, h)
+		end := start + 1
+		for end < len(areas) && worstRatio(areas[start:end+1], short) <= worstRatio(areas[start:end], short) {
+			end++
+		}
+
+		var sum float64
+		for _, a := range areas[start:end] {
+			sum += a
+		}
+		if w >= h {
+			// Column along the left edge.
+			cw := sum / h
+			yy := y
+			for _, a := range areas[start:end] {
+				out = append(out, frect{x, yy, cw, a / cw})
+				yy += a / cw
+			}
+			x += cw
+			w -= cw
+		} else {
+			// Row along the top edge.
+			rh := sum / w
+			xx := x
+			for _, a := range areas[start:end] {
+				out = append(out, frect{xx, y, a / rh, rh})
+				xx += a / rh
+			}
+			y += rh
+			h -= rh
+		}
+		start = end
+	}
+
+	// Snap edges to cells; neighbors share edge values, so cells are
+	// covered exactly once.
+	for i, f := range out {
+		x0, x1 := snap(f.x, width), snap(f.x+f.w, width)
+		y0, y1 := snap(f.y/cellAspect, height), snap((f.y+f.h)/cellAspect, height)
+		rects[i] = rect{x: x0, y: y0, w: x1 - x0, h: y1 - y0}
+	}
+	return rects
+}
+
+// worstRatio returns the worst aspect ratio of a row of areas laid along a
+// side of the given length.
+func worstRatio(row []float64, side float64) float64 {
+	var sum, lo, hi float64
+	lo = math.MaxFloat64
+	for _, a := range row {
+		sum += a
+		lo = min(lo, a)
+		hi = max(hi, a)
+	}
+	if sum == 0 || lo == 0 {
+		return math.MaxFloat64
+	}
+	s2, sum2 := side*side, sum*sum
+	return max(s2*hi/sum2, sum2/(s2*lo))
+}
+
+// snap rounds a layout coordinate to a cell boundary within [0, limit].
+func snap(v float64, limit int) int {
+	return max(0, min(int(math.Round(v)), limit))
+}
+
+// openTreemap switches to the treemap, starting at the top of the tree.
+// The treemap needs the daemon's tree, so it is unavailable until loaded.
+func (m *Model) openTreemap() {
+	if m.treeView == nil || m.treeView.root == nil {
+		return
+	}
+	m.treemap = NewTreemap(m.treeView.root)
+	m.treemapMode = true
+}
+
+// handleTreemapKey handles keys while the treemap is shown.
+func (m Model) handleTreemapKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "q":
+		return m, tea.Quit
+	case "esc", "m":
+		m.treemapMode = false
+	case "L":
+		return m, m.toggleLogViewer()
+	case "up", "k":
+		m.treemap.Move(0, -1)
+	case "down", "j":
+		m.treemap.Move(0, 1)
+	case "left", "h":
+		m.treemap.Move(-1, 0)
+	case "right", "l":
+		m.treemap.Move(1, 0)
+	case "tab":
+		m.treemap.Next()
+	case "enter":
+		m.treemap.Enter()
+	case "backspace":
+		m.treemap.Up()
+	}
+	return m, nil
+}
+
+// renderTreemapView renders the treemap with its header and help bar.
+func (m Model) renderTreemapView(height int) string {
+	contentWidth := max(m.width-4, 40)
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(m.renderTreeHeader(contentWidth))
+	b.WriteString("\n")
+	b.WriteString(renderDivider(contentWidth))
+	b.WriteString("\n")
+
+	// Current directory and the item under the cursor
+	dir := m.treemap.Dir()
+	location := "  " + titleStyle.Render(truncatePath(dir.Path, contentWidth/2)) + " " +
+		mutedTextStyle.Render(formatSize(nodeSize(dir)))
+	if sel := m.treemap.Selected(); sel != nil {
+		pct := 0
+		if total := nodeSize(dir); total > 0 {
+			pct = int(float64(nodeSize(sel)) / float64(total) * 100)
+		}
+		location += "  " + keyDescStyle.Render(fmt.Sprintf("> %s  %s  %d%%", sel.Name, formatSize(nodeSize(sel)), pct))
+	}
+	b.WriteString(location)
+	b.WriteString("\n")
+	b.WriteString(renderDivider(contentWidth))
+	b.WriteString("\n")
+
+	// Reserve: margin, header, divider, location, divider, divider, help
+	mapHeight := max(height-9, 5)
+	b.WriteString(m.treemap.View(contentWidth, mapHeight))
+	b.WriteString("\n")
+
+	b.WriteString(renderDivider(contentWidth))
+	b.WriteString("\n")
+	hints := []string{
+		keyStyle.Render("arrows") + " " + keyDescStyle.Render("move"),
+		keyStyle.Render("tab") + " " + keyDescStyle.Render("next"),
+		keyStyle.Render("enter") + " " + keyDescStyle.Render("open"),
+		keyStyle.Render("backspace") + " " + keyDescStyle.Render("up"),
+		keyStyle.Render("m") + " " + keyDescStyle.Render("close"),
+		keyStyle.Render("q") + " " + keyDescStyle.Render("quit"),
+	}
+	b.WriteString("  " + strings.Join(hints, "  "))
+
+	return outerBoxStyle.Width(m.width - 2).Render(b.String())
+}
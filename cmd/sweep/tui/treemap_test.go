@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLayoutTreemapCoversArea(t *testing.T) {
+	sizes := []int64{600, 250, 100, 50}
+	width, height := 60, 20
+	rects := layoutTreemap(sizes, width, height)
+
+	covered := make([][]int, height)
+	for y := range covered {
+		covered[y] = make([]int, width)
+	}
+	for i, r := range rects {
+		if r.w <= 0 || r.h <= 0 {
+			t.Fatalf("rect %d is empty: %+v", i, r)
+		}
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				covered[y][x]++
+			}
+		}
+	}
+	for y := range covered {
+		for x, n := range covered[y] {
+			if n != 1 {
+				t.Fatalf("cell (%d,%d) covered %d times, want 1", x, y, n)
+			}
+		}
+	}
+
+	// Areas should be roughly proportional to sizes.
+	for i, r := range rects {
+		got := float64(r.w*r.h) / float64(width*height)
+		want := float64(sizes[i]) / 1000
+		if got < want*0.7 || got > want*1.3 {
+			t.Errorf("rect %d covers %.2f of the area, want about %.2f", i, got, want)
+		}
+	}
+}
+
+func TestLayoutTreemapEmpty(t *testing.T) {
+	for _, r := range layoutTreemap([]int64{0, 0}, 10, 10) {
+		if r.w != 0 || r.h != 0 {
+			t.Errorf("zero sizes should get empty rects, got %+v", r)
+		}
+	}
+}
+
+func TestTreemapNavigation(t *testing.T) {
+	tm := NewTreemap(createTestTree())
+
+	if got := tm.Selected().Name; got != "dir1" {
+		t.Fatalf("cursor starts on %q, want the largest child dir1", got)
+	}
+
+	tm.View(40, 10)
+	tm.Move(1, 0)
+	tm.Move(0, 1)
+	if got := tm.Selected().Name; got != "dir2" {
+		t.Errorf("moving away from dir1 should reach dir2, got %q", got)
+	}
+
+	tm.Enter()
+	if tm.Dir().Name != "dir2" {
+		t.Fatalf("Enter should drill into dir2, showing %q", tm.Dir().Name)
+	}
+	if got := tm.Selected().Name; got != "file3.txt" {
+		t.Errorf("cursor = %q, want file3.txt", got)
+	}
+
+	tm.Enter() // Files can't be entered
+	if tm.Dir().Name != "dir2" {
+		t.Errorf("Enter on a file should do nothing")
+	}
+
+	tm.Up()
+	if tm.Dir().Name != "test" {
+		t.Fatalf("Up should return to the root, showing %q", tm.Dir().Name)
+	}
+	if got := tm.Selected().Name; got != "dir2" {
+		t.Errorf("Up should leave the cursor on the directory just left, got %q", got)
+	}
+
+	tm.Up() // Already at the root
+	if tm.Dir().Name != "test" {
+		t.Errorf("Up at the root should do nothing")
+	}
+
+	tm.Next()
+	if got := tm.Selected().Name; got != "dir1" {
+		t.Errorf("Next should wrap to dir1, got %q", got)
+	}
+}
+
+func TestTreemapView(t *testing.T) {
+	tm := NewTreemap(createTestTree())
+	view := tm.View(40, 10)
+
+	lines := strings.Split(view, "\n")
+	if len(lines) != 10 {
+		t.Fatalf("view has %d lines, want 10", len(lines))
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != 40 {
+			t.Errorf("line %d is %d wide, want 40", i, w)
+		}
+	}
+	if !strings.Contains(view, "dir1/") || !strings.Contains(view, "dir2/") {
+		t.Errorf("view should label directories:\n%s", view)
+	}
+	if !strings.Contains(view, "200 MiB") {
+		t.Errorf("view should show sizes:\n%s", view)
+	}
+}
+
+func TestTreemapKeys(t *testing.T) {
+	m := NewModel(Options{})
+	m.treeView = NewTreeView(createTestTree())
+
+	next, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = next.(Model)
+	if !m.treemapMode {
+		t.Fatal("m should open the treemap")
+	}
+
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.treemap.Dir().Name != "dir1" {
+		t.Errorf("enter should drill into dir1, showing %q", m.treemap.Dir().Name)
+	}
+
+	next, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = next.(Model)
+	if m.treemapMode {
+		t.Error("m should close the treemap")
+	}
+}
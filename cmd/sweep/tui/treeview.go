@@ -4,11 +4,15 @@ package tui
 import (
 	"fmt"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
 	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -23,6 +27,8 @@ const (
 	// Files: filled = selected, outline = unselected
 	iconFileSelected   = "\u25CF" // ● Black circle (filled)
 	iconFileUnselected = "\u25CB" // ○ White circle (outline)
+	// Pinned directories stay expanded across refreshes
+	iconPinned = "\u2691" // ⚑ Black flag
 )
 
 // getNodeIcon returns the appropriate icon for a node based on its type,
@@ -52,10 +58,18 @@ func getNodeIcon(node *tree.Node, isSelected bool) string {
 // with expand/collapse, selection, and scrolling support.
 type TreeView struct {
 	root     *tree.Node
-	flat     []*tree.Node    // Flattened visible nodes
-	cursor   int             // Index in flat slice
-	offset   int             // Scroll offset
-	selected map[string]bool // Selected file paths
+	flat     []*tree.Node     // Flattened visible nodes
+	cursor   int              // Index in flat slice
+	offset   int              // Scroll offset
+	selected map[string]bool  // Selected file paths
+	pinned   map[string]bool  // Pinned directory paths, kept expanded
+	agg      *tree.Aggregator // Applies live changes incrementally
+
+	ageColors *AgeGradient    // Optional; colors file names by age
+	matches   map[string]bool // Paths a search matched, highlighted
+
+	showEntries bool              // Show each directory's inode count ('N')
+	entries     map[string]du.Dir // Entry counts by path; nil until read
 }
 
 // NewTreeView creates a new TreeView with the given root node.
@@ -65,18 +79,33 @@ func NewTreeView(root *tree.Node) *TreeView {
 		cursor:   0,
 		offset:   0,
 		selected: make(map[string]bool),
+		pinned:   make(map[string]bool),
+	}
+	if root != nil {
+		tv.agg = tree.NewAggregator(root)
+		tv.agg.DetectType = filetype.Detect
 	}
 	tv.refresh()
 	return tv
 }
 
-// refresh rebuilds the flat list from the current tree state.
+// refresh rebuilds the flat list from the current tree state, expanding
+// pinned directories and keeping the cursor on the same node when rows are
+// added or removed above it.
 func (tv *TreeView) refresh() {
 	if tv.root == nil {
 		tv.flat = nil
 		return
 	}
+	var current string
+	if node := tv.Selected(); node != nil {
+		current = node.Path
+	}
+	tv.expandPinned()
 	tv.flat = tv.root.Flatten()
+	if current != "" {
+		tv.moveCursorTo(current)
+	}
 
 	// Ensure cursor is in bounds
 	if tv.cursor >= len(tv.flat) {
@@ -87,6 +116,176 @@ func (tv *TreeView) refresh() {
 	}
 }
 
+// expandPinned expands every pinned directory still in the tree, along
+// with its ancestors so it is visible.
+func (tv *TreeView) expandPinned() {
+	for path := range tv.pinned {
+		node := tv.lookup(path)
+		if node == nil {
+			continue
+		}
+		for n := node; n != nil; n = n.Parent {
+			n.Expanded = true
+		}
+	}
+}
+
+// lookup finds the node for path in the tree.
+func (tv *TreeView) lookup(path string) *tree.Node {
+	if tv.agg != nil {
+		return tv.agg.Lookup(path)
+	}
+	return nil
+}
+
+// moveCursorTo puts the cursor on path. If the node is gone and was inside
+// a pinned directory, the cursor moves to its closest remaining ancestor so
+// the pinned subtree stays in view; otherwise the cursor index is kept.
+func (tv *TreeView) moveCursorTo(path string) {
+	if i := tv.indexOf(path); i >= 0 {
+		tv.cursor = i
+		return
+	}
+	if !tv.inPinned(path) {
+		return
+	}
+	for dir := filepath.Dir(path); dir != path; path, dir = dir, filepath.Dir(dir) {
+		if i := tv.indexOf(dir); i >= 0 {
+			tv.cursor = i
+			return
+		}
+	}
+}
+
+// Reveal puts the cursor on path, expanding the directories above it so
+// it is visible.
+func (tv *TreeView) Reveal(path string) {
+	node := tv.lookup(path)
+	if node == nil {
+		return
+	}
+	for n := node.Parent; n != nil; n = n.Parent {
+		n.Expanded = true
+	}
+	tv.flat = tv.root.Flatten()
+	tv.moveCursorTo(path)
+	tv.ensureVisible()
+}
+
+// ExpandedDirs returns the paths of the expanded directories.
+func (tv *TreeView) ExpandedDirs() []string {
+	var paths []string
+	walkTree(tv.root, func(n *tree.Node) {
+		if n.IsDir && n.Expanded {
+			paths = append(paths, n.Path)
+		}
+	})
+	return paths
+}
+
+// Expand expands the directories at paths that are still in the tree.
+func (tv *TreeView) Expand(paths []string) {
+	for _, path := range paths {
+		if node := tv.lookup(path); node != nil && node.IsDir {
+			node.Expanded = true
+		}
+	}
+	tv.refresh()
+}
+
+// ScrollTo puts the cursor on path, if it is shown, and scrolls offset
+// rows down, as far as the cursor stays in view.
+func (tv *TreeView) ScrollTo(path string, offset int) {
+	if i := tv.indexOf(path); i >= 0 {
+		tv.cursor = i
+	}
+	tv.offset = offset
+	tv.ensureVisible()
+}
+
+// indexOf returns the position of path in the flat list, or -1.
+func (tv *TreeView) indexOf(path string) int {
+	for i, node := range tv.flat {
+		if node.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// inPinned reports whether path is a pinned directory or inside one.
+func (tv *TreeView) inPinned(path string) bool {
+	for pin := range tv.pinned {
+		if path == pin || strings.HasPrefix(path, pin+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TogglePin pins or unpins the directory under the cursor. Pinning expands
+// it; unpinning leaves it as it is.
+func (tv *TreeView) TogglePin() {
+	node := tv.Selected()
+	if node == nil || !node.IsDir {
+		return
+	}
+	if tv.pinned[node.Path] {
+		delete(tv.pinned, node.Path)
+		return
+	}
+	tv.pinned[node.Path] = true
+	tv.refresh()
+}
+
+// IsPinned reports whether the directory at path is pinned.
+func (tv *TreeView) IsPinned(path string) bool {
+	return tv.pinned[path]
+}
+
+// RestoreState carries pins, selection, and the cursor position over from
+// the view of a previously loaded tree.
+func (tv *TreeView) RestoreState(prev *TreeView) {
+	if prev == nil {
+		return
+	}
+	for path := range prev.pinned {
+		tv.pinned[path] = true
+	}
+	tv.showEntries, tv.entries = prev.showEntries, prev.entries
+	for path := range prev.selected {
+		if tv.lookup(path) != nil {
+			tv.selected[path] = true
+		}
+	}
+	tv.refresh()
+	if node := prev.Selected(); node != nil {
+		tv.moveCursorTo(node.Path)
+	}
+	tv.offset = prev.offset
+	tv.ensureVisible()
+}
+
+// ToggleEntries shows or hides the inode counts of directories and reports
+// whether they are now shown.
+func (tv *TreeView) ToggleEntries() bool {
+	tv.showEntries = !tv.showEntries
+	return tv.showEntries
+}
+
+// HasEntries reports whether entry counts have been read.
+func (tv *TreeView) HasEntries() bool {
+	return tv.entries != nil
+}
+
+// SetEntries sets the entry counts shown for directories.
+func (tv *TreeView) SetEntries(dirs []du.Dir) {
+	tv.entries = make(map[string]du.Dir, len(dirs))
+	for _, d := range dirs {
+		tv.entries[d.Path] = d
+	}
+}
+
 // MoveUp moves the cursor up one position.
 func (tv *TreeView) MoveUp() {
 	if len(tv.flat) == 0 {
@@ -132,6 +331,8 @@ func (tv *TreeView) Toggle() {
 	}
 
 	if node.IsDir {
+		// Collapsing a pinned directory unpins it, or it would reopen
+		delete(tv.pinned, node.Path)
 		node.Toggle()
 		tv.refresh()
 	} else {
@@ -154,6 +355,11 @@ func (tv *TreeView) ToggleSelect() {
 	}
 }
 
+// Deselect removes a path from the selection.
+func (tv *TreeView) Deselect(path string) {
+	delete(tv.selected, path)
+}
+
 // Selected returns the currently highlighted node.
 func (tv *TreeView) Selected() *tree.Node {
 	if len(tv.flat) == 0 || tv.cursor < 0 || tv.cursor >= len(tv.flat) {
@@ -258,6 +464,10 @@ func (tv *TreeView) renderNode(node *tree.Node, width int, isCursor, isSelected
 
 	// Name
 	content.WriteString(node.Name)
+	pinned := tv.pinned[node.Path]
+	if pinned {
+		content.WriteString(" " + iconPinned)
+	}
 
 	// Calculate percentage of total size
 	var percent int
@@ -280,6 +490,9 @@ func (tv *TreeView) renderNode(node *tree.Node, width int, isCursor, isSelected
 				node.LargeFileCount,
 				formatSize(node.LargeFileSize))
 		}
+		if tv.showEntries {
+			sizeStr = strings.TrimSpace(tv.entriesCell(node.Path) + " " + sizeStr)
+		}
 	} else {
 		sizeStr = formatSize(node.Size)
 	}
@@ -310,7 +523,16 @@ func (tv *TreeView) renderNode(node *tree.Node, width int, isCursor, isSelected
 		styled.WriteString(lipgloss.NewStyle().Foreground(treeUnselectedColor).Render(icon))
 	}
 	styled.WriteString(" ")
-	styled.WriteString(node.Name)
+	if tv.matches[node.Path] {
+		styled.WriteString(searchMatchStyle.Render(node.Name))
+	} else if node.IsDir || node.ModTime == 0 {
+		styled.WriteString(node.Name)
+	} else {
+		styled.WriteString(tv.ageColors.Render(node.Name, time.Unix(node.ModTime, 0), time.Now()))
+	}
+	if pinned {
+		styled.WriteString(" " + lipgloss.NewStyle().Foreground(treePinnedColor).Render(iconPinned))
+	}
 	styled.WriteString(strings.Repeat(" ", padding))
 	styled.WriteString(treePercentStyle.Render(fmt.Sprintf("%4s", percentStr)))
 	styled.WriteString(" ")
@@ -319,6 +541,19 @@ func (tv *TreeView) renderNode(node *tree.Node, width int, isCursor, isSelected
 	return treeRowNormalStyle.Width(width).Render(styled.String())
 }
 
+// entriesCell renders the files and directories beneath a directory, or
+// "…" until they are counted.
+func (tv *TreeView) entriesCell(path string) string {
+	if tv.entries == nil {
+		return "…"
+	}
+	d, ok := tv.entries[path]
+	if !ok {
+		return "-"
+	}
+	return humanize.Comma(d.Inodes()) + " inodes"
+}
+
 // formatSize formats a size in bytes as a human-readable string.
 func formatSize(bytes int64) string {
 	return types.FormatSize(bytes)
@@ -380,341 +615,141 @@ func (tv *TreeView) HasSelection() bool {
 // Creates intermediate directories as needed.
 // Updates aggregates up to root and resorts affected directories.
 func (tv *TreeView) AddFile(path string, size int64, modTime int64) {
-	if tv.root == nil {
-		return
-	}
-
-	// Create the file node
-	fileNode := &tree.Node{
-		Path:     path,
-		Name:     filepath.Base(path),
-		IsDir:    false,
-		Size:     size,
-		ModTime:  modTime,
-		FileType: detectFileType(path),
-	}
-
-	// Ensure parent directories exist and get the immediate parent
-	parentPath := filepath.Dir(path)
-	parent := tv.ensureParentDirs(parentPath)
-	if parent == nil {
-		return
-	}
-
-	// Add file to parent
-	parent.AddChild(fileNode)
-
-	// Update aggregates up the tree
-	tv.updateAncestorAggregates(parent, size, 1)
-	parent.LargeFileSize += size
-	parent.LargeFileCount++
-
-	// Resort the parent's children
-	tv.sortNodeChildren(parent)
-
-	// Resort parent directories up to root (size changes may affect sort order)
-	for p := parent.Parent; p != nil; p = p.Parent {
-		tv.sortNodeChildren(p)
-	}
-
-	// Refresh the flat list
-	tv.refresh()
+	tv.QueueFile(path, size, modTime)
+	tv.Flush()
 }
 
 // RemoveFile removes a file from the tree by path.
 // It also removes the file from the selection map, cleans up empty directories,
 // and refreshes the flat list.
 func (tv *TreeView) RemoveFile(path string) {
-	// Remove from selection
-	delete(tv.selected, path)
-
-	// Find and remove the node from the tree
-	if tv.root != nil {
-		tv.removeNodeByPath(tv.root, path)
-	}
-
-	// Clean up empty directories
-	tv.cleanupEmptyDirs(tv.root)
-
-	// Refresh the flat list
-	tv.refresh()
+	tv.QueueRemove(path)
+	tv.Flush()
 }
 
 // UpdateFile updates a file's size in the tree.
 // Recalculates aggregates up to root and resorts affected directories.
 func (tv *TreeView) UpdateFile(path string, newSize int64) {
-	if tv.root == nil {
+	if tv.agg == nil {
 		return
 	}
-
-	// Find the node
-	node := tv.findNodeByPath(tv.root, path)
+	node := tv.agg.Lookup(path)
 	if node == nil || node.IsDir {
 		return
 	}
+	tv.QueueFile(path, newSize, node.ModTime)
+	tv.Flush()
+}
 
-	// Calculate size delta
-	oldSize := node.Size
-	sizeDelta := newSize - oldSize
-
-	// Update the node's size
-	node.Size = newSize
-
-	// Update aggregates up the tree
-	if node.Parent != nil {
-		node.Parent.LargeFileSize += sizeDelta
-		tv.updateAncestorAggregates(node.Parent, sizeDelta, 0)
-
-		// Resort parent directories (size changes may affect sort order)
-		for p := node.Parent; p != nil; p = p.Parent {
-			tv.sortNodeChildren(p)
-		}
+// QueueFile adds or updates a file without recomputing aggregates.
+// Changes take effect on the next Flush.
+func (tv *TreeView) QueueFile(path string, size int64, modTime int64) {
+	if tv.agg == nil {
+		return
 	}
-
-	// Refresh the flat list
-	tv.refresh()
+	tv.agg.Upsert(path, size, modTime)
 }
 
-// ensureParentDirs ensures all parent directories exist from root to the given path.
-// Returns the immediate parent node.
-func (tv *TreeView) ensureParentDirs(parentPath string) *tree.Node {
-	// If parent is the root, return root
-	if parentPath == tv.root.Path {
-		return tv.root
+// QueueRemove removes a file and clears its selection without recomputing
+// aggregates. Changes take effect on the next Flush.
+func (tv *TreeView) QueueRemove(path string) {
+	delete(tv.selected, path)
+	if tv.agg == nil {
+		return
 	}
+	tv.agg.Remove(path)
+}
 
-	// Check if parent already exists
-	existing := tv.findNodeByPath(tv.root, parentPath)
-	if existing != nil {
-		return existing
+// QueueRename moves the files under a renamed directory to its new path,
+// keeping their selection, without recomputing aggregates. Changes take
+// effect on the next Flush.
+func (tv *TreeView) QueueRename(oldDir, newDir string) {
+	if tv.agg == nil {
+		return
 	}
-
-	// Build list of directories to create (from target up to root)
-	var dirsToCreate []string
-	current := parentPath
-	for current != tv.root.Path && current != "/" && current != "." {
-		if tv.findNodeByPath(tv.root, current) == nil {
-			dirsToCreate = append(dirsToCreate, current)
-		}
-		current = filepath.Dir(current)
+	node := tv.agg.Lookup(oldDir)
+	if node == nil || !node.IsDir {
+		return
 	}
-
-	// Create directories in reverse order (from root down)
-	for i := len(dirsToCreate) - 1; i >= 0; i-- {
-		dirPath := dirsToCreate[i]
-		dirNode := &tree.Node{
-			Path:     dirPath,
-			Name:     filepath.Base(dirPath),
-			IsDir:    true,
-			Expanded: false, // New directories are collapsed by default
-		}
-
-		// Find parent and add
-		dirParentPath := filepath.Dir(dirPath)
-		var dirParent *tree.Node
-		if dirParentPath == tv.root.Path {
-			dirParent = tv.root
-		} else {
-			dirParent = tv.findNodeByPath(tv.root, dirParentPath)
-		}
-
-		if dirParent != nil {
-			dirParent.AddChild(dirNode)
+	var files []*tree.Node
+	var collect func(n *tree.Node)
+	collect = func(n *tree.Node) {
+		for _, child := range n.Children {
+			if child.IsDir {
+				collect(child)
+			} else {
+				files = append(files, child)
+			}
 		}
 	}
+	collect(node)
 
-	// Return the immediate parent
-	return tv.findNodeByPath(tv.root, parentPath)
-}
-
-// removeNodeByPath recursively searches for and removes a node with the given path.
-func (tv *TreeView) removeNodeByPath(node *tree.Node, path string) bool {
-	for i, child := range node.Children {
-		if child.Path == path {
-			// Found it - remove from parent's children
-			node.Children = append(node.Children[:i], node.Children[i+1:]...)
-
-			// Update parent's aggregates for large files
-			if node.IsDir && !child.IsDir {
-				node.LargeFileCount--
-				node.LargeFileSize -= child.Size
-				// Propagate up to ancestors
-				tv.updateAncestorAggregates(node, -child.Size, -1)
-			}
-			return true
-		}
-		// Recurse into directories
-		if child.IsDir && tv.removeNodeByPath(child, path) {
-			return true
+	tv.agg.Remove(oldDir)
+	for _, f := range files {
+		newPath := newDir + f.Path[len(oldDir):]
+		tv.agg.Upsert(newPath, f.Size, f.ModTime)
+		if tv.selected[f.Path] {
+			delete(tv.selected, f.Path)
+			tv.selected[newPath] = true
 		}
 	}
-	return false
 }
 
-// findNodeByPath recursively searches for a node with the given path.
-func (tv *TreeView) findNodeByPath(node *tree.Node, path string) *tree.Node {
-	if node.Path == path {
-		return node
-	}
-	for _, child := range node.Children {
-		if found := tv.findNodeByPath(child, path); found != nil {
-			return found
+// RemoveDir removes a directory and everything under it from the tree,
+// clearing their selection, and refreshes the flat list.
+func (tv *TreeView) RemoveDir(dir string) {
+	for path := range tv.selected {
+		if path == dir || underDir(path, dir) {
+			delete(tv.selected, path)
 		}
 	}
-	return nil
-}
-
-// updateAncestorAggregates updates LargeFileSize and LargeFileCount up the tree.
-func (tv *TreeView) updateAncestorAggregates(node *tree.Node, sizeDelta int64, countDelta int) {
-	for parent := node.Parent; parent != nil; parent = parent.Parent {
-		parent.LargeFileSize += sizeDelta
-		parent.LargeFileCount += countDelta
+	if tv.agg == nil {
+		return
 	}
+	tv.agg.Remove(dir)
+	tv.Flush()
 }
 
-// sortNodeChildren sorts a node's children by size descending.
-// Directories come before files when sizes are equal.
-func (tv *TreeView) sortNodeChildren(node *tree.Node) {
-	if !node.IsDir || len(node.Children) == 0 {
+// RemoveUnowned removes files not owned by the filter's user.
+func (tv *TreeView) RemoveUnowned(f *owner.Filter) {
+	if tv.root == nil {
 		return
 	}
-
-	sort.Slice(node.Children, func(i, j int) bool {
-		a, b := node.Children[i], node.Children[j]
-
-		// Get comparable size (LargeFileSize for dirs, Size for files)
-		aSize := a.Size
-		if a.IsDir {
-			aSize = a.LargeFileSize
-		}
-		bSize := b.Size
-		if b.IsDir {
-			bSize = b.LargeFileSize
-		}
-
-		// Sort by size descending
-		if aSize != bSize {
-			return aSize > bSize
+	var unowned []string
+	var walk func(n *tree.Node)
+	walk = func(n *tree.Node) {
+		if !n.IsDir {
+			if !f.OwnsPath(n.Path) {
+				unowned = append(unowned, n.Path)
+			}
+			return
 		}
-
-		// Directories before files when same size
-		if a.IsDir != b.IsDir {
-			return a.IsDir
+		for _, child := range n.Children {
+			walk(child)
 		}
-
-		// Alphabetical as tiebreaker
-		return a.Name < b.Name
-	})
-}
-
-// cleanupEmptyDirs recursively removes empty directories from the tree.
-// Returns true if the node itself should be removed (is empty).
-func (tv *TreeView) cleanupEmptyDirs(node *tree.Node) bool {
-	if node == nil {
-		return false
 	}
+	walk(tv.root)
 
-	// Process children first (bottom-up)
-	var remaining []*tree.Node
-	for _, child := range node.Children {
-		if child.IsDir {
-			// Recursively clean up this directory
-			if !tv.cleanupEmptyDirs(child) {
-				remaining = append(remaining, child)
-			}
-		} else {
-			// Keep files
-			remaining = append(remaining, child)
-		}
+	for _, path := range unowned {
+		tv.QueueRemove(path)
 	}
-	node.Children = remaining
+	tv.Flush()
+}
 
-	// A directory is empty if it has no children (don't remove root)
-	if node.IsDir && len(node.Children) == 0 && node.Parent != nil {
-		return true
-	}
-	return false
+// Pending reports whether queued changes are waiting for Flush.
+func (tv *TreeView) Pending() bool {
+	return tv.agg != nil && tv.agg.Pending()
 }
 
-// detectFileType returns a human-readable file type based on the file extension.
-func detectFileType(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	// Programming languages
-	case ".go":
-		return "Go"
-	case ".py":
-		return "Python"
-	case ".js":
-		return "JavaScript"
-	case ".ts":
-		return "TypeScript"
-	case ".rs":
-		return "Rust"
-	case ".c", ".h":
-		return "C"
-	case ".cpp", ".cc", ".cxx", ".hpp":
-		return "C++"
-	case ".java":
-		return "Java"
-	case ".rb":
-		return "Ruby"
-	case ".sh", ".bash", ".zsh":
-		return "Shell"
-
-	// Data/Config
-	case ".json":
-		return "JSON"
-	case ".yaml", ".yml":
-		return "YAML"
-	case ".toml":
-		return "TOML"
-	case ".xml":
-		return "XML"
-
-	// Documentation
-	case ".md", ".markdown":
-		return "Markdown"
-	case ".txt":
-		return "Text"
-	case ".pdf":
-		return "PDF"
-
-	// Images
-	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp", ".ico":
-		return "Image"
-
-	// Video
-	case ".mp4", ".mov", ".avi", ".mkv", ".webm":
-		return "Video"
-
-	// Audio
-	case ".mp3", ".wav", ".ogg", ".flac", ".aac":
-		return "Audio"
-
-	// Archives
-	case ".zip", ".tar", ".gz", ".rar", ".7z", ".bz2", ".xz":
-		return "Archive"
-
-	// Executables and libraries
-	case ".exe":
-		return "Executable"
-	case ".dll", ".so", ".dylib", ".a":
-		return "Library"
-	case ".wasm":
-		return "WebAssembly"
-
-	// Database
-	case ".db", ".sqlite", ".sqlite3":
-		return "Database"
-
-	// Binary
-	case ".bin":
-		return "Binary"
-
-	default:
-		return "File"
+// Flush applies queued changes, updating aggregates and sort order only for
+// the affected directories, then refreshes the flat list.
+// Returns true if the tree changed.
+func (tv *TreeView) Flush() bool {
+	if tv.agg == nil || !tv.agg.Flush() {
+		return false
 	}
+	tv.refresh()
+	return true
 }
 
 // Tree view styles (following existing styles.go patterns).
@@ -735,6 +770,9 @@ var (
 	// Size color
 	treeSizeColor = lipgloss.Color("#00AAFF")
 
+	// Pin marker color
+	treePinnedColor = lipgloss.Color("#FFAA00")
+
 	// Percentage style (muted)
 	treePercentStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#666666"))
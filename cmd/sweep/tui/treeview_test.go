@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
 )
 
 // Helper to create a test tree structure.
@@ -902,6 +903,30 @@ func TestTreeViewRemoveFile(t *testing.T) {
 	}
 }
 
+func TestTreeViewQueueRename(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+	tv.selected["/test/dir1/file1.txt"] = true
+
+	tv.QueueRename("/test/dir1", "/test/moved")
+	tv.Flush()
+
+	if tv.agg.Lookup("/test/dir1") != nil {
+		t.Error("expected the old directory to be gone")
+	}
+	for _, path := range []string{"/test/moved/file1.txt", "/test/moved/file2.txt"} {
+		if tv.agg.Lookup(path) == nil {
+			t.Errorf("expected %s in the tree", path)
+		}
+	}
+	if !tv.selected["/test/moved/file1.txt"] || tv.selected["/test/dir1/file1.txt"] {
+		t.Error("expected the selection to follow the file")
+	}
+	if tv.root.LargeFileSize != 1024*1024*250 || tv.root.LargeFileCount != 3 {
+		t.Errorf("root aggregates = %d bytes in %d files, want them unchanged", tv.root.LargeFileSize, tv.root.LargeFileCount)
+	}
+}
+
 func TestTreeViewRemoveFileCleanupEmptyDirs(t *testing.T) {
 	root := createTestTree()
 	tv := NewTreeView(root)
@@ -1046,3 +1071,180 @@ func TestTreeViewAddFileMaintainsSortOrder(t *testing.T) {
 		t.Errorf("expected dir2 first after adding large file, got %s", tv.root.Children[0].Name)
 	}
 }
+
+func TestTreeViewQueueDefersUntilFlush(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	initialCount := len(tv.flat)
+	initialSize := tv.root.LargeFileSize
+
+	tv.QueueFile("/test/dir2/a.txt", 1024*1024*10, 1234567890)
+	tv.QueueFile("/test/dir2/a.txt", 1024*1024*20, 1234567891)
+	tv.QueueRemove("/test/dir2/file3.txt")
+
+	if !tv.Pending() {
+		t.Fatal("expected pending changes after queueing")
+	}
+	if len(tv.flat) != initialCount || tv.root.LargeFileSize != initialSize {
+		t.Error("expected queued changes not to apply before flush")
+	}
+
+	if !tv.Flush() {
+		t.Fatal("expected flush to report changes")
+	}
+	if tv.Pending() {
+		t.Error("expected no pending changes after flush")
+	}
+
+	// dir2 now holds only a.txt at its latest size
+	expectedRootSize := int64(1024 * 1024 * 220)
+	if tv.root.LargeFileSize != expectedRootSize {
+		t.Errorf("expected root LargeFileSize %d, got %d", expectedRootSize, tv.root.LargeFileSize)
+	}
+	if tv.root.LargeFileCount != 3 {
+		t.Errorf("expected root LargeFileCount 3, got %d", tv.root.LargeFileCount)
+	}
+}
+
+func TestTreeViewPinSurvivesRebuild(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	tv.MoveDown()
+	tv.MoveDown() // dir2
+	tv.TogglePin()
+	if !tv.IsPinned("/test/dir2") {
+		t.Fatal("expected dir2 to be pinned")
+	}
+
+	// Removing the last file prunes dir2; a new file recreates it collapsed
+	// unless it is pinned
+	tv.RemoveFile("/test/dir2/file3.txt")
+	tv.AddFile("/test/dir2/new.txt", 1024*1024*30, 1234567890)
+
+	if tv.indexOf("/test/dir2/new.txt") < 0 {
+		t.Error("expected pinned dir2 to be expanded after it was recreated")
+	}
+	if !strings.Contains(tv.View(80, 10), iconPinned) {
+		t.Error("expected pin marker in view")
+	}
+}
+
+func TestTreeViewPinExpandsAncestors(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	tv.AddFile("/test/deep/a/b/file.bin", 1024*1024*10, 1234567890)
+	tv.moveCursorTo("/test/deep")
+	tv.Toggle() // expand deep
+	tv.moveCursorTo("/test/deep/a")
+	tv.Toggle() // expand a
+	tv.moveCursorTo("/test/deep/a/b")
+	tv.TogglePin()
+
+	// Collapsing an ancestor by hand is undone on the next refresh
+	tv.lookup("/test/deep").Expanded = false
+	tv.refresh()
+	if tv.indexOf("/test/deep/a/b/file.bin") < 0 {
+		t.Error("expected ancestors of a pinned directory to stay expanded")
+	}
+}
+
+func TestTreeViewCursorFollowsNode(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	tv.moveCursorTo("/test/dir2/file3.txt")
+
+	// A larger directory sorts above the cursor
+	tv.AddFile("/test/huge/file.bin", 1024*1024*500, 1234567890)
+
+	if got := tv.Selected().Path; got != "/test/dir2/file3.txt" {
+		t.Errorf("expected cursor to stay on file3, got %s", got)
+	}
+}
+
+func TestTreeViewCursorStaysInPinnedDir(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	tv.AddFile("/test/dir2/file4.txt", 1024*1024*10, 1234567890)
+	tv.moveCursorTo("/test/dir2")
+	tv.TogglePin()
+	tv.moveCursorTo("/test/dir2/file3.txt")
+
+	tv.RemoveFile("/test/dir2/file3.txt")
+
+	if got := tv.Selected().Path; got != "/test/dir2" {
+		t.Errorf("expected cursor to move to pinned dir2, got %s", got)
+	}
+}
+
+func TestTreeViewCollapseUnpins(t *testing.T) {
+	root := createTestTree()
+	tv := NewTreeView(root)
+
+	tv.moveCursorTo("/test/dir2")
+	tv.TogglePin()
+	tv.Toggle() // collapse
+
+	if tv.IsPinned("/test/dir2") {
+		t.Error("expected collapsing to unpin dir2")
+	}
+	if tv.indexOf("/test/dir2/file3.txt") >= 0 {
+		t.Error("expected dir2 to stay collapsed")
+	}
+}
+
+func TestTreeViewRestoreState(t *testing.T) {
+	prev := NewTreeView(createTestTree())
+	prev.moveCursorTo("/test/dir1")
+	prev.TogglePin()
+	prev.moveCursorTo("/test/dir1/file2.txt")
+	prev.ToggleSelect()
+
+	// A reloaded tree starts with only the root expanded
+	root := createTestTree()
+	for _, child := range root.Children {
+		child.Expanded = false
+	}
+	tv := NewTreeView(root)
+	tv.RestoreState(prev)
+
+	if !tv.IsPinned("/test/dir1") || tv.indexOf("/test/dir1/file1.txt") < 0 {
+		t.Error("expected pinned dir1 to be expanded after reload")
+	}
+	if got := tv.Selected().Path; got != "/test/dir1/file2.txt" {
+		t.Errorf("expected cursor restored to file2, got %s", got)
+	}
+	if tv.SelectedCount() != 1 {
+		t.Errorf("expected selection restored, got %d", tv.SelectedCount())
+	}
+}
+
+func TestTreeViewEntries(t *testing.T) {
+	tv := NewTreeView(createTestTree())
+	if strings.Contains(tv.View(120, 10), "inodes") {
+		t.Error("expected inode counts hidden by default")
+	}
+
+	if !tv.ToggleEntries() {
+		t.Fatal("expected inode counts shown after toggling")
+	}
+	if tv.HasEntries() || !strings.Contains(tv.View(120, 10), "…") {
+		t.Error("expected a placeholder until the counts are read")
+	}
+
+	tv.SetEntries([]du.Dir{{Path: "/test/dir1", Files: 120000, Dirs: 3456}})
+	view := tv.View(120, 10)
+	if !strings.Contains(view, "123,456 inodes") {
+		t.Errorf("expected dir1's inode count, got:\n%s", view)
+	}
+
+	reloaded := NewTreeView(createTestTree())
+	reloaded.RestoreState(tv)
+	if !reloaded.HasEntries() || !strings.Contains(reloaded.View(120, 10), "123,456 inodes") {
+		t.Error("expected counts kept across a reload")
+	}
+}
@@ -0,0 +1,186 @@
+//go:build !lite
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [path]",
+	Short: "Print large file changes as they happen",
+	Long: `Print each large file the daemon sees created, modified, deleted, or
+renamed under path (the current directory by default) until interrupted.
+Files smaller than --min-size and paths matching --exclude are left out.
+A path the daemon hasn't indexed yet is indexed first, so it is watched.
+
+With -o jsonl each event is a JSON object on its own line, for scripts and
+other tools to react to:
+
+  {"time":"2025-06-01T14:03:11Z","type":"created","path":"/data/dump.tar","size":5368709120}
+
+type is created, modified, deleted, or renamed; a renamed directory has
+new_path, where it and everything in it moved. mod_time is set when the
+daemon reports it. Fields may be added in later versions, but existing
+ones keep their names and meaning. sweep watch exits non-zero if the
+daemon stops, so a supervisor can restart it.
+
+Examples:
+  sweep watch ~/Downloads --min-size 1G
+  sweep watch /data -o jsonl | jq -r 'select(.type == "created") | .path'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
+
+// errWatchStreamClosed is returned when the daemon ends the event stream,
+// as it does when it shuts down.
+var errWatchStreamClosed = errors.New("the daemon closed the event stream")
+
+// watchEventReport is one line of 'sweep watch -o jsonl'.
+type watchEventReport struct {
+	Time    time.Time  `json:"time"` // When sweep received the event
+	Type    string     `json:"type"` // created, modified, deleted, or renamed
+	Path    string     `json:"path"`
+	Size    int64      `json:"size"`
+	ModTime *time.Time `json:"mod_time,omitempty"`
+	NewPath string     `json:"new_path,omitempty"`
+}
+
+func runWatch(_ *cobra.Command, args []string) error {
+	format := viper.GetString("output")
+	switch format {
+	case "jsonl", "text", "", "pretty", "plain":
+	default:
+		return fmt.Errorf("unknown output format %q (available: text, jsonl)", format)
+	}
+	minSize, err := types.ParseSize(viper.GetString("min_size"))
+	if err != nil {
+		return fmt.Errorf("invalid min-size: %w", err)
+	}
+	exclude := viper.GetStringSlice("exclude")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	daemonClient, target, err := connectDaemon(ctx)
+	if err != nil {
+		return err
+	}
+	defer daemonClient.Close()
+
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if !target.IsRemote() {
+		if path, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolve path: %w", err)
+		}
+	}
+	if err := ensureWatched(ctx, daemonClient, path); err != nil {
+		return err
+	}
+
+	events, err := daemonClient.WatchLargeFiles(ctx, path, minSize, exclude)
+	if err != nil {
+		return fmt.Errorf("watch %s: %w", path, err)
+	}
+	if format != "jsonl" {
+		printInfo("Watching %s for files of %s or more (Ctrl+C to stop)", path, types.FormatSize(minSize))
+	}
+	err = streamWatchEvents(ctx, events, os.Stdout, format, time.Now)
+	if ctx.Err() != nil {
+		return nil // Interrupted
+	}
+	return err
+}
+
+// ensureWatched has the daemon index path if it hasn't, since the daemon
+// only watches what it indexed.
+func ensureWatched(ctx context.Context, daemonClient *client.Client, path string) error {
+	status, err := daemonClient.GetIndexStatus(ctx, path)
+	if err != nil {
+		return fmt.Errorf("get index status: %w", err)
+	}
+	if status.State != "not_indexed" {
+		return nil
+	}
+	if err := daemonClient.TriggerIndex(ctx, path, false); err != nil {
+		return fmt.Errorf("index %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Indexing %s so the daemon watches it\n", path)
+	return nil
+}
+
+// streamWatchEvents writes each event to w in format until events closes,
+// which returns errWatchStreamClosed, or ctx is done. now timestamps them.
+func streamWatchEvents(ctx context.Context, events <-chan client.FileEvent, w io.Writer, format string, now func() time.Time) error {
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return errWatchStreamClosed
+			}
+			report := newWatchEventReport(ev, now())
+			var err error
+			if format == "jsonl" {
+				err = enc.Encode(report)
+			} else {
+				err = writeWatchEventText(w, report)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newWatchEventReport builds the report of ev, received at now.
+func newWatchEventReport(ev client.FileEvent, now time.Time) watchEventReport {
+	report := watchEventReport{
+		Time:    now.UTC(),
+		Type:    ev.Type,
+		Path:    ev.Path,
+		Size:    ev.Size,
+		NewPath: ev.NewPath,
+	}
+	if ev.ModTime > 0 {
+		modTime := time.Unix(ev.ModTime, 0).UTC()
+		report.ModTime = &modTime
+	}
+	return report
+}
+
+// writeWatchEventText writes r as a line for people to read.
+func writeWatchEventText(w io.Writer, r watchEventReport) error {
+	path := r.Path
+	if r.NewPath != "" {
+		path += " → " + r.NewPath
+	}
+	size := ""
+	if r.Type != "deleted" && r.Type != "renamed" {
+		size = types.FormatSize(r.Size)
+	}
+	_, err := fmt.Fprintf(w, "%s  %-8s  %10s  %s\n", r.Time.Local().Format(time.TimeOnly), r.Type, size, path)
+	return err
+}
@@ -0,0 +1,76 @@
+//go:build !lite
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/client"
+)
+
+func TestStreamWatchEventsJSONL(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	events := make(chan client.FileEvent, 3)
+	events <- client.FileEvent{Type: "created", Path: "/data/dump.tar", Size: 5 << 30, ModTime: 1699999990}
+	events <- client.FileEvent{Type: "deleted", Path: "/data/old.iso"}
+	events <- client.FileEvent{Type: "renamed", Path: "/data/a", NewPath: "/data/b"}
+	close(events)
+
+	var buf bytes.Buffer
+	err := streamWatchEvents(context.Background(), events, &buf, "jsonl", func() time.Time { return now })
+	if !errors.Is(err, errWatchStreamClosed) {
+		t.Errorf("streamWatchEvents() = %v, want errWatchStreamClosed once the stream ends", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	var created map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &created); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[0], err)
+	}
+	want := map[string]any{
+		"time":     "2023-11-14T22:13:20Z",
+		"type":     "created",
+		"path":     "/data/dump.tar",
+		"size":     float64(5 << 30),
+		"mod_time": "2023-11-14T22:13:10Z",
+	}
+	for key, value := range want {
+		if created[key] != value {
+			t.Errorf("%s = %v, want %v", key, created[key], value)
+		}
+	}
+	if strings.Contains(lines[1], "mod_time") {
+		t.Errorf("deleted event %s has a mod_time", lines[1])
+	}
+	if !strings.Contains(lines[2], `"new_path":"/data/b"`) {
+		t.Errorf("renamed event %s lacks new_path", lines[2])
+	}
+}
+
+func TestStreamWatchEventsText(t *testing.T) {
+	events := make(chan client.FileEvent)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- streamWatchEvents(ctx, events, &buf, "text", time.Now)
+	}()
+	events <- client.FileEvent{Type: "renamed", Path: "/data/a", NewPath: "/data/b"}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("streamWatchEvents() = %v, want context.Canceled", err)
+	}
+	if !strings.Contains(buf.String(), "renamed") || !strings.Contains(buf.String(), "/data/a → /data/b") {
+		t.Errorf("text output = %q", buf.String())
+	}
+}
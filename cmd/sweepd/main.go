@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"os"
@@ -9,18 +11,36 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/jamesainslie/sweep/pkg/daemon"
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/throttle"
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
+// version is set by goreleaser or go build -ldflags.
+var version = "dev"
+
 func main() {
 	os.Exit(actualMain())
 }
 
 func actualMain() int {
+	// 'sweep doctor' compares the installed binary's version with sweep's
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-version") {
+		fmt.Printf("sweepd %s\n", version)
+		return 0
+	}
+
 	// Ensure XDG directories exist
 	if err := config.EnsureDataDir(); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create data dir: %v\n", err)
@@ -62,6 +82,7 @@ func actualMain() int {
 			Daily:      cfg.Logging.Rotation.Daily,
 		},
 		Components: cfg.Logging.Components,
+		BufferSize: daemon.LogBufferSize, // Served to clients by GetLogs
 	}
 	if err := logging.Init(logCfg); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logging: %v\n", err)
@@ -69,6 +90,9 @@ func actualMain() int {
 	}
 	defer logging.Close()
 
+	// Reopen the log file on SIGHUP for external logrotate setups
+	defer logging.ReopenOnHangup()()
+
 	log := logging.Get("daemon")
 
 	// Default paths
@@ -88,23 +112,126 @@ func actualMain() int {
 		return 1
 	}
 
-	// Parse min index size from config
-	var minIndexSize int64
-	if cfg.Daemon.MinIndexSize != "" {
-		if parsed, parseErr := parseSize(cfg.Daemon.MinIndexSize); parseErr == nil {
-			minIndexSize = parsed
-			log.Info("using configured min index size", "size", cfg.Daemon.MinIndexSize, "bytes", minIndexSize)
+	settings := daemonSettings(cfg, log)
+	if settings.MinLargeFileSize > 0 {
+		log.Info("using configured min index size", "size", cfg.Daemon.MinIndexSize, "bytes", settings.MinLargeFileSize)
+	}
+
+	var maxStoreSize int64
+	if cfg.Daemon.MaxStoreSize != "" {
+		if parsed, parseErr := parseSize(cfg.Daemon.MaxStoreSize); parseErr == nil {
+			maxStoreSize = parsed
+			log.Info("limiting index store size", "size", cfg.Daemon.MaxStoreSize, "bytes", maxStoreSize)
 		} else {
-			log.Warn("invalid min_index_size, using default", "value", cfg.Daemon.MinIndexSize, "error", parseErr)
+			log.Warn("invalid max_store_size, not limiting the store", "value", cfg.Daemon.MaxStoreSize, "error", parseErr)
 		}
 	}
 
+	snapshotInterval, err := filter.ParseDuration(cfg.Daemon.SnapshotInterval)
+	if err != nil {
+		log.Warn("invalid snapshot_interval, using 6h", "value", cfg.Daemon.SnapshotInterval, "error", err)
+		snapshotInterval = 6 * time.Hour
+	}
+	snapshotRetention, err := filter.ParseDuration(cfg.Daemon.SnapshotRetention)
+	if err != nil {
+		log.Warn("invalid snapshot_retention, using 90d", "value", cfg.Daemon.SnapshotRetention, "error", err)
+		snapshotRetention = 90 * 24 * time.Hour
+	}
+	compactInterval, err := filter.ParseDuration(cfg.Daemon.CompactInterval)
+	if err != nil {
+		log.Warn("invalid compact_interval, using 24h", "value", cfg.Daemon.CompactInterval, "error", err)
+		compactInterval = 24 * time.Hour
+	}
+	alertInterval, err := filter.ParseDuration(cfg.Alerts.Interval)
+	if err != nil || alertInterval <= 0 {
+		log.Warn("invalid alerts.interval, using 5m", "value", cfg.Alerts.Interval, "error", err)
+		alertInterval = daemon.DefaultAlertInterval
+	}
+	indexStagger, err := filter.ParseDuration(cfg.Daemon.IndexStagger)
+	if err != nil {
+		log.Warn("invalid index_stagger, using 10s", "value", cfg.Daemon.IndexStagger, "error", err)
+		indexStagger = daemon.DefaultIndexStagger
+	}
+	indexThrottle := applyThrottle(cfg.Daemon.Throttle, log)
+
+	indexMode, err := indexer.ParseMode(cfg.Daemon.IndexMode)
+	if err != nil {
+		log.Warn("invalid index_mode, using full", "error", err)
+		indexMode = indexer.ModeFull
+	}
+
+	symlinks, err := types.ParseSymlinkPolicy(cfg.Symlinks)
+	if err != nil {
+		log.Warn("invalid symlinks, using skip", "error", err)
+		symlinks = types.SymlinksSkip
+	}
+
+	watchSuggestions, err := daemon.ParseWatchSuggestions(cfg.Daemon.WatchSuggestions)
+	if err != nil {
+		log.Warn("invalid watch_suggestions, using suggest", "error", err)
+		watchSuggestions = daemon.SuggestWatches
+	}
+
+	network := mounts.Network{
+		Detect: cfg.Network.Detect,
+		Paths:  mountPoints("network.paths", cfg.Network.Paths, log),
+		Local:  mountPoints("network.local", cfg.Network.Local, log),
+	}
+
 	// Create server
 	srvCfg := daemon.Config{
-		SocketPath:       socketPath,
-		DataDir:          dataDir,
-		MinLargeFileSize: minIndexSize, // 0 means use default (10MB)
+		SocketPath:        socketPath,
+		DataDir:           dataDir,
+		MinLargeFileSize:  settings.MinLargeFileSize, // 0 means use default (10MB)
+		HashWarmer:        cfg.Daemon.HashWarmer,
+		HashWorkers:       cfg.Daemon.HashWorkers,
+		IndexMode:         indexMode,
+		Symlinks:          symlinks,
+		IncludeSnapshots:  cfg.IncludeSnapshots,
+		OneFileSystem:     cfg.OneFileSystem,
+		SkipMounts:        mountPoints("mounts.skip", cfg.Mounts.Skip, log),
+		CrossMounts:       mountPoints("mounts.cross", cfg.Mounts.Cross, log),
+		Network:           network,
+		NetworkWorkers:    cfg.Network.FileWorkers,
+		WatchNetwork:      cfg.Network.Watch,
+		StoreBackend:      cfg.Daemon.StoreBackend,
+		MaxStoreSize:      maxStoreSize,
+		MaxResults:        cfg.Daemon.MaxResults,
+		SnapshotInterval:  snapshotInterval,
+		SnapshotRetention: snapshotRetention,
+		CompactInterval:   compactInterval,
+		Throttle:          indexThrottle,
+		IndexStagger:      indexStagger,
+		ReadOnly:          cfg.ReadOnly,
+		PermanentRoots:    permanentRoots(cfg.Trash.PermanentRoots, log),
+		Backends:          deleteBackends(cfg.Trash, log),
+		Alerts:            settings.Alerts,
+		AlertActions:      settings.AlertActions,
+		AlertInterval:     alertInterval,
+		Hooks:             settings.Hooks,
+		WatchSuggestions:  watchSuggestions,
+		LogLevel:          settings.LogLevel,
+		LogComponents:     settings.LogComponents,
+		LoadSettings:      reloadSettings,
+		Version:           version,
 	}
+	if path, err := config.Path(); err == nil {
+		srvCfg.ConfigPath = path
+	}
+	if cfg.Daemon.Listen != "" {
+		tlsCfg, err := remoteTLS(cfg.Daemon.TLS)
+		if err != nil {
+			log.Error("cannot serve remote clients", "listen", cfg.Daemon.Listen, "error", err)
+			_ = daemon.WriteStatusError(statusPath, err) // Best-effort before exit
+			return 1
+		}
+		srvCfg.ListenAddr = cfg.Daemon.Listen
+		srvCfg.RemoteTLS = tlsCfg
+	}
+	srvCfg.HTTPAddr = cfg.Daemon.HTTP.Listen
+	srvCfg.HTTPToken = cfg.Daemon.HTTP.Token
+	srvCfg.HTTPOrigins = cfg.Daemon.HTTP.Origins
+	srvCfg.MetricsAddr = cfg.Daemon.MetricsAddr
 
 	srv, err := daemon.NewServer(srvCfg)
 	if err != nil {
@@ -113,6 +240,9 @@ func actualMain() int {
 		return 1
 	}
 
+	// Index and watch configured directories
+	srv.IndexPaths(settings.IndexPaths)
+
 	// Write PID file
 	if err := daemon.WritePIDFile(pidPath); err != nil {
 		log.Error("failed to write PID file", "error", err)
@@ -131,6 +261,11 @@ func actualMain() int {
 	}
 	defer func() { _ = daemon.RemoveStatus(statusPath) }() // Best-effort cleanup
 
+	// Run cleanup rules until shutdown
+	rulesCtx, stopRules := context.WithCancel(context.Background())
+	defer stopRules()
+	startRules(rulesCtx, cfg, log)
+
 	// Handle shutdown signals and RPC shutdown requests
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -142,12 +277,22 @@ func actualMain() int {
 		case <-srv.ShutdownChan():
 			log.Info("shutting down (RPC request)")
 		}
+		stopRules()
 		if err := srv.Close(); err != nil {
 			log.Warn("error during shutdown", "error", err)
 		}
 	}()
 
-	log.Info("daemon starting", "socket", socketPath)
+	log.Info("daemon starting", "version", version, "socket", socketPath)
+	if addr := srv.RemoteAddr(); addr != nil {
+		log.Info("serving remote clients with mutual TLS", "address", addr.String())
+	}
+	if addr := srv.HTTPAddr(); addr != nil {
+		log.Info("serving HTTP API", "address", addr.String())
+	}
+	if addr := srv.MetricsAddr(); addr != nil {
+		log.Info("serving metrics", "address", addr.String())
+	}
 
 	// Start serving
 	if err := srv.Serve(); err != nil {
@@ -158,6 +303,236 @@ func actualMain() int {
 	return 0
 }
 
+// daemonSettings returns the parts of cfg a reload applies again, warning
+// about and leaving out those that are invalid.
+func daemonSettings(cfg *config.Config, log *logging.Logger) daemon.Settings {
+	var minIndexSize int64
+	if cfg.Daemon.MinIndexSize != "" {
+		if parsed, err := parseSize(cfg.Daemon.MinIndexSize); err == nil {
+			minIndexSize = parsed
+		} else {
+			log.Warn("invalid min_index_size, using default", "value", cfg.Daemon.MinIndexSize, "error", err)
+		}
+	}
+	return daemon.Settings{
+		LogLevel:         cfg.Logging.Level,
+		LogComponents:    cfg.Logging.Components,
+		MinLargeFileSize: minIndexSize,
+		IndexPaths:       indexPaths(cfg.Daemon.IndexPaths, log),
+		Alerts:           alertThresholds(cfg.Alerts.Thresholds, log),
+		AlertActions:     alert.Actions{Desktop: cfg.Alerts.Desktop, Webhook: cfg.Alerts.Webhook, Exec: cfg.Alerts.Exec},
+		Hooks:            fileHooks(cfg.Hooks, log),
+	}
+}
+
+// reloadSettings reads the config file again for a reload.
+func reloadSettings() (daemon.Settings, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return daemon.Settings{}, err
+	}
+	return daemonSettings(cfg, logging.Get("daemon")), nil
+}
+
+// indexPaths resolves configured index paths to absolute directories,
+// skipping entries that do not exist.
+func indexPaths(configured []string, log *logging.Logger) []string {
+	var paths []string
+	for _, p := range configured {
+		expanded, err := config.ExpandPath(p)
+		if err == nil {
+			expanded, err = filepath.Abs(expanded)
+		}
+		if err != nil {
+			log.Warn("invalid index path", "path", p, "error", err)
+			continue
+		}
+		if info, err := os.Stat(expanded); err != nil || !info.IsDir() {
+			log.Warn("index path is not a directory, skipping", "path", expanded)
+			continue
+		}
+		paths = append(paths, expanded)
+	}
+	return paths
+}
+
+// permanentRoots expands the configured trash.permanent_roots, skipping
+// those that aren't absolute paths.
+func permanentRoots(configured []string, log *logging.Logger) []string {
+	var roots []string
+	for _, p := range configured {
+		expanded, err := config.ExpandPath(p)
+		if err == nil && !filepath.IsAbs(expanded) {
+			err = errors.New("must be an absolute path")
+		}
+		if err != nil {
+			log.Warn("invalid permanent root, skipping", "path", p, "error", err)
+			continue
+		}
+		roots = append(roots, expanded)
+	}
+	if len(roots) > 0 {
+		log.Info("allowing permanent deletes", "roots", roots)
+	}
+	return roots
+}
+
+// deleteBackends reads the staging and upload backend settings from the
+// trash section. A staging directory that isn't an absolute path disables
+// the staging backend.
+func deleteBackends(tc config.TrashConfig, log *logging.Logger) trash.BackendOptions {
+	opts := trash.BackendOptions{Upload: trash.Upload{Command: tc.Upload.Command}}
+	dir := tc.Staging.Dir
+	if dir == "" {
+		dir = config.DefaultStagingDir()
+	}
+	expanded, err := config.ExpandPath(dir)
+	if err == nil && !filepath.IsAbs(expanded) {
+		err = errors.New("must be an absolute path")
+	}
+	if err != nil {
+		log.Warn("invalid staging directory, staging disabled", "dir", dir, "error", err)
+		return opts
+	}
+	opts.Staging = trash.Staging{Dir: expanded, Days: tc.Staging.Days}
+	return opts
+}
+
+// mountPoints expands the mount points configured at key, skipping those
+// that aren't absolute paths.
+func mountPoints(key string, configured []string, log *logging.Logger) []string {
+	var paths []string
+	for _, p := range configured {
+		expanded, err := config.ExpandPath(p)
+		if err == nil && !filepath.IsAbs(expanded) {
+			err = errors.New("must be an absolute path")
+		}
+		if err != nil {
+			log.Warn("invalid mount point, skipping", "key", key, "path", p, "error", err)
+			continue
+		}
+		paths = append(paths, filepath.Clean(expanded))
+	}
+	return paths
+}
+
+// alertThresholds parses the configured alerts.thresholds, skipping those
+// that are invalid.
+func alertThresholds(configured []config.AlertThresholdConfig, log *logging.Logger) []alert.Threshold {
+	var thresholds []alert.Threshold
+	for _, c := range configured {
+		t := alert.Threshold{Name: c.Name}
+		path, err := config.ExpandPath(c.Path)
+		if err == nil && !filepath.IsAbs(path) {
+			err = errors.New("must be an absolute path")
+		}
+		t.Path = path
+		if err == nil && c.MaxSize != "" {
+			t.MaxSize, err = types.ParseSize(c.MaxSize)
+		}
+		if err == nil && c.MaxUsed != "" {
+			t.MaxUsed, err = alert.ParsePercent(c.MaxUsed)
+		}
+		if err == nil {
+			err = t.Validate()
+		}
+		if err != nil {
+			log.Warn("invalid alert threshold, skipping", "path", c.Path, "error", err)
+			continue
+		}
+		thresholds = append(thresholds, t)
+	}
+	if len(thresholds) > 0 {
+		log.Info("checking disk usage alerts", "thresholds", len(thresholds))
+	}
+	return thresholds
+}
+
+// fileHooks parses the configured hooks, skipping those that are invalid.
+func fileHooks(configured []config.HookConfig, log *logging.Logger) []daemon.Hook {
+	var hooks []daemon.Hook
+	for _, c := range configured {
+		h := daemon.Hook{Name: c.Name, Exclude: c.Exclude, Webhook: c.Webhook, Exec: c.Exec}
+		path, err := config.ExpandPath(c.Path)
+		if err == nil && !filepath.IsAbs(path) {
+			err = errors.New("must be an absolute path")
+		}
+		h.Path = path
+		for _, name := range c.Events {
+			if err != nil {
+				break
+			}
+			var event broadcaster.EventType
+			event, err = broadcaster.ParseEventType(name)
+			h.Events = append(h.Events, event)
+		}
+		if err == nil && c.MinSize != "" {
+			h.MinSize, err = types.ParseSize(c.MinSize)
+		}
+		if err == nil && h.Webhook == "" && h.Exec == "" {
+			err = errors.New("hook has neither webhook nor exec")
+		}
+		if err != nil {
+			log.Warn("invalid hook, skipping", "path", c.Path, "error", err)
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	if len(hooks) > 0 {
+		log.Info("running file event hooks", "hooks", len(hooks))
+	}
+	return hooks
+}
+
+// applyThrottle sets the daemon's nice level and returns what slows its
+// indexing down, or nil when nothing is configured to.
+func applyThrottle(cfg config.DaemonThrottleConfig, log *logging.Logger) *indexer.Throttle {
+	if cfg.Nice != 0 {
+		if err := throttle.SetNice(cfg.Nice); err != nil {
+			log.Warn("cannot set nice level", "nice", cfg.Nice, "error", err)
+		} else {
+			log.Info("running at lower priority", "nice", cfg.Nice)
+		}
+	}
+
+	var activeWithin time.Duration
+	if cfg.PauseOnActivity != "" {
+		parsed, err := filter.ParseDuration(cfg.PauseOnActivity)
+		if err != nil {
+			log.Warn("invalid throttle.pause_on_activity, not pausing for activity", "value", cfg.PauseOnActivity, "error", err)
+		}
+		activeWithin = parsed
+	}
+	if cfg.OpsPerSec < 0 {
+		log.Warn("invalid throttle.ops_per_sec, not limiting the rate", "value", cfg.OpsPerSec)
+		cfg.OpsPerSec = 0
+	}
+
+	t := &indexer.Throttle{OpsPerSec: cfg.OpsPerSec}
+	if p := throttle.NewPauser(cfg.PauseOnBattery, activeWithin); p != nil {
+		t.Paused = p.Paused
+	}
+	if t.OpsPerSec == 0 && t.Paused == nil {
+		return nil
+	}
+	log.Info("throttling indexing", "ops_per_sec", cfg.OpsPerSec,
+		"pause_on_battery", cfg.PauseOnBattery, "pause_on_activity", activeWithin)
+	return t
+}
+
+// remoteTLS loads the certificates for serving remote clients.
+func remoteTLS(cfg config.DaemonTLSConfig) (*tls.Config, error) {
+	paths := []*string{&cfg.Cert, &cfg.Key, &cfg.ClientCA}
+	for _, p := range paths {
+		expanded, err := config.ExpandPath(*p)
+		if err != nil {
+			return nil, err
+		}
+		*p = expanded
+	}
+	return daemon.ServerTLSConfig(cfg.Cert, cfg.Key, cfg.ClientCA)
+}
+
 // parseSize parses size strings like "10MB" to bytes.
 func parseSize(s string) (int64, error) {
 	s = strings.ToUpper(strings.TrimSpace(s))
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/rules"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// startRules runs the configured cleanup rules on their schedules until ctx
+// is cancelled. Invalid rules disable all of them rather than running a
+// partial set.
+func startRules(ctx context.Context, cfg *config.Config, log *logging.Logger) {
+	if len(cfg.Rules) == 0 {
+		return
+	}
+	list, err := rules.FromConfig(cfg.Rules)
+	if err != nil {
+		log.Error("invalid cleanup rules, none will run", "error", err)
+		return
+	}
+	if cfg.ReadOnly {
+		log.Info("read-only mode, cleanup rules will not run", "rules", len(list))
+		return
+	}
+
+	var m *manifest.Manifest
+	if cfg.Manifest.Enabled {
+		if m, err = manifest.New(cfg.Manifest.Path); err != nil {
+			log.Warn("cleanup rule runs will not be recorded", "error", err)
+		}
+	}
+
+	// Rules with the permanent backend delete only under permanent_roots,
+	// as DeleteFiles does
+	backends := deleteBackends(cfg.Trash, log)
+	backends.PermanentRoots = trash.NewPermanentRoots(permanentRoots(cfg.Trash.PermanentRoots, log))
+	scheduler := rules.NewScheduler(list, func(ctx context.Context, r rules.Rule) {
+		backend, err := trash.NewBackend(cmp.Or(r.Backend, cfg.Trash.Backend), backends)
+		if err != nil {
+			log.Error("cleanup rule not run", "rule", r.Name, "error", err)
+			return
+		}
+		log.Info("running cleanup rule", "rule", r.Name, "path", r.Path, "backend", backend.Name())
+		report, err := rules.Run(ctx, r, rules.RunOptions{Backend: backend, Manifest: m})
+		if err != nil {
+			log.Error("cleanup rule failed", "rule", r.Name, "error", err)
+		}
+		for _, f := range report.Failed {
+			log.Warn("cleanup rule could not delete file", "rule", r.Name, "path", f.Path, "error", f.Error)
+		}
+		log.Info("cleanup rule finished", "rule", r.Name,
+			"matched", len(report.Matched), "deleted", len(report.Deleted),
+			"bytes", report.DeletedBytes(), "manifest", report.Manifest)
+	})
+	for _, r := range list {
+		if !r.Schedule.IsZero() {
+			log.Info("scheduled cleanup rule", "rule", r.Name, "schedule", r.Schedule.String())
+		}
+	}
+	go scheduler.Run(ctx)
+}
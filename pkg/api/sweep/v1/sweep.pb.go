@@ -178,7 +178,7 @@ func (x FileEvent_EventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use FileEvent_EventType.Descriptor instead.
 func (FileEvent_EventType) EnumDescriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{15, 0}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{22, 0}
 }
 
 type TreeEvent_Type int32
@@ -187,6 +187,7 @@ const (
 	TreeEvent_CREATED  TreeEvent_Type = 0
 	TreeEvent_MODIFIED TreeEvent_Type = 1
 	TreeEvent_DELETED  TreeEvent_Type = 2
+	TreeEvent_RENAMED  TreeEvent_Type = 3 // A directory moved to new_path with everything in it
 )
 
 // Enum value maps for TreeEvent_Type.
@@ -195,11 +196,13 @@ var (
 		0: "CREATED",
 		1: "MODIFIED",
 		2: "DELETED",
+		3: "RENAMED",
 	}
 	TreeEvent_Type_value = map[string]int32{
 		"CREATED":  0,
 		"MODIFIED": 1,
 		"DELETED":  2,
+		"RENAMED":  3,
 	}
 )
 
@@ -227,7 +230,7 @@ func (x TreeEvent_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TreeEvent_Type.Descriptor instead.
 func (TreeEvent_Type) EnumDescriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{20, 0}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{30, 0}
 }
 
 type GetLargeFilesRequest struct {
@@ -235,7 +238,9 @@ type GetLargeFilesRequest struct {
 	Path    string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	MinSize int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
 	Exclude []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
-	Limit   int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Most files to stream; 0 uses the daemon's default (daemon.max_results).
+	// The last file streamed has truncated set when more files matched.
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	// Pattern matching (include patterns - files must match at least one if specified)
 	Include []string `protobuf:"bytes,5,rep,name=include,proto3" json:"include,omitempty"`
 	// File extensions to include (e.g., ".mp4", ".mkv")
@@ -252,8 +257,12 @@ type GetLargeFilesRequest struct {
 	// Sorting
 	SortBy         SortField `protobuf:"varint,11,opt,name=sort_by,json=sortBy,proto3,enum=sweep.v1.SortField" json:"sort_by,omitempty"`
 	SortDescending bool      `protobuf:"varint,12,opt,name=sort_descending,json=sortDescending,proto3" json:"sort_descending,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Continues a query from the next_page_token of the previous page's last
+	// file. The other fields must be the same as for the first page; the
+	// limit is the page size.
+	PageToken     string `protobuf:"bytes,13,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLargeFilesRequest) Reset() {
@@ -370,15 +379,28 @@ func (x *GetLargeFilesRequest) GetSortDescending() bool {
 	return false
 }
 
+func (x *GetLargeFilesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 type FileInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
-	ModTime       int64                  `protobuf:"varint,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
-	CreateTime    int64                  `protobuf:"varint,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
-	Owner         string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
-	Group         string                 `protobuf:"bytes,6,opt,name=group,proto3" json:"group,omitempty"`
-	Mode          uint32                 `protobuf:"varint,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Path       string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size       int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime    int64                  `protobuf:"varint,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	CreateTime int64                  `protobuf:"varint,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	Owner      string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	Group      string                 `protobuf:"bytes,6,opt,name=group,proto3" json:"group,omitempty"`
+	Mode       uint32                 `protobuf:"varint,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	Sharing    *Sharing               `protobuf:"bytes,8,opt,name=sharing,proto3" json:"sharing,omitempty"`      // Set when the file shares storage with other files
+	Truncated  bool                   `protobuf:"varint,9,opt,name=truncated,proto3" json:"truncated,omitempty"` // Set on the last file of GetLargeFiles when the limit cut the results short
+	// Set on the last file of a GetLargeFiles page when the request had a
+	// limit and more files matched; request it as page_token for the next page
+	NextPageToken string `protobuf:"bytes,10,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Cloud         *Cloud `protobuf:"bytes,11,opt,name=cloud,proto3" json:"cloud,omitempty"` // Set when the file is an online-only cloud placeholder
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -462,6 +484,165 @@ func (x *FileInfo) GetMode() uint32 {
 	return 0
 }
 
+func (x *FileInfo) GetSharing() *Sharing {
+	if x != nil {
+		return x.Sharing
+	}
+	return nil
+}
+
+func (x *FileInfo) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *FileInfo) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *FileInfo) GetCloud() *Cloud {
+	if x != nil {
+		return x.Cloud
+	}
+	return nil
+}
+
+// Storage a file shares through hard links or APFS clones.
+type Sharing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dev           uint64                 `protobuf:"varint,1,opt,name=dev,proto3" json:"dev,omitempty"`
+	Ino           uint64                 `protobuf:"varint,2,opt,name=ino,proto3" json:"ino,omitempty"`
+	Links         uint64                 `protobuf:"varint,3,opt,name=links,proto3" json:"links,omitempty"`                                // Hard links to the inode
+	CloneId       uint64                 `protobuf:"varint,4,opt,name=clone_id,json=cloneId,proto3" json:"clone_id,omitempty"`             // APFS clone ID; clones with the same ID share blocks
+	PrivateSize   int64                  `protobuf:"varint,5,opt,name=private_size,json=privateSize,proto3" json:"private_size,omitempty"` // Bytes of a clone that are its own
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sharing) Reset() {
+	*x = Sharing{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sharing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sharing) ProtoMessage() {}
+
+func (x *Sharing) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sharing.ProtoReflect.Descriptor instead.
+func (*Sharing) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Sharing) GetDev() uint64 {
+	if x != nil {
+		return x.Dev
+	}
+	return 0
+}
+
+func (x *Sharing) GetIno() uint64 {
+	if x != nil {
+		return x.Ino
+	}
+	return 0
+}
+
+func (x *Sharing) GetLinks() uint64 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+func (x *Sharing) GetCloneId() uint64 {
+	if x != nil {
+		return x.CloneId
+	}
+	return 0
+}
+
+func (x *Sharing) GetPrivateSize() int64 {
+	if x != nil {
+		return x.PrivateSize
+	}
+	return 0
+}
+
+// An online-only placeholder of a cloud storage provider, whose contents
+// are fetched on access.
+type Cloud struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`                          // iCloud, OneDrive, Dropbox or Google Drive; empty if unknown
+	OnDiskSize    int64                  `protobuf:"varint,2,opt,name=on_disk_size,json=onDiskSize,proto3" json:"on_disk_size,omitempty"` // Bytes stored locally
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cloud) Reset() {
+	*x = Cloud{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cloud) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cloud) ProtoMessage() {}
+
+func (x *Cloud) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cloud.ProtoReflect.Descriptor instead.
+func (*Cloud) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Cloud) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Cloud) GetOnDiskSize() int64 {
+	if x != nil {
+		return x.OnDiskSize
+	}
+	return 0
+}
+
 type GetIndexStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
@@ -471,7 +652,7 @@ type GetIndexStatusRequest struct {
 
 func (x *GetIndexStatusRequest) Reset() {
 	*x = GetIndexStatusRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[2]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -483,7 +664,7 @@ func (x *GetIndexStatusRequest) String() string {
 func (*GetIndexStatusRequest) ProtoMessage() {}
 
 func (x *GetIndexStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[2]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,7 +677,7 @@ func (x *GetIndexStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIndexStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetIndexStatusRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{2}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetIndexStatusRequest) GetPath() string {
@@ -507,21 +688,24 @@ func (x *GetIndexStatusRequest) GetPath() string {
 }
 
 type IndexStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	State         IndexState             `protobuf:"varint,2,opt,name=state,proto3,enum=sweep.v1.IndexState" json:"state,omitempty"`
-	FilesIndexed  int64                  `protobuf:"varint,3,opt,name=files_indexed,json=filesIndexed,proto3" json:"files_indexed,omitempty"`
-	DirsIndexed   int64                  `protobuf:"varint,4,opt,name=dirs_indexed,json=dirsIndexed,proto3" json:"dirs_indexed,omitempty"`
-	TotalSize     int64                  `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
-	LastUpdated   int64                  `protobuf:"varint,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
-	Progress      float32                `protobuf:"fixed32,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Path         string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	State        IndexState             `protobuf:"varint,2,opt,name=state,proto3,enum=sweep.v1.IndexState" json:"state,omitempty"`
+	FilesIndexed int64                  `protobuf:"varint,3,opt,name=files_indexed,json=filesIndexed,proto3" json:"files_indexed,omitempty"`
+	DirsIndexed  int64                  `protobuf:"varint,4,opt,name=dirs_indexed,json=dirsIndexed,proto3" json:"dirs_indexed,omitempty"`
+	TotalSize    int64                  `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	LastUpdated  int64                  `protobuf:"varint,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	Progress     float32                `protobuf:"fixed32,7,opt,name=progress,proto3" json:"progress,omitempty"`
+	// How the path was indexed: "full" stores every entry, "aggregates" only
+	// directory totals and large files.
+	IndexMode     string `protobuf:"bytes,8,opt,name=index_mode,json=indexMode,proto3" json:"index_mode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *IndexStatus) Reset() {
 	*x = IndexStatus{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[3]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -533,7 +717,7 @@ func (x *IndexStatus) String() string {
 func (*IndexStatus) ProtoMessage() {}
 
 func (x *IndexStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[3]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -546,7 +730,7 @@ func (x *IndexStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IndexStatus.ProtoReflect.Descriptor instead.
 func (*IndexStatus) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{3}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *IndexStatus) GetPath() string {
@@ -598,6 +782,13 @@ func (x *IndexStatus) GetProgress() float32 {
 	return 0
 }
 
+func (x *IndexStatus) GetIndexMode() string {
+	if x != nil {
+		return x.IndexMode
+	}
+	return ""
+}
+
 type TriggerIndexRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
@@ -608,7 +799,7 @@ type TriggerIndexRequest struct {
 
 func (x *TriggerIndexRequest) Reset() {
 	*x = TriggerIndexRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[4]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -620,7 +811,7 @@ func (x *TriggerIndexRequest) String() string {
 func (*TriggerIndexRequest) ProtoMessage() {}
 
 func (x *TriggerIndexRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[4]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,7 +824,7 @@ func (x *TriggerIndexRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerIndexRequest.ProtoReflect.Descriptor instead.
 func (*TriggerIndexRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{4}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *TriggerIndexRequest) GetPath() string {
@@ -660,7 +851,7 @@ type TriggerIndexResponse struct {
 
 func (x *TriggerIndexResponse) Reset() {
 	*x = TriggerIndexResponse{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[5]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -672,7 +863,7 @@ func (x *TriggerIndexResponse) String() string {
 func (*TriggerIndexResponse) ProtoMessage() {}
 
 func (x *TriggerIndexResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[5]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,7 +876,7 @@ func (x *TriggerIndexResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerIndexResponse.ProtoReflect.Descriptor instead.
 func (*TriggerIndexResponse) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{5}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TriggerIndexResponse) GetStarted() bool {
@@ -711,7 +902,7 @@ type WatchIndexProgressRequest struct {
 
 func (x *WatchIndexProgressRequest) Reset() {
 	*x = WatchIndexProgressRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[6]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -723,7 +914,7 @@ func (x *WatchIndexProgressRequest) String() string {
 func (*WatchIndexProgressRequest) ProtoMessage() {}
 
 func (x *WatchIndexProgressRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[6]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -736,7 +927,7 @@ func (x *WatchIndexProgressRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchIndexProgressRequest.ProtoReflect.Descriptor instead.
 func (*WatchIndexProgressRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{6}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *WatchIndexProgressRequest) GetPath() string {
@@ -760,7 +951,7 @@ type IndexProgress struct {
 
 func (x *IndexProgress) Reset() {
 	*x = IndexProgress{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[7]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -772,7 +963,7 @@ func (x *IndexProgress) String() string {
 func (*IndexProgress) ProtoMessage() {}
 
 func (x *IndexProgress) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[7]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -785,7 +976,7 @@ func (x *IndexProgress) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IndexProgress.ProtoReflect.Descriptor instead.
 func (*IndexProgress) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{7}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *IndexProgress) GetPath() string {
@@ -838,7 +1029,7 @@ type GetDaemonStatusRequest struct {
 
 func (x *GetDaemonStatusRequest) Reset() {
 	*x = GetDaemonStatusRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[8]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -850,7 +1041,7 @@ func (x *GetDaemonStatusRequest) String() string {
 func (*GetDaemonStatusRequest) ProtoMessage() {}
 
 func (x *GetDaemonStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[8]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -863,7 +1054,7 @@ func (x *GetDaemonStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDaemonStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetDaemonStatusRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{8}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{10}
 }
 
 type DaemonStatus struct {
@@ -874,13 +1065,21 @@ type DaemonStatus struct {
 	WatchedPaths      []string               `protobuf:"bytes,4,rep,name=watched_paths,json=watchedPaths,proto3" json:"watched_paths,omitempty"`
 	CacheSizeBytes    int64                  `protobuf:"varint,5,opt,name=cache_size_bytes,json=cacheSizeBytes,proto3" json:"cache_size_bytes,omitempty"`
 	TotalFilesIndexed int64                  `protobuf:"varint,6,opt,name=total_files_indexed,json=totalFilesIndexed,proto3" json:"total_files_indexed,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	HashWarmer        *HashWarmerStatus      `protobuf:"bytes,7,opt,name=hash_warmer,json=hashWarmer,proto3" json:"hash_warmer,omitempty"`
+	// Index mode used for new indexes ("full" or "aggregates").
+	IndexMode     string         `protobuf:"bytes,8,opt,name=index_mode,json=indexMode,proto3" json:"index_mode,omitempty"`
+	Version       string         `protobuf:"bytes,9,opt,name=version,proto3" json:"version,omitempty"` // The daemon's version
+	Roots         []*RootStatus  `protobuf:"bytes,10,rep,name=roots,proto3" json:"roots,omitempty"`    // Indexed and watched roots, by path
+	Watcher       *WatcherStatus `protobuf:"bytes,11,opt,name=watcher,proto3" json:"watcher,omitempty"`
+	Store         *StoreStatus   `protobuf:"bytes,12,opt,name=store,proto3" json:"store,omitempty"`
+	RecentErrors  []*DaemonError `protobuf:"bytes,13,rep,name=recent_errors,json=recentErrors,proto3" json:"recent_errors,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DaemonStatus) Reset() {
 	*x = DaemonStatus{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[9]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -892,7 +1091,7 @@ func (x *DaemonStatus) String() string {
 func (*DaemonStatus) ProtoMessage() {}
 
 func (x *DaemonStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[9]
+	mi := &file_sweep_v1_sweep_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -905,7 +1104,7 @@ func (x *DaemonStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DaemonStatus.ProtoReflect.Descriptor instead.
 func (*DaemonStatus) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{9}
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DaemonStatus) GetRunning() bool {
@@ -950,64 +1149,91 @@ func (x *DaemonStatus) GetTotalFilesIndexed() int64 {
 	return 0
 }
 
-type ShutdownRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DaemonStatus) GetHashWarmer() *HashWarmerStatus {
+	if x != nil {
+		return x.HashWarmer
+	}
+	return nil
 }
 
-func (x *ShutdownRequest) Reset() {
-	*x = ShutdownRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DaemonStatus) GetIndexMode() string {
+	if x != nil {
+		return x.IndexMode
+	}
+	return ""
 }
 
-func (x *ShutdownRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DaemonStatus) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
 }
 
-func (*ShutdownRequest) ProtoMessage() {}
+func (x *DaemonStatus) GetRoots() []*RootStatus {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
 
-func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[10]
+func (x *DaemonStatus) GetWatcher() *WatcherStatus {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Watcher
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ShutdownRequest.ProtoReflect.Descriptor instead.
-func (*ShutdownRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{10}
+func (x *DaemonStatus) GetStore() *StoreStatus {
+	if x != nil {
+		return x.Store
+	}
+	return nil
 }
 
-type ShutdownResponse struct {
+func (x *DaemonStatus) GetRecentErrors() []*DaemonError {
+	if x != nil {
+		return x.RecentErrors
+	}
+	return nil
+}
+
+// RootStatus is the state of one indexed or watched root.
+type RootStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	State         IndexState             `protobuf:"varint,2,opt,name=state,proto3,enum=sweep.v1.IndexState" json:"state,omitempty"`
+	FilesIndexed  int64                  `protobuf:"varint,3,opt,name=files_indexed,json=filesIndexed,proto3" json:"files_indexed,omitempty"`
+	DirsIndexed   int64                  `protobuf:"varint,4,opt,name=dirs_indexed,json=dirsIndexed,proto3" json:"dirs_indexed,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`       // Bytes of the files found by the last index
+	LastIndexed   int64                  `protobuf:"varint,6,opt,name=last_indexed,json=lastIndexed,proto3" json:"last_indexed,omitempty"` // Unix seconds the last index completed; 0 if before the daemon started
+	LastQueried   int64                  `protobuf:"varint,7,opt,name=last_queried,json=lastQueried,proto3" json:"last_queried,omitempty"` // Unix seconds of the latest query; 0 if never
+	IndexMode     string                 `protobuf:"bytes,8,opt,name=index_mode,json=indexMode,proto3" json:"index_mode,omitempty"`
+	Saved         bool                   `protobuf:"varint,9,opt,name=saved,proto3" json:"saved,omitempty"` // Added with AddWatch
+	Paused        bool                   `protobuf:"varint,10,opt,name=paused,proto3" json:"paused,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`                                // Why the last index failed
+	Progress      float32                `protobuf:"fixed32,12,opt,name=progress,proto3" json:"progress,omitempty"`                        // Share of an index in progress done, estimated from the last index; 0 if unknown
+	CurrentPath   string                 `protobuf:"bytes,13,opt,name=current_path,json=currentPath,proto3" json:"current_path,omitempty"` // Where an index in progress has got to
+	Queued        bool                   `protobuf:"varint,14,opt,name=queued,proto3" json:"queued,omitempty"`                             // Waiting its turn to be indexed at startup
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShutdownResponse) Reset() {
-	*x = ShutdownResponse{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[11]
+func (x *RootStatus) Reset() {
+	*x = RootStatus{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShutdownResponse) String() string {
+func (x *RootStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShutdownResponse) ProtoMessage() {}
+func (*RootStatus) ProtoMessage() {}
 
-func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[11]
+func (x *RootStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1018,40 +1244,3050 @@ func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShutdownResponse.ProtoReflect.Descriptor instead.
-func (*ShutdownResponse) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RootStatus.ProtoReflect.Descriptor instead.
+func (*RootStatus) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ShutdownResponse) GetSuccess() bool {
+func (x *RootStatus) GetPath() string {
 	if x != nil {
-		return x.Success
+		return x.Path
 	}
-	return false
+	return ""
 }
 
-type ClearCacheRequest struct {
+func (x *RootStatus) GetState() IndexState {
+	if x != nil {
+		return x.State
+	}
+	return IndexState_INDEX_STATE_UNKNOWN
+}
+
+func (x *RootStatus) GetFilesIndexed() int64 {
+	if x != nil {
+		return x.FilesIndexed
+	}
+	return 0
+}
+
+func (x *RootStatus) GetDirsIndexed() int64 {
+	if x != nil {
+		return x.DirsIndexed
+	}
+	return 0
+}
+
+func (x *RootStatus) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *RootStatus) GetLastIndexed() int64 {
+	if x != nil {
+		return x.LastIndexed
+	}
+	return 0
+}
+
+func (x *RootStatus) GetLastQueried() int64 {
+	if x != nil {
+		return x.LastQueried
+	}
+	return 0
+}
+
+func (x *RootStatus) GetIndexMode() string {
+	if x != nil {
+		return x.IndexMode
+	}
+	return ""
+}
+
+func (x *RootStatus) GetSaved() bool {
+	if x != nil {
+		return x.Saved
+	}
+	return false
+}
+
+func (x *RootStatus) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *RootStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RootStatus) GetProgress() float32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *RootStatus) GetCurrentPath() string {
+	if x != nil {
+		return x.CurrentPath
+	}
+	return ""
+}
+
+func (x *RootStatus) GetQueued() bool {
+	if x != nil {
+		return x.Queued
+	}
+	return false
+}
+
+// WatcherStatus reports the filesystem watcher's activity since the daemon
+// started.
+type WatcherStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Directories   int64                  `protobuf:"varint,1,opt,name=directories,proto3" json:"directories,omitempty"`              // Directories with a filesystem watch
+	Events        int64                  `protobuf:"varint,2,opt,name=events,proto3" json:"events,omitempty"`                        // Filesystem events received
+	Errors        int64                  `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`                        // Errors reported by the watcher
+	LastEvent     int64                  `protobuf:"varint,4,opt,name=last_event,json=lastEvent,proto3" json:"last_event,omitempty"` // Unix seconds of the latest event; 0 if none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherStatus) Reset() {
+	*x = WatcherStatus{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherStatus) ProtoMessage() {}
+
+func (x *WatcherStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherStatus.ProtoReflect.Descriptor instead.
+func (*WatcherStatus) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatcherStatus) GetDirectories() int64 {
+	if x != nil {
+		return x.Directories
+	}
+	return 0
+}
+
+func (x *WatcherStatus) GetEvents() int64 {
+	if x != nil {
+		return x.Events
+	}
+	return 0
+}
+
+func (x *WatcherStatus) GetErrors() int64 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *WatcherStatus) GetLastEvent() int64 {
+	if x != nil {
+		return x.LastEvent
+	}
+	return 0
+}
+
+// StoreStatus reports the index store.
+type StoreStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backend       string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"` // "badger" or "sqlite"
+	SizeBytes     int64                  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	SchemaVersion int32                  `protobuf:"varint,3,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Migrating     bool                   `protobuf:"varint,4,opt,name=migrating,proto3" json:"migrating,omitempty"`
+	LastCompacted int64                  `protobuf:"varint,5,opt,name=last_compacted,json=lastCompacted,proto3" json:"last_compacted,omitempty"` // Unix seconds; 0 if not since the daemon started
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreStatus) Reset() {
+	*x = StoreStatus{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreStatus) ProtoMessage() {}
+
+func (x *StoreStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreStatus.ProtoReflect.Descriptor instead.
+func (*StoreStatus) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StoreStatus) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *StoreStatus) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *StoreStatus) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *StoreStatus) GetMigrating() bool {
+	if x != nil {
+		return x.Migrating
+	}
+	return false
+}
+
+func (x *StoreStatus) GetLastCompacted() int64 {
+	if x != nil {
+		return x.LastCompacted
+	}
+	return 0
+}
+
+// DaemonError is an error the daemon logged.
+type DaemonError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`          // Unix seconds
+	Component     string                 `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"` // "indexer", "watcher", "store", ...
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`           // The path concerned, if any
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DaemonError) Reset() {
+	*x = DaemonError{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DaemonError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DaemonError) ProtoMessage() {}
+
+func (x *DaemonError) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DaemonError.ProtoReflect.Descriptor instead.
+func (*DaemonError) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DaemonError) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *DaemonError) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *DaemonError) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DaemonError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// HashWarmerStatus reports background hashing of large files.
+type HashWarmerStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Queued        int64                  `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`
+	FilesHashed   int64                  `protobuf:"varint,4,opt,name=files_hashed,json=filesHashed,proto3" json:"files_hashed,omitempty"`
+	BytesHashed   int64                  `protobuf:"varint,5,opt,name=bytes_hashed,json=bytesHashed,proto3" json:"bytes_hashed,omitempty"`
+	CurrentPath   string                 `protobuf:"bytes,6,opt,name=current_path,json=currentPath,proto3" json:"current_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashWarmerStatus) Reset() {
+	*x = HashWarmerStatus{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashWarmerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashWarmerStatus) ProtoMessage() {}
+
+func (x *HashWarmerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashWarmerStatus.ProtoReflect.Descriptor instead.
+func (*HashWarmerStatus) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HashWarmerStatus) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *HashWarmerStatus) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *HashWarmerStatus) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *HashWarmerStatus) GetFilesHashed() int64 {
+	if x != nil {
+		return x.FilesHashed
+	}
+	return 0
+}
+
+func (x *HashWarmerStatus) GetBytesHashed() int64 {
+	if x != nil {
+		return x.BytesHashed
+	}
+	return 0
+}
+
+func (x *HashWarmerStatus) GetCurrentPath() string {
+	if x != nil {
+		return x.CurrentPath
+	}
+	return ""
+}
+
+type ShutdownRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShutdownRequest) Reset() {
+	*x = ShutdownRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownRequest) ProtoMessage() {}
+
+func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownRequest.ProtoReflect.Descriptor instead.
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{17}
+}
+
+type ShutdownResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShutdownResponse) Reset() {
+	*x = ShutdownResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownResponse) ProtoMessage() {}
+
+func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownResponse.ProtoReflect.Descriptor instead.
+func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ShutdownResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ClearCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearCacheRequest) Reset() {
+	*x = ClearCacheRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCacheRequest) ProtoMessage() {}
+
+func (x *ClearCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCacheRequest.ProtoReflect.Descriptor instead.
+func (*ClearCacheRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ClearCacheRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ClearCacheResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	EntriesCleared int64                  `protobuf:"varint,2,opt,name=entries_cleared,json=entriesCleared,proto3" json:"entries_cleared,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearCacheResponse) Reset() {
+	*x = ClearCacheResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCacheResponse) ProtoMessage() {}
+
+func (x *ClearCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCacheResponse.ProtoReflect.Descriptor instead.
+func (*ClearCacheResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ClearCacheResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ClearCacheResponse) GetEntriesCleared() int64 {
+	if x != nil {
+		return x.EntriesCleared
+	}
+	return 0
+}
+
+// Request to watch for file system changes
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	Exclude       []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+// Event emitted when a file changes
+type FileEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          FileEvent_EventType    `protobuf:"varint,1,opt,name=type,proto3,enum=sweep.v1.FileEvent_EventType" json:"type,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	NewPath       string                 `protobuf:"bytes,5,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"` // RENAMED directories: where path moved, with everything in it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileEvent) Reset() {
+	*x = FileEvent{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileEvent) ProtoMessage() {}
+
+func (x *FileEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileEvent.ProtoReflect.Descriptor instead.
+func (*FileEvent) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FileEvent) GetType() FileEvent_EventType {
+	if x != nil {
+		return x.Type
+	}
+	return FileEvent_CREATED
+}
+
+func (x *FileEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileEvent) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileEvent) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *FileEvent) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+// Tree node for hierarchical display
+type TreeNode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	IsDir bool                   `protobuf:"varint,3,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	// For files
+	Size     int64  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime  int64  `protobuf:"varint,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	FileType string `protobuf:"bytes,6,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	// For directories
+	LargeFileSize  int64 `protobuf:"varint,7,opt,name=large_file_size,json=largeFileSize,proto3" json:"large_file_size,omitempty"`
+	LargeFileCount int32 `protobuf:"varint,8,opt,name=large_file_count,json=largeFileCount,proto3" json:"large_file_count,omitempty"`
+	// Children (only populated if expanded)
+	Children      []*TreeNode `protobuf:"bytes,9,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreeNode) Reset() {
+	*x = TreeNode{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeNode) ProtoMessage() {}
+
+func (x *TreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeNode.ProtoReflect.Descriptor instead.
+func (*TreeNode) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TreeNode) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TreeNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TreeNode) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *TreeNode) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TreeNode) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *TreeNode) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *TreeNode) GetLargeFileSize() int64 {
+	if x != nil {
+		return x.LargeFileSize
+	}
+	return 0
+}
+
+func (x *TreeNode) GetLargeFileCount() int32 {
+	if x != nil {
+		return x.LargeFileCount
+	}
+	return 0
+}
+
+func (x *TreeNode) GetChildren() []*TreeNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type GetTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	Exclude       []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	MaxDepth      int32                  `protobuf:"varint,4,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"` // 0 = unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTreeRequest) Reset() {
+	*x = GetTreeRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTreeRequest) ProtoMessage() {}
+
+func (x *GetTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetTreeRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetTreeRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *GetTreeRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *GetTreeRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *GetTreeRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+type GetTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          *TreeNode              `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	TotalIndexed  int64                  `protobuf:"varint,2,opt,name=total_indexed,json=totalIndexed,proto3" json:"total_indexed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTreeResponse) Reset() {
+	*x = GetTreeResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTreeResponse) ProtoMessage() {}
+
+func (x *GetTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetTreeResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetTreeResponse) GetRoot() *TreeNode {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *GetTreeResponse) GetTotalIndexed() int64 {
+	if x != nil {
+		return x.TotalIndexed
+	}
+	return 0
+}
+
+type GetDirSizesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	MaxDepth      int32                  `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"` // Levels below root; 0 = unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDirSizesRequest) Reset() {
+	*x = GetDirSizesRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDirSizesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDirSizesRequest) ProtoMessage() {}
+
+func (x *GetDirSizesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDirSizesRequest.ProtoReflect.Descriptor instead.
+func (*GetDirSizesRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetDirSizesRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *GetDirSizesRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+// Total size and file count of a directory, including its subdirectories
+type DirSize struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Files         int64                  `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	Depth         int32                  `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`     // Levels below the requested root
+	Dirs          int64                  `protobuf:"varint,5,opt,name=dirs,proto3" json:"dirs,omitempty"`       // Directories beneath it
+	Entries       int64                  `protobuf:"varint,6,opt,name=entries,proto3" json:"entries,omitempty"` // Files and directories directly in it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirSize) Reset() {
+	*x = DirSize{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirSize) ProtoMessage() {}
+
+func (x *DirSize) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirSize.ProtoReflect.Descriptor instead.
+func (*DirSize) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DirSize) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DirSize) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DirSize) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *DirSize) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *DirSize) GetDirs() int64 {
+	if x != nil {
+		return x.Dirs
+	}
+	return 0
+}
+
+func (x *DirSize) GetEntries() int64 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+type GetDirSizesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dirs          []*DirSize             `protobuf:"bytes,1,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	IndexMode     string                 `protobuf:"bytes,2,opt,name=index_mode,json=indexMode,proto3" json:"index_mode,omitempty"` // "full" or "aggregates"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDirSizesResponse) Reset() {
+	*x = GetDirSizesResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDirSizesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDirSizesResponse) ProtoMessage() {}
+
+func (x *GetDirSizesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDirSizesResponse.ProtoReflect.Descriptor instead.
+func (*GetDirSizesResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetDirSizesResponse) GetDirs() []*DirSize {
+	if x != nil {
+		return x.Dirs
+	}
+	return nil
+}
+
+func (x *GetDirSizesResponse) GetIndexMode() string {
+	if x != nil {
+		return x.IndexMode
+	}
+	return ""
+}
+
+// Request to watch for tree changes
+type WatchTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTreeRequest) Reset() {
+	*x = WatchTreeRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTreeRequest) ProtoMessage() {}
+
+func (x *WatchTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTreeRequest.ProtoReflect.Descriptor instead.
+func (*WatchTreeRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchTreeRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *WatchTreeRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+// Event emitted when a tree node changes
+type TreeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          TreeEvent_Type         `protobuf:"varint,1,opt,name=type,proto3,enum=sweep.v1.TreeEvent_Type" json:"type,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	ParentPath    string                 `protobuf:"bytes,5,opt,name=parent_path,json=parentPath,proto3" json:"parent_path,omitempty"`
+	NewPath       string                 `protobuf:"bytes,6,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreeEvent) Reset() {
+	*x = TreeEvent{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeEvent) ProtoMessage() {}
+
+func (x *TreeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeEvent.ProtoReflect.Descriptor instead.
+func (*TreeEvent) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TreeEvent) GetType() TreeEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TreeEvent_CREATED
+}
+
+func (x *TreeEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TreeEvent) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TreeEvent) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *TreeEvent) GetParentPath() string {
+	if x != nil {
+		return x.ParentPath
+	}
+	return ""
+}
+
+func (x *TreeEvent) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+// Request to move files to the trash
+type DeleteFilesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Paths  []string               `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`                  // Absolute paths of files or directories
+	DryRun bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // Report what would be deleted without deleting
+	// Delete permanently instead of trashing. Only paths under the daemon's
+	// trash.permanent_roots are deleted; the rest fail.
+	Permanent bool `protobuf:"varint,3,opt,name=permanent,proto3" json:"permanent,omitempty"`
+	// Delete backend: trash, permanent, staging, or upload; empty trashes,
+	// or deletes permanently with permanent. Staging and upload use the
+	// daemon's trash.staging and trash.upload settings.
+	Backend       string `protobuf:"bytes,4,opt,name=backend,proto3" json:"backend,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFilesRequest) Reset() {
+	*x = DeleteFilesRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFilesRequest) ProtoMessage() {}
+
+func (x *DeleteFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFilesRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFilesRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteFilesRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *DeleteFilesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DeleteFilesRequest) GetPermanent() bool {
+	if x != nil {
+		return x.Permanent
+	}
+	return false
+}
+
+func (x *DeleteFilesRequest) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+// Result of deleting one file
+type DeleteProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Deleted       bool                   `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`  // Moved to the trash, or deleted with permanent (or would be, in a dry run)
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`       // Why the file wasn't deleted
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`        // Size of the file, or of everything in the directory
+	Current       int32                  `protobuf:"varint,5,opt,name=current,proto3" json:"current,omitempty"`  // Files finished so far, including this one
+	Total         int32                  `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`      // Files in the request
+	Location      string                 `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"` // Where the staging backend moved it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProgress) Reset() {
+	*x = DeleteProgress{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProgress) ProtoMessage() {}
+
+func (x *DeleteProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProgress.ProtoReflect.Descriptor instead.
+func (*DeleteProgress) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteProgress) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeleteProgress) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *DeleteProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeleteProgress) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DeleteProgress) GetCurrent() int32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *DeleteProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *DeleteProgress) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+// Request to export the index
+type ExportIndexRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"` // Empty exports every indexed path
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportIndexRequest) Reset() {
+	*x = ExportIndexRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportIndexRequest) ProtoMessage() {}
+
+func (x *ExportIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportIndexRequest.ProtoReflect.Descriptor instead.
+func (*ExportIndexRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ExportIndexRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+// A file or directory in the index. Indexes built in aggregates mode only
+// hold their large files.
+type IndexEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"` // For directories, the size of the files beneath
+	ModTime       int64                  `protobuf:"varint,3,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	IsDir         bool                   `protobuf:"varint,4,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Files         int64                  `protobuf:"varint,5,opt,name=files,proto3" json:"files,omitempty"`                      // Files beneath a directory
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`                       // Username, or the UID if it has no account; empty if unknown
+	FileType      string                 `protobuf:"bytes,7,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"` // From the file extension
+	Shared        bool                   `protobuf:"varint,8,opt,name=shared,proto3" json:"shared,omitempty"`                    // Shares storage with other files through hard links or clones
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexEntry) Reset() {
+	*x = IndexEntry{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexEntry) ProtoMessage() {}
+
+func (x *IndexEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexEntry.ProtoReflect.Descriptor instead.
+func (*IndexEntry) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *IndexEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *IndexEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *IndexEntry) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *IndexEntry) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *IndexEntry) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *IndexEntry) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *IndexEntry) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *IndexEntry) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+// A batch of exported index entries
+type ExportIndexResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*IndexEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportIndexResponse) Reset() {
+	*x = ExportIndexResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportIndexResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportIndexResponse) ProtoMessage() {}
+
+func (x *ExportIndexResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportIndexResponse.ProtoReflect.Descriptor instead.
+func (*ExportIndexResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportIndexResponse) GetEntries() []*IndexEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Request to watch a directory
+type AddWatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Absolute path of a directory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWatchRequest) Reset() {
+	*x = AddWatchRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWatchRequest) ProtoMessage() {}
+
+func (x *AddWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWatchRequest.ProtoReflect.Descriptor instead.
+func (*AddWatchRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AddWatchRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type AddWatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Started       bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"` // Indexing started; false if it was already running
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWatchResponse) Reset() {
+	*x = AddWatchResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWatchResponse) ProtoMessage() {}
+
+func (x *AddWatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWatchResponse.ProtoReflect.Descriptor instead.
+func (*AddWatchResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AddWatchResponse) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+func (x *AddWatchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Request to stop watching a directory
+type RemoveWatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Clear         bool                   `protobuf:"varint,2,opt,name=clear,proto3" json:"clear,omitempty"` // Also drop the directory's index
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWatchRequest) Reset() {
+	*x = RemoveWatchRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWatchRequest) ProtoMessage() {}
+
+func (x *RemoveWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWatchRequest.ProtoReflect.Descriptor instead.
+func (*RemoveWatchRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RemoveWatchRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RemoveWatchRequest) GetClear() bool {
+	if x != nil {
+		return x.Clear
+	}
+	return false
+}
+
+type RemoveWatchResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EntriesCleared int64                  `protobuf:"varint,1,opt,name=entries_cleared,json=entriesCleared,proto3" json:"entries_cleared,omitempty"` // Index entries dropped with clear
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RemoveWatchResponse) Reset() {
+	*x = RemoveWatchResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWatchResponse) ProtoMessage() {}
+
+func (x *RemoveWatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWatchResponse.ProtoReflect.Descriptor instead.
+func (*RemoveWatchResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RemoveWatchResponse) GetEntriesCleared() int64 {
+	if x != nil {
+		return x.EntriesCleared
+	}
+	return 0
+}
+
+type ListWatchesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWatchesRequest) Reset() {
+	*x = ListWatchesRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWatchesRequest) ProtoMessage() {}
+
+func (x *ListWatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListWatchesRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{40}
+}
+
+// A directory the daemon indexes and watches
+type WatchedRoot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	State         IndexState             `protobuf:"varint,2,opt,name=state,proto3,enum=sweep.v1.IndexState" json:"state,omitempty"`
+	FilesIndexed  int64                  `protobuf:"varint,3,opt,name=files_indexed,json=filesIndexed,proto3" json:"files_indexed,omitempty"`
+	Saved         bool                   `protobuf:"varint,4,opt,name=saved,proto3" json:"saved,omitempty"`   // Added with AddWatch, so watched again after a restart
+	Paused        bool                   `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"` // Changes are not applied until ResumeWatch
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchedRoot) Reset() {
+	*x = WatchedRoot{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchedRoot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchedRoot) ProtoMessage() {}
+
+func (x *WatchedRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchedRoot.ProtoReflect.Descriptor instead.
+func (*WatchedRoot) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *WatchedRoot) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchedRoot) GetState() IndexState {
+	if x != nil {
+		return x.State
+	}
+	return IndexState_INDEX_STATE_UNKNOWN
+}
+
+func (x *WatchedRoot) GetFilesIndexed() int64 {
+	if x != nil {
+		return x.FilesIndexed
+	}
+	return 0
+}
+
+func (x *WatchedRoot) GetSaved() bool {
+	if x != nil {
+		return x.Saved
+	}
+	return false
+}
+
+func (x *WatchedRoot) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type ListWatchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roots         []*WatchedRoot         `protobuf:"bytes,1,rep,name=roots,proto3" json:"roots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWatchesResponse) Reset() {
+	*x = ListWatchesResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWatchesResponse) ProtoMessage() {}
+
+func (x *ListWatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListWatchesResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListWatchesResponse) GetRoots() []*WatchedRoot {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+// Request to pause watching a directory
+type PauseWatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // A watched directory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseWatchRequest) Reset() {
+	*x = PauseWatchRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseWatchRequest) ProtoMessage() {}
+
+func (x *PauseWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseWatchRequest.ProtoReflect.Descriptor instead.
+func (*PauseWatchRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PauseWatchRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type PauseWatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlreadyPaused bool                   `protobuf:"varint,1,opt,name=already_paused,json=alreadyPaused,proto3" json:"already_paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseWatchResponse) Reset() {
+	*x = PauseWatchResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseWatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseWatchResponse) ProtoMessage() {}
+
+func (x *PauseWatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseWatchResponse.ProtoReflect.Descriptor instead.
+func (*PauseWatchResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PauseWatchResponse) GetAlreadyPaused() bool {
+	if x != nil {
+		return x.AlreadyPaused
+	}
+	return false
+}
+
+// Request to resume watching a paused directory
+type ResumeWatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeWatchRequest) Reset() {
+	*x = ResumeWatchRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeWatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeWatchRequest) ProtoMessage() {}
+
+func (x *ResumeWatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeWatchRequest.ProtoReflect.Descriptor instead.
+func (*ResumeWatchRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResumeWatchRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ResumeWatchResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DirsReconciled int64                  `protobuf:"varint,1,opt,name=dirs_reconciled,json=dirsReconciled,proto3" json:"dirs_reconciled,omitempty"` // Directories that changed while paused
+	Reindexing     bool                   `protobuf:"varint,2,opt,name=reindexing,proto3" json:"reindexing,omitempty"`                               // Too much changed to catch up; indexing again
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResumeWatchResponse) Reset() {
+	*x = ResumeWatchResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeWatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeWatchResponse) ProtoMessage() {}
+
+func (x *ResumeWatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeWatchResponse.ProtoReflect.Descriptor instead.
+func (*ResumeWatchResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ResumeWatchResponse) GetDirsReconciled() int64 {
+	if x != nil {
+		return x.DirsReconciled
+	}
+	return 0
+}
+
+func (x *ResumeWatchResponse) GetReindexing() bool {
+	if x != nil {
+		return x.Reindexing
+	}
+	return false
+}
+
+type GetWatchSuggestionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWatchSuggestionsRequest) Reset() {
+	*x = GetWatchSuggestionsRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWatchSuggestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWatchSuggestionsRequest) ProtoMessage() {}
+
+func (x *GetWatchSuggestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWatchSuggestionsRequest.ProtoReflect.Descriptor instead.
+func (*GetWatchSuggestionsRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{47}
+}
+
+// An indexed directory clients use often, worth saving as a watch
+type WatchSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Uses          int32                  `protobuf:"varint,2,opt,name=uses,proto3" json:"uses,omitempty"`                         // Separate times it was queried since the daemon started
+	LastUsed      int64                  `protobuf:"varint,3,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"` // Unix seconds of the latest query
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSuggestion) Reset() {
+	*x = WatchSuggestion{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSuggestion) ProtoMessage() {}
+
+func (x *WatchSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSuggestion.ProtoReflect.Descriptor instead.
+func (*WatchSuggestion) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WatchSuggestion) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchSuggestion) GetUses() int32 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+func (x *WatchSuggestion) GetLastUsed() int64 {
+	if x != nil {
+		return x.LastUsed
+	}
+	return 0
+}
+
+type GetWatchSuggestionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*WatchSuggestion     `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"` // Most used first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWatchSuggestionsResponse) Reset() {
+	*x = GetWatchSuggestionsResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWatchSuggestionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWatchSuggestionsResponse) ProtoMessage() {}
+
+func (x *GetWatchSuggestionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWatchSuggestionsResponse.ProtoReflect.Descriptor instead.
+func (*GetWatchSuggestionsResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetWatchSuggestionsResponse) GetSuggestions() []*WatchSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// Request to stop suggesting a directory
+type DismissWatchSuggestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DismissWatchSuggestionRequest) Reset() {
+	*x = DismissWatchSuggestionRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissWatchSuggestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissWatchSuggestionRequest) ProtoMessage() {}
+
+func (x *DismissWatchSuggestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissWatchSuggestionRequest.ProtoReflect.Descriptor instead.
+func (*DismissWatchSuggestionRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DismissWatchSuggestionRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type DismissWatchSuggestionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DismissWatchSuggestionResponse) Reset() {
+	*x = DismissWatchSuggestionResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissWatchSuggestionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissWatchSuggestionResponse) ProtoMessage() {}
+
+func (x *DismissWatchSuggestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissWatchSuggestionResponse.ProtoReflect.Descriptor instead.
+func (*DismissWatchSuggestionResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{51}
+}
+
+// Request to compare disk usage with an earlier snapshot
+type GetSizeDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	SinceSeconds  int64                  `protobuf:"varint,2,opt,name=since_seconds,json=sinceSeconds,proto3" json:"since_seconds,omitempty"` // Compare with the latest snapshot at least this old, or the oldest
+	MaxDepth      int32                  `protobuf:"varint,3,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`             // Directory levels below the path (0 = unlimited)
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                   // Changes per list, largest first (0 = all)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSizeDiffRequest) Reset() {
+	*x = GetSizeDiffRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSizeDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSizeDiffRequest) ProtoMessage() {}
+
+func (x *GetSizeDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSizeDiffRequest.ProtoReflect.Descriptor instead.
+func (*GetSizeDiffRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetSizeDiffRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetSizeDiffRequest) GetSinceSeconds() int64 {
+	if x != nil {
+		return x.SinceSeconds
+	}
+	return 0
+}
+
+func (x *GetSizeDiffRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *GetSizeDiffRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// How much a directory or large file grew or shrank
+type SizeChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Before        int64                  `protobuf:"varint,2,opt,name=before,proto3" json:"before,omitempty"` // 0 if it is new
+	After         int64                  `protobuf:"varint,3,opt,name=after,proto3" json:"after,omitempty"`   // 0 if it is gone
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SizeChange) Reset() {
+	*x = SizeChange{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SizeChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeChange) ProtoMessage() {}
+
+func (x *SizeChange) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeChange.ProtoReflect.Descriptor instead.
+func (*SizeChange) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SizeChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SizeChange) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *SizeChange) GetAfter() int64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+type GetSizeDiffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SnapshotTime  int64                  `protobuf:"varint,1,opt,name=snapshot_time,json=snapshotTime,proto3" json:"snapshot_time,omitempty"` // When the snapshot compared against was taken (Unix seconds)
+	Dirs          []*SizeChange          `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	Files         []*SizeChange          `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSizeDiffResponse) Reset() {
+	*x = GetSizeDiffResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSizeDiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSizeDiffResponse) ProtoMessage() {}
+
+func (x *GetSizeDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSizeDiffResponse.ProtoReflect.Descriptor instead.
+func (*GetSizeDiffResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetSizeDiffResponse) GetSnapshotTime() int64 {
+	if x != nil {
+		return x.SnapshotTime
+	}
+	return 0
+}
+
+func (x *GetSizeDiffResponse) GetDirs() []*SizeChange {
+	if x != nil {
+		return x.Dirs
+	}
+	return nil
+}
+
+func (x *GetSizeDiffResponse) GetFiles() []*SizeChange {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type GetSizeHistogramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSizeHistogramRequest) Reset() {
+	*x = GetSizeHistogramRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSizeHistogramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSizeHistogramRequest) ProtoMessage() {}
+
+func (x *GetSizeHistogramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSizeHistogramRequest.ProtoReflect.Descriptor instead.
+func (*GetSizeHistogramRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{55}
+}
+
+// Indexed files of at least min_size, and smaller than the next bucket's
+type SizeBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinSize       int64                  `protobuf:"varint,1,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	Files         int64                  `protobuf:"varint,2,opt,name=files,proto3" json:"files,omitempty"`
+	Bytes         int64                  `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	IndexBytes    int64                  `protobuf:"varint,4,opt,name=index_bytes,json=indexBytes,proto3" json:"index_bytes,omitempty"` // Store bytes they take in the large files index
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SizeBucket) Reset() {
+	*x = SizeBucket{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SizeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeBucket) ProtoMessage() {}
+
+func (x *SizeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeBucket.ProtoReflect.Descriptor instead.
+func (*SizeBucket) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *SizeBucket) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *SizeBucket) GetFiles() int64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *SizeBucket) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SizeBucket) GetIndexBytes() int64 {
+	if x != nil {
+		return x.IndexBytes
+	}
+	return 0
+}
+
+type GetSizeHistogramResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Buckets        []*SizeBucket          `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`                                  // Smallest first, the first from 0
+	MinIndexSize   int64                  `protobuf:"varint,2,opt,name=min_index_size,json=minIndexSize,proto3" json:"min_index_size,omitempty"` // The large files index threshold
+	StoreSizeBytes int64                  `protobuf:"varint,3,opt,name=store_size_bytes,json=storeSizeBytes,proto3" json:"store_size_bytes,omitempty"`
+	// Files below min_index_size in roots indexed in aggregates mode, which
+	// are counted but not stored, so the buckets leave them out
+	UnsizedFiles  int64 `protobuf:"varint,4,opt,name=unsized_files,json=unsizedFiles,proto3" json:"unsized_files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSizeHistogramResponse) Reset() {
+	*x = GetSizeHistogramResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSizeHistogramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSizeHistogramResponse) ProtoMessage() {}
+
+func (x *GetSizeHistogramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSizeHistogramResponse.ProtoReflect.Descriptor instead.
+func (*GetSizeHistogramResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetSizeHistogramResponse) GetBuckets() []*SizeBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetSizeHistogramResponse) GetMinIndexSize() int64 {
+	if x != nil {
+		return x.MinIndexSize
+	}
+	return 0
+}
+
+func (x *GetSizeHistogramResponse) GetStoreSizeBytes() int64 {
+	if x != nil {
+		return x.StoreSizeBytes
+	}
+	return 0
+}
+
+func (x *GetSizeHistogramResponse) GetUnsizedFiles() int64 {
+	if x != nil {
+		return x.UnsizedFiles
+	}
+	return 0
+}
+
+type SetMinIndexSizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int64                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMinIndexSizeRequest) Reset() {
+	*x = SetMinIndexSizeRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMinIndexSizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMinIndexSizeRequest) ProtoMessage() {}
+
+func (x *SetMinIndexSizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMinIndexSizeRequest.ProtoReflect.Descriptor instead.
+func (*SetMinIndexSizeRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SetMinIndexSizeRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type SetMinIndexSizeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Previous      int64                  `protobuf:"varint,1,opt,name=previous,proto3" json:"previous,omitempty"`
+	Added         int64                  `protobuf:"varint,2,opt,name=added,proto3" json:"added,omitempty"`          // Files added to the large files index
+	Removed       int64                  `protobuf:"varint,3,opt,name=removed,proto3" json:"removed,omitempty"`      // Files dropped from it
+	Reindexing    []string               `protobuf:"bytes,4,rep,name=reindexing,proto3" json:"reindexing,omitempty"` // Roots being re-indexed to find smaller files
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMinIndexSizeResponse) Reset() {
+	*x = SetMinIndexSizeResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMinIndexSizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMinIndexSizeResponse) ProtoMessage() {}
+
+func (x *SetMinIndexSizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMinIndexSizeResponse.ProtoReflect.Descriptor instead.
+func (*SetMinIndexSizeResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SetMinIndexSizeResponse) GetPrevious() int64 {
+	if x != nil {
+		return x.Previous
+	}
+	return 0
+}
+
+func (x *SetMinIndexSizeResponse) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *SetMinIndexSizeResponse) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *SetMinIndexSizeResponse) GetReindexing() []string {
+	if x != nil {
+		return x.Reindexing
+	}
+	return nil
+}
+
+type CompactStoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompactStoreRequest) Reset() {
+	*x = CompactStoreRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactStoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactStoreRequest) ProtoMessage() {}
+
+func (x *CompactStoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactStoreRequest.ProtoReflect.Descriptor instead.
+func (*CompactStoreRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{60}
+}
+
+type CompactStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pruned        int64                  `protobuf:"varint,1,opt,name=pruned,proto3" json:"pruned,omitempty"`                           // Stale records dropped
+	SizeBefore    int64                  `protobuf:"varint,2,opt,name=size_before,json=sizeBefore,proto3" json:"size_before,omitempty"` // Bytes the store took on disk before
+	SizeAfter     int64                  `protobuf:"varint,3,opt,name=size_after,json=sizeAfter,proto3" json:"size_after,omitempty"`    // Bytes it takes after
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompactStoreResponse) Reset() {
+	*x = CompactStoreResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactStoreResponse) ProtoMessage() {}
+
+func (x *CompactStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactStoreResponse.ProtoReflect.Descriptor instead.
+func (*CompactStoreResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CompactStoreResponse) GetPruned() int64 {
+	if x != nil {
+		return x.Pruned
+	}
+	return 0
+}
+
+func (x *CompactStoreResponse) GetSizeBefore() int64 {
+	if x != nil {
+		return x.SizeBefore
+	}
+	return 0
+}
+
+func (x *CompactStoreResponse) GetSizeAfter() int64 {
+	if x != nil {
+		return x.SizeAfter
+	}
+	return 0
+}
+
+type GetDiagnosticsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Read the whole store to check its records, which takes a while on a
+	// large index. Refused while the store is being migrated.
+	CheckStore    bool `protobuf:"varint,1,opt,name=check_store,json=checkStore,proto3" json:"check_store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDiagnosticsRequest) Reset() {
+	*x = GetDiagnosticsRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiagnosticsRequest) ProtoMessage() {}
+
+func (x *GetDiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*GetDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetDiagnosticsRequest) GetCheckStore() bool {
+	if x != nil {
+		return x.CheckStore
+	}
+	return false
+}
+
+type Diagnostics struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Pid           int32                  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	Goroutines    int64                  `protobuf:"varint,3,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	OpenFds       int64                  `protobuf:"varint,4,opt,name=open_fds,json=openFds,proto3" json:"open_fds,omitempty"`                   // -1 if unknown
+	FdLimit       int64                  `protobuf:"varint,5,opt,name=fd_limit,json=fdLimit,proto3" json:"fd_limit,omitempty"`                   // Soft limit on open files; -1 if unknown
+	WatcherQueued int64                  `protobuf:"varint,6,opt,name=watcher_queued,json=watcherQueued,proto3" json:"watcher_queued,omitempty"` // Events received but not handled yet
+	WatcherHeld   int64                  `protobuf:"varint,7,opt,name=watcher_held,json=watcherHeld,proto3" json:"watcher_held,omitempty"`       // Changed directories held back under paused roots
+	WatcherDirs   int64                  `protobuf:"varint,8,opt,name=watcher_dirs,json=watcherDirs,proto3" json:"watcher_dirs,omitempty"`       // Directories with a filesystem watch
+	StoreCheck    *StoreCheck            `protobuf:"bytes,9,opt,name=store_check,json=storeCheck,proto3" json:"store_check,omitempty"`           // Unset unless check_store was asked for
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearCacheRequest) Reset() {
-	*x = ClearCacheRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[12]
+func (x *Diagnostics) Reset() {
+	*x = Diagnostics{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClearCacheRequest) String() string {
+func (x *Diagnostics) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearCacheRequest) ProtoMessage() {}
+func (*Diagnostics) ProtoMessage() {}
 
-func (x *ClearCacheRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[12]
+func (x *Diagnostics) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1062,41 +4298,100 @@ func (x *ClearCacheRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearCacheRequest.ProtoReflect.Descriptor instead.
-func (*ClearCacheRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use Diagnostics.ProtoReflect.Descriptor instead.
+func (*Diagnostics) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *ClearCacheRequest) GetPath() string {
+func (x *Diagnostics) GetVersion() string {
 	if x != nil {
-		return x.Path
+		return x.Version
 	}
 	return ""
 }
 
-type ClearCacheResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	EntriesCleared int64                  `protobuf:"varint,2,opt,name=entries_cleared,json=entriesCleared,proto3" json:"entries_cleared,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *Diagnostics) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
 }
 
-func (x *ClearCacheResponse) Reset() {
-	*x = ClearCacheResponse{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[13]
+func (x *Diagnostics) GetGoroutines() int64 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetOpenFds() int64 {
+	if x != nil {
+		return x.OpenFds
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetFdLimit() int64 {
+	if x != nil {
+		return x.FdLimit
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetWatcherQueued() int64 {
+	if x != nil {
+		return x.WatcherQueued
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetWatcherHeld() int64 {
+	if x != nil {
+		return x.WatcherHeld
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetWatcherDirs() int64 {
+	if x != nil {
+		return x.WatcherDirs
+	}
+	return 0
+}
+
+func (x *Diagnostics) GetStoreCheck() *StoreCheck {
+	if x != nil {
+		return x.StoreCheck
+	}
+	return nil
+}
+
+// StoreCheck is the result of reading every record in the store.
+type StoreCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       int64                  `protobuf:"varint,1,opt,name=records,proto3" json:"records,omitempty"`
+	Problems      []string               `protobuf:"bytes,2,rep,name=problems,proto3" json:"problems,omitempty"` // The first problems found; none means sound
+	Damaged       int64                  `protobuf:"varint,3,opt,name=damaged,proto3" json:"damaged,omitempty"`  // Problems found, listed or not
+	DurationMs    int64                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCheck) Reset() {
+	*x = StoreCheck{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClearCacheResponse) String() string {
+func (x *StoreCheck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearCacheResponse) ProtoMessage() {}
+func (*StoreCheck) ProtoMessage() {}
 
-func (x *ClearCacheResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[13]
+func (x *StoreCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1107,50 +4402,61 @@ func (x *ClearCacheResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearCacheResponse.ProtoReflect.Descriptor instead.
-func (*ClearCacheResponse) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use StoreCheck.ProtoReflect.Descriptor instead.
+func (*StoreCheck) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *ClearCacheResponse) GetSuccess() bool {
+func (x *StoreCheck) GetRecords() int64 {
 	if x != nil {
-		return x.Success
+		return x.Records
 	}
-	return false
+	return 0
 }
 
-func (x *ClearCacheResponse) GetEntriesCleared() int64 {
+func (x *StoreCheck) GetProblems() []string {
 	if x != nil {
-		return x.EntriesCleared
+		return x.Problems
+	}
+	return nil
+}
+
+func (x *StoreCheck) GetDamaged() int64 {
+	if x != nil {
+		return x.Damaged
 	}
 	return 0
 }
 
-// Request to watch for file system changes
-type WatchRequest struct {
+func (x *StoreCheck) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type ComputeHashesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
-	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
-	Exclude       []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	Paths         []string               `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"` // Absolute paths of regular files
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchRequest) Reset() {
-	*x = WatchRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[14]
+func (x *ComputeHashesRequest) Reset() {
+	*x = ComputeHashesRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchRequest) String() string {
+func (x *ComputeHashesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchRequest) ProtoMessage() {}
+func (*ComputeHashesRequest) ProtoMessage() {}
 
-func (x *WatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[14]
+func (x *ComputeHashesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1161,58 +4467,142 @@ func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
-func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ComputeHashesRequest.ProtoReflect.Descriptor instead.
+func (*ComputeHashesRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *WatchRequest) GetRoot() string {
+func (x *ComputeHashesRequest) GetPaths() []string {
 	if x != nil {
-		return x.Root
+		return x.Paths
+	}
+	return nil
+}
+
+// Hashes of one file, sent as each completes
+type FileHash struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        []byte                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Xxhash        uint64                 `protobuf:"varint,4,opt,name=xxhash,proto3" json:"xxhash,omitempty"`
+	Cached        bool                   `protobuf:"varint,5,opt,name=cached,proto3" json:"cached,omitempty"`   // Read from the store rather than the file
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`      // Why the file wasn't hashed
+	Current       int32                  `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"` // Files finished so far, including this one
+	Total         int32                  `protobuf:"varint,8,opt,name=total,proto3" json:"total,omitempty"`     // Files in the request
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileHash) Reset() {
+	*x = FileHash{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileHash) ProtoMessage() {}
+
+func (x *FileHash) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileHash.ProtoReflect.Descriptor instead.
+func (*FileHash) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *FileHash) GetPath() string {
+	if x != nil {
+		return x.Path
 	}
 	return ""
 }
 
-func (x *WatchRequest) GetMinSize() int64 {
+func (x *FileHash) GetSize() int64 {
 	if x != nil {
-		return x.MinSize
+		return x.Size
 	}
 	return 0
 }
 
-func (x *WatchRequest) GetExclude() []string {
+func (x *FileHash) GetSha256() []byte {
 	if x != nil {
-		return x.Exclude
+		return x.Sha256
 	}
 	return nil
 }
 
-// Event emitted when a file changes
-type FileEvent struct {
+func (x *FileHash) GetXxhash() uint64 {
+	if x != nil {
+		return x.Xxhash
+	}
+	return 0
+}
+
+func (x *FileHash) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *FileHash) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FileHash) GetCurrent() int32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *FileHash) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetIndexHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          FileEvent_EventType    `protobuf:"varint,1,opt,name=type,proto3,enum=sweep.v1.FileEvent_EventType" json:"type,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                                      // An indexed path, charted by its root; empty for every root
+	SinceSeconds  int64                  `protobuf:"varint,2,opt,name=since_seconds,json=sinceSeconds,proto3" json:"since_seconds,omitempty"` // Only totals recorded this recently (0 = all)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FileEvent) Reset() {
-	*x = FileEvent{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[15]
+func (x *GetIndexHistoryRequest) Reset() {
+	*x = GetIndexHistoryRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FileEvent) String() string {
+func (x *GetIndexHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FileEvent) ProtoMessage() {}
+func (*GetIndexHistoryRequest) ProtoMessage() {}
 
-func (x *FileEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[15]
+func (x *GetIndexHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1223,73 +4613,109 @@ func (x *FileEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FileEvent.ProtoReflect.Descriptor instead.
-func (*FileEvent) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetIndexHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetIndexHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *FileEvent) GetType() FileEvent_EventType {
+func (x *GetIndexHistoryRequest) GetPath() string {
 	if x != nil {
-		return x.Type
+		return x.Path
 	}
-	return FileEvent_CREATED
+	return ""
 }
 
-func (x *FileEvent) GetPath() string {
+func (x *GetIndexHistoryRequest) GetSinceSeconds() int64 {
 	if x != nil {
-		return x.Path
+		return x.SinceSeconds
 	}
-	return ""
+	return 0
 }
 
-func (x *FileEvent) GetSize() int64 {
+// The totals of an indexed root at one time
+type IndexHistoryPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"` // Unix seconds
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Files         int64                  `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexHistoryPoint) Reset() {
+	*x = IndexHistoryPoint{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexHistoryPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexHistoryPoint) ProtoMessage() {}
+
+func (x *IndexHistoryPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexHistoryPoint.ProtoReflect.Descriptor instead.
+func (*IndexHistoryPoint) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *IndexHistoryPoint) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *IndexHistoryPoint) GetSize() int64 {
 	if x != nil {
 		return x.Size
 	}
 	return 0
 }
 
-func (x *FileEvent) GetModTime() int64 {
+func (x *IndexHistoryPoint) GetFiles() int64 {
 	if x != nil {
-		return x.ModTime
+		return x.Files
 	}
 	return 0
 }
 
-// Tree node for hierarchical display
-type TreeNode struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	IsDir bool                   `protobuf:"varint,3,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
-	// For files
-	Size     int64  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
-	ModTime  int64  `protobuf:"varint,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
-	FileType string `protobuf:"bytes,6,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
-	// For directories
-	LargeFileSize  int64 `protobuf:"varint,7,opt,name=large_file_size,json=largeFileSize,proto3" json:"large_file_size,omitempty"`
-	LargeFileCount int32 `protobuf:"varint,8,opt,name=large_file_count,json=largeFileCount,proto3" json:"large_file_count,omitempty"`
-	// Children (only populated if expanded)
-	Children      []*TreeNode `protobuf:"bytes,9,rep,name=children,proto3" json:"children,omitempty"`
+type IndexHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	Points        []*IndexHistoryPoint   `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"` // Oldest first
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TreeNode) Reset() {
-	*x = TreeNode{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[16]
+func (x *IndexHistory) Reset() {
+	*x = IndexHistory{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TreeNode) String() string {
+func (x *IndexHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TreeNode) ProtoMessage() {}
+func (*IndexHistory) ProtoMessage() {}
 
-func (x *TreeNode) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[16]
+func (x *IndexHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1300,99 +4726,130 @@ func (x *TreeNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TreeNode.ProtoReflect.Descriptor instead.
-func (*TreeNode) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use IndexHistory.ProtoReflect.Descriptor instead.
+func (*IndexHistory) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *TreeNode) GetPath() string {
+func (x *IndexHistory) GetRoot() string {
 	if x != nil {
-		return x.Path
+		return x.Root
 	}
 	return ""
 }
 
-func (x *TreeNode) GetName() string {
+func (x *IndexHistory) GetPoints() []*IndexHistoryPoint {
 	if x != nil {
-		return x.Name
+		return x.Points
 	}
-	return ""
+	return nil
 }
 
-func (x *TreeNode) GetIsDir() bool {
-	if x != nil {
-		return x.IsDir
-	}
-	return false
+type GetIndexHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roots         []*IndexHistory        `protobuf:"bytes,1,rep,name=roots,proto3" json:"roots,omitempty"` // By root path
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TreeNode) GetSize() int64 {
-	if x != nil {
-		return x.Size
-	}
-	return 0
+func (x *GetIndexHistoryResponse) Reset() {
+	*x = GetIndexHistoryResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TreeNode) GetModTime() int64 {
-	if x != nil {
-		return x.ModTime
-	}
-	return 0
+func (x *GetIndexHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *TreeNode) GetFileType() string {
+func (*GetIndexHistoryResponse) ProtoMessage() {}
+
+func (x *GetIndexHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[70]
 	if x != nil {
-		return x.FileType
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *TreeNode) GetLargeFileSize() int64 {
-	if x != nil {
-		return x.LargeFileSize
-	}
-	return 0
+// Deprecated: Use GetIndexHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetIndexHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *TreeNode) GetLargeFileCount() int32 {
+func (x *GetIndexHistoryResponse) GetRoots() []*IndexHistory {
 	if x != nil {
-		return x.LargeFileCount
+		return x.Roots
 	}
-	return 0
+	return nil
 }
 
-func (x *TreeNode) GetChildren() []*TreeNode {
+type ReloadConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadConfigRequest) Reset() {
+	*x = ReloadConfigRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigRequest) ProtoMessage() {}
+
+func (x *ReloadConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[71]
 	if x != nil {
-		return x.Children
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type GetTreeRequest struct {
+// Deprecated: Use ReloadConfigRequest.ProtoReflect.Descriptor instead.
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{71}
+}
+
+type ReloadConfigResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
-	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
-	Exclude       []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
-	MaxDepth      int32                  `protobuf:"varint,4,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"` // 0 = unlimited
+	Changed       []string               `protobuf:"bytes,1,rep,name=changed,proto3" json:"changed,omitempty"`     // Settings that changed, e.g. "logging", "alerts"
+	Indexing      []string               `protobuf:"bytes,2,rep,name=indexing,proto3" json:"indexing,omitempty"`   // Paths added to daemon.index_paths, now indexed
+	Unwatched     []string               `protobuf:"bytes,3,rep,name=unwatched,proto3" json:"unwatched,omitempty"` // Paths removed from daemon.index_paths, no longer watched
+	Errors        []string               `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`       // Settings that couldn't be applied, and why
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTreeRequest) Reset() {
-	*x = GetTreeRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[17]
+func (x *ReloadConfigResponse) Reset() {
+	*x = ReloadConfigResponse{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTreeRequest) String() string {
+func (x *ReloadConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTreeRequest) ProtoMessage() {}
+func (*ReloadConfigResponse) ProtoMessage() {}
 
-func (x *GetTreeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[17]
+func (x *ReloadConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1403,62 +4860,65 @@ func (x *GetTreeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTreeRequest.ProtoReflect.Descriptor instead.
-func (*GetTreeRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ReloadConfigResponse.ProtoReflect.Descriptor instead.
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *GetTreeRequest) GetRoot() string {
+func (x *ReloadConfigResponse) GetChanged() []string {
 	if x != nil {
-		return x.Root
+		return x.Changed
 	}
-	return ""
+	return nil
 }
 
-func (x *GetTreeRequest) GetMinSize() int64 {
+func (x *ReloadConfigResponse) GetIndexing() []string {
 	if x != nil {
-		return x.MinSize
+		return x.Indexing
 	}
-	return 0
+	return nil
 }
 
-func (x *GetTreeRequest) GetExclude() []string {
+func (x *ReloadConfigResponse) GetUnwatched() []string {
 	if x != nil {
-		return x.Exclude
+		return x.Unwatched
 	}
 	return nil
 }
 
-func (x *GetTreeRequest) GetMaxDepth() int32 {
+func (x *ReloadConfigResponse) GetErrors() []string {
 	if x != nil {
-		return x.MaxDepth
+		return x.Errors
 	}
-	return 0
+	return nil
 }
 
-type GetTreeResponse struct {
+type GetLogsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Root          *TreeNode              `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
-	TotalIndexed  int64                  `protobuf:"varint,2,opt,name=total_indexed,json=totalIndexed,proto3" json:"total_indexed,omitempty"`
+	MinLevel      string                 `protobuf:"bytes,1,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"` // "debug", "info", "warn" or "error"; empty for debug
+	Components    []string               `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`             // Only these components; empty for all
+	Since         int64                  `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`                      // Only entries logged after this time, Unix nanoseconds (0 = all)
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                      // Send at most this many of the recent entries (0 = all kept)
+	Follow        bool                   `protobuf:"varint,5,opt,name=follow,proto3" json:"follow,omitempty"`                    // Keep streaming new entries until canceled
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTreeResponse) Reset() {
-	*x = GetTreeResponse{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[18]
+func (x *GetLogsRequest) Reset() {
+	*x = GetLogsRequest{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTreeResponse) String() string {
+func (x *GetLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTreeResponse) ProtoMessage() {}
+func (*GetLogsRequest) ProtoMessage() {}
 
-func (x *GetTreeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[18]
+func (x *GetLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1469,49 +4929,73 @@ func (x *GetTreeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTreeResponse.ProtoReflect.Descriptor instead.
-func (*GetTreeResponse) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use GetLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetLogsRequest) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *GetTreeResponse) GetRoot() *TreeNode {
+func (x *GetLogsRequest) GetMinLevel() string {
 	if x != nil {
-		return x.Root
+		return x.MinLevel
+	}
+	return ""
+}
+
+func (x *GetLogsRequest) GetComponents() []string {
+	if x != nil {
+		return x.Components
 	}
 	return nil
 }
 
-func (x *GetTreeResponse) GetTotalIndexed() int64 {
+func (x *GetLogsRequest) GetSince() int64 {
 	if x != nil {
-		return x.TotalIndexed
+		return x.Since
 	}
 	return 0
 }
 
-// Request to watch for tree changes
-type WatchTreeRequest struct {
+func (x *GetLogsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// A structured log entry
+type LogEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Root          string                 `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
-	MinSize       int64                  `protobuf:"varint,2,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"` // Unix nanoseconds
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Component     string                 `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Fields        []*LogField            `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"` // In the order they were logged
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchTreeRequest) Reset() {
-	*x = WatchTreeRequest{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[19]
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchTreeRequest) String() string {
+func (x *LogEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchTreeRequest) ProtoMessage() {}
+func (*LogEntry) ProtoMessage() {}
 
-func (x *WatchTreeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[19]
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1522,52 +5006,69 @@ func (x *WatchTreeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchTreeRequest.ProtoReflect.Descriptor instead.
-func (*WatchTreeRequest) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *WatchTreeRequest) GetRoot() string {
+func (x *LogEntry) GetTime() int64 {
 	if x != nil {
-		return x.Root
+		return x.Time
+	}
+	return 0
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
 	}
 	return ""
 }
 
-func (x *WatchTreeRequest) GetMinSize() int64 {
+func (x *LogEntry) GetComponent() string {
 	if x != nil {
-		return x.MinSize
+		return x.Component
 	}
-	return 0
+	return ""
 }
 
-// Event emitted when a tree node changes
-type TreeEvent struct {
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetFields() []*LogField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type LogField struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          TreeEvent_Type         `protobuf:"varint,1,opt,name=type,proto3,enum=sweep.v1.TreeEvent_Type" json:"type,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
-	ParentPath    string                 `protobuf:"bytes,5,opt,name=parent_path,json=parentPath,proto3" json:"parent_path,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TreeEvent) Reset() {
-	*x = TreeEvent{}
-	mi := &file_sweep_v1_sweep_proto_msgTypes[20]
+func (x *LogField) Reset() {
+	*x = LogField{}
+	mi := &file_sweep_v1_sweep_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TreeEvent) String() string {
+func (x *LogField) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TreeEvent) ProtoMessage() {}
+func (*LogField) ProtoMessage() {}
 
-func (x *TreeEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_sweep_v1_sweep_proto_msgTypes[20]
+func (x *LogField) ProtoReflect() protoreflect.Message {
+	mi := &file_sweep_v1_sweep_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1578,42 +5079,21 @@ func (x *TreeEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TreeEvent.ProtoReflect.Descriptor instead.
-func (*TreeEvent) Descriptor() ([]byte, []int) {
-	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{20}
-}
-
-func (x *TreeEvent) GetType() TreeEvent_Type {
-	if x != nil {
-		return x.Type
-	}
-	return TreeEvent_CREATED
+// Deprecated: Use LogField.ProtoReflect.Descriptor instead.
+func (*LogField) Descriptor() ([]byte, []int) {
+	return file_sweep_v1_sweep_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *TreeEvent) GetPath() string {
+func (x *LogField) GetKey() string {
 	if x != nil {
-		return x.Path
+		return x.Key
 	}
 	return ""
 }
 
-func (x *TreeEvent) GetSize() int64 {
-	if x != nil {
-		return x.Size
-	}
-	return 0
-}
-
-func (x *TreeEvent) GetModTime() int64 {
-	if x != nil {
-		return x.ModTime
-	}
-	return 0
-}
-
-func (x *TreeEvent) GetParentPath() string {
+func (x *LogField) GetValue() string {
 	if x != nil {
-		return x.ParentPath
+		return x.Value
 	}
 	return ""
 }
@@ -1622,7 +5102,7 @@ var File_sweep_v1_sweep_proto protoreflect.FileDescriptor
 
 const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\n" +
-	"\x14sweep/v1/sweep.proto\x12\bsweep.v1\"\xa0\x03\n" +
+	"\x14sweep/v1/sweep.proto\x12\bsweep.v1\"\xbf\x03\n" +
 	"\x14GetLargeFilesRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x19\n" +
 	"\bmin_size\x18\x02 \x01(\x03R\aminSize\x12\x18\n" +
@@ -1639,7 +5119,9 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\tmax_depth\x18\n" +
 	" \x01(\x05R\bmaxDepth\x12,\n" +
 	"\asort_by\x18\v \x01(\x0e2\x13.sweep.v1.SortFieldR\x06sortBy\x12'\n" +
-	"\x0fsort_descending\x18\f \x01(\bR\x0esortDescending\"\xae\x01\n" +
+	"\x0fsort_descending\x18\f \x01(\bR\x0esortDescending\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\r \x01(\tR\tpageToken\"\xc8\x02\n" +
 	"\bFileInfo\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x19\n" +
@@ -1648,9 +5130,24 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"createTime\x12\x14\n" +
 	"\x05owner\x18\x05 \x01(\tR\x05owner\x12\x14\n" +
 	"\x05group\x18\x06 \x01(\tR\x05group\x12\x12\n" +
-	"\x04mode\x18\a \x01(\rR\x04mode\"+\n" +
+	"\x04mode\x18\a \x01(\rR\x04mode\x12+\n" +
+	"\asharing\x18\b \x01(\v2\x11.sweep.v1.SharingR\asharing\x12\x1c\n" +
+	"\ttruncated\x18\t \x01(\bR\ttruncated\x12&\n" +
+	"\x0fnext_page_token\x18\n" +
+	" \x01(\tR\rnextPageToken\x12%\n" +
+	"\x05cloud\x18\v \x01(\v2\x0f.sweep.v1.CloudR\x05cloud\"\x81\x01\n" +
+	"\aSharing\x12\x10\n" +
+	"\x03dev\x18\x01 \x01(\x04R\x03dev\x12\x10\n" +
+	"\x03ino\x18\x02 \x01(\x04R\x03ino\x12\x14\n" +
+	"\x05links\x18\x03 \x01(\x04R\x05links\x12\x19\n" +
+	"\bclone_id\x18\x04 \x01(\x04R\acloneId\x12!\n" +
+	"\fprivate_size\x18\x05 \x01(\x03R\vprivateSize\"E\n" +
+	"\x05Cloud\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12 \n" +
+	"\fon_disk_size\x18\x02 \x01(\x03R\n" +
+	"onDiskSize\"+\n" +
 	"\x15GetIndexStatusRequest\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\"\xf3\x01\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x92\x02\n" +
 	"\vIndexStatus\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12*\n" +
 	"\x05state\x18\x02 \x01(\x0e2\x14.sweep.v1.IndexStateR\x05state\x12#\n" +
@@ -1659,7 +5156,9 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\n" +
 	"total_size\x18\x05 \x01(\x03R\ttotalSize\x12!\n" +
 	"\flast_updated\x18\x06 \x01(\x03R\vlastUpdated\x12\x1a\n" +
-	"\bprogress\x18\a \x01(\x02R\bprogress\"?\n" +
+	"\bprogress\x18\a \x01(\x02R\bprogress\x12\x1d\n" +
+	"\n" +
+	"index_mode\x18\b \x01(\tR\tindexMode\"?\n" +
 	"\x13TriggerIndexRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
 	"\x05force\x18\x02 \x01(\bR\x05force\"J\n" +
@@ -1675,14 +5174,68 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\rfiles_scanned\x18\x04 \x01(\x03R\ffilesScanned\x12!\n" +
 	"\fcurrent_path\x18\x05 \x01(\tR\vcurrentPath\x12\x1a\n" +
 	"\bprogress\x18\x06 \x01(\x02R\bprogress\"\x18\n" +
-	"\x16GetDaemonStatusRequest\"\xf1\x01\n" +
+	"\x16GetDaemonStatusRequest\"\xaf\x04\n" +
 	"\fDaemonStatus\x12\x18\n" +
 	"\arunning\x18\x01 \x01(\bR\arunning\x12%\n" +
 	"\x0euptime_seconds\x18\x02 \x01(\x03R\ruptimeSeconds\x12!\n" +
 	"\fmemory_bytes\x18\x03 \x01(\x03R\vmemoryBytes\x12#\n" +
 	"\rwatched_paths\x18\x04 \x03(\tR\fwatchedPaths\x12(\n" +
 	"\x10cache_size_bytes\x18\x05 \x01(\x03R\x0ecacheSizeBytes\x12.\n" +
-	"\x13total_files_indexed\x18\x06 \x01(\x03R\x11totalFilesIndexed\"\x11\n" +
+	"\x13total_files_indexed\x18\x06 \x01(\x03R\x11totalFilesIndexed\x12;\n" +
+	"\vhash_warmer\x18\a \x01(\v2\x1a.sweep.v1.HashWarmerStatusR\n" +
+	"hashWarmer\x12\x1d\n" +
+	"\n" +
+	"index_mode\x18\b \x01(\tR\tindexMode\x12\x18\n" +
+	"\aversion\x18\t \x01(\tR\aversion\x12*\n" +
+	"\x05roots\x18\n" +
+	" \x03(\v2\x14.sweep.v1.RootStatusR\x05roots\x121\n" +
+	"\awatcher\x18\v \x01(\v2\x17.sweep.v1.WatcherStatusR\awatcher\x12+\n" +
+	"\x05store\x18\f \x01(\v2\x15.sweep.v1.StoreStatusR\x05store\x12:\n" +
+	"\rrecent_errors\x18\r \x03(\v2\x15.sweep.v1.DaemonErrorR\frecentErrors\"\xb3\x03\n" +
+	"\n" +
+	"RootStatus\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.sweep.v1.IndexStateR\x05state\x12#\n" +
+	"\rfiles_indexed\x18\x03 \x01(\x03R\ffilesIndexed\x12!\n" +
+	"\fdirs_indexed\x18\x04 \x01(\x03R\vdirsIndexed\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x03R\ttotalSize\x12!\n" +
+	"\flast_indexed\x18\x06 \x01(\x03R\vlastIndexed\x12!\n" +
+	"\flast_queried\x18\a \x01(\x03R\vlastQueried\x12\x1d\n" +
+	"\n" +
+	"index_mode\x18\b \x01(\tR\tindexMode\x12\x14\n" +
+	"\x05saved\x18\t \x01(\bR\x05saved\x12\x16\n" +
+	"\x06paused\x18\n" +
+	" \x01(\bR\x06paused\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1a\n" +
+	"\bprogress\x18\f \x01(\x02R\bprogress\x12!\n" +
+	"\fcurrent_path\x18\r \x01(\tR\vcurrentPath\x12\x16\n" +
+	"\x06queued\x18\x0e \x01(\bR\x06queued\"\x80\x01\n" +
+	"\rWatcherStatus\x12 \n" +
+	"\vdirectories\x18\x01 \x01(\x03R\vdirectories\x12\x16\n" +
+	"\x06events\x18\x02 \x01(\x03R\x06events\x12\x16\n" +
+	"\x06errors\x18\x03 \x01(\x03R\x06errors\x12\x1d\n" +
+	"\n" +
+	"last_event\x18\x04 \x01(\x03R\tlastEvent\"\xb2\x01\n" +
+	"\vStoreStatus\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x02 \x01(\x03R\tsizeBytes\x12%\n" +
+	"\x0eschema_version\x18\x03 \x01(\x05R\rschemaVersion\x12\x1c\n" +
+	"\tmigrating\x18\x04 \x01(\bR\tmigrating\x12%\n" +
+	"\x0elast_compacted\x18\x05 \x01(\x03R\rlastCompacted\"m\n" +
+	"\vDaemonError\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\tR\tcomponent\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xc5\x01\n" +
+	"\x10HashWarmerStatus\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\x03R\x06queued\x12!\n" +
+	"\ffiles_hashed\x18\x04 \x01(\x03R\vfilesHashed\x12!\n" +
+	"\fbytes_hashed\x18\x05 \x01(\x03R\vbytesHashed\x12!\n" +
+	"\fcurrent_path\x18\x06 \x01(\tR\vcurrentPath\"\x11\n" +
 	"\x0fShutdownRequest\",\n" +
 	"\x10ShutdownResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\"'\n" +
@@ -1694,12 +5247,13 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\fWatchRequest\x12\x12\n" +
 	"\x04root\x18\x01 \x01(\tR\x04root\x12\x19\n" +
 	"\bmin_size\x18\x02 \x01(\x03R\aminSize\x12\x18\n" +
-	"\aexclude\x18\x03 \x03(\tR\aexclude\"\xc3\x01\n" +
+	"\aexclude\x18\x03 \x03(\tR\aexclude\"\xde\x01\n" +
 	"\tFileEvent\x121\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x1d.sweep.v1.FileEvent.EventTypeR\x04type\x12\x12\n" +
 	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
 	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x19\n" +
-	"\bmod_time\x18\x04 \x01(\x03R\amodTime\"@\n" +
+	"\bmod_time\x18\x04 \x01(\x03R\amodTime\x12\x19\n" +
+	"\bnew_path\x18\x05 \x01(\tR\anewPath\"@\n" +
 	"\tEventType\x12\v\n" +
 	"\aCREATED\x10\x00\x12\f\n" +
 	"\bMODIFIED\x10\x01\x12\v\n" +
@@ -1722,21 +5276,216 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\tmax_depth\x18\x04 \x01(\x05R\bmaxDepth\"^\n" +
 	"\x0fGetTreeResponse\x12&\n" +
 	"\x04root\x18\x01 \x01(\v2\x12.sweep.v1.TreeNodeR\x04root\x12#\n" +
-	"\rtotal_indexed\x18\x02 \x01(\x03R\ftotalIndexed\"A\n" +
+	"\rtotal_indexed\x18\x02 \x01(\x03R\ftotalIndexed\"E\n" +
+	"\x12GetDirSizesRequest\x12\x12\n" +
+	"\x04root\x18\x01 \x01(\tR\x04root\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\"\x8b\x01\n" +
+	"\aDirSize\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x14\n" +
+	"\x05files\x18\x03 \x01(\x03R\x05files\x12\x14\n" +
+	"\x05depth\x18\x04 \x01(\x05R\x05depth\x12\x12\n" +
+	"\x04dirs\x18\x05 \x01(\x03R\x04dirs\x12\x18\n" +
+	"\aentries\x18\x06 \x01(\x03R\aentries\"[\n" +
+	"\x13GetDirSizesResponse\x12%\n" +
+	"\x04dirs\x18\x01 \x03(\v2\x11.sweep.v1.DirSizeR\x04dirs\x12\x1d\n" +
+	"\n" +
+	"index_mode\x18\x02 \x01(\tR\tindexMode\"A\n" +
 	"\x10WatchTreeRequest\x12\x12\n" +
 	"\x04root\x18\x01 \x01(\tR\x04root\x12\x19\n" +
-	"\bmin_size\x18\x02 \x01(\x03R\aminSize\"\xcd\x01\n" +
+	"\bmin_size\x18\x02 \x01(\x03R\aminSize\"\xf5\x01\n" +
 	"\tTreeEvent\x12,\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x18.sweep.v1.TreeEvent.TypeR\x04type\x12\x12\n" +
 	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
 	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x19\n" +
 	"\bmod_time\x18\x04 \x01(\x03R\amodTime\x12\x1f\n" +
 	"\vparent_path\x18\x05 \x01(\tR\n" +
-	"parentPath\".\n" +
+	"parentPath\x12\x19\n" +
+	"\bnew_path\x18\x06 \x01(\tR\anewPath\";\n" +
 	"\x04Type\x12\v\n" +
 	"\aCREATED\x10\x00\x12\f\n" +
 	"\bMODIFIED\x10\x01\x12\v\n" +
-	"\aDELETED\x10\x02*\x8a\x01\n" +
+	"\aDELETED\x10\x02\x12\v\n" +
+	"\aRENAMED\x10\x03\"{\n" +
+	"\x12DeleteFilesRequest\x12\x14\n" +
+	"\x05paths\x18\x01 \x03(\tR\x05paths\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x1c\n" +
+	"\tpermanent\x18\x03 \x01(\bR\tpermanent\x12\x18\n" +
+	"\abackend\x18\x04 \x01(\tR\abackend\"\xb4\x01\n" +
+	"\x0eDeleteProgress\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\bR\adeleted\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x18\n" +
+	"\acurrent\x18\x05 \x01(\x05R\acurrent\x12\x14\n" +
+	"\x05total\x18\x06 \x01(\x05R\x05total\x12\x1a\n" +
+	"\blocation\x18\a \x01(\tR\blocation\"(\n" +
+	"\x12ExportIndexRequest\x12\x12\n" +
+	"\x04root\x18\x01 \x01(\tR\x04root\"\xc7\x01\n" +
+	"\n" +
+	"IndexEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x19\n" +
+	"\bmod_time\x18\x03 \x01(\x03R\amodTime\x12\x15\n" +
+	"\x06is_dir\x18\x04 \x01(\bR\x05isDir\x12\x14\n" +
+	"\x05files\x18\x05 \x01(\x03R\x05files\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x1b\n" +
+	"\tfile_type\x18\a \x01(\tR\bfileType\x12\x16\n" +
+	"\x06shared\x18\b \x01(\bR\x06shared\"E\n" +
+	"\x13ExportIndexResponse\x12.\n" +
+	"\aentries\x18\x01 \x03(\v2\x14.sweep.v1.IndexEntryR\aentries\"%\n" +
+	"\x0fAddWatchRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"F\n" +
+	"\x10AddWatchResponse\x12\x18\n" +
+	"\astarted\x18\x01 \x01(\bR\astarted\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\">\n" +
+	"\x12RemoveWatchRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05clear\x18\x02 \x01(\bR\x05clear\">\n" +
+	"\x13RemoveWatchResponse\x12'\n" +
+	"\x0fentries_cleared\x18\x01 \x01(\x03R\x0eentriesCleared\"\x14\n" +
+	"\x12ListWatchesRequest\"\xa0\x01\n" +
+	"\vWatchedRoot\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.sweep.v1.IndexStateR\x05state\x12#\n" +
+	"\rfiles_indexed\x18\x03 \x01(\x03R\ffilesIndexed\x12\x14\n" +
+	"\x05saved\x18\x04 \x01(\bR\x05saved\x12\x16\n" +
+	"\x06paused\x18\x05 \x01(\bR\x06paused\"B\n" +
+	"\x13ListWatchesResponse\x12+\n" +
+	"\x05roots\x18\x01 \x03(\v2\x15.sweep.v1.WatchedRootR\x05roots\"'\n" +
+	"\x11PauseWatchRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\";\n" +
+	"\x12PauseWatchResponse\x12%\n" +
+	"\x0ealready_paused\x18\x01 \x01(\bR\ralreadyPaused\"(\n" +
+	"\x12ResumeWatchRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"^\n" +
+	"\x13ResumeWatchResponse\x12'\n" +
+	"\x0fdirs_reconciled\x18\x01 \x01(\x03R\x0edirsReconciled\x12\x1e\n" +
+	"\n" +
+	"reindexing\x18\x02 \x01(\bR\n" +
+	"reindexing\"\x1c\n" +
+	"\x1aGetWatchSuggestionsRequest\"V\n" +
+	"\x0fWatchSuggestion\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04uses\x18\x02 \x01(\x05R\x04uses\x12\x1b\n" +
+	"\tlast_used\x18\x03 \x01(\x03R\blastUsed\"Z\n" +
+	"\x1bGetWatchSuggestionsResponse\x12;\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x19.sweep.v1.WatchSuggestionR\vsuggestions\"3\n" +
+	"\x1dDismissWatchSuggestionRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\" \n" +
+	"\x1eDismissWatchSuggestionResponse\"\x80\x01\n" +
+	"\x12GetSizeDiffRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rsince_seconds\x18\x02 \x01(\x03R\fsinceSeconds\x12\x1b\n" +
+	"\tmax_depth\x18\x03 \x01(\x05R\bmaxDepth\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"N\n" +
+	"\n" +
+	"SizeChange\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\x03R\x06before\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\x03R\x05after\"\x90\x01\n" +
+	"\x13GetSizeDiffResponse\x12#\n" +
+	"\rsnapshot_time\x18\x01 \x01(\x03R\fsnapshotTime\x12(\n" +
+	"\x04dirs\x18\x02 \x03(\v2\x14.sweep.v1.SizeChangeR\x04dirs\x12*\n" +
+	"\x05files\x18\x03 \x03(\v2\x14.sweep.v1.SizeChangeR\x05files\"\x19\n" +
+	"\x17GetSizeHistogramRequest\"t\n" +
+	"\n" +
+	"SizeBucket\x12\x19\n" +
+	"\bmin_size\x18\x01 \x01(\x03R\aminSize\x12\x14\n" +
+	"\x05files\x18\x02 \x01(\x03R\x05files\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x12\x1f\n" +
+	"\vindex_bytes\x18\x04 \x01(\x03R\n" +
+	"indexBytes\"\xbf\x01\n" +
+	"\x18GetSizeHistogramResponse\x12.\n" +
+	"\abuckets\x18\x01 \x03(\v2\x14.sweep.v1.SizeBucketR\abuckets\x12$\n" +
+	"\x0emin_index_size\x18\x02 \x01(\x03R\fminIndexSize\x12(\n" +
+	"\x10store_size_bytes\x18\x03 \x01(\x03R\x0estoreSizeBytes\x12#\n" +
+	"\runsized_files\x18\x04 \x01(\x03R\funsizedFiles\",\n" +
+	"\x16SetMinIndexSizeRequest\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\"\x85\x01\n" +
+	"\x17SetMinIndexSizeResponse\x12\x1a\n" +
+	"\bprevious\x18\x01 \x01(\x03R\bprevious\x12\x14\n" +
+	"\x05added\x18\x02 \x01(\x03R\x05added\x12\x18\n" +
+	"\aremoved\x18\x03 \x01(\x03R\aremoved\x12\x1e\n" +
+	"\n" +
+	"reindexing\x18\x04 \x03(\tR\n" +
+	"reindexing\"\x15\n" +
+	"\x13CompactStoreRequest\"n\n" +
+	"\x14CompactStoreResponse\x12\x16\n" +
+	"\x06pruned\x18\x01 \x01(\x03R\x06pruned\x12\x1f\n" +
+	"\vsize_before\x18\x02 \x01(\x03R\n" +
+	"sizeBefore\x12\x1d\n" +
+	"\n" +
+	"size_after\x18\x03 \x01(\x03R\tsizeAfter\"8\n" +
+	"\x15GetDiagnosticsRequest\x12\x1f\n" +
+	"\vcheck_store\x18\x01 \x01(\bR\n" +
+	"checkStore\"\xb3\x02\n" +
+	"\vDiagnostics\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x05R\x03pid\x12\x1e\n" +
+	"\n" +
+	"goroutines\x18\x03 \x01(\x03R\n" +
+	"goroutines\x12\x19\n" +
+	"\bopen_fds\x18\x04 \x01(\x03R\aopenFds\x12\x19\n" +
+	"\bfd_limit\x18\x05 \x01(\x03R\afdLimit\x12%\n" +
+	"\x0ewatcher_queued\x18\x06 \x01(\x03R\rwatcherQueued\x12!\n" +
+	"\fwatcher_held\x18\a \x01(\x03R\vwatcherHeld\x12!\n" +
+	"\fwatcher_dirs\x18\b \x01(\x03R\vwatcherDirs\x125\n" +
+	"\vstore_check\x18\t \x01(\v2\x14.sweep.v1.StoreCheckR\n" +
+	"storeCheck\"}\n" +
+	"\n" +
+	"StoreCheck\x12\x18\n" +
+	"\arecords\x18\x01 \x01(\x03R\arecords\x12\x1a\n" +
+	"\bproblems\x18\x02 \x03(\tR\bproblems\x12\x18\n" +
+	"\adamaged\x18\x03 \x01(\x03R\adamaged\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x03R\n" +
+	"durationMs\",\n" +
+	"\x14ComputeHashesRequest\x12\x14\n" +
+	"\x05paths\x18\x01 \x03(\tR\x05paths\"\xc0\x01\n" +
+	"\bFileHash\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\fR\x06sha256\x12\x16\n" +
+	"\x06xxhash\x18\x04 \x01(\x04R\x06xxhash\x12\x16\n" +
+	"\x06cached\x18\x05 \x01(\bR\x06cached\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x18\n" +
+	"\acurrent\x18\a \x01(\x05R\acurrent\x12\x14\n" +
+	"\x05total\x18\b \x01(\x05R\x05total\"Q\n" +
+	"\x16GetIndexHistoryRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12#\n" +
+	"\rsince_seconds\x18\x02 \x01(\x03R\fsinceSeconds\"Q\n" +
+	"\x11IndexHistoryPoint\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x14\n" +
+	"\x05files\x18\x03 \x01(\x03R\x05files\"W\n" +
+	"\fIndexHistory\x12\x12\n" +
+	"\x04root\x18\x01 \x01(\tR\x04root\x123\n" +
+	"\x06points\x18\x02 \x03(\v2\x1b.sweep.v1.IndexHistoryPointR\x06points\"G\n" +
+	"\x17GetIndexHistoryResponse\x12,\n" +
+	"\x05roots\x18\x01 \x03(\v2\x16.sweep.v1.IndexHistoryR\x05roots\"\x15\n" +
+	"\x13ReloadConfigRequest\"\x82\x01\n" +
+	"\x14ReloadConfigResponse\x12\x18\n" +
+	"\achanged\x18\x01 \x03(\tR\achanged\x12\x1a\n" +
+	"\bindexing\x18\x02 \x03(\tR\bindexing\x12\x1c\n" +
+	"\tunwatched\x18\x03 \x03(\tR\tunwatched\x12\x16\n" +
+	"\x06errors\x18\x04 \x03(\tR\x06errors\"\x91\x01\n" +
+	"\x0eGetLogsRequest\x12\x1b\n" +
+	"\tmin_level\x18\x01 \x01(\tR\bminLevel\x12\x1e\n" +
+	"\n" +
+	"components\x18\x02 \x03(\tR\n" +
+	"components\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06follow\x18\x05 \x01(\bR\x06follow\"\x98\x01\n" +
+	"\bLogEntry\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x1c\n" +
+	"\tcomponent\x18\x03 \x01(\tR\tcomponent\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12*\n" +
+	"\x06fields\x18\x05 \x03(\v2\x12.sweep.v1.LogFieldR\x06fields\"2\n" +
+	"\bLogField\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value*\x8a\x01\n" +
 	"\n" +
 	"IndexState\x12\x17\n" +
 	"\x13INDEX_STATE_UNKNOWN\x10\x00\x12\x1b\n" +
@@ -1747,7 +5496,7 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"\tSortField\x12\r\n" +
 	"\tSORT_SIZE\x10\x00\x12\x11\n" +
 	"\rSORT_MOD_TIME\x10\x01\x12\r\n" +
-	"\tSORT_PATH\x10\x022\xde\x05\n" +
+	"\tSORT_PATH\x10\x022\xc3\x11\n" +
 	"\vSweepDaemon\x12E\n" +
 	"\rGetLargeFiles\x12\x1e.sweep.v1.GetLargeFilesRequest\x1a\x12.sweep.v1.FileInfo0\x01\x12H\n" +
 	"\x0eGetIndexStatus\x12\x1f.sweep.v1.GetIndexStatusRequest\x1a\x15.sweep.v1.IndexStatus\x12M\n" +
@@ -1759,7 +5508,27 @@ const file_sweep_v1_sweep_proto_rawDesc = "" +
 	"ClearCache\x12\x1b.sweep.v1.ClearCacheRequest\x1a\x1c.sweep.v1.ClearCacheResponse\x12@\n" +
 	"\x0fWatchLargeFiles\x12\x16.sweep.v1.WatchRequest\x1a\x13.sweep.v1.FileEvent0\x01\x12>\n" +
 	"\aGetTree\x12\x18.sweep.v1.GetTreeRequest\x1a\x19.sweep.v1.GetTreeResponse\x12>\n" +
-	"\tWatchTree\x12\x1a.sweep.v1.WatchTreeRequest\x1a\x13.sweep.v1.TreeEvent0\x01B8Z6github.com/jamesainslie/sweep/pkg/api/sweep/v1;sweepv1b\x06proto3"
+	"\tWatchTree\x12\x1a.sweep.v1.WatchTreeRequest\x1a\x13.sweep.v1.TreeEvent0\x01\x12J\n" +
+	"\vGetDirSizes\x12\x1c.sweep.v1.GetDirSizesRequest\x1a\x1d.sweep.v1.GetDirSizesResponse\x12G\n" +
+	"\vDeleteFiles\x12\x1c.sweep.v1.DeleteFilesRequest\x1a\x18.sweep.v1.DeleteProgress0\x01\x12L\n" +
+	"\vExportIndex\x12\x1c.sweep.v1.ExportIndexRequest\x1a\x1d.sweep.v1.ExportIndexResponse0\x01\x12A\n" +
+	"\bAddWatch\x12\x19.sweep.v1.AddWatchRequest\x1a\x1a.sweep.v1.AddWatchResponse\x12J\n" +
+	"\vRemoveWatch\x12\x1c.sweep.v1.RemoveWatchRequest\x1a\x1d.sweep.v1.RemoveWatchResponse\x12J\n" +
+	"\vListWatches\x12\x1c.sweep.v1.ListWatchesRequest\x1a\x1d.sweep.v1.ListWatchesResponse\x12G\n" +
+	"\n" +
+	"PauseWatch\x12\x1b.sweep.v1.PauseWatchRequest\x1a\x1c.sweep.v1.PauseWatchResponse\x12J\n" +
+	"\vResumeWatch\x12\x1c.sweep.v1.ResumeWatchRequest\x1a\x1d.sweep.v1.ResumeWatchResponse\x12b\n" +
+	"\x13GetWatchSuggestions\x12$.sweep.v1.GetWatchSuggestionsRequest\x1a%.sweep.v1.GetWatchSuggestionsResponse\x12k\n" +
+	"\x16DismissWatchSuggestion\x12'.sweep.v1.DismissWatchSuggestionRequest\x1a(.sweep.v1.DismissWatchSuggestionResponse\x12J\n" +
+	"\vGetSizeDiff\x12\x1c.sweep.v1.GetSizeDiffRequest\x1a\x1d.sweep.v1.GetSizeDiffResponse\x12Y\n" +
+	"\x10GetSizeHistogram\x12!.sweep.v1.GetSizeHistogramRequest\x1a\".sweep.v1.GetSizeHistogramResponse\x12V\n" +
+	"\x0fSetMinIndexSize\x12 .sweep.v1.SetMinIndexSizeRequest\x1a!.sweep.v1.SetMinIndexSizeResponse\x12M\n" +
+	"\fCompactStore\x12\x1d.sweep.v1.CompactStoreRequest\x1a\x1e.sweep.v1.CompactStoreResponse\x12H\n" +
+	"\x0eGetDiagnostics\x12\x1f.sweep.v1.GetDiagnosticsRequest\x1a\x15.sweep.v1.Diagnostics\x12E\n" +
+	"\rComputeHashes\x12\x1e.sweep.v1.ComputeHashesRequest\x1a\x12.sweep.v1.FileHash0\x01\x12V\n" +
+	"\x0fGetIndexHistory\x12 .sweep.v1.GetIndexHistoryRequest\x1a!.sweep.v1.GetIndexHistoryResponse\x12M\n" +
+	"\fReloadConfig\x12\x1d.sweep.v1.ReloadConfigRequest\x1a\x1e.sweep.v1.ReloadConfigResponse\x129\n" +
+	"\aGetLogs\x12\x18.sweep.v1.GetLogsRequest\x1a\x12.sweep.v1.LogEntry0\x01B8Z6github.com/jamesainslie/sweep/pkg/api/sweep/v1;sweepv1b\x06proto3"
 
 var (
 	file_sweep_v1_sweep_proto_rawDescOnce sync.Once
@@ -1774,67 +5543,180 @@ func file_sweep_v1_sweep_proto_rawDescGZIP() []byte {
 }
 
 var file_sweep_v1_sweep_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_sweep_v1_sweep_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_sweep_v1_sweep_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
 var file_sweep_v1_sweep_proto_goTypes = []any{
-	(IndexState)(0),                   // 0: sweep.v1.IndexState
-	(SortField)(0),                    // 1: sweep.v1.SortField
-	(FileEvent_EventType)(0),          // 2: sweep.v1.FileEvent.EventType
-	(TreeEvent_Type)(0),               // 3: sweep.v1.TreeEvent.Type
-	(*GetLargeFilesRequest)(nil),      // 4: sweep.v1.GetLargeFilesRequest
-	(*FileInfo)(nil),                  // 5: sweep.v1.FileInfo
-	(*GetIndexStatusRequest)(nil),     // 6: sweep.v1.GetIndexStatusRequest
-	(*IndexStatus)(nil),               // 7: sweep.v1.IndexStatus
-	(*TriggerIndexRequest)(nil),       // 8: sweep.v1.TriggerIndexRequest
-	(*TriggerIndexResponse)(nil),      // 9: sweep.v1.TriggerIndexResponse
-	(*WatchIndexProgressRequest)(nil), // 10: sweep.v1.WatchIndexProgressRequest
-	(*IndexProgress)(nil),             // 11: sweep.v1.IndexProgress
-	(*GetDaemonStatusRequest)(nil),    // 12: sweep.v1.GetDaemonStatusRequest
-	(*DaemonStatus)(nil),              // 13: sweep.v1.DaemonStatus
-	(*ShutdownRequest)(nil),           // 14: sweep.v1.ShutdownRequest
-	(*ShutdownResponse)(nil),          // 15: sweep.v1.ShutdownResponse
-	(*ClearCacheRequest)(nil),         // 16: sweep.v1.ClearCacheRequest
-	(*ClearCacheResponse)(nil),        // 17: sweep.v1.ClearCacheResponse
-	(*WatchRequest)(nil),              // 18: sweep.v1.WatchRequest
-	(*FileEvent)(nil),                 // 19: sweep.v1.FileEvent
-	(*TreeNode)(nil),                  // 20: sweep.v1.TreeNode
-	(*GetTreeRequest)(nil),            // 21: sweep.v1.GetTreeRequest
-	(*GetTreeResponse)(nil),           // 22: sweep.v1.GetTreeResponse
-	(*WatchTreeRequest)(nil),          // 23: sweep.v1.WatchTreeRequest
-	(*TreeEvent)(nil),                 // 24: sweep.v1.TreeEvent
+	(IndexState)(0),                        // 0: sweep.v1.IndexState
+	(SortField)(0),                         // 1: sweep.v1.SortField
+	(FileEvent_EventType)(0),               // 2: sweep.v1.FileEvent.EventType
+	(TreeEvent_Type)(0),                    // 3: sweep.v1.TreeEvent.Type
+	(*GetLargeFilesRequest)(nil),           // 4: sweep.v1.GetLargeFilesRequest
+	(*FileInfo)(nil),                       // 5: sweep.v1.FileInfo
+	(*Sharing)(nil),                        // 6: sweep.v1.Sharing
+	(*Cloud)(nil),                          // 7: sweep.v1.Cloud
+	(*GetIndexStatusRequest)(nil),          // 8: sweep.v1.GetIndexStatusRequest
+	(*IndexStatus)(nil),                    // 9: sweep.v1.IndexStatus
+	(*TriggerIndexRequest)(nil),            // 10: sweep.v1.TriggerIndexRequest
+	(*TriggerIndexResponse)(nil),           // 11: sweep.v1.TriggerIndexResponse
+	(*WatchIndexProgressRequest)(nil),      // 12: sweep.v1.WatchIndexProgressRequest
+	(*IndexProgress)(nil),                  // 13: sweep.v1.IndexProgress
+	(*GetDaemonStatusRequest)(nil),         // 14: sweep.v1.GetDaemonStatusRequest
+	(*DaemonStatus)(nil),                   // 15: sweep.v1.DaemonStatus
+	(*RootStatus)(nil),                     // 16: sweep.v1.RootStatus
+	(*WatcherStatus)(nil),                  // 17: sweep.v1.WatcherStatus
+	(*StoreStatus)(nil),                    // 18: sweep.v1.StoreStatus
+	(*DaemonError)(nil),                    // 19: sweep.v1.DaemonError
+	(*HashWarmerStatus)(nil),               // 20: sweep.v1.HashWarmerStatus
+	(*ShutdownRequest)(nil),                // 21: sweep.v1.ShutdownRequest
+	(*ShutdownResponse)(nil),               // 22: sweep.v1.ShutdownResponse
+	(*ClearCacheRequest)(nil),              // 23: sweep.v1.ClearCacheRequest
+	(*ClearCacheResponse)(nil),             // 24: sweep.v1.ClearCacheResponse
+	(*WatchRequest)(nil),                   // 25: sweep.v1.WatchRequest
+	(*FileEvent)(nil),                      // 26: sweep.v1.FileEvent
+	(*TreeNode)(nil),                       // 27: sweep.v1.TreeNode
+	(*GetTreeRequest)(nil),                 // 28: sweep.v1.GetTreeRequest
+	(*GetTreeResponse)(nil),                // 29: sweep.v1.GetTreeResponse
+	(*GetDirSizesRequest)(nil),             // 30: sweep.v1.GetDirSizesRequest
+	(*DirSize)(nil),                        // 31: sweep.v1.DirSize
+	(*GetDirSizesResponse)(nil),            // 32: sweep.v1.GetDirSizesResponse
+	(*WatchTreeRequest)(nil),               // 33: sweep.v1.WatchTreeRequest
+	(*TreeEvent)(nil),                      // 34: sweep.v1.TreeEvent
+	(*DeleteFilesRequest)(nil),             // 35: sweep.v1.DeleteFilesRequest
+	(*DeleteProgress)(nil),                 // 36: sweep.v1.DeleteProgress
+	(*ExportIndexRequest)(nil),             // 37: sweep.v1.ExportIndexRequest
+	(*IndexEntry)(nil),                     // 38: sweep.v1.IndexEntry
+	(*ExportIndexResponse)(nil),            // 39: sweep.v1.ExportIndexResponse
+	(*AddWatchRequest)(nil),                // 40: sweep.v1.AddWatchRequest
+	(*AddWatchResponse)(nil),               // 41: sweep.v1.AddWatchResponse
+	(*RemoveWatchRequest)(nil),             // 42: sweep.v1.RemoveWatchRequest
+	(*RemoveWatchResponse)(nil),            // 43: sweep.v1.RemoveWatchResponse
+	(*ListWatchesRequest)(nil),             // 44: sweep.v1.ListWatchesRequest
+	(*WatchedRoot)(nil),                    // 45: sweep.v1.WatchedRoot
+	(*ListWatchesResponse)(nil),            // 46: sweep.v1.ListWatchesResponse
+	(*PauseWatchRequest)(nil),              // 47: sweep.v1.PauseWatchRequest
+	(*PauseWatchResponse)(nil),             // 48: sweep.v1.PauseWatchResponse
+	(*ResumeWatchRequest)(nil),             // 49: sweep.v1.ResumeWatchRequest
+	(*ResumeWatchResponse)(nil),            // 50: sweep.v1.ResumeWatchResponse
+	(*GetWatchSuggestionsRequest)(nil),     // 51: sweep.v1.GetWatchSuggestionsRequest
+	(*WatchSuggestion)(nil),                // 52: sweep.v1.WatchSuggestion
+	(*GetWatchSuggestionsResponse)(nil),    // 53: sweep.v1.GetWatchSuggestionsResponse
+	(*DismissWatchSuggestionRequest)(nil),  // 54: sweep.v1.DismissWatchSuggestionRequest
+	(*DismissWatchSuggestionResponse)(nil), // 55: sweep.v1.DismissWatchSuggestionResponse
+	(*GetSizeDiffRequest)(nil),             // 56: sweep.v1.GetSizeDiffRequest
+	(*SizeChange)(nil),                     // 57: sweep.v1.SizeChange
+	(*GetSizeDiffResponse)(nil),            // 58: sweep.v1.GetSizeDiffResponse
+	(*GetSizeHistogramRequest)(nil),        // 59: sweep.v1.GetSizeHistogramRequest
+	(*SizeBucket)(nil),                     // 60: sweep.v1.SizeBucket
+	(*GetSizeHistogramResponse)(nil),       // 61: sweep.v1.GetSizeHistogramResponse
+	(*SetMinIndexSizeRequest)(nil),         // 62: sweep.v1.SetMinIndexSizeRequest
+	(*SetMinIndexSizeResponse)(nil),        // 63: sweep.v1.SetMinIndexSizeResponse
+	(*CompactStoreRequest)(nil),            // 64: sweep.v1.CompactStoreRequest
+	(*CompactStoreResponse)(nil),           // 65: sweep.v1.CompactStoreResponse
+	(*GetDiagnosticsRequest)(nil),          // 66: sweep.v1.GetDiagnosticsRequest
+	(*Diagnostics)(nil),                    // 67: sweep.v1.Diagnostics
+	(*StoreCheck)(nil),                     // 68: sweep.v1.StoreCheck
+	(*ComputeHashesRequest)(nil),           // 69: sweep.v1.ComputeHashesRequest
+	(*FileHash)(nil),                       // 70: sweep.v1.FileHash
+	(*GetIndexHistoryRequest)(nil),         // 71: sweep.v1.GetIndexHistoryRequest
+	(*IndexHistoryPoint)(nil),              // 72: sweep.v1.IndexHistoryPoint
+	(*IndexHistory)(nil),                   // 73: sweep.v1.IndexHistory
+	(*GetIndexHistoryResponse)(nil),        // 74: sweep.v1.GetIndexHistoryResponse
+	(*ReloadConfigRequest)(nil),            // 75: sweep.v1.ReloadConfigRequest
+	(*ReloadConfigResponse)(nil),           // 76: sweep.v1.ReloadConfigResponse
+	(*GetLogsRequest)(nil),                 // 77: sweep.v1.GetLogsRequest
+	(*LogEntry)(nil),                       // 78: sweep.v1.LogEntry
+	(*LogField)(nil),                       // 79: sweep.v1.LogField
 }
 var file_sweep_v1_sweep_proto_depIdxs = []int32{
 	1,  // 0: sweep.v1.GetLargeFilesRequest.sort_by:type_name -> sweep.v1.SortField
-	0,  // 1: sweep.v1.IndexStatus.state:type_name -> sweep.v1.IndexState
-	0,  // 2: sweep.v1.IndexProgress.state:type_name -> sweep.v1.IndexState
-	2,  // 3: sweep.v1.FileEvent.type:type_name -> sweep.v1.FileEvent.EventType
-	20, // 4: sweep.v1.TreeNode.children:type_name -> sweep.v1.TreeNode
-	20, // 5: sweep.v1.GetTreeResponse.root:type_name -> sweep.v1.TreeNode
-	3,  // 6: sweep.v1.TreeEvent.type:type_name -> sweep.v1.TreeEvent.Type
-	4,  // 7: sweep.v1.SweepDaemon.GetLargeFiles:input_type -> sweep.v1.GetLargeFilesRequest
-	6,  // 8: sweep.v1.SweepDaemon.GetIndexStatus:input_type -> sweep.v1.GetIndexStatusRequest
-	8,  // 9: sweep.v1.SweepDaemon.TriggerIndex:input_type -> sweep.v1.TriggerIndexRequest
-	10, // 10: sweep.v1.SweepDaemon.WatchIndexProgress:input_type -> sweep.v1.WatchIndexProgressRequest
-	12, // 11: sweep.v1.SweepDaemon.GetDaemonStatus:input_type -> sweep.v1.GetDaemonStatusRequest
-	14, // 12: sweep.v1.SweepDaemon.Shutdown:input_type -> sweep.v1.ShutdownRequest
-	16, // 13: sweep.v1.SweepDaemon.ClearCache:input_type -> sweep.v1.ClearCacheRequest
-	18, // 14: sweep.v1.SweepDaemon.WatchLargeFiles:input_type -> sweep.v1.WatchRequest
-	21, // 15: sweep.v1.SweepDaemon.GetTree:input_type -> sweep.v1.GetTreeRequest
-	23, // 16: sweep.v1.SweepDaemon.WatchTree:input_type -> sweep.v1.WatchTreeRequest
-	5,  // 17: sweep.v1.SweepDaemon.GetLargeFiles:output_type -> sweep.v1.FileInfo
-	7,  // 18: sweep.v1.SweepDaemon.GetIndexStatus:output_type -> sweep.v1.IndexStatus
-	9,  // 19: sweep.v1.SweepDaemon.TriggerIndex:output_type -> sweep.v1.TriggerIndexResponse
-	11, // 20: sweep.v1.SweepDaemon.WatchIndexProgress:output_type -> sweep.v1.IndexProgress
-	13, // 21: sweep.v1.SweepDaemon.GetDaemonStatus:output_type -> sweep.v1.DaemonStatus
-	15, // 22: sweep.v1.SweepDaemon.Shutdown:output_type -> sweep.v1.ShutdownResponse
-	17, // 23: sweep.v1.SweepDaemon.ClearCache:output_type -> sweep.v1.ClearCacheResponse
-	19, // 24: sweep.v1.SweepDaemon.WatchLargeFiles:output_type -> sweep.v1.FileEvent
-	22, // 25: sweep.v1.SweepDaemon.GetTree:output_type -> sweep.v1.GetTreeResponse
-	24, // 26: sweep.v1.SweepDaemon.WatchTree:output_type -> sweep.v1.TreeEvent
-	17, // [17:27] is the sub-list for method output_type
-	7,  // [7:17] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	6,  // 1: sweep.v1.FileInfo.sharing:type_name -> sweep.v1.Sharing
+	7,  // 2: sweep.v1.FileInfo.cloud:type_name -> sweep.v1.Cloud
+	0,  // 3: sweep.v1.IndexStatus.state:type_name -> sweep.v1.IndexState
+	0,  // 4: sweep.v1.IndexProgress.state:type_name -> sweep.v1.IndexState
+	20, // 5: sweep.v1.DaemonStatus.hash_warmer:type_name -> sweep.v1.HashWarmerStatus
+	16, // 6: sweep.v1.DaemonStatus.roots:type_name -> sweep.v1.RootStatus
+	17, // 7: sweep.v1.DaemonStatus.watcher:type_name -> sweep.v1.WatcherStatus
+	18, // 8: sweep.v1.DaemonStatus.store:type_name -> sweep.v1.StoreStatus
+	19, // 9: sweep.v1.DaemonStatus.recent_errors:type_name -> sweep.v1.DaemonError
+	0,  // 10: sweep.v1.RootStatus.state:type_name -> sweep.v1.IndexState
+	2,  // 11: sweep.v1.FileEvent.type:type_name -> sweep.v1.FileEvent.EventType
+	27, // 12: sweep.v1.TreeNode.children:type_name -> sweep.v1.TreeNode
+	27, // 13: sweep.v1.GetTreeResponse.root:type_name -> sweep.v1.TreeNode
+	31, // 14: sweep.v1.GetDirSizesResponse.dirs:type_name -> sweep.v1.DirSize
+	3,  // 15: sweep.v1.TreeEvent.type:type_name -> sweep.v1.TreeEvent.Type
+	38, // 16: sweep.v1.ExportIndexResponse.entries:type_name -> sweep.v1.IndexEntry
+	0,  // 17: sweep.v1.WatchedRoot.state:type_name -> sweep.v1.IndexState
+	45, // 18: sweep.v1.ListWatchesResponse.roots:type_name -> sweep.v1.WatchedRoot
+	52, // 19: sweep.v1.GetWatchSuggestionsResponse.suggestions:type_name -> sweep.v1.WatchSuggestion
+	57, // 20: sweep.v1.GetSizeDiffResponse.dirs:type_name -> sweep.v1.SizeChange
+	57, // 21: sweep.v1.GetSizeDiffResponse.files:type_name -> sweep.v1.SizeChange
+	60, // 22: sweep.v1.GetSizeHistogramResponse.buckets:type_name -> sweep.v1.SizeBucket
+	68, // 23: sweep.v1.Diagnostics.store_check:type_name -> sweep.v1.StoreCheck
+	72, // 24: sweep.v1.IndexHistory.points:type_name -> sweep.v1.IndexHistoryPoint
+	73, // 25: sweep.v1.GetIndexHistoryResponse.roots:type_name -> sweep.v1.IndexHistory
+	79, // 26: sweep.v1.LogEntry.fields:type_name -> sweep.v1.LogField
+	4,  // 27: sweep.v1.SweepDaemon.GetLargeFiles:input_type -> sweep.v1.GetLargeFilesRequest
+	8,  // 28: sweep.v1.SweepDaemon.GetIndexStatus:input_type -> sweep.v1.GetIndexStatusRequest
+	10, // 29: sweep.v1.SweepDaemon.TriggerIndex:input_type -> sweep.v1.TriggerIndexRequest
+	12, // 30: sweep.v1.SweepDaemon.WatchIndexProgress:input_type -> sweep.v1.WatchIndexProgressRequest
+	14, // 31: sweep.v1.SweepDaemon.GetDaemonStatus:input_type -> sweep.v1.GetDaemonStatusRequest
+	21, // 32: sweep.v1.SweepDaemon.Shutdown:input_type -> sweep.v1.ShutdownRequest
+	23, // 33: sweep.v1.SweepDaemon.ClearCache:input_type -> sweep.v1.ClearCacheRequest
+	25, // 34: sweep.v1.SweepDaemon.WatchLargeFiles:input_type -> sweep.v1.WatchRequest
+	28, // 35: sweep.v1.SweepDaemon.GetTree:input_type -> sweep.v1.GetTreeRequest
+	33, // 36: sweep.v1.SweepDaemon.WatchTree:input_type -> sweep.v1.WatchTreeRequest
+	30, // 37: sweep.v1.SweepDaemon.GetDirSizes:input_type -> sweep.v1.GetDirSizesRequest
+	35, // 38: sweep.v1.SweepDaemon.DeleteFiles:input_type -> sweep.v1.DeleteFilesRequest
+	37, // 39: sweep.v1.SweepDaemon.ExportIndex:input_type -> sweep.v1.ExportIndexRequest
+	40, // 40: sweep.v1.SweepDaemon.AddWatch:input_type -> sweep.v1.AddWatchRequest
+	42, // 41: sweep.v1.SweepDaemon.RemoveWatch:input_type -> sweep.v1.RemoveWatchRequest
+	44, // 42: sweep.v1.SweepDaemon.ListWatches:input_type -> sweep.v1.ListWatchesRequest
+	47, // 43: sweep.v1.SweepDaemon.PauseWatch:input_type -> sweep.v1.PauseWatchRequest
+	49, // 44: sweep.v1.SweepDaemon.ResumeWatch:input_type -> sweep.v1.ResumeWatchRequest
+	51, // 45: sweep.v1.SweepDaemon.GetWatchSuggestions:input_type -> sweep.v1.GetWatchSuggestionsRequest
+	54, // 46: sweep.v1.SweepDaemon.DismissWatchSuggestion:input_type -> sweep.v1.DismissWatchSuggestionRequest
+	56, // 47: sweep.v1.SweepDaemon.GetSizeDiff:input_type -> sweep.v1.GetSizeDiffRequest
+	59, // 48: sweep.v1.SweepDaemon.GetSizeHistogram:input_type -> sweep.v1.GetSizeHistogramRequest
+	62, // 49: sweep.v1.SweepDaemon.SetMinIndexSize:input_type -> sweep.v1.SetMinIndexSizeRequest
+	64, // 50: sweep.v1.SweepDaemon.CompactStore:input_type -> sweep.v1.CompactStoreRequest
+	66, // 51: sweep.v1.SweepDaemon.GetDiagnostics:input_type -> sweep.v1.GetDiagnosticsRequest
+	69, // 52: sweep.v1.SweepDaemon.ComputeHashes:input_type -> sweep.v1.ComputeHashesRequest
+	71, // 53: sweep.v1.SweepDaemon.GetIndexHistory:input_type -> sweep.v1.GetIndexHistoryRequest
+	75, // 54: sweep.v1.SweepDaemon.ReloadConfig:input_type -> sweep.v1.ReloadConfigRequest
+	77, // 55: sweep.v1.SweepDaemon.GetLogs:input_type -> sweep.v1.GetLogsRequest
+	5,  // 56: sweep.v1.SweepDaemon.GetLargeFiles:output_type -> sweep.v1.FileInfo
+	9,  // 57: sweep.v1.SweepDaemon.GetIndexStatus:output_type -> sweep.v1.IndexStatus
+	11, // 58: sweep.v1.SweepDaemon.TriggerIndex:output_type -> sweep.v1.TriggerIndexResponse
+	13, // 59: sweep.v1.SweepDaemon.WatchIndexProgress:output_type -> sweep.v1.IndexProgress
+	15, // 60: sweep.v1.SweepDaemon.GetDaemonStatus:output_type -> sweep.v1.DaemonStatus
+	22, // 61: sweep.v1.SweepDaemon.Shutdown:output_type -> sweep.v1.ShutdownResponse
+	24, // 62: sweep.v1.SweepDaemon.ClearCache:output_type -> sweep.v1.ClearCacheResponse
+	26, // 63: sweep.v1.SweepDaemon.WatchLargeFiles:output_type -> sweep.v1.FileEvent
+	29, // 64: sweep.v1.SweepDaemon.GetTree:output_type -> sweep.v1.GetTreeResponse
+	34, // 65: sweep.v1.SweepDaemon.WatchTree:output_type -> sweep.v1.TreeEvent
+	32, // 66: sweep.v1.SweepDaemon.GetDirSizes:output_type -> sweep.v1.GetDirSizesResponse
+	36, // 67: sweep.v1.SweepDaemon.DeleteFiles:output_type -> sweep.v1.DeleteProgress
+	39, // 68: sweep.v1.SweepDaemon.ExportIndex:output_type -> sweep.v1.ExportIndexResponse
+	41, // 69: sweep.v1.SweepDaemon.AddWatch:output_type -> sweep.v1.AddWatchResponse
+	43, // 70: sweep.v1.SweepDaemon.RemoveWatch:output_type -> sweep.v1.RemoveWatchResponse
+	46, // 71: sweep.v1.SweepDaemon.ListWatches:output_type -> sweep.v1.ListWatchesResponse
+	48, // 72: sweep.v1.SweepDaemon.PauseWatch:output_type -> sweep.v1.PauseWatchResponse
+	50, // 73: sweep.v1.SweepDaemon.ResumeWatch:output_type -> sweep.v1.ResumeWatchResponse
+	53, // 74: sweep.v1.SweepDaemon.GetWatchSuggestions:output_type -> sweep.v1.GetWatchSuggestionsResponse
+	55, // 75: sweep.v1.SweepDaemon.DismissWatchSuggestion:output_type -> sweep.v1.DismissWatchSuggestionResponse
+	58, // 76: sweep.v1.SweepDaemon.GetSizeDiff:output_type -> sweep.v1.GetSizeDiffResponse
+	61, // 77: sweep.v1.SweepDaemon.GetSizeHistogram:output_type -> sweep.v1.GetSizeHistogramResponse
+	63, // 78: sweep.v1.SweepDaemon.SetMinIndexSize:output_type -> sweep.v1.SetMinIndexSizeResponse
+	65, // 79: sweep.v1.SweepDaemon.CompactStore:output_type -> sweep.v1.CompactStoreResponse
+	67, // 80: sweep.v1.SweepDaemon.GetDiagnostics:output_type -> sweep.v1.Diagnostics
+	70, // 81: sweep.v1.SweepDaemon.ComputeHashes:output_type -> sweep.v1.FileHash
+	74, // 82: sweep.v1.SweepDaemon.GetIndexHistory:output_type -> sweep.v1.GetIndexHistoryResponse
+	76, // 83: sweep.v1.SweepDaemon.ReloadConfig:output_type -> sweep.v1.ReloadConfigResponse
+	78, // 84: sweep.v1.SweepDaemon.GetLogs:output_type -> sweep.v1.LogEntry
+	56, // [56:85] is the sub-list for method output_type
+	27, // [27:56] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_sweep_v1_sweep_proto_init() }
@@ -1848,7 +5730,7 @@ func file_sweep_v1_sweep_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sweep_v1_sweep_proto_rawDesc), len(file_sweep_v1_sweep_proto_rawDesc)),
 			NumEnums:      4,
-			NumMessages:   21,
+			NumMessages:   76,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
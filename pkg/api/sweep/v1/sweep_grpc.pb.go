@@ -19,16 +19,35 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SweepDaemon_GetLargeFiles_FullMethodName      = "/sweep.v1.SweepDaemon/GetLargeFiles"
-	SweepDaemon_GetIndexStatus_FullMethodName     = "/sweep.v1.SweepDaemon/GetIndexStatus"
-	SweepDaemon_TriggerIndex_FullMethodName       = "/sweep.v1.SweepDaemon/TriggerIndex"
-	SweepDaemon_WatchIndexProgress_FullMethodName = "/sweep.v1.SweepDaemon/WatchIndexProgress"
-	SweepDaemon_GetDaemonStatus_FullMethodName    = "/sweep.v1.SweepDaemon/GetDaemonStatus"
-	SweepDaemon_Shutdown_FullMethodName           = "/sweep.v1.SweepDaemon/Shutdown"
-	SweepDaemon_ClearCache_FullMethodName         = "/sweep.v1.SweepDaemon/ClearCache"
-	SweepDaemon_WatchLargeFiles_FullMethodName    = "/sweep.v1.SweepDaemon/WatchLargeFiles"
-	SweepDaemon_GetTree_FullMethodName            = "/sweep.v1.SweepDaemon/GetTree"
-	SweepDaemon_WatchTree_FullMethodName          = "/sweep.v1.SweepDaemon/WatchTree"
+	SweepDaemon_GetLargeFiles_FullMethodName          = "/sweep.v1.SweepDaemon/GetLargeFiles"
+	SweepDaemon_GetIndexStatus_FullMethodName         = "/sweep.v1.SweepDaemon/GetIndexStatus"
+	SweepDaemon_TriggerIndex_FullMethodName           = "/sweep.v1.SweepDaemon/TriggerIndex"
+	SweepDaemon_WatchIndexProgress_FullMethodName     = "/sweep.v1.SweepDaemon/WatchIndexProgress"
+	SweepDaemon_GetDaemonStatus_FullMethodName        = "/sweep.v1.SweepDaemon/GetDaemonStatus"
+	SweepDaemon_Shutdown_FullMethodName               = "/sweep.v1.SweepDaemon/Shutdown"
+	SweepDaemon_ClearCache_FullMethodName             = "/sweep.v1.SweepDaemon/ClearCache"
+	SweepDaemon_WatchLargeFiles_FullMethodName        = "/sweep.v1.SweepDaemon/WatchLargeFiles"
+	SweepDaemon_GetTree_FullMethodName                = "/sweep.v1.SweepDaemon/GetTree"
+	SweepDaemon_WatchTree_FullMethodName              = "/sweep.v1.SweepDaemon/WatchTree"
+	SweepDaemon_GetDirSizes_FullMethodName            = "/sweep.v1.SweepDaemon/GetDirSizes"
+	SweepDaemon_DeleteFiles_FullMethodName            = "/sweep.v1.SweepDaemon/DeleteFiles"
+	SweepDaemon_ExportIndex_FullMethodName            = "/sweep.v1.SweepDaemon/ExportIndex"
+	SweepDaemon_AddWatch_FullMethodName               = "/sweep.v1.SweepDaemon/AddWatch"
+	SweepDaemon_RemoveWatch_FullMethodName            = "/sweep.v1.SweepDaemon/RemoveWatch"
+	SweepDaemon_ListWatches_FullMethodName            = "/sweep.v1.SweepDaemon/ListWatches"
+	SweepDaemon_PauseWatch_FullMethodName             = "/sweep.v1.SweepDaemon/PauseWatch"
+	SweepDaemon_ResumeWatch_FullMethodName            = "/sweep.v1.SweepDaemon/ResumeWatch"
+	SweepDaemon_GetWatchSuggestions_FullMethodName    = "/sweep.v1.SweepDaemon/GetWatchSuggestions"
+	SweepDaemon_DismissWatchSuggestion_FullMethodName = "/sweep.v1.SweepDaemon/DismissWatchSuggestion"
+	SweepDaemon_GetSizeDiff_FullMethodName            = "/sweep.v1.SweepDaemon/GetSizeDiff"
+	SweepDaemon_GetSizeHistogram_FullMethodName       = "/sweep.v1.SweepDaemon/GetSizeHistogram"
+	SweepDaemon_SetMinIndexSize_FullMethodName        = "/sweep.v1.SweepDaemon/SetMinIndexSize"
+	SweepDaemon_CompactStore_FullMethodName           = "/sweep.v1.SweepDaemon/CompactStore"
+	SweepDaemon_GetDiagnostics_FullMethodName         = "/sweep.v1.SweepDaemon/GetDiagnostics"
+	SweepDaemon_ComputeHashes_FullMethodName          = "/sweep.v1.SweepDaemon/ComputeHashes"
+	SweepDaemon_GetIndexHistory_FullMethodName        = "/sweep.v1.SweepDaemon/GetIndexHistory"
+	SweepDaemon_ReloadConfig_FullMethodName           = "/sweep.v1.SweepDaemon/ReloadConfig"
+	SweepDaemon_GetLogs_FullMethodName                = "/sweep.v1.SweepDaemon/GetLogs"
 )
 
 // SweepDaemonClient is the client API for SweepDaemon service.
@@ -57,6 +76,60 @@ type SweepDaemonClient interface {
 	GetTree(ctx context.Context, in *GetTreeRequest, opts ...grpc.CallOption) (*GetTreeResponse, error)
 	// Watch for tree changes (file create, modify, delete) in real-time
 	WatchTree(ctx context.Context, in *WatchTreeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TreeEvent], error)
+	// Get the total size of each directory under a path
+	GetDirSizes(ctx context.Context, in *GetDirSizesRequest, opts ...grpc.CallOption) (*GetDirSizesResponse, error)
+	// Delete files and directories on the daemon's machine with a delete
+	// backend, the trash by default, streaming the result for each. Deleted paths
+	// leave the index at once. Refused in read-only mode.
+	DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeleteProgress], error)
+	// Stream every file and directory in the index under a path, in batches,
+	// for exporting the index to other tools
+	ExportIndex(ctx context.Context, in *ExportIndexRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportIndexResponse], error)
+	// Index and watch a directory, and again each time the daemon starts
+	AddWatch(ctx context.Context, in *AddWatchRequest, opts ...grpc.CallOption) (*AddWatchResponse, error)
+	// Stop watching a directory and forget it
+	RemoveWatch(ctx context.Context, in *RemoveWatchRequest, opts ...grpc.CallOption) (*RemoveWatchResponse, error)
+	// List the directories the daemon indexes and watches
+	ListWatches(ctx context.Context, in *ListWatchesRequest, opts ...grpc.CallOption) (*ListWatchesResponse, error)
+	// Stop applying changes under a watched directory until it is resumed,
+	// for heavy operations like large builds or media imports
+	PauseWatch(ctx context.Context, in *PauseWatchRequest, opts ...grpc.CallOption) (*PauseWatchResponse, error)
+	// Apply changes under a paused directory again, catching up on the
+	// directories that changed while it was paused
+	ResumeWatch(ctx context.Context, in *ResumeWatchRequest, opts ...grpc.CallOption) (*ResumeWatchResponse, error)
+	// List indexed directories clients use often that aren't saved watches,
+	// so they can be offered to the user with AddWatch
+	GetWatchSuggestions(ctx context.Context, in *GetWatchSuggestionsRequest, opts ...grpc.CallOption) (*GetWatchSuggestionsResponse, error)
+	// Stop suggesting a directory until the daemon restarts
+	DismissWatchSuggestion(ctx context.Context, in *DismissWatchSuggestionRequest, opts ...grpc.CallOption) (*DismissWatchSuggestionResponse, error)
+	// Compare disk usage under a path now with a snapshot taken earlier
+	GetSizeDiff(ctx context.Context, in *GetSizeDiffRequest, opts ...grpc.CallOption) (*GetSizeDiffResponse, error)
+	// Count the indexed files in each size range, for choosing min_index_size
+	GetSizeHistogram(ctx context.Context, in *GetSizeHistogramRequest, opts ...grpc.CallOption) (*GetSizeHistogramResponse, error)
+	// Change the large files index threshold until the daemon restarts,
+	// adding and dropping files from the index to match. Roots indexed in
+	// aggregates mode are re-indexed when it is lowered.
+	SetMinIndexSize(ctx context.Context, in *SetMinIndexSizeRequest, opts ...grpc.CallOption) (*SetMinIndexSizeResponse, error)
+	// Drop stale records from the index and rewrite the store to free the
+	// space they took. Refused while a path is being indexed.
+	CompactStore(ctx context.Context, in *CompactStoreRequest, opts ...grpc.CallOption) (*CompactStoreResponse, error)
+	// Report the daemon's process health: goroutines, open file descriptors,
+	// the watcher's backlog and, when asked, an integrity check of the store.
+	GetDiagnostics(ctx context.Context, in *GetDiagnosticsRequest, opts ...grpc.CallOption) (*Diagnostics, error)
+	// Hash files on the daemon's worker pool, streaming each file's SHA-256
+	// and xxhash as it completes. Hashes are cached in the store until the
+	// file changes, and back duplicate detection.
+	ComputeHashes(ctx context.Context, in *ComputeHashesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileHash], error)
+	// Report the total size and file count of indexed roots over time,
+	// recorded with each snapshot, for charting index growth.
+	GetIndexHistory(ctx context.Context, in *GetIndexHistoryRequest, opts ...grpc.CallOption) (*GetIndexHistoryResponse, error)
+	// Read the configuration file again and apply what changed without a
+	// restart: log levels, the large files index threshold, index paths,
+	// alerts, and hooks. The daemon also reloads whenever the file changes.
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// Stream the daemon's recent log entries, oldest first, filtered by level,
+	// component, and time, then optionally each new entry as it is logged.
+	GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error)
 }
 
 type sweepDaemonClient struct {
@@ -203,6 +276,232 @@ func (c *sweepDaemonClient) WatchTree(ctx context.Context, in *WatchTreeRequest,
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SweepDaemon_WatchTreeClient = grpc.ServerStreamingClient[TreeEvent]
 
+func (c *sweepDaemonClient) GetDirSizes(ctx context.Context, in *GetDirSizesRequest, opts ...grpc.CallOption) (*GetDirSizesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDirSizesResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetDirSizes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) DeleteFiles(ctx context.Context, in *DeleteFilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeleteProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SweepDaemon_ServiceDesc.Streams[4], SweepDaemon_DeleteFiles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DeleteFilesRequest, DeleteProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_DeleteFilesClient = grpc.ServerStreamingClient[DeleteProgress]
+
+func (c *sweepDaemonClient) ExportIndex(ctx context.Context, in *ExportIndexRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportIndexResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SweepDaemon_ServiceDesc.Streams[5], SweepDaemon_ExportIndex_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportIndexRequest, ExportIndexResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_ExportIndexClient = grpc.ServerStreamingClient[ExportIndexResponse]
+
+func (c *sweepDaemonClient) AddWatch(ctx context.Context, in *AddWatchRequest, opts ...grpc.CallOption) (*AddWatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWatchResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_AddWatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) RemoveWatch(ctx context.Context, in *RemoveWatchRequest, opts ...grpc.CallOption) (*RemoveWatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveWatchResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_RemoveWatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) ListWatches(ctx context.Context, in *ListWatchesRequest, opts ...grpc.CallOption) (*ListWatchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWatchesResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_ListWatches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) PauseWatch(ctx context.Context, in *PauseWatchRequest, opts ...grpc.CallOption) (*PauseWatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseWatchResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_PauseWatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) ResumeWatch(ctx context.Context, in *ResumeWatchRequest, opts ...grpc.CallOption) (*ResumeWatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeWatchResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_ResumeWatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) GetWatchSuggestions(ctx context.Context, in *GetWatchSuggestionsRequest, opts ...grpc.CallOption) (*GetWatchSuggestionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWatchSuggestionsResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetWatchSuggestions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) DismissWatchSuggestion(ctx context.Context, in *DismissWatchSuggestionRequest, opts ...grpc.CallOption) (*DismissWatchSuggestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DismissWatchSuggestionResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_DismissWatchSuggestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) GetSizeDiff(ctx context.Context, in *GetSizeDiffRequest, opts ...grpc.CallOption) (*GetSizeDiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSizeDiffResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetSizeDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) GetSizeHistogram(ctx context.Context, in *GetSizeHistogramRequest, opts ...grpc.CallOption) (*GetSizeHistogramResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSizeHistogramResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetSizeHistogram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) SetMinIndexSize(ctx context.Context, in *SetMinIndexSizeRequest, opts ...grpc.CallOption) (*SetMinIndexSizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMinIndexSizeResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_SetMinIndexSize_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) CompactStore(ctx context.Context, in *CompactStoreRequest, opts ...grpc.CallOption) (*CompactStoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompactStoreResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_CompactStore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) GetDiagnostics(ctx context.Context, in *GetDiagnosticsRequest, opts ...grpc.CallOption) (*Diagnostics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Diagnostics)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetDiagnostics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) ComputeHashes(ctx context.Context, in *ComputeHashesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileHash], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SweepDaemon_ServiceDesc.Streams[6], SweepDaemon_ComputeHashes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ComputeHashesRequest, FileHash]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_ComputeHashesClient = grpc.ServerStreamingClient[FileHash]
+
+func (c *sweepDaemonClient) GetIndexHistory(ctx context.Context, in *GetIndexHistoryRequest, opts ...grpc.CallOption) (*GetIndexHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIndexHistoryResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_GetIndexHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, SweepDaemon_ReloadConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sweepDaemonClient) GetLogs(ctx context.Context, in *GetLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SweepDaemon_ServiceDesc.Streams[7], SweepDaemon_GetLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetLogsRequest, LogEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_GetLogsClient = grpc.ServerStreamingClient[LogEntry]
+
 // SweepDaemonServer is the server API for SweepDaemon service.
 // All implementations must embed UnimplementedSweepDaemonServer
 // for forward compatibility.
@@ -229,6 +528,60 @@ type SweepDaemonServer interface {
 	GetTree(context.Context, *GetTreeRequest) (*GetTreeResponse, error)
 	// Watch for tree changes (file create, modify, delete) in real-time
 	WatchTree(*WatchTreeRequest, grpc.ServerStreamingServer[TreeEvent]) error
+	// Get the total size of each directory under a path
+	GetDirSizes(context.Context, *GetDirSizesRequest) (*GetDirSizesResponse, error)
+	// Delete files and directories on the daemon's machine with a delete
+	// backend, the trash by default, streaming the result for each. Deleted paths
+	// leave the index at once. Refused in read-only mode.
+	DeleteFiles(*DeleteFilesRequest, grpc.ServerStreamingServer[DeleteProgress]) error
+	// Stream every file and directory in the index under a path, in batches,
+	// for exporting the index to other tools
+	ExportIndex(*ExportIndexRequest, grpc.ServerStreamingServer[ExportIndexResponse]) error
+	// Index and watch a directory, and again each time the daemon starts
+	AddWatch(context.Context, *AddWatchRequest) (*AddWatchResponse, error)
+	// Stop watching a directory and forget it
+	RemoveWatch(context.Context, *RemoveWatchRequest) (*RemoveWatchResponse, error)
+	// List the directories the daemon indexes and watches
+	ListWatches(context.Context, *ListWatchesRequest) (*ListWatchesResponse, error)
+	// Stop applying changes under a watched directory until it is resumed,
+	// for heavy operations like large builds or media imports
+	PauseWatch(context.Context, *PauseWatchRequest) (*PauseWatchResponse, error)
+	// Apply changes under a paused directory again, catching up on the
+	// directories that changed while it was paused
+	ResumeWatch(context.Context, *ResumeWatchRequest) (*ResumeWatchResponse, error)
+	// List indexed directories clients use often that aren't saved watches,
+	// so they can be offered to the user with AddWatch
+	GetWatchSuggestions(context.Context, *GetWatchSuggestionsRequest) (*GetWatchSuggestionsResponse, error)
+	// Stop suggesting a directory until the daemon restarts
+	DismissWatchSuggestion(context.Context, *DismissWatchSuggestionRequest) (*DismissWatchSuggestionResponse, error)
+	// Compare disk usage under a path now with a snapshot taken earlier
+	GetSizeDiff(context.Context, *GetSizeDiffRequest) (*GetSizeDiffResponse, error)
+	// Count the indexed files in each size range, for choosing min_index_size
+	GetSizeHistogram(context.Context, *GetSizeHistogramRequest) (*GetSizeHistogramResponse, error)
+	// Change the large files index threshold until the daemon restarts,
+	// adding and dropping files from the index to match. Roots indexed in
+	// aggregates mode are re-indexed when it is lowered.
+	SetMinIndexSize(context.Context, *SetMinIndexSizeRequest) (*SetMinIndexSizeResponse, error)
+	// Drop stale records from the index and rewrite the store to free the
+	// space they took. Refused while a path is being indexed.
+	CompactStore(context.Context, *CompactStoreRequest) (*CompactStoreResponse, error)
+	// Report the daemon's process health: goroutines, open file descriptors,
+	// the watcher's backlog and, when asked, an integrity check of the store.
+	GetDiagnostics(context.Context, *GetDiagnosticsRequest) (*Diagnostics, error)
+	// Hash files on the daemon's worker pool, streaming each file's SHA-256
+	// and xxhash as it completes. Hashes are cached in the store until the
+	// file changes, and back duplicate detection.
+	ComputeHashes(*ComputeHashesRequest, grpc.ServerStreamingServer[FileHash]) error
+	// Report the total size and file count of indexed roots over time,
+	// recorded with each snapshot, for charting index growth.
+	GetIndexHistory(context.Context, *GetIndexHistoryRequest) (*GetIndexHistoryResponse, error)
+	// Read the configuration file again and apply what changed without a
+	// restart: log levels, the large files index threshold, index paths,
+	// alerts, and hooks. The daemon also reloads whenever the file changes.
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	// Stream the daemon's recent log entries, oldest first, filtered by level,
+	// component, and time, then optionally each new entry as it is logged.
+	GetLogs(*GetLogsRequest, grpc.ServerStreamingServer[LogEntry]) error
 	mustEmbedUnimplementedSweepDaemonServer()
 }
 
@@ -269,6 +622,63 @@ func (UnimplementedSweepDaemonServer) GetTree(context.Context, *GetTreeRequest)
 func (UnimplementedSweepDaemonServer) WatchTree(*WatchTreeRequest, grpc.ServerStreamingServer[TreeEvent]) error {
 	return status.Errorf(codes.Unimplemented, "method WatchTree not implemented")
 }
+func (UnimplementedSweepDaemonServer) GetDirSizes(context.Context, *GetDirSizesRequest) (*GetDirSizesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDirSizes not implemented")
+}
+func (UnimplementedSweepDaemonServer) DeleteFiles(*DeleteFilesRequest, grpc.ServerStreamingServer[DeleteProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method DeleteFiles not implemented")
+}
+func (UnimplementedSweepDaemonServer) ExportIndex(*ExportIndexRequest, grpc.ServerStreamingServer[ExportIndexResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportIndex not implemented")
+}
+func (UnimplementedSweepDaemonServer) AddWatch(context.Context, *AddWatchRequest) (*AddWatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWatch not implemented")
+}
+func (UnimplementedSweepDaemonServer) RemoveWatch(context.Context, *RemoveWatchRequest) (*RemoveWatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWatch not implemented")
+}
+func (UnimplementedSweepDaemonServer) ListWatches(context.Context, *ListWatchesRequest) (*ListWatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWatches not implemented")
+}
+func (UnimplementedSweepDaemonServer) PauseWatch(context.Context, *PauseWatchRequest) (*PauseWatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWatch not implemented")
+}
+func (UnimplementedSweepDaemonServer) ResumeWatch(context.Context, *ResumeWatchRequest) (*ResumeWatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeWatch not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetWatchSuggestions(context.Context, *GetWatchSuggestionsRequest) (*GetWatchSuggestionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWatchSuggestions not implemented")
+}
+func (UnimplementedSweepDaemonServer) DismissWatchSuggestion(context.Context, *DismissWatchSuggestionRequest) (*DismissWatchSuggestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DismissWatchSuggestion not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetSizeDiff(context.Context, *GetSizeDiffRequest) (*GetSizeDiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSizeDiff not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetSizeHistogram(context.Context, *GetSizeHistogramRequest) (*GetSizeHistogramResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSizeHistogram not implemented")
+}
+func (UnimplementedSweepDaemonServer) SetMinIndexSize(context.Context, *SetMinIndexSizeRequest) (*SetMinIndexSizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMinIndexSize not implemented")
+}
+func (UnimplementedSweepDaemonServer) CompactStore(context.Context, *CompactStoreRequest) (*CompactStoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompactStore not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetDiagnostics(context.Context, *GetDiagnosticsRequest) (*Diagnostics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDiagnostics not implemented")
+}
+func (UnimplementedSweepDaemonServer) ComputeHashes(*ComputeHashesRequest, grpc.ServerStreamingServer[FileHash]) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeHashes not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetIndexHistory(context.Context, *GetIndexHistoryRequest) (*GetIndexHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIndexHistory not implemented")
+}
+func (UnimplementedSweepDaemonServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedSweepDaemonServer) GetLogs(*GetLogsRequest, grpc.ServerStreamingServer[LogEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+}
 func (UnimplementedSweepDaemonServer) mustEmbedUnimplementedSweepDaemonServer() {}
 func (UnimplementedSweepDaemonServer) testEmbeddedByValue()                     {}
 
@@ -442,6 +852,320 @@ func _SweepDaemon_WatchTree_Handler(srv interface{}, stream grpc.ServerStream) e
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SweepDaemon_WatchTreeServer = grpc.ServerStreamingServer[TreeEvent]
 
+func _SweepDaemon_GetDirSizes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDirSizesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetDirSizes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetDirSizes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetDirSizes(ctx, req.(*GetDirSizesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_DeleteFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeleteFilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SweepDaemonServer).DeleteFiles(m, &grpc.GenericServerStream[DeleteFilesRequest, DeleteProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_DeleteFilesServer = grpc.ServerStreamingServer[DeleteProgress]
+
+func _SweepDaemon_ExportIndex_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportIndexRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SweepDaemonServer).ExportIndex(m, &grpc.GenericServerStream[ExportIndexRequest, ExportIndexResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_ExportIndexServer = grpc.ServerStreamingServer[ExportIndexResponse]
+
+func _SweepDaemon_AddWatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).AddWatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_AddWatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).AddWatch(ctx, req.(*AddWatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_RemoveWatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).RemoveWatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_RemoveWatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).RemoveWatch(ctx, req.(*RemoveWatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_ListWatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).ListWatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_ListWatches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).ListWatches(ctx, req.(*ListWatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_PauseWatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseWatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).PauseWatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_PauseWatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).PauseWatch(ctx, req.(*PauseWatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_ResumeWatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeWatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).ResumeWatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_ResumeWatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).ResumeWatch(ctx, req.(*ResumeWatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_GetWatchSuggestions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWatchSuggestionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetWatchSuggestions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetWatchSuggestions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetWatchSuggestions(ctx, req.(*GetWatchSuggestionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_DismissWatchSuggestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DismissWatchSuggestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).DismissWatchSuggestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_DismissWatchSuggestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).DismissWatchSuggestion(ctx, req.(*DismissWatchSuggestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_GetSizeDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSizeDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetSizeDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetSizeDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetSizeDiff(ctx, req.(*GetSizeDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_GetSizeHistogram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSizeHistogramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetSizeHistogram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetSizeHistogram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetSizeHistogram(ctx, req.(*GetSizeHistogramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_SetMinIndexSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMinIndexSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).SetMinIndexSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_SetMinIndexSize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).SetMinIndexSize(ctx, req.(*SetMinIndexSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_CompactStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactStoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).CompactStore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_CompactStore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).CompactStore(ctx, req.(*CompactStoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_GetDiagnostics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDiagnosticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetDiagnostics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetDiagnostics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetDiagnostics(ctx, req.(*GetDiagnosticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_ComputeHashes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ComputeHashesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SweepDaemonServer).ComputeHashes(m, &grpc.GenericServerStream[ComputeHashesRequest, FileHash]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_ComputeHashesServer = grpc.ServerStreamingServer[FileHash]
+
+func _SweepDaemon_GetIndexHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIndexHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).GetIndexHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_GetIndexHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).GetIndexHistory(ctx, req.(*GetIndexHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SweepDaemonServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SweepDaemon_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SweepDaemonServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SweepDaemon_GetLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SweepDaemonServer).GetLogs(m, &grpc.GenericServerStream[GetLogsRequest, LogEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SweepDaemon_GetLogsServer = grpc.ServerStreamingServer[LogEntry]
+
 // SweepDaemon_ServiceDesc is the grpc.ServiceDesc for SweepDaemon service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -473,6 +1197,66 @@ var SweepDaemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTree",
 			Handler:    _SweepDaemon_GetTree_Handler,
 		},
+		{
+			MethodName: "GetDirSizes",
+			Handler:    _SweepDaemon_GetDirSizes_Handler,
+		},
+		{
+			MethodName: "AddWatch",
+			Handler:    _SweepDaemon_AddWatch_Handler,
+		},
+		{
+			MethodName: "RemoveWatch",
+			Handler:    _SweepDaemon_RemoveWatch_Handler,
+		},
+		{
+			MethodName: "ListWatches",
+			Handler:    _SweepDaemon_ListWatches_Handler,
+		},
+		{
+			MethodName: "PauseWatch",
+			Handler:    _SweepDaemon_PauseWatch_Handler,
+		},
+		{
+			MethodName: "ResumeWatch",
+			Handler:    _SweepDaemon_ResumeWatch_Handler,
+		},
+		{
+			MethodName: "GetWatchSuggestions",
+			Handler:    _SweepDaemon_GetWatchSuggestions_Handler,
+		},
+		{
+			MethodName: "DismissWatchSuggestion",
+			Handler:    _SweepDaemon_DismissWatchSuggestion_Handler,
+		},
+		{
+			MethodName: "GetSizeDiff",
+			Handler:    _SweepDaemon_GetSizeDiff_Handler,
+		},
+		{
+			MethodName: "GetSizeHistogram",
+			Handler:    _SweepDaemon_GetSizeHistogram_Handler,
+		},
+		{
+			MethodName: "SetMinIndexSize",
+			Handler:    _SweepDaemon_SetMinIndexSize_Handler,
+		},
+		{
+			MethodName: "CompactStore",
+			Handler:    _SweepDaemon_CompactStore_Handler,
+		},
+		{
+			MethodName: "GetDiagnostics",
+			Handler:    _SweepDaemon_GetDiagnostics_Handler,
+		},
+		{
+			MethodName: "GetIndexHistory",
+			Handler:    _SweepDaemon_GetIndexHistory_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _SweepDaemon_ReloadConfig_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -495,6 +1279,26 @@ var SweepDaemon_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _SweepDaemon_WatchTree_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "DeleteFiles",
+			Handler:       _SweepDaemon_DeleteFiles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportIndex",
+			Handler:       _SweepDaemon_ExportIndex_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ComputeHashes",
+			Handler:       _SweepDaemon_ComputeHashes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetLogs",
+			Handler:       _SweepDaemon_GetLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "sweep/v1/sweep.proto",
 }
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,17 +19,63 @@ import (
 
 	"github.com/adrg/xdg"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/ipc"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/growth"
+	"github.com/jamesainslie/sweep/pkg/sweep/indexsize"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
+// DefaultTimeout is the deadline for unary RPCs whose context has none,
+// so a daemon that stops answering can't hang a command.
+const DefaultTimeout = 30 * time.Second
+
 // Client connects to the sweepd daemon via gRPC.
 type Client struct {
-	conn   *grpc.ClientConn
-	client sweepv1.SweepDaemonClient
+	conn    *grpc.ClientConn
+	client  sweepv1.SweepDaemonClient
+	timeout time.Duration
+}
+
+// newClient returns a client whose connection is made by dial, which is
+// given the options every connection needs.
+func newClient(dial func(opts ...grpc.DialOption) (*grpc.ClientConn, error)) (*Client, error) {
+	c := &Client{timeout: DefaultTimeout}
+	conn, err := dial(grpc.WithUnaryInterceptor(c.withDeadline))
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.client = sweepv1.NewSweepDaemonClient(conn)
+	return c, nil
+}
+
+// SetTimeout sets the deadline for unary RPCs whose context has none;
+// zero or less sets none. Streams, such as large file queries and watches,
+// are bounded only by their context.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+// withDeadline gives a unary RPC the client's timeout unless its context
+// already has a deadline.
+func (c *Client) withDeadline(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
 }
 
 // IndexStatus represents the indexing status of a path.
@@ -40,6 +87,146 @@ type IndexStatus struct {
 	TotalSize    int64
 	LastUpdated  time.Time
 	Progress     float32
+	Mode         string // "full" or "aggregates"
+}
+
+// LargeFiles is the result of a large files query.
+type LargeFiles struct {
+	Files []types.FileInfo
+	// Truncated is set when more files matched than were returned, because
+	// of the request's limit or the daemon's default (daemon.max_results).
+	Truncated bool
+	// NextPage continues a paged query (QueryLargeFilesPage) with the
+	// files after these; it is empty on the last page.
+	NextPage string
+}
+
+// ErrUnsupported is returned for requests the daemon is too old to serve.
+var ErrUnsupported = errors.New("not supported by the running daemon; restart it after upgrading")
+
+// DeleteResult is the outcome of deleting one path through the daemon.
+type DeleteResult struct {
+	Path    string
+	Size    int64 // Size of the file, or of everything in the directory
+	Err     error // Why the path wasn't deleted
+	Current int   // Paths finished so far, including this one
+	Total   int
+
+	Location string // Where the staging backend moved the path
+}
+
+// DeleteOptions control DeleteFiles.
+type DeleteOptions struct {
+	DryRun    bool   // Report what would be deleted without deleting
+	Permanent bool   // Delete instead of trashing; only under the daemon's trash.permanent_roots
+	Backend   string // Delete backend, e.g. trash.BackendStaging; empty trashes, or deletes with Permanent
+}
+
+// FileHash is the outcome of hashing one file through the daemon.
+type FileHash struct {
+	Path    string
+	Size    int64
+	SHA256  []byte
+	XXHash  uint64
+	Cached  bool  // Read from the daemon's store rather than the file
+	Err     error // Why the file wasn't hashed
+	Current int   // Files finished so far, including this one
+	Total   int
+}
+
+// WatchedRoot is a directory the daemon indexes and watches.
+type WatchedRoot struct {
+	Path         string
+	State        string // As in IndexStatus
+	FilesIndexed int64
+	Saved        bool // Watched again after the daemon restarts
+	Paused       bool // Changes are not applied until resumed
+}
+
+// ResumedWatch is the result of resuming a paused watch.
+type ResumedWatch struct {
+	DirsReconciled int64 // Directories that changed while paused
+	Reindexing     bool  // Too much changed to catch up, so indexing again
+}
+
+// WatchSuggestion is an indexed directory used often enough to be worth
+// watching across daemon restarts.
+type WatchSuggestion struct {
+	Path     string
+	Uses     int       // Separate times it was queried since the daemon started
+	LastUsed time.Time // Latest query
+}
+
+// IndexSizeChange is the result of changing the daemon's large files index
+// threshold.
+type IndexSizeChange struct {
+	Previous   int64    // The threshold before
+	Added      int64    // Files added to the large files index
+	Removed    int64    // Files dropped from it
+	Reindexing []string // Roots being re-indexed to find smaller files
+}
+
+// Compaction is the result of compacting the daemon's index store.
+type Compaction struct {
+	Pruned     int64 // Stale records dropped
+	SizeBefore int64 // Bytes the store took on disk before
+	SizeAfter  int64 // Bytes it takes after
+}
+
+// Reclaimed returns the bytes compacting freed on disk.
+func (c Compaction) Reclaimed() int64 {
+	return max(c.SizeBefore-c.SizeAfter, 0)
+}
+
+// ConfigReload is what reloading the daemon's configuration changed.
+type ConfigReload struct {
+	Changed   []string // Settings that changed, e.g. "logging"
+	Indexing  []string // Paths added to daemon.index_paths, now indexed
+	Unwatched []string // Paths removed from it, no longer watched
+	Errors    []string // Settings that couldn't be applied, and why
+}
+
+// LogQuery selects the daemon log entries GetLogs streams.
+type LogQuery struct {
+	logging.EntryFilter
+	Limit  int  // At most this many of the recent entries (0 = all the daemon keeps)
+	Follow bool // Keep streaming new entries until the context is canceled
+}
+
+// LogSource is the LogEntry.Source of entries read from the daemon.
+const LogSource = "daemon"
+
+// Diagnostics reports the daemon's process health.
+type Diagnostics struct {
+	Version       string
+	PID           int
+	Goroutines    int64
+	OpenFDs       int64       // -1 if unknown
+	FDLimit       int64       // Soft limit on open files; -1 if unknown
+	WatcherQueued int64       // Filesystem events received but not handled yet
+	WatcherHeld   int64       // Changed directories held back under paused roots
+	WatcherDirs   int64       // Directories with a filesystem watch
+	StoreCheck    *StoreCheck // Nil unless the store was checked
+}
+
+// StoreCheck is the result of checking every record in the index store.
+type StoreCheck struct {
+	Records  int64
+	Problems []string // The first problems found; none means the store is sound
+	Damaged  int64    // Problems found, listed or not
+	Duration time.Duration
+}
+
+// IndexEntry is a file or directory in the daemon's index.
+type IndexEntry struct {
+	Path     string
+	Size     int64 // For directories, the size of the files beneath
+	ModTime  int64 // Unix seconds
+	IsDir    bool
+	Files    int64  // Files beneath a directory
+	Owner    string // Username, or UID without an account; empty if unknown
+	FileType string
+	Shared   bool // Shares storage through hard links or clones
 }
 
 // DaemonStatus represents the daemon's current status.
@@ -50,6 +237,66 @@ type DaemonStatus struct {
 	WatchedPaths      []string
 	CacheSizeBytes    int64
 	TotalFilesIndexed int64
+	HashWarmer        HashWarmerStatus
+	IndexMode         string // Mode used for new indexes
+	Version           string // Empty for daemons that predate reporting it
+	Roots             []RootStatus
+	Watcher           WatcherStatus
+	Store             StoreStatus
+	RecentErrors      []DaemonError // Oldest first
+}
+
+// RootStatus is the state of a root the daemon indexed or watches.
+type RootStatus struct {
+	Path         string
+	State        string // As in IndexStatus
+	FilesIndexed int64
+	DirsIndexed  int64
+	TotalSize    int64     // Bytes found by the last index; 0 if before the daemon started
+	LastIndexed  time.Time // Zero if indexed before the daemon started
+	LastQueried  time.Time // Zero if never queried
+	Mode         string
+	Saved        bool    // Watched again after the daemon restarts
+	Paused       bool    // Changes are not applied until resumed
+	Error        string  // Why the last index failed
+	Progress     float32 // Share of an index in progress done, from 0 to 1; 0 if unknown
+	CurrentPath  string  // Where an index in progress has got to
+	Queued       bool    // Waiting its turn to be indexed at daemon startup
+}
+
+// WatcherStatus reports the daemon's filesystem watcher since it started.
+type WatcherStatus struct {
+	Directories int64
+	Events      int64
+	Errors      int64
+	LastEvent   time.Time // Zero if none
+}
+
+// StoreStatus reports the daemon's index store.
+type StoreStatus struct {
+	Backend       string
+	SizeBytes     int64
+	SchemaVersion int
+	Migrating     bool
+	LastCompacted time.Time // Zero if not since the daemon started
+}
+
+// DaemonError is an error from the daemon's background work.
+type DaemonError struct {
+	Time      time.Time
+	Component string
+	Path      string
+	Message   string
+}
+
+// HashWarmerStatus reports the daemon's background hashing progress.
+type HashWarmerStatus struct {
+	Enabled     bool
+	Active      bool
+	Queued      int64
+	FilesHashed int64
+	BytesHashed int64
+	CurrentPath string
 }
 
 // FileEvent represents a file change event from the daemon.
@@ -58,16 +305,18 @@ type FileEvent struct {
 	Path    string
 	Size    int64
 	ModTime int64
+	NewPath string // Where a renamed directory moved, with everything in it
 }
 
 // TreeEvent represents a tree change event from the daemon.
 // It includes ParentPath to enable efficient tree updates.
 type TreeEvent struct {
-	Type       string // "created", "modified", "deleted"
+	Type       string // "created", "modified", "deleted", "renamed"
 	Path       string
 	Size       int64
 	ModTime    int64
 	ParentPath string
+	NewPath    string // Where a renamed directory moved, with everything in it
 }
 
 // TreeNode represents a node in the large file tree.
@@ -83,7 +332,8 @@ type TreeNode struct {
 	Children       []*TreeNode
 }
 
-// DefaultSocketPath returns the default Unix socket path for sweepd.
+// DefaultSocketPath returns the default Unix socket path for sweepd. On
+// Windows sweepd listens on a named pipe derived from it.
 func DefaultSocketPath() string {
 	return filepath.Join(xdg.DataHome, "sweep", "sweep.sock")
 }
@@ -123,28 +373,27 @@ func Connect(socketPath string) (*Client, error) {
 // ConnectWithContext establishes a connection to the sweepd daemon with a custom context.
 func ConnectWithContext(ctx context.Context, socketPath string) (*Client, error) {
 	// Check if socket exists
-	if _, err := os.Stat(socketPath); os.IsNotExist(err) {
+	if !ipc.Exists(socketPath) {
 		return nil, fmt.Errorf("daemon socket not found at %s", socketPath)
 	}
 
-	target := "unix://" + socketPath
-
-	// Use DialContext with block option to ensure connection is established
-	//nolint:staticcheck // grpc.DialContext is deprecated but NewClient doesn't support blocking
-	conn, err := grpc.DialContext(
-		ctx,
-		target,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	// Use DialContext with block option to ensure connection is established.
+	// The socket, or named pipe on Windows, is dialed directly.
+	c, err := newClient(func(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+		//nolint:staticcheck // grpc.DialContext is deprecated but NewClient doesn't support blocking
+		return grpc.DialContext(ctx, "passthrough:///sweepd", append(opts,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return ipc.Dial(ctx, socketPath)
+			}),
+			grpc.WithAuthority("localhost"),
+			grpc.WithBlock(),
+		)...)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
-
-	return &Client{
-		conn:   conn,
-		client: sweepv1.NewSweepDaemonClient(conn),
-	}, nil
+	return c, nil
 }
 
 // Close closes the connection to the daemon.
@@ -156,21 +405,50 @@ func (c *Client) Close() error {
 }
 
 // GetLargeFiles queries the daemon for files matching the criteria.
-// Returns files sorted by size (largest first).
+// Returns files sorted by size (largest first). A limit of 0 uses the
+// daemon's default.
 func (c *Client) GetLargeFiles(ctx context.Context, path string, minSize int64, exclude []string, limit int) ([]types.FileInfo, error) {
-	req := &sweepv1.GetLargeFilesRequest{
+	result, err := c.QueryLargeFiles(ctx, path, minSize, exclude, limit)
+	if err != nil {
+		return nil, err
+	}
+	return result.Files, nil
+}
+
+// QueryLargeFiles is GetLargeFiles that also reports whether the daemon
+// truncated the results.
+func (c *Client) QueryLargeFiles(ctx context.Context, path string, minSize int64, exclude []string, limit int) (*LargeFiles, error) {
+	return c.queryLargeFiles(ctx, &sweepv1.GetLargeFilesRequest{
 		Path:    path,
 		MinSize: minSize,
 		Exclude: exclude,
 		Limit:   int32(limit),
-	}
+	})
+}
 
+// QueryLargeFilesPage returns one page of up to pageSize files, largest
+// first. Pass an empty pageToken for the first page, then the previous
+// result's NextPage for each page after it, keeping the other arguments
+// the same.
+func (c *Client) QueryLargeFilesPage(ctx context.Context, path string, minSize int64, exclude []string, pageSize int, pageToken string) (*LargeFiles, error) {
+	return c.queryLargeFiles(ctx, &sweepv1.GetLargeFilesRequest{
+		Path:           path,
+		MinSize:        minSize,
+		Exclude:        exclude,
+		Limit:          int32(pageSize),
+		SortBy:         sweepv1.SortField_SORT_SIZE,
+		SortDescending: true,
+		PageToken:      pageToken,
+	})
+}
+
+func (c *Client) queryLargeFiles(ctx context.Context, req *sweepv1.GetLargeFilesRequest) (*LargeFiles, error) {
 	stream, err := c.client.GetLargeFiles(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("GetLargeFiles RPC failed: %w", err)
 	}
 
-	var files []types.FileInfo
+	result := &LargeFiles{}
 	for {
 		fileInfo, err := stream.Recv()
 		if errors.Is(err, io.EOF) {
@@ -179,10 +457,16 @@ func (c *Client) GetLargeFiles(ctx context.Context, path string, minSize int64,
 		if err != nil {
 			return nil, fmt.Errorf("error receiving file: %w", err)
 		}
-		files = append(files, protoToFileInfo(fileInfo))
+		result.Files = append(result.Files, protoToFileInfo(fileInfo))
+		if fileInfo.GetTruncated() {
+			result.Truncated = true
+		}
+		if token := fileInfo.GetNextPageToken(); token != "" {
+			result.NextPage = token
+		}
 	}
 
-	return files, nil
+	return result, nil
 }
 
 // IsIndexReady checks if the index for the given path is ready for queries.
@@ -214,6 +498,7 @@ func (c *Client) GetIndexStatus(ctx context.Context, path string) (*IndexStatus,
 		TotalSize:    status.GetTotalSize(),
 		LastUpdated:  time.Unix(status.GetLastUpdated(), 0),
 		Progress:     status.GetProgress(),
+		Mode:         status.GetIndexMode(),
 	}, nil
 }
 
@@ -242,6 +527,7 @@ func (c *Client) GetDaemonStatus(ctx context.Context) (*DaemonStatus, error) {
 		return nil, fmt.Errorf("GetDaemonStatus RPC failed: %w", err)
 	}
 
+	hw := status.GetHashWarmer()
 	return &DaemonStatus{
 		Running:           status.GetRunning(),
 		UptimeSeconds:     status.GetUptimeSeconds(),
@@ -249,9 +535,86 @@ func (c *Client) GetDaemonStatus(ctx context.Context) (*DaemonStatus, error) {
 		WatchedPaths:      status.GetWatchedPaths(),
 		CacheSizeBytes:    status.GetCacheSizeBytes(),
 		TotalFilesIndexed: status.GetTotalFilesIndexed(),
+		HashWarmer: HashWarmerStatus{
+			Enabled:     hw.GetEnabled(),
+			Active:      hw.GetActive(),
+			Queued:      hw.GetQueued(),
+			FilesHashed: hw.GetFilesHashed(),
+			BytesHashed: hw.GetBytesHashed(),
+			CurrentPath: hw.GetCurrentPath(),
+		},
+		IndexMode:    status.GetIndexMode(),
+		Version:      status.GetVersion(),
+		Roots:        rootStatuses(status.GetRoots()),
+		Watcher:      watcherStatus(status.GetWatcher()),
+		Store:        storeStatus(status.GetStore()),
+		RecentErrors: daemonErrors(status.GetRecentErrors()),
 	}, nil
 }
 
+func rootStatuses(roots []*sweepv1.RootStatus) []RootStatus {
+	out := make([]RootStatus, 0, len(roots))
+	for _, r := range roots {
+		out = append(out, RootStatus{
+			Path:         r.GetPath(),
+			State:        indexStateToString(r.GetState()),
+			FilesIndexed: r.GetFilesIndexed(),
+			DirsIndexed:  r.GetDirsIndexed(),
+			TotalSize:    r.GetTotalSize(),
+			LastIndexed:  unixTime(r.GetLastIndexed()),
+			LastQueried:  unixTime(r.GetLastQueried()),
+			Mode:         r.GetIndexMode(),
+			Saved:        r.GetSaved(),
+			Paused:       r.GetPaused(),
+			Error:        r.GetError(),
+			Progress:     r.GetProgress(),
+			CurrentPath:  r.GetCurrentPath(),
+			Queued:       r.GetQueued(),
+		})
+	}
+	return out
+}
+
+func watcherStatus(w *sweepv1.WatcherStatus) WatcherStatus {
+	return WatcherStatus{
+		Directories: w.GetDirectories(),
+		Events:      w.GetEvents(),
+		Errors:      w.GetErrors(),
+		LastEvent:   unixTime(w.GetLastEvent()),
+	}
+}
+
+func storeStatus(s *sweepv1.StoreStatus) StoreStatus {
+	return StoreStatus{
+		Backend:       s.GetBackend(),
+		SizeBytes:     s.GetSizeBytes(),
+		SchemaVersion: int(s.GetSchemaVersion()),
+		Migrating:     s.GetMigrating(),
+		LastCompacted: unixTime(s.GetLastCompacted()),
+	}
+}
+
+func daemonErrors(errs []*sweepv1.DaemonError) []DaemonError {
+	out := make([]DaemonError, 0, len(errs))
+	for _, e := range errs {
+		out = append(out, DaemonError{
+			Time:      unixTime(e.GetTime()),
+			Component: e.GetComponent(),
+			Path:      e.GetPath(),
+			Message:   e.GetMessage(),
+		})
+	}
+	return out
+}
+
+// unixTime converts Unix seconds from the daemon, where 0 means unset.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
 // Shutdown requests the daemon to shut down gracefully.
 func (c *Client) Shutdown(ctx context.Context) error {
 	resp, err := c.client.Shutdown(ctx, &sweepv1.ShutdownRequest{})
@@ -283,6 +646,461 @@ func (c *Client) ClearCache(ctx context.Context, path string) (int64, error) {
 	return resp.GetEntriesCleared(), nil
 }
 
+// AddWatch asks the daemon to index and watch a directory, and to do so
+// again each time it starts. It reports whether indexing started; it
+// doesn't when the directory is already watched.
+func (c *Client) AddWatch(ctx context.Context, path string) (bool, error) {
+	resp, err := c.client.AddWatch(ctx, &sweepv1.AddWatchRequest{Path: path})
+	if status.Code(err) == codes.Unimplemented {
+		return false, fmt.Errorf("AddWatch: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return false, fmt.Errorf("AddWatch RPC failed: %w", err)
+	}
+	return resp.GetStarted(), nil
+}
+
+// RemoveWatch asks the daemon to stop watching a directory and forget it.
+// With clear, its index is dropped too and the number of entries cleared
+// is returned.
+func (c *Client) RemoveWatch(ctx context.Context, path string, clear bool) (int64, error) {
+	resp, err := c.client.RemoveWatch(ctx, &sweepv1.RemoveWatchRequest{Path: path, Clear: clear})
+	if status.Code(err) == codes.Unimplemented {
+		return 0, fmt.Errorf("RemoveWatch: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("RemoveWatch RPC failed: %w", err)
+	}
+	return resp.GetEntriesCleared(), nil
+}
+
+// ListWatches returns the directories the daemon indexes and watches.
+func (c *Client) ListWatches(ctx context.Context) ([]WatchedRoot, error) {
+	resp, err := c.client.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("ListWatches: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ListWatches RPC failed: %w", err)
+	}
+	roots := make([]WatchedRoot, 0, len(resp.GetRoots()))
+	for _, r := range resp.GetRoots() {
+		roots = append(roots, WatchedRoot{
+			Path:         r.GetPath(),
+			State:        indexStateToString(r.GetState()),
+			FilesIndexed: r.GetFilesIndexed(),
+			Saved:        r.GetSaved(),
+			Paused:       r.GetPaused(),
+		})
+	}
+	return roots, nil
+}
+
+// PauseWatch asks the daemon to stop applying changes under a watched
+// directory until ResumeWatch. It reports whether the directory was
+// already paused.
+func (c *Client) PauseWatch(ctx context.Context, path string) (bool, error) {
+	resp, err := c.client.PauseWatch(ctx, &sweepv1.PauseWatchRequest{Path: path})
+	if status.Code(err) == codes.Unimplemented {
+		return false, fmt.Errorf("PauseWatch: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return false, fmt.Errorf("PauseWatch RPC failed: %w", err)
+	}
+	return resp.GetAlreadyPaused(), nil
+}
+
+// ResumeWatch asks the daemon to apply changes under a paused directory
+// again, catching up on the directories that changed while it was paused.
+func (c *Client) ResumeWatch(ctx context.Context, path string) (*ResumedWatch, error) {
+	resp, err := c.client.ResumeWatch(ctx, &sweepv1.ResumeWatchRequest{Path: path})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("ResumeWatch: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ResumeWatch RPC failed: %w", err)
+	}
+	return &ResumedWatch{DirsReconciled: resp.GetDirsReconciled(), Reindexing: resp.GetReindexing()}, nil
+}
+
+// WatchSuggestions lists the indexed directories the daemon has seen used
+// often that aren't saved watches, most used first. Save one with
+// AddWatch, or stop it being suggested with DismissWatchSuggestion.
+func (c *Client) WatchSuggestions(ctx context.Context) ([]WatchSuggestion, error) {
+	resp, err := c.client.GetWatchSuggestions(ctx, &sweepv1.GetWatchSuggestionsRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("GetWatchSuggestions: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetWatchSuggestions RPC failed: %w", err)
+	}
+	suggestions := make([]WatchSuggestion, 0, len(resp.GetSuggestions()))
+	for _, w := range resp.GetSuggestions() {
+		suggestions = append(suggestions, WatchSuggestion{
+			Path:     w.GetPath(),
+			Uses:     int(w.GetUses()),
+			LastUsed: time.Unix(w.GetLastUsed(), 0),
+		})
+	}
+	return suggestions, nil
+}
+
+// DismissWatchSuggestion asks the daemon to stop suggesting a directory
+// until it restarts.
+func (c *Client) DismissWatchSuggestion(ctx context.Context, path string) error {
+	_, err := c.client.DismissWatchSuggestion(ctx, &sweepv1.DismissWatchSuggestionRequest{Path: path})
+	if status.Code(err) == codes.Unimplemented {
+		return fmt.Errorf("DismissWatchSuggestion: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return fmt.Errorf("DismissWatchSuggestion RPC failed: %w", err)
+	}
+	return nil
+}
+
+// GetSizeDiff compares the disk usage under path now with the daemon's
+// snapshot from since ago, or its oldest if none is that old. maxDepth
+// limits the directories compared (0 for all) and limit the changes
+// returned of each kind (0 for all). It returns ErrUnsupported if the
+// daemon predates snapshots.
+func (c *Client) GetSizeDiff(ctx context.Context, path string, since time.Duration, maxDepth, limit int) (*sizediff.Report, error) {
+	resp, err := c.client.GetSizeDiff(ctx, &sweepv1.GetSizeDiffRequest{
+		Path:         path,
+		SinceSeconds: int64(since.Seconds()),
+		MaxDepth:     int32(maxDepth),
+		Limit:        int32(limit),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("GetSizeDiff: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetSizeDiff RPC failed: %w", err)
+	}
+	return &sizediff.Report{
+		Root:  path,
+		Since: time.Unix(resp.GetSnapshotTime(), 0),
+		Dirs:  sizeChangesFromProto(resp.GetDirs()),
+		Files: sizeChangesFromProto(resp.GetFiles()),
+	}, nil
+}
+
+// GetSizeHistogram counts the daemon's indexed files by size. It returns
+// ErrUnsupported if the daemon predates the request.
+func (c *Client) GetSizeHistogram(ctx context.Context) (*indexsize.Histogram, error) {
+	resp, err := c.client.GetSizeHistogram(ctx, &sweepv1.GetSizeHistogramRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("GetSizeHistogram: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetSizeHistogram RPC failed: %w", err)
+	}
+	h := &indexsize.Histogram{
+		MinIndexSize: resp.GetMinIndexSize(),
+		StoreSize:    resp.GetStoreSizeBytes(),
+		UnsizedFiles: resp.GetUnsizedFiles(),
+	}
+	for _, b := range resp.GetBuckets() {
+		h.Buckets = append(h.Buckets, indexsize.Bucket{
+			MinSize:    b.GetMinSize(),
+			Files:      b.GetFiles(),
+			Bytes:      b.GetBytes(),
+			IndexBytes: b.GetIndexBytes(),
+		})
+	}
+	return h, nil
+}
+
+// GetIndexHistory returns the totals the daemon recorded for the indexed
+// root covering path, or for every indexed root if path is empty, over the
+// last since (0 for all of them). It returns ErrUnsupported if the daemon
+// predates the request.
+func (c *Client) GetIndexHistory(ctx context.Context, path string, since time.Duration) ([]growth.Series, error) {
+	resp, err := c.client.GetIndexHistory(ctx, &sweepv1.GetIndexHistoryRequest{
+		Path:         path,
+		SinceSeconds: int64(since.Seconds()),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("GetIndexHistory: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetIndexHistory RPC failed: %w", err)
+	}
+	series := make([]growth.Series, 0, len(resp.GetRoots()))
+	for _, h := range resp.GetRoots() {
+		s := growth.Series{Root: h.GetRoot()}
+		for _, p := range h.GetPoints() {
+			s.Points = append(s.Points, growth.Point{Time: time.Unix(p.GetTime(), 0), Size: p.GetSize(), Files: p.GetFiles()})
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// SetMinIndexSize changes the daemon's large files index threshold until
+// it restarts. It returns ErrUnsupported if the daemon predates the
+// request.
+func (c *Client) SetMinIndexSize(ctx context.Context, size int64) (*IndexSizeChange, error) {
+	resp, err := c.client.SetMinIndexSize(ctx, &sweepv1.SetMinIndexSizeRequest{Size: size})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("SetMinIndexSize: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SetMinIndexSize RPC failed: %w", err)
+	}
+	return &IndexSizeChange{
+		Previous:   resp.GetPrevious(),
+		Added:      resp.GetAdded(),
+		Removed:    resp.GetRemoved(),
+		Reindexing: resp.GetReindexing(),
+	}, nil
+}
+
+// CompactStore drops stale records from the daemon's index store and
+// rewrites it to free their space. It returns ErrUnsupported if the daemon
+// predates the request.
+func (c *Client) CompactStore(ctx context.Context) (*Compaction, error) {
+	resp, err := c.client.CompactStore(ctx, &sweepv1.CompactStoreRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("CompactStore: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CompactStore RPC failed: %w", err)
+	}
+	return &Compaction{
+		Pruned:     resp.GetPruned(),
+		SizeBefore: resp.GetSizeBefore(),
+		SizeAfter:  resp.GetSizeAfter(),
+	}, nil
+}
+
+// ReloadConfig has the daemon read its configuration file again and apply
+// what changed. It returns ErrUnsupported if the daemon predates the
+// request.
+func (c *Client) ReloadConfig(ctx context.Context) (*ConfigReload, error) {
+	resp, err := c.client.ReloadConfig(ctx, &sweepv1.ReloadConfigRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("ReloadConfig: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ReloadConfig RPC failed: %w", err)
+	}
+	return &ConfigReload{
+		Changed:   resp.GetChanged(),
+		Indexing:  resp.GetIndexing(),
+		Unwatched: resp.GetUnwatched(),
+		Errors:    resp.GetErrors(),
+	}, nil
+}
+
+// GetLogs calls onEntry for each of the daemon's recent log entries the
+// query selects, oldest first, and when following, for each new one until
+// ctx is canceled. Entries have LogSource as their source. It returns
+// ErrUnsupported if the daemon predates the request.
+func (c *Client) GetLogs(ctx context.Context, query LogQuery, onEntry func(logging.LogEntry)) error {
+	req := &sweepv1.GetLogsRequest{
+		MinLevel:   query.MinLevel.String(),
+		Components: query.Components,
+		Limit:      int32(query.Limit),
+		Follow:     query.Follow,
+	}
+	if !query.Since.IsZero() {
+		req.Since = query.Since.UnixNano()
+	}
+	stream, err := c.client.GetLogs(ctx, req)
+	if err != nil {
+		return fmt.Errorf("GetLogs RPC failed: %w", err)
+	}
+
+	for {
+		e, err := stream.Recv()
+		if errors.Is(err, io.EOF) || (err != nil && ctx.Err() != nil) {
+			return nil
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("GetLogs: %w", ErrUnsupported)
+		}
+		if err != nil {
+			return fmt.Errorf("GetLogs RPC failed: %w", err)
+		}
+		level, _ := logging.ParseLevel(e.GetLevel()) // Unknown levels read as info
+		entry := logging.LogEntry{
+			Time:      time.Unix(0, e.GetTime()),
+			Level:     level,
+			Component: e.GetComponent(),
+			Message:   e.GetMessage(),
+			Source:    LogSource,
+		}
+		for _, f := range e.GetFields() {
+			entry.Fields = append(entry.Fields, logging.Field{Key: f.GetKey(), Value: f.GetValue()})
+		}
+		if onEntry != nil {
+			onEntry(entry)
+		}
+	}
+}
+
+// GetDiagnostics reports the daemon's process health, checking every record
+// in its index store too if checkStore is set, which takes a while on a
+// large index. It returns ErrUnsupported if the daemon predates the request.
+func (c *Client) GetDiagnostics(ctx context.Context, checkStore bool) (*Diagnostics, error) {
+	resp, err := c.client.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{CheckStore: checkStore})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("GetDiagnostics: %w", ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetDiagnostics RPC failed: %w", err)
+	}
+	d := &Diagnostics{
+		Version:       resp.GetVersion(),
+		PID:           int(resp.GetPid()),
+		Goroutines:    resp.GetGoroutines(),
+		OpenFDs:       resp.GetOpenFds(),
+		FDLimit:       resp.GetFdLimit(),
+		WatcherQueued: resp.GetWatcherQueued(),
+		WatcherHeld:   resp.GetWatcherHeld(),
+		WatcherDirs:   resp.GetWatcherDirs(),
+	}
+	if sc := resp.GetStoreCheck(); sc != nil {
+		d.StoreCheck = &StoreCheck{
+			Records:  sc.GetRecords(),
+			Problems: sc.GetProblems(),
+			Damaged:  sc.GetDamaged(),
+			Duration: time.Duration(sc.GetDurationMs()) * time.Millisecond,
+		}
+	}
+	return d, nil
+}
+
+func sizeChangesFromProto(changes []*sweepv1.SizeChange) []sizediff.Change {
+	out := make([]sizediff.Change, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, sizediff.NewChange(c.GetPath(), c.GetBefore(), c.GetAfter()))
+	}
+	return out
+}
+
+// DeleteFiles asks the daemon to move paths to the trash on its machine,
+// or to delete them permanently, which removes them from its index at once.
+// onResult is called as each path finishes. With opts.DryRun, nothing is
+// deleted but the results say what would be. It returns ErrUnsupported if
+// the daemon predates the request.
+func (c *Client) DeleteFiles(ctx context.Context, paths []string, opts DeleteOptions, onResult func(DeleteResult)) error {
+	stream, err := c.client.DeleteFiles(ctx, &sweepv1.DeleteFilesRequest{
+		Paths:     paths,
+		DryRun:    opts.DryRun,
+		Permanent: opts.Permanent,
+		Backend:   opts.Backend,
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteFiles RPC failed: %w", err)
+	}
+
+	for {
+		progress, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("DeleteFiles: %w", ErrUnsupported)
+		}
+		if err != nil {
+			return fmt.Errorf("DeleteFiles RPC failed: %w", err)
+		}
+		result := DeleteResult{
+			Path:     progress.GetPath(),
+			Size:     progress.GetSize(),
+			Current:  int(progress.GetCurrent()),
+			Total:    int(progress.GetTotal()),
+			Location: progress.GetLocation(),
+		}
+		if !progress.GetDeleted() {
+			result.Err = errors.New(progress.GetError())
+		}
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+}
+
+// ComputeHashes has the daemon hash files, calling onResult for each as it
+// completes. The daemon caches the hashes until a file changes. It returns
+// ErrUnsupported if the daemon predates the request.
+func (c *Client) ComputeHashes(ctx context.Context, paths []string, onResult func(FileHash)) error {
+	stream, err := c.client.ComputeHashes(ctx, &sweepv1.ComputeHashesRequest{Paths: paths})
+	if err != nil {
+		return fmt.Errorf("ComputeHashes RPC failed: %w", err)
+	}
+
+	for {
+		h, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("ComputeHashes: %w", ErrUnsupported)
+		}
+		if err != nil {
+			return fmt.Errorf("ComputeHashes RPC failed: %w", err)
+		}
+		result := FileHash{
+			Path:    h.GetPath(),
+			Size:    h.GetSize(),
+			SHA256:  h.GetSha256(),
+			XXHash:  h.GetXxhash(),
+			Cached:  h.GetCached(),
+			Current: int(h.GetCurrent()),
+			Total:   int(h.GetTotal()),
+		}
+		if h.GetError() != "" {
+			result.Err = errors.New(h.GetError())
+		}
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+}
+
+// ExportIndex calls fn for every entry in the index under root, or under
+// every indexed path when root is empty. An error from fn stops the export
+// and is returned. It returns ErrUnsupported if the daemon predates the
+// request.
+func (c *Client) ExportIndex(ctx context.Context, root string, fn func(IndexEntry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.client.ExportIndex(ctx, &sweepv1.ExportIndexRequest{Root: root})
+	if err != nil {
+		return fmt.Errorf("ExportIndex RPC failed: %w", err)
+	}
+
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return fmt.Errorf("ExportIndex: %w", ErrUnsupported)
+		}
+		if err != nil {
+			return fmt.Errorf("ExportIndex RPC failed: %w", err)
+		}
+		for _, e := range batch.GetEntries() {
+			err := fn(IndexEntry{
+				Path:     e.GetPath(),
+				Size:     e.GetSize(),
+				ModTime:  e.GetModTime(),
+				IsDir:    e.GetIsDir(),
+				Files:    e.GetFiles(),
+				Owner:    e.GetOwner(),
+				FileType: e.GetFileType(),
+				Shared:   e.GetShared(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // WatchLargeFiles subscribes to file events for large files under a path.
 // Returns a channel that receives events until the context is cancelled.
 func (c *Client) WatchLargeFiles(ctx context.Context, root string, minSize int64, exclude []string) (<-chan FileEvent, error) {
@@ -314,6 +1132,7 @@ func (c *Client) WatchLargeFiles(ctx context.Context, root string, minSize int64
 				Path:    event.GetPath(),
 				Size:    event.GetSize(),
 				ModTime: event.GetModTime(),
+				NewPath: event.GetNewPath(),
 			}:
 			case <-ctx.Done():
 				return
@@ -369,6 +1188,8 @@ func (c *Client) WatchTree(ctx context.Context, root string, minSize int64) (<-c
 				eventType = "modified"
 			case sweepv1.TreeEvent_DELETED:
 				eventType = "deleted"
+			case sweepv1.TreeEvent_RENAMED:
+				eventType = "renamed"
 			default:
 				eventType = "unknown"
 			}
@@ -380,6 +1201,7 @@ func (c *Client) WatchTree(ctx context.Context, root string, minSize int64) (<-c
 				Size:       event.GetSize(),
 				ModTime:    event.GetModTime(),
 				ParentPath: event.GetParentPath(),
+				NewPath:    event.GetNewPath(),
 			}:
 			case <-ctx.Done():
 				return
@@ -406,6 +1228,31 @@ func (c *Client) GetTree(ctx context.Context, root string, minSize int64, exclud
 	return protoToTreeNode(resp.GetRoot()), nil
 }
 
+// GetDirSizes returns the total size of each directory under root, down to
+// maxDepth levels (0 for unlimited), along with the index mode of root.
+func (c *Client) GetDirSizes(ctx context.Context, root string, maxDepth int) ([]du.Dir, string, error) {
+	resp, err := c.client.GetDirSizes(ctx, &sweepv1.GetDirSizesRequest{
+		Root:     root,
+		MaxDepth: int32(maxDepth),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("GetDirSizes RPC failed: %w", err)
+	}
+
+	dirs := make([]du.Dir, 0, len(resp.GetDirs()))
+	for _, d := range resp.GetDirs() {
+		dirs = append(dirs, du.Dir{
+			Path:    d.GetPath(),
+			Size:    d.GetSize(),
+			Files:   d.GetFiles(),
+			Dirs:    d.GetDirs(),
+			Entries: d.GetEntries(),
+			Depth:   int(d.GetDepth()),
+		})
+	}
+	return dirs, resp.GetIndexMode(), nil
+}
+
 // protoToTreeNode converts a proto TreeNode to a client TreeNode.
 func protoToTreeNode(p *sweepv1.TreeNode) *TreeNode {
 	if p == nil {
@@ -480,7 +1327,7 @@ func StartDaemon(paths DaemonPaths) error {
 		time.Sleep(100 * time.Millisecond)
 
 		// Check socket first (success fast path)
-		if _, err := os.Stat(paths.Socket); err == nil {
+		if ipc.Exists(paths.Socket) {
 			return nil
 		}
 
@@ -542,6 +1389,31 @@ func RestartDaemon(paths DaemonPaths) error {
 	return nil
 }
 
+// ReopenDaemonLogs asks the daemon to reopen its log file by sending it
+// SIGHUP, as a logrotate postrotate script would.
+func ReopenDaemonLogs(paths DaemonPaths) error {
+	paths = paths.withDefaults()
+
+	pid, err := readPIDFile(paths.PID)
+	if err != nil {
+		return fmt.Errorf("daemon not running: %w", err)
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("find daemon process: %w", err)
+	}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		return fmt.Errorf("signal daemon: %w", err)
+	}
+	return nil
+}
+
+// FindDaemonBinary returns the sweepd binary StartDaemon would run for
+// paths.
+func FindDaemonBinary(paths DaemonPaths) (string, error) {
+	return resolveBinary(paths.Binary)
+}
+
 // resolveBinary finds the sweepd binary path.
 // Priority: configured path > same directory as executable > GOBIN/GOPATH > PATH.
 func resolveBinary(configured string) (string, error) {
@@ -555,7 +1427,7 @@ func resolveBinary(configured string) (string, error) {
 
 	// Try same directory as current executable
 	if execPath, err := os.Executable(); err == nil {
-		candidate := filepath.Join(filepath.Dir(execPath), "sweepd")
+		candidate := filepath.Join(filepath.Dir(execPath), config.DaemonBinaryName())
 		if _, err := os.Stat(candidate); err == nil {
 			return candidate, nil
 		}
@@ -581,15 +1453,7 @@ func IsDaemonRunning(pidPath string) bool {
 		return false
 	}
 
-	// Check if process exists
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Send signal 0 to check if process exists
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
+	return ipc.ProcessRunning(pid)
 }
 
 // readPIDFile reads a PID from a file.
@@ -617,13 +1481,37 @@ func protoToFileInfo(p *sweepv1.FileInfo) types.FileInfo {
 		Mode:       os.FileMode(p.GetMode()),
 		Owner:      p.GetOwner(),
 		Group:      p.GetGroup(),
+		Sharing:    protoToSharing(p.GetSharing()),
+		Cloud:      protoToCloud(p.GetCloud()),
+	}
+}
+
+// protoToCloud converts a protobuf Cloud to cloud.Info.
+func protoToCloud(p *sweepv1.Cloud) *cloud.Info {
+	if p == nil {
+		return nil
+	}
+	return &cloud.Info{Provider: p.GetProvider(), OnDisk: p.GetOnDiskSize()}
+}
+
+// protoToSharing converts a protobuf Sharing to sharing.Info.
+func protoToSharing(p *sweepv1.Sharing) *sharing.Info {
+	if p == nil {
+		return nil
+	}
+	return &sharing.Info{
+		Dev:     p.GetDev(),
+		Ino:     p.GetIno(),
+		Links:   p.GetLinks(),
+		CloneID: p.GetCloneId(),
+		Private: p.GetPrivateSize(),
 	}
 }
 
 // fileInfoToProto converts types.FileInfo to a protobuf FileInfo.
 // This is the inverse of protoToFileInfo and is used for testing round-trip conversion.
 func fileInfoToProto(f *types.FileInfo) *sweepv1.FileInfo {
-	return &sweepv1.FileInfo{
+	p := &sweepv1.FileInfo{
 		Path:       f.Path,
 		Size:       f.Size,
 		ModTime:    f.ModTime.Unix(),
@@ -632,6 +1520,19 @@ func fileInfoToProto(f *types.FileInfo) *sweepv1.FileInfo {
 		Owner:      f.Owner,
 		Group:      f.Group,
 	}
+	if f.Sharing != nil {
+		p.Sharing = &sweepv1.Sharing{
+			Dev:         f.Sharing.Dev,
+			Ino:         f.Sharing.Ino,
+			Links:       f.Sharing.Links,
+			CloneId:     f.Sharing.CloneID,
+			PrivateSize: f.Sharing.Private,
+		}
+	}
+	if f.Cloud != nil {
+		p.Cloud = &sweepv1.Cloud{Provider: f.Cloud.Provider, OnDiskSize: f.Cloud.OnDisk}
+	}
+	return p
 }
 
 // indexStateToString converts an IndexState enum to a string.
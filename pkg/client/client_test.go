@@ -3,17 +3,24 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -21,15 +28,132 @@ import (
 type mockSweepDaemonServer struct {
 	sweepv1.UnimplementedSweepDaemonServer
 	largeFiles    []*sweepv1.FileInfo
+	largeReq      *sweepv1.GetLargeFilesRequest
 	indexStatus   *sweepv1.IndexStatus
 	daemonStatus  *sweepv1.DaemonStatus
 	triggerResp   *sweepv1.TriggerIndexResponse
 	shutdownResp  *sweepv1.ShutdownResponse
 	clearResp     *sweepv1.ClearCacheResponse
 	shutdownCalls int
+	deleted       []*sweepv1.DeleteProgress // nil acts like a daemon without DeleteFiles
+	deleteReq     *sweepv1.DeleteFilesRequest
+	exported      []*sweepv1.ExportIndexResponse
+	watches       []*sweepv1.WatchedRoot
+	suggestions   []*sweepv1.WatchSuggestion
+	sizeDiff      *sweepv1.GetSizeDiffResponse
+	histogram     *sweepv1.GetSizeHistogramResponse
+	minIndexSize  int64
+	compaction    *sweepv1.CompactStoreResponse // nil acts like a daemon without CompactStore
+	diagnostics   *sweepv1.Diagnostics          // nil acts like a daemon without GetDiagnostics
+	diagnosticReq *sweepv1.GetDiagnosticsRequest
+	statusDelay   time.Duration // How long GetDaemonStatus takes
+	statusCtx     context.Context
+	hashes        []*sweepv1.FileHash // nil acts like a daemon without ComputeHashes
+	history       *sweepv1.GetIndexHistoryResponse
+	historyReq    *sweepv1.GetIndexHistoryRequest
+	reload        *sweepv1.ReloadConfigResponse // nil acts like a daemon without ReloadConfig
+	logs          []*sweepv1.LogEntry           // nil acts like a daemon without GetLogs
+	logsReq       *sweepv1.GetLogsRequest
 }
 
-func (m *mockSweepDaemonServer) GetLargeFiles(_ *sweepv1.GetLargeFilesRequest, stream grpc.ServerStreamingServer[sweepv1.FileInfo]) error {
+func (m *mockSweepDaemonServer) GetLogs(req *sweepv1.GetLogsRequest, stream grpc.ServerStreamingServer[sweepv1.LogEntry]) error {
+	if m.logs == nil {
+		return m.UnimplementedSweepDaemonServer.GetLogs(req, stream)
+	}
+	m.logsReq = req
+	for _, e := range m.logs {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSweepDaemonServer) ReloadConfig(ctx context.Context, req *sweepv1.ReloadConfigRequest) (*sweepv1.ReloadConfigResponse, error) {
+	if m.reload == nil {
+		return m.UnimplementedSweepDaemonServer.ReloadConfig(ctx, req)
+	}
+	return m.reload, nil
+}
+
+func (m *mockSweepDaemonServer) GetIndexHistory(_ context.Context, req *sweepv1.GetIndexHistoryRequest) (*sweepv1.GetIndexHistoryResponse, error) {
+	m.historyReq = req
+	return m.history, nil
+}
+
+func (m *mockSweepDaemonServer) GetSizeHistogram(_ context.Context, _ *sweepv1.GetSizeHistogramRequest) (*sweepv1.GetSizeHistogramResponse, error) {
+	return m.histogram, nil
+}
+
+func (m *mockSweepDaemonServer) SetMinIndexSize(_ context.Context, req *sweepv1.SetMinIndexSizeRequest) (*sweepv1.SetMinIndexSizeResponse, error) {
+	previous := m.minIndexSize
+	m.minIndexSize = req.GetSize()
+	return &sweepv1.SetMinIndexSizeResponse{Previous: previous, Removed: 3}, nil
+}
+
+func (m *mockSweepDaemonServer) CompactStore(ctx context.Context, req *sweepv1.CompactStoreRequest) (*sweepv1.CompactStoreResponse, error) {
+	if m.compaction == nil {
+		return m.UnimplementedSweepDaemonServer.CompactStore(ctx, req)
+	}
+	return m.compaction, nil
+}
+
+func (m *mockSweepDaemonServer) GetDiagnostics(ctx context.Context, req *sweepv1.GetDiagnosticsRequest) (*sweepv1.Diagnostics, error) {
+	if m.diagnostics == nil {
+		return m.UnimplementedSweepDaemonServer.GetDiagnostics(ctx, req)
+	}
+	m.diagnosticReq = req
+	return m.diagnostics, nil
+}
+
+func (m *mockSweepDaemonServer) GetSizeDiff(_ context.Context, _ *sweepv1.GetSizeDiffRequest) (*sweepv1.GetSizeDiffResponse, error) {
+	return m.sizeDiff, nil
+}
+
+func (m *mockSweepDaemonServer) ListWatches(_ context.Context, _ *sweepv1.ListWatchesRequest) (*sweepv1.ListWatchesResponse, error) {
+	return &sweepv1.ListWatchesResponse{Roots: m.watches}, nil
+}
+
+func (m *mockSweepDaemonServer) GetWatchSuggestions(_ context.Context, _ *sweepv1.GetWatchSuggestionsRequest) (*sweepv1.GetWatchSuggestionsResponse, error) {
+	return &sweepv1.GetWatchSuggestionsResponse{Suggestions: m.suggestions}, nil
+}
+
+func (m *mockSweepDaemonServer) ExportIndex(_ *sweepv1.ExportIndexRequest, stream grpc.ServerStreamingServer[sweepv1.ExportIndexResponse]) error {
+	for _, b := range m.exported {
+		if err := stream.Send(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSweepDaemonServer) DeleteFiles(req *sweepv1.DeleteFilesRequest, stream grpc.ServerStreamingServer[sweepv1.DeleteProgress]) error {
+	m.deleteReq = req
+	if m.deleted == nil {
+		return m.UnimplementedSweepDaemonServer.DeleteFiles(nil, stream)
+	}
+	for _, p := range m.deleted {
+		if err := stream.Send(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSweepDaemonServer) ComputeHashes(req *sweepv1.ComputeHashesRequest, stream grpc.ServerStreamingServer[sweepv1.FileHash]) error {
+	if m.hashes == nil {
+		return m.UnimplementedSweepDaemonServer.ComputeHashes(req, stream)
+	}
+	for _, h := range m.hashes {
+		if err := stream.Send(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockSweepDaemonServer) GetLargeFiles(req *sweepv1.GetLargeFilesRequest, stream grpc.ServerStreamingServer[sweepv1.FileInfo]) error {
+	m.largeReq = req
 	for _, f := range m.largeFiles {
 		if err := stream.Send(f); err != nil {
 			return err
@@ -57,7 +181,13 @@ func (m *mockSweepDaemonServer) TriggerIndex(_ context.Context, _ *sweepv1.Trigg
 	}, nil
 }
 
-func (m *mockSweepDaemonServer) GetDaemonStatus(_ context.Context, _ *sweepv1.GetDaemonStatusRequest) (*sweepv1.DaemonStatus, error) {
+func (m *mockSweepDaemonServer) GetDaemonStatus(ctx context.Context, _ *sweepv1.GetDaemonStatusRequest) (*sweepv1.DaemonStatus, error) {
+	m.statusCtx = ctx
+	select {
+	case <-time.After(m.statusDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 	if m.daemonStatus != nil {
 		return m.daemonStatus, nil
 	}
@@ -215,6 +345,342 @@ func TestGetLargeFiles(t *testing.T) {
 	}
 }
 
+func TestQueryLargeFilesTruncated(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		largeFiles: []*sweepv1.FileInfo{
+			{Path: "/tmp/file1.bin", Size: 1024 * 1024 * 100},
+			{Path: "/tmp/file2.bin", Size: 1024 * 1024 * 50, Truncated: true},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.QueryLargeFiles(context.Background(), "/tmp", 1024*1024, nil, 0)
+	if err != nil {
+		t.Fatalf("QueryLargeFiles() failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("QueryLargeFiles() returned %d files, expected 2", len(result.Files))
+	}
+	if !result.Truncated {
+		t.Error("QueryLargeFiles() Truncated = false, expected true")
+	}
+}
+
+func TestQueryLargeFilesPage(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		largeFiles: []*sweepv1.FileInfo{
+			{Path: "/tmp/file1.bin", Size: 1024 * 1024 * 100},
+			{Path: "/tmp/file2.bin", Size: 1024 * 1024 * 50, Truncated: true, NextPageToken: "next"},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.QueryLargeFilesPage(context.Background(), "/tmp", 1024*1024, nil, 2, "prev")
+	if err != nil {
+		t.Fatalf("QueryLargeFilesPage() failed: %v", err)
+	}
+	if result.NextPage != "next" {
+		t.Errorf("QueryLargeFilesPage() NextPage = %q, expected next", result.NextPage)
+	}
+	req := mock.largeReq
+	if req.GetPageToken() != "prev" || req.GetLimit() != 2 || !req.GetSortDescending() {
+		t.Errorf("QueryLargeFilesPage() sent %v, expected page token prev, limit 2, largest first", req)
+	}
+}
+
+func TestDeleteFiles(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		deleted: []*sweepv1.DeleteProgress{
+			{Path: "/tmp/a.bin", Deleted: true, Size: 100, Current: 1, Total: 2},
+			{Path: "/tmp/b.bin", Error: "permission denied", Current: 2, Total: 2},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	var results []DeleteResult
+	err = client.DeleteFiles(context.Background(), []string{"/tmp/a.bin", "/tmp/b.bin"}, DeleteOptions{Permanent: true}, func(r DeleteResult) {
+		results = append(results, r)
+	})
+	if err != nil {
+		t.Fatalf("DeleteFiles() failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("DeleteFiles() reported %d results, expected 2", len(results))
+	}
+	if results[0].Err != nil || results[0].Size != 100 {
+		t.Errorf("first result = %+v, expected a 100 byte deletion", results[0])
+	}
+	if results[1].Err == nil || results[1].Err.Error() != "permission denied" {
+		t.Errorf("second result error = %v, expected permission denied", results[1].Err)
+	}
+	if results[1].Current != 2 || results[1].Total != 2 {
+		t.Errorf("second result progress = %d/%d, expected 2/2", results[1].Current, results[1].Total)
+	}
+	if !mock.deleteReq.GetPermanent() || mock.deleteReq.GetDryRun() {
+		t.Errorf("DeleteFiles() request = %v, expected permanent", mock.deleteReq)
+	}
+}
+
+func TestDeleteFilesUnsupported(t *testing.T) {
+	socketPath, cleanup := setupTestServer(t, &mockSweepDaemonServer{})
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.DeleteFiles(context.Background(), []string{"/tmp/a.bin"}, DeleteOptions{}, nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DeleteFiles() error = %v, expected ErrUnsupported", err)
+	}
+}
+
+func TestComputeHashes(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		hashes: []*sweepv1.FileHash{
+			{Path: "/tmp/a.bin", Size: 100, Sha256: []byte{1, 2}, Xxhash: 42, Cached: true, Current: 1, Total: 2},
+			{Path: "/tmp/b.bin", Error: "permission denied", Current: 2, Total: 2},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	var results []FileHash
+	err = client.ComputeHashes(context.Background(), []string{"/tmp/a.bin", "/tmp/b.bin"}, func(h FileHash) {
+		results = append(results, h)
+	})
+	if err != nil {
+		t.Fatalf("ComputeHashes() failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("ComputeHashes() reported %d results, expected 2", len(results))
+	}
+	if a := results[0]; a.Err != nil || a.XXHash != 42 || len(a.SHA256) != 2 || !a.Cached || a.Size != 100 {
+		t.Errorf("first result = %+v, expected cached hashes", a)
+	}
+	if results[1].Err == nil || results[1].Err.Error() != "permission denied" {
+		t.Errorf("second result error = %v, expected permission denied", results[1].Err)
+	}
+}
+
+func TestComputeHashesUnsupported(t *testing.T) {
+	socketPath, cleanup := setupTestServer(t, &mockSweepDaemonServer{})
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.ComputeHashes(context.Background(), []string{"/tmp/a.bin"}, nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ComputeHashes() error = %v, expected ErrUnsupported", err)
+	}
+}
+
+func TestGetLogs(t *testing.T) {
+	logged := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock := &mockSweepDaemonServer{
+		logs: []*sweepv1.LogEntry{
+			{Time: logged.UnixNano(), Level: "warn", Component: "watcher", Message: "overflow",
+				Fields: []*sweepv1.LogField{{Key: "root", Value: "/data"}}},
+			{Time: logged.UnixNano(), Level: "error", Component: "indexer", Message: "failed"},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	query := LogQuery{
+		EntryFilter: logging.EntryFilter{MinLevel: logging.LevelWarn, Components: []string{"watcher", "indexer"}, Since: logged.Add(-time.Hour)},
+		Limit:       50,
+	}
+	var entries []logging.LogEntry
+	if err := client.GetLogs(context.Background(), query, func(e logging.LogEntry) { entries = append(entries, e) }); err != nil {
+		t.Fatalf("GetLogs() failed: %v", err)
+	}
+	if req := mock.logsReq; req.GetMinLevel() != "warn" || len(req.GetComponents()) != 2 ||
+		req.GetSince() != logged.Add(-time.Hour).UnixNano() || req.GetLimit() != 50 || req.GetFollow() {
+		t.Errorf("GetLogs() sent %v, expected the query", req)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("GetLogs() reported %d entries, expected 2", len(entries))
+	}
+	first := entries[0]
+	if first.Level != logging.LevelWarn || first.Component != "watcher" || first.Message != "overflow" ||
+		!first.Time.Equal(logged) || first.Source != LogSource {
+		t.Errorf("first entry = %+v, expected the daemon's overflow warning", first)
+	}
+	if len(first.Fields) != 1 || first.Fields[0] != (logging.Field{Key: "root", Value: "/data"}) {
+		t.Errorf("first entry fields = %v, expected root=/data", first.Fields)
+	}
+	if entries[1].Level != logging.LevelError {
+		t.Errorf("second entry level = %v, expected error", entries[1].Level)
+	}
+}
+
+func TestGetLogsUnsupported(t *testing.T) {
+	socketPath, cleanup := setupTestServer(t, &mockSweepDaemonServer{})
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.GetLogs(context.Background(), LogQuery{}, nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetLogs() error = %v, expected ErrUnsupported", err)
+	}
+}
+
+func TestExportIndex(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		exported: []*sweepv1.ExportIndexResponse{
+			{Entries: []*sweepv1.IndexEntry{
+				{Path: "/data", IsDir: true, Size: 300, Files: 2},
+				{Path: "/data/a.mp4", Size: 200, ModTime: 1700000000, Owner: "alice", FileType: "Video"},
+			}},
+			{Entries: []*sweepv1.IndexEntry{
+				{Path: "/data/b.bin", Size: 100, Shared: true},
+			}},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	var entries []IndexEntry
+	err = client.ExportIndex(context.Background(), "", func(e IndexEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportIndex() failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("ExportIndex() returned %d entries, expected 3", len(entries))
+	}
+	if !entries[0].IsDir || entries[0].Files != 2 {
+		t.Errorf("first entry = %+v, expected the directory", entries[0])
+	}
+	if e := entries[1]; e.Owner != "alice" || e.FileType != "Video" || e.ModTime != 1700000000 {
+		t.Errorf("second entry = %+v", e)
+	}
+	if !entries[2].Shared {
+		t.Error("third entry should be shared")
+	}
+
+	stop := errors.New("stop")
+	err = client.ExportIndex(context.Background(), "", func(IndexEntry) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("ExportIndex() error = %v, expected the callback's error", err)
+	}
+}
+
+func TestListWatches(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		watches: []*sweepv1.WatchedRoot{
+			{Path: "/data", State: sweepv1.IndexState_INDEX_STATE_READY, FilesIndexed: 42, Saved: true},
+			{Path: "/scratch", State: sweepv1.IndexState_INDEX_STATE_INDEXING, Paused: true},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	roots, err := client.ListWatches(context.Background())
+	if err != nil {
+		t.Fatalf("ListWatches() failed: %v", err)
+	}
+	want := []WatchedRoot{
+		{Path: "/data", State: "ready", FilesIndexed: 42, Saved: true},
+		{Path: "/scratch", State: "indexing", Paused: true},
+	}
+	if !reflect.DeepEqual(roots, want) {
+		t.Errorf("ListWatches() = %+v, expected %+v", roots, want)
+	}
+
+	// The mock doesn't implement AddWatch, like an older daemon
+	if _, err := client.AddWatch(context.Background(), "/data"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("AddWatch() error = %v, expected ErrUnsupported", err)
+	}
+}
+
+func TestWatchSuggestions(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		suggestions: []*sweepv1.WatchSuggestion{{Path: "/src", Uses: 4, LastUsed: 1700000000}},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	suggestions, err := client.WatchSuggestions(context.Background())
+	if err != nil {
+		t.Fatalf("WatchSuggestions() failed: %v", err)
+	}
+	want := []WatchSuggestion{{Path: "/src", Uses: 4, LastUsed: time.Unix(1700000000, 0)}}
+	if !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("WatchSuggestions() = %+v, expected %+v", suggestions, want)
+	}
+
+	// The mock doesn't implement DismissWatchSuggestion, like an older daemon
+	if err := client.DismissWatchSuggestion(context.Background(), "/src"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DismissWatchSuggestion() error = %v, expected ErrUnsupported", err)
+	}
+}
+
 func TestGetLargeFilesEmpty(t *testing.T) {
 	mock := &mockSweepDaemonServer{
 		largeFiles: []*sweepv1.FileInfo{},
@@ -387,6 +853,18 @@ func TestGetDaemonStatus(t *testing.T) {
 			WatchedPaths:      []string{"/home", "/var"},
 			CacheSizeBytes:    1024 * 1024 * 50,
 			TotalFilesIndexed: 5000,
+			Version:           "1.2.3",
+			Roots: []*sweepv1.RootStatus{{
+				Path:         "/home",
+				State:        sweepv1.IndexState_INDEX_STATE_READY,
+				FilesIndexed: 4000,
+				TotalSize:    1 << 30,
+				LastIndexed:  1700000000,
+				Saved:        true,
+			}},
+			Watcher:      &sweepv1.WatcherStatus{Directories: 120, Events: 42},
+			Store:        &sweepv1.StoreStatus{Backend: "badger", SchemaVersion: 3},
+			RecentErrors: []*sweepv1.DaemonError{{Time: 1700000100, Component: "watcher", Message: "queue overflow"}},
 		},
 	}
 	socketPath, cleanup := setupTestServer(t, mock)
@@ -413,6 +891,74 @@ func TestGetDaemonStatus(t *testing.T) {
 	if len(status.WatchedPaths) != 2 {
 		t.Errorf("GetDaemonStatus().WatchedPaths length = %d, expected 2", len(status.WatchedPaths))
 	}
+	if status.Version != "1.2.3" {
+		t.Errorf("GetDaemonStatus().Version = %q, expected 1.2.3", status.Version)
+	}
+	wantRoot := RootStatus{Path: "/home", State: "ready", FilesIndexed: 4000, TotalSize: 1 << 30, LastIndexed: time.Unix(1700000000, 0), Saved: true}
+	if len(status.Roots) != 1 || status.Roots[0] != wantRoot {
+		t.Errorf("GetDaemonStatus().Roots = %+v, expected [%+v]", status.Roots, wantRoot)
+	}
+	if !status.Roots[0].LastQueried.IsZero() {
+		t.Error("an unset time should be zero")
+	}
+	if status.Watcher.Directories != 120 || status.Store.SchemaVersion != 3 {
+		t.Errorf("unexpected watcher %+v or store %+v", status.Watcher, status.Store)
+	}
+	if len(status.RecentErrors) != 1 || status.RecentErrors[0].Message != "queue overflow" {
+		t.Errorf("GetDaemonStatus().RecentErrors = %+v", status.RecentErrors)
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	mock := &mockSweepDaemonServer{}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	// Requests get the default deadline
+	if _, err := client.GetDaemonStatus(context.Background()); err != nil {
+		t.Fatalf("GetDaemonStatus() failed: %v", err)
+	}
+	deadline, ok := mock.statusCtx.Deadline()
+	if !ok || time.Until(deadline) > DefaultTimeout {
+		t.Errorf("deadline = %v, %v; want one within %v", deadline, ok, DefaultTimeout)
+	}
+
+	// A caller's deadline is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if _, err := client.GetDaemonStatus(ctx); err != nil {
+		t.Fatalf("GetDaemonStatus() failed: %v", err)
+	}
+	if deadline, _ := mock.statusCtx.Deadline(); time.Until(deadline) < DefaultTimeout {
+		t.Errorf("deadline = %v, want the caller's hour", deadline)
+	}
+
+	// A negative timeout sets none
+	client.SetTimeout(-1)
+	if _, err := client.GetDaemonStatus(context.Background()); err != nil {
+		t.Fatalf("GetDaemonStatus() failed: %v", err)
+	}
+	if deadline, ok := mock.statusCtx.Deadline(); ok {
+		t.Errorf("deadline = %v, want none", deadline)
+	}
+
+	// A slow request fails when its deadline passes
+	client.SetTimeout(50 * time.Millisecond)
+	mock.statusDelay = 5 * time.Second
+	start := time.Now()
+	_, err = client.GetDaemonStatus(context.Background())
+	if status.Code(errors.Unwrap(err)) != codes.DeadlineExceeded {
+		t.Errorf("GetDaemonStatus() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetDaemonStatus() took %v, want it cut off at the deadline", elapsed)
+	}
 }
 
 func TestShutdown(t *testing.T) {
@@ -562,6 +1108,8 @@ func TestTypesConversionRoundTrip(t *testing.T) {
 		Mode:       0755,
 		Owner:      "testuser",
 		Group:      "testgroup",
+		Sharing:    &sharing.Info{Dev: 1, Ino: 2, Links: 3, CloneID: 4, Private: 5},
+		Cloud:      &cloud.Info{Provider: cloud.ProviderOneDrive, OnDisk: 4096},
 	}
 
 	// Convert to proto
@@ -585,7 +1133,202 @@ func TestTypesConversionRoundTrip(t *testing.T) {
 	if converted.Group != original.Group {
 		t.Errorf("Group mismatch: got %q, want %q", converted.Group, original.Group)
 	}
+	if *converted.Sharing != *original.Sharing {
+		t.Errorf("Sharing mismatch: got %+v, want %+v", converted.Sharing, original.Sharing)
+	}
+	if *converted.Cloud != *original.Cloud {
+		t.Errorf("Cloud mismatch: got %+v, want %+v", converted.Cloud, original.Cloud)
+	}
 }
 
 // Compile-time interface check.
 var _ io.Closer = (*Client)(nil)
+
+func TestGetSizeDiff(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		sizeDiff: &sweepv1.GetSizeDiffResponse{
+			SnapshotTime: 1700000000,
+			Dirs:         []*sweepv1.SizeChange{{Path: "/data/videos", Before: 100, After: 400}},
+			Files:        []*sweepv1.SizeChange{{Path: "/data/old.iso", Before: 300}},
+		},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	report, err := client.GetSizeDiff(context.Background(), "/data", 7*24*time.Hour, 0, 10)
+	if err != nil {
+		t.Fatalf("GetSizeDiff() failed: %v", err)
+	}
+	if report.Root != "/data" || !report.Since.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("GetSizeDiff() root, since = %s, %s", report.Root, report.Since)
+	}
+	if len(report.Dirs) != 1 || report.Dirs[0].Delta != 300 {
+		t.Errorf("GetSizeDiff() dirs = %+v, expected /data/videos +300", report.Dirs)
+	}
+	if len(report.Files) != 1 || report.Files[0].Delta != -300 {
+		t.Errorf("GetSizeDiff() files = %+v, expected /data/old.iso -300", report.Files)
+	}
+}
+
+func TestGetIndexHistory(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		history: &sweepv1.GetIndexHistoryResponse{Roots: []*sweepv1.IndexHistory{
+			{Root: "/data", Points: []*sweepv1.IndexHistoryPoint{{Time: 1700000000, Size: 100, Files: 2}, {Time: 1700086400, Size: 400, Files: 3}}},
+			{Root: "/empty"},
+		}},
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	series, err := client.GetIndexHistory(context.Background(), "/data/videos", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetIndexHistory() failed: %v", err)
+	}
+	if mock.historyReq.GetPath() != "/data/videos" || mock.historyReq.GetSinceSeconds() != 30*24*3600 {
+		t.Errorf("GetIndexHistory() request = %v", mock.historyReq)
+	}
+	if len(series) != 2 || series[0].Root != "/data" || len(series[1].Points) != 0 {
+		t.Fatalf("GetIndexHistory() = %+v, expected /data and /empty", series)
+	}
+	if last := series[0].Last(); !last.Time.Equal(time.Unix(1700086400, 0)) || last.Size != 400 || last.Files != 3 {
+		t.Errorf("GetIndexHistory() last point = %+v", last)
+	}
+}
+
+func TestSizeHistogramAndSetMinIndexSize(t *testing.T) {
+	mock := &mockSweepDaemonServer{
+		histogram: &sweepv1.GetSizeHistogramResponse{
+			Buckets:        []*sweepv1.SizeBucket{{MinSize: 0, Files: 10, Bytes: 100}, {MinSize: 1024, Files: 2, Bytes: 4096, IndexBytes: 80}},
+			MinIndexSize:   1024,
+			StoreSizeBytes: 5000,
+		},
+		minIndexSize: 1024,
+	}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	h, err := client.GetSizeHistogram(context.Background())
+	if err != nil {
+		t.Fatalf("GetSizeHistogram() failed: %v", err)
+	}
+	if h.MinIndexSize != 1024 || h.StoreSize != 5000 || len(h.Buckets) != 2 || h.Buckets[1].IndexBytes != 80 {
+		t.Errorf("GetSizeHistogram() = %+v", h)
+	}
+
+	change, err := client.SetMinIndexSize(context.Background(), 4096)
+	if err != nil {
+		t.Fatalf("SetMinIndexSize() failed: %v", err)
+	}
+	if change.Previous != 1024 || change.Removed != 3 || mock.minIndexSize != 4096 {
+		t.Errorf("SetMinIndexSize() = %+v, daemon threshold %d", change, mock.minIndexSize)
+	}
+}
+
+func TestCompactStore(t *testing.T) {
+	mock := &mockSweepDaemonServer{}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CompactStore(context.Background()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CompactStore() on an old daemon = %v, want ErrUnsupported", err)
+	}
+
+	mock.compaction = &sweepv1.CompactStoreResponse{Pruned: 12, SizeBefore: 5000, SizeAfter: 3000}
+	res, err := client.CompactStore(context.Background())
+	if err != nil {
+		t.Fatalf("CompactStore() failed: %v", err)
+	}
+	if res.Pruned != 12 || res.Reclaimed() != 2000 {
+		t.Errorf("CompactStore() = %+v", res)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	mock := &mockSweepDaemonServer{}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.ReloadConfig(context.Background()); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ReloadConfig() on an old daemon = %v, want ErrUnsupported", err)
+	}
+
+	mock.reload = &sweepv1.ReloadConfigResponse{
+		Changed:  []string{"logging", "index_paths"},
+		Indexing: []string{"/data"},
+		Errors:   []string{"min_index_size: /srv is being indexed"},
+	}
+	res, err := client.ReloadConfig(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadConfig() failed: %v", err)
+	}
+	if len(res.Changed) != 2 || len(res.Indexing) != 1 || len(res.Unwatched) != 0 || len(res.Errors) != 1 {
+		t.Errorf("ReloadConfig() = %+v", res)
+	}
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	mock := &mockSweepDaemonServer{}
+	socketPath, cleanup := setupTestServer(t, mock)
+	defer cleanup()
+
+	client, err := Connect(socketPath)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetDiagnostics(context.Background(), false); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetDiagnostics() on an old daemon = %v, want ErrUnsupported", err)
+	}
+
+	mock.diagnostics = &sweepv1.Diagnostics{
+		Version:    "1.2.3",
+		Pid:        42,
+		OpenFds:    30,
+		FdLimit:    1024,
+		StoreCheck: &sweepv1.StoreCheck{Records: 100, Problems: []string{"bad"}, Damaged: 3, DurationMs: 1500},
+	}
+	d, err := client.GetDiagnostics(context.Background(), true)
+	if err != nil {
+		t.Fatalf("GetDiagnostics() failed: %v", err)
+	}
+	if !mock.diagnosticReq.GetCheckStore() {
+		t.Error("GetDiagnostics(true) didn't ask for a store check")
+	}
+	if d.Version != "1.2.3" || d.PID != 42 || d.OpenFDs != 30 || d.FDLimit != 1024 {
+		t.Errorf("GetDiagnostics() = %+v", d)
+	}
+	if d.StoreCheck == nil || d.StoreCheck.Damaged != 3 || d.StoreCheck.Duration != 1500*time.Millisecond {
+		t.Errorf("GetDiagnostics().StoreCheck = %+v", d.StoreCheck)
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// ErrRemoteCertRequired is returned when connecting to a remote daemon
+// without a client certificate, which the daemon always requires.
+var ErrRemoteCertRequired = errors.New("remote daemon requires a client certificate (remote.cert and remote.key)")
+
+// Target says how to reach the daemon: the local Unix socket, or a daemon
+// on another machine over TCP with mutual TLS.
+type Target struct {
+	Socket string              // Local socket; empty uses DefaultSocketPath
+	PID    string              // Local PID file; empty uses DefaultPIDPath
+	Remote config.RemoteConfig // Used instead of the socket when Remote.Address is set
+	// Timeout is the deadline for unary RPCs whose context has none; 0 uses
+	// DefaultTimeout and a negative value sets none.
+	Timeout time.Duration
+}
+
+// IsRemote reports whether the target is a daemon on another machine.
+func (t Target) IsRemote() bool {
+	return t.Remote.Address != ""
+}
+
+// Running reports whether the daemon may be reachable. A local daemon is
+// checked through its PID file; a remote one can only be found by
+// connecting, so it is assumed to be running.
+func (t Target) Running() bool {
+	if t.IsRemote() {
+		return true
+	}
+	pid := t.PID
+	if pid == "" {
+		pid = DefaultPIDPath()
+	}
+	return IsDaemonRunning(pid)
+}
+
+// Connect connects to the target daemon.
+func (t Target) Connect(ctx context.Context) (*Client, error) {
+	var c *Client
+	var err error
+	if t.IsRemote() {
+		c, err = ConnectRemote(ctx, t.Remote)
+	} else {
+		socket := t.Socket
+		if socket == "" {
+			socket = DefaultSocketPath()
+		}
+		c, err = ConnectWithContext(ctx, socket)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.Timeout != 0 {
+		c.SetTimeout(t.Timeout)
+	}
+	return c, nil
+}
+
+// ConnectRemote connects to a daemon listening on TCP. The daemon's
+// certificate is verified against remote.CA (or the system roots) and the
+// client presents remote.Cert so the daemon can verify it in turn.
+func ConnectRemote(ctx context.Context, remote config.RemoteConfig) (*Client, error) {
+	tlsCfg, err := RemoteTLSConfig(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newClient(func(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+		//nolint:staticcheck // grpc.DialContext is deprecated but NewClient doesn't support blocking
+		return grpc.DialContext(ctx, remote.Address, append(opts,
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)),
+			grpc.WithBlock(),
+		)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote daemon %s: %w", remote.Address, err)
+	}
+	return c, nil
+}
+
+// RemoteTLSConfig builds the client side of the mutual TLS handshake.
+func RemoteTLSConfig(remote config.RemoteConfig) (*tls.Config, error) {
+	if remote.Cert == "" || remote.Key == "" {
+		return nil, ErrRemoteCertRequired
+	}
+	certFile, err := config.ExpandPath(remote.Cert)
+	if err != nil {
+		return nil, err
+	}
+	keyFile, err := config.ExpandPath(remote.Key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ServerName:   remote.ServerName,
+	}
+	if tlsCfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(remote.Address); err == nil {
+			tlsCfg.ServerName = host
+		}
+	}
+	if remote.CA != "" {
+		pool, err := loadCertPool(remote.CA)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return tlsCfg, nil
+}
+
+// loadCertPool reads PEM certificates from a file into a pool.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// testCA is a throwaway certificate authority for the mutual TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string // PEM certificate
+}
+
+var testSerial int64
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testSerial++
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerial),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, name+".pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// issue signs a leaf certificate and returns its certificate and key files.
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testSerial++
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(testSerial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupRemoteServer serves mock over TCP with the daemon's mutual TLS
+// settings, trusting clients signed by ca.
+func setupRemoteServer(t *testing.T, dir string, ca *testCA, mock *mockSweepDaemonServer) string {
+	t.Helper()
+	certFile, keyFile := ca.issue(t, dir, "server", x509.ExtKeyUsageServerAuth)
+	tlsCfg, err := daemon.ServerTLSConfig(certFile, keyFile, ca.file)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() failed: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create listener: %v", err)
+	}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsCfg)))
+	sweepv1.RegisterSweepDaemonServer(srv, mock)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestConnectRemote(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	addr := setupRemoteServer(t, dir, ca, &mockSweepDaemonServer{})
+	certFile, keyFile := ca.issue(t, dir, "laptop", x509.ExtKeyUsageClientAuth)
+
+	target := Target{Remote: config.RemoteConfig{Address: addr, Cert: certFile, Key: keyFile, CA: ca.file}}
+	if !target.IsRemote() || !target.Running() {
+		t.Fatal("remote target should be remote and assumed running")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := target.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer client.Close()
+
+	status, err := client.GetDaemonStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetDaemonStatus() failed: %v", err)
+	}
+	if !status.Running {
+		t.Error("expected running status from remote daemon")
+	}
+}
+
+func TestConnectRemoteRejectsUntrustedClient(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	addr := setupRemoteServer(t, dir, ca, &mockSweepDaemonServer{})
+
+	// A client certificate from another CA must be refused
+	other := newTestCA(t, dir, "other-ca")
+	certFile, keyFile := other.issue(t, dir, "intruder", x509.ExtKeyUsageClientAuth)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client, err := ConnectRemote(ctx, config.RemoteConfig{Address: addr, Cert: certFile, Key: keyFile, CA: ca.file})
+	if err == nil {
+		// Some TLS versions only report the rejection on the first call
+		defer client.Close()
+		if _, err = client.GetDaemonStatus(ctx); err == nil {
+			t.Fatal("expected untrusted client certificate to be rejected")
+		}
+	}
+}
+
+func TestRemoteTLSConfig(t *testing.T) {
+	_, err := RemoteTLSConfig(config.RemoteConfig{Address: "nas:7420"})
+	if !errors.Is(err, ErrRemoteCertRequired) {
+		t.Errorf("expected ErrRemoteCertRequired, got %v", err)
+	}
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	certFile, keyFile := ca.issue(t, dir, "laptop", x509.ExtKeyUsageClientAuth)
+
+	cfg, err := RemoteTLSConfig(config.RemoteConfig{Address: "nas.local:7420", Cert: certFile, Key: keyFile, CA: ca.file})
+	if err != nil {
+		t.Fatalf("RemoteTLSConfig() failed: %v", err)
+	}
+	if cfg.ServerName != "nas.local" {
+		t.Errorf("ServerName = %q, want host from address", cfg.ServerName)
+	}
+	if cfg.RootCAs == nil || cfg.MinVersion != tls.VersionTLS12 {
+		t.Error("expected CA pool and TLS 1.2 minimum")
+	}
+
+	cfg, err = RemoteTLSConfig(config.RemoteConfig{Address: "10.0.0.5:7420", Cert: certFile, Key: keyFile, ServerName: "nas"})
+	if err != nil {
+		t.Fatalf("RemoteTLSConfig() failed: %v", err)
+	}
+	if cfg.ServerName != "nas" {
+		t.Errorf("ServerName = %q, want explicit server_name", cfg.ServerName)
+	}
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+// DefaultAlertInterval is the default for Config.AlertInterval.
+const DefaultAlertInterval = 5 * time.Minute
+
+// alerter checks the configured disk usage thresholds and sends an alert
+// for each one crossed.
+type alerter struct {
+	thresholds []alert.Threshold
+	actions    alert.Actions
+	monitor    *alert.Monitor
+	unindexed  map[string]bool // Paths warned to be unmeasurable
+}
+
+// checkAlerts checks cfg's thresholds every interval until ctx is
+// canceled. Sizes come from the index, so they are only as current as it
+// is, and the volume's usage from the filesystem.
+func (s *Server) checkAlerts(ctx context.Context, thresholds []alert.Threshold, actions alert.Actions, interval time.Duration) {
+	a := &alerter{
+		thresholds: thresholds,
+		actions:    actions,
+		monitor:    alert.NewMonitor(),
+		unindexed:  make(map[string]bool),
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.IsMigrating() {
+				s.service.checkThresholds(ctx, a, now)
+			}
+		}
+	}
+}
+
+// checkThresholds measures each threshold's path and sends the alerts
+// newly crossed, returning them.
+func (s *Service) checkThresholds(ctx context.Context, a *alerter, now time.Time) []alert.Alert {
+	log := logging.Get("daemon")
+	var fired []alert.Alert
+	for _, t := range a.thresholds {
+		u := s.thresholdUsage(ctx, t)
+		if t.MaxSize > 0 && u.Size < 0 && !a.unindexed[t.Path] {
+			log.Warn("can't check alert threshold: path is not indexed", "path", t.Path)
+			a.unindexed[t.Path] = true
+		}
+		for _, al := range a.monitor.Check(t, u, now) {
+			log.Warn("disk usage alert", "name", al.Name, "path", al.Path, "kind", al.Kind, "message", al.Message())
+			if err := alert.Notify(ctx, a.actions, al); err != nil {
+				log.Warn("failed to send alert", "path", al.Path, "error", err)
+				s.errors.add("alert", al.Path, err)
+			}
+			fired = append(fired, al)
+		}
+	}
+	return fired
+}
+
+// thresholdUsage measures what t limits: the size of its path from the
+// index, if the path is indexed and not being indexed, and the share of
+// its volume in use.
+func (s *Service) thresholdUsage(ctx context.Context, t alert.Threshold) alert.Usage {
+	u := alert.Usage{Size: -1, Used: -1}
+	if t.MaxSize > 0 {
+		if covered, indexed := s.store.IsPathCovered(t.Path); covered && !s.isIndexingPath(indexed) {
+			dirs, _, err := s.dirTotals(ctx, t.Path, indexed, 1)
+			for _, d := range dirs {
+				if err == nil && d.Depth == 0 {
+					u.Size = d.Size
+				}
+			}
+		}
+	}
+	if t.MaxUsed > 0 {
+		if usage, err := mounts.DiskUsage(t.Path); err == nil && usage.Total > 0 {
+			u.Used = float64(usage.Used()) / float64(usage.Total)
+		}
+	}
+	return u
+}
@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
+)
+
+func TestCheckThresholds(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	downloads := filepath.Join(root, "Downloads")
+	require.NoError(t, os.MkdirAll(filepath.Join(downloads, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloads, "a.iso"), make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(downloads, "sub", "b.iso"), make([]byte, 50), 0o644))
+	_, err = svc.indexer.Index(ctx, root, nil)
+	require.NoError(t, err)
+
+	a := &alerter{
+		thresholds: []alert.Threshold{
+			{Name: "downloads", Path: downloads, MaxSize: 120},
+			{Path: filepath.Join(t.TempDir(), "elsewhere"), MaxSize: 1}, // Not indexed
+		},
+		monitor:   alert.NewMonitor(),
+		unindexed: make(map[string]bool),
+	}
+
+	fired := svc.checkThresholds(ctx, a, time.Now())
+	require.Len(t, fired, 1)
+	assert.Equal(t, "downloads", fired[0].Name)
+	assert.Equal(t, alert.KindSize, fired[0].Kind)
+	assert.InDelta(t, 150, fired[0].Value, 0)
+	assert.True(t, a.unindexed[a.thresholds[1].Path], "expected a warning about the path that isn't indexed")
+
+	assert.Empty(t, svc.checkThresholds(ctx, a, time.Now()), "expected the alert to fire once")
+}
@@ -2,11 +2,15 @@
 package broadcaster
 
 import (
+	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/google/uuid"
+
+	sweepfilter "github.com/jamesainslie/sweep/pkg/sweep/filter"
 )
 
 // EventType represents the type of file event.
@@ -19,27 +23,63 @@ const (
 	EventRenamed
 )
 
+// eventNames are the names of event types, as in hook configuration.
+var eventNames = []string{"created", "modified", "deleted", "renamed"}
+
+// String returns the event type's name, e.g. "created".
+func (t EventType) String() string {
+	if int(t) < 0 || int(t) >= len(eventNames) {
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+	return eventNames[t]
+}
+
+// ParseEventType parses an event type's name.
+func ParseEventType(name string) (EventType, error) {
+	if i := slices.Index(eventNames, strings.ToLower(strings.TrimSpace(name))); i >= 0 {
+		return EventType(i), nil
+	}
+	return 0, fmt.Errorf("unknown event %q (available: %s)", name, strings.Join(eventNames, ", "))
+}
+
 // FileEvent represents a file system event.
 type FileEvent struct {
 	Type    EventType
 	Path    string
 	Size    int64
 	ModTime int64
+	NewPath string // Where a renamed directory moved, with everything in it
 }
 
 // Subscriber represents a client subscribed to file events.
+// Events are shared with other subscribers and must not be modified.
 type Subscriber struct {
 	ID      string
 	Root    string
 	MinSize int64
 	Exclude []string
 	Events  chan *FileEvent
+
+	filter *filter
+}
+
+// filter is a set of subscriber filters shared by every subscriber that
+// asked for the same root, size threshold, and exclusions. Each event is
+// evaluated once per filter, however many subscribers share it.
+type filter struct {
+	key         string
+	root        string // Cleaned root; empty matches every path
+	minSize     int64
+	exclude     []sweepfilter.NameGlob // Compiled once for every event
+	subscribers map[string]*Subscriber
 }
 
 // Broadcaster manages subscribers and distributes file events.
 type Broadcaster struct {
 	mu          sync.RWMutex
 	subscribers map[string]*Subscriber
+	filters     map[string]*filter   // By filter key
+	byRoot      map[string][]*filter // By cleaned root, for ancestor lookups
 	closed      bool
 }
 
@@ -47,6 +87,8 @@ type Broadcaster struct {
 func New() *Broadcaster {
 	return &Broadcaster{
 		subscribers: make(map[string]*Subscriber),
+		filters:     make(map[string]*filter),
+		byRoot:      make(map[string][]*filter),
 	}
 }
 
@@ -67,6 +109,26 @@ func (b *Broadcaster) Subscribe(root string, minSize int64, exclude []string) *S
 		Events:  make(chan *FileEvent, 100),
 	}
 
+	cleaned := root
+	if cleaned != "" {
+		cleaned = filepath.Clean(cleaned)
+	}
+	key := fmt.Sprintf("%s\x00%d\x00%s", cleaned, minSize, strings.Join(exclude, "\x00"))
+	f, ok := b.filters[key]
+	if !ok {
+		f = &filter{
+			key:         key,
+			root:        cleaned,
+			minSize:     minSize,
+			exclude:     sweepfilter.CompileNameGlobs(exclude),
+			subscribers: make(map[string]*Subscriber),
+		}
+		b.filters[key] = f
+		b.byRoot[cleaned] = append(b.byRoot[cleaned], f)
+	}
+	f.subscribers[sub.ID] = sub
+	sub.filter = f
+
 	b.subscribers[sub.ID] = sub
 	return sub
 }
@@ -76,14 +138,42 @@ func (b *Broadcaster) Unsubscribe(id string) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if sub, ok := b.subscribers[id]; ok {
-		close(sub.Events)
-		delete(b.subscribers, id)
+	sub, ok := b.subscribers[id]
+	if !ok {
+		return
+	}
+	close(sub.Events)
+	delete(b.subscribers, id)
+
+	f := sub.filter
+	delete(f.subscribers, id)
+	if len(f.subscribers) == 0 {
+		delete(b.filters, f.key)
+		b.byRoot[f.root] = slices.DeleteFunc(b.byRoot[f.root], func(g *filter) bool { return g == f })
+		if len(b.byRoot[f.root]) == 0 {
+			delete(b.byRoot, f.root)
+		}
 	}
 }
 
-// Notify sends an event to all matching subscribers.
+// Notify sends an event to all matching subscribers. The event is built once
+// and only the filters rooted at the path or one of its ancestors are
+// evaluated, so the cost grows with path depth rather than with the number
+// of subscribers.
 func (b *Broadcaster) Notify(path string, eventType EventType, size int64) {
+	b.notify(&FileEvent{Type: eventType, Path: path, Size: size}, path)
+}
+
+// NotifyRenamed sends one EventRenamed for a directory that moved from
+// oldPath to newPath with everything in it, in place of an event for each
+// file. Subscribers rooted under either path receive it.
+func (b *Broadcaster) NotifyRenamed(oldPath, newPath string) {
+	b.notify(&FileEvent{Type: EventRenamed, Path: oldPath, NewPath: newPath}, oldPath, newPath)
+}
+
+// notify delivers event to the subscribers whose filters match one of the
+// paths, once per filter.
+func (b *Broadcaster) notify(event *FileEvent, paths ...string) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
@@ -91,42 +181,53 @@ func (b *Broadcaster) Notify(path string, eventType EventType, size int64) {
 		return
 	}
 
-	for _, sub := range b.subscribers {
-		if b.matches(sub, path, size) {
-			event := &FileEvent{
-				Type: eventType,
-				Path: path,
-				Size: size,
+	var delivered []*filter // Only needed with several paths
+	deliver := func(path string, filters []*filter) {
+		for _, f := range filters {
+			if !f.matches(path, event.Size) || slices.Contains(delivered, f) {
+				continue
+			}
+			if len(paths) > 1 {
+				delivered = append(delivered, f)
 			}
-			select {
-			case sub.Events <- event:
-			default:
-				// Channel full, event dropped
+			for _, sub := range f.subscribers {
+				select {
+				case sub.Events <- event:
+				default:
+					// Channel full, event dropped
+				}
 			}
 		}
 	}
-}
 
-// matches checks if an event matches a subscriber's filters.
-func (b *Broadcaster) matches(sub *Subscriber, path string, size int64) bool {
-	// Check path is under root
-	if !strings.HasPrefix(path, sub.Root) {
-		return false
-	}
-	// Ensure it's actually under the root (not just a prefix match)
-	if len(path) > len(sub.Root) && path[len(sub.Root)] != filepath.Separator {
-		return false
+	for _, path := range paths {
+		deliver(path, b.byRoot[""])
+		for dir := filepath.Clean(path); ; {
+			deliver(path, b.byRoot[dir])
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
 	}
+}
 
+// matches checks an event against the filter's size threshold and
+// exclusions. The root was already matched by the ancestor lookup.
+func (f *filter) matches(path string, size int64) bool {
 	// Check size threshold (skip for deletions - size might be 0)
-	if size > 0 && size < sub.MinSize {
+	if size > 0 && size < f.minSize {
 		return false
 	}
 
 	// Check exclusions
-	for _, pattern := range sub.Exclude {
-		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
-			return false
+	if len(f.exclude) > 0 {
+		base := filepath.Base(path)
+		for _, g := range f.exclude {
+			if g.Match(base) {
+				return false
+			}
 		}
 	}
 
@@ -147,6 +248,8 @@ func (b *Broadcaster) Close() {
 		close(sub.Events)
 	}
 	b.subscribers = make(map[string]*Subscriber)
+	b.filters = make(map[string]*filter)
+	b.byRoot = make(map[string][]*filter)
 }
 
 // SubscriberCount returns the number of active subscribers.
@@ -155,3 +258,11 @@ func (b *Broadcaster) SubscriberCount() int {
 	defer b.mu.RUnlock()
 	return len(b.subscribers)
 }
+
+// FilterCount returns the number of distinct subscriber filters, each of
+// which is evaluated once per event.
+func (b *Broadcaster) FilterCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.filters)
+}
@@ -1,6 +1,7 @@
 package broadcaster
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -71,6 +72,25 @@ func TestBroadcaster_Notify_FiltersByPath(t *testing.T) {
 	}
 }
 
+func TestBroadcaster_Notify_FiltersByExclude(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	// A malformed pattern is dropped rather than excluding everything
+	sub := b.Subscribe("/tmp/test", 1024, []string{"*.part", "cache", "[a-"})
+
+	b.Notify("/tmp/test/download.iso.part", EventCreated, 2048)
+	b.Notify("/tmp/test/cache", EventCreated, 2048)
+	b.Notify("/tmp/test/download.iso", EventCreated, 2048)
+
+	select {
+	case event := <-sub.Events:
+		assert.Equal(t, "/tmp/test/download.iso", event.Path, "excluded names are filtered out")
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected event not received")
+	}
+}
+
 func TestBroadcaster_Unsubscribe(t *testing.T) {
 	b := New()
 	defer b.Close()
@@ -82,3 +102,97 @@ func TestBroadcaster_Unsubscribe(t *testing.T) {
 	_, ok := <-sub.Events
 	assert.False(t, ok, "channel should be closed after unsubscribe")
 }
+
+func TestBroadcaster_SharedFilter(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	// Two TUIs watching the same tree share one filter
+	first := b.Subscribe("/tmp/test", 1024, []string{"*.tmp"})
+	second := b.Subscribe("/tmp/test/", 1024, []string{"*.tmp"})
+	other := b.Subscribe("/tmp", 0, nil)
+	assert.Equal(t, 3, b.SubscriberCount())
+	assert.Equal(t, 2, b.FilterCount())
+
+	b.Notify("/tmp/test/big.iso", EventCreated, 4096)
+	got1, got2, got3 := <-first.Events, <-second.Events, <-other.Events
+	assert.Same(t, got1, got2, "matching subscribers share one event")
+	assert.Same(t, got1, got3)
+
+	// Exclusions are still applied per filter
+	b.Notify("/tmp/test/scratch.tmp", EventCreated, 4096)
+	assert.Len(t, first.Events, 0)
+	assert.Len(t, other.Events, 1)
+
+	b.Unsubscribe(first.ID)
+	assert.Equal(t, 2, b.FilterCount(), "filter kept while a subscriber uses it")
+	b.Unsubscribe(second.ID)
+	assert.Equal(t, 1, b.FilterCount())
+}
+
+func TestBroadcaster_Notify_Roots(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	all := b.Subscribe("", 0, nil)
+	exact := b.Subscribe("/data/file.bin", 0, nil)
+	sibling := b.Subscribe("/data/fil", 0, nil)
+
+	b.Notify("/data/file.bin", EventModified, 10)
+	assert.Len(t, all.Events, 1, "an empty root matches every path")
+	assert.Len(t, exact.Events, 1, "a root matches itself")
+	assert.Len(t, sibling.Events, 0, "a root only matches whole path components")
+}
+
+func TestBroadcaster_NotifyRenamed(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	all := b.Subscribe("", 0, nil)
+	from := b.Subscribe("/data/old", 0, nil)
+	to := b.Subscribe("/data/new", 0, nil)
+	other := b.Subscribe("/data/other", 0, nil)
+
+	b.NotifyRenamed("/data/old", "/data/new")
+	require.Len(t, all.Events, 1, "a subscriber matching both paths gets one event")
+	event := <-all.Events
+	assert.Equal(t, EventRenamed, event.Type)
+	assert.Equal(t, "/data/old", event.Path)
+	assert.Equal(t, "/data/new", event.NewPath)
+	assert.Len(t, from.Events, 1)
+	assert.Len(t, to.Events, 1)
+	assert.Len(t, other.Events, 0)
+}
+
+func BenchmarkBroadcaster_Notify(b *testing.B) {
+	bc := New()
+	defer bc.Close()
+
+	// Several TUIs attached to a few trees
+	for i := range 16 {
+		sub := bc.Subscribe(fmt.Sprintf("/home/user/project%d", i%4), 1<<20, []string{"*.tmp"})
+		go func() {
+			for range sub.Events {
+			}
+		}()
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		bc.Notify("/home/user/project1/build/output/app.bin", EventModified, 8<<20)
+	}
+}
+
+func TestParseEventType(t *testing.T) {
+	for _, typ := range []EventType{EventCreated, EventModified, EventDeleted, EventRenamed} {
+		parsed, err := ParseEventType(typ.String())
+		require.NoError(t, err)
+		assert.Equal(t, typ, parsed)
+	}
+	parsed, err := ParseEventType(" Created ")
+	require.NoError(t, err)
+	assert.Equal(t, EventCreated, parsed)
+
+	_, err = ParseEventType("exploded")
+	assert.ErrorContains(t, err, "available: created, modified, deleted, renamed")
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errCompactBusy is returned by compact while a path is being indexed, the
+// store is being migrated, or another compaction is running.
+var errCompactBusy = errors.New("the index store is busy")
+
+// compact drops stale records from the store and reclaims their space. It
+// fails with errCompactBusy rather than wait, since compacting while a path
+// is indexed would drop the entries of a root not yet recorded as indexed.
+func (s *Service) compact(ctx context.Context) (store.CompactResult, error) {
+	if s.migrating != nil && s.migrating() {
+		return store.CompactResult{}, errCompactBusy
+	}
+	if !s.compactMu.TryLock() {
+		return store.CompactResult{}, errCompactBusy
+	}
+	defer s.compactMu.Unlock()
+
+	start := time.Now()
+	res, err := s.store.Compact(ctx)
+	if err != nil {
+		return res, err
+	}
+	s.lastCompacted.Store(time.Now().Unix())
+	logging.Get("daemon").Info("index store compacted",
+		"pruned", res.Pruned,
+		"size_before", res.SizeBefore,
+		"size_after", res.SizeAfter,
+		"reclaimed", res.Reclaimed(),
+		"duration", time.Since(start))
+	return res, nil
+}
+
+// CompactStore drops stale records from the index store and rewrites it to
+// free the space they took.
+func (s *Service) CompactStore(ctx context.Context, _ *sweepv1.CompactStoreRequest) (*sweepv1.CompactStoreResponse, error) {
+	res, err := s.compact(ctx)
+	if errors.Is(err, errCompactBusy) {
+		return nil, status.Error(codes.FailedPrecondition, "a path is being indexed or the index store migrated; try again when it finishes")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compact index store: %v", err)
+	}
+	return &sweepv1.CompactStoreResponse{
+		Pruned:     res.Pruned,
+		SizeBefore: res.SizeBefore,
+		SizeAfter:  res.SizeAfter,
+	}, nil
+}
+
+// compactStore compacts the store every interval until ctx is done. Runs
+// that find the store busy are skipped until the next.
+func (s *Server) compactStore(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := s.service.compact(ctx)
+			switch {
+			case errors.Is(err, errCompactBusy):
+				logging.Get("daemon").Debug("index store busy, skipping compaction")
+			case err != nil && ctx.Err() == nil:
+				logging.Get("daemon").Warn("failed to compact index store", "error", err)
+				s.service.errors.add("store", "", err)
+			}
+		}
+	}
+}
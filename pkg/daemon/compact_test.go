@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestCompactStore(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+	_, err = svc.indexer.Index(ctx, root, nil)
+	require.NoError(t, err)
+	// Left behind by a root no longer indexed
+	orphan := filepath.Join(t.TempDir(), "gone.bin")
+	require.NoError(t, st.Put(&store.Entry{Path: orphan, Size: 10}))
+
+	resp, err := svc.CompactStore(ctx, &sweepv1.CompactStoreRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.GetPruned())
+	assert.Positive(t, resp.GetSizeBefore())
+	_, err = st.Get(orphan)
+	assert.Error(t, err, "the orphaned entry is dropped")
+	_, err = st.Get(filepath.Join(root, "a.bin"))
+	assert.NoError(t, err, "indexed entries are kept")
+
+	// Refused while a path is indexed
+	svc.compactMu.RLock()
+	_, err = svc.CompactStore(ctx, &sweepv1.CompactStoreRequest{})
+	svc.compactMu.RUnlock()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// And while the store is migrated
+	svc.migrating = func() bool { return true }
+	_, err = svc.CompactStore(ctx, &sweepv1.CompactStoreRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// DeleteFiles moves files and directories to the trash, or deletes them
+// with the backend asked for: permanently when they are under
+// PermanentRoots, or with the staging or upload backend as configured in
+// Backends. It streams the result for each. Deleted paths are removed from
+// the index as they go, rather than when the watcher notices, so queries
+// made right after the stream ends don't return them.
+func (s *Service) DeleteFiles(req *sweepv1.DeleteFilesRequest, stream grpc.ServerStreamingServer[sweepv1.DeleteProgress]) error {
+	if s.ReadOnly {
+		return status.Error(codes.FailedPrecondition, "read-only mode: refusing to delete files")
+	}
+	paths := req.GetPaths()
+	if len(paths) == 0 {
+		return status.Error(codes.InvalidArgument, "no paths to delete")
+	}
+	name, err := trash.ParseBackend(req.GetBackend())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetBackend() == "" && req.GetPermanent() {
+		name = trash.BackendPermanent
+	}
+	permanent := name == trash.BackendPermanent
+	if permanent && len(s.PermanentRoots) == 0 {
+		return status.Error(codes.FailedPrecondition, "permanent deletes are disabled: set trash.permanent_roots in the daemon's config")
+	}
+	opts := s.Backends
+	opts.PermanentRoots = s.PermanentRoots
+	backend, err := trash.NewBackend(name, opts)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "%v of the daemon", err)
+	}
+	log := logging.Get("daemon")
+
+	total := int32(len(paths))
+	var current int32
+	var deleted int
+	var freed int64
+	// send reports one file. Calls are serialized: backends serialize their
+	// callbacks and the checks below run before them.
+	var sendErr error
+	send := func(path string, size int64, location string, err error) {
+		current++
+		progress := &sweepv1.DeleteProgress{
+			Path:     path,
+			Deleted:  err == nil,
+			Size:     size,
+			Current:  current,
+			Total:    total,
+			Location: location,
+		}
+		if err != nil {
+			progress.Error = err.Error()
+		} else {
+			deleted++
+			freed += size
+		}
+		if sendErr == nil {
+			sendErr = stream.Send(progress)
+		}
+	}
+
+	var trashPaths []string
+	sizes := make(map[string]int64, len(paths))
+	for _, path := range paths {
+		size, err := deletable(path)
+		if err == nil && permanent && !s.PermanentRoots.Allows(path) {
+			err = fmt.Errorf("%s: not under trash.permanent_roots, refusing to delete permanently", path)
+		}
+		if err != nil {
+			send(path, 0, "", err)
+			continue
+		}
+		sizes[path] = size
+		trashPaths = append(trashPaths, path)
+	}
+
+	if req.GetDryRun() {
+		for _, path := range trashPaths {
+			send(path, sizes[path], "", nil)
+		}
+		return sendErr
+	}
+
+	backend.DeleteMany(stream.Context(), trashPaths, func(r trash.Result) {
+		if r.Err == nil {
+			s.removeDeleted(r.Path)
+		} else {
+			log.Warn("failed to delete file", "path", r.Path, "error", r.Err)
+		}
+		send(r.Path, sizes[r.Path], r.Location, r.Err)
+	})
+	log.Info("deleted files", "requested", total, "deleted", deleted, "freed", freed, "backend", backend.Name())
+	return sendErr
+}
+
+// deletable checks that path can be deleted through DeleteFiles and
+// returns its size, with everything in it for a directory.
+func deletable(path string) (int64, error) {
+	if !filepath.IsAbs(path) {
+		return 0, fmt.Errorf("%s: path must be absolute", path)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+	var size int64
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Count what can be read
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return size, err
+}
+
+// removeDeleted drops a deleted file or directory from the index and tells
+// watching clients it is gone.
+func (s *Service) removeDeleted(path string) {
+	if err := s.store.Remove(path); err != nil {
+		logging.Get("daemon").Warn("failed to remove deleted file from index", "path", path, "error", err)
+	}
+	if s.broadcaster != nil {
+		s.broadcaster.Notify(path, broadcaster.EventDeleted, 0)
+	}
+}
+
+// stagingPurgeInterval is how often staged files past their days are
+// purged, on top of the purge before each staged delete.
+const stagingPurgeInterval = time.Hour
+
+// purgeStaging purges the staging directory's expired days every
+// stagingPurgeInterval until ctx is done.
+func (s *Server) purgeStaging(ctx context.Context, staging trash.Staging) {
+	ticker := time.NewTicker(stagingPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			freed, err := trash.PurgeStaging(staging.Dir, staging.Days, time.Now())
+			if err != nil {
+				logging.Get("daemon").Warn("failed to purge staged files", "dir", staging.Dir, "error", err)
+			} else if freed > 0 {
+				logging.Get("daemon").Info("purged staged files", "dir", staging.Dir, "freed", freed)
+			}
+		}
+	}
+}
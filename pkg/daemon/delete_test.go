@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// mockDeleteStream implements grpc.ServerStreamingServer[sweepv1.DeleteProgress] for testing.
+type mockDeleteStream struct {
+	grpc.ServerStream
+	progress []*sweepv1.DeleteProgress
+}
+
+func (m *mockDeleteStream) Send(p *sweepv1.DeleteProgress) error {
+	m.progress = append(m.progress, p)
+	return nil
+}
+
+func (m *mockDeleteStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestServiceDeleteFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("trashing without a desktop is tested on Linux")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	b := broadcaster.New()
+	defer b.Close()
+	svc := NewServiceWithBroadcaster(st, b)
+	svc.indexer.MinLargeFileSize = 10
+
+	root := t.TempDir()
+	big, other := filepath.Join(root, "big.iso"), filepath.Join(root, "big.iso.part")
+	dir := filepath.Join(root, "old")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	require.NoError(t, os.WriteFile(big, make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(other, make([]byte, 50), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.bin"), make([]byte, 30), 0o644))
+	_, err = svc.indexer.Index(context.Background(), root, nil)
+	require.NoError(t, err)
+
+	sub := b.Subscribe(root, 0, nil)
+	defer b.Unsubscribe(sub.ID)
+
+	missing := filepath.Join(root, "missing.iso")
+	stream := &mockDeleteStream{}
+	err = svc.DeleteFiles(&sweepv1.DeleteFilesRequest{Paths: []string{big, missing, dir, "relative.iso"}}, stream)
+	require.NoError(t, err)
+
+	require.Len(t, stream.progress, 4)
+	byPath := make(map[string]*sweepv1.DeleteProgress)
+	for i, p := range stream.progress {
+		assert.Equal(t, int32(i+1), p.GetCurrent())
+		assert.Equal(t, int32(4), p.GetTotal())
+		byPath[p.GetPath()] = p
+	}
+	assert.True(t, byPath[big].GetDeleted())
+	assert.Equal(t, int64(100), byPath[big].GetSize())
+	assert.False(t, byPath[missing].GetDeleted())
+	assert.NotEmpty(t, byPath[missing].GetError())
+	assert.Contains(t, byPath["relative.iso"].GetError(), "must be absolute")
+	assert.True(t, byPath[dir].GetDeleted())
+	assert.Equal(t, int64(30), byPath[dir].GetSize(), "directories report what was in them")
+
+	_, err = os.Stat(big)
+	assert.True(t, os.IsNotExist(err), "the file is moved to the trash")
+	_, err = st.Get(big)
+	assert.Error(t, err, "the file leaves the index without waiting for the watcher")
+	files, err := st.GetLargeFiles(context.Background(), root, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, other, files[0].Path, "a file sharing the prefix stays indexed")
+	_, err = st.Get(filepath.Join(dir, "a.bin"))
+	assert.Error(t, err, "files in deleted directories leave the index")
+
+	deleted := make(map[string]bool)
+	for range 2 {
+		event := <-sub.Events
+		assert.Equal(t, broadcaster.EventDeleted, event.Type)
+		deleted[event.Path] = true
+	}
+	assert.Equal(t, map[string]bool{big: true, dir: true}, deleted)
+}
+
+func TestServiceDeleteFilesDryRun(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	path := filepath.Join(t.TempDir(), "big.iso")
+	require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o644))
+
+	stream := &mockDeleteStream{}
+	require.NoError(t, svc.DeleteFiles(&sweepv1.DeleteFilesRequest{Paths: []string{path}, DryRun: true}, stream))
+	require.Len(t, stream.progress, 1)
+	assert.True(t, stream.progress[0].GetDeleted())
+	assert.FileExists(t, path)
+}
+
+func TestServiceDeleteFilesReadOnly(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.ReadOnly = true
+
+	path := filepath.Join(t.TempDir(), "big.iso")
+	require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o644))
+
+	err = svc.DeleteFiles(&sweepv1.DeleteFilesRequest{Paths: []string{path}}, &mockDeleteStream{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.FileExists(t, path)
+}
+
+func TestServiceDeleteFilesPermanent(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.indexer.MinLargeFileSize = 10
+
+	scratch, home := t.TempDir(), t.TempDir()
+	render, photo := filepath.Join(scratch, "render.mov"), filepath.Join(home, "photo.raw")
+	require.NoError(t, os.WriteFile(render, make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(photo, make([]byte, 100), 0o644))
+	_, err = svc.indexer.Index(context.Background(), scratch, nil)
+	require.NoError(t, err)
+
+	req := &sweepv1.DeleteFilesRequest{Paths: []string{render, photo}, Permanent: true}
+	err = svc.DeleteFiles(req, &mockDeleteStream{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "no permanent roots configured")
+	assert.FileExists(t, render)
+
+	svc.PermanentRoots = trash.NewPermanentRoots([]string{scratch})
+	stream := &mockDeleteStream{}
+	require.NoError(t, svc.DeleteFiles(req, stream))
+	require.Len(t, stream.progress, 2)
+	byPath := make(map[string]*sweepv1.DeleteProgress)
+	for _, p := range stream.progress {
+		byPath[p.GetPath()] = p
+	}
+	assert.True(t, byPath[render].GetDeleted())
+	assert.False(t, byPath[photo].GetDeleted())
+	assert.Contains(t, byPath[photo].GetError(), "trash.permanent_roots")
+
+	assert.NoFileExists(t, render)
+	assert.FileExists(t, photo, "outside the permanent roots")
+	_, err = st.Get(render)
+	assert.Error(t, err, "the file leaves the index")
+}
+
+func TestServiceDeleteFilesStaging(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	root := t.TempDir()
+	path := filepath.Join(root, "big.iso")
+	require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o644))
+
+	req := &sweepv1.DeleteFilesRequest{Paths: []string{path}, Backend: trash.BackendStaging}
+	err = svc.DeleteFiles(req, &mockDeleteStream{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "no staging directory configured")
+	err = svc.DeleteFiles(&sweepv1.DeleteFilesRequest{Paths: []string{path}, Backend: "shred"}, &mockDeleteStream{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.FileExists(t, path)
+
+	svc.Backends.Staging = trash.Staging{Dir: filepath.Join(root, ".staging"), Days: 7}
+	stream := &mockDeleteStream{}
+	require.NoError(t, svc.DeleteFiles(req, stream))
+	require.Len(t, stream.progress, 1)
+	assert.True(t, stream.progress[0].GetDeleted())
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, stream.progress[0].GetLocation(), "the file is kept in the staging directory")
+}
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetDiagnostics reports the daemon's process health, and checks every
+// record in the store when asked to.
+func (s *Service) GetDiagnostics(ctx context.Context, req *sweepv1.GetDiagnosticsRequest) (*sweepv1.Diagnostics, error) {
+	resp := &sweepv1.Diagnostics{
+		Version:    s.version,
+		Pid:        int32(os.Getpid()), //nolint:gosec // PIDs fit in 32 bits
+		Goroutines: int64(runtime.NumGoroutine()),
+	}
+	resp.OpenFds, resp.FdLimit = openFDs()
+	if s.watcher != nil {
+		st := s.watcher.Stats()
+		resp.WatcherQueued = int64(st.Queued)
+		resp.WatcherHeld = int64(st.Held)
+		resp.WatcherDirs = int64(st.Dirs)
+	}
+
+	if !req.GetCheckStore() {
+		return resp, nil
+	}
+	if s.migrating != nil && s.migrating() {
+		return nil, status.Error(codes.FailedPrecondition, "the index store is being migrated; try again when it finishes")
+	}
+	start := time.Now()
+	res, err := s.store.Check(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check index store: %v", err)
+	}
+	resp.StoreCheck = &sweepv1.StoreCheck{
+		Records:    res.Records,
+		Problems:   res.Problems,
+		Damaged:    res.Damaged,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if res.Damaged > 0 {
+		logging.Get("daemon").Warn("index store check found problems",
+			"records", res.Records, "damaged", res.Damaged)
+	}
+	return resp, nil
+}
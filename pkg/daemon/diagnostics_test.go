@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestGetDiagnostics(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.version = "1.2.3"
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+	_, err = svc.indexer.Index(ctx, root, nil)
+	require.NoError(t, err)
+
+	resp, err := svc.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", resp.GetVersion())
+	assert.Equal(t, int32(os.Getpid()), resp.GetPid())
+	assert.Positive(t, resp.GetGoroutines())
+	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
+		assert.Positive(t, resp.GetOpenFds())
+		assert.GreaterOrEqual(t, resp.GetFdLimit(), resp.GetOpenFds())
+	}
+	assert.Nil(t, resp.GetStoreCheck(), "the store is only checked when asked")
+
+	resp, err = svc.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{CheckStore: true})
+	require.NoError(t, err)
+	require.NotNil(t, resp.GetStoreCheck())
+	assert.Positive(t, resp.GetStoreCheck().GetRecords())
+	assert.Zero(t, resp.GetStoreCheck().GetDamaged())
+
+	// An indexed root without its root entry is reported
+	require.NoError(t, st.AddIndexedPath(filepath.Join(t.TempDir(), "lost")))
+	resp, err = svc.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{CheckStore: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.GetStoreCheck().GetDamaged())
+	assert.Len(t, resp.GetStoreCheck().GetProblems(), 1)
+
+	// The check is refused while the store is migrated
+	svc.migrating = func() bool { return true }
+	_, err = svc.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{CheckStore: true})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = svc.GetDiagnostics(ctx, &sweepv1.GetDiagnosticsRequest{})
+	assert.NoError(t, err, "diagnostics without the check are still served")
+}
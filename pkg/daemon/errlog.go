@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+)
+
+// maxRecentErrors is how many errors GetDaemonStatus reports.
+const maxRecentErrors = 20
+
+// errorLog keeps the latest errors from background work, such as failed
+// index runs, for GetDaemonStatus. They are logged too; this is so
+// monitoring can see them without reading the log.
+type errorLog struct {
+	mu     sync.Mutex
+	errors []*sweepv1.DaemonError // Oldest first
+}
+
+// add records err from component, about path if it concerns one.
+func (l *errorLog) add(component, path string, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.errors) == maxRecentErrors {
+		l.errors = append(l.errors[:0], l.errors[1:]...)
+	}
+	l.errors = append(l.errors, &sweepv1.DaemonError{
+		Time:      time.Now().Unix(),
+		Component: component,
+		Path:      path,
+		Message:   err.Error(),
+	})
+}
+
+// recent returns the recorded errors, oldest first.
+func (l *errorLog) recent() []*sweepv1.DaemonError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*sweepv1.DaemonError(nil), l.errors...)
+}
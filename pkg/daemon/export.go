@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+)
+
+// exportBatchSize is the number of entries ExportIndex sends per message.
+const exportBatchSize = 1000
+
+// ExportIndex streams every entry in the index under a path, or under every
+// indexed path when none is given. Directories carry the size and count of
+// the files beneath them whatever the index mode; in aggregates mode, where
+// only large files are kept, those are the files exported. Owners are read
+// from the filesystem, so files that have gone since they were indexed have
+// none.
+func (s *Service) ExportIndex(req *sweepv1.ExportIndexRequest, stream grpc.ServerStreamingServer[sweepv1.ExportIndexResponse]) error {
+	type exportRoot struct{ path, indexed string }
+	var roots []exportRoot
+	if root := req.GetRoot(); root != "" {
+		root = filepath.Clean(root)
+		covered, indexed := s.store.IsPathCovered(root)
+		if !covered {
+			return status.Errorf(codes.FailedPrecondition, "%s is not indexed", root)
+		}
+		roots = []exportRoot{{root, indexed}}
+	} else {
+		paths, err := s.store.GetIndexedPaths()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to list indexed paths: %v", err)
+		}
+		for _, p := range paths {
+			roots = append(roots, exportRoot{p, p})
+		}
+	}
+
+	ctx := stream.Context()
+	names := make(owner.Names)
+	batch := &sweepv1.ExportIndexResponse{}
+	var sendErr error
+	flush := func() error {
+		if len(batch.Entries) > 0 {
+			sendErr = stream.Send(batch)
+			batch = &sweepv1.ExportIndexResponse{}
+		}
+		return sendErr
+	}
+	add := func(entry *sweepv1.IndexEntry) error {
+		if !entry.IsDir {
+			entry.FileType = filetype.FromExtension(entry.Path)
+		}
+		if info, err := os.Lstat(entry.Path); err == nil {
+			entry.Owner = names.Of(info)
+		}
+		batch.Entries = append(batch.Entries, entry)
+		if len(batch.Entries) >= exportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+
+	for _, root := range roots {
+		aggregates := false
+		if meta := s.store.GetIndexMeta(root.indexed); meta != nil {
+			aggregates = meta.Mode == string(indexer.ModeAggregates)
+		}
+		var err error
+		if aggregates {
+			err = s.exportAggregates(ctx, root.path, add)
+		} else {
+			err = s.exportFull(root.path, add)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		if sendErr != nil {
+			return sendErr
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read index: %v", err)
+		}
+	}
+	return flush()
+}
+
+// exportFull exports a root indexed in full mode: every file as it is
+// stored, then every directory with the totals of the files beneath it.
+func (s *Service) exportFull(root string, add func(*sweepv1.IndexEntry) error) error {
+	totals := du.NewTotals(root, 0)
+	var usage sharing.Counter // Shared storage counts once and placeholders locally, as in GetDirSizes
+	dirTimes := make(map[string]int64)
+	err := s.store.Walk(root, func(e *store.Entry) error {
+		if e.IsDir {
+			dirTimes[e.Path] = e.ModTime
+			return nil
+		}
+		totals.AddFile(e.Path, usage.Add(e.Shared, cloud.Local(e.Cloud, e.Size)))
+		return add(&sweepv1.IndexEntry{
+			Path:    e.Path,
+			Size:    e.Size,
+			ModTime: e.ModTime,
+			Shared:  e.Shared != nil,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	dirs := totals.Dirs()
+	slices.SortFunc(dirs, func(a, b du.Dir) int { return strings.Compare(a.Path, b.Path) })
+	for _, d := range dirs {
+		modTime, ok := dirTimes[d.Path]
+		if !ok {
+			continue // Not in the index, like the parents of a root given under an indexed path
+		}
+		delete(dirTimes, d.Path)
+		if err := add(&sweepv1.IndexEntry{Path: d.Path, Size: d.Size, ModTime: modTime, IsDir: true, Files: d.Files}); err != nil {
+			return err
+		}
+	}
+	// Directories without files
+	for _, path := range slices.Sorted(maps.Keys(dirTimes)) {
+		if err := add(&sweepv1.IndexEntry{Path: path, ModTime: dirTimes[path], IsDir: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportAggregates exports a root indexed in aggregates mode: its
+// directories, which already hold their totals, and its large files.
+func (s *Service) exportAggregates(ctx context.Context, root string, add func(*sweepv1.IndexEntry) error) error {
+	err := s.store.Walk(root, func(e *store.Entry) error {
+		if !e.IsDir {
+			return nil
+		}
+		return add(&sweepv1.IndexEntry{Path: e.Path, Size: e.Size, ModTime: e.ModTime, IsDir: true, Files: e.Files})
+	})
+	if err != nil {
+		return err
+	}
+
+	files, err := s.store.GetLargeFiles(ctx, root, 0, 0)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !store.IsPathUnderRoot(f.Path, root) {
+			continue // A sibling sharing the prefix, like /data2 for /data
+		}
+		if err := add(&sweepv1.IndexEntry{Path: f.Path, Size: f.Size, ModTime: f.ModTime, Shared: f.Shared != nil}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// mockExportStream implements grpc.ServerStreamingServer[sweepv1.ExportIndexResponse] for testing.
+type mockExportStream struct {
+	grpc.ServerStream
+	batches []*sweepv1.ExportIndexResponse
+}
+
+func (m *mockExportStream) Send(r *sweepv1.ExportIndexResponse) error {
+	m.batches = append(m.batches, r)
+	return nil
+}
+
+func (m *mockExportStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestServiceExportIndex(t *testing.T) {
+	for _, mode := range []indexer.Mode{indexer.ModeFull, indexer.ModeAggregates} {
+		t.Run(string(mode), func(t *testing.T) {
+			st, err := store.Open(t.TempDir())
+			require.NoError(t, err)
+			defer st.Close()
+			svc := NewService(st)
+			svc.indexer.Mode = mode
+			svc.indexer.MinLargeFileSize = 50
+
+			root := t.TempDir()
+			src := filepath.Join(root, "src")
+			video := filepath.Join(root, "movie.mp4")
+			small := filepath.Join(src, "main.go")
+			require.NoError(t, os.Mkdir(src, 0o755))
+			require.NoError(t, os.WriteFile(video, make([]byte, 100), 0o644))
+			require.NoError(t, os.WriteFile(small, make([]byte, 10), 0o644))
+			_, err = svc.indexer.Index(context.Background(), root, nil)
+			require.NoError(t, err)
+
+			for _, req := range []*sweepv1.ExportIndexRequest{{Root: root}, {}} {
+				stream := &mockExportStream{}
+				require.NoError(t, svc.ExportIndex(req, stream))
+
+				byPath := make(map[string]*sweepv1.IndexEntry)
+				for _, b := range stream.batches {
+					for _, e := range b.GetEntries() {
+						byPath[e.GetPath()] = e
+					}
+				}
+				require.Contains(t, byPath, video)
+				assert.Equal(t, int64(100), byPath[video].GetSize())
+				assert.Equal(t, "Video", byPath[video].GetFileType())
+				assert.NotEmpty(t, byPath[video].GetOwner())
+
+				require.Contains(t, byPath, root)
+				require.Contains(t, byPath, src)
+				assert.True(t, byPath[src].GetIsDir())
+				assert.Equal(t, int64(1), byPath[src].GetFiles())
+				assert.Equal(t, int64(10), byPath[src].GetSize(), "directories hold the size of their files")
+				assert.Equal(t, int64(2), byPath[root].GetFiles())
+				assert.Equal(t, int64(110), byPath[root].GetSize())
+
+				if mode == indexer.ModeFull {
+					assert.Contains(t, byPath, small, "small files are exported from full indexes")
+				} else {
+					assert.NotContains(t, byPath, small, "aggregates indexes only keep large files")
+				}
+			}
+		})
+	}
+}
+
+func TestServiceExportIndexNotIndexed(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	err = svc.ExportIndex(&sweepv1.ExportIndexRequest{Root: t.TempDir()}, &mockExportStream{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
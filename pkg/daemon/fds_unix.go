@@ -0,0 +1,29 @@
+//go:build darwin || linux
+
+package daemon
+
+import (
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// openFDs returns how many file descriptors the daemon has open and its soft
+// limit on them, or -1 for either that can't be read.
+func openFDs() (open, limit int64) {
+	open, limit = -1, -1
+	dir := "/dev/fd"
+	if runtime.GOOS == "linux" {
+		dir = "/proc/self/fd"
+	}
+	// Reading the directory opens one more, which is listed too
+	if entries, err := os.ReadDir(dir); err == nil {
+		open = int64(len(entries)) - 1
+	}
+	var rlim unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &rlim); err == nil && rlim.Cur <= 1<<62 {
+		limit = int64(rlim.Cur)
+	}
+	return open, limit
+}
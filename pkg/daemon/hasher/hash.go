@@ -0,0 +1,67 @@
+// Package hasher computes content hashes of files on a pool of workers,
+// both on request and for a background warmer that feeds it new large files
+// while the daemon is idle.
+package hasher
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// chunkSize is the read size between cancellation checks.
+const chunkSize = 1 << 20
+
+// ErrInterrupted is returned when hashing is abandoned because the
+// interrupt callback asked to yield.
+var ErrInterrupted = errors.New("hashing interrupted")
+
+// Sums are the hashes of a file's contents.
+type Sums struct {
+	SHA256 []byte
+	XXHash uint64 // xxhash64, much quicker to compare or recompute
+}
+
+// HashFile returns the SHA-256 and xxhash of a file's contents, computed in
+// a single read. If interrupt is non-nil it is polled between chunks;
+// returning true stops hashing with ErrInterrupted.
+func HashFile(ctx context.Context, path string, interrupt func() bool) (Sums, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Sums{}, 0, fmt.Errorf("failed to open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	x := xxhash.New()
+	buf := make([]byte, chunkSize)
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return Sums{}, total, err
+		}
+		if interrupt != nil && interrupt() {
+			return Sums{}, total, ErrInterrupted
+		}
+
+		n, err := f.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			x.Write(buf[:n])
+			total += int64(n)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Sums{}, total, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+	}
+
+	return Sums{SHA256: h.Sum(nil), XXHash: x.Sum64()}, total, nil
+}
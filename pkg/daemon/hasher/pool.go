@@ -0,0 +1,129 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// DefaultWorkers is the default number of files a Pool hashes at once:
+// enough to keep an SSD busy without starving the rest of the system.
+var DefaultWorkers = min(4, runtime.NumCPU())
+
+// ErrChanged is returned for a file that was modified while being hashed.
+var ErrChanged = errors.New("file changed while being hashed")
+
+// Result is the outcome of hashing one file.
+type Result struct {
+	Path   string
+	Size   int64
+	Sums   Sums
+	Cached bool // The sums came from the store without reading the file
+	Err    error
+}
+
+// Pool hashes files with a fixed number of workers shared by all requests
+// and the warmer, caching the sums in the store so that later requests
+// don't read the files again.
+type Pool struct {
+	store store.StorageBackend
+	sem   chan struct{}
+}
+
+// NewPool creates a pool that hashes up to workers files at once (0 = use
+// DefaultWorkers) and caches their sums in s.
+func NewPool(s store.StorageBackend, workers int) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &Pool{store: s, sem: make(chan struct{}, workers)}
+}
+
+// Hash hashes paths and calls fn with each result as it completes. Calls
+// to fn are serialized. Files still waiting for a worker when ctx is
+// canceled are skipped; the context's error is returned.
+func (p *Pool) Hash(ctx context.Context, paths []string, fn func(Result)) error {
+	results := make(chan Result)
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+		for _, path := range paths {
+			select {
+			case p.sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := p.hashOne(ctx, path, nil)
+				<-p.sem
+				results <- r
+			}()
+		}
+	}()
+
+	for r := range results {
+		fn(r)
+	}
+	return ctx.Err()
+}
+
+// hashOnWorker hashes one file as hashOne does once a worker is free.
+func (p *Pool) hashOnWorker(ctx context.Context, path string, interrupt func() bool) Result {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return Result{Path: path, Err: ctx.Err()}
+	}
+	defer func() { <-p.sem }()
+	return p.hashOne(ctx, path, interrupt)
+}
+
+// hashOne returns the cached sums of a file, or hashes it and caches them.
+// interrupt is passed to HashFile.
+func (p *Pool) hashOne(ctx context.Context, path string, interrupt func() bool) Result {
+	r := Result{Path: path}
+	info, err := os.Stat(path)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if !info.Mode().IsRegular() {
+		r.Err = fmt.Errorf("%s: not a regular file", path)
+		return r
+	}
+	size, modTime := info.Size(), info.ModTime().Unix()
+	r.Size = size
+
+	// Hashes cached before xxhash was computed are recomputed
+	if e, ok := p.store.GetHashes(path, size, modTime); ok && e.XXHash != 0 {
+		r.Sums, r.Cached = Sums{SHA256: e.Sum, XXHash: e.XXHash}, true
+		return r
+	}
+
+	sums, _, err := HashFile(ctx, path, interrupt)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	if after, err := os.Stat(path); err != nil || after.Size() != size || after.ModTime().Unix() != modTime {
+		r.Err = fmt.Errorf("%s: %w", path, ErrChanged)
+		return r
+	}
+	r.Sums = sums
+
+	entry := store.HashEntry{Path: path, Size: size, ModTime: modTime, Sum: sums.SHA256, XXHash: sums.XXHash}
+	if err := p.store.PutHashes(entry); err != nil {
+		r.Err = fmt.Errorf("failed to cache hashes: %w", err)
+	}
+	return r
+}
@@ -0,0 +1,108 @@
+package hasher
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestPoolHashesAndCaches(t *testing.T) {
+	s := openStore(t)
+	dir := t.TempDir()
+	data := []byte("pooled contents")
+	var paths []string
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	missing := filepath.Join(dir, "missing.bin")
+
+	p := NewPool(s, 2)
+	hash := func(paths ...string) map[string]Result {
+		results := make(map[string]Result)
+		if err := p.Hash(context.Background(), paths, func(r Result) { results[r.Path] = r }); err != nil {
+			t.Fatalf("Hash failed: %v", err)
+		}
+		return results
+	}
+
+	results := hash(append(paths, missing)...)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	if results[missing].Err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	want := sha256.Sum256(data)
+	for _, path := range paths {
+		r := results[path]
+		if r.Err != nil || r.Cached {
+			t.Fatalf("%s: Err = %v, Cached = %v", path, r.Err, r.Cached)
+		}
+		if string(r.Sums.SHA256) != string(want[:]) || r.Sums.XXHash != xxhash.Sum64(data) || r.Size != int64(len(data)) {
+			t.Errorf("%s: got %+v", path, r)
+		}
+	}
+
+	for _, path := range paths {
+		info, _ := os.Stat(path)
+		if e, ok := s.GetHashes(path, info.Size(), info.ModTime().Unix()); !ok || e.XXHash != xxhash.Sum64(data) {
+			t.Errorf("%s: cached = %+v, %v", path, e, ok)
+		}
+	}
+
+	// A second request reads nothing
+	for _, r := range hash(paths...) {
+		if !r.Cached || r.Sums.XXHash != xxhash.Sum64(data) {
+			t.Errorf("%s: expected cached sums, got %+v", r.Path, r)
+		}
+	}
+}
+
+func TestPoolRecomputesLegacyHashes(t *testing.T) {
+	s := openStore(t)
+	path := filepath.Join(t.TempDir(), "old.bin")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	sum := sha256.Sum256([]byte("old"))
+	if err := s.PutHashes(store.HashEntry{Path: path, Size: info.Size(), ModTime: info.ModTime().Unix(), Sum: sum[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got Result
+	if err := NewPool(s, 1).Hash(context.Background(), []string{path}, func(r Result) { got = r }); err != nil {
+		t.Fatal(err)
+	}
+	if got.Cached || got.Sums.XXHash != xxhash.Sum64([]byte("old")) {
+		t.Errorf("expected a hash cached without xxhash to be recomputed, got %+v", got)
+	}
+}
+
+func TestPoolCanceled(t *testing.T) {
+	s := openStore(t)
+	path := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewPool(s, 1).Hash(ctx, []string{path, path, path}, func(r Result) {
+		if r.Err == nil {
+			t.Errorf("expected nothing hashed after cancellation, got %+v", r)
+		}
+	})
+	if err == nil {
+		t.Error("expected the context's error")
+	}
+}
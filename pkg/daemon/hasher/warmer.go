@@ -0,0 +1,200 @@
+package hasher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// Default warmer settings.
+const (
+	// DefaultIdleDelay is how long the daemon must be quiet before hashing.
+	DefaultIdleDelay = 30 * time.Second
+
+	// pollInterval is how often the warmer re-checks for idleness.
+	pollInterval = time.Second
+)
+
+// Stats reports warmer progress.
+type Stats struct {
+	Active      bool   // Currently hashing
+	Queued      int    // Files waiting to be hashed
+	Hashed      int64  // Files hashed since start
+	BytesHashed int64  // Bytes hashed since start
+	Current     string // File being hashed, if any
+}
+
+// Warmer feeds queued large files to a Pool in the background whenever the
+// daemon has been idle for IdleDelay, so later requests can use cached
+// hashes. Any activity reported with Touch pauses hashing; an interrupted
+// file is re-queued.
+type Warmer struct {
+	pool *Pool
+
+	// IdleDelay is the quiet period required before hashing starts.
+	IdleDelay time.Duration
+
+	// Busy, if set, reports additional work (e.g., indexing) that should
+	// keep the warmer paused.
+	Busy func() bool
+
+	mu           sync.Mutex
+	queue        []string
+	queued       map[string]bool
+	lastActivity time.Time
+	stats        Stats
+	wake         chan struct{}
+}
+
+// NewWarmer creates a warmer that hashes files on pool, caching them in
+// the pool's store.
+func NewWarmer(pool *Pool) *Warmer {
+	return &Warmer{
+		pool:         pool,
+		IdleDelay:    DefaultIdleDelay,
+		queued:       make(map[string]bool),
+		lastActivity: time.Now(),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules files for hashing. Enqueuing counts as activity.
+func (w *Warmer) Enqueue(paths ...string) {
+	w.mu.Lock()
+	for _, p := range paths {
+		if !w.queued[p] {
+			w.queued[p] = true
+			w.queue = append(w.queue, p)
+		}
+	}
+	w.lastActivity = time.Now()
+	w.stats.Queued = len(w.queue)
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Forget removes a file from the queue and drops its cached hash.
+func (w *Warmer) Forget(path string) {
+	w.mu.Lock()
+	if w.queued[path] {
+		delete(w.queued, path)
+		for i, p := range w.queue {
+			if p == path {
+				w.queue = append(w.queue[:i], w.queue[i+1:]...)
+				break
+			}
+		}
+		w.stats.Queued = len(w.queue)
+	}
+	w.mu.Unlock()
+
+	_ = w.pool.store.RemoveHash(path)
+}
+
+// Touch records daemon activity, postponing hashing.
+func (w *Warmer) Touch() {
+	w.mu.Lock()
+	w.lastActivity = time.Now()
+	w.mu.Unlock()
+}
+
+// Stats returns a snapshot of warmer progress.
+func (w *Warmer) Stats() Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// idle reports whether the daemon has been quiet long enough to hash.
+func (w *Warmer) idle() bool {
+	if w.Busy != nil && w.Busy() {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return time.Since(w.lastActivity) >= w.IdleDelay
+}
+
+// next pops the next queued file, if any.
+func (w *Warmer) next() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.queue) == 0 {
+		return "", false
+	}
+	path := w.queue[0]
+	w.queue = w.queue[1:]
+	delete(w.queued, path)
+	w.stats.Queued = len(w.queue)
+	return path, true
+}
+
+// Run processes the queue until ctx is canceled.
+func (w *Warmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+
+		for w.idle() {
+			path, ok := w.next()
+			if !ok {
+				break
+			}
+			if err := w.hashOne(ctx, path); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if errors.Is(err, ErrInterrupted) {
+					w.Enqueue(path)
+					break
+				}
+				logging.Get("hasher").Debug("failed to hash file", "path", path, "error", err)
+			}
+		}
+	}
+}
+
+// hashOne hashes a single file on the pool unless it has valid cached
+// hashes.
+func (w *Warmer) hashOne(ctx context.Context, path string) error {
+	w.mu.Lock()
+	w.stats.Active = true
+	w.stats.Current = path
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.stats.Active = false
+		w.stats.Current = ""
+		w.mu.Unlock()
+	}()
+
+	r := w.pool.hashOnWorker(ctx, path, func() bool { return !w.idle() })
+	// Files that changed while being read will be re-queued by the watcher
+	// when the writer is done
+	if r.Cached || errors.Is(r.Err, ErrChanged) {
+		return nil
+	}
+	if r.Err != nil {
+		return r.Err
+	}
+
+	w.mu.Lock()
+	w.stats.Hashed++
+	w.stats.BytesHashed += r.Size
+	w.mu.Unlock()
+	return nil
+}
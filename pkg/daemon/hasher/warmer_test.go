@@ -0,0 +1,141 @@
+package hasher
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func openStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.Open failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.bin")
+	data := []byte("hello, sweep")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sums, n, err := HashFile(context.Background(), path, nil)
+	if err != nil {
+		t.Fatalf("HashFile failed: %v", err)
+	}
+	want := sha256.Sum256(data)
+	if string(sums.SHA256) != string(want[:]) || n != int64(len(data)) {
+		t.Errorf("HashFile = %x (%d bytes), want %x (%d bytes)", sums.SHA256, n, want, len(data))
+	}
+	if sums.XXHash != xxhash.Sum64(data) {
+		t.Errorf("HashFile xxhash = %x, want %x", sums.XXHash, xxhash.Sum64(data))
+	}
+
+	if _, _, err := HashFile(context.Background(), path, func() bool { return true }); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted, got %v", err)
+	}
+}
+
+func TestWarmerHashesQueuedFiles(t *testing.T) {
+	s := openStore(t)
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.bin")
+	b := filepath.Join(dir, "b.bin")
+	_ = os.WriteFile(a, []byte("same"), 0o644)
+	_ = os.WriteFile(b, []byte("same"), 0o644)
+
+	pool := NewPool(s, 1)
+	w := NewWarmer(pool)
+	w.IdleDelay = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	w.Enqueue(a, b, a)
+	waitFor(t, func() bool { return w.Stats().Hashed == 2 })
+
+	if st := w.Stats(); st.Queued != 0 || st.BytesHashed != 8 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	// The pool serves what the warmer hashed from the cache
+	_ = pool.Hash(ctx, []string{a, b}, func(r Result) {
+		if !r.Cached || r.Sums.XXHash == 0 {
+			t.Errorf("%s: got %+v, want both sums from the cache", r.Path, r)
+		}
+	})
+
+	// Already-hashed files are not hashed again.
+	w.Enqueue(a)
+	waitFor(t, func() bool { return w.Stats().Queued == 0 })
+	if w.Stats().Hashed != 2 {
+		t.Errorf("Hashed = %d, want 2 (cached)", w.Stats().Hashed)
+	}
+}
+
+func TestWarmerWaitsWhileBusy(t *testing.T) {
+	s := openStore(t)
+	path := filepath.Join(t.TempDir(), "a.bin")
+	_ = os.WriteFile(path, []byte("data"), 0o644)
+
+	var busy atomic.Bool
+	busy.Store(true)
+
+	w := NewWarmer(NewPool(s, 1))
+	w.IdleDelay = 0
+	w.Busy = busy.Load
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	w.Enqueue(path)
+	time.Sleep(50 * time.Millisecond)
+	if st := w.Stats(); st.Hashed != 0 || st.Queued != 1 {
+		t.Fatalf("expected no hashing while busy, got %+v", st)
+	}
+
+	busy.Store(false)
+	w.Enqueue() // wake the warmer
+	waitFor(t, func() bool { return w.Stats().Hashed == 1 })
+}
+
+func TestWarmerForget(t *testing.T) {
+	s := openStore(t)
+	w := NewWarmer(NewPool(s, 1))
+	_ = s.PutHashes(store.HashEntry{Path: "/data/a.bin", Size: 1, ModTime: 1, Sum: []byte("sum")})
+
+	w.Enqueue("/data/a.bin", "/data/b.bin")
+	w.Forget("/data/a.bin")
+
+	if st := w.Stats(); st.Queued != 1 {
+		t.Errorf("Queued = %d, want 1", st.Queued)
+	}
+	if _, ok := s.GetHashes("/data/a.bin", 1, 1); ok {
+		t.Error("expected Forget to drop the cached hash")
+	}
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/hasher"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// ComputeHashes hashes files on the service's worker pool and streams the
+// SHA-256 and xxhash of each as it completes, in no particular order. Sums
+// cached in the store are sent without reading the file; the rest are
+// cached as they are computed, for later requests.
+func (s *Service) ComputeHashes(req *sweepv1.ComputeHashesRequest, stream grpc.ServerStreamingServer[sweepv1.FileHash]) error {
+	paths := req.GetPaths()
+	if len(paths) == 0 {
+		return status.Error(codes.InvalidArgument, "no paths to hash")
+	}
+	// Reading files is activity the warmer yields to
+	if s.warmer != nil {
+		s.warmer.Touch()
+	}
+
+	total := int32(len(paths))
+	var current int32
+	var hashed, cached int
+	// send reports one file. Calls are serialized: the pool serializes its
+	// callbacks and the checks below run before it starts.
+	var sendErr error
+	send := func(r hasher.Result) {
+		current++
+		msg := &sweepv1.FileHash{
+			Path:    r.Path,
+			Size:    r.Size,
+			Cached:  r.Cached,
+			Current: current,
+			Total:   total,
+		}
+		if r.Err != nil {
+			msg.Error = r.Err.Error()
+		} else {
+			msg.Sha256, msg.Xxhash = r.Sums.SHA256, r.Sums.XXHash
+			hashed++
+			if r.Cached {
+				cached++
+			}
+		}
+		if sendErr == nil {
+			sendErr = stream.Send(msg)
+		}
+	}
+
+	var valid []string
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			send(hasher.Result{Path: path, Err: fmt.Errorf("%s: path must be absolute", path)})
+			continue
+		}
+		valid = append(valid, path)
+	}
+
+	if err := s.hashes.Hash(stream.Context(), valid, send); err != nil && sendErr == nil {
+		return status.FromContextError(err).Err()
+	}
+	logging.Get("daemon").Info("computed hashes", "requested", total, "hashed", hashed, "cached", cached)
+	return sendErr
+}
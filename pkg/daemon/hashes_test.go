@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// mockHashStream implements grpc.ServerStreamingServer[sweepv1.FileHash] for testing.
+type mockHashStream struct {
+	grpc.ServerStream
+	hashes []*sweepv1.FileHash
+}
+
+func (m *mockHashStream) Send(h *sweepv1.FileHash) error {
+	m.hashes = append(m.hashes, h)
+	return nil
+}
+
+func (m *mockHashStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestServiceComputeHashes(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	root := t.TempDir()
+	a, b := filepath.Join(root, "a.iso"), filepath.Join(root, "b.iso")
+	require.NoError(t, os.WriteFile(a, []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(b, []byte("same"), 0o644))
+	missing := filepath.Join(root, "missing.iso")
+
+	stream := &mockHashStream{}
+	err = svc.ComputeHashes(&sweepv1.ComputeHashesRequest{Paths: []string{a, b, missing, "relative.iso"}}, stream)
+	require.NoError(t, err)
+
+	require.Len(t, stream.hashes, 4)
+	byPath := make(map[string]*sweepv1.FileHash)
+	for i, h := range stream.hashes {
+		assert.Equal(t, int32(i+1), h.GetCurrent())
+		assert.Equal(t, int32(4), h.GetTotal())
+		byPath[h.GetPath()] = h
+	}
+	want := sha256.Sum256([]byte("same"))
+	assert.Equal(t, want[:], byPath[a].GetSha256())
+	assert.NotZero(t, byPath[a].GetXxhash())
+	assert.Equal(t, byPath[a].GetXxhash(), byPath[b].GetXxhash())
+	assert.Equal(t, int64(4), byPath[a].GetSize())
+	assert.False(t, byPath[a].GetCached())
+	assert.NotEmpty(t, byPath[missing].GetError())
+	assert.Contains(t, byPath["relative.iso"].GetError(), "must be absolute")
+
+	info, err := os.Stat(a)
+	require.NoError(t, err)
+	e, ok := st.GetHashes(a, info.Size(), info.ModTime().Unix())
+	assert.True(t, ok, "the hashes should be cached")
+	assert.Equal(t, want[:], e.Sum)
+
+	stream = &mockHashStream{}
+	require.NoError(t, svc.ComputeHashes(&sweepv1.ComputeHashesRequest{Paths: []string{a}}, stream))
+	require.Len(t, stream.hashes, 1)
+	assert.True(t, stream.hashes[0].GetCached())
+}
+
+func TestServiceComputeHashesNoPaths(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+
+	err = NewService(st).ComputeHashes(&sweepv1.ComputeHashesRequest{}, &mockHashStream{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/sweep/hook"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Hook POSTs to a webhook or runs a command for each file event under a
+// path that matches its filter.
+type Hook struct {
+	Name    string                  // Display name; defaults to Path
+	Path    string                  // Absolute path events must be under
+	Events  []broadcaster.EventType // Event types to run for; empty means DefaultHookEvents
+	MinSize int64                   // Smallest file to run for (0 = the large file threshold); deletions and renames, whose size is unknown, always match
+	Exclude []string                // File name globs to ignore
+	Webhook string                  // URL the event is POSTed to as JSON
+	Exec    string                  // Shell command run with the event in its environment and, as JSON, on its standard input
+}
+
+// DefaultHookEvents are the event types hooks run for unless configured
+// otherwise. Deletions and renames carry no size, so they would run a hook
+// for every small file too.
+var DefaultHookEvents = []broadcaster.EventType{broadcaster.EventCreated, broadcaster.EventModified}
+
+// DisplayName returns the hook name, falling back to its path.
+func (h Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Path
+}
+
+// hookPayload is the JSON a hook sends for an event. Text makes it a
+// message Slack's incoming webhooks accept as is.
+type hookPayload struct {
+	Hook    string    `json:"hook"`
+	Event   string    `json:"event"`
+	Path    string    `json:"path"`
+	NewPath string    `json:"new_path,omitempty"`
+	Size    int64     `json:"size"`
+	Time    time.Time `json:"time"`
+	Text    string    `json:"text"`
+}
+
+// newHookPayload describes event for h.
+func newHookPayload(h Hook, event *broadcaster.FileEvent, now time.Time) hookPayload {
+	p := hookPayload{
+		Hook:    h.DisplayName(),
+		Event:   event.Type.String(),
+		Path:    event.Path,
+		NewPath: event.NewPath,
+		Size:    event.Size,
+		Time:    now,
+	}
+	switch event.Type {
+	case broadcaster.EventCreated:
+		p.Text = fmt.Sprintf("A %s file appeared: %s", types.FormatSize(event.Size), event.Path)
+	case broadcaster.EventModified:
+		p.Text = fmt.Sprintf("A file changed, now %s: %s", types.FormatSize(event.Size), event.Path)
+	case broadcaster.EventDeleted:
+		p.Text = "A file was deleted: " + event.Path
+	default:
+		p.Text = "A file was moved: " + event.Path
+		if event.NewPath != "" {
+			p.Text += " → " + event.NewPath
+		}
+	}
+	p.Text = "[" + p.Hook + "] " + p.Text
+	return p
+}
+
+// runHooks subscribes each hook to file events under its path and runs it
+// for those it matches until ctx is canceled. Hooks without a size run for
+// files of at least minSize.
+func (s *Server) runHooks(ctx context.Context, hooks []Hook, minSize int64) {
+	for _, h := range hooks {
+		if h.MinSize <= 0 {
+			h.MinSize = minSize
+		}
+		if len(h.Events) == 0 {
+			h.Events = DefaultHookEvents
+		}
+		sub := s.broadcaster.Subscribe(h.Path, h.MinSize, h.Exclude)
+		if sub == nil {
+			return
+		}
+		go func() {
+			defer s.broadcaster.Unsubscribe(sub.ID)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event, ok := <-sub.Events:
+					if !ok {
+						return
+					}
+					s.service.runHook(ctx, h, event)
+				}
+			}
+		}()
+	}
+}
+
+// runHook sends event to h's webhook and command if it is one of h's event
+// types. Events arriving while a slow hook runs wait for it, and are
+// dropped once the subscription's buffer is full.
+func (s *Service) runHook(ctx context.Context, h Hook, event *broadcaster.FileEvent) {
+	if !slices.Contains(h.Events, event.Type) {
+		return
+	}
+	payload := newHookPayload(h, event, time.Now())
+
+	var errs []error
+	if h.Webhook != "" {
+		if err := hook.Post(ctx, h.Webhook, payload); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if h.Exec != "" {
+		env := []string{
+			"SWEEP_HOOK=" + payload.Hook,
+			"SWEEP_EVENT=" + payload.Event,
+			"SWEEP_PATH=" + payload.Path,
+			"SWEEP_NEW_PATH=" + payload.NewPath,
+			"SWEEP_SIZE=" + strconv.FormatInt(payload.Size, 10),
+		}
+		if err := hook.Exec(ctx, h.Exec, env, payload); err != nil {
+			errs = append(errs, fmt.Errorf("exec: %w", err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil && ctx.Err() == nil {
+		logging.Get("daemon").Warn("file event hook failed", "hook", payload.Hook, "path", event.Path, "error", err)
+		s.errors.add("hook", event.Path, err)
+	}
+}
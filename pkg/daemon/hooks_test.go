@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestRunHooks(t *testing.T) {
+	received := make(chan hookPayload, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var p hookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		received <- p
+	}))
+	defer srv.Close()
+
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	b := broadcaster.New()
+	defer b.Close()
+	s := &Server{broadcaster: b, service: NewServiceWithBroadcaster(st, b)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.runHooks(ctx, []Hook{{
+		Name:    "uploads",
+		Path:    "/srv/uploads",
+		Exclude: []string{"*.part"},
+		Webhook: srv.URL,
+	}}, 5*types.GiB)
+
+	b.Notify("/srv/uploads/small.mkv", broadcaster.EventCreated, types.GiB)
+	b.Notify("/srv/uploads/big.mkv.part", broadcaster.EventCreated, 6*types.GiB)
+	b.Notify("/srv/other/big.mkv", broadcaster.EventCreated, 6*types.GiB)
+	b.Notify("/srv/uploads/old.mkv", broadcaster.EventDeleted, 0)
+	b.Notify("/srv/uploads/big.mkv", broadcaster.EventCreated, 6*types.GiB)
+
+	select {
+	case p := <-received:
+		assert.Equal(t, "uploads", p.Hook)
+		assert.Equal(t, "created", p.Event)
+		assert.Equal(t, "/srv/uploads/big.mkv", p.Path)
+		assert.Equal(t, 6*types.GiB, p.Size)
+		assert.Equal(t, "[uploads] A 6.0 GiB file appeared: /srv/uploads/big.mkv", p.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the hook didn't run")
+	}
+	select {
+	case p := <-received:
+		t.Errorf("expected only the large created file, also got %+v", p)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRunHookRecordsFailures(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	svc.runHook(context.Background(), Hook{Path: "/srv", Events: DefaultHookEvents, Webhook: "http://127.0.0.1:1/unreachable"},
+		&broadcaster.FileEvent{Type: broadcaster.EventCreated, Path: "/srv/a.iso", Size: 10})
+	recent := svc.errors.recent()
+	require.Len(t, recent, 1)
+	assert.Equal(t, "hook", recent[0].GetComponent())
+	assert.Equal(t, "/srv/a.iso", recent[0].GetPath())
+}
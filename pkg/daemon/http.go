@@ -0,0 +1,422 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// ErrHTTPTokenRequired is returned when the HTTP API would be reachable from
+// other machines without a token.
+var ErrHTTPTokenRequired = errors.New("serving HTTP on a non-loopback address requires http.token")
+
+// ssePingInterval keeps idle event streams open through proxies that drop
+// silent connections.
+const ssePingInterval = 30 * time.Second
+
+// httpFile is a large file in the HTTP API.
+type httpFile struct {
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	ModTime   int64  `json:"mod_time"`                  // Unix seconds
+	Truncated bool   `json:"truncated,omitempty"`       // On the last file when the limit cut results short
+	NextPage  string `json:"next_page_token,omitempty"` // On the last file of a page with more after it
+}
+
+// httpEvent is a watch event in the HTTP API.
+type httpEvent struct {
+	Type    string `json:"type"` // created, modified, deleted, or renamed
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mod_time"` // Unix seconds
+}
+
+// httpAPI serves the service's streaming RPCs as newline-delimited JSON or
+// server-sent events, so browser dashboards can use the daemon without a
+// gRPC-web proxy.
+type httpAPI struct {
+	svc     *Service
+	token   string
+	origins []string
+}
+
+// newHTTPHandler returns the handler for the HTTP API.
+func newHTTPHandler(svc *Service, token string, origins []string) http.Handler {
+	api := &httpAPI{svc: svc, token: token, origins: origins}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/large-files", api.largeFiles)
+	mux.HandleFunc("GET /v1/watch", api.watch)
+	return api.guard(mux)
+}
+
+// checkHTTPAddr rejects listening beyond loopback without a token.
+func checkHTTPAddr(addr, token string) error {
+	if token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid http.listen %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return ErrHTTPTokenRequired
+}
+
+// guard answers CORS preflight requests and checks the token.
+func (a *httpAPI) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && a.allowOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "GET")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		if a.token != "" {
+			// EventSource can't set headers, so the token may be a parameter
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if got == "" {
+				got = r.URL.Query().Get("token")
+			}
+			if subtle.ConstantTimeCompare([]byte(got), []byte(a.token)) != 1 {
+				writeHTTPError(w, http.StatusUnauthorized, "invalid or missing token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowOrigin reports whether a browser origin may call the API.
+func (a *httpAPI) allowOrigin(origin string) bool {
+	return slices.Contains(a.origins, "*") || slices.Contains(a.origins, origin)
+}
+
+// largeFiles streams the large files under a path, like GetLargeFiles.
+// Server-sent event streams end with a "done" event, so EventSource
+// clients know not to reconnect.
+func (a *httpAPI) largeFiles(w http.ResponseWriter, r *http.Request) {
+	req, err := a.largeFilesRequest(r)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ew := newEventWriter(w, r)
+	stream := &httpStream[sweepv1.FileInfo]{ctx: r.Context(), send: func(f *sweepv1.FileInfo) error {
+		return ew.write("file", httpFile{Path: f.GetPath(), Size: f.GetSize(), ModTime: f.GetModTime(), Truncated: f.GetTruncated(), NextPage: f.GetNextPageToken()})
+	}}
+	if err := a.svc.GetLargeFiles(req, stream); err != nil {
+		ew.fail(err)
+		return
+	}
+	ew.done()
+}
+
+// largeFilesRequest builds a GetLargeFilesRequest from query parameters
+// named after the command-line flags.
+func (a *httpAPI) largeFilesRequest(r *http.Request) (*sweepv1.GetLargeFilesRequest, error) {
+	q := r.URL.Query()
+	req := &sweepv1.GetLargeFilesRequest{
+		Path:       q.Get("path"),
+		Include:    listParam(q["include"]),
+		Exclude:    listParam(q["exclude"]),
+		Extensions: listParam(q["ext"]),
+		TypeGroups: listParam(q["type"]),
+		PageToken:  q.Get("page_token"),
+	}
+	if req.Path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	var err error
+	if req.MinSize, err = a.minSize(q); err != nil {
+		return nil, err
+	}
+	for name, dst := range map[string]*int64{"older_than": &req.OlderThanSeconds, "newer_than": &req.NewerThanSeconds} {
+		if v := q.Get(name); v != "" {
+			d, err := filter.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", name, v, err)
+			}
+			*dst = int64(d / time.Second)
+		}
+	}
+	for name, dst := range map[string]*int32{"limit": &req.Limit, "max_depth": &req.MaxDepth} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = int32(n)
+		}
+	}
+
+	sortBy := filter.SortSize
+	if v := q.Get("sort"); v != "" {
+		if sortBy, err = filter.ParseSortField(v); err != nil {
+			return nil, err
+		}
+	}
+	reverse := q.Get("reverse") == "true" || q.Get("reverse") == "1"
+	switch sortBy {
+	case filter.SortAge:
+		req.SortBy = sweepv1.SortField_SORT_MOD_TIME
+	case filter.SortPath:
+		req.SortBy = sweepv1.SortField_SORT_PATH
+	default:
+		req.SortBy = sweepv1.SortField_SORT_SIZE
+	}
+	// Like --reverse: largest and oldest first unless reversed, paths A-Z
+	req.SortDescending = !reverse
+	if sortBy == filter.SortPath {
+		req.SortDescending = reverse
+	}
+	return req, nil
+}
+
+// watch streams changes to large files under a path, like WatchLargeFiles,
+// until the client disconnects.
+func (a *httpAPI) watch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &sweepv1.WatchRequest{
+		Root:    q.Get("path"),
+		Exclude: listParam(q["exclude"]),
+	}
+	var err error
+	if req.MinSize, err = a.minSize(q); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if a.svc.broadcaster == nil {
+		writeHTTPError(w, http.StatusServiceUnavailable, "file watching not available")
+		return
+	}
+
+	ew := newEventWriter(w, r)
+	if err := ew.open(); err != nil {
+		return
+	}
+	stopPing := ew.keepAlive(ssePingInterval)
+	defer stopPing()
+
+	stream := &httpStream[sweepv1.FileEvent]{ctx: r.Context(), send: func(e *sweepv1.FileEvent) error {
+		typ := strings.ToLower(e.GetType().String())
+		return ew.write(typ, httpEvent{Type: typ, Path: e.GetPath(), Size: e.GetSize(), ModTime: e.GetModTime()})
+	}}
+	if err := a.svc.WatchLargeFiles(req, stream); err != nil {
+		ew.fail(err)
+	}
+}
+
+// minSize parses the min_size parameter, defaulting to the index threshold.
+func (a *httpAPI) minSize(q url.Values) (int64, error) {
+	v := q.Get("min_size")
+	if v == "" {
+		return a.svc.minIndexSize(), nil
+	}
+	size, err := types.ParseSize(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid min_size %q: %w", v, err)
+	}
+	return size, nil
+}
+
+// listParam splits repeated and comma-separated query values.
+func listParam(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// eventWriter writes records as newline-delimited JSON, or as server-sent
+// events when the client asks for text/event-stream, flushing each one.
+// It is safe for concurrent use.
+type eventWriter struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	sse bool
+
+	mu     sync.Mutex
+	opened bool
+	count  int
+}
+
+func newEventWriter(w http.ResponseWriter, r *http.Request) *eventWriter {
+	sse := r.URL.Query().Get("format") == "sse" ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	return &eventWriter{w: w, rc: http.NewResponseController(w), sse: sse}
+}
+
+// open sends the response headers, if not sent yet.
+func (e *eventWriter) open() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.openLocked()
+}
+
+func (e *eventWriter) openLocked() error {
+	if e.opened {
+		return nil
+	}
+	e.opened = true
+	if e.sse {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	e.w.Header().Set("Cache-Control", "no-cache")
+	e.w.WriteHeader(http.StatusOK)
+	return e.rc.Flush()
+}
+
+// write sends one record; event names the server-sent event.
+func (e *eventWriter) write(event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.openLocked(); err != nil {
+		return err
+	}
+	if e.sse {
+		_, err = fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", event, data)
+	} else {
+		_, err = fmt.Fprintf(e.w, "%s\n", data)
+	}
+	if err != nil {
+		return err
+	}
+	e.count++
+	return e.rc.Flush()
+}
+
+// done ends a finite stream; server-sent events get a final "done" event
+// with the number of records.
+func (e *eventWriter) done() {
+	if e.sse {
+		e.mu.Lock()
+		count := e.count
+		e.mu.Unlock()
+		_ = e.write("done", map[string]int{"count": count})
+		return
+	}
+	_ = e.open()
+}
+
+// fail reports an error: as an HTTP status before anything was sent, and
+// as an "error" record after.
+func (e *eventWriter) fail(err error) {
+	e.mu.Lock()
+	started := e.opened
+	e.mu.Unlock()
+	msg := status.Convert(err).Message()
+	if !started {
+		writeHTTPError(e.w, httpStatus(err), msg)
+		return
+	}
+	_ = e.write("error", map[string]string{"error": msg})
+}
+
+// keepAlive sends server-sent event comments until stopped.
+func (e *eventWriter) keepAlive(interval time.Duration) (stop func()) {
+	if !e.sse {
+		return func() {}
+	}
+	ticker := time.NewTicker(interval)
+	quit := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				e.mu.Lock()
+				if _, err := fmt.Fprint(e.w, ": ping\n\n"); err == nil {
+					_ = e.rc.Flush()
+				}
+				e.mu.Unlock()
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(quit)
+	}
+}
+
+// httpStatus maps a service error to an HTTP status.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeHTTPError sends an error as a JSON object.
+func writeHTTPError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// httpStream adapts an HTTP response to the server side of a streaming RPC,
+// so the HTTP API reuses the service's handlers. Only Send and Context are
+// used by them.
+type httpStream[T any] struct {
+	ctx  context.Context
+	send func(*T) error
+}
+
+func (s *httpStream[T]) Send(m *T) error              { return s.send(m) }
+func (s *httpStream[T]) Context() context.Context     { return s.ctx }
+func (s *httpStream[T]) SetHeader(metadata.MD) error  { return nil }
+func (s *httpStream[T]) SendHeader(metadata.MD) error { return nil }
+func (s *httpStream[T]) SetTrailer(metadata.MD)       {}
+func (s *httpStream[T]) SendMsg(any) error            { return errors.New("not supported over HTTP") }
+func (s *httpStream[T]) RecvMsg(any) error            { return errors.New("not supported over HTTP") }
@@ -0,0 +1,205 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+)
+
+// startHTTPServer runs a daemon serving the HTTP API with a directory of
+// one small and two large files indexed.
+func startHTTPServer(t *testing.T, token string) (baseURL, root string) {
+	t.Helper()
+	root = t.TempDir()
+	for name, size := range map[string]int{"small.txt": 100, "large.txt": 10000, "huge.dat": 100000} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), make([]byte, size), 0o644))
+	}
+
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{
+		SocketPath:       filepath.Join(tmpDir, "test.sock"),
+		DataDir:          filepath.Join(tmpDir, "data"),
+		MinLargeFileSize: 5000,
+		HTTPAddr:         "127.0.0.1:0",
+		HTTPToken:        token,
+	})
+	require.NoError(t, err)
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	_, err = srv.service.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: root})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return srv.store.HasIndex(root) && !srv.service.isIndexing() },
+		5*time.Second, 20*time.Millisecond)
+	return "http://" + srv.HTTPAddr().String(), root
+}
+
+func get(t *testing.T, url string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestHTTPLargeFiles(t *testing.T) {
+	base, root := startHTTPServer(t, "")
+
+	t.Run("newline-delimited JSON", func(t *testing.T) {
+		resp, body := get(t, base+"/v1/large-files?path="+root, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		require.Len(t, lines, 2)
+		var first httpFile
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, filepath.Join(root, "huge.dat"), first.Path)
+		assert.Equal(t, int64(100000), first.Size)
+		assert.NotZero(t, first.ModTime)
+	})
+
+	t.Run("server-sent events", func(t *testing.T) {
+		resp, body := get(t, base+"/v1/large-files?path="+root+"&sort=path",
+			http.Header{"Accept": {"text/event-stream"}})
+		require.Equal(t, http.StatusOK, resp.StatusCode, body)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		assert.Equal(t, 2, strings.Count(body, "event: file\n"))
+		assert.Less(t, strings.Index(body, "huge.dat"), strings.Index(body, "large.txt"), "sorted by path")
+		assert.True(t, strings.HasSuffix(body, "event: done\ndata: {\"count\":2}\n\n"), body)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		_, body := get(t, base+"/v1/large-files?path="+root+"&min_size=50KB", nil)
+		assert.Equal(t, 1, strings.Count(body, "\n"))
+		_, body = get(t, base+"/v1/large-files?path="+root+"&ext=.txt", nil)
+		assert.Contains(t, body, "large.txt")
+		assert.NotContains(t, body, "huge.dat")
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		for _, query := range []string{"", "?path=" + root + "&min_size=lots", "?path=" + root + "&sort=color"} {
+			resp, body := get(t, base+"/v1/large-files"+query, nil)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+			assert.Contains(t, body, `"error"`)
+		}
+	})
+}
+
+func TestHTTPToken(t *testing.T) {
+	base, root := startHTTPServer(t, "s3cret")
+	url := base + "/v1/large-files?path=" + root
+
+	resp, _ := get(t, url, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = get(t, url, http.Header{"Authorization": {"Bearer wrong"}})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = get(t, url, http.Header{"Authorization": {"Bearer s3cret"}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = get(t, url+"&token=s3cret", nil) // As EventSource sends it
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPListenRequiresToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	_, err := NewServer(Config{
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		DataDir:    filepath.Join(tmpDir, "data"),
+		HTTPAddr:   ":0",
+	})
+	assert.True(t, errors.Is(err, ErrHTTPTokenRequired), "got %v", err)
+
+	assert.NoError(t, checkHTTPAddr("localhost:7434", ""))
+	assert.NoError(t, checkHTTPAddr("[::1]:7434", ""))
+	assert.NoError(t, checkHTTPAddr("0.0.0.0:7434", "token"))
+}
+
+func TestHTTPCORS(t *testing.T) {
+	svc := &Service{indexStates: make(map[string]*indexState)}
+	ts := httptest.NewServer(newHTTPHandler(svc, "token", []string{"http://dash.local"}))
+	defer ts.Close()
+
+	// Preflight requests carry no token
+	req, err := http.NewRequest(http.MethodOptions, ts.URL+"/v1/watch", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://dash.local")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "http://dash.local", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Authorization")
+
+	resp, _ = get(t, ts.URL+"/v1/watch", http.Header{"Origin": {"http://evil.local"}})
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestHTTPWatch(t *testing.T) {
+	b := broadcaster.New()
+	defer b.Close()
+	svc := &Service{broadcaster: b, indexStates: make(map[string]*indexState)}
+	ts := httptest.NewServer(newHTTPHandler(svc, "", nil))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/watch?path=/data&min_size=1KB&format=sse")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The headers arrive before the subscription; notify until it is live
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "stream ended early")
+			if line != "" {
+				got = append(got, line)
+			}
+		case <-ticker.C:
+			b.Notify("/data/small.txt", broadcaster.EventCreated, 10)  // Below min_size
+			b.Notify("/other/big.iso", broadcaster.EventCreated, 4096) // Outside the path
+			b.Notify("/data/big.iso", broadcaster.EventDeleted, 4096)
+		case <-timeout:
+			t.Fatalf("no event received, got %q", got)
+		}
+	}
+	assert.Equal(t, "event: deleted", got[0])
+	assert.Equal(t, `data: {"type":"deleted","path":"/data/big.iso","size":4096,"mod_time":0}`, got[1])
+}
@@ -0,0 +1,112 @@
+package indexer
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// Entries are written to the store in batches whose size adapts to how
+// long the store takes to commit them. Batches grow while commits are
+// quick, so a fast disk commits fewer, larger ones, and shrink when commits
+// slow down or memory runs short, so a small machine doesn't hold many
+// entries at once.
+const (
+	minBatchSize     = 100
+	maxBatchSize     = 50000
+	initialBatchSize = 1000
+
+	// batchCommitTarget is how long a batch should take to commit.
+	batchCommitTarget = 100 * time.Millisecond
+
+	// maxBatchBytes caps the memory the entries of a pending batch take,
+	// however many there are.
+	maxBatchBytes = 32 << 20
+
+	// entryOverhead is roughly the memory an entry takes besides its path.
+	entryOverhead = 160
+
+	// memoryPressure is the share of the memory limit (GOMEMLIMIT) the heap
+	// may use before batches shrink.
+	memoryPressure = 0.75
+)
+
+// batchSizer picks how many entries to write to the store at a time. It is
+// kept by the Indexer, so each index starts from the size the last one
+// settled on.
+type batchSizer struct {
+	mu   sync.Mutex
+	size int
+
+	// nearLimit reports whether the heap is close to the memory limit
+	nearLimit func() bool
+}
+
+func newBatchSizer() *batchSizer {
+	return &batchSizer{size: initialBatchSize, nearLimit: nearMemoryLimit}
+}
+
+// get returns the number of entries to write at a time.
+func (b *batchSizer) get() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
+}
+
+// observe adapts the size to a batch of n entries that took took to commit.
+// The size moves halfway toward the one that would commit in
+// batchCommitTarget, by at most double or half, so one slow commit doesn't
+// swing it. Batches much smaller than the size, like the last of an index,
+// say little about the store and are ignored.
+func (b *batchSizer) observe(n int, took time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.nearLimit != nil && b.nearLimit() {
+		b.size = max(b.size/2, minBatchSize)
+		return
+	}
+	if n < b.size/2 || took <= 0 {
+		return
+	}
+	ideal := int(min(int64(n)*int64(batchCommitTarget)/int64(took), 2*maxBatchSize))
+	next := (b.size + ideal) / 2
+	b.size = min(max(next, b.size/2, minBatchSize), b.size*2, maxBatchSize)
+}
+
+// nearMemoryLimit reports whether the heap uses more than memoryPressure of
+// the memory limit. Without a limit, it never is.
+func nearMemoryLimit() bool {
+	limit := debug.SetMemoryLimit(-1)
+	if limit == math.MaxInt64 {
+		return false
+	}
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return false
+	}
+	return float64(sample[0].Value.Uint64()) > memoryPressure*float64(limit)
+}
+
+// entryBytes estimates the memory an entry pending in a batch takes.
+func entryBytes(e *store.Entry) int64 {
+	return int64(len(e.Path) + entryOverhead)
+}
+
+// writeBatch writes entries with write, timing the commit to adapt the
+// batch size.
+func (idx *Indexer) writeBatch(entries []*store.Entry, write func([]*store.Entry) error) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	start := time.Now()
+	if err := write(entries); err != nil {
+		return err
+	}
+	idx.batches.observe(len(entries), time.Since(start))
+	return nil
+}
@@ -0,0 +1,54 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchSizer(t *testing.T) {
+	b := &batchSizer{size: initialBatchSize}
+
+	// Quick commits grow batches, at most doubling each time
+	b.observe(1000, time.Millisecond)
+	if got := b.get(); got != 2000 {
+		t.Errorf("after a quick commit, size = %d, want 2000", got)
+	}
+	for range 20 {
+		b.observe(b.get(), time.Millisecond)
+	}
+	if got := b.get(); got != maxBatchSize {
+		t.Errorf("after many quick commits, size = %d, want %d", got, maxBatchSize)
+	}
+
+	// Slow ones shrink them, at most halving each time
+	b.observe(maxBatchSize, 10*time.Second)
+	if got := b.get(); got < maxBatchSize/2 || got >= maxBatchSize {
+		t.Errorf("after a slow commit, size = %d, want at least %d", got, maxBatchSize/2)
+	}
+	for range 20 {
+		b.observe(b.get(), 10*time.Second)
+	}
+	if got := b.get(); got != minBatchSize {
+		t.Errorf("after many slow commits, size = %d, want %d", got, minBatchSize)
+	}
+
+	// Commits at the target keep the size
+	b.size = 4000
+	b.observe(4000, batchCommitTarget)
+	if got := b.get(); got != 4000 {
+		t.Errorf("after a commit on target, size = %d, want 4000", got)
+	}
+
+	// A small last batch says little about the store
+	b.observe(10, 10*time.Second)
+	if got := b.get(); got != 4000 {
+		t.Errorf("after a small batch, size = %d, want 4000", got)
+	}
+
+	// Near the memory limit, batches shrink however quick commits are
+	b.nearLimit = func() bool { return true }
+	b.observe(4000, time.Millisecond)
+	if got := b.get(); got != 2000 {
+		t.Errorf("near the memory limit, size = %d, want 2000", got)
+	}
+}
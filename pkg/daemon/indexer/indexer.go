@@ -4,14 +4,25 @@ package indexer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/charlievieth/fastwalk"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+	"github.com/jamesainslie/sweep/pkg/sweep/tuner"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
 // Progress reports indexing progress.
@@ -28,6 +39,7 @@ type Result struct {
 	DirsIndexed   int64
 	FilesIndexed  int64
 	TotalSize     int64
+	ActualSize    int64 // TotalSize with hard links and clones counted once
 	Duration      time.Duration
 	Cached        bool     // True if path was already covered by an indexed path
 	CoveredBy     string   // Parent path that covers this one (if Cached is true)
@@ -41,18 +53,73 @@ type ProgressFunc func(Progress)
 // Files >= this size are indexed for fast large file queries.
 const DefaultMinLargeFileSize = 10 * 1024 * 1024 // 10 MiB
 
+// Mode selects what the indexer stores.
+type Mode string
+
+const (
+	// ModeFull stores an entry for every file and directory.
+	ModeFull Mode = "full"
+
+	// ModeAggregates stores only directories, each with the total size and
+	// count of the files beneath it, plus the large files index. Small files
+	// are counted but not stored, which keeps the store small for users who
+	// only want directory usage and the largest files.
+	ModeAggregates Mode = "aggregates"
+)
+
+// ErrUnknownMode is returned by ParseMode for unsupported index modes.
+var ErrUnknownMode = errors.New("unknown index mode")
+
+// ParseMode parses an index mode; "" means ModeFull.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case "":
+		return ModeFull, nil
+	case ModeFull, ModeAggregates:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: full, aggregates)", ErrUnknownMode, s)
+	}
+}
+
 // Indexer indexes filesystem paths into the store.
 type Indexer struct {
-	store            *store.Store
-	MinLargeFileSize int64 // Threshold for large files index (default: DefaultMinLargeFileSize)
+	store            store.StorageBackend
+	MinLargeFileSize int64               // Threshold for large files index (default: DefaultMinLargeFileSize)
+	Mode             Mode                // What to store (default: ModeFull)
+	Symlinks         types.SymlinkPolicy // Which symlinked directories to follow (default: none)
+	Throttle         *Throttle           // Slows indexing down (default: none)
+	IncludeSnapshots bool                // Index filesystem snapshots too (default: skip them)
+	OneFileSystem    bool                // Stay on the root's file system, except at CrossMounts
+	SkipMounts       []string            // Mount points never indexed
+	CrossMounts      []string            // Mount points indexed even with OneFileSystem
+	Network          mounts.Network      // Which roots are on network file systems (default: none)
+	NetworkWorkers   int                 // Walkers indexing one (default: tuner.DefaultNetworkFileWorkers)
+
+	batches *batchSizer
 }
 
 // New creates a new indexer with default settings.
-func New(s *store.Store) *Indexer {
+func New(s store.StorageBackend) *Indexer {
 	return &Indexer{
 		store:            s,
 		MinLargeFileSize: DefaultMinLargeFileSize,
+		Mode:             ModeFull,
+		batches:          newBatchSizer(),
+	}
+}
+
+// aggregates reports whether the indexer stores only directory totals.
+func (idx *Indexer) aggregates() bool {
+	return idx.Mode == ModeAggregates
+}
+
+// IndexMode returns the mode new indexes are built with.
+func (idx *Indexer) IndexMode() Mode {
+	if idx.Mode == "" {
+		return ModeFull
 	}
+	return idx.Mode
 }
 
 // indexState holds the state during indexing.
@@ -60,10 +127,16 @@ type indexState struct {
 	dirsScanned  atomic.Int64
 	filesScanned atomic.Int64
 	totalSize    atomic.Int64
+	usage        sharing.Counter // Counts shared storage once, placeholders at their local size
 	currentPath  atomic.Value
 	entriesMu    sync.Mutex
 	entries      []*store.Entry
+	entryBytes   int64          // Memory the entries take, roughly
 	largeFiles   []*store.Entry // Files >= MinLargeFileSize for fast queries
+
+	// dirs holds every directory in aggregates mode, with the size and count
+	// of its own files until aggregateDirs adds in its subdirectories
+	dirs map[string]*store.Entry
 }
 
 // Index indexes a path and stores results.
@@ -86,7 +159,7 @@ func (idx *Indexer) Index(ctx context.Context, root string, onProgress ProgressF
 		}, nil
 	}
 
-	state := &indexState{}
+	state := &indexState{dirs: make(map[string]*store.Entry)}
 	state.currentPath.Store("")
 
 	// Start progress reporting
@@ -104,9 +177,13 @@ func (idx *Indexer) Index(ctx context.Context, root string, onProgress ProgressF
 	}
 
 	// Write remaining entries
+	if idx.aggregates() {
+		state.entries = append(state.entries, aggregateDirs(absRoot, state.dirs)...)
+	}
 	if err := idx.flushRemainingEntries(state); err != nil {
 		return nil, err
 	}
+	logging.Get("indexer").Debug("store batch size", "path", absRoot, "entries", idx.batches.get())
 
 	// Save metadata for fast status lookups
 	files := state.filesScanned.Load()
@@ -114,6 +191,7 @@ func (idx *Indexer) Index(ctx context.Context, root string, onProgress ProgressF
 	_ = idx.store.SetIndexMeta(absRoot, &store.IndexMeta{
 		Files: files,
 		Dirs:  dirs,
+		Mode:  string(idx.IndexMode()),
 	})
 
 	// Ensure schema is up to date (new indexes are always current version)
@@ -132,6 +210,7 @@ func (idx *Indexer) Index(ctx context.Context, root string, onProgress ProgressF
 		DirsIndexed:   dirs,
 		FilesIndexed:  files,
 		TotalSize:     state.totalSize.Load(),
+		ActualSize:    state.usage.Actual(),
 		Duration:      time.Since(startTime),
 		SubsumedPaths: subsumedPaths,
 	}, nil
@@ -180,15 +259,27 @@ func (idx *Indexer) startProgressReporter(ctx context.Context, absRoot string, s
 // walkFilesystem performs the filesystem walk.
 func (idx *Indexer) walkFilesystem(ctx context.Context, absRoot string, state *indexState) error {
 	conf := fastwalk.Config{
-		Follow: false,
+		Follow: false, // Symlinks are followed below, by policy
+	}
+
+	// Each stat on a network file system is a round trip to the server,
+	// which the default number of walkers would flood
+	if mountPoint, fsType, ok := idx.Network.Find(absRoot); ok {
+		conf.NumWorkers = idx.NetworkWorkers
+		if conf.NumWorkers <= 0 {
+			conf.NumWorkers = tuner.DefaultNetworkFileWorkers
+		}
+		logging.Get("indexer").Info("indexing a network file system with fewer workers",
+			"path", absRoot, "mount", mountPoint, "type", fsType, "workers", conf.NumWorkers)
 	}
+	links := scanner.NewSymlinkFollower(idx.Symlinks, absRoot)
+	snapshots := idx.snapshotMounts(absRoot)
+	boundary := scanner.NewBoundary(absRoot, idx.OneFileSystem, idx.SkipMounts, idx.CrossMounts)
 
 	return fastwalk.Walk(&conf, absRoot, func(path string, d fs.DirEntry, walkErr error) error {
-		// Check for context cancellation
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+		// Check for context cancellation, and wait while throttled
+		if err := idx.Throttle.wait(ctx); err != nil {
+			return err
 		}
 
 		// Skip entries with errors - intentionally continue walking
@@ -196,6 +287,33 @@ func (idx *Indexer) walkFilesystem(ctx context.Context, absRoot string, state *i
 			return nil //nolint:nilerr // Intentionally skip errors and continue walking
 		}
 
+		// A symlink is indexed as the directory it points to when followed,
+		// and not at all otherwise
+		if d.Type()&fs.ModeSymlink != 0 {
+			if follow, _ := links.Follow(path); !follow {
+				return nil
+			}
+			info, statErr := os.Stat(path)
+			if statErr != nil {
+				return nil //nolint:nilerr // Intentionally skip entries we can't stat
+			}
+			if err := idx.processEntry(path, info, true, state); err != nil {
+				return err
+			}
+			return fastwalk.ErrTraverseLink
+		}
+
+		// Snapshots would count the data in them again
+		if d.IsDir() && path != absRoot && !idx.IncludeSnapshots &&
+			(mounts.IsSnapshotDir(path) || slices.Contains(snapshots, path)) {
+			return fastwalk.SkipDir
+		}
+
+		// Nor other file systems, when staying on one
+		if d.IsDir() && path != absRoot && boundary.Stops(path, d) {
+			return fastwalk.SkipDir
+		}
+
 		info, infoErr := d.Info()
 		if infoErr != nil {
 			return nil //nolint:nilerr // Intentionally skip entries we can't stat
@@ -205,6 +323,19 @@ func (idx *Indexer) walkFilesystem(ctx context.Context, absRoot string, state *i
 	})
 }
 
+// snapshotMounts returns the snapshots mounted under root, which aren't
+// indexed unless IncludeSnapshots is set.
+func (idx *Indexer) snapshotMounts(root string) []string {
+	if idx.IncludeSnapshots {
+		return nil
+	}
+	table, err := mounts.Load()
+	if err != nil {
+		return nil
+	}
+	return table.Snapshots(root)
+}
+
 // processEntry processes a single filesystem entry.
 func (idx *Indexer) processEntry(path string, info fs.FileInfo, isDir bool, state *indexState) error {
 	entry := &store.Entry{
@@ -213,9 +344,25 @@ func (idx *Indexer) processEntry(path string, info fs.FileInfo, isDir bool, stat
 		ModTime: info.ModTime().Unix(),
 		IsDir:   isDir,
 	}
+	added := entry.Size
+	if !isDir {
+		// Clones are only looked up for large files, as it costs a system call
+		if entry.Size >= idx.MinLargeFileSize {
+			entry.Shared = sharing.Stat(path, info)
+		} else {
+			entry.Shared = sharing.FromFileInfo(info)
+		}
+		entry.Cloud = cloud.FromFileInfo(path, info)
+		added = state.usage.Add(entry.Shared, cloud.Local(entry.Cloud, entry.Size))
+	}
 
 	state.entriesMu.Lock()
-	state.entries = append(state.entries, entry)
+	if idx.aggregates() {
+		addToDir(state.dirs, entry, added)
+	} else {
+		state.entries = append(state.entries, entry)
+		state.entryBytes += entryBytes(entry)
+	}
 	// Track large files for fast queries
 	if !isDir && info.Size() >= idx.MinLargeFileSize {
 		state.largeFiles = append(state.largeFiles, entry)
@@ -230,21 +377,27 @@ func (idx *Indexer) processEntry(path string, info fs.FileInfo, isDir bool, stat
 		state.totalSize.Add(info.Size())
 	}
 
-	// Batch write every 1000 entries
 	return idx.flushBatchIfNeeded(state)
 }
 
-// flushBatchIfNeeded writes entries to store if batch size is reached.
+// flushBatchIfNeeded writes the entries, and the large files, to the store
+// once there are a batch of them.
 func (idx *Indexer) flushBatchIfNeeded(state *indexState) error {
+	size := idx.batches.get()
+	var entries, largeFiles []*store.Entry
 	state.entriesMu.Lock()
-	if len(state.entries) >= 1000 {
-		batch := state.entries
-		state.entries = nil
-		state.entriesMu.Unlock()
-		return idx.store.PutBatch(batch)
+	if len(state.entries) >= size || state.entryBytes >= maxBatchBytes {
+		entries, state.entries, state.entryBytes = state.entries, nil, 0
+	}
+	if len(state.largeFiles) >= size {
+		largeFiles, state.largeFiles = state.largeFiles, nil
 	}
 	state.entriesMu.Unlock()
-	return nil
+
+	if err := idx.writeBatch(entries, idx.store.PutBatch); err != nil {
+		return err
+	}
+	return idx.writeBatch(largeFiles, idx.store.AddLargeFileBatch)
 }
 
 // flushRemainingEntries writes any remaining entries to the store.
@@ -253,25 +406,116 @@ func (idx *Indexer) flushRemainingEntries(state *indexState) error {
 	remaining := state.entries
 	largeFiles := state.largeFiles
 	state.entries = nil
+	state.entryBytes = 0
 	state.largeFiles = nil
 	state.entriesMu.Unlock()
 
-	if len(remaining) > 0 {
-		if err := idx.store.PutBatch(remaining); err != nil {
+	for len(remaining) > 0 {
+		n := min(len(remaining), idx.batches.get())
+		if err := idx.writeBatch(remaining[:n], idx.store.PutBatch); err != nil {
 			return err
 		}
+		remaining = remaining[n:]
 	}
 
 	// Write large files to the fast-query index
-	if len(largeFiles) > 0 {
-		if err := idx.store.AddLargeFileBatch(largeFiles); err != nil {
+	for len(largeFiles) > 0 {
+		n := min(len(largeFiles), idx.batches.get())
+		if err := idx.writeBatch(largeFiles[:n], idx.store.AddLargeFileBatch); err != nil {
 			return err
 		}
+		largeFiles = largeFiles[n:]
 	}
 
 	return nil
 }
 
+// addToDir records an entry in aggregates mode: a directory is added to
+// dirs, and a file is counted in its parent directory. added is the bytes
+// the file adds to disk usage, which is less than its size when it shares
+// storage with a file already counted.
+func addToDir(dirs map[string]*store.Entry, entry *store.Entry, added int64) {
+	if entry.IsDir {
+		if d, ok := dirs[entry.Path]; ok {
+			// Files inside were seen first
+			d.ModTime = entry.ModTime
+			return
+		}
+		dirs[entry.Path] = &store.Entry{Path: entry.Path, ModTime: entry.ModTime, IsDir: true}
+		return
+	}
+
+	parent := filepath.Dir(entry.Path)
+	d, ok := dirs[parent]
+	if !ok {
+		d = &store.Entry{Path: parent, IsDir: true}
+		dirs[parent] = d
+	}
+	d.Size += added
+	d.Files++
+}
+
+// aggregateDirs adds each directory's totals into its ancestors up to root
+// and returns the directories. Children are processed before their parents
+// because a child's path is always longer.
+func aggregateDirs(root string, dirs map[string]*store.Entry) []*store.Entry {
+	entries := make([]*store.Entry, 0, len(dirs))
+	for _, d := range dirs {
+		entries = append(entries, d)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return len(entries[i].Path) > len(entries[j].Path)
+	})
+
+	for _, d := range entries {
+		if d.Path == root {
+			continue
+		}
+		if parent, ok := dirs[filepath.Dir(d.Path)]; ok && store.IsPathUnderRoot(parent.Path, root) {
+			parent.Size += d.Size
+			parent.Files += d.Files
+		}
+	}
+	return entries
+}
+
+// Evict drops the file entries of an indexed root to free space, keeping
+// the totals of its directories as an aggregates index would. The root is
+// no longer indexed afterwards; indexing it again restores its files.
+// It returns the number of file entries dropped.
+func (idx *Indexer) Evict(root string) (int64, error) {
+	meta := idx.store.GetIndexMeta(root)
+	aggregated := meta != nil && meta.Mode == string(ModeAggregates)
+
+	dirs := make(map[string]*store.Entry)
+	var files []string
+	var usage sharing.Counter
+	err := idx.store.Walk(root, func(e *store.Entry) error {
+		added := e.Size
+		if !e.IsDir {
+			files = append(files, e.Path)
+			added = usage.Add(e.Shared, cloud.Local(e.Cloud, e.Size))
+		}
+		if !aggregated {
+			addToDir(dirs, e, added)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Aggregates indexes already hold directory totals
+	var totals []*store.Entry
+	if !aggregated {
+		totals = aggregateDirs(root, dirs)
+	}
+	if err := idx.store.Evict(root, totals, files); err != nil {
+		return 0, err
+	}
+	return int64(len(files)), nil
+}
+
 // IsIndexed checks if a path has been indexed.
 func (idx *Indexer) IsIndexed(root string) bool {
 	return idx.store.HasIndex(root)
@@ -2,12 +2,16 @@ package indexer_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
 func createTestTree(t *testing.T) string {
@@ -66,7 +70,7 @@ func TestIndexerIndexPath(t *testing.T) {
 	}
 
 	// Verify we can query large files
-	large, err := s.GetLargeFiles(root, 5000, 10)
+	large, err := s.GetLargeFiles(context.Background(), root, 5000, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles failed: %v", err)
 	}
@@ -235,3 +239,283 @@ func TestIndexingCoveredPath(t *testing.T) {
 		t.Errorf("Expected 1 indexed path, got %d: %v", len(paths), paths)
 	}
 }
+
+func TestIndexerAggregatesMode(t *testing.T) {
+	root := createTestTree(t)
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	idx := indexer.New(s)
+	idx.MinLargeFileSize = 5000
+	idx.Mode = indexer.ModeAggregates
+
+	result, err := idx.Index(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.FilesIndexed != 4 {
+		t.Errorf("Expected 4 files counted, got %d", result.FilesIndexed)
+	}
+
+	// Files are counted but not stored
+	files, dirs, err := s.CountEntries(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 0 || dirs != 4 {
+		t.Errorf("Expected 0 files and 4 dirs stored, got %d files and %d dirs", files, dirs)
+	}
+
+	// Directories hold the totals of everything beneath them
+	totals := map[string]struct{ size, files int64 }{
+		"":         {65100, 4},
+		"a":        {60100, 3},
+		"a/nested": {50000, 1},
+		"b":        {5000, 1},
+	}
+	for rel, want := range totals {
+		path := filepath.Join(root, rel)
+		entry, err := s.Get(path)
+		if err != nil {
+			t.Fatalf("Get(%s) failed: %v", path, err)
+		}
+		if !entry.IsDir || entry.Size != want.size || entry.Files != want.files {
+			t.Errorf("%s: size %d files %d, want size %d files %d", rel, entry.Size, entry.Files, want.size, want.files)
+		}
+	}
+
+	// The large files index is still complete
+	large, err := s.GetLargeFiles(context.Background(), root, 5000, 10)
+	if err != nil {
+		t.Fatalf("GetLargeFiles failed: %v", err)
+	}
+	if len(large) != 3 {
+		t.Errorf("Expected 3 large files (>=5000), got %d", len(large))
+	}
+
+	meta := s.GetIndexMeta(root)
+	if meta == nil || meta.Mode != string(indexer.ModeAggregates) || meta.Files != 4 {
+		t.Errorf("index meta = %+v, want aggregates mode with 4 files", meta)
+	}
+}
+
+func TestIndexerHardLinks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "copy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	orig := filepath.Join(root, "disk.img")
+	if err := os.WriteFile(orig, make([]byte, 10000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(orig, filepath.Join(root, "copy", "disk.img")); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	idx := indexer.New(s)
+	idx.MinLargeFileSize = 5000
+	idx.Mode = indexer.ModeAggregates
+
+	result, err := idx.Index(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.TotalSize != 20000 || result.ActualSize != 10000 {
+		t.Errorf("sizes = %d apparent, %d actual; want 20000 and 10000", result.TotalSize, result.ActualSize)
+	}
+	entry, err := s.Get(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Size != 10000 || entry.Files != 2 {
+		t.Errorf("root: size %d files %d, want the linked file counted once", entry.Size, entry.Files)
+	}
+
+	large, err := s.GetLargeFiles(context.Background(), root, 5000, 10)
+	if err != nil {
+		t.Fatalf("GetLargeFiles failed: %v", err)
+	}
+	if len(large) != 2 {
+		t.Fatalf("Expected both links as large files, got %d", len(large))
+	}
+	for _, f := range large {
+		if f.Shared == nil || f.Shared.Links != 2 {
+			t.Errorf("%s: sharing %+v, want 2 hard links", f.Path, f.Shared)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]indexer.Mode{"": indexer.ModeFull, "full": indexer.ModeFull, "aggregates": indexer.ModeAggregates} {
+		got, err := indexer.ParseMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := indexer.ParseMode("sparse"); !errors.Is(err, indexer.ErrUnknownMode) {
+		t.Errorf("ParseMode(sparse) error = %v, want ErrUnknownMode", err)
+	}
+}
+
+func TestIndexerSymlinks(t *testing.T) {
+	root, outside := createTestTree(t), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "ext.bin"), make([]byte, 20000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "ext")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	for _, tt := range []struct {
+		policy types.SymlinkPolicy
+		files  int64
+	}{
+		{types.SymlinksSkip, 4},
+		{types.SymlinksWithinRoot, 4},
+		{types.SymlinksFollow, 5},
+	} {
+		s, err := store.Open(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx := indexer.New(s)
+		idx.Symlinks = tt.policy
+		result, err := idx.Index(context.Background(), root, nil)
+		if err != nil {
+			t.Fatalf("%s: Index failed: %v", tt.policy, err)
+		}
+		if result.FilesIndexed != tt.files {
+			t.Errorf("%s: indexed %d files, want %d", tt.policy, result.FilesIndexed, tt.files)
+		}
+		// The symlink is stored as the directory it points to, or not at all
+		entry, err := s.Get(link)
+		if tt.policy == types.SymlinksFollow {
+			if err != nil || !entry.IsDir {
+				t.Errorf("%s: symlink entry = %+v, %v; want a directory", tt.policy, entry, err)
+			}
+		} else if err == nil {
+			t.Errorf("%s: symlink stored as %+v, want no entry", tt.policy, entry)
+		}
+		s.Close()
+	}
+}
+
+func TestIndexerSnapshots(t *testing.T) {
+	root := createTestTree(t)
+	snapshot := filepath.Join(root, ".snapshots", "1", "snapshot")
+	if err := os.MkdirAll(snapshot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshot, "big.dat"), make([]byte, 50000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, include := range []bool{false, true} {
+		s, err := store.Open(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx := indexer.New(s)
+		idx.IncludeSnapshots = include
+		result, err := idx.Index(context.Background(), root, nil)
+		if err != nil {
+			t.Fatalf("include %v: Index failed: %v", include, err)
+		}
+		want := int64(4)
+		if include {
+			want = 5
+		}
+		if result.FilesIndexed != want {
+			t.Errorf("include %v: indexed %d files, want %d", include, result.FilesIndexed, want)
+		}
+		s.Close()
+	}
+}
+
+func TestIndexerMountBoundary(t *testing.T) {
+	root := createTestTree(t)
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// The test tree is all on one file system, so only the skipped mount
+	// point is left out
+	idx := indexer.New(s)
+	idx.OneFileSystem = true
+	idx.SkipMounts = []string{filepath.Join(root, "a", "nested")}
+	result, err := idx.Index(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.FilesIndexed != 3 {
+		t.Errorf("indexed %d files, want 3", result.FilesIndexed)
+	}
+}
+
+func TestIndexerNetwork(t *testing.T) {
+	root := createTestTree(t)
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Indexed with a single walker, as if on a network file system
+	idx := indexer.New(s)
+	idx.Network = mounts.Network{Paths: []string{root}}
+	idx.NetworkWorkers = 1
+	result, err := idx.Index(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.FilesIndexed != 4 {
+		t.Errorf("indexed %d files, want 4", result.FilesIndexed)
+	}
+}
+
+func TestIndexerThrottle(t *testing.T) {
+	root := createTestTree(t) // 4 directories and 4 files
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	idx := indexer.New(s)
+	idx.Throttle = &indexer.Throttle{OpsPerSec: 40}
+	start := time.Now()
+	result, err := idx.Index(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.FilesIndexed != 4 {
+		t.Errorf("FilesIndexed = %d, want 4", result.FilesIndexed)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("8 entries at 40 per second took %v, want at least 175ms", elapsed)
+	}
+
+	// A paused index waits until cancelled, examining nothing
+	other := createTestTree(t)
+	idx.Throttle = &indexer.Throttle{Paused: func() (string, bool) { return "on battery", true }}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	result, err = idx.Index(ctx, other, nil)
+	if err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+	if result.FilesIndexed != 0 || result.DirsIndexed != 0 {
+		t.Errorf("paused index examined %d files and %d dirs, want none", result.FilesIndexed, result.DirsIndexed)
+	}
+}
@@ -0,0 +1,105 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// pausePoll is how often a paused index checks whether it may go on.
+const pausePoll = 5 * time.Second
+
+// Throttle slows indexing down so a large index doesn't make the machine
+// sluggish. The zero value doesn't slow it at all.
+type Throttle struct {
+	// OpsPerSec is the most files and directories examined per second
+	// (0 = no limit).
+	OpsPerSec int
+
+	// Paused, if set, reports whether indexing should wait, and why, such
+	// as running on battery. It is called often, so should be cheap.
+	Paused func() (string, bool)
+
+	mu      sync.Mutex
+	next    time.Time // When the next operation may start
+	pausing bool      // A pause has been logged
+}
+
+// wait blocks until the next file or directory may be examined: while
+// paused, then until the rate allows it. It returns ctx's error if ctx is
+// done first.
+func (t *Throttle) wait(ctx context.Context) error {
+	if t == nil {
+		return ctx.Err()
+	}
+	if err := t.waitUnpaused(ctx); err != nil {
+		return err
+	}
+	if t.OpsPerSec <= 0 {
+		return ctx.Err()
+	}
+
+	interval := time.Second / time.Duration(t.OpsPerSec)
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	at := t.next
+	t.next = t.next.Add(interval)
+	t.mu.Unlock()
+	return sleep(ctx, time.Until(at))
+}
+
+// waitUnpaused blocks while Paused reports indexing should wait.
+func (t *Throttle) waitUnpaused(ctx context.Context) error {
+	if t.Paused == nil {
+		return nil
+	}
+	reason, paused := t.Paused()
+	if !paused {
+		return nil
+	}
+	t.logPause(true, reason)
+	for paused {
+		if err := sleep(ctx, pausePoll); err != nil {
+			return err
+		}
+		_, paused = t.Paused()
+	}
+	t.logPause(false, "")
+	return nil
+}
+
+// logPause logs once that indexing paused, or resumed, however many walk
+// workers wait.
+func (t *Throttle) logPause(pausing bool, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pausing == pausing {
+		return
+	}
+	t.pausing = pausing
+	if pausing {
+		logging.Get("indexer").Info("indexing paused", "reason", reason)
+	} else {
+		logging.Get("indexer").Info("indexing resumed")
+	}
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
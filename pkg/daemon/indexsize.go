@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/indexsize"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// minIndexSize returns the large files index threshold, which
+// SetMinIndexSize changes while requests are served.
+func (s *Service) minIndexSize() int64 {
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	return s.indexer.MinLargeFileSize
+}
+
+// aggregatesRoot reports whether root was indexed in aggregates mode, so
+// only its large files are stored.
+func (s *Service) aggregatesRoot(root string) (*store.IndexMeta, bool) {
+	meta := s.store.GetIndexMeta(root)
+	return meta, meta != nil && indexer.Mode(meta.Mode) == indexer.ModeAggregates
+}
+
+// GetSizeHistogram counts the indexed files of every root in each
+// indexsize bucket. Roots indexed in aggregates mode only store their large
+// files; the rest are reported as unsized.
+func (s *Service) GetSizeHistogram(ctx context.Context, _ *sweepv1.GetSizeHistogramRequest) (*sweepv1.GetSizeHistogramResponse, error) {
+	roots, err := s.store.GetIndexedPaths()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list indexed paths: %v", err)
+	}
+
+	buckets := make([]*sweepv1.SizeBucket, len(indexsize.Bounds))
+	for i, bound := range indexsize.Bounds {
+		buckets[i] = &sweepv1.SizeBucket{MinSize: bound}
+	}
+	add := func(e *store.Entry) {
+		b := buckets[indexsize.BucketOf(e.Size)]
+		b.Files++
+		b.Bytes += e.Size
+		b.IndexBytes += store.LargeFileIndexBytes(e)
+	}
+
+	var unsized int64
+	for _, root := range roots {
+		if meta, ok := s.aggregatesRoot(root); ok {
+			files, err := s.store.GetLargeFiles(ctx, root, 0, 0)
+			if err != nil {
+				return nil, storeError(ctx, err)
+			}
+			var sized int64
+			for _, f := range files {
+				if store.IsPathUnderRoot(f.Path, root) {
+					add(f)
+					sized++
+				}
+			}
+			unsized += max(meta.Files-sized, 0)
+			continue
+		}
+
+		err := s.store.Walk(root, func(e *store.Entry) error {
+			if !e.IsDir {
+				add(e)
+			}
+			return ctx.Err()
+		})
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read index: %v", err)
+		}
+	}
+
+	return &sweepv1.GetSizeHistogramResponse{
+		Buckets:        buckets,
+		MinIndexSize:   s.minIndexSize(),
+		StoreSizeBytes: s.store.Size(),
+		UnsizedFiles:   unsized,
+	}, nil
+}
+
+// SetMinIndexSize changes the large files index threshold. Raising it
+// drops the smaller files from the index; lowering it adds the files now
+// large enough from the stored entries, or re-indexes roots indexed in
+// aggregates mode, which don't store them. The change lasts until the
+// daemon restarts, so clients save it in daemon.min_index_size too.
+func (s *Service) SetMinIndexSize(ctx context.Context, req *sweepv1.SetMinIndexSizeRequest) (*sweepv1.SetMinIndexSizeResponse, error) {
+	size := req.GetSize()
+	if size <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "min index size must be positive")
+	}
+	roots, err := s.store.GetIndexedPaths()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list indexed paths: %v", err)
+	}
+
+	// Indexes in progress use the threshold they started with
+	s.indexMu.Lock()
+	for path, state := range s.indexStates {
+		if state.state == sweepv1.IndexState_INDEX_STATE_INDEXING {
+			s.indexMu.Unlock()
+			return nil, status.Errorf(codes.FailedPrecondition, "%s is being indexed; try again when it finishes", path)
+		}
+	}
+	previous := s.indexer.MinLargeFileSize
+	s.indexer.MinLargeFileSize = size
+	s.indexMu.Unlock()
+	if s.watcher != nil {
+		s.watcher.SetMinLargeFileSize(size)
+	}
+
+	resp := &sweepv1.SetMinIndexSizeResponse{Previous: previous}
+	for _, root := range roots {
+		switch _, aggregates := s.aggregatesRoot(root); {
+		case size > previous:
+			removed, err := s.store.PruneLargeFiles(root, size)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to update large files index: %v", err)
+			}
+			resp.Removed += int64(removed)
+		case size == previous:
+		case aggregates:
+			if _, err := s.TriggerIndex(ctx, &sweepv1.TriggerIndexRequest{Path: root, Force: true}); err != nil {
+				return nil, err
+			}
+			resp.Reindexing = append(resp.Reindexing, root)
+		default:
+			before, err := s.store.GetLargeFiles(ctx, root, 0, 0)
+			if err != nil {
+				return nil, storeError(ctx, err)
+			}
+			after, err := s.store.RebuildLargeFilesIndex(root, size)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to update large files index: %v", err)
+			}
+			resp.Added += int64(max(after-len(before), 0))
+		}
+	}
+
+	logging.Get("daemon").Info("changed min index size",
+		"previous", previous, "size", size, "added", resp.GetAdded(), "removed", resp.GetRemoved(), "reindexing", len(resp.GetReindexing()))
+	return resp, nil
+}
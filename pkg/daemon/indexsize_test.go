@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/indexsize"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// indexSized indexes a root holding a 100 KiB, a 2 MiB, and a 20 MiB file.
+func indexSized(t *testing.T, svc *Service) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSized(t, filepath.Join(root, "notes.txt"), 100*types.KiB)
+	writeSized(t, filepath.Join(root, "photo.raw"), 2*types.MiB)
+	writeSized(t, filepath.Join(root, "video.mkv"), 20*types.MiB)
+	_, err := svc.indexer.Index(context.Background(), root, nil)
+	require.NoError(t, err)
+	return root
+}
+
+func TestServiceGetSizeHistogram(t *testing.T) {
+	for _, mode := range []indexer.Mode{indexer.ModeFull, indexer.ModeAggregates} {
+		t.Run(string(mode), func(t *testing.T) {
+			st, err := store.Open(t.TempDir())
+			require.NoError(t, err)
+			defer st.Close()
+			svc := NewService(st)
+			svc.indexer.Mode = mode
+			svc.indexer.MinLargeFileSize = types.MiB
+			indexSized(t, svc)
+
+			resp, err := svc.GetSizeHistogram(context.Background(), &sweepv1.GetSizeHistogramRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, types.MiB, resp.GetMinIndexSize())
+			require.Len(t, resp.GetBuckets(), len(indexsize.Bounds))
+
+			files := make(map[int64]int64)
+			for _, b := range resp.GetBuckets() {
+				if b.GetFiles() > 0 {
+					files[b.GetMinSize()] = b.GetFiles()
+					assert.Positive(t, b.GetIndexBytes())
+				}
+			}
+			if mode == indexer.ModeFull {
+				assert.Equal(t, map[int64]int64{64 * types.KiB: 1, types.MiB: 1, 10 * types.MiB: 1}, files)
+				assert.Zero(t, resp.GetUnsizedFiles())
+			} else {
+				assert.Equal(t, map[int64]int64{types.MiB: 1, 10 * types.MiB: 1}, files, "small files aren't stored")
+				assert.Equal(t, int64(1), resp.GetUnsizedFiles())
+			}
+		})
+	}
+}
+
+func TestServiceSetMinIndexSize(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.indexer.MinLargeFileSize = types.MiB
+	root := indexSized(t, svc)
+	ctx := context.Background()
+
+	largeFiles := func() int {
+		files, err := st.GetLargeFiles(context.Background(), root, 0, 0)
+		require.NoError(t, err)
+		return len(files)
+	}
+	require.Equal(t, 2, largeFiles())
+
+	resp, err := svc.SetMinIndexSize(ctx, &sweepv1.SetMinIndexSizeRequest{Size: 10 * types.MiB})
+	require.NoError(t, err)
+	assert.Equal(t, types.MiB, resp.GetPrevious())
+	assert.Equal(t, int64(1), resp.GetRemoved())
+	assert.Equal(t, 1, largeFiles())
+	assert.Equal(t, 10*types.MiB, svc.minIndexSize())
+
+	resp, err = svc.SetMinIndexSize(ctx, &sweepv1.SetMinIndexSizeRequest{Size: 64 * types.KiB})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.GetAdded(), "found in the stored entries")
+	assert.Empty(t, resp.GetReindexing())
+	assert.Equal(t, 3, largeFiles())
+
+	_, err = svc.SetMinIndexSize(ctx, &sweepv1.SetMinIndexSizeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServiceSetMinIndexSizeAggregates(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.indexer.Mode = indexer.ModeAggregates
+	svc.indexer.MinLargeFileSize = types.MiB
+	root := indexSized(t, svc)
+
+	resp, err := svc.SetMinIndexSize(context.Background(), &sweepv1.SetMinIndexSizeRequest{Size: 64 * types.KiB})
+	require.NoError(t, err)
+	assert.Equal(t, []string{root}, resp.GetReindexing(), "small files have to be found on disk")
+
+	require.Eventually(t, func() bool { return !svc.isIndexing() }, 5*time.Second, 10*time.Millisecond)
+	files, err := st.GetLargeFiles(context.Background(), root, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+}
@@ -0,0 +1,4 @@
+// Package ipc connects sweep to a local sweepd: over a Unix socket, or on
+// Windows over a named pipe derived from the socket path, so the socket
+// path configures both.
+package ipc
@@ -0,0 +1,77 @@
+package ipc_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/ipc"
+)
+
+func TestListenDial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sweep.sock")
+	if ipc.Exists(path) {
+		t.Fatal("nothing should be listening yet")
+	}
+
+	ln, err := ipc.Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if !ipc.Exists(path) {
+		t.Fatal("the listener should exist")
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := ipc.Dial(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("got %q back, want ping", buf)
+	}
+
+	// The path can be listened on again, as when sweepd restarts
+	if err := ln.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ln, err = ipc.Listen(path)
+	if err != nil {
+		t.Fatalf("listening again after close: %v", err)
+	}
+	ln.Close()
+}
+
+func TestProcessRunning(t *testing.T) {
+	if !ipc.ProcessRunning(os.Getpid()) {
+		t.Error("this process should be running")
+	}
+	if ipc.ProcessRunning(1 << 30) {
+		t.Error("an unused PID should not be running")
+	}
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Listen listens on the Unix socket at path, replacing a stale socket left
+// by a daemon that didn't shut down cleanly.
+func Listen(path string) (net.Listener, error) {
+	if err := os.RemoveAll(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	var lc net.ListenConfig
+	return lc.Listen(context.Background(), "unix", path)
+}
+
+// Dial connects to the daemon listening at path.
+func Dial(ctx context.Context, path string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", path)
+}
+
+// Exists reports whether a daemon has created its socket at path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ProcessRunning reports whether a process with the given PID is running.
+func ProcessRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
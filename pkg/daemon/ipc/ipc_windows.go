@@ -0,0 +1,259 @@
+//go:build windows
+
+package ipc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipePrefix is the namespace of local named pipes.
+const pipePrefix = `\\.\pipe\`
+
+// stillActive is the exit code of a process that hasn't exited.
+const stillActive = 259
+
+// pipeBufferSize is the size of each pipe instance's input and output
+// buffers.
+const pipeBufferSize = 64 << 10
+
+// PipeName returns the named pipe for a socket path. Named pipes live in
+// their own namespace, so the path is folded into the pipe's name; a path
+// that already names a pipe is used as is.
+func PipeName(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), pipePrefix) {
+		return path
+	}
+	name := strings.NewReplacer(`\`, "-", ":", "").Replace(filepath.Clean(path))
+	return pipePrefix + "sweep-" + strings.ToLower(name)
+}
+
+// Listen listens on the named pipe for path. Only the current user and
+// SYSTEM can connect, as with a Unix socket in the user's data directory,
+// and remote clients are refused.
+func Listen(path string) (net.Listener, error) {
+	sa, err := ownerOnly()
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{name: PipeName(path), sa: sa}
+
+	// The first instance fails if another process already owns the pipe
+	if l.next, err = l.create(true); err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", l.name, err)
+	}
+	return l, nil
+}
+
+// ownerOnly returns security attributes allowing only the current user and
+// SYSTEM.
+func ownerOnly() (*windows.SecurityAttributes, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+	sd, err := windows.SecurityDescriptorFromString(fmt.Sprintf("D:P(A;;GA;;;SY)(A;;GA;;;%s)", user.User.Sid))
+	if err != nil {
+		return nil, err
+	}
+	sa := &windows.SecurityAttributes{SecurityDescriptor: sd}
+	sa.Length = uint32(unsafe.Sizeof(*sa))
+	return sa, nil
+}
+
+// pipeListener accepts connections on a named pipe. An instance of the
+// pipe always waits for the next client, so clients never find it missing
+// between connections.
+type pipeListener struct {
+	name string
+	sa   *windows.SecurityAttributes
+
+	mu      sync.Mutex
+	closed  bool
+	next    windows.Handle      // The instance waiting for a client
+	pending *windows.Overlapped // Its wait, kept on the heap for the kernel
+}
+
+// create makes a new instance of the pipe.
+func (l *pipeListener) create(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	return windows.CreateNamedPipe(name, flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+// Accept waits for a client to connect to the pipe.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(event)
+
+	for {
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			return nil, net.ErrClosed
+		}
+		h := l.next
+		l.pending = &windows.Overlapped{HEvent: event}
+		ol := l.pending
+		l.mu.Unlock()
+
+		err := connectPipe(h, ol)
+
+		l.mu.Lock()
+		l.pending = nil
+		if l.closed {
+			l.mu.Unlock()
+			return nil, net.ErrClosed
+		}
+		if err != nil {
+			// The client went away before it was accepted; reset the
+			// instance for the next one
+			l.mu.Unlock()
+			_ = windows.DisconnectNamedPipe(h)
+			continue
+		}
+		next, err := l.create(false)
+		if err != nil {
+			l.mu.Unlock()
+			_ = windows.DisconnectNamedPipe(h)
+			return nil, fmt.Errorf("accept on %s: %w", l.name, err)
+		}
+		l.next = next
+		l.mu.Unlock()
+		return newPipeConn(h, l.name), nil
+	}
+}
+
+// connectPipe waits for a client to connect to the pipe instance h.
+func connectPipe(h windows.Handle, ol *windows.Overlapped) error {
+	if err := windows.ResetEvent(ol.HEvent); err != nil {
+		return err
+	}
+	err := windows.ConnectNamedPipe(h, ol)
+	if errors.Is(err, windows.ERROR_IO_PENDING) {
+		if _, err = windows.WaitForSingleObject(ol.HEvent, windows.INFINITE); err != nil {
+			return err
+		}
+		var n uint32
+		err = windows.GetOverlappedResult(h, ol, &n, false)
+	}
+	if errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		return nil // The client connected before ConnectNamedPipe was called
+	}
+	return err
+}
+
+// Close stops accepting connections. Connections already accepted stay
+// open.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if l.pending != nil {
+		_ = windows.CancelIoEx(l.next, l.pending)
+	}
+	return windows.CloseHandle(l.next)
+}
+
+// Addr returns the pipe's name.
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.name)
+}
+
+// Dial connects to the daemon listening on the named pipe for path,
+// waiting while every instance of the pipe is busy.
+func Dial(ctx context.Context, path string) (net.Conn, error) {
+	name := PipeName(path)
+	p, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Identification only: the daemon can't act as the client
+		h, err := windows.CreateFile(p, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+			windows.FILE_FLAG_OVERLAPPED|windows.SECURITY_SQOS_PRESENT|windows.SECURITY_IDENTIFICATION, 0)
+		if err == nil {
+			return newPipeConn(h, name), nil
+		}
+		if !errors.Is(err, windows.ERROR_PIPE_BUSY) {
+			return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(name), Err: err}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Exists reports whether a daemon is listening on the named pipe for path.
+// It looks the pipe up without connecting, which would use an instance.
+func Exists(path string) bool {
+	p, err := windows.UTF16PtrFromString(PipeName(path))
+	if err != nil {
+		return false
+	}
+	var data windows.Win32finddata
+	h, err := windows.FindFirstFile(p, &data)
+	if err != nil {
+		return false
+	}
+	_ = windows.FindClose(h)
+	return true
+}
+
+// ProcessRunning reports whether a process with the given PID is running.
+func ProcessRunning(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+	var code uint32
+	return windows.GetExitCodeProcess(h, &code) == nil && code == stillActive
+}
+
+// pipeConn is a connected pipe instance. Opened for overlapped I/O, it is
+// read and written through the runtime's poller like a socket, deadlines
+// included.
+type pipeConn struct {
+	*os.File
+	addr pipeAddr
+}
+
+func newPipeConn(h windows.Handle, name string) *pipeConn {
+	return &pipeConn{File: os.NewFile(uintptr(h), name), addr: pipeAddr(name)}
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+// pipeAddr is the name of a named pipe.
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
@@ -5,7 +5,6 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"syscall"
 )
 
 // WritePIDFile writes the current process ID to a file.
@@ -41,15 +40,7 @@ func IsDaemonRunning(pidPath string) bool {
 		return false
 	}
 
-	// Check if process exists
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// Send signal 0 to check if process exists
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
+	return IsProcessRunning(pid)
 }
 
 // ErrDaemonAlreadyRunning is returned when trying to start a daemon that's already running.
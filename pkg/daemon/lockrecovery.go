@@ -3,8 +3,8 @@ package daemon
 import (
 	"os"
 	"path/filepath"
-	"syscall"
 
+	"github.com/jamesainslie/sweep/pkg/daemon/ipc"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
 )
 
@@ -37,9 +37,5 @@ func RecoverFromStaleDaemon(pidPath, socketPath, dataDir string) error {
 
 // IsProcessRunning checks if a process with the given PID is running.
 func IsProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	return process.Signal(syscall.Signal(0)) == nil
+	return ipc.ProcessRunning(pid)
 }
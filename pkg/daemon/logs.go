@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// LogBufferSize is how many recent log entries sweepd keeps for GetLogs.
+const LogBufferSize = 1000
+
+// GetLogs streams the recent log entries the request selects, oldest first,
+// then, when following, each new one as it is logged until the client
+// cancels. Following entries may be dropped if the client falls behind.
+func (s *Service) GetLogs(req *sweepv1.GetLogsRequest, stream grpc.ServerStreamingServer[sweepv1.LogEntry]) error {
+	filter := logging.EntryFilter{Components: req.GetComponents()}
+	if req.GetMinLevel() != "" {
+		level, err := logging.ParseLevel(req.GetMinLevel())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter.MinLevel = level
+	}
+	if req.GetSince() > 0 {
+		filter.Since = time.Unix(0, req.GetSince())
+	}
+	buf := logging.GetLogBuffer()
+	if buf == nil {
+		return status.Error(codes.FailedPrecondition, "the daemon keeps no log entries")
+	}
+
+	// Subscribe before reading the buffer so no entry falls between them
+	var live <-chan logging.LogEntry
+	if req.GetFollow() {
+		live = logging.Subscribe()
+		defer logging.Unsubscribe(live)
+	}
+
+	entries := buf.Entries()
+	var seen time.Time // Entries logged by then were in the buffer
+	if len(entries) > 0 {
+		seen = entries[len(entries)-1].Time
+	}
+	entries = filter.Filter(entries)
+	if limit := int(req.GetLimit()); limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	for _, e := range entries {
+		if err := stream.Send(logEntryToProto(e)); err != nil {
+			return err
+		}
+	}
+	if live == nil {
+		return nil
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-live:
+			if !ok {
+				return nil // Logging closed
+			}
+			if !e.Time.After(seen) || !filter.Match(e) {
+				continue
+			}
+			if err := stream.Send(logEntryToProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// logEntryToProto converts a log entry to its wire form.
+func logEntryToProto(e logging.LogEntry) *sweepv1.LogEntry {
+	msg := &sweepv1.LogEntry{
+		Time:      e.Time.UnixNano(),
+		Level:     e.Level.String(),
+		Component: e.Component,
+		Message:   e.Message,
+	}
+	for _, f := range e.Fields {
+		msg.Fields = append(msg.Fields, &sweepv1.LogField{Key: f.Key, Value: f.Value})
+	}
+	return msg
+}
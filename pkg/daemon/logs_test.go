@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// mockLogStream implements grpc.ServerStreamingServer[sweepv1.LogEntry] for testing.
+type mockLogStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	entries chan *sweepv1.LogEntry
+}
+
+func (m *mockLogStream) Send(e *sweepv1.LogEntry) error {
+	m.entries <- e
+	return nil
+}
+
+func (m *mockLogStream) Context() context.Context {
+	return m.ctx
+}
+
+// messages returns the messages of the entries sent so far.
+func (m *mockLogStream) messages() []string {
+	var msgs []string
+	for {
+		select {
+		case e := <-m.entries:
+			msgs = append(msgs, e.GetMessage())
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestServiceGetLogs(t *testing.T) {
+	svc := &Service{}
+	stream := &mockLogStream{ctx: context.Background(), entries: make(chan *sweepv1.LogEntry, 10)}
+	require.NoError(t, logging.Init(logging.Config{Level: "info", Path: filepath.Join(t.TempDir(), "sweepd.log")}))
+	err := svc.GetLogs(&sweepv1.GetLogsRequest{}, stream)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "no buffer is kept unless asked")
+
+	require.NoError(t, logging.Init(logging.Config{
+		Level:      "info",
+		Path:       filepath.Join(t.TempDir(), "sweepd.log"),
+		BufferSize: LogBufferSize,
+	}))
+	t.Cleanup(func() { _ = logging.Close() })
+	indexer, watcher := logging.Get("logs-indexer"), logging.Get("logs-watcher")
+	indexer.Debug("walking", "dir", "/data")
+	indexer.Info("indexed", "root", "/data", "files", 3)
+	watcher.Warn("overflow")
+	cutoff := time.Now()
+	indexer.Error("failed")
+
+	req := &sweepv1.GetLogsRequest{Components: []string{"logs-indexer", "logs-watcher"}}
+	require.NoError(t, svc.GetLogs(req, stream))
+	assert.Equal(t, []string{"walking", "indexed", "overflow", "failed"}, stream.messages())
+
+	req.MinLevel = "info"
+	require.NoError(t, svc.GetLogs(req, stream))
+	assert.Equal(t, []string{"indexed", "overflow", "failed"}, stream.messages())
+
+	req.Limit = 2
+	require.NoError(t, svc.GetLogs(req, stream))
+	assert.Equal(t, []string{"overflow", "failed"}, stream.messages(), "the limit keeps the latest")
+
+	require.NoError(t, svc.GetLogs(&sweepv1.GetLogsRequest{Components: []string{"logs-indexer"}, Since: cutoff.UnixNano()}, stream))
+	assert.Equal(t, []string{"failed"}, stream.messages())
+
+	require.NoError(t, svc.GetLogs(&sweepv1.GetLogsRequest{Components: []string{"logs-indexer"}, MinLevel: "info", Limit: 1}, stream))
+	e := <-stream.entries
+	assert.Equal(t, "error", e.GetLevel())
+	assert.Equal(t, "logs-indexer", e.GetComponent())
+
+	require.NoError(t, svc.GetLogs(&sweepv1.GetLogsRequest{Components: []string{"logs-indexer"}, MinLevel: "info", Limit: 2}, stream))
+	e = <-stream.entries
+	require.Len(t, e.GetFields(), 2)
+	assert.Equal(t, "files", e.GetFields()[1].GetKey())
+	assert.Equal(t, "3", e.GetFields()[1].GetValue())
+	stream.messages()
+
+	err = svc.GetLogs(&sweepv1.GetLogsRequest{MinLevel: "loud"}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServiceGetLogsFollow(t *testing.T) {
+	require.NoError(t, logging.Init(logging.Config{
+		Level:      "info",
+		Path:       filepath.Join(t.TempDir(), "sweepd.log"),
+		BufferSize: LogBufferSize,
+	}))
+	t.Cleanup(func() { _ = logging.Close() })
+	log := logging.Get("logs-follow")
+	log.Info("before")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockLogStream{ctx: ctx, entries: make(chan *sweepv1.LogEntry, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- (&Service{}).GetLogs(&sweepv1.GetLogsRequest{
+			Components: []string{"logs-follow"},
+			MinLevel:   "warn",
+			Follow:     true,
+		}, stream)
+	}()
+
+	// Entries logged once following are streamed, if they match
+	require.Eventually(t, func() bool {
+		log.Info("ignored")
+		log.Warn("after")
+		select {
+		case e := <-stream.entries:
+			return e.GetMessage() == "after"
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetLogs didn't return once canceled")
+	}
+	for _, msg := range stream.messages() {
+		assert.Equal(t, "after", msg, "only matching entries are streamed")
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/metrics"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// daemonMetrics are the Prometheus metrics served on Config.MetricsAddr.
+type daemonMetrics struct {
+	registry      *metrics.Registry
+	filesIndexed  *metrics.Counter
+	dirsIndexed   *metrics.Counter
+	indexDuration *metrics.Histogram
+	watcherEvents *metrics.Counter
+	rpcDuration   *metrics.Histogram
+}
+
+func newDaemonMetrics(st store.StorageBackend) *daemonMetrics {
+	r := metrics.NewRegistry()
+	m := &daemonMetrics{
+		registry: r,
+		filesIndexed: r.Counter("sweepd_files_indexed_total",
+			"Files added to the index by completed index runs."),
+		dirsIndexed: r.Counter("sweepd_dirs_indexed_total",
+			"Directories added to the index by completed index runs."),
+		indexDuration: r.Histogram("sweepd_index_duration_seconds",
+			"Time taken by index runs, by result (ok, cached, or error).",
+			metrics.DefaultBuckets, "result"),
+		watcherEvents: r.Counter("sweepd_watcher_events_total",
+			"Filesystem events received by the watcher, by operation.", "op"),
+		rpcDuration: r.Histogram("sweepd_rpc_duration_seconds",
+			"Time taken by gRPC requests, by method and status code; streams are timed until they end.",
+			metrics.DefaultBuckets, "method", "code"),
+	}
+	r.GaugeFunc("sweepd_store_size_bytes", "Size of the index store on disk.",
+		func() float64 { return float64(st.Size()) })
+	return m
+}
+
+// indexed records a finished index run.
+func (m *daemonMetrics) indexed(result *indexer.Result, err error, took time.Duration) {
+	outcome := "ok"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case result.Cached:
+		outcome = "cached"
+	default:
+		m.filesIndexed.Add(float64(result.FilesIndexed))
+		m.dirsIndexed.Add(float64(result.DirsIndexed))
+	}
+	m.indexDuration.Observe(took.Seconds(), outcome)
+}
+
+// watcherEvent counts a filesystem event, under the operation the watcher
+// handles it as.
+func (m *daemonMetrics) watcherEvent(_ string, op fsnotify.Op) {
+	label := "chmod"
+	switch {
+	case op&fsnotify.Create != 0:
+		label = "create"
+	case op&fsnotify.Write != 0:
+		label = "write"
+	case op&fsnotify.Remove != 0:
+		label = "remove"
+	case op&fsnotify.Rename != 0:
+		label = "rename"
+	}
+	m.watcherEvents.Inc(label)
+}
+
+// serverOptions time every gRPC request.
+func (m *daemonMetrics) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			start := time.Now()
+			resp, err := handler(ctx, req)
+			m.rpcDuration.Observe(time.Since(start).Seconds(), info.FullMethod, status.Code(err).String())
+			return resp, err
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			start := time.Now()
+			err := handler(srv, ss)
+			m.rpcDuration.Observe(time.Since(start).Seconds(), info.FullMethod, status.Code(err).String())
+			return err
+		}),
+	}
+}
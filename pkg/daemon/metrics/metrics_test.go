@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, r.Write(&b))
+	return b.String()
+}
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	plain := r.Counter("sweep_files_total", "Files seen.")
+	events := r.Counter("sweep_events_total", "Events by type.", "type")
+
+	assert.Contains(t, render(t, r), "sweep_files_total 0\n", "unlabelled counters start at zero")
+	assert.NotContains(t, render(t, r), "sweep_events_total{")
+
+	plain.Add(3)
+	events.Inc("write")
+	events.Inc("create")
+	events.Inc("write")
+	events.Inc(`a"b`)
+
+	assert.Equal(t, `# HELP sweep_files_total Files seen.
+# TYPE sweep_files_total counter
+sweep_files_total 3
+# HELP sweep_events_total Events by type.
+# TYPE sweep_events_total counter
+sweep_events_total{type="a\"b"} 1
+sweep_events_total{type="create"} 1
+sweep_events_total{type="write"} 2
+`, render(t, r))
+
+	assert.Panics(t, func() { events.Inc() }, "missing label value")
+	assert.Panics(t, func() { plain.Add(-1) })
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("sweep_rpc_seconds", "RPC durations.", []float64{1, 0.1}, "method")
+	h.Observe(0.05, "/A")
+	h.Observe(0.5, "/A")
+	h.Observe(7, "/A")
+
+	assert.Equal(t, `# HELP sweep_rpc_seconds RPC durations.
+# TYPE sweep_rpc_seconds histogram
+sweep_rpc_seconds_bucket{method="/A",le="0.1"} 1
+sweep_rpc_seconds_bucket{method="/A",le="1"} 2
+sweep_rpc_seconds_bucket{method="/A",le="+Inf"} 3
+sweep_rpc_seconds_sum{method="/A"} 7.55
+sweep_rpc_seconds_count{method="/A"} 3
+`, render(t, r))
+
+	r = NewRegistry()
+	r.Histogram("sweep_index_seconds", "Index durations.", []float64{1})
+	assert.Contains(t, render(t, r), "sweep_index_seconds_bucket{le=\"+Inf\"} 0\nsweep_index_seconds_sum 0\n")
+}
+
+func TestGaugeFuncHandler(t *testing.T) {
+	r := NewRegistry()
+	size := 1024.0
+	r.GaugeFunc("sweep_store_bytes", "Store size.", func() float64 { return size })
+	size = 2048
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "# TYPE sweep_store_bytes gauge\nsweep_store_bytes 2048\n")
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+	srv, err := NewServer(Config{
+		SocketPath:  socketPath,
+		DataDir:     filepath.Join(tmpDir, "data"),
+		MetricsAddr: "127.0.0.1:0",
+	})
+	require.NoError(t, err)
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := sweepv1.NewSweepDaemonClient(conn)
+
+	_, err = client.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: root})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return srv.store.HasIndex(root) && !srv.service.isIndexing() },
+		5*time.Second, 20*time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b.bin"), nil, 0o644))
+
+	url := "http://" + srv.MetricsAddr().String() + "/metrics"
+	var body string
+	require.Eventually(t, func() bool {
+		_, body = get(t, url, nil)
+		return strings.Contains(body, `sweepd_watcher_events_total{op="create"}`)
+	}, 5*time.Second, 50*time.Millisecond, "the watcher counts the new file")
+
+	assert.Contains(t, body, "sweepd_files_indexed_total 1\n")
+	assert.Contains(t, body, `sweepd_index_duration_seconds_count{result="ok"} 1`)
+	assert.Contains(t, body, `sweepd_rpc_duration_seconds_count{method="/sweep.v1.SweepDaemon/TriggerIndex",code="OK"} 1`)
+	assert.Contains(t, body, "# TYPE sweepd_store_size_bytes gauge")
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+)
+
+// errPageToken is returned for a page token that is malformed or was
+// issued for a different query.
+var errPageToken = errors.New("invalid page token; start again from the first page")
+
+// pageToken returns the token for the page of req's results starting at
+// offset. Tokens hold the offset and a hash of the query, so one can't be
+// used to continue a different query. Pages are offsets into the results
+// when each page is requested: files added or removed in between can shift
+// a file onto the previous or next page.
+func pageToken(req *sweepv1.GetLargeFilesRequest, offset int) string {
+	raw := fmt.Sprintf("%d.%x", offset, queryHash(req))
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// pageOffset returns where the page req.PageToken asks for starts, or 0
+// for a first page.
+func pageOffset(req *sweepv1.GetLargeFilesRequest) (int, error) {
+	if req.GetPageToken() == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(req.GetPageToken())
+	if err != nil {
+		return 0, errPageToken
+	}
+	offsetStr, hash, ok := strings.Cut(string(raw), ".")
+	if !ok || hash != fmt.Sprintf("%x", queryHash(req)) {
+		return 0, errPageToken
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, errPageToken
+	}
+	return offset, nil
+}
+
+// queryHash hashes the fields of req that select and order files.
+func queryHash(req *sweepv1.GetLargeFilesRequest) uint64 {
+	query := proto.Clone(req).(*sweepv1.GetLargeFilesRequest)
+	query.Limit = 0
+	query.PageToken = ""
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(query)
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return h.Sum64()
+}
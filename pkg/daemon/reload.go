@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// Settings are the parts of the daemon's configuration a reload applies
+// while it runs. Everything else takes a restart.
+type Settings struct {
+	LogLevel         string            // Default log level
+	LogComponents    map[string]string // Per-component log levels
+	MinLargeFileSize int64             // Large files index threshold (0 = the default)
+	IndexPaths       []string          // Directories indexed and watched besides saved watches
+	Alerts           []alert.Threshold
+	AlertActions     alert.Actions
+	Hooks            []Hook
+}
+
+// ErrNoReload is returned by Reload when the daemon was started without a
+// way to read its configuration again.
+var ErrNoReload = errors.New("the daemon has no configuration to reload")
+
+// configSettle is how long the config file must go without changing before
+// it is reloaded, so an editor's several writes cause one reload.
+var configSettle = 500 * time.Millisecond
+
+// Reload reads the configuration again with Config.LoadSettings and applies
+// the settings that changed since they were last applied. Settings that
+// can't be applied are reported and left as they were; a configuration
+// that can't be read changes nothing.
+func (s *Server) Reload(ctx context.Context) (*sweepv1.ReloadConfigResponse, error) {
+	if s.cfg.LoadSettings == nil {
+		return nil, ErrNoReload
+	}
+	next, err := s.cfg.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	prev := s.settings
+	resp := &sweepv1.ReloadConfigResponse{}
+	fail := func(setting string, err error) {
+		if st, ok := status.FromError(err); ok {
+			err = errors.New(st.Message())
+		}
+		resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", setting, err))
+	}
+
+	if next.LogLevel != prev.LogLevel || !maps.Equal(next.LogComponents, prev.LogComponents) {
+		if err := logging.SetLevels(next.LogLevel, next.LogComponents); err != nil {
+			fail("logging", err)
+			next.LogLevel, next.LogComponents = prev.LogLevel, prev.LogComponents
+		} else {
+			resp.Changed = append(resp.Changed, "logging")
+		}
+	}
+
+	if next.MinLargeFileSize <= 0 {
+		next.MinLargeFileSize = s.largeFileThreshold
+	}
+	if next.MinLargeFileSize != prev.MinLargeFileSize {
+		_, err := s.service.SetMinIndexSize(ctx, &sweepv1.SetMinIndexSizeRequest{Size: next.MinLargeFileSize})
+		if err != nil {
+			fail("min_index_size", err)
+			next.MinLargeFileSize = prev.MinLargeFileSize
+		} else {
+			resp.Changed = append(resp.Changed, "min_index_size")
+		}
+	}
+
+	if !slices.Equal(next.IndexPaths, prev.IndexPaths) {
+		resp.Changed = append(resp.Changed, "index_paths")
+		resp.Unwatched, err = s.unwatchConfigured(prev.IndexPaths, next.IndexPaths)
+		if err != nil {
+			fail("index_paths", err)
+		}
+		resp.Indexing = s.indexConfigured(prev.IndexPaths, next.IndexPaths)
+	}
+
+	if !reflect.DeepEqual(next.Alerts, prev.Alerts) || next.AlertActions != prev.AlertActions {
+		s.startAlerts(next.Alerts, next.AlertActions)
+		resp.Changed = append(resp.Changed, "alerts")
+	}
+
+	// Hooks without a size follow the threshold
+	if !reflect.DeepEqual(next.Hooks, prev.Hooks) || next.MinLargeFileSize != prev.MinLargeFileSize {
+		s.startHooks(next.Hooks, next.MinLargeFileSize)
+		if !reflect.DeepEqual(next.Hooks, prev.Hooks) {
+			resp.Changed = append(resp.Changed, "hooks")
+		}
+	}
+
+	s.settings = next
+	return resp, nil
+}
+
+// indexConfigured indexes the paths in next that aren't in prev, one after
+// another, and returns them. Paths already indexed or being indexed, such
+// as saved watches, are left alone.
+func (s *Server) indexConfigured(prev, next []string) []string {
+	var added []string
+	s.service.indexMu.RLock()
+	for _, path := range next {
+		if _, known := s.service.indexStates[path]; !known && !slices.Contains(prev, path) {
+			added = append(added, path)
+		}
+	}
+	s.service.indexMu.RUnlock()
+	if len(added) > 0 {
+		s.service.queueIndex(added)
+		go s.service.indexStaggered(s.watcherCtx, added, s.cfg.IndexStagger)
+	}
+	return added
+}
+
+// unwatchConfigured stops watching the paths in prev that aren't in next
+// or saved watches, keeping their indexes, and returns them.
+func (s *Server) unwatchConfigured(prev, next []string) ([]string, error) {
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	var errs []error
+	for _, path := range prev {
+		if slices.Contains(next, path) || slices.Contains(saved, path) {
+			continue
+		}
+		if err := s.service.unwatch(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, path)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// startAlerts checks thresholds until the watcher stops or alerts are
+// started again, replacing the thresholds checked before.
+func (s *Server) startAlerts(thresholds []alert.Threshold, actions alert.Actions) {
+	if s.stopAlerts != nil {
+		s.stopAlerts()
+		s.stopAlerts = nil
+	}
+	if len(thresholds) == 0 {
+		return
+	}
+	interval := s.cfg.AlertInterval
+	if interval <= 0 {
+		interval = DefaultAlertInterval
+	}
+	ctx, stop := context.WithCancel(s.watcherCtx)
+	s.stopAlerts = stop
+	go s.checkAlerts(ctx, thresholds, actions, interval)
+}
+
+// startHooks runs hooks until the watcher stops or hooks are started
+// again, replacing the hooks run before.
+func (s *Server) startHooks(hooks []Hook, minSize int64) {
+	if s.stopHooks != nil {
+		s.stopHooks()
+	}
+	ctx, stop := context.WithCancel(s.watcherCtx)
+	s.stopHooks = stop
+	s.runHooks(ctx, hooks, minSize)
+}
+
+// watchConfig reloads the configuration whenever path changes, until ctx
+// is canceled. Its directory is watched rather than the file, since
+// editors often replace a file instead of writing to it.
+func (s *Server) watchConfig(ctx context.Context, path string) {
+	log := logging.Get("daemon")
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Warn("can't watch config file for changes", "path", path, "error", err)
+		return
+	}
+	defer w.Close()
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		log.Warn("can't watch config file for changes", "path", path, "error", err)
+		return
+	}
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == path && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				settle = time.After(configSettle)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			log.Warn("config file watcher error", "error", err)
+		case <-settle:
+			settle = nil
+			resp, err := s.Reload(ctx)
+			if err != nil {
+				log.Warn("failed to reload config, keeping the current settings", "path", path, "error", err)
+				s.service.errors.add("config", path, err)
+				continue
+			}
+			logReload(resp)
+		}
+	}
+}
+
+// logReload logs what a reload changed.
+func logReload(resp *sweepv1.ReloadConfigResponse) {
+	log := logging.Get("daemon")
+	for _, msg := range resp.GetErrors() {
+		log.Warn("failed to apply config setting", "error", msg)
+	}
+	if len(resp.GetChanged()) == 0 {
+		log.Debug("reloaded config, nothing changed")
+		return
+	}
+	log.Info("reloaded config", "changed", resp.GetChanged(), "indexing", resp.GetIndexing(), "unwatched", resp.GetUnwatched())
+}
+
+// ReloadConfig reads the configuration again and applies what changed.
+func (s *Service) ReloadConfig(ctx context.Context, _ *sweepv1.ReloadConfigRequest) (*sweepv1.ReloadConfigResponse, error) {
+	if s.reload == nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrNoReload.Error())
+	}
+	resp, err := s.reload(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to reload config: %v", err)
+	}
+	logReload(resp)
+	return resp, nil
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// reloadable is a configuration tests change between reloads.
+type reloadable struct {
+	mu       sync.Mutex
+	settings Settings
+	err      error
+	loads    int
+}
+
+func (r *reloadable) set(s Settings, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.settings, r.err = s, err
+}
+
+func (r *reloadable) load() (Settings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loads++
+	return r.settings, r.err
+}
+
+func (r *reloadable) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loads
+}
+
+// newReloadServer starts a server reloading its settings from r.
+func newReloadServer(t *testing.T, r *reloadable, configPath string) *Server {
+	t.Helper()
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{
+		SocketPath:   filepath.Join(tmpDir, "test.sock"),
+		DataDir:      filepath.Join(tmpDir, "data"),
+		LogLevel:     "info",
+		LoadSettings: r.load,
+		ConfigPath:   configPath,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+func TestServerReload(t *testing.T) {
+	r := &reloadable{}
+	srv := newReloadServer(t, r, "")
+	ctx := context.Background()
+
+	first, second := t.TempDir(), t.TempDir()
+	srv.IndexPaths([]string{first})
+	require.Eventually(t, func() bool { return srv.store.HasIndex(first) && !srv.service.isIndexing() },
+		5*time.Second, 20*time.Millisecond)
+
+	r.set(Settings{
+		LogLevel:         "info",
+		MinLargeFileSize: types.MiB,
+		IndexPaths:       []string{second},
+		Alerts:           []alert.Threshold{{Path: second, MaxSize: types.GiB}},
+		Hooks:            []Hook{{Path: second, Exec: "true"}},
+	}, nil)
+	resp, err := srv.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"min_index_size", "index_paths", "alerts", "hooks"}, resp.GetChanged())
+	assert.Equal(t, []string{second}, resp.GetIndexing())
+	assert.Equal(t, []string{first}, resp.GetUnwatched())
+	assert.Empty(t, resp.GetErrors())
+	assert.Equal(t, types.MiB, srv.service.indexer.MinLargeFileSize)
+	require.Eventually(t, func() bool { return srv.store.HasIndex(second) && !srv.service.isIndexing() },
+		5*time.Second, 20*time.Millisecond, "paths added to the config are indexed")
+	srv.service.indexMu.RLock()
+	_, watched := srv.service.indexStates[first]
+	srv.service.indexMu.RUnlock()
+	assert.False(t, watched, "paths removed from the config aren't watched")
+	assert.True(t, srv.store.HasIndex(first), "their index is kept")
+
+	resp, err = srv.Reload(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetChanged(), "nothing changed since the last reload")
+
+	bad := r.settings
+	bad.LogLevel = "loud"
+	r.set(bad, nil)
+	resp, err = srv.Reload(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetChanged())
+	require.Len(t, resp.GetErrors(), 1)
+	assert.Contains(t, resp.GetErrors()[0], "logging: ")
+
+	r.set(Settings{}, errors.New("yaml: line 3: mapping values are not allowed"))
+	_, err = srv.Reload(ctx)
+	assert.Error(t, err, "a config that can't be read changes nothing")
+	_, err = srv.service.ReloadConfig(ctx, &sweepv1.ReloadConfigRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerReloadRefused(t *testing.T) {
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{SocketPath: filepath.Join(tmpDir, "test.sock"), DataDir: filepath.Join(tmpDir, "data")})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	_, err = srv.Reload(context.Background())
+	assert.ErrorIs(t, err, ErrNoReload)
+	_, err = srv.service.ReloadConfig(context.Background(), &sweepv1.ReloadConfigRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServerReloadsChangedConfig(t *testing.T) {
+	settle := configSettle
+	configSettle = 10 * time.Millisecond
+	t.Cleanup(func() { configSettle = settle })
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	r := &reloadable{settings: Settings{LogLevel: "info"}}
+	newReloadServer(t, r, configPath)
+	time.Sleep(50 * time.Millisecond) // Let the watcher start
+
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(configPath), "other.yaml"), []byte("x: 1\n"), 0o644))
+	require.NoError(t, os.WriteFile(configPath, []byte("logging:\n  level: info\n"), 0o644))
+	require.Eventually(t, func() bool { return r.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, r.count(), "only the config file's changes reload it")
+}
@@ -2,26 +2,133 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
 	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/daemon/hasher"
 	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
+	"github.com/jamesainslie/sweep/pkg/daemon/ipc"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
 	"github.com/jamesainslie/sweep/pkg/daemon/watcher"
+	"github.com/jamesainslie/sweep/pkg/sweep/alert"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
 // Config holds daemon configuration.
 type Config struct {
 	SocketPath       string
 	DataDir          string
-	MinLargeFileSize int64 // Threshold for large files index (0 = use default)
+	MinLargeFileSize int64               // Threshold for large files index (0 = use default)
+	HashWarmer       bool                // Hash new large files in the background while idle
+	IndexMode        indexer.Mode        // What new indexes store (empty = indexer.ModeFull)
+	Symlinks         types.SymlinkPolicy // Symlinked directories indexes follow (empty = none)
+	IncludeSnapshots bool                // Index and watch filesystem snapshots, such as .zfs/snapshot
+	OneFileSystem    bool                // Index and watch only each path's own file system, except at CrossMounts
+	SkipMounts       []string            // Mount points never indexed or watched
+	CrossMounts      []string            // Mount points indexed even with OneFileSystem
+	Network          mounts.Network      // Which paths are on network file systems
+	NetworkWorkers   int                 // Walkers indexing one (0 = tuner.DefaultNetworkFileWorkers)
+	WatchNetwork     bool                // Watch indexed network file systems for changes
+	StoreBackend     string              // store.BackendBadger (or empty) or store.BackendSQLite
+
+	// ListenAddr is an optional TCP address for remote clients, served in
+	// addition to the socket. RemoteTLS is required with it.
+	ListenAddr string
+	RemoteTLS  *tls.Config
+
+	// HTTPAddr is an optional TCP address serving large files and watch
+	// events as JSON over HTTP. HTTPToken is required unless it is loopback;
+	// HTTPOrigins lists browser origins allowed to call it.
+	HTTPAddr    string
+	HTTPToken   string
+	HTTPOrigins []string
+
+	// MetricsAddr is an optional TCP address serving Prometheus metrics at
+	// /metrics.
+	MetricsAddr string
+
+	// MaxStoreSize is a soft limit on the index store's size in bytes. Above
+	// it, the least recently queried root's files are evicted. 0 = no limit.
+	MaxStoreSize int64
+
+	// MaxResults caps GetLargeFiles requests without a limit (0 = use
+	// DefaultMaxResults).
+	MaxResults int
+
+	// ReadOnly refuses DeleteFiles requests.
+	ReadOnly bool
+
+	// PermanentRoots are the directories under which DeleteFiles may
+	// delete files permanently (trash.permanent_roots).
+	PermanentRoots []string
+
+	// Backends configure the staging and upload delete backends. Files in
+	// the staging directory are purged after Backends.Staging.Days.
+	Backends trash.BackendOptions
+
+	// SnapshotInterval is how often each indexed root's disk usage is saved
+	// for GetSizeDiff (0 = no snapshots). Snapshots older than
+	// SnapshotRetention are deleted (0 = keep them).
+	SnapshotInterval  time.Duration
+	SnapshotRetention time.Duration
+
+	// WatchSuggestions is what to do with roots clients use often that
+	// aren't saved watches (empty = SuggestWatches).
+	WatchSuggestions WatchSuggestions
+
+	// CompactInterval is how often stale records are dropped from the index
+	// store and its space reclaimed (0 = only on request).
+	CompactInterval time.Duration
+
+	// Throttle slows indexing down (nil = as fast as the disk allows).
+	Throttle *indexer.Throttle
+
+	// IndexStagger is how long IndexPaths waits after indexing one path
+	// before it starts the next (0 = no wait).
+	IndexStagger time.Duration
+
+	// HashWorkers is how many files ComputeHashes requests hash at once
+	// (0 = hasher.DefaultWorkers).
+	HashWorkers int
+
+	// Alerts are disk usage thresholds checked every AlertInterval (0 =
+	// DefaultAlertInterval); crossing one sends an alert with AlertActions,
+	// and logs it.
+	Alerts        []alert.Threshold
+	AlertActions  alert.Actions
+	AlertInterval time.Duration
+
+	// Hooks are run for the file events they match.
+	Hooks []Hook
+
+	// LogLevel and LogComponents are the log levels the daemon started
+	// with, so a reload can tell whether they changed.
+	LogLevel      string
+	LogComponents map[string]string
+
+	// LoadSettings reads the configuration again for Reload (nil = reloads
+	// are refused). The daemon reloads whenever ConfigPath changes, if set.
+	LoadSettings func() (Settings, error)
+	ConfigPath   string
+
+	// Version is the daemon's version, reported by GetDaemonStatus.
+	Version string
 }
 
 // MigrationStatus represents the current migration state.
@@ -38,13 +145,27 @@ type Server struct {
 	cfg         Config
 	grpc        *grpc.Server
 	listener    net.Listener
-	store       *store.Store
+	remote      *grpc.Server // Serves remote clients over TCP with mutual TLS
+	remoteLn    net.Listener
+	http        *http.Server // Serves the JSON API for web dashboards
+	httpLn      net.Listener
+	metrics     *http.Server // Serves Prometheus metrics
+	metricsLn   net.Listener
+	store       store.StorageBackend
 	service     *Service
 	broadcaster *broadcaster.Broadcaster
 	watcher     *watcher.Watcher
+	warmer      *hasher.Warmer
 	watcherCtx  context.Context
 	watcherStop context.CancelFunc
 
+	// Settings in effect, changed by Reload
+	reloadMu           sync.Mutex
+	settings           Settings
+	largeFileThreshold int64 // The default for Settings.MinLargeFileSize
+	stopAlerts         context.CancelFunc
+	stopHooks          context.CancelFunc
+
 	// Migration state
 	migrationMu     sync.RWMutex
 	migrationStatus MigrationStatus
@@ -61,28 +182,71 @@ func NewServer(cfg Config) (*Server, error) {
 		return nil, err
 	}
 
-	// Remove stale socket if exists
-	if err := os.RemoveAll(cfg.SocketPath); err != nil {
+	// Create the local listener: a Unix socket, or a named pipe on Windows
+	listener, err := ipc.Listen(cfg.SocketPath)
+	if err != nil {
 		return nil, err
 	}
 
-	// Ensure socket directory exists
-	if err := os.MkdirAll(filepath.Dir(cfg.SocketPath), 0755); err != nil {
-		return nil, err
+	// Create the TCP listener for remote clients
+	var lc net.ListenConfig
+	var remoteLn net.Listener
+	if cfg.ListenAddr != "" {
+		if cfg.RemoteTLS == nil {
+			_ = listener.Close()
+			return nil, ErrRemoteTLSRequired
+		}
+		remoteLn, err = lc.Listen(context.Background(), "tcp", cfg.ListenAddr)
+		if err != nil {
+			_ = listener.Close()
+			return nil, err
+		}
 	}
 
-	// Create Unix socket listener
-	var lc net.ListenConfig
-	listener, err := lc.Listen(context.Background(), "unix", cfg.SocketPath)
-	if err != nil {
-		return nil, err
+	// Create the TCP listener for the HTTP API
+	var httpLn net.Listener
+	if cfg.HTTPAddr != "" {
+		err = checkHTTPAddr(cfg.HTTPAddr, cfg.HTTPToken)
+		if err == nil {
+			httpLn, err = lc.Listen(context.Background(), "tcp", cfg.HTTPAddr)
+		}
+		if err != nil {
+			_ = listener.Close()
+			if remoteLn != nil {
+				_ = remoteLn.Close()
+			}
+			return nil, err
+		}
+	}
+
+	// Create the TCP listener for metrics
+	var metricsLn net.Listener
+	if cfg.MetricsAddr != "" {
+		metricsLn, err = lc.Listen(context.Background(), "tcp", cfg.MetricsAddr)
+		if err != nil {
+			_ = listener.Close()
+			if remoteLn != nil {
+				_ = remoteLn.Close()
+			}
+			if httpLn != nil {
+				_ = httpLn.Close()
+			}
+			return nil, err
+		}
+	}
+	closeListeners := func() {
+		_ = listener.Close()
+		for _, ln := range []net.Listener{remoteLn, httpLn, metricsLn} {
+			if ln != nil {
+				_ = ln.Close()
+			}
+		}
 	}
 
 	// Open the store
-	dbPath := filepath.Join(cfg.DataDir, "index.db")
-	st, err := store.Open(dbPath)
+	st, err := store.OpenBackend(cfg.StoreBackend, cfg.DataDir)
 	if err != nil {
-		_ = listener.Close()
+		closeListeners()
 		return nil, err
 	}
 
@@ -99,11 +263,15 @@ func NewServer(cfg Config) (*Server, error) {
 	w, err := watcher.New(st)
 	if err != nil {
 		_ = st.Close()
-		_ = listener.Close()
+		closeListeners()
 		return nil, err
 	}
 	w.SetBroadcaster(bc)
 	w.SetMinLargeFileSize(largeFileThreshold)
+	w.SetAggregates(cfg.IndexMode == indexer.ModeAggregates)
+	w.SetIncludeSnapshots(cfg.IncludeSnapshots)
+	w.SetMountBoundary(cfg.OneFileSystem, cfg.SkipMounts, cfg.CrossMounts)
+	w.SetNetwork(cfg.Network, cfg.WatchNetwork)
 
 	// Create context for watcher goroutine
 	watcherCtx, watcherStop := context.WithCancel(context.Background())
@@ -114,12 +282,40 @@ func NewServer(cfg Config) (*Server, error) {
 	// Create service with broadcaster and optional config
 	svc := NewServiceWithBroadcaster(st, bc)
 	svc.indexer.MinLargeFileSize = largeFileThreshold
+	if cfg.IndexMode != "" {
+		svc.indexer.Mode = cfg.IndexMode
+	}
+	if cfg.MaxResults > 0 {
+		svc.MaxResults = cfg.MaxResults
+	}
+	svc.hashes = hasher.NewPool(st, cfg.HashWorkers)
+	svc.indexer.Symlinks = cfg.Symlinks
+	svc.indexer.IncludeSnapshots = cfg.IncludeSnapshots
+	svc.indexer.OneFileSystem = cfg.OneFileSystem
+	svc.indexer.SkipMounts = cfg.SkipMounts
+	svc.indexer.CrossMounts = cfg.CrossMounts
+	svc.indexer.Network = cfg.Network
+	svc.indexer.NetworkWorkers = cfg.NetworkWorkers
+	svc.indexer.Throttle = cfg.Throttle
+	svc.ReadOnly = cfg.ReadOnly
+	svc.PermanentRoots = trash.NewPermanentRoots(cfg.PermanentRoots)
+	svc.Backends = cfg.Backends
+	svc.WatchSuggestions = cfg.WatchSuggestions
+	svc.version = cfg.Version
+	svc.backend = cfg.StoreBackend
 	svc.SetWatcher(w)
+	w.SetErrorHandler(func(err error) { svc.errors.add("watcher", "", err) })
 	svc.SetShutdownChan(shutdownChan)
 
+	var grpcOpts []grpc.ServerOption
+	if metricsLn != nil {
+		svc.metrics = newDaemonMetrics(st)
+		grpcOpts = svc.metrics.serverOptions()
+	}
+
 	srv := &Server{
 		cfg:          cfg,
-		grpc:         grpc.NewServer(),
+		grpc:         grpc.NewServer(grpcOpts...),
 		listener:     listener,
 		store:        st,
 		service:      svc,
@@ -128,13 +324,74 @@ func NewServer(cfg Config) (*Server, error) {
 		watcherCtx:   watcherCtx,
 		watcherStop:  watcherStop,
 		shutdownChan: shutdownChan,
+		settings: Settings{
+			LogLevel:         cfg.LogLevel,
+			LogComponents:    cfg.LogComponents,
+			MinLargeFileSize: largeFileThreshold,
+			Alerts:           cfg.Alerts,
+			AlertActions:     cfg.AlertActions,
+			Hooks:            cfg.Hooks,
+		},
+		largeFileThreshold: largeFileThreshold,
+	}
+	svc.migrating = srv.IsMigrating
+	if cfg.LoadSettings != nil {
+		svc.reload = srv.Reload
 	}
 
 	// Register gRPC service
 	sweepv1.RegisterSweepDaemonServer(srv.grpc, svc)
+	if remoteLn != nil {
+		srv.remote = grpc.NewServer(append(grpcOpts, grpc.Creds(credentials.NewTLS(cfg.RemoteTLS)))...)
+		srv.remoteLn = remoteLn
+		sweepv1.RegisterSweepDaemonServer(srv.remote, svc)
+	}
+	if httpLn != nil {
+		srv.http = &http.Server{
+			Handler:           newHTTPHandler(svc, cfg.HTTPToken, cfg.HTTPOrigins),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		srv.httpLn = httpLn
+	}
+	var onChange func(string, fsnotify.Op)
+	if metricsLn != nil {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", svc.metrics.registry.Handler())
+		srv.metrics = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		srv.metricsLn = metricsLn
+		onChange = svc.metrics.watcherEvent
+	}
 
 	// Start watcher event loop in background
-	go srv.watcher.Run(srv.watcherCtx, nil)
+	go srv.watcher.Run(srv.watcherCtx, onChange)
+
+	// Start the hash warmer, sharing the watcher's lifetime
+	if cfg.HashWarmer {
+		srv.warmer = hasher.NewWarmer(svc.hashes)
+		srv.warmer.Busy = func() bool { return svc.isIndexing() || srv.IsMigrating() }
+		svc.SetWarmer(srv.warmer)
+		go srv.warmer.Run(srv.watcherCtx)
+		go srv.feedWarmer(srv.watcherCtx, largeFileThreshold)
+	}
+
+	if cfg.MaxStoreSize > 0 {
+		go srv.enforceStoreLimit(srv.watcherCtx, cfg.MaxStoreSize)
+	}
+	if cfg.SnapshotInterval > 0 {
+		go srv.takeSnapshots(srv.watcherCtx, cfg.SnapshotInterval, cfg.SnapshotRetention)
+	}
+	if cfg.CompactInterval > 0 {
+		go srv.compactStore(srv.watcherCtx, cfg.CompactInterval)
+	}
+	if cfg.Backends.Staging.Dir != "" && cfg.Backends.Staging.Days > 0 {
+		go srv.purgeStaging(srv.watcherCtx, cfg.Backends.Staging)
+	}
+	go srv.checkVolumes(srv.watcherCtx)
+	srv.startAlerts(cfg.Alerts, cfg.AlertActions)
+	srv.startHooks(cfg.Hooks, largeFileThreshold)
+	if cfg.ConfigPath != "" && cfg.LoadSettings != nil {
+		go srv.watchConfig(srv.watcherCtx, filepath.Clean(cfg.ConfigPath))
+	}
 
 	// Check if migration is needed and start it in background
 	if st.NeedsMigration() {
@@ -150,11 +407,92 @@ func NewServer(cfg Config) (*Server, error) {
 	return srv, nil
 }
 
-// Serve starts the gRPC server. Blocks until stopped.
+// Serve starts the gRPC server, and the remote, HTTP, and metrics servers
+// when configured. Blocks until stopped.
 func (s *Server) Serve() error {
+	if s.remote != nil {
+		go func() {
+			if err := s.remote.Serve(s.remoteLn); err != nil {
+				logging.Get("daemon").Error("remote server error", "error", err)
+			}
+		}()
+	}
+	if s.http != nil {
+		go func() {
+			if err := s.http.Serve(s.httpLn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logging.Get("daemon").Error("HTTP server error", "error", err)
+			}
+		}()
+	}
+	if s.metrics != nil {
+		go func() {
+			if err := s.metrics.Serve(s.metricsLn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logging.Get("daemon").Error("metrics server error", "error", err)
+			}
+		}()
+	}
 	return s.grpc.Serve(s.listener)
 }
 
+// RemoteAddr returns the TCP address serving remote clients, or nil.
+func (s *Server) RemoteAddr() net.Addr {
+	if s.remoteLn == nil {
+		return nil
+	}
+	return s.remoteLn.Addr()
+}
+
+// HTTPAddr returns the TCP address serving the HTTP API, or nil.
+func (s *Server) HTTPAddr() net.Addr {
+	if s.httpLn == nil {
+		return nil
+	}
+	return s.httpLn.Addr()
+}
+
+// MetricsAddr returns the TCP address serving metrics, or nil.
+func (s *Server) MetricsAddr() net.Addr {
+	if s.metricsLn == nil {
+		return nil
+	}
+	return s.metricsLn.Addr()
+}
+
+// IndexPaths starts indexing the given directories, and those saved with
+// AddWatch, in the background: one after another, Config.IndexStagger
+// apart, so the daemon doesn't swamp the disk as the machine starts.
+// GetDaemonStatus reports those waiting as queued. Each directory is
+// watched once its index completes.
+func (s *Server) IndexPaths(paths []string) {
+	log := logging.Get("daemon")
+	s.reloadMu.Lock()
+	s.settings.IndexPaths = slices.Clone(paths)
+	s.reloadMu.Unlock()
+	// Drives mounted somewhere new since the daemon last ran move first
+	s.service.reattachVolumes(context.Background())
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		log.Warn("failed to read saved watches", "error", err)
+	}
+	for _, path := range saved {
+		if slices.Contains(paths, path) {
+			continue
+		}
+		// Kept for when the directory comes back, like a drive plugged in again
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			log.Warn("saved watch is not a directory, skipping", "path", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return
+	}
+	log.Info("queued configured paths for indexing", "count", len(paths), "stagger", s.cfg.IndexStagger)
+	s.service.queueIndex(paths)
+	go s.service.indexStaggered(s.watcherCtx, paths, s.cfg.IndexStagger)
+}
+
 // ShutdownChan returns a channel that receives when shutdown is requested via RPC.
 func (s *Server) ShutdownChan() <-chan struct{} {
 	return s.shutdownChan
@@ -175,6 +513,16 @@ func (s *Server) Close() error {
 		_ = s.watcher.Close()
 	}
 
+	if s.remote != nil {
+		s.remote.GracefulStop()
+	}
+	if s.http != nil {
+		// Watch streams never finish on their own, so don't wait for them
+		_ = s.http.Close()
+	}
+	if s.metrics != nil {
+		_ = s.metrics.Close()
+	}
 	s.grpc.GracefulStop()
 	if s.broadcaster != nil {
 		s.broadcaster.Close()
@@ -237,8 +585,42 @@ func (s *Server) startMigration(threshold int64) {
 
 		if err != nil {
 			log.Error("migration failed", "error", err)
+			s.service.errors.add("store", "", err)
 		} else {
 			log.Info("migration completed", "migrations_run", count)
 		}
 	}()
 }
+
+// feedWarmer forwards filesystem events to the hash warmer: new or changed
+// large files are queued, deleted files are forgotten, and every event
+// counts as activity that postpones hashing.
+func (s *Server) feedWarmer(ctx context.Context, minSize int64) {
+	sub := s.broadcaster.Subscribe("", 0, nil)
+	if sub == nil {
+		return
+	}
+	defer s.broadcaster.Unsubscribe(sub.ID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			switch event.Type {
+			case broadcaster.EventDeleted, broadcaster.EventRenamed:
+				s.warmer.Forget(event.Path)
+				s.warmer.Touch()
+			default:
+				if event.Size >= minSize {
+					s.warmer.Enqueue(event.Path)
+				} else {
+					s.warmer.Touch()
+				}
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package daemon_test
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/jamesainslie/sweep/pkg/daemon"
@@ -22,3 +24,15 @@ func TestNewServer(t *testing.T) {
 		t.Fatal("Expected non-nil server")
 	}
 }
+
+func TestNewServerListenRequiresTLS(t *testing.T) {
+	cfg := daemon.Config{
+		SocketPath: filepath.Join(t.TempDir(), "sweep.sock"),
+		DataDir:    t.TempDir(),
+		ListenAddr: "127.0.0.1:0",
+	}
+
+	if _, err := daemon.NewServer(cfg); !errors.Is(err, daemon.ErrRemoteTLSRequired) {
+		t.Fatalf("expected ErrRemoteTLSRequired, got %v", err)
+	}
+}
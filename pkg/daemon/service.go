@@ -2,10 +2,13 @@ package daemon
 
 import (
 	"context"
+	"maps"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
@@ -14,12 +17,18 @@ import (
 
 	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
 	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
+	"github.com/jamesainslie/sweep/pkg/daemon/hasher"
 	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
 	"github.com/jamesainslie/sweep/pkg/daemon/tree"
 	"github.com/jamesainslie/sweep/pkg/daemon/watcher"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
 	"github.com/jamesainslie/sweep/pkg/sweep/filter"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
 )
 
 // indexState tracks the state of an index operation.
@@ -29,44 +38,106 @@ type indexState struct {
 	files    int64
 	dirs     int64
 	current  string
+	size     int64     // Bytes found by the last index
+	indexed  time.Time // When the last index completed
+	err      string    // Why the last index failed
 }
 
 // Service implements the SweepDaemon gRPC service.
 type Service struct {
 	sweepv1.UnimplementedSweepDaemonServer
 
-	store       *store.Store
+	store       store.StorageBackend
 	indexer     *indexer.Indexer
 	broadcaster *broadcaster.Broadcaster
 	watcher     *watcher.Watcher
+	warmer      *hasher.Warmer
+	hashes      *hasher.Pool
+	metrics     *daemonMetrics // nil unless metrics are served
 	startTime   time.Time
+	version     string
+	backend     string // Config.StoreBackend, as reported by GetDaemonStatus
+	errors      errorLog
 
-	// Track indexing state per path
+	// Track indexing state per path, and paths waiting their turn to be
+	// indexed at startup
 	indexMu     sync.RWMutex
 	indexStates map[string]*indexState
+	queued      map[string]bool
 
 	// Shutdown signaling
 	shutdownChan chan<- struct{}
+
+	storeLimitWarned bool // Warned that nothing is left to evict
+
+	// Held for reading while a path is indexed and for writing while the
+	// store is compacted, so neither starts during the other
+	compactMu     sync.RWMutex
+	migrating     func() bool  // Reports a schema migration running; nil if none can
+	lastCompacted atomic.Int64 // Unix seconds
+
+	// Reads the configuration again and applies it; nil refuses reloads
+	reload func(context.Context) (*sweepv1.ReloadConfigResponse, error)
+
+	// MaxResults caps GetLargeFiles requests that don't set a limit, so a
+	// client can't stream millions of files by accident.
+	MaxResults int
+
+	// ReadOnly refuses requests that modify files.
+	ReadOnly bool
+
+	// PermanentRoots are where DeleteFiles may delete files permanently;
+	// empty refuses permanent deletes.
+	PermanentRoots trash.PermanentRoots
+
+	// Backends configure the staging and upload backends DeleteFiles may
+	// be asked for; those left unset are refused.
+	Backends trash.BackendOptions
+
+	// WatchSuggestions is what to do with roots clients use often that
+	// aren't saved watches; empty means SuggestWatches.
+	WatchSuggestions WatchSuggestions
+
+	// Uses of indexed roots, and roots not to suggest, since the daemon
+	// started
+	suggestMu sync.Mutex
+	rootUses  map[string]*rootUse
+	dismissed map[string]bool
+
+	// volumes lists the mounted volumes with UUIDs; mounted is each
+	// recorded volume's mount point at the last check
+	volumes  func() ([]mounts.Volume, error)
+	volumeMu sync.Mutex
+	mounted  map[string]string
 }
 
+// DefaultMaxResults is the default for Service.MaxResults.
+const DefaultMaxResults = 10000
+
 // NewService creates a new gRPC service.
-func NewService(s *store.Store) *Service {
+func NewService(s store.StorageBackend) *Service {
 	return &Service{
 		store:       s,
 		indexer:     indexer.New(s),
+		hashes:      hasher.NewPool(s, 0),
 		startTime:   time.Now(),
 		indexStates: make(map[string]*indexState),
+		MaxResults:  DefaultMaxResults,
+		volumes:     mounts.Volumes,
 	}
 }
 
 // NewServiceWithBroadcaster creates a new gRPC service with a broadcaster.
-func NewServiceWithBroadcaster(s *store.Store, b *broadcaster.Broadcaster) *Service {
+func NewServiceWithBroadcaster(s store.StorageBackend, b *broadcaster.Broadcaster) *Service {
 	return &Service{
 		store:       s,
 		indexer:     indexer.New(s),
+		hashes:      hasher.NewPool(s, 0),
 		broadcaster: b,
 		startTime:   time.Now(),
 		indexStates: make(map[string]*indexState),
+		MaxResults:  DefaultMaxResults,
+		volumes:     mounts.Volumes,
 	}
 }
 
@@ -75,6 +146,23 @@ func (s *Service) SetWatcher(w *watcher.Watcher) {
 	s.watcher = w
 }
 
+// SetWarmer sets the background hash warmer for the service.
+func (s *Service) SetWarmer(w *hasher.Warmer) {
+	s.warmer = w
+}
+
+// isIndexing reports whether any path is currently being indexed.
+func (s *Service) isIndexing() bool {
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	for _, state := range s.indexStates {
+		if state.state == sweepv1.IndexState_INDEX_STATE_INDEXING {
+			return true
+		}
+	}
+	return false
+}
+
 // SetShutdownChan sets the channel to signal shutdown requests.
 func (s *Service) SetShutdownChan(ch chan<- struct{}) {
 	s.shutdownChan = ch
@@ -82,6 +170,7 @@ func (s *Service) SetShutdownChan(ch chan<- struct{}) {
 
 // requestToFilter converts a GetLargeFilesRequest to a filter.Filter.
 // This allows the daemon to apply server-side filtering using the filter package.
+// The request's limit is left to GetLargeFiles, which reports truncation.
 func requestToFilter(req *sweepv1.GetLargeFilesRequest) *filter.Filter {
 	var opts []filter.Option
 
@@ -90,13 +179,6 @@ func requestToFilter(req *sweepv1.GetLargeFilesRequest) *filter.Filter {
 		opts = append(opts, filter.WithMinSize(req.GetMinSize()))
 	}
 
-	// Limit
-	limit := int(req.GetLimit())
-	if limit == 0 {
-		limit = 1000 // Default limit
-	}
-	opts = append(opts, filter.WithLimit(limit))
-
 	// Pattern filters
 	if len(req.GetInclude()) > 0 {
 		opts = append(opts, filter.WithInclude(req.GetInclude()...))
@@ -172,47 +254,84 @@ func storeEntryToFilterInfo(e *store.Entry, root string) filter.FileInfo {
 func (s *Service) GetLargeFiles(req *sweepv1.GetLargeFilesRequest, stream grpc.ServerStreamingServer[sweepv1.FileInfo]) error {
 	root := req.GetPath()
 	minSize := req.GetMinSize()
+	s.touchRoot(root)
 
 	// Warn if query minSize is below the index threshold
-	if minSize < s.indexer.MinLargeFileSize {
+	if threshold := s.minIndexSize(); minSize < threshold {
 		log := logging.Get("daemon")
 		log.Warn("query minSize below index threshold - results may be incomplete",
 			"query_min_size", minSize,
-			"index_threshold", s.indexer.MinLargeFileSize,
+			"index_threshold", threshold,
 			"hint", "configure daemon.min_index_size in config or use --no-daemon")
 	}
 
-	// Build the filter from request
-	f := requestToFilter(req)
-
-	// Query a larger set from the store to allow for filtering
-	// We fetch more than the limit to ensure we have enough after filtering
-	fetchLimit := f.Limit * 10
-	if fetchLimit < 10000 {
-		fetchLimit = 10000 // Minimum fetch to ensure good filtering coverage
+	// Requests without a limit get the daemon's default rather than
+	// everything, and learn that the results were cut short
+	limit := int(req.GetLimit())
+	explicit := limit > 0
+	if !explicit {
+		limit = s.MaxResults
+	}
+	if limit <= 0 {
+		limit = DefaultMaxResults
+	}
+	offset, err := pageOffset(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Query the large files index (populated during indexing or migration)
-	entries, err := s.store.GetLargeFiles(root, minSize, fetchLimit)
+	// Query the large files index (populated during indexing or migration).
+	// Everything under root is read so the limit keeps the right files.
+	entries, err := s.store.GetLargeFiles(stream.Context(), root, minSize, 0)
 	if err != nil {
-		return err
+		return storeError(stream.Context(), err)
 	}
 
 	// Convert store entries to filter.FileInfo
 	fileInfos := make([]filter.FileInfo, 0, len(entries))
+	shared := make(map[string]*sharing.Info)
+	online := make(map[string]*cloud.Info)
 	for _, e := range entries {
 		fileInfos = append(fileInfos, storeEntryToFilterInfo(e, root))
+		if e.Shared != nil {
+			shared[e.Path] = e.Shared
+		}
+		if e.Cloud != nil {
+			online[e.Path] = e.Cloud
+		}
 	}
 
-	// Apply the filter (match, sort, limit)
+	// Apply the filter (match, sort), then the page and the limit. An
+	// explicit limit takes the first files in the requested order, and
+	// pages continue from there; the default one keeps the largest files,
+	// whatever the order, since a request that doesn't set one also doesn't
+	// ask for size descending.
+	f := requestToFilter(req)
 	filtered := f.Apply(fileInfos)
+	filtered = filtered[min(offset, len(filtered)):]
+	truncated := len(filtered) > limit
+	var nextPage string
+	if truncated {
+		if explicit {
+			filtered = filtered[:limit]
+			nextPage = pageToken(req, offset+limit)
+		} else {
+			filtered = f.Sort(filter.New(filter.WithLimit(limit)).Apply(filtered))
+		}
+	}
 
 	// Stream the results
-	for _, fi := range filtered {
+	for i, fi := range filtered {
 		info := &sweepv1.FileInfo{
-			Path:    fi.Path,
-			Size:    fi.Size,
-			ModTime: fi.ModTime.Unix(),
+			Path:      fi.Path,
+			Size:      fi.Size,
+			ModTime:   fi.ModTime.Unix(),
+			Sharing:   sharingToProto(shared[fi.Path]),
+			Cloud:     cloudToProto(online[fi.Path]),
+			Truncated: truncated && i == len(filtered)-1,
+		}
+		if i == len(filtered)-1 {
+			info.NextPageToken = nextPage
 		}
 		if err := stream.Send(info); err != nil {
 			return err
@@ -222,6 +341,37 @@ func (s *Service) GetLargeFiles(req *sweepv1.GetLargeFilesRequest, stream grpc.S
 	return nil
 }
 
+// storeError returns the status for an error from a store query: the
+// request context's error when it ended the query, Internal otherwise.
+func storeError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return status.Errorf(codes.Internal, "failed to read index: %v", err)
+}
+
+// sharingToProto converts a file's sharing to protobuf.
+func sharingToProto(info *sharing.Info) *sweepv1.Sharing {
+	if info == nil {
+		return nil
+	}
+	return &sweepv1.Sharing{
+		Dev:         info.Dev,
+		Ino:         info.Ino,
+		Links:       info.Links,
+		CloneId:     info.CloneID,
+		PrivateSize: info.Private,
+	}
+}
+
+// cloudToProto converts a file's cloud placeholder to protobuf.
+func cloudToProto(info *cloud.Info) *sweepv1.Cloud {
+	if info == nil {
+		return nil
+	}
+	return &sweepv1.Cloud{Provider: info.Provider, OnDiskSize: info.OnDisk}
+}
+
 // GetIndexStatus returns the index status for a path.
 func (s *Service) GetIndexStatus(_ context.Context, req *sweepv1.GetIndexStatusRequest) (*sweepv1.IndexStatus, error) {
 	reqPath := req.GetPath()
@@ -240,12 +390,18 @@ func (s *Service) GetIndexStatus(_ context.Context, req *sweepv1.GetIndexStatusR
 		idxStatus.Progress = state.progress
 		idxStatus.FilesIndexed = state.files
 		idxStatus.DirsIndexed = state.dirs
-	case s.store.HasIndex(reqPath):
+		idxStatus.IndexMode = string(s.indexer.IndexMode())
+	case s.store.HasIndex(reqPath) && !s.evicted(reqPath):
 		idxStatus.State = sweepv1.IndexState_INDEX_STATE_READY
+		// Indexes from before modes existed stored everything
+		idxStatus.IndexMode = string(indexer.ModeFull)
 		// Use cached metadata for fast lookups
 		if meta := s.store.GetIndexMeta(reqPath); meta != nil {
 			idxStatus.FilesIndexed = meta.Files
 			idxStatus.DirsIndexed = meta.Dirs
+			if meta.Mode != "" {
+				idxStatus.IndexMode = meta.Mode
+			}
 		}
 		// If no metadata, counts will be 0 (old index without metadata)
 	default:
@@ -270,12 +426,18 @@ func (s *Service) TriggerIndex(_ context.Context, req *sweepv1.TriggerIndexReque
 		}, nil
 	}
 
+	delete(s.queued, reqPath) // Indexed now rather than in its turn
+
 	// Clear existing if force
 	if req.GetForce() {
 		log.Info("force re-index requested, clearing existing data", "path", reqPath)
 		if err := s.store.DeletePrefix(reqPath); err != nil {
 			log.Debug("failed to clear existing data for force re-index", "path", reqPath, "error", err)
 		}
+		// Otherwise the indexer finds the path covered and keeps nothing
+		if err := s.store.RemoveIndexedPath(reqPath); err != nil {
+			log.Debug("failed to forget indexed path for force re-index", "path", reqPath, "error", err)
+		}
 	}
 
 	s.indexStates[reqPath] = &indexState{
@@ -299,24 +461,40 @@ func (s *Service) TriggerIndex(_ context.Context, req *sweepv1.TriggerIndexReque
 // runIndexing performs the indexing operation in the background.
 func (s *Service) runIndexing(ctx context.Context, path string) {
 	log := logging.Get("indexer")
+	s.compactMu.RLock()
+	defer s.compactMu.RUnlock()
 
+	// Progress is estimated from the entries the last index found
+	var expected int64
+	if meta := s.store.GetIndexMeta(path); meta != nil {
+		expected = meta.Files + meta.Dirs
+	}
 	progress := func(p indexer.Progress) {
 		s.indexMu.Lock()
 		if state, exists := s.indexStates[path]; exists {
 			state.files = p.FilesScanned
 			state.dirs = p.DirsScanned
 			state.current = p.CurrentPath
+			if expected > 0 {
+				state.progress = min(float32(p.FilesScanned+p.DirsScanned)/float32(expected), 0.99)
+			}
 		}
 		s.indexMu.Unlock()
 	}
 
+	start := time.Now()
 	result, err := s.indexer.Index(ctx, path, progress)
+	if s.metrics != nil {
+		s.metrics.indexed(result, err, time.Since(start))
+	}
 
 	s.indexMu.Lock()
 	if err != nil {
 		log.Error("indexing failed", "path", path, "error", err)
+		s.errors.add("indexer", path, err)
 		s.indexStates[path] = &indexState{
 			state: sweepv1.IndexState_INDEX_STATE_STALE,
+			err:   err.Error(),
 		}
 	} else {
 		log.Info("indexing complete", "path", path, "files", result.FilesIndexed, "dirs", result.DirsIndexed)
@@ -325,15 +503,44 @@ func (s *Service) runIndexing(ctx context.Context, path string) {
 			progress: 1.0,
 			files:    result.FilesIndexed,
 			dirs:     result.DirsIndexed,
+			size:     result.TotalSize,
+			indexed:  time.Now(),
 		}
+		_ = s.store.TouchRoot(path, time.Now()) // A new index is not evicted first
 		// Start watching the indexed path for changes
 		if s.watcher != nil {
 			if watchErr := s.watcher.Watch(path); watchErr != nil {
 				log.Warn("failed to start watching indexed path", "path", path, "error", watchErr)
+				s.errors.add("watcher", path, watchErr)
 			}
 		}
 	}
 	s.indexMu.Unlock()
+
+	// A drive's index follows it to another mount point
+	if err == nil {
+		s.rememberVolume(path)
+	}
+
+	// Queue newly indexed large files for background hashing
+	if err == nil && s.warmer != nil {
+		s.queueForHashing(path)
+	}
+}
+
+// queueForHashing schedules the large files under path for the hash warmer.
+func (s *Service) queueForHashing(path string) {
+	entries, err := s.store.GetLargeFiles(context.Background(), path, s.minIndexSize(), 0)
+	if err != nil {
+		logging.Get("hasher").Warn("failed to list large files for hashing", "path", path, "error", err)
+		return
+	}
+	paths := make([]string, 0, len(entries))
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	s.warmer.Enqueue(paths...)
+	logging.Get("hasher").Debug("queued files for hashing", "path", path, "count", len(paths))
 }
 
 // WatchIndexProgress streams indexing progress.
@@ -394,13 +601,139 @@ func (s *Service) GetDaemonStatus(_ context.Context, _ *sweepv1.GetDaemonStatusR
 	}
 	s.indexMu.RUnlock()
 
-	return &sweepv1.DaemonStatus{
+	resp := &sweepv1.DaemonStatus{
 		Running:           true,
 		UptimeSeconds:     int64(time.Since(s.startTime).Seconds()),
 		MemoryBytes:       int64(mem.Alloc),
 		WatchedPaths:      watchedPaths,
+		CacheSizeBytes:    s.store.Size(),
 		TotalFilesIndexed: totalFiles,
-	}, nil
+		HashWarmer:        &sweepv1.HashWarmerStatus{},
+		IndexMode:         string(s.indexer.IndexMode()),
+		Version:           s.version,
+		Roots:             s.rootStatuses(),
+		Watcher:           &sweepv1.WatcherStatus{},
+		Store: &sweepv1.StoreStatus{
+			Backend:       s.backend,
+			SizeBytes:     s.store.Size(),
+			Migrating:     s.migrating != nil && s.migrating(),
+			LastCompacted: s.lastCompacted.Load(),
+		},
+		RecentErrors: s.errors.recent(),
+	}
+	if resp.Store.Backend == "" {
+		resp.Store.Backend = store.BackendBadger
+	}
+	if schema := s.store.GetSchema(); schema != nil {
+		resp.Store.SchemaVersion = int32(schema.Version) //nolint:gosec // Schema versions are small
+	}
+	if s.watcher != nil {
+		st := s.watcher.Stats()
+		resp.Watcher = &sweepv1.WatcherStatus{
+			Directories: int64(st.Dirs),
+			Events:      st.Events,
+			Errors:      st.Errors,
+		}
+		if !st.LastEvent.IsZero() {
+			resp.Watcher.LastEvent = st.LastEvent.Unix()
+		}
+	}
+
+	if s.warmer != nil {
+		st := s.warmer.Stats()
+		resp.HashWarmer = &sweepv1.HashWarmerStatus{
+			Enabled:     true,
+			Active:      st.Active,
+			Queued:      int64(st.Queued),
+			FilesHashed: st.Hashed,
+			BytesHashed: st.BytesHashed,
+			CurrentPath: st.Current,
+		}
+	}
+
+	return resp, nil
+}
+
+// rootStatuses returns the state of every root the daemon indexed, in this
+// run or an earlier one, or is saved to watch, sorted by path.
+func (s *Service) rootStatuses() []*sweepv1.RootStatus {
+	roots := make(map[string]*sweepv1.RootStatus)
+	indexed, err := s.store.GetIndexedPaths()
+	if err != nil {
+		logging.Get("daemon").Debug("failed to list indexed paths for status", "error", err)
+	}
+	for _, path := range indexed {
+		if s.evicted(path) {
+			continue
+		}
+		root := &sweepv1.RootStatus{
+			Path:      path,
+			State:     sweepv1.IndexState_INDEX_STATE_READY,
+			IndexMode: string(indexer.ModeFull),
+		}
+		if meta := s.store.GetIndexMeta(path); meta != nil {
+			root.FilesIndexed = meta.Files
+			root.DirsIndexed = meta.Dirs
+			if meta.Mode != "" {
+				root.IndexMode = meta.Mode
+			}
+		}
+		roots[path] = root
+	}
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		logging.Get("daemon").Debug("failed to read saved watches for status", "error", err)
+	}
+	for _, path := range saved {
+		if _, ok := roots[path]; !ok {
+			roots[path] = &sweepv1.RootStatus{Path: path, State: sweepv1.IndexState_INDEX_STATE_NOT_INDEXED}
+		}
+		roots[path].Saved = true
+	}
+
+	s.indexMu.RLock()
+	for path, state := range s.indexStates {
+		root, ok := roots[path]
+		if !ok {
+			root = &sweepv1.RootStatus{Path: path}
+			roots[path] = root
+		}
+		root.State = state.state
+		root.FilesIndexed = state.files
+		root.DirsIndexed = state.dirs
+		root.TotalSize = state.size
+		root.Error = state.err
+		if state.state == sweepv1.IndexState_INDEX_STATE_INDEXING {
+			root.Progress = state.progress
+			root.CurrentPath = state.current
+		}
+		if !state.indexed.IsZero() {
+			root.LastIndexed = state.indexed.Unix()
+			root.IndexMode = string(s.indexer.IndexMode())
+		}
+	}
+	for path := range s.queued {
+		root, ok := roots[path]
+		if !ok {
+			root = &sweepv1.RootStatus{Path: path, State: sweepv1.IndexState_INDEX_STATE_NOT_INDEXED}
+			roots[path] = root
+		}
+		root.Queued = true
+	}
+	s.indexMu.RUnlock()
+
+	resp := make([]*sweepv1.RootStatus, 0, len(roots))
+	for _, path := range slices.Sorted(maps.Keys(roots)) {
+		root := roots[path]
+		if t := s.store.LastQueried(path); !t.IsZero() {
+			root.LastQueried = t.Unix()
+		}
+		if s.watcher != nil {
+			root.Paused = s.watcher.Paused(path)
+		}
+		resp = append(resp, root)
+	}
+	return resp
 }
 
 // Shutdown gracefully shuts down the daemon.
@@ -481,6 +814,7 @@ func (s *Service) WatchLargeFiles(req *sweepv1.WatchRequest, stream grpc.ServerS
 				Path:    event.Path,
 				Size:    event.Size,
 				ModTime: event.ModTime,
+				NewPath: event.NewPath,
 			}
 			if err := stream.Send(protoEvent); err != nil {
 				return err
@@ -526,6 +860,13 @@ func (s *Service) WatchTree(req *sweepv1.WatchTreeRequest, stream grpc.ServerStr
 				treeEvent.Type = sweepv1.TreeEvent_MODIFIED
 			case broadcaster.EventDeleted:
 				treeEvent.Type = sweepv1.TreeEvent_DELETED
+			case broadcaster.EventRenamed:
+				// A renamed file is removed; its new name is created next
+				treeEvent.Type = sweepv1.TreeEvent_DELETED
+				if event.NewPath != "" {
+					treeEvent.Type = sweepv1.TreeEvent_RENAMED
+					treeEvent.NewPath = event.NewPath
+				}
 			}
 
 			if err := stream.Send(treeEvent); err != nil {
@@ -536,14 +877,15 @@ func (s *Service) WatchTree(req *sweepv1.WatchTreeRequest, stream grpc.ServerStr
 }
 
 // GetTree returns a tree view of large files under a path.
-func (s *Service) GetTree(_ context.Context, req *sweepv1.GetTreeRequest) (*sweepv1.GetTreeResponse, error) {
+func (s *Service) GetTree(ctx context.Context, req *sweepv1.GetTreeRequest) (*sweepv1.GetTreeResponse, error) {
 	root := req.GetRoot()
 	minSize := req.GetMinSize()
+	s.touchRoot(root)
 
 	// Query large files from store
-	entries, err := s.store.GetLargeFiles(root, minSize, 0) // 0 = no limit
+	entries, err := s.store.GetLargeFiles(ctx, root, minSize, 0) // 0 = no limit
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get large files: %v", err)
+		return nil, storeError(ctx, err)
 	}
 
 	// Convert store entries to tree.LargeFile
@@ -568,6 +910,87 @@ func (s *Service) GetTree(_ context.Context, req *sweepv1.GetTreeRequest) (*swee
 	}, nil
 }
 
+// GetDirSizes returns the total size of each directory under a path. In
+// aggregates mode directories already hold their totals; otherwise the
+// indexed files are added up.
+func (s *Service) GetDirSizes(ctx context.Context, req *sweepv1.GetDirSizesRequest) (*sweepv1.GetDirSizesResponse, error) {
+	root := filepath.Clean(req.GetRoot())
+	covered, indexed := s.store.IsPathCovered(root)
+	if covered {
+		_ = s.store.TouchRoot(indexed, time.Now()) // Only orders evictions
+	} else {
+		// Evicted roots keep their directory totals
+		if indexed, covered = s.store.EvictedRoot(root); !covered {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s is not indexed", root)
+		}
+	}
+	dirs, mode, err := s.dirTotals(ctx, root, indexed, int(req.GetMaxDepth()))
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, status.FromContextError(ctxErr).Err()
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read index: %v", err)
+	}
+
+	resp := &sweepv1.GetDirSizesResponse{IndexMode: mode}
+	for _, d := range dirs {
+		resp.Dirs = append(resp.Dirs, &sweepv1.DirSize{
+			Path:    d.Path,
+			Size:    d.Size,
+			Files:   d.Files,
+			Depth:   int32(d.Depth),
+			Dirs:    d.Dirs,
+			Entries: d.Entries,
+		})
+	}
+	return resp, nil
+}
+
+// dirTotals returns the total size of each directory under root, down to
+// maxDepth levels (0 for unlimited), from the index of indexed, which
+// covers root, along with its index mode.
+func (s *Service) dirTotals(ctx context.Context, root, indexed string, maxDepth int) ([]du.Dir, string, error) {
+	mode := string(indexer.ModeFull)
+	if meta := s.store.GetIndexMeta(indexed); meta != nil && meta.Mode != "" {
+		mode = meta.Mode
+	}
+
+	var dirs []du.Dir
+	var visit func(e *store.Entry)
+	totals := du.NewTotals(root, maxDepth)
+	var usage sharing.Counter // Shared storage counts once and placeholders locally, as in aggregates mode
+	if mode == string(indexer.ModeAggregates) {
+		// Every directory is kept until their entries are counted
+		visit = func(e *store.Entry) {
+			if e.IsDir {
+				dirs = append(dirs, du.Dir{Path: e.Path, Size: e.Size, Files: e.Files, Depth: du.Depth(root, e.Path)})
+			}
+		}
+	} else {
+		visit = func(e *store.Entry) {
+			if e.IsDir {
+				totals.AddDir(e.Path)
+			} else {
+				totals.AddFile(e.Path, usage.Add(e.Shared, cloud.Local(e.Cloud, e.Size)))
+			}
+		}
+	}
+
+	err := s.store.Walk(root, func(e *store.Entry) error {
+		visit(e)
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, mode, err
+	}
+	if mode == string(indexer.ModeAggregates) {
+		dirs = du.CountEntries(root, dirs, maxDepth)
+	} else {
+		dirs = totals.Dirs()
+	}
+	return dirs, mode, nil
+}
+
 // nodeToProto recursively converts a tree.Node to a sweepv1.TreeNode.
 func nodeToProto(n *tree.Node) *sweepv1.TreeNode {
 	if n == nil {
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestGetDaemonStatusRoots(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.version = "1.2.3"
+	ctx := context.Background()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+	svc.runIndexing(ctx, root)
+	missing := filepath.Join(t.TempDir(), "missing")
+	svc.runIndexing(ctx, missing)
+
+	status, err := svc.GetDaemonStatus(ctx, &sweepv1.GetDaemonStatusRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", status.GetVersion())
+	assert.Equal(t, store.BackendBadger, status.GetStore().GetBackend())
+
+	roots := make(map[string]*sweepv1.RootStatus)
+	for _, r := range status.GetRoots() {
+		roots[r.GetPath()] = r
+	}
+	require.Contains(t, roots, root)
+	ready := roots[root]
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_READY, ready.GetState())
+	assert.Equal(t, int64(1), ready.GetFilesIndexed())
+	assert.Equal(t, int64(100), ready.GetTotalSize())
+	assert.Positive(t, ready.GetLastIndexed())
+	assert.Empty(t, ready.GetError())
+
+	require.Contains(t, roots, missing)
+	failed := roots[missing]
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_STALE, failed.GetState())
+	assert.NotEmpty(t, failed.GetError())
+
+	require.Len(t, status.GetRecentErrors(), 1)
+	assert.Equal(t, "indexer", status.GetRecentErrors()[0].GetComponent())
+	assert.Equal(t, missing, status.GetRecentErrors()[0].GetPath())
+}
+
+func TestErrorLogKeepsLatest(t *testing.T) {
+	var l errorLog
+	for i := range maxRecentErrors + 5 {
+		l.add("store", "", fmt.Errorf("error %d", i))
+	}
+	recent := l.recent()
+	require.Len(t, recent, maxRecentErrors)
+	assert.Equal(t, "error 5", recent[0].GetMessage(), "the oldest are dropped")
+	assert.Equal(t, fmt.Sprintf("error %d", maxRecentErrors+4), recent[len(recent)-1].GetMessage())
+
+	l.add("watcher", "/data", errors.New("queue overflow"))
+	assert.Len(t, recent, maxRecentErrors, "recent returns a copy")
+}
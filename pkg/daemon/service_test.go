@@ -10,10 +10,13 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
 	"github.com/jamesainslie/sweep/pkg/daemon"
+	"github.com/jamesainslie/sweep/pkg/daemon/indexer"
 )
 
 func createTestFiles(t *testing.T) string {
@@ -109,6 +112,202 @@ func TestServiceGetLargeFiles(t *testing.T) {
 	if len(files) != 2 {
 		t.Errorf("Expected 2 large files, got %d", len(files))
 	}
+	for _, f := range files {
+		if f.GetTruncated() {
+			t.Errorf("%s marked truncated, but every match was returned", f.GetPath())
+		}
+	}
+}
+
+func TestServiceGetLargeFilesMaxResults(t *testing.T) {
+	testDir := createTestFiles(t)
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	cfg := daemon.Config{
+		SocketPath:       socketPath,
+		DataDir:          filepath.Join(tmpDir, "data"),
+		MinLargeFileSize: 5000,
+		MaxResults:       1,
+	}
+
+	srv, err := daemon.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go func() {
+		_ = srv.Serve()
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	client := sweepv1.NewSweepDaemonClient(conn)
+
+	if _, err := client.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: testDir}); err != nil {
+		t.Fatalf("TriggerIndex failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	query := func(limit int32) []*sweepv1.FileInfo {
+		t.Helper()
+		stream, err := client.GetLargeFiles(context.Background(), &sweepv1.GetLargeFilesRequest{
+			Path:    testDir,
+			MinSize: 5000,
+			Limit:   limit,
+		})
+		if err != nil {
+			t.Fatalf("GetLargeFiles failed: %v", err)
+		}
+		var files []*sweepv1.FileInfo
+		for {
+			file, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return files
+			}
+			if err != nil {
+				t.Fatalf("Recv failed: %v", err)
+			}
+			files = append(files, file)
+		}
+	}
+
+	// Without a limit, the daemon's default applies and the cut is flagged
+	files := query(0)
+	if len(files) != 1 {
+		t.Fatalf("Expected max_results to cap the stream at 1 file, got %d", len(files))
+	}
+	if files[0].GetPath() != filepath.Join(testDir, "huge.dat") {
+		t.Errorf("Expected the largest file, got %s", files[0].GetPath())
+	}
+	if !files[0].GetTruncated() {
+		t.Error("Expected the last file to be marked truncated")
+	}
+
+	// An explicit limit overrides the default
+	files = query(5)
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 large files with an explicit limit, got %d", len(files))
+	}
+	if files[1].GetTruncated() {
+		t.Error("Expected no truncation when every match fits the limit")
+	}
+}
+
+func TestServiceGetLargeFilesPages(t *testing.T) {
+	testDir := createTestFiles(t)
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	cfg := daemon.Config{
+		SocketPath:       socketPath,
+		DataDir:          filepath.Join(tmpDir, "data"),
+		MinLargeFileSize: 5000,
+	}
+
+	srv, err := daemon.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go func() {
+		_ = srv.Serve()
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	client := sweepv1.NewSweepDaemonClient(conn)
+
+	if _, err := client.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: testDir}); err != nil {
+		t.Fatalf("TriggerIndex failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	query := func(minSize int64, pageToken string) ([]*sweepv1.FileInfo, error) {
+		t.Helper()
+		stream, err := client.GetLargeFiles(context.Background(), &sweepv1.GetLargeFilesRequest{
+			Path:           testDir,
+			MinSize:        minSize,
+			Limit:          1,
+			SortDescending: true,
+			PageToken:      pageToken,
+		})
+		if err != nil {
+			t.Fatalf("GetLargeFiles failed: %v", err)
+		}
+		var files []*sweepv1.FileInfo
+		for {
+			file, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return files, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+	}
+
+	// Follow the page tokens through both large files, largest first
+	var paths []string
+	token := ""
+	for page := 0; page == 0 || token != ""; page++ {
+		if page > 2 {
+			t.Fatalf("Expected 2 pages, still getting page tokens: %v", paths)
+		}
+		files, err := query(5000, token)
+		if err != nil {
+			t.Fatalf("Page %d failed: %v", page, err)
+		}
+		if len(files) != 1 {
+			t.Fatalf("Expected 1 file on page %d, got %d", page, len(files))
+		}
+		paths = append(paths, files[0].GetPath())
+		token = files[0].GetNextPageToken()
+	}
+	if len(paths) != 2 || paths[0] != filepath.Join(testDir, "huge.dat") || paths[0] == paths[1] {
+		t.Errorf("Expected the 2 large files largest first, got %v", paths)
+	}
+
+	// Tokens are bound to the query they came from
+	files, err := query(5000, "")
+	if err != nil {
+		t.Fatalf("GetLargeFiles failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name    string
+		minSize int64
+		token   string
+	}{
+		{"malformed", 5000, "not a token"},
+		{"other query", 6000, files[0].GetNextPageToken()},
+	} {
+		if _, err := query(tc.minSize, tc.token); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s token: expected InvalidArgument, got %v", tc.name, err)
+		}
+	}
 }
 
 func TestServiceGetIndexStatus(t *testing.T) {
@@ -186,6 +385,152 @@ func TestServiceGetIndexStatus(t *testing.T) {
 	if status.GetFilesIndexed() < 3 {
 		t.Errorf("Expected at least 3 files indexed, got %d", status.GetFilesIndexed())
 	}
+
+	if status.GetIndexMode() != "full" {
+		t.Errorf("Expected full index mode, got %q", status.GetIndexMode())
+	}
+}
+
+func TestServiceGetIndexStatusAggregates(t *testing.T) {
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+	testDir := createTestFiles(t)
+
+	cfg := daemon.Config{
+		SocketPath: socketPath,
+		DataDir:    filepath.Join(tmpDir, "data"),
+		IndexMode:  indexer.ModeAggregates,
+	}
+
+	srv, err := daemon.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	go func() {
+		_ = srv.Serve()
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := sweepv1.NewSweepDaemonClient(conn)
+
+	daemonStatus, err := client.GetDaemonStatus(context.Background(), &sweepv1.GetDaemonStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetDaemonStatus failed: %v", err)
+	}
+	if daemonStatus.GetIndexMode() != "aggregates" {
+		t.Errorf("Expected daemon index mode aggregates, got %q", daemonStatus.GetIndexMode())
+	}
+
+	if _, err := client.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: testDir}); err != nil {
+		t.Fatalf("TriggerIndex failed: %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	status, err := client.GetIndexStatus(context.Background(), &sweepv1.GetIndexStatusRequest{Path: testDir})
+	if err != nil {
+		t.Fatalf("GetIndexStatus failed: %v", err)
+	}
+	if status.GetState() != sweepv1.IndexState_INDEX_STATE_READY {
+		t.Errorf("Expected READY state, got %v", status.GetState())
+	}
+	if status.GetIndexMode() != "aggregates" {
+		t.Errorf("Expected aggregates index mode, got %q", status.GetIndexMode())
+	}
+	if status.GetFilesIndexed() != 3 {
+		t.Errorf("Expected 3 files counted, got %d", status.GetFilesIndexed())
+	}
+}
+
+func TestServiceGetDirSizes(t *testing.T) {
+	for _, mode := range []indexer.Mode{indexer.ModeFull, indexer.ModeAggregates} {
+		t.Run(string(mode), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			socketPath := filepath.Join(tmpDir, "test.sock")
+			testDir := createTestFiles(t)
+			nested := filepath.Join(testDir, "sub", "deeper")
+			if err := os.MkdirAll(nested, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(nested, "nested.bin"), make([]byte, 5000), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			srv, err := daemon.NewServer(daemon.Config{
+				SocketPath: socketPath,
+				DataDir:    filepath.Join(tmpDir, "data"),
+				IndexMode:  mode,
+			})
+			if err != nil {
+				t.Fatalf("NewServer failed: %v", err)
+			}
+			go func() {
+				_ = srv.Serve()
+			}()
+			defer func() {
+				_ = srv.Close()
+			}()
+			time.Sleep(100 * time.Millisecond)
+
+			conn, err := grpc.NewClient(
+				"unix://"+socketPath,
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			if err != nil {
+				t.Fatalf("NewClient failed: %v", err)
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+			client := sweepv1.NewSweepDaemonClient(conn)
+
+			if _, err := client.GetDirSizes(context.Background(), &sweepv1.GetDirSizesRequest{Root: testDir}); status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("Expected FailedPrecondition before indexing, got %v", err)
+			}
+
+			if _, err := client.TriggerIndex(context.Background(), &sweepv1.TriggerIndexRequest{Path: testDir}); err != nil {
+				t.Fatalf("TriggerIndex failed: %v", err)
+			}
+			time.Sleep(500 * time.Millisecond)
+
+			resp, err := client.GetDirSizes(context.Background(), &sweepv1.GetDirSizesRequest{Root: testDir, MaxDepth: 1})
+			if err != nil {
+				t.Fatalf("GetDirSizes failed: %v", err)
+			}
+			if resp.GetIndexMode() != string(mode) {
+				t.Errorf("Expected index mode %s, got %q", mode, resp.GetIndexMode())
+			}
+
+			got := make(map[string]*sweepv1.DirSize)
+			for _, d := range resp.GetDirs() {
+				got[d.GetPath()] = d
+			}
+			if len(got) != 2 {
+				t.Fatalf("Expected the root and sub at depth 1, got %v", resp.GetDirs())
+			}
+			if root := got[testDir]; root.GetSize() != 115100 || root.GetFiles() != 4 || root.GetDepth() != 0 {
+				t.Errorf("Unexpected root totals: %v", root)
+			}
+			if sub := got[filepath.Join(testDir, "sub")]; sub.GetSize() != 5000 || sub.GetFiles() != 1 || sub.GetDepth() != 1 {
+				t.Errorf("Unexpected sub totals: %v", sub)
+			}
+		})
+	}
 }
 
 func TestServiceGetDaemonStatus(t *testing.T) {
@@ -240,6 +585,52 @@ func TestServiceGetDaemonStatus(t *testing.T) {
 	if status.GetMemoryBytes() <= 0 {
 		t.Error("Expected positive memory usage")
 	}
+
+	if status.GetHashWarmer().GetEnabled() {
+		t.Error("Expected hash warmer to be disabled by default")
+	}
+}
+
+func TestServiceGetDaemonStatusHashWarmer(t *testing.T) {
+	tmpDir := t.TempDir()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	srv, err := daemon.NewServer(daemon.Config{
+		SocketPath: socketPath,
+		DataDir:    filepath.Join(tmpDir, "data"),
+		HashWarmer: true,
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	go func() {
+		_ = srv.Serve()
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(
+		"unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	status, err := sweepv1.NewSweepDaemonClient(conn).GetDaemonStatus(context.Background(), &sweepv1.GetDaemonStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetDaemonStatus failed: %v", err)
+	}
+
+	hw := status.GetHashWarmer()
+	if !hw.GetEnabled() || hw.GetActive() || hw.GetFilesHashed() != 0 {
+		t.Errorf("unexpected hash warmer status: %v", hw)
+	}
 }
 
 func TestServiceClearCache(t *testing.T) {
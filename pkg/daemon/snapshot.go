@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/du"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// snapshotMinDirSize is the smallest directory a snapshot records, which
+// keeps snapshots of trees with many small directories small. A directory
+// missing from a snapshot was smaller than this.
+const snapshotMinDirSize = types.MiB
+
+// maxSnapshotCheck is the longest the daemon waits between checks for
+// roots due a snapshot, so new indexes get their first one soon.
+const maxSnapshotCheck = 10 * time.Minute
+
+// snapshotRoots saves the disk usage of each indexed root whose latest
+// snapshot is older than interval, then deletes snapshots older than
+// retention (0 keeps them). Roots being indexed wait for the next check.
+func (s *Service) snapshotRoots(ctx context.Context, now time.Time, interval, retention time.Duration) {
+	log := logging.Get("daemon")
+	roots, err := s.store.GetIndexedPaths()
+	if err != nil {
+		log.Warn("failed to list indexed paths for snapshots", "error", err)
+		return
+	}
+	for _, root := range roots {
+		if s.isIndexingPath(root) {
+			continue
+		}
+		times, err := s.store.SnapshotTimes(root)
+		if err != nil {
+			log.Warn("failed to read snapshots", "path", root, "error", err)
+			continue
+		}
+		if len(times) > 0 && now.Sub(times[len(times)-1]) < interval {
+			continue
+		}
+		snap, err := s.takeSnapshot(ctx, root, now)
+		if err == nil {
+			err = s.store.PutSnapshot(snap)
+		}
+		if err == nil {
+			err = s.store.PutIndexStat(root, indexStat(snap))
+		}
+		if err != nil {
+			log.Warn("failed to save snapshot", "path", root, "error", err)
+			s.errors.add("snapshots", root, err)
+			continue
+		}
+		log.Debug("saved snapshot", "path", root, "dirs", len(snap.Dirs), "files", len(snap.Files))
+	}
+
+	if retention > 0 {
+		if n, err := s.store.PruneSnapshots(now.Add(-retention)); err != nil {
+			log.Warn("failed to delete old snapshots", "error", err)
+		} else if n > 0 {
+			log.Debug("deleted old snapshots", "count", n)
+		}
+	}
+}
+
+// takeSnapshot records the directory totals and large files under an
+// indexed root.
+func (s *Service) takeSnapshot(ctx context.Context, root string, now time.Time) (*store.Snapshot, error) {
+	dirs, _, err := s.dirTotals(ctx, root, root, 0)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.store.GetLargeFiles(ctx, root, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &store.Snapshot{Root: root, Time: now}
+	for _, d := range dirs {
+		if d.Size >= snapshotMinDirSize || d.Path == root {
+			snap.Dirs = append(snap.Dirs, store.SnapshotEntry{Path: d.Path, Size: d.Size, Files: d.Files})
+		}
+	}
+	for _, f := range files {
+		if store.IsPathUnderRoot(f.Path, root) {
+			snap.Files = append(snap.Files, store.SnapshotEntry{Path: f.Path, Size: f.Size})
+		}
+	}
+	return snap, nil
+}
+
+// takeSnapshots checks for roots due a snapshot until ctx is done.
+func (s *Server) takeSnapshots(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(min(interval, maxSnapshotCheck))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.IsMigrating() {
+				s.service.snapshotRoots(ctx, now, interval, retention)
+			}
+		}
+	}
+}
+
+// GetSizeDiff compares the directory totals and large files under a path
+// now with the snapshot of its indexed root closest to the requested age.
+// Directories smaller than snapshotMinDirSize at both times are left out.
+func (s *Service) GetSizeDiff(ctx context.Context, req *sweepv1.GetSizeDiffRequest) (*sweepv1.GetSizeDiffResponse, error) {
+	path := filepath.Clean(req.GetPath())
+	covered, indexed := s.store.IsPathCovered(path)
+	if !covered {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is not indexed", path)
+	}
+	s.touchRoot(path)
+
+	since := time.Now().Add(-time.Duration(req.GetSinceSeconds()) * time.Second)
+	snap, err := s.store.FindSnapshot(indexed, since)
+	if errors.Is(err, store.ErrNoSnapshot) {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"no snapshots of %s yet; the daemon saves one every daemon.snapshot_interval", indexed)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read snapshot: %v", err)
+	}
+
+	maxDepth := int(req.GetMaxDepth())
+	beforeDirs := make(map[string]int64)
+	for _, d := range snap.Dirs {
+		if store.IsPathUnderRoot(d.Path, path) && (maxDepth == 0 || du.Depth(path, d.Path) <= maxDepth) {
+			beforeDirs[d.Path] = d.Size
+		}
+	}
+	beforeFiles := make(map[string]int64)
+	for _, f := range snap.Files {
+		if store.IsPathUnderRoot(f.Path, path) {
+			beforeFiles[f.Path] = f.Size
+		}
+	}
+
+	dirs, _, err := s.dirTotals(ctx, path, indexed, maxDepth)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, status.FromContextError(ctxErr).Err()
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read index: %v", err)
+	}
+	afterDirs := make(map[string]int64, len(dirs))
+	for _, d := range dirs {
+		// The snapshot doesn't know small directories either
+		if _, ok := beforeDirs[d.Path]; ok || d.Size >= snapshotMinDirSize {
+			afterDirs[d.Path] = d.Size
+		}
+	}
+	files, err := s.store.GetLargeFiles(ctx, path, 0, 0)
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+	afterFiles := make(map[string]int64, len(files))
+	for _, f := range files {
+		if store.IsPathUnderRoot(f.Path, path) {
+			afterFiles[f.Path] = f.Size
+		}
+	}
+
+	limit := int(req.GetLimit())
+	return &sweepv1.GetSizeDiffResponse{
+		SnapshotTime: snap.Time.Unix(),
+		Dirs:         sizeChangesToProto(sizediff.Compare(beforeDirs, afterDirs), limit),
+		Files:        sizeChangesToProto(sizediff.Compare(beforeFiles, afterFiles), limit),
+	}, nil
+}
+
+// sizeChangesToProto converts the first limit changes (all for 0).
+func sizeChangesToProto(changes []sizediff.Change, limit int) []*sweepv1.SizeChange {
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	out := make([]*sweepv1.SizeChange, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, &sweepv1.SizeChange{Path: c.Path, Before: c.Before, After: c.After})
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// writeSized creates a sparse file of the given size.
+func writeSized(t *testing.T, path string, size int64) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(size))
+	require.NoError(t, f.Close())
+}
+
+func TestSnapshotRoots(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	root := t.TempDir()
+	writeSized(t, filepath.Join(root, "videos", "a.mkv"), 2*types.MiB)
+	writeSized(t, filepath.Join(root, "notes", "todo.txt"), 100)
+	_, err = svc.indexer.Index(context.Background(), root, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	start := time.Now()
+	svc.snapshotRoots(ctx, start, time.Hour, 0)
+	svc.snapshotRoots(ctx, start.Add(30*time.Minute), time.Hour, 0)
+	times, err := st.SnapshotTimes(root)
+	require.NoError(t, err)
+	require.Len(t, times, 1, "a root isn't snapshotted again within the interval")
+
+	snap, err := st.FindSnapshot(root, start)
+	require.NoError(t, err)
+	dirs := make(map[string]int64)
+	for _, d := range snap.Dirs {
+		dirs[d.Path] = d.Size
+	}
+	assert.Equal(t, map[string]int64{root: 2*types.MiB + 100, filepath.Join(root, "videos"): 2 * types.MiB}, dirs,
+		"small directories are left out of snapshots")
+
+	svc.snapshotRoots(ctx, start.Add(2*time.Hour), time.Hour, 90*time.Minute)
+	times, err = st.SnapshotTimes(root)
+	require.NoError(t, err)
+	assert.Len(t, times, 1, "the next snapshot is taken and the first is past retention")
+}
+
+func TestServiceGetSizeDiff(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	svc.indexer.MinLargeFileSize = types.MiB
+
+	root := t.TempDir()
+	videos := filepath.Join(root, "videos")
+	writeSized(t, filepath.Join(videos, "a.mkv"), 2*types.MiB)
+	writeSized(t, filepath.Join(root, "old", "b.iso"), 3*types.MiB)
+	_, err = svc.indexer.Index(context.Background(), root, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	req := &sweepv1.GetSizeDiffRequest{Path: root, SinceSeconds: int64((24 * time.Hour).Seconds())}
+	_, err = svc.GetSizeDiff(ctx, req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "no snapshot yet")
+
+	taken := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	svc.snapshotRoots(ctx, taken, time.Hour, 0)
+
+	// Two days of changes, as the watcher records them: the videos grew and
+	// the ISO went
+	added := &store.Entry{Path: filepath.Join(videos, "c.mkv"), Size: 5 * types.MiB, ModTime: time.Now().Unix()}
+	require.NoError(t, st.Put(added))
+	require.NoError(t, st.PutLargeFile(added))
+	require.NoError(t, st.Remove(filepath.Join(root, "old")))
+
+	resp, err := svc.GetSizeDiff(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, taken.Unix(), resp.GetSnapshotTime())
+
+	dirs := make(map[string]*sweepv1.SizeChange)
+	for _, c := range resp.GetDirs() {
+		dirs[c.GetPath()] = c
+	}
+	require.Contains(t, dirs, videos)
+	assert.Equal(t, 2*types.MiB, dirs[videos].GetBefore())
+	assert.Equal(t, 7*types.MiB, dirs[videos].GetAfter())
+	require.Contains(t, dirs, filepath.Join(root, "old"))
+	assert.Zero(t, dirs[filepath.Join(root, "old")].GetAfter())
+
+	require.NotEmpty(t, resp.GetFiles())
+	first := resp.GetFiles()[0]
+	assert.Equal(t, filepath.Join(videos, "c.mkv"), first.GetPath(), "the largest change comes first")
+	assert.Zero(t, first.GetBefore())
+
+	req.Limit = 1
+	resp, err = svc.GetSizeDiff(ctx, req)
+	require.NoError(t, err)
+	assert.Len(t, resp.GetDirs(), 1)
+	assert.Len(t, resp.GetFiles(), 1)
+}
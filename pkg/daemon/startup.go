@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// DefaultIndexStagger is the default for Config.IndexStagger.
+const DefaultIndexStagger = 10 * time.Second
+
+// queueIndex marks paths as waiting to be indexed at startup, so
+// GetDaemonStatus reports them before their turn comes.
+func (s *Service) queueIndex(paths []string) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.queued == nil {
+		s.queued = make(map[string]bool)
+	}
+	for _, path := range paths {
+		s.queued[path] = true
+	}
+}
+
+// indexQueued indexes a path queued at startup, returning once it is
+// indexed and watched. It returns false without indexing if a client had
+// the path indexed while it waited.
+func (s *Service) indexQueued(ctx context.Context, path string) bool {
+	s.indexMu.Lock()
+	if !s.queued[path] {
+		s.indexMu.Unlock()
+		return false
+	}
+	delete(s.queued, path)
+	s.indexStates[path] = &indexState{state: sweepv1.IndexState_INDEX_STATE_INDEXING}
+	s.indexMu.Unlock()
+
+	logging.Get("daemon").Info("indexing configured path", "path", path)
+	s.runIndexing(ctx, path)
+	return true
+}
+
+// indexStaggered indexes paths queued at startup one after another,
+// waiting stagger between them so the disk isn't swamped while the
+// machine starts, until ctx is done.
+func (s *Service) indexStaggered(ctx context.Context, paths []string, stagger time.Duration) {
+	ran := false
+	for _, path := range paths {
+		if ran && stagger > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(stagger):
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if s.indexQueued(ctx, path) {
+			ran = true
+		} else {
+			logging.Get("daemon").Debug("configured path was indexed on request", "path", path)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestIndexPathsStaggered(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	for _, root := range []string{first, second} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "big.iso"), make([]byte, 100), 0o644))
+	}
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{
+		SocketPath:   filepath.Join(tmpDir, "test.sock"),
+		DataDir:      filepath.Join(tmpDir, "data"),
+		IndexStagger: time.Hour, // The second waits its turn until the end of the test
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	srv.IndexPaths([]string{first, second})
+	ctx := context.Background()
+	roots := func() map[string]*sweepv1.RootStatus {
+		status, err := srv.service.GetDaemonStatus(ctx, &sweepv1.GetDaemonStatusRequest{})
+		require.NoError(t, err)
+		byPath := make(map[string]*sweepv1.RootStatus)
+		for _, r := range status.GetRoots() {
+			byPath[r.GetPath()] = r
+		}
+		return byPath
+	}
+	require.Eventually(t, func() bool {
+		return roots()[first].GetState() == sweepv1.IndexState_INDEX_STATE_READY
+	}, 5*time.Second, 20*time.Millisecond)
+
+	waiting := roots()[second]
+	require.NotNil(t, waiting, "a queued path is reported")
+	assert.True(t, waiting.GetQueued())
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_NOT_INDEXED, waiting.GetState())
+	assert.False(t, roots()[first].GetQueued())
+
+	// A client asking for the path indexes it without waiting its turn
+	resp, err := srv.service.TriggerIndex(ctx, &sweepv1.TriggerIndexRequest{Path: second})
+	require.NoError(t, err)
+	assert.True(t, resp.GetStarted())
+	assert.False(t, roots()[second].GetQueued())
+	assert.False(t, srv.service.indexQueued(ctx, second), "a path indexed on request isn't indexed again")
+}
+
+func TestRootStatusesReportProgress(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	svc.indexStates["/data"] = &indexState{
+		state:    sweepv1.IndexState_INDEX_STATE_INDEXING,
+		progress: 0.4,
+		current:  "/data/videos",
+	}
+	status, err := svc.GetDaemonStatus(context.Background(), &sweepv1.GetDaemonStatusRequest{})
+	require.NoError(t, err)
+	require.Len(t, status.GetRoots(), 1)
+	root := status.GetRoots()[0]
+	assert.InDelta(t, 0.4, root.GetProgress(), 0.001)
+	assert.Equal(t, "/data/videos", root.GetCurrentPath())
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// indexStat returns the totals of a snapshot's root, from its directory
+// entry.
+func indexStat(snap *store.Snapshot) store.IndexStat {
+	stat := store.IndexStat{Time: snap.Time}
+	for _, d := range snap.Dirs {
+		if d.Path == snap.Root {
+			stat.Size, stat.Files = d.Size, d.Files
+			break
+		}
+	}
+	return stat
+}
+
+// GetIndexHistory returns the totals recorded with each snapshot of the
+// indexed root covering a path, or of every indexed root.
+func (s *Service) GetIndexHistory(ctx context.Context, req *sweepv1.GetIndexHistoryRequest) (*sweepv1.GetIndexHistoryResponse, error) {
+	var roots []string
+	if req.GetPath() == "" {
+		var err error
+		if roots, err = s.store.GetIndexedPaths(); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list indexed paths: %v", err)
+		}
+	} else {
+		path := filepath.Clean(req.GetPath())
+		covered, indexed := s.store.IsPathCovered(path)
+		if !covered {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s is not indexed", path)
+		}
+		roots = []string{indexed}
+	}
+
+	var since time.Time
+	if req.GetSinceSeconds() > 0 {
+		since = time.Now().Add(-time.Duration(req.GetSinceSeconds()) * time.Second)
+	}
+	resp := &sweepv1.GetIndexHistoryResponse{}
+	for _, root := range roots {
+		stats, err := s.store.IndexStats(root, since)
+		if err != nil {
+			return nil, storeError(ctx, err)
+		}
+		history := &sweepv1.IndexHistory{Root: root}
+		for _, st := range stats {
+			history.Points = append(history.Points, &sweepv1.IndexHistoryPoint{
+				Time:  st.Time.Unix(),
+				Size:  st.Size,
+				Files: st.Files,
+			})
+		}
+		resp.Roots = append(resp.Roots, history)
+	}
+	return resp, nil
+}
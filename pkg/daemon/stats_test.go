@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestServiceGetIndexHistory(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+
+	root := t.TempDir()
+	writeSized(t, filepath.Join(root, "videos", "a.mkv"), 2*types.MiB)
+	writeSized(t, filepath.Join(root, "notes", "todo.txt"), 100)
+	_, err = svc.indexer.Index(context.Background(), root, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	resp, err := svc.GetIndexHistory(ctx, &sweepv1.GetIndexHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRoots(), 1)
+	assert.Empty(t, resp.GetRoots()[0].GetPoints(), "nothing is recorded before the first snapshot")
+
+	first := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	svc.snapshotRoots(ctx, first, time.Hour, 0)
+	added := &store.Entry{Path: filepath.Join(root, "videos", "b.mkv"), Size: 3 * types.MiB, ModTime: time.Now().Unix()}
+	require.NoError(t, st.Put(added))
+	require.NoError(t, st.PutLargeFile(added))
+	second := time.Now().Add(-time.Hour).Truncate(time.Second)
+	svc.snapshotRoots(ctx, second, time.Hour, 0)
+
+	resp, err = svc.GetIndexHistory(ctx, &sweepv1.GetIndexHistoryRequest{Path: filepath.Join(root, "videos")})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRoots(), 1)
+	history := resp.GetRoots()[0]
+	assert.Equal(t, root, history.GetRoot(), "a path is charted by its indexed root")
+	require.Len(t, history.GetPoints(), 2)
+	assert.Equal(t, first.Unix(), history.GetPoints()[0].GetTime())
+	assert.Equal(t, 2*types.MiB+100, history.GetPoints()[0].GetSize())
+	assert.Equal(t, int64(2), history.GetPoints()[0].GetFiles())
+	assert.Equal(t, 5*types.MiB+100, history.GetPoints()[1].GetSize())
+	assert.Equal(t, int64(3), history.GetPoints()[1].GetFiles())
+
+	day := int64((24 * time.Hour).Seconds())
+	resp, err = svc.GetIndexHistory(ctx, &sweepv1.GetIndexHistoryRequest{Path: root, SinceSeconds: day})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRoots()[0].GetPoints(), 1, "only totals recorded in the last day")
+	assert.Equal(t, second.Unix(), resp.GetRoots()[0].GetPoints()[0].GetTime())
+
+	_, err = svc.GetIndexHistory(ctx, &sweepv1.GetIndexHistoryRequest{Path: t.TempDir()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
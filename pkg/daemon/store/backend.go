@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// Storage backends selectable with daemon.store_backend.
+const (
+	BackendBadger = "badger" // The default: a Badger key-value store
+	BackendSQLite = "sqlite" // A SQLite database, for ad-hoc SQL over the index
+)
+
+// StorageBackend is the index storage used by the daemon. Store implements
+// it with Badger; the SQLite backend keeps the same data in tables that can
+// be queried with the sqlite3 shell while the daemon runs.
+type StorageBackend interface {
+	Close() error
+	Size() int64
+	ReclaimSpace()
+	Compact(ctx context.Context) (CompactResult, error)
+	Check(ctx context.Context) (CheckResult, error)
+
+	GetSchema() *Schema
+	SetSchema(schema *Schema) error
+	NeedsMigration() bool
+	Migrate(ctx context.Context, largeFileThreshold int64, onProgress MigrationProgressFunc) (int, error)
+
+	Put(entry *Entry) error
+	PutBatch(entries []*Entry) error
+	Get(path string) (*Entry, error)
+	Delete(path string) error
+	DeletePrefix(prefix string) error
+	Remove(path string) error
+	Move(oldPath, newPath string) (int, error)
+	GetIndexedRoot(root string) (*Entry, error)
+	CountEntries(prefix string) (files, dirs int64, err error)
+	Walk(root string, fn func(*Entry) error) error
+	HasIndex(root string) bool
+
+	GetLargeFiles(ctx context.Context, root string, minSize int64, limit int) ([]*Entry, error)
+	AddLargeFile(path string, size, modTime int64) error
+	PutLargeFile(f *Entry) error
+	AddLargeFileBatch(files []*Entry) error
+	RemoveLargeFile(path string) error
+	PruneLargeFiles(root string, minSize int64) (int, error)
+	RebuildLargeFilesIndex(root string, minSize int64) (int, error)
+	HasLargeFilesIndex(root string) bool
+
+	SetIndexMeta(root string, meta *IndexMeta) error
+	GetIndexMeta(root string) *IndexMeta
+	AddIndexedPath(path string) error
+	RemoveIndexedPath(path string) error
+	GetIndexedPaths() ([]string, error)
+	AddIndexedPathWithSubsumption(path string) ([]string, error)
+	IsPathCovered(path string) (bool, string)
+
+	TouchRoot(root string, t time.Time) error
+	LastQueried(root string) time.Time
+	Evict(root string, dirs []*Entry, files []string) error
+	EvictedRoot(path string) (string, bool)
+
+	PutHashes(e HashEntry) error
+	GetHashes(path string, size, modTime int64) (HashEntry, bool)
+	RemoveHash(path string) error
+
+	PutSnapshot(snap *Snapshot) error
+	SnapshotTimes(root string) ([]time.Time, error)
+	FindSnapshot(root string, t time.Time) (*Snapshot, error)
+	PruneSnapshots(t time.Time) (int, error)
+
+	PutIndexStat(root string, stat IndexStat) error
+	IndexStats(root string, since time.Time) ([]IndexStat, error)
+
+	AddWatchedRoot(root string) error
+	RemoveWatchedRoot(root string) (bool, error)
+	GetWatchedRoots() ([]string, error)
+
+	SetVolumeMount(uuid, mountPoint string) error
+	GetVolumeMounts() (map[string]string, error)
+}
+
+var _ StorageBackend = (*Store)(nil)
+
+// OpenBackend opens or creates the store of the given backend in dataDir:
+// index.db for Badger, index.sqlite for SQLite. An empty backend is Badger.
+// The two don't share data; switching backends starts a new, empty index.
+func OpenBackend(backend, dataDir string) (StorageBackend, error) {
+	switch backend {
+	case "", BackendBadger:
+		st, err := Open(filepath.Join(dataDir, "index.db"))
+		if err != nil {
+			return nil, err
+		}
+		return st, nil
+	case BackendSQLite:
+		return openSQLite(filepath.Join(dataDir, "index.sqlite"))
+	default:
+		return nil, fmt.Errorf("unknown store backend %q (want %s or %s)", backend, BackendBadger, BackendSQLite)
+	}
+}
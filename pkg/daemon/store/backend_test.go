@@ -0,0 +1,475 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
+)
+
+// backends lists the storage backends built into the test binary; the
+// SQLite backend adds itself when built with -tags sqlite.
+var backends = []string{store.BackendBadger}
+
+// forEachBackend runs fn against a new store of each backend.
+func forEachBackend(t *testing.T, fn func(t *testing.T, s store.StorageBackend)) {
+	for _, backend := range backends {
+		t.Run(backend, func(t *testing.T) {
+			s, err := store.OpenBackend(backend, t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenBackend(%s) failed: %v", backend, err)
+			}
+			defer s.Close()
+			fn(t, s)
+		})
+	}
+}
+
+func TestOpenBackendUnknown(t *testing.T) {
+	if _, err := store.OpenBackend("postgres", t.TempDir()); err == nil {
+		t.Error("OpenBackend(postgres) succeeded, want an error")
+	}
+}
+
+func TestBackendEntries(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		shared := &sharing.Info{Links: 2}
+		entries := []*store.Entry{
+			{Path: "/data", IsDir: true, Size: 7000, Files: 2, Children: []string{"/data/a.iso", "/data/sub"}},
+			{Path: "/data/a.iso", Size: 5000, ModTime: 10, Shared: shared},
+			{Path: "/data/sub", IsDir: true},
+			{Path: "/data/sub/b.bin", Size: 2000, ModTime: 20},
+			{Path: "/data2/c.bin", Size: 9000},
+		}
+		if err := s.PutBatch(entries); err != nil {
+			t.Fatalf("PutBatch failed: %v", err)
+		}
+
+		got, err := s.Get("/data")
+		if err != nil || !reflect.DeepEqual(got, entries[0]) {
+			t.Errorf("Get(/data) = %+v, %v; want %+v", got, err, entries[0])
+		}
+		if got, err := s.Get("/data/a.iso"); err != nil || got.Shared == nil || got.Shared.Links != 2 {
+			t.Errorf("Get(/data/a.iso) = %+v, %v; want its sharing", got, err)
+		}
+		if _, err := s.Get("/missing"); err == nil {
+			t.Error("Get(/missing) succeeded")
+		}
+
+		var walked []string
+		if err := s.Walk("/data", func(e *store.Entry) error {
+			walked = append(walked, e.Path)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"/data", "/data/a.iso", "/data/sub", "/data/sub/b.bin"}; !reflect.DeepEqual(walked, want) {
+			t.Errorf("Walk(/data) = %v, want %v", walked, want)
+		}
+
+		files, dirs, err := s.CountEntries("/data/")
+		if err != nil || files != 2 || dirs != 1 {
+			t.Errorf("CountEntries(/data/) = %d, %d, %v; want 2, 1", files, dirs, err)
+		}
+
+		if err := s.Remove("/data/sub"); err != nil {
+			t.Fatal(err)
+		}
+		if s.HasIndex("/data/sub/b.bin") || !s.HasIndex("/data/a.iso") {
+			t.Error("Remove(/data/sub) didn't remove just the subtree")
+		}
+		if err := s.DeletePrefix("/data"); err != nil {
+			t.Fatal(err)
+		}
+		if s.HasIndex("/data") || s.HasIndex("/data2/c.bin") {
+			t.Error("DeletePrefix(/data) left entries sharing the prefix")
+		}
+	})
+}
+
+func TestBackendLargeFiles(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		if err := s.AddLargeFileBatch([]*store.Entry{
+			{Path: "/a/medium.bin", Size: 1000, ModTime: 1},
+			{Path: "/a/large.bin", Size: 10000, ModTime: 2},
+			{Path: "/a/sub/huge.bin", Size: 100000, ModTime: 3},
+			{Path: "/ab/other.bin", Size: 50000},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		large, err := s.GetLargeFiles(context.Background(), "/a/", 5000, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, f := range large {
+			paths = append(paths, f.Path)
+		}
+		if want := []string{"/a/sub/huge.bin", "/a/large.bin"}; !reflect.DeepEqual(paths, want) {
+			t.Errorf("GetLargeFiles(/a/, 5000) = %v, want %v", paths, want)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := s.GetLargeFiles(ctx, "/a/", 0, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetLargeFiles with a cancelled context error = %v, want context.Canceled", err)
+		}
+		if !s.HasLargeFilesIndex("/a/") || s.HasLargeFilesIndex("/b/") {
+			t.Error("HasLargeFilesIndex is wrong")
+		}
+
+		if n, err := s.PruneLargeFiles("/a", 5000); err != nil || n != 1 {
+			t.Errorf("PruneLargeFiles(/a, 5000) = %d, %v; want 1", n, err)
+		}
+		if moved, err := s.Move("/a/sub", "/a/renamed"); err != nil || moved != 0 {
+			t.Errorf("Move = %d, %v; want no entries moved", moved, err)
+		}
+		if large, _ = s.GetLargeFiles(context.Background(), "/a", 0, 1); len(large) != 1 {
+			t.Errorf("GetLargeFiles(/a, limit 1) = %+v, want one file", large)
+		}
+		if err := s.RemoveLargeFile("/ab/other.bin"); err != nil {
+			t.Fatal(err)
+		}
+		large, _ = s.GetLargeFiles(context.Background(), "/a", 0, 0)
+		if len(large) != 2 || large[0].Path != "/a/renamed/huge.bin" {
+			t.Errorf("GetLargeFiles(/a) after the move = %+v", large)
+		}
+
+		if err := s.Put(&store.Entry{Path: "/c/disc.iso", Size: 8000}); err != nil {
+			t.Fatal(err)
+		}
+		if n, err := s.RebuildLargeFilesIndex("/c", 5000); err != nil || n != 1 {
+			t.Errorf("RebuildLargeFilesIndex(/c) = %d, %v; want 1", n, err)
+		}
+	})
+}
+
+func TestBackendCloudPlaceholders(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		online := &cloud.Info{Provider: cloud.ProviderICloud, OnDisk: 0}
+		files := []*store.Entry{
+			{Path: "/icloud/film.mov", Size: 9000, Cloud: online},
+			{Path: "/icloud/linked.mov", Size: 8000, Shared: &sharing.Info{Links: 2}, Cloud: online},
+			{Path: "/icloud/local.mov", Size: 7000},
+		}
+		if err := s.PutBatch(files); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddLargeFileBatch(files); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err := s.Get("/icloud/film.mov"); err != nil || !reflect.DeepEqual(got.Cloud, online) {
+			t.Errorf("Get(film.mov) = %+v, %v; want its placeholder", got, err)
+		}
+		large, err := s.GetLargeFiles(context.Background(), "/icloud", 0, 0)
+		if err != nil || len(large) != 3 {
+			t.Fatalf("GetLargeFiles(/icloud) = %+v, %v", large, err)
+		}
+		for i, f := range large {
+			if !reflect.DeepEqual(f, files[i]) {
+				t.Errorf("GetLargeFiles()[%d] = %+v, want %+v", i, f, files[i])
+			}
+		}
+	})
+}
+
+func TestBackendIndexedPathsAndEviction(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		for _, p := range []string{"/data/photos", "/data/music"} {
+			if err := s.AddIndexedPath(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		subsumed, err := s.AddIndexedPathWithSubsumption("/data")
+		if err != nil || len(subsumed) != 2 {
+			t.Errorf("AddIndexedPathWithSubsumption(/data) = %v, %v; want both children", subsumed, err)
+		}
+		if covered, by := s.IsPathCovered("/data/photos/2024"); !covered || by != "/data" {
+			t.Errorf("IsPathCovered = %v, %q; want /data", covered, by)
+		}
+
+		if err := s.SetIndexMeta("/data", &store.IndexMeta{Files: 3, Dirs: 1, Mode: "full"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PutBatch([]*store.Entry{{Path: "/data", IsDir: true}, {Path: "/data/a.iso", Size: 9000}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PutHashes(store.HashEntry{Path: "/data/a.iso", Size: 9000, ModTime: 1, Sum: []byte{1}}); err != nil {
+			t.Fatal(err)
+		}
+		now := time.Unix(1700000000, 0)
+		if err := s.TouchRoot("/data", now); err != nil || !s.LastQueried("/data").Equal(now) {
+			t.Errorf("LastQueried after TouchRoot = %v, %v", s.LastQueried("/data"), err)
+		}
+
+		if err := s.Evict("/data", []*store.Entry{{Path: "/data", IsDir: true, Size: 9000, Files: 1}}, []string{"/data/a.iso"}); err != nil {
+			t.Fatal(err)
+		}
+		if meta := s.GetIndexMeta("/data"); meta == nil || meta.Mode != "aggregates" || meta.Files != 3 {
+			t.Errorf("GetIndexMeta after Evict = %+v", meta)
+		}
+		if root, ok := s.EvictedRoot("/data/a.iso"); !ok || root != "/data" {
+			t.Errorf("EvictedRoot = %q, %v; want /data", root, ok)
+		}
+		if paths, _ := s.GetIndexedPaths(); len(paths) != 0 {
+			t.Errorf("GetIndexedPaths after Evict = %v, want none", paths)
+		}
+		if _, ok := s.GetHashes("/data/a.iso", 9000, 1); ok || s.HasIndex("/data/a.iso") {
+			t.Error("Evict kept files or hashes")
+		}
+
+		if err := s.AddIndexedPath("/data"); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.EvictedRoot("/data/a.iso"); ok {
+			t.Error("indexing an evicted root again didn't clear it")
+		}
+	})
+}
+
+func TestBackendHashesSnapshotsAndWatches(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		if s.NeedsMigration() {
+			t.Error("an empty store needs migration")
+		}
+		for _, h := range []store.HashEntry{
+			{Path: "/d/a", Size: 10, ModTime: 1, Sum: []byte{1}},
+			{Path: "/d/b", Size: 10, ModTime: 1, Sum: []byte{1}},
+			{Path: "/d/c", Size: 20, ModTime: 1, Sum: []byte{2}},
+		} {
+			if err := s.PutHashes(h); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, ok := s.GetHashes("/d/a", 10, 2); ok {
+			t.Error("GetHashes returned a stale hash")
+		}
+		if e, ok := s.GetHashes("/d/a", 10, 1); !ok || e.XXHash != 0 {
+			t.Errorf("GetHashes = %+v, %v; want no xxhash", e, ok)
+		}
+		if e, ok := s.GetHashes("/d/c", 20, 1); !ok || e.Sum[0] != 2 {
+			t.Errorf("GetHashes = %+v, %v; want the hash put", e, ok)
+		}
+
+		day := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		for i := range 3 {
+			snap := &store.Snapshot{Root: "/d", Time: day.AddDate(0, 0, i), Dirs: []store.SnapshotEntry{{Path: "/d", Size: int64(i)}}}
+			if err := s.PutSnapshot(snap); err != nil {
+				t.Fatal(err)
+			}
+		}
+		snap, err := s.FindSnapshot("/d", day.AddDate(0, 0, 1).Add(time.Hour))
+		if err != nil || snap.Dirs[0].Size != 1 {
+			t.Errorf("FindSnapshot = %+v, %v; want the second", snap, err)
+		}
+		if n, err := s.PruneSnapshots(day.AddDate(0, 0, 2)); err != nil || n != 2 {
+			t.Errorf("PruneSnapshots = %d, %v; want 2", n, err)
+		}
+
+		if err := s.AddWatchedRoot("/d/"); err != nil {
+			t.Fatal(err)
+		}
+		if roots, _ := s.GetWatchedRoots(); !reflect.DeepEqual(roots, []string{"/d"}) {
+			t.Errorf("GetWatchedRoots = %v", roots)
+		}
+		if found, err := s.RemoveWatchedRoot("/d"); !found || err != nil {
+			t.Errorf("RemoveWatchedRoot = %v, %v", found, err)
+		}
+
+		if err := s.SetSchema(&store.Schema{Version: store.CurrentSchemaVersion}); err != nil {
+			t.Fatal(err)
+		}
+		if schema := s.GetSchema(); schema == nil || schema.Version != store.CurrentSchemaVersion {
+			t.Errorf("GetSchema = %+v", schema)
+		}
+	})
+}
+
+func TestBackendHashSums(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		sum := make([]byte, 32)
+		sum[0] = 7
+		for _, e := range []store.HashEntry{
+			{Path: "/d/a", Size: 10, ModTime: 1, Sum: sum, XXHash: 0xfeedface},
+			{Path: "/d/b", Size: 10, ModTime: 1, Sum: sum, XXHash: 0xfeedface},
+		} {
+			if err := s.PutHashes(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		e, ok := s.GetHashes("/d/a", 10, 1)
+		if !ok || e.XXHash != 0xfeedface || string(e.Sum) != string(sum) {
+			t.Errorf("GetHashes = %+v, %v", e, ok)
+		}
+		if _, ok := s.GetHashes("/d/a", 11, 1); ok {
+			t.Error("GetHashes returned a stale hash")
+		}
+	})
+}
+
+func TestBackendIndexStats(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		for i, stat := range []store.IndexStat{
+			{Time: day, Size: 100, Files: 10},
+			{Time: day.Add(24 * time.Hour), Size: 150, Files: 12},
+			{Time: day.Add(48 * time.Hour), Size: 90, Files: 9},
+		} {
+			if err := s.PutIndexStat("/data", stat); err != nil {
+				t.Fatal(err)
+			}
+			// Another root's, and one of a root sharing the prefix
+			if err := s.PutIndexStat("/data2", store.IndexStat{Time: stat.Time, Size: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		stats, err := s.IndexStats("/data", time.Time{})
+		if err != nil || len(stats) != 3 || stats[0].Size != 100 || stats[2].Files != 9 || !stats[1].Time.Equal(day.Add(24*time.Hour)) {
+			t.Fatalf("IndexStats = %+v, %v", stats, err)
+		}
+		stats, err = s.IndexStats("/data", day.Add(time.Hour))
+		if err != nil || len(stats) != 2 || stats[0].Size != 150 {
+			t.Errorf("IndexStats since the second day = %+v, %v", stats, err)
+		}
+		if stats, err := s.IndexStats("/none", time.Time{}); err != nil || len(stats) != 0 {
+			t.Errorf("IndexStats of an unknown root = %+v, %v", stats, err)
+		}
+	})
+}
+
+func TestBackendCompact(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		entries := []*store.Entry{
+			{Path: "/data", IsDir: true},
+			{Path: "/data/a.iso", Size: 5000, ModTime: 10},
+			{Path: "/data/d.iso", Size: 3000, ModTime: 20},
+			{Path: "/gone", IsDir: true},
+			{Path: "/gone/b.bin", Size: 4000, ModTime: 30},
+			{Path: "/cold", IsDir: true},
+		}
+		if err := s.PutBatch(entries); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddLargeFileBatch([]*store.Entry{entries[1], entries[2], entries[4]}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetSchema(&store.Schema{Version: store.CurrentSchemaVersion}); err != nil {
+			t.Fatal(err)
+		}
+		for _, root := range []string{"/data", "/gone"} {
+			if err := s.SetIndexMeta(root, &store.IndexMeta{Files: 2}); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.TouchRoot(root, time.Unix(100, 0)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.AddIndexedPath("/data"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Evict("/cold", []*store.Entry{entries[5]}, nil); err != nil {
+			t.Fatal(err)
+		}
+		sum := []byte{1, 2, 3}
+		for _, h := range []struct {
+			path          string
+			size, modTime int64
+		}{
+			{"/data/a.iso", 5000, 10}, // Current
+			{"/data/d.iso", 3000, 19}, // Hashed before the file changed
+			{"/data/c.iso", 1000, 10}, // File no longer indexed
+		} {
+			if err := s.PutHashes(store.HashEntry{Path: h.path, Size: h.size, ModTime: h.modTime, Sum: sum}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		res, err := s.Compact(context.Background())
+		if err != nil {
+			t.Fatalf("Compact failed: %v", err)
+		}
+		// /gone and /gone/b.bin, its large file, meta, and query time, and
+		// two hashes
+		if res.Pruned != 7 {
+			t.Errorf("Pruned = %d, want 7", res.Pruned)
+		}
+		if res.SizeBefore <= 0 || res.SizeAfter <= 0 {
+			t.Errorf("sizes = %d -> %d, want both measured", res.SizeBefore, res.SizeAfter)
+		}
+
+		for _, path := range []string{"/data", "/data/a.iso", "/data/d.iso", "/cold"} {
+			if _, err := s.Get(path); err != nil {
+				t.Errorf("Get(%s) after Compact: %v", path, err)
+			}
+		}
+		if _, err := s.Get("/gone/b.bin"); err == nil {
+			t.Error("entries outside indexed roots should be pruned")
+		}
+		files, err := s.GetLargeFiles(context.Background(), "/", 0, 0)
+		if err != nil || len(files) != 2 {
+			t.Errorf("large files after Compact = %d, %v; want the 2 under /data", len(files), err)
+		}
+		if s.GetIndexMeta("/gone") != nil || s.GetIndexMeta("/data") == nil || s.GetIndexMeta("/cold") == nil {
+			t.Error("only the metadata of indexed and evicted roots should be kept")
+		}
+		if !s.LastQueried("/gone").IsZero() || s.LastQueried("/data").IsZero() {
+			t.Error("only the query times of indexed and evicted roots should be kept")
+		}
+		if _, ok := s.GetHashes("/data/a.iso", 5000, 10); !ok {
+			t.Error("the current hash should be kept")
+		}
+		if _, ok := s.GetHashes("/data/d.iso", 3000, 19); ok {
+			t.Error("the stale hash should be pruned")
+		}
+		if _, ok := s.GetHashes("/data/c.iso", 1000, 10); ok {
+			t.Error("the hash of a file no longer indexed should be pruned")
+		}
+		if s.GetSchema() == nil {
+			t.Error("the schema version should be kept")
+		}
+	})
+}
+
+func TestBackendCheck(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s store.StorageBackend) {
+		entries := []*store.Entry{
+			{Path: "/data", IsDir: true, Size: 5000, Files: 1, Children: []string{"/data/a.iso"}},
+			{Path: "/data/a.iso", Size: 5000, ModTime: 10},
+		}
+		if err := s.PutBatch(entries); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddLargeFileBatch(entries[1:]); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddIndexedPath("/data"); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := s.Check(context.Background())
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if res.Records == 0 || res.Damaged != 0 || len(res.Problems) != 0 {
+			t.Errorf("Check of a sound store = %+v, want records and no problems", res)
+		}
+
+		if err := s.AddIndexedPath("/lost"); err != nil {
+			t.Fatal(err)
+		}
+		res, err = s.Check(context.Background())
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if res.Damaged != 1 || len(res.Problems) != 1 {
+			t.Errorf("Check with a rootless index = %+v, want one problem", res)
+		}
+	})
+}
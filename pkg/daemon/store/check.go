@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// maxCheckProblems is how many problems a check lists; the rest are only
+// counted.
+const maxCheckProblems = 20
+
+// CheckResult reports what Check found.
+type CheckResult struct {
+	Records  int64    // Records read
+	Problems []string // The first problems found; none means the store is sound
+	Damaged  int64    // Problems found, listed or not
+}
+
+// add records a problem.
+func (r *CheckResult) add(format string, args ...any) {
+	r.Damaged++
+	if len(r.Problems) < maxCheckProblems {
+		r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// Check reads every record in the store and reports those that don't
+// decode, along with indexed roots whose root entry is missing. Reading the
+// whole store takes a while on a large index; it stops with ctx's error if
+// ctx is done first.
+func (s *Store) Check(ctx context.Context) (CheckResult, error) {
+	var res CheckResult
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			item := it.Item()
+			key := string(item.Key())
+			res.Records++
+			err := item.Value(func(val []byte) error {
+				checkRecord(&res, key, val)
+				return nil
+			})
+			if err != nil {
+				res.add("%s: unreadable value: %v", key, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	return res, checkIndexedRoots(s, &res)
+}
+
+// checkRecord checks that the value of the record at key decodes.
+func checkRecord(res *CheckResult, key string, val []byte) {
+	prefix := keyPrefix([]byte(key))
+	path := key[len(prefix):]
+	switch prefix {
+	case "":
+		var entry Entry
+		if err := json.Unmarshal(val, &entry); err != nil {
+			res.add("entry %s doesn't decode: %v", path, err)
+		} else if entry.Path != path {
+			res.add("entry %s records the path %s", path, entry.Path)
+		}
+	case prefixLargeFile:
+		if len(val) < 16 {
+			res.add("large file %s has a truncated record", path)
+		}
+	case prefixMeta:
+		if key != schemaKey && len(val) < 16 {
+			res.add("index metadata of %s is truncated", path)
+		}
+	case prefixHash:
+		if _, ok := decodeHash(path, val); !ok {
+			res.add("cached hash of %s doesn't decode", path)
+		}
+	}
+}
+
+// checkIndexedRoots reports indexed roots without their root entry, whose
+// queries find nothing until they are indexed again.
+func checkIndexedRoots(s StorageBackend, res *CheckResult) error {
+	roots, err := s.GetIndexedPaths()
+	if err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if !s.HasIndex(root) {
+			res.add("indexed root %s has no root entry", root)
+		}
+	}
+	return nil
+}
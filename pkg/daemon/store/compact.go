@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// CompactResult reports what Compact pruned and the space it freed.
+type CompactResult struct {
+	Pruned     int64 // Stale records dropped
+	SizeBefore int64 // Bytes on disk before compacting
+	SizeAfter  int64 // Bytes on disk after
+}
+
+// Reclaimed returns the bytes compacting freed on disk.
+func (r CompactResult) Reclaimed() int64 {
+	return max(r.SizeBefore-r.SizeAfter, 0)
+}
+
+// prefixes lists every key prefix; other keys are entries keyed by path.
+var prefixes = []string{
+	prefixEntry, prefixLargeFile, prefixMeta, prefixIndexedPath, prefixQueried,
+	prefixEvicted, prefixHash, prefixSnapshot, prefixVolume, prefixWatchedRoot,
+	prefixStat,
+}
+
+// keyPrefix returns the prefix of key, or "" for an entry.
+func keyPrefix(key []byte) string {
+	if len(key) < 2 || key[1] != ':' {
+		return ""
+	}
+	for _, p := range prefixes {
+		if string(key[:2]) == p {
+			return p
+		}
+	}
+	return ""
+}
+
+// liveRoots returns whether a path is under one of roots, and whether it
+// is one of them. Compact keeps the records of indexed and evicted roots.
+func liveRoots(roots []string) (covered func(string) bool, isRoot map[string]bool) {
+	isRoot = make(map[string]bool, len(roots))
+	for _, root := range roots {
+		isRoot[root] = true
+	}
+	return func(path string) bool {
+		for _, root := range roots {
+			if IsPathUnderRoot(path, root) {
+				return true
+			}
+		}
+		return false
+	}, isRoot
+}
+
+// Compact drops records that outlived what they describe, then rewrites
+// the store to free the space they and deleted keys took. Stale records
+// are entries and large files outside every indexed or evicted root,
+// metadata and query times of roots that are neither, and cached hashes of
+// files no longer indexed at the size and modification time hashed. It
+// must not run while a root is being indexed, since a root only becomes
+// indexed once its entries are written.
+func (s *Store) Compact(ctx context.Context) (CompactResult, error) {
+	res := CompactResult{SizeBefore: s.diskSize()}
+
+	indexed, err := s.GetIndexedPaths()
+	if err != nil {
+		return res, err
+	}
+	var evicted []string
+	err = s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		p := []byte(prefixEvicted)
+		for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+			evicted = append(evicted, string(it.Item().Key()[len(prefixEvicted):]))
+		}
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	covered, isRoot := liveRoots(append(indexed, evicted...))
+
+	stale, err := s.staleKeys(ctx, covered, isRoot)
+	if err != nil {
+		return res, err
+	}
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range stale {
+		if err := wb.Delete(key); err != nil {
+			return res, err
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return res, err
+	}
+	res.Pruned = int64(len(stale))
+
+	if err := ctx.Err(); err != nil {
+		return res, err
+	}
+	// Compacting every level into one drops deleted keys and old versions
+	if err := s.db.Flatten(max(runtime.NumCPU()/2, 1)); err != nil {
+		return res, err
+	}
+	s.ReclaimSpace()
+	res.SizeAfter = s.diskSize()
+	return res, nil
+}
+
+// staleKeys returns the keys of the records Compact drops.
+func (s *Store) staleKeys(ctx context.Context, covered func(string) bool, isRoot map[string]bool) ([][]byte, error) {
+	var stale [][]byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			item := it.Item()
+			key := item.Key()
+			prefix := keyPrefix(key)
+			path := string(key[len(prefix):])
+
+			var drop bool
+			switch prefix {
+			case "", prefixLargeFile:
+				drop = !covered(path)
+			case prefixMeta:
+				drop = string(key) != schemaKey && !isRoot[path]
+			case prefixQueried:
+				drop = !isRoot[path]
+			case prefixHash:
+				if !covered(path) {
+					drop = true
+					break
+				}
+				var err error
+				if drop, err = staleHash(txn, item, path); err != nil {
+					return err
+				}
+			}
+			if drop {
+				stale = append(stale, item.KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// staleHash reports whether the cached hash in item no longer matches the
+// indexed file at path: the large file or entry is gone, or has another
+// size or modification time.
+func staleHash(txn *badger.Txn, item *badger.Item, path string) (bool, error) {
+	var hash HashEntry
+	var ok bool
+	if err := item.Value(func(val []byte) error {
+		hash, ok = decodeHash(path, val)
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, nil
+	}
+
+	var size, modTime int64
+	found := false
+	if large, err := txn.Get([]byte(prefixLargeFile + path)); err == nil {
+		err = large.Value(func(val []byte) error {
+			if len(val) >= 16 {
+				size = int64(binary.BigEndian.Uint64(val[0:8]))
+				modTime = int64(binary.BigEndian.Uint64(val[8:16]))
+				found = true
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	} else if !errors.Is(err, badger.ErrKeyNotFound) {
+		return false, err
+	}
+	if !found {
+		entry, err := txn.Get([]byte(path))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		err = entry.Value(func(val []byte) error {
+			var e Entry
+			if json.Unmarshal(val, &e) == nil && !e.IsDir {
+				size, modTime, found = e.Size, e.ModTime, true
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	return !found || size != hash.Size || modTime != hash.ModTime, nil
+}
+
+// diskSize returns the bytes the store's files take on disk. Unlike Size,
+// it is measured now rather than by Badger once a minute.
+func (s *Store) diskSize() int64 {
+	opts := s.db.Opts()
+	size := dirSize(opts.Dir)
+	if opts.ValueDir != opts.Dir {
+		size += dirSize(opts.ValueDir)
+	}
+	return size
+}
+
+// dirSize returns the total size of the regular files in dir.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Files can vanish as Badger compacts
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
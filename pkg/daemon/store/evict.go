@@ -0,0 +1,181 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Key prefixes for store size limits.
+const (
+	prefixQueried = "q:" // q:<root> -> unix nanos of the last query
+	prefixEvicted = "v:" // v:<root> -> unix nanos of the eviction
+)
+
+// TouchRoot records that an indexed root was queried at t.
+func (s *Store) TouchRoot(root string, t time.Time) error {
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, uint64(t.UnixNano()))
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(prefixQueried+root), val)
+	})
+}
+
+// LastQueried returns when an indexed root was last queried, or the zero
+// time if it never was.
+func (s *Store) LastQueried(root string) time.Time {
+	nanos, ok := s.getTime(prefixQueried + root)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+func (s *Store) getTime(key string) (int64, bool) {
+	var nanos int64
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			if len(val) != 8 {
+				return badger.ErrKeyNotFound
+			}
+			nanos = int64(binary.BigEndian.Uint64(val))
+			return nil
+		})
+	})
+	return nanos, err == nil
+}
+
+// Evict drops the file entries of an indexed root to free space. Directory
+// entries are replaced by dirs, which hold their totals as in an
+// aggregates index; files lists the file entries to delete. The large files
+// index and cached hashes under the root are dropped, and the root is no
+// longer an indexed path until it is indexed again.
+func (s *Store) Evict(root string, dirs []*Entry, files []string) error {
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, d := range dirs {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		if err := wb.Set([]byte(d.Path), data); err != nil {
+			return err
+		}
+	}
+	for _, path := range files {
+		if err := wb.Delete([]byte(path)); err != nil {
+			return err
+		}
+	}
+	for _, prefix := range []string{prefixLargeFile, prefixHash} {
+		keys, err := s.keysUnder(prefix, root)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := wb.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	if err := wb.Flush(); err != nil {
+		return err
+	}
+
+	meta := s.GetIndexMeta(root)
+	if meta == nil {
+		meta = &IndexMeta{}
+	}
+	meta.Mode = "aggregates"
+	if err := s.SetIndexMeta(root, meta); err != nil {
+		return err
+	}
+
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, uint64(time.Now().UnixNano()))
+	return s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Delete([]byte(prefixIndexedPath + root)); err != nil {
+			return err
+		}
+		return txn.Set([]byte(prefixEvicted+root), val)
+	})
+}
+
+// keysUnder returns the keys with the given prefix for paths under root.
+func (s *Store) keysUnder(prefix, root string) ([][]byte, error) {
+	var keys [][]byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		p := []byte(prefix + root)
+		for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			if IsPathUnderRoot(string(key[len(prefix):]), root) {
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// EvictedRoot returns the evicted root that path is in, if any. Evicted
+// roots keep their directory totals but no files.
+func (s *Store) EvictedRoot(path string) (string, bool) {
+	clean := filepath.Clean(path)
+	var root string
+	_ = s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		p := []byte(prefixEvicted)
+		for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+			if evicted := string(it.Item().Key()[len(prefixEvicted):]); IsPathUnderRoot(clean, evicted) {
+				root = evicted
+				return nil
+			}
+		}
+		return nil
+	})
+	return root, root != ""
+}
+
+// clearEvicted forgets evicted roots under path, once it is indexed again.
+func (s *Store) clearEvicted(path string) error {
+	keys, err := s.keysUnder(prefixEvicted, path)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReclaimSpace asks Badger to rewrite value log files that are mostly
+// deleted data. Deleted keys in the LSM tree are dropped as it compacts in
+// the background.
+func (s *Store) ReclaimSpace() {
+	for {
+		if err := s.db.RunValueLogGC(0.5); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// prefixHash keys content hashes: h:<path> -> size (8) | mtime (8) | sum,
+// followed by the xxhash (8) when known.
+const prefixHash = "h:"
+
+// HashEntry is a cached content hash for a file.
+type HashEntry struct {
+	Path    string
+	Size    int64
+	ModTime int64
+	Sum     []byte // SHA-256
+	XXHash  uint64 // xxhash64, a quicker check; 0 when not computed
+}
+
+// PutHashes caches the SHA-256, and the xxhash if computed, of a file at the
+// size and mtime they were computed for.
+func (s *Store) PutHashes(e HashEntry) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(prefixHash+e.Path), encodeHash(e))
+	})
+}
+
+// GetHashes returns the cached hashes of a file if they were computed for
+// the same size and mtime.
+func (s *Store) GetHashes(path string, size, modTime int64) (HashEntry, bool) {
+	var entry HashEntry
+	var found bool
+	_ = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(prefixHash + path))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			e, ok := decodeHash(path, val)
+			if ok && e.Size == size && e.ModTime == modTime {
+				entry, found = e, true
+			}
+			return nil
+		})
+	})
+	return entry, found
+}
+
+// RemoveHash drops the cached hash of a file.
+func (s *Store) RemoveHash(path string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(prefixHash + path))
+	})
+}
+
+// encodeHash encodes a hash value. The xxhash is left out when unknown, as
+// in values written before it was computed.
+func encodeHash(e HashEntry) []byte {
+	val := make([]byte, 16, 16+len(e.Sum)+8)
+	binary.BigEndian.PutUint64(val[0:8], uint64(e.Size))
+	binary.BigEndian.PutUint64(val[8:16], uint64(e.ModTime))
+	val = append(val, e.Sum...)
+	if e.XXHash != 0 && len(e.Sum) == sha256.Size {
+		val = binary.BigEndian.AppendUint64(val, e.XXHash)
+	}
+	return val
+}
+
+// decodeHash parses a hash value. The sum is copied out of the Badger buffer.
+func decodeHash(path string, val []byte) (HashEntry, bool) {
+	if len(val) <= 16 {
+		return HashEntry{}, false
+	}
+	entry := HashEntry{
+		Path:    path,
+		Size:    int64(binary.BigEndian.Uint64(val[0:8])),
+		ModTime: int64(binary.BigEndian.Uint64(val[8:16])),
+	}
+	sum := val[16:]
+	if len(sum) == sha256.Size+8 {
+		entry.XXHash = binary.BigEndian.Uint64(sum[sha256.Size:])
+		sum = sum[:sha256.Size]
+	}
+	entry.Sum = append([]byte(nil), sum...)
+	return entry, true
+}
@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestStoreHashes(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.PutHashes(store.HashEntry{Path: "/data/a.iso", Size: 100, ModTime: 10, Sum: []byte("sum-a")}); err != nil {
+		t.Fatalf("PutHashes failed: %v", err)
+	}
+
+	if e, ok := s.GetHashes("/data/a.iso", 100, 10); !ok || string(e.Sum) != "sum-a" {
+		t.Errorf("GetHashes = %+v, %v; want sum-a, true", e, ok)
+	}
+	if _, ok := s.GetHashes("/data/a.iso", 200, 10); ok {
+		t.Error("expected stale hash (size changed) to miss")
+	}
+	if _, ok := s.GetHashes("/data/a.iso", 100, 11); ok {
+		t.Error("expected stale hash (mtime changed) to miss")
+	}
+
+	if err := s.RemoveHash("/data/a.iso"); err != nil {
+		t.Fatalf("RemoveHash failed: %v", err)
+	}
+	if _, ok := s.GetHashes("/data/a.iso", 100, 10); ok {
+		t.Error("expected removed hash to miss")
+	}
+}
+
+func TestStoreDeletePrefixHashes(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	_ = s.PutHashes(store.HashEntry{Path: "/data/a.iso", Size: 100, ModTime: 1, Sum: []byte("same")})
+	_ = s.PutHashes(store.HashEntry{Path: "/other/a.iso", Size: 100, ModTime: 1, Sum: []byte("same")})
+
+	// Clearing a prefix drops its hashes too.
+	if err := s.DeletePrefix("/data"); err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if _, ok := s.GetHashes("/data/a.iso", 100, 1); ok {
+		t.Error("expected the hash under /data to be dropped")
+	}
+	if _, ok := s.GetHashes("/other/a.iso", 100, 1); !ok {
+		t.Error("expected the hash outside /data to be kept")
+	}
+}
@@ -66,7 +66,7 @@ func TestMigrateFromV1ToV2(t *testing.T) {
 	}
 
 	// Query large files
-	files, err := s.GetLargeFiles("/root", threshold, 10)
+	files, err := s.GetLargeFiles(context.Background(), "/root", threshold, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles failed: %v", err)
 	}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Move re-keys a renamed file or directory: its entry, every entry beneath
+// it, and their large files index entries and cached hashes move from
+// oldPath to newPath, replacing anything already at newPath. Subtrees are
+// moved in one transaction unless they are too big for Badger to commit at
+// once, in which case they are moved in several. It returns the number of
+// entries moved.
+func (s *Store) Move(oldPath, newPath string) (int, error) {
+	type move struct {
+		from, to []byte
+		val      []byte
+	}
+	var moves []move
+	var moved int
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for _, prefix := range []string{"", prefixLargeFile, prefixHash} {
+			p := []byte(prefix + oldPath)
+			for it.Seek(p); it.ValidForPrefix(p); it.Next() {
+				item := it.Item()
+				path := string(item.Key()[len(prefix):])
+				if !IsPathUnderRoot(path, oldPath) {
+					continue // A sibling sharing the prefix, like /data2 for /data
+				}
+				val, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				to := newPath + path[len(oldPath):]
+				if prefix == "" {
+					// Entries hold their path
+					var entry Entry
+					if err := json.Unmarshal(val, &entry); err != nil {
+						continue // Not an entry
+					}
+					entry.Path = to
+					if val, err = json.Marshal(&entry); err != nil {
+						return err
+					}
+					moved++
+				}
+				moves = append(moves, move{from: item.KeyCopy(nil), to: []byte(prefix + to), val: val})
+			}
+		}
+		return nil
+	})
+	if err != nil || len(moves) == 0 {
+		return 0, err
+	}
+
+	txn := s.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+	apply := func(m move) error {
+		if err := txn.Delete(m.from); err != nil {
+			return err
+		}
+		return txn.Set(m.to, m.val)
+	}
+	for _, m := range moves {
+		err := apply(m)
+		if errors.Is(err, badger.ErrTxnTooBig) {
+			if err = txn.Commit(); err != nil {
+				return 0, err
+			}
+			txn = s.db.NewTransaction(true)
+			err = apply(m)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Remove drops a deleted file or directory: its entry, every entry beneath
+// it, and their large files index entries and cached hashes. Unlike
+// DeletePrefix, siblings sharing the prefix (/data/a.iso.part for
+// /data/a.iso) are kept. A file is removed in one transaction, as is a
+// subtree unless it is too big for Badger to commit at once.
+func (s *Store) Remove(path string) error {
+	var keys [][]byte
+	for _, prefix := range []string{"", prefixLargeFile, prefixHash} {
+		under, err := s.keysUnder(prefix, path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, under...)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	txn := s.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+	for _, key := range keys {
+		err := txn.Delete(key)
+		if errors.Is(err, badger.ErrTxnTooBig) {
+			if err = txn.Commit(); err != nil {
+				return err
+			}
+			txn = s.db.NewTransaction(true)
+			err = txn.Delete(key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
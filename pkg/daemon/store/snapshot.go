@@ -0,0 +1,170 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// prefixSnapshot keys disk usage snapshots: s:<root>\x00<unix seconds> ->
+// gzipped JSON. The separator keeps /data's snapshots apart from /data2's.
+const prefixSnapshot = "s:"
+
+// ErrNoSnapshot is returned by FindSnapshot when a root has no snapshots.
+var ErrNoSnapshot = errors.New("no snapshots")
+
+// Snapshot records the disk usage under an indexed root at one time, so
+// growth can be measured later.
+type Snapshot struct {
+	Root  string          `json:"root"`
+	Time  time.Time       `json:"time"`
+	Dirs  []SnapshotEntry `json:"dirs"`
+	Files []SnapshotEntry `json:"files"` // Large files
+}
+
+// SnapshotEntry is the size of a directory, with everything beneath it, or
+// of a file.
+type SnapshotEntry struct {
+	Path  string `json:"p"`
+	Size  int64  `json:"s"`
+	Files int64  `json:"f,omitempty"` // Files beneath a directory
+}
+
+func snapshotPrefix(root string) []byte {
+	return []byte(prefixSnapshot + root + "\x00")
+}
+
+func snapshotKey(root string, t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(snapshotPrefix(root), uint64(t.Unix()))
+}
+
+// PutSnapshot saves a snapshot, replacing any of the same root taken in the
+// same second.
+func (s *Store) PutSnapshot(snap *Snapshot) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(snapshotKey(snap.Root, snap.Time), buf.Bytes())
+	})
+}
+
+// SnapshotTimes returns when the snapshots of root were taken, oldest
+// first.
+func (s *Store) SnapshotTimes(root string) ([]time.Time, error) {
+	var times []time.Time
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := snapshotPrefix(root)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().Key()
+			if len(key) != len(prefix)+8 {
+				continue
+			}
+			times = append(times, time.Unix(int64(binary.BigEndian.Uint64(key[len(prefix):])), 0))
+		}
+		return nil
+	})
+	return times, err
+}
+
+// FindSnapshot returns the latest snapshot of root taken at or before t, or
+// the oldest one when all were taken later. It returns ErrNoSnapshot when
+// root has none.
+func (s *Store) FindSnapshot(root string, t time.Time) (*Snapshot, error) {
+	times, err := s.SnapshotTimes(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	at := snapshotAt(times, t)
+
+	var snap Snapshot
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(snapshotKey(root, at))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			zr, err := gzip.NewReader(bytes.NewReader(val))
+			if err != nil {
+				return err
+			}
+			data, err := io.ReadAll(zr)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, &snap)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// snapshotAt picks the time FindSnapshot loads from times, oldest first,
+// which must not be empty.
+func snapshotAt(times []time.Time, t time.Time) time.Time {
+	at := times[0]
+	for _, taken := range times {
+		if taken.After(t) {
+			break
+		}
+		at = taken
+	}
+	return at
+}
+
+// PruneSnapshots deletes the snapshots of every root taken before t and
+// returns how many were deleted.
+func (s *Store) PruneSnapshots(t time.Time) (int, error) {
+	var keys [][]byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := []byte(prefixSnapshot)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().Key()
+			if len(key) < len(prefix)+9 {
+				continue
+			}
+			if int64(binary.BigEndian.Uint64(key[len(key)-8:])) < t.Unix() {
+				keys = append(keys, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	err = s.db.Update(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(keys), err
+}
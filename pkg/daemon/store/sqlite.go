@@ -0,0 +1,966 @@
+//go:build sqlite
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 driver
+)
+
+// sqliteSchema creates the SQLite backend's tables, which hold what the
+// Badger store keeps under its key prefixes. Text compares byte by byte,
+// like Badger keys, so a path prefix is a range scan of a primary key, and
+// large files are also indexed by size for GetLargeFiles.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS settings (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS entries (
+	path     TEXT PRIMARY KEY,
+	size     INTEGER NOT NULL,
+	mod_time INTEGER NOT NULL,
+	is_dir   INTEGER NOT NULL,
+	files    INTEGER NOT NULL DEFAULT 0,
+	children TEXT, -- JSON array of child paths
+	shared   TEXT  -- JSON sharing of hard links and clones, and cloud placeholder
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS large_files (
+	path     TEXT PRIMARY KEY,
+	size     INTEGER NOT NULL,
+	mod_time INTEGER NOT NULL,
+	shared   TEXT
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS large_files_size ON large_files (size DESC, path);
+CREATE TABLE IF NOT EXISTS index_meta (
+	root  TEXT PRIMARY KEY,
+	files INTEGER NOT NULL,
+	dirs  INTEGER NOT NULL,
+	mode  TEXT NOT NULL DEFAULT ''
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS indexed_paths (path TEXT PRIMARY KEY) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS watched_roots (path TEXT PRIMARY KEY) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS volumes (
+	uuid        TEXT PRIMARY KEY,
+	mount_point TEXT NOT NULL
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS queried (
+	root TEXT PRIMARY KEY,
+	at   INTEGER NOT NULL -- Unix nanoseconds
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS evicted (
+	root TEXT PRIMARY KEY,
+	at   INTEGER NOT NULL -- Unix nanoseconds
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS hashes (
+	path     TEXT PRIMARY KEY,
+	size     INTEGER NOT NULL,
+	mod_time INTEGER NOT NULL,
+	sum      BLOB NOT NULL,
+	xxhash   INTEGER -- NULL when not computed
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS snapshots (
+	root TEXT NOT NULL,
+	time INTEGER NOT NULL, -- Unix seconds
+	data TEXT NOT NULL,    -- JSON
+	PRIMARY KEY (root, time)
+) WITHOUT ROWID;
+CREATE TABLE IF NOT EXISTS index_stats (
+	root  TEXT NOT NULL,
+	time  INTEGER NOT NULL, -- Unix seconds
+	size  INTEGER NOT NULL,
+	files INTEGER NOT NULL,
+	PRIMARY KEY (root, time)
+) WITHOUT ROWID;
+`
+
+const entryColumns = "path, size, mod_time, is_dir, files, children, shared"
+
+// sqliteStore is the index storage backed by SQLite. The database is in
+// WAL mode, so the sqlite3 shell can query it while the daemon writes.
+type sqliteStore struct {
+	db   *sql.DB
+	path string
+}
+
+var _ StorageBackend = (*sqliteStore)(nil)
+
+// openSQLite opens or creates a SQLite store at path.
+func openSQLite(path string) (StorageBackend, error) {
+	dsn := "file:" + path + "?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=10000&_txlock=immediate&_auto_vacuum=incremental"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if err := addSQLiteColumns(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &sqliteStore{db: db, path: path}, nil
+}
+
+// sqliteColumns are columns added to tables after their first release,
+// which CREATE TABLE IF NOT EXISTS leaves out of existing databases.
+var sqliteColumns = []struct{ table, column, decl string }{
+	{"hashes", "xxhash", "INTEGER"},
+}
+
+// addSQLiteColumns adds sqliteColumns to tables created without them.
+func addSQLiteColumns(db *sql.DB) error {
+	for _, c := range sqliteColumns {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", c.table, c.column).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.decl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close updates the query planner's statistics and closes the database.
+func (s *sqliteStore) Close() error {
+	_, _ = s.db.Exec("PRAGMA optimize") // Best effort
+	return s.db.Close()
+}
+
+// Size returns the bytes the database and its write-ahead log take on disk.
+func (s *sqliteStore) Size() int64 {
+	var size int64
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if info, err := os.Stat(s.path + suffix); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// ReclaimSpace checkpoints the write-ahead log into the database and
+// returns its free pages to the filesystem.
+func (s *sqliteStore) ReclaimSpace() {
+	_, _ = s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	_, _ = s.db.Exec("PRAGMA incremental_vacuum")
+}
+
+// Check runs SQLite's integrity check of the database, then looks for
+// indexed roots whose root entry is missing, as the Badger store's does.
+func (s *sqliteStore) Check(ctx context.Context) (CheckResult, error) {
+	var res CheckResult
+	rows, err := s.db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return res, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return res, err
+		}
+		if line != "ok" {
+			res.add("%s", line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return res, err
+	}
+	err = s.db.QueryRowContext(ctx, "SELECT (SELECT COUNT(*) FROM entries) + (SELECT COUNT(*) FROM large_files) + (SELECT COUNT(*) FROM hashes)").Scan(&res.Records)
+	if err != nil {
+		return res, err
+	}
+	return res, checkIndexedRoots(s, &res)
+}
+
+// Compact drops the stale records the Badger store's Compact does, then
+// rebuilds the database to return the space they took.
+func (s *sqliteStore) Compact(ctx context.Context) (CompactResult, error) {
+	res := CompactResult{SizeBefore: s.Size()}
+
+	roots, err := s.queryStrings("SELECT path FROM indexed_paths UNION SELECT root FROM evicted")
+	if err != nil {
+		return res, err
+	}
+	covered, isRoot := liveRoots(roots)
+	err = s.inTx(func(tx *sql.Tx) error {
+		r, err := tx.ExecContext(ctx, `DELETE FROM hashes WHERE
+			NOT EXISTS (SELECT 1 FROM large_files l WHERE l.path = hashes.path AND l.size = hashes.size AND l.mod_time = hashes.mod_time) AND
+			NOT EXISTS (SELECT 1 FROM entries e WHERE e.path = hashes.path AND NOT e.is_dir AND e.size = hashes.size AND e.mod_time = hashes.mod_time)`)
+		if err != nil {
+			return err
+		}
+		n, err := r.RowsAffected()
+		if err != nil {
+			return err
+		}
+		res.Pruned += n
+
+		prune := func(table, col string, stale func(string) bool) error {
+			rows, err := tx.QueryContext(ctx, "SELECT "+col+" FROM "+table)
+			if err != nil {
+				return err
+			}
+			var drop []string
+			for rows.Next() {
+				var v string
+				if err := rows.Scan(&v); err != nil {
+					rows.Close()
+					return err
+				}
+				if stale(v) {
+					drop = append(drop, v)
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			for _, v := range drop {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE "+col+" = ?", v); err != nil {
+					return err
+				}
+			}
+			res.Pruned += int64(len(drop))
+			return nil
+		}
+		notCovered := func(path string) bool { return !covered(path) }
+		notRoot := func(root string) bool { return !isRoot[root] }
+		for _, table := range []string{"entries", "large_files", "hashes"} {
+			if err := prune(table, "path", notCovered); err != nil {
+				return err
+			}
+		}
+		if err := prune("index_meta", "root", notRoot); err != nil {
+			return err
+		}
+		return prune("queried", "root", notRoot)
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return res, err
+	}
+	s.ReclaimSpace()
+	res.SizeAfter = s.Size()
+	return res, nil
+}
+
+// GetSchema returns the current schema version, or nil if not set.
+func (s *sqliteStore) GetSchema() *Schema {
+	var data string
+	if err := s.db.QueryRow("SELECT value FROM settings WHERE key = 'schema'").Scan(&data); err != nil {
+		return nil
+	}
+	var schema Schema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		return nil
+	}
+	return &schema
+}
+
+// SetSchema stores the schema version.
+func (s *sqliteStore) SetSchema(schema *Schema) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('schema', ?)", string(data))
+	return err
+}
+
+// NeedsMigration returns true if the database needs migration.
+func (s *sqliteStore) NeedsMigration() bool {
+	schema := s.GetSchema()
+	if schema == nil {
+		var found bool
+		_ = s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM entries)").Scan(&found)
+		return found
+	}
+	return schema.Version < CurrentSchemaVersion
+}
+
+// Migrate brings the schema version up to date. SQLite stores have kept the
+// large files index and metadata from the start, so there is nothing to
+// convert.
+func (s *sqliteStore) Migrate(ctx context.Context, _ int64, _ MigrationProgressFunc) (int, error) {
+	if schema := s.GetSchema(); schema != nil && schema.Version >= CurrentSchemaVersion {
+		return 0, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return 0, s.SetSchema(&Schema{Version: CurrentSchemaVersion, UpdatedAt: time.Now()})
+}
+
+// execer runs statements on the database or in a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// inTx runs fn in a write transaction, committing it if fn succeeds.
+func (s *sqliteStore) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// prefixEnd returns the smallest string greater than every string starting
+// with prefix, or "" when there is none.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+// hasPrefix returns a condition matching col values starting with prefix,
+// as Badger's prefix iteration does, and its arguments.
+func hasPrefix(col, prefix string) (string, []any) {
+	end := prefixEnd(prefix)
+	if end == "" {
+		return col + " >= ?", []any{prefix}
+	}
+	return "(" + col + " >= ? AND " + col + " < ?)", []any{prefix, end}
+}
+
+// underRoot returns a condition matching col values for root and the paths
+// beneath it, as IsPathUnderRoot does, and its arguments.
+func underRoot(col, root string) (string, []any) {
+	clean := filepath.Clean(root)
+	cond, args := hasPrefix(col, clean+string(filepath.Separator))
+	return "(" + col + " = ? OR " + cond + ")", append([]any{clean}, args...)
+}
+
+// nullJSON encodes v as JSON, or NULL when empty is set.
+func nullJSON(v any, empty bool) sql.NullString {
+	if empty {
+		return sql.NullString{}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// nullExtra returns a file's sharing and cloud placeholder as JSON for the
+// shared columns, or NULL if it has neither.
+func nullExtra(e *Entry) sql.NullString {
+	data := encodeExtra(e)
+	if data == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+func putEntry(db execer, entry *Entry) error {
+	_, err := db.Exec("INSERT OR REPLACE INTO entries ("+entryColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		entry.Path, entry.Size, entry.ModTime, entry.IsDir, entry.Files,
+		nullJSON(entry.Children, len(entry.Children) == 0), nullExtra(entry))
+	return err
+}
+
+// scanner reads a row from sql.Row or sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row scanner) (*Entry, error) {
+	var entry Entry
+	var children, shared sql.NullString
+	if err := row.Scan(&entry.Path, &entry.Size, &entry.ModTime, &entry.IsDir, &entry.Files, &children, &shared); err != nil {
+		return nil, err
+	}
+	if children.Valid {
+		_ = json.Unmarshal([]byte(children.String), &entry.Children)
+	}
+	if shared.Valid {
+		decodeExtra([]byte(shared.String), &entry)
+	}
+	return &entry, nil
+}
+
+// Put stores an entry.
+func (s *sqliteStore) Put(entry *Entry) error {
+	return putEntry(s.db, entry)
+}
+
+// PutBatch stores multiple entries in one transaction.
+func (s *sqliteStore) PutBatch(entries []*Entry) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, entry := range entries {
+			if err := putEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Get retrieves an entry by path. It returns sql.ErrNoRows if there is
+// none.
+func (s *sqliteStore) Get(path string) (*Entry, error) {
+	return scanEntry(s.db.QueryRow("SELECT "+entryColumns+" FROM entries WHERE path = ?", path))
+}
+
+// Delete removes an entry.
+func (s *sqliteStore) Delete(path string) error {
+	_, err := s.db.Exec("DELETE FROM entries WHERE path = ?", path)
+	return err
+}
+
+// DeletePrefix removes all entries with the given path prefix, with their
+// large files index entries, cached hashes, and metadata.
+func (s *sqliteStore) DeletePrefix(prefix string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, table := range []struct{ name, col string }{
+			{"entries", "path"}, {"large_files", "path"}, {"hashes", "path"}, {"index_meta", "root"},
+		} {
+			cond, args := hasPrefix(table.col, prefix)
+			if _, err := tx.Exec("DELETE FROM "+table.name+" WHERE "+cond, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Remove drops a deleted file or directory: its entry, every entry beneath
+// it, and their large files index entries and cached hashes. Siblings
+// sharing the prefix are kept.
+func (s *sqliteStore) Remove(path string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		cond, args := underRoot("path", path)
+		for _, table := range []string{"entries", "large_files", "hashes"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE "+cond, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Move re-keys a renamed file or directory: its entry, every entry beneath
+// it, and their large files index entries and cached hashes move from
+// oldPath to newPath in one transaction, replacing anything already at
+// newPath. It returns the number of entries moved.
+func (s *sqliteStore) Move(oldPath, newPath string) (int, error) {
+	var moved int64
+	err := s.inTx(func(tx *sql.Tx) error {
+		cond, args := underRoot("path", oldPath)
+		for _, table := range []struct{ name, rest string }{
+			{"entries", "size, mod_time, is_dir, files, children, shared"},
+			{"large_files", "size, mod_time, shared"},
+			{"hashes", "size, mod_time, sum, xxhash"},
+		} {
+			// substr and length both count characters, so the new path is
+			// newPath followed by what comes after oldPath
+			res, err := tx.Exec("INSERT OR REPLACE INTO "+table.name+" (path, "+table.rest+")"+
+				" SELECT ? || substr(path, length(?) + 1), "+table.rest+" FROM "+table.name+" WHERE "+cond,
+				append([]any{newPath, oldPath}, args...)...)
+			if err != nil {
+				return err
+			}
+			if table.name == "entries" {
+				if moved, err = res.RowsAffected(); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec("DELETE FROM "+table.name+" WHERE "+cond, args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(moved), nil
+}
+
+// GetIndexedRoot returns the root path if it exists in the index.
+func (s *sqliteStore) GetIndexedRoot(root string) (*Entry, error) {
+	return s.Get(root)
+}
+
+// CountEntries returns the number of entries under a path.
+func (s *sqliteStore) CountEntries(prefix string) (files, dirs int64, err error) {
+	cond, args := hasPrefix("path", prefix)
+	err = s.db.QueryRow("SELECT COALESCE(SUM(NOT is_dir), 0), COALESCE(SUM(is_dir), 0) FROM entries WHERE "+cond, args...).
+		Scan(&files, &dirs)
+	return files, dirs, err
+}
+
+// Walk calls fn for root and every entry beneath it, in path order.
+func (s *sqliteStore) Walk(root string, fn func(*Entry) error) error {
+	cond, args := underRoot("path", root)
+	rows, err := s.db.Query("SELECT "+entryColumns+" FROM entries WHERE "+cond+" ORDER BY path", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// HasIndex checks if a path has been indexed.
+func (s *sqliteStore) HasIndex(root string) bool {
+	_, err := s.Get(root)
+	return err == nil
+}
+
+// GetLargeFiles returns files >= minSize under the given root path, largest
+// first. Unlike the Badger store, which stops at the first limit files in
+// path order, the limit keeps the largest files.
+func (s *sqliteStore) GetLargeFiles(ctx context.Context, root string, minSize int64, limit int) ([]*Entry, error) {
+	cond, args := hasPrefix("path", root)
+	query := "SELECT path, size, mod_time, shared FROM large_files WHERE " + cond + " AND size >= ? ORDER BY size DESC, path"
+	args = append(args, minSize)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Entry
+	for rows.Next() {
+		entry := &Entry{}
+		var shared sql.NullString
+		if err := rows.Scan(&entry.Path, &entry.Size, &entry.ModTime, &shared); err != nil {
+			return nil, err
+		}
+		if shared.Valid {
+			decodeExtra([]byte(shared.String), entry)
+		}
+		results = append(results, entry)
+	}
+	return results, rows.Err()
+}
+
+// AddLargeFile adds a file to the large files index for fast queries.
+func (s *sqliteStore) AddLargeFile(path string, size, modTime int64) error {
+	return s.PutLargeFile(&Entry{Path: path, Size: size, ModTime: modTime})
+}
+
+func putLargeFile(db execer, f *Entry) error {
+	_, err := db.Exec("INSERT OR REPLACE INTO large_files (path, size, mod_time, shared) VALUES (?, ?, ?, ?)",
+		f.Path, f.Size, f.ModTime, nullExtra(f))
+	return err
+}
+
+// PutLargeFile adds a file entry, with its sharing and cloud placeholder, to
+// the large files index.
+func (s *sqliteStore) PutLargeFile(f *Entry) error {
+	return putLargeFile(s.db, f)
+}
+
+// AddLargeFileBatch adds multiple files to the large files index in one
+// transaction.
+func (s *sqliteStore) AddLargeFileBatch(files []*Entry) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, f := range files {
+			if err := putLargeFile(tx, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveLargeFile removes a file from the large files index.
+func (s *sqliteStore) RemoveLargeFile(path string) error {
+	_, err := s.db.Exec("DELETE FROM large_files WHERE path = ?", path)
+	return err
+}
+
+// PruneLargeFiles removes the files under root smaller than minSize from
+// the large files index and returns how many were removed.
+func (s *sqliteStore) PruneLargeFiles(root string, minSize int64) (int, error) {
+	cond, args := underRoot("path", root)
+	res, err := s.db.Exec("DELETE FROM large_files WHERE "+cond+" AND size < ?", append(args, minSize)...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// RebuildLargeFilesIndex rebuilds the large files index from existing entries.
+func (s *sqliteStore) RebuildLargeFilesIndex(root string, minSize int64) (int, error) {
+	cond, args := hasPrefix("path", root)
+	res, err := s.db.Exec("INSERT OR REPLACE INTO large_files (path, size, mod_time, shared)"+
+		" SELECT path, size, mod_time, shared FROM entries WHERE "+cond+" AND NOT is_dir AND size >= ?",
+		append(args, minSize)...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// HasLargeFilesIndex checks if the large files index exists for a root.
+func (s *sqliteStore) HasLargeFilesIndex(root string) bool {
+	cond, args := hasPrefix("path", root)
+	var found bool
+	_ = s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM large_files WHERE "+cond+")", args...).Scan(&found)
+	return found
+}
+
+func setIndexMeta(db execer, root string, meta *IndexMeta) error {
+	_, err := db.Exec("INSERT OR REPLACE INTO index_meta (root, files, dirs, mode) VALUES (?, ?, ?, ?)",
+		root, meta.Files, meta.Dirs, meta.Mode)
+	return err
+}
+
+// SetIndexMeta stores metadata for an indexed path.
+func (s *sqliteStore) SetIndexMeta(root string, meta *IndexMeta) error {
+	return setIndexMeta(s.db, root, meta)
+}
+
+// GetIndexMeta retrieves metadata for an indexed path.
+// Returns nil if no metadata exists.
+func (s *sqliteStore) GetIndexMeta(root string) *IndexMeta {
+	var meta IndexMeta
+	err := s.db.QueryRow("SELECT files, dirs, mode FROM index_meta WHERE root = ?", root).
+		Scan(&meta.Files, &meta.Dirs, &meta.Mode)
+	if err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// AddIndexedPath records a path as having been indexed, and forgets
+// evicted roots under it.
+func (s *sqliteStore) AddIndexedPath(path string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		cond, args := underRoot("root", path)
+		if _, err := tx.Exec("DELETE FROM evicted WHERE "+cond, args...); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT OR IGNORE INTO indexed_paths (path) VALUES (?)", path)
+		return err
+	})
+}
+
+// RemoveIndexedPath removes a path from the indexed paths list.
+func (s *sqliteStore) RemoveIndexedPath(path string) error {
+	_, err := s.db.Exec("DELETE FROM indexed_paths WHERE path = ?", path)
+	return err
+}
+
+// queryStrings returns the single text column query selects.
+func (s *sqliteStore) queryStrings(query string, args ...any) ([]string, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+// GetIndexedPaths returns all paths that have been indexed.
+func (s *sqliteStore) GetIndexedPaths() ([]string, error) {
+	return s.queryStrings("SELECT path FROM indexed_paths ORDER BY path")
+}
+
+// AddIndexedPathWithSubsumption adds a path and removes any child paths it subsumes.
+// Returns the list of subsumed paths that were removed.
+func (s *sqliteStore) AddIndexedPathWithSubsumption(path string) ([]string, error) {
+	return addIndexedPathWithSubsumption(s, path)
+}
+
+// IsPathCovered checks if a path is already covered by an indexed path.
+// Returns true and the covering path if the path is under an already-indexed path.
+func (s *sqliteStore) IsPathCovered(path string) (bool, string) {
+	return isPathCovered(s, path)
+}
+
+// TouchRoot records that an indexed root was queried at t.
+func (s *sqliteStore) TouchRoot(root string, t time.Time) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO queried (root, at) VALUES (?, ?)", root, t.UnixNano())
+	return err
+}
+
+// LastQueried returns when an indexed root was last queried, or the zero
+// time if it never was.
+func (s *sqliteStore) LastQueried(root string) time.Time {
+	var nanos int64
+	if err := s.db.QueryRow("SELECT at FROM queried WHERE root = ?", root).Scan(&nanos); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// Evict drops the file entries of an indexed root to free space, as the
+// Badger store's Evict does, in one transaction.
+func (s *sqliteStore) Evict(root string, dirs []*Entry, files []string) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		for _, d := range dirs {
+			if err := putEntry(tx, d); err != nil {
+				return err
+			}
+		}
+		for _, path := range files {
+			if _, err := tx.Exec("DELETE FROM entries WHERE path = ?", path); err != nil {
+				return err
+			}
+		}
+		cond, args := underRoot("path", root)
+		for _, table := range []string{"large_files", "hashes"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE "+cond, args...); err != nil {
+				return err
+			}
+		}
+
+		meta := &IndexMeta{}
+		_ = tx.QueryRow("SELECT files, dirs FROM index_meta WHERE root = ?", root).Scan(&meta.Files, &meta.Dirs)
+		meta.Mode = "aggregates"
+		if err := setIndexMeta(tx, root, meta); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM indexed_paths WHERE path = ?", root); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT OR REPLACE INTO evicted (root, at) VALUES (?, ?)", root, time.Now().UnixNano())
+		return err
+	})
+}
+
+// EvictedRoot returns the evicted root that path is in, if any.
+func (s *sqliteStore) EvictedRoot(path string) (string, bool) {
+	roots, err := s.queryStrings("SELECT root FROM evicted ORDER BY root")
+	if err != nil {
+		return "", false
+	}
+	clean := filepath.Clean(path)
+	for _, root := range roots {
+		if IsPathUnderRoot(clean, root) {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+// PutHashes caches the SHA-256, and the xxhash if computed, of a file at the
+// size and mtime they were computed for.
+func (s *sqliteStore) PutHashes(e HashEntry) error {
+	var xxhash sql.NullInt64
+	if e.XXHash != 0 {
+		xxhash = sql.NullInt64{Int64: int64(e.XXHash), Valid: true}
+	}
+	_, err := s.db.Exec("INSERT OR REPLACE INTO hashes (path, size, mod_time, sum, xxhash) VALUES (?, ?, ?, ?, ?)",
+		e.Path, e.Size, e.ModTime, e.Sum, xxhash)
+	return err
+}
+
+// GetHashes returns the cached hashes of a file if they were computed for
+// the same size and mtime.
+func (s *sqliteStore) GetHashes(path string, size, modTime int64) (HashEntry, bool) {
+	e := HashEntry{Path: path, Size: size, ModTime: modTime}
+	var xxhash sql.NullInt64
+	err := s.db.QueryRow("SELECT sum, xxhash FROM hashes WHERE path = ? AND size = ? AND mod_time = ?", path, size, modTime).
+		Scan(&e.Sum, &xxhash)
+	if err != nil || len(e.Sum) == 0 {
+		return HashEntry{}, false
+	}
+	e.XXHash = uint64(xxhash.Int64)
+	return e, true
+}
+
+// RemoveHash drops the cached hash of a file.
+func (s *sqliteStore) RemoveHash(path string) error {
+	_, err := s.db.Exec("DELETE FROM hashes WHERE path = ?", path)
+	return err
+}
+
+// PutSnapshot saves a snapshot, replacing any of the same root taken in the
+// same second.
+func (s *sqliteStore) PutSnapshot(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("INSERT OR REPLACE INTO snapshots (root, time, data) VALUES (?, ?, ?)",
+		snap.Root, snap.Time.Unix(), string(data))
+	return err
+}
+
+// SnapshotTimes returns when the snapshots of root were taken, oldest
+// first.
+func (s *sqliteStore) SnapshotTimes(root string) ([]time.Time, error) {
+	rows, err := s.db.Query("SELECT time FROM snapshots WHERE root = ? ORDER BY time", root)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var times []time.Time
+	for rows.Next() {
+		var secs int64
+		if err := rows.Scan(&secs); err != nil {
+			return nil, err
+		}
+		times = append(times, time.Unix(secs, 0))
+	}
+	return times, rows.Err()
+}
+
+// FindSnapshot returns the latest snapshot of root taken at or before t, or
+// the oldest one when all were taken later. It returns ErrNoSnapshot when
+// root has none.
+func (s *sqliteStore) FindSnapshot(root string, t time.Time) (*Snapshot, error) {
+	times, err := s.SnapshotTimes(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	var data string
+	err = s.db.QueryRow("SELECT data FROM snapshots WHERE root = ? AND time = ?", root, snapshotAt(times, t).Unix()).
+		Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal([]byte(data), &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// PruneSnapshots deletes the snapshots of every root taken before t and
+// returns how many were deleted.
+func (s *sqliteStore) PruneSnapshots(t time.Time) (int, error) {
+	res, err := s.db.Exec("DELETE FROM snapshots WHERE time < ?", t.Unix())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// PutIndexStat records root's totals, replacing any recorded in the same
+// second.
+func (s *sqliteStore) PutIndexStat(root string, stat IndexStat) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO index_stats (root, time, size, files) VALUES (?, ?, ?, ?)",
+		root, stat.Time.Unix(), stat.Size, stat.Files)
+	return err
+}
+
+// IndexStats returns the totals recorded for root at or after since, oldest
+// first.
+func (s *sqliteStore) IndexStats(root string, since time.Time) ([]IndexStat, error) {
+	rows, err := s.db.Query("SELECT time, size, files FROM index_stats WHERE root = ? AND time >= ? ORDER BY time",
+		root, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stats []IndexStat
+	for rows.Next() {
+		var secs int64
+		var stat IndexStat
+		if err := rows.Scan(&secs, &stat.Size, &stat.Files); err != nil {
+			return nil, err
+		}
+		stat.Time = time.Unix(secs, 0)
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// AddWatchedRoot saves root as a directory to watch across restarts.
+func (s *sqliteStore) AddWatchedRoot(root string) error {
+	_, err := s.db.Exec("INSERT OR IGNORE INTO watched_roots (path) VALUES (?)", filepath.Clean(root))
+	return err
+}
+
+// RemoveWatchedRoot forgets a saved root. It reports whether root was
+// saved.
+func (s *sqliteStore) RemoveWatchedRoot(root string) (bool, error) {
+	res, err := s.db.Exec("DELETE FROM watched_roots WHERE path = ?", filepath.Clean(root))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetWatchedRoots returns the saved roots in path order.
+func (s *sqliteStore) GetWatchedRoots() ([]string, error) {
+	return s.queryStrings("SELECT path FROM watched_roots ORDER BY path")
+}
+
+// SetVolumeMount records that the volume with uuid is mounted at
+// mountPoint.
+func (s *sqliteStore) SetVolumeMount(uuid, mountPoint string) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO volumes (uuid, mount_point) VALUES (?, ?)", uuid, mountPoint)
+	return err
+}
+
+// GetVolumeMounts returns the mount point recorded for each volume, by
+// UUID.
+func (s *sqliteStore) GetVolumeMounts() (map[string]string, error) {
+	rows, err := s.db.Query("SELECT uuid, mount_point FROM volumes")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	mounts := make(map[string]string)
+	for rows.Next() {
+		var uuid, mountPoint string
+		if err := rows.Scan(&uuid, &mountPoint); err != nil {
+			return nil, err
+		}
+		mounts[uuid] = mountPoint
+	}
+	return mounts, rows.Err()
+}
@@ -0,0 +1,10 @@
+//go:build !sqlite
+
+package store
+
+import "errors"
+
+// openSQLite fails in builds without the SQLite backend, which needs cgo.
+func openSQLite(string) (StorageBackend, error) {
+	return nil, errors.New("this sweepd was built without the SQLite store backend; rebuild it with CGO_ENABLED=1 go build -tags sqlite, or set daemon.store_backend to badger")
+}
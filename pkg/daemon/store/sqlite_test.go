@@ -0,0 +1,44 @@
+//go:build sqlite
+
+package store_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func init() {
+	backends = append(backends, store.BackendSQLite)
+}
+
+func TestSQLiteAddsColumnsToOldDatabases(t *testing.T) {
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "index.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The hashes table as released, without xxhash
+	if _, err := db.Exec(`CREATE TABLE hashes (path TEXT PRIMARY KEY, size INTEGER NOT NULL, mod_time INTEGER NOT NULL, sum BLOB NOT NULL) WITHOUT ROWID;
+INSERT INTO hashes VALUES ('/d/a', 10, 1, x'07')`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s, err := store.OpenBackend(store.BackendSQLite, dir)
+	if err != nil {
+		t.Fatalf("OpenBackend failed: %v", err)
+	}
+	defer s.Close()
+	if e, ok := s.GetHashes("/d/a", 10, 1); !ok || e.XXHash != 0 {
+		t.Errorf("GetHashes = %+v, %v; want the old hash without xxhash", e, ok)
+	}
+	if err := s.PutHashes(store.HashEntry{Path: "/d/b", Size: 10, ModTime: 1, Sum: []byte{7}, XXHash: 42}); err != nil {
+		t.Fatalf("PutHashes failed: %v", err)
+	}
+	if e, ok := s.GetHashes("/d/b", 10, 1); !ok || e.XXHash != 42 {
+		t.Errorf("GetHashes = %+v, %v", e, ok)
+	}
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// prefixStat keys the totals recorded for charting index growth:
+// g:<root>\x00<unix seconds> -> size and file count, 8 bytes each.
+const prefixStat = "g:"
+
+// IndexStat is the total size and file count of an indexed root at one
+// time. A few bytes each, they are kept after snapshots are pruned.
+type IndexStat struct {
+	Time  time.Time
+	Size  int64
+	Files int64
+}
+
+func statPrefix(root string) []byte {
+	return []byte(prefixStat + root + "\x00")
+}
+
+// PutIndexStat records root's totals, replacing any recorded in the same
+// second.
+func (s *Store) PutIndexStat(root string, stat IndexStat) error {
+	key := binary.BigEndian.AppendUint64(statPrefix(root), uint64(stat.Time.Unix()))
+	val := binary.BigEndian.AppendUint64(nil, uint64(stat.Size))
+	val = binary.BigEndian.AppendUint64(val, uint64(stat.Files))
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, val)
+	})
+}
+
+// IndexStats returns the totals recorded for root at or after since, oldest
+// first.
+func (s *Store) IndexStats(root string, since time.Time) ([]IndexStat, error) {
+	var stats []IndexStat
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := statPrefix(root)
+		start := binary.BigEndian.AppendUint64(statPrefix(root), uint64(max(since.Unix(), 0)))
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().Key()
+			if len(key) != len(prefix)+8 {
+				continue
+			}
+			err := it.Item().Value(func(val []byte) error {
+				if len(val) == 16 {
+					stats = append(stats, IndexStat{
+						Time:  time.Unix(int64(binary.BigEndian.Uint64(key[len(prefix):])), 0),
+						Size:  int64(binary.BigEndian.Uint64(val)),
+						Files: int64(binary.BigEndian.Uint64(val[8:])),
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
@@ -1,7 +1,9 @@
-// Package store provides Badger DB-backed storage for the file index.
+// Package store provides the daemon's storage for the file index, backed by
+// Badger DB or, in builds with the sqlite tag, SQLite.
 package store
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"path/filepath"
@@ -9,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/dgraph-io/badger/v4"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 )
 
 // Key prefixes for different data types.
@@ -19,13 +23,54 @@ const (
 	prefixIndexedPath = "p:" // Indexed paths (for additive indexing)
 )
 
-// Entry represents a file or directory in the index.
+// Entry represents a file or directory in the index. In aggregates mode
+// directories hold the total size and count of the files beneath them.
 type Entry struct {
 	Path     string   `json:"path"`
 	Size     int64    `json:"size"`
 	ModTime  int64    `json:"mod_time"`
 	IsDir    bool     `json:"is_dir"`
+	Files    int64    `json:"files,omitempty"` // Files beneath a directory (aggregates mode)
 	Children []string `json:"children,omitempty"`
+
+	// Shared is set for files that share storage through hard links or
+	// APFS clones
+	Shared *sharing.Info `json:"shared,omitempty"`
+
+	// Cloud is set for online-only placeholders of cloud storage providers
+	Cloud *cloud.Info `json:"cloud,omitempty"`
+}
+
+// fileExtra is a file's sharing and cloud placeholder as stored in the
+// large files index, and in the SQLite backend's shared columns. The
+// sharing fields stay at the top level, as they were before placeholders
+// were recorded, so older values still decode.
+type fileExtra struct {
+	*sharing.Info
+	Cloud *cloud.Info `json:"cloud,omitempty"`
+}
+
+// encodeExtra returns the JSON of a file's sharing and cloud placeholder,
+// or nil if it has neither.
+func encodeExtra(e *Entry) []byte {
+	if e.Shared == nil && e.Cloud == nil {
+		return nil
+	}
+	data, err := json.Marshal(fileExtra{Info: e.Shared, Cloud: e.Cloud})
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// decodeExtra sets a file's sharing and cloud placeholder from data. Both
+// are informational, so data that doesn't decode is ignored.
+func decodeExtra(data []byte, e *Entry) {
+	var extra fileExtra
+	if json.Unmarshal(data, &extra) != nil {
+		return
+	}
+	e.Shared, e.Cloud = extra.Info, extra.Cloud
 }
 
 // Store is the index storage backed by Badger DB.
@@ -51,6 +96,13 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Size returns the bytes the store takes on disk, as last measured by
+// Badger, which refreshes it about once a minute.
+func (s *Store) Size() int64 {
+	lsm, vlog := s.db.Size()
+	return lsm + vlog
+}
+
 // Put stores an entry.
 func (s *Store) Put(entry *Entry) error {
 	data, err := json.Marshal(entry)
@@ -104,8 +156,9 @@ func (s *Store) Get(path string) (*Entry, error) {
 }
 
 // GetLargeFiles returns files >= minSize under the given root path.
-// Uses the pre-computed large files index for fast queries.
-func (s *Store) GetLargeFiles(root string, minSize int64, limit int) ([]*Entry, error) {
+// Uses the pre-computed large files index for fast queries. It stops with
+// ctx's error if ctx is done first.
+func (s *Store) GetLargeFiles(ctx context.Context, root string, minSize int64, limit int) ([]*Entry, error) {
 	var results []*Entry
 
 	err := s.db.View(func(txn *badger.Txn) error {
@@ -114,12 +167,15 @@ func (s *Store) GetLargeFiles(root string, minSize int64, limit int) ([]*Entry,
 		it := txn.NewIterator(opts)
 		defer it.Close()
 
-		// Use large files index: l:<root>/<path> -> size (8 bytes)
+		// Use large files index: l:<root>/<path> -> size, mod time and sharing
 		prefix := []byte(prefixLargeFile + root)
 		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
 			if limit > 0 && len(results) >= limit {
 				break
 			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 
 			item := it.Item()
 			key := item.Key()
@@ -134,12 +190,16 @@ func (s *Store) GetLargeFiles(root string, minSize int64, limit int) ([]*Entry,
 				modTime := int64(binary.BigEndian.Uint64(val[8:16]))
 
 				if size >= minSize {
-					results = append(results, &Entry{
+					entry := &Entry{
 						Path:    path,
 						Size:    size,
 						ModTime: modTime,
 						IsDir:   false,
-					})
+					}
+					if len(val) > 16 {
+						decodeExtra(val[16:], entry)
+					}
+					results = append(results, entry)
 				}
 				return nil
 			})
@@ -161,16 +221,27 @@ func (s *Store) GetLargeFiles(root string, minSize int64, limit int) ([]*Entry,
 // AddLargeFile adds a file to the large files index for fast queries.
 // Call this during indexing for files that meet the size threshold.
 func (s *Store) AddLargeFile(path string, size, modTime int64) error {
-	key := []byte(prefixLargeFile + path)
-	val := make([]byte, 16)
-	binary.BigEndian.PutUint64(val[0:8], uint64(size))
-	binary.BigEndian.PutUint64(val[8:16], uint64(modTime))
+	return s.PutLargeFile(&Entry{Path: path, Size: size, ModTime: modTime})
+}
 
+// PutLargeFile adds a file entry, with its sharing and cloud placeholder, to
+// the large files index.
+func (s *Store) PutLargeFile(f *Entry) error {
 	return s.db.Update(func(txn *badger.Txn) error {
-		return txn.Set(key, val)
+		return txn.Set([]byte(prefixLargeFile+f.Path), largeFileValue(f))
 	})
 }
 
+// largeFileValue encodes a large files index value: the size and mod time,
+// followed by the file's sharing and cloud placeholder as JSON when it has
+// either.
+func largeFileValue(f *Entry) []byte {
+	val := make([]byte, 16)
+	binary.BigEndian.PutUint64(val[0:8], uint64(f.Size))
+	binary.BigEndian.PutUint64(val[8:16], uint64(f.ModTime))
+	return append(val, encodeExtra(f)...)
+}
+
 // AddLargeFileBatch adds multiple files to the large files index efficiently.
 func (s *Store) AddLargeFileBatch(files []*Entry) error {
 	if len(files) == 0 {
@@ -182,10 +253,7 @@ func (s *Store) AddLargeFileBatch(files []*Entry) error {
 
 	for _, f := range files {
 		key := []byte(prefixLargeFile + f.Path)
-		val := make([]byte, 16)
-		binary.BigEndian.PutUint64(val[0:8], uint64(f.Size))
-		binary.BigEndian.PutUint64(val[8:16], uint64(f.ModTime))
-		if err := wb.Set(key, val); err != nil {
+		if err := wb.Set(key, largeFileValue(f)); err != nil {
 			return err
 		}
 	}
@@ -201,6 +269,36 @@ func (s *Store) RemoveLargeFile(path string) error {
 	})
 }
 
+// LargeFileIndexBytes returns the key and value bytes a file takes in the
+// large files index, before compression.
+func LargeFileIndexBytes(f *Entry) int64 {
+	return int64(len(prefixLargeFile) + len(f.Path) + len(largeFileValue(f)))
+}
+
+// PruneLargeFiles removes the files under root smaller than minSize from
+// the large files index and returns how many were removed.
+func (s *Store) PruneLargeFiles(root string, minSize int64) (int, error) {
+	files, err := s.GetLargeFiles(context.Background(), root, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	var removed int
+	for _, f := range files {
+		if f.Size >= minSize || !IsPathUnderRoot(f.Path, root) {
+			continue
+		}
+		if err := wb.Delete([]byte(prefixLargeFile + f.Path)); err != nil {
+			return 0, err
+		}
+		removed++
+	}
+	return removed, wb.Flush()
+}
+
 // RebuildLargeFilesIndex rebuilds the large files index from existing entries.
 // This is used for migration when upgrading from older versions.
 func (s *Store) RebuildLargeFilesIndex(root string, minSize int64) (int, error) {
@@ -302,6 +400,13 @@ func (s *Store) DeletePrefix(prefix string) error {
 			keysToDelete = append(keysToDelete, key)
 		}
 
+		// Also delete cached content hashes
+		hashPrefix := []byte(prefixHash + prefix)
+		for it.Seek(hashPrefix); it.ValidForPrefix(hashPrefix); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			keysToDelete = append(keysToDelete, key)
+		}
+
 		// Also delete metadata for this path
 		metaKey := []byte(prefixMeta + prefix)
 		for it.Seek(metaKey); it.ValidForPrefix(metaKey); it.Next() {
@@ -355,6 +460,33 @@ func (s *Store) CountEntries(prefix string) (files, dirs int64, err error) {
 	return files, dirs, err
 }
 
+// Walk calls fn for root and every entry beneath it, in key order.
+func (s *Store) Walk(root string, fn func(*Entry) error) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := []byte(root)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			if !IsPathUnderRoot(string(it.Item().Key()), root) {
+				continue // A sibling sharing the prefix, like /data2 for /data
+			}
+			var entry Entry
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry)
+			}); err != nil {
+				return err
+			}
+			if err := fn(&entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // HasIndex checks if a path has been indexed.
 func (s *Store) HasIndex(root string) bool {
 	_, err := s.Get(root)
@@ -363,16 +495,18 @@ func (s *Store) HasIndex(root string) bool {
 
 // IndexMeta holds metadata about an indexed path.
 type IndexMeta struct {
-	Files int64 `json:"files"`
-	Dirs  int64 `json:"dirs"`
+	Files int64  `json:"files"`
+	Dirs  int64  `json:"dirs"`
+	Mode  string `json:"mode,omitempty"` // Index mode; empty for indexes that predate modes
 }
 
 // SetIndexMeta stores metadata for an indexed path.
 func (s *Store) SetIndexMeta(root string, meta *IndexMeta) error {
 	key := []byte(prefixMeta + root)
-	val := make([]byte, 16)
+	val := make([]byte, 16, 16+len(meta.Mode))
 	binary.BigEndian.PutUint64(val[0:8], uint64(meta.Files))
 	binary.BigEndian.PutUint64(val[8:16], uint64(meta.Dirs))
+	val = append(val, meta.Mode...)
 
 	return s.db.Update(func(txn *badger.Txn) error {
 		return txn.Set(key, val)
@@ -395,6 +529,7 @@ func (s *Store) GetIndexMeta(root string) *IndexMeta {
 				meta = &IndexMeta{
 					Files: int64(binary.BigEndian.Uint64(val[0:8])),
 					Dirs:  int64(binary.BigEndian.Uint64(val[8:16])),
+					Mode:  string(val[16:]),
 				}
 			}
 			return nil
@@ -414,6 +549,9 @@ func IsPathUnderRoot(path, root string) bool {
 // AddIndexedPath records a path as having been indexed.
 // This supports additive indexing where new paths can be added to the index.
 func (s *Store) AddIndexedPath(path string) error {
+	if err := s.clearEvicted(path); err != nil {
+		return err
+	}
 	key := []byte(prefixIndexedPath + path)
 	return s.db.Update(func(txn *badger.Txn) error {
 		return txn.Set(key, []byte{1}) // Value is just a marker
@@ -454,6 +592,26 @@ func (s *Store) GetIndexedPaths() ([]string, error) {
 // AddIndexedPathWithSubsumption adds a path and removes any child paths it subsumes.
 // Returns the list of subsumed paths that were removed.
 func (s *Store) AddIndexedPathWithSubsumption(path string) ([]string, error) {
+	return addIndexedPathWithSubsumption(s, path)
+}
+
+// IsPathCovered checks if a path is already covered by an indexed path.
+// Returns true and the covering path if the path is under an already-indexed path.
+func (s *Store) IsPathCovered(path string) (bool, string) {
+	return isPathCovered(s, path)
+}
+
+// indexedPaths is the part of a backend that records indexed paths, which
+// subsumption and coverage are worked out from.
+type indexedPaths interface {
+	AddIndexedPath(path string) error
+	RemoveIndexedPath(path string) error
+	GetIndexedPaths() ([]string, error)
+}
+
+// addIndexedPathWithSubsumption adds a path and removes any child paths it subsumes.
+// Returns the list of subsumed paths that were removed.
+func addIndexedPathWithSubsumption(s indexedPaths, path string) ([]string, error) {
 	cleanPath := filepath.Clean(path)
 	var subsumed []string
 
@@ -491,9 +649,9 @@ func (s *Store) AddIndexedPathWithSubsumption(path string) ([]string, error) {
 	return subsumed, nil
 }
 
-// IsPathCovered checks if a path is already covered by an indexed path.
+// isPathCovered checks if a path is already covered by an indexed path.
 // Returns true and the covering path if the path is under an already-indexed path.
-func (s *Store) IsPathCovered(path string) (bool, string) {
+func isPathCovered(s indexedPaths, path string) (bool, string) {
 	cleanPath := filepath.Clean(path)
 
 	paths, err := s.GetIndexedPaths()
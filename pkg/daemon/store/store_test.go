@@ -1,6 +1,9 @@
 package store_test
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -56,7 +59,7 @@ func TestStoreGetLargeFiles(t *testing.T) {
 	}
 
 	// Query for files >= 1000 bytes under /a
-	results, err := s.GetLargeFiles("/a", 1000, 10)
+	results, err := s.GetLargeFiles(context.Background(), "/a", 1000, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles failed: %v", err)
 	}
@@ -181,6 +184,54 @@ func TestStoreDeletePrefix(t *testing.T) {
 	}
 }
 
+func TestStoreRemove(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	for _, e := range []*store.Entry{
+		{Path: "/data/big.iso", Size: 500},
+		{Path: "/data/big.iso.part", Size: 400},
+		{Path: "/data/old", IsDir: true},
+		{Path: "/data/old/a.bin", Size: 300},
+	} {
+		if err := s.Put(e); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := s.PutLargeFile(e); err != nil {
+			t.Fatalf("PutLargeFile failed: %v", err)
+		}
+	}
+	if err := s.PutHashes(store.HashEntry{Path: "/data/big.iso", Size: 500, ModTime: 0, Sum: []byte("sum")}); err != nil {
+		t.Fatalf("PutHashes failed: %v", err)
+	}
+
+	for _, path := range []string{"/data/big.iso", "/data/old"} {
+		if err := s.Remove(path); err != nil {
+			t.Fatalf("Remove(%s) failed: %v", path, err)
+		}
+	}
+	if _, err := s.Get("/data/old/a.bin"); err == nil {
+		t.Error("Expected entries in a removed directory to be removed")
+	}
+
+	if _, err := s.Get("/data/big.iso"); err == nil {
+		t.Error("Expected the entry to be removed")
+	}
+	if _, ok := s.GetHashes("/data/big.iso", 500, 0); ok {
+		t.Error("Expected the cached hash to be removed")
+	}
+	files, err := s.GetLargeFiles(context.Background(), "/data", 0, 0)
+	if err != nil {
+		t.Fatalf("GetLargeFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "/data/big.iso.part" {
+		t.Errorf("Expected only the file sharing the prefix to be left, got %v", files)
+	}
+}
+
 func TestStoreCountEntries(t *testing.T) {
 	s, err := store.Open(t.TempDir())
 	if err != nil {
@@ -288,7 +339,7 @@ func TestLargeFilesIndex(t *testing.T) {
 	}
 
 	// Query it back
-	results, err := s.GetLargeFiles("/test", 0, 10)
+	results, err := s.GetLargeFiles(context.Background(), "/test", 0, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles failed: %v", err)
 	}
@@ -305,7 +356,7 @@ func TestLargeFilesIndex(t *testing.T) {
 	}
 
 	// Should be empty now
-	results, err = s.GetLargeFiles("/test", 0, 10)
+	results, err = s.GetLargeFiles(context.Background(), "/test", 0, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles after remove failed: %v", err)
 	}
@@ -351,7 +402,7 @@ func TestRebuildLargeFilesIndex(t *testing.T) {
 	}
 
 	// Now query should work
-	results, err := s.GetLargeFiles("/root", 10*1024*1024, 10)
+	results, err := s.GetLargeFiles(context.Background(), "/root", 10*1024*1024, 10)
 	if err != nil {
 		t.Fatalf("GetLargeFiles failed: %v", err)
 	}
@@ -360,6 +411,43 @@ func TestRebuildLargeFilesIndex(t *testing.T) {
 	}
 }
 
+func TestPruneLargeFiles(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	for _, f := range []*store.Entry{
+		{Path: "/data/small.bin", Size: 2000},
+		{Path: "/data/big.bin", Size: 9000},
+		{Path: "/data2/small.bin", Size: 2000},
+	} {
+		if err := s.PutLargeFile(f); err != nil {
+			t.Fatalf("PutLargeFile failed: %v", err)
+		}
+	}
+
+	removed, err := s.PruneLargeFiles("/data", 5000)
+	if err != nil {
+		t.Fatalf("PruneLargeFiles failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 file removed, got %d", removed)
+	}
+	results, err := s.GetLargeFiles(context.Background(), "/", 0, 0)
+	if err != nil {
+		t.Fatalf("GetLargeFiles failed: %v", err)
+	}
+	var paths []string
+	for _, r := range results {
+		paths = append(paths, r.Path)
+	}
+	if want := []string{"/data/big.bin", "/data2/small.bin"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected %v left, got %v", want, paths)
+	}
+}
+
 func TestIndexedPaths(t *testing.T) {
 	s, err := store.Open(t.TempDir())
 	if err != nil {
@@ -734,3 +822,210 @@ func TestIsPathCovered_MultipleIndexedPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestIndexMetaMode(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.SetIndexMeta("/data", &store.IndexMeta{Files: 10, Dirs: 2, Mode: "aggregates"}); err != nil {
+		t.Fatalf("SetIndexMeta failed: %v", err)
+	}
+	meta := s.GetIndexMeta("/data")
+	if meta == nil || meta.Files != 10 || meta.Dirs != 2 || meta.Mode != "aggregates" {
+		t.Errorf("GetIndexMeta = %+v, want 10 files, 2 dirs, aggregates", meta)
+	}
+
+	// Metadata written before modes existed has no mode
+	if err := s.SetIndexMeta("/old", &store.IndexMeta{Files: 1, Dirs: 1}); err != nil {
+		t.Fatalf("SetIndexMeta failed: %v", err)
+	}
+	if meta := s.GetIndexMeta("/old"); meta == nil || meta.Mode != "" {
+		t.Errorf("GetIndexMeta = %+v, want no mode", meta)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	err = s.PutBatch([]*store.Entry{
+		{Path: "/data", IsDir: true},
+		{Path: "/data/a.txt", Size: 1},
+		{Path: "/data/sub/b.txt", Size: 2},
+		{Path: "/data2/c.txt", Size: 3},
+	})
+	if err != nil {
+		t.Fatalf("PutBatch failed: %v", err)
+	}
+
+	var paths []string
+	if err := s.Walk("/data", func(e *store.Entry) error {
+		paths = append(paths, e.Path)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	want := []string{"/data", "/data/a.txt", "/data/sub/b.txt"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Walk visited %v, want %v", paths, want)
+	}
+
+	stop := errors.New("stop")
+	if err := s.Walk("/data", func(*store.Entry) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("Walk error = %v, want the callback's error", err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	for _, e := range []*store.Entry{
+		{Path: "/data/old", IsDir: true},
+		{Path: "/data/old/sub", IsDir: true},
+		{Path: "/data/old/sub/big.bin", Size: 5000},
+		{Path: "/data/older/keep.bin", Size: 5000},
+	} {
+		if err := s.Put(e); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if err := s.AddLargeFile("/data/old/sub/big.bin", 5000, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutHashes(store.HashEntry{Path: "/data/old/sub/big.bin", Size: 5000, ModTime: 1, Sum: []byte{1, 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := s.Move("/data/old", "/data/new")
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if moved != 3 {
+		t.Errorf("Expected 3 entries moved, got %d", moved)
+	}
+
+	entry, err := s.Get("/data/new/sub/big.bin")
+	if err != nil || entry.Path != "/data/new/sub/big.bin" || entry.Size != 5000 {
+		t.Errorf("Get(moved) = %+v, %v", entry, err)
+	}
+	if _, err := s.Get("/data/old/sub/big.bin"); err == nil {
+		t.Error("Old entry still present")
+	}
+	if _, err := s.Get("/data/older/keep.bin"); err != nil {
+		t.Errorf("Sibling sharing the prefix was moved: %v", err)
+	}
+	large, err := s.GetLargeFiles(context.Background(), "/data/", 0, 0)
+	if err != nil || len(large) != 1 || large[0].Path != "/data/new/sub/big.bin" {
+		t.Errorf("large files = %+v, %v", large, err)
+	}
+	if _, ok := s.GetHashes("/data/new/sub/big.bin", 5000, 1); !ok {
+		t.Error("Cached hash not moved")
+	}
+}
+
+func TestWatchedRoots(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	for _, root := range []string{"/data/photos", "/data/music/"} {
+		if err := s.AddWatchedRoot(root); err != nil {
+			t.Fatalf("AddWatchedRoot(%s) failed: %v", root, err)
+		}
+	}
+	// Watched roots don't count as indexed paths
+	if err := s.AddIndexedPath("/data/other"); err != nil {
+		t.Fatal(err)
+	}
+
+	roots, err := s.GetWatchedRoots()
+	if err != nil {
+		t.Fatalf("GetWatchedRoots failed: %v", err)
+	}
+	if want := []string{"/data/music", "/data/photos"}; !reflect.DeepEqual(roots, want) {
+		t.Errorf("GetWatchedRoots = %v, want %v", roots, want)
+	}
+
+	if found, err := s.RemoveWatchedRoot("/data/music"); err != nil || !found {
+		t.Errorf("RemoveWatchedRoot(/data/music) = %v, %v; want true", found, err)
+	}
+	if found, err := s.RemoveWatchedRoot("/data/music"); err != nil || found {
+		t.Errorf("second RemoveWatchedRoot(/data/music) = %v, %v; want false", found, err)
+	}
+	roots, _ = s.GetWatchedRoots()
+	if want := []string{"/data/photos"}; !reflect.DeepEqual(roots, want) {
+		t.Errorf("GetWatchedRoots after remove = %v, want %v", roots, want)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.FindSnapshot("/data", time.Now()); !errors.Is(err, store.ErrNoSnapshot) {
+		t.Fatalf("FindSnapshot without snapshots = %v, want ErrNoSnapshot", err)
+	}
+
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	for i, root := range []string{"/data", "/data", "/data", "/data2"} {
+		snap := &store.Snapshot{
+			Root:  root,
+			Time:  day.AddDate(0, 0, i),
+			Dirs:  []store.SnapshotEntry{{Path: root, Size: int64(i+1) * 100, Files: 2}},
+			Files: []store.SnapshotEntry{{Path: root + "/a.iso", Size: 50}},
+		}
+		if err := s.PutSnapshot(snap); err != nil {
+			t.Fatalf("PutSnapshot failed: %v", err)
+		}
+	}
+
+	times, err := s.SnapshotTimes("/data")
+	if err != nil || len(times) != 3 {
+		t.Fatalf("SnapshotTimes(/data) = %v, %v; want 3 times", times, err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want int64
+	}{
+		{day.AddDate(0, 0, 1).Add(time.Hour), 200}, // Latest before
+		{day.AddDate(0, 0, 1), 200},                // Exactly at
+		{day.AddDate(0, 0, -5), 100},               // Oldest when all are later
+		{day.AddDate(0, 0, 30), 300},
+	}
+	for _, tt := range tests {
+		snap, err := s.FindSnapshot("/data", tt.at)
+		if err != nil {
+			t.Fatalf("FindSnapshot(%v) failed: %v", tt.at, err)
+		}
+		if snap.Dirs[0].Size != tt.want || snap.Root != "/data" || len(snap.Files) != 1 {
+			t.Errorf("FindSnapshot(%v) = %+v, want the snapshot of size %d", tt.at, snap, tt.want)
+		}
+	}
+
+	pruned, err := s.PruneSnapshots(day.AddDate(0, 0, 2))
+	if err != nil || pruned != 2 {
+		t.Errorf("PruneSnapshots = %d, %v; want 2", pruned, err)
+	}
+	if times, _ := s.SnapshotTimes("/data"); len(times) != 1 {
+		t.Errorf("%d snapshots of /data left, want 1", len(times))
+	}
+	if times, _ := s.SnapshotTimes("/data2"); len(times) != 1 {
+		t.Errorf("%d snapshots of /data2 left, want 1", len(times))
+	}
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"github.com/dgraph-io/badger/v4"
+)
+
+// prefixVolume keys the mount point each external volume holding an
+// indexed path was last seen at, by volume UUID, so its index can follow
+// it to another mount point.
+const prefixVolume = "u:"
+
+// SetVolumeMount records that the volume with uuid is mounted at
+// mountPoint.
+func (s *Store) SetVolumeMount(uuid, mountPoint string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(prefixVolume+uuid), []byte(mountPoint))
+	})
+}
+
+// GetVolumeMounts returns the mount point recorded for each volume, by
+// UUID.
+func (s *Store) GetVolumeMounts() (map[string]string, error) {
+	mounts := make(map[string]string)
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		prefix := []byte(prefixVolume)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			val, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			mounts[string(it.Item().Key()[len(prefixVolume):])] = string(val)
+		}
+		return nil
+	})
+	return mounts, err
+}
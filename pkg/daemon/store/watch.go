@@ -0,0 +1,56 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// prefixWatchedRoot keys the roots added with "sweep daemon watch add",
+// which the daemon indexes and watches again each time it starts.
+const prefixWatchedRoot = "w:"
+
+// AddWatchedRoot saves root as a directory to watch across restarts.
+func (s *Store) AddWatchedRoot(root string) error {
+	key := []byte(prefixWatchedRoot + filepath.Clean(root))
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, []byte{1}) // Value is just a marker
+	})
+}
+
+// RemoveWatchedRoot forgets a saved root. It reports whether root was
+// saved.
+func (s *Store) RemoveWatchedRoot(root string) (bool, error) {
+	key := []byte(prefixWatchedRoot + filepath.Clean(root))
+	var found bool
+	err := s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get(key); err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		found = true
+		return txn.Delete(key)
+	})
+	return found, err
+}
+
+// GetWatchedRoots returns the saved roots in path order.
+func (s *Store) GetWatchedRoots() ([]string, error) {
+	var roots []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := []byte(prefixWatchedRoot)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			roots = append(roots, string(it.Item().Key()[len(prefixWatchedRoot):]))
+		}
+		return nil
+	})
+	return roots, err
+}
@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// storeLimitInterval is how often the store size is checked against
+// Config.MaxStoreSize. Badger measures its size about once a minute and
+// frees deleted entries as it compacts, so at most one root is evicted
+// per check to give the size time to drop.
+const storeLimitInterval = 5 * time.Minute
+
+// touchRoot records a query of path against the indexed root covering it,
+// so the least recently queried root is evicted first and often used roots
+// are suggested for watching.
+func (s *Service) touchRoot(path string) {
+	if covered, root := s.store.IsPathCovered(path); covered {
+		now := time.Now()
+		_ = s.store.TouchRoot(root, now) // Only orders evictions
+		s.noteUse(root, now)
+	}
+}
+
+// enforceStoreLimit evicts the file entries of the least recently queried
+// root when the store's size is above limit. Roots being indexed are left
+// alone. It returns the evicted root, if any.
+func (s *Service) enforceStoreLimit(size, limit int64) (string, bool) {
+	if size <= limit {
+		s.storeLimitWarned = false
+		return "", false
+	}
+	log := logging.Get("daemon")
+
+	roots, err := s.store.GetIndexedPaths()
+	if err != nil {
+		log.Warn("failed to list indexed paths for eviction", "error", err)
+		return "", false
+	}
+	var victim string
+	var victimQueried time.Time
+	for _, root := range roots {
+		if s.isIndexingPath(root) {
+			continue
+		}
+		queried := s.store.LastQueried(root)
+		if victim == "" || queried.Before(victimQueried) {
+			victim, victimQueried = root, queried
+		}
+	}
+	if victim == "" {
+		if !s.storeLimitWarned {
+			log.Warn("index store exceeds daemon.max_store_size with nothing left to evict",
+				"size", size, "max_store_size", limit)
+			s.storeLimitWarned = true
+		}
+		return "", false
+	}
+
+	if s.watcher != nil {
+		s.watcher.Unwatch(victim)
+	}
+	files, err := s.indexer.Evict(victim)
+	if err != nil {
+		log.Error("failed to evict indexed path", "path", victim, "error", err)
+		s.errors.add("store", victim, err)
+		return "", false
+	}
+	s.indexMu.Lock()
+	delete(s.indexStates, victim)
+	s.indexMu.Unlock()
+	s.store.ReclaimSpace()
+
+	lastQueried := "never"
+	if !victimQueried.IsZero() {
+		lastQueried = victimQueried.Format(time.RFC3339)
+	}
+	log.Warn("index store exceeds daemon.max_store_size, evicted least recently queried path",
+		"path", victim, "files_evicted", files, "last_queried", lastQueried,
+		"size", size, "max_store_size", limit,
+		"hint", "directory totals are kept; re-index the path to query its files again")
+	return victim, true
+}
+
+// evicted reports whether path is in a root whose files were evicted.
+func (s *Service) evicted(path string) bool {
+	_, ok := s.store.EvictedRoot(path)
+	return ok
+}
+
+// isIndexingPath reports whether path is being indexed.
+func (s *Service) isIndexingPath(path string) bool {
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	state, ok := s.indexStates[path]
+	return ok && state.state == sweepv1.IndexState_INDEX_STATE_INDEXING
+}
+
+// enforceStoreLimit checks the store size every storeLimitInterval until
+// ctx is done.
+func (s *Server) enforceStoreLimit(ctx context.Context, limit int64) {
+	ticker := time.NewTicker(storeLimitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.IsMigrating() {
+				s.service.enforceStoreLimit(s.store.Size(), limit)
+			}
+		}
+	}
+}
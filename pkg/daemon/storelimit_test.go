@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+func TestEnforceStoreLimit(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	defer st.Close()
+	svc := NewService(st)
+	ctx := context.Background()
+
+	base := t.TempDir()
+	older, newer := filepath.Join(base, "older"), filepath.Join(base, "newer")
+	for _, root := range []string{older, newer} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "b.bin"), make([]byte, 50), 0o644))
+		_, err := svc.indexer.Index(ctx, root, nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, st.TouchRoot(older, time.Now().Add(-time.Hour)))
+	require.NoError(t, st.TouchRoot(newer, time.Now()))
+
+	_, evicted := svc.enforceStoreLimit(100, 200)
+	assert.False(t, evicted, "under the limit")
+
+	root, evicted := svc.enforceStoreLimit(300, 200)
+	require.True(t, evicted)
+	assert.Equal(t, older, root, "the least recently queried root goes first")
+
+	_, err = st.Get(filepath.Join(older, "a.bin"))
+	assert.Error(t, err, "file entries are dropped")
+	status, err := svc.GetIndexStatus(ctx, &sweepv1.GetIndexStatusRequest{Path: older})
+	require.NoError(t, err)
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_NOT_INDEXED, status.GetState())
+	status, err = svc.GetIndexStatus(ctx, &sweepv1.GetIndexStatusRequest{Path: newer})
+	require.NoError(t, err)
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_READY, status.GetState())
+
+	// Directory totals survive
+	sizes, err := svc.GetDirSizes(ctx, &sweepv1.GetDirSizesRequest{Root: older})
+	require.NoError(t, err)
+	totals := make(map[string]int64)
+	for _, d := range sizes.GetDirs() {
+		totals[d.GetPath()] = d.GetSize()
+	}
+	assert.Equal(t, map[string]int64{older: 150, filepath.Join(older, "sub"): 50}, totals)
+
+	root, evicted = svc.enforceStoreLimit(300, 200)
+	require.True(t, evicted)
+	assert.Equal(t, newer, root)
+	_, evicted = svc.enforceStoreLimit(300, 200)
+	assert.False(t, evicted, "nothing left to evict")
+
+	// Indexing again restores the files
+	result, err := svc.indexer.Index(ctx, older, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	_, err = st.Get(filepath.Join(older, "a.bin"))
+	require.NoError(t, err)
+	status, err = svc.GetIndexStatus(ctx, &sweepv1.GetIndexStatusRequest{Path: older})
+	require.NoError(t, err)
+	assert.Equal(t, sweepv1.IndexState_INDEX_STATE_READY, status.GetState())
+}
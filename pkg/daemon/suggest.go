@@ -0,0 +1,169 @@
+package daemon
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// WatchSuggestions is what the daemon does with indexed roots clients use
+// often that aren't saved watches (daemon.watch_suggestions).
+type WatchSuggestions string
+
+const (
+	// SuggestWatches lists them with GetWatchSuggestions, for clients to
+	// offer to the user.
+	SuggestWatches WatchSuggestions = "suggest"
+
+	// AutoWatch saves them as watches without asking, as AddWatch does.
+	AutoWatch WatchSuggestions = "auto"
+
+	// NoWatchSuggestions neither tracks nor suggests them.
+	NoWatchSuggestions WatchSuggestions = "off"
+)
+
+// ErrUnknownWatchSuggestions is returned by ParseWatchSuggestions for
+// unsupported values.
+var ErrUnknownWatchSuggestions = errors.New("unknown watch_suggestions")
+
+// ParseWatchSuggestions parses a daemon.watch_suggestions value; "" means
+// SuggestWatches.
+func ParseWatchSuggestions(s string) (WatchSuggestions, error) {
+	switch w := WatchSuggestions(s); w {
+	case "":
+		return SuggestWatches, nil
+	case SuggestWatches, AutoWatch, NoWatchSuggestions:
+		return w, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: suggest, auto, off)", ErrUnknownWatchSuggestions, s)
+	}
+}
+
+const (
+	// watchSuggestionUses is how many separate uses make a root worth
+	// watching.
+	watchSuggestionUses = 3
+
+	// watchSuggestionGap is how long a root goes unqueried before the next
+	// query counts as a new use, so a TUI session refreshing its results
+	// is one use.
+	watchSuggestionGap = 10 * time.Minute
+)
+
+// rootUse counts the uses of an indexed root since the daemon started.
+type rootUse struct {
+	uses int
+	last time.Time // Latest query
+}
+
+// noteUse records a query of an indexed root at now. In AutoWatch mode, a
+// root reaching watchSuggestionUses is saved as a watch.
+func (s *Service) noteUse(root string, now time.Time) {
+	if s.WatchSuggestions == NoWatchSuggestions {
+		return
+	}
+	s.suggestMu.Lock()
+	if s.rootUses == nil {
+		s.rootUses = make(map[string]*rootUse)
+	}
+	u := s.rootUses[root]
+	if u == nil {
+		u = &rootUse{}
+		s.rootUses[root] = u
+	}
+	if u.last.IsZero() || now.Sub(u.last) >= watchSuggestionGap {
+		u.uses++
+	}
+	u.last = now
+	reached := u.uses == watchSuggestionUses && !s.dismissed[root]
+	s.suggestMu.Unlock()
+
+	if reached && s.WatchSuggestions == AutoWatch {
+		s.autoWatch(root)
+	}
+}
+
+// autoWatch saves an often used root as a watch, unless it already is one.
+func (s *Service) autoWatch(root string) {
+	log := logging.Get("daemon")
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		log.Warn("failed to read saved watches", "error", err)
+		return
+	}
+	if slices.Contains(saved, root) {
+		return
+	}
+	log.Info("watching often used path", "path", root)
+	if _, err := s.AddWatch(context.Background(), &sweepv1.AddWatchRequest{Path: root}); err != nil {
+		log.Warn("failed to watch often used path", "path", root, "error", err)
+	}
+}
+
+// GetWatchSuggestions lists the indexed roots used at least
+// watchSuggestionUses times since the daemon started that aren't saved
+// watches and weren't dismissed, most used first.
+func (s *Service) GetWatchSuggestions(_ context.Context, _ *sweepv1.GetWatchSuggestionsRequest) (*sweepv1.GetWatchSuggestionsResponse, error) {
+	resp := &sweepv1.GetWatchSuggestionsResponse{}
+	if s.WatchSuggestions == NoWatchSuggestions {
+		return resp, nil
+	}
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list watches: %v", err)
+	}
+
+	s.suggestMu.Lock()
+	for root, u := range s.rootUses {
+		if u.uses < watchSuggestionUses || s.dismissed[root] || slices.Contains(saved, root) {
+			continue
+		}
+		resp.Suggestions = append(resp.Suggestions, &sweepv1.WatchSuggestion{
+			Path:     root,
+			Uses:     int32(u.uses),
+			LastUsed: u.last.Unix(),
+		})
+	}
+	s.suggestMu.Unlock()
+
+	// Roots whose index was cleared since aren't worth suggesting
+	resp.Suggestions = slices.DeleteFunc(resp.Suggestions, func(w *sweepv1.WatchSuggestion) bool {
+		return !s.store.HasIndex(w.GetPath())
+	})
+	slices.SortFunc(resp.Suggestions, func(a, b *sweepv1.WatchSuggestion) int {
+		if c := cmp.Compare(b.GetUses(), a.GetUses()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.GetPath(), b.GetPath())
+	})
+	return resp, nil
+}
+
+// DismissWatchSuggestion stops suggesting a root, and watching it in
+// AutoWatch mode, until the daemon restarts.
+func (s *Service) DismissWatchSuggestion(_ context.Context, req *sweepv1.DismissWatchSuggestionRequest) (*sweepv1.DismissWatchSuggestionResponse, error) {
+	path := filepath.Clean(req.GetPath())
+	s.dismissSuggestion(path)
+	logging.Get("daemon").Info("watch suggestion dismissed", "path", path)
+	return &sweepv1.DismissWatchSuggestionResponse{}, nil
+}
+
+// dismissSuggestion keeps a root from being suggested again.
+func (s *Service) dismissSuggestion(path string) {
+	s.suggestMu.Lock()
+	if s.dismissed == nil {
+		s.dismissed = make(map[string]bool)
+	}
+	s.dismissed[path] = true
+	s.suggestMu.Unlock()
+}
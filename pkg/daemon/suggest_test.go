@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+)
+
+// indexedService returns a service over a new store with roots indexed.
+func indexedService(t *testing.T, roots ...string) (*Service, *store.Store) {
+	t.Helper()
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	svc := NewService(st)
+	for _, root := range roots {
+		require.NoError(t, os.MkdirAll(root, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "a.bin"), make([]byte, 100), 0o644))
+		_, err := svc.indexer.Index(context.Background(), root, nil)
+		require.NoError(t, err)
+	}
+	return svc, st
+}
+
+func suggestedPaths(t *testing.T, svc *Service) []string {
+	t.Helper()
+	resp, err := svc.GetWatchSuggestions(context.Background(), &sweepv1.GetWatchSuggestionsRequest{})
+	require.NoError(t, err)
+	var paths []string
+	for _, w := range resp.GetSuggestions() {
+		paths = append(paths, w.GetPath())
+	}
+	return paths
+}
+
+func TestWatchSuggestions(t *testing.T) {
+	base := t.TempDir()
+	often, saved := filepath.Join(base, "often"), filepath.Join(base, "saved")
+	svc, st := indexedService(t, often, saved)
+	require.NoError(t, st.AddWatchedRoot(saved))
+	ctx := context.Background()
+
+	start := time.Now()
+	for _, root := range []string{often, saved} {
+		svc.noteUse(root, start)
+		svc.noteUse(root, start.Add(time.Minute))
+		svc.noteUse(root, start.Add(5*time.Minute))
+	}
+	assert.Empty(t, suggestedPaths(t, svc), "queries close together are one use")
+
+	for _, root := range []string{often, saved} {
+		svc.noteUse(root, start.Add(20*time.Minute))
+		svc.noteUse(root, start.Add(40*time.Minute))
+	}
+	resp, err := svc.GetWatchSuggestions(ctx, &sweepv1.GetWatchSuggestionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetSuggestions(), 1, "saved watches aren't suggested")
+	assert.Equal(t, often, resp.GetSuggestions()[0].GetPath())
+	assert.Equal(t, int32(3), resp.GetSuggestions()[0].GetUses())
+	assert.Equal(t, start.Add(40*time.Minute).Unix(), resp.GetSuggestions()[0].GetLastUsed())
+
+	_, err = svc.DismissWatchSuggestion(ctx, &sweepv1.DismissWatchSuggestionRequest{Path: often})
+	require.NoError(t, err)
+	assert.Empty(t, suggestedPaths(t, svc))
+}
+
+func TestWatchSuggestionsOff(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "root")
+	svc, _ := indexedService(t, root)
+	svc.WatchSuggestions = NoWatchSuggestions
+
+	start := time.Now()
+	for i := range 5 {
+		svc.noteUse(root, start.Add(time.Duration(i)*time.Hour))
+	}
+	assert.Empty(t, suggestedPaths(t, svc))
+}
+
+func TestAutoWatch(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "root")
+	svc, st := indexedService(t, root)
+	svc.WatchSuggestions = AutoWatch
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := range watchSuggestionUses {
+		svc.noteUse(root, start.Add(time.Duration(i)*time.Hour))
+	}
+	saved, err := st.GetWatchedRoots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{root}, saved, "an often used root is saved as a watch")
+	assert.Empty(t, suggestedPaths(t, svc), "nothing is left to suggest")
+	require.Eventually(t, func() bool { return !svc.isIndexing() }, 5*time.Second, 20*time.Millisecond)
+
+	// A removed watch isn't saved again by more use
+	_, err = svc.RemoveWatch(ctx, &sweepv1.RemoveWatchRequest{Path: root})
+	require.NoError(t, err)
+	for i := range watchSuggestionUses {
+		svc.noteUse(root, start.Add(time.Duration(10+i)*time.Hour))
+	}
+	saved, err = st.GetWatchedRoots()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
+
+func TestParseWatchSuggestions(t *testing.T) {
+	for in, want := range map[string]WatchSuggestions{"": SuggestWatches, "suggest": SuggestWatches, "auto": AutoWatch, "off": NoWatchSuggestions} {
+		got, err := ParseWatchSuggestions(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseWatchSuggestions("always")
+	assert.ErrorIs(t, err, ErrUnknownWatchSuggestions)
+}
@@ -0,0 +1,30 @@
+package throttle
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// hidIdleTime matches the nanoseconds since the last keyboard or mouse
+// input in ioreg's output.
+var hidIdleTime = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+// IdleTime returns how long the keyboard and mouse have been idle.
+func IdleTime() (time.Duration, error) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4", "-r", "-k", "HIDIdleTime").Output()
+	if err != nil {
+		return 0, err
+	}
+	m := hidIdleTime.FindSubmatch(out)
+	if m == nil {
+		return 0, errors.New("no HIDIdleTime in ioreg output")
+	}
+	ns, err := strconv.ParseInt(string(m[1]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ns), nil
+}
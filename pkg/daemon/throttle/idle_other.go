@@ -0,0 +1,14 @@
+//go:build !darwin
+
+package throttle
+
+import (
+	"errors"
+	"time"
+)
+
+// IdleTime returns errors.ErrUnsupported: there is no one way to read
+// keyboard and mouse activity here, across X11, Wayland, and consoles.
+func IdleTime() (time.Duration, error) {
+	return 0, errors.ErrUnsupported
+}
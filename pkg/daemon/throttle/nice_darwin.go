@@ -0,0 +1,8 @@
+package throttle
+
+import "golang.org/x/sys/unix"
+
+// SetNice sets the daemon's nice level, from 0 to 19 for lower priority.
+func SetNice(n int) error {
+	return unix.Setpriority(unix.PRIO_PROCESS, 0, n)
+}
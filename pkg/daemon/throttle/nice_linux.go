@@ -0,0 +1,30 @@
+package throttle
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetNice sets the daemon's nice level, from 0 to 19 for lower priority.
+// Linux sets it per thread, so each existing thread is set; threads
+// started later inherit it. The disk scheduler also gives lower I/O
+// priority to nicer processes that don't set their own.
+func SetNice(n int) error {
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return unix.Setpriority(unix.PRIO_PROCESS, 0, n)
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		if err := unix.Setpriority(unix.PRIO_PROCESS, tid, n); err != nil && !errors.Is(err, unix.ESRCH) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package throttle
+
+import "errors"
+
+// SetNice returns errors.ErrUnsupported: nice levels don't exist here.
+func SetNice(int) error {
+	return errors.ErrUnsupported
+}
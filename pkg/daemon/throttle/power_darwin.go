@@ -0,0 +1,15 @@
+package throttle
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// OnBattery reports whether the Mac is running on battery, as pmset says.
+func OnBattery() (bool, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(out), "'Battery Power'"), nil
+}
@@ -0,0 +1,39 @@
+package throttle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// powerSupplyDir is where Linux lists batteries and AC adapters.
+const powerSupplyDir = "/sys/class/power_supply"
+
+// OnBattery reports whether the machine is running on battery: a battery
+// is discharging. Machines without one never are.
+func OnBattery() (bool, error) {
+	supplies, err := os.ReadDir(powerSupplyDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, s := range supplies {
+		dir := filepath.Join(powerSupplyDir, s.Name())
+		if readAttr(dir, "type") == "Battery" && readAttr(dir, "status") == "Discharging" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// readAttr returns the trimmed contents of a power supply attribute, or ""
+// when it can't be read.
+func readAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package throttle
+
+import "errors"
+
+// OnBattery returns errors.ErrUnsupported: the power source isn't read on
+// this platform.
+func OnBattery() (bool, error) {
+	return false, errors.ErrUnsupported
+}
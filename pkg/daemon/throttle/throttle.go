@@ -0,0 +1,92 @@
+// Package throttle decides when the daemon's background indexing should
+// wait, such as on battery or while the user is at the machine, and lowers
+// the daemon's scheduling priority.
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// checkInterval is how long a check of the battery and user activity is
+// reused. Both can start a process, which is too slow to do per file.
+const checkInterval = 10 * time.Second
+
+// Pauser reports when indexing should wait.
+type Pauser struct {
+	// OnBattery pauses indexing while the machine runs on battery.
+	OnBattery bool
+
+	// ActiveWithin, if set, pauses indexing until the keyboard and mouse
+	// have been idle this long.
+	ActiveWithin time.Duration
+
+	// onBattery and idleTime read the machine's state; replaced in tests
+	onBattery func() (bool, error)
+	idleTime  func() (time.Duration, error)
+
+	mu      sync.Mutex
+	checked time.Time
+	reason  string
+	warned  map[string]bool // Checks that failed, warned about once
+}
+
+// NewPauser returns a Pauser for the given conditions, or nil when there
+// are none.
+func NewPauser(onBattery bool, activeWithin time.Duration) *Pauser {
+	if !onBattery && activeWithin <= 0 {
+		return nil
+	}
+	return &Pauser{
+		OnBattery:    onBattery,
+		ActiveWithin: activeWithin,
+		onBattery:    OnBattery,
+		idleTime:     IdleTime,
+		warned:       make(map[string]bool),
+	}
+}
+
+// Paused reports whether indexing should wait, and why. A condition that
+// can't be checked, such as user activity on a platform without a way to
+// read it, never pauses.
+func (p *Pauser) Paused() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now := time.Now(); now.Sub(p.checked) >= checkInterval {
+		p.checked = now
+		p.reason = p.check()
+	}
+	return p.reason, p.reason != ""
+}
+
+// check returns why indexing should wait now, or "".
+func (p *Pauser) check() string {
+	if p.OnBattery {
+		battery, err := p.onBattery()
+		if err != nil {
+			p.warn("pause_on_battery", err)
+		} else if battery {
+			return "on battery"
+		}
+	}
+	if p.ActiveWithin > 0 {
+		idle, err := p.idleTime()
+		if err != nil {
+			p.warn("pause_on_activity", err)
+		} else if idle < p.ActiveWithin {
+			return "user active"
+		}
+	}
+	return ""
+}
+
+// warn logs once that a condition can't be checked.
+func (p *Pauser) warn(setting string, err error) {
+	if p.warned[setting] {
+		return
+	}
+	p.warned[setting] = true
+	logging.Get("daemon").Warn("cannot check daemon.throttle."+setting+", ignoring it", "error", err)
+}
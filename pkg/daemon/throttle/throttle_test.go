@@ -0,0 +1,42 @@
+package throttle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauser(t *testing.T) {
+	assert.Nil(t, NewPauser(false, 0), "no conditions, no pauser")
+
+	battery, idle := false, time.Hour
+	p := NewPauser(true, 2*time.Minute)
+	p.onBattery = func() (bool, error) { return battery, nil }
+	p.idleTime = func() (time.Duration, error) { return idle, nil }
+
+	_, paused := p.Paused()
+	assert.False(t, paused)
+
+	battery = true
+	_, paused = p.Paused()
+	assert.False(t, paused, "checks are reused for a while")
+	p.checked = time.Time{}
+	reason, paused := p.Paused()
+	assert.True(t, paused)
+	assert.Equal(t, "on battery", reason)
+
+	battery, idle = false, 10*time.Second
+	p.checked = time.Time{}
+	reason, paused = p.Paused()
+	assert.True(t, paused)
+	assert.Equal(t, "user active", reason)
+
+	// Conditions that can't be checked don't pause
+	p.idleTime = func() (time.Duration, error) { return 0, errors.ErrUnsupported }
+	p.checked = time.Time{}
+	_, paused = p.Paused()
+	assert.False(t, paused)
+	assert.True(t, p.warned["pause_on_activity"])
+}
@@ -0,0 +1,269 @@
+package tree
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultFlushDelay is how long callers should wait after the first pending
+// change before flushing, so bursts of events are aggregated together.
+const DefaultFlushDelay = 200 * time.Millisecond
+
+// delta is a pending change to a directory's aggregates.
+type delta struct {
+	size  int64
+	count int
+}
+
+// Aggregator applies file changes to a tree incrementally.
+//
+// Upsert and Remove update file nodes immediately but only record the
+// size and count change against the file's parent directory. Flush then
+// propagates the net change of each touched directory to its ancestors once,
+// re-sorts only the directories whose children changed, and prunes
+// directories left empty. Event-heavy directories (build output, caches)
+// therefore cost one ancestor walk per flush instead of one per event.
+type Aggregator struct {
+	root  *Node
+	nodes map[string]*Node // Path index of every node under root
+
+	// DetectType returns the file type for new file nodes.
+	// Defaults to DetectFileType.
+	DetectType func(path string) string
+
+	pending map[*Node]delta // Aggregate changes per parent directory
+	dirty   map[*Node]bool  // Directories whose children changed
+}
+
+// NewAggregator creates an aggregator for an existing tree.
+// The tree's aggregates are assumed to be up to date.
+func NewAggregator(root *Node) *Aggregator {
+	a := &Aggregator{
+		root:       root,
+		nodes:      make(map[string]*Node),
+		DetectType: DetectFileType,
+		pending:    make(map[*Node]delta),
+		dirty:      make(map[*Node]bool),
+	}
+	if root != nil {
+		a.index(root)
+	}
+	return a
+}
+
+// index adds node and its descendants to the path index.
+func (a *Aggregator) index(node *Node) {
+	a.nodes[node.Path] = node
+	for _, child := range node.Children {
+		a.index(child)
+	}
+}
+
+// unindex removes node and its descendants from the path index and from
+// any pending bookkeeping.
+func (a *Aggregator) unindex(node *Node) {
+	delete(a.nodes, node.Path)
+	delete(a.pending, node)
+	delete(a.dirty, node)
+	for _, child := range node.Children {
+		a.unindex(child)
+	}
+}
+
+// Lookup returns the node for path, or nil if it is not in the tree.
+func (a *Aggregator) Lookup(path string) *Node {
+	return a.nodes[path]
+}
+
+// Pending reports whether there are changes waiting for Flush.
+func (a *Aggregator) Pending() bool {
+	return len(a.pending) > 0 || len(a.dirty) > 0
+}
+
+// Upsert adds a file to the tree or updates its size and modification time.
+// Missing parent directories are created collapsed. Files outside the root
+// are ignored. Returns the file node, or nil if the change was ignored.
+func (a *Aggregator) Upsert(path string, size, modTime int64) *Node {
+	if a.root == nil {
+		return nil
+	}
+
+	if node, ok := a.nodes[path]; ok {
+		if node.IsDir {
+			return nil
+		}
+		if node.Size != size {
+			a.record(node.Parent, size-node.Size, 0)
+			node.Size = size
+		}
+		node.ModTime = modTime
+		return node
+	}
+
+	parent := a.ensureDir(filepath.Dir(path))
+	if parent == nil {
+		return nil
+	}
+
+	node := &Node{
+		Path:     path,
+		Name:     filepath.Base(path),
+		Size:     size,
+		ModTime:  modTime,
+		FileType: a.DetectType(path),
+	}
+	parent.AddChild(node)
+	a.nodes[path] = node
+	a.record(parent, size, 1)
+	return node
+}
+
+// Remove deletes a file or directory from the tree.
+// Returns false if the path is not in the tree or is the root.
+func (a *Aggregator) Remove(path string) bool {
+	node, ok := a.nodes[path]
+	if !ok || node.Parent == nil {
+		return false
+	}
+
+	// Ancestors only reflect flushed changes, which for a directory are its
+	// own aggregates; pending changes below it are dropped with it.
+	size, count := node.Size, 1
+	if node.IsDir {
+		size, count = node.LargeFileSize, node.LargeFileCount
+	}
+
+	parent := node.Parent
+	for i, child := range parent.Children {
+		if child == node {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			break
+		}
+	}
+	node.Parent = nil
+	a.unindex(node)
+	a.record(parent, -size, -count)
+	return true
+}
+
+// Flush propagates pending aggregate changes to ancestors, re-sorts changed
+// directories, and removes directories left without children.
+// Returns true if the tree changed.
+func (a *Aggregator) Flush() bool {
+	if !a.Pending() {
+		return false
+	}
+
+	// Propagate each directory's net change up to the root once. A size
+	// change can move any of those ancestors among its siblings, so they
+	// all need re-sorting.
+	for dir, d := range a.pending {
+		for n := dir; n != nil; n = n.Parent {
+			n.LargeFileSize += d.size
+			n.LargeFileCount += d.count
+			a.dirty[n] = true
+		}
+	}
+	clear(a.pending)
+
+	a.pruneEmpty()
+
+	for dir := range a.dirty {
+		sortNodes(dir.Children)
+	}
+	clear(a.dirty)
+	return true
+}
+
+// pruneEmpty removes dirty directories that no longer have children,
+// walking up while parents become empty too. The root is never removed.
+func (a *Aggregator) pruneEmpty() {
+	for dir := range a.dirty {
+		for n := dir; n.Parent != nil && n.IsDir && len(n.Children) == 0; {
+			parent := n.Parent
+			for i, child := range parent.Children {
+				if child == n {
+					parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+					break
+				}
+			}
+			n.Parent = nil
+			delete(a.nodes, n.Path)
+			delete(a.dirty, n)
+			a.dirty[parent] = true
+			n = parent
+		}
+	}
+}
+
+// record notes a pending aggregate change for dir.
+func (a *Aggregator) record(dir *Node, size int64, count int) {
+	if dir == nil {
+		return
+	}
+	d := a.pending[dir]
+	d.size += size
+	d.count += count
+	a.pending[dir] = d
+	a.dirty[dir] = true
+}
+
+// ensureDir returns the directory node for path, creating it and any
+// missing ancestors below the root. Returns nil if path is outside the root
+// or names a file.
+func (a *Aggregator) ensureDir(path string) *Node {
+	if node, ok := a.nodes[path]; ok {
+		if !node.IsDir {
+			return nil
+		}
+		return node
+	}
+	prefix := a.root.Path
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	if !strings.HasPrefix(path, prefix) {
+		return nil
+	}
+
+	parent := a.ensureDir(filepath.Dir(path))
+	if parent == nil {
+		return nil
+	}
+	dir := &Node{
+		Path:  path,
+		Name:  filepath.Base(path),
+		IsDir: true,
+	}
+	parent.AddChild(dir)
+	a.nodes[path] = dir
+	a.dirty[parent] = true
+	return dir
+}
+
+// sortNodes sorts sibling nodes by size descending.
+// Directories come before files when sizes are equal.
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+
+		aSize := a.Size
+		if a.IsDir {
+			aSize = a.LargeFileSize
+		}
+		bSize := b.Size
+		if b.IsDir {
+			bSize = b.LargeFileSize
+		}
+
+		if aSize != bSize {
+			return aSize > bSize
+		}
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		return a.Name < b.Name
+	})
+}
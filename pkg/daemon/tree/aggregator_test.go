@@ -0,0 +1,154 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/daemon/tree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aggregatorTestTree() *tree.Node {
+	return tree.BuildTree("/project", []tree.LargeFile{
+		{Path: "/project/src/main.go", Size: 1000},
+		{Path: "/project/src/utils.go", Size: 2000},
+		{Path: "/project/build/out.bin", Size: 5000},
+	}, 0)
+}
+
+// assertMatchesRebuild checks incremental aggregates against a full rebuild.
+func assertMatchesRebuild(t *testing.T, root *tree.Node, files []tree.LargeFile) {
+	t.Helper()
+	want := tree.BuildTree(root.Path, files, 0)
+	var walk func(got, want *tree.Node)
+	walk = func(got, want *tree.Node) {
+		assert.Equal(t, want.Path, got.Path)
+		assert.Equal(t, want.LargeFileSize, got.LargeFileSize, "size of %s", got.Path)
+		assert.Equal(t, want.LargeFileCount, got.LargeFileCount, "count of %s", got.Path)
+		require.Len(t, got.Children, len(want.Children), "children of %s", got.Path)
+		for i := range want.Children {
+			walk(got.Children[i], want.Children[i])
+		}
+	}
+	walk(root, want)
+}
+
+func TestAggregatorUpsert(t *testing.T) {
+	t.Run("defers aggregates until flush", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		node := agg.Upsert("/project/src/new.go", 4000, 1705600000)
+		require.NotNil(t, node)
+		assert.Equal(t, "Go", node.FileType)
+		assert.True(t, agg.Pending())
+		assert.Equal(t, int64(8000), root.LargeFileSize)
+
+		assert.True(t, agg.Flush())
+		assert.False(t, agg.Pending())
+		assertMatchesRebuild(t, root, []tree.LargeFile{
+			{Path: "/project/src/main.go", Size: 1000},
+			{Path: "/project/src/utils.go", Size: 2000},
+			{Path: "/project/src/new.go", Size: 4000},
+			{Path: "/project/build/out.bin", Size: 5000},
+		})
+	})
+
+	t.Run("updates existing files in place", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		agg.Upsert("/project/src/main.go", 9000, 1705600100)
+		agg.Flush()
+
+		assert.Equal(t, int64(1705600100), agg.Lookup("/project/src/main.go").ModTime)
+		assertMatchesRebuild(t, root, []tree.LargeFile{
+			{Path: "/project/src/main.go", Size: 9000},
+			{Path: "/project/src/utils.go", Size: 2000},
+			{Path: "/project/build/out.bin", Size: 5000},
+		})
+		assert.Equal(t, "src", root.Children[0].Name, "src should now sort first")
+	})
+
+	t.Run("creates collapsed parent directories", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		agg.Upsert("/project/build/obj/a/b.o", 100, 0)
+		agg.Flush()
+
+		dir := agg.Lookup("/project/build/obj/a")
+		require.NotNil(t, dir)
+		assert.True(t, dir.IsDir)
+		assert.False(t, dir.Expanded)
+		assert.Equal(t, int64(100), dir.LargeFileSize)
+		assert.Equal(t, int64(5100), agg.Lookup("/project/build").LargeFileSize)
+	})
+
+	t.Run("ignores paths outside the root", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		assert.Nil(t, agg.Upsert("/other/file.bin", 100, 0))
+		assert.Nil(t, agg.Upsert("/projectx/file.bin", 100, 0))
+		assert.False(t, agg.Pending())
+	})
+
+	t.Run("batches bursts of events", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		for i := 0; i < 100; i++ {
+			agg.Upsert("/project/build/out.bin", int64(5000+i), 0)
+		}
+		agg.Upsert("/project/build/tmp.o", 10, 0)
+		agg.Remove("/project/build/tmp.o")
+		agg.Flush()
+
+		assertMatchesRebuild(t, root, []tree.LargeFile{
+			{Path: "/project/src/main.go", Size: 1000},
+			{Path: "/project/src/utils.go", Size: 2000},
+			{Path: "/project/build/out.bin", Size: 5099},
+		})
+	})
+}
+
+func TestAggregatorRemove(t *testing.T) {
+	t.Run("removes files and prunes empty directories", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		assert.True(t, agg.Remove("/project/build/out.bin"))
+		agg.Flush()
+
+		assert.Nil(t, agg.Lookup("/project/build"))
+		assertMatchesRebuild(t, root, []tree.LargeFile{
+			{Path: "/project/src/main.go", Size: 1000},
+			{Path: "/project/src/utils.go", Size: 2000},
+		})
+	})
+
+	t.Run("removes directories with pending changes", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		agg.Upsert("/project/src/main.go", 1500, 0)
+		agg.Upsert("/project/src/pkg/extra.go", 700, 0)
+		assert.True(t, agg.Remove("/project/src"))
+		agg.Flush()
+
+		assert.Nil(t, agg.Lookup("/project/src/pkg/extra.go"))
+		assertMatchesRebuild(t, root, []tree.LargeFile{
+			{Path: "/project/build/out.bin", Size: 5000},
+		})
+	})
+
+	t.Run("ignores unknown paths and the root", func(t *testing.T) {
+		root := aggregatorTestTree()
+		agg := tree.NewAggregator(root)
+
+		assert.False(t, agg.Remove("/project/missing.bin"))
+		assert.False(t, agg.Remove("/project"))
+		assert.False(t, agg.Flush())
+	})
+}
@@ -2,8 +2,9 @@ package tree
 
 import (
 	"path/filepath"
-	"sort"
 	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
 )
 
 // LargeFile represents a file that exceeds the size threshold.
@@ -18,7 +19,9 @@ type LargeFile struct {
 // The resulting tree only contains directories that have large files underneath.
 // Children are sorted by size descending (directories by LargeFileSize, files by Size).
 func BuildTree(root string, files []LargeFile, minSize int64) *Node {
-	root = strings.TrimSuffix(root, "/")
+	if len(root) > len(filepath.VolumeName(root))+1 {
+		root = strings.TrimSuffix(root, string(filepath.Separator))
+	}
 
 	rootNode := &Node{
 		Path:  root,
@@ -72,7 +75,7 @@ func ensureAncestors(root, filePath string, nodes map[string]*Node) {
 
 	// Build list of directories to create (from file up to root)
 	var dirsToCreate []string
-	for parentPath != root && parentPath != "/" && parentPath != "." {
+	for parentPath != root && parentPath != "." && filepath.Dir(parentPath) != parentPath {
 		if _, exists := nodes[parentPath]; !exists {
 			dirsToCreate = append(dirsToCreate, parentPath)
 		}
@@ -122,33 +125,7 @@ func sortChildren(node *Node) {
 		return
 	}
 
-	// Sort children
-	sort.Slice(node.Children, func(i, j int) bool {
-		a, b := node.Children[i], node.Children[j]
-
-		// Get comparable size (LargeFileSize for dirs, Size for files)
-		aSize := a.Size
-		if a.IsDir {
-			aSize = a.LargeFileSize
-		}
-		bSize := b.Size
-		if b.IsDir {
-			bSize = b.LargeFileSize
-		}
-
-		// Sort by size descending
-		if aSize != bSize {
-			return aSize > bSize
-		}
-
-		// Directories before files when same size
-		if a.IsDir != b.IsDir {
-			return a.IsDir
-		}
-
-		// Alphabetical as tiebreaker
-		return a.Name < b.Name
-	})
+	sortNodes(node.Children)
 
 	// Sort children recursively
 	for _, child := range node.Children {
@@ -156,99 +133,9 @@ func sortChildren(node *Node) {
 	}
 }
 
-// fileTypeMap maps file extensions to human-readable type names.
-var fileTypeMap = map[string]string{
-	// Programming languages
-	".go":   "Go",
-	".py":   "Python",
-	".js":   "JavaScript",
-	".ts":   "TypeScript",
-	".rs":   "Rust",
-	".c":    "C",
-	".cpp":  "C++",
-	".cc":   "C++",
-	".cxx":  "C++",
-	".java": "Java",
-	".rb":   "Ruby",
-	".sh":   "Shell",
-	".bash": "Shell",
-	".zsh":  "Shell",
-
-	// Web
-	".html":   "HTML",
-	".htm":    "HTML",
-	".css":    "CSS",
-	".jsx":    "JSX",
-	".tsx":    "TSX",
-	".vue":    "Vue",
-	".svelte": "Svelte",
-
-	// Data/Config
-	".json": "JSON",
-	".yaml": "YAML",
-	".yml":  "YAML",
-	".toml": "TOML",
-	".xml":  "XML",
-	".csv":  "CSV",
-
-	// Documentation
-	".md":       "Markdown",
-	".markdown": "Markdown",
-	".txt":      "Text",
-	".pdf":      "PDF",
-
-	// Images
-	".png":  "Image",
-	".jpg":  "Image",
-	".jpeg": "Image",
-	".gif":  "Image",
-	".svg":  "Image",
-	".webp": "Image",
-	".bmp":  "Image",
-	".ico":  "Image",
-
-	// Video
-	".mp4":  "Video",
-	".mov":  "Video",
-	".avi":  "Video",
-	".mkv":  "Video",
-	".webm": "Video",
-
-	// Audio
-	".mp3":  "Audio",
-	".wav":  "Audio",
-	".ogg":  "Audio",
-	".flac": "Audio",
-	".aac":  "Audio",
-
-	// Archives
-	".zip": "Archive",
-	".tar": "Archive",
-	".gz":  "Archive",
-	".rar": "Archive",
-	".7z":  "Archive",
-	".bz2": "Archive",
-	".xz":  "Archive",
-
-	// Executables and libraries
-	".exe":   "Executable",
-	".dll":   "Library",
-	".so":    "Library",
-	".dylib": "Library",
-	".a":     "Library",
-	".wasm":  "WebAssembly",
-
-	// Database
-	".db":      "Database",
-	".sqlite":  "Database",
-	".sqlite3": "Database",
-}
-
-// DetectFileType returns a human-readable file type based on the file extension.
+// DetectFileType returns a human-readable file type based on the file
+// extension. Trees are built from many files at once, so their content
+// isn't read; see filetype.Detect.
 func DetectFileType(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-	if fileType, ok := fileTypeMap[ext]; ok {
-		return fileType
-	}
-	return "File"
+	return filetype.FromExtension(path)
 }
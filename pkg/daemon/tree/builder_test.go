@@ -103,6 +103,19 @@ func TestBuildTree(t *testing.T) {
 		require.Len(t, root.Children, 1)
 		assert.Equal(t, "/project/main.go", root.Children[0].Path)
 	})
+
+	t.Run("builds tree from the filesystem root", func(t *testing.T) {
+		files := []tree.LargeFile{
+			{Path: "/project/main.go", Size: 1000, ModTime: 1705600000},
+		}
+
+		root := tree.BuildTree("/", files, 0)
+
+		assert.Equal(t, "/", root.Path)
+		require.Len(t, root.Children, 1)
+		assert.Equal(t, "/project", root.Children[0].Path)
+		assert.Equal(t, int64(1000), root.LargeFileSize)
+	})
 }
 
 func TestBuildTreeHidesEmptyDirs(t *testing.T) {
@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+// volumeCheckInterval is how often the daemon looks for external drives
+// plugged in again, possibly at another mount point.
+const volumeCheckInterval = 30 * time.Second
+
+// rememberVolume records the mount point of the external volume holding
+// root, so its index and watches can follow the volume when it is mounted
+// somewhere else. The system's root volume never moves and isn't recorded.
+func (s *Service) rememberVolume(root string) {
+	if s.volumes == nil {
+		return
+	}
+	vols, err := s.volumes()
+	if err != nil {
+		logging.Get("daemon").Debug("failed to list volumes", "error", err)
+		return
+	}
+	v, ok := mounts.VolumeAt(vols, root)
+	if !ok || v.MountPoint == "/" {
+		return
+	}
+	if err := s.store.SetVolumeMount(v.UUID, v.MountPoint); err != nil {
+		logging.Get("daemon").Warn("failed to record volume", "path", root, "uuid", v.UUID, "error", err)
+	}
+}
+
+// reattachVolumes looks for recorded volumes mounted since the last check,
+// as when a drive is plugged in again. The index and watches of one
+// mounted at another mount point move to the new one, and its saved
+// watches are watched again. It returns the number of volumes moved.
+func (s *Service) reattachVolumes(ctx context.Context) int {
+	if s.volumes == nil {
+		return 0
+	}
+	log := logging.Get("daemon")
+	recorded, err := s.store.GetVolumeMounts()
+	if err != nil || len(recorded) == 0 {
+		return 0
+	}
+	vols, err := s.volumes()
+	if err != nil {
+		log.Debug("failed to list volumes", "error", err)
+		return 0
+	}
+
+	s.volumeMu.Lock()
+	defer s.volumeMu.Unlock()
+	mounted := make(map[string]string, len(vols))
+	moved := 0
+	for _, v := range vols {
+		mounted[v.UUID] = v.MountPoint
+		old, ok := recorded[v.UUID]
+		if !ok || s.mounted[v.UUID] == v.MountPoint {
+			continue // Not recorded, or mounted there at the last check
+		}
+		if old != v.MountPoint {
+			if err := s.moveVolume(old, v.MountPoint); err != nil {
+				log.Warn("failed to move index to new mount point", "uuid", v.UUID, "from", old, "to", v.MountPoint, "error", err)
+				continue
+			}
+			if err := s.store.SetVolumeMount(v.UUID, v.MountPoint); err != nil {
+				log.Warn("failed to record volume", "uuid", v.UUID, "error", err)
+			}
+			log.Info("volume mounted somewhere new, index moved", "uuid", v.UUID, "from", old, "to", v.MountPoint)
+			moved++
+		}
+		s.rewatch(ctx, v.MountPoint)
+	}
+	s.mounted = mounted
+	return moved
+}
+
+// moveVolume re-keys the indexed paths and saved watches under the mount
+// point from to the same paths under to.
+func (s *Service) moveVolume(from, to string) error {
+	rebase := func(path string) string { return to + path[len(from):] }
+
+	roots, err := s.store.GetIndexedPaths()
+	if err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if !store.IsPathUnderRoot(root, from) || s.isIndexingPath(root) {
+			continue
+		}
+		moved := rebase(root)
+		if _, err := s.store.Move(root, moved); err != nil {
+			return err
+		}
+		if meta := s.store.GetIndexMeta(root); meta != nil {
+			if err := s.store.SetIndexMeta(moved, meta); err != nil {
+				return err
+			}
+		}
+		if t := s.store.LastQueried(root); !t.IsZero() {
+			_ = s.store.TouchRoot(moved, t) // Only orders evictions
+		}
+		if err := s.store.RemoveIndexedPath(root); err != nil {
+			return err
+		}
+		if err := s.store.AddIndexedPath(moved); err != nil {
+			return err
+		}
+
+		if s.watcher != nil {
+			s.watcher.Unwatch(root)
+		}
+		s.indexMu.Lock()
+		delete(s.indexStates, root)
+		s.indexMu.Unlock()
+	}
+
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		return err
+	}
+	for _, root := range saved {
+		if !store.IsPathUnderRoot(root, from) {
+			continue
+		}
+		if _, err := s.store.RemoveWatchedRoot(root); err != nil {
+			return err
+		}
+		if err := s.store.AddWatchedRoot(rebase(root)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewatch watches the saved watches under a newly mounted mountPoint again,
+// unless they are being indexed. Their indexes are kept, so only the watch
+// starts; watches from before the volume was unmounted are dropped.
+func (s *Service) rewatch(ctx context.Context, mountPoint string) {
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		return
+	}
+	for _, root := range saved {
+		if !store.IsPathUnderRoot(root, mountPoint) || s.isIndexingPath(root) {
+			continue
+		}
+		if s.watcher != nil {
+			s.watcher.Unwatch(root)
+		}
+		s.indexMu.Lock()
+		delete(s.indexStates, root)
+		s.indexMu.Unlock()
+		if _, err := s.TriggerIndex(ctx, &sweepv1.TriggerIndexRequest{Path: root}); err != nil {
+			logging.Get("daemon").Warn("failed to watch path on volume", "path", root, "error", err)
+		}
+	}
+}
+
+// checkVolumes reattaches moved volumes every volumeCheckInterval until
+// ctx is done.
+func (s *Server) checkVolumes(ctx context.Context) {
+	ticker := time.NewTicker(volumeCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.IsMigrating() {
+				s.service.reattachVolumes(ctx)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+func TestVolumeFollowedToNewMountPoint(t *testing.T) {
+	media, tmpDir := t.TempDir(), t.TempDir()
+	oldMount, newMount := filepath.Join(media, "usb"), filepath.Join(media, "usb1")
+	require.NoError(t, os.MkdirAll(filepath.Join(oldMount, "photos"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(oldMount, "photos", "big.raw"), make([]byte, 100), 0o644))
+
+	srv, err := NewServer(Config{
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		DataDir:    filepath.Join(tmpDir, "data"),
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+	svc := srv.service
+	vol := mounts.Volume{UUID: "1234-ABCD", MountPoint: oldMount}
+	svc.volumes = func() ([]mounts.Volume, error) { return []mounts.Volume{vol}, nil }
+	ctx := context.Background()
+	ready := func(path string) func() bool {
+		return func() bool {
+			resp, err := svc.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+			require.NoError(t, err)
+			for _, root := range resp.GetRoots() {
+				if root.GetPath() == path {
+					return root.GetState() == sweepv1.IndexState_INDEX_STATE_READY
+				}
+			}
+			return false
+		}
+	}
+
+	_, err = svc.AddWatch(ctx, &sweepv1.AddWatchRequest{Path: filepath.Join(oldMount, "photos")})
+	require.NoError(t, err)
+	require.Eventually(t, ready(filepath.Join(oldMount, "photos")), 5*time.Second, 20*time.Millisecond)
+	recorded, err := svc.store.GetVolumeMounts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{vol.UUID: oldMount}, recorded)
+	assert.Zero(t, svc.reattachVolumes(ctx), "nothing moved while the drive stays put")
+
+	// Plugged in again at another mount point
+	require.NoError(t, os.Rename(oldMount, newMount))
+	vol.MountPoint = newMount
+	assert.Equal(t, 1, svc.reattachVolumes(ctx))
+
+	photos := filepath.Join(newMount, "photos")
+	saved, err := svc.store.GetWatchedRoots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{photos}, saved)
+	indexed, err := svc.store.GetIndexedPaths()
+	require.NoError(t, err)
+	assert.Equal(t, []string{photos}, indexed)
+	entry, err := svc.store.Get(filepath.Join(photos, "big.raw"))
+	require.NoError(t, err, "the index moved with the drive")
+	assert.Equal(t, int64(100), entry.Size)
+	require.Eventually(t, ready(photos), 5*time.Second, 20*time.Millisecond)
+
+	recorded, err = svc.store.GetVolumeMounts()
+	require.NoError(t, err)
+	assert.Equal(t, newMount, recorded[vol.UUID])
+}
@@ -3,30 +3,98 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/scanner"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 )
 
 // Watcher watches directories for filesystem changes and updates the store.
 type Watcher struct {
-	store            *store.Store
+	store            store.StorageBackend
 	watcher          *fsnotify.Watcher
 	paths            map[string]bool
 	mu               sync.RWMutex
 	closed           bool
 	broadcaster      *broadcaster.Broadcaster
-	minLargeFileSize int64 // Threshold for large files index
+	minLargeFileSize int64    // Threshold for large files index
+	aggregates       bool     // Store directories and large files only
+	includeSnapshots bool     // Watch filesystem snapshot directories too
+	oneFileSystem    bool     // Stay on each root's file system, except at crossMounts
+	skipMounts       []string // Mount points never watched
+	crossMounts      []string // Mount points watched even with oneFileSystem
+	network          mounts.Network
+	watchNetwork     bool // Watch network file systems too
+	renamed          *renamedDir
+	paused           map[string]*pausedRoot
+	onError          func(error)
+
+	events    atomic.Int64
+	errors    atomic.Int64
+	lastEvent atomic.Int64 // Unix seconds
+}
+
+// Stats reports the watcher's activity since it was created.
+type Stats struct {
+	Dirs      int       // Directories with a filesystem watch
+	Events    int64     // Filesystem events received
+	Errors    int64     // Errors reported by the OS
+	LastEvent time.Time // Zero if there were none
+	Queued    int       // Events received from the OS but not handled yet
+	Held      int       // Changed directories held back under paused roots
+}
+
+// renameWindow is how long a renamed directory waits for the create event
+// of its new name. fsnotify reports a rename as the old name going away
+// and the new one appearing; a directory moved out of the watched paths
+// never reappears and is treated as removed once the window passes.
+const renameWindow = time.Second
+
+// renameSample is how many entries under a renamed directory are checked
+// at the new name before the two events are taken to be one move.
+const renameSample = 8
+
+// renamedDir is a watched directory that was renamed and has not been
+// matched with its new name yet.
+type renamedDir struct {
+	path  string
+	timer *time.Timer
+}
+
+// maxPausedDirs is how many changed directories a paused root tracks.
+// Past it, the root is indexed again on resume rather than reconciled.
+const maxPausedDirs = 10000
+
+// ErrNotPaused is returned by Resume for a root that isn't paused.
+var ErrNotPaused = errors.New("not paused")
+
+// ErrTooManyChanges is returned by Resume when more directories changed
+// while the root was paused than were tracked, so the root must be indexed
+// again to catch up.
+var ErrTooManyChanges = errors.New("too many changes while paused")
+
+// pausedRoot collects the directories that changed under a paused root.
+type pausedRoot struct {
+	dirs     map[string]bool
+	overflow bool
 }
 
 // New creates a new Watcher.
-func New(s *store.Store) (*Watcher, error) {
+func New(s store.StorageBackend) (*Watcher, error) {
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
@@ -36,6 +104,7 @@ func New(s *store.Store) (*Watcher, error) {
 		store:   s,
 		watcher: fsw,
 		paths:   make(map[string]bool),
+		paused:  make(map[string]*pausedRoot),
 	}, nil
 }
 
@@ -46,6 +115,36 @@ func (w *Watcher) SetBroadcaster(b *broadcaster.Broadcaster) {
 	w.broadcaster = b
 }
 
+// SetErrorHandler sets a function called with each error the OS reports,
+// such as an overflowed event queue, after it is logged.
+func (w *Watcher) SetErrorHandler(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = fn
+}
+
+// Stats returns the watcher's activity since it was created.
+func (w *Watcher) Stats() Stats {
+	w.mu.RLock()
+	dirs := len(w.paths)
+	held := 0
+	for _, p := range w.paused {
+		held += len(p.dirs)
+	}
+	w.mu.RUnlock()
+	st := Stats{
+		Dirs:   dirs,
+		Events: w.events.Load(),
+		Errors: w.errors.Load(),
+		Queued: len(w.watcher.Events),
+		Held:   held,
+	}
+	if last := w.lastEvent.Load(); last > 0 {
+		st.LastEvent = time.Unix(last, 0)
+	}
+	return st
+}
+
 // SetMinLargeFileSize sets the threshold for large files.
 func (w *Watcher) SetMinLargeFileSize(size int64) {
 	w.mu.Lock()
@@ -53,9 +152,82 @@ func (w *Watcher) SetMinLargeFileSize(size int64) {
 	w.minLargeFileSize = size
 }
 
+// SetAggregates makes the watcher match an index built in aggregates mode:
+// file entries are not stored, only the large files index is kept current,
+// and directory totals are left as of the last index.
+func (w *Watcher) SetAggregates(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.aggregates = enabled
+}
+
+// SetIncludeSnapshots makes the watcher watch and store directories of
+// filesystem snapshots, such as .zfs/snapshot, which it otherwise skips to
+// match an index that skipped them.
+func (w *Watcher) SetIncludeSnapshots(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.includeSnapshots = enabled
+}
+
+// skipsSnapshot reports whether dir holds snapshots the watcher skips.
+func (w *Watcher) skipsSnapshot(dir string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return !w.includeSnapshots && mounts.IsSnapshotDir(dir)
+}
+
+// SetMountBoundary keeps the watcher out of the skip mount points and,
+// with oneFileSystem, off other file systems than a watched root's except
+// at the cross mount points, to match an index that left them out.
+func (w *Watcher) SetMountBoundary(oneFileSystem bool, skip, cross []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.oneFileSystem, w.skipMounts, w.crossMounts = oneFileSystem, skip, cross
+}
+
+// SetNetwork tells the watcher which paths are on network file systems,
+// which it doesn't watch unless watch is set: their file events are
+// unreliable, and watching means listing every directory over the network.
+func (w *Watcher) SetNetwork(network mounts.Network, watch bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.network, w.watchNetwork = network, watch
+}
+
+// boundary returns the mount points a walk of root stays out of,
+// including network ones not watched.
+func (w *Watcher) boundary(root string) *scanner.Boundary {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	skip := w.skipMounts
+	if !w.watchNetwork {
+		skip = append(slices.Clone(skip), w.network.MountsUnder(root)...)
+	}
+	return scanner.NewBoundary(root, w.oneFileSystem, skip, w.crossMounts)
+}
+
+// onUnwatchedNetwork reports whether root is on a network file system the
+// watcher doesn't watch.
+func (w *Watcher) onUnwatchedNetwork(root string) bool {
+	w.mu.RLock()
+	network, watch := w.network, w.watchNetwork
+	w.mu.RUnlock()
+	if watch {
+		return false
+	}
+	mountPoint, fsType, ok := network.Find(root)
+	if ok {
+		logging.Get("watcher").Info("not watching a network file system for changes",
+			"path", root, "mount", mountPoint, "type", fsType)
+	}
+	return ok
+}
+
 // Watch starts watching a path recursively.
 // It adds watches to the root directory and all subdirectories.
-// Symlinks are not followed to avoid loops.
+// Symlinks are not followed to avoid loops. A path on a network file
+// system isn't watched unless SetNetwork says to.
 func (w *Watcher) Watch(root string) error {
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
@@ -71,8 +243,12 @@ func (w *Watcher) Watch(root string) error {
 	if !info.IsDir() {
 		return nil // Only watch directories
 	}
+	if w.onUnwatchedNetwork(absRoot) {
+		return nil
+	}
 
 	// Walk and add all directories
+	boundary := w.boundary(absRoot)
 	return filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return nil //nolint:nilerr // Skip entries with errors
@@ -84,6 +260,9 @@ func (w *Watcher) Watch(root string) error {
 		}
 
 		if d.IsDir() {
+			if path != absRoot && (w.skipsSnapshot(path) || boundary.Stops(path, d)) {
+				return filepath.SkipDir
+			}
 			return w.addWatch(path)
 		}
 
@@ -135,6 +314,153 @@ func (w *Watcher) Unwatch(root string) {
 			delete(w.paths, path)
 		}
 	}
+	for path := range w.paused {
+		if path == absRoot || isSubPath(path, absRoot) {
+			delete(w.paused, path)
+		}
+	}
+}
+
+// Pause stops applying changes under root until Resume is called, so a
+// burst of changes such as a large build doesn't flood the event pipeline.
+// The directories that change are remembered, for Resume to catch up on.
+// It returns false if root was already paused.
+func (w *Watcher) Pause(root string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.paused[root]; ok {
+		return false
+	}
+	w.paused[root] = &pausedRoot{dirs: make(map[string]bool)}
+	return true
+}
+
+// Paused reports whether root is paused.
+func (w *Watcher) Paused(root string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.paused[root]
+	return ok
+}
+
+// Resume applies changes under root again, after bringing the store up to
+// date with the directories that changed while it was paused. It returns
+// how many directories were reconciled, ErrNotPaused if root wasn't
+// paused, or ErrTooManyChanges if too many directories changed to track.
+func (w *Watcher) Resume(root string) (int, error) {
+	w.mu.Lock()
+	p, ok := w.paused[root]
+	delete(w.paused, root)
+	w.mu.Unlock()
+	if !ok {
+		return 0, ErrNotPaused
+	}
+	if p.overflow {
+		return 0, ErrTooManyChanges
+	}
+
+	// Parents first, so a new directory is added whole before its
+	// subdirectories are looked at
+	dirs := slices.Sorted(maps.Keys(p.dirs))
+	for _, dir := range dirs {
+		w.reconcileDir(dir)
+	}
+	logging.Get("watcher").Info("resumed watch", "path", root, "dirs_reconciled", len(dirs))
+	return len(dirs), nil
+}
+
+// holdPaused records the directory an event under a paused root changed,
+// and reports whether the event is held back.
+func (w *Watcher) holdPaused(path string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for root, p := range w.paused {
+		if path != root && !isSubPath(path, root) {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if path == root {
+			dir = root
+		}
+		if !p.dirs[dir] && len(p.dirs) >= maxPausedDirs {
+			p.overflow = true
+		} else if !p.overflow {
+			p.dirs[dir] = true
+		}
+		return true
+	}
+	return false
+}
+
+// reconcileDir brings the store up to date with the entries of dir: new
+// entries are added, with everything under new directories, changed files
+// are updated, and entries that are gone are removed.
+func (w *Watcher) reconcileDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			w.handleRemove(dir)
+		}
+		return
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		seen[path] = true
+		if e.Type()&fs.ModeSymlink != 0 {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		stored, err := w.store.Get(path)
+		switch {
+		case err != nil && info.IsDir():
+			w.addTree(path)
+		case err != nil:
+			w.storeCreated(path, info)
+		case !info.IsDir() && (stored.Size != info.Size() || stored.ModTime != info.ModTime().Unix()):
+			w.handleWrite(path)
+		}
+	}
+
+	var removed []string
+	_ = w.store.Walk(dir, func(e *store.Entry) error {
+		if filepath.Dir(e.Path) == dir && e.Path != dir && !seen[e.Path] {
+			removed = append(removed, e.Path)
+		}
+		return nil
+	})
+	for _, path := range removed {
+		w.handleRemove(path)
+	}
+}
+
+// addTree watches and stores a new directory and everything under it.
+func (w *Watcher) addTree(root string) {
+	boundary := w.boundary(root)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return nil //nolint:nilerr // Skip entries with errors
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil // Skip symlinks
+		}
+		if d.IsDir() && (w.skipsSnapshot(path) || (path != root && boundary.Stops(path, d))) {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr // Gone since it was listed
+		}
+		if d.IsDir() {
+			_ = w.addWatch(path)
+		}
+		w.storeCreated(path, info)
+		return nil
+	})
 }
 
 // Run starts the event loop. It blocks until the context is cancelled.
@@ -150,19 +476,32 @@ func (w *Watcher) Run(ctx context.Context, onChange func(path string, op fsnotif
 				return
 			}
 
+			w.events.Add(1)
+			w.lastEvent.Store(time.Now().Unix())
 			w.handleEvent(event, onChange)
 
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
 				return
 			}
+			w.errors.Add(1)
 			logging.Get("watcher").Error("watcher error", "error", err)
+			w.mu.RLock()
+			onError := w.onError
+			w.mu.RUnlock()
+			if onError != nil {
+				onError(err)
+			}
 		}
 	}
 }
 
 // handleEvent processes a single filesystem event.
 func (w *Watcher) handleEvent(event fsnotify.Event, onChange func(path string, op fsnotify.Op)) {
+	if w.holdPaused(event.Name) {
+		return
+	}
+
 	// Handle different event types
 	switch {
 	case event.Op&fsnotify.Create != 0:
@@ -194,6 +533,22 @@ func (w *Watcher) handleCreate(path string) {
 		return
 	}
 
+	// A directory renamed within the watched paths moves in the store
+	// rather than being removed and created again
+	if info.IsDir() {
+		if oldPath, ok := w.takeRenamed(); ok {
+			if w.moveDir(oldPath, path) {
+				return
+			}
+			w.handleRemove(oldPath)
+		}
+	}
+
+	// Snapshots aren't indexed, so aren't watched either
+	if info.IsDir() && w.skipsSnapshot(path) {
+		return
+	}
+
 	// If it's a directory, add a watch for it
 	if info.IsDir() {
 		// Add watch to this directory
@@ -208,28 +563,48 @@ func (w *Watcher) handleCreate(path string) {
 				return nil // Skip symlinks
 			}
 			if d.IsDir() && subpath != path {
+				if w.skipsSnapshot(subpath) {
+					return filepath.SkipDir
+				}
 				_ = w.addWatch(subpath)
 			}
 			return nil
 		})
 	}
 
-	// Update store with new entry
+	w.storeCreated(path, info)
+}
+
+// storeCreated adds a new file or directory to the store and announces a
+// new file.
+func (w *Watcher) storeCreated(path string, info fs.FileInfo) {
+	// Update store with new entry; in aggregates mode only directories
+	// are stored, starting with no files counted
 	entry := &store.Entry{
 		Path:    path,
 		Size:    info.Size(),
 		ModTime: info.ModTime().Unix(),
 		IsDir:   info.IsDir(),
 	}
+	if !info.IsDir() {
+		entry.Shared = w.fileSharing(path, info)
+		entry.Cloud = cloud.FromFileInfo(path, info)
+	}
+	large := *entry
+	if w.aggregates {
+		entry.Size = 0
+	}
 
-	if err := w.store.Put(entry); err != nil {
-		log := logging.Get("watcher")
-		log.Debug("failed to store entry on create", "path", path, "error", err)
+	if !w.aggregates || info.IsDir() {
+		if err := w.store.Put(entry); err != nil {
+			log := logging.Get("watcher")
+			log.Debug("failed to store entry on create", "path", path, "error", err)
+		}
 	}
 
 	// Update large files index if this is a large file
 	if !info.IsDir() && w.minLargeFileSize > 0 && info.Size() >= w.minLargeFileSize {
-		if err := w.store.AddLargeFile(path, info.Size(), info.ModTime().Unix()); err != nil {
+		if err := w.store.PutLargeFile(&large); err != nil {
 			log := logging.Get("watcher")
 			log.Debug("failed to add large file on create", "path", path, "error", err)
 		}
@@ -241,6 +616,15 @@ func (w *Watcher) handleCreate(path string) {
 	}
 }
 
+// fileSharing returns how a file shares storage. As when indexing, clones are
+// only looked up for large files.
+func (w *Watcher) fileSharing(path string, info fs.FileInfo) *sharing.Info {
+	if w.minLargeFileSize > 0 && info.Size() >= w.minLargeFileSize {
+		return sharing.Stat(path, info)
+	}
+	return sharing.FromFileInfo(info)
+}
+
 // handleWrite handles file modification events.
 func (w *Watcher) handleWrite(path string) {
 	info, err := os.Stat(path)
@@ -255,16 +639,23 @@ func (w *Watcher) handleWrite(path string) {
 		ModTime: info.ModTime().Unix(),
 		IsDir:   info.IsDir(),
 	}
+	if !info.IsDir() {
+		entry.Shared = w.fileSharing(path, info)
+		entry.Cloud = cloud.FromFileInfo(path, info)
+	}
 
-	if err := w.store.Put(entry); err != nil {
-		log := logging.Get("watcher")
-		log.Debug("failed to store entry on write", "path", path, "error", err)
+	// Directory entries hold totals in aggregates mode, so leave them be
+	if !w.aggregates {
+		if err := w.store.Put(entry); err != nil {
+			log := logging.Get("watcher")
+			log.Debug("failed to store entry on write", "path", path, "error", err)
+		}
 	}
 
 	// Update large files index based on new size
 	if !info.IsDir() && w.minLargeFileSize > 0 {
 		if info.Size() >= w.minLargeFileSize {
-			if err := w.store.AddLargeFile(path, info.Size(), info.ModTime().Unix()); err != nil {
+			if err := w.store.PutLargeFile(entry); err != nil {
 				log := logging.Get("watcher")
 				log.Debug("failed to add large file on write", "path", path, "error", err)
 			}
@@ -284,15 +675,13 @@ func (w *Watcher) handleWrite(path string) {
 }
 
 // handleRename handles file/directory rename events (old path).
+// A renamed directory waits for its new name to be created, so that it
+// can be moved in the store and announced as one rename.
 func (w *Watcher) handleRename(path string) {
-	// Notify broadcaster with renamed event (size 0 for the old path)
-	if w.broadcaster != nil {
-		w.broadcaster.Notify(path, broadcaster.EventRenamed, 0)
-	}
-
 	// Remove watch if it was a directory (same cleanup as delete)
 	w.mu.Lock()
-	if w.paths[path] {
+	isDir := w.paths[path]
+	if isDir {
 		_ = w.watcher.Remove(path)
 		delete(w.paths, path)
 	}
@@ -304,7 +693,122 @@ func (w *Watcher) handleRename(path string) {
 			delete(w.paths, childPath)
 		}
 	}
+
+	// Only one rename waits at a time; an earlier one was moved away
+	var expired string
+	if isDir {
+		if w.renamed != nil {
+			w.renamed.timer.Stop()
+			expired = w.renamed.path
+		}
+		r := &renamedDir{path: path}
+		r.timer = time.AfterFunc(renameWindow, func() { w.expireRenamed(r) })
+		w.renamed = r
+	}
+	w.mu.Unlock()
+
+	if expired != "" {
+		w.handleRemove(expired)
+	}
+	if isDir {
+		return
+	}
+
+	// Notify broadcaster with renamed event (size 0 for the old path)
+	if w.broadcaster != nil {
+		w.broadcaster.Notify(path, broadcaster.EventRenamed, 0)
+	}
+}
+
+// takeRenamed returns the renamed directory waiting for its new name, if
+// any, and stops waiting.
+func (w *Watcher) takeRenamed() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.renamed == nil {
+		return "", false
+	}
+	w.renamed.timer.Stop()
+	path := w.renamed.path
+	w.renamed = nil
+	return path, true
+}
+
+// expireRenamed removes a renamed directory whose new name never appeared,
+// unless it was taken while the timer fired.
+func (w *Watcher) expireRenamed(r *renamedDir) {
+	w.mu.Lock()
+	expired := w.renamed == r
+	if expired {
+		w.renamed = nil
+	}
 	w.mu.Unlock()
+
+	if expired {
+		w.handleRemove(r.path)
+	}
+}
+
+// moveDir moves a directory renamed from oldPath to newPath in the store
+// and watches it under its new name. The move is only made if entries
+// stored under oldPath are found under newPath, so an unrelated directory
+// created just after a rename isn't mistaken for it. It returns false if
+// the directory wasn't moved.
+func (w *Watcher) moveDir(oldPath, newPath string) bool {
+	if !w.sameDir(oldPath, newPath) {
+		return false
+	}
+	moved, err := w.store.Move(oldPath, newPath)
+	if err != nil {
+		logging.Get("watcher").Debug("failed to move renamed directory", "from", oldPath, "to", newPath, "error", err)
+		return false
+	}
+
+	_ = filepath.WalkDir(newPath, func(subpath string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return nil //nolint:nilerr // Skip entries with errors
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil // Skip symlinks
+		}
+		if d.IsDir() {
+			if w.skipsSnapshot(subpath) {
+				return filepath.SkipDir
+			}
+			_ = w.addWatch(subpath)
+		}
+		return nil
+	})
+
+	logging.Get("watcher").Debug("moved renamed directory", "from", oldPath, "to", newPath, "entries", moved)
+	if w.broadcaster != nil {
+		w.broadcaster.NotifyRenamed(oldPath, newPath)
+	}
+	return true
+}
+
+// errSampled stops a store walk once enough entries have been seen.
+var errSampled = errors.New("sampled")
+
+// sameDir reports whether the directory at newPath is the one stored at
+// oldPath, by checking that the first entries stored beneath oldPath exist
+// under newPath. A directory with nothing stored beneath it matches.
+func (w *Watcher) sameDir(oldPath, newPath string) bool {
+	same, checked := true, 0
+	err := w.store.Walk(oldPath, func(e *store.Entry) error {
+		if e.Path == oldPath {
+			return nil
+		}
+		if _, err := os.Lstat(newPath + e.Path[len(oldPath):]); err != nil {
+			same = false
+			return errSampled
+		}
+		if checked++; checked == renameSample {
+			return errSampled
+		}
+		return nil
+	})
+	return same && (err == nil || errors.Is(err, errSampled))
 }
 
 // handleRemove handles file/directory deletion events.
@@ -348,6 +852,11 @@ func (w *Watcher) Close() error {
 
 	w.closed = true
 	w.paths = make(map[string]bool)
+	w.paused = make(map[string]*pausedRoot)
+	if w.renamed != nil {
+		w.renamed.timer.Stop()
+		w.renamed = nil
+	}
 	return w.watcher.Close()
 }
 
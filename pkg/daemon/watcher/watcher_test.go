@@ -3,6 +3,8 @@ package watcher
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -10,7 +12,9 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/jamesainslie/sweep/pkg/daemon/broadcaster"
 	"github.com/jamesainslie/sweep/pkg/daemon/store"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
 )
 
 // setupTestStore creates a temporary store for testing.
@@ -468,6 +472,127 @@ func TestWatchIgnoresSymlinks(t *testing.T) {
 	}
 }
 
+func TestWatchSkipsSnapshots(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	tmpDir := t.TempDir()
+	snapshot := filepath.Join(tmpDir, ".zfs", "snapshot", "daily")
+	if err := os.MkdirAll(snapshot, 0o755); err != nil {
+		t.Fatalf("failed to create snapshot dir: %v", err)
+	}
+
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	_, tracked := w.paths[snapshot]
+	w.mu.RUnlock()
+	if tracked {
+		t.Error("Watch() tracked a snapshot directory")
+	}
+
+	w.SetIncludeSnapshots(true)
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	_, tracked = w.paths[snapshot]
+	w.mu.RUnlock()
+	if !tracked {
+		t.Error("Watch() did not track a snapshot directory with snapshots included")
+	}
+}
+
+func TestWatchSkipsMounts(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	tmpDir := t.TempDir()
+	mount, kept := filepath.Join(tmpDir, "nas"), filepath.Join(tmpDir, "kept")
+	for _, dir := range []string{filepath.Join(mount, "share"), kept} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	w.SetMountBoundary(true, []string{mount}, nil)
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if _, tracked := w.paths[mount]; tracked {
+		t.Error("Watch() tracked a skipped mount point")
+	}
+	if _, tracked := w.paths[filepath.Join(mount, "share")]; tracked {
+		t.Error("Watch() tracked a directory in a skipped mount point")
+	}
+	if _, tracked := w.paths[kept]; !tracked {
+		t.Error("Watch() did not track a directory on the root's file system")
+	}
+}
+
+func TestWatchSkipsNetwork(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	tmpDir := t.TempDir()
+	share := filepath.Join(tmpDir, "share")
+	if err := os.MkdirAll(filepath.Join(share, "docs"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	w.SetNetwork(mounts.Network{Paths: []string{share}}, false)
+	if err := w.Watch(share); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	watched := len(w.paths)
+	w.mu.RUnlock()
+	if watched != 0 {
+		t.Errorf("Watch() watched %d directories on a network file system", watched)
+	}
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	_, tracked := w.paths[share]
+	w.mu.RUnlock()
+	if tracked {
+		t.Error("Watch() tracked a network mount under the root")
+	}
+
+	w.SetNetwork(mounts.Network{Paths: []string{share}}, true)
+	if err := w.Watch(share); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	w.mu.RLock()
+	_, tracked = w.paths[filepath.Join(share, "docs")]
+	w.mu.RUnlock()
+	if !tracked {
+		t.Error("Watch() did not track a network file system with watching it allowed")
+	}
+}
+
 func TestClose(t *testing.T) {
 	s, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -551,3 +676,287 @@ func TestNewDirectoryWatchAdded(t *testing.T) {
 		t.Error("Run() did not add watch for newly created directory")
 	}
 }
+
+func TestRenamedDirectoryMoves(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+	b := broadcaster.New()
+	defer b.Close()
+	w.SetBroadcaster(b)
+	w.SetMinLargeFileSize(10)
+
+	tmpDir := t.TempDir()
+	oldDir := filepath.Join(tmpDir, "photos")
+	if err := os.MkdirAll(filepath.Join(oldDir, "2024"), 0o755); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+	bigFile := filepath.Join(oldDir, "2024", "big.raw")
+	if err := os.WriteFile(bigFile, make([]byte, 100), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	for _, e := range []*store.Entry{
+		{Path: oldDir, IsDir: true},
+		{Path: filepath.Join(oldDir, "2024"), IsDir: true},
+		{Path: bigFile, Size: 100},
+	} {
+		if err := s.Put(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddLargeFile(bigFile, 100, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	sub := b.Subscribe(tmpDir, 0, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go w.Run(ctx, nil)
+	time.Sleep(100 * time.Millisecond)
+
+	newDir := filepath.Join(tmpDir, "pictures")
+	if err := os.Rename(oldDir, newDir); err != nil {
+		t.Fatalf("failed to rename dir: %v", err)
+	}
+
+	select {
+	case event := <-sub.Events:
+		if event.Type != broadcaster.EventRenamed || event.Path != oldDir || event.NewPath != newDir {
+			t.Errorf("got event %+v, want one rename of %s to %s", event, oldDir, newDir)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no rename event")
+	}
+
+	movedFile := filepath.Join(newDir, "2024", "big.raw")
+	if e, err := s.Get(movedFile); err != nil || e.Path != movedFile {
+		t.Errorf("Get(%s) = %+v, %v; want the moved entry", movedFile, e, err)
+	}
+	if _, err := s.Get(bigFile); err == nil {
+		t.Error("entry still stored under the old name")
+	}
+	large, err := s.GetLargeFiles(context.Background(), tmpDir, 0, 0)
+	if err != nil || len(large) != 1 || large[0].Path != movedFile {
+		t.Errorf("large files = %+v, %v; want only %s", large, err, movedFile)
+	}
+
+	w.mu.RLock()
+	watched := w.paths[filepath.Join(newDir, "2024")]
+	w.mu.RUnlock()
+	if !watched {
+		t.Error("subdirectory not watched under its new name")
+	}
+}
+
+func TestRenamedDirectoryMovedAway(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	watched, elsewhere := t.TempDir(), t.TempDir()
+	oldDir := filepath.Join(watched, "build")
+	if err := os.MkdirAll(oldDir, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := s.Put(&store.Entry{Path: oldDir, IsDir: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Watch(watched); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go w.Run(ctx, nil)
+	time.Sleep(100 * time.Millisecond)
+
+	if err := os.Rename(oldDir, filepath.Join(elsewhere, "build")); err != nil {
+		t.Fatalf("failed to move dir: %v", err)
+	}
+
+	// Removed once the new name fails to appear
+	deadline := time.Now().Add(renameWindow + 2*time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := s.Get(oldDir); err != nil {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("directory moved out of the watched paths is still stored")
+}
+
+func TestPauseHoldsChangesUntilResume(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	tmpDir := t.TempDir()
+	kept := filepath.Join(tmpDir, "kept.bin")
+	gone := filepath.Join(tmpDir, "gone.bin")
+	for _, path := range []string{kept, gone} {
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		info, _ := os.Stat(path)
+		if err := s.Put(&store.Entry{Path: path, Size: info.Size(), ModTime: info.ModTime().Unix()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go w.Run(ctx, nil)
+	time.Sleep(100 * time.Millisecond)
+
+	if !w.Pause(tmpDir) {
+		t.Fatal("Pause() = false, want true")
+	}
+	if w.Pause(tmpDir) {
+		t.Error("Pause() of a paused root = true, want false")
+	}
+
+	// A build writes, adds a new tree, and removes a file
+	added := filepath.Join(tmpDir, "out", "lib", "added.o")
+	if err := os.MkdirAll(filepath.Dir(added), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(added, []byte("object"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kept, []byte("rewritten"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	if _, err := s.Get(added); err == nil {
+		t.Error("a change was applied while paused")
+	}
+
+	dirs, err := w.Resume(tmpDir)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if dirs == 0 {
+		t.Error("Resume() reconciled no directories")
+	}
+	if w.Paused(tmpDir) {
+		t.Error("Paused() = true after Resume()")
+	}
+	if _, err := s.Get(added); err != nil {
+		t.Errorf("file in a new directory not stored on resume: %v", err)
+	}
+	if e, err := s.Get(kept); err != nil || e.Size != int64(len("rewritten")) {
+		t.Errorf("rewritten file = %+v, %v; want its new size", e, err)
+	}
+	if _, err := s.Get(gone); err == nil {
+		t.Error("removed file still stored after resume")
+	}
+
+	// The new directory is watched again
+	w.mu.RLock()
+	tracked := w.paths[filepath.Dir(added)]
+	w.mu.RUnlock()
+	if !tracked {
+		t.Error("new directory not watched after resume")
+	}
+
+	if _, err := w.Resume(tmpDir); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("Resume() of a running root error = %v, want ErrNotPaused", err)
+	}
+}
+
+func TestPauseTooManyChanges(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	root := t.TempDir()
+	w.Pause(root)
+	for i := range maxPausedDirs + 1 {
+		if !w.holdPaused(filepath.Join(root, fmt.Sprintf("d%d", i), "file")) {
+			t.Fatal("holdPaused() = false for a path under a paused root")
+		}
+	}
+	if w.holdPaused(filepath.Join(t.TempDir(), "file")) {
+		t.Error("holdPaused() = true for a path outside the paused root")
+	}
+	if _, err := w.Resume(root); !errors.Is(err, ErrTooManyChanges) {
+		t.Errorf("Resume() error = %v, want ErrTooManyChanges", err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	w, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Watch(tmpDir); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if st := w.Stats(); st.Dirs != 2 || st.Events != 0 || !st.LastEvent.IsZero() {
+		t.Errorf("Stats() before any event = %+v, want 2 dirs and no events", st)
+	}
+	w.Pause(tmpDir)
+	w.holdPaused(filepath.Join(tmpDir, "sub", "a.txt"))
+	w.holdPaused(filepath.Join(tmpDir, "b.txt"))
+	if st := w.Stats(); st.Held != 2 {
+		t.Errorf("Stats().Held = %d, want 2", st.Held)
+	}
+	if _, err := w.Resume(tmpDir); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go w.Run(ctx, nil)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for w.Stats().Events == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if st := w.Stats(); st.Events == 0 || st.LastEvent.IsZero() {
+		t.Errorf("Stats() after a write = %+v, want events counted", st)
+	}
+}
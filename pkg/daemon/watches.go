@@ -0,0 +1,202 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+	"github.com/jamesainslie/sweep/pkg/daemon/watcher"
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+// AddWatch saves a directory to watch and indexes it; the directory is
+// watched once its index completes, and indexed and watched again each time
+// the daemon starts.
+func (s *Service) AddWatch(ctx context.Context, req *sweepv1.AddWatchRequest) (*sweepv1.AddWatchResponse, error) {
+	path := req.GetPath()
+	if !filepath.IsAbs(path) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: path must be absolute", path)
+	}
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if !info.IsDir() {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is not a directory", path)
+	}
+
+	if err := s.store.AddWatchedRoot(path); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save watch: %v", err)
+	}
+	logging.Get("daemon").Info("watch added", "path", path)
+
+	s.indexMu.RLock()
+	state, exists := s.indexStates[path]
+	s.indexMu.RUnlock()
+	if exists && state.state == sweepv1.IndexState_INDEX_STATE_READY {
+		return &sweepv1.AddWatchResponse{Started: false, Message: "already watched"}, nil
+	}
+
+	resp, err := s.TriggerIndex(ctx, &sweepv1.TriggerIndexRequest{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	return &sweepv1.AddWatchResponse{Started: resp.GetStarted(), Message: resp.GetMessage()}, nil
+}
+
+// RemoveWatch stops watching a directory and forgets it, so it isn't
+// watched again after a restart. Its index is kept, as of the last change
+// seen, unless clear is set.
+func (s *Service) RemoveWatch(ctx context.Context, req *sweepv1.RemoveWatchRequest) (*sweepv1.RemoveWatchResponse, error) {
+	path := filepath.Clean(req.GetPath())
+
+	s.indexMu.RLock()
+	state, exists := s.indexStates[path]
+	s.indexMu.RUnlock()
+	if exists && state.state == sweepv1.IndexState_INDEX_STATE_INDEXING {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is being indexed; try again when it finishes", path)
+	}
+
+	saved, err := s.store.RemoveWatchedRoot(path)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove watch: %v", err)
+	}
+	if !saved && !exists {
+		return nil, status.Errorf(codes.NotFound, "%s is not watched", path)
+	}
+	logging.Get("daemon").Info("watch removed", "path", path, "clear", req.GetClear())
+	s.dismissSuggestion(path) // Not suggested or watched again automatically
+
+	if req.GetClear() {
+		resp, err := s.ClearCache(ctx, &sweepv1.ClearCacheRequest{Path: path})
+		if err != nil {
+			return nil, err
+		}
+		return &sweepv1.RemoveWatchResponse{EntriesCleared: resp.GetEntriesCleared()}, nil
+	}
+
+	s.stopWatching(path)
+	return &sweepv1.RemoveWatchResponse{}, nil
+}
+
+// stopWatching stops watching path and forgets its index state, keeping
+// its index. A path still waiting its turn at startup isn't indexed.
+func (s *Service) stopWatching(path string) {
+	if s.watcher != nil {
+		s.watcher.Unwatch(path)
+	}
+	s.indexMu.Lock()
+	delete(s.indexStates, path)
+	delete(s.queued, path)
+	s.indexMu.Unlock()
+}
+
+// unwatch stops watching a directory that isn't a saved watch, unless it
+// is being indexed.
+func (s *Service) unwatch(path string) error {
+	s.indexMu.RLock()
+	state, exists := s.indexStates[path]
+	s.indexMu.RUnlock()
+	if exists && state.state == sweepv1.IndexState_INDEX_STATE_INDEXING {
+		return fmt.Errorf("%s is being indexed; remove it again when it finishes", path)
+	}
+	s.stopWatching(path)
+	logging.Get("daemon").Info("stopped watching path removed from config", "path", path)
+	return nil
+}
+
+// ListWatches lists the directories indexed since the daemon started and
+// those saved with AddWatch, in path order.
+func (s *Service) ListWatches(_ context.Context, _ *sweepv1.ListWatchesRequest) (*sweepv1.ListWatchesResponse, error) {
+	saved, err := s.store.GetWatchedRoots()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list watches: %v", err)
+	}
+
+	roots := make(map[string]*sweepv1.WatchedRoot)
+	for _, path := range saved {
+		roots[path] = &sweepv1.WatchedRoot{
+			Path:  path,
+			State: sweepv1.IndexState_INDEX_STATE_NOT_INDEXED,
+			Saved: true,
+		}
+	}
+	s.indexMu.RLock()
+	for path, state := range s.indexStates {
+		root, ok := roots[path]
+		if !ok {
+			root = &sweepv1.WatchedRoot{Path: path}
+			roots[path] = root
+		}
+		root.State = state.state
+		root.FilesIndexed = state.files
+	}
+	s.indexMu.RUnlock()
+	if s.watcher != nil {
+		for path, root := range roots {
+			root.Paused = s.watcher.Paused(path)
+		}
+	}
+
+	resp := &sweepv1.ListWatchesResponse{}
+	for _, path := range slices.Sorted(maps.Keys(roots)) {
+		resp.Roots = append(resp.Roots, roots[path])
+	}
+	return resp, nil
+}
+
+// PauseWatch stops applying changes under a watched directory until
+// ResumeWatch, while remembering which directories change. Pauses last
+// until resumed or the daemon restarts.
+func (s *Service) PauseWatch(_ context.Context, req *sweepv1.PauseWatchRequest) (*sweepv1.PauseWatchResponse, error) {
+	path := filepath.Clean(req.GetPath())
+	if s.watcher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the daemon is not watching for changes")
+	}
+	s.indexMu.RLock()
+	_, exists := s.indexStates[path]
+	s.indexMu.RUnlock()
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "%s is not watched", path)
+	}
+
+	if !s.watcher.Pause(path) {
+		return &sweepv1.PauseWatchResponse{AlreadyPaused: true}, nil
+	}
+	logging.Get("daemon").Info("watch paused", "path", path)
+	return &sweepv1.PauseWatchResponse{}, nil
+}
+
+// ResumeWatch applies changes under a paused directory again. The
+// directories that changed while it was paused are reconciled with the
+// index; if too many changed, the directory is indexed again instead.
+func (s *Service) ResumeWatch(ctx context.Context, req *sweepv1.ResumeWatchRequest) (*sweepv1.ResumeWatchResponse, error) {
+	path := filepath.Clean(req.GetPath())
+	if s.watcher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the daemon is not watching for changes")
+	}
+
+	dirs, err := s.watcher.Resume(path)
+	switch {
+	case errors.Is(err, watcher.ErrNotPaused):
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is not paused", path)
+	case errors.Is(err, watcher.ErrTooManyChanges):
+		logging.Get("daemon").Info("too many changes while paused, indexing again", "path", path)
+		if _, err := s.TriggerIndex(ctx, &sweepv1.TriggerIndexRequest{Path: path, Force: true}); err != nil {
+			return nil, err
+		}
+		return &sweepv1.ResumeWatchResponse{Reindexing: true}, nil
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to resume watch: %v", err)
+	}
+	return &sweepv1.ResumeWatchResponse{DirsReconciled: int64(dirs)}, nil
+}
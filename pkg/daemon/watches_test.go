@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sweepv1 "github.com/jamesainslie/sweep/pkg/api/sweep/v1"
+)
+
+func TestWatchesSurviveRestart(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "big.iso"), make([]byte, 100), 0o644))
+	tmpDir := t.TempDir()
+	cfg := Config{
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		DataDir:    filepath.Join(tmpDir, "data"),
+	}
+	ctx := context.Background()
+	watched := func(srv *Server) bool {
+		resp, err := srv.service.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+		require.NoError(t, err)
+		return len(resp.GetRoots()) == 1 && resp.GetRoots()[0].GetState() == sweepv1.IndexState_INDEX_STATE_READY
+	}
+
+	srv, err := NewServer(cfg)
+	require.NoError(t, err)
+	resp, err := srv.service.AddWatch(ctx, &sweepv1.AddWatchRequest{Path: root})
+	require.NoError(t, err)
+	assert.True(t, resp.GetStarted())
+	require.Eventually(t, func() bool { return watched(srv) }, 5*time.Second, 20*time.Millisecond)
+
+	list, err := srv.service.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, root, list.GetRoots()[0].GetPath())
+	assert.True(t, list.GetRoots()[0].GetSaved())
+
+	again, err := srv.service.AddWatch(ctx, &sweepv1.AddWatchRequest{Path: root})
+	require.NoError(t, err)
+	assert.False(t, again.GetStarted(), "a watched directory isn't indexed again")
+	require.NoError(t, srv.Close())
+
+	// A restarted daemon watches the saved directory with no configured paths
+	srv, err = NewServer(cfg)
+	require.NoError(t, err)
+	defer srv.Close()
+	srv.IndexPaths(nil)
+	require.Eventually(t, func() bool { return watched(srv) }, 5*time.Second, 20*time.Millisecond)
+
+	_, err = srv.service.RemoveWatch(ctx, &sweepv1.RemoveWatchRequest{Path: root})
+	require.NoError(t, err)
+	list, err = srv.service.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, list.GetRoots())
+	assert.True(t, srv.store.HasIndex(root), "the index is kept without clear")
+
+	_, err = srv.service.RemoveWatch(ctx, &sweepv1.RemoveWatchRequest{Path: root})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAddWatchInvalidPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		DataDir:    filepath.Join(tmpDir, "data"),
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	file := filepath.Join(tmpDir, "file.txt")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	for _, path := range []string{"relative", filepath.Join(tmpDir, "missing"), file} {
+		_, err := srv.service.AddWatch(context.Background(), &sweepv1.AddWatchRequest{Path: path})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), path)
+	}
+	roots, err := srv.store.GetWatchedRoots()
+	require.NoError(t, err)
+	assert.Empty(t, roots)
+}
+
+func TestPauseAndResumeWatch(t *testing.T) {
+	root := t.TempDir()
+	tmpDir := t.TempDir()
+	srv, err := NewServer(Config{
+		SocketPath: filepath.Join(tmpDir, "test.sock"),
+		DataDir:    filepath.Join(tmpDir, "data"),
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err = srv.service.PauseWatch(ctx, &sweepv1.PauseWatchRequest{Path: root})
+	assert.Equal(t, codes.NotFound, status.Code(err), "an unwatched directory can't be paused")
+
+	_, err = srv.service.AddWatch(ctx, &sweepv1.AddWatchRequest{Path: root})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		list, err := srv.service.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+		require.NoError(t, err)
+		return list.GetRoots()[0].GetState() == sweepv1.IndexState_INDEX_STATE_READY
+	}, 5*time.Second, 20*time.Millisecond)
+
+	resp, err := srv.service.PauseWatch(ctx, &sweepv1.PauseWatchRequest{Path: root})
+	require.NoError(t, err)
+	assert.False(t, resp.GetAlreadyPaused())
+	list, err := srv.service.ListWatches(ctx, &sweepv1.ListWatchesRequest{})
+	require.NoError(t, err)
+	assert.True(t, list.GetRoots()[0].GetPaused())
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new.iso"), make([]byte, 100), 0o644))
+	time.Sleep(300 * time.Millisecond)
+	_, err = srv.store.Get(filepath.Join(root, "new.iso"))
+	assert.Error(t, err, "changes aren't applied while paused")
+
+	resumed, err := srv.service.ResumeWatch(ctx, &sweepv1.ResumeWatchRequest{Path: root})
+	require.NoError(t, err)
+	assert.False(t, resumed.GetReindexing())
+	_, err = srv.store.Get(filepath.Join(root, "new.iso"))
+	assert.NoError(t, err, "changes made while paused are caught up on resume")
+
+	_, err = srv.service.ResumeWatch(ctx, &sweepv1.ResumeWatchRequest{Path: root})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,148 @@
+// Package alert checks disk usage thresholds, such as a directory growing
+// past 50 GB or its volume filling past 90%, and notifies about the
+// thresholds crossed: with a desktop notification, a webhook, or a command.
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// ErrNoLimit is returned for a threshold with neither limit set.
+var ErrNoLimit = errors.New("threshold has neither max_size nor max_used")
+
+// Threshold is a limit on the disk usage of a path.
+type Threshold struct {
+	Name    string  // Display name; defaults to Path
+	Path    string  // Absolute path
+	MaxSize int64   // Most bytes under Path, as indexed (0 = unlimited)
+	MaxUsed float64 // Largest share of Path's volume in use, from 0 to 1 (0 = unlimited)
+}
+
+// DisplayName returns the threshold name, falling back to its path.
+func (t Threshold) DisplayName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Path
+}
+
+// Validate checks that the threshold has a path and a limit.
+func (t Threshold) Validate() error {
+	if t.Path == "" {
+		return errors.New("threshold path is empty")
+	}
+	if t.MaxSize <= 0 && t.MaxUsed <= 0 {
+		return ErrNoLimit
+	}
+	if t.MaxUsed > 1 {
+		return fmt.Errorf("max_used %.0f%% is over 100%%", t.MaxUsed*100)
+	}
+	return nil
+}
+
+// ParsePercent parses a share of a volume such as "90%" or "0.9".
+func ParsePercent(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	if percent || v > 1 {
+		v /= 100
+	}
+	return v, nil
+}
+
+// Kind is which limit of a threshold an alert is about.
+type Kind string
+
+// Kinds of alert.
+const (
+	KindSize   Kind = "size"   // The path grew past MaxSize
+	KindVolume Kind = "volume" // Its volume filled past MaxUsed
+)
+
+// Alert reports a threshold crossed.
+type Alert struct {
+	Threshold Threshold `json:"-"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	Kind      Kind      `json:"kind"`
+	Value     float64   `json:"value"` // Bytes for KindSize, share of the volume for KindVolume
+	Limit     float64   `json:"limit"`
+	Time      time.Time `json:"time"`
+}
+
+// newAlert returns an alert about kind of t's limits.
+func newAlert(t Threshold, kind Kind, value, limit float64, now time.Time) Alert {
+	return Alert{Threshold: t, Name: t.DisplayName(), Path: t.Path, Kind: kind, Value: value, Limit: limit, Time: now}
+}
+
+// Title is a short summary of the alert, for a notification's title.
+func (a Alert) Title() string {
+	if a.Kind == KindVolume {
+		return "Disk almost full"
+	}
+	return a.Name + " is over its limit"
+}
+
+// Message describes the alert in a sentence.
+func (a Alert) Message() string {
+	if a.Kind == KindVolume {
+		return fmt.Sprintf("The volume holding %s is %.0f%% full (limit %.0f%%)", a.Path, a.Value*100, a.Limit*100)
+	}
+	return fmt.Sprintf("%s holds %s (limit %s)", a.Path, types.FormatSize(int64(a.Value)), types.FormatSize(int64(a.Limit)))
+}
+
+// Usage is what a check measured for a threshold's path. Size is -1 when
+// the path isn't measured, and Used is -1 when its volume's usage is
+// unknown.
+type Usage struct {
+	Size int64
+	Used float64
+}
+
+// Monitor tracks which thresholds are crossed, so each alert fires once
+// when a threshold is crossed and again only after usage falls back below
+// it. It is not safe for concurrent use.
+type Monitor struct {
+	crossed map[string]bool // By threshold path and kind
+}
+
+// NewMonitor creates a monitor with no thresholds crossed.
+func NewMonitor() *Monitor {
+	return &Monitor{crossed: make(map[string]bool)}
+}
+
+// Check compares the usage measured for t with its limits and returns the
+// alerts newly crossed.
+func (m *Monitor) Check(t Threshold, u Usage, now time.Time) []Alert {
+	var alerts []Alert
+	if t.MaxSize > 0 && u.Size >= 0 {
+		if m.cross(t, KindSize, u.Size > t.MaxSize) {
+			alerts = append(alerts, newAlert(t, KindSize, float64(u.Size), float64(t.MaxSize), now))
+		}
+	}
+	if t.MaxUsed > 0 && u.Used >= 0 {
+		if m.cross(t, KindVolume, u.Used > t.MaxUsed) {
+			alerts = append(alerts, newAlert(t, KindVolume, u.Used, t.MaxUsed, now))
+		}
+	}
+	return alerts
+}
+
+// cross records whether t's kind limit is exceeded and reports whether it
+// just became so.
+func (m *Monitor) cross(t Threshold, kind Kind, over bool) bool {
+	key := t.Path + "\x00" + string(kind)
+	was := m.crossed[key]
+	m.crossed[key] = over
+	return over && !was
+}
@@ -0,0 +1,74 @@
+package alert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestParsePercent(t *testing.T) {
+	tests := map[string]float64{"90%": 0.9, "0.9": 0.9, "85": 0.85, " 50% ": 0.5}
+	for s, want := range tests {
+		if got, err := ParsePercent(s); err != nil || got != want {
+			t.Errorf("ParsePercent(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "ninety", "-5%"} {
+		if _, err := ParsePercent(s); err == nil {
+			t.Errorf("ParsePercent(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestThresholdValidate(t *testing.T) {
+	if err := (Threshold{Path: "/data"}).Validate(); !errors.Is(err, ErrNoLimit) {
+		t.Errorf("Validate() = %v, want ErrNoLimit", err)
+	}
+	if err := (Threshold{MaxSize: 1}).Validate(); err == nil {
+		t.Error("expected an error for a threshold without a path")
+	}
+	if err := (Threshold{Path: "/", MaxUsed: 1.5}).Validate(); err == nil {
+		t.Error("expected an error for max_used over 100%")
+	}
+	if err := (Threshold{Path: "/", MaxUsed: 0.9}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestMonitorFiresOncePerCrossing(t *testing.T) {
+	th := Threshold{Name: "downloads", Path: "/home/ann/Downloads", MaxSize: 50 * types.GiB, MaxUsed: 0.9}
+	m := NewMonitor()
+	now := time.Now()
+
+	if alerts := m.Check(th, Usage{Size: 10 * types.GiB, Used: 0.5}, now); len(alerts) != 0 {
+		t.Fatalf("expected no alerts under the limits, got %+v", alerts)
+	}
+	alerts := m.Check(th, Usage{Size: 60 * types.GiB, Used: 0.95}, now)
+	if len(alerts) != 2 || alerts[0].Kind != KindSize || alerts[1].Kind != KindVolume {
+		t.Fatalf("expected a size and a volume alert, got %+v", alerts)
+	}
+	if msg := alerts[0].Message(); !strings.Contains(msg, "60 GiB") || !strings.Contains(msg, "50 GiB") {
+		t.Errorf("Message() = %q", msg)
+	}
+	if msg := alerts[1].Message(); !strings.Contains(msg, "95% full") {
+		t.Errorf("Message() = %q", msg)
+	}
+
+	// Still over: nothing new
+	if alerts := m.Check(th, Usage{Size: 61 * types.GiB, Used: 0.96}, now); len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %+v", alerts)
+	}
+	// Unknown usage leaves the state alone
+	if alerts := m.Check(th, Usage{Size: -1, Used: -1}, now); len(alerts) != 0 {
+		t.Errorf("expected no alerts without measurements, got %+v", alerts)
+	}
+	// Back under, then over again
+	m.Check(th, Usage{Size: 40 * types.GiB, Used: 0.96}, now)
+	alerts = m.Check(th, Usage{Size: 55 * types.GiB, Used: 0.96}, now)
+	if len(alerts) != 1 || alerts[0].Kind != KindSize {
+		t.Errorf("expected the size alert to fire again, got %+v", alerts)
+	}
+}
@@ -0,0 +1,111 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/hook"
+)
+
+// Actions are how alerts are sent. Any combination may be set.
+type Actions struct {
+	Desktop bool   // Show a desktop notification
+	Webhook string // URL the alert is POSTed to as JSON
+	Exec    string // Shell command run with the alert in its environment and, as JSON, on its standard input
+}
+
+// Empty reports whether no action is set, in which case alerts are only
+// logged.
+func (a Actions) Empty() bool {
+	return !a.Desktop && a.Webhook == "" && a.Exec == ""
+}
+
+// Replaced in tests.
+var (
+	goos       = runtime.GOOS
+	runCommand = hook.Run
+)
+
+// Notify sends an alert with each of the actions, returning the errors of
+// those that failed, joined.
+func Notify(ctx context.Context, actions Actions, a Alert) error {
+	var errs []error
+	if actions.Desktop {
+		if err := desktop(ctx, a.Title(), a.Message()); err != nil {
+			errs = append(errs, fmt.Errorf("desktop notification: %w", err))
+		}
+	}
+	if actions.Webhook != "" {
+		if err := hook.Post(ctx, actions.Webhook, newPayload(a)); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if actions.Exec != "" {
+		if err := hook.Exec(ctx, actions.Exec, execEnv(a), newPayload(a)); err != nil {
+			errs = append(errs, fmt.Errorf("exec: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// desktop shows a notification with the platform's notification tool.
+func desktop(ctx context.Context, title, body string) error {
+	ctx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		// Passed as arguments, so nothing in them is read as AppleScript
+		cmd = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv", "-e", "display notification (item 2 of argv) with title (item 1 of argv)", "-e", "end run",
+			title, body)
+	case "windows":
+		// Passed in the environment, so nothing in them is read as PowerShell
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast)
+		cmd.Env = append(os.Environ(), "SWEEP_ALERT_TITLE="+title, "SWEEP_ALERT_MESSAGE="+body)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=sweep", title, body)
+	}
+	return runCommand(cmd)
+}
+
+// windowsToast shows a toast notification from the Windows Runtime.
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text[0].AppendChild($xml.CreateTextNode($env:SWEEP_ALERT_TITLE)) > $null
+$text[1].AppendChild($xml.CreateTextNode($env:SWEEP_ALERT_MESSAGE)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('sweep').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// payload is the JSON sent to webhooks and commands. Text makes it a
+// message Slack's incoming webhooks accept as is.
+type payload struct {
+	Alert
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Text    string `json:"text"`
+}
+
+// newPayload returns the JSON payload for a.
+func newPayload(a Alert) payload {
+	return payload{Alert: a, Title: a.Title(), Message: a.Message(), Text: a.Title() + ": " + a.Message()}
+}
+
+// execEnv returns the SWEEP_ALERT_* variables commands are run with.
+func execEnv(a Alert) []string {
+	return []string{
+		"SWEEP_ALERT_NAME=" + a.Name,
+		"SWEEP_ALERT_PATH=" + a.Path,
+		"SWEEP_ALERT_KIND=" + string(a.Kind),
+		"SWEEP_ALERT_VALUE=" + strconv.FormatFloat(a.Value, 'f', -1, 64),
+		"SWEEP_ALERT_LIMIT=" + strconv.FormatFloat(a.Limit, 'f', -1, 64),
+		"SWEEP_ALERT_TITLE=" + a.Title(),
+		"SWEEP_ALERT_MESSAGE=" + a.Message(),
+	}
+}
@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAlert() Alert {
+	th := Threshold{Name: "downloads", Path: "/home/ann/Downloads", MaxSize: 100}
+	return newAlert(th, KindSize, 150, 100, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	if err := Notify(context.Background(), Actions{Webhook: srv.URL}, testAlert()); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if got["name"] != "downloads" || got["kind"] != "size" || got["value"] != 150.0 || got["message"] == "" {
+		t.Errorf("webhook got %v", got)
+	}
+}
+
+func TestNotifyWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := Notify(context.Background(), Actions{Webhook: srv.URL}, testAlert())
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Notify() = %v, want the webhook's status", err)
+	}
+}
+
+func TestNotifyExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "alert.txt")
+	cmd := `echo "$SWEEP_ALERT_KIND $SWEEP_ALERT_PATH" > "$OUT"; cat >> "$OUT"`
+	t.Setenv("OUT", out)
+
+	if err := Notify(context.Background(), Actions{Exec: cmd}, testAlert()); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "size /home/ann/Downloads\n{") || !strings.Contains(string(data), `"limit":100`) {
+		t.Errorf("the command got:\n%s", data)
+	}
+
+	if err := Notify(context.Background(), Actions{Exec: "echo oops >&2; exit 3"}, testAlert()); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("Notify() = %v, want the command's output", err)
+	}
+}
+
+func TestNotifyDesktop(t *testing.T) {
+	defer func(g string, r func(*exec.Cmd) error) { goos, runCommand = g, r }(goos, runCommand)
+	var args []string
+	runCommand = func(cmd *exec.Cmd) error {
+		args = cmd.Args
+		return nil
+	}
+
+	for _, tc := range []struct{ goos, tool string }{{"darwin", "osascript"}, {"linux", "notify-send"}, {"windows", "powershell"}} {
+		goos = tc.goos
+		if err := Notify(context.Background(), Actions{Desktop: true}, testAlert()); err != nil {
+			t.Fatalf("%s: Notify() failed: %v", tc.goos, err)
+		}
+		if len(args) == 0 || args[0] != tc.tool {
+			t.Errorf("%s: ran %v, want %s", tc.goos, args, tc.tool)
+		}
+	}
+	// The title and message are arguments, never part of a script
+	goos = "darwin"
+	_ = Notify(context.Background(), Actions{Desktop: true}, testAlert())
+	if args[len(args)-2] != "downloads is over its limit" {
+		t.Errorf("osascript args = %q", args)
+	}
+}
@@ -0,0 +1,148 @@
+// Package archive lists the members of zip and tar archives, largest first,
+// so a large archive can be slimmed by re-creating it without its biggest
+// members rather than deleted whole.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrUnsupported is returned by List for files that aren't a supported
+// archive.
+var ErrUnsupported = errors.New("not a supported archive")
+
+// Formats of the archives List reads.
+const (
+	FormatZip   = "zip"
+	FormatTar   = "tar"
+	FormatTarGz = "tar.gz"
+)
+
+// Entry is a file inside an archive.
+type Entry struct {
+	Name       string
+	Size       int64 // Uncompressed
+	Compressed int64 // Stored size, 0 when the format doesn't record it
+}
+
+// Listing is the contents of an archive.
+type Listing struct {
+	Path    string
+	Format  string
+	Entries []Entry // Largest first
+	Size    int64   // Uncompressed size of all entries
+}
+
+// Format returns the format of the archive at path, judged by its name, or
+// "" if it isn't one List reads.
+func Format(path string) string {
+	name := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return FormatZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGz
+	case strings.HasSuffix(name, ".tar"):
+		return FormatTar
+	}
+	return ""
+}
+
+// List reads the entries of the archive at path. Directories are left out.
+// Reading a compressed tar decompresses all of it, so List stops early with
+// ctx's error when ctx is done.
+func List(ctx context.Context, path string) (*Listing, error) {
+	listing := &Listing{Path: path, Format: Format(path)}
+	var err error
+	switch listing.Format {
+	case FormatZip:
+		listing.Entries, err = listZip(path)
+	case FormatTar, FormatTarGz:
+		listing.Entries, err = listTar(ctx, path, listing.Format == FormatTarGz)
+	default:
+		return nil, fmt.Errorf("%s: %w", path, ErrUnsupported)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(listing.Entries, func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Name, b.Name))
+	})
+	for _, e := range listing.Entries {
+		listing.Size += e.Size
+	}
+	return listing, nil
+}
+
+// listZip reads the entries from a zip's central directory, without
+// decompressing anything.
+func listZip(path string) ([]Entry, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading zip %s: %w", path, err)
+	}
+	defer r.Close()
+
+	var entries []Entry
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		entries = append(entries, Entry{
+			Name:       f.Name,
+			Size:       int64(f.UncompressedSize64),
+			Compressed: int64(f.CompressedSize64),
+		})
+	}
+	return entries, nil
+}
+
+// listTar reads the entries from a tar's headers, gunzipping it first if
+// gzipped.
+func listTar(ctx context.Context, path string, gzipped bool) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var src io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading gzip %s: %w", path, err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	var entries []Entry
+	tr := tar.NewReader(src)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar %s: %w", path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		entries = append(entries, Entry{Name: hdr.Name, Size: hdr.Size})
+	}
+}
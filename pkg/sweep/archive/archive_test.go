@@ -0,0 +1,149 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// members are written to each test archive, smallest first.
+var members = []struct {
+	name string
+	size int
+}{
+	{"docs/readme.txt", 10},
+	{"data/small.bin", 1000},
+	{"data/big.bin", 50000},
+}
+
+func writeZip(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	if _, err := w.Create("docs/"); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range members {
+		fw, err := w.Create(m.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(bytes.Repeat([]byte("a"), m.size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTar(t *testing.T, path string, gzipped bool) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var dst io.Writer = f
+	if gzipped {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		dst = gz
+	}
+	w := tar.NewWriter(dst)
+	if err := w.WriteHeader(&tar.Header{Name: "docs/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range members {
+		hdr := &tar.Header{Name: m.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(m.size)}
+		if err := w.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(bytes.Repeat([]byte("a"), m.size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"/data/backup.zip":     FormatZip,
+		"/data/Backup.ZIP":     FormatZip,
+		"/data/site.tar":       FormatTar,
+		"/data/site.tar.gz":    FormatTarGz,
+		"/data/site.tgz":       FormatTarGz,
+		"/data/site.tar.bz2":   "",
+		"/data/movie.mkv":      "",
+		"/data/archive.gz.txt": "",
+	}
+	for path, want := range tests {
+		if got := Format(path); got != want {
+			t.Errorf("Format(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.zip", "a.tar", "a.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			switch Format(name) {
+			case FormatZip:
+				writeZip(t, path)
+			default:
+				writeTar(t, path, Format(name) == FormatTarGz)
+			}
+
+			listing, err := List(context.Background(), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(listing.Entries) != len(members) {
+				t.Fatalf("got %d entries, want %d without directories: %+v", len(listing.Entries), len(members), listing.Entries)
+			}
+			for i, want := range []string{"data/big.bin", "data/small.bin", "docs/readme.txt"} {
+				if listing.Entries[i].Name != want {
+					t.Errorf("entry %d = %q, want %q", i, listing.Entries[i].Name, want)
+				}
+			}
+			if listing.Size != 51010 {
+				t.Errorf("Size = %d, want 51010", listing.Size)
+			}
+			big := listing.Entries[0]
+			if zipped := listing.Format == FormatZip; zipped != (big.Compressed > 0) {
+				t.Errorf("Compressed = %d, only zips record it", big.Compressed)
+			}
+		})
+	}
+}
+
+func TestListUnsupported(t *testing.T) {
+	if _, err := List(context.Background(), "/data/movie.mkv"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("List() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestListCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.tar.gz")
+	if err := os.WriteFile(path, []byte("not gzip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := List(context.Background(), path); err == nil {
+		t.Error("expected an error for a corrupt archive")
+	}
+}
@@ -0,0 +1,302 @@
+// Package backup looks up whether files are present in backups made by
+// restic, borg, or Time Machine, so a large file can be shown as "backed up
+// on <date>" before it is deleted.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// Kind is the backup tool that made a repository.
+type Kind string
+
+// Supported backup tools.
+const (
+	KindRestic      Kind = "restic"
+	KindBorg        Kind = "borg"
+	KindTimeMachine Kind = "timemachine"
+)
+
+// ErrUnknownKind is returned for an unsupported backup type.
+var ErrUnknownKind = errors.New("unknown backup type")
+
+// Repo is a backup repository files are looked up in.
+type Repo struct {
+	Name       string
+	Kind       Kind
+	Repository string   // Repository location; empty for Time Machine
+	Paths      []string // Only files under these paths are looked up; empty means all
+	Binary     string   // Backup tool to run
+	Env        []string // Extra environment for the tool, e.g. password settings
+}
+
+// Covers reports whether path is under one of the repository's paths.
+func (r Repo) Covers(path string) bool {
+	if len(r.Paths) == 0 {
+		return true
+	}
+	for _, p := range r.Paths {
+		if path == p || strings.HasPrefix(path, strings.TrimSuffix(p, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromConfig validates the configured repositories.
+func FromConfig(configured []config.BackupConfig) ([]Repo, error) {
+	repos := make([]Repo, 0, len(configured))
+	seen := make(map[string]bool)
+	for i, bc := range configured {
+		r := Repo{Name: bc.Name, Kind: Kind(strings.ToLower(bc.Type)), Repository: bc.Repository, Binary: bc.Binary}
+		if r.Name == "" {
+			r.Name = string(r.Kind)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("backup %q is defined twice", r.Name)
+		}
+		seen[r.Name] = true
+
+		switch r.Kind {
+		case KindRestic, KindBorg:
+			if r.Repository == "" {
+				return nil, fmt.Errorf("backup %q: repository is required", r.Name)
+			}
+		case KindTimeMachine:
+			if r.Binary == "" {
+				r.Binary = "tmutil"
+			}
+		default:
+			return nil, fmt.Errorf("backup %d: %w %q (use restic, borg, or timemachine)", i+1, ErrUnknownKind, bc.Type)
+		}
+		if r.Binary == "" {
+			r.Binary = string(r.Kind)
+		}
+
+		for _, p := range bc.Paths {
+			path, err := config.ExpandPath(p)
+			if err != nil {
+				return nil, err
+			}
+			if path, err = filepath.Abs(path); err != nil {
+				return nil, err
+			}
+			r.Paths = append(r.Paths, path)
+		}
+
+		if bc.PasswordFile != "" {
+			path, err := config.ExpandPath(bc.PasswordFile)
+			if err != nil {
+				return nil, err
+			}
+			switch r.Kind {
+			case KindRestic:
+				r.Env = append(r.Env, "RESTIC_PASSWORD_FILE="+path)
+			case KindBorg:
+				// Borg has no password file setting, so read it through a command
+				r.Env = append(r.Env, "BORG_PASSCOMMAND=cat "+shellQuote(path))
+			}
+		}
+		if bc.PasswordCommand != "" {
+			switch r.Kind {
+			case KindRestic:
+				r.Env = append(r.Env, "RESTIC_PASSWORD_COMMAND="+bc.PasswordCommand)
+			case KindBorg:
+				r.Env = append(r.Env, "BORG_PASSCOMMAND="+bc.PasswordCommand)
+			}
+		}
+		repos = append(repos, r)
+	}
+	return repos, nil
+}
+
+// Hint describes the copy of a file in one repository.
+type Hint struct {
+	Repo    string
+	Found   bool      // The file is in the latest backup
+	Date    time.Time // When the latest backup was made
+	Changed bool      // The backed-up copy differs in size or modification time
+	Err     error     // The lookup failed
+}
+
+// String returns a short description for the detail panel.
+func (h Hint) String() string {
+	switch {
+	case h.Err != nil:
+		return h.Repo + ": lookup failed"
+	case !h.Found:
+		return h.Repo + ": not in latest backup"
+	case h.Changed:
+		return fmt.Sprintf("%s: older version from %s", h.Repo, h.Date.Format("2006-01-02"))
+	default:
+		return fmt.Sprintf("%s: backed up %s", h.Repo, h.Date.Format("2006-01-02"))
+	}
+}
+
+// entry is a file as recorded in a backup.
+type entry struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshot is the latest backup of a repository.
+type snapshot struct {
+	id   string // Archive name or backup directory
+	date time.Time
+}
+
+// runFunc runs a backup tool and returns its standard output.
+type runFunc func(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
+
+// run executes a backup tool; tests replace it.
+var run runFunc = func(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// Checker looks up files in a set of repositories. Results are cached per
+// file version, and each repository's latest backup is resolved once.
+type Checker struct {
+	repos []Repo
+
+	mu        sync.Mutex
+	hints     map[string][]Hint   // Keyed by path, size, and modification time
+	snapshots map[string]snapshot // Latest backup by repository name
+}
+
+// NewChecker creates a checker for repos.
+func NewChecker(repos []Repo) *Checker {
+	return &Checker{
+		repos:     repos,
+		hints:     make(map[string][]Hint),
+		snapshots: make(map[string]snapshot),
+	}
+}
+
+// Cached returns the hints of a previous Check of the same file version.
+func (c *Checker) Cached(path string, size int64, modTime time.Time) ([]Hint, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hints, ok := c.hints[cacheKey(path, size, modTime)]
+	return hints, ok
+}
+
+// Check looks up path in every repository that covers it. Failed lookups
+// are reported in the hint rather than as an error, so one unreachable
+// repository doesn't hide the others.
+func (c *Checker) Check(ctx context.Context, path string, size int64, modTime time.Time) []Hint {
+	if hints, ok := c.Cached(path, size, modTime); ok {
+		return hints
+	}
+
+	var hints []Hint
+	for _, r := range c.repos {
+		if !r.Covers(path) {
+			continue
+		}
+		h := Hint{Repo: r.Name}
+		e, snap, found, err := c.lookup(ctx, r, path)
+		switch {
+		case err != nil:
+			h.Err = err
+		case found:
+			h.Found = true
+			h.Date = snap.date
+			h.Changed = e.size != size || !sameTime(e.modTime, modTime)
+		default:
+			h.Date = snap.date
+		}
+		hints = append(hints, h)
+	}
+
+	// Don't cache failures caused by the caller giving up
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		c.hints[cacheKey(path, size, modTime)] = hints
+		c.mu.Unlock()
+	}
+	return hints
+}
+
+// lookup finds path in the latest backup of r.
+func (c *Checker) lookup(ctx context.Context, r Repo, path string) (entry, snapshot, bool, error) {
+	if r.Kind == KindRestic {
+		// restic resolves "latest" itself in the same call
+		return lookupRestic(ctx, r, path)
+	}
+
+	snap, err := c.latest(ctx, r)
+	if err != nil {
+		return entry{}, snapshot{}, false, err
+	}
+	var e entry
+	var found bool
+	switch r.Kind {
+	case KindBorg:
+		e, found, err = lookupBorg(ctx, r, snap, path)
+	case KindTimeMachine:
+		e, found, err = lookupTimeMachine(snap, path)
+	}
+	return e, snap, found, err
+}
+
+// latest returns the cached latest backup of a borg or Time Machine repository.
+func (c *Checker) latest(ctx context.Context, r Repo) (snapshot, error) {
+	c.mu.Lock()
+	snap, ok := c.snapshots[r.Name]
+	c.mu.Unlock()
+	if ok {
+		return snap, nil
+	}
+
+	var err error
+	switch r.Kind {
+	case KindBorg:
+		snap, err = latestBorg(ctx, r)
+	case KindTimeMachine:
+		snap, err = latestTimeMachine(ctx, r)
+	}
+	if err != nil {
+		return snapshot{}, err
+	}
+	c.mu.Lock()
+	c.snapshots[r.Name] = snap
+	c.mu.Unlock()
+	return snap, nil
+}
+
+func cacheKey(path string, size int64, modTime time.Time) string {
+	return fmt.Sprintf("%s\x00%d\x00%d", path, size, modTime.Unix())
+}
+
+// sameTime compares modification times at the one-second precision every
+// backup tool keeps.
+func sameTime(a, b time.Time) bool {
+	return a.Unix() == b.Unix()
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// fakeRun replaces the backup tool runner with one that answers by the
+// command line, recording every call.
+func fakeRun(t *testing.T, outputs map[string]string) *[]string {
+	t.Helper()
+	var calls []string
+	orig := run
+	run = func(_ context.Context, _ []string, name string, args ...string) ([]byte, error) {
+		line := name + " " + strings.Join(args, " ")
+		calls = append(calls, line)
+		out, ok := outputs[line]
+		if !ok {
+			return nil, errors.New("unexpected command: " + line)
+		}
+		return []byte(out), nil
+	}
+	t.Cleanup(func() { run = orig })
+	return &calls
+}
+
+func TestFromConfig(t *testing.T) {
+	repos, err := FromConfig([]config.BackupConfig{
+		{Type: "restic", Repository: "/srv/restic", PasswordFile: "/etc/pw", Paths: []string{"/home/me"}},
+		{Name: "offsite", Type: "Borg", Repository: "ssh://host/repo", PasswordCommand: "pass borg"},
+		{Type: "timemachine"},
+	})
+	require.NoError(t, err)
+	require.Len(t, repos, 3)
+
+	assert.Equal(t, "restic", repos[0].Name)
+	assert.Equal(t, "restic", repos[0].Binary)
+	assert.Equal(t, []string{"RESTIC_PASSWORD_FILE=/etc/pw"}, repos[0].Env)
+	assert.Equal(t, KindBorg, repos[1].Kind)
+	assert.Equal(t, []string{"BORG_PASSCOMMAND=pass borg"}, repos[1].Env)
+	assert.Equal(t, "tmutil", repos[2].Binary)
+
+	_, err = FromConfig([]config.BackupConfig{{Type: "duplicity", Repository: "x"}})
+	assert.ErrorIs(t, err, ErrUnknownKind)
+	_, err = FromConfig([]config.BackupConfig{{Type: "restic"}})
+	assert.ErrorContains(t, err, "repository is required")
+	_, err = FromConfig([]config.BackupConfig{{Type: "timemachine"}, {Type: "timemachine"}})
+	assert.ErrorContains(t, err, "defined twice")
+}
+
+func TestRepoCovers(t *testing.T) {
+	r := Repo{Paths: []string{"/home/me", "/srv/"}}
+	assert.True(t, r.Covers("/home/me/a.iso"))
+	assert.True(t, r.Covers("/srv/data/b.tar"))
+	assert.False(t, r.Covers("/home/mel/a.iso"))
+	assert.False(t, r.Covers("/tmp/a.iso"))
+	assert.True(t, Repo{}.Covers("/anything"))
+}
+
+func TestCheckRestic(t *testing.T) {
+	mod := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	calls := fakeRun(t, map[string]string{
+		"restic --repo /srv/restic --no-lock ls --json latest /home/me/movie.mkv": `{"time":"2025-03-10T08:00:00Z","id":"abc","struct_type":"snapshot"}
+{"name":"movie.mkv","type":"file","path":"/home/me/movie.mkv","size":4096,"mtime":"2025-03-01T12:00:00.123Z","struct_type":"node"}
+`,
+		"restic --repo /srv/restic --no-lock ls --json latest /home/me/new.iso": `{"time":"2025-03-10T08:00:00Z","id":"abc","struct_type":"snapshot"}
+`,
+	})
+	c := NewChecker([]Repo{{Name: "nas", Kind: KindRestic, Repository: "/srv/restic", Binary: "restic"}})
+
+	hints := c.Check(context.Background(), "/home/me/movie.mkv", 4096, mod)
+	require.Len(t, hints, 1)
+	assert.True(t, hints[0].Found)
+	assert.False(t, hints[0].Changed)
+	assert.Equal(t, time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC), hints[0].Date.UTC())
+	assert.Equal(t, "nas: backed up 2025-03-10", hints[0].String())
+
+	hints = c.Check(context.Background(), "/home/me/movie.mkv", 8192, mod)
+	assert.True(t, hints[0].Changed, "size differs from the backed-up copy")
+
+	hints = c.Check(context.Background(), "/home/me/new.iso", 1, mod)
+	assert.False(t, hints[0].Found)
+	assert.Equal(t, "nas: not in latest backup", hints[0].String())
+
+	// The same file version is answered from the cache
+	n := len(*calls)
+	c.Check(context.Background(), "/home/me/movie.mkv", 4096, mod)
+	assert.Len(t, *calls, n)
+	_, ok := c.Cached("/home/me/movie.mkv", 4096, mod)
+	assert.True(t, ok)
+}
+
+func TestCheckBorg(t *testing.T) {
+	mod := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local)
+	calls := fakeRun(t, map[string]string{
+		"borg list --json --last 1 /srv/borg": `{"archives":[{"name":"laptop-2025-01-05","start":"2025-01-05T10:00:00.000000"}]}`,
+		"borg list --json-lines /srv/borg::laptop-2025-01-05 -- home/me/a.tar": `{"type":"-","path":"home/me/a.tar","size":10,"mtime":"2025-01-02T03:04:05.000000"}
+`,
+		"borg list --json-lines /srv/borg::laptop-2025-01-05 -- home/me/b.tar": ``,
+	})
+	c := NewChecker([]Repo{{Name: "borg", Kind: KindBorg, Repository: "/srv/borg", Binary: "borg", Paths: []string{"/home/me"}}})
+
+	hints := c.Check(context.Background(), "/home/me/a.tar", 10, mod)
+	require.Len(t, hints, 1)
+	assert.True(t, hints[0].Found)
+	assert.False(t, hints[0].Changed)
+	assert.Equal(t, time.Date(2025, 1, 5, 10, 0, 0, 0, time.Local), hints[0].Date)
+
+	hints = c.Check(context.Background(), "/home/me/b.tar", 10, mod)
+	assert.False(t, hints[0].Found)
+
+	assert.Empty(t, c.Check(context.Background(), "/tmp/c.tar", 10, mod), "outside the backed-up paths")
+
+	listings := 0
+	for _, call := range *calls {
+		if strings.Contains(call, "--last 1") {
+			listings++
+		}
+	}
+	assert.Equal(t, 1, listings, "latest archive is resolved once")
+}
+
+func TestCheckTimeMachine(t *testing.T) {
+	backup := filepath.Join(t.TempDir(), "2025-02-03-040506.backup")
+	file := filepath.Join(backup, "Macintosh HD - Data", "Users", "me", "disk.img")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+	mod := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(file, mod, mod))
+
+	fakeRun(t, map[string]string{"tmutil latestbackup": backup + "\n"})
+	c := NewChecker([]Repo{{Name: "timemachine", Kind: KindTimeMachine, Binary: "tmutil"}})
+
+	hints := c.Check(context.Background(), "/Users/me/disk.img", 4, mod)
+	require.Len(t, hints, 1)
+	assert.True(t, hints[0].Found)
+	assert.False(t, hints[0].Changed)
+	assert.Equal(t, time.Date(2025, 2, 3, 4, 5, 6, 0, time.Local), hints[0].Date)
+
+	hints = c.Check(context.Background(), "/Users/me/other.img", 4, mod)
+	assert.False(t, hints[0].Found)
+}
+
+func TestCheckReportsErrors(t *testing.T) {
+	fakeRun(t, nil)
+	c := NewChecker([]Repo{
+		{Name: "broken", Kind: KindRestic, Repository: "/nowhere", Binary: "restic"},
+	})
+	hints := c.Check(context.Background(), "/a", 1, time.Now())
+	require.Len(t, hints, 1)
+	assert.Error(t, hints[0].Err)
+	assert.Equal(t, "broken: lookup failed", hints[0].String())
+}
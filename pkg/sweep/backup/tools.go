@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// resticLine is a line of 'restic ls --json': the snapshot first, then
+// one node per matching file.
+type resticLine struct {
+	StructType string    `json:"struct_type"`
+	ID         string    `json:"id"`
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	MTime      time.Time `json:"mtime"`
+}
+
+// lookupRestic lists path in the latest restic snapshot.
+func lookupRestic(ctx context.Context, r Repo, path string) (entry, snapshot, bool, error) {
+	out, err := run(ctx, r.Env, r.Binary, "--repo", r.Repository, "--no-lock", "ls", "--json", "latest", path)
+	if err != nil {
+		return entry{}, snapshot{}, false, err
+	}
+
+	var snap snapshot
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var line resticLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			return entry{}, snapshot{}, false, fmt.Errorf("failed to parse restic output: %w", err)
+		}
+		switch {
+		case line.StructType == "snapshot":
+			snap = snapshot{id: line.ID, date: line.Time}
+		case line.StructType == "node" && line.Type == "file" && line.Path == path:
+			return entry{size: line.Size, modTime: line.MTime}, snap, true, nil
+		}
+	}
+	return entry{}, snap, false, sc.Err()
+}
+
+// borgTimeLayout is how borg prints times: local time without a zone.
+const borgTimeLayout = "2006-01-02T15:04:05.999999"
+
+// latestBorg returns the most recent archive of a borg repository.
+func latestBorg(ctx context.Context, r Repo) (snapshot, error) {
+	out, err := run(ctx, r.Env, r.Binary, "list", "--json", "--last", "1", r.Repository)
+	if err != nil {
+		return snapshot{}, err
+	}
+	var list struct {
+		Archives []struct {
+			Name  string `json:"name"`
+			Start string `json:"start"`
+		} `json:"archives"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return snapshot{}, fmt.Errorf("failed to parse borg output: %w", err)
+	}
+	if len(list.Archives) == 0 {
+		return snapshot{}, errors.New("borg repository has no archives")
+	}
+	a := list.Archives[0]
+	date, err := time.ParseInLocation(borgTimeLayout, a.Start, time.Local)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed to parse borg archive time: %w", err)
+	}
+	return snapshot{id: a.Name, date: date}, nil
+}
+
+// lookupBorg lists path in a borg archive. Borg stores paths without the
+// leading slash.
+func lookupBorg(ctx context.Context, r Repo, snap snapshot, path string) (entry, bool, error) {
+	rel := strings.TrimPrefix(path, "/")
+	out, err := run(ctx, r.Env, r.Binary, "list", "--json-lines", r.Repository+"::"+snap.id, "--", rel)
+	if err != nil {
+		return entry{}, false, err
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var line struct {
+			Type  string `json:"type"`
+			Path  string `json:"path"`
+			Size  int64  `json:"size"`
+			MTime string `json:"mtime"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			return entry{}, false, fmt.Errorf("failed to parse borg output: %w", err)
+		}
+		if line.Type != "-" || line.Path != rel {
+			continue
+		}
+		mtime, err := time.ParseInLocation(borgTimeLayout, line.MTime, time.Local)
+		if err != nil {
+			return entry{}, false, fmt.Errorf("failed to parse borg file time: %w", err)
+		}
+		return entry{size: line.Size, modTime: mtime}, true, nil
+	}
+	return entry{}, false, sc.Err()
+}
+
+// timeMachineLayout is how Time Machine names backup directories.
+const timeMachineLayout = "2006-01-02-150405"
+
+// latestTimeMachine returns the directory of the latest Time Machine backup.
+func latestTimeMachine(ctx context.Context, r Repo) (snapshot, error) {
+	out, err := run(ctx, r.Env, r.Binary, "latestbackup")
+	if err != nil {
+		return snapshot{}, err
+	}
+	dir := strings.TrimSpace(string(out))
+	if dir == "" {
+		return snapshot{}, errors.New("no Time Machine backup found")
+	}
+	name := strings.TrimSuffix(filepath.Base(dir), ".backup")
+	date, err := time.ParseInLocation(timeMachineLayout, name, time.Local)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("failed to parse Time Machine backup date: %w", err)
+	}
+	return snapshot{id: dir, date: date}, nil
+}
+
+// lookupTimeMachine stats path inside the backup. Each backed-up volume is
+// a directory at the top of the backup, so path is tried under each.
+func lookupTimeMachine(snap snapshot, path string) (entry, bool, error) {
+	volumes, err := os.ReadDir(snap.id)
+	if err != nil {
+		return entry{}, false, fmt.Errorf("failed to read Time Machine backup: %w", err)
+	}
+	for _, v := range volumes {
+		if !v.IsDir() {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(snap.id, v.Name(), path))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		return entry{size: info.Size(), modTime: info.ModTime()}, true, nil
+	}
+	return entry{}, false, nil
+}
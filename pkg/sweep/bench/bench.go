@@ -0,0 +1,63 @@
+package bench
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Counts is what one benchmark run processed.
+type Counts struct {
+	Files int64
+	Dirs  int64
+}
+
+// Benchmark is a named operation over a directory tree.
+type Benchmark struct {
+	Name string
+	Run  func(ctx context.Context, root string) (Counts, error)
+}
+
+// Result summarizes the runs of one benchmark.
+type Result struct {
+	Name     string        `json:"name"`
+	Runs     int           `json:"runs"`
+	Median   time.Duration `json:"median_ns"`
+	Fastest  time.Duration `json:"fastest_ns"`
+	Files    int64         `json:"files"`
+	Dirs     int64         `json:"dirs"`
+	FilesSec float64       `json:"files_per_sec"`
+	DirsSec  float64       `json:"dirs_per_sec"`
+}
+
+// Measure runs b the given number of times and reports the median run.
+func Measure(ctx context.Context, b Benchmark, root string, runs int) (Result, error) {
+	runs = max(runs, 1)
+	durations := make([]time.Duration, 0, runs)
+	var counts Counts
+
+	for range runs {
+		start := time.Now()
+		c, err := b.Run(ctx, root)
+		if err != nil {
+			return Result{}, err
+		}
+		durations = append(durations, time.Since(start))
+		counts = c
+	}
+
+	slices.Sort(durations)
+	r := Result{
+		Name:    b.Name,
+		Runs:    runs,
+		Median:  durations[len(durations)/2],
+		Fastest: durations[0],
+		Files:   counts.Files,
+		Dirs:    counts.Dirs,
+	}
+	if secs := r.Median.Seconds(); secs > 0 {
+		r.FilesSec = float64(r.Files) / secs
+		r.DirsSec = float64(r.Dirs) / secs
+	}
+	return r, nil
+}
@@ -0,0 +1,139 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"5000", 5000},
+		{"100k", 100_000},
+		{"1M", 1_000_000},
+		{"1.5m", 1_500_000},
+	}
+	for _, tt := range tests {
+		got, err := ParseCount(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, bad := range []string{"", "lots", "-5", "1G"} {
+		_, err := ParseCount(bad)
+		assert.ErrorIs(t, err, ErrInvalidCount, bad)
+	}
+}
+
+func TestDirPath(t *testing.T) {
+	assert.Equal(t, "/r", dirPath("/r", 0, 10))
+	assert.Equal(t, "/r/d1", dirPath("/r", 1, 10))
+	assert.Equal(t, "/r/d1/d11", dirPath("/r", 11, 10))
+	assert.Equal(t, "/r/d2/d5", dirPath("/r", 5, 2))
+}
+
+func TestGenerate(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "tree")
+	spec := Spec{Files: 250, Dirs: 12, Fanout: 3, Distribution: "small", Seed: 7}
+
+	tree, err := Generate(context.Background(), root, spec)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), tree.Files)
+	assert.Equal(t, int64(12), tree.Dirs)
+
+	var files, dirs, bytes int64
+	err = filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		if d.IsDir() {
+			dirs++
+			return nil
+		}
+		info, err := d.Info()
+		require.NoError(t, err)
+		files++
+		bytes += info.Size()
+		assert.LessOrEqual(t, info.Size(), int64(64*1024))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tree.Files, files)
+	assert.Equal(t, tree.Dirs, dirs)
+	assert.Equal(t, tree.Bytes, bytes)
+
+	// The same seed gives the same sizes.
+	again, err := Generate(context.Background(), filepath.Join(t.TempDir(), "tree"), spec)
+	require.NoError(t, err)
+	assert.Equal(t, tree.Bytes, again.Bytes)
+}
+
+func TestGenerateUnknownDistribution(t *testing.T) {
+	_, err := Generate(context.Background(), t.TempDir(), Spec{Files: 1, Distribution: "huge"})
+	assert.ErrorIs(t, err, ErrUnknownDistribution)
+}
+
+func TestMeasure(t *testing.T) {
+	calls := 0
+	b := Benchmark{
+		Name: "fake",
+		Run: func(context.Context, string) (Counts, error) {
+			calls++
+			time.Sleep(time.Duration(calls) * time.Millisecond)
+			return Counts{Files: 1000, Dirs: 10}, nil
+		},
+	}
+
+	r, err := Measure(context.Background(), b, "/", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "fake", r.Name)
+	assert.LessOrEqual(t, r.Fastest, r.Median)
+	assert.Greater(t, r.FilesSec, r.DirsSec)
+
+	failing := Benchmark{Name: "bad", Run: func(context.Context, string) (Counts, error) {
+		return Counts{}, errors.New("boom")
+	}}
+	_, err = Measure(context.Background(), failing, "/", 3)
+	assert.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	report := Report{
+		Version:  "1.2.3",
+		Platform: "linux/amd64",
+		CPUs:     8,
+		Root:     "/tmp/tree",
+		Tree:     &Tree{Root: "/tmp/tree", Files: 1_000_000, Dirs: 20_000, Bytes: 1 << 40},
+		Generate: 40 * time.Second,
+		Results: []Result{
+			{Name: "scanner", Runs: 3, Median: 2 * time.Second, Fastest: time.Second, Files: 1_000_000, Dirs: 20_000, FilesSec: 500_000, DirsSec: 10_000},
+		},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, "pretty", report))
+	assert.Contains(t, text.String(), "1,000,000 files in 20,000 dirs")
+	assert.Contains(t, text.String(), "500,000")
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, output.ReportJSON, report))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+
+	err := Write(&bytes.Buffer{}, "xml", report)
+	assert.ErrorIs(t, err, output.ErrUnknownReportFormat)
+	assert.True(t, strings.Contains(err.Error(), "json, text"))
+}
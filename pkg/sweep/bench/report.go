@@ -0,0 +1,62 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Report is the outcome of a benchmark session. Its environment fields make
+// numbers from different machines and releases comparable.
+type Report struct {
+	Version  string        `json:"version"`
+	Platform string        `json:"platform"` // GOOS/GOARCH
+	CPUs     int           `json:"cpus"`
+	Root     string        `json:"root"`
+	Tree     *Tree         `json:"tree,omitempty"`        // Set for synthetic trees
+	Generate time.Duration `json:"generate_ns,omitempty"` // Time to generate the tree
+	Results  []Result      `json:"results"`
+}
+
+// Write renders the report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteJSON renders the report as indented JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteText renders the report as a table.
+func WriteText(w io.Writer, r Report) error {
+	fmt.Fprintf(w, "sweep %s (%s, %d CPUs)\n", r.Version, r.Platform, r.CPUs)
+	if r.Tree != nil {
+		fmt.Fprintf(w, "Tree: %s, %s files in %s dirs, %s apparent (generated in %s)\n",
+			r.Root, humanize.Comma(r.Tree.Files), humanize.Comma(r.Tree.Dirs),
+			types.FormatSize(r.Tree.Bytes), r.Generate.Round(time.Millisecond))
+	} else {
+		fmt.Fprintf(w, "Tree: %s\n", r.Root)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tMEDIAN\tFASTEST\tFILES/S\tDIRS/S")
+	for _, res := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", res.Name,
+			res.Median.Round(time.Millisecond), res.Fastest.Round(time.Millisecond),
+			humanize.Comma(int64(res.FilesSec)), humanize.Comma(int64(res.DirsSec)))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,239 @@
+// Package bench generates synthetic directory trees and measures scan
+// throughput, so performance can be compared across releases and machines.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Errors returned when parsing benchmark settings.
+var (
+	ErrInvalidCount        = errors.New("invalid count")
+	ErrUnknownDistribution = errors.New("unknown size distribution")
+)
+
+// sizeBucket is a range of file sizes chosen with the given weight.
+// Sizes are spread log-uniformly between Min and Max.
+type sizeBucket struct {
+	Weight int
+	Min    int64
+	Max    int64
+}
+
+// distributions are the named file size distributions.
+var distributions = map[string][]sizeBucket{
+	// Mostly small files with a long tail of large ones, like a home directory.
+	"mixed": {
+		{Weight: 90, Min: 1 * types.KiB, Max: 1 * types.MiB},
+		{Weight: 9, Min: 1 * types.MiB, Max: 100 * types.MiB},
+		{Weight: 1, Min: 100 * types.MiB, Max: 4 * types.GiB},
+	},
+	// Source trees and caches.
+	"small": {
+		{Weight: 1, Min: 0, Max: 64 * types.KiB},
+	},
+	// Media libraries and build outputs.
+	"large": {
+		{Weight: 1, Min: 10 * types.MiB, Max: 4 * types.GiB},
+	},
+}
+
+// DefaultDistribution is the size distribution used when none is given.
+const DefaultDistribution = "mixed"
+
+// Distributions returns the names of the available size distributions.
+func Distributions() []string {
+	return []string{"mixed", "small", "large"}
+}
+
+// Spec describes a synthetic tree.
+type Spec struct {
+	Files        int    // Number of files
+	Dirs         int    // Number of directories, including the root; 0 picks one per 50 files
+	Fanout       int    // Subdirectories per directory; 0 uses 10
+	Distribution string // Size distribution name; empty uses DefaultDistribution
+	Seed         int64  // Random seed, so trees are reproducible
+}
+
+// Tree describes a generated tree.
+type Tree struct {
+	Root  string `json:"root"`
+	Files int64  `json:"files"`
+	Dirs  int64  `json:"dirs"`
+	Bytes int64  `json:"bytes"` // Apparent size; files are sparse and use little disk
+}
+
+// ParseCount parses counts such as "5000", "100k", or "1M".
+func ParseCount(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	mult := 1
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult = 1_000
+		s = s[:len(s)-1]
+	case strings.HasSuffix(s, "m"), strings.HasSuffix(s, "M"):
+		mult = 1_000_000
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCount, s)
+	}
+	return int(n * float64(mult)), nil
+}
+
+// withDefaults fills in zero fields.
+func (s Spec) withDefaults() (Spec, error) {
+	if s.Files < 0 || s.Dirs < 0 || s.Fanout < 0 {
+		return s, fmt.Errorf("%w: counts must not be negative", ErrInvalidCount)
+	}
+	if s.Dirs == 0 {
+		s.Dirs = max(1, s.Files/50)
+	}
+	if s.Fanout == 0 {
+		s.Fanout = 10
+	}
+	if s.Distribution == "" {
+		s.Distribution = DefaultDistribution
+	}
+	if _, ok := distributions[s.Distribution]; !ok {
+		return s, fmt.Errorf("%w %q (available: %s)", ErrUnknownDistribution, s.Distribution, strings.Join(Distributions(), ", "))
+	}
+	return s, nil
+}
+
+// dirPath returns the path of directory i. Directory 0 is the root and
+// directory i > 0 is a child of directory (i-1)/fanout.
+func dirPath(root string, i, fanout int) string {
+	var parts []string
+	for i > 0 {
+		parts = append(parts, "d"+strconv.Itoa(i))
+		i = (i - 1) / fanout
+	}
+	for l, r := 0, len(parts)-1; l < r; l, r = l+1, r-1 {
+		parts[l], parts[r] = parts[r], parts[l]
+	}
+	return filepath.Join(append([]string{root}, parts...)...)
+}
+
+// sampleSize picks a file size from buckets.
+func sampleSize(rng *rand.Rand, buckets []sizeBucket) int64 {
+	total := 0
+	for _, b := range buckets {
+		total += b.Weight
+	}
+	pick := rng.Intn(total)
+	b := buckets[0]
+	for _, candidate := range buckets {
+		if pick < candidate.Weight {
+			b = candidate
+			break
+		}
+		pick -= candidate.Weight
+	}
+
+	// Log-uniform between Min and Max, treating 0 as 1 byte.
+	lo, hi := math.Log(float64(max(b.Min, 1))), math.Log(float64(b.Max))
+	size := int64(math.Exp(lo + rng.Float64()*(hi-lo)))
+	return max(b.Min, min(size, b.Max))
+}
+
+// Generate creates the tree described by spec under root, creating root if
+// needed. Files are sparse, so even terabytes of apparent size need little
+// disk space.
+func Generate(ctx context.Context, root string, spec Spec) (*Tree, error) {
+	spec, err := spec.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	buckets := distributions[spec.Distribution]
+
+	for i := range spec.Dirs {
+		if err := os.MkdirAll(dirPath(root, i, spec.Fanout), 0o755); err != nil {
+			return nil, err
+		}
+		if i%1000 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	// Sizes come from one seeded generator so the tree is reproducible
+	// regardless of how the writes are scheduled.
+	rng := rand.New(rand.NewSource(spec.Seed)) //nolint:gosec // Not security sensitive
+	sizes := make([]int64, spec.Files)
+	var totalBytes int64
+	for i := range sizes {
+		sizes[i] = sampleSize(rng, buckets)
+		totalBytes += sizes[i]
+	}
+
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := runtime.NumCPU()
+	var next atomic.Int64
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= spec.Files || writeCtx.Err() != nil {
+					return
+				}
+				path := filepath.Join(dirPath(root, i%spec.Dirs, spec.Fanout), "f"+strconv.Itoa(i)+".bin")
+				if err := writeSparse(path, sizes[i]); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Tree{
+		Root:  root,
+		Files: int64(spec.Files),
+		Dirs:  int64(spec.Dirs),
+		Bytes: totalBytes,
+	}, nil
+}
+
+// writeSparse creates a file with the given apparent size and no data.
+func writeSparse(path string, size int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,149 @@
+// Package budget checks directories against storage budgets and reports
+// violations in formats understood by CI systems (text, JSON, JUnit XML,
+// and SARIF).
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// largestLimit is the number of largest files recorded per result.
+const largestLimit = 5
+
+// ErrEmptyPath is returned when a budget has no path.
+var ErrEmptyPath = errors.New("budget path is empty")
+
+// Budget is a storage limit for a directory.
+type Budget struct {
+	Name     string // Display name; defaults to Path
+	Path     string
+	MaxSize  int64 // Maximum total size in bytes (0 = unlimited)
+	MaxFiles int64 // Maximum number of files (0 = unlimited)
+}
+
+// DisplayName returns the budget name, falling back to its path.
+func (b Budget) DisplayName() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return b.Path
+}
+
+// File is a single file contributing to a budget.
+type File struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Result is the outcome of checking one budget.
+type Result struct {
+	Budget  Budget
+	Size    int64
+	Files   int64
+	Largest []File // Largest files under the path, biggest first
+	Err     error  // Set if the path could not be measured
+}
+
+// SizeExceeded reports whether the total size is over the budget.
+func (r Result) SizeExceeded() bool {
+	return r.Budget.MaxSize > 0 && r.Size > r.Budget.MaxSize
+}
+
+// FilesExceeded reports whether the file count is over the budget.
+func (r Result) FilesExceeded() bool {
+	return r.Budget.MaxFiles > 0 && r.Files > r.Budget.MaxFiles
+}
+
+// Failed reports whether the budget was violated or could not be checked.
+func (r Result) Failed() bool {
+	return r.Err != nil || r.SizeExceeded() || r.FilesExceeded()
+}
+
+// Failures counts the failed results.
+func Failures(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if r.Failed() {
+			n++
+		}
+	}
+	return n
+}
+
+// Check measures each budget's path and returns one result per budget,
+// in the same order.
+func Check(ctx context.Context, budgets []Budget) []Result {
+	results := make([]Result, 0, len(budgets))
+	for _, b := range budgets {
+		r := Result{Budget: b}
+		if b.Path == "" {
+			r.Err = ErrEmptyPath
+		} else {
+			r.Err = measure(ctx, &r)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// measure walks the budget path, filling in size, file count, and the
+// largest files. Unreadable subdirectories are skipped.
+func measure(ctx context.Context, r *Result) error {
+	root := r.Budget.Path
+	var largest []File
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		r.Size += info.Size()
+		r.Files++
+		largest = append(largest, File{Path: path, Size: info.Size()})
+		if len(largest) > largestLimit*4 {
+			largest = topFiles(largest)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to measure %s: %w", root, err)
+	}
+
+	r.Largest = topFiles(largest)
+	return nil
+}
+
+// topFiles returns the largest files, biggest first.
+func topFiles(files []File) []File {
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	if len(files) > largestLimit {
+		files = files[:largestLimit]
+	}
+	return files
+}
@@ -0,0 +1,80 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for i := 0; i < 8; i++ {
+		writeFile(t, filepath.Join(dir, "artifacts", "nested", string(rune('a'+i))+".bin"), (i+1)*100)
+	}
+
+	results := Check(context.Background(), []Budget{
+		{Name: "ok", Path: filepath.Join(dir, "artifacts"), MaxSize: 10000},
+		{Name: "size", Path: filepath.Join(dir, "artifacts"), MaxSize: 1000},
+		{Name: "files", Path: filepath.Join(dir, "artifacts"), MaxFiles: 3},
+		{Name: "missing", Path: filepath.Join(dir, "nope")},
+		{Name: "empty"},
+	})
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+
+	ok := results[0]
+	if ok.Failed() || ok.Size != 3600 || ok.Files != 8 {
+		t.Errorf("unexpected ok result: size=%d files=%d failed=%v", ok.Size, ok.Files, ok.Failed())
+	}
+	if len(ok.Largest) != largestLimit || ok.Largest[0].Size != 800 {
+		t.Errorf("Largest = %+v, want %d files starting with 800 bytes", ok.Largest, largestLimit)
+	}
+
+	if !results[1].SizeExceeded() || results[1].FilesExceeded() {
+		t.Errorf("expected size-only violation: %+v", results[1])
+	}
+	if !results[2].FilesExceeded() || results[2].SizeExceeded() {
+		t.Errorf("expected file-count-only violation: %+v", results[2])
+	}
+	if results[3].Err == nil {
+		t.Error("expected error for missing path")
+	}
+	if !errors.Is(results[4].Err, ErrEmptyPath) {
+		t.Errorf("expected ErrEmptyPath, got %v", results[4].Err)
+	}
+
+	if got := Failures(results); got != 4 {
+		t.Errorf("Failures() = %d, want 4", got)
+	}
+}
+
+func TestCheckCanceled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.bin"), 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := Check(ctx, []Budget{{Path: dir}})
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", results[0].Err)
+	}
+}
@@ -0,0 +1,346 @@
+package budget
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Report formats, besides output.ReportText and output.ReportJSON.
+const (
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// SARIF rule IDs.
+const (
+	ruleSize  = "storage-budget/size"
+	ruleFiles = "storage-budget/files"
+	ruleError = "storage-budget/error"
+)
+
+// Formats lists the supported report formats.
+func Formats() []string {
+	return []string{output.ReportText, output.ReportJSON, FormatJUnit, FormatSARIF}
+}
+
+// Write renders results in the given format.
+func Write(w io.Writer, format string, results []Result) error {
+	return output.WriteReport(w, format, results, output.ReportWriters[[]Result]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+		FormatJUnit:       WriteJUnit,
+		FormatSARIF:       WriteSARIF,
+	})
+}
+
+// status returns a short status label for a result.
+func status(r Result) string {
+	switch {
+	case r.Err != nil:
+		return "ERROR"
+	case r.Failed():
+		return "FAIL"
+	default:
+		return "OK"
+	}
+}
+
+// limitString formats a size limit, using "-" for unlimited.
+func limitString(limit int64, format func(int64) string) string {
+	if limit <= 0 {
+		return "-"
+	}
+	return format(limit)
+}
+
+// message describes a result in one sentence.
+func message(r Result) string {
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+	var parts []string
+	if r.SizeExceeded() {
+		parts = append(parts, fmt.Sprintf("size %s exceeds budget %s by %s",
+			types.FormatSize(r.Size), types.FormatSize(r.Budget.MaxSize),
+			types.FormatSize(r.Size-r.Budget.MaxSize)))
+	}
+	if r.FilesExceeded() {
+		parts = append(parts, fmt.Sprintf("%d files exceeds budget of %d", r.Files, r.Budget.MaxFiles))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%s in %d files is within budget", types.FormatSize(r.Size), r.Files)
+	}
+	return fmt.Sprintf("%s: %s", r.Budget.DisplayName(), strings.Join(parts, "; "))
+}
+
+// WriteText renders results as a human-readable table.
+func WriteText(w io.Writer, results []Result) error {
+	fmt.Fprintf(w, "%-6s  %-30s  %-12s  %-12s  %-8s\n", "STATUS", "BUDGET", "SIZE", "LIMIT", "FILES")
+	fmt.Fprintln(w, strings.Repeat("-", 76))
+	for _, r := range results {
+		name := r.Budget.DisplayName()
+		if len(name) > 30 {
+			name = "..." + name[len(name)-27:]
+		}
+		fmt.Fprintf(w, "%-6s  %-30s  %-12s  %-12s  %-8d\n",
+			status(r), name, types.FormatSize(r.Size),
+			limitString(r.Budget.MaxSize, types.FormatSize), r.Files)
+		if r.Failed() {
+			fmt.Fprintf(w, "        %s\n", message(r))
+			for _, f := range r.Largest {
+				fmt.Fprintf(w, "          %-10s %s\n", types.FormatSize(f.Size), f.Path)
+			}
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 76))
+	_, err := fmt.Fprintf(w, "%d budget(s) checked, %d failed\n", len(results), Failures(results))
+	return err
+}
+
+// jsonResult is the JSON form of a Result.
+type jsonResult struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Size     int64  `json:"size"`
+	MaxSize  int64  `json:"max_size,omitempty"`
+	Files    int64  `json:"files"`
+	MaxFiles int64  `json:"max_files,omitempty"`
+	Message  string `json:"message"`
+	Largest  []File `json:"largest,omitempty"`
+}
+
+// WriteJSON renders results as a JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, jsonResult{
+			Name:     r.Budget.DisplayName(),
+			Path:     r.Budget.Path,
+			Status:   strings.ToLower(status(r)),
+			Size:     r.Size,
+			MaxSize:  r.Budget.MaxSize,
+			Files:    r.Files,
+			MaxFiles: r.Budget.MaxFiles,
+			Message:  message(r),
+			Largest:  r.Largest,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// JUnit XML structures. Each budget is a test case; violations are
+// failures and measurement errors are errors.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit renders results as a JUnit XML report.
+func WriteJUnit(w io.Writer, results []Result) error {
+	suite := junitSuite{Name: "storage-budgets", Tests: len(results)}
+	for _, r := range results {
+		tc := junitCase{
+			Name:      r.Budget.DisplayName(),
+			Classname: "sweep.budget",
+			File:      r.Budget.Path,
+			SystemOut: fmt.Sprintf("size=%d files=%d max_size=%d max_files=%d",
+				r.Size, r.Files, r.Budget.MaxSize, r.Budget.MaxFiles),
+		}
+		switch {
+		case r.Err != nil:
+			suite.Errors++
+			tc.Error = &junitProblem{Message: message(r), Type: "MeasureError", Body: r.Err.Error()}
+		case r.Failed():
+			suite.Failures++
+			problemType := "SizeExceeded"
+			if !r.SizeExceeded() {
+				problemType = "FilesExceeded"
+			}
+			var body strings.Builder
+			body.WriteString("Largest files:\n")
+			for _, f := range r.Largest {
+				fmt.Fprintf(&body, "  %s  %s\n", types.FormatSize(f.Size), f.Path)
+			}
+			tc.Failure = &junitProblem{Message: message(r), Type: problemType, Body: body.String()}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitSuites{
+		Name:     "sweep",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode junit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// SARIF 2.1.0 structures, limited to what code scanning tools consume.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifURI converts a path to a SARIF artifact URI. Relative paths stay
+// relative so CI tools can map them to repository files.
+func sarifURI(path string) string {
+	if filepath.IsAbs(path) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+	return filepath.ToSlash(path)
+}
+
+// WriteSARIF renders budget violations as a SARIF 2.1.0 log.
+// Passing budgets produce no results.
+func WriteSARIF(w io.Writer, results []Result) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "sweep",
+			InformationURI: "https://github.com/jamesainslie/sweep",
+			Rules: []sarifRule{
+				{ID: ruleSize, ShortDescription: sarifMessage{Text: "Directory exceeds its storage size budget"}},
+				{ID: ruleFiles, ShortDescription: sarifMessage{Text: "Directory exceeds its file count budget"}},
+				{ID: ruleError, ShortDescription: sarifMessage{Text: "Storage budget could not be checked"}},
+			},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, r := range results {
+		if !r.Failed() {
+			continue
+		}
+		res := sarifResult{
+			Level:   "error",
+			Message: sarifMessage{Text: message(r)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(r.Budget.Path)},
+				},
+			}},
+		}
+		switch {
+		case r.Err != nil:
+			res.RuleID = ruleError
+		case r.SizeExceeded():
+			res.RuleID = ruleSize
+		default:
+			res.RuleID = ruleFiles
+		}
+		for i, f := range r.Largest {
+			res.RelatedLocations = append(res.RelatedLocations, sarifLocation{
+				ID: i + 1,
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(f.Path)},
+				},
+				Message: &sarifMessage{Text: types.FormatSize(f.Size)},
+			})
+		}
+		run.Results = append(run.Results, res)
+	}
+
+	doc := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,102 @@
+package budget
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+)
+
+func sampleResults() []Result {
+	return []Result{
+		{Budget: Budget{Name: "dist", Path: "build/dist", MaxSize: 1000}, Size: 500, Files: 2},
+		{
+			Budget:  Budget{Name: "cache", Path: "build/cache", MaxSize: 1000},
+			Size:    5000,
+			Files:   3,
+			Largest: []File{{Path: "build/cache/big.bin", Size: 4000}},
+		},
+		{Budget: Budget{Path: "/abs/missing"}, Err: errors.New("no such file or directory")},
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, sampleResults()); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Errors != 1 {
+		t.Errorf("tests/failures/errors = %d/%d/%d, want 3/1/1", doc.Tests, doc.Failures, doc.Errors)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].Error != nil {
+		t.Error("passing budget should have no failure")
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Type != "SizeExceeded" ||
+		!strings.Contains(cases[1].Failure.Body, "build/cache/big.bin") {
+		t.Errorf("unexpected failure: %+v", cases[1].Failure)
+	}
+	if cases[2].Error == nil {
+		t.Error("measurement error should be reported as <error>")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, sampleResults()); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", doc)
+	}
+
+	results := doc.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results (violations only), got %d", len(results))
+	}
+	if results[0].RuleID != ruleSize || results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI != "build/cache" {
+		t.Errorf("unexpected size result: %+v", results[0])
+	}
+	if len(results[0].RelatedLocations) != 1 {
+		t.Errorf("expected largest file as related location")
+	}
+	if results[1].RuleID != ruleError || results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI != "file:///abs/missing" {
+		t.Errorf("unexpected error result: %+v", results[1])
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range Formats() {
+		var buf bytes.Buffer
+		if err := Write(&buf, format, sampleResults()); err != nil {
+			t.Errorf("Write(%q) error = %v", format, err)
+		}
+		if buf.Len() == 0 {
+			t.Errorf("Write(%q) produced no output", format)
+		}
+	}
+
+	if err := Write(&bytes.Buffer{}, "html", nil); !errors.Is(err, output.ErrUnknownReportFormat) {
+		t.Errorf("Write(html) error = %v, want output.ErrUnknownReportFormat", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package clipboard copies text to the system clipboard. It uses the
+// platform's clipboard tool where one is available, and otherwise asks the
+// terminal to set its clipboard with an OSC 52 escape sequence, which also
+// works over SSH.
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// MethodOSC52 is the method reported when the terminal was asked to copy.
+const MethodOSC52 = "OSC 52"
+
+// ErrUnavailable is returned when no clipboard tool worked and there is no
+// terminal to send an OSC 52 sequence to.
+var ErrUnavailable = errors.New("no clipboard available")
+
+// tool is a command that reads the text to copy from its standard input.
+type tool struct {
+	name string
+	args []string
+}
+
+// Replaced in tests.
+var (
+	getenv   = os.Getenv
+	goos     = runtime.GOOS
+	lookPath = exec.LookPath
+	runTool  = func(t tool, text string) error {
+		cmd := exec.Command(t.name, t.args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	// terminal receives OSC 52 sequences; stderr is the terminal even
+	// while a full-screen UI owns stdout.
+	terminal = func() (io.Writer, bool) {
+		info, err := os.Stderr.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return nil, false
+		}
+		return os.Stderr, true
+	}
+)
+
+// Copy puts text on the clipboard and returns how: the name of the tool
+// used, or MethodOSC52. In SSH sessions the terminal is asked first, since
+// the local clipboard tools would copy on the remote machine.
+func Copy(text string) (string, error) {
+	remote := getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+	if remote && copyOSC52(text) {
+		return MethodOSC52, nil
+	}
+
+	var failed error
+	for _, t := range tools() {
+		if _, err := lookPath(t.name); err != nil {
+			continue
+		}
+		if err := runTool(t, text); err != nil {
+			failed = fmt.Errorf("%s: %w", t.name, err)
+			continue
+		}
+		return t.name, nil
+	}
+
+	if !remote && copyOSC52(text) {
+		return MethodOSC52, nil
+	}
+	if failed != nil {
+		return "", failed
+	}
+	return "", ErrUnavailable
+}
+
+// tools lists the clipboard tools to try on this platform, in order.
+func tools() []tool {
+	switch goos {
+	case "darwin":
+		return []tool{{name: "pbcopy"}}
+	case "windows":
+		return []tool{{name: "clip.exe"}}
+	}
+
+	var list []tool
+	if getenv("WAYLAND_DISPLAY") != "" {
+		list = append(list, tool{name: "wl-copy"})
+	}
+	if getenv("DISPLAY") != "" {
+		list = append(list,
+			tool{name: "xclip", args: []string{"-selection", "clipboard"}},
+			tool{name: "xsel", args: []string{"--clipboard", "--input"}},
+		)
+	}
+	if getenv("WSL_DISTRO_NAME") != "" {
+		list = append(list, tool{name: "clip.exe"})
+	}
+	return list
+}
+
+// copyOSC52 sends text to the terminal's clipboard, reporting whether
+// there was a terminal to send it to. Terminals don't acknowledge the
+// sequence, so a terminal that ignores it can't be detected.
+func copyOSC52(text string) bool {
+	w, ok := terminal()
+	if !ok {
+		return false
+	}
+	_, err := io.WriteString(w, osc52(text))
+	return err == nil
+}
+
+// osc52 builds the escape sequence that copies text, wrapped for tmux and
+// screen so they pass it on to the outer terminal.
+func osc52(text string) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		return "\x1bP" + seq + "\x1b\\"
+	default:
+		return seq
+	}
+}
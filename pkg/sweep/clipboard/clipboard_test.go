@@ -0,0 +1,108 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEnv stands in for the environment, the installed tools, and the
+// terminal, recording the tools run and the terminal output.
+type fakeEnv struct {
+	env       map[string]string
+	installed map[string]error // Tool name -> result of running it
+	tty       *bytes.Buffer    // nil when there is no terminal
+	ran       []string
+}
+
+// setup makes Copy run on platform in f.
+func setup(t *testing.T, platform string, f *fakeEnv) {
+	t.Helper()
+	origEnv, origOS, origLook, origRun, origTerm := getenv, goos, lookPath, runTool, terminal
+	t.Cleanup(func() {
+		getenv, goos, lookPath, runTool, terminal = origEnv, origOS, origLook, origRun, origTerm
+	})
+
+	goos = platform
+	getenv = func(k string) string { return f.env[k] }
+	lookPath = func(name string) (string, error) {
+		if _, ok := f.installed[name]; ok {
+			return "/usr/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	runTool = func(tl tool, _ string) error {
+		f.ran = append(f.ran, tl.name)
+		return f.installed[tl.name]
+	}
+	terminal = func() (io.Writer, bool) {
+		if f.tty == nil {
+			return nil, false
+		}
+		return f.tty, true
+	}
+}
+
+func TestCopyUsesPlatformTool(t *testing.T) {
+	f := &fakeEnv{installed: map[string]error{"pbcopy": nil}, tty: &bytes.Buffer{}}
+	setup(t, "darwin", f)
+
+	method, err := Copy("/tmp/a.iso")
+	require.NoError(t, err)
+	assert.Equal(t, "pbcopy", method)
+	assert.Zero(t, f.tty.Len(), "no escape sequence when a tool worked")
+}
+
+func TestCopyLinuxToolOrder(t *testing.T) {
+	f := &fakeEnv{
+		env:       map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"},
+		installed: map[string]error{"wl-copy": errors.New("no compositor"), "xsel": nil},
+	}
+	setup(t, "linux", f)
+
+	method, err := Copy("x")
+	require.NoError(t, err)
+	assert.Equal(t, "xsel", method)
+	assert.Equal(t, []string{"wl-copy", "xsel"}, f.ran, "xclip is not installed")
+}
+
+func TestCopyFallsBackToOSC52(t *testing.T) {
+	f := &fakeEnv{tty: &bytes.Buffer{}}
+	setup(t, "linux", f)
+
+	method, err := Copy("/tmp/a.iso")
+	require.NoError(t, err)
+	assert.Equal(t, MethodOSC52, method)
+	assert.Equal(t, "\x1b]52;c;L3RtcC9hLmlzbw==\a", f.tty.String())
+}
+
+func TestCopyOverSSHPrefersTerminal(t *testing.T) {
+	f := &fakeEnv{
+		env:       map[string]string{"SSH_TTY": "/dev/pts/1", "DISPLAY": ":0", "TMUX": "/tmp/tmux-1000/default,1,0"},
+		installed: map[string]error{"xclip": nil},
+		tty:       &bytes.Buffer{},
+	}
+	setup(t, "linux", f)
+
+	method, err := Copy("a")
+	require.NoError(t, err)
+	assert.Equal(t, MethodOSC52, method)
+	assert.Empty(t, f.ran)
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;YQ==\a\x1b\\", f.tty.String())
+}
+
+func TestCopyUnavailable(t *testing.T) {
+	setup(t, "linux", &fakeEnv{})
+	_, err := Copy("a")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	f := &fakeEnv{env: map[string]string{"DISPLAY": ":0"}, installed: map[string]error{"xclip": errors.New("exit status 1")}}
+	setup(t, "linux", f)
+	_, err = Copy("a")
+	assert.ErrorContains(t, err, "xclip: exit status 1")
+}
@@ -0,0 +1,81 @@
+// Package cloud detects online-only placeholder files left by iCloud Drive,
+// OneDrive, Dropbox and Google Drive, whose size is that of the file in the
+// cloud while little or none of it is stored locally. Deleting one frees
+// next to nothing and may delete the file from the cloud too.
+package cloud
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Cloud storage providers, as reported in Info.Provider.
+const (
+	ProviderICloud      = "iCloud"
+	ProviderOneDrive    = "OneDrive"
+	ProviderDropbox     = "Dropbox"
+	ProviderGoogleDrive = "Google Drive"
+)
+
+// Info describes an online-only placeholder file.
+type Info struct {
+	Provider string `json:"provider,omitempty"` // Empty when the folder isn't one a known provider syncs
+	OnDisk   int64  `json:"on_disk"`            // Bytes stored locally
+}
+
+// FromFileInfo returns the placeholder information for the file at path,
+// or nil if its contents are stored locally or the platform doesn't mark
+// placeholders. It needs no system call beyond the stat behind fi.
+func FromFileInfo(path string, fi fs.FileInfo) *Info {
+	onDisk, ok := placeholder(fi)
+	if !ok {
+		return nil
+	}
+	return &Info{Provider: Provider(path), OnDisk: onDisk}
+}
+
+// Local returns the bytes a file of the given size stores locally: size,
+// unless it is a placeholder.
+func Local(info *Info, size int64) int64 {
+	if info == nil {
+		return size
+	}
+	return min(info.OnDisk, size)
+}
+
+// String describes the placeholder, e.g. "iCloud, online only".
+func (i *Info) String() string {
+	if i.Provider == "" {
+		return "online only"
+	}
+	return i.Provider + ", online only"
+}
+
+// providerDirs are path components under which providers sync files, by
+// provider. Those in ~/Library/CloudStorage start with the provider's name
+// and go on with the account.
+var providerDirs = []struct {
+	prefix   string
+	provider string
+}{
+	{"Mobile Documents", ProviderICloud},
+	{"iCloud Drive", ProviderICloud},
+	{"OneDrive", ProviderOneDrive},
+	{"Dropbox", ProviderDropbox},
+	{"GoogleDrive", ProviderGoogleDrive},
+	{"Google Drive", ProviderGoogleDrive},
+}
+
+// Provider guesses the provider syncing path from the folders it is in,
+// returning "" when none matches.
+func Provider(path string) string {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		for _, p := range providerDirs {
+			if strings.HasPrefix(dir, p.prefix) {
+				return p.provider
+			}
+		}
+	}
+	return ""
+}
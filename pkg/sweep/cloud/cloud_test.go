@@ -0,0 +1,61 @@
+package cloud
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProvider(t *testing.T) {
+	tests := map[string]string{
+		"/Users/ann/Library/Mobile Documents/com~apple~CloudDocs/film.mov":  ProviderICloud,
+		"/Users/ann/Library/CloudStorage/OneDrive-Contoso/Deck.pptx":        ProviderOneDrive,
+		"C:/Users/ann/OneDrive - Contoso/Deck.pptx":                         ProviderOneDrive,
+		"/Users/ann/Library/CloudStorage/Dropbox/video.mp4":                 ProviderDropbox,
+		"/home/ann/Dropbox/video.mp4":                                       ProviderDropbox,
+		"/Users/ann/Library/CloudStorage/GoogleDrive-ann@example.com/a.iso": ProviderGoogleDrive,
+		"/Users/ann/Movies/film.mov":                                        "",
+		"/Users/ann/Movies/Dropbox.dmg":                                     "", // Only folders count
+	}
+	for path, want := range tests {
+		if got := Provider(filepath.FromSlash(path)); got != want {
+			t.Errorf("Provider(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	if got := Local(nil, 100); got != 100 {
+		t.Errorf("Local(nil, 100) = %d, want 100", got)
+	}
+	if got := Local(&Info{OnDisk: 4096}, 1<<30); got != 4096 {
+		t.Errorf("Local() = %d, want the bytes on disk", got)
+	}
+	// Blocks round up past the size of a small, partly fetched file
+	if got := Local(&Info{OnDisk: 4096}, 100); got != 100 {
+		t.Errorf("Local() = %d, want no more than the size", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := (&Info{Provider: ProviderICloud}).String(); got != "iCloud, online only" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (&Info{}).String(); got != "online only" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestFromFileInfoLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "local.bin")
+	if err := os.WriteFile(path, make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := FromFileInfo(path, fi); info != nil {
+		t.Errorf("FromFileInfo() = %+v for a local file, want nil", info)
+	}
+}
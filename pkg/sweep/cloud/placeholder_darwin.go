@@ -0,0 +1,23 @@
+//go:build darwin
+
+package cloud
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// sfDataless is the file flag macOS sets on files whose contents are
+// fetched from a file provider, such as iCloud Drive or OneDrive, on first
+// access.
+const sfDataless = 0x40000000
+
+// placeholder reports whether fi is a dataless file, and the bytes it
+// stores locally.
+func placeholder(fi fs.FileInfo) (onDisk int64, ok bool) {
+	st, isStat := fi.Sys().(*syscall.Stat_t)
+	if !isStat || st.Flags&sfDataless == 0 {
+		return 0, false
+	}
+	return st.Blocks * 512, true
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !windows
+
+package cloud
+
+import "io/fs"
+
+// placeholder reports no placeholders: other platforms don't mark them.
+func placeholder(fs.FileInfo) (onDisk int64, ok bool) {
+	return 0, false
+}
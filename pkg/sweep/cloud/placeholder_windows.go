@@ -0,0 +1,27 @@
+//go:build windows
+
+package cloud
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// File attributes the Windows cloud files API sets on placeholders whose
+// contents are fetched on access, as OneDrive's Files On-Demand does.
+const (
+	fileAttributeOffline            = 0x1000
+	fileAttributeRecallOnOpen       = 0x40000
+	fileAttributeRecallOnDataAccess = 0x400000
+)
+
+// placeholder reports whether fi is an online-only file. Windows doesn't
+// report how much of it is stored locally, so none is assumed.
+func placeholder(fi fs.FileInfo) (onDisk int64, ok bool) {
+	data, isAttr := fi.Sys().(*syscall.Win32FileAttributeData)
+	if !isAttr {
+		return 0, false
+	}
+	const online = fileAttributeOffline | fileAttributeRecallOnOpen | fileAttributeRecallOnDataAccess
+	return 0, data.FileAttributes&online != 0
+}
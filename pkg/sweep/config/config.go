@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/adrg/xdg"
@@ -34,6 +35,228 @@ type DaemonConfig struct {
 	SocketPath   string `mapstructure:"socket_path"`
 	PIDPath      string `mapstructure:"pid_path"`
 	MinIndexSize string `mapstructure:"min_index_size"` // Minimum file size for large file index (default: 10MB)
+	HashWarmer   bool   `mapstructure:"hash_warmer"`    // Hash new large files in the background while idle
+	HashWorkers  int    `mapstructure:"hash_workers"`   // Files 'sweep hash' requests hash at once (0 = min(4, CPUs))
+	IndexMode    string `mapstructure:"index_mode"`     // "full" (default) or "aggregates": directory totals and large files only
+	StoreBackend string `mapstructure:"store_backend"`  // "badger" (default) or "sqlite", which needs a sweepd built with -tags sqlite
+
+	IndexPaths   []string `mapstructure:"index_paths"`   // Directories to index and watch at startup
+	IndexStagger string   `mapstructure:"index_stagger"` // Wait between indexing them, one after another, e.g. "10s"
+
+	// MaxStoreSize is a soft limit on the index store, e.g. "20GB". Above it
+	// the least recently queried path's files are evicted. Empty means no
+	// limit.
+	MaxStoreSize string `mapstructure:"max_store_size"`
+
+	// MaxResults caps the large files returned to a client that doesn't ask
+	// for a limit. 0 uses the daemon's default of 10000.
+	MaxResults int `mapstructure:"max_results"`
+
+	// SnapshotInterval is how often the daemon saves each indexed path's
+	// directory sizes for 'sweep diff', and its totals for 'sweep stats',
+	// e.g. "6h"; "0" disables snapshots. SnapshotRetention is how long
+	// snapshots are kept, e.g. "90d"; "0" keeps them forever.
+	SnapshotInterval  string `mapstructure:"snapshot_interval"`
+	SnapshotRetention string `mapstructure:"snapshot_retention"`
+
+	// CompactInterval is how often the daemon drops stale records from its
+	// index store and reclaims their space, e.g. "24h"; "0" only compacts
+	// on 'sweep daemon compact'.
+	CompactInterval string `mapstructure:"compact_interval"`
+
+	// Throttle slows background indexing down so it doesn't make the
+	// machine sluggish.
+	Throttle DaemonThrottleConfig `mapstructure:"throttle"`
+
+	// WatchSuggestions is what the daemon does with paths clients look at
+	// often that aren't saved watches: "suggest" (default) offers them in
+	// the TUI, "auto" watches them without asking, and "off" does neither.
+	WatchSuggestions string `mapstructure:"watch_suggestions"`
+
+	// Listen is a TCP address, such as ":7433", where the daemon also serves
+	// remote clients. Remote clients must present a certificate signed by
+	// TLS.ClientCA.
+	Listen string          `mapstructure:"listen"`
+	TLS    DaemonTLSConfig `mapstructure:"tls"`
+
+	HTTP DaemonHTTPConfig `mapstructure:"http"`
+
+	MetricsAddr string `mapstructure:"metrics_addr"` // TCP address serving Prometheus metrics at /metrics; empty disables
+}
+
+// DaemonThrottleConfig slows the daemon's indexing down.
+type DaemonThrottleConfig struct {
+	OpsPerSec       int    `mapstructure:"ops_per_sec"`       // Most files and directories indexed per second; 0 = no limit
+	Nice            int    `mapstructure:"nice"`              // Nice level the daemon runs at, 0 to 19; 0 leaves it
+	PauseOnBattery  bool   `mapstructure:"pause_on_battery"`  // Wait while the machine runs on battery
+	PauseOnActivity string `mapstructure:"pause_on_activity"` // Wait until keyboard and mouse are idle this long, e.g. "2m"; empty doesn't
+}
+
+// DaemonHTTPConfig serves large-file results and watch events as JSON over
+// HTTP, for browser dashboards that can't speak gRPC.
+type DaemonHTTPConfig struct {
+	Listen  string   `mapstructure:"listen"`  // TCP address, e.g. "127.0.0.1:7434"; empty disables HTTP
+	Token   string   `mapstructure:"token"`   // Bearer token; required unless listening on loopback
+	Origins []string `mapstructure:"origins"` // Browser origins allowed to call the API (CORS); "*" allows any
+}
+
+// DaemonTLSConfig holds the daemon's certificates for remote clients.
+type DaemonTLSConfig struct {
+	Cert     string `mapstructure:"cert"`      // Server certificate (PEM)
+	Key      string `mapstructure:"key"`       // Server private key (PEM)
+	ClientCA string `mapstructure:"client_ca"` // CA that signs client certificates
+}
+
+// RemoteConfig connects sweep to a daemon on another machine.
+type RemoteConfig struct {
+	Address    string `mapstructure:"address"`     // host:port; empty uses the local daemon
+	Cert       string `mapstructure:"cert"`        // Client certificate (PEM)
+	Key        string `mapstructure:"key"`         // Client private key (PEM)
+	CA         string `mapstructure:"ca"`          // CA that signs the daemon's certificate; empty uses system roots
+	ServerName string `mapstructure:"server_name"` // Name expected in the daemon's certificate; defaults to the host
+}
+
+// ClientConfig configures how sweep talks to the daemon.
+type ClientConfig struct {
+	// Timeout is the deadline for each daemon request, e.g. "30s"; "0"
+	// waits as long as the daemon takes. Streams, such as the TUI's live
+	// updates, are not bounded by it.
+	Timeout string `mapstructure:"timeout"`
+}
+
+// BudgetConfig is a storage budget checked by 'sweep check'.
+type BudgetConfig struct {
+	Name     string `mapstructure:"name"`
+	Path     string `mapstructure:"path"`
+	MaxSize  string `mapstructure:"max_size"`  // e.g., "500MB"; empty means unlimited
+	MaxFiles int64  `mapstructure:"max_files"` // 0 means unlimited
+}
+
+// AlertConfig configures the daemon's disk usage alerts.
+type AlertConfig struct {
+	Thresholds []AlertThresholdConfig `mapstructure:"thresholds"`
+	Interval   string                 `mapstructure:"interval"` // How often thresholds are checked, e.g. "5m"
+	Desktop    bool                   `mapstructure:"desktop"`  // Show a desktop notification
+	Webhook    string                 `mapstructure:"webhook"`  // URL alerts are POSTed to as JSON
+	Exec       string                 `mapstructure:"exec"`     // Shell command run for each alert
+}
+
+// AlertThresholdConfig is a disk usage limit the daemon alerts about.
+type AlertThresholdConfig struct {
+	Name    string `mapstructure:"name"`
+	Path    string `mapstructure:"path"`
+	MaxSize string `mapstructure:"max_size"` // e.g., "50GB"; empty means unlimited
+	MaxUsed string `mapstructure:"max_used"` // Share of the path's volume, e.g., "90%"; empty means unlimited
+}
+
+// HookConfig is a webhook or command the daemon runs for file events.
+type HookConfig struct {
+	Name    string   `mapstructure:"name"`
+	Path    string   `mapstructure:"path"`     // Directory events must be under
+	Events  []string `mapstructure:"events"`   // created, modified, deleted, renamed; empty means created and modified
+	MinSize string   `mapstructure:"min_size"` // e.g., "5GB"; empty means the daemon's large file threshold
+	Exclude []string `mapstructure:"exclude"`  // File name globs to ignore
+	Webhook string   `mapstructure:"webhook"`  // URL events are POSTed to as JSON
+	Exec    string   `mapstructure:"exec"`     // Shell command run for each event
+}
+
+// UIConfig configures the terminal UI.
+type UIConfig struct {
+	Columns []string       `mapstructure:"columns"` // File list columns: size, mtime, age, owner, type, path, name
+	Widths  map[string]int `mapstructure:"widths"`  // Per-column width overrides
+	Units   string         `mapstructure:"units"`   // Size units: iec, si, bytes
+	Locale  string         `mapstructure:"locale"`  // Language of relative ages, e.g. "de"; empty uses LC_TIME/LANG
+	Resume  string         `mapstructure:"resume"`  // Resuming the last session when run without paths: ask, always, never
+
+	AgeColors AgeColorsConfig `mapstructure:"age_colors"`
+}
+
+// AgeColorsConfig colors file rows by age, from fresh to stale.
+type AgeColorsConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Fresh   string   `mapstructure:"fresh"`  // Age of the first color, e.g. "7d" (default)
+	Stale   string   `mapstructure:"stale"`  // Age of the last color, e.g. "2y" (default)
+	Colors  []string `mapstructure:"colors"` // Hex colors from fresh to stale; default green, yellow, red
+}
+
+// TrashConfig configures the system trash.
+type TrashConfig struct {
+	Quota   string            `mapstructure:"quota"`   // Trash size limit per volume, e.g. "20GB"; empty means unlimited
+	Volumes map[string]string `mapstructure:"volumes"` // Quota overrides by mount point
+
+	// PermanentRoots are directories whose files may be deleted permanently,
+	// skipping the trash, with --permanent.
+	PermanentRoots []string `mapstructure:"permanent_roots"`
+
+	// Backend is how files are deleted when --backend isn't given: trash
+	// (default), permanent, staging, or upload.
+	Backend string        `mapstructure:"backend"`
+	Staging StagingConfig `mapstructure:"staging"`
+	Upload  UploadConfig  `mapstructure:"upload"`
+}
+
+// StagingConfig configures the staging delete backend, which moves files
+// into a quarantine directory and purges them after a number of days.
+type StagingConfig struct {
+	Dir  string `mapstructure:"dir"`  // Default DefaultStagingDir(); must be on the volume of the files staged
+	Days int    `mapstructure:"days"` // Days files are kept before they are purged; 0 keeps them
+}
+
+// UploadConfig configures the upload delete backend, which deletes each
+// file once a command has uploaded it.
+type UploadConfig struct {
+	Command string `mapstructure:"command"` // Shell command run with $SWEEP_PATH and $SWEEP_SIZE
+}
+
+// MoveConfig sets the defaults of 'sweep move' and 'M' in the TUI, which
+// move files to another volume instead of deleting them.
+type MoveConfig struct {
+	Dest    string `mapstructure:"dest"`    // Directory files are moved into, e.g. "/Volumes/Archive"
+	Verify  string `mapstructure:"verify"`  // "size" (default) or "hash"
+	Symlink bool   `mapstructure:"symlink"` // Leave a symlink at each old path
+}
+
+// ConfirmConfig sets how deleting files in the TUI is confirmed by the
+// class of directory they are in. Levels are "none" (no dialog),
+// "standard" (yes or no), and "strict" (type "delete").
+type ConfirmConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`   // Off asks yes or no everywhere
+	Cache     string `mapstructure:"cache"`     // Known cache directories; default none
+	Home      string `mapstructure:"home"`      // Files directly in the home directory; default strict
+	Documents string `mapstructure:"documents"` // ~/Documents and ~/Desktop; default strict
+	Default   string `mapstructure:"default"`   // Everywhere else; default standard
+
+	CacheDirs    []string `mapstructure:"cache_dirs"`    // More directories classed as caches
+	DocumentDirs []string `mapstructure:"document_dirs"` // More directories classed as documents
+
+	// RecentDays flags files modified within this many days in dry-run
+	// reports, whether or not confirmation is enabled; default 7.
+	RecentDays int `mapstructure:"recent_days"`
+}
+
+// BackupConfig describes a backup repository checked for copies of large
+// files, so the TUI can show whether a file is already backed up.
+type BackupConfig struct {
+	Name            string   `mapstructure:"name"`
+	Type            string   `mapstructure:"type"`             // restic, borg, or timemachine
+	Repository      string   `mapstructure:"repository"`       // Repository location; not used for Time Machine
+	PasswordFile    string   `mapstructure:"password_file"`    // File holding the repository password
+	PasswordCommand string   `mapstructure:"password_command"` // Command printing the repository password
+	Paths           []string `mapstructure:"paths"`            // Backed-up paths; empty means every file is looked up
+	Binary          string   `mapstructure:"binary"`           // Backup tool binary; defaults to the type's tool on PATH
+}
+
+// RuleConfig is a cleanup rule that the daemon runs on a schedule.
+type RuleConfig struct {
+	Name      string   `mapstructure:"name"`
+	Path      string   `mapstructure:"path"`
+	Include   []string `mapstructure:"include"`    // Glob patterns matched against file names; empty matches all
+	OlderThan string   `mapstructure:"older_than"` // e.g., "30d"; files modified more recently are kept
+	MinSize   string   `mapstructure:"min_size"`   // e.g., "10MB"
+	Schedule  string   `mapstructure:"schedule"`   // Cron expression, "@daily", or "@every 6h"; empty runs only on demand
+	Preset    string   `mapstructure:"preset"`     // gradle, maven, or bazel: clean the tool's cache by entry; path defaults to its location
+	Projects  []string `mapstructure:"projects"`   // Directories searched for the projects using a preset's entries
+	Backend   string   `mapstructure:"backend"`    // How matches are deleted, e.g. "staging"; default trash.backend
 }
 
 // Config represents the application configuration.
@@ -41,6 +264,7 @@ type Config struct {
 	MinSize     string   `mapstructure:"min_size"`
 	DefaultPath string   `mapstructure:"default_path"`
 	Exclude     []string `mapstructure:"exclude"`
+	Symlinks    string   `mapstructure:"symlinks"` // Symlinked directories to follow: skip (default), within-root, or follow
 	Workers     struct {
 		Dir  int `mapstructure:"dir"`
 		File int `mapstructure:"file"`
@@ -50,8 +274,47 @@ type Config struct {
 		Path          string `mapstructure:"path"`
 		RetentionDays int    `mapstructure:"retention_days"`
 	} `mapstructure:"manifest"`
-	Logging LoggingConfig `mapstructure:"logging"`
-	Daemon  DaemonConfig  `mapstructure:"daemon"`
+	Logging LoggingConfig  `mapstructure:"logging"`
+	Daemon  DaemonConfig   `mapstructure:"daemon"`
+	Budgets []BudgetConfig `mapstructure:"budgets"`
+	Alerts  AlertConfig    `mapstructure:"alerts"`
+	Hooks   []HookConfig   `mapstructure:"hooks"`
+	UI      UIConfig       `mapstructure:"ui"`
+	Trash   TrashConfig    `mapstructure:"trash"`
+	Move    MoveConfig     `mapstructure:"move"`
+	Confirm ConfirmConfig  `mapstructure:"confirm"`
+	Rules   []RuleConfig   `mapstructure:"rules"`
+	Backups []BackupConfig `mapstructure:"backups"`
+	Remote  RemoteConfig   `mapstructure:"remote"`
+	Client  ClientConfig   `mapstructure:"client"`
+	// ReadOnly disables actions that modify files, including cleanup rules.
+	ReadOnly bool `mapstructure:"read_only"`
+	// IncludeSnapshots scans and indexes filesystem snapshot directories,
+	// such as .zfs/snapshot, which are otherwise skipped.
+	IncludeSnapshots bool `mapstructure:"include_snapshots"`
+	// OneFileSystem keeps scans and indexes on the file system of the path
+	// scanned, not entering others mounted under it.
+	OneFileSystem bool         `mapstructure:"one_file_system"`
+	Mounts        MountsConfig `mapstructure:"mounts"`
+	// Network sets how network file systems are scanned and watched.
+	Network NetworkConfig `mapstructure:"network"`
+}
+
+// MountsConfig lists mount points scans and indexes treat specially.
+type MountsConfig struct {
+	Skip  []string `mapstructure:"skip"`  // Never entered
+	Cross []string `mapstructure:"cross"` // Entered even with one_file_system
+}
+
+// NetworkConfig sets how scans and the daemon treat network file systems,
+// such as NFS, SMB, and FUSE mounts, where every stat is a round trip.
+type NetworkConfig struct {
+	Detect      bool     `mapstructure:"detect"`       // Detect them by file system type
+	Paths       []string `mapstructure:"paths"`        // Mount points treated as network ones anyway
+	Local       []string `mapstructure:"local"`        // Mount points treated as local anyway, such as a fast NAS
+	DirWorkers  int      `mapstructure:"dir_workers"`  // Directory workers scanning one (0 = 4)
+	FileWorkers int      `mapstructure:"file_workers"` // File workers scanning one (0 = 2)
+	Watch       bool     `mapstructure:"watch"`        // Have the daemon watch indexed ones for changes
 }
 
 // Load loads configuration from file and environment variables.
@@ -60,6 +323,8 @@ type Config struct {
 //   - $HOME/.config/sweep/config.yaml
 //
 // Environment variables are prefixed with SWEEP_ (e.g., SWEEP_MIN_SIZE).
+// The file may include other files and reference environment variables as
+// ${VAR}; see ReadInConfig.
 func Load() (*Config, error) {
 	v := viper.New()
 
@@ -87,6 +352,8 @@ func Load() (*Config, error) {
 	v.SetDefault("min_size", DefaultMinSize)
 	v.SetDefault("default_path", DefaultPath)
 	v.SetDefault("exclude", DefaultExclusions)
+	v.SetDefault("symlinks", "skip")
+	v.SetDefault("network.detect", true)
 	v.SetDefault("workers.dir", DefaultDirWorkers)
 	v.SetDefault("workers.file", DefaultFileWorkers)
 	v.SetDefault("manifest.enabled", true)
@@ -110,13 +377,26 @@ func Load() (*Config, error) {
 	})
 
 	// Daemon defaults
+	v.SetDefault("client.timeout", DefaultClientTimeout)
+
 	v.SetDefault("daemon.auto_start", true)
 	v.SetDefault("daemon.socket_path", "")    // Empty means use default XDG path
 	v.SetDefault("daemon.pid_path", "")       // Empty means use default XDG path
 	v.SetDefault("daemon.min_index_size", "") // Empty means use default (10MB)
-
-	// Read config file (ignore if not found)
-	if err := v.ReadInConfig(); err != nil {
+	v.SetDefault("daemon.hash_warmer", false)
+	v.SetDefault("daemon.index_mode", "full")
+	v.SetDefault("daemon.store_backend", "badger")
+	v.SetDefault("daemon.max_results", 10000)
+	v.SetDefault("daemon.snapshot_interval", "6h")
+	v.SetDefault("daemon.snapshot_retention", "90d")
+	v.SetDefault("daemon.compact_interval", "24h")
+	v.SetDefault("daemon.index_stagger", "10s")
+	v.SetDefault("daemon.watch_suggestions", "suggest")
+	v.SetDefault("alerts.interval", "5m")
+	v.SetDefault("alerts.desktop", true)
+
+	// Read config file with its includes (ignore if not found)
+	if _, err := ReadInConfig(v); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
 		if !errors.As(err, &configFileNotFoundError) {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -190,20 +470,60 @@ func EnsureManifestDir() error {
 	return nil
 }
 
+// Setup holds the answers from first-run setup that shape a new config file.
+type Setup struct {
+	Exclude    []string // Exclusion patterns
+	IndexPaths []string // Directories for the daemon to index and watch
+	AutoStart  bool     // Start the daemon automatically
+}
+
+// DefaultSetup returns the settings written by 'sweep config init'.
+func DefaultSetup() Setup {
+	return Setup{
+		Exclude:   DefaultExclusions,
+		AutoStart: true,
+	}
+}
+
+// Path returns the path of the user's config file, whether or not it exists.
+func Path() (string, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.yaml"), nil
+}
+
 // WriteDefault writes a default config file if none exists.
 // Returns nil if a config file already exists.
 func WriteDefault() error {
+	return WriteSetup(DefaultSetup())
+}
+
+// yamlList formats items as an indented YAML block sequence, or [] if empty.
+func yamlList(items []string, indent string) string {
+	if len(items) == 0 {
+		return " []"
+	}
+	var b strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&b, "\n%s- %q", indent, item)
+	}
+	return b.String()
+}
+
+// WriteSetup writes a commented config file using the given setup answers.
+// Returns nil without changes if a config file already exists.
+func WriteSetup(setup Setup) error {
 	if err := EnsureConfigDir(); err != nil {
 		return err
 	}
 
-	configDir, err := ConfigDir()
+	configPath, err := Path()
 	if err != nil {
 		return err
 	}
 
-	configPath := filepath.Join(configDir, "config.yaml")
-
 	// Check if config file already exists
 	if _, err := os.Stat(configPath); err == nil {
 		// Config file exists, do nothing
@@ -223,6 +543,9 @@ func WriteDefault() error {
 # Location: ~/.config/sweep/config.yaml (or $XDG_CONFIG_HOME/sweep/config.yaml)
 # Regenerate defaults: sweep config init
 # View current config: sweep config show
+#
+# Layer shared defaults under these settings with include: [/etc/sweep/org.yaml]
+# Values may reference environment variables as ${NAME} or ${NAME:-default}
 # =============================================================================
 
 # -----------------------------------------------------------------------------
@@ -243,10 +566,7 @@ default_path: %s
 # Paths to exclude from scanning (glob patterns supported)
 # These paths are skipped entirely during directory traversal
 # Common exclusions: virtual filesystems, build artifacts, caches
-exclude:
-  - /proc
-  - /sys
-  - /dev
+exclude:%s
   # Uncomment to exclude additional paths:
   # - /tmp
   # - "**/node_modules"
@@ -254,6 +574,56 @@ exclude:
   # - "**/target"        # Rust build artifacts
   # - "**/vendor"        # Go vendor directory
 
+# Which symlinks to directories scans and the daemon's indexes follow
+# Options: skip (follow none), within-root (only to directories under the
+#   scanned root), follow (all). Symlinks back into a directory being walked,
+#   or into a tree already followed, are never followed; symlinks to files
+#   never are either, as the files are counted where they are
+# CLI override: sweep --symlinks <policy>
+symlinks: skip
+
+# Whether scans and the daemon's indexes enter filesystem snapshots:
+#   .zfs/snapshot, btrfs .snapshots (snapper) and Timeshift snapshots, and
+#   Time Machine local snapshots, along with snapshots mounted elsewhere.
+#   Each is a copy of data counted already, so they are skipped by default
+# CLI override: sweep --include-snapshots
+include_snapshots: false
+
+# Whether scans and the daemon's indexes stay on the file system of the path
+#   scanned, as du -x does, so scanning / doesn't descend into network shares,
+#   external drives, or /proc. Mount points not entered are reported as
+#   skipped at a device boundary
+# CLI override: sweep --one-file-system
+one_file_system: false
+
+# Mount points handled explicitly, whether or not one_file_system is set
+mounts:
+  # Never entered, such as a slow network share under a scanned directory
+  skip: []
+  #   - /mnt/nas
+  # Entered, with everything mounted in them, even with one_file_system
+  cross: []
+  #   - /home
+
+# How network file systems (NFS, SMB, AFP, WebDAV, and FUSE mounts such as
+#   sshfs and rclone) are handled. Each stat on one is a round trip to the
+#   server, so scans of them use fewer workers, and the daemon indexes them
+#   but doesn't watch them for changes, as file events over the network are
+#   unreliable and registering watches means listing every directory
+network:
+  # Detect them by file system type
+  detect: true
+  # Mount points treated as network ones whatever their type
+  paths: []
+  # Mount points treated as local whatever their type, such as a fast NAS
+  local: []
+  # Workers scanning a path on one; 0 uses the defaults (4 and 2). The
+  #   -w flag overrides these
+  dir_workers: 0
+  file_workers: 0
+  # Have the daemon watch indexed network mounts for changes
+  watch: false
+
 # -----------------------------------------------------------------------------
 # Worker Pool Configuration
 # -----------------------------------------------------------------------------
@@ -336,11 +706,25 @@ logging:
 # Start manually: sweepd
 # Query via daemon: sweep (auto-connects if running)
 # Bypass daemon: sweep --no-daemon
+# Changes to logging, min_index_size, index_paths, alerts, and hooks are
+# applied when this file is saved (or with: sweep daemon reload); the rest
+# take a restart: sweep daemon restart
 
 daemon:
   # Automatically start daemon when running sweep commands
   # If false, must start daemon manually with: sweepd
-  auto_start: true
+  auto_start: %t
+
+  # Directories to index and watch as soon as the daemon starts
+  # Other paths are indexed the first time they are scanned
+  # Example: [~/Downloads, ~/Projects]
+  index_paths:%s
+
+  # They and the paths saved with 'sweep daemon watch add' are indexed one
+  # after another, waiting this long between them so the daemon doesn't
+  # swamp the disk as the machine starts; "0" doesn't wait. Those waiting
+  # show as queued in 'sweep daemon status'
+  index_stagger: 10s
 
   # Path to sweepd binary
   # Empty string uses auto-discovery in order:
@@ -353,6 +737,7 @@ daemon:
   # Unix socket path for daemon communication
   # Empty string uses default: $XDG_DATA_HOME/sweep/sweep.sock
   # On macOS: ~/Library/Application Support/sweep/sweep.sock
+  # On Windows the daemon listens on a named pipe derived from this path
   socket_path: ""
 
   # PID file path
@@ -375,8 +760,313 @@ daemon:
   # Format: number with unit suffix (KB, MB, GB)
   # Default (when empty): 10MB
   # Examples: 1MB, 500KB, 100KB, 50MB
+  # See what each size would index, and apply one, with: sweep daemon tune
   min_index_size: ""
 
+  # Hash new large files in the background while the daemon is idle
+  # Cached hashes are returned without reading the files again
+  # Hashing pauses whenever indexing runs or files change
+  # Off by default, since it reads every new large file in full
+  hash_warmer: false
+
+  # How many files 'sweep hash' requests hash at once, shared by all clients
+  # Default (when 0): 4, or the number of CPUs if fewer
+  hash_workers: 0
+
+  # What the index stores
+  #   full:       every file and directory (default)
+  #   aggregates: directory totals (size and file count) plus the large file
+  #               index only; a much smaller store when you only need
+  #               directory usage and the largest files. Directory totals are
+  #               refreshed when a path is re-indexed, not on every change.
+  # Re-index existing paths (sweep daemon index --force) after changing this
+  index_mode: full
+
+  # Where the index is stored
+  #   badger: a Badger key-value store in index.db (default)
+  #   sqlite: a SQLite database in index.sqlite, which you can query with
+  #           the sqlite3 shell while the daemon runs. Needs a sweepd built
+  #           with cgo and -tags sqlite.
+  # The two don't share data: after switching, paths are indexed again
+  store_backend: badger
+
+  # Soft limit on the index store's size on disk (e.g., "20GB")
+  # Above it, the files of the least recently queried path are dropped from
+  # the index; its directory totals are kept for 'sweep du'. The path then
+  # reports as not indexed until it is indexed again. Checked every 5 minutes.
+  # Default (when empty): no limit
+  max_store_size: ""
+
+  # Most large files returned to a client that doesn't set a limit
+  # The TUI shows when its list was cut short; use --limit or --no-daemon to
+  # see more
+  max_results: 10000
+
+  # How often to save each indexed path's directory sizes, so
+  # 'sweep diff --since 7d' can show what grew; "0" disables snapshots
+  snapshot_interval: 6h
+
+  # How long snapshots are kept; "0" keeps them forever
+  snapshot_retention: 90d
+
+  # How often to drop stale records from the index store, such as entries
+  # left by removed watches and hashes of changed files, and reclaim the
+  # space; "0" only compacts on 'sweep daemon compact'
+  compact_interval: 24h
+
+  # Paths you look at often that aren't saved watches (sweep daemon watch add)
+  #   suggest: the TUI offers to keep them indexed and watched (default)
+  #   auto:    watch them without asking
+  #   off:     neither
+  # A path counts as used often after 3 separate sessions since sweepd started
+  watch_suggestions: suggest
+
+  # Slow background indexing down so indexing a large home directory doesn't
+  # make the machine sluggish
+  # throttle:
+  #   ops_per_sec: 2000        # Most files and directories indexed per second
+  #   nice: 10                 # Run the daemon at a lower CPU (and on Linux,
+  #                            # disk) priority, 0 to 19
+  #   pause_on_battery: true   # Wait while running on battery
+  #   pause_on_activity: 2m    # Wait until the keyboard and mouse have been
+  #                            # idle this long (macOS only)
+
+  # Also serve remote clients on a TCP address, e.g. on a NAS or server
+  # Remote clients connect with: sweep --remote host:port
+  # Mutual TLS is required: the daemon presents cert/key and only accepts
+  # clients whose certificate is signed by client_ca
+  # listen: ":7433"
+  # tls:
+  #   cert: ~/.config/sweep/tls/server.pem
+  #   key: ~/.config/sweep/tls/server-key.pem
+  #   client_ca: ~/.config/sweep/tls/ca.pem
+
+  # Stream large files and watch events as JSON over HTTP for web dashboards
+  # GET /v1/large-files and /v1/watch answer with newline-delimited JSON, or
+  # server-sent events for EventSource (Accept: text/event-stream)
+  # A token is required unless listening on loopback; pass it as
+  # "Authorization: Bearer <token>" or ?token=<token>
+  # http:
+  #   listen: "127.0.0.1:7434"
+  #   token: ""
+  #   origins: ["http://localhost:3000"]
+
+  # Serve Prometheus metrics at http://<metrics_addr>/metrics: files indexed,
+  # index durations, watcher events, gRPC request durations, and store size
+  # Metrics carry no file paths and need no token; keep the address private
+  # metrics_addr: "127.0.0.1:9433"
+
+# -----------------------------------------------------------------------------
+# Remote Daemon
+# -----------------------------------------------------------------------------
+# Browse the index of a daemon on another machine (see daemon.listen there).
+# Remote sessions are read-only: files on the other machine are never deleted.
+
+# remote:
+#   address: nas.local:7433       # Or pass --remote host:port
+#   cert: ~/.config/sweep/tls/client.pem
+#   key: ~/.config/sweep/tls/client-key.pem
+#   ca: ~/.config/sweep/tls/ca.pem  # CA that signed the daemon's certificate
+
+# Deadline for each request to the daemon, local or remote ("0" for none).
+# Raise it for a slow link or a very large index.
+# client:
+#   timeout: 30s
+
+# -----------------------------------------------------------------------------
+# Storage Budgets
+# -----------------------------------------------------------------------------
+# Limits checked by 'sweep check' (exits non-zero when exceeded)
+# Report formats for CI: sweep check -o junit, sweep check -o sarif
+
+# budgets:
+#   - name: build artifacts
+#     path: ./dist
+#     max_size: 500MB
+#   - path: ./node_modules
+#     max_files: 50000
+
+# -----------------------------------------------------------------------------
+# Disk Usage Alerts
+# -----------------------------------------------------------------------------
+# Thresholds the daemon checks every interval. Crossing one sends an alert
+# once; it is sent again only after usage falls back below the threshold.
+# max_size is measured from the index, so the path must be indexed; max_used
+# is the share of the path's volume in use (macOS and Linux).
+
+# alerts:
+#   interval: 5m
+#   desktop: true                            # Desktop notification
+#   webhook: https://hooks.example.com/disk  # POSTed the alert as JSON
+#   exec: ~/bin/on-disk-alert.sh             # Run with SWEEP_ALERT_* set and the JSON on stdin
+#   thresholds:
+#     - name: downloads
+#       path: ~/Downloads
+#       max_size: 50GB
+#     - path: /
+#       max_used: 90%%
+
+# -----------------------------------------------------------------------------
+# File Event Hooks
+# -----------------------------------------------------------------------------
+# Webhooks and commands the daemon runs when large files under a watched
+# path are created, modified, deleted or renamed. Each is POSTed, or gets on
+# its standard input, the event as JSON: hook, event, path, new_path, size,
+# time and text (a sentence, so Slack incoming webhooks take it as is).
+# Commands also get SWEEP_HOOK, SWEEP_EVENT, SWEEP_PATH, SWEEP_NEW_PATH and
+# SWEEP_SIZE in their environment.
+
+# hooks:
+#   - name: big uploads
+#     path: /srv/uploads
+#     events: [created]           # Default: created, modified
+#     min_size: 5GB               # Default: daemon.min_index_size
+#     exclude: ["*.part"]
+#     webhook: https://hooks.slack.com/services/T000/B000/XXXX
+#   - path: ~/Downloads
+#     events: [created, deleted]  # Deletions carry no size: every file matches
+#     exec: ~/bin/file-changed.sh
+
+# -----------------------------------------------------------------------------
+# Terminal UI
+# -----------------------------------------------------------------------------
+# Columns of the flat file list, left to right. The path or name column is
+# always last and takes the remaining width.
+# Available: size, mtime, age (e.g. "3 mo"), owner, type, path (full path),
+# name (file name)
+# Toggle at runtime: 1-5 (size, mtime, owner, type, age), p (path/name),
+# u (units)
+
+# ui:
+#   columns: [size, mtime, owner, path]
+#   widths:
+#     owner: 12
+#   units: iec          # iec (MiB), si (MB), or bytes
+#   locale: de          # Language of ages: en, de, es, fr, it, nl, pt
+#                       # (default: from LC_ALL, LC_TIME, or LANG)
+#   resume: ask         # Run without paths, offer to resume the last
+#                       # session: ask (default), always, or never
+#   age_colors:         # Color file names by age, fresh to stale
+#     enabled: true
+#     fresh: 7d         # Files this new get the first color
+#     stale: 2y         # Files this old get the last color
+#     colors: ["#28A745", "#FFC107", "#DC3545"]
+
+# -----------------------------------------------------------------------------
+# Trash
+# -----------------------------------------------------------------------------
+# Each volume keeps its own trash (the home trash, or .Trash-<uid> at the top
+# of other drives). With a quota, deleting files that would grow a volume's
+# trash past it asks whether to purge the oldest trash first or to delete the
+# files permanently.
+#
+# Huge files that would only bloat the trash can be deleted permanently with
+# --permanent, but only under permanent_roots, and only after typing
+# "delete" in the confirm dialog. The daemon refuses permanent deletes
+# outside them too.
+#
+# backend sets how files are deleted when --backend isn't given: trash
+# (default), permanent (still only under permanent_roots from the TUI),
+# staging (moved to staging.dir and purged after staging.days), or upload
+# (deleted once upload.command succeeds for them).
+
+# trash:
+#   quota: 20GB               # Per volume; empty means unlimited
+#   volumes:
+#     /Volumes/External: 5GB  # Override for one mount point
+#   permanent_roots:
+#     - ~/Movies/Renders
+#     - /scratch
+#   backend: trash
+#   staging:
+#     dir: ~/.sweep-staging   # Default: ~/.local/share/sweep/staging
+#     days: 30                # 0 keeps files until purged by hand
+#   upload:
+#     command: rclone copyto "$SWEEP_PATH" "archive:$SWEEP_PATH"
+
+# -----------------------------------------------------------------------------
+# Moving files
+# -----------------------------------------------------------------------------
+# 'sweep move' and M in the TUI move files to another volume instead of
+# deleting them. Copies are verified before the originals are removed, by
+# size or also by SHA-256 hash, and a symlink can be left behind so the file
+# stays reachable at its old path.
+
+# move:
+#   dest: /Volumes/Archive
+#   verify: size        # size or hash
+#   symlink: false
+
+# -----------------------------------------------------------------------------
+# Confirmation
+# -----------------------------------------------------------------------------
+# How deleting files in the TUI is confirmed, by the class of directory they
+# are in: none deletes without a dialog, standard asks yes or no, and strict
+# asks for "delete" to be typed. A selection spanning classes gets the
+# strictest. Caches are ~/.cache, ~/Library/Caches, the build tool caches of
+# the rule presets, and the paths of preset rules.
+
+# confirm:
+#   enabled: true
+#   cache: none         # Known cache directories
+#   home: strict        # Files directly in the home directory
+#   documents: strict   # ~/Documents and ~/Desktop
+#   default: standard   # Everywhere else
+#   cache_dirs: [~/Library/Developer/Xcode/DerivedData]
+#   document_dirs: [~/Projects]
+#   recent_days: 7      # Dry runs warn about files modified this recently
+
+# -----------------------------------------------------------------------------
+# Backups
+# -----------------------------------------------------------------------------
+# Backup repositories to look up when a file is selected in the TUI. The
+# detail panel shows the date of the latest backup holding the file, which
+# makes deleting it less risky. Lookups run the backup tool in the
+# background and only cover files under 'paths' when it is set.
+
+# backups:
+#   - name: nas
+#     type: restic
+#     repository: sftp:nas:/backups/laptop
+#     password_file: ~/.config/restic/password
+#     paths: [~/]
+#   - name: offsite
+#     type: borg
+#     repository: ssh://backup@example.com/./laptop
+#     password_command: security find-generic-password -s borg -w
+#   - type: timemachine
+
+# -----------------------------------------------------------------------------
+# Cleanup Rules
+# -----------------------------------------------------------------------------
+# The daemon runs each rule on its schedule, moving matching files to the
+# trash and recording them in the manifest (see 'sweep history').
+# Schedules are cron expressions (minute hour day month weekday), shorthands
+# like @daily or @weekly, or @every with a duration. Rules without a
+# schedule only run with 'sweep rules run'. Preview with 'sweep rules test'.
+
+# rules:
+#   - name: old logs
+#     path: ~/Library/Logs
+#     include: ["*.log", "*.log.gz"]
+#     older_than: 30d
+#     schedule: "0 3 * * *"   # Every day at 03:00
+#   - name: stale downloads
+#     path: ~/Downloads
+#     include: ["*.dmg", "*.iso", "*.zip"]
+#     older_than: 90d
+#     min_size: 100MB
+#     schedule: "@weekly"
+#
+# A preset (gradle, maven, or bazel) cleans a build tool's cache by entry,
+# e.g. each dependency version, aged by when it was last used. Path
+# defaults to the tool's cache; projects are searched for the builds using
+# each entry, named in 'sweep rules test'. See 'sweep rules presets'.
+#   - preset: gradle
+#     older_than: 180d
+#     projects: [~/src]
+#     schedule: "@monthly"
+
 # =============================================================================
 # CLI Quick Reference
 # =============================================================================
@@ -388,10 +1078,13 @@ daemon:
 # sweep -f plain            # Plain text output (one file per line)
 # sweep config init         # Regenerate this config with defaults
 # sweep config show         # Display current configuration
+# sweep check -o junit      # Check storage budgets for CI
+# sweep rules test          # Preview what cleanup rules would delete
 # sweepd                    # Start daemon manually
 # sweepd stop               # Stop running daemon
 # =============================================================================
-`, DefaultMinSize, DefaultPath, DefaultDirWorkers, DefaultFileWorkers, manifestDir, DefaultRetentionDays)
+`, DefaultMinSize, DefaultPath, yamlList(setup.Exclude, "  "), DefaultDirWorkers, DefaultFileWorkers, manifestDir,
+		DefaultRetentionDays, setup.AutoStart, yamlList(setup.IndexPaths, "    "))
 
 	if err := os.WriteFile(configPath, []byte(defaultConfig), 0o644); err != nil {
 		return fmt.Errorf("failed to write default config: %w", err)
@@ -424,11 +1117,18 @@ func StateDir() string {
 	return filepath.Join(xdg.StateHome, "sweep")
 }
 
-// CacheDir returns $XDG_CACHE_HOME/sweep/ (reserved for future use).
+// CacheDir returns $XDG_CACHE_HOME/sweep/ for data that is safe to lose,
+// such as the entry counts scans estimate their progress from.
 func CacheDir() string {
 	return filepath.Join(xdg.CacheHome, "sweep")
 }
 
+// DefaultStagingDir returns where the staging delete backend keeps files
+// when trash.staging.dir isn't set.
+func DefaultStagingDir() string {
+	return filepath.Join(DataDir(), "staging")
+}
+
 // DefaultSocketPath returns the default Unix socket path.
 func DefaultSocketPath() string {
 	return filepath.Join(DataDir(), "sweep.sock")
@@ -449,13 +1149,32 @@ func DefaultLogPath() string {
 	return filepath.Join(StateDir(), "sweep.log")
 }
 
+// DefaultTagsPath returns the default path of the persisted tag store.
+func DefaultTagsPath() string {
+	return filepath.Join(StateDir(), "tags.json")
+}
+
+// DefaultSessionsPath returns the default path of the TUI's saved
+// sessions, which record where it was left on each root.
+func DefaultSessionsPath() string {
+	return filepath.Join(StateDir(), "sessions.json")
+}
+
+// DaemonBinaryName returns the file name of the sweepd executable.
+func DaemonBinaryName() string {
+	if runtime.GOOS == "windows" {
+		return "sweepd.exe"
+	}
+	return "sweepd"
+}
+
 // DefaultBinaryPath returns the default sweepd binary path.
 // Priority: GOBIN > GOPATH/bin > $HOME/go/bin
 // Returns empty string if none of these locations exist.
 func DefaultBinaryPath() string {
 	// Check GOBIN first
 	if gobin := os.Getenv("GOBIN"); gobin != "" {
-		candidate := filepath.Join(gobin, "sweepd")
+		candidate := filepath.Join(gobin, DaemonBinaryName())
 		if _, err := os.Stat(candidate); err == nil {
 			return candidate
 		}
@@ -463,7 +1182,7 @@ func DefaultBinaryPath() string {
 
 	// Check GOPATH/bin
 	if gopath := os.Getenv("GOPATH"); gopath != "" {
-		candidate := filepath.Join(gopath, "bin", "sweepd")
+		candidate := filepath.Join(gopath, "bin", DaemonBinaryName())
 		if _, err := os.Stat(candidate); err == nil {
 			return candidate
 		}
@@ -471,7 +1190,7 @@ func DefaultBinaryPath() string {
 
 	// Check $HOME/go/bin (Go's default)
 	if home, err := os.UserHomeDir(); err == nil {
-		candidate := filepath.Join(home, "go", "bin", "sweepd")
+		candidate := filepath.Join(home, "go", "bin", DaemonBinaryName())
 		if _, err := os.Stat(candidate); err == nil {
 			return candidate
 		}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -711,3 +712,33 @@ func TestEnsureCacheDir(t *testing.T) {
 		t.Errorf("%q is not a directory", expectedDir)
 	}
 }
+
+func TestWriteSetup(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	setup := Setup{
+		Exclude:    []string{"/proc", "node_modules"},
+		IndexPaths: []string{"/home/user/Projects", "/home/user/My Files"},
+		AutoStart:  false,
+	}
+	if err := WriteSetup(setup); err != nil {
+		t.Fatalf("WriteSetup() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Exclude, setup.Exclude) {
+		t.Errorf("Exclude = %v, want %v", cfg.Exclude, setup.Exclude)
+	}
+	if !reflect.DeepEqual(cfg.Daemon.IndexPaths, setup.IndexPaths) {
+		t.Errorf("Daemon.IndexPaths = %v, want %v", cfg.Daemon.IndexPaths, setup.IndexPaths)
+	}
+	if cfg.Daemon.AutoStart {
+		t.Error("Daemon.AutoStart = true, want false")
+	}
+}
@@ -30,6 +30,9 @@ const (
 
 	// DefaultMinIndexSizeBytes is DefaultMinIndexSize in bytes.
 	DefaultMinIndexSizeBytes = 10 * 1024 * 1024
+
+	// DefaultClientTimeout is the default deadline for a request to the daemon.
+	DefaultClientTimeout = "30s"
 )
 
 // DefaultExclusions contains paths that should be excluded from scanning by default.
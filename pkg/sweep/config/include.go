@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth limits how deeply include files may include others.
+const maxIncludeDepth = 8
+
+// envRef matches ${NAME} and ${NAME:-default} references in config values,
+// and $${, which escapes a literal ${.
+var envRef = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// ReadInConfig reads the config file v is set up to find, as
+// v.ReadInConfig does, then applies the file's include list and the
+// ${VAR} references in its values. Included files are org-wide defaults:
+// they are read in order, each overriding the ones before it, and the
+// including file overrides them all. Paths are relative to the including
+// file and may be globs. It returns the included files read.
+func ReadInConfig(v *viper.Viper) ([]string, error) {
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	path := v.ConfigFileUsed()
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+		return nil, nil
+	}
+
+	settings, included, err := readLayered(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	return included, v.ReadConfig(bytes.NewReader(data))
+}
+
+// readLayered reads the config file at path with its includes merged
+// beneath it. chain holds the files including it, to catch cycles.
+func readLayered(path string, chain []string) (map[string]any, []string, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if slices.Contains(chain, path) {
+		return nil, nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(chain, " -> "), path)
+	}
+	if len(chain) > maxIncludeDepth {
+		return nil, nil, fmt.Errorf("%s: includes nested more than %d deep", path, maxIncludeDepth)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var settings map[string]any
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	if err := interpolate(settings); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	patterns, err := includePatterns(settings["include"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	delete(settings, "include")
+
+	merged := map[string]any{}
+	var included []string
+	for _, pattern := range patterns {
+		files, err := includeFiles(filepath.Dir(path), pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, file := range files {
+			layer, nested, err := readLayered(file, append(chain, path))
+			if err != nil {
+				return nil, nil, err
+			}
+			mergeSettings(merged, layer)
+			included = append(append(included, file), nested...)
+		}
+	}
+	mergeSettings(merged, settings)
+	return merged, included, nil
+}
+
+// includePatterns returns the include setting, a path or a list of paths.
+func includePatterns(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		patterns := make([]string, 0, len(v))
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("include: %v is not a path", p)
+			}
+			patterns = append(patterns, s)
+		}
+		return patterns, nil
+	default:
+		return nil, errors.New("include must be a path or a list of paths")
+	}
+}
+
+// includeFiles resolves an include pattern against dir. A glob may match
+// nothing, so a fragments directory can be empty; a plain path must exist.
+func includeFiles(dir, pattern string) ([]string, error) {
+	path, err := ExpandPath(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if strings.ContainsAny(pattern, "*?[") {
+		files, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", pattern, err)
+		}
+		return files, nil // Sorted by Glob
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("include %s: %w", pattern, err)
+	}
+	return []string{path}, nil
+}
+
+// interpolate replaces ${VAR} references in the string values of
+// settings, in place. A reference to an unset variable without a default
+// is an error, so a missing variable isn't silently read as empty.
+func interpolate(settings map[string]any) error {
+	var missing []string
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, val := range v {
+				v[k] = walk(val)
+			}
+		case []any:
+			for i, val := range v {
+				v[i] = walk(val)
+			}
+		case string:
+			return envRef.ReplaceAllStringFunc(v, func(ref string) string {
+				if ref == "$${" {
+					return "${"
+				}
+				m := envRef.FindStringSubmatch(ref)
+				if val, ok := os.LookupEnv(m[1]); ok {
+					return val
+				}
+				if m[2] != "" {
+					return m[2][len(":-"):]
+				}
+				missing = append(missing, m[1])
+				return ""
+			})
+		}
+		return v
+	}
+	walk(settings)
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("environment variables not set: %s (use ${NAME:-default} for a fallback)",
+			strings.Join(slices.Compact(missing), ", "))
+	}
+	return nil
+}
+
+// mergeSettings merges src into dst. Nested sections are merged key by
+// key; other values in src, lists included, replace those in dst.
+func mergeSettings(dst, src map[string]any) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]any); ok {
+			if existing, ok := dst[k].(map[string]any); ok {
+				mergeSettings(existing, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfigFiles writes files, relative to $XDG_CONFIG_HOME/sweep, for
+// Load to read.
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "sweep")
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"config.yaml": `include:
+  - org.yaml
+  - conf.d/*.yaml
+min_size: 50MB
+daemon:
+  index_mode: aggregates
+`,
+		"org.yaml": `min_size: 1GB
+exclude: [/org/cache]
+daemon:
+  max_results: 500
+  index_mode: full
+`,
+		"conf.d/10-team.yaml": `daemon:
+  max_results: 800
+`,
+		"conf.d/20-site.yaml": `include: ../site.yaml
+`,
+		"site.yaml": `read_only: true
+`,
+	})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MinSize != "50MB" {
+		t.Errorf("MinSize = %q, want the including file's 50MB", cfg.MinSize)
+	}
+	if !reflect.DeepEqual(cfg.Exclude, []string{"/org/cache"}) {
+		t.Errorf("Exclude = %v, want the included list", cfg.Exclude)
+	}
+	if cfg.Daemon.MaxResults != 800 {
+		t.Errorf("Daemon.MaxResults = %d, want 800 from the later include", cfg.Daemon.MaxResults)
+	}
+	if cfg.Daemon.IndexMode != "aggregates" {
+		t.Errorf("Daemon.IndexMode = %q, want aggregates", cfg.Daemon.IndexMode)
+	}
+	if !cfg.ReadOnly {
+		t.Error("ReadOnly = false, want true from a nested include")
+	}
+	if !cfg.Daemon.AutoStart {
+		t.Error("defaults are lost under includes")
+	}
+
+	// Included files are reported in the order they were read
+	_, included, err := readLayered(filepath.Join(dir, "config.yaml"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"org.yaml", "conf.d/10-team.yaml", "conf.d/20-site.yaml", "site.yaml"}
+	for i := range want {
+		want[i] = filepath.Join(dir, want[i])
+	}
+	if !reflect.DeepEqual(included, want) {
+		t.Errorf("included = %v, want %v", included, want)
+	}
+}
+
+func TestLoad_EnvInterpolation(t *testing.T) {
+	writeConfigFiles(t, map[string]string{
+		"config.yaml": `include: ${SWEEP_TEST_ORG_CONFIG}
+default_path: ${SWEEP_TEST_ROOT}/scans
+daemon:
+  max_results: ${SWEEP_TEST_MAX:-250}
+  http:
+    token: "$${literal}"
+`,
+		"fleet.yaml": `min_size: 2GB
+`,
+	})
+	t.Setenv("SWEEP_TEST_ORG_CONFIG", "fleet.yaml")
+	t.Setenv("SWEEP_TEST_ROOT", "/srv")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DefaultPath != "/srv/scans" {
+		t.Errorf("DefaultPath = %q, want /srv/scans", cfg.DefaultPath)
+	}
+	if cfg.Daemon.MaxResults != 250 {
+		t.Errorf("Daemon.MaxResults = %d, want the default 250", cfg.Daemon.MaxResults)
+	}
+	if cfg.Daemon.HTTP.Token != "${literal}" {
+		t.Errorf("Daemon.HTTP.Token = %q, want the escaped ${literal}", cfg.Daemon.HTTP.Token)
+	}
+	if cfg.MinSize != "2GB" {
+		t.Errorf("MinSize = %q, want 2GB from the interpolated include", cfg.MinSize)
+	}
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "unset variable",
+			files: map[string]string{"config.yaml": "default_path: ${SWEEP_TEST_UNSET}\n"},
+			want:  "SWEEP_TEST_UNSET",
+		},
+		{
+			name:  "missing include",
+			files: map[string]string{"config.yaml": "include: missing.yaml\n"},
+			want:  "include missing.yaml",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config.yaml": "include: a.yaml\n",
+				"a.yaml":      "include: config.yaml\n",
+			},
+			want: "include cycle",
+		},
+		{
+			name:  "not a path",
+			files: map[string]string{"config.yaml": "include: {file: a.yaml}\n"},
+			want:  "include must be a path",
+		},
+		{
+			name: "invalid fragment",
+			files: map[string]string{
+				"config.yaml": "include: bad.yaml\n",
+				"bad.yaml":    "daemon: [unclosed\n",
+			},
+			want: "bad.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFiles(t, tt.files)
+			_, err := Load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// plainValue matches values written to YAML without quotes.
+var plainValue = regexp.MustCompile(`^[A-Za-z0-9._/~-]+$`)
+
+// emptyFlowList matches an empty list after a setting's name.
+var emptyFlowList = regexp.MustCompile(`:\s*\[\s*\]`)
+
+// SetValue sets a setting such as "daemon.min_index_size" in the config
+// file at path, creating the file if needed. The file is edited in place
+// rather than re-encoded, so comments and layout are kept. Only settings
+// one level inside a top-level section are supported.
+func SetValue(path, key, value string) error {
+	section, name, ok := strings.Cut(key, ".")
+	if !ok || section == "" || name == "" || strings.Contains(name, ".") {
+		return fmt.Errorf("unsupported config key %q: expected section.setting", key)
+	}
+	value = yamlScalar(value)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	return writeConfig(path, setYAMLValue(string(data), section, name, value))
+}
+
+// setYAMLValue sets section.name to value in a YAML document, replacing
+// the setting's line if it has one (keeping any trailing comment), adding
+// it to the end of the section if not, and adding the section if missing.
+func setYAMLValue(doc, section, name, value string) string {
+	lines := strings.Split(doc, "\n")
+	header := regexp.MustCompile(`^` + regexp.QuoteMeta(section) + `:\s*(#.*)?$`)
+	setting := regexp.MustCompile(`^\s+` + regexp.QuoteMeta(name) + `:\s*("[^"]*"|'[^']*'|[^\s#]*)(\s+#.*)?$`)
+
+	start := -1
+	for i, line := range lines {
+		if header.MatchString(line) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		doc = strings.TrimRight(doc, "\n")
+		if doc != "" {
+			doc += "\n\n"
+		}
+		return fmt.Sprintf("%s%s:\n  %s: %s\n", doc, section, name, value)
+	}
+
+	// The section runs until the next line that isn't indented, blank, or
+	// a comment; comments at the margin introduce what follows
+	indent, last := "", start
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			break
+		}
+		last = i
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lineIndent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent == "" {
+			indent = lineIndent
+		}
+		if lineIndent != indent {
+			continue // Nested deeper
+		}
+		if m := setting.FindStringSubmatch(line); m != nil {
+			lines[i] = indent + name + ": " + value + m[2]
+			return strings.Join(lines, "\n")
+		}
+	}
+	if indent == "" {
+		indent = "  "
+	}
+
+	added := indent + name + ": " + value
+	lines = append(lines[:last+1], append([]string{added}, lines[last+1:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// AddListItem adds item to the top-level list setting key, such as
+// "exclude", in the config file at path, creating the file if needed. Like
+// SetValue, it edits the file in place. A list the file doesn't set yet
+// starts from defaults, so setting it doesn't drop them. An item already in
+// the list is left alone.
+func AddListItem(path, key, item string, defaults []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	updated, err := addYAMLListItem(string(data), key, item, defaults)
+	if err != nil {
+		return err
+	}
+	if updated == string(data) {
+		return nil
+	}
+	return writeConfig(path, updated)
+}
+
+// addYAMLListItem adds item to the end of the top-level list key in a YAML
+// document, after its last item rather than any comments that follow it.
+func addYAMLListItem(doc, key, item string, defaults []string) (string, error) {
+	lines := strings.Split(doc, "\n")
+	header := regexp.MustCompile(`^` + regexp.QuoteMeta(key) + `:\s*(\[\s*\])?\s*(#.*)?$`)
+	listItem := regexp.MustCompile(`^(\s+)-\s+("[^"]*"|'[^']*'|[^\s#]*)`)
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, key+":") {
+			if !header.MatchString(line) {
+				return "", fmt.Errorf("unsupported %s setting in config: expected a list of one item per line", key)
+			}
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		doc = strings.TrimRight(doc, "\n")
+		if doc != "" {
+			doc += "\n\n"
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s%s:\n", doc, key)
+		for _, d := range slices.DeleteFunc(slices.Clone(defaults), func(d string) bool { return d == item }) {
+			fmt.Fprintf(&b, "  - %s\n", yamlScalar(d))
+		}
+		fmt.Fprintf(&b, "  - %s\n", yamlScalar(item))
+		return b.String(), nil
+	}
+	// An empty flow list becomes a block list
+	lines[start] = emptyFlowList.ReplaceAllString(lines[start], ":")
+
+	indent, last := "  ", start
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		m := listItem.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		if unquote(m[2]) == item {
+			return doc, nil
+		}
+		indent, last = m[1], i
+	}
+
+	added := indent + "- " + yamlScalar(item)
+	lines = append(lines[:last+1], append([]string{added}, lines[last+1:]...)...)
+	return strings.Join(lines, "\n"), nil
+}
+
+// yamlScalar formats value for YAML, quoting it unless it's plain.
+func yamlScalar(value string) string {
+	if plainValue.MatchString(value) {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// unquote strips the quotes from a YAML scalar.
+func unquote(value string) string {
+	if s, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return s
+	}
+	return strings.Trim(value, "'")
+}
+
+// writeConfig replaces the config file at path with data.
+func writeConfig(path, data string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetYAMLValue(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "replaces the setting and keeps comments",
+			doc: `min_size: 100MB
+daemon:
+  # Minimum file size for the large file index
+  min_index_size: "" # default 10MB
+  hash_warmer: true
+
+# Cleanup rules
+rules: []
+`,
+			want: `min_size: 100MB
+daemon:
+  # Minimum file size for the large file index
+  min_index_size: 1MB # default 10MB
+  hash_warmer: true
+
+# Cleanup rules
+rules: []
+`,
+		},
+		{
+			name: "adds the setting at the end of the section",
+			doc: `daemon:
+    auto_start: true
+    remote:
+      min_index_size: 5MB
+
+# Scan settings
+min_size: 100MB
+`,
+			want: `daemon:
+    auto_start: true
+    remote:
+      min_index_size: 5MB
+    min_index_size: 1MB
+
+# Scan settings
+min_size: 100MB
+`,
+		},
+		{
+			name: "adds the section",
+			doc:  "min_size: 100MB\n",
+			want: "min_size: 100MB\n\ndaemon:\n  min_index_size: 1MB\n",
+		},
+		{
+			name: "empty document",
+			want: "daemon:\n  min_index_size: 1MB\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setYAMLValue(tt.doc, "daemon", "min_index_size", "1MB"); got != tt.want {
+				t.Errorf("setYAMLValue() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sweep", "config.yaml")
+	if err := SetValue(path, "daemon.min_index_size", "256KB"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	if err := SetValue(path, "daemon.socket_path", "/run/my sweep.sock"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "daemon:\n  min_index_size: 256KB\n  socket_path: \"/run/my sweep.sock\"\n"
+	if string(data) != want {
+		t.Errorf("config =\n%s\nwant\n%s", data, want)
+	}
+
+	if err := SetValue(path, "min_size", "1MB"); err == nil {
+		t.Error("SetValue() with a top-level key succeeded, want an error")
+	}
+}
+
+func TestAddYAMLListItem(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "adds after the last item, before comments",
+			doc: `exclude:
+  - "/proc"
+  - "/sys"
+  # Uncomment to exclude additional paths:
+  # - /tmp
+
+symlinks: skip
+`,
+			want: `exclude:
+  - "/proc"
+  - "/sys"
+  - /home/me/node_modules
+  # Uncomment to exclude additional paths:
+  # - /tmp
+
+symlinks: skip
+`,
+		},
+		{
+			name: "leaves an item already listed",
+			doc:  "exclude:\n  - \"/home/me/node_modules\"\n",
+			want: "exclude:\n  - \"/home/me/node_modules\"\n",
+		},
+		{
+			name: "turns an empty flow list into a block list",
+			doc:  "exclude: []\nsymlinks: skip\n",
+			want: "exclude:\n  - /home/me/node_modules\nsymlinks: skip\n",
+		},
+		{
+			name: "adds the list with the defaults",
+			doc:  "min_size: 100MB\n",
+			want: "min_size: 100MB\n\nexclude:\n  - /proc\n  - /home/me/node_modules\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addYAMLListItem(tt.doc, "exclude", "/home/me/node_modules", []string{"/proc"})
+			if err != nil {
+				t.Fatalf("addYAMLListItem() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("addYAMLListItem() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := addYAMLListItem("exclude: [/proc]\n", "exclude", "/tmp", nil); err == nil {
+		t.Error("addYAMLListItem() with a flow list succeeded, want an error")
+	}
+}
+
+func TestAddListItem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sweep", "config.yaml")
+	if err := AddListItem(path, "exclude", "/data/My Photos", nil); err != nil {
+		t.Fatalf("AddListItem() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "exclude:\n  - \"/data/My Photos\"\n"; string(data) != want {
+		t.Errorf("config =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,156 @@
+// Package dryrun projects what deleting files would do, for dry runs: the
+// space reclaimed under each directory, how much of each volume would be
+// free afterwards, and which files were modified recently enough that
+// something may still be using them.
+package dryrun
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// DefaultRecentDays is how many days back a file's modification makes it
+// recent unless configured otherwise.
+const DefaultRecentDays = 7
+
+// File is a file that would be deleted.
+type File struct {
+	Path    string    `json:"path" yaml:"path"`
+	Size    int64     `json:"size" yaml:"size"`
+	ModTime time.Time `json:"mod_time" yaml:"mod_time"`
+}
+
+// Dir is the space that would be reclaimed from the files directly in a
+// directory.
+type Dir struct {
+	Path  string `json:"path" yaml:"path"`
+	Files int    `json:"files" yaml:"files"`
+	Size  int64  `json:"size" yaml:"size"`
+}
+
+// Volume is a volume's free space now and once the files on it are
+// deleted. Trashed files only free their space when the trash is emptied.
+type Volume struct {
+	Mount            string  `json:"mount" yaml:"mount"`
+	Total            int64   `json:"total" yaml:"total"`
+	Free             int64   `json:"free" yaml:"free"`
+	Reclaimed        int64   `json:"reclaimed" yaml:"reclaimed"`
+	FreeAfter        int64   `json:"free_after" yaml:"free_after"`
+	FreePercent      float64 `json:"free_percent" yaml:"free_percent"`
+	FreePercentAfter float64 `json:"free_percent_after" yaml:"free_percent_after"`
+}
+
+// Report is what deleting a set of files would do.
+type Report struct {
+	Files      int      `json:"files" yaml:"files"`
+	Size       int64    `json:"size" yaml:"size"`
+	Dirs       []Dir    `json:"dirs" yaml:"dirs"`                           // Largest first
+	Volumes    []Volume `json:"volumes,omitempty" yaml:"volumes,omitempty"` // By mount point
+	RecentDays int      `json:"recent_days" yaml:"recent_days"`
+	Recent     []File   `json:"recent,omitempty" yaml:"recent,omitempty"` // Modified in the last RecentDays, newest first
+}
+
+// Options configures Project.
+type Options struct {
+	RecentDays int       // Files modified this many days before Now are recent; 0 uses DefaultRecentDays
+	Now        time.Time // Zero uses time.Now
+
+	// Mount returns the mount point of the volume holding path, and Usage
+	// that volume's usage; nil looks them up. Files whose volume can't be
+	// measured are left out of Report.Volumes.
+	Mount func(path string) (string, error)
+	Usage func(mount string) (mounts.Usage, error)
+}
+
+// Project reports what deleting files would do.
+func Project(files []File, opts Options) Report {
+	if opts.RecentDays <= 0 {
+		opts.RecentDays = DefaultRecentDays
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Mount == nil {
+		opts.Mount = mountOf
+	}
+	if opts.Usage == nil {
+		opts.Usage = mounts.DiskUsage
+	}
+	recentSince := opts.Now.AddDate(0, 0, -opts.RecentDays)
+
+	r := Report{Files: len(files), Dirs: []Dir{}, RecentDays: opts.RecentDays}
+	dirs := make(map[string]*Dir)
+	vols := make(map[string]*Volume) // nil for volumes that can't be measured
+	for _, f := range files {
+		r.Size += f.Size
+
+		dir := filepath.Dir(f.Path)
+		d, ok := dirs[dir]
+		if !ok {
+			d = &Dir{Path: dir}
+			dirs[dir] = d
+		}
+		d.Files++
+		d.Size += f.Size
+
+		if mount, err := opts.Mount(f.Path); err == nil {
+			v, ok := vols[mount]
+			if !ok {
+				if usage, err := opts.Usage(mount); err == nil {
+					v = &Volume{Mount: mount, Total: usage.Total, Free: usage.Free}
+				}
+				vols[mount] = v
+			}
+			if v != nil {
+				v.Reclaimed += f.Size
+			}
+		}
+
+		if f.ModTime.After(recentSince) {
+			r.Recent = append(r.Recent, f)
+		}
+	}
+
+	for _, d := range dirs {
+		r.Dirs = append(r.Dirs, *d)
+	}
+	sort.Slice(r.Dirs, func(i, j int) bool {
+		if r.Dirs[i].Size != r.Dirs[j].Size {
+			return r.Dirs[i].Size > r.Dirs[j].Size
+		}
+		return r.Dirs[i].Path < r.Dirs[j].Path
+	})
+
+	for _, v := range vols {
+		if v == nil {
+			continue
+		}
+		v.FreeAfter = min(v.Free+v.Reclaimed, max(v.Total, v.Free))
+		v.FreePercent = percent(v.Free, v.Total)
+		v.FreePercentAfter = percent(v.FreeAfter, v.Total)
+		r.Volumes = append(r.Volumes, *v)
+	}
+	sort.Slice(r.Volumes, func(i, j int) bool { return r.Volumes[i].Mount < r.Volumes[j].Mount })
+
+	sort.SliceStable(r.Recent, func(i, j int) bool { return r.Recent[i].ModTime.After(r.Recent[j].ModTime) })
+	return r
+}
+
+// percent returns n as a percentage of total, or 0 for an unknown total.
+func percent(n, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// mountOf returns the mount point of the volume holding path, found the
+// way trashing finds it.
+func mountOf(path string) (string, error) {
+	v, err := trash.VolumeOf(path)
+	return v.Mount, err
+}
@@ -0,0 +1,84 @@
+package dryrun
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+)
+
+func TestProject(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, -2, 0)
+	files := []File{
+		{Path: "/data/videos/a.mkv", Size: 400, ModTime: old},
+		{Path: "/data/videos/b.mkv", Size: 300, ModTime: now.Add(-time.Hour)},
+		{Path: "/data/isos/c.iso", Size: 500, ModTime: now.AddDate(0, 0, -3)},
+		{Path: "/ext/d.bin", Size: 100, ModTime: old},
+		{Path: "/unmounted/e.bin", Size: 50, ModTime: old},
+	}
+	opts := Options{
+		Now: now,
+		Mount: func(path string) (string, error) {
+			switch {
+			case strings.HasPrefix(path, "/data/"):
+				return "/data", nil
+			case strings.HasPrefix(path, "/ext/"):
+				return "/ext", nil
+			}
+			return "", errors.New("no volume")
+		},
+		Usage: func(mount string) (mounts.Usage, error) {
+			if mount == "/ext" {
+				return mounts.Usage{}, errors.New("statfs failed")
+			}
+			return mounts.Usage{Total: 10000, Free: 1000}, nil
+		},
+	}
+
+	r := Project(files, opts)
+	if r.Files != 5 || r.Size != 1350 {
+		t.Errorf("Files, Size = %d, %d, want 5, 1350", r.Files, r.Size)
+	}
+
+	var dirs []string
+	for _, d := range r.Dirs {
+		dirs = append(dirs, d.Path)
+	}
+	if want := "/data/videos /data/isos /ext /unmounted"; strings.Join(dirs, " ") != want {
+		t.Errorf("Dirs = %v, want %s", dirs, want)
+	}
+	if d := r.Dirs[0]; d.Files != 2 || d.Size != 700 {
+		t.Errorf("Dirs[0] = %+v, want 2 files, 700 bytes", d)
+	}
+
+	// /ext can't be measured and /unmounted has no volume
+	if len(r.Volumes) != 1 {
+		t.Fatalf("Volumes = %+v, want /data only", r.Volumes)
+	}
+	v := r.Volumes[0]
+	if v.Mount != "/data" || v.Reclaimed != 1200 || v.FreeAfter != 2200 || v.FreePercent != 10 || v.FreePercentAfter != 22 {
+		t.Errorf("Volumes[0] = %+v, want /data reclaiming 1200 bytes, 10%% -> 22%% free", v)
+	}
+
+	if r.RecentDays != DefaultRecentDays {
+		t.Errorf("RecentDays = %d, want %d", r.RecentDays, DefaultRecentDays)
+	}
+	if len(r.Recent) != 2 || r.Recent[0].Path != "/data/videos/b.mkv" || r.Recent[1].Path != "/data/isos/c.iso" {
+		t.Errorf("Recent = %+v, want b.mkv then c.iso", r.Recent)
+	}
+
+	opts.RecentDays = 1
+	if r := Project(files, opts); len(r.Recent) != 1 {
+		t.Errorf("with RecentDays 1, Recent = %+v, want b.mkv only", r.Recent)
+	}
+}
+
+func TestProjectNoFiles(t *testing.T) {
+	r := Project(nil, Options{})
+	if r.Files != 0 || r.Dirs == nil || len(r.Volumes) != 0 || len(r.Recent) != 0 {
+		t.Errorf("Project(nil) = %+v, want an empty report", r)
+	}
+}
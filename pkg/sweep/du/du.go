@@ -0,0 +1,253 @@
+// Package du totals file sizes per directory for 'sweep du', from the
+// daemon index or from a scan.
+package du
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Dir is the total size and file count of a directory, including all of
+// its subdirectories.
+type Dir struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Files   int64  `json:"files"`
+	Dirs    int64  `json:"dirs,omitempty"`    // Directories beneath it
+	Entries int64  `json:"entries,omitempty"` // Files and directories directly in it
+	Depth   int    `json:"depth"`             // Levels below the root; the root is 0
+}
+
+// Inodes returns the number of files and directories beneath d, each of
+// which uses an inode.
+func (d Dir) Inodes() int64 {
+	return d.Files + d.Dirs
+}
+
+// ErrUnknownSort is returned by Sort for unsupported orders.
+var ErrUnknownSort = errors.New("unknown sort order")
+
+// Sort orders.
+const (
+	SortSize    = "size"
+	SortPath    = "path"
+	SortFiles   = "files"
+	SortEntries = "entries"
+	SortInodes  = "inodes"
+)
+
+// Totals adds up file sizes into their directories down to a maximum
+// depth; files deeper down count towards their ancestor at that depth.
+// It is safe for concurrent use.
+type Totals struct {
+	root     string
+	maxDepth int // 0 = unlimited
+
+	mu   sync.Mutex
+	dirs map[string]*Dir
+}
+
+// NewTotals creates totals for the directories under root.
+func NewTotals(root string, maxDepth int) *Totals {
+	root = filepath.Clean(root)
+	return &Totals{
+		root:     root,
+		maxDepth: maxDepth,
+		dirs:     map[string]*Dir{root: {Path: root}},
+	}
+}
+
+// AddFile counts a file in each directory above it, and as an entry of
+// the directory holding it.
+func (t *Totals) AddFile(path string, size int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent := filepath.Dir(filepath.Clean(path))
+	t.ancestors(parent, func(d *Dir) {
+		d.Size += size
+		d.Files++
+		if d.Path == parent {
+			d.Entries++
+		}
+	})
+}
+
+// AddDir counts a directory below the root in each directory above it,
+// and as an entry of the directory holding it.
+func (t *Totals) AddDir(path string) {
+	path = filepath.Clean(path)
+	if path == t.root {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent := filepath.Dir(path)
+	t.ancestors(parent, func(d *Dir) {
+		d.Dirs++
+		if d.Path == parent {
+			d.Entries++
+		}
+	})
+}
+
+// ancestors calls fn for dir and each directory above it up to the root,
+// skipping those deeper than maxDepth. Paths outside the root are ignored.
+func (t *Totals) ancestors(dir string, fn func(*Dir)) {
+	rel, err := filepath.Rel(t.root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return
+	}
+
+	depth := Depth(t.root, dir)
+	for ; depth >= 0; depth-- {
+		if t.maxDepth == 0 || depth <= t.maxDepth {
+			d, ok := t.dirs[dir]
+			if !ok {
+				d = &Dir{Path: dir, Depth: depth}
+				t.dirs[dir] = d
+			}
+			fn(d)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Dirs returns the directories in no particular order.
+func (t *Totals) Dirs() []Dir {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	dirs := make([]Dir, 0, len(t.dirs))
+	for _, d := range t.dirs {
+		dirs = append(dirs, *d)
+	}
+	return dirs
+}
+
+// CountEntries fills in the directory and entry counts of dirs, which
+// hold the totals of every directory under root as an aggregates index
+// stores them, then drops those deeper than maxDepth (0 for unlimited) and
+// empty ones below the root.
+func CountEntries(root string, dirs []Dir, maxDepth int) []Dir {
+	root = filepath.Clean(root)
+	byPath := make(map[string]*Dir, len(dirs))
+	for i := range dirs {
+		dirs[i].Dirs, dirs[i].Entries = 0, 0
+		byPath[dirs[i].Path] = &dirs[i]
+	}
+
+	// Files directly in a directory are those not in its subdirectories
+	direct := make(map[string]int64, len(dirs))
+	for _, d := range dirs {
+		direct[d.Path] += d.Files
+		if d.Path == root {
+			continue
+		}
+		parent := filepath.Dir(d.Path)
+		if p, ok := byPath[parent]; ok {
+			p.Entries++
+			direct[parent] -= d.Files
+		}
+		for dir := parent; ; dir = filepath.Dir(dir) {
+			if a, ok := byPath[dir]; ok {
+				a.Dirs++
+			}
+			if dir == root || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	kept := dirs[:0]
+	for _, d := range dirs {
+		d.Entries += max(direct[d.Path], 0)
+		if (maxDepth > 0 && d.Depth > maxDepth) || (d.Depth > 0 && d.Files == 0 && d.Dirs == 0) {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
+
+// Depth returns how many levels path is below root.
+func Depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// Filter drops directories smaller than threshold, keeping the root.
+func Filter(dirs []Dir, threshold int64) []Dir {
+	if threshold <= 0 {
+		return dirs
+	}
+	kept := dirs[:0]
+	for _, d := range dirs {
+		if d.Depth == 0 || d.Size >= threshold {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// FilterEntries drops directories with fewer than threshold entries,
+// keeping the root.
+func FilterEntries(dirs []Dir, threshold int64) []Dir {
+	if threshold <= 0 {
+		return dirs
+	}
+	kept := dirs[:0]
+	for _, d := range dirs {
+		if d.Depth == 0 || d.Entries >= threshold {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// Sort orders directories by size, file, entry, or inode count, largest
+// first, or by path.
+func Sort(dirs []Dir, by string) error {
+	switch by {
+	case SortSize, "":
+		sort.SliceStable(dirs, func(i, j int) bool {
+			if dirs[i].Size != dirs[j].Size {
+				return dirs[i].Size > dirs[j].Size
+			}
+			return dirs[i].Path < dirs[j].Path
+		})
+	case SortFiles:
+		sort.SliceStable(dirs, func(i, j int) bool {
+			if dirs[i].Files != dirs[j].Files {
+				return dirs[i].Files > dirs[j].Files
+			}
+			return dirs[i].Path < dirs[j].Path
+		})
+	case SortEntries:
+		sort.SliceStable(dirs, func(i, j int) bool {
+			if dirs[i].Entries != dirs[j].Entries {
+				return dirs[i].Entries > dirs[j].Entries
+			}
+			return dirs[i].Path < dirs[j].Path
+		})
+	case SortInodes:
+		sort.SliceStable(dirs, func(i, j int) bool {
+			if dirs[i].Inodes() != dirs[j].Inodes() {
+				return dirs[i].Inodes() > dirs[j].Inodes()
+			}
+			return dirs[i].Path < dirs[j].Path
+		})
+	case SortPath, "name":
+		sort.SliceStable(dirs, func(i, j int) bool {
+			return dirs[i].Path < dirs[j].Path
+		})
+	default:
+		return fmt.Errorf("%w: %q (available: %s, %s, %s, %s, %s)", ErrUnknownSort, by, SortSize, SortFiles, SortEntries, SortInodes, SortPath)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package du
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func byPath(dirs []Dir) map[string]Dir {
+	m := make(map[string]Dir, len(dirs))
+	for _, d := range dirs {
+		m[d.Path] = d
+	}
+	return m
+}
+
+func TestTotals(t *testing.T) {
+	root := filepath.FromSlash("/data")
+	totals := NewTotals(root, 1)
+	totals.AddFile(filepath.FromSlash("/data/a.txt"), 10)
+	totals.AddFile(filepath.FromSlash("/data/videos/b.mkv"), 100)
+	totals.AddFile(filepath.FromSlash("/data/videos/2024/c.mkv"), 1000)
+	totals.AddFile(filepath.FromSlash("/data2/elsewhere.txt"), 5)
+
+	dirs := byPath(totals.Dirs())
+	require.Len(t, dirs, 2, "deeper directories count towards their ancestor")
+	assert.Equal(t, Dir{Path: root, Size: 1110, Files: 3, Entries: 1}, dirs[root])
+	videos := filepath.FromSlash("/data/videos")
+	assert.Equal(t, Dir{Path: videos, Size: 1100, Files: 2, Entries: 1, Depth: 1}, dirs[videos])
+}
+
+func TestTotalsDirs(t *testing.T) {
+	totals := NewTotals("/data", 0)
+	totals.AddDir("/data")
+	totals.AddDir("/data/cache")
+	totals.AddDir("/data/cache/a")
+	for _, name := range []string{"x", "y", "z"} {
+		totals.AddFile("/data/cache/a/"+name, 1)
+	}
+	totals.AddFile("/data/cache/b", 1)
+
+	dirs := byPath(totals.Dirs())
+	assert.Equal(t, Dir{Path: "/data", Size: 4, Files: 4, Dirs: 2, Entries: 1}, dirs["/data"], "the root isn't its own entry")
+	assert.Equal(t, Dir{Path: "/data/cache", Size: 4, Files: 4, Dirs: 1, Entries: 2, Depth: 1}, dirs["/data/cache"])
+	assert.Equal(t, int64(3), dirs["/data/cache/a"].Entries)
+	assert.Equal(t, int64(6), dirs["/data"].Inodes())
+}
+
+func TestCountEntries(t *testing.T) {
+	// As an aggregates index stores them: totals of every directory
+	dirs := []Dir{
+		{Path: "/data", Size: 5, Files: 5},
+		{Path: "/data/cache", Size: 4, Files: 4, Depth: 1},
+		{Path: "/data/cache/a", Size: 3, Files: 3, Depth: 2},
+		{Path: "/data/empty", Depth: 1},
+	}
+
+	got := byPath(CountEntries("/data", dirs, 1))
+	require.Len(t, got, 2, "deeper and empty directories are dropped after counting")
+	assert.Equal(t, Dir{Path: "/data", Size: 5, Files: 5, Dirs: 3, Entries: 3}, got["/data"])
+	assert.Equal(t, Dir{Path: "/data/cache", Size: 4, Files: 4, Dirs: 1, Entries: 2, Depth: 1}, got["/data/cache"])
+}
+
+func TestTotalsUnlimitedDepth(t *testing.T) {
+	totals := NewTotals("/data", 0)
+	totals.AddFile("/data/x/y/z/f", 7)
+	dirs := byPath(totals.Dirs())
+	require.Len(t, dirs, 4)
+	assert.Equal(t, 3, dirs["/data/x/y/z"].Depth)
+	assert.Equal(t, int64(7), dirs["/data/x"].Size)
+}
+
+func TestDepth(t *testing.T) {
+	assert.Equal(t, 0, Depth("/a", "/a"))
+	assert.Equal(t, 1, Depth("/a", "/a/b"))
+	assert.Equal(t, 3, Depth("/a", "/a/b/c/d"))
+}
+
+func TestFilterAndSort(t *testing.T) {
+	dirs := []Dir{
+		{Path: "/r", Size: 10, Files: 9},
+		{Path: "/r/b", Size: 7, Files: 1, Depth: 1},
+		{Path: "/r/a", Size: 2, Files: 8, Depth: 1},
+		{Path: "/r/c", Size: 1, Files: 1, Depth: 1},
+	}
+
+	kept := Filter(append([]Dir(nil), dirs...), 5)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "/r/b", kept[1].Path)
+	assert.Len(t, Filter(dirs, 100), 1, "the root is always kept")
+
+	require.NoError(t, Sort(dirs, SortPath))
+	assert.Equal(t, []string{"/r", "/r/a", "/r/b", "/r/c"}, paths(dirs))
+	require.NoError(t, Sort(dirs, SortFiles))
+	assert.Equal(t, []string{"/r", "/r/a", "/r/b", "/r/c"}, paths(dirs))
+	require.NoError(t, Sort(dirs, SortSize))
+	assert.Equal(t, []string{"/r", "/r/b", "/r/a", "/r/c"}, paths(dirs))
+	assert.ErrorIs(t, Sort(dirs, "age"), ErrUnknownSort)
+}
+
+func TestFilterAndSortEntries(t *testing.T) {
+	dirs := []Dir{
+		{Path: "/r", Files: 10, Dirs: 3, Entries: 2},
+		{Path: "/r/a", Files: 1, Entries: 1, Depth: 1},
+		{Path: "/r/b", Files: 7, Dirs: 2, Entries: 3, Depth: 1},
+		{Path: "/r/c", Files: 8, Entries: 8, Depth: 1},
+	}
+
+	require.NoError(t, Sort(dirs, SortEntries))
+	assert.Equal(t, []string{"/r/c", "/r/b", "/r", "/r/a"}, paths(dirs))
+	require.NoError(t, Sort(dirs, SortInodes))
+	assert.Equal(t, []string{"/r", "/r/b", "/r/c", "/r/a"}, paths(dirs))
+
+	kept := FilterEntries(dirs, 3)
+	assert.Equal(t, []string{"/r", "/r/b", "/r/c"}, paths(kept), "the root is always kept")
+}
+
+func paths(dirs []Dir) []string {
+	out := make([]string, len(dirs))
+	for i, d := range dirs {
+		out[i] = d.Path
+	}
+	return out
+}
+
+func TestWrite(t *testing.T) {
+	report := Report{Root: "/r", Source: SourceIndex, Dirs: []Dir{{Path: "/r", Size: 2048, Files: 3}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, output.ReportText, report))
+	assert.Contains(t, buf.String(), "DIRECTORY")
+	assert.Contains(t, buf.String(), "2.0 KiB")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, Report{Root: "/r", Source: SourceScan}))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "scan", decoded["source"])
+	assert.Equal(t, []any{}, decoded["dirs"])
+
+	assert.ErrorIs(t, Write(&buf, "csv", report), output.ErrUnknownReportFormat)
+}
+
+func TestWriteInodes(t *testing.T) {
+	report := Report{
+		Root: "/r", Source: SourceIndex, Inodes: 1000, FreeInodes: 250,
+		Dirs: []Dir{{Path: "/r", Files: 12000, Dirs: 345, Entries: 2}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteInodes(&buf, output.ReportText, report))
+	assert.Contains(t, buf.String(), "750 of 1,000 inodes used (75%)")
+	assert.Contains(t, buf.String(), "ENTRIES")
+	assert.Contains(t, buf.String(), "12,345")
+
+	buf.Reset()
+	require.NoError(t, WriteInodes(&buf, output.ReportJSON, report))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, float64(250), decoded["free_inodes"])
+
+	assert.ErrorIs(t, WriteInodes(&buf, "csv", report), output.ErrUnknownReportFormat)
+}
@@ -0,0 +1,82 @@
+package du
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Sources of the totals.
+const (
+	SourceIndex = "index"
+	SourceScan  = "scan"
+)
+
+// Report is the result of 'sweep du' and 'sweep inodes'.
+type Report struct {
+	Root   string `json:"root"`
+	Source string `json:"source"` // SourceIndex or SourceScan
+	Dirs   []Dir  `json:"dirs"`
+
+	// Inode usage of the volume holding the root, set by 'sweep inodes'
+	// when the volume has a fixed number of inodes.
+	Inodes     int64 `json:"inodes,omitempty"`
+	FreeInodes int64 `json:"free_inodes,omitempty"`
+}
+
+// Write renders a report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteInodes renders a report of entry counts in the given format.
+func WriteInodes(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteInodesText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders the directories as a table in report order.
+func WriteText(w io.Writer, r Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "SIZE\tFILES\t\tDIRECTORY")
+	for _, d := range r.Dirs {
+		fmt.Fprintf(tw, "%s\t%d\t\t%s\n", types.FormatSize(d.Size), d.Files, d.Path)
+	}
+	return tw.Flush()
+}
+
+// WriteInodesText renders the directories' entry and inode counts as a
+// table in report order, after the volume's inode usage when known.
+func WriteInodesText(w io.Writer, r Report) error {
+	if r.Inodes > 0 {
+		used := r.Inodes - r.FreeInodes
+		fmt.Fprintf(w, "Volume: %s of %s inodes used (%.0f%%)\n\n",
+			humanize.Comma(used), humanize.Comma(r.Inodes), float64(used)/float64(r.Inodes)*100)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ENTRIES\tINODES\tSIZE\t\tDIRECTORY")
+	for _, d := range r.Dirs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\t%s\n", humanize.Comma(d.Entries), humanize.Comma(d.Inodes()), types.FormatSize(d.Size), d.Path)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON renders the report as JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Dirs == nil {
+		r.Dirs = []Dir{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,358 @@
+// Package filetype classifies files for display and filtering. Types come
+// from the file's content where it has a known signature, so a video
+// renamed to .bak is still a video, and from the extension otherwise.
+package filetype
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Unknown is the type of files that are neither recognized by content nor
+// by extension.
+const Unknown = "File"
+
+// headerSize is how much of a file is read to recognize its content; tar
+// headers are the deepest signature, at offset 257.
+const headerSize = 512
+
+// extensions maps lowercase file extensions to human-readable types.
+var extensions = map[string]string{
+	// Programming languages
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".ts":    "TypeScript",
+	".rs":    "Rust",
+	".c":     "C",
+	".h":     "C",
+	".cpp":   "C++",
+	".cc":    "C++",
+	".cxx":   "C++",
+	".hpp":   "C++",
+	".java":  "Java",
+	".rb":    "Ruby",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zsh":   "Shell",
+	".fish":  "Shell",
+	".php":   "PHP",
+	".swift": "Swift",
+	".kt":    "Kotlin",
+	".scala": "Scala",
+	".cs":    "C#",
+
+	// Web
+	".html":   "HTML",
+	".htm":    "HTML",
+	".css":    "CSS",
+	".jsx":    "JSX",
+	".tsx":    "TSX",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+
+	// Data/Config
+	".json": "JSON",
+	".yaml": "YAML",
+	".yml":  "YAML",
+	".toml": "TOML",
+	".xml":  "XML",
+	".csv":  "CSV",
+
+	// Documentation
+	".md":       "Markdown",
+	".markdown": "Markdown",
+	".txt":      "Text",
+	".pdf":      "PDF",
+	".doc":      "Document",
+	".docx":     "Document",
+	".xls":      "Document",
+	".xlsx":     "Document",
+	".ppt":      "Document",
+	".pptx":     "Document",
+	".odt":      "Document",
+	".ods":      "Document",
+	".odp":      "Document",
+	".rtf":      "Document",
+	".epub":     "Document",
+
+	// Logs
+	".log":  "Log",
+	".logs": "Log",
+
+	// Images
+	".png":  "Image",
+	".jpg":  "Image",
+	".jpeg": "Image",
+	".gif":  "Image",
+	".svg":  "Image",
+	".webp": "Image",
+	".bmp":  "Image",
+	".ico":  "Image",
+	".tiff": "Image",
+	".tif":  "Image",
+	".heic": "Image",
+	".heif": "Image",
+	".raw":  "Image",
+
+	// Video
+	".mp4":  "Video",
+	".mov":  "Video",
+	".avi":  "Video",
+	".mkv":  "Video",
+	".webm": "Video",
+	".wmv":  "Video",
+	".flv":  "Video",
+	".m4v":  "Video",
+	".mpeg": "Video",
+	".mpg":  "Video",
+
+	// Audio
+	".mp3":  "Audio",
+	".wav":  "Audio",
+	".ogg":  "Audio",
+	".flac": "Audio",
+	".aac":  "Audio",
+	".wma":  "Audio",
+	".m4a":  "Audio",
+	".opus": "Audio",
+	".aiff": "Audio",
+	".alac": "Audio",
+
+	// Archives
+	".zip":  "Archive",
+	".tar":  "Archive",
+	".gz":   "Archive",
+	".tgz":  "Archive",
+	".rar":  "Archive",
+	".7z":   "Archive",
+	".bz2":  "Archive",
+	".tbz2": "Archive",
+	".xz":   "Archive",
+	".zst":  "Archive",
+
+	// Executables and libraries
+	".exe":   "Executable",
+	".dll":   "Library",
+	".so":    "Library",
+	".dylib": "Library",
+	".a":     "Library",
+	".wasm":  "WebAssembly",
+
+	// Database
+	".db":      "Database",
+	".sqlite":  "Database",
+	".sqlite3": "Database",
+
+	// Binary
+	".bin": "Binary",
+}
+
+// groups maps types to the filter type groups (--type) they belong to.
+var groups = map[string]string{
+	"Video":    "video",
+	"Audio":    "audio",
+	"Image":    "image",
+	"Archive":  "archive",
+	"PDF":      "document",
+	"Document": "document",
+	"Text":     "document",
+	"Log":      "log",
+
+	"Go":         "code",
+	"Python":     "code",
+	"JavaScript": "code",
+	"TypeScript": "code",
+	"Rust":       "code",
+	"C":          "code",
+	"C++":        "code",
+	"Java":       "code",
+	"Ruby":       "code",
+	"Shell":      "code",
+	"PHP":        "code",
+	"Swift":      "code",
+	"Kotlin":     "code",
+	"Scala":      "code",
+	"C#":         "code",
+}
+
+// FromExtension returns the type of a file based on its extension, or
+// Unknown.
+func FromExtension(path string) string {
+	if t, ok := extensions[strings.ToLower(filepath.Ext(path))]; ok {
+		return t
+	}
+	return Unknown
+}
+
+// signature is a byte pattern at a fixed offset in a file's header.
+type signature struct {
+	offset int
+	magic  []byte
+	typ    string
+}
+
+// signatures are checked in order; the first match wins.
+var signatures = []signature{
+	// Images
+	{0, []byte("\x89PNG\r\n\x1a\n"), "Image"},
+	{0, []byte("\xff\xd8\xff"), "Image"},
+	{0, []byte("GIF87a"), "Image"},
+	{0, []byte("GIF89a"), "Image"},
+	{0, []byte("II*\x00"), "Image"},
+	{0, []byte("MM\x00*"), "Image"},
+
+	// Video
+	{0, []byte("\x1a\x45\xdf\xa3"), "Video"}, // Matroska and WebM
+	{0, []byte("FLV\x01"), "Video"},
+	{0, []byte("\x00\x00\x01\xba"), "Video"}, // MPEG program stream
+
+	// Audio
+	{0, []byte("ID3"), "Audio"},
+	{0, []byte("fLaC"), "Audio"},
+	{0, []byte("OggS"), "Audio"},
+	{0, []byte("\xff\xfb"), "Audio"}, // MP3 frame without ID3 tag
+
+	// Archives
+	{0, []byte("PK\x03\x04"), "Archive"},
+	{0, []byte("\x1f\x8b"), "Archive"},
+	{0, []byte("BZh"), "Archive"},
+	{0, []byte("\xfd7zXZ\x00"), "Archive"},
+	{0, []byte("7z\xbc\xaf\x27\x1c"), "Archive"},
+	{0, []byte("Rar!\x1a\x07"), "Archive"},
+	{0, []byte("\x28\xb5\x2f\xfd"), "Archive"}, // Zstandard
+	{257, []byte("ustar"), "Archive"},
+
+	// Documents
+	{0, []byte("%PDF-"), "PDF"},
+
+	// Executables
+	{0, []byte("\x7fELF"), "Executable"},
+	{0, []byte("MZ"), "Executable"},
+	{0, []byte("\xcf\xfa\xed\xfe"), "Executable"}, // Mach-O 64-bit
+	{0, []byte("\xce\xfa\xed\xfe"), "Executable"}, // Mach-O 32-bit
+	{0, []byte("\xca\xfe\xba\xbe"), "Executable"}, // Mach-O universal
+	{0, []byte("\x00asm"), "WebAssembly"},
+
+	// Database
+	{0, []byte("SQLite format 3\x00"), "Database"},
+}
+
+// FromContent returns the type of a file based on the first bytes of its
+// content, or "" if they aren't recognized.
+func FromContent(header []byte) string {
+	// RIFF and ISO base media files carry their kind after a size field
+	if len(header) >= 12 && bytes.HasPrefix(header, []byte("RIFF")) {
+		switch string(header[8:12]) {
+		case "AVI ":
+			return "Video"
+		case "WAVE":
+			return "Audio"
+		case "WEBP":
+			return "Image"
+		}
+	}
+	if len(header) >= 12 && string(header[4:8]) == "ftyp" {
+		switch brand := string(header[8:12]); brand {
+		case "M4A ", "M4B ", "M4P ":
+			return "Audio"
+		case "heic", "heix", "mif1", "msf1", "avif":
+			return "Image"
+		default:
+			return "Video"
+		}
+	}
+
+	for _, s := range signatures {
+		end := s.offset + len(s.magic)
+		if len(header) >= end && bytes.Equal(header[s.offset:end], s.magic) {
+			return s.typ
+		}
+	}
+	return ""
+}
+
+// Sniff reads the start of a file and returns its type, or "" if the file
+// can't be read or its content isn't recognized. Only regular files are
+// read; opening a FIFO would block.
+func Sniff(path string) string {
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return FromContent(header[:n])
+}
+
+// Detect returns the type of a file, preferring its content over its
+// extension. Containers such as zip and ELF also hold documents, packages,
+// and libraries, so those signatures only decide the type of files whose
+// extension isn't known.
+func Detect(path string) string {
+	byExt := FromExtension(path)
+	switch byContent := Sniff(path); {
+	case byContent == "":
+		return byExt
+	case byExt != Unknown && (byContent == "Archive" || byContent == "Executable"):
+		return byExt
+	default:
+		return byContent
+	}
+}
+
+// Group returns the filter type group (video, audio, image, archive,
+// document, code, or log) of a type, or "" if it isn't in one.
+func Group(typ string) string {
+	return groups[typ]
+}
+
+// cacheKey identifies a version of a file.
+type cacheKey struct {
+	path    string
+	size    int64
+	modTime int64
+}
+
+// Cache remembers detected types so views that render often don't read
+// the same files again. A file is detected again when its size or
+// modification time changes. The zero value is ready to use and safe for
+// concurrent use.
+type Cache struct {
+	mu    sync.Mutex
+	types map[cacheKey]string
+}
+
+// Detect returns the type of a file, from the cache if it was detected
+// before.
+func (c *Cache) Detect(path string, size int64, modTime time.Time) string {
+	key := cacheKey{path: path, size: size, modTime: modTime.UnixNano()}
+	c.mu.Lock()
+	typ, ok := c.types[key]
+	c.mu.Unlock()
+	if ok {
+		return typ
+	}
+
+	typ = Detect(path)
+	c.mu.Lock()
+	if c.types == nil {
+		c.types = make(map[cacheKey]string)
+	}
+	c.types[key] = typ
+	c.mu.Unlock()
+	return typ
+}
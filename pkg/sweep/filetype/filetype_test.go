@@ -0,0 +1,128 @@
+package filetype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mp4Header is the start of an MP4 file: a box size, then the ftyp box
+// with the isom brand.
+var mp4Header = []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00isomiso2avc1mp41")
+
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, content, 0o644))
+	return path
+}
+
+func TestFromExtension(t *testing.T) {
+	tests := map[string]string{
+		"/p/main.go":     "Go",
+		"/p/MOVIE.MKV":   "Video",
+		"/p/a.tar.gz":    "Archive",
+		"/p/report.docx": "Document",
+		"/p/app.log":     "Log",
+		"/p/data.bin":    "Binary",
+		"/p/unknown.xyz": Unknown,
+		"/p/noextension": Unknown,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, FromExtension(path), path)
+	}
+}
+
+func TestFromContent(t *testing.T) {
+	tar := make([]byte, 512)
+	copy(tar[257:], "ustar")
+
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"mp4", mp4Header, "Video"},
+		{"quicktime", []byte("\x00\x00\x00\x14ftypqt  "), "Video"},
+		{"m4a", []byte("\x00\x00\x00\x1cftypM4A "), "Audio"},
+		{"heic", []byte("\x00\x00\x00\x18ftypheic"), "Image"},
+		{"mkv", []byte("\x1a\x45\xdf\xa3\x01\x00"), "Video"},
+		{"avi", []byte("RIFF\x00\x00\x00\x00AVI LIST"), "Video"},
+		{"wav", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), "Audio"},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "Image"},
+		{"mp3", []byte("ID3\x04\x00"), "Audio"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00"), "Image"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0"), "Image"},
+		{"zip", []byte("PK\x03\x04\x14\x00"), "Archive"},
+		{"gzip", []byte("\x1f\x8b\x08\x00"), "Archive"},
+		{"tar", tar, "Archive"},
+		{"pdf", []byte("%PDF-1.7\n"), "PDF"},
+		{"elf", []byte("\x7fELF\x02\x01"), "Executable"},
+		{"wasm", []byte("\x00asm\x01\x00"), "WebAssembly"},
+		{"sqlite", []byte("SQLite format 3\x00"), "Database"},
+		{"text", []byte("hello, world\n"), ""},
+		{"short", []byte("RI"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromContent(tt.header))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"movie.bak", mp4Header, "Video"},           // Content beats a misleading extension
+		{"movie", mp4Header, "Video"},               // and a missing one
+		{"notes.txt", []byte("plain text"), "Text"}, // Unrecognized content uses the extension
+		{"report.docx", []byte("PK\x03\x04\x14\x00"), "Document"},
+		{"libfoo.so", []byte("\x7fELF\x02\x01"), "Library"},
+		{"download.part", []byte("PK\x03\x04\x14\x00"), "Archive"},
+		{"data.xyz", []byte("\x00\x01\x02"), Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Detect(writeFile(t, tt.name, tt.content)))
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		assert.Equal(t, "Video", Detect("/nonexistent/movie.mp4"))
+	})
+	t.Run("directory", func(t *testing.T) {
+		assert.Equal(t, Unknown, Detect(t.TempDir()))
+	})
+}
+
+func TestGroup(t *testing.T) {
+	assert.Equal(t, "video", Group("Video"))
+	assert.Equal(t, "document", Group("PDF"))
+	assert.Equal(t, "code", Group("Go"))
+	assert.Equal(t, "", Group("JSON"))
+	assert.Equal(t, "", Group(Unknown))
+}
+
+func TestCache(t *testing.T) {
+	path := writeFile(t, "clip.bak", mp4Header)
+	mod := time.Now()
+
+	var c Cache
+	assert.Equal(t, "Video", c.Detect(path, 100, mod))
+
+	// The same version is served from the cache, even if the file changed
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.7\n"), 0o644))
+	assert.Equal(t, "Video", c.Detect(path, 100, mod))
+
+	// A new size or modification time detects again
+	assert.Equal(t, "PDF", c.Detect(path, 9, mod))
+	assert.Equal(t, "PDF", c.Detect(path, 100, mod.Add(time.Second)))
+}
@@ -2,11 +2,13 @@ package filter
 
 import (
 	"cmp"
+	"path/filepath"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/gobwas/glob"
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
 )
 
 // Filter defines criteria for filtering, sorting, and limiting file lists.
@@ -14,6 +16,10 @@ type Filter struct {
 	// MinSize is the minimum file size in bytes. Files smaller are excluded.
 	MinSize int64
 
+	// MaxSize is the maximum file size in bytes. Files larger are excluded.
+	// 0 means no maximum.
+	MaxSize int64
+
 	// Include contains glob patterns. If non-empty, files must match at least one.
 	Include []string
 
@@ -24,12 +30,21 @@ type Filter struct {
 	// If non-empty, only files with matching extensions are included.
 	Extensions []string
 
+	// Types contains type groups (e.g., "video") whose files are included
+	// even without a matching extension, when their content is of that
+	// type. Files are only read if they pass every other check.
+	Types []string
+
 	// OlderThan excludes files modified more recently than this duration ago.
 	OlderThan time.Duration
 
 	// NewerThan excludes files modified longer ago than this duration.
 	NewerThan time.Duration
 
+	// Paths restricts results to files that are, or are beneath, one of these
+	// paths. If empty, no path restriction is applied.
+	Paths []string
+
 	// MaxDepth limits how deep into the directory tree to include files.
 	// 0 means unlimited.
 	MaxDepth int
@@ -42,6 +57,9 @@ type Filter struct {
 
 	// Limit is the maximum number of files to return. 0 means unlimited.
 	Limit int
+
+	// compiled caches Include and Exclude compiled, for Match
+	compiled atomic.Pointer[compiledPatterns]
 }
 
 // Option is a functional option for configuring a Filter.
@@ -88,6 +106,17 @@ func WithMinSize(minSize int64) Option {
 	}
 }
 
+// WithMaxSize sets the maximum file size in bytes.
+// If maxSize <= 0, it is set to 0 (no maximum).
+func WithMaxSize(maxSize int64) Option {
+	return func(f *Filter) {
+		if maxSize < 0 {
+			maxSize = 0
+		}
+		f.MaxSize = maxSize
+	}
+}
+
 // WithInclude sets the include glob patterns.
 // If any patterns are specified, files must match at least one to be included.
 func WithInclude(patterns ...string) Option {
@@ -104,8 +133,9 @@ func WithExclude(patterns ...string) Option {
 	}
 }
 
-// WithExtensions sets the file extensions to include.
-// Extensions are normalized: lowercase and prefixed with "." if missing.
+// WithExtensions sets the file extensions to include, replacing any type
+// groups. Extensions are normalized: lowercase and prefixed with "." if
+// missing.
 func WithExtensions(extensions ...string) Option {
 	return func(f *Filter) {
 		normalized := make([]string, 0, len(extensions))
@@ -117,20 +147,36 @@ func WithExtensions(extensions ...string) Option {
 			normalized = append(normalized, ext)
 		}
 		f.Extensions = normalized
+		f.Types = nil
 	}
 }
 
-// WithTypeGroups expands type group names to their extensions and sets them.
+// WithTypeGroups expands type group names to their extensions and sets them,
+// and also matches files whose content is of one of the groups.
 // Unknown group names are silently ignored.
 func WithTypeGroups(groups ...string) Option {
 	return func(f *Filter) {
-		var extensions []string
+		var extensions, known []string
 		for _, group := range groups {
 			if exts, ok := TypeGroups[group]; ok {
 				extensions = append(extensions, exts...)
+				known = append(known, group)
 			}
 		}
 		f.Extensions = extensions
+		f.Types = known
+	}
+}
+
+// WithPaths restricts results to files at or beneath the given paths.
+// Paths are cleaned before use.
+func WithPaths(paths ...string) Option {
+	return func(f *Filter) {
+		cleaned := make([]string, 0, len(paths))
+		for _, p := range paths {
+			cleaned = append(cleaned, filepath.Clean(p))
+		}
+		f.Paths = cleaned
 	}
 }
 
@@ -176,13 +222,14 @@ func WithSortDescending(desc bool) Option {
 }
 
 // Match returns true if the file matches all filter criteria.
-// It checks MinSize, Extensions, OlderThan, NewerThan, MaxDepth,
-// Exclude patterns, and Include patterns in that order.
+// It checks MinSize, MaxSize, Extensions, MaxDepth, OlderThan, NewerThan, Paths,
+// Exclude patterns, Include patterns, and content Types in that order.
 func (f *Filter) Match(fi FileInfo) bool {
 	if !f.matchSize(fi) {
 		return false
 	}
-	if !f.matchExtension(fi) {
+	extMatched := f.matchExtension(fi)
+	if !extMatched && len(f.Types) == 0 {
 		return false
 	}
 	if !f.matchDepth(fi) {
@@ -191,15 +238,24 @@ func (f *Filter) Match(fi FileInfo) bool {
 	if !f.matchAge(fi) {
 		return false
 	}
+	if !f.matchPaths(fi) {
+		return false
+	}
 	if !f.matchPatterns(fi) {
 		return false
 	}
+	if !extMatched && !f.matchType(fi) {
+		return false
+	}
 	return true
 }
 
-// matchSize checks if the file meets the minimum size requirement.
+// matchSize checks if the file is within the size bounds.
 func (f *Filter) matchSize(fi FileInfo) bool {
-	return f.MinSize <= 0 || fi.Size >= f.MinSize
+	if f.MinSize > 0 && fi.Size < f.MinSize {
+		return false
+	}
+	return f.MaxSize <= 0 || fi.Size <= f.MaxSize
 }
 
 // matchExtension checks if the file has an allowed extension.
@@ -216,6 +272,16 @@ func (f *Filter) matchExtension(fi FileInfo) bool {
 	return false
 }
 
+// matchType checks if the file's content is in one of the type groups,
+// for files whose extension doesn't say so.
+func (f *Filter) matchType(fi FileInfo) bool {
+	typ := fi.Type
+	if typ == "" {
+		typ = filetype.Detect(fi.Path)
+	}
+	return slices.Contains(f.Types, filetype.Group(typ))
+}
+
 // matchDepth checks if the file is within the maximum depth.
 func (f *Filter) matchDepth(fi FileInfo) bool {
 	return f.MaxDepth <= 0 || fi.Depth <= f.MaxDepth
@@ -244,35 +310,39 @@ func (f *Filter) matchAge(fi FileInfo) bool {
 	return true
 }
 
+// matchPaths checks if the file is at or beneath one of the allowed paths.
+func (f *Filter) matchPaths(fi FileInfo) bool {
+	if len(f.Paths) == 0 {
+		return true
+	}
+	for _, p := range f.Paths {
+		if fi.Path == p || strings.HasPrefix(fi.Path, strings.TrimSuffix(p, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchPatterns checks if the file matches include/exclude patterns.
 func (f *Filter) matchPatterns(fi FileInfo) bool {
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		return true
+	}
+	p := f.patterns()
+
 	// Check exclude patterns
-	if f.matchesAnyPattern(fi.Path, f.Exclude) {
+	if p.excludeGlobs.Match(fi.Path) {
 		return false
 	}
 
 	// Check include patterns (if any specified, must match at least one)
-	if len(f.Include) > 0 && !f.matchesAnyPattern(fi.Path, f.Include) {
+	if len(f.Include) > 0 && !p.includeGlobs.Match(fi.Path) {
 		return false
 	}
 
 	return true
 }
 
-// matchesAnyPattern returns true if the path matches any of the glob patterns.
-func (f *Filter) matchesAnyPattern(path string, patterns []string) bool {
-	for _, pattern := range patterns {
-		g, err := glob.Compile(pattern, '/')
-		if err != nil {
-			continue // Skip invalid patterns
-		}
-		if g.Match(path) {
-			return true
-		}
-	}
-	return false
-}
-
 // Sort returns a sorted copy of the files slice based on the filter's sort settings.
 // The original slice is not modified.
 func (f *Filter) Sort(files []FileInfo) []FileInfo {
@@ -302,7 +372,12 @@ func (f *Filter) Sort(files []FileInfo) []FileInfo {
 		}
 
 		if f.SortDescending {
-			return -result
+			result = -result
+		}
+		if result == 0 {
+			// Ties in path order keep the order, and so the daemon's
+			// result pages, stable
+			return cmp.Compare(a.Path, b.Path)
 		}
 		return result
 	})
@@ -1,6 +1,8 @@
 package filter
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -173,6 +175,41 @@ func TestWithTypeGroups_InvalidGroup(t *testing.T) {
 	}
 }
 
+func TestWithTypeGroups_MatchesContent(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) FileInfo {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return FileInfo{Path: path, Name: name, Ext: filepath.Ext(name), Size: int64(len(content))}
+	}
+	mp4 := "\x00\x00\x00\x20ftypisom\x00\x00\x02\x00isomiso2avc1mp41"
+
+	f := New(WithTypeGroups("video"))
+	tests := []struct {
+		fi   FileInfo
+		want bool
+	}{
+		{write("movie.bak", mp4), true},      // Video content with a misleading extension
+		{write("notes.bak", "hello"), false}, // Unrecognized content
+		{write("clip.mp4", "not a video"), true},
+		{FileInfo{Path: "/nonexistent/a.bak", Ext: ".bak"}, false},
+		{FileInfo{Path: "/nonexistent/b.bak", Ext: ".bak", Type: "Video"}, true}, // Type given, not read
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.fi); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.fi.Path, got, tt.want)
+		}
+	}
+
+	// Content is only checked for files that pass the other criteria
+	f = New(WithTypeGroups("video"), WithMinSize(1<<20))
+	if f.Match(tests[0].fi) {
+		t.Error("expected a small file to be rejected by size")
+	}
+}
+
 func TestWithOlderThan(t *testing.T) {
 	dur := 24 * time.Hour
 	f := New(WithOlderThan(dur))
@@ -308,6 +345,31 @@ func TestMatch_MinSize(t *testing.T) {
 	}
 }
 
+func TestMatch_MaxSize(t *testing.T) {
+	f := New(WithMinSize(1024), WithMaxSize(4096))
+
+	tests := []struct {
+		name string
+		size int64
+		want bool
+	}{
+		{name: "within bounds", size: 2048, want: true},
+		{name: "at maximum", size: 4096, want: true},
+		{name: "above maximum", size: 4097, want: false},
+		{name: "below minimum", size: 512, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi := FileInfo{Size: tt.size}
+			got := f.Match(fi)
+			if got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMatch_Extensions(t *testing.T) {
 	f := New(WithExtensions(".mp4", ".mkv"))
 
@@ -461,6 +523,31 @@ func TestMatch_Include(t *testing.T) {
 	}
 }
 
+func TestMatch_Paths(t *testing.T) {
+	f := New(WithPaths("/home/user/proj", "/tmp/one.iso"))
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "exact file", path: "/tmp/one.iso", want: true},
+		{name: "beneath directory", path: "/home/user/proj/build/out.bin", want: true},
+		{name: "sibling with shared prefix", path: "/home/user/project/out.bin", want: false},
+		{name: "unrelated", path: "/var/log/syslog", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi := FileInfo{Path: tt.path}
+			got := f.Match(fi)
+			if got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMatch_CombinedFilters(t *testing.T) {
 	now := time.Now()
 	f := New(
@@ -766,3 +853,53 @@ func TestApply_AllFiltered(t *testing.T) {
 		t.Errorf("Apply: got %d results, want 0", len(result))
 	}
 }
+
+func TestMatch_PatternsChangedAfterUse(t *testing.T) {
+	f := New(WithExclude("**/*.tmp"))
+	fi := FileInfo{Path: "/home/user/cache.tmp"}
+	if f.Match(fi) {
+		t.Fatalf("Match(%q) = true, want false", fi.Path)
+	}
+
+	// The compiled patterns follow the field
+	f.Exclude = []string{"**/*.log"}
+	if !f.Match(fi) {
+		t.Errorf("Match(%q) = false after changing Exclude, want true", fi.Path)
+	}
+}
+
+func TestNameGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "node_modules", name: "node_modules", want: true},
+		{pattern: "node_modules", name: "node_modules_backup", want: false},
+		{pattern: "*.tmp", name: "cache.tmp", want: true},
+		{pattern: "*.tmp", name: ".tmp", want: true},
+		{pattern: "*.tmp", name: "cache.tmp.gz", want: false},
+		{pattern: "*.tmp", name: "dir/cache.tmp", want: false},
+		{pattern: "cache-?.bin", name: "cache-1.bin", want: true},
+		{pattern: "[a-c]*.iso", name: "disc.iso", want: false},
+		{pattern: `\*.tmp`, name: "*.tmp", want: true},
+		{pattern: `\*.tmp`, name: "a.tmp", want: false},
+	}
+	for _, tt := range tests {
+		g, err := CompileNameGlob(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompileNameGlob(%q) error = %v", tt.pattern, err)
+		}
+		want, _ := filepath.Match(tt.pattern, tt.name)
+		if got := g.Match(tt.name); got != tt.want || got != want {
+			t.Errorf("CompileNameGlob(%q).Match(%q) = %v, want %v (filepath.Match %v)", tt.pattern, tt.name, got, tt.want, want)
+		}
+	}
+
+	if _, err := CompileNameGlob("[a-"); err == nil {
+		t.Error("CompileNameGlob() accepted a malformed pattern")
+	}
+	if got := CompileNameGlobs([]string{"*.tmp", "[a-"}); len(got) != 1 || got[0].String() != "*.tmp" {
+		t.Errorf("CompileNameGlobs() = %v, want only *.tmp", got)
+	}
+}
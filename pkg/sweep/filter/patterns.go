@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// Patterns is a set of compiled glob patterns, so matching many paths
+// against them parses each pattern once rather than once per path.
+type Patterns []glob.Glob
+
+// CompilePatterns compiles glob patterns that match whole paths, with ** to
+// cross directories. Invalid patterns are skipped, as they match nothing.
+// Patterns and paths are matched with forward slashes, so on Windows a
+// pattern may use either separator.
+func CompilePatterns(patterns []string) Patterns {
+	compiled := make(Patterns, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(filepath.ToSlash(pattern), '/')
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, g)
+	}
+	return compiled
+}
+
+// Match reports whether path matches any of the patterns.
+func (p Patterns) Match(path string) bool {
+	path = filepath.ToSlash(path)
+	for _, g := range p {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compiledPatterns holds a Filter's compiled Include and Exclude patterns
+// with the lists they were compiled from, so changes to the fields are
+// noticed.
+type compiledPatterns struct {
+	include, exclude []string
+	includeGlobs     Patterns
+	excludeGlobs     Patterns
+}
+
+// patterns returns the filter's compiled patterns, compiling them on first
+// use and again if Include or Exclude has changed since.
+func (f *Filter) patterns() *compiledPatterns {
+	if c := f.compiled.Load(); c != nil && slices.Equal(c.include, f.Include) && slices.Equal(c.exclude, f.Exclude) {
+		return c
+	}
+	c := &compiledPatterns{
+		include:      slices.Clone(f.Include),
+		exclude:      slices.Clone(f.Exclude),
+		includeGlobs: CompilePatterns(f.Include),
+		excludeGlobs: CompilePatterns(f.Exclude),
+	}
+	f.compiled.Store(c)
+	return c
+}
+
+// NameGlob is a filepath.Match pattern for base names that has been checked
+// once, so the common literal ("node_modules") and suffix ("*.tmp") forms
+// match by comparing strings instead of interpreting the pattern per name.
+type NameGlob struct {
+	pattern string
+	literal string // The name, or the suffix after a leading *
+	kind    nameGlobKind
+}
+
+type nameGlobKind int
+
+const (
+	nameGlobLiteral nameGlobKind = iota
+	nameGlobSuffix
+	nameGlobGeneral
+)
+
+// CompileNameGlob checks a filepath.Match pattern, returning
+// filepath.ErrBadPattern if it is malformed.
+func CompileNameGlob(pattern string) (NameGlob, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return NameGlob{}, err
+	}
+	const meta = `*?[\`
+	switch {
+	case !strings.ContainsAny(pattern, meta):
+		return NameGlob{pattern: pattern, literal: pattern, kind: nameGlobLiteral}, nil
+	case strings.HasPrefix(pattern, "*") && !strings.ContainsAny(pattern[1:], meta):
+		return NameGlob{pattern: pattern, literal: pattern[1:], kind: nameGlobSuffix}, nil
+	default:
+		return NameGlob{pattern: pattern, kind: nameGlobGeneral}, nil
+	}
+}
+
+// CompileNameGlobs compiles patterns for base names, skipping malformed
+// ones, which match nothing.
+func CompileNameGlobs(patterns []string) []NameGlob {
+	globs := make([]NameGlob, 0, len(patterns))
+	for _, pattern := range patterns {
+		if g, err := CompileNameGlob(pattern); err == nil {
+			globs = append(globs, g)
+		}
+	}
+	return globs
+}
+
+// Match reports whether name matches the pattern, like filepath.Match.
+func (g NameGlob) Match(name string) bool {
+	switch g.kind {
+	case nameGlobLiteral:
+		return name == g.literal
+	case nameGlobSuffix:
+		// * doesn't match the separator
+		return strings.HasSuffix(name, g.literal) && !strings.Contains(name[:len(name)-len(g.literal)], string(filepath.Separator))
+	default:
+		matched, _ := filepath.Match(g.pattern, name)
+		return matched
+	}
+}
+
+// String returns the pattern.
+func (g NameGlob) String() string {
+	return g.pattern
+}
@@ -117,4 +117,8 @@ type FileInfo struct {
 
 	// Depth is the directory depth relative to the scan root.
 	Depth int
+
+	// Type is the file's type as returned by filetype.Detect. If empty and
+	// needed for type group matching, it is detected from the file.
+	Type string
 }
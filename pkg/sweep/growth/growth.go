@@ -0,0 +1,222 @@
+// Package growth charts how the daemon's indexed roots grow over time, for
+// 'sweep stats'.
+package growth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/sizediff"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Point is the total size and file count of an indexed root at one time.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Size  int64     `json:"size"`
+	Files int64     `json:"files"`
+}
+
+// Series is the totals recorded for an indexed root, oldest first.
+type Series struct {
+	Root   string  `json:"root"`
+	Points []Point `json:"points"`
+}
+
+// First returns the oldest point, or a zero point if there are none.
+func (s Series) First() Point {
+	if len(s.Points) == 0 {
+		return Point{}
+	}
+	return s.Points[0]
+}
+
+// Last returns the latest point, or a zero point if there are none.
+func (s Series) Last() Point {
+	if len(s.Points) == 0 {
+		return Point{}
+	}
+	return s.Points[len(s.Points)-1]
+}
+
+// Sizes returns the total size at each point.
+func (s Series) Sizes() []int64 {
+	sizes := make([]int64, len(s.Points))
+	for i, p := range s.Points {
+		sizes[i] = p.Size
+	}
+	return sizes
+}
+
+// FileCounts returns the file count at each point.
+func (s Series) FileCounts() []int64 {
+	files := make([]int64, len(s.Points))
+	for i, p := range s.Points {
+		files[i] = p.Files
+	}
+	return files
+}
+
+// sparks are the bars of a sparkline, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a line of bars scaled from their smallest to
+// their largest. More values than width are grouped into width bars, each
+// showing the last value of its group, since values are running totals.
+// A width of 0 draws a bar per value.
+func Sparkline(values []int64, width int) string {
+	if width > 0 && len(values) > width {
+		sampled := make([]int64, width)
+		for i := range sampled {
+			sampled[i] = values[(i+1)*len(values)/width-1]
+		}
+		values = sampled
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) * int64(len(sparks)-1) / (hi - lo))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+// GraphWidth is the most bars WriteGraph draws per chart.
+const GraphWidth = 60
+
+// Write renders series in the given format, charting them in text when
+// graph is set.
+func Write(w io.Writer, format string, series []Series, graph bool) error {
+	text := WriteText
+	if graph {
+		text = WriteGraph
+	}
+	return output.WriteReport(w, format, series, output.ReportWriters[[]Series]{
+		output.ReportText: text,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// noStats is printed when no totals have been recorded.
+const noStats = "No index statistics recorded yet."
+
+// WriteText renders a table of each root's latest totals and how they
+// changed since the oldest recorded.
+func WriteText(w io.Writer, series []Series) error {
+	if !recorded(series) {
+		_, err := fmt.Fprintln(w, noStats)
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROOT\tSIZE\tCHANGE\tFILES\tCHANGE\tSINCE")
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		first, last := s.First(), s.Last()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Root,
+			types.FormatSize(last.Size), sizediff.FormatDelta(last.Size-first.Size),
+			formatCount(last.Files), formatCountDelta(last.Files-first.Files),
+			first.Time.Local().Format("2006-01-02"))
+	}
+	return tw.Flush()
+}
+
+// WriteGraph renders each root's size and file count as sparklines, with
+// the first and latest totals.
+func WriteGraph(w io.Writer, series []Series) error {
+	if !recorded(series) {
+		_, err := fmt.Fprintln(w, noStats)
+		return err
+	}
+	first := true
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(w)
+		}
+		first = false
+
+		from, to := s.First(), s.Last()
+		fmt.Fprintf(w, "%s (%d point(s), %s to %s)\n", s.Root, len(s.Points),
+			from.Time.Local().Format("2006-01-02 15:04"), to.Time.Local().Format("2006-01-02 15:04"))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  size\t%s\t%s → %s\t(%s)\n", Sparkline(s.Sizes(), GraphWidth),
+			types.FormatSize(from.Size), types.FormatSize(to.Size), sizediff.FormatDelta(to.Size-from.Size))
+		fmt.Fprintf(tw, "  files\t%s\t%s → %s\t(%s)\n", Sparkline(s.FileCounts(), GraphWidth),
+			formatCount(from.Files), formatCount(to.Files), formatCountDelta(to.Files-from.Files))
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recorded reports whether any series has a point.
+func recorded(series []Series) bool {
+	for _, s := range series {
+		if len(s.Points) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCount formats n with thousands separators, e.g. "1,234,567".
+func formatCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// formatCountDelta formats a file count change with its sign.
+func formatCountDelta(delta int64) string {
+	if delta < 0 {
+		return formatCount(delta)
+	}
+	return "+" + formatCount(delta)
+}
+
+// WriteJSON renders the series as a JSON array.
+func WriteJSON(w io.Writer, series []Series) error {
+	if series == nil {
+		series = []Series{}
+	}
+	for i := range series {
+		if series[i].Points == nil {
+			series[i].Points = []Point{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(series)
+}
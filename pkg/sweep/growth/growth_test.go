@@ -0,0 +1,75 @@
+package growth
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", Sparkline(nil, 10))
+	assert.Equal(t, "▁▁▁", Sparkline([]int64{5, 5, 5}, 0), "flat values sit at the bottom")
+	assert.Equal(t, "▁▄█", Sparkline([]int64{0, 50, 100}, 0))
+	assert.Equal(t, "█▁", Sparkline([]int64{100, 0}, 0))
+	assert.Equal(t, "▁█", Sparkline([]int64{0, 10, 20, 30}, 2), "each bar shows the last value of its group")
+}
+
+func TestFormatCount(t *testing.T) {
+	assert.Equal(t, "0", formatCount(0))
+	assert.Equal(t, "999", formatCount(999))
+	assert.Equal(t, "1,000", formatCount(1000))
+	assert.Equal(t, "1,234,567", formatCount(1234567))
+	assert.Equal(t, "-12,345", formatCount(-12345))
+	assert.Equal(t, "+12", formatCountDelta(12))
+	assert.Equal(t, "-1,200", formatCountDelta(-1200))
+}
+
+func TestWrite(t *testing.T) {
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local)
+	series := []Series{
+		{Root: "/data", Points: []Point{
+			{Time: day, Size: types.GiB, Files: 1000},
+			{Time: day.AddDate(0, 0, 1), Size: 2 * types.GiB, Files: 1500},
+			{Time: day.AddDate(0, 0, 2), Size: 3 * types.GiB, Files: 1200},
+		}},
+		{Root: "/empty"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "pretty", series, false))
+	out := buf.String()
+	assert.Contains(t, out, "/data")
+	assert.Contains(t, out, "3.0 GiB")
+	assert.Contains(t, out, "+2.0 GiB")
+	assert.Contains(t, out, "+200")
+	assert.Contains(t, out, "2026-01-10")
+	assert.NotContains(t, out, "/empty", "roots without totals are left out")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "pretty", series, true))
+	out = buf.String()
+	assert.Contains(t, out, "/data (3 point(s), 2026-01-10 12:00 to 2026-01-12 12:00)")
+	assert.Contains(t, out, "▁▄█")
+	assert.Contains(t, out, "▁█▃")
+	assert.Contains(t, out, "1,000 → 1,200")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, "pretty", []Series{{Root: "/empty"}}, true))
+	assert.Contains(t, buf.String(), noStats)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, series, false))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, []any{}, decoded[1]["points"], "empty lists are arrays, not null")
+
+	assert.ErrorIs(t, Write(&buf, "xml", series, false), output.ErrUnknownReportFormat)
+}
@@ -0,0 +1,78 @@
+// Package hook sends JSON payloads to the integrations users configure: a
+// webhook, POSTed the payload, or a shell command, run with the payload on
+// its standard input.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Timeout bounds each webhook request or command run.
+const Timeout = 30 * time.Second
+
+// Replaced in tests.
+var goos = runtime.GOOS
+
+// Post POSTs payload as JSON to url. Any status but 2xx is an error.
+func Post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// Exec runs command with the shell (cmd on Windows), with payload as JSON
+// on its standard input and env added to its environment. A failed
+// command's error includes what it printed.
+func Exec(ctx context.Context, command string, env []string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if goos == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), env...)
+	return Run(cmd)
+}
+
+// Run runs cmd, adding what it printed to its error if it fails.
+func Run(cmd *exec.Cmd) error {
+	out, err := cmd.CombinedOutput()
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPost(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["fail"] != "" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	if err := Post(context.Background(), srv.URL, map[string]string{"text": "hello"}); err != nil {
+		t.Fatalf("Post() failed: %v", err)
+	}
+	if got["text"] != "hello" {
+		t.Errorf("the webhook got %v", got)
+	}
+	if err := Post(context.Background(), srv.URL, map[string]string{"fail": "yes"}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Post() = %v, want the status", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	err := Exec(context.Background(), `read -r line; echo "$GREETING $line" >&2; exit 1`, []string{"GREETING=hi"}, map[string]int{"n": 1})
+	if err == nil || !strings.Contains(err.Error(), `hi {"n":1}`) {
+		t.Errorf("Exec() = %v, want the command's output", err)
+	}
+	if err := Exec(context.Background(), "cat > /dev/null", nil, nil); err != nil {
+		t.Errorf("Exec() = %v", err)
+	}
+}
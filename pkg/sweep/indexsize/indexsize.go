@@ -0,0 +1,139 @@
+// Package indexsize counts indexed files by size and recommends the
+// daemon's min_index_size from them, for 'sweep daemon tune'.
+package indexsize
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Bounds are the lower bounds of the size buckets files are counted in,
+// smallest first. Each bound but the first is a threshold to choose from.
+var Bounds = []int64{
+	0,
+	64 * types.KiB,
+	256 * types.KiB,
+	types.MiB,
+	5 * types.MiB,
+	10 * types.MiB,
+	50 * types.MiB,
+	100 * types.MiB,
+	500 * types.MiB,
+	types.GiB,
+}
+
+// BucketOf returns the index in Bounds of the bucket a file of size falls
+// in.
+func BucketOf(size int64) int {
+	return sort.Search(len(Bounds), func(i int) bool { return Bounds[i] > size }) - 1
+}
+
+// MaxIndexFiles is the most files Recommend puts in the large files index
+// when it has the choice. Each costs a store entry, written again whenever
+// the file changes, and large file queries read them all.
+const MaxIndexFiles = 100_000
+
+// Bucket counts the indexed files of at least MinSize and below the next
+// bucket's.
+type Bucket struct {
+	MinSize    int64 `json:"min_size"`
+	Files      int64 `json:"files"`
+	Bytes      int64 `json:"bytes"`
+	IndexBytes int64 `json:"index_bytes"` // Store bytes they take in the large files index
+}
+
+// Histogram is the daemon's indexed files by size.
+type Histogram struct {
+	Buckets      []Bucket `json:"buckets"` // In Bounds order
+	MinIndexSize int64    `json:"min_index_size"`
+	StoreSize    int64    `json:"store_size"`
+
+	// UnsizedFiles are files below MinIndexSize in roots indexed in
+	// aggregates mode, which are counted but not stored, so the buckets
+	// below MinIndexSize leave them out.
+	UnsizedFiles int64 `json:"unsized_files,omitempty"`
+}
+
+// Files returns the number of indexed files, including unsized ones.
+func (h Histogram) Files() int64 {
+	n := h.UnsizedFiles
+	for _, b := range h.Buckets {
+		n += b.Files
+	}
+	return n
+}
+
+// Option is what the large files index would hold with a threshold.
+type Option struct {
+	Threshold  int64 `json:"threshold"`
+	Files      int64 `json:"files"`
+	Bytes      int64 `json:"bytes"`
+	IndexBytes int64 `json:"index_bytes"`
+
+	// Partial is set when the threshold is below MinIndexSize and some
+	// files below it weren't sized, so the counts are too low.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Options returns the index each threshold in Bounds would give, smallest
+// threshold first.
+func (h Histogram) Options() []Option {
+	if len(h.Buckets) < 2 {
+		return nil
+	}
+	opts := make([]Option, len(h.Buckets)-1)
+	var files, bytes, indexBytes int64
+	for i := len(h.Buckets) - 1; i > 0; i-- {
+		b := h.Buckets[i]
+		files += b.Files
+		bytes += b.Bytes
+		indexBytes += b.IndexBytes
+		opts[i-1] = Option{
+			Threshold:  b.MinSize,
+			Files:      files,
+			Bytes:      bytes,
+			IndexBytes: indexBytes,
+			Partial:    h.UnsizedFiles > 0 && b.MinSize < h.MinIndexSize,
+		}
+	}
+	return opts
+}
+
+// Recommend returns the smallest threshold that keeps the large files
+// index within MaxIndexFiles, but no larger than maxSize, the smallest
+// file size queries ask for, so they are answered from the index in full.
+// When every threshold up to maxSize indexes more files than that, the
+// largest of them is returned. A maxSize of 0 leaves out that bound.
+func (h Histogram) Recommend(maxSize int64) int64 {
+	opts := h.Options()
+	if len(opts) == 0 {
+		return h.MinIndexSize
+	}
+	fallback := opts[0].Threshold
+	for _, o := range opts {
+		if maxSize > 0 && o.Threshold > maxSize {
+			break
+		}
+		fallback = o.Threshold
+		if !o.Partial && o.Files <= MaxIndexFiles {
+			return o.Threshold
+		}
+	}
+	return fallback
+}
+
+// ConfigSize formats a size as daemon.min_index_size takes it, in the
+// largest whole unit, e.g. "10MB".
+func ConfigSize(size int64) string {
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", types.GiB}, {"MB", types.MiB}, {"KB", types.KiB}} {
+		if size >= u.size && size%u.size == 0 {
+			return fmt.Sprintf("%d%s", size/u.size, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d", size)
+}
@@ -0,0 +1,67 @@
+package indexsize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// histogram returns a histogram with files[i] files in bucket i.
+func histogram(minIndexSize int64, files ...int64) Histogram {
+	h := Histogram{MinIndexSize: minIndexSize}
+	for i, bound := range Bounds {
+		b := Bucket{MinSize: bound}
+		if i < len(files) {
+			b.Files = files[i]
+			b.Bytes = files[i] * max(bound, 1)
+			b.IndexBytes = files[i] * 100
+		}
+		h.Buckets = append(h.Buckets, b)
+	}
+	return h
+}
+
+func TestBucketOf(t *testing.T) {
+	assert.Equal(t, 0, BucketOf(0))
+	assert.Equal(t, 0, BucketOf(64*types.KiB-1))
+	assert.Equal(t, 1, BucketOf(64*types.KiB))
+	assert.Equal(t, 5, BucketOf(10*types.MiB))
+	assert.Equal(t, len(Bounds)-1, BucketOf(10*types.GiB))
+}
+
+func TestOptions(t *testing.T) {
+	h := histogram(10*types.MiB, 1000, 100, 10, 1)
+	opts := h.Options()
+	assert.Len(t, opts, len(Bounds)-1)
+	assert.Equal(t, Option{Threshold: 64 * types.KiB, Files: 111, Bytes: 100*64*types.KiB + 10*256*types.KiB + types.MiB, IndexBytes: 11100}, opts[0])
+	assert.Equal(t, int64(1), opts[2].Files, "files of at least 1 MiB")
+	assert.Equal(t, int64(1111), h.Files())
+
+	h.UnsizedFiles = 5000
+	opts = h.Options()
+	assert.True(t, opts[0].Partial, "unsized files are below the current threshold")
+	assert.False(t, opts[4].Partial, "10 MiB is the current threshold")
+}
+
+func TestRecommend(t *testing.T) {
+	small := histogram(10*types.MiB, 5_000_000, 50_000, 20_000, 1000)
+	assert.Equal(t, 64*types.KiB, small.Recommend(100*types.MiB), "everything above 64 KiB fits")
+
+	many := histogram(10*types.MiB, 5_000_000, 500_000, 200_000, 70_000, 20_000)
+	assert.Equal(t, types.MiB, many.Recommend(100*types.MiB))
+	assert.Equal(t, 256*types.KiB, many.Recommend(256*types.KiB), "queries for smaller files come first")
+	assert.Equal(t, types.MiB, many.Recommend(0))
+
+	many.UnsizedFiles = 1_000_000
+	assert.Equal(t, 10*types.MiB, many.Recommend(100*types.MiB), "unsized thresholds aren't recommended when a sized one fits")
+}
+
+func TestConfigSize(t *testing.T) {
+	assert.Equal(t, "64KB", ConfigSize(64*types.KiB))
+	assert.Equal(t, "10MB", ConfigSize(10*types.MiB))
+	assert.Equal(t, "1GB", ConfigSize(types.GiB))
+	assert.Equal(t, "1536KB", ConfigSize(1536*types.KiB))
+	assert.Equal(t, "1000", ConfigSize(1000))
+}
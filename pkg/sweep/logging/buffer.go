@@ -1,6 +1,10 @@
 package logging
 
-import "sync"
+import (
+	"slices"
+	"sync"
+	"time"
+)
 
 // DefaultBufferSize is the default number of log entries to keep in the buffer.
 const DefaultBufferSize = 100
@@ -90,3 +94,32 @@ func (b *LogBuffer) Clear() {
 	b.start = 0
 	b.count = 0
 }
+
+// EntryFilter selects log entries by level, component, and time.
+type EntryFilter struct {
+	MinLevel   Level     // Entries at this level and above
+	Components []string  // Entries from these components; empty for all
+	Since      time.Time // Entries logged after this time; zero for all
+}
+
+// Match reports whether the filter selects entry.
+func (f EntryFilter) Match(entry LogEntry) bool {
+	if entry.Level < f.MinLevel {
+		return false
+	}
+	if len(f.Components) > 0 && !slices.Contains(f.Components, entry.Component) {
+		return false
+	}
+	return f.Since.IsZero() || entry.Time.After(f.Since)
+}
+
+// Filter returns the entries the filter selects, in order.
+func (f EntryFilter) Filter(entries []LogEntry) []LogEntry {
+	var matched []LogEntry
+	for _, e := range entries {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
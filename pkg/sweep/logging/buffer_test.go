@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -139,3 +140,36 @@ func TestNewLogBuffer_InvalidSize(t *testing.T) {
 		t.Errorf("expected default size %d, got %d", DefaultBufferSize, buf.maxSize)
 	}
 }
+
+func TestEntryFilter(t *testing.T) {
+	now := time.Now()
+	entries := []LogEntry{
+		{Time: now.Add(-2 * time.Minute), Level: LevelError, Component: "indexer", Message: "old"},
+		{Time: now, Level: LevelDebug, Component: "indexer", Message: "debug"},
+		{Time: now, Level: LevelWarn, Component: "watcher", Message: "warn"},
+		{Time: now, Level: LevelInfo, Component: "indexer", Message: "info"},
+	}
+
+	tests := []struct {
+		name   string
+		filter EntryFilter
+		want   []string
+	}{
+		{"everything", EntryFilter{}, []string{"old", "debug", "warn", "info"}},
+		{"min level", EntryFilter{MinLevel: LevelInfo}, []string{"old", "warn", "info"}},
+		{"components", EntryFilter{Components: []string{"indexer"}}, []string{"old", "debug", "info"}},
+		{"since", EntryFilter{MinLevel: LevelWarn, Since: now.Add(-time.Minute)}, []string{"warn"}},
+		{"nothing", EntryFilter{Components: []string{"store"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range tt.filter.Filter(entries) {
+				got = append(got, e.Message)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package logging
+
+import "syscall"
+
+// lock acquires an exclusive lock on the log file.
+func (w *RotatingWriter) lock() error {
+	return syscall.Flock(int(w.file.Fd()), syscall.LOCK_EX)
+}
+
+// unlock releases the lock on the log file.
+func (w *RotatingWriter) unlock() {
+	_ = syscall.Flock(int(w.file.Fd()), syscall.LOCK_UN) // ignore unlock errors
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package logging
+
+import "golang.org/x/sys/windows"
+
+// lock acquires an exclusive lock on the log file.
+func (w *RotatingWriter) lock() error {
+	var ol windows.Overlapped
+	return windows.LockFileEx(windows.Handle(w.file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &ol)
+}
+
+// unlock releases the lock on the log file.
+func (w *RotatingWriter) unlock() {
+	var ol windows.Overlapped
+	_ = windows.UnlockFileEx(windows.Handle(w.file.Fd()), 0, 1, 0, &ol) // ignore unlock errors
+}
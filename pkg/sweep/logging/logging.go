@@ -116,6 +116,16 @@ type Config struct {
 	// - Disables console output (TUI owns the screen)
 	// - Enables ring buffer for log panel
 	TUIMode bool
+
+	// BufferSize keeps the most recent entries in a ring buffer outside
+	// TUI mode too, so a daemon can serve them to clients. 0 keeps none.
+	BufferSize int
+}
+
+// Field is a key/value pair logged with an entry.
+type Field struct {
+	Key   string
+	Value string
 }
 
 // LogEntry represents a single log entry for TUI subscription.
@@ -131,6 +141,14 @@ type LogEntry struct {
 
 	// Message is the log message.
 	Message string
+
+	// Fields are the entry's key/value pairs in the order they were
+	// logged, with values formatted as text.
+	Fields []Field
+
+	// Source names the process that logged the entry when it isn't this
+	// one, such as "daemon" for entries read from sweepd; empty otherwise.
+	Source string
 }
 
 // Logger wraps charmbracelet/log with component identification.
@@ -177,9 +195,27 @@ func (l *Logger) log(level Level, msg string, args ...interface{}) {
 		Level:     level,
 		Component: l.component,
 		Message:   msg,
+		Fields:    fields(args),
 	})
 }
 
+// fields returns key/value args as fields with formatted values. A
+// trailing key without a value is kept with an empty one.
+func fields(args []interface{}) []Field {
+	if len(args) == 0 {
+		return nil
+	}
+	f := make([]Field, 0, (len(args)+1)/2)
+	for i := 0; i < len(args); i += 2 {
+		field := Field{Key: fmt.Sprint(args[i])}
+		if i+1 < len(args) {
+			field.Value = fmt.Sprint(args[i+1])
+		}
+		f = append(f, field)
+	}
+	return f
+}
+
 // logTo writes a log message to the given logger at the specified level.
 func logTo(logger *log.Logger, level Level, msg string, args ...interface{}) {
 	switch level {
@@ -221,7 +257,7 @@ type state struct {
 	consoleLevel   Level
 	tuiMode        bool
 
-	// TUI log buffer (only created when TUIMode is true)
+	// Recent entries (only created in TUI mode or with Config.BufferSize)
 	logBuffer *LogBuffer
 }
 
@@ -277,10 +313,13 @@ func Init(cfg Config) error {
 		globalState.consoleEnabled = true
 	}
 
-	// Create log buffer for TUI mode
-	if cfg.TUIMode {
+	// Create log buffer for TUI mode, or when asked to keep entries
+	switch {
+	case cfg.BufferSize > 0:
+		globalState.logBuffer = NewLogBuffer(cfg.BufferSize)
+	case cfg.TUIMode:
 		globalState.logBuffer = NewLogBuffer(DefaultBufferSize)
-	} else {
+	default:
 		globalState.logBuffer = nil
 	}
 
@@ -413,6 +452,51 @@ func Close() error {
 	return nil
 }
 
+// Reopen reopens the log file so entries go to a new file after an
+// external tool such as logrotate has moved the old one away.
+// It does nothing before Init.
+func Reopen() error {
+	globalState.mu.RLock()
+	defer globalState.mu.RUnlock()
+
+	if globalState.writer == nil {
+		return nil
+	}
+	if err := globalState.writer.Reopen(); err != nil {
+		return fmt.Errorf("reopening log file: %w", err)
+	}
+	return nil
+}
+
+// SetLevels changes the default and per-component log levels, for loggers
+// already handed out by Get as well as new ones, leaving the log file open.
+// Levels are only changed if all of them parse.
+func SetLevels(level string, components map[string]string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("parsing log level: %w", err)
+	}
+	levels := make(map[string]Level, len(components))
+	for comp, lvl := range components {
+		if levels[comp], err = ParseLevel(lvl); err != nil {
+			return fmt.Errorf("parsing level for component %s: %w", comp, err)
+		}
+	}
+
+	globalState.mu.Lock()
+	defer globalState.mu.Unlock()
+	globalState.level = parsed
+	globalState.components = levels
+	for comp, logger := range globalState.loggers {
+		if lvl, ok := levels[comp]; ok {
+			logger.file.SetLevel(lvl.toCharmLevel())
+		} else {
+			logger.file.SetLevel(parsed.toCharmLevel())
+		}
+	}
+	return nil
+}
+
 // Subscribe returns a channel that receives log entries.
 // The TUI uses this to display real-time log updates.
 // The channel is buffered to prevent blocking the logging goroutine.
@@ -446,7 +530,7 @@ func (s *state) broadcast(entry LogEntry) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Add to log buffer if in TUI mode
+	// Add to log buffer if one is kept
 	if s.logBuffer != nil {
 		s.logBuffer.Add(entry)
 	}
@@ -461,8 +545,8 @@ func (s *state) broadcast(entry LogEntry) {
 	}
 }
 
-// GetLogBuffer returns the log buffer for TUI display.
-// Returns nil if not in TUI mode or not initialized.
+// GetLogBuffer returns the buffer of recent entries.
+// Returns nil if not in TUI mode, without Config.BufferSize, or not initialized.
 func GetLogBuffer() *LogBuffer {
 	globalState.mu.RLock()
 	defer globalState.mu.RUnlock()
@@ -3,6 +3,7 @@ package logging_test
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -254,6 +255,50 @@ func TestComponentLevelOverride(t *testing.T) {
 	}
 }
 
+func TestSetLevels(t *testing.T) {
+	// No t.Parallel() - uses global state
+
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "levels.log")
+
+	if err := logging.Init(logging.Config{Level: "error", Path: logPath}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	daemonLogger := logging.Get("daemon")
+	daemonLogger.Info("info before the change should not appear")
+
+	if err := logging.SetLevels("info", map[string]string{"watcher": "debug"}); err != nil {
+		t.Fatalf("SetLevels() error = %v", err)
+	}
+	daemonLogger.Info("info after the change should appear")
+	daemonLogger.Debug("debug after the change should not appear")
+	logging.Get("watcher").Debug("watcher debug should appear")
+
+	if err := logging.SetLevels("loud", nil); err == nil {
+		t.Error("SetLevels() with an invalid level should fail")
+	}
+	daemonLogger.Info("info after a failed change should appear")
+
+	if err := logging.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	logContent := string(content)
+	for _, msg := range []string{"info after the change should appear", "watcher debug should appear", "info after a failed change should appear"} {
+		if !strings.Contains(logContent, msg) {
+			t.Errorf("log should contain %q", msg)
+		}
+	}
+	for _, msg := range []string{"info before the change should not appear", "debug after the change should not appear"} {
+		if strings.Contains(logContent, msg) {
+			t.Errorf("log should not contain %q", msg)
+		}
+	}
+}
+
 func TestSubscribeUnsubscribe(t *testing.T) {
 	// No t.Parallel() - uses global state
 
@@ -439,6 +484,41 @@ func TestLogEntryFields(t *testing.T) {
 	}
 }
 
+func TestBufferSize(t *testing.T) {
+	// No t.Parallel() - uses global state
+
+	cfg := logging.Config{
+		Level:      "info",
+		Path:       filepath.Join(t.TempDir(), "buffered.log"),
+		BufferSize: 2,
+	}
+	if err := logging.Init(cfg); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer logging.Close()
+
+	logger := logging.Get("buffertest")
+	logger.Info("first")
+	logger.Info("second", "path", "/data", "size", 42)
+	logger.Warn("third", "dangling")
+
+	buf := logging.GetLogBuffer()
+	if buf == nil {
+		t.Fatal("GetLogBuffer() = nil, want a buffer outside TUI mode")
+	}
+	entries := buf.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("buffered %d entries, want 2", len(entries))
+	}
+	want := []logging.Field{{Key: "path", Value: "/data"}, {Key: "size", Value: "42"}}
+	if entries[0].Message != "second" || !slices.Equal(entries[0].Fields, want) {
+		t.Errorf("entries[0] = %+v, want second with fields %v", entries[0], want)
+	}
+	if want := []logging.Field{{Key: "dangling"}}; !slices.Equal(entries[1].Fields, want) {
+		t.Errorf("entries[1].Fields = %v, want %v", entries[1].Fields, want)
+	}
+}
+
 func TestMultipleSubscribers(t *testing.T) {
 	// No t.Parallel() - uses global state
 
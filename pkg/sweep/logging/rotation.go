@@ -9,7 +9,6 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 )
 
@@ -129,6 +128,29 @@ func (w *RotatingWriter) Close() error {
 	return err
 }
 
+// Reopen closes the log file and opens it again at the same path.
+// External tools such as logrotate rename or truncate the file and then
+// call this (via SIGHUP) so new entries go to a fresh file.
+func (w *RotatingWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Open the new file first so a failure keeps logging to the old one.
+	old, lastRotate := w.file, w.lastRotate
+	if err := w.openFile(); err != nil {
+		return err
+	}
+	// A fresh file's mtime is now; keep the daily rotation schedule.
+	w.lastRotate = lastRotate
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			return fmt.Errorf("closing old log file: %w", err)
+		}
+	}
+	return nil
+}
+
 // openFile opens or creates the log file.
 func (w *RotatingWriter) openFile() error {
 	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
@@ -280,13 +302,3 @@ func (w *RotatingWriter) cleanup() {
 		}
 	}
 }
-
-// lock acquires an exclusive lock on the log file.
-func (w *RotatingWriter) lock() error {
-	return syscall.Flock(int(w.file.Fd()), syscall.LOCK_EX)
-}
-
-// unlock releases the lock on the log file.
-func (w *RotatingWriter) unlock() {
-	_ = syscall.Flock(int(w.file.Fd()), syscall.LOCK_UN) // ignore unlock errors
-}
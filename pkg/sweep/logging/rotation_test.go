@@ -400,3 +400,48 @@ func TestRotationConcurrentWrites(t *testing.T) {
 		t.Errorf("expected %d lines, got %d", expectedLines, len(lines))
 	}
 }
+
+func TestRotationReopen(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "reopen.log")
+	movedPath := filepath.Join(tempDir, "reopen.log.1")
+
+	writer, err := logging.NewRotatingWriter(logPath, logging.RotationConfig{Daily: false})
+	if err != nil {
+		t.Fatalf("NewRotatingWriter() error = %v", err)
+	}
+	defer writer.Close()
+
+	if _, err := writer.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// Simulate logrotate moving the file away.
+	if err := os.Rename(logPath, movedPath); err != nil {
+		t.Fatalf("rename error = %v", err)
+	}
+	if err := writer.Reopen(); err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if _, err := writer.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	moved, err := os.ReadFile(movedPath)
+	if err != nil {
+		t.Fatalf("failed to read moved file: %v", err)
+	}
+	if string(moved) != "before\n" {
+		t.Errorf("moved file content = %q, want %q", moved, "before\n")
+	}
+
+	current, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read reopened file: %v", err)
+	}
+	if string(current) != "after\n" {
+		t.Errorf("reopened file content = %q, want %q", current, "after\n")
+	}
+}
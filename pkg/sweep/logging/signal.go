@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReopenOnHangup reopens the log file whenever the process receives SIGHUP,
+// the conventional signal sent by logrotate's postrotate scripts.
+// The returned function stops handling the signal.
+func ReopenOnHangup() (stop func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				if err := Reopen(); err != nil {
+					Get("logging").Error("failed to reopen log file", "error", err)
+					continue
+				}
+				Get("logging").Info("reopened log file (SIGHUP)")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build unix
+
+package logging_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+)
+
+func TestReopenOnHangup(t *testing.T) {
+	// No t.Parallel() - uses global state and process signals
+
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "hangup.log")
+
+	if err := logging.Init(logging.Config{Level: "info", Path: logPath}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer logging.Close()
+
+	stop := logging.ReopenOnHangup()
+	defer stop()
+
+	if err := os.Rename(logPath, logPath+".1"); err != nil {
+		t.Fatalf("rename error = %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("kill error = %v", err)
+	}
+
+	// The handler logs to the reopened file once it has run.
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		content, err := os.ReadFile(logPath)
+		if err == nil && strings.Contains(string(content), "reopened log file") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("log file was not reopened after SIGHUP")
+}
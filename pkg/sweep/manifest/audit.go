@@ -0,0 +1,154 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditFile is the name of the audit log in the manifest directory.
+const AuditFile = "audit.jsonl"
+
+// How files were deleted, recorded as FileRecord.Method. They match the
+// names of the delete backends.
+const (
+	MethodTrash     = "trash"     // Moved to the trash
+	MethodPermanent = "permanent" // Removed without the trash
+	MethodStaging   = "staging"   // Moved to the staging directory
+	MethodUpload    = "upload"    // Removed after a command uploaded it
+)
+
+// AuditRecord is a file in the audit log: what was deleted or restored,
+// when, by whom, and how. Unlike entries, records are only ever appended,
+// and history cleanup leaves them alone.
+type AuditRecord struct {
+	Time      time.Time     `json:"time"`
+	User      string        `json:"user"`
+	Host      string        `json:"host"`
+	Operation OperationType `json:"operation"`
+	Method    string        `json:"method,omitempty"`
+	Rule      string        `json:"rule,omitempty"`
+	Entry     string        `json:"entry"` // ID of the manifest entry the file is in
+	Path      string        `json:"path"`
+	Size      int64         `json:"size"`
+	ModTime   time.Time     `json:"mod_time"`
+}
+
+// AuditQuery selects audit records. Zero fields match every record.
+type AuditQuery struct {
+	Since      time.Time       // Only records at or after this time
+	Until      time.Time       // Only records before this time
+	Path       string          // Only files at or under this path
+	Operations []OperationType // Only these operations
+}
+
+// Match reports whether r is selected by q.
+func (q AuditQuery) Match(r AuditRecord) bool {
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !r.Time.Before(q.Until) {
+		return false
+	}
+	if q.Path != "" && r.Path != q.Path && !within(q.Path, r.Path) {
+		return false
+	}
+	return len(q.Operations) == 0 || slices.Contains(q.Operations, r.Operation)
+}
+
+// appendAudit appends a record for each file of entry to the audit log, in
+// a single write so concurrent writers don't interleave them.
+func (m *Manifest) appendAudit(entry *Entry) error {
+	who, host := auditUser(), auditHost()
+	var buf []byte
+	for _, f := range entry.Files {
+		line, err := json.Marshal(AuditRecord{
+			Time:      entry.Timestamp,
+			User:      who,
+			Host:      host,
+			Operation: entry.Operation,
+			Method:    f.Method,
+			Rule:      entry.Rule,
+			Entry:     entry.ID,
+			Path:      f.Path,
+			Size:      f.Size,
+			ModTime:   f.ModTime,
+		})
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	file, err := os.OpenFile(filepath.Join(m.dir, AuditFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Audit returns the audit records q selects, oldest first. Lines that
+// can't be parsed are skipped.
+func (m *Manifest) Audit(q AuditQuery) ([]AuditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.Open(filepath.Join(m.dir, AuditFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return []AuditRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	records := []AuditRecord{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if q.Match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// auditUser returns the name of the user sweep runs as.
+func auditUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// auditHost returns the machine's host name, or "" if it is unknown.
+func auditHost() string {
+	host, _ := os.Hostname()
+	return host
+}
+
+// within reports whether path is beneath dir.
+func within(dir, path string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
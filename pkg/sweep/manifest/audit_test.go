@@ -0,0 +1,198 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+)
+
+func TestAuditRecordsDeletesAndRestores(t *testing.T) {
+	t.Parallel()
+	m, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.EnsureDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.LogScan([]FileRecord{{Path: "/data/scanned", Size: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := m.LogDelete([]FileRecord{
+		{Path: "/data/a.iso", Size: 100, Method: MethodTrash},
+		{Path: "/data/b.iso", Size: 200, Method: MethodPermanent},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LogRule("old-logs", []FileRecord{{Path: "/var/log/old.log", Size: 10, Method: MethodTrash}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LogRestore(deleted.ID, []FileRecord{{Path: "/data/a.iso", Size: 100}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := m.Audit(AuditQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range records {
+		got = append(got, string(r.Operation)+" "+r.Method+" "+r.Path)
+	}
+	want := []string{
+		"delete trash /data/a.iso",
+		"delete permanent /data/b.iso",
+		"rule trash /var/log/old.log",
+		"restore  /data/a.iso",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Audit() = %q, want %q", got, want)
+	}
+	if r := records[0]; r.Entry != deleted.ID || r.Size != 100 || r.User == "" || !r.Time.Equal(deleted.Timestamp) {
+		t.Errorf("record = %+v, want entry %s, size 100, a user and the entry's time", r, deleted.ID)
+	}
+	if records[2].Rule != "old-logs" {
+		t.Errorf("rule record Rule = %q, want old-logs", records[2].Rule)
+	}
+
+	// Cleanup removes entries but never the audit log
+	if err := m.Cleanup(-1); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := m.Audit(AuditQuery{}); err != nil || len(records) != 4 {
+		t.Errorf("after Cleanup, Audit() = %d records, %v, want 4", len(records), err)
+	}
+}
+
+func TestAuditMissingLog(t *testing.T) {
+	t.Parallel()
+	m, err := New(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := m.Audit(AuditQuery{})
+	if err != nil || records == nil || len(records) != 0 {
+		t.Errorf("Audit() = %v, %v, want an empty list", records, err)
+	}
+}
+
+func TestAuditSkipsCorruptLines(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	m, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LogDelete([]FileRecord{{Path: "/a", Size: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, AuditFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{truncated\n")
+	_ = f.Close()
+	if _, err := m.LogDelete([]FileRecord{{Path: "/b", Size: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := m.Audit(AuditQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Path != "/a" || records[1].Path != "/b" {
+		t.Errorf("Audit() = %+v, want /a and /b", records)
+	}
+}
+
+func TestAuditQueryMatch(t *testing.T) {
+	t.Parallel()
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := AuditRecord{Time: day, Operation: OpRule, Path: "/srv/shared/old.tar"}
+
+	tests := []struct {
+		name string
+		q    AuditQuery
+		want bool
+	}{
+		{"empty query", AuditQuery{}, true},
+		{"since before", AuditQuery{Since: day.Add(-time.Hour)}, true},
+		{"since exactly", AuditQuery{Since: day}, true},
+		{"since after", AuditQuery{Since: day.Add(time.Hour)}, false},
+		{"until after", AuditQuery{Until: day.Add(time.Hour)}, true},
+		{"until exactly", AuditQuery{Until: day}, false},
+		{"path parent", AuditQuery{Path: "/srv/shared"}, true},
+		{"path parent with slash", AuditQuery{Path: "/srv/shared/"}, true},
+		{"path itself", AuditQuery{Path: "/srv/shared/old.tar"}, true},
+		{"path sibling prefix", AuditQuery{Path: "/srv/share"}, false},
+		{"root", AuditQuery{Path: "/"}, true},
+		{"operation", AuditQuery{Operations: []OperationType{OpDelete, OpRule}}, true},
+		{"other operation", AuditQuery{Operations: []OperationType{OpDelete}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Match(r); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteAudit(t *testing.T) {
+	t.Parallel()
+	records := []AuditRecord{{
+		Time:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		User:      "alice",
+		Host:      "box",
+		Operation: OpRule,
+		Method:    MethodTrash,
+		Rule:      "old-logs",
+		Entry:     "rule-1",
+		Path:      "/var/log/a, b.log",
+		Size:      2048,
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteAudit(&buf, output.ReportCSV, records); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || strings.Join(rows[0], ",") != strings.Join(auditCSVHeader, ",") {
+		t.Fatalf("CSV = %q, want a header and a row", rows)
+	}
+	if row := rows[1]; row[0] != "2026-03-01T12:00:00Z" || row[1] != "alice" || row[7] != "/var/log/a, b.log" || row[8] != "2048" || row[9] != "2.0 KiB" {
+		t.Errorf("CSV row = %q", row)
+	}
+
+	buf.Reset()
+	if err := WriteAudit(&buf, output.ReportJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []AuditRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded == nil {
+		t.Errorf("JSON of no records = %q, %v, want []", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := WriteAudit(&buf, "pretty", records); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "trash (rule old-logs)") || !strings.Contains(out, "1 file(s), 2.0 KiB") {
+		t.Errorf("text output = %q", out)
+	}
+
+	if err := WriteAudit(&buf, "yaml", records); !errors.Is(err, output.ErrUnknownReportFormat) {
+		t.Errorf("WriteAudit(yaml) error = %v, want output.ErrUnknownReportFormat", err)
+	}
+}
@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// WriteAudit renders audit records in the given format.
+func WriteAudit(w io.Writer, format string, records []AuditRecord) error {
+	return output.WriteReport(w, format, records, output.ReportWriters[[]AuditRecord]{
+		output.ReportText: WriteAuditText,
+		output.ReportJSON: WriteAuditJSON,
+		output.ReportCSV:  WriteAuditCSV,
+	})
+}
+
+// WriteAuditText renders the records as a table, with a total.
+func WriteAuditText(w io.Writer, records []AuditRecord) error {
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "No audit records found.")
+		return err
+	}
+
+	var total int64
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tOPERATION\tHOW\tSIZE\tPATH")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"), r.User, r.Operation, how(r), types.FormatSize(r.Size), r.Path)
+		total += r.Size
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d file(s), %s\n", len(records), types.FormatSize(total))
+	return err
+}
+
+// how describes how a record's file was handled, naming the rule for rule
+// runs.
+func how(r AuditRecord) string {
+	s := r.Method
+	if s == "" {
+		s = "-"
+	}
+	if r.Rule != "" {
+		s += " (rule " + r.Rule + ")"
+	}
+	return s
+}
+
+// WriteAuditJSON renders the records as a JSON array.
+func WriteAuditJSON(w io.Writer, records []AuditRecord) error {
+	if records == nil {
+		records = []AuditRecord{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// auditCSVHeader names the CSV columns, matching the JSON field names of a
+// record. Sizes are in bytes, with a human-readable copy for spreadsheets.
+var auditCSVHeader = []string{"time", "user", "host", "operation", "method", "rule", "entry", "path", "size", "size_human", "mod_time"}
+
+// WriteAuditCSV renders the records as CSV, one row per file, with times
+// in RFC 3339.
+func WriteAuditCSV(w io.Writer, records []AuditRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			r.Time.Format(time.RFC3339), r.User, r.Host, string(r.Operation), r.Method, r.Rule, r.Entry, r.Path,
+			strconv.FormatInt(r.Size, 10), types.FormatSize(r.Size), r.ModTime.Format(time.RFC3339),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
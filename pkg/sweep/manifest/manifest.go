@@ -36,43 +36,49 @@ func (m *Manifest) EnsureDir() error {
 
 // LogScan logs a scan operation and returns the created entry.
 func (m *Manifest) LogScan(files []FileRecord) (*Entry, error) {
-	return m.log(OpScan, files)
+	return m.log(Entry{Operation: OpScan, Files: files})
 }
 
 // LogDelete logs a delete operation and returns the created entry.
 func (m *Manifest) LogDelete(files []FileRecord) (*Entry, error) {
-	return m.log(OpDelete, files)
+	return m.log(Entry{Operation: OpDelete, Files: files})
 }
 
-// log creates and persists a manifest entry for the given operation.
-func (m *Manifest) log(op OperationType, files []FileRecord) (*Entry, error) {
+// LogRule logs the files deleted by a cleanup rule and returns the created entry.
+func (m *Manifest) LogRule(rule string, files []FileRecord) (*Entry, error) {
+	return m.log(Entry{Operation: OpRule, Rule: rule, Files: files})
+}
+
+// LogRestore logs the files restored from the entry with ID restored and
+// returns the created entry. Each record's path is where the file now is.
+func (m *Manifest) LogRestore(restored string, files []FileRecord) (*Entry, error) {
+	return m.log(Entry{Operation: OpRestore, Restores: restored, Files: files})
+}
+
+// log completes an entry with its ID, time, and summary and persists it.
+// Files deleted or restored are appended to the audit log first, so they
+// are audited even if the entry can't be written.
+func (m *Manifest) log(entry Entry) (*Entry, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	now := time.Now().UTC()
-	id := generateID(op)
-
-	var totalBytes int64
-	for _, f := range files {
-		totalBytes += f.Size
+	entry.ID = generateID(entry.Operation)
+	entry.Timestamp = time.Now().UTC()
+	entry.Summary = Summary{TotalFiles: int64(len(entry.Files))}
+	for _, f := range entry.Files {
+		entry.Summary.TotalBytes += f.Size
 	}
 
-	entry := &Entry{
-		ID:        id,
-		Timestamp: now,
-		Operation: op,
-		Files:     files,
-		Summary: Summary{
-			TotalFiles: int64(len(files)),
-			TotalBytes: totalBytes,
-		},
+	if entry.Operation != OpScan && len(entry.Files) > 0 {
+		if err := m.appendAudit(&entry); err != nil {
+			return nil, fmt.Errorf("failed to append to audit log: %w", err)
+		}
 	}
-
-	if err := m.writeEntry(entry); err != nil {
+	if err := m.writeEntry(&entry); err != nil {
 		return nil, fmt.Errorf("failed to write manifest entry: %w", err)
 	}
 
-	return entry, nil
+	return &entry, nil
 }
 
 // writeEntry writes an entry to a JSON file in the manifest directory.
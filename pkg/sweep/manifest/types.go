@@ -11,6 +11,10 @@ const (
 	OpScan OperationType = "scan"
 	// OpDelete represents a delete operation.
 	OpDelete OperationType = "delete"
+	// OpRule represents a cleanup rule run.
+	OpRule OperationType = "rule"
+	// OpRestore represents files restored from the trash.
+	OpRestore OperationType = "restore"
 )
 
 // Entry represents a single manifest entry.
@@ -18,6 +22,8 @@ type Entry struct {
 	ID        string        `json:"id"`
 	Timestamp time.Time     `json:"timestamp"`
 	Operation OperationType `json:"operation"`
+	Rule      string        `json:"rule,omitempty"`     // Cleanup rule name, for rule runs
+	Restores  string        `json:"restores,omitempty"` // ID of the entry whose files were restored
 	Files     []FileRecord  `json:"files"`
 	Summary   Summary       `json:"summary"`
 }
@@ -29,6 +35,8 @@ type FileRecord struct {
 	ModTime   time.Time `json:"mod_time"`
 	SHA256    string    `json:"sha256,omitempty"`     // Optional checksum
 	DeletedAt time.Time `json:"deleted_at,omitempty"` // Set when file is deleted
+	Method    string    `json:"method,omitempty"`     // How the file was deleted, e.g. MethodTrash
+	Location  string    `json:"location,omitempty"`   // Where MethodStaging moved the file
 }
 
 // Summary contains operation summary.
@@ -0,0 +1,62 @@
+//go:build linux
+
+package mounts
+
+import (
+	"fmt"
+	"os"
+)
+
+// mountInfoPath is the kernel's per-process mount table.
+const mountInfoPath = "/proc/self/mountinfo"
+
+// Load reads the mount table of the current process.
+func Load() (Table, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mount table: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// InContainer reports whether the current process appears to be running
+// inside a container (Docker, Podman, or a Kubernetes pod).
+func InContainer() bool {
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	// The root filesystem of most container runtimes is an overlay.
+	table, err := Load()
+	if err != nil {
+		return false
+	}
+	for _, m := range table {
+		if m.MountPoint == "/" {
+			return m.FSType == FSTypeOverlay
+		}
+	}
+	return false
+}
+
+// ReadOnlyVolumes returns the paths that are on read-only mounts, each
+// mapped to its mount point. Paths are all checked against one read of the
+// mount table; if it can't be read, none are reported.
+func ReadOnlyVolumes(paths []string) map[string]string {
+	table, err := Load()
+	if err != nil {
+		return nil
+	}
+	result := make(map[string]string)
+	for _, p := range paths {
+		if m, ok := table.Find(p); ok && m.ReadOnly() {
+			result[p] = m.MountPoint
+		}
+	}
+	return result
+}
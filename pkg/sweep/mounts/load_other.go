@@ -0,0 +1,14 @@
+//go:build !linux
+
+package mounts
+
+// Load returns an empty table on platforms without /proc/self/mountinfo.
+// Bind mounts and overlays are a Linux concept, so there is nothing to dedupe.
+func Load() (Table, error) {
+	return nil, nil
+}
+
+// InContainer always reports false on non-Linux platforms.
+func InContainer() bool {
+	return false
+}
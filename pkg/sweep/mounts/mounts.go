@@ -0,0 +1,303 @@
+// Package mounts reads the system mount table and identifies mount points
+// that expose content already visible elsewhere, such as bind mounts and
+// overlayfs merged views used by container runtimes. Scans use this to avoid
+// counting the same bytes more than once. It also lists volumes by UUID,
+// so the daemon can follow an external drive to a new mount point.
+package mounts
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FSTypeOverlay is the filesystem type reported for overlayfs mounts.
+const FSTypeOverlay = "overlay"
+
+// ErrMalformedLine is returned when a mountinfo line cannot be parsed.
+var ErrMalformedLine = errors.New("malformed mountinfo line")
+
+// Mount describes a single entry in the mount table.
+type Mount struct {
+	ID           int
+	ParentID     int
+	Device       string // "major:minor"
+	Root         string // Path within the source filesystem that is mounted
+	MountPoint   string
+	FSType       string
+	Source       string
+	MountOptions map[string]string // Per-mount options (e.g., ro, nosuid)
+	Options      map[string]string // Superblock options (e.g., lowerdir for overlay)
+}
+
+// ReadOnly reports whether files on the mount can't be changed, because
+// either the mount or its filesystem is read-only, as with disk images,
+// snapshots, and shares mounted ro.
+func (m Mount) ReadOnly() bool {
+	_, ro := m.MountOptions["ro"]
+	_, superRO := m.Options["ro"]
+	return ro || superRO
+}
+
+// IsBind reports whether the mount exposes a subdirectory of its filesystem
+// rather than the filesystem root, which is how bind mounts appear.
+func (m Mount) IsBind() bool {
+	return m.Root != "/"
+}
+
+// Overlay describes the layers behind an overlayfs mount.
+type Overlay struct {
+	MountPoint string
+	UpperDir   string
+	WorkDir    string
+	LowerDirs  []string
+}
+
+// Overlay returns the layer description of an overlayfs mount.
+// The second return value is false for non-overlay mounts.
+func (m Mount) Overlay() (Overlay, bool) {
+	if m.FSType != FSTypeOverlay {
+		return Overlay{}, false
+	}
+	o := Overlay{
+		MountPoint: m.MountPoint,
+		UpperDir:   m.Options["upperdir"],
+		WorkDir:    m.Options["workdir"],
+	}
+	if lower := m.Options["lowerdir"]; lower != "" {
+		// Docker's overlay2 driver passes lower layers relative to its
+		// storage directory, which is two levels above the upper layer.
+		base := filepath.Dir(filepath.Dir(o.UpperDir))
+		for _, dir := range strings.Split(lower, ":") {
+			if !filepath.IsAbs(dir) && o.UpperDir != "" {
+				dir = filepath.Join(base, dir)
+			}
+			o.LowerDirs = append(o.LowerDirs, dir)
+		}
+	}
+	return o, true
+}
+
+// Dirs returns every directory backing the overlay (upper and lower layers).
+func (o Overlay) Dirs() []string {
+	dirs := make([]string, 0, len(o.LowerDirs)+1)
+	if o.UpperDir != "" {
+		dirs = append(dirs, o.UpperDir)
+	}
+	return append(dirs, o.LowerDirs...)
+}
+
+// Table is a parsed mount table.
+type Table []Mount
+
+// Parse reads a table in /proc/self/mountinfo format.
+func Parse(r io.Reader) (Table, error) {
+	var table Table
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		m, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		table = append(table, m)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mount table: %w", err)
+	}
+	return table, nil
+}
+
+// parseLine parses a single mountinfo line:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func parseLine(line string) (Mount, error) {
+	fields := strings.Fields(line)
+	sep := -1
+	for i, f := range fields {
+		if f == "-" && i >= 6 {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 || len(fields) < sep+3 {
+		return Mount{}, fmt.Errorf("%w: %q", ErrMalformedLine, line)
+	}
+
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Mount{}, fmt.Errorf("%w: bad mount ID in %q", ErrMalformedLine, line)
+	}
+	parent, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Mount{}, fmt.Errorf("%w: bad parent ID in %q", ErrMalformedLine, line)
+	}
+
+	m := Mount{
+		ID:           id,
+		ParentID:     parent,
+		Device:       fields[2],
+		Root:         unescape(fields[3]),
+		MountPoint:   unescape(fields[4]),
+		FSType:       fields[sep+1],
+		Source:       unescape(fields[sep+2]),
+		MountOptions: parseOptions(fields[5]),
+		Options:      make(map[string]string),
+	}
+	if len(fields) > sep+3 {
+		m.Options = parseOptions(fields[sep+3])
+	}
+	return m, nil
+}
+
+// parseOptions parses a comma-separated list of options.
+func parseOptions(list string) map[string]string {
+	opts := make(map[string]string)
+	for _, opt := range strings.Split(list, ",") {
+		k, v, _ := strings.Cut(opt, "=")
+		opts[k] = unescape(v)
+	}
+	return opts
+}
+
+// unescape decodes the octal escapes (\040 etc.) the kernel uses in mountinfo.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Overlays returns the overlayfs mounts in the table.
+func (t Table) Overlays() []Overlay {
+	var result []Overlay
+	for _, m := range t {
+		if o, ok := m.Overlay(); ok {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// Find returns the mount holding path: the one with the longest mount
+// point containing it, and of those the last mounted, which hides the
+// others. The second return value is false when no mount contains path.
+func (t Table) Find(path string) (Mount, bool) {
+	path = filepath.Clean(path)
+	var found Mount
+	ok := false
+	for _, m := range t {
+		if within(m.MountPoint, path) && (!ok || len(m.MountPoint) >= len(found.MountPoint)) {
+			found, ok = m, true
+		}
+	}
+	return found, ok
+}
+
+// Binds returns the bind mounts in the table.
+func (t Table) Binds() []Mount {
+	var result []Mount
+	for _, m := range t {
+		if m.IsBind() {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// DuplicatePaths returns mount points beneath root whose content is already
+// reachable through another path beneath root. Skipping these paths during a
+// scan avoids counting the same bytes twice. Two cases are detected:
+//
+//   - bind mounts whose source directory is visible through another mount
+//     of the same device under root
+//   - overlayfs merged views whose upper or lower layers live under root
+func (t Table) DuplicatePaths(root string) []string {
+	root = filepath.Clean(root)
+	dup := make(map[string]bool)
+
+	// Group mounts of the same device that are visible under root.
+	byDevice := make(map[string][]Mount)
+	for _, m := range t {
+		if within(root, m.MountPoint) || within(m.MountPoint, root) {
+			byDevice[m.Device] = append(byDevice[m.Device], m)
+		}
+	}
+
+	for _, group := range byDevice {
+		if len(group) < 2 {
+			continue
+		}
+		// Canonical order: widest source root first, then lowest mount ID.
+		sort.Slice(group, func(i, j int) bool {
+			if len(group[i].Root) != len(group[j].Root) {
+				return len(group[i].Root) < len(group[j].Root)
+			}
+			return group[i].ID < group[j].ID
+		})
+		for i := 1; i < len(group); i++ {
+			m := group[i]
+			if !within(root, m.MountPoint) || m.MountPoint == root {
+				continue
+			}
+			for _, canon := range group[:i] {
+				if canon.MountPoint == m.MountPoint || !within(canon.Root, m.Root) {
+					continue
+				}
+				// Where canon exposes the same source directory.
+				rel := strings.TrimPrefix(m.Root, strings.TrimSuffix(canon.Root, "/"))
+				if within(root, filepath.Join(canon.MountPoint, rel)) {
+					dup[m.MountPoint] = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, o := range t.Overlays() {
+		if !within(root, o.MountPoint) || o.MountPoint == root {
+			continue
+		}
+		for _, dir := range o.Dirs() {
+			if within(root, dir) {
+				dup[o.MountPoint] = true
+				break
+			}
+		}
+	}
+
+	result := make([]string, 0, len(dup))
+	for p := range dup {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// within reports whether path is parent itself or beneath it.
+func within(parent, path string) bool {
+	if parent == path || parent == "/" {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(parent, "/")+"/")
+}
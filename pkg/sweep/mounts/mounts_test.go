@@ -0,0 +1,330 @@
+package mounts
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+30 22 8:1 /srv/data /mnt/data rw,relatime shared:1 - ext4 /dev/sda1 rw
+31 22 8:1 /srv/data /home/user/data rw,relatime shared:1 - ext4 /dev/sda1 rw
+33 22 8:1 /srv/data /mnt/backup rw,relatime shared:1 - ext4 /dev/sda1 rw
+32 22 8:2 / /home/user/media rw,relatime - ext4 /dev/sdb1 rw
+40 22 0:50 / /var/lib/docker/overlay2/abc/merged rw,relatime - overlay overlay rw,lowerdir=/var/lib/docker/overlay2/l/L1:/var/lib/docker/overlay2/l/L2,upperdir=/var/lib/docker/overlay2/abc/diff,workdir=/var/lib/docker/overlay2/abc/work
+41 22 8:1 /home/my\040files /mnt/my\040files rw - ext4 /dev/sda1 rw
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	table, err := Parse(strings.NewReader(sampleMountInfo))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(table) != 7 {
+		t.Fatalf("Parse() returned %d mounts, want 7", len(table))
+	}
+
+	root := table[0]
+	if root.ID != 22 || root.ParentID != 1 || root.Device != "8:1" || root.MountPoint != "/" ||
+		root.FSType != "ext4" || root.Source != "/dev/sda1" || root.IsBind() {
+		t.Errorf("unexpected root mount: %+v", root)
+	}
+
+	if !table[1].IsBind() || table[1].Root != "/srv/data" {
+		t.Errorf("expected /mnt/data to be a bind mount of /srv/data: %+v", table[1])
+	}
+
+	escaped := table[6]
+	if escaped.Root != "/home/my files" || escaped.MountPoint != "/mnt/my files" {
+		t.Errorf("octal escapes not decoded: root=%q mountpoint=%q", escaped.Root, escaped.MountPoint)
+	}
+
+	o, ok := table[5].Overlay()
+	if !ok {
+		t.Fatal("expected overlay mount")
+	}
+	wantLower := []string{"/var/lib/docker/overlay2/l/L1", "/var/lib/docker/overlay2/l/L2"}
+	if !reflect.DeepEqual(o.LowerDirs, wantLower) {
+		t.Errorf("LowerDirs = %v, want %v", o.LowerDirs, wantLower)
+	}
+	if o.UpperDir != "/var/lib/docker/overlay2/abc/diff" {
+		t.Errorf("UpperDir = %q", o.UpperDir)
+	}
+	if _, ok := table[0].Overlay(); ok {
+		t.Error("ext4 mount reported as overlay")
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"22 1 8:1 / / rw",
+		"x 1 8:1 / / rw - ext4 /dev/sda1 rw",
+		"22 y 8:1 / / rw - ext4 /dev/sda1 rw",
+	}
+	for _, line := range tests {
+		if _, err := Parse(strings.NewReader(line)); !errors.Is(err, ErrMalformedLine) {
+			t.Errorf("Parse(%q) error = %v, want ErrMalformedLine", line, err)
+		}
+	}
+}
+
+func TestDuplicatePaths(t *testing.T) {
+	t.Parallel()
+
+	table, err := Parse(strings.NewReader(sampleMountInfo))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		root string
+		want []string
+	}{
+		{
+			name: "whole filesystem skips binds and overlays",
+			root: "/",
+			want: []string{
+				"/home/user/data",
+				"/mnt/backup",
+				"/mnt/data",
+				"/mnt/my files",
+				"/var/lib/docker/overlay2/abc/merged",
+			},
+		},
+		{
+			name: "bind whose source is outside root is kept",
+			root: "/home/user",
+			want: []string{},
+		},
+		{
+			name: "two binds of the same source under root keep the first",
+			root: "/mnt",
+			want: []string{"/mnt/backup"},
+		},
+		{
+			name: "overlay without layers under root is kept",
+			root: "/var/lib/docker/overlay2/abc/merged",
+			want: []string{},
+		},
+		{
+			name: "overlay with layers under root is skipped",
+			root: "/var/lib/docker",
+			want: []string{"/var/lib/docker/overlay2/abc/merged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := table.DuplicatePaths(tt.root)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DuplicatePaths(%q) = %v, want %v", tt.root, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindReadOnly(t *testing.T) {
+	t.Parallel()
+
+	table, err := Parse(strings.NewReader(`22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+50 22 7:0 / /mnt/image ro,relatime - iso9660 /dev/loop0 ro
+51 22 0:60 / /mnt/share rw,relatime - nfs server:/export ro,vers=4.2
+52 50 8:3 / /mnt/image/data rw,relatime - ext4 /dev/sdc1 rw
+53 22 8:4 / /mnt/share rw,relatime - ext4 /dev/sdd1 rw
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		mount    string
+		readOnly bool
+	}{
+		{"/home/user/file", "/", false},
+		{"/mnt/image/disc.iso", "/mnt/image", true},
+		{"/mnt/imagery/file", "/", false},
+		{"/mnt/image/data/file", "/mnt/image/data", false},
+		// The later mount hides the read-only share
+		{"/mnt/share/file", "/mnt/share", false},
+	}
+	for _, tt := range tests {
+		m, ok := table.Find(tt.path)
+		if !ok {
+			t.Errorf("Find(%q) found no mount", tt.path)
+			continue
+		}
+		if m.MountPoint != tt.mount || m.ReadOnly() != tt.readOnly {
+			t.Errorf("Find(%q) = %s (read-only %v), want %s (read-only %v)",
+				tt.path, m.MountPoint, m.ReadOnly(), tt.mount, tt.readOnly)
+		}
+	}
+
+	if !table[2].ReadOnly() {
+		t.Error("a read-only filesystem is read-only whatever the mount says")
+	}
+	if _, ok := Table(nil).Find("/"); ok {
+		t.Error("Find() in an empty table found a mount")
+	}
+}
+
+func TestVolumes(t *testing.T) {
+	t.Parallel()
+
+	table, err := Parse(strings.NewReader(sampleMountInfo))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	vols := table.Volumes(map[string]string{"/dev/sda1": "1111", "/dev/sdb1": "2222"})
+	want := []Volume{{UUID: "1111", MountPoint: "/"}, {UUID: "2222", MountPoint: "/home/user/media"}}
+	if !reflect.DeepEqual(vols, want) {
+		t.Errorf("Volumes() = %v, want %v without bind mounts", vols, want)
+	}
+
+	if v, ok := VolumeAt(vols, "/home/user/media/photos"); !ok || v.UUID != "2222" {
+		t.Errorf("VolumeAt(media) = %v, %v; want 2222", v, ok)
+	}
+	if v, ok := VolumeAt(vols, "/home/user/mediafiles"); !ok || v.UUID != "1111" {
+		t.Errorf("VolumeAt(mediafiles) = %v, %v; want the root volume", v, ok)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	t.Parallel()
+
+	u, err := DiskUsage(t.TempDir())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("volume usage isn't read on this platform")
+	}
+	if err != nil {
+		t.Fatalf("DiskUsage() error = %v", err)
+	}
+	if u.Total <= 0 || u.Free < 0 || u.Free > u.Total {
+		t.Errorf("DiskUsage() = %+v", u)
+	}
+
+	if got := (Usage{Total: 400, Free: 100}).FreeFraction(); got != 0.25 {
+		t.Errorf("FreeFraction() = %v, want 0.25", got)
+	}
+	if got := (Usage{}).FreeFraction(); got != 0 {
+		t.Errorf("FreeFraction() of an unknown size = %v, want 0", got)
+	}
+}
+
+func TestIsSnapshotDir(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"/tank/data/.zfs/snapshot": true,
+		"/.snapshots":              true,
+		"/timeshift/snapshots":     true,
+		"/run/timeshift/backup/timeshift-btrfs/snapshots":    true,
+		"/Volumes/Data/com.apple.TimeMachine.localsnapshots": true,
+		"/.MobileBackups":                  true,
+		"/tank/data/.zfs":                  false,
+		"/home/user/snapshot":              false,
+		"/home/user/snapshots":             false,
+		"/home/user/.snapshots/1/snapshot": false,
+	}
+	for path, want := range tests {
+		if got := IsSnapshotDir(path); got != want {
+			t.Errorf("IsSnapshotDir(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	const info = `22 1 0:30 / / rw - btrfs /dev/sda2 rw,subvol=/@
+23 22 0:30 /@/.snapshots/12/snapshot /mnt/restore ro - btrfs /dev/sda2 ro,subvol=/@/.snapshots/12/snapshot
+24 22 0:30 /@home /home rw - btrfs /dev/sda2 rw,subvol=/@home
+25 22 0:40 / /tank rw - zfs tank rw
+26 25 0:41 / /tank/.zfs/snapshot/daily ro - zfs tank@daily ro
+27 22 0:42 / /mnt/old ro - zfs tank@old ro
+`
+	table, err := Parse(strings.NewReader(info))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		root string
+		want []string
+	}{
+		{root: "/", want: []string{"/mnt/old", "/mnt/restore", "/tank/.zfs/snapshot/daily"}},
+		{root: "/tank", want: []string{"/tank/.zfs/snapshot/daily"}},
+		{root: "/home", want: nil},
+		{root: "/mnt/old", want: nil}, // A snapshot asked for by name is scanned
+	}
+	for _, tt := range tests {
+		if got := table.Snapshots(tt.root); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Snapshots(%q) = %v, want %v", tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestIsNetworkFSType(t *testing.T) {
+	t.Parallel()
+
+	for fsType, want := range map[string]bool{
+		"nfs4": true, "cifs": true, "smbfs": true, "afpfs": true, "fuse.sshfs": true,
+		"fuse.rclone": true, "macfuse": true, "ext4": false, "apfs": false,
+		"fuseblk": false, "overlay": false, "tmpfs": false,
+	} {
+		if got := IsNetworkFSType(fsType); got != want {
+			t.Errorf("IsNetworkFSType(%q) = %v, want %v", fsType, got, want)
+		}
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	const info = `22 1 8:1 / / rw - ext4 /dev/sda1 rw
+23 22 0:50 / /mnt/nas rw - nfs4 nas:/export rw
+24 22 0:51 / /home/ana/remote rw - fuse.sshfs ana@box: rw
+25 23 8:17 / /mnt/nas/cache rw - ext4 /dev/sdb1 rw
+`
+	table, err := Parse(strings.NewReader(info))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	orig := loadFSTable
+	loadFSTable = func() (Table, error) { return table, nil }
+	t.Cleanup(func() { loadFSTable = orig })
+
+	n := Network{Detect: true, Paths: []string{"/srv/share"}, Local: []string{"/home/ana/remote"}}
+	tests := []struct {
+		path       string
+		mountPoint string
+		fsType     string
+	}{
+		{path: "/mnt/nas/photos", mountPoint: "/mnt/nas", fsType: "nfs4"},
+		{path: "/mnt/nas/cache/x", mountPoint: ""},      // A local disk mounted inside
+		{path: "/home/ana/remote/docs", mountPoint: ""}, // Configured as local
+		{path: "/srv/share/a", mountPoint: "/srv/share", fsType: "configured"},
+		{path: "/usr", mountPoint: ""},
+	}
+	for _, tt := range tests {
+		mountPoint, fsType, ok := n.Find(tt.path)
+		if mountPoint != tt.mountPoint || fsType != tt.fsType || ok != (tt.mountPoint != "") {
+			t.Errorf("Find(%q) = %q, %q, %v; want %q, %q", tt.path, mountPoint, fsType, ok, tt.mountPoint, tt.fsType)
+		}
+	}
+
+	if got, want := n.MountsUnder("/"), []string{"/mnt/nas", "/srv/share"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MountsUnder(/) = %v, want %v", got, want)
+	}
+	if got := n.MountsUnder("/mnt/nas"); got != nil {
+		t.Errorf("MountsUnder(/mnt/nas) = %v, want none", got)
+	}
+	if _, _, ok := (Network{}).Find("/mnt/nas"); ok {
+		t.Error("the zero Network found a network mount")
+	}
+}
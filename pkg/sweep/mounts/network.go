@@ -0,0 +1,106 @@
+package mounts
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// networkFSTypes are the file system types served over the network, as
+// the Linux mount table and macOS statfs name them.
+var networkFSTypes = []string{
+	"nfs", "nfs4", "cifs", "smb3", "smbfs", "afpfs", "webdav", "davfs",
+	"9p", "ceph", "glusterfs", "lustre", "gpfs", "afs", "ncpfs", "sshfs",
+}
+
+// loadFSTable is replaced in tests.
+var loadFSTable = fsTable
+
+// IsNetworkFSType reports whether file systems of type fsType may answer
+// each stat with a round trip to another machine: network file systems,
+// such as NFS and SMB, and FUSE ones, such as sshfs and rclone, which are
+// mostly backed by a remote service. fuseblk, FUSE over a local disk as
+// ntfs-3g uses, is local.
+func IsNetworkFSType(fsType string) bool {
+	fsType = strings.ToLower(fsType)
+	if slices.Contains(networkFSTypes, fsType) {
+		return true
+	}
+	return fsType != "fuseblk" && (fsType == "fuse" || strings.HasPrefix(fsType, "fuse.") ||
+		strings.HasSuffix(fsType, "fuse") || strings.HasPrefix(fsType, "fusefs"))
+}
+
+// IsNetwork reports whether the mount is of a network or FUSE file system
+// (see IsNetworkFSType).
+func (m Mount) IsNetwork() bool {
+	return IsNetworkFSType(m.FSType)
+}
+
+// Network tells which paths are on network file systems, which scans walk
+// with fewer workers and the daemon doesn't watch. The zero Network treats
+// every path as local.
+type Network struct {
+	Detect bool     // Look up the file system type of paths
+	Paths  []string // Mount points treated as network ones whatever their type
+	Local  []string // Mount points treated as local whatever their type
+}
+
+// Find returns the network mount point path is on, and its file system
+// type, or "configured" for one of Paths. The most specific of the mount
+// holding path and the configured mount points decides, Local ones winning
+// ties, then Paths. The last return value is false for local paths.
+func (n Network) Find(path string) (mountPoint, fsType string, ok bool) {
+	path = filepath.Clean(path)
+	mountPoint = longestWithin(n.Paths, path)
+	fsType, ok = "configured", mountPoint != ""
+	if n.Detect {
+		if table, err := loadFSTable(); err == nil {
+			if m, found := table.Find(path); found && len(m.MountPoint) > len(mountPoint) {
+				mountPoint, fsType, ok = m.MountPoint, m.FSType, m.IsNetwork()
+			}
+		}
+	}
+	if local := longestWithin(n.Local, path); !ok || (local != "" && len(local) >= len(mountPoint)) {
+		return "", "", false
+	}
+	return mountPoint, fsType, true
+}
+
+// MountsUnder returns the network mount points beneath root, other than
+// root itself, that aren't treated as local.
+func (n Network) MountsUnder(root string) []string {
+	root = filepath.Clean(root)
+	var result []string
+	add := func(mountPoint string) {
+		if mountPoint != root && within(root, mountPoint) && !slices.Contains(result, mountPoint) &&
+			len(longestWithin(n.Local, mountPoint)) < len(mountPoint) {
+			result = append(result, mountPoint)
+		}
+	}
+	for _, p := range n.Paths {
+		add(filepath.Clean(p))
+	}
+	if n.Detect {
+		table, _ := loadFSTable()
+		for _, m := range table {
+			if m.IsNetwork() {
+				add(m.MountPoint)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// longestWithin returns the longest of dirs that path is within, or "".
+func longestWithin(dirs []string, path string) string {
+	longest := ""
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if within(dir, path) && len(dir) > len(longest) {
+			longest = dir
+		}
+	}
+	return longest
+}
@@ -0,0 +1,22 @@
+//go:build darwin
+
+package mounts
+
+import "golang.org/x/sys/unix"
+
+// ReadOnlyVolumes returns the paths that are on read-only volumes, such as
+// mounted disk images and APFS snapshots, each mapped to its mount point.
+// Paths that can't be checked are not reported.
+func ReadOnlyVolumes(paths []string) map[string]string {
+	result := make(map[string]string)
+	for _, p := range paths {
+		var st unix.Statfs_t
+		if err := unix.Statfs(p, &st); err != nil {
+			continue
+		}
+		if st.Flags&unix.MNT_RDONLY != 0 {
+			result[p] = unix.ByteSliceToString(st.Mntonname[:])
+		}
+	}
+	return result
+}
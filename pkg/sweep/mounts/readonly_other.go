@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package mounts
+
+// ReadOnlyVolumes reports no paths on platforms where sweep can't tell
+// read-only volumes apart; deleting there fails per file instead.
+func ReadOnlyVolumes([]string) map[string]string {
+	return nil
+}
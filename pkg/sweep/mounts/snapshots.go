@@ -0,0 +1,69 @@
+package mounts
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Filesystem types whose snapshots can be mounted like any other mount.
+const (
+	FSTypeBtrfs = "btrfs"
+	FSTypeZFS   = "zfs"
+)
+
+// IsSnapshotDir reports whether path is a directory that holds filesystem
+// snapshots, each a read-only copy of the filesystem that shares its
+// blocks, so walking one counts the same data again:
+//
+//   - .zfs/snapshot, where ZFS mounts snapshots on access
+//   - .snapshots, where snapper keeps btrfs subvolume snapshots
+//   - timeshift/snapshots and timeshift-btrfs/snapshots, Timeshift's
+//   - com.apple.TimeMachine.localsnapshots, where macOS mounts local Time
+//     Machine snapshots, and .MobileBackups, where older releases did
+//
+// Only the name is looked at, so it costs no system call.
+func IsSnapshotDir(path string) bool {
+	base := filepath.Base(path)
+	switch base {
+	case ".snapshots", "com.apple.TimeMachine.localsnapshots", ".MobileBackups":
+		return true
+	case "snapshot", "snapshots":
+		parent := filepath.Base(filepath.Dir(path))
+		return base == "snapshot" && parent == ".zfs" ||
+			base == "snapshots" && (parent == "timeshift" || parent == "timeshift-btrfs")
+	}
+	return false
+}
+
+// IsSnapshot reports whether the mount is a filesystem snapshot: a ZFS
+// snapshot, named dataset@snapshot, or a btrfs subvolume kept in one of the
+// snapshot directories IsSnapshotDir knows.
+func (m Mount) IsSnapshot() bool {
+	switch m.FSType {
+	case FSTypeZFS:
+		return strings.Contains(m.Source, "@")
+	case FSTypeBtrfs:
+		subvol := "/" + strings.TrimPrefix(m.Options["subvol"], "/")
+		for dir := subvol; dir != "/"; dir = filepath.Dir(dir) {
+			if IsSnapshotDir(dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Snapshots returns the mount points beneath root of mounted snapshots,
+// wherever they are mounted, other than root itself.
+func (t Table) Snapshots(root string) []string {
+	root = filepath.Clean(root)
+	var result []string
+	for _, m := range t {
+		if m.IsSnapshot() && m.MountPoint != root && within(root, m.MountPoint) {
+			result = append(result, m.MountPoint)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,27 @@
+//go:build darwin
+
+package mounts
+
+import "golang.org/x/sys/unix"
+
+// fsTable returns the mounted file systems with their types, from statfs.
+func fsTable() (Table, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]unix.Statfs_t, n)
+	if n, err = unix.Getfsstat(stats, unix.MNT_NOWAIT); err != nil {
+		return nil, err
+	}
+	table := make(Table, 0, n)
+	for _, st := range stats[:n] {
+		table = append(table, Mount{
+			Root:       "/",
+			MountPoint: unix.ByteSliceToString(st.Mntonname[:]),
+			FSType:     unix.ByteSliceToString(st.Fstypename[:]),
+			Source:     unix.ByteSliceToString(st.Mntfromname[:]),
+		})
+	}
+	return table, nil
+}
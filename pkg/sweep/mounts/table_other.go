@@ -0,0 +1,9 @@
+//go:build !darwin
+
+package mounts
+
+// fsTable returns the mounted file systems with their types: the mount
+// table, where there is one.
+func fsTable() (Table, error) {
+	return Load()
+}
@@ -0,0 +1,24 @@
+package mounts
+
+// Usage is how much of a volume is in use.
+type Usage struct {
+	Total int64 // Bytes the volume holds
+	Free  int64 // Bytes available to unprivileged users
+
+	Inodes     int64 // Inodes the volume holds; 0 if it allocates them as needed
+	FreeInodes int64 // Inodes not in use
+}
+
+// Used returns the bytes not available, including those reserved for root.
+func (u Usage) Used() int64 {
+	return max(u.Total-u.Free, 0)
+}
+
+// FreeFraction returns the share of the volume that is available, from 0
+// to 1, or 0 for a volume of unknown size.
+func (u Usage) FreeFraction() float64 {
+	if u.Total <= 0 {
+		return 0
+	}
+	return min(float64(u.Free)/float64(u.Total), 1)
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package mounts
+
+import "errors"
+
+// DiskUsage returns errors.ErrUnsupported: volume usage isn't read on this
+// platform.
+func DiskUsage(string) (Usage, error) {
+	return Usage{}, errors.ErrUnsupported
+}
@@ -0,0 +1,21 @@
+//go:build darwin || linux
+
+package mounts
+
+import "golang.org/x/sys/unix"
+
+// DiskUsage returns the usage of the volume holding path.
+func DiskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := int64(st.Bsize) //nolint:unconvert // uint32 on darwin
+	return Usage{
+		Total: int64(st.Blocks) * bsize,
+		Free:  int64(st.Bavail) * bsize,
+
+		Inodes:     int64(st.Files),
+		FreeInodes: int64(st.Ffree),
+	}, nil
+}
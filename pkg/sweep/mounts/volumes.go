@@ -0,0 +1,46 @@
+package mounts
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Volume is a mounted filesystem with a UUID, which stays the same
+// wherever the volume is mounted, so an external drive can be recognized
+// when it is plugged in again at another mount point.
+type Volume struct {
+	UUID       string
+	MountPoint string
+}
+
+// Volumes returns the mounts in the table whose source device has a UUID
+// in uuids, which maps device paths to UUIDs. Bind mounts are left out, so
+// each volume is listed at the mount point that exposes all of it. The
+// result is in mount point order.
+func (t Table) Volumes(uuids map[string]string) []Volume {
+	var vols []Volume
+	for _, m := range t {
+		if m.IsBind() {
+			continue
+		}
+		if uuid, ok := uuids[m.Source]; ok {
+			vols = append(vols, Volume{UUID: uuid, MountPoint: m.MountPoint})
+		}
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].MountPoint < vols[j].MountPoint })
+	return vols
+}
+
+// VolumeAt returns the volume holding path: the one with the longest mount
+// point containing it. The second return value is false when none does.
+func VolumeAt(vols []Volume, path string) (Volume, bool) {
+	path = filepath.Clean(path)
+	var found Volume
+	ok := false
+	for _, v := range vols {
+		if within(v.MountPoint, path) && (!ok || len(v.MountPoint) > len(found.MountPoint)) {
+			found, ok = v, true
+		}
+	}
+	return found, ok
+}
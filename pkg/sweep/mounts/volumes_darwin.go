@@ -0,0 +1,61 @@
+//go:build darwin
+
+package mounts
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// volumeUUIDPattern finds the volume UUID in the property list printed by
+// diskutil info -plist.
+var volumeUUIDPattern = regexp.MustCompile(`<key>VolumeUUID</key>\s*<string>([^<]+)</string>`)
+
+// Volumes returns the volumes mounted under /Volumes, where external
+// drives appear, that have a UUID. The system volumes are left out, as
+// they never move.
+func Volumes() ([]Volume, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]unix.Statfs_t, n)
+	if n, err = unix.Getfsstat(stats, unix.MNT_NOWAIT); err != nil {
+		return nil, err
+	}
+
+	var table Table
+	uuids := make(map[string]string)
+	for _, st := range stats[:n] {
+		mountPoint := unix.ByteSliceToString(st.Mntonname[:])
+		if !strings.HasPrefix(mountPoint, "/Volumes/") {
+			continue
+		}
+		source := unix.ByteSliceToString(st.Mntfromname[:])
+		table = append(table, Mount{Root: "/", MountPoint: mountPoint, Source: source})
+		if uuid := volumeUUID(mountPoint); uuid != "" {
+			uuids[source] = uuid
+		}
+	}
+	return table.Volumes(uuids), nil
+}
+
+// volumeUUID asks diskutil for the UUID of the volume mounted at
+// mountPoint, or returns "" if it has none.
+func volumeUUID(mountPoint string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "diskutil", "info", "-plist", mountPoint).Output()
+	if err != nil {
+		return ""
+	}
+	if m := volumeUUIDPattern.FindSubmatch(out); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package mounts
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// uuidDir holds a symlink to each block device with a filesystem UUID,
+// named after the UUID.
+const uuidDir = "/dev/disk/by-uuid"
+
+// Volumes returns the mounted volumes that have a UUID. Without udev's
+// UUID links, as in most containers, there are none.
+func Volumes() ([]Volume, error) {
+	links, err := os.ReadDir(uuidDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	uuids := make(map[string]string, len(links))
+	for _, link := range links {
+		if dev, err := filepath.EvalSymlinks(filepath.Join(uuidDir, link.Name())); err == nil {
+			uuids[dev] = link.Name()
+		}
+	}
+
+	table, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	// Mounts may name the device through a link, like /dev/mapper/root
+	for i, m := range table {
+		if dev, err := filepath.EvalSymlinks(m.Source); err == nil {
+			table[i].Source = dev
+		}
+	}
+	return table.Volumes(uuids), nil
+}
@@ -35,21 +35,7 @@ type JSONLFormatter struct{}
 // Format writes the formatted output to the buffer.
 func (f *JSONLFormatter) Format(w *bytes.Buffer, r *Result) error {
 	for _, file := range r.Files {
-		sf := StructuredFile{
-			Path:      file.Path,
-			Name:      file.Name,
-			Dir:       file.Dir,
-			Ext:       file.Ext,
-			Size:      file.Size,
-			SizeHuman: file.SizeHuman,
-			ModTime:   file.ModTime,
-			Age:       FormatDurationString(file.Age),
-			Perms:     file.Perms,
-			Owner:     file.Owner,
-			Depth:     file.Depth,
-		}
-
-		data, err := json.Marshal(sf)
+		data, err := json.Marshal(structuredFile(file))
 		if err != nil {
 			return err
 		}
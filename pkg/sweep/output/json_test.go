@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jamesainslie/sweep/pkg/sweep/dryrun"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +56,32 @@ func TestJSONFormatter_Format_BasicOutput(t *testing.T) {
 	assert.Equal(t, float64(1073741824), file1["size"])
 }
 
+func TestJSONFormatter_Format_MultipleRoots(t *testing.T) {
+	formatter := &JSONFormatter{}
+	var buf bytes.Buffer
+
+	result := &Result{
+		Files: []FileInfo{
+			{Path: "/movies/film.mkv", Size: 4096, SizeHuman: "4.0 KiB", Root: "/movies"},
+		},
+		Roots: []RootSummary{
+			{Path: "/downloads", DirsScanned: 3},
+			{Path: "/movies", Files: 1, TotalSize: 4096, DirsScanned: 5, FilesScanned: 9},
+		},
+	}
+
+	require.NoError(t, formatter.Format(&buf, result))
+	var parsed StructuredOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, "/movies", parsed.Files[0].Root)
+	assert.Equal(t, result.Roots, parsed.Roots)
+
+	// A single root adds neither field
+	buf.Reset()
+	require.NoError(t, formatter.Format(&buf, &Result{Files: []FileInfo{{Path: "/a"}}}))
+	assert.NotContains(t, buf.String(), `"root`)
+}
+
 func TestJSONFormatter_Format_EmptyResult(t *testing.T) {
 	formatter := &JSONFormatter{}
 	var buf bytes.Buffer
@@ -125,6 +152,32 @@ func TestJSONFormatter_Format_Indented(t *testing.T) {
 	assert.Contains(t, output, "{\n")
 }
 
+func TestJSONFormatter_Format_Skipped(t *testing.T) {
+	formatter := &JSONFormatter{}
+	var buf bytes.Buffer
+
+	result := &Result{
+		Stats: ScanStats{
+			Duration: time.Second,
+			Skipped:  map[string]int64{"below_min_size": 12, "symlink": 1},
+		},
+		Source:       "/home/user",
+		SkippedPaths: []SkippedPath{{Path: "/home/user/link", Reason: "symlink"}},
+	}
+
+	require.NoError(t, formatter.Format(&buf, result))
+
+	var parsed StructuredOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, int64(12), parsed.Stats.Skipped["below_min_size"])
+	assert.Equal(t, []SkippedPath{{Path: "/home/user/link", Reason: "symlink"}}, parsed.SkippedPaths)
+
+	// Omitted when there is nothing to report
+	buf.Reset()
+	require.NoError(t, formatter.Format(&buf, &Result{Source: "/home/user"}))
+	assert.NotContains(t, buf.String(), "skipped")
+}
+
 func TestJSONFormatter_Registration(t *testing.T) {
 	formatter, err := Get("json")
 	require.NoError(t, err)
@@ -286,3 +339,36 @@ func TestJSONLFormatter_Registration(t *testing.T) {
 	require.NoError(t, err)
 	assert.IsType(t, &JSONLFormatter{}, formatter)
 }
+
+func TestJSONFormatter_Format_DryRun(t *testing.T) {
+	formatter := &JSONFormatter{}
+	var buf bytes.Buffer
+
+	result := &Result{
+		Files:      []FileInfo{{Path: "/data/a.iso", Size: 100, SizeHuman: "100 B"}},
+		Source:     "/data",
+		TotalFiles: 1,
+		DryRun: &dryrun.Report{
+			Files:      1,
+			Size:       100,
+			Dirs:       []dryrun.Dir{{Path: "/data", Files: 1, Size: 100}},
+			Volumes:    []dryrun.Volume{{Mount: "/", Total: 1000, Free: 100, Reclaimed: 100, FreeAfter: 200, FreePercent: 10, FreePercentAfter: 20}},
+			RecentDays: 7,
+		},
+	}
+	require.NoError(t, formatter.Format(&buf, result))
+
+	var parsed struct {
+		DryRun *dryrun.Report `json:"dry_run"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	require.NotNil(t, parsed.DryRun)
+	assert.Equal(t, "/data", parsed.DryRun.Dirs[0].Path)
+	assert.Equal(t, 20.0, parsed.DryRun.Volumes[0].FreePercentAfter)
+
+	// Without --dry-run there is no projection
+	buf.Reset()
+	result.DryRun = nil
+	require.NoError(t, formatter.Format(&buf, result))
+	assert.NotContains(t, buf.String(), "dry_run")
+}
@@ -25,7 +25,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/dryrun"
 	"github.com/jamesainslie/sweep/pkg/sweep/logging"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 )
 
 // logger is the package-level logger for output operations.
@@ -70,6 +73,17 @@ type FileInfo struct {
 
 	// Depth is the directory depth relative to the scan root.
 	Depth int `json:"depth" yaml:"depth"`
+
+	// Sharing is set when the file shares storage through hard links or
+	// APFS clones.
+	Sharing *sharing.Info `json:"sharing,omitempty" yaml:"sharing,omitempty"`
+
+	// Cloud is set when the file is an online-only cloud placeholder.
+	Cloud *cloud.Info `json:"cloud,omitempty" yaml:"cloud,omitempty"`
+
+	// Root is the scan root the file was found under, set when several
+	// roots were scanned.
+	Root string `json:"root,omitempty" yaml:"root,omitempty"`
 }
 
 // ScanStats contains statistics about a scan operation.
@@ -85,6 +99,10 @@ type ScanStats struct {
 
 	// Duration is the total time taken to complete the scan.
 	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// Skipped counts the files and directories a direct scan left out, by
+	// reason (e.g. "below_min_size"). Daemon results don't have it.
+	Skipped map[string]int64 `json:"skipped,omitempty" yaml:"skipped,omitempty"`
 }
 
 // Result contains the complete output data for formatting.
@@ -117,6 +135,39 @@ type Result struct {
 
 	// Interrupted indicates if the scan was interrupted by the user.
 	Interrupted bool `json:"interrupted" yaml:"interrupted"`
+
+	// Roots totals the files under each root when several were scanned,
+	// in the order they were given.
+	Roots []RootSummary `json:"roots,omitempty" yaml:"roots,omitempty"`
+
+	// SkippedPaths lists each path counted in Stats.Skipped, when the scan
+	// recorded them (--list-skipped).
+	SkippedPaths []SkippedPath `json:"skipped_paths,omitempty" yaml:"skipped_paths,omitempty"`
+
+	// DryRun projects what deleting the files would do, with --dry-run.
+	DryRun *dryrun.Report `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+// SkippedPath is a file or directory a scan left out, and why.
+type SkippedPath struct {
+	Path   string `json:"path" yaml:"path"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// RootSummary totals the results under one of several scan roots.
+type RootSummary struct {
+	// Path is the scan root.
+	Path string `json:"path" yaml:"path"`
+
+	// Files is the number of files in the result under the root.
+	Files int `json:"files" yaml:"files"`
+
+	// TotalSize is the sum of their sizes.
+	TotalSize int64 `json:"total_size" yaml:"total_size"`
+
+	// DirsScanned and FilesScanned count what was examined under the root.
+	DirsScanned  int64 `json:"dirs_scanned" yaml:"dirs_scanned"`
+	FilesScanned int64 `json:"files_scanned" yaml:"files_scanned"`
 }
 
 // TotalSize returns the sum of all file sizes in the result.
@@ -128,6 +179,17 @@ func (r *Result) TotalSize() int64 {
 	return total
 }
 
+// ActualSize returns the sum of all file sizes with storage shared through
+// hard links and APFS clones counted once, and online-only cloud files
+// counted at what they store locally.
+func (r *Result) ActualSize() int64 {
+	var usage sharing.Counter
+	for _, f := range r.Files {
+		usage.Add(f.Sharing, cloud.Local(f.Cloud, f.Size))
+	}
+	return usage.Actual()
+}
+
 // Formatter is the interface that all output formatters must implement.
 type Formatter interface {
 	// Format writes the formatted output to the buffer.
@@ -207,9 +269,12 @@ func Available() []string {
 // structured formatters (JSON, YAML). This type provides dual tags
 // for both JSON and YAML encoding.
 type StructuredOutput struct {
-	Files []StructuredFile `json:"files" yaml:"files"`
-	Stats StructuredStats  `json:"stats" yaml:"stats"`
-	Meta  StructuredMeta   `json:"meta" yaml:"meta"`
+	Files        []StructuredFile `json:"files" yaml:"files"`
+	Roots        []RootSummary    `json:"roots,omitempty" yaml:"roots,omitempty"`
+	Stats        StructuredStats  `json:"stats" yaml:"stats"`
+	Meta         StructuredMeta   `json:"meta" yaml:"meta"`
+	SkippedPaths []SkippedPath    `json:"skipped_paths,omitempty" yaml:"skipped_paths,omitempty"`
+	DryRun       *dryrun.Report   `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
 }
 
 // StructuredFile represents a file in structured output formats.
@@ -225,14 +290,19 @@ type StructuredFile struct {
 	Perms     string    `json:"perms,omitempty" yaml:"perms,omitempty"`
 	Owner     string    `json:"owner,omitempty" yaml:"owner,omitempty"`
 	Depth     int       `json:"depth,omitempty" yaml:"depth,omitempty"`
+	Root      string    `json:"root,omitempty" yaml:"root,omitempty"`
+
+	Sharing *sharing.Info `json:"sharing,omitempty" yaml:"sharing,omitempty"`
+	Cloud   *cloud.Info   `json:"cloud,omitempty" yaml:"cloud,omitempty"`
 }
 
 // StructuredStats represents scan statistics in structured output formats.
 type StructuredStats struct {
-	DirsScanned  int64  `json:"dirs_scanned" yaml:"dirs_scanned"`
-	FilesScanned int64  `json:"files_scanned" yaml:"files_scanned"`
-	LargeFiles   int64  `json:"large_files" yaml:"large_files"`
-	Duration     string `json:"duration" yaml:"duration"`
+	DirsScanned  int64            `json:"dirs_scanned" yaml:"dirs_scanned"`
+	FilesScanned int64            `json:"files_scanned" yaml:"files_scanned"`
+	LargeFiles   int64            `json:"large_files" yaml:"large_files"`
+	Duration     string           `json:"duration" yaml:"duration"`
+	Skipped      map[string]int64 `json:"skipped,omitempty" yaml:"skipped,omitempty"`
 }
 
 // StructuredMeta represents metadata in structured output formats.
@@ -243,6 +313,7 @@ type StructuredMeta struct {
 	WatchActive bool     `json:"watch_active" yaml:"watch_active"`
 	TotalFiles  int      `json:"total_files" yaml:"total_files"`
 	TotalSize   int64    `json:"total_size" yaml:"total_size"`
+	ActualSize  int64    `json:"actual_size" yaml:"actual_size"` // Shared storage counted once, placeholders locally
 	Warnings    []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 	Interrupted bool     `json:"interrupted" yaml:"interrupted"`
 }
@@ -252,19 +323,9 @@ type StructuredMeta struct {
 func BuildStructuredOutput(r *Result) StructuredOutput {
 	files := make([]StructuredFile, len(r.Files))
 	for i, file := range r.Files {
-		files[i] = StructuredFile{
-			Path:      file.Path,
-			Name:      file.Name,
-			Dir:       file.Dir,
-			Ext:       file.Ext,
-			Size:      file.Size,
-			SizeHuman: file.SizeHuman,
-			ModTime:   file.ModTime,
-			Age:       FormatDurationString(file.Age),
-			Perms:     file.Perms,
-			Owner:     file.Owner,
-			Depth:     file.Depth,
-		}
+		files[i] = structuredFile(file)
+		files[i].Sharing = file.Sharing
+		files[i].Cloud = file.Cloud
 	}
 
 	stats := StructuredStats{
@@ -272,6 +333,7 @@ func BuildStructuredOutput(r *Result) StructuredOutput {
 		FilesScanned: r.Stats.FilesScanned,
 		LargeFiles:   r.Stats.LargeFiles,
 		Duration:     FormatDurationString(r.Stats.Duration),
+		Skipped:      r.Stats.Skipped,
 	}
 
 	meta := StructuredMeta{
@@ -281,14 +343,37 @@ func BuildStructuredOutput(r *Result) StructuredOutput {
 		WatchActive: r.WatchActive,
 		TotalFiles:  r.TotalFiles,
 		TotalSize:   r.TotalSize(),
+		ActualSize:  r.ActualSize(),
 		Warnings:    r.Warnings,
 		Interrupted: r.Interrupted,
 	}
 
 	return StructuredOutput{
-		Files: files,
-		Stats: stats,
-		Meta:  meta,
+		Files:        files,
+		Roots:        r.Roots,
+		Stats:        stats,
+		Meta:         meta,
+		SkippedPaths: r.SkippedPaths,
+		DryRun:       r.DryRun,
+	}
+}
+
+// structuredFile converts a FileInfo to a StructuredFile, without sharing
+// and cloud details.
+func structuredFile(file FileInfo) StructuredFile {
+	return StructuredFile{
+		Path:      file.Path,
+		Name:      file.Name,
+		Dir:       file.Dir,
+		Ext:       file.Ext,
+		Size:      file.Size,
+		SizeHuman: file.SizeHuman,
+		ModTime:   file.ModTime,
+		Age:       FormatDurationString(file.Age),
+		Perms:     file.Perms,
+		Owner:     file.Owner,
+		Depth:     file.Depth,
+		Root:      file.Root,
 	}
 }
 
@@ -8,6 +8,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 )
 
 func TestFileInfo(t *testing.T) {
@@ -135,6 +138,36 @@ func TestResult_TotalSize(t *testing.T) {
 	}
 }
 
+func TestResult_ActualSize(t *testing.T) {
+	link := &sharing.Info{Dev: 1, Ino: 42, Links: 2}
+	result := Result{Files: []FileInfo{
+		{Path: "/a.img", Size: 3000, Sharing: link},
+		{Path: "/copy/a.img", Size: 3000, Sharing: link},
+		{Path: "/b.bin", Size: 1000},
+	}}
+	assert.Equal(t, int64(7000), result.TotalSize())
+	assert.Equal(t, int64(4000), result.ActualSize(), "hard links count once")
+
+	meta := BuildStructuredOutput(&result).Meta
+	assert.Equal(t, int64(7000), meta.TotalSize)
+	assert.Equal(t, int64(4000), meta.ActualSize)
+}
+
+func TestResult_ActualSizeCloudPlaceholders(t *testing.T) {
+	online := &cloud.Info{Provider: cloud.ProviderDropbox, OnDisk: 100}
+	result := Result{Files: []FileInfo{
+		{Path: "/Dropbox/film.mov", Size: 5000, Cloud: online},
+		{Path: "/b.bin", Size: 1000},
+	}}
+	assert.Equal(t, int64(6000), result.TotalSize())
+	assert.Equal(t, int64(1100), result.ActualSize(), "placeholders count at their local size")
+
+	structured := BuildStructuredOutput(&result)
+	assert.Equal(t, int64(1100), structured.Meta.ActualSize)
+	assert.Equal(t, online, structured.Files[0].Cloud)
+	assert.Nil(t, structured.Files[1].Cloud)
+}
+
 // mockFormatter is a simple formatter for testing the registry
 type mockFormatter struct {
 	formatCalled bool
@@ -0,0 +1,328 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+// ParquetFormatter writes files as an Apache Parquet table with one row per
+// file, so scans of millions of files can be loaded into DuckDB, pandas, or
+// Spark. Columns are PLAIN encoded and Snappy compressed, in row groups of
+// parquetRowGroupRows rows.
+type ParquetFormatter struct{}
+
+// parquetRowGroupRows bounds the rows per row group so readers can process
+// large scans in parallel.
+const parquetRowGroupRows = 1 << 17
+
+// parquetMagic starts and ends every Parquet file.
+const parquetMagic = "PAR1"
+
+// Parquet physical types, converted types, and enums from parquet.thrift.
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+	parquetNoConverted     = -1
+
+	parquetPlain      = 0
+	parquetRLE        = 3
+	parquetSnappy     = 1
+	parquetDataPage   = 0
+	parquetFileFormat = 1
+)
+
+// parquetColumn describes one column and how to encode its values.
+type parquetColumn struct {
+	name      string
+	typ       int32
+	converted int32
+	encode    func(b *bytes.Buffer, f *FileInfo)
+}
+
+// parquetColumns is the table schema; all columns are required.
+var parquetColumns = []parquetColumn{
+	{"path", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Path) }},
+	{"name", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Name) }},
+	{"dir", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Dir) }},
+	{"ext", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Ext) }},
+	{"size", parquetInt64, parquetNoConverted, func(b *bytes.Buffer, f *FileInfo) { plainInt64(b, f.Size) }},
+	{"mod_time", parquetInt64, parquetTimestampMicros, func(b *bytes.Buffer, f *FileInfo) { plainInt64(b, f.ModTime.UnixMicro()) }},
+	{"perms", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Perms) }},
+	{"owner", parquetByteArray, parquetUTF8, func(b *bytes.Buffer, f *FileInfo) { plainString(b, f.Owner) }},
+	{"depth", parquetInt32, parquetNoConverted, func(b *bytes.Buffer, f *FileInfo) { plainInt32(b, int32(f.Depth)) }},
+}
+
+// parquetChunk records where a column chunk was written.
+type parquetChunk struct {
+	offset       int64
+	uncompressed int64
+	compressed   int64
+	values       int64
+}
+
+// Format writes the formatted output to the buffer.
+func (f *ParquetFormatter) Format(w *bytes.Buffer, r *Result) error {
+	start := w.Len()
+	w.WriteString(parquetMagic)
+
+	var groups [][]parquetChunk
+	for lo := 0; lo < len(r.Files); lo += parquetRowGroupRows {
+		hi := min(lo+parquetRowGroupRows, len(r.Files))
+		rows := r.Files[lo:hi]
+
+		chunks := make([]parquetChunk, len(parquetColumns))
+		for i, col := range parquetColumns {
+			var raw bytes.Buffer
+			for j := range rows {
+				col.encode(&raw, &rows[j])
+			}
+			data := snappy.Encode(nil, raw.Bytes())
+			header := parquetPageHeader(len(rows), raw.Len(), len(data))
+
+			chunks[i] = parquetChunk{
+				offset:       int64(w.Len() - start),
+				uncompressed: int64(len(header) + raw.Len()),
+				compressed:   int64(len(header) + len(data)),
+				values:       int64(len(rows)),
+			}
+			w.Write(header)
+			w.Write(data)
+		}
+		groups = append(groups, chunks)
+	}
+
+	meta := parquetFileMetaData(r, groups)
+	w.Write(meta)
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(meta)))
+	w.Write(n[:])
+	w.WriteString(parquetMagic)
+	return nil
+}
+
+// parquetPageHeader encodes the header of a PLAIN data page.
+func parquetPageHeader(values, uncompressed, compressed int) []byte {
+	t := newThriftWriter()
+	t.i32(1, parquetDataPage)
+	t.i32(2, int32(uncompressed))
+	t.i32(3, int32(compressed))
+	t.beginStruct(5) // DataPageHeader
+	t.i32(1, int32(values))
+	t.i32(2, parquetPlain)
+	t.i32(3, parquetRLE)
+	t.i32(4, parquetRLE)
+	t.endStruct()
+	return t.finish()
+}
+
+// parquetFileMetaData encodes the footer describing the schema and the
+// location of every column chunk.
+func parquetFileMetaData(r *Result, groups [][]parquetChunk) []byte {
+	t := newThriftWriter()
+	t.i32(1, parquetFileFormat)
+
+	t.beginList(2, thriftStruct, len(parquetColumns)+1)
+	t.beginElem()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(parquetColumns)))
+	t.endStruct()
+	for _, col := range parquetColumns {
+		t.beginElem()
+		t.i32(1, col.typ)
+		t.i32(3, 0) // Required
+		t.binary(4, col.name)
+		if col.converted != parquetNoConverted {
+			t.i32(6, col.converted)
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, int64(len(r.Files)))
+
+	t.beginList(4, thriftStruct, len(groups))
+	for g, chunks := range groups {
+		rows := chunks[0].values
+		var uncompressed, compressed int64
+		for _, c := range chunks {
+			uncompressed += c.uncompressed
+			compressed += c.compressed
+		}
+
+		t.beginElem() // RowGroup
+		t.beginList(1, thriftStruct, len(chunks))
+		for i, c := range chunks {
+			col := parquetColumns[i]
+			t.beginElem() // ColumnChunk
+			t.i64(2, c.offset)
+			t.beginStruct(3) // ColumnMetaData
+			t.i32(1, col.typ)
+			t.beginList(2, thriftI32, 1)
+			t.listI32(parquetPlain)
+			t.beginList(3, thriftBinary, 1)
+			t.listString(col.name)
+			t.i32(4, parquetSnappy)
+			t.i64(5, c.values)
+			t.i64(6, c.uncompressed)
+			t.i64(7, c.compressed)
+			t.i64(9, c.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, uncompressed)
+		t.i64(3, rows)
+		t.i64(5, chunks[0].offset)
+		t.i64(6, compressed)
+		t.i16(7, int16(g))
+		t.endStruct()
+	}
+
+	if r.Source != "" {
+		t.beginList(5, thriftStruct, 1)
+		t.beginElem() // KeyValue
+		t.binary(1, "sweep.source")
+		t.binary(2, r.Source)
+		t.endStruct()
+	}
+	t.binary(6, "sweep")
+	return t.finish()
+}
+
+// plainString appends a PLAIN encoded BYTE_ARRAY value.
+func plainString(b *bytes.Buffer, s string) {
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(s)))
+	b.Write(n[:])
+	b.WriteString(s)
+}
+
+// plainInt64 appends a PLAIN encoded INT64 value.
+func plainInt64(b *bytes.Buffer, v int64) {
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(v))
+	b.Write(n[:])
+}
+
+// plainInt32 appends a PLAIN encoded INT32 value.
+func plainInt32(b *bytes.Buffer, v int32) {
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(v))
+	b.Write(n[:])
+}
+
+// Thrift compact protocol type IDs.
+const (
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift compact protocol structs Parquet uses for
+// its metadata. It tracks the last field ID of each open struct, since field
+// headers are written as deltas.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int16{0}}
+}
+
+// finish closes the top-level struct and returns the encoding.
+func (t *thriftWriter) finish() []byte {
+	t.buf.WriteByte(0)
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) uvarint(v uint64) {
+	var n [binary.MaxVarintLen64]byte
+	t.buf.Write(n[:binary.PutUvarint(n[:], v)])
+}
+
+// varint writes a zigzag encoded integer.
+func (t *thriftWriter) varint(v int64) {
+	t.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thriftWriter) i16(id int16, v int16) {
+	t.field(id, thriftI16)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.listString(s)
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// beginElem starts a struct element of a list.
+func (t *thriftWriter) beginElem() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) beginList(id int16, elem byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elem)
+		return
+	}
+	t.buf.WriteByte(0xF0 | elem)
+	t.uvarint(uint64(n))
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) listString(s string) {
+	t.uvarint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func init() {
+	Register("parquet", func() Formatter {
+		return &ParquetFormatter{}
+	})
+}
+
+// Ensure ParquetFormatter implements Formatter.
+var _ Formatter = (*ParquetFormatter)(nil)
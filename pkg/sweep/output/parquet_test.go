@@ -0,0 +1,214 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact protocol structs into maps keyed by
+// field ID, enough to check the metadata the formatter writes.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case thriftI16, thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		h := r.byte()
+		n, elem := int(h>>4), h&0x0F
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.structure()
+	default:
+		panic(fmt.Sprintf("unexpected thrift type %d", typ))
+	}
+}
+
+func (r *thriftReader) structure() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		h := r.byte()
+		if h == 0 {
+			return fields
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(h & 0x0F)
+		last = id
+	}
+}
+
+// readParquet decodes the footer of a Parquet file.
+func readParquet(t *testing.T, data []byte) map[int16]any {
+	t.Helper()
+	require.Equal(t, parquetMagic, string(data[:4]))
+	require.Equal(t, parquetMagic, string(data[len(data)-4:]))
+	n := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := data[len(data)-8-n : len(data)-8]
+	r := &thriftReader{data: footer}
+	meta := r.structure()
+	require.Equal(t, len(footer), r.pos, "footer should be fully consumed")
+	return meta
+}
+
+// readColumn decodes the PLAIN values of a column chunk.
+func readColumn(t *testing.T, data []byte, chunk map[int16]any) []any {
+	t.Helper()
+	cm := chunk[3].(map[int16]any)
+	r := &thriftReader{data: data, pos: int(cm[9].(int64))}
+	header := r.structure()
+	assert.Equal(t, int64(parquetDataPage), header[1])
+
+	page := data[r.pos : r.pos+int(header[3].(int64))]
+	raw, err := snappy.Decode(nil, page)
+	require.NoError(t, err)
+	require.Len(t, raw, int(header[2].(int64)))
+
+	var values []any
+	for len(raw) > 0 {
+		switch cm[1].(int64) {
+		case parquetByteArray:
+			n := binary.LittleEndian.Uint32(raw)
+			values = append(values, string(raw[4:4+n]))
+			raw = raw[4+n:]
+		case parquetInt64:
+			values = append(values, int64(binary.LittleEndian.Uint64(raw)))
+			raw = raw[8:]
+		case parquetInt32:
+			values = append(values, int64(int32(binary.LittleEndian.Uint32(raw))))
+			raw = raw[4:]
+		}
+	}
+	return values
+}
+
+func TestParquetFormatter_Format(t *testing.T) {
+	modTime := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	result := &Result{
+		Files: []FileInfo{
+			{Path: "/data/movie.mkv", Name: "movie.mkv", Dir: "/data", Ext: ".mkv", Size: 4 << 30, ModTime: modTime, Owner: "alice", Depth: 1},
+			{Path: "/data/ünïcode.iso", Name: "ünïcode.iso", Dir: "/data", Ext: ".iso", Size: 1 << 20, ModTime: modTime, Depth: 1},
+		},
+		Source: "/data",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&ParquetFormatter{}).Format(&buf, result))
+	data := buf.Bytes()
+	meta := readParquet(t, data)
+
+	assert.Equal(t, int64(2), meta[3], "num_rows")
+	schema := meta[2].([]any)
+	require.Len(t, schema, len(parquetColumns)+1)
+	assert.Equal(t, int64(len(parquetColumns)), schema[0].(map[int16]any)[5])
+	for i, col := range parquetColumns {
+		el := schema[i+1].(map[int16]any)
+		assert.Equal(t, col.name, el[4])
+		assert.Equal(t, int64(0), el[3], "%s should be required", col.name)
+	}
+	assert.Equal(t, int64(parquetTimestampMicros), schema[6].(map[int16]any)[6])
+
+	groups := meta[4].([]any)
+	require.Len(t, groups, 1)
+	chunks := groups[0].(map[int16]any)[1].([]any)
+	require.Len(t, chunks, len(parquetColumns))
+
+	byName := make(map[string][]any)
+	for i, c := range chunks {
+		byName[parquetColumns[i].name] = readColumn(t, data, c.(map[int16]any))
+	}
+	assert.Equal(t, []any{"/data/movie.mkv", "/data/ünïcode.iso"}, byName["path"])
+	assert.Equal(t, []any{int64(4 << 30), int64(1 << 20)}, byName["size"])
+	assert.Equal(t, []any{modTime.UnixMicro(), modTime.UnixMicro()}, byName["mod_time"])
+	assert.Equal(t, []any{"alice", ""}, byName["owner"])
+	assert.Equal(t, []any{int64(1), int64(1)}, byName["depth"])
+
+	kv := meta[5].([]any)[0].(map[int16]any)
+	assert.Equal(t, "sweep.source", kv[1])
+	assert.Equal(t, "/data", kv[2])
+}
+
+func TestParquetFormatter_RowGroups(t *testing.T) {
+	files := make([]FileInfo, parquetRowGroupRows+3)
+	for i := range files {
+		files[i] = FileInfo{Path: fmt.Sprintf("/f%d", i), Size: int64(i)}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&ParquetFormatter{}).Format(&buf, &Result{Files: files}))
+	data := buf.Bytes()
+	meta := readParquet(t, data)
+
+	groups := meta[4].([]any)
+	require.Len(t, groups, 2)
+	assert.Equal(t, int64(parquetRowGroupRows), groups[0].(map[int16]any)[3])
+	assert.Equal(t, int64(3), groups[1].(map[int16]any)[3])
+
+	last := groups[1].(map[int16]any)[1].([]any)[0].(map[int16]any)
+	assert.Equal(t, []any{
+		fmt.Sprintf("/f%d", parquetRowGroupRows),
+		fmt.Sprintf("/f%d", parquetRowGroupRows+1),
+		fmt.Sprintf("/f%d", parquetRowGroupRows+2),
+	}, readColumn(t, data, last))
+}
+
+func TestParquetFormatter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&ParquetFormatter{}).Format(&buf, &Result{}))
+	meta := readParquet(t, buf.Bytes())
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+	assert.NotContains(t, meta, int16(5), "no source metadata without a source")
+}
+
+func TestParquetFormatter_Registration(t *testing.T) {
+	formatter, err := Get("parquet")
+	require.NoError(t, err)
+	assert.IsType(t, &ParquetFormatter{}, formatter)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/dryrun"
 )
 
 // PrettyFormatter formats output with colors and styling using lipgloss.
@@ -28,6 +29,11 @@ func (f *PrettyFormatter) Format(w *bytes.Buffer, r *Result) error {
 	footer := f.formatFooter(r)
 	w.WriteString(footer)
 
+	if r.DryRun != nil {
+		w.WriteString("\n")
+		w.WriteString(f.formatDryRun(r.DryRun))
+	}
+
 	// Add warnings if any
 	if len(r.Warnings) > 0 {
 		warnings := f.formatWarnings(r.Warnings)
@@ -42,10 +48,20 @@ func (f *PrettyFormatter) Format(w *bytes.Buffer, r *Result) error {
 func (f *PrettyFormatter) formatHeader(r *Result) string {
 	var lines []string
 
-	// Source line
+	// Source line, or a line per root with its share of the results
 	sourceLabel := LabelStyle.Render("Source:")
-	sourceValue := ValueStyle.Render(r.Source)
-	lines = append(lines, fmt.Sprintf("%s %s", sourceLabel, sourceValue))
+	if len(r.Roots) == 0 {
+		sourceValue := ValueStyle.Render(r.Source)
+		lines = append(lines, fmt.Sprintf("%s %s", sourceLabel, sourceValue))
+	}
+	for i, root := range r.Roots {
+		label := strings.Repeat(" ", lipgloss.Width(sourceLabel))
+		if i == 0 {
+			label = sourceLabel
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s", label, ValueStyle.Render(root.Path),
+			MutedStyle.Render(fmt.Sprintf("(%d files, %s)", root.Files, humanize.IBytes(uint64(root.TotalSize))))))
+	}
 
 	// Index age and scan info line
 	var infoParts []string
@@ -118,6 +134,10 @@ func (f *PrettyFormatter) formatTable(r *Result) string {
 	for _, file := range r.Files {
 		sizeStr := SizeStyle.Render(padLeft(file.SizeHuman, maxSizeWidth))
 		pathStr := PathStyle.Render(file.Path)
+		if file.Cloud != nil {
+			// Deleting a placeholder frees next to nothing
+			pathStr += MutedStyle.Render(" (" + file.Cloud.String() + ")")
+		}
 		sb.WriteString(fmt.Sprintf("  %s  %s\n", sizeStr, pathStr))
 	}
 
@@ -137,6 +157,9 @@ func (f *PrettyFormatter) formatFooter(r *Result) string {
 	totalSize := r.TotalSize()
 	totalSizeLabel := LabelStyle.Render("Total:")
 	totalSizeValue := SizeStyle.Render(humanize.IBytes(uint64(totalSize)))
+	if actual := r.ActualSize(); actual < totalSize {
+		totalSizeValue += MutedStyle.Render(fmt.Sprintf(" (%s on disk)", humanize.IBytes(uint64(actual))))
+	}
 	parts = append(parts, fmt.Sprintf("%s %s", totalSizeLabel, totalSizeValue))
 
 	// Hints
@@ -147,6 +170,43 @@ func (f *PrettyFormatter) formatFooter(r *Result) string {
 	return FooterBox.Render(content)
 }
 
+// dryRunDirs is how many directories the dry-run block lists.
+const dryRunDirs = 5
+
+// formatDryRun builds the block projecting what deleting the files would
+// do: the directories freed most, each volume's free space before and
+// after, and the recently modified files.
+func (f *PrettyFormatter) formatDryRun(d *dryrun.Report) string {
+	var sb strings.Builder
+	sb.WriteString(TitleStyle.Render("Dry run:"))
+	sb.WriteString(ValueStyle.Render(fmt.Sprintf(" deleting these %d files would free %s", d.Files, humanize.IBytes(uint64(d.Size)))))
+	sb.WriteString("\n")
+
+	for i, dir := range d.Dirs {
+		if i == dryRunDirs {
+			sb.WriteString(MutedStyle.Render(fmt.Sprintf("  ... and %d more directories", len(d.Dirs)-dryRunDirs)))
+			sb.WriteString("\n")
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  %s  %s %s\n", SizeStyle.Render(padLeft(humanize.IBytes(uint64(dir.Size)), 8)),
+			PathStyle.Render(dir.Path), MutedStyle.Render(fmt.Sprintf("(%d files)", dir.Files))))
+	}
+	for _, v := range d.Volumes {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", LabelStyle.Render(v.Mount+":"),
+			ValueStyle.Render(fmt.Sprintf("%.1f%% free, %.1f%% after (%s of %s)",
+				v.FreePercent, v.FreePercentAfter, humanize.IBytes(uint64(v.FreeAfter)), humanize.IBytes(uint64(v.Total))))))
+	}
+	if len(d.Recent) > 0 {
+		sb.WriteString(WarningStyle.Render(fmt.Sprintf("  %d files were modified in the last %d days:", len(d.Recent), d.RecentDays)))
+		sb.WriteString("\n")
+		for _, file := range d.Recent {
+			sb.WriteString(WarningStyle.Render("    " + file.Path))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
 // formatWarnings builds a warning block.
 func (f *PrettyFormatter) formatWarnings(warnings []string) string {
 	var sb strings.Builder
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Formats of the reports commands other than scans write, such as
+// sweep rules run or sweep summary. Packages add their own where needed.
+const (
+	ReportText = "text"
+	ReportJSON = "json"
+	ReportCSV  = "csv"
+)
+
+// ErrUnknownReportFormat is returned by WriteReport for unsupported formats.
+var ErrUnknownReportFormat = errors.New("unknown report format")
+
+// ReportWriters maps the formats a report can be written in to the
+// functions that write it.
+type ReportWriters[T any] map[string]func(io.Writer, T) error
+
+// WriteReport writes report in format with the matching writer. "", and
+// the scan formats "pretty" and "plain", mean ReportText.
+func WriteReport[T any](w io.Writer, format string, report T, writers ReportWriters[T]) error {
+	switch format {
+	case "", "pretty", "plain":
+		format = ReportText
+	}
+	if write, ok := writers[format]; ok {
+		return write(w, report)
+	}
+	available := slices.Sorted(maps.Keys(writers))
+	return fmt.Errorf("%w: %q (available: %s)", ErrUnknownReportFormat, format, strings.Join(available, ", "))
+}
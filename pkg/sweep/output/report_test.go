@@ -0,0 +1,40 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReport(t *testing.T) {
+	writers := ReportWriters[int]{
+		ReportText: func(w io.Writer, n int) error {
+			_, err := fmt.Fprintf(w, "text %d", n)
+			return err
+		},
+		ReportJSON: func(w io.Writer, n int) error {
+			_, err := fmt.Fprintf(w, "{\"n\": %d}", n)
+			return err
+		},
+	}
+
+	for format, want := range map[string]string{
+		"":         "text 7",
+		"pretty":   "text 7",
+		"plain":    "text 7",
+		ReportText: "text 7",
+		ReportJSON: `{"n": 7}`,
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteReport(&buf, format, 7, writers), format)
+		assert.Equal(t, want, buf.String(), format)
+	}
+
+	err := WriteReport(&bytes.Buffer{}, "xml", 7, writers)
+	assert.ErrorIs(t, err, ErrUnknownReportFormat)
+	assert.ErrorContains(t, err, `"xml" (available: json, text)`)
+}
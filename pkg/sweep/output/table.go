@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/csv"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // TSVFormatter formats output as tab-separated values.
@@ -34,21 +36,50 @@ func init() {
 var _ Formatter = (*TSVFormatter)(nil)
 
 // CSVFormatter formats output as comma-separated values with proper quoting.
-// It uses encoding/csv for RFC 4180 compliant output.
+// It uses encoding/csv for RFC 4180 compliant output. Sizes are written in
+// bytes and times in RFC 3339 so spreadsheets can sort and compute on them.
 type CSVFormatter struct{}
 
+// csvHeader names the CSV columns, matching the JSON field names. A root
+// column is added when several roots were scanned.
+var csvHeader = []string{"path", "name", "dir", "ext", "size", "size_human", "mod_time", "perms", "owner", "depth"}
+
 // Format writes the formatted output to the buffer.
 func (f *CSVFormatter) Format(w *bytes.Buffer, r *Result) error {
 	writer := csv.NewWriter(w)
+	multiRoot := len(r.Roots) > 0
 
 	// Write header
-	if err := writer.Write([]string{"SIZE", "PATH"}); err != nil {
+	header := csvHeader
+	if multiRoot {
+		header = append(header[:len(header):len(header)], "root")
+	}
+	if err := writer.Write(header); err != nil {
 		return err
 	}
 
 	// Write data rows
 	for _, file := range r.Files {
-		if err := writer.Write([]string{file.SizeHuman, file.Path}); err != nil {
+		var modTime string
+		if !file.ModTime.IsZero() {
+			modTime = file.ModTime.Format(time.RFC3339)
+		}
+		record := []string{
+			file.Path,
+			file.Name,
+			file.Dir,
+			file.Ext,
+			strconv.FormatInt(file.Size, 10),
+			file.SizeHuman,
+			modTime,
+			file.Perms,
+			file.Owner,
+			strconv.Itoa(file.Depth),
+		}
+		if multiRoot {
+			record = append(record, file.Root)
+		}
+		if err := writer.Write(record); err != nil {
 			return err
 		}
 	}
@@ -128,12 +128,61 @@ func TestCSVFormatter_Format_BasicOutput(t *testing.T) {
 	require.Len(t, records, 3)
 
 	// Verify header
-	assert.Equal(t, "SIZE", records[0][0])
-	assert.Equal(t, "PATH", records[0][1])
+	assert.Equal(t, csvHeader, records[0])
 
-	// Verify data
-	assert.Equal(t, "1.0 GiB", records[1][0])
-	assert.Equal(t, "/home/user/large.zip", records[1][1])
+	// Verify data: sizes in bytes for sorting, plus the human-readable size
+	assert.Equal(t, "/home/user/large.zip", records[1][0])
+	assert.Equal(t, "1073741824", records[1][4])
+	assert.Equal(t, "1.0 GiB", records[1][5])
+	assert.Equal(t, "", records[1][6], "zero mod time should be empty")
+}
+
+func TestCSVFormatter_Format_AllColumns(t *testing.T) {
+	formatter := &CSVFormatter{}
+	var buf bytes.Buffer
+
+	modTime := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	result := &Result{
+		Files: []FileInfo{{
+			Path: "/data/logs/app.log", Name: "app.log", Dir: "/data/logs", Ext: ".log",
+			Size: 2048, SizeHuman: "2.0 KiB", ModTime: modTime,
+			Perms: "-rw-r--r--", Owner: "alice", Depth: 2,
+		}},
+	}
+
+	require.NoError(t, formatter.Format(&buf, result))
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{
+		"/data/logs/app.log", "app.log", "/data/logs", ".log", "2048", "2.0 KiB",
+		"2025-03-14T15:09:26Z", "-rw-r--r--", "alice", "2",
+	}, records[1])
+}
+
+func TestCSVFormatter_Format_MultipleRoots(t *testing.T) {
+	formatter := &CSVFormatter{}
+	var buf bytes.Buffer
+
+	result := &Result{
+		Files: []FileInfo{
+			{Path: "/movies/film.mkv", Size: 4096, SizeHuman: "4.0 KiB", Root: "/movies"},
+			{Path: "/downloads/setup.dmg", Size: 2048, SizeHuman: "2.0 KiB", Root: "/downloads"},
+		},
+		Roots: []RootSummary{
+			{Path: "/downloads", Files: 1, TotalSize: 2048},
+			{Path: "/movies", Files: 1, TotalSize: 4096},
+		},
+	}
+
+	require.NoError(t, formatter.Format(&buf, result))
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "root", records[0][len(records[0])-1])
+	assert.Equal(t, "/movies", records[1][len(records[1])-1])
+	assert.Equal(t, "/downloads", records[2][len(records[2])-1])
+	assert.Len(t, csvHeader, 10, "the shared header must not grow")
 }
 
 func TestCSVFormatter_Format_EmptyResult(t *testing.T) {
@@ -186,9 +235,9 @@ func TestCSVFormatter_Format_QuotedFields(t *testing.T) {
 	require.Len(t, records, 4) // header + 3 data
 
 	// Verify special characters are preserved
-	assert.Equal(t, "/home/user/file,with,commas.zip", records[1][1])
-	assert.Equal(t, "/home/user/file\"with\"quotes.zip", records[2][1])
-	assert.Equal(t, "/home/user/file\nwith\nnewlines.zip", records[3][1])
+	assert.Equal(t, "/home/user/file,with,commas.zip", records[1][0])
+	assert.Equal(t, "/home/user/file\"with\"quotes.zip", records[2][0])
+	assert.Equal(t, "/home/user/file\nwith\nnewlines.zip", records[3][0])
 }
 
 func TestCSVFormatter_Registration(t *testing.T) {
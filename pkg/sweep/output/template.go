@@ -21,7 +21,8 @@ type TemplateFormatter struct {
 // It wraps Result to add computed fields.
 type templateData struct {
 	*Result
-	TotalSize int64
+	TotalSize  int64
+	ActualSize int64
 }
 
 // NewTemplateFormatter creates a new template formatter with the given template string.
@@ -75,8 +76,9 @@ func (f *TemplateFormatter) Format(w *bytes.Buffer, r *Result) error {
 
 	// Prepare data with computed fields
 	data := templateData{
-		Result:    r,
-		TotalSize: r.TotalSize(),
+		Result:     r,
+		TotalSize:  r.TotalSize(),
+		ActualSize: r.ActualSize(),
 	}
 
 	return f.template.Execute(w, data)
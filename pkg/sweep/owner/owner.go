@@ -0,0 +1,158 @@
+// Package owner restricts scans to files owned by a single user, for shared
+// hosts where users only care about their own files.
+package owner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by Parse.
+var (
+	ErrInvalidSpec = errors.New("invalid owner")
+	ErrUnsupported = errors.New("owner filtering is not supported on this platform")
+)
+
+// Filter matches files owned by one user.
+type Filter struct {
+	UID  uint32
+	Name string // Username, empty if the UID has no account
+
+	// groups holds the user's group IDs; nil if they could not be resolved.
+	groups map[uint32]bool
+}
+
+// Parse builds a filter from an owner spec:
+//
+//	me         the current user
+//	uid:1000   a numeric user ID
+//	alice      a username
+func Parse(spec string) (*Filter, error) {
+	if !supported {
+		return nil, ErrUnsupported
+	}
+
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return nil, fmt.Errorf("%w: empty", ErrInvalidSpec)
+	case spec == "me":
+		return ForUID(uint32(os.Getuid())), nil
+	case strings.HasPrefix(spec, "uid:"):
+		uid, err := strconv.ParseUint(strings.TrimPrefix(spec, "uid:"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: expected uid:<number>", ErrInvalidSpec, spec)
+		}
+		return ForUID(uint32(uid)), nil
+	default:
+		u, err := user.Lookup(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidSpec, spec, err)
+		}
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: non-numeric uid %s", ErrInvalidSpec, spec, u.Uid)
+		}
+		return ForUID(uint32(uid)), nil
+	}
+}
+
+// ForUID returns a filter for uid, resolving its username and groups when
+// the account exists.
+func ForUID(uid uint32) *Filter {
+	f := &Filter{UID: uid}
+	u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10))
+	if err != nil {
+		return f
+	}
+	f.Name = u.Username
+	if gids, err := u.GroupIds(); err == nil {
+		f.groups = make(map[uint32]bool, len(gids))
+		for _, g := range gids {
+			if gid, err := strconv.ParseUint(g, 10, 32); err == nil {
+				f.groups[uint32(gid)] = true
+			}
+		}
+	}
+	return f
+}
+
+// String describes the filter, e.g. "alice (uid 1000)".
+func (f *Filter) String() string {
+	if f.Name == "" {
+		return fmt.Sprintf("uid %d", f.UID)
+	}
+	return fmt.Sprintf("%s (uid %d)", f.Name, f.UID)
+}
+
+// Owns reports whether the file belongs to the filter's user.
+// Files whose ownership cannot be determined are kept.
+func (f *Filter) Owns(info fs.FileInfo) bool {
+	uid, _, ok := ids(info)
+	return !ok || uid == f.UID
+}
+
+// OwnsPath reports whether the file at path belongs to the filter's user.
+// Files that cannot be stat-ed are dropped.
+func (f *Filter) OwnsPath(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	return f.Owns(info)
+}
+
+// CanEnter reports whether the user has search permission on a directory.
+// A directory the user cannot enter cannot hold files they created, so a
+// scan can skip it without reading it. Group permissions count only when
+// the user's groups are known to include the directory's group; when they
+// are unknown, any group or other search bit is enough.
+func (f *Filter) CanEnter(info fs.FileInfo) bool {
+	if !info.IsDir() || f.UID == 0 {
+		return true
+	}
+	uid, gid, ok := ids(info)
+	if !ok {
+		return true
+	}
+
+	perm := info.Mode().Perm()
+	switch {
+	case uid == f.UID:
+		return perm&0o100 != 0
+	case f.groups == nil:
+		return perm&0o011 != 0
+	case f.groups[gid]:
+		return perm&0o010 != 0
+	default:
+		return perm&0o001 != 0
+	}
+}
+
+// Names resolves file owners to usernames, remembering each lookup so
+// listing many files costs one lookup per user. The zero value is not
+// usable; make one with make(Names).
+type Names map[uint32]string
+
+// Of returns the username of the file's owner, or its UID when the UID has
+// no account. It returns "" when ownership cannot be determined.
+func (n Names) Of(info fs.FileInfo) string {
+	uid, _, ok := ids(info)
+	if !ok {
+		return ""
+	}
+	name, ok := n[uid]
+	if !ok {
+		name = strconv.FormatUint(uint64(uid), 10)
+		if u, err := user.LookupId(name); err == nil {
+			name = u.Username
+		}
+		n[uid] = name
+	}
+	return name
+}
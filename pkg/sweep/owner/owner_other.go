@@ -0,0 +1,13 @@
+//go:build !unix
+
+package owner
+
+import "io/fs"
+
+// supported reports whether file ownership is available on this platform.
+const supported = false
+
+// ids is unavailable on platforms without Unix file ownership.
+func ids(fs.FileInfo) (uid, gid uint32, ok bool) {
+	return 0, 0, false
+}
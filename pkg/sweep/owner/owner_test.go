@@ -0,0 +1,146 @@
+//go:build unix
+
+package owner
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// statInfo is a fs.FileInfo with fixed ownership and permissions.
+type statInfo struct {
+	mode     fs.FileMode
+	uid, gid uint32
+}
+
+func (s statInfo) Name() string       { return "entry" }
+func (s statInfo) Size() int64        { return 0 }
+func (s statInfo) Mode() fs.FileMode  { return s.mode }
+func (s statInfo) ModTime() time.Time { return time.Time{} }
+func (s statInfo) IsDir() bool        { return s.mode.IsDir() }
+func (s statInfo) Sys() any           { return &syscall.Stat_t{Uid: s.uid, Gid: s.gid} }
+
+func TestParse(t *testing.T) {
+	t.Run("me", func(t *testing.T) {
+		f, err := Parse("me")
+		if err != nil {
+			t.Fatalf("Parse(me) error = %v", err)
+		}
+		if f.UID != uint32(os.Getuid()) {
+			t.Errorf("UID = %d, want %d", f.UID, os.Getuid())
+		}
+	})
+
+	t.Run("uid", func(t *testing.T) {
+		f, err := Parse("uid:4242")
+		if err != nil {
+			t.Fatalf("Parse(uid:4242) error = %v", err)
+		}
+		if f.UID != 4242 {
+			t.Errorf("UID = %d, want 4242", f.UID)
+		}
+	})
+
+	t.Run("username", func(t *testing.T) {
+		f, err := Parse("root")
+		if err != nil {
+			t.Skipf("no root account: %v", err)
+		}
+		if f.UID != 0 || f.Name != "root" {
+			t.Errorf("got %s, want root (uid 0)", f)
+		}
+	})
+
+	for _, spec := range []string{"", "uid:", "uid:abc", "uid:-1", "no-such-user-sweep"} {
+		t.Run("invalid "+spec, func(t *testing.T) {
+			if _, err := Parse(spec); !errors.Is(err, ErrInvalidSpec) {
+				t.Errorf("Parse(%q) error = %v, want ErrInvalidSpec", spec, err)
+			}
+		})
+	}
+}
+
+func TestFilterString(t *testing.T) {
+	if got := (&Filter{UID: 1000, Name: "alice"}).String(); got != "alice (uid 1000)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (&Filter{UID: 4242}).String(); got != "uid 4242" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestFilterOwns(t *testing.T) {
+	f := &Filter{UID: 1000}
+	if !f.Owns(statInfo{uid: 1000}) {
+		t.Error("expected file owned by uid 1000 to match")
+	}
+	if f.Owns(statInfo{uid: 1001}) {
+		t.Error("expected file owned by uid 1001 not to match")
+	}
+}
+
+func TestFilterOwnsPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	me := ForUID(uint32(os.Getuid()))
+	if !me.OwnsPath(path) {
+		t.Error("expected own file to match")
+	}
+	if me.OwnsPath(path + ".missing") {
+		t.Error("expected missing file not to match")
+	}
+	if (&Filter{UID: uint32(os.Getuid()) + 1}).OwnsPath(path) {
+		t.Error("expected file not to match another uid")
+	}
+}
+
+func TestFilterCanEnter(t *testing.T) {
+	member := &Filter{UID: 1000, groups: map[uint32]bool{100: true}}
+	unknown := &Filter{UID: 1000}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		info   statInfo
+		want   bool
+	}{
+		{"own private dir", member, statInfo{fs.ModeDir | 0o700, 1000, 1000}, true},
+		{"own dir without search", member, statInfo{fs.ModeDir | 0o600, 1000, 1000}, false},
+		{"other private dir", member, statInfo{fs.ModeDir | 0o700, 1001, 1001}, false},
+		{"other public dir", member, statInfo{fs.ModeDir | 0o755, 1001, 1001}, true},
+		{"group dir as member", member, statInfo{fs.ModeDir | 0o750, 1001, 100}, true},
+		{"group dir as non-member", member, statInfo{fs.ModeDir | 0o750, 1001, 200}, false},
+		{"group dir with unknown groups", unknown, statInfo{fs.ModeDir | 0o750, 1001, 200}, true},
+		{"private dir with unknown groups", unknown, statInfo{fs.ModeDir | 0o700, 1001, 200}, false},
+		{"files are never skipped", member, statInfo{0o600, 1001, 1001}, true},
+		{"root enters everything", &Filter{UID: 0}, statInfo{fs.ModeDir | 0o700, 1001, 1001}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.CanEnter(tt.info); got != tt.want {
+				t.Errorf("CanEnter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamesOf(t *testing.T) {
+	names := make(Names)
+	if got := names.Of(statInfo{0o644, 0, 0}); got != "root" {
+		t.Errorf("Of(uid 0) = %q, want root", got)
+	}
+	// UIDs without an account fall back to the number
+	if got := names.Of(statInfo{0o644, 4242424, 0}); got != "4242424" {
+		t.Errorf("Of(uid 4242424) = %q, want 4242424", got)
+	}
+	if len(names) != 2 {
+		t.Errorf("cached %d names, want 2", len(names))
+	}
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package owner
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// supported reports whether file ownership is available on this platform.
+const supported = true
+
+// ids returns the owning user and group of a file.
+func ids(info fs.FileInfo) (uid, gid uint32, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return stat.Uid, stat.Gid, true
+}
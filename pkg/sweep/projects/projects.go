@@ -0,0 +1,218 @@
+// Package projects finds software projects under a directory and measures
+// the dependency and build directories they can regenerate, such as
+// node_modules and target, so stale projects can be cleaned in bulk.
+package projects
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is a type of project, recognized by a marker file in its root.
+type Kind struct {
+	Name      string
+	Markers   []string // Files, any of which marks a project root
+	Artifacts []string // Directories in the root that a build or install regenerates
+}
+
+// Kinds are the projects Find recognizes.
+var Kinds = []Kind{
+	{Name: "node", Markers: []string{"package.json"}, Artifacts: []string{"node_modules", "dist", ".next", ".nuxt", ".parcel-cache", ".turbo"}},
+	{Name: "rust", Markers: []string{"Cargo.toml"}, Artifacts: []string{"target"}},
+	{Name: "go", Markers: []string{"go.mod"}, Artifacts: []string{"vendor"}}, // go mod vendor
+	{Name: "python", Markers: []string{"pyproject.toml", "setup.py", "requirements.txt"}, Artifacts: []string{".venv", "venv", ".tox", ".pytest_cache", ".mypy_cache", "__pycache__"}},
+	{Name: "maven", Markers: []string{"pom.xml"}, Artifacts: []string{"target"}},
+	{Name: "gradle", Markers: []string{"build.gradle", "build.gradle.kts"}, Artifacts: []string{"build", ".gradle"}},
+}
+
+// Options configures Find.
+type Options struct {
+	Exclude []string // Glob patterns or path prefixes to skip
+}
+
+// Artifact is a regenerable directory in a project.
+type Artifact struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Project is a project root and its artifact directories.
+type Project struct {
+	Path      string     `json:"path"`
+	Kinds     []string   `json:"kinds"`
+	Artifacts []Artifact `json:"artifacts"`
+	Size      int64      `json:"size"` // Of all artifacts
+	// LastUsed is the newest modification of a file outside the artifacts,
+	// including nested projects, or of the .git directory, which commits
+	// and checkouts touch. Builds and installs alone don't count.
+	LastUsed time.Time `json:"last_used"`
+}
+
+// UnusedSince reports whether the project was last used before t.
+func (p Project) UnusedSince(t time.Time) bool {
+	return p.LastUsed.Before(t)
+}
+
+// Find walks root and returns the projects under it that have artifact
+// directories, largest first. Artifact directories are not searched for
+// further projects.
+func Find(ctx context.Context, root string, opts Options) ([]Project, error) {
+	f := finder{ctx: ctx, root: filepath.Clean(root), exclude: opts.Exclude}
+	if err := f.walk(f.root, nil); err != nil {
+		return nil, err
+	}
+
+	var found []Project
+	for _, p := range f.projects {
+		if len(p.Artifacts) > 0 {
+			found = append(found, *p)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Size != found[j].Size {
+			return found[i].Size > found[j].Size
+		}
+		return found[i].Path < found[j].Path
+	})
+	return found, nil
+}
+
+// finder holds the state of a walk.
+type finder struct {
+	ctx      context.Context
+	root     string
+	exclude  []string
+	projects []*Project
+}
+
+// walk searches dir, whose enclosing projects are open, innermost last.
+func (f *finder) walk(dir string, open []*Project) error {
+	if err := f.ctx.Err(); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if dir == f.root {
+			return err
+		}
+		return nil // Unreadable directory; skip it
+	}
+
+	artifacts := f.open(dir, entries)
+	if artifacts != nil {
+		open = append(slices.Clip(open), f.projects[len(f.projects)-1])
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if excluded(path, f.exclude) {
+			continue
+		}
+		switch {
+		case e.IsDir() && artifacts[e.Name()]:
+			p := open[len(open)-1]
+			size := dirSize(path)
+			p.Artifacts = append(p.Artifacts, Artifact{Path: path, Size: size})
+			p.Size += size
+		case e.IsDir() && e.Name() == ".git":
+			touch(open, e)
+		case e.IsDir():
+			if err := f.walk(path, open); err != nil {
+				return err
+			}
+		case e.Type().IsRegular():
+			touch(open, e)
+		}
+	}
+	return nil
+}
+
+// open starts a project at dir if its entries include a marker file, and
+// returns the names of the project's artifact directories, or nil if dir
+// is not a project root.
+func (f *finder) open(dir string, entries []fs.DirEntry) map[string]bool {
+	var p *Project
+	var artifacts map[string]bool
+	for _, k := range Kinds {
+		if !slices.ContainsFunc(entries, func(e fs.DirEntry) bool {
+			return !e.IsDir() && slices.Contains(k.Markers, e.Name())
+		}) {
+			continue
+		}
+		if p == nil {
+			p = &Project{Path: dir}
+			artifacts = make(map[string]bool)
+			f.projects = append(f.projects, p)
+		}
+		p.Kinds = append(p.Kinds, k.Name)
+		for _, name := range k.Artifacts {
+			artifacts[name] = true
+		}
+	}
+	return artifacts
+}
+
+// touch counts e's modification time towards the last use of the open
+// projects.
+func touch(open []*Project, e fs.DirEntry) {
+	if len(open) == 0 {
+		return
+	}
+	info, err := e.Info()
+	if err != nil {
+		return
+	}
+	for _, p := range open {
+		if info.ModTime().After(p.LastUsed) {
+			p.LastUsed = info.ModTime()
+		}
+	}
+}
+
+// dirSize returns the total size of regular files under dir. Unreadable
+// entries are skipped.
+func dirSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// excluded reports whether path matches an exclusion pattern, either as a
+// path prefix or as a glob against the base name or full path.
+func excluded(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		if path == p || strings.HasPrefix(path, p+string(filepath.Separator)) {
+			return true
+		}
+		if ok, err := filepath.Match(p, filepath.Base(path)); err == nil && ok {
+			return true
+		}
+		if ok, err := filepath.Match(p, path); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package projects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+)
+
+var testNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func byPath(found []Project) map[string]Project {
+	m := make(map[string]Project, len(found))
+	for _, p := range found {
+		m[p.Path] = p
+	}
+	return m
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	old := testNow.Add(-365 * 24 * time.Hour)
+	recent := testNow.Add(-24 * time.Hour)
+
+	// A monorepo whose nested package was edited recently
+	mono := filepath.Join(root, "mono")
+	writeFile(t, filepath.Join(mono, "package.json"), 10, old)
+	writeFile(t, filepath.Join(mono, "node_modules", "react", "index.js"), 5000, recent)
+	writeFile(t, filepath.Join(mono, "node_modules", "x", "package.json"), 10, recent)
+	writeFile(t, filepath.Join(mono, "packages", "ui", "package.json"), 10, old)
+	writeFile(t, filepath.Join(mono, "packages", "ui", "src", "app.js"), 10, recent)
+	writeFile(t, filepath.Join(mono, "packages", "ui", "dist", "app.min.js"), 300, recent)
+
+	// An old Rust project rebuilt recently, which doesn't count as use
+	crate := filepath.Join(root, "crate")
+	writeFile(t, filepath.Join(crate, "Cargo.toml"), 10, old)
+	writeFile(t, filepath.Join(crate, "src", "main.rs"), 10, old)
+	writeFile(t, filepath.Join(crate, "target", "debug", "crate"), 2000, recent)
+
+	// A project with nothing to clean, and a stray node_modules outside any project
+	writeFile(t, filepath.Join(root, "tool", "go.mod"), 10, old)
+	writeFile(t, filepath.Join(root, "stray", "node_modules", "a.js"), 100, old)
+
+	found, err := Find(context.Background(), root, Options{})
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+	got := byPath(found)
+
+	assert.Equal(t, mono, found[0].Path, "largest first")
+	assert.Equal(t, int64(5010), got[mono].Size, "node_modules is not searched for projects")
+	assert.Equal(t, []string{"node"}, got[mono].Kinds)
+	assert.True(t, got[mono].LastUsed.Equal(recent), "nested project use counts for the monorepo")
+
+	ui := got[filepath.Join(mono, "packages", "ui")]
+	assert.Equal(t, []Artifact{{Path: filepath.Join(mono, "packages", "ui", "dist"), Size: 300}}, ui.Artifacts)
+
+	assert.Equal(t, int64(2000), got[crate].Size)
+	assert.True(t, got[crate].LastUsed.Equal(old), "builds don't count as use")
+	assert.True(t, got[crate].UnusedSince(testNow.Add(-90*24*time.Hour)))
+	assert.False(t, got[mono].UnusedSince(testNow.Add(-90*24*time.Hour)))
+
+	found, err = Find(context.Background(), root, Options{Exclude: []string{crate}})
+	require.NoError(t, err)
+	assert.NotContains(t, byPath(found), crate)
+}
+
+func TestFindGitCountsAsUse(t *testing.T) {
+	root := t.TempDir()
+	old := testNow.Add(-365 * 24 * time.Hour)
+	committed := testNow.Add(-time.Hour)
+
+	writeFile(t, filepath.Join(root, "pyproject.toml"), 10, old)
+	writeFile(t, filepath.Join(root, ".venv", "lib", "site.py"), 100, old)
+	writeFile(t, filepath.Join(root, ".git", "objects", "ab", "cdef"), 100, old)
+	require.NoError(t, os.Chtimes(filepath.Join(root, ".git"), committed, committed))
+
+	found, err := Find(context.Background(), root, Options{})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.True(t, found[0].LastUsed.Equal(committed))
+}
+
+func TestFindCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Find(ctx, t.TempDir(), Options{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWrite(t *testing.T) {
+	found := []Project{{
+		Path:      "/src/app",
+		Kinds:     []string{"node"},
+		Artifacts: []Artifact{{Path: "/src/app/node_modules", Size: 2 << 20}},
+		Size:      2 << 20,
+		LastUsed:  testNow.Add(-48 * time.Hour),
+	}}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, output.ReportText, found, reltime.Lookup("en"), testNow))
+	assert.Contains(t, text.String(), "node_modules")
+	assert.Contains(t, text.String(), "/src/app")
+	assert.Contains(t, text.String(), "1 project(s)")
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, output.ReportJSON, found, reltime.Locale{}, testNow))
+	var decoded []Project
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, found[0].Path, decoded[0].Path)
+
+	assert.ErrorIs(t, Write(&out, "csv", found, reltime.Locale{}, testNow), output.ErrUnknownReportFormat)
+}
@@ -0,0 +1,66 @@
+package projects
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/reltime"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Write renders projects in the given format. Text reports describe last
+// use relative to now in locale.
+func Write(w io.Writer, format string, projects []Project, locale reltime.Locale, now time.Time) error {
+	return output.WriteReport(w, format, projects, output.ReportWriters[[]Project]{
+		output.ReportText: func(w io.Writer, projects []Project) error {
+			return WriteText(w, projects, locale, now)
+		},
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders projects as a table, largest first, with a total.
+func WriteText(w io.Writer, projects []Project, locale reltime.Locale, now time.Time) error {
+	if len(projects) == 0 {
+		_, err := fmt.Fprintln(w, "No projects with build or dependency directories found.")
+		return err
+	}
+
+	var total int64
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SIZE\tLAST USED\tKIND\tARTIFACTS\tPROJECT")
+	for _, p := range projects {
+		names := make([]string, len(p.Artifacts))
+		for i, a := range p.Artifacts {
+			names[i] = filepath.Base(a.Path)
+		}
+		lastUsed := "-"
+		if !p.LastUsed.IsZero() {
+			lastUsed = locale.FormatAgo(p.LastUsed, now)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", types.FormatSize(p.Size), lastUsed,
+			strings.Join(p.Kinds, ","), strings.Join(names, " "), p.Path)
+		total += p.Size
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d project(s), %s reclaimable\n", len(projects), types.FormatSize(total))
+	return err
+}
+
+// WriteJSON renders projects as a JSON array.
+func WriteJSON(w io.Writer, projects []Project) error {
+	if projects == nil {
+		projects = []Project{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(projects)
+}
@@ -0,0 +1,492 @@
+// Package query parses sweep's query language, a small SQL-like language
+// for finding files:
+//
+//	size > 1GB and ext in (.mp4, .mkv) and mtime < now-180d order by size desc limit 50
+//
+// A query is a list of conditions joined by "and", followed by optional
+// "order by" and "limit" clauses. It compiles to a filter.Filter, whose
+// minimum size and exclude patterns a daemon applies to its index itself.
+//
+// The fields and the operators they take are:
+//
+//	size   >, >=, <, <=, =   a size, such as 1GB or 500M
+//	mtime  >, >=, <, <=      now, now-180d, or a date, such as 2024-01-31
+//	ext    =, in (...)       an extension, such as .mp4 or mp4
+//	type   =, in (...)       a type group, such as video
+//	path   like, not like    a glob matching the whole path, such as '**/cache/**'
+//	depth  <, <=             directory levels below the root
+//
+// Keywords and field names are case-insensitive. Values holding spaces or
+// any of ( ) , < > = ! are quoted with ' or ".
+package query
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+)
+
+// ErrSyntax is returned for queries that cannot be parsed.
+var ErrSyntax = errors.New("invalid query")
+
+// dateLayout is the layout of dates in mtime conditions.
+const dateLayout = "2006-01-02"
+
+// Compile parses q and returns the filter it describes. The filter starts
+// from defaults, which the query's clauses override; without an order by
+// clause, files are sorted as defaults say, largest first if they don't.
+// Relative times such as now-180d are taken from now.
+func Compile(q string, now time.Time, defaults ...filter.Option) (*filter.Filter, error) {
+	toks, err := lex(q)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, now: now, f: filter.New(defaults...), seen: map[string]bool{}}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.f, nil
+}
+
+// tokenKind is the kind of a token.
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokWord             // A keyword, field, or unquoted value
+	tokString           // A quoted value
+	tokOp               // A comparison operator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is one token of a query, with its byte offset for errors.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// isValue reports whether t can be a value.
+func (t token) isValue() bool {
+	return t.kind == tokWord || t.kind == tokString
+}
+
+// describe names t for error messages.
+func (t token) describe() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+// lex splits q into tokens, ending with a tokEOF.
+func lex(q string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(q) {
+		c := q[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '<' || c == '>' || c == '=':
+			op := string(c)
+			if c != '=' && i+1 < len(q) && q[i+1] == '=' {
+				op += "="
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i += len(op)
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(q[i+1:], c)
+			if end < 0 {
+				return nil, syntaxError(i, "unterminated string")
+			}
+			toks = append(toks, token{tokString, q[i+1 : i+1+end], i})
+			i += end + 2
+		case c == '!':
+			return nil, syntaxError(i, `unexpected "!"; use "not like" to exclude paths`)
+		default:
+			start := i
+			for i < len(q) && !strings.ContainsRune(" \t\n\r(),<>=!'\"", rune(q[i])) {
+				i++
+			}
+			toks = append(toks, token{tokWord, q[start:i], start})
+		}
+	}
+	return append(toks, token{tokEOF, "", len(q)}), nil
+}
+
+// syntaxError returns an ErrSyntax for the given byte offset.
+func syntaxError(pos int, format string, args ...any) error {
+	return fmt.Errorf("%w at column %d: %s", ErrSyntax, pos+1, fmt.Sprintf(format, args...))
+}
+
+// parser compiles a query's tokens into a filter as it reads them.
+type parser struct {
+	toks []token
+	i    int
+	now  time.Time
+	f    *filter.Filter
+	seen map[string]bool // Fields that may appear only once
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+// next consumes and returns the next token.
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the keyword kw.
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && strings.EqualFold(t.text, kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// value consumes a value.
+func (p *parser) value() (token, error) {
+	t := p.next()
+	if !t.isValue() {
+		return t, syntaxError(t.pos, "expected a value, got %s", t.describe())
+	}
+	return t, nil
+}
+
+// list consumes a value, or a parenthesized, comma-separated list of them.
+func (p *parser) list() ([]string, error) {
+	if p.peek().kind != tokLParen {
+		t, err := p.value()
+		return []string{t.text}, err
+	}
+	p.next()
+	var values []string
+	for {
+		t, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, t.text)
+		switch t := p.next(); t.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, syntaxError(t.pos, `expected "," or ")", got %s`, t.describe())
+		}
+	}
+}
+
+// parse reads the whole query.
+func (p *parser) parse() error {
+	p.keyword("where")
+	if t := p.peek(); t.kind != tokEOF && !strings.EqualFold(t.text, "order") && !strings.EqualFold(t.text, "limit") {
+		for {
+			if err := p.condition(); err != nil {
+				return err
+			}
+			if !p.keyword("and") {
+				break
+			}
+		}
+	}
+	if p.keyword("order") {
+		if err := p.orderBy(); err != nil {
+			return err
+		}
+	}
+	if p.keyword("limit") {
+		t, err := p.value()
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(t.text)
+		if err != nil || n < 0 {
+			return syntaxError(t.pos, "limit must be a whole number, got %s", t.describe())
+		}
+		p.f.Limit = n
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if strings.EqualFold(t.text, "or") {
+			return syntaxError(t.pos, `only "and" is supported`)
+		}
+		return syntaxError(t.pos, "unexpected %s", t.describe())
+	}
+	return nil
+}
+
+// operator consumes a comparison operator, which must be one of ops.
+func (p *parser) operator(field string, ops ...string) (token, error) {
+	t := p.next()
+	if t.kind != tokOp || !slices.Contains(ops, t.text) {
+		return t, syntaxError(t.pos, "%s takes %s, got %s", field, strings.Join(ops, " "), t.describe())
+	}
+	return t, nil
+}
+
+// once reports an error if field has already been given.
+func (p *parser) once(t token, field string) error {
+	if p.seen[field] {
+		return syntaxError(t.pos, "%s may only be given once", field)
+	}
+	p.seen[field] = true
+	return nil
+}
+
+// condition reads one condition.
+func (p *parser) condition() error {
+	t := p.next()
+	if t.kind != tokWord {
+		return syntaxError(t.pos, "expected a field, got %s", t.describe())
+	}
+	switch field := strings.ToLower(t.text); field {
+	case "size":
+		return p.size()
+	case "mtime":
+		return p.mtime()
+	case "ext", "type":
+		return p.kinds(t, field)
+	case "path":
+		return p.path()
+	case "depth":
+		return p.depth()
+	default:
+		return syntaxError(t.pos, "unknown field %s; fields are size, mtime, ext, type, path, and depth", t.describe())
+	}
+}
+
+// size reads a size comparison. Bounds from several conditions combine.
+func (p *parser) size() error {
+	op, err := p.operator("size", ">", ">=", "<", "<=", "=")
+	if err != nil {
+		return err
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	n, err := filter.ParseSize(v.text)
+	if err != nil {
+		return syntaxError(v.pos, "%v", err)
+	}
+
+	lower, upper := int64(-1), int64(-1)
+	switch op.text {
+	case ">":
+		lower = n + 1
+	case ">=":
+		lower = n
+	case "<":
+		upper = n - 1
+	case "<=":
+		upper = n
+	case "=":
+		lower, upper = n, n
+	}
+	if upper == 0 || upper < -1 {
+		return syntaxError(v.pos, "size %s %s matches only empty files", op.text, v.text)
+	}
+	if lower > p.f.MinSize {
+		p.f.MinSize = lower
+	}
+	if upper > 0 && (p.f.MaxSize == 0 || upper < p.f.MaxSize) {
+		p.f.MaxSize = upper
+	}
+	return nil
+}
+
+// mtime reads a modification time comparison. Bounds from several
+// conditions combine.
+func (p *parser) mtime() error {
+	op, err := p.operator("mtime", ">", ">=", "<", "<=")
+	if err != nil {
+		return err
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	t, err := parseTime(v.text, p.now)
+	if err != nil {
+		return syntaxError(v.pos, "%v", err)
+	}
+	age := p.now.Sub(t)
+	if age < 0 {
+		return syntaxError(v.pos, "%s is in the future", v.text)
+	}
+
+	if op.text == "<" || op.text == "<=" {
+		p.f.OlderThan = max(p.f.OlderThan, age)
+		return nil
+	}
+	if age == 0 {
+		return syntaxError(v.pos, "mtime %s now matches no files", op.text)
+	}
+	if p.f.NewerThan == 0 || age < p.f.NewerThan {
+		p.f.NewerThan = age
+	}
+	return nil
+}
+
+// parseTime parses now, now minus a duration such as now-180d, or a date.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	lower := strings.ToLower(s)
+	if lower == "now" {
+		return now, nil
+	}
+	if rest, ok := strings.CutPrefix(lower, "now-"); ok {
+		d, err := filter.ParseDuration(rest)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.ParseInLocation(dateLayout, s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected now, now-<duration>, or a YYYY-MM-DD date, got %q", s)
+	}
+	return t, nil
+}
+
+// kinds reads an ext or type condition. Only one of the two may be given,
+// as a filter holds either extensions or type groups.
+func (p *parser) kinds(t token, field string) error {
+	if err := p.once(t, "ext or type"); err != nil {
+		return err
+	}
+	var values []string
+	if p.keyword("in") {
+		var err error
+		if values, err = p.list(); err != nil {
+			return err
+		}
+	} else {
+		if op := p.next(); op.kind != tokOp || op.text != "=" {
+			return syntaxError(op.pos, "%s takes = or in (...), got %s", field, op.describe())
+		}
+		v, err := p.value()
+		if err != nil {
+			return err
+		}
+		values = []string{v.text}
+	}
+
+	if field == "ext" {
+		filter.WithExtensions(values...)(p.f)
+		return nil
+	}
+	for i, group := range values {
+		values[i] = strings.ToLower(group)
+		if _, ok := filter.TypeGroups[values[i]]; !ok {
+			names := slices.Sorted(maps.Keys(filter.TypeGroups))
+			return syntaxError(t.pos, "unknown type %q; types are %s", group, strings.Join(names, ", "))
+		}
+	}
+	filter.WithTypeGroups(values...)(p.f)
+	return nil
+}
+
+// path reads a like or not like condition. Only one like may be given, as
+// a file passes a filter's include patterns by matching any one of them.
+func (p *parser) path() error {
+	t := p.peek()
+	exclude := p.keyword("not")
+	if !p.keyword("like") {
+		t := p.peek()
+		return syntaxError(t.pos, `path takes like or not like, got %s`, t.describe())
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	if exclude {
+		p.f.Exclude = append(p.f.Exclude, v.text)
+		return nil
+	}
+	if err := p.once(t, "path like"); err != nil {
+		return err
+	}
+	p.f.Include = []string{v.text}
+	return nil
+}
+
+// depth reads a depth comparison.
+func (p *parser) depth() error {
+	op, err := p.operator("depth", "<", "<=")
+	if err != nil {
+		return err
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(v.text)
+	if op.text == "<" {
+		n--
+	}
+	if err != nil || n < 1 {
+		return syntaxError(v.pos, "depth %s %s matches no files", op.text, v.text)
+	}
+	if p.f.MaxDepth == 0 || n < p.f.MaxDepth {
+		p.f.MaxDepth = n
+	}
+	return nil
+}
+
+// orderBy reads the rest of an order by clause. Like SQL, order is
+// ascending unless desc is given.
+func (p *parser) orderBy() error {
+	if !p.keyword("by") {
+		t := p.peek()
+		return syntaxError(t.pos, `expected "by" after "order", got %s`, t.describe())
+	}
+	t := p.next()
+	var field filter.SortField
+	switch strings.ToLower(t.text) {
+	case "size":
+		field = filter.SortSize
+	case "mtime":
+		field = filter.SortAge
+	case "path":
+		field = filter.SortPath
+	default:
+		return syntaxError(t.pos, "cannot order by %s; order by size, mtime, or path", t.describe())
+	}
+	desc := false
+	if p.keyword("desc") {
+		desc = true
+	} else {
+		p.keyword("asc")
+	}
+	// The filter sorts by age, the reverse of modification time
+	if field == filter.SortAge {
+		desc = !desc
+	}
+	p.f.SortBy, p.f.SortDescending = field, desc
+	return nil
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func compile(t *testing.T, q string) *filter.Filter {
+	t.Helper()
+	f, err := Compile(q, now)
+	require.NoError(t, err, q)
+	return f
+}
+
+func TestCompile(t *testing.T) {
+	f := compile(t, "size > 1GB and ext in (.mp4, MKV) and mtime < now-180d order by size desc limit 20")
+	assert.Equal(t, filter.GiB+1, f.MinSize)
+	assert.Equal(t, []string{".mp4", ".mkv"}, f.Extensions)
+	assert.Equal(t, 180*filter.Day, f.OlderThan)
+	assert.Equal(t, filter.SortSize, f.SortBy)
+	assert.True(t, f.SortDescending)
+	assert.Equal(t, 20, f.Limit)
+
+	f = compile(t, `SIZE >= 10M AND size < 1G and mtime > 2026-05-01 and type = video and path not like '**/cache/**' and path not like "*.tmp" and depth <= 3`)
+	assert.Equal(t, 10*filter.MiB, f.MinSize)
+	assert.Equal(t, filter.GiB-1, f.MaxSize)
+	assert.Equal(t, 31*filter.Day+12*time.Hour, f.NewerThan)
+	assert.Equal(t, []string{"video"}, f.Types)
+	assert.Equal(t, []string{"**/cache/**", "*.tmp"}, f.Exclude)
+	assert.Equal(t, 3, f.MaxDepth)
+
+	f = compile(t, "size > 1G and size > 2G and size <= 8G and size < 4G")
+	assert.Equal(t, 2*filter.GiB+1, f.MinSize, "the tighter bounds win")
+	assert.Equal(t, 4*filter.GiB-1, f.MaxSize)
+
+	f = compile(t, "path like '/home/*/Downloads/**' order by mtime")
+	assert.Equal(t, []string{"/home/*/Downloads/**"}, f.Include)
+	assert.Equal(t, filter.SortAge, f.SortBy)
+	assert.True(t, f.SortDescending, "oldest first is the greatest age first")
+
+	f, err := Compile("limit 0", now, filter.WithLimit(5), filter.WithSortBy(filter.SortPath))
+	require.NoError(t, err)
+	assert.Zero(t, f.Limit, "the query overrides the defaults")
+	assert.Equal(t, filter.SortPath, f.SortBy, "defaults apply without an order by")
+}
+
+func TestCompileMatches(t *testing.T) {
+	f := compile(t, "size > 1K and ext = mp4 and mtime < now-1y and path not like '**/tmp/**'")
+	old := now.Add(-2 * filter.Year)
+	assert.True(t, f.Match(filter.FileInfo{Path: "/v/a.mp4", Ext: ".mp4", Size: 2048, ModTime: old}))
+	assert.False(t, f.Match(filter.FileInfo{Path: "/v/a.mp4", Ext: ".mp4", Size: 1024, ModTime: old}), "size > 1K excludes 1K")
+	assert.False(t, f.Match(filter.FileInfo{Path: "/v/tmp/a.mp4", Ext: ".mp4", Size: 2048, ModTime: old}))
+	assert.False(t, f.Match(filter.FileInfo{Path: "/v/a.mp4", Ext: ".mp4", Size: 2048, ModTime: time.Now()}))
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, q := range []string{
+		"size > ",
+		"size ~ 1G",
+		"size > lots",
+		"size < 1",
+		"mtime = now",
+		"mtime > now",
+		"mtime < yesterday",
+		"mtime < now+1d",
+		"ext = .mp4 and type = video",
+		"ext in (.mp4 .mkv)",
+		"type = holiday-snaps",
+		"path like 'a' and path like 'b'",
+		"path = /tmp",
+		"depth < 1",
+		"owner = me",
+		"size > 1G or ext = .iso",
+		"size > 1G order size",
+		"order by name",
+		"limit -1",
+		"limit 5 size > 1G",
+		"path like 'unterminated",
+		"size != 1G",
+	} {
+		_, err := Compile(q, now)
+		assert.ErrorIs(t, err, ErrSyntax, q)
+	}
+
+	_, err := Compile("size > 1G and colour = red", now)
+	assert.ErrorContains(t, err, "at column 15: unknown field")
+}
@@ -0,0 +1,356 @@
+// Package relocate moves files into a directory on another volume, such
+// as an archive disk, as an alternative to deleting them. Files on the
+// same volume are renamed; others are copied, verified, and only then
+// removed, optionally leaving a symlink to where they went.
+package relocate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// How copies are verified before the original is removed.
+const (
+	VerifySize = "size" // The copy has the original's size (default)
+	VerifyHash = "hash" // The copy also has the original's SHA-256
+)
+
+// partialSuffix marks a copy in progress, which is renamed into place once
+// verified.
+const partialSuffix = ".sweep-partial"
+
+// copyBuffer is how much is copied between progress reports.
+const copyBuffer = 1 << 20
+
+var (
+	// ErrUnknownVerify is returned by ParseVerify for unsupported modes.
+	ErrUnknownVerify = errors.New("unknown verify mode")
+
+	// ErrMismatch is returned when a copy differs from its original; the
+	// original is kept and the copy removed.
+	ErrMismatch = errors.New("copy does not match the original")
+
+	// ErrChanged is returned when a file changes while it is copied.
+	ErrChanged = errors.New("file changed while being copied")
+)
+
+// link hard-links files within a volume; tests replace it to force copies.
+var link = os.Link
+
+// Options configures Move.
+type Options struct {
+	Dest    string // Directory files are moved into; must exist
+	Verify  string // VerifySize (default) or VerifyHash
+	Symlink bool   // Leave a symlink at each old path to its new one
+	DryRun  bool   // Only report what would be moved
+
+	// OnProgress, if set, is called as each file is copied. OnDone, if
+	// set, is called once per path as it finishes. Calls are serialized.
+	OnProgress func(Progress)
+	OnDone     func(Result)
+}
+
+// Progress is how far the copy of one file has got.
+type Progress struct {
+	Path   string
+	Copied int64
+	Size   int64
+}
+
+// Result is the outcome of moving one path.
+type Result struct {
+	Path   string `json:"path"`
+	Dest   string `json:"dest,omitempty"` // Set once the file has moved, even if linking back failed
+	Size   int64  `json:"size"`
+	Copied bool   `json:"copied,omitempty"` // Copied to another volume rather than renamed
+	Linked bool   `json:"linked,omitempty"` // A symlink at Path points to Dest
+	Error  string `json:"error,omitempty"`
+}
+
+// Moved reports whether the file is now at Dest.
+func (r Result) Moved() bool {
+	return r.Dest != ""
+}
+
+// Report is the outcome of a Move.
+type Report struct {
+	Dest    string   `json:"dest"`
+	Verify  string   `json:"verify"`
+	DryRun  bool     `json:"dry_run,omitempty"`
+	Results []Result `json:"results"`
+}
+
+// Moved returns the number of files moved and their total size.
+func (r Report) Moved() (int, int64) {
+	var n int
+	var size int64
+	for _, res := range r.Results {
+		if res.Moved() {
+			n++
+			size += res.Size
+		}
+	}
+	return n, size
+}
+
+// Failed returns the number of results with an error.
+func (r Report) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseVerify checks a verify mode, returning it in lower case; "" is
+// VerifySize.
+func ParseVerify(mode string) (string, error) {
+	switch mode := strings.ToLower(mode); mode {
+	case "":
+		return VerifySize, nil
+	case VerifySize, VerifyHash:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: %s, %s)", ErrUnknownVerify, mode, VerifySize, VerifyHash)
+	}
+}
+
+// Move moves each of paths into opts.Dest under its own name, one at a
+// time. Only regular files are moved, and an existing file at the
+// destination is never replaced. Paths not yet moved when ctx is cancelled
+// fail with the context's error; a copy in progress is removed.
+func Move(ctx context.Context, paths []string, opts Options) (Report, error) {
+	verify, err := ParseVerify(opts.Verify)
+	if err != nil {
+		return Report{}, err
+	}
+	dest, err := filepath.Abs(opts.Dest)
+	if err != nil {
+		return Report{}, fmt.Errorf("invalid destination %q: %w", opts.Dest, err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return Report{}, fmt.Errorf("invalid destination: %w", err)
+	}
+	if !info.IsDir() {
+		return Report{}, fmt.Errorf("invalid destination %s: not a directory", dest)
+	}
+	opts.Dest, opts.Verify = dest, verify
+
+	report := Report{Dest: dest, Verify: verify, DryRun: opts.DryRun, Results: make([]Result, len(paths))}
+	for i, path := range paths {
+		res := Result{Path: path}
+		if err := ctx.Err(); err != nil {
+			res.Error = err.Error()
+		} else {
+			res = move(ctx, path, opts)
+		}
+		report.Results[i] = res
+		if opts.OnDone != nil {
+			opts.OnDone(res)
+		}
+	}
+	return report, nil
+}
+
+// move moves one path as Move describes.
+func move(ctx context.Context, path string, opts Options) Result {
+	res := Result{Path: path}
+	fail := func(err error) Result {
+		res.Error = err.Error()
+		return res
+	}
+
+	src, err := filepath.Abs(path)
+	if err != nil {
+		return fail(fmt.Errorf("cannot resolve absolute path: %w", err))
+	}
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fail(err)
+	}
+	if !info.Mode().IsRegular() {
+		return fail(fmt.Errorf("%s is not a regular file", path))
+	}
+	res.Size = info.Size()
+
+	dest := filepath.Join(opts.Dest, filepath.Base(src))
+	if dest == src {
+		return fail(fmt.Errorf("%s is already in %s", path, opts.Dest))
+	}
+	if _, err := os.Lstat(dest); err == nil {
+		return fail(fmt.Errorf("%s already exists", dest))
+	}
+	if opts.DryRun {
+		return res
+	}
+
+	err = renameNoReplace(src, dest)
+	if errors.Is(err, syscall.EXDEV) {
+		res.Copied = true
+		err = copyVerified(ctx, src, dest, info, opts)
+		if err == nil {
+			if err = os.Remove(src); err != nil {
+				// Both copies exist; keeping the original loses nothing
+				_ = os.Remove(dest)
+			}
+		}
+	}
+	if err != nil {
+		return fail(err)
+	}
+	res.Dest = dest
+
+	if opts.Symlink {
+		if err := os.Symlink(dest, src); err != nil {
+			return fail(fmt.Errorf("moved to %s, but not linked back: %w", dest, err))
+		}
+		res.Linked = true
+	}
+	return res
+}
+
+// copyVerified copies src to dest through a partial file, which becomes
+// dest once it matches src as opts.Verify asks.
+func copyVerified(ctx context.Context, src, dest string, info os.FileInfo, opts Options) (err error) {
+	partial := dest + partialSuffix
+	defer func() {
+		if err != nil {
+			_ = os.Remove(partial)
+		}
+	}()
+
+	var sum []byte
+	if sum, err = copyFile(ctx, src, partial, info, opts); err != nil {
+		return err
+	}
+
+	after, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if after.Size() != info.Size() || !after.ModTime().Equal(info.ModTime()) {
+		return fmt.Errorf("%s: %w", src, ErrChanged)
+	}
+	copied, err := os.Stat(partial)
+	if err != nil {
+		return err
+	}
+	if copied.Size() != info.Size() {
+		return fmt.Errorf("%s: %w: %d bytes, not %d", dest, ErrMismatch, copied.Size(), info.Size())
+	}
+	if sum != nil {
+		got, err := hashFile(partial)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, sum) {
+			return fmt.Errorf("%s: %w: SHA-256 differs", dest, ErrMismatch)
+		}
+	}
+
+	_ = os.Chtimes(partial, info.ModTime(), info.ModTime())
+	return renameNoReplace(partial, dest)
+}
+
+// renameNoReplace renames oldpath to newpath on the same volume without
+// replacing a file already at newpath, even one created a moment before:
+// newpath is linked to oldpath, which fails if it exists, and oldpath then
+// removed. Where hard links aren't supported, newpath is checked before an
+// ordinary rename instead.
+func renameNoReplace(oldpath, newpath string) error {
+	err := link(oldpath, newpath)
+	switch {
+	case err == nil:
+		if err := os.Remove(oldpath); err != nil {
+			_ = os.Remove(newpath)
+			return err
+		}
+		return nil
+	case errors.Is(err, fs.ErrExist):
+		return fmt.Errorf("%s already exists", newpath)
+	case errors.Is(err, syscall.EXDEV):
+		return err
+	}
+	if _, err := os.Lstat(newpath); err == nil {
+		return fmt.Errorf("%s already exists", newpath)
+	}
+	return os.Rename(oldpath, newpath)
+}
+
+// copyFile copies src to dest with src's permissions, reporting progress,
+// and returns src's SHA-256 when hashes are verified.
+func copyFile(ctx context.Context, src, dest string, info os.FileInfo, opts Options) ([]byte, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	var h hash.Hash
+	var r io.Reader = in
+	if opts.Verify == VerifyHash {
+		h = sha256.New()
+		r = io.TeeReader(in, h)
+	}
+
+	buf := make([]byte, copyBuffer)
+	var copied int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n, err := io.CopyBuffer(out, io.LimitReader(r, copyBuffer), buf)
+		if err != nil {
+			return nil, fmt.Errorf("cannot copy %s: %w", src, err)
+		}
+		copied += n
+		if opts.OnProgress != nil {
+			opts.OnProgress(Progress{Path: src, Copied: copied, Size: info.Size()})
+		}
+		if n < copyBuffer {
+			break
+		}
+	}
+	if err := out.Sync(); err != nil {
+		return nil, fmt.Errorf("cannot copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("cannot copy %s: %w", src, err)
+	}
+	if h == nil {
+		return nil, nil
+	}
+	return h.Sum(nil), nil
+}
+
+// hashFile returns the SHA-256 of the file at path, read back from disk.
+func hashFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,217 @@
+package relocate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crossVolume makes every link but the copy's fail as if dest were on
+// another volume, for the duration of the test.
+func crossVolume(t *testing.T) {
+	t.Helper()
+	link = func(oldpath, newpath string) error {
+		if strings.HasSuffix(oldpath, partialSuffix) {
+			return os.Link(oldpath, newpath)
+		}
+		return &os.LinkError{Op: "link", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { link = os.Link })
+}
+
+// setup returns a source directory holding the named files, each with its
+// name as content, and an empty destination directory.
+func setup(t *testing.T, names ...string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	src, dest := filepath.Join(dir, "src"), filepath.Join(dir, "archive")
+	require.NoError(t, os.Mkdir(src, 0o755))
+	require.NoError(t, os.Mkdir(dest, 0o755))
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(src, name), []byte(name), 0o640))
+	}
+	return src, dest
+}
+
+func TestParseVerify(t *testing.T) {
+	for in, want := range map[string]string{"": VerifySize, "size": VerifySize, "HASH": VerifyHash} {
+		got, err := ParseVerify(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseVerify("crc")
+	assert.ErrorIs(t, err, ErrUnknownVerify)
+}
+
+func TestMoveRename(t *testing.T) {
+	src, dest := setup(t, "a.iso")
+	path := filepath.Join(src, "a.iso")
+
+	var done []Result
+	report, err := Move(context.Background(), []string{path}, Options{Dest: dest, OnDone: func(r Result) { done = append(done, r) }})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, report.Results, done)
+
+	res := report.Results[0]
+	assert.Empty(t, res.Error)
+	assert.False(t, res.Copied, "same volume")
+	assert.Equal(t, filepath.Join(dest, "a.iso"), res.Dest)
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, res.Dest)
+	n, size := report.Moved()
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int64(5), size)
+}
+
+func TestMoveCopy(t *testing.T) {
+	for _, verify := range []string{VerifySize, VerifyHash} {
+		t.Run(verify, func(t *testing.T) {
+			crossVolume(t)
+			src, dest := setup(t, "a.iso", "b.mov")
+			mtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			require.NoError(t, os.Chtimes(filepath.Join(src, "a.iso"), mtime, mtime))
+
+			var progress []Progress
+			report, err := Move(context.Background(), []string{filepath.Join(src, "a.iso"), filepath.Join(src, "b.mov")}, Options{
+				Dest:       dest,
+				Verify:     verify,
+				Symlink:    true,
+				OnProgress: func(p Progress) { progress = append(progress, p) },
+			})
+			require.NoError(t, err)
+			assert.Zero(t, report.Failed())
+			assert.Equal(t, verify, report.Verify)
+
+			for _, res := range report.Results {
+				assert.True(t, res.Copied)
+				assert.True(t, res.Linked)
+				target, err := os.Readlink(res.Path)
+				require.NoError(t, err)
+				assert.Equal(t, res.Dest, target)
+
+				got, err := os.ReadFile(res.Dest)
+				require.NoError(t, err)
+				assert.Equal(t, filepath.Base(res.Path), string(got))
+				info, err := os.Stat(res.Dest)
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+			}
+			info, err := os.Stat(filepath.Join(dest, "a.iso"))
+			require.NoError(t, err)
+			assert.True(t, info.ModTime().Equal(mtime), "the modification time is kept")
+			assert.NoFileExists(t, filepath.Join(dest, "a.iso"+partialSuffix))
+
+			require.NotEmpty(t, progress)
+			last := progress[len(progress)-1]
+			assert.Equal(t, last.Size, last.Copied)
+		})
+	}
+}
+
+func TestMoveRefuses(t *testing.T) {
+	src, dest := setup(t, "a.iso", "taken.iso")
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "taken.iso"), []byte("other"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(src, "dir"), 0o755))
+
+	report, err := Move(context.Background(), []string{
+		filepath.Join(src, "taken.iso"),
+		filepath.Join(src, "dir"),
+		filepath.Join(src, "missing"),
+		filepath.Join(dest, "taken.iso"),
+	}, Options{Dest: dest})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Failed())
+	assert.Contains(t, report.Results[0].Error, "already exists")
+	assert.Contains(t, report.Results[1].Error, "not a regular file")
+	assert.Contains(t, report.Results[3].Error, "already in")
+	for _, res := range report.Results {
+		assert.False(t, res.Moved(), res.Path)
+	}
+	assert.FileExists(t, filepath.Join(src, "taken.iso"))
+
+	_, err = Move(context.Background(), nil, Options{Dest: filepath.Join(src, "a.iso")})
+	assert.ErrorContains(t, err, "not a directory")
+	_, err = Move(context.Background(), nil, Options{Dest: dest, Verify: "crc"})
+	assert.ErrorIs(t, err, ErrUnknownVerify)
+}
+
+func TestMoveRace(t *testing.T) {
+	for _, mode := range []string{"rename", "copy"} {
+		t.Run(mode, func(t *testing.T) {
+			if mode == "copy" {
+				crossVolume(t)
+			}
+			src, dest := setup(t, "a.iso")
+			// Another process creates the destination once it has been checked
+			next := link
+			link = func(oldpath, newpath string) error {
+				require.NoError(t, os.WriteFile(newpath, []byte("other"), 0o644))
+				return next(oldpath, newpath)
+			}
+			t.Cleanup(func() { link = os.Link })
+
+			report, err := Move(context.Background(), []string{filepath.Join(src, "a.iso")}, Options{Dest: dest})
+			require.NoError(t, err)
+			assert.Contains(t, report.Results[0].Error, "already exists")
+			assert.False(t, report.Results[0].Moved())
+			assert.FileExists(t, filepath.Join(src, "a.iso"))
+			got, err := os.ReadFile(filepath.Join(dest, "a.iso"))
+			require.NoError(t, err)
+			assert.Equal(t, "other", string(got), "the new file is not replaced")
+		})
+	}
+}
+
+func TestMoveDryRunAndCancel(t *testing.T) {
+	src, dest := setup(t, "a.iso")
+	path := filepath.Join(src, "a.iso")
+
+	report, err := Move(context.Background(), []string{path}, Options{Dest: dest, DryRun: true})
+	require.NoError(t, err)
+	assert.Zero(t, report.Failed())
+	assert.False(t, report.Results[0].Moved())
+	assert.FileExists(t, path)
+
+	crossVolume(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err = Move(ctx, []string{path}, Options{Dest: dest})
+	require.NoError(t, err)
+	assert.Equal(t, context.Canceled.Error(), report.Results[0].Error)
+	assert.FileExists(t, path)
+	entries, err := os.ReadDir(dest)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no partial copy is left")
+}
+
+func TestWrite(t *testing.T) {
+	report := Report{Dest: "/archive", Verify: VerifySize, Results: []Result{
+		{Path: "/src/a.iso", Dest: "/archive/a.iso", Size: 2048, Copied: true, Linked: true},
+		{Path: "/src/b.iso", Size: 10, Error: "/archive/b.iso already exists"},
+	}}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, output.ReportText, report))
+	assert.Contains(t, text.String(), "/src/a.iso -> /archive/a.iso (linked)")
+	assert.Contains(t, text.String(), "failed")
+	assert.Contains(t, text.String(), "Moved 1 of 2 file(s) to /archive, 2.0 KiB; 1 failed")
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, output.ReportJSON, report))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+
+	assert.ErrorIs(t, Write(&out, "xml", report), output.ErrUnknownReportFormat)
+}
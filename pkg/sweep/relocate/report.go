@@ -0,0 +1,68 @@
+package relocate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Write renders a report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders a report as one line per file and a summary.
+func WriteText(w io.Writer, r Report) error {
+	var b strings.Builder
+	for _, res := range r.Results {
+		switch {
+		case res.Error != "" && res.Moved():
+			fmt.Fprintf(&b, "  moved     %10s  %s -> %s: %s\n", types.FormatSize(res.Size), res.Path, res.Dest, res.Error)
+		case res.Error != "":
+			fmt.Fprintf(&b, "  failed    %10s  %s: %s\n", types.FormatSize(res.Size), res.Path, res.Error)
+		case r.DryRun:
+			fmt.Fprintf(&b, "  move      %10s  %s\n", types.FormatSize(res.Size), res.Path)
+		case res.Linked:
+			fmt.Fprintf(&b, "  moved     %10s  %s -> %s (linked)\n", types.FormatSize(res.Size), res.Path, res.Dest)
+		default:
+			fmt.Fprintf(&b, "  moved     %10s  %s -> %s\n", types.FormatSize(res.Size), res.Path, res.Dest)
+		}
+	}
+
+	n, size := r.Moved()
+	verb := "Moved"
+	if r.DryRun {
+		verb = "Would move"
+		n, size = 0, 0
+		for _, res := range r.Results {
+			if res.Error == "" {
+				n++
+				size += res.Size
+			}
+		}
+	}
+	fmt.Fprintf(&b, "%s %d of %d file(s) to %s, %s", verb, n, len(r.Results), r.Dest, types.FormatSize(size))
+	if failed := r.Failed(); failed > 0 {
+		fmt.Fprintf(&b, "; %d failed", failed)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON renders a report as a JSON object.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Results == nil {
+		r.Results = []Result{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
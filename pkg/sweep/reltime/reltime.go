@@ -0,0 +1,98 @@
+// Package reltime formats how long ago something happened, such as "3 mo"
+// or "2 yr", in the user's language.
+package reltime
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Locale holds the abbreviated unit names of one language.
+type Locale struct {
+	Tag    string // Language code, e.g. "en"
+	Now    string // Less than a minute ago
+	Minute string
+	Hour   string
+	Day    string
+	Week   string
+	Month  string
+	Year   string
+	Ago    string // Format for a past age, e.g. "%s ago"
+}
+
+// English is the fallback locale.
+var English = Locale{
+	Tag: "en", Now: "now",
+	Minute: "min", Hour: "h", Day: "d", Week: "wk", Month: "mo", Year: "yr",
+	Ago: "%s ago",
+}
+
+// locales are the supported languages by code. Unit names stay short so an
+// age fits a narrow column.
+var locales = map[string]Locale{
+	"en": English,
+	"de": {Tag: "de", Now: "jetzt", Minute: "Min.", Hour: "Std.", Day: "T.", Week: "Wo.", Month: "Mon.", Year: "J.", Ago: "vor %s"},
+	"es": {Tag: "es", Now: "ahora", Minute: "min", Hour: "h", Day: "d", Week: "sem.", Month: "m", Year: "a", Ago: "hace %s"},
+	"fr": {Tag: "fr", Now: "<1 min", Minute: "min", Hour: "h", Day: "j", Week: "sem.", Month: "mois", Year: "a", Ago: "il y a %s"},
+	"it": {Tag: "it", Now: "ora", Minute: "min", Hour: "h", Day: "g", Week: "sett.", Month: "mes.", Year: "a", Ago: "%s fa"},
+	"nl": {Tag: "nl", Now: "nu", Minute: "min", Hour: "u", Day: "d", Week: "wk", Month: "mnd", Year: "jr", Ago: "%s geleden"},
+	"pt": {Tag: "pt", Now: "agora", Minute: "min", Hour: "h", Day: "d", Week: "sem.", Month: "m", Year: "a", Ago: "há %s"},
+}
+
+// Lookup returns the locale for a language tag such as "de", "de-AT", or
+// "de_DE.UTF-8". Unsupported languages, "C", and "POSIX" get English.
+func Lookup(tag string) Locale {
+	lang := strings.ToLower(tag)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if l, ok := locales[lang]; ok {
+		return l
+	}
+	return English
+}
+
+// Detect returns the locale from the environment, checking LC_ALL,
+// LC_TIME, and LANG in the order the C library does.
+func Detect() Locale {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return Lookup(v)
+		}
+	}
+	return English
+}
+
+// Format returns how long before now t was, in the largest whole unit:
+// "12 min", "5 d", "3 mo". Times in the future count as now.
+func (l Locale) Format(t, now time.Time) string {
+	d := now.Sub(t)
+	days := int(d / (24 * time.Hour))
+	switch {
+	case d < time.Minute:
+		return l.Now
+	case d < time.Hour:
+		return fmt.Sprintf("%d %s", int(d/time.Minute), l.Minute)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d %s", int(d/time.Hour), l.Hour)
+	case days < 14:
+		return fmt.Sprintf("%d %s", days, l.Day)
+	case days < 60:
+		return fmt.Sprintf("%d %s", days/7, l.Week)
+	case days < 365:
+		return fmt.Sprintf("%d %s", days/30, l.Month)
+	default:
+		return fmt.Sprintf("%d %s", days/365, l.Year)
+	}
+}
+
+// FormatAgo is Format with the locale's wording for the past, such as
+// "3 mo ago" or "vor 3 Mon.".
+func (l Locale) FormatAgo(t, now time.Time) string {
+	if now.Sub(t) < time.Minute {
+		return l.Now
+	}
+	return fmt.Sprintf(l.Ago, l.Format(t, now))
+}
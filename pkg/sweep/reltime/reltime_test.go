@@ -0,0 +1,72 @@
+package reltime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{-time.Hour, "now"},
+		{30 * time.Second, "now"},
+		{12 * time.Minute, "12 min"},
+		{5 * time.Hour, "5 h"},
+		{5 * 24 * time.Hour, "5 d"},
+		{20 * 24 * time.Hour, "2 wk"},
+		{95 * 24 * time.Hour, "3 mo"},
+		{800 * 24 * time.Hour, "2 yr"},
+	}
+	for _, tt := range tests {
+		if got := English.Format(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("Format(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAgo(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	then := now.Add(-95 * 24 * time.Hour)
+
+	tests := map[string]string{
+		"en_US.UTF-8": "3 mo ago",
+		"de_DE.UTF-8": "vor 3 Mon.",
+		"fr-CA":       "il y a 3 mois",
+		"es":          "hace 3 m",
+		"C":           "3 mo ago",
+		"xx_YY":       "3 mo ago",
+	}
+	for tag, want := range tests {
+		if got := Lookup(tag).FormatAgo(then, now); got != want {
+			t.Errorf("Lookup(%q).FormatAgo = %q, want %q", tag, got, want)
+		}
+	}
+
+	if got := English.FormatAgo(now, now); got != "now" {
+		t.Errorf("FormatAgo(now) = %q, want %q", got, "now")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "nl_NL.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := Detect().Tag; got != "nl" {
+		t.Errorf("Detect() = %q, want nl from LC_TIME", got)
+	}
+
+	t.Setenv("LC_ALL", "pt_BR.UTF-8")
+	if got := Detect().Tag; got != "pt" {
+		t.Errorf("Detect() = %q, want pt from LC_ALL", got)
+	}
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "")
+	t.Setenv("LANG", "")
+	if got := Detect().Tag; got != "en" {
+		t.Errorf("Detect() = %q, want en", got)
+	}
+}
@@ -0,0 +1,66 @@
+package restore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Write renders a report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders a report as one line per file and a summary.
+func WriteText(w io.Writer, r Report) error {
+	var b strings.Builder
+	var bytes int64
+	for _, res := range r.Results {
+		switch res.Status {
+		case StatusRestored:
+			bytes += res.Size
+			if res.RestoredTo != res.Path {
+				fmt.Fprintf(&b, "  restored  %10s  %s -> %s\n", types.FormatSize(res.Size), res.Path, res.RestoredTo)
+			} else {
+				fmt.Fprintf(&b, "  restored  %10s  %s\n", types.FormatSize(res.Size), res.Path)
+			}
+		default:
+			fmt.Fprintf(&b, "  %-8s  %10s  %s: %s\n", res.Status, types.FormatSize(res.Size), res.Path, res.Error)
+		}
+	}
+
+	verb := "Restored"
+	if r.DryRun {
+		verb = "Would restore"
+	}
+	fmt.Fprintf(&b, "%s %d of %d file(s) from %s, %s", verb, r.Count(StatusRestored), len(r.Results), r.Entry, types.FormatSize(bytes))
+	if n := r.Count(StatusSkipped); n > 0 {
+		fmt.Fprintf(&b, "; %d skipped", n)
+	}
+	if n := r.Count(StatusFailed); n > 0 {
+		fmt.Fprintf(&b, "; %d failed", n)
+	}
+	b.WriteString("\n")
+	if r.Manifest != "" {
+		fmt.Fprintf(&b, "Recorded in history as %s\n", r.Manifest)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON renders a report as a JSON object.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Results == nil {
+		r.Results = []Result{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,278 @@
+// Package restore moves deleted files back from the trash, or the staging
+// directory, to where they were, using the deletions recorded in the
+// manifest.
+package restore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// Conflict says what to do when a file's original path is occupied.
+type Conflict string
+
+// Conflict policies.
+const (
+	ConflictSkip      Conflict = "skip"      // Leave the file in the trash
+	ConflictRename    Conflict = "rename"    // Restore next to it as "name (restored).ext"
+	ConflictOverwrite Conflict = "overwrite" // Move the occupying file to the trash first
+)
+
+// ErrUnknownConflict is returned by ParseConflict for unsupported policies.
+var ErrUnknownConflict = errors.New("unknown conflict policy")
+
+// ErrNotRestorable is returned for manifest entries that deleted nothing,
+// such as scans.
+var ErrNotRestorable = errors.New("operation did not delete files")
+
+// maxRenames bounds the search for a free "(restored N)" name.
+const maxRenames = 1000
+
+// ParseConflict parses a conflict policy; "" means rename.
+func ParseConflict(s string) (Conflict, error) {
+	switch c := Conflict(strings.ToLower(s)); c {
+	case "":
+		return ConflictRename, nil
+	case ConflictSkip, ConflictRename, ConflictOverwrite:
+		return c, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: skip, rename, overwrite)", ErrUnknownConflict, s)
+	}
+}
+
+// Restorable reports whether a manifest entry records deleted files.
+func Restorable(e *manifest.Entry) bool {
+	return e.Operation == manifest.OpDelete || e.Operation == manifest.OpRule
+}
+
+// TrashRecords returns the files that entries, as from manifest.List,
+// moved to the trash, for trash.FindTrashed to find the ones still there.
+func TrashRecords(entries []manifest.Entry) []trash.Record {
+	var records []trash.Record
+	for i := range entries {
+		e := &entries[i]
+		if !Restorable(e) {
+			continue
+		}
+		for _, f := range e.Files {
+			if f.Location != "" {
+				continue // Staged, not trashed
+			}
+			deleted := f.DeletedAt
+			if deleted.IsZero() {
+				deleted = e.Timestamp
+			}
+			records = append(records, trash.Record{Path: f.Path, Size: f.Size, Deleted: deleted, Entry: e.ID})
+		}
+	}
+	return records
+}
+
+// Status is the outcome of restoring one file.
+type Status string
+
+// Restore outcomes.
+const (
+	StatusRestored Status = "restored"
+	StatusSkipped  Status = "skipped"
+	StatusFailed   Status = "failed"
+)
+
+// Result is the outcome for one deleted file.
+type Result struct {
+	Path       string `json:"path"`                  // Original path
+	RestoredTo string `json:"restored_to,omitempty"` // Differs from Path after a rename
+	Size       int64  `json:"size"`
+	Status     Status `json:"status"`
+	Error      string `json:"error,omitempty"` // Why the file was skipped or failed
+}
+
+// Options configures Entry.
+type Options struct {
+	Conflict Conflict
+	DryRun   bool               // Only report what would be restored
+	Paths    []string           // Restore only these original paths; empty restores all
+	Manifest *manifest.Manifest // Records the restored files; nil to skip
+
+	// Find locates a file in the trash; nil uses trash.Find, or
+	// trash.StagedItem for staged files.
+	Find func(rec manifest.FileRecord) (trash.Item, error)
+	// Restore moves an item out of the trash; nil uses trash.Restore.
+	Restore func(it trash.Item, dest string) error
+	// Displace moves a file occupying an original path out of the way for
+	// ConflictOverwrite; nil uses trash.MoveToTrash.
+	Displace func(path string) error
+}
+
+// Report is the outcome of restoring a manifest entry.
+type Report struct {
+	Entry    string   `json:"entry"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+	Results  []Result `json:"results"`
+	Manifest string   `json:"manifest,omitempty"` // ID of the entry recording the restore
+}
+
+// Count returns the number of results with the given status.
+func (r Report) Count(s Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Restored returns the files that were restored.
+func (r Report) Restored() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Status == StatusRestored {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Entry restores the files deleted by a manifest entry. Files that can't be
+// restored are reported rather than stopping the rest.
+func Entry(e *manifest.Entry, opts Options) (Report, error) {
+	report := Report{Entry: e.ID, DryRun: opts.DryRun}
+	if !Restorable(e) {
+		return report, fmt.Errorf("%s: %w", e.ID, ErrNotRestorable)
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictRename
+	}
+	if opts.Find == nil {
+		opts.Find = func(rec manifest.FileRecord) (trash.Item, error) {
+			if rec.Method == manifest.MethodStaging && rec.Location != "" {
+				return trash.StagedItem(rec.Path, rec.Location, rec.DeletedAt)
+			}
+			return trash.Find(rec.Path, rec.Size, rec.DeletedAt)
+		}
+	}
+	if opts.Restore == nil {
+		opts.Restore = trash.Restore
+	}
+	if opts.Displace == nil {
+		opts.Displace = trash.MoveToTrash
+	}
+
+	want := make(map[string]bool, len(opts.Paths))
+	for _, p := range opts.Paths {
+		want[filepath.Clean(p)] = true
+	}
+
+	var records []manifest.FileRecord
+	for _, rec := range e.Files {
+		if len(want) > 0 && !want[rec.Path] {
+			continue
+		}
+		res := restoreFile(rec, opts)
+		report.Results = append(report.Results, res)
+		if res.Status == StatusRestored {
+			records = append(records, manifest.FileRecord{Path: res.RestoredTo, Size: rec.Size, ModTime: rec.ModTime})
+		}
+	}
+	if len(want) > 0 && len(report.Results) == 0 {
+		return report, fmt.Errorf("none of the given paths were deleted by %s", e.ID)
+	}
+
+	if opts.Manifest != nil && !opts.DryRun && len(records) > 0 {
+		if err := opts.Manifest.EnsureDir(); err != nil {
+			return report, fmt.Errorf("failed to record restore: %w", err)
+		}
+		entry, err := opts.Manifest.LogRestore(e.ID, records)
+		if err != nil {
+			return report, fmt.Errorf("failed to record restore: %w", err)
+		}
+		report.Manifest = entry.ID
+	}
+	return report, nil
+}
+
+// Latest returns the most recent entry in entries (newest first, as from
+// manifest.List) that deleted files and has not been restored since.
+func Latest(entries []manifest.Entry) (*manifest.Entry, error) {
+	restored := make(map[string]bool)
+	for i := range entries {
+		e := &entries[i]
+		if e.Operation == manifest.OpRestore {
+			restored[e.Restores] = true
+			continue
+		}
+		if Restorable(e) && !restored[e.ID] {
+			return e, nil
+		}
+	}
+	return nil, errors.New("no deletions to restore in history")
+}
+
+// restoreFile restores one deleted file according to opts.
+func restoreFile(rec manifest.FileRecord, opts Options) Result {
+	res := Result{Path: rec.Path, RestoredTo: rec.Path, Size: rec.Size}
+	fail := func(status Status, err error) Result {
+		res.Status = status
+		res.RestoredTo = ""
+		res.Error = err.Error()
+		return res
+	}
+
+	item, err := opts.Find(rec)
+	if err != nil {
+		return fail(StatusFailed, err)
+	}
+
+	if _, err := os.Lstat(rec.Path); err == nil {
+		switch opts.Conflict {
+		case ConflictSkip:
+			return fail(StatusSkipped, fmt.Errorf("%s already exists", rec.Path))
+		case ConflictRename:
+			dest, err := freeName(rec.Path)
+			if err != nil {
+				return fail(StatusFailed, err)
+			}
+			res.RestoredTo = dest
+		case ConflictOverwrite:
+			if !opts.DryRun {
+				if err := opts.Displace(rec.Path); err != nil {
+					return fail(StatusFailed, fmt.Errorf("cannot move existing file aside: %w", err))
+				}
+			}
+		}
+	}
+
+	if !opts.DryRun {
+		if err := opts.Restore(item, res.RestoredTo); err != nil {
+			return fail(StatusFailed, err)
+		}
+	}
+	res.Status = StatusRestored
+	return res
+}
+
+// freeName returns the first unused "name (restored).ext" or
+// "name (restored N).ext" next to path.
+func freeName(path string) (string, error) {
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for n := 1; n <= maxRenames; n++ {
+		suffix := " (restored)"
+		if n > 1 {
+			suffix = fmt.Sprintf(" (restored %d)", n)
+		}
+		candidate := filepath.Join(dir, stem+suffix+ext)
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free name to restore %s", path)
+}
@@ -0,0 +1,263 @@
+package restore
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+)
+
+// fakeTrash stands in for the trash: items hold each deleted file's content.
+type fakeTrash struct {
+	items     map[string]string
+	displaced []string
+}
+
+func (f *fakeTrash) options(conflict Conflict) Options {
+	return Options{
+		Conflict: conflict,
+		Find: func(rec manifest.FileRecord) (trash.Item, error) {
+			if _, ok := f.items[rec.Path]; !ok {
+				return trash.Item{}, trash.ErrNotInTrash
+			}
+			return trash.Item{Path: rec.Path, Original: rec.Path}, nil
+		},
+		Restore: func(it trash.Item, dest string) error {
+			if err := os.WriteFile(dest, []byte(f.items[it.Original]), 0o644); err != nil {
+				return err
+			}
+			delete(f.items, it.Original)
+			return nil
+		},
+		Displace: func(path string) error {
+			f.displaced = append(f.displaced, path)
+			return os.Remove(path)
+		},
+	}
+}
+
+func deleteEntry(paths ...string) *manifest.Entry {
+	e := &manifest.Entry{ID: "delete-1", Operation: manifest.OpDelete}
+	for _, p := range paths {
+		e.Files = append(e.Files, manifest.FileRecord{Path: p, Size: 3})
+	}
+	return e
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestEntryRestoresFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	ft := &fakeTrash{items: map[string]string{a: "aaa"}}
+
+	report, err := Entry(deleteEntry(a, b), ft.options(ConflictRename))
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+
+	assert.Equal(t, StatusRestored, report.Results[0].Status)
+	assert.Equal(t, a, report.Results[0].RestoredTo)
+	assert.Equal(t, "aaa", readFile(t, a))
+
+	assert.Equal(t, StatusFailed, report.Results[1].Status, "b was never in the trash")
+	assert.Contains(t, report.Results[1].Error, "not found in trash")
+	assert.Equal(t, 1, report.Count(StatusRestored))
+}
+
+func TestEntryConflicts(t *testing.T) {
+	tests := []struct {
+		conflict  Conflict
+		status    Status
+		restoreTo string
+		original  string // Content left at the original path
+	}{
+		{ConflictSkip, StatusSkipped, "", "new"},
+		{ConflictRename, StatusRestored, "a (restored).txt", "new"},
+		{ConflictOverwrite, StatusRestored, "a.txt", "old"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.conflict), func(t *testing.T) {
+			dir := t.TempDir()
+			a := filepath.Join(dir, "a.txt")
+			require.NoError(t, os.WriteFile(a, []byte("new"), 0o644))
+			ft := &fakeTrash{items: map[string]string{a: "old"}}
+
+			report, err := Entry(deleteEntry(a), ft.options(tt.conflict))
+			require.NoError(t, err)
+			res := report.Results[0]
+			assert.Equal(t, tt.status, res.Status)
+			if tt.restoreTo != "" {
+				assert.Equal(t, filepath.Join(dir, tt.restoreTo), res.RestoredTo)
+				assert.Equal(t, "old", readFile(t, res.RestoredTo))
+			}
+			assert.Equal(t, tt.original, readFile(t, a))
+			if tt.conflict == ConflictOverwrite {
+				assert.Equal(t, []string{a}, ft.displaced)
+			}
+		})
+	}
+}
+
+func TestEntryDryRun(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(a, []byte("new"), 0o644))
+	ft := &fakeTrash{items: map[string]string{a: "old"}}
+
+	opts := ft.options(ConflictOverwrite)
+	opts.DryRun = true
+	report, err := Entry(deleteEntry(a), opts)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, StatusRestored, report.Results[0].Status)
+	assert.Equal(t, "new", readFile(t, a))
+	assert.Empty(t, ft.displaced)
+	assert.Contains(t, ft.items, a)
+}
+
+func TestEntryPaths(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	ft := &fakeTrash{items: map[string]string{a: "aaa", b: "bbb"}}
+
+	opts := ft.options(ConflictRename)
+	opts.Paths = []string{b}
+	report, err := Entry(deleteEntry(a, b), opts)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, b, report.Results[0].Path)
+
+	opts.Paths = []string{filepath.Join(dir, "c.txt")}
+	_, err = Entry(deleteEntry(a, b), opts)
+	assert.Error(t, err)
+}
+
+func TestEntryRecordsRestore(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	ft := &fakeTrash{items: map[string]string{a: "aaa"}}
+	m, err := manifest.New(filepath.Join(dir, "manifest"))
+	require.NoError(t, err)
+
+	opts := ft.options(ConflictRename)
+	opts.Manifest = m
+	report, err := Entry(deleteEntry(a), opts)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Manifest)
+
+	entry, err := m.Get(report.Manifest)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OpRestore, entry.Operation)
+	assert.Equal(t, "delete-1", entry.Restores)
+	assert.Equal(t, a, entry.Files[0].Path)
+}
+
+func TestEntryNotRestorable(t *testing.T) {
+	_, err := Entry(&manifest.Entry{ID: "scan-1", Operation: manifest.OpScan}, Options{})
+	assert.ErrorIs(t, err, ErrNotRestorable)
+}
+
+func TestLatest(t *testing.T) {
+	entries := []manifest.Entry{
+		{ID: "restore-2", Operation: manifest.OpRestore, Restores: "delete-2"},
+		{ID: "scan-1", Operation: manifest.OpScan},
+		{ID: "delete-2", Operation: manifest.OpDelete},
+		{ID: "rule-1", Operation: manifest.OpRule},
+		{ID: "delete-1", Operation: manifest.OpDelete},
+	}
+	e, err := Latest(entries)
+	require.NoError(t, err)
+	assert.Equal(t, "rule-1", e.ID)
+
+	_, err = Latest(entries[:3])
+	assert.Error(t, err)
+}
+
+func TestTrashRecords(t *testing.T) {
+	deleted := time.Now().Add(-time.Hour).UTC()
+	entries := []manifest.Entry{
+		{ID: "restore-1", Operation: manifest.OpRestore, Restores: "delete-1", Files: []manifest.FileRecord{{Path: "/data/c.iso"}}},
+		{ID: "delete-1", Operation: manifest.OpDelete, Timestamp: deleted.Add(time.Minute), Files: []manifest.FileRecord{
+			{Path: "/data/a.iso", Size: 100, DeletedAt: deleted},
+			{Path: "/data/b.iso", Size: 200},
+		}},
+		{ID: "scan-1", Operation: manifest.OpScan, Files: []manifest.FileRecord{{Path: "/data/scanned.iso", Size: 10}}},
+	}
+
+	records := TrashRecords(entries)
+	require.Len(t, records, 2, "only deleted files")
+	assert.Equal(t, trash.Record{Path: "/data/a.iso", Size: 100, Deleted: deleted, Entry: "delete-1"}, records[0])
+	assert.Equal(t, entries[1].Timestamp, records[1].Deleted, "files without a deletion time use the entry's")
+}
+
+func TestFreeName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.tar.gz")
+
+	name, err := freeName(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "a.tar (restored).gz"), name)
+
+	require.NoError(t, os.WriteFile(name, nil, 0o644))
+	name, err = freeName(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "a.tar (restored 2).gz"), name)
+}
+
+func TestParseConflict(t *testing.T) {
+	c, err := ParseConflict("")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictRename, c)
+
+	c, err = ParseConflict("Overwrite")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictOverwrite, c)
+
+	_, err = ParseConflict("merge")
+	assert.ErrorIs(t, err, ErrUnknownConflict)
+}
+
+func TestWrite(t *testing.T) {
+	report := Report{
+		Entry: "delete-1",
+		Results: []Result{
+			{Path: "/a", RestoredTo: "/a", Size: 1024, Status: StatusRestored},
+			{Path: "/b", RestoredTo: "/b (restored)", Size: 1024, Status: StatusRestored},
+			{Path: "/c", Size: 10, Status: StatusSkipped, Error: "/c already exists"},
+		},
+		Manifest: "restore-1",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, output.ReportText, report))
+	out := buf.String()
+	assert.Contains(t, out, "/b -> /b (restored)")
+	assert.Contains(t, out, "Restored 2 of 3 file(s) from delete-1")
+	assert.Contains(t, out, "1 skipped")
+	assert.Contains(t, out, "restore-1")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, Report{Entry: "delete-1"}))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, []any{}, decoded["results"])
+
+	err := Write(&buf, "yaml", report)
+	assert.ErrorIs(t, err, output.ErrUnknownReportFormat)
+	assert.True(t, strings.Contains(err.Error(), "json"))
+}
@@ -0,0 +1,20 @@
+//go:build darwin
+
+package rules
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time of a file. Most filesystems are
+// mounted relatime, which updates it at most daily, enough to tell which
+// cache entries a tool still reads.
+func accessTime(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+}
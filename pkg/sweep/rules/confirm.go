@@ -0,0 +1,223 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// Confirm is how deleting files is confirmed.
+type Confirm int
+
+const (
+	ConfirmStandard Confirm = iota // A yes or no dialog
+	ConfirmNone                    // No dialog
+	ConfirmStrict                  // A word must be typed
+)
+
+// ErrUnknownConfirm is returned for a confirmation level that doesn't exist.
+var ErrUnknownConfirm = errors.New("unknown confirmation level (available: none, standard, strict)")
+
+// ParseConfirm parses "none", "standard", or "strict".
+func ParseConfirm(s string) (Confirm, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none":
+		return ConfirmNone, nil
+	case "standard":
+		return ConfirmStandard, nil
+	case "strict":
+		return ConfirmStrict, nil
+	}
+	return ConfirmStandard, fmt.Errorf("%w: %q", ErrUnknownConfirm, s)
+}
+
+// String returns the level's name in the config file.
+func (c Confirm) String() string {
+	switch c {
+	case ConfirmNone:
+		return "none"
+	case ConfirmStrict:
+		return "strict"
+	default:
+		return "standard"
+	}
+}
+
+// rank orders levels from none to strict.
+func (c Confirm) rank() int {
+	switch c {
+	case ConfirmNone:
+		return 0
+	case ConfirmStrict:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Directory classes of a confirmation policy.
+const (
+	ClassCache     = "cache"
+	ClassHome      = "home"
+	ClassDocuments = "documents"
+)
+
+// DirClass is a class of directories, deleting from which is confirmed
+// alike.
+type DirClass struct {
+	Name    string
+	Confirm Confirm
+	Dirs    []string // Files anywhere beneath these are in the class
+	Direct  []string // Only files directly in these are, as for the home directory
+}
+
+// ConfirmPolicy decides how deleting a file is confirmed from the class of
+// directory it is in.
+type ConfirmPolicy struct {
+	Classes []DirClass
+	Default Confirm // For files in no class
+}
+
+// ConfirmPolicyFromConfig builds the policy the confirm settings describe,
+// or returns nil when they are not enabled. Caches are the user's cache
+// directory, the caches of the rule presets, the paths of preset rules
+// among rules, and the configured cache_dirs.
+func ConfirmPolicyFromConfig(cc config.ConfirmConfig, rules []Rule) (*ConfirmPolicy, error) {
+	if !cc.Enabled {
+		return nil, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	level := func(key, value string, def Confirm) (Confirm, error) {
+		if value == "" {
+			return def, nil
+		}
+		c, err := ParseConfirm(value)
+		if err != nil {
+			return c, fmt.Errorf("confirm.%s: %w", key, err)
+		}
+		return c, nil
+	}
+	p := &ConfirmPolicy{}
+	if p.Default, err = level("default", cc.Default, ConfirmStandard); err != nil {
+		return nil, err
+	}
+	cache := DirClass{Name: ClassCache, Dirs: []string{filepath.Join(homeDir, ".cache")}}
+	homeRoot := DirClass{Name: ClassHome, Direct: []string{homeDir}}
+	documents := DirClass{Name: ClassDocuments, Dirs: []string{filepath.Join(homeDir, "Documents"), filepath.Join(homeDir, "Desktop")}}
+	if cache.Confirm, err = level("cache", cc.Cache, ConfirmNone); err != nil {
+		return nil, err
+	}
+	if homeRoot.Confirm, err = level("home", cc.Home, ConfirmStrict); err != nil {
+		return nil, err
+	}
+	if documents.Confirm, err = level("documents", cc.Documents, ConfirmStrict); err != nil {
+		return nil, err
+	}
+
+	if dir, err := os.UserCacheDir(); err == nil {
+		cache.Dirs = append(cache.Dirs, dir)
+	}
+	for i := range Presets {
+		cache.Dirs = append(cache.Dirs, Presets[i].DefaultPath())
+	}
+	for _, r := range rules {
+		if r.Preset != nil {
+			cache.Dirs = append(cache.Dirs, r.Path)
+		}
+	}
+	if cache.Dirs, err = appendDirs(cache.Dirs, "cache_dirs", cc.CacheDirs); err != nil {
+		return nil, err
+	}
+	if documents.Dirs, err = appendDirs(documents.Dirs, "document_dirs", cc.DocumentDirs); err != nil {
+		return nil, err
+	}
+
+	p.Classes = []DirClass{cache, homeRoot, documents}
+	return p, nil
+}
+
+// appendDirs adds configured directories to dirs, expanding ~ and making
+// them absolute, and drops repeats.
+func appendDirs(dirs []string, key string, configured []string) ([]string, error) {
+	for _, dir := range configured {
+		expanded, err := config.ExpandPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(expanded) {
+			return nil, fmt.Errorf("invalid confirm.%s entry %q: must be an absolute path", key, dir)
+		}
+		dirs = append(dirs, expanded)
+	}
+	for i, dir := range dirs {
+		dirs[i] = filepath.Clean(dir)
+	}
+	slices.Sort(dirs)
+	return slices.Compact(dirs), nil
+}
+
+// Classify returns the class of the directory holding path. When the
+// directories of several classes hold it, the deepest decides, so a cache
+// inside the home directory is still a cache.
+func (p *ConfirmPolicy) Classify(path string) (DirClass, bool) {
+	path = filepath.Clean(path)
+	best, depth := -1, -1
+	for i, c := range p.Classes {
+		for _, dir := range c.Dirs {
+			if len(dir) > depth && within(dir, path) {
+				best, depth = i, len(dir)
+			}
+		}
+		for _, dir := range c.Direct {
+			if len(dir) > depth && filepath.Dir(path) == dir {
+				best, depth = i, len(dir)
+			}
+		}
+	}
+	if best < 0 {
+		return DirClass{}, false
+	}
+	return p.Classes[best], true
+}
+
+// Level returns how deleting path is confirmed.
+func (p *ConfirmPolicy) Level(path string) Confirm {
+	if c, ok := p.Classify(path); ok {
+		return c.Confirm
+	}
+	return p.Default
+}
+
+// Strictest returns how deleting all of paths together is confirmed: the
+// strictest of their levels, with the name of the class asking for it, or
+// "" when it is the default.
+func (p *ConfirmPolicy) Strictest(paths []string) (Confirm, string) {
+	if len(paths) == 0 {
+		return p.Default, ""
+	}
+	level, class := ConfirmNone, ""
+	for i, path := range paths {
+		c, ok := p.Classify(path)
+		if !ok {
+			c = DirClass{Confirm: p.Default}
+		}
+		if i == 0 || c.Confirm.rank() > level.rank() {
+			level, class = c.Confirm, c.Name
+		}
+	}
+	return level, class
+}
+
+// within reports whether path is beneath dir.
+func within(dir, path string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmPolicyFromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+
+	policy, err := ConfirmPolicyFromConfig(config.ConfirmConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, policy, "the policy is off unless enabled")
+
+	rules, err := FromConfig([]config.RuleConfig{{Preset: "maven", Path: "/builds/m2", OlderThan: "30d"}})
+	require.NoError(t, err)
+	policy, err = ConfirmPolicyFromConfig(config.ConfirmConfig{
+		Enabled:      true,
+		CacheDirs:    []string{"~/scratch/tmp"},
+		DocumentDirs: []string{"/srv/papers"},
+	}, rules)
+	require.NoError(t, err)
+
+	for path, want := range map[string]Confirm{
+		filepath.Join(home, ".cache", "go", "a.o"):           ConfirmNone,
+		filepath.Join(home, ".gradle", "caches", "x.jar"):    ConfirmNone,
+		"/builds/m2/org/lib.jar":                             ConfirmNone,
+		filepath.Join(home, "scratch", "tmp", "frame.png"):   ConfirmNone,
+		filepath.Join(home, "notes.txt"):                     ConfirmStrict,
+		filepath.Join(home, "Documents", "thesis.pdf"):       ConfirmStrict,
+		filepath.Join(home, "Desktop", "shot.png"):           ConfirmStrict,
+		"/srv/papers/draft.tex":                              ConfirmStrict,
+		filepath.Join(home, "src", "big.iso"):                ConfirmStandard,
+		filepath.Join(home, "Documents", ".cache", "idx.db"): ConfirmStrict,
+	} {
+		assert.Equal(t, want, policy.Level(path), path)
+	}
+
+	level, class := policy.Strictest([]string{
+		filepath.Join(home, ".cache", "a"),
+		filepath.Join(home, "src", "b"),
+		filepath.Join(home, "Documents", "c"),
+	})
+	assert.Equal(t, ConfirmStrict, level)
+	assert.Equal(t, ClassDocuments, class)
+
+	level, class = policy.Strictest([]string{filepath.Join(home, ".cache", "a"), filepath.Join(home, "src", "b")})
+	assert.Equal(t, ConfirmStandard, level)
+	assert.Empty(t, class, "the default level has no class")
+}
+
+func TestConfirmPolicyFromConfigLevels(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	policy, err := ConfirmPolicyFromConfig(config.ConfirmConfig{Enabled: true, Cache: "standard", Default: "Strict"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmStrict, policy.Default)
+	assert.Equal(t, ConfirmStandard, policy.Classes[0].Confirm)
+
+	_, err = ConfirmPolicyFromConfig(config.ConfirmConfig{Enabled: true, Home: "paranoid"}, nil)
+	assert.ErrorIs(t, err, ErrUnknownConfirm)
+	assert.ErrorContains(t, err, "confirm.home")
+
+	_, err = ConfirmPolicyFromConfig(config.ConfirmConfig{Enabled: true, CacheDirs: []string{"relative/dir"}}, nil)
+	assert.ErrorContains(t, err, "must be an absolute path")
+}
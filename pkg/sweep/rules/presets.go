@@ -0,0 +1,447 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrUnknownPreset is returned for a rule naming a preset that doesn't exist.
+var ErrUnknownPreset = errors.New("unknown preset")
+
+// Preset cleans a build tool's cache entry by entry instead of file by file:
+// each dependency version, tool version, or Bazel output base is deleted
+// whole, aged by when the tool last used it rather than when it was
+// downloaded, and attributed to the projects that use it.
+type Preset struct {
+	Name        string
+	Description string
+
+	path    func() string                       // Default cache location
+	entries func(root string) ([]string, error) // Deletable entries under the cache root
+	markers []string                            // Files in an entry the tool writes on every use
+	reads   []string                            // Files in an entry sweep reads, so their access time means nothing
+
+	// workspace returns the project the cache records an entry as built
+	// for, or "" when it doesn't record one.
+	workspace func(entry string) string
+
+	// uses reports whether a project found under a rule's projects uses
+	// an entry.
+	uses func(root, entry string, p buildFiles) bool
+}
+
+// Presets are the caches rules can clean with preset.
+var Presets = []Preset{
+	{
+		Name:        "gradle",
+		Description: "Gradle dependency versions, and the caches, wrappers, and daemons of each Gradle version",
+		path:        func() string { return filepath.Join(home(), ".gradle") },
+		entries:     gradleEntries,
+		uses:        gradleUses,
+	},
+	{
+		Name:        "maven",
+		Description: "Maven repository artifact versions",
+		path:        func() string { return filepath.Join(home(), ".m2", "repository") },
+		entries:     mavenEntries,
+		uses:        mavenUses,
+	},
+	{
+		Name:        "bazel",
+		Description: "Bazel output bases, one per workspace",
+		path:        bazelOutputUserRoot,
+		entries:     bazelEntries,
+		markers:     []string{"command.log"},
+		reads:       []string{"DO_NOT_BUILD_HERE"},
+		workspace:   bazelWorkspace,
+	},
+}
+
+// LookupPreset returns the preset with the given name.
+func LookupPreset(name string) (*Preset, error) {
+	for i := range Presets {
+		if Presets[i].Name == name {
+			return &Presets[i], nil
+		}
+	}
+	names := make([]string, len(Presets))
+	for i, p := range Presets {
+		names[i] = p.Name
+	}
+	return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownPreset, name, strings.Join(names, ", "))
+}
+
+// DefaultPath returns where the tool keeps its cache by default.
+func (p *Preset) DefaultPath() string {
+	return p.path()
+}
+
+func home() string {
+	dir, _ := os.UserHomeDir()
+	return dir
+}
+
+// match returns the preset's entries under the rule's path that the rule
+// selects, with their size, when they were last used, and the projects
+// using them.
+func (p *Preset) match(ctx context.Context, r Rule, now time.Time) ([]File, error) {
+	if _, err := os.Stat(r.Path); err != nil {
+		return nil, err
+	}
+	entries, err := p.entries(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := findBuildFiles(ctx, r.Projects)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now.Add(-r.OlderThan)
+	var files []File
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !r.included(filepath.Base(entry)) {
+			continue
+		}
+		f, ok := p.stat(entry)
+		if !ok || f.Size < r.MinSize {
+			continue
+		}
+		if r.OlderThan > 0 && !f.LastUsed.Before(cutoff) {
+			continue
+		}
+		if p.workspace != nil {
+			if ws := p.workspace(entry); ws != "" {
+				f.Projects = append(f.Projects, ws)
+				if _, err := os.Stat(ws); errors.Is(err, fs.ErrNotExist) {
+					f.Orphaned = true
+				}
+			}
+		}
+		if p.uses != nil {
+			for _, bf := range projects {
+				if p.uses(r.Path, entry, bf) && !slices.Contains(f.Projects, bf.dir) {
+					f.Projects = append(f.Projects, bf.dir)
+				}
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// stat totals an entry's size and finds when it was last used: the newest
+// access or modification of a file in it, or of one of the preset's
+// markers. Directory times are ignored, since listing or cleaning a cache
+// touches them without using it.
+func (p *Preset) stat(entry string) (File, bool) {
+	f := File{Path: entry}
+	err := filepath.WalkDir(entry, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == entry {
+				return err
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		f.Size += info.Size()
+		if info.ModTime().After(f.ModTime) {
+			f.ModTime = info.ModTime()
+		}
+		if at := accessTime(info); at.After(f.LastUsed) && !slices.Contains(p.reads, d.Name()) {
+			f.LastUsed = at
+		}
+		return nil
+	})
+	if err != nil {
+		return File{}, false
+	}
+	for _, m := range p.markers {
+		if info, err := os.Stat(filepath.Join(entry, m)); err == nil && info.ModTime().After(f.LastUsed) {
+			f.LastUsed = info.ModTime()
+		}
+	}
+	if f.ModTime.After(f.LastUsed) {
+		f.LastUsed = f.ModTime
+	}
+	return f, true
+}
+
+// subdirs returns the directories matching a glob pattern.
+func subdirs(pattern string) []string {
+	matches, _ := filepath.Glob(pattern)
+	dirs := matches[:0]
+	for _, m := range matches {
+		if info, err := os.Lstat(m); err == nil && info.IsDir() {
+			dirs = append(dirs, m)
+		}
+	}
+	return dirs
+}
+
+// gradleVersion matches the Gradle version in a versioned cache, wrapper
+// distribution, or daemon directory name, e.g. 8.5 in gradle-8.5-bin.
+var gradleVersion = regexp.MustCompile(`^(?:gradle-)?(\d+\.\d+(?:\.\d+)?(?:-(?:rc|milestone)-\d+)?)(?:-(?:bin|all))?$`)
+
+// gradleEntries lists each dependency version in the module cache, and the
+// caches, wrapper distributions, and daemon logs of each Gradle version.
+func gradleEntries(root string) ([]string, error) {
+	entries := subdirs(filepath.Join(root, "caches", "modules-2", "files-2.1", "*", "*", "*"))
+	for _, dir := range []string{filepath.Join(root, "caches"), filepath.Join(root, "wrapper", "dists"), filepath.Join(root, "daemon")} {
+		for _, d := range subdirs(filepath.Join(dir, "*")) {
+			if gradleVersion.MatchString(filepath.Base(d)) {
+				entries = append(entries, d)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// gradleUses reports whether a project builds with an entry's Gradle
+// version, named in its wrapper properties, or declares an entry's
+// dependency as group:artifact:version in a build script.
+func gradleUses(root, entry string, p buildFiles) bool {
+	rel, err := filepath.Rel(filepath.Join(root, "caches", "modules-2", "files-2.1"), entry)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		coord := []byte(strings.ReplaceAll(filepath.ToSlash(rel), "/", ":"))
+		return bytes.Contains(p.files["build.gradle"], coord) || bytes.Contains(p.files["build.gradle.kts"], coord)
+	}
+	if m := gradleVersion.FindStringSubmatch(filepath.Base(entry)); m != nil {
+		return bytes.Contains(p.files["gradle-wrapper.properties"], []byte("gradle-"+m[1]+"-"))
+	}
+	return false
+}
+
+// mavenEntries lists each artifact version in a Maven repository: the
+// directories holding a .pom.
+func mavenEntries(root string) ([]string, error) {
+	var entries []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !strings.HasSuffix(d.Name(), ".pom") {
+			return nil
+		}
+		entries = append(entries, filepath.Dir(path))
+		return fs.SkipDir // The rest of the version directory
+	})
+	return entries, err
+}
+
+// mavenUses reports whether a project's pom declares an entry's artifact
+// version as a dependency, plugin, or parent.
+func mavenUses(root, entry string, p buildFiles) bool {
+	if p.pom == nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, entry)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 3 {
+		return false
+	}
+	want := pomCoord{
+		GroupID:    strings.Join(parts[:len(parts)-2], "."),
+		ArtifactID: parts[len(parts)-2],
+		Version:    parts[len(parts)-1],
+	}
+	return slices.Contains(p.pom.coords(), want)
+}
+
+// bazelOutputUserRoot returns Bazel's default output user root, which
+// holds an output base for each workspace built.
+func bazelOutputUserRoot() string {
+	name := "_bazel_"
+	if u, err := user.Current(); err == nil {
+		name += filepath.Base(u.Username) // DOMAIN\user on Windows
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join("/private/var/tmp", name)
+	case "windows":
+		return filepath.Join(home(), name)
+	default:
+		return filepath.Join(home(), ".cache", "bazel", name)
+	}
+}
+
+// bazelEntries lists the output bases under an output user root, which
+// Bazel marks with the workspace they belong to.
+func bazelEntries(root string) ([]string, error) {
+	var entries []string
+	for _, d := range subdirs(filepath.Join(root, "*")) {
+		if _, err := os.Stat(filepath.Join(d, "DO_NOT_BUILD_HERE")); err == nil {
+			entries = append(entries, d)
+		}
+	}
+	return entries, nil
+}
+
+// bazelWorkspace returns the workspace an output base was created for.
+func bazelWorkspace(entry string) string {
+	data, err := os.ReadFile(filepath.Join(entry, "DO_NOT_BUILD_HERE"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// buildFiles are the build files of a project that say which cache entries
+// it uses.
+type buildFiles struct {
+	dir   string
+	files map[string][]byte // Contents by name
+	pom   *pom
+}
+
+// buildFileNames are the build files read, with the directory, relative to
+// the file, of the project they belong to.
+var buildFileNames = map[string]string{
+	"pom.xml":                   ".",
+	"build.gradle":              ".",
+	"build.gradle.kts":          ".",
+	"gradle-wrapper.properties": "../..", // gradle/wrapper/
+}
+
+// skipDirs are directories never searched for build files, holding
+// dependencies or build output rather than projects.
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "target": true, "build": true, ".gradle": true, "bazel-out": true,
+}
+
+// findBuildFiles finds the projects under dirs and reads their build files.
+func findBuildFiles(ctx context.Context, dirs []string) ([]buildFiles, error) {
+	byDir := make(map[string]*buildFiles)
+	var found []*buildFiles
+	for _, root := range dirs {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				if path == root {
+					return err
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && skipDirs[d.Name()] {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			rel, ok := buildFileNames[d.Name()]
+			if !ok || !d.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			dir := filepath.Clean(filepath.Join(filepath.Dir(path), rel))
+			bf := byDir[dir]
+			if bf == nil {
+				bf = &buildFiles{dir: dir, files: make(map[string][]byte)}
+				byDir[dir] = bf
+				found = append(found, bf)
+			}
+			bf.files[d.Name()] = data
+			if d.Name() == "pom.xml" {
+				var p pom
+				if xml.Unmarshal(data, &p) == nil {
+					bf.pom = &p
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("search projects in %s: %w", root, err)
+		}
+	}
+	out := make([]buildFiles, len(found))
+	for i, bf := range found {
+		out[i] = *bf
+	}
+	return out, nil
+}
+
+// pom is the part of a Maven pom.xml naming the artifacts a project uses.
+type pom struct {
+	Version      string     `xml:"version"`
+	Parent       pomCoord   `xml:"parent"`
+	Dependencies []pomCoord `xml:"dependencies>dependency"`
+	Managed      []pomCoord `xml:"dependencyManagement>dependencies>dependency"`
+	Plugins      []pomCoord `xml:"build>plugins>plugin"`
+	Properties   struct {
+		Items []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+}
+
+// pomCoord identifies an artifact version.
+type pomCoord struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// pomProperty matches a ${property} reference.
+var pomProperty = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// coords returns the artifact versions the pom names, with ${property}
+// references to its own properties and version resolved.
+func (p *pom) coords() []pomCoord {
+	props := map[string]string{"project.version": p.Version, "version": p.Version}
+	for _, item := range p.Properties.Items {
+		props[item.XMLName.Local] = strings.TrimSpace(item.Value)
+	}
+	resolve := func(s string) string {
+		return pomProperty.ReplaceAllStringFunc(strings.TrimSpace(s), func(ref string) string {
+			if v, ok := props[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+
+	var coords []pomCoord
+	for _, c := range slices.Concat([]pomCoord{p.Parent}, p.Dependencies, p.Managed, p.Plugins) {
+		c = pomCoord{GroupID: resolve(c.GroupID), ArtifactID: resolve(c.ArtifactID), Version: resolve(c.Version)}
+		if c.GroupID == "" && c.ArtifactID != "" {
+			c.GroupID = "org.apache.maven.plugins" // The default for plugins
+		}
+		if c.ArtifactID != "" && c.Version != "" {
+			coords = append(coords, c)
+		}
+	}
+	return coords
+}
@@ -0,0 +1,163 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func presetRule(t *testing.T, rc config.RuleConfig) Rule {
+	t.Helper()
+	list, err := FromConfig([]config.RuleConfig{rc})
+	require.NoError(t, err)
+	return list[0]
+}
+
+func byPath(files []File) map[string]File {
+	out := make(map[string]File, len(files))
+	for _, f := range files {
+		out[f.Path] = f
+	}
+	return out
+}
+
+func TestFromConfigPreset(t *testing.T) {
+	r := presetRule(t, config.RuleConfig{Preset: "maven", OlderThan: "90d", Projects: []string{"/src"}})
+	assert.Equal(t, "maven", r.Name, "preset rules are named after the preset")
+	require.NotNil(t, r.Preset)
+	assert.Equal(t, r.Preset.DefaultPath(), r.Path)
+	assert.Equal(t, []string{"/src"}, r.Projects)
+
+	_, err := FromConfig([]config.RuleConfig{{Preset: "npm", OlderThan: "1d"}})
+	assert.ErrorIs(t, err, ErrUnknownPreset)
+	_, err = FromConfig([]config.RuleConfig{{Preset: "gradle"}})
+	assert.ErrorIs(t, err, ErrNoCriteria)
+	_, err = FromConfig([]config.RuleConfig{{Path: "/tmp", OlderThan: "1d", Projects: []string{"/src"}}})
+	assert.ErrorContains(t, err, "needs a preset")
+}
+
+func TestGradlePreset(t *testing.T) {
+	root, src := t.TempDir(), t.TempDir()
+	old, recent := testNow.AddDate(-1, 0, 0), testNow.AddDate(0, 0, -7)
+	modules := filepath.Join(root, "caches", "modules-2", "files-2.1")
+	guavaOld := filepath.Join(modules, "com.google.guava", "guava", "31.0")
+	guavaNew := filepath.Join(modules, "com.google.guava", "guava", "32.0")
+	junit := filepath.Join(modules, "junit", "junit", "4.13")
+	dist := filepath.Join(root, "wrapper", "dists", "gradle-8.5-bin")
+	caches := filepath.Join(root, "caches", "8.5")
+	writeFile(t, filepath.Join(guavaOld, "abc", "guava-31.0.jar"), 3000, old)
+	writeFile(t, filepath.Join(guavaNew, "def", "guava-32.0.jar"), 3000, recent)
+	writeFile(t, filepath.Join(junit, "123", "junit-4.13.jar"), 1000, old)
+	writeFile(t, filepath.Join(dist, "x", "gradle-8.5", "lib", "gradle.jar"), 5000, old)
+	writeFile(t, filepath.Join(caches, "fileHashes", "fileHashes.bin"), 2000, old)
+	writeFile(t, filepath.Join(root, "caches", "jars-9", "a.jar"), 100, old)
+
+	app := filepath.Join(src, "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(app, "gradle", "wrapper"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(app, "build.gradle"),
+		[]byte("dependencies {\n  implementation 'com.google.guava:guava:31.0'\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(app, "gradle", "wrapper", "gradle-wrapper.properties"),
+		[]byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-8.5-bin.zip\n"), 0644))
+
+	r := presetRule(t, config.RuleConfig{Preset: "gradle", Path: root, OlderThan: "180d", Projects: []string{src}})
+	files, err := r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+
+	got := byPath(files)
+	assert.Len(t, got, 4, "recent versions and unversioned caches are kept")
+	assert.Equal(t, dist, files[0].Path, "largest first")
+	assert.Equal(t, []string{app}, got[guavaOld].Projects)
+	assert.Equal(t, []string{app}, got[dist].Projects)
+	assert.Equal(t, []string{app}, got[caches].Projects)
+	assert.Empty(t, got[junit].Projects)
+	assert.Equal(t, int64(3000), got[guavaOld].Size)
+	assert.True(t, got[guavaOld].LastUsed.Equal(old))
+}
+
+func TestMavenPreset(t *testing.T) {
+	root, src := t.TempDir(), t.TempDir()
+	old := testNow.AddDate(-1, 0, 0)
+	slf4j := filepath.Join(root, "org", "slf4j", "slf4j-api", "1.7.36")
+	surefire := filepath.Join(root, "org", "apache", "maven", "plugins", "maven-surefire-plugin", "3.0.0")
+	unused := filepath.Join(root, "commons-io", "commons-io", "2.11.0")
+	for _, dir := range []string{slf4j, surefire, unused} {
+		name := filepath.Base(filepath.Dir(dir)) + "-" + filepath.Base(dir)
+		writeFile(t, filepath.Join(dir, name+".pom"), 100, old)
+		writeFile(t, filepath.Join(dir, name+".jar"), 1000, old)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, "pom.xml"), []byte(`<project>
+  <version>1.0</version>
+  <properties><slf4j.version>1.7.36</slf4j.version></properties>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>${slf4j.version}</version></dependency>
+  </dependencies>
+  <build><plugins>
+    <plugin><artifactId>maven-surefire-plugin</artifactId><version>3.0.0</version></plugin>
+  </plugins></build>
+</project>`), 0644))
+
+	r := presetRule(t, config.RuleConfig{Preset: "maven", Path: root, OlderThan: "30d", Projects: []string{src}})
+	files, err := r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+
+	got := byPath(files)
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{src}, got[slf4j].Projects, "properties are resolved")
+	assert.Equal(t, []string{src}, got[surefire].Projects, "plugins default to the Maven plugins group")
+	assert.Empty(t, got[unused].Projects)
+	assert.Equal(t, int64(1100), got[unused].Size)
+}
+
+func TestBazelPreset(t *testing.T) {
+	root, workspace := t.TempDir(), t.TempDir()
+	old := testNow.AddDate(0, -6, 0)
+	gone := filepath.Join(root, "0a1b")
+	active := filepath.Join(root, "2c3d")
+	writeFile(t, filepath.Join(gone, "execroot", "out.o"), 4000, old)
+	require.NoError(t, os.WriteFile(filepath.Join(gone, "DO_NOT_BUILD_HERE"), []byte("/nonexistent/ws"), 0644))
+	writeFile(t, filepath.Join(active, "execroot", "out.o"), 4000, old)
+	require.NoError(t, os.WriteFile(filepath.Join(active, "DO_NOT_BUILD_HERE"), []byte(workspace), 0644))
+	writeFile(t, filepath.Join(root, "install", "abc", "bazel"), 100, old)
+	for _, dir := range []string{gone, active} {
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "DO_NOT_BUILD_HERE"), old, old))
+	}
+
+	r := presetRule(t, config.RuleConfig{Preset: "bazel", Path: root, OlderThan: "30d"})
+	files, err := r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+	require.Len(t, files, 2, "the install base isn't an output base")
+	got := byPath(files)
+	assert.True(t, got[gone].Orphaned)
+	assert.Equal(t, []string{"/nonexistent/ws"}, got[gone].Projects)
+	assert.False(t, got[active].Orphaned)
+	assert.Equal(t, []string{workspace}, got[active].Projects)
+
+	// A build writes command.log, so the output base counts as used
+	writeFile(t, filepath.Join(active, "command.log"), 10, testNow.Add(-time.Hour))
+	files, err = r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{gone}, []string{files[0].Path})
+	assert.Len(t, files, 1)
+}
+
+func TestWritePresetReport(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, WriteText(&b, []Report{{
+		Rule: "bazel", Path: "/cache", Preset: "bazel", DryRun: true,
+		Matched: []File{
+			{Path: "/cache/0a1b", Size: 4096, LastUsed: testNow, Projects: []string{"/ws"}, Orphaned: true},
+			{Path: "/cache/2c3d", Size: 1024, LastUsed: testNow, Projects: []string{"/src/app"}},
+		},
+	}}))
+	assert.Contains(t, b.String(), "Would delete 2 bazel cache entries")
+	assert.Contains(t, b.String(), "last used 2025-06-01, workspace gone: /ws")
+	assert.Contains(t, b.String(), "last used 2025-06-01, by /src/app")
+}
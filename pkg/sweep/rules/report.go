@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// textFiles is the number of matched files listed per rule in text reports.
+const textFiles = 10
+
+// Write renders reports in the given format.
+func Write(w io.Writer, format string, reports []Report) error {
+	return output.WriteReport(w, format, reports, output.ReportWriters[[]Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders each report with its largest matched files.
+func WriteText(w io.Writer, reports []Report) error {
+	var b strings.Builder
+	for i, r := range reports {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if r.Rule == r.Path {
+			fmt.Fprintf(&b, "%s\n", r.Rule)
+		} else {
+			fmt.Fprintf(&b, "%s (%s)\n", r.Rule, r.Path)
+		}
+
+		noun := "file(s)"
+		if r.Preset != "" {
+			noun = r.Preset + " cache entries"
+		}
+		switch {
+		case len(r.Matched) == 0:
+			b.WriteString("  No matching files\n")
+			continue
+		case r.DryRun:
+			fmt.Fprintf(&b, "  Would delete %d %s, %s\n", len(r.Matched), noun, types.FormatSize(r.MatchedBytes()))
+		default:
+			fmt.Fprintf(&b, "  Moved %d of %d %s to trash, %s\n", len(r.Deleted), len(r.Matched), noun, types.FormatSize(r.DeletedBytes()))
+		}
+
+		for _, f := range r.Matched[:min(len(r.Matched), textFiles)] {
+			fmt.Fprintf(&b, "    %10s  %s\n", types.FormatSize(f.Size), f.Path)
+			if r.Preset != "" {
+				fmt.Fprintf(&b, "    %10s  %s\n", "", describeUse(f))
+			}
+		}
+		if more := len(r.Matched) - textFiles; more > 0 {
+			fmt.Fprintf(&b, "    ... and %d more\n", more)
+		}
+		for _, f := range r.Failed {
+			fmt.Fprintf(&b, "  Failed: %s: %s\n", f.Path, f.Error)
+		}
+		if r.Manifest != "" {
+			fmt.Fprintf(&b, "  Recorded in history as %s\n", r.Manifest)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// describeUse summarizes when a preset entry was last used and by what,
+// e.g. "last used 2024-03-01, by /src/app".
+func describeUse(f File) string {
+	s := "last used " + f.LastUsed.Format("2006-01-02")
+	projects := f.Projects
+	if f.Orphaned && len(projects) > 0 {
+		s += ", workspace gone: " + projects[0]
+		projects = projects[1:]
+	}
+	if len(projects) > 0 {
+		s += ", by " + strings.Join(projects, ", ")
+	}
+	return s
+}
+
+// WriteJSON renders reports as a JSON array.
+func WriteJSON(w io.Writer, reports []Report) error {
+	out := make([]Report, len(reports))
+	for i, r := range reports {
+		if r.Matched == nil {
+			r.Matched = []File{}
+		}
+		out[i] = r
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,338 @@
+// Package rules evaluates cleanup rules from the config file, such as
+// "delete *.log in ~/Library/Logs older than 30 days", and runs them on
+// their schedules.
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/filter"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// ErrNoCriteria is returned for a rule that would match every file under
+// its path.
+var ErrNoCriteria = errors.New("rule needs include, older_than, or min_size")
+
+// Rule selects files under a directory for deletion.
+type Rule struct {
+	Name      string
+	Path      string
+	Include   []string      // Glob patterns matched against file names; empty matches all
+	OlderThan time.Duration // Only files unmodified for longer; 0 for any age
+	MinSize   int64         // Only files at least this large; 0 for any size
+	Schedule  Schedule      // When the daemon runs the rule; zero for on demand only
+
+	// Preset, if set, selects whole entries of a build tool's cache under
+	// Path instead of files; OlderThan then applies to when each was last
+	// used. Projects are searched for the projects using each entry.
+	Preset   *Preset
+	Projects []string
+
+	// Backend is how the rule's matches are deleted, e.g.
+	// trash.BackendStaging; empty uses the configured default.
+	Backend string
+}
+
+// File is a file matched by a rule, or an entry of a preset's cache.
+type File struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+
+	// For preset entries: when the tool last used it, the projects using
+	// it, and whether the project the cache recorded it for is gone.
+	LastUsed time.Time `json:"last_used,omitzero"`
+	Projects []string  `json:"projects,omitempty"`
+	Orphaned bool      `json:"orphaned,omitempty"`
+}
+
+// Failure is a matched file that could not be deleted.
+type Failure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// Report is the outcome of running or testing a rule.
+type Report struct {
+	Rule     string    `json:"rule"`
+	Path     string    `json:"path"`
+	Preset   string    `json:"preset,omitempty"`
+	Started  time.Time `json:"started"`
+	DryRun   bool      `json:"dry_run"`
+	Matched  []File    `json:"matched"`
+	Deleted  []File    `json:"deleted,omitempty"`
+	Failed   []Failure `json:"failed,omitempty"`
+	Manifest string    `json:"manifest,omitempty"` // ID of the manifest entry
+}
+
+// MatchedBytes returns the total size of the matched files.
+func (r Report) MatchedBytes() int64 {
+	return totalSize(r.Matched)
+}
+
+// DeletedBytes returns the total size of the deleted files.
+func (r Report) DeletedBytes() int64 {
+	return totalSize(r.Deleted)
+}
+
+func totalSize(files []File) int64 {
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+	return total
+}
+
+// FromConfig validates rules from the config file. Paths have ~ expanded
+// and rules without a name are named after their path.
+func FromConfig(configured []config.RuleConfig) ([]Rule, error) {
+	rules := make([]Rule, 0, len(configured))
+	seen := make(map[string]bool)
+	for i, rc := range configured {
+		var preset *Preset
+		if rc.Preset != "" {
+			p, err := LookupPreset(rc.Preset)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			preset = p
+			if rc.Path == "" {
+				rc.Path = p.DefaultPath()
+			}
+			if rc.Name == "" {
+				rc.Name = p.Name
+			}
+		}
+		if rc.Path == "" {
+			return nil, fmt.Errorf("rule %d: path is required", i+1)
+		}
+		path, err := config.ExpandPath(rc.Path)
+		if err != nil {
+			return nil, err
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, err
+		}
+		r := Rule{Name: rc.Name, Path: path, Include: rc.Include, Preset: preset}
+		if r.Name == "" {
+			r.Name = rc.Path
+		}
+		for _, dir := range rc.Projects {
+			if preset == nil {
+				return nil, fmt.Errorf("rule %q: projects needs a preset", r.Name)
+			}
+			if dir, err = config.ExpandPath(dir); err != nil {
+				return nil, err
+			}
+			if dir, err = filepath.Abs(dir); err != nil {
+				return nil, err
+			}
+			r.Projects = append(r.Projects, dir)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("rule %q is defined twice", r.Name)
+		}
+		seen[r.Name] = true
+
+		for _, p := range r.Include {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid include pattern %q: %w", r.Name, p, err)
+			}
+		}
+		if rc.OlderThan != "" {
+			if r.OlderThan, err = filter.ParseDuration(rc.OlderThan); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid older_than: %w", r.Name, err)
+			}
+		}
+		if rc.MinSize != "" {
+			if r.MinSize, err = types.ParseSize(rc.MinSize); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid min_size %q: %w", r.Name, rc.MinSize, err)
+			}
+		}
+		if len(r.Include) == 0 && r.OlderThan == 0 && r.MinSize == 0 {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, ErrNoCriteria)
+		}
+		if rc.Schedule != "" {
+			if r.Schedule, err = ParseSchedule(rc.Schedule); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+		}
+		if rc.Backend != "" {
+			if r.Backend, err = trash.ParseBackend(rc.Backend); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Select returns the rules with the given names, or all rules when names
+// is empty.
+func Select(rules []Rule, names []string) ([]Rule, error) {
+	if len(names) == 0 {
+		return rules, nil
+	}
+	selected := make([]Rule, 0, len(names))
+	for _, name := range names {
+		i := -1
+		for j, r := range rules {
+			if r.Name == name {
+				i = j
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("no rule named %q", name)
+		}
+		selected = append(selected, rules[i])
+	}
+	return selected, nil
+}
+
+// Match walks the rule's path and returns the regular files it selects,
+// or the cache entries for a preset rule, largest first. Symbolic links
+// are never followed or matched, nor are online-only cloud placeholders:
+// deleting one frees next to nothing and may delete it from the cloud.
+func (r Rule) Match(ctx context.Context, now time.Time) ([]File, error) {
+	if r.Preset != nil {
+		files, err := r.Preset.match(ctx, r, now)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+		return files, nil
+	}
+
+	cutoff := now.Add(-r.OlderThan)
+	var files []File
+	err := filepath.WalkDir(r.Path, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == r.Path {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !r.included(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if r.OlderThan > 0 && !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if info.Size() < r.MinSize || cloud.FromFileInfo(path, info) != nil {
+			return nil
+		}
+		files = append(files, File{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	return files, nil
+}
+
+// included reports whether a file name matches the include patterns.
+func (r Rule) included(name string) bool {
+	if len(r.Include) == 0 {
+		return true
+	}
+	for _, p := range r.Include {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RunOptions configures Run.
+type RunOptions struct {
+	DryRun   bool                    // Only report what would be deleted
+	Delete   func(path string) error // Deletes a file; nil uses Backend
+	Backend  trash.Backend           // Deletes files when Delete is nil; nil uses the trash
+	Manifest *manifest.Manifest      // Records the deleted files; nil to skip
+	Now      time.Time               // Reference time for older_than; zero uses time.Now
+}
+
+// Run deletes the files a rule matches and records them in the manifest.
+// Files that fail to delete are reported rather than stopping the run.
+func Run(ctx context.Context, r Rule, opts RunOptions) (Report, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	var method string // Unknown for callers' Delete
+	locations := make(map[string]string)
+	if opts.Delete == nil {
+		backend := opts.Backend
+		if backend == nil {
+			backend, _ = trash.NewBackend(trash.BackendTrash, trash.BackendOptions{})
+		}
+		opts.Delete = func(path string) error {
+			res := backend.DeleteMany(ctx, []string{path}, nil)[0]
+			locations[path] = res.Location
+			return res.Err
+		}
+		method = backend.Name()
+	}
+
+	report := Report{Rule: r.Name, Path: r.Path, Started: opts.Now, DryRun: opts.DryRun}
+	if r.Preset != nil {
+		report.Preset = r.Preset.Name
+	}
+	matched, err := r.Match(ctx, opts.Now)
+	if err != nil {
+		return report, fmt.Errorf("rule %q: %w", r.Name, err)
+	}
+	report.Matched = matched
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, f := range matched {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		if err := opts.Delete(f.Path); err != nil {
+			report.Failed = append(report.Failed, Failure{Path: f.Path, Error: err.Error()})
+			continue
+		}
+		report.Deleted = append(report.Deleted, f)
+	}
+
+	if opts.Manifest != nil && len(report.Deleted) > 0 {
+		deletedAt := time.Now().UTC()
+		records := make([]manifest.FileRecord, len(report.Deleted))
+		for i, f := range report.Deleted {
+			records[i] = manifest.FileRecord{Path: f.Path, Size: f.Size, ModTime: f.ModTime, DeletedAt: deletedAt, Method: method, Location: locations[f.Path]}
+		}
+		if err := opts.Manifest.EnsureDir(); err != nil {
+			return report, fmt.Errorf("failed to record rule %q: %w", r.Name, err)
+		}
+		entry, err := opts.Manifest.LogRule(r.Name, records)
+		if err != nil {
+			return report, fmt.Errorf("failed to record rule %q: %w", r.Name, err)
+		}
+		report.Manifest = entry.ID
+	}
+	return report, ctx.Err()
+}
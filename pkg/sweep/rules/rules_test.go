@@ -0,0 +1,186 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/manifest"
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/trash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func paths(files []File) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = filepath.Base(f.Path)
+	}
+	return out
+}
+
+func TestFromConfig(t *testing.T) {
+	list, err := FromConfig([]config.RuleConfig{
+		{Name: "logs", Path: "/var/tmp/logs", Include: []string{"*.log"}, OlderThan: "30d", Schedule: "@daily"},
+		{Path: "/var/tmp/dl", MinSize: "100MB"},
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	assert.Equal(t, 30*24*time.Hour, list[0].OlderThan)
+	assert.Equal(t, "@daily", list[0].Schedule.String())
+	assert.Equal(t, "/var/tmp/dl", list[1].Name, "unnamed rules are named after their path")
+	assert.Equal(t, int64(100*1024*1024), list[1].MinSize)
+	assert.True(t, list[1].Schedule.IsZero())
+
+	selected, err := Select(list, []string{"/var/tmp/dl"})
+	require.NoError(t, err)
+	assert.Equal(t, list[1:], selected)
+	_, err = Select(list, []string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestFromConfigErrors(t *testing.T) {
+	tests := map[string]config.RuleConfig{
+		"no path":      {Name: "x", OlderThan: "1d"},
+		"no criteria":  {Name: "x", Path: "/tmp"},
+		"bad pattern":  {Name: "x", Path: "/tmp", Include: []string{"[a"}},
+		"bad age":      {Name: "x", Path: "/tmp", OlderThan: "soon"},
+		"bad size":     {Name: "x", Path: "/tmp", MinSize: "big"},
+		"bad schedule": {Name: "x", Path: "/tmp", OlderThan: "1d", Schedule: "daily"},
+	}
+	for name, rc := range tests {
+		_, err := FromConfig([]config.RuleConfig{rc})
+		assert.Error(t, err, name)
+	}
+
+	_, err := FromConfig([]config.RuleConfig{{Path: "/tmp"}})
+	assert.True(t, errors.Is(err, ErrNoCriteria))
+
+	_, err = FromConfig([]config.RuleConfig{
+		{Name: "x", Path: "/a", OlderThan: "1d"},
+		{Name: "x", Path: "/b", OlderThan: "1d"},
+	})
+	assert.ErrorContains(t, err, "defined twice")
+}
+
+func TestRuleMatch(t *testing.T) {
+	dir := t.TempDir()
+	old := testNow.AddDate(0, -2, 0)
+	writeFile(t, filepath.Join(dir, "old.log"), 100, old)
+	writeFile(t, filepath.Join(dir, "nested", "older.log"), 300, old.AddDate(0, -1, 0))
+	writeFile(t, filepath.Join(dir, "new.log"), 500, testNow.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, "old.txt"), 200, old)
+	require.NoError(t, os.Symlink(filepath.Join(dir, "old.log"), filepath.Join(dir, "link.log")))
+
+	r := Rule{Name: "logs", Path: dir, Include: []string{"*.log"}, OlderThan: 30 * 24 * time.Hour}
+	files, err := r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"older.log", "old.log"}, paths(files), "largest first, no symlinks")
+
+	r.MinSize = 200
+	files, err = r.Match(context.Background(), testNow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"older.log"}, paths(files))
+
+	_, err = Rule{Path: filepath.Join(dir, "missing"), MinSize: 1}.Match(context.Background(), testNow)
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	old := testNow.AddDate(-1, 0, 0)
+	writeFile(t, filepath.Join(dir, "a.log"), 100, old)
+	writeFile(t, filepath.Join(dir, "b.log"), 200, old)
+	writeFile(t, filepath.Join(dir, "locked.log"), 300, old)
+	r := Rule{Name: "logs", Path: dir, Include: []string{"*.log"}, OlderThan: 24 * time.Hour}
+
+	var deleted []string
+	del := func(path string) error {
+		if filepath.Base(path) == "locked.log" {
+			return errors.New("permission denied")
+		}
+		deleted = append(deleted, filepath.Base(path))
+		return os.Remove(path)
+	}
+
+	report, err := Run(context.Background(), r, RunOptions{DryRun: true, Delete: del, Now: testNow})
+	require.NoError(t, err)
+	assert.Len(t, report.Matched, 3)
+	assert.Empty(t, deleted, "dry run deletes nothing")
+
+	m, err := manifest.New(filepath.Join(t.TempDir(), "manifest"))
+	require.NoError(t, err)
+	report, err = Run(context.Background(), r, RunOptions{Delete: del, Manifest: m, Now: testNow})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a.log", "b.log"}, deleted)
+	assert.Equal(t, int64(300), report.DeletedBytes())
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, "permission denied", report.Failed[0].Error)
+
+	entry, err := m.Get(report.Manifest)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OpRule, entry.Operation)
+	assert.Equal(t, "logs", entry.Rule)
+	assert.Equal(t, int64(2), entry.Summary.TotalFiles)
+}
+
+func TestRunPermanentOutsideRoots(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache", "a.log")
+	writeFile(t, path, 100, testNow.AddDate(-1, 0, 0))
+	list, err := FromConfig([]config.RuleConfig{
+		{Name: "logs", Path: filepath.Dir(path), OlderThan: "1d", Backend: "permanent"},
+	})
+	require.NoError(t, err)
+	r := list[0]
+
+	backend, err := trash.NewBackend(r.Backend, trash.BackendOptions{
+		PermanentRoots: trash.NewPermanentRoots([]string{filepath.Join(dir, "scratch")}),
+	})
+	require.NoError(t, err)
+	report, err := Run(context.Background(), r, RunOptions{Backend: backend, Now: testNow})
+	require.NoError(t, err)
+	require.Len(t, report.Failed, 1)
+	assert.Contains(t, report.Failed[0].Error, "permanent_roots")
+	assert.FileExists(t, path, "files outside permanent_roots are kept")
+}
+
+func TestWrite(t *testing.T) {
+	reports := []Report{
+		{Rule: "logs", Path: "/var/log", DryRun: true, Matched: []File{{Path: "/var/log/a.log", Size: 2048}}},
+		{Rule: "/tmp", Path: "/tmp"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, output.ReportText, reports))
+	out := buf.String()
+	assert.Contains(t, out, "logs (/var/log)")
+	assert.Contains(t, out, "Would delete 1 file(s), 2.0 KiB")
+	assert.Contains(t, out, "/var/log/a.log")
+	assert.Contains(t, out, "No matching files")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, reports))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, []any{}, decoded[1]["matched"])
+
+	assert.True(t, errors.Is(Write(&buf, "xml", reports), output.ErrUnknownReportFormat))
+}
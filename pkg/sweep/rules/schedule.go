@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned for schedules that cannot be parsed.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// scheduleAliases are the cron shorthands.
+var scheduleAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule says when a rule runs: a five-field cron expression
+// ("minute hour day-of-month month day-of-week"), a shorthand such as
+// "@daily", or "@every 6h".
+type Schedule struct {
+	spec                          string
+	every                         time.Duration
+	minute, hour, dom, month, dow uint64 // Bit sets of allowed values
+	domAny, dowAny                bool   // Field was "*"
+}
+
+// ParseSchedule parses a schedule.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	s := Schedule{spec: spec}
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Minute {
+			return Schedule{}, fmt.Errorf("%w %q: @every needs a duration of at least 1m", ErrInvalidSchedule, spec)
+		}
+		s.every = d
+		return s, nil
+	}
+	expr := spec
+	if alias, ok := scheduleAliases[strings.ToLower(spec)]; ok {
+		expr = alias
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("%w %q: want 5 fields (minute hour day month weekday)", ErrInvalidSchedule, spec)
+	}
+	var err error
+	parse := func(field string, lo, hi int) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseField(field, lo, hi)
+		if err != nil {
+			err = fmt.Errorf("%w %q: %v", ErrInvalidSchedule, spec, err)
+		}
+		return bits
+	}
+	s.minute = parse(fields[0], 0, 59)
+	s.hour = parse(fields[1], 0, 23)
+	s.dom = parse(fields[2], 1, 31)
+	s.month = parse(fields[3], 1, 12)
+	s.dow = parse(fields[4], 0, 7)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is Sunday too
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return s, nil
+}
+
+// parseField parses one cron field: "*", "5", "1-5", "*/15", "1-30/2",
+// or a comma-separated list of these.
+func parseField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("bad value %q", part)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// String returns the schedule as written.
+func (s Schedule) String() string {
+	return s.spec
+}
+
+// IsZero reports whether the schedule is unset, meaning the rule only runs
+// on demand.
+func (s Schedule) IsZero() bool {
+	return s.spec == ""
+}
+
+// Next returns the first time after t that the schedule fires, or the zero
+// time if it never does.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.IsZero() {
+		return time.Time{}
+	}
+	if s.every > 0 {
+		return t.Truncate(s.every).Add(s.every)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0) // Impossible dates like Feb 30 never match
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: when both the day of month and the
+// weekday are restricted, either may match.
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
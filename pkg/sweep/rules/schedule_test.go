@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2025, 6, 4, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 3 * * *", time.Date(2025, 6, 5, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)},
+		{"30 10,14 * * *", time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 6, 5, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)}, // Friday or the 13th
+		{"@weekly", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 6, 4, 11, 0, 0, 0, time.UTC)},
+		{"@every 6h", time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, s.Next(from), tt.spec)
+	}
+}
+
+func TestScheduleNeverFires(t *testing.T) {
+	s, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+
+	assert.True(t, Schedule{}.IsZero())
+	assert.True(t, Schedule{}.Next(time.Now()).IsZero())
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 10s", "@sometimes"} {
+		_, err := ParseSchedule(spec)
+		assert.True(t, errors.Is(err, ErrInvalidSchedule), "spec %q: %v", spec, err)
+	}
+}
+
+func TestSchedulerDue(t *testing.T) {
+	daily, err := ParseSchedule("0 3 * * *")
+	require.NoError(t, err)
+	hourly, err := ParseSchedule("@hourly")
+	require.NoError(t, err)
+
+	s := NewScheduler([]Rule{{Name: "daily", Schedule: daily}, {Name: "manual"}, {Name: "hourly", Schedule: hourly}}, nil)
+	start := time.Date(2025, 6, 4, 2, 30, 0, 0, time.UTC)
+	s.plan(start)
+
+	assert.Equal(t, time.Date(2025, 6, 4, 3, 0, 0, 0, time.UTC), s.earliest())
+	assert.Empty(t, s.due(start))
+	assert.Equal(t, []int{0, 2}, s.due(start.Add(30*time.Minute)))
+	assert.True(t, s.next[1].IsZero(), "rules without a schedule never run")
+}
@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"context"
+	"time"
+)
+
+// maxWait bounds how long the scheduler sleeps between checks, so runs
+// missed while the machine was asleep start soon after it wakes.
+const maxWait = time.Minute
+
+// Scheduler runs rules on their schedules.
+type Scheduler struct {
+	rules []Rule
+	run   func(context.Context, Rule)
+	next  []time.Time // Next run of each rule; zero if never
+}
+
+// NewScheduler creates a scheduler that calls run for each rule when it is
+// due. Rules without a schedule are ignored.
+func NewScheduler(rules []Rule, run func(context.Context, Rule)) *Scheduler {
+	return &Scheduler{rules: rules, run: run}
+}
+
+// Run runs rules as they fall due until ctx is cancelled. Rules run one at a
+// time; a rule that falls due while another runs starts afterwards.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.plan(time.Now())
+	for {
+		next := s.earliest()
+		if next.IsZero() {
+			return // Nothing scheduled
+		}
+
+		timer := time.NewTimer(max(min(time.Until(next), maxWait), 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, i := range s.due(time.Now()) {
+			if ctx.Err() != nil {
+				return
+			}
+			s.run(ctx, s.rules[i])
+			s.next[i] = s.rules[i].Schedule.Next(time.Now())
+		}
+	}
+}
+
+// plan computes each rule's next run after now.
+func (s *Scheduler) plan(now time.Time) {
+	s.next = make([]time.Time, len(s.rules))
+	for i, r := range s.rules {
+		s.next[i] = r.Schedule.Next(now)
+	}
+}
+
+// earliest returns the soonest next run, or the zero time if none.
+func (s *Scheduler) earliest() time.Time {
+	var earliest time.Time
+	for _, t := range s.next {
+		if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	return earliest
+}
+
+// due returns the indexes of the rules whose next run is not after now.
+func (s *Scheduler) due(now time.Time) []int {
+	var due []int
+	for i, t := range s.next {
+		if !t.IsZero() && !t.After(now) {
+			due = append(due, i)
+		}
+	}
+	return due
+}
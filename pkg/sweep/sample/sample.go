@@ -0,0 +1,62 @@
+// Package sample picks files at random with a probability proportional to
+// their size. Reviewing a handful of such files is a quick way to find
+// unexpected kinds of files taking up space, without reading a full report.
+package sample
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Weighted returns n of items picked at random without replacement, each
+// with a probability proportional to its weight, in the order they appear
+// in items. Items with no weight are never picked, so fewer than n items
+// are returned when fewer have a weight.
+func Weighted[T any](items []T, n int, weight func(T) int64, rng *rand.Rand) []T {
+	if n <= 0 {
+		return nil
+	}
+
+	// Each item gets the key ln(u)/w for a uniform u in (0, 1], and the n
+	// largest keys are picked (Efraimidis and Spirakis, 2006).
+	type keyed struct {
+		index int
+		key   float64
+	}
+	keys := make([]keyed, 0, len(items))
+	for i, item := range items {
+		w := weight(item)
+		if w <= 0 {
+			continue
+		}
+		u := 1 - rng.Float64()
+		keys = append(keys, keyed{index: i, key: math.Log(u) / float64(w)})
+	}
+	if len(keys) > n {
+		slices.SortFunc(keys, func(a, b keyed) int {
+			return cmp.Compare(b.key, a.key)
+		})
+		keys = keys[:n]
+		slices.SortFunc(keys, func(a, b keyed) int {
+			return a.index - b.index
+		})
+	}
+
+	picked := make([]T, len(keys))
+	for i, k := range keys {
+		picked[i] = items[k.index]
+	}
+	return picked
+}
+
+// NewRand returns a random source for Weighted. A seed of 0 picks a
+// different sample each time; any other seed repeats the same sample of
+// the same files.
+func NewRand(seed uint64) *rand.Rand {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return rand.New(rand.NewPCG(seed, seed))
+}
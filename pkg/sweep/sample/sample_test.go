@@ -0,0 +1,66 @@
+package sample
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func size(n int64) int64 { return n }
+
+func TestWeighted_PicksInProportionToSize(t *testing.T) {
+	// One file holds 90% of the bytes; the rest are equal
+	items := []int64{900, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10}
+	rng := NewRand(1)
+
+	counts := make(map[int]int)
+	const rounds = 2000
+	for range rounds {
+		picked := Weighted(items, 1, size, rng)
+		require.Len(t, picked, 1)
+		if picked[0] == 900 {
+			counts[900]++
+		} else {
+			counts[10]++
+		}
+	}
+	assert.InDelta(t, 0.9, float64(counts[900])/rounds, 0.03)
+}
+
+func TestWeighted_WithoutReplacement(t *testing.T) {
+	items := []int64{5, 1, 0, 8, 3, 0, 2}
+
+	picked := Weighted(items, 3, size, NewRand(7))
+	require.Len(t, picked, 3)
+	assert.NotContains(t, picked, int64(0))
+
+	// In the order of items, with no file picked twice
+	pos := -1
+	for _, p := range picked {
+		i := indexOf(items, p)
+		assert.Greater(t, i, pos)
+		pos = i
+	}
+
+	// Asking for more than there are returns every file with a size
+	assert.Equal(t, []int64{5, 1, 8, 3, 2}, Weighted(items, 10, size, NewRand(7)))
+	assert.Empty(t, Weighted(items, 0, size, NewRand(7)))
+}
+
+func TestWeighted_SeedRepeatsSample(t *testing.T) {
+	items := make([]int64, 100)
+	for i := range items {
+		items[i] = int64(i + 1)
+	}
+	assert.Equal(t, Weighted(items, 10, size, NewRand(42)), Weighted(items, 10, size, NewRand(42)))
+}
+
+func indexOf(items []int64, v int64) int {
+	for i, item := range items {
+		if item == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// Boundary says which mount points under a root a walk enters: all but
+// those it skips, and, when it stays on one file system, only those it is
+// told to cross into. A nil Boundary enters them all.
+type Boundary struct {
+	skip  []string // Mount points never entered
+	cross []string // Mount points entered, with everything in them, anyway
+
+	oneFS  bool
+	device uint64 // The root's file system, when oneFS
+}
+
+// NewBoundary returns the boundary of a walk of root that doesn't enter the
+// skip mount points and, with oneFileSystem, doesn't enter other file
+// systems mounted under root except at the cross mount points. It returns
+// nil when the walk enters everything.
+func NewBoundary(root string, oneFileSystem bool, skip, cross []string) *Boundary {
+	b := &Boundary{skip: skip, cross: cross}
+	if oneFileSystem {
+		// Devices aren't known on every platform, nor for a root that
+		// can't be stat'ed, which has nothing to walk anyway
+		if info, err := os.Stat(root); err == nil {
+			b.device, b.oneFS = deviceOf(info)
+		}
+	}
+	if len(skip) == 0 && !b.oneFS {
+		return nil
+	}
+	return b
+}
+
+// Stops reports whether the walk stays out of the directory at path: a
+// mount point it skips, or, when it stays on one file system, one on
+// another file system than the root's.
+func (b *Boundary) Stops(path string, d fs.DirEntry) bool {
+	if b == nil {
+		return false
+	}
+	if slices.Contains(b.skip, path) {
+		return true
+	}
+	if !b.oneFS {
+		return false
+	}
+	info, err := d.Info()
+	if err != nil {
+		return false
+	}
+	if dev, ok := deviceOf(info); !ok || dev == b.device {
+		return false
+	}
+	for _, mount := range b.cross {
+		if within(path, mount) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// dirEntry returns the entry for the directory at path.
+func dirEntry(t *testing.T, path string) fs.DirEntry {
+	t.Helper()
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("failed to stat %s: %v", path, err)
+	}
+	return fs.FileInfoToDirEntry(info)
+}
+
+// TestBoundary verifies which directories a walk stays out of.
+func TestBoundary(t *testing.T) {
+	root := t.TempDir()
+	sub, skipped := filepath.Join(root, "sub"), filepath.Join(root, "skipped")
+	for _, dir := range []string{sub, skipped} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	if b := NewBoundary(root, false, nil, nil); b != nil {
+		t.Errorf("expected no boundary when entering everything, got %+v", b)
+	}
+	var none *Boundary
+	if none.Stops(sub, dirEntry(t, sub)) {
+		t.Error("expected a nil boundary to stop nothing")
+	}
+
+	b := NewBoundary(root, true, []string{skipped}, nil)
+	if b == nil {
+		t.Fatal("expected a boundary")
+	}
+	if !b.Stops(skipped, dirEntry(t, skipped)) {
+		t.Errorf("expected %s skipped", skipped)
+	}
+	if b.Stops(sub, dirEntry(t, sub)) {
+		t.Errorf("expected %s, on the root's file system, entered", sub)
+	}
+	if runtime.GOOS != "darwin" && runtime.GOOS != "linux" {
+		return
+	}
+
+	// As if root were on another file system than its directories
+	other := &Boundary{oneFS: true, device: b.device + 1, cross: []string{sub}}
+	if !other.Stops(skipped, dirEntry(t, skipped)) {
+		t.Errorf("expected %s, on another file system, stopped at", skipped)
+	}
+	nested := filepath.Join(sub, "nested")
+	if err := os.Mkdir(nested, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if other.Stops(sub, dirEntry(t, sub)) || other.Stops(nested, dirEntry(t, nested)) {
+		t.Errorf("expected %s and everything in it crossed into", sub)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package scanner
+
+import "io/fs"
+
+// deviceOf reports false: devices aren't known on this platform, so walks
+// can't tell where other file systems are mounted.
+func deviceOf(fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,17 @@
+//go:build darwin || linux
+
+package scanner
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// deviceOf returns the device of the file system info's file is on.
+func deviceOf(info fs.FileInfo) (uint64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true //nolint:unconvert // Dev is an int32 on darwin
+}
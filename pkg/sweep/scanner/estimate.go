@@ -0,0 +1,158 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+const (
+	// estimateInterval is how often the rate is sampled; shorter intervals
+	// make it jumpy.
+	estimateInterval = 500 * time.Millisecond
+
+	// estimateSmoothing weighs each sample against the rate so far.
+	estimateSmoothing = 0.3
+)
+
+// Estimate is how far along a scan is.
+type Estimate struct {
+	// Percent is the share of the expected directories and files
+	// examined, from 0 to 100.
+	Percent float64
+
+	// Remaining is the estimated time left, or 0 while it isn't known.
+	Remaining time.Duration
+}
+
+// String formats the estimate, e.g. "42%, about 1m20s left".
+func (e Estimate) String() string {
+	s := fmt.Sprintf("%.0f%%", e.Percent)
+	switch {
+	case e.Remaining >= time.Minute:
+		s += fmt.Sprintf(", about %s left", e.Remaining.Round(time.Second))
+	case e.Remaining >= 10*time.Second:
+		s += fmt.Sprintf(", about %ds left", int(e.Remaining.Round(5*time.Second).Seconds()))
+	case e.Remaining > 0:
+		s += ", a few seconds left"
+	}
+	return s
+}
+
+// Estimator turns a scan's progress reports into an estimate of how far
+// along it is: the directories and files examined against how many the
+// scan expects, and the time left at the rate it is examining them.
+//
+// The zero value is ready to use. An Estimator is not safe for concurrent
+// use.
+type Estimator struct {
+	last        time.Time
+	lastEntries int64
+	rate        float64 // Entries per second, smoothed
+	estimate    Estimate
+}
+
+// Update takes a progress report made at now and returns the estimate, and
+// whether there is one: a scan that doesn't know how many entries to
+// expect has none. The percentage never goes down, and stays below 100
+// until the walk is complete.
+func (e *Estimator) Update(p types.ScanProgress, now time.Time) (Estimate, bool) {
+	if p.WalkComplete {
+		e.estimate = Estimate{Percent: 100}
+		return e.estimate, true
+	}
+	if p.EntriesExpected <= 0 {
+		return Estimate{}, false
+	}
+
+	entries := p.DirsScanned + p.FilesScanned
+	percent := 100 * float64(entries) / float64(p.EntriesExpected)
+	e.estimate.Percent = max(e.estimate.Percent, min(percent, 99))
+
+	if e.last.IsZero() {
+		e.last, e.lastEntries = now, entries
+		return e.estimate, true
+	}
+	dt := now.Sub(e.last).Seconds()
+	if dt < estimateInterval.Seconds() {
+		return e.estimate, true
+	}
+	rate := float64(entries-e.lastEntries) / dt
+	if e.rate > 0 {
+		rate = estimateSmoothing*rate + (1-estimateSmoothing)*e.rate
+	}
+	e.last, e.lastEntries, e.rate = now, entries, rate
+
+	e.estimate.Remaining = 0
+	if left := p.EntriesExpected - entries; left > 0 && rate > 0 {
+		e.estimate.Remaining = time.Duration(float64(left) / rate * float64(time.Second))
+	}
+	return e.estimate, true
+}
+
+// entriesFile is the file in a cache directory holding how many entries
+// the last complete scan of each root examined.
+const entriesFile = "scan-entries.json"
+
+// entriesMu serializes this process's updates of the entries file.
+var entriesMu sync.Mutex
+
+// LastEntries returns how many directories and files the last scan of
+// root recorded in dir examined, or 0 if there is no record.
+func LastEntries(dir, root string) int64 {
+	counts, _ := readEntries(dir)
+	return counts[root]
+}
+
+// RecordEntries records in dir how many directories and files a complete
+// scan of root examined, for LastEntries.
+func RecordEntries(dir, root string, entries int64) error {
+	entriesMu.Lock()
+	defer entriesMu.Unlock()
+
+	counts, err := readEntries(dir)
+	if err != nil {
+		return err
+	}
+	if counts == nil {
+		counts = make(map[string]int64)
+	}
+	counts[root] = entries
+
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, entriesFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, entriesFile))
+}
+
+// readEntries reads the entries file in dir. A missing or corrupt file is
+// no record.
+func readEntries(dir string) (map[string]int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, entriesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var counts map[string]int64
+	if json.Unmarshal(data, &counts) != nil {
+		return nil, nil
+	}
+	return counts, nil
+}
@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestEstimator(t *testing.T) {
+	start := time.Now()
+	at := func(s float64) time.Time { return start.Add(time.Duration(s * float64(time.Second))) }
+
+	var e Estimator
+	if _, ok := e.Update(types.ScanProgress{DirsScanned: 100, FilesScanned: 900}, at(0)); ok {
+		t.Error("Update() has an estimate without an expected entry count")
+	}
+
+	// 1,000 entries a second of 10,000
+	est, _ := e.Update(types.ScanProgress{DirsScanned: 100, FilesScanned: 900, EntriesExpected: 10000}, at(0))
+	if est.Percent != 10 || est.Remaining != 0 {
+		t.Errorf("first report = %+v, want 10%% and no time left", est)
+	}
+	est, _ = e.Update(types.ScanProgress{DirsScanned: 200, FilesScanned: 1800, EntriesExpected: 10000}, at(1))
+	if est.Percent != 20 || est.Remaining != 8*time.Second {
+		t.Errorf("second report = %+v, want 20%% and 8s left", est)
+	}
+
+	// Reports closer together than the sampling interval keep the rate
+	if again, _ := e.Update(types.ScanProgress{DirsScanned: 200, FilesScanned: 1900, EntriesExpected: 10000}, at(1.1)); again.Remaining != est.Remaining {
+		t.Errorf("remaining changed between samples: %v, then %v", est.Remaining, again.Remaining)
+	}
+
+	// More entries than expected: the percentage holds below 100
+	est, _ = e.Update(types.ScanProgress{DirsScanned: 1000, FilesScanned: 11000, EntriesExpected: 10000}, at(2))
+	if est.Percent != 99 || est.Remaining != 0 {
+		t.Errorf("overrun = %+v, want 99%% and no time left", est)
+	}
+	if est, ok := e.Update(types.ScanProgress{DirsScanned: 1000, FilesScanned: 11000, WalkComplete: true}, at(2.5)); !ok || est.Percent != 100 || est.Remaining != 0 {
+		t.Errorf("complete = %+v, want 100%%", est)
+	}
+}
+
+func TestRecordEntries(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	if n := LastEntries(dir, "/home"); n != 0 {
+		t.Errorf("LastEntries() = %d without a record, want 0", n)
+	}
+	for root, n := range map[string]int64{"/home": 1000, "/srv": 20} {
+		if err := RecordEntries(dir, root, n); err != nil {
+			t.Fatalf("RecordEntries(%s) error = %v", root, err)
+		}
+	}
+	if err := RecordEntries(dir, "/home", 1200); err != nil {
+		t.Fatal(err)
+	}
+	if n := LastEntries(dir, "/home"); n != 1200 {
+		t.Errorf("LastEntries(/home) = %d, want the latest 1200", n)
+	}
+	if n := LastEntries(dir, "/srv"); n != 20 {
+		t.Errorf("LastEntries(/srv) = %d, want 20", n)
+	}
+}
+
+func TestEstimateString(t *testing.T) {
+	tests := []struct {
+		est  Estimate
+		want string
+	}{
+		{Estimate{Percent: 42}, "42%"},
+		{Estimate{Percent: 42, Remaining: 3 * time.Second}, "42%, a few seconds left"},
+		{Estimate{Percent: 42, Remaining: 37 * time.Second}, "42%, about 35s left"},
+		{Estimate{Percent: 42, Remaining: 80*time.Second + 300*time.Millisecond}, "42%, about 1m20s left"},
+	}
+	for _, tt := range tests {
+		if got := tt.est.String(); got != tt.want {
+			t.Errorf("String(%+v) = %q, want %q", tt.est, got, tt.want)
+		}
+	}
+}
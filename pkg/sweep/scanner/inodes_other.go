@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+
+package scanner
+
+// inodesInUse returns 0: the file system's inode count isn't available.
+func inodesInUse(string) int64 {
+	return 0
+}
@@ -0,0 +1,38 @@
+//go:build darwin || linux
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// inodesInUse returns the number of inodes in use on root's file system
+// when root is its mount point, so a scan of root examines about that many
+// directories and files; otherwise 0.
+func inodesInUse(root string) int64 {
+	if parent := filepath.Dir(root); parent != root {
+		rootInfo, err := os.Lstat(root)
+		if err != nil {
+			return 0
+		}
+		parentInfo, err := os.Lstat(parent)
+		if err != nil {
+			return 0
+		}
+		rootStat, ok1 := rootInfo.Sys().(*syscall.Stat_t)
+		parentStat, ok2 := parentInfo.Sys().(*syscall.Stat_t)
+		if !ok1 || !ok2 || rootStat.Dev == parentStat.Dev {
+			return 0
+		}
+	}
+
+	var fs unix.Statfs_t
+	if err := unix.Statfs(root, &fs); err != nil || fs.Files < fs.Ffree {
+		return 0
+	}
+	return int64(fs.Files - fs.Ffree)
+}
@@ -4,6 +4,7 @@ package scanner
 
 import (
 	"github.com/jamesainslie/sweep/pkg/sweep/config"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -20,6 +21,37 @@ type Options struct {
 	// Patterns are matched against the full path.
 	Exclude []string
 
+	// Owner, if set, limits results to files owned by one user. Directories
+	// the user cannot enter are skipped without being read.
+	Owner *owner.Filter
+
+	// SkipMounts are mount points not to enter. They are reported as
+	// skipped at a device boundary rather than excluded.
+	SkipMounts []string
+
+	// OneFileSystem keeps the scan on Root's file system, as du -x does:
+	// directories where another file system is mounted, such as network
+	// shares and external drives, are skipped at a device boundary.
+	OneFileSystem bool
+
+	// CrossMounts are mount points entered, with everything in them, even
+	// with OneFileSystem.
+	CrossMounts []string
+
+	// IncludeSnapshots enters directories that hold filesystem snapshots
+	// (see mounts.IsSnapshotDir), which are otherwise skipped, since each
+	// snapshot counts the same data again.
+	IncludeSnapshots bool
+
+	// RecordSkipped lists each skipped path and its reason in the result;
+	// otherwise only the count of each reason is kept.
+	RecordSkipped bool
+
+	// Symlinks says which symlinked directories to follow; empty follows
+	// none. Files in a followed directory are reported under the symlink's
+	// path.
+	Symlinks types.SymlinkPolicy
+
 	// DirWorkers is the number of concurrent workers for directory traversal.
 	// More workers help with directories containing many subdirectories.
 	DirWorkers int
@@ -32,10 +64,28 @@ type Options struct {
 	// It must be safe to call from multiple goroutines.
 	OnProgress func(types.ScanProgress)
 
+	// ExpectedEntries is how many directories and files the scan is
+	// expected to examine, such as the count from the last scan of Root
+	// (see LastEntries), for estimating progress. When Root is the root of
+	// its file system, the file system's count of inodes in use is used
+	// instead.
+	ExpectedEntries int64
+
 	// OnFile is called for each file that matches the MinSize threshold.
 	// It allows streaming results as files are found rather than waiting
 	// for the entire scan to complete. Must be safe for concurrent calls.
 	OnFile func(types.FileInfo)
+
+	// OnStat is called for every file examined, whatever its size, with the
+	// bytes it adds to disk usage, so callers can total directories. Hard
+	// links to a file already seen add nothing. Must be safe for concurrent
+	// calls.
+	OnStat func(path string, size int64)
+
+	// OnDir is called for every directory entered below the root, so
+	// callers can count directory entries. Must be safe for concurrent
+	// calls.
+	OnDir func(path string)
 }
 
 // DefaultOptions returns options with sensible defaults for most systems.
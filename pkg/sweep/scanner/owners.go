@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"runtime"
+	"sync"
+)
+
+// Ownership is the owner and group names of a file. Names fall back to the
+// numeric ID when it has no account, and are empty when the file could not
+// be stat-ed.
+type Ownership struct {
+	Owner string
+	Group string
+}
+
+// LookupOwners resolves the owner and group of each path, using up to
+// workers goroutines (the number of CPUs if workers < 1). Scans leave
+// ownership out of the stat hot path; callers resolve it afterwards for the
+// files they show, which are usually far fewer than the files scanned.
+// Each user and group is looked up once. The result is in the order of
+// paths; paths not reached before ctx is done are left empty.
+func LookupOwners(ctx context.Context, paths []string, workers int) []Ownership {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(paths))
+
+	owners := make([]Ownership, len(paths))
+	names := &ownerNames{}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				info, err := os.Lstat(paths[i])
+				if err != nil {
+					continue
+				}
+				owners[i].Owner, owners[i].Group = getOwnership(info, names)
+			}
+		}()
+	}
+
+feed:
+	for i := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case work <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	return owners
+}
+
+// ownerNames remembers user and group name lookups by ID, so resolving many
+// files costs one lookup per user and group. Safe for concurrent use.
+type ownerNames struct {
+	mu     sync.Mutex
+	users  map[string]string
+	groups map[string]string
+}
+
+// user returns the username for uid, or uid when it has no account.
+func (n *ownerNames) user(uid string) string {
+	return n.lookup(&n.users, uid, func(id string) (string, error) {
+		u, err := user.LookupId(id)
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	})
+}
+
+// group returns the group name for gid, or gid when it has no group.
+func (n *ownerNames) group(gid string) string {
+	return n.lookup(&n.groups, gid, func(id string) (string, error) {
+		g, err := user.LookupGroupId(id)
+		if err != nil {
+			return "", err
+		}
+		return g.Name, nil
+	})
+}
+
+func (n *ownerNames) lookup(cache *map[string]string, id string, resolve func(string) (string, error)) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if name, ok := (*cache)[id]; ok {
+		return name
+	}
+	name, err := resolve(id)
+	if err != nil {
+		name = id
+	}
+	if *cache == nil {
+		*cache = make(map[string]string)
+	}
+	(*cache)[id] = name
+	return name
+}
@@ -11,6 +11,9 @@ import (
 	"time"
 
 	"github.com/charlievieth/fastwalk"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/mounts"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -24,6 +27,10 @@ type Scanner struct {
 	largeFiles   atomic.Int64
 	bytesScanned atomic.Int64
 
+	// usage counts storage shared by hard links and clones once, and
+	// cloud placeholders at their local size.
+	usage sharing.Counter
+
 	// currentPath is the path currently being scanned (for progress).
 	currentPath atomic.Value
 
@@ -31,6 +38,12 @@ type Scanner struct {
 	errors   []types.ScanError
 	errorsMu sync.Mutex
 
+	// skipCounts counts skipped paths by reason; skipped lists them when
+	// Options.RecordSkipped is set.
+	skipCounts map[types.SkipReason]*atomic.Int64
+	skipped    []types.SkippedPath
+	skippedMu  sync.Mutex
+
 	// results collects files matching the criteria.
 	results   []types.FileInfo
 	resultsMu sync.Mutex
@@ -41,6 +54,16 @@ type Scanner struct {
 	// root is the resolved absolute path being scanned.
 	root string
 
+	// links decides which symlinked directories to follow.
+	links *SymlinkFollower
+
+	// boundary decides which mount points to enter.
+	boundary *Boundary
+
+	// expected is how many directories and files the scan expects to
+	// examine, or 0 if unknown.
+	expected int64
+
 	// walkComplete indicates directory traversal is finished.
 	walkComplete atomic.Bool
 }
@@ -53,9 +76,13 @@ func New(opts Options) *Scanner {
 	_ = opts.Validate()
 
 	s := &Scanner{
-		opts:    opts,
-		errors:  make([]types.ScanError, 0),
-		results: make([]types.FileInfo, 0),
+		opts:       opts,
+		errors:     make([]types.ScanError, 0),
+		results:    make([]types.FileInfo, 0),
+		skipCounts: make(map[types.SkipReason]*atomic.Int64, len(types.SkipReasons)),
+	}
+	for _, reason := range types.SkipReasons {
+		s.skipCounts[reason] = new(atomic.Int64)
 	}
 	s.currentPath.Store("")
 	return s
@@ -72,6 +99,12 @@ func (s *Scanner) Scan(ctx context.Context) (*types.ScanResult, error) {
 		return nil, err
 	}
 	s.root = root
+	s.links = NewSymlinkFollower(s.opts.Symlinks, root)
+	s.boundary = NewBoundary(root, s.opts.OneFileSystem, s.opts.SkipMounts, s.opts.CrossMounts)
+	s.expected = s.opts.ExpectedEntries
+	if n := inodesInUse(root); n > 0 {
+		s.expected = n
+	}
 
 	// Report initial progress immediately.
 	s.currentPath.Store(root)
@@ -92,15 +125,22 @@ func (s *Scanner) Scan(ctx context.Context) (*types.ScanResult, error) {
 		DirsScanned:  s.dirsScanned.Load(),
 		FilesScanned: s.filesScanned.Load(),
 		TotalSize:    s.bytesScanned.Load(),
+		ActualSize:   s.usage.Actual(),
 		Elapsed:      time.Since(startTime),
 		Errors:       s.errors,
+		Skipped:      s.skipCountsByReason(),
+		SkippedPaths: s.skipped,
 	}, nil
 }
 
 // executeWalk runs fastwalk on the root directory.
 func (s *Scanner) executeWalk(ctx context.Context) error {
 	conf := fastwalk.Config{
-		Follow: false, // Don't follow symlinks.
+		Follow: false, // Symlinks are followed by the walk callback, by policy.
+
+		// Each walker stats the files it lists, so FileWorkers bounds them,
+		// as network file systems need; fastwalk's default suits local disks.
+		NumWorkers: min(s.opts.FileWorkers, fastwalk.DefaultNumWorkers()),
 	}
 
 	walkCtx, cancel := context.WithCancel(ctx)
@@ -150,27 +190,64 @@ func (s *Scanner) walkCallback(done <-chan struct{}) fs.WalkDirFunc {
 
 		// Handle errors gracefully - log and continue.
 		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				s.skip(path, types.SkipPermission)
+			}
 			s.addError(path, err)
 			return nil
 		}
 
 		// Check exclusions.
 		if s.isExcluded(path) {
+			s.skip(path, types.SkipExcluded)
 			if d.IsDir() {
 				return fastwalk.SkipDir
 			}
 			return nil
 		}
 
+		if d.IsDir() && path != s.root {
+			// Don't enter mount points that are to be skipped.
+			if s.boundary.Stops(path, d) {
+				s.skip(path, types.SkipDeviceBoundary)
+				return fastwalk.SkipDir
+			}
+
+			// Don't count snapshots of data counted already.
+			if !s.opts.IncludeSnapshots && mounts.IsSnapshotDir(path) {
+				s.skip(path, types.SkipSnapshot)
+				return fastwalk.SkipDir
+			}
+
+			// Skip other users' private directories when filtering by owner.
+			if s.opts.Owner != nil {
+				if info, err := d.Info(); err == nil && !s.opts.Owner.CanEnter(info) {
+					s.skip(path, types.SkipOwner)
+					return fastwalk.SkipDir
+				}
+			}
+		}
+
 		// Handle directories.
 		if d.IsDir() {
 			s.handleDirectory(path)
 			return nil
 		}
 
-		// Process regular files.
-		if d.Type().IsRegular() {
+		// Process regular files, and symlinked directories the policy
+		// follows.
+		switch {
+		case d.Type().IsRegular():
 			s.processFile(path, d)
+		case d.Type()&fs.ModeSymlink != 0:
+			if follow, reason := s.links.Follow(path); !follow {
+				s.skip(path, reason)
+				return nil
+			}
+			s.handleDirectory(path)
+			return fastwalk.ErrTraverseLink
+		default:
+			s.skip(path, types.SkipSpecial)
 		}
 
 		return nil
@@ -182,6 +259,9 @@ func (s *Scanner) handleDirectory(path string) {
 	s.dirsScanned.Add(1)
 	s.currentPath.Store(path)
 	s.reportProgress()
+	if s.opts.OnDir != nil && path != s.root {
+		s.opts.OnDir(path)
+	}
 }
 
 // processFile handles a regular file entry.
@@ -189,18 +269,42 @@ func (s *Scanner) processFile(path string, d fs.DirEntry) {
 	// Get file info (this triggers a stat call).
 	info, err := d.Info()
 	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			s.skip(path, types.SkipPermission)
+		}
 		s.addError(path, err)
 		return
 	}
 
 	size := info.Size()
 
+	// Only large files are checked for clones, which costs a system call.
+	var shared *sharing.Info
+	if size >= s.opts.MinSize {
+		shared = sharing.Stat(path, info)
+	} else {
+		shared = sharing.FromFileInfo(info)
+	}
+	// Placeholders count at what they store locally.
+	online := cloud.FromFileInfo(path, info)
+	actual := s.usage.Add(shared, cloud.Local(online, size))
+
 	// Update counters.
 	s.filesScanned.Add(1)
 	s.bytesScanned.Add(size)
+	if s.opts.OnStat != nil {
+		s.opts.OnStat(path, actual)
+	}
 
 	// Filter by minimum size.
 	if size < s.opts.MinSize {
+		s.skip(path, types.SkipBelowMinSize)
+		return
+	}
+
+	// Filter by owner.
+	if s.opts.Owner != nil && !s.opts.Owner.Owns(info) {
+		s.skip(path, types.SkipOwner)
 		return
 	}
 
@@ -211,8 +315,9 @@ func (s *Scanner) processFile(path string, d fs.DirEntry) {
 		ModTime:    info.ModTime(),
 		Mode:       info.Mode(),
 		CreateTime: getCreateTime(info),
+		Sharing:    shared,
+		Cloud:      online,
 	}
-	fi.Owner, fi.Group = getOwnership(info)
 
 	// Increment large files counter.
 	s.largeFiles.Add(1)
@@ -238,6 +343,32 @@ func (s *Scanner) addError(path string, err error) {
 	s.errorsMu.Unlock()
 }
 
+// skip counts a skipped path, and records it if asked to.
+func (s *Scanner) skip(path string, reason types.SkipReason) {
+	s.skipCounts[reason].Add(1)
+	if s.opts.RecordSkipped {
+		s.skippedMu.Lock()
+		s.skipped = append(s.skipped, types.SkippedPath{Path: path, Reason: reason})
+		s.skippedMu.Unlock()
+	}
+}
+
+// skipCountsByReason returns the reasons paths were skipped for, with
+// their counts, or nil if nothing was skipped.
+func (s *Scanner) skipCountsByReason() map[types.SkipReason]int64 {
+	var counts map[types.SkipReason]int64
+	for reason, n := range s.skipCounts {
+		if n.Load() == 0 {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[types.SkipReason]int64)
+		}
+		counts[reason] = n.Load()
+	}
+	return counts
+}
+
 // reportProgress calls the progress callback if configured.
 // Throttles calls to avoid excessive overhead.
 func (s *Scanner) reportProgress() {
@@ -279,6 +410,8 @@ func (s *Scanner) sendProgress() {
 		CurrentPath:  currentPath,
 		BytesScanned: s.bytesScanned.Load(),
 		WalkComplete: s.walkComplete.Load(),
+
+		EntriesExpected: s.expected,
 	})
 }
 
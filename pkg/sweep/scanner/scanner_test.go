@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
 	"github.com/jamesainslie/sweep/pkg/sweep/types"
 )
 
@@ -406,14 +407,50 @@ func TestScanFileInfo(t *testing.T) {
 			t.Error("Mode should be set")
 		}
 
-		// Verify Owner is set (should be current user or UID).
-		if f.Owner == "" || f.Owner == "unknown" {
-			t.Errorf("Owner should be set: %q", f.Owner)
+		// Verify ownership is left for LookupOwners.
+		if f.Owner != "" || f.Group != "" {
+			t.Errorf("ownership should not be resolved during the scan: %q %q", f.Owner, f.Group)
 		}
+	}
+}
+
+// TestLookupOwners verifies ownership is resolved after a scan, in the
+// order of the paths given.
+func TestLookupOwners(t *testing.T) {
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	result, err := New(Options{Root: root, MinSize: 1 * int64(types.MiB)}).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	paths := []string{filepath.Join(root, "missing")}
+	for _, f := range result.Files {
+		paths = append(paths, f.Path)
+	}
 
-		// Verify Group is set.
-		if f.Group == "" || f.Group == "unknown" {
-			t.Errorf("Group should be set: %q", f.Group)
+	owners := LookupOwners(context.Background(), paths, 2)
+	if len(owners) != len(paths) {
+		t.Fatalf("got %d owners for %d paths", len(owners), len(paths))
+	}
+	if owners[0] != (Ownership{}) {
+		t.Errorf("a missing file should have no owner, got %+v", owners[0])
+	}
+	for i, o := range owners[1:] {
+		// Should be the current user or UID.
+		if o.Owner == "" || o.Owner == "unknown" {
+			t.Errorf("%s: Owner should be set: %q", paths[i+1], o.Owner)
+		}
+		if o.Group == "" || o.Group == "unknown" {
+			t.Errorf("%s: Group should be set: %q", paths[i+1], o.Group)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, o := range LookupOwners(ctx, paths, 2) {
+		if o != (Ownership{}) {
+			t.Errorf("a cancelled lookup should resolve nothing, got %+v", o)
 		}
 	}
 }
@@ -558,6 +595,9 @@ func TestScanPermissionErrors(t *testing.T) {
 	if len(result.Errors) == 0 {
 		t.Error("expected permission error to be collected")
 	}
+	if result.Skipped[types.SkipPermission] != 1 {
+		t.Errorf("expected the unreadable dir to be skipped, got %v", result.Skipped)
+	}
 
 	// Other files should still be found.
 	if result.FilesScanned < 4 {
@@ -565,6 +605,220 @@ func TestScanPermissionErrors(t *testing.T) {
 	}
 }
 
+// TestScanSkipReasons verifies skipped paths are counted and, when asked,
+// recorded with their reasons.
+func TestScanSkipReasons(t *testing.T) {
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	if err := os.Symlink(filepath.Join(root, "large.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	mount := filepath.Join(root, "subdir", "nested")
+
+	opts := Options{
+		Root:          root,
+		MinSize:       500 * int64(types.KiB),
+		Exclude:       []string{"excluded"},
+		SkipMounts:    []string{mount},
+		RecordSkipped: true,
+	}
+	result, err := New(opts).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	want := map[types.SkipReason]int64{
+		types.SkipBelowMinSize:   2, // small.txt, medium.txt
+		types.SkipExcluded:       1, // The directory, not its file
+		types.SkipSymlink:        1,
+		types.SkipDeviceBoundary: 1,
+	}
+	if len(result.Skipped) != len(want) {
+		t.Errorf("expected skips %v, got %v", want, result.Skipped)
+	}
+	for reason, n := range want {
+		if result.Skipped[reason] != n {
+			t.Errorf("expected %d skipped as %s, got %d", n, reason, result.Skipped[reason])
+		}
+	}
+
+	reasons := make(map[string]types.SkipReason)
+	for _, p := range result.SkippedPaths {
+		reasons[p.Path] = p.Reason
+	}
+	if len(result.SkippedPaths) != 5 {
+		t.Errorf("expected 5 skipped paths, got %v", result.SkippedPaths)
+	}
+	if reasons[mount] != types.SkipDeviceBoundary {
+		t.Errorf("expected %s skipped at a device boundary, got %q", mount, reasons[mount])
+	}
+	if reasons[filepath.Join(root, "link.txt")] != types.SkipSymlink {
+		t.Errorf("expected link.txt skipped as a symlink, got %q", reasons[filepath.Join(root, "link.txt")])
+	}
+
+	// Only counts are kept unless asked
+	opts.RecordSkipped = false
+	result, err = New(opts).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if result.SkippedPaths != nil || result.Skipped[types.SkipBelowMinSize] != 2 {
+		t.Errorf("expected counts without paths, got %v and %v", result.Skipped, result.SkippedPaths)
+	}
+}
+
+// TestScanOneFileSystem verifies a scan kept on one file system enters the
+// directories on it.
+func TestScanOneFileSystem(t *testing.T) {
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	opts := Options{Root: root, MinSize: 500 * int64(types.KiB), OneFileSystem: true, RecordSkipped: true}
+	result, err := New(opts).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if result.Skipped[types.SkipDeviceBoundary] != 0 || len(result.Files) != 3 {
+		t.Errorf("expected every file on the one file system, got %d files and skips %v", len(result.Files), result.Skipped)
+	}
+
+}
+
+// TestScanSnapshots verifies snapshot directories are skipped unless
+// included.
+func TestScanSnapshots(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"data.bin", filepath.Join(".zfs", "snapshot", "daily", "data.bin")} {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := createFileOfSize(path, 1*int64(types.MiB)); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	opts := Options{Root: root, RecordSkipped: true}
+	result, err := New(opts).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Skipped[types.SkipSnapshot] != 1 {
+		t.Errorf("expected one file and the snapshot dir skipped, got %d files and skips %v", len(result.Files), result.Skipped)
+	}
+	snapshots := filepath.Join(root, ".zfs", "snapshot")
+	if len(result.SkippedPaths) != 1 || result.SkippedPaths[0].Path != snapshots {
+		t.Errorf("expected %s skipped, got %v", snapshots, result.SkippedPaths)
+	}
+
+	opts.IncludeSnapshots = true
+	result, err = New(opts).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("expected both files with snapshots included, got %d", len(result.Files))
+	}
+}
+
+// TestScanOwner verifies owner filtering and skipping of private directories.
+func TestScanOwner(t *testing.T) {
+	// Changing file ownership requires root.
+	if os.Getuid() != 0 {
+		t.Skip("skipping ownership test as non-root")
+	}
+
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	const uid, otherUID = 4242, 4243
+	for _, rel := range []string{"large.txt", filepath.Join("excluded", "ignored.txt")} {
+		if err := os.Chown(filepath.Join(root, rel), uid, uid); err != nil {
+			t.Fatalf("chown failed: %v", err)
+		}
+	}
+
+	// The user cannot enter another user's private directory, so it is
+	// skipped even though it contains a file the user owns.
+	private := filepath.Join(root, "private")
+	if err := os.Mkdir(private, 0o700); err != nil {
+		t.Fatalf("failed to create private dir: %v", err)
+	}
+	hidden := filepath.Join(private, "hidden.bin")
+	if err := createFileOfSize(hidden, 1*int64(types.MiB)); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.Chown(hidden, uid, uid); err != nil {
+		t.Fatalf("chown failed: %v", err)
+	}
+	if err := os.Chown(private, otherUID, otherUID); err != nil {
+		t.Fatalf("chown failed: %v", err)
+	}
+
+	scanner := New(Options{
+		Root:    root,
+		MinSize: 500 * int64(types.KiB),
+		Owner:   owner.ForUID(uid),
+	})
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.Files) != 2 {
+		t.Errorf("expected 2 files owned by uid %d, got %d", uid, len(result.Files))
+	}
+	for _, f := range result.Files {
+		if f.Path == hidden {
+			t.Errorf("expected %s in a private directory to be skipped", hidden)
+		}
+		if filepath.Base(f.Path) == "big.txt" {
+			t.Errorf("expected %s owned by another user to be filtered", f.Path)
+		}
+	}
+}
+
+// TestScanHardLinks verifies hard links count once towards actual size.
+func TestScanHardLinks(t *testing.T) {
+	root := t.TempDir()
+	orig := filepath.Join(root, "a.iso")
+	if err := os.WriteFile(orig, make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(orig, filepath.Join(root, "b.iso")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "c.iso"), make([]byte, 500), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var statted atomic.Int64
+	result, err := New(Options{
+		Root:   root,
+		OnStat: func(_ string, size int64) { statted.Add(size) },
+	}).Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if result.TotalSize != 2500 || result.ActualSize != 1500 {
+		t.Errorf("TotalSize = %d, ActualSize = %d; want 2500, 1500", result.TotalSize, result.ActualSize)
+	}
+	if got := statted.Load(); got != 1500 {
+		t.Errorf("OnStat sizes add up to %d, want 1500", got)
+	}
+	if len(result.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(result.Files))
+	}
+	for _, f := range result.Files {
+		linked := filepath.Base(f.Path) != "c.iso"
+		if f.Sharing.HardLinked() != linked {
+			t.Errorf("%s: Sharing = %+v, want hard linked %v", f.Path, f.Sharing, linked)
+		}
+	}
+}
+
 // TestScanEmptyDirectory verifies scanning an empty directory.
 func TestScanEmptyDirectory(t *testing.T) {
 	root, err := os.MkdirTemp("", "scanner-empty-*")
@@ -707,3 +961,63 @@ func BenchmarkScanParallel(b *testing.B) {
 		}
 	})
 }
+
+// TestScanSymlinkPolicy verifies which symlinked directories each policy
+// follows, and that loops are cut.
+func TestScanSymlinkPolicy(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	for _, f := range []string{filepath.Join(root, "data", "big.bin"), filepath.Join(outside, "ext.bin")} {
+		if err := os.MkdirAll(filepath.Dir(f), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, make([]byte, 1000), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"in":        filepath.Join(root, "data"),
+		"again":     filepath.Join(root, "data"),
+		"out":       outside,
+		"data/loop": root,
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		policy types.SymlinkPolicy
+		files  int64
+		want   map[types.SkipReason]int64
+	}{
+		{types.SymlinksSkip, 1, map[types.SkipReason]int64{types.SkipSymlink: 4}},
+		// One of in and again is followed; the other, data/loop, and the
+		// loop seen again through the followed link are cut
+		{types.SymlinksWithinRoot, 2, map[types.SkipReason]int64{types.SkipSymlinkLoop: 3, types.SkipOutsideRoot: 1}},
+		{types.SymlinksFollow, 3, map[types.SkipReason]int64{types.SkipSymlinkLoop: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			result, err := New(Options{Root: root, Symlinks: tt.policy}).Scan(context.Background())
+			if err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if result.FilesScanned != tt.files {
+				t.Errorf("expected %d files scanned, got %d", tt.files, result.FilesScanned)
+			}
+			if len(result.Skipped) != len(tt.want) {
+				t.Errorf("expected skips %v, got %v", tt.want, result.Skipped)
+			}
+			for reason, n := range tt.want {
+				if result.Skipped[reason] != n {
+					t.Errorf("expected %d skipped as %s, got %d", n, reason, result.Skipped[reason])
+				}
+			}
+		})
+	}
+}
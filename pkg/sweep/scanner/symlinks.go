@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// SymlinkFollower decides which symlinks to directories a walk of one root
+// follows, under a SymlinkPolicy. It cuts loops: a symlink is not followed
+// to a directory it is already inside, nor into a tree the walk has
+// already followed another symlink into, so each followed tree is walked
+// once however many symlinks lead to it.
+//
+// Safe for concurrent use.
+type SymlinkFollower struct {
+	policy types.SymlinkPolicy
+	root   string // With symlinks resolved
+
+	mu       sync.Mutex
+	followed map[string]bool // Targets entered, with symlinks resolved
+}
+
+// NewSymlinkFollower returns a follower for a walk of root.
+func NewSymlinkFollower(policy types.SymlinkPolicy, root string) *SymlinkFollower {
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	return &SymlinkFollower{policy: policy, root: root, followed: make(map[string]bool)}
+}
+
+// Follow reports whether the walk should enter the directory the symlink
+// at path points to, and if not, why. A nil follower follows nothing.
+func (f *SymlinkFollower) Follow(path string) (bool, types.SkipReason) {
+	if f == nil || f.policy == "" || f.policy == types.SymlinksSkip {
+		return false, types.SkipSymlink
+	}
+
+	// Dangling symlinks, symlinks to files, and chains of symlinks that
+	// loop on themselves are left alone.
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false, types.SkipSymlink
+	}
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		return false, types.SkipSymlink
+	}
+	if f.policy == types.SymlinksWithinRoot && !within(target, f.root) {
+		return false, types.SkipOutsideRoot
+	}
+
+	// A symlink to a directory it is in would be walked forever.
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return false, types.SkipSymlink
+	}
+	if within(dir, target) {
+		return false, types.SkipSymlinkLoop
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for followed := range f.followed {
+		if within(target, followed) {
+			return false, types.SkipSymlinkLoop
+		}
+	}
+	f.followed[target] = true
+	return true, ""
+}
+
+// within reports whether path is dir or beneath it.
+func within(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, dir)
+}
@@ -15,6 +15,6 @@ func getCreateTime(info os.FileInfo) time.Time {
 
 // getOwnership returns the owner and group names for a file.
 // On unsupported platforms, returns "unknown" for both.
-func getOwnership(info os.FileInfo) (owner, group string) {
+func getOwnership(info os.FileInfo, _ *ownerNames) (owner, group string) {
 	return "unknown", "unknown"
 }
@@ -0,0 +1,86 @@
+package score
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	// duplicateMinSize is the smallest file checked for duplicates; smaller
+	// files rarely change where cleanup time is best spent.
+	duplicateMinSize = 1 << 20
+
+	// sampleSize is the number of bytes hashed from each end of a file.
+	sampleSize = 64 << 10
+)
+
+// candidate is a file that may have identical copies.
+type candidate struct {
+	path  string
+	size  int64
+	entry *Entry
+}
+
+// markDuplicates finds files with identical content and adds all copies but
+// the first (by path) to their directory's duplicate bytes. Files are first
+// grouped by size, then compared by a hash of their first and last 64 KiB,
+// which keeps the check fast on large trees at the cost of rare false matches.
+func markDuplicates(ctx context.Context, candidates []candidate) error {
+	bySize := make(map[int64][]candidate)
+	for _, c := range candidates {
+		bySize[c.size] = append(bySize[c.size], c)
+	}
+
+	for _, group := range bySize {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].path < group[j].path })
+
+		seen := make(map[[sha256.Size]byte]bool)
+		for _, c := range group {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			sum, err := sampleHash(c.path, c.size)
+			if err != nil {
+				continue // Unreadable files are not counted as duplicates
+			}
+			if seen[sum] {
+				c.entry.DuplicateBytes += c.size
+				continue
+			}
+			seen[sum] = true
+		}
+	}
+	return nil
+}
+
+// sampleHash hashes the first and last sampleSize bytes of a file.
+func sampleHash(path string, size int64) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, f, min(size, sampleSize)); err != nil {
+		return sum, err
+	}
+	if size > sampleSize {
+		tail := min(size-sampleSize, sampleSize)
+		if _, err := f.Seek(-tail, io.SeekEnd); err != nil {
+			return sum, err
+		}
+		if _, err := io.CopyN(h, f, tail); err != nil {
+			return sum, err
+		}
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
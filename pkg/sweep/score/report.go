@@ -0,0 +1,54 @@
+package score
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Write renders entries in the given format.
+func Write(w io.Writer, format string, entries []Entry) error {
+	return output.WriteReport(w, format, entries, output.ReportWriters[[]Entry]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders entries as a table, highest score first.
+func WriteText(w io.Writer, entries []Entry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(w, "No directories to score.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCORE\tSEVERITY\tSIZE\tSTALE\tDUPES\tDIRECTORY")
+	for _, e := range entries {
+		dir := e.Path
+		if e.Rebuildable != "" {
+			dir += " (rebuildable: " + e.Rebuildable + ")"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", e.Score, e.Severity, types.FormatSize(e.Size),
+			percent(e.StaleRatio()), percent(e.DuplicateRatio()), dir)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON renders entries as a JSON array.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// percent formats a ratio as a whole percentage.
+func percent(r float64) string {
+	return fmt.Sprintf("%.0f%%", r*100)
+}
@@ -0,0 +1,284 @@
+// Package score ranks directories by how much a cleanup is likely to pay
+// off, combining size, staleness, rebuildability, and duplication into a
+// single cleanup score.
+package score
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+)
+
+// Defaults for Options.
+const (
+	DefaultDepth      = 1
+	DefaultStaleAfter = 180 * 24 * time.Hour
+)
+
+// Weights of each factor in the score. They sum to 1.
+const (
+	weightSize      = 0.40
+	weightStale     = 0.25
+	weightRebuild   = 0.20
+	weightDuplicate = 0.15
+)
+
+// Severity levels, from the score.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// rebuildableDirs are directory names whose contents can be regenerated,
+// with the way to regenerate them.
+var rebuildableDirs = map[string]string{
+	"node_modules":     "npm install",
+	"bower_components": "bower install",
+	".venv":            "Python virtualenv",
+	"venv":             "Python virtualenv",
+	"__pycache__":      "Python bytecode",
+	".tox":             "tox environments",
+	".gradle":          "Gradle cache",
+	".next":            "Next.js build",
+	".nuxt":            "Nuxt build",
+	"target":           "Cargo/Maven build",
+	"DerivedData":      "Xcode build",
+	".terraform":       "terraform init",
+	".cache":           "cache",
+}
+
+// Options configures Compute.
+type Options struct {
+	Depth      int           // Depth below the root of the directories to rank; 0 uses DefaultDepth
+	StaleAfter time.Duration // Files unmodified for longer count as stale; 0 uses DefaultStaleAfter
+	Exclude    []string      // Glob patterns or path prefixes to skip
+	Duplicates bool          // Detect duplicate files by sampled hashing
+	Now        time.Time     // Reference time for staleness; zero uses time.Now
+}
+
+// Entry is the score of one directory.
+type Entry struct {
+	Path           string `json:"path"`
+	Size           int64  `json:"size"`
+	Files          int64  `json:"files"`
+	StaleBytes     int64  `json:"stale_bytes"`
+	DuplicateBytes int64  `json:"duplicate_bytes"`
+	Rebuildable    string `json:"rebuildable,omitempty"` // How the contents can be regenerated
+	Score          int    `json:"score"`                 // 0-100
+	Severity       string `json:"severity"`
+}
+
+// StaleRatio returns the fraction of bytes that are stale.
+func (e Entry) StaleRatio() float64 {
+	return ratio(e.StaleBytes, e.Size)
+}
+
+// DuplicateRatio returns the fraction of bytes duplicated elsewhere.
+func (e Entry) DuplicateRatio() float64 {
+	return ratio(e.DuplicateBytes, e.Size)
+}
+
+func ratio(part, whole int64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
+
+// Compute walks root and returns its directories ranked by cleanup score,
+// highest first. Directories at opts.Depth below root are ranked, except that
+// rebuildable directories such as node_modules are always ranked on their
+// own, wherever they are.
+func Compute(ctx context.Context, root string, opts Options) ([]Entry, error) {
+	if opts.Depth <= 0 {
+		opts.Depth = DefaultDepth
+	}
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = DefaultStaleAfter
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	staleBefore := opts.Now.Add(-opts.StaleAfter)
+
+	root = filepath.Clean(root)
+	if info, err := os.Stat(root); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	entries := make(map[string]*Entry)
+	unitOf := make(map[string]string) // Directory -> directory it is ranked under
+	var candidates []candidate
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir // Unreadable directory; skip it
+			}
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if path != root && excluded(path, opts.Exclude) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			unit := dirUnit(root, path, unitOf, opts.Depth)
+			unitOf[path] = unit
+			if unit == path {
+				entries[path] = &Entry{Path: path, Rebuildable: rebuildableDirs[d.Name()]}
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		e := entries[unitOf[filepath.Dir(path)]]
+		// Cloud placeholders count at what they store locally, and aren't
+		// hashed for duplicates, which would download them
+		online := cloud.FromFileInfo(path, info)
+		size := cloud.Local(online, info.Size())
+		e.Size += size
+		e.Files++
+		if info.ModTime().Before(staleBefore) {
+			e.StaleBytes += size
+		}
+		if opts.Duplicates && online == nil && size >= duplicateMinSize {
+			candidates = append(candidates, candidate{path: path, size: size, entry: e})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Duplicates {
+		if err := markDuplicates(ctx, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	return rank(entries), nil
+}
+
+// dirUnit returns the directory that dir is ranked under.
+func dirUnit(root, dir string, unitOf map[string]string, depth int) string {
+	if dir == root {
+		return dir
+	}
+	parentUnit := unitOf[filepath.Dir(dir)]
+	if _, ok := rebuildableDirs[filepath.Base(parentUnit)]; ok && parentUnit != root {
+		return parentUnit // Inside a rebuildable directory
+	}
+	if _, ok := rebuildableDirs[filepath.Base(dir)]; ok {
+		return dir
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || strings.Count(rel, string(filepath.Separator))+1 > depth {
+		return parentUnit
+	}
+	return dir
+}
+
+// rank scores entries and sorts them, highest score first.
+func rank(entries map[string]*Entry) []Entry {
+	var largest int64
+	for _, e := range entries {
+		largest = max(largest, e.Size)
+	}
+
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Size == 0 {
+			continue
+		}
+		rebuild := 0.0
+		if e.Rebuildable != "" {
+			rebuild = 1
+		}
+		s := weightSize*sizeFactor(e.Size, largest) +
+			weightStale*e.StaleRatio() +
+			weightRebuild*rebuild +
+			weightDuplicate*e.DuplicateRatio()
+		e.Score = int(math.Round(s * 100))
+		e.Severity = severity(e.Score)
+		out = append(out, *e)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Size != out[j].Size {
+			return out[i].Size > out[j].Size
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// sizeFactor scores size on a log scale relative to the largest directory,
+// so a directory a tenth the size of the largest still scores well.
+func sizeFactor(size, largest int64) float64 {
+	if largest <= 0 {
+		return 0
+	}
+	const unit = 1 << 20 // Measure in MiB so tiny directories score near zero
+	return math.Log1p(float64(size)/unit) / math.Log1p(float64(largest)/unit)
+}
+
+// severity maps a score to a severity level.
+func severity(score int) string {
+	switch {
+	case score >= 60:
+		return SeverityHigh
+	case score >= 35:
+		return SeverityMedium
+	default:
+		return SeverityLow
+	}
+}
+
+// excluded reports whether path matches an exclusion pattern, either as a
+// path prefix or as a glob against the base name or full path.
+func excluded(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		if path == p || strings.HasPrefix(path, p+string(filepath.Separator)) {
+			return true
+		}
+		if ok, err := filepath.Match(p, filepath.Base(path)); err == nil && ok {
+			return true
+		}
+		if ok, err := filepath.Match(p, path); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package score
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func byPath(entries []Entry) map[string]Entry {
+	m := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		m[e.Path] = e
+	}
+	return m
+}
+
+func TestCompute(t *testing.T) {
+	root := t.TempDir()
+	fresh := testNow.Add(-24 * time.Hour)
+	old := testNow.Add(-365 * 24 * time.Hour)
+
+	writeFile(t, filepath.Join(root, "docs", "a.txt"), make([]byte, 1000), fresh)
+	writeFile(t, filepath.Join(root, "docs", "deep", "b.txt"), make([]byte, 3000), old)
+	writeFile(t, filepath.Join(root, "app", "main.go"), make([]byte, 100), fresh)
+	writeFile(t, filepath.Join(root, "app", "web", "node_modules", "x", "index.js"), make([]byte, 5000), fresh)
+	writeFile(t, filepath.Join(root, "top.txt"), make([]byte, 10), fresh)
+
+	entries, err := Compute(context.Background(), root, Options{Now: testNow})
+	require.NoError(t, err)
+	got := byPath(entries)
+
+	docs := got[filepath.Join(root, "docs")]
+	assert.Equal(t, int64(4000), docs.Size, "nested files roll up to the depth-1 directory")
+	assert.Equal(t, int64(2), docs.Files)
+	assert.Equal(t, int64(3000), docs.StaleBytes)
+	assert.InDelta(t, 0.75, docs.StaleRatio(), 0.001)
+
+	app := got[filepath.Join(root, "app")]
+	assert.Equal(t, int64(100), app.Size, "rebuildable directories are split out")
+
+	modules := got[filepath.Join(root, "app", "web", "node_modules")]
+	assert.Equal(t, int64(5000), modules.Size)
+	assert.Equal(t, "npm install", modules.Rebuildable)
+
+	assert.Equal(t, int64(10), got[root].Size, "files directly in the root are ranked as the root")
+
+	assert.Equal(t, filepath.Join(root, "app", "web", "node_modules"), entries[0].Path)
+	for i := 1; i < len(entries); i++ {
+		assert.GreaterOrEqual(t, entries[i-1].Score, entries[i].Score, "entries are sorted by score")
+	}
+}
+
+func TestComputeDepth(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", "b", "c", "f.txt"), []byte("data"), testNow)
+
+	entries, err := Compute(context.Background(), root, Options{Depth: 2, Now: testNow})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Join(root, "a", "b"), entries[0].Path)
+}
+
+func TestComputeExclude(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "keep", "f.txt"), []byte("data"), testNow)
+	writeFile(t, filepath.Join(root, "skip", "f.txt"), []byte("data"), testNow)
+
+	entries, err := Compute(context.Background(), root, Options{Exclude: []string{"skip"}, Now: testNow})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Join(root, "keep"), entries[0].Path)
+}
+
+func TestComputeDuplicates(t *testing.T) {
+	root := t.TempDir()
+	data := bytes.Repeat([]byte("sweep"), duplicateMinSize/5+1)
+	other := bytes.Clone(data)
+	other[len(other)-1] = 'x'
+
+	writeFile(t, filepath.Join(root, "a", "orig.bin"), data, testNow)
+	writeFile(t, filepath.Join(root, "b", "copy.bin"), data, testNow)
+	writeFile(t, filepath.Join(root, "c", "other.bin"), other, testNow)
+
+	entries, err := Compute(context.Background(), root, Options{Duplicates: true, Now: testNow})
+	require.NoError(t, err)
+	got := byPath(entries)
+
+	assert.Zero(t, got[filepath.Join(root, "a")].DuplicateBytes, "the first copy is kept")
+	assert.Equal(t, int64(len(data)), got[filepath.Join(root, "b")].DuplicateBytes)
+	assert.Zero(t, got[filepath.Join(root, "c")].DuplicateBytes, "files differing at the end are not duplicates")
+
+	entries, err = Compute(context.Background(), root, Options{Now: testNow})
+	require.NoError(t, err)
+	assert.Zero(t, byPath(entries)[filepath.Join(root, "b")].DuplicateBytes, "duplicates are only checked when enabled")
+}
+
+func TestComputeCancelled(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", "f.txt"), []byte("data"), testNow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Compute(ctx, root, Options{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestComputeFileRoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "afile.txt")
+	writeFile(t, path, []byte("data"), testNow)
+
+	_, err := Compute(context.Background(), path, Options{})
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, SeverityHigh, severity(60))
+	assert.Equal(t, SeverityMedium, severity(59))
+	assert.Equal(t, SeverityMedium, severity(35))
+	assert.Equal(t, SeverityLow, severity(34))
+}
+
+func TestWrite(t *testing.T) {
+	entries := []Entry{
+		{Path: "/p/node_modules", Size: 2 << 20, StaleBytes: 1 << 20, Rebuildable: "npm install", Score: 72, Severity: SeverityHigh},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, "pretty", entries))
+	assert.Contains(t, text.String(), "SCORE")
+	assert.Contains(t, text.String(), "50%")
+	assert.Contains(t, text.String(), "/p/node_modules (rebuildable: npm install)")
+
+	var js bytes.Buffer
+	require.NoError(t, Write(&js, output.ReportJSON, entries))
+	var decoded []Entry
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, entries, decoded)
+
+	assert.ErrorIs(t, Write(&text, "xml", entries), output.ErrUnknownReportFormat)
+}
@@ -0,0 +1,275 @@
+// Package service installs sweepd as a user service, so the operating
+// system starts it at login and restarts it if it crashes: a launchd agent
+// on macOS, or a systemd user unit on Linux.
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/config"
+)
+
+// Service managers.
+const (
+	Launchd = "launchd"
+	Systemd = "systemd"
+)
+
+// Label is the launchd label of the sweepd agent.
+const Label = "io.github.jamesainslie.sweepd"
+
+// Unit is the name of the sweepd systemd user unit.
+const Unit = "sweepd.service"
+
+// restartDelay is how long the service manager waits before restarting
+// sweepd after it crashes, in seconds.
+const restartDelay = 10
+
+// ErrUnsupported is returned on systems without a supported service
+// manager.
+var ErrUnsupported = errors.New("installing sweepd as a service is only supported with launchd (macOS) and systemd (Linux)")
+
+// xdgVars are passed on to sweepd when set, since a service doesn't inherit
+// the shell's environment and would otherwise use different directories
+// than sweep.
+var xdgVars = []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME"}
+
+// Replaced in tests.
+var (
+	getenv     = os.Getenv
+	goos       = runtime.GOOS
+	homeDir    = os.UserHomeDir
+	getuid     = os.Getuid
+	runCommand = func(ctx context.Context, name string, args ...string) error {
+		out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+			}
+			return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		}
+		return nil
+	}
+)
+
+// Service is sweepd as a user service.
+type Service struct {
+	Manager string   // Launchd or Systemd
+	Binary  string   // The sweepd binary to run
+	Env     []string // KEY=value variables set for sweepd
+	Path    string   // Where the plist or unit file goes
+	LogPath string   // Where launchd writes sweepd's output; systemd keeps it in the journal
+}
+
+// New returns the service running binary with this system's service
+// manager. The XDG base directory variables set now are passed on to
+// sweepd so it uses the same config, data and log directories as sweep.
+// It returns ErrUnsupported on other systems.
+func New(binary string) (Service, error) {
+	home, err := homeDir()
+	if err != nil {
+		return Service{}, fmt.Errorf("find home directory: %w", err)
+	}
+	s := Service{Binary: binary}
+	for _, key := range xdgVars {
+		if value := getenv(key); value != "" {
+			s.Env = append(s.Env, key+"="+value)
+		}
+	}
+
+	switch goos {
+	case "darwin":
+		s.Manager = Launchd
+		s.Path = filepath.Join(home, "Library", "LaunchAgents", Label+".plist")
+		s.LogPath = filepath.Join(config.StateDir(), "sweepd.out.log")
+	case "linux":
+		s.Manager = Systemd
+		configHome := getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		s.Path = filepath.Join(configHome, "systemd", "user", Unit)
+	default:
+		return Service{}, ErrUnsupported
+	}
+	return s, nil
+}
+
+// Definition returns the launchd plist or systemd unit for the service.
+func (s Service) Definition() []byte {
+	if s.Manager == Launchd {
+		return s.plist()
+	}
+	return s.unit()
+}
+
+// plist renders the launchd agent. It starts sweepd at login and restarts
+// it unless it exits cleanly, as it does for 'sweep daemon stop'.
+func (s Service) plist() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	key := func(k string) { fmt.Fprintf(&b, "\t<key>%s</key>\n", k) }
+	str := func(indent, v string) {
+		fmt.Fprintf(&b, "%s<string>", indent)
+		_ = xml.EscapeText(&b, []byte(v))
+		b.WriteString("</string>\n")
+	}
+
+	key("Label")
+	str("\t", Label)
+	key("ProgramArguments")
+	b.WriteString("\t<array>\n")
+	str("\t\t", s.Binary)
+	b.WriteString("\t</array>\n")
+	if len(s.Env) > 0 {
+		key("EnvironmentVariables")
+		b.WriteString("\t<dict>\n")
+		for _, kv := range s.Env {
+			k, v, _ := strings.Cut(kv, "=")
+			b.WriteString("\t\t<key>")
+			_ = xml.EscapeText(&b, []byte(k))
+			b.WriteString("</key>\n")
+			str("\t\t", v)
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	key("RunAtLoad")
+	b.WriteString("\t<true/>\n")
+	key("KeepAlive")
+	b.WriteString("\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	key("ThrottleInterval")
+	fmt.Fprintf(&b, "\t<integer>%d</integer>\n", restartDelay)
+	key("ProcessType")
+	str("\t", "Background")
+	if s.LogPath != "" {
+		key("StandardOutPath")
+		str("\t", s.LogPath)
+		key("StandardErrorPath")
+		str("\t", s.LogPath)
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+// unit renders the systemd user unit. It starts sweepd at login and
+// restarts it if it fails, but not after 'sweep daemon stop'.
+func (s Service) unit() []byte {
+	var b bytes.Buffer
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=sweep disk usage daemon\n")
+	b.WriteString("Documentation=https://github.com/jamesainslie/sweep\n\n")
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdQuote(s.Binary))
+	for _, kv := range s.Env {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(kv))
+	}
+	b.WriteString("Restart=on-failure\n")
+	fmt.Fprintf(&b, "RestartSec=%d\n\n", restartDelay)
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.Bytes()
+}
+
+// systemdQuote quotes s for a unit file if it holds spaces, quotes,
+// backslashes, or specifiers.
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// Installed reports whether the service's file exists.
+func (s Service) Installed() bool {
+	_, err := os.Stat(s.Path)
+	return err == nil
+}
+
+// Install writes the service's file and loads it, which starts sweepd. A
+// service already installed is replaced.
+func (s Service) Install(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(s.Path), err)
+	}
+	if s.LogPath != "" {
+		if err := os.MkdirAll(filepath.Dir(s.LogPath), 0o755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(s.LogPath), err)
+		}
+	}
+
+	switch s.Manager {
+	case Launchd:
+		// bootstrap fails for a loaded agent, so unload the one replaced
+		if s.Installed() {
+			_ = runCommand(ctx, "launchctl", "bootout", s.launchdTarget())
+		}
+		if err := os.WriteFile(s.Path, s.Definition(), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", s.Path, err)
+		}
+		return runCommand(ctx, "launchctl", "bootstrap", s.launchdDomain(), s.Path)
+	case Systemd:
+		if err := os.WriteFile(s.Path, s.Definition(), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", s.Path, err)
+		}
+		if err := runCommand(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+			return err
+		}
+		return runCommand(ctx, "systemctl", "--user", "enable", "--now", Unit)
+	default:
+		return ErrUnsupported
+	}
+}
+
+// Uninstall unloads the service, which stops sweepd, and removes its file.
+// It does nothing if the service isn't installed.
+func (s Service) Uninstall(ctx context.Context) error {
+	if !s.Installed() {
+		return nil
+	}
+
+	switch s.Manager {
+	case Launchd:
+		// Fails if the agent isn't loaded, which leaves nothing to do
+		_ = runCommand(ctx, "launchctl", "bootout", s.launchdTarget())
+	case Systemd:
+		if err := runCommand(ctx, "systemctl", "--user", "disable", "--now", Unit); err != nil {
+			return err
+		}
+	default:
+		return ErrUnsupported
+	}
+
+	if err := os.Remove(s.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove %s: %w", s.Path, err)
+	}
+	if s.Manager == Systemd {
+		return runCommand(ctx, "systemctl", "--user", "daemon-reload")
+	}
+	return nil
+}
+
+// launchdDomain is the user's GUI session, where login agents run.
+func (s Service) launchdDomain() string {
+	return "gui/" + strconv.Itoa(getuid())
+}
+
+// launchdTarget is the agent in the user's GUI session.
+func (s Service) launchdTarget() string {
+	return s.launchdDomain() + "/" + Label
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSystem stands in for the environment and the service manager,
+// recording the commands run.
+type fakeSystem struct {
+	env  map[string]string
+	home string
+	fail map[string]error // Command line -> its error
+	ran  []string
+}
+
+// setup makes New and the services it returns run on platform in f.
+func setup(t *testing.T, platform string, f *fakeSystem) {
+	t.Helper()
+	origEnv, origOS, origHome, origUID, origRun := getenv, goos, homeDir, getuid, runCommand
+	t.Cleanup(func() {
+		getenv, goos, homeDir, getuid, runCommand = origEnv, origOS, origHome, origUID, origRun
+	})
+
+	goos = platform
+	getenv = func(k string) string { return f.env[k] }
+	homeDir = func() (string, error) { return f.home, nil }
+	getuid = func() int { return 501 }
+	runCommand = func(_ context.Context, name string, args ...string) error {
+		line := strings.Join(append([]string{name}, args...), " ")
+		f.ran = append(f.ran, line)
+		return f.fail[line]
+	}
+}
+
+func TestNew(t *testing.T) {
+	f := &fakeSystem{home: "/home/ana", env: map[string]string{"XDG_DATA_HOME": "/srv/data"}}
+	setup(t, "linux", f)
+
+	s, err := New("/usr/local/bin/sweepd")
+	require.NoError(t, err)
+	assert.Equal(t, Systemd, s.Manager)
+	assert.Equal(t, "/home/ana/.config/systemd/user/sweepd.service", s.Path)
+	assert.Equal(t, []string{"XDG_DATA_HOME=/srv/data"}, s.Env, "only the variables set are passed on")
+	assert.Empty(t, s.LogPath, "systemd keeps the output in the journal")
+
+	f.env["XDG_CONFIG_HOME"] = "/etc/ana"
+	s, err = New("/usr/local/bin/sweepd")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ana/systemd/user/sweepd.service", s.Path)
+
+	goos = "darwin"
+	s, err = New("/usr/local/bin/sweepd")
+	require.NoError(t, err)
+	assert.Equal(t, Launchd, s.Manager)
+	assert.Equal(t, "/home/ana/Library/LaunchAgents/"+Label+".plist", s.Path)
+	assert.NotEmpty(t, s.LogPath)
+
+	goos = "windows"
+	_, err = New(`C:\sweepd.exe`)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestDefinitionSystemd(t *testing.T) {
+	s := Service{
+		Manager: Systemd,
+		Binary:  "/opt/my tools/sweepd",
+		Env:     []string{"XDG_STATE_HOME=/srv/100%/state"},
+	}
+	unit := string(s.Definition())
+	assert.Contains(t, unit, `ExecStart="/opt/my tools/sweepd"`)
+	assert.Contains(t, unit, "Environment=XDG_STATE_HOME=/srv/100%%/state")
+	assert.Contains(t, unit, "Restart=on-failure")
+	assert.Contains(t, unit, "WantedBy=default.target")
+}
+
+func TestDefinitionLaunchd(t *testing.T) {
+	s := Service{
+		Manager: Launchd,
+		Binary:  "/Users/ana/go/bin/sweepd",
+		Env:     []string{"XDG_CONFIG_HOME=/Users/ana/<cfg>"},
+		LogPath: "/Users/ana/.local/state/sweep/sweepd.out.log",
+	}
+	plist := s.Definition()
+
+	// The plist is well-formed XML
+	dec := xml.NewDecoder(strings.NewReader(string(plist)))
+	for {
+		_, err := dec.Token()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	out := string(plist)
+	assert.Contains(t, out, "<string>"+Label+"</string>")
+	assert.Contains(t, out, "<string>/Users/ana/go/bin/sweepd</string>")
+	assert.Contains(t, out, "<key>XDG_CONFIG_HOME</key>")
+	assert.Contains(t, out, "<string>/Users/ana/&lt;cfg&gt;</string>")
+	assert.Contains(t, out, "<key>SuccessfulExit</key>\n\t\t<false/>")
+	assert.Contains(t, out, "<key>StandardErrorPath</key>")
+}
+
+func TestInstallSystemd(t *testing.T) {
+	home := t.TempDir()
+	f := &fakeSystem{home: home}
+	setup(t, "linux", f)
+	s, err := New("/usr/bin/sweepd")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Install(ctx))
+	assert.True(t, s.Installed())
+	written, err := os.ReadFile(s.Path)
+	require.NoError(t, err)
+	assert.Equal(t, s.Definition(), written)
+	assert.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now sweepd.service",
+	}, f.ran)
+
+	f.ran = nil
+	require.NoError(t, s.Uninstall(ctx))
+	assert.False(t, s.Installed())
+	assert.Equal(t, []string{
+		"systemctl --user disable --now sweepd.service",
+		"systemctl --user daemon-reload",
+	}, f.ran)
+
+	f.ran = nil
+	require.NoError(t, s.Uninstall(ctx), "uninstalling twice does nothing")
+	assert.Empty(t, f.ran)
+}
+
+func TestInstallLaunchd(t *testing.T) {
+	home := t.TempDir()
+	f := &fakeSystem{home: home, env: map[string]string{"XDG_STATE_HOME": filepath.Join(home, "state")}}
+	setup(t, "darwin", f)
+	s, err := New("/usr/local/bin/sweepd")
+	require.NoError(t, err)
+	s.LogPath = filepath.Join(home, "state", "sweep", "sweepd.out.log")
+	ctx := context.Background()
+
+	require.NoError(t, s.Install(ctx))
+	assert.DirExists(t, filepath.Dir(s.LogPath))
+	assert.Equal(t, []string{"launchctl bootstrap gui/501 " + s.Path}, f.ran)
+
+	f.ran = nil
+	require.NoError(t, s.Install(ctx), "installing again replaces the agent")
+	assert.Equal(t, []string{
+		"launchctl bootout gui/501/" + Label,
+		"launchctl bootstrap gui/501 " + s.Path,
+	}, f.ran)
+
+	f.ran = nil
+	f.fail = map[string]error{"launchctl bootout gui/501/" + Label: errors.New("No such process")}
+	require.NoError(t, s.Uninstall(ctx), "an agent that isn't loaded is still removed")
+	assert.False(t, s.Installed())
+}
+
+func TestInstallFails(t *testing.T) {
+	f := &fakeSystem{home: t.TempDir()}
+	setup(t, "linux", f)
+	s, err := New("/usr/bin/sweepd")
+	require.NoError(t, err)
+
+	f.fail = map[string]error{"systemctl --user enable --now sweepd.service": errors.New("Failed to connect to bus")}
+	assert.ErrorContains(t, s.Install(context.Background()), "Failed to connect to bus")
+}
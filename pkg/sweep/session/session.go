@@ -0,0 +1,172 @@
+// Package session remembers where the TUI was left for each scanned root:
+// the view, the cursor and scroll positions, the list's sort, and the
+// expanded directories of the tree. Sessions are persisted to a JSON file in the state directory,
+// so reopening sweep on the same directory picks up where it left off, and
+// sweep run without a directory can offer to resume the last one.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Views the TUI can be left in.
+const (
+	ViewList    = "list"
+	ViewTree    = "tree"
+	ViewTreemap = "treemap"
+)
+
+// MaxSessions is how many roots are remembered; the least recently used
+// are forgotten first.
+const MaxSessions = 200
+
+// State is where the TUI was left on a root.
+type State struct {
+	View string `json:"view"` // ViewList, ViewTree, or ViewTreemap
+
+	// The roots scanned, to resume the last session without naming them
+	Roots []string `json:"roots,omitempty"`
+
+	// The file under the list's cursor, and how far the list was scrolled
+	ListCursor string `json:"list_cursor,omitempty"`
+	ListOffset int    `json:"list_offset,omitempty"`
+
+	// The list's sort key, empty for by size, and whether it is reversed
+	ListSort    string `json:"list_sort,omitempty"`
+	ListReverse bool   `json:"list_reverse,omitempty"`
+
+	// The node under the tree's cursor, how far the tree was scrolled,
+	// and its expanded directories
+	TreeCursor string   `json:"tree_cursor,omitempty"`
+	TreeOffset int      `json:"tree_offset,omitempty"`
+	Expanded   []string `json:"expanded,omitempty"`
+
+	Saved time.Time `json:"saved"`
+}
+
+// Store manages sessions persisted to a JSON file.
+type Store struct {
+	path     string
+	mu       sync.Mutex
+	sessions map[string]State // Key -> state
+}
+
+// fileFormat is the on-disk representation of the store.
+type fileFormat struct {
+	Sessions map[string]State `json:"sessions"`
+}
+
+// Key identifies a session by the roots scanned in it.
+func Key(roots ...string) string {
+	cleaned := make([]string, len(roots))
+	for i, root := range roots {
+		cleaned[i] = filepath.Clean(root)
+	}
+	return strings.Join(cleaned, string(os.PathListSeparator))
+}
+
+// Open loads the store from path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("sessions file path cannot be empty")
+	}
+
+	s := &Store{path: path, sessions: make(map[string]State)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions file: %w", err)
+	}
+
+	var ff fileFormat
+	if err := json.Unmarshal(data, &ff); err != nil {
+		return nil, fmt.Errorf("failed to parse sessions file: %w", err)
+	}
+	for key, state := range ff.Sessions {
+		s.sessions[key] = state
+	}
+	return s, nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the session saved for key.
+func (s *Store) Get(key string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.sessions[key]
+	return state, ok
+}
+
+// Last returns the most recently saved session that records its roots.
+func (s *Store) Last() (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last State
+	for _, state := range s.sessions {
+		if len(state.Roots) > 0 && state.Saved.After(last.Saved) {
+			last = state
+		}
+	}
+	return last, len(last.Roots) > 0
+}
+
+// Put saves state as the session for key and persists the store,
+// forgetting the least recently saved sessions beyond MaxSessions.
+func (s *Store) Put(key string, state State) error {
+	if state.Saved.IsZero() {
+		state.Saved = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[key] = state
+	if len(s.sessions) > MaxSessions {
+		keys := make([]string, 0, len(s.sessions))
+		for k := range s.sessions {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return s.sessions[b].Saved.Compare(s.sessions[a].Saved)
+		})
+		for _, k := range keys[MaxSessions:] {
+			delete(s.sessions, k)
+		}
+	}
+	return s.save()
+}
+
+// save writes the store to disk atomically. Callers must hold s.mu.
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Sessions: s.sessions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sessions: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sessions.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, ok := s.Get(Key("/data")); ok {
+		t.Fatal("a new store should have no sessions")
+	}
+
+	want := State{
+		View:       ViewTree,
+		ListCursor: "/data/big.iso",
+		ListOffset: 12,
+		TreeCursor: "/data/vm",
+		TreeOffset: 3,
+		Expanded:   []string{"/data", "/data/vm"},
+	}
+	if err := s.Put(Key("/data/"), want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got, ok := reopened.Get(Key("/data"))
+	if !ok {
+		t.Fatal("the session should be persisted")
+	}
+	if got.Saved.IsZero() {
+		t.Error("Put should stamp the session")
+	}
+	got.Saved = time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+	if _, ok := reopened.Get(Key("/data", "/more")); ok {
+		t.Error("sessions on several roots should be kept apart")
+	}
+}
+
+func TestStoreForgetsOldest(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "sessions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1_700_000_000, 0)
+	for i := range MaxSessions + 2 {
+		state := State{View: ViewList, Saved: start.Add(time.Duration(i) * time.Minute)}
+		if err := s.Put(fmt.Sprintf("/root%d", i), state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.sessions) != MaxSessions {
+		t.Fatalf("%d sessions kept, want %d", len(s.sessions), MaxSessions)
+	}
+	for _, key := range []string{"/root0", "/root1"} {
+		if _, ok := s.Get(key); ok {
+			t.Errorf("%s is the oldest and should be forgotten", key)
+		}
+	}
+}
+
+func TestOpenCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Fatal("Open() error = nil, want parse error")
+	}
+}
+
+func TestStoreLast(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "sessions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Last(); ok {
+		t.Fatal("a new store should have no last session")
+	}
+	start := time.Unix(1_700_000_000, 0)
+	puts := []struct {
+		key   string
+		state State
+	}{
+		{"/old", State{View: ViewList, Roots: []string{"/old"}, Saved: start}},
+		{"/movies", State{View: ViewTree, Roots: []string{"/movies"}, Saved: start.Add(time.Hour)}},
+		{"/unrecorded", State{View: ViewList, Saved: start.Add(2 * time.Hour)}}, // Saved before roots were
+	}
+	for _, p := range puts {
+		if err := s.Put(p.key, p.state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last, ok := s.Last()
+	if !ok || !reflect.DeepEqual(last.Roots, []string{"/movies"}) || last.View != ViewTree {
+		t.Errorf("Last() = %+v, %v, want the /movies session", last, ok)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build darwin
+
+package sharing
+
+import (
+	"encoding/binary"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Extended common attributes of getattrlist(2) that x/sys/unix lacks.
+const (
+	attrCmnExtPrivateSize = 0x00000008 // off_t: bytes not shared with other files
+	attrCmnExtCloneID     = 0x00000100 // u_int64_t: ID shared by clones
+	attrCmnExtExtFlags    = 0x00000200 // u_int64_t: EF_* flags
+
+	efMayShareBlocks = 0x00000001 // The file may share blocks with another file
+)
+
+// cloneInfo returns the clone ID and private size of a file that may share
+// blocks with APFS clones.
+func cloneInfo(path string) (id uint64, private int64, ok bool) {
+	p, err := unix.BytePtrFromString(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	attrs := unix.Attrlist{
+		Bitmapcount: unix.ATTR_BIT_MAP_COUNT,
+		Forkattr:    attrCmnExtPrivateSize | attrCmnExtCloneID | attrCmnExtExtFlags,
+	}
+	// Length (4), then each attribute packed on 4-byte boundaries in bit order
+	var buf [4 + 8 + 8 + 8]byte
+	_, _, errno := unix.Syscall6(unix.SYS_GETATTRLIST,
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&attrs)),
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(len(buf)),
+		unix.FSOPT_NOFOLLOW|unix.FSOPT_ATTR_CMN_EXTENDED|unix.FSOPT_PACK_INVAL_ATTRS,
+		0)
+	if errno != 0 {
+		return 0, 0, false
+	}
+
+	flags := binary.LittleEndian.Uint64(buf[20:28])
+	if flags&efMayShareBlocks == 0 {
+		return 0, 0, false
+	}
+	private = int64(binary.LittleEndian.Uint64(buf[4:12])) //nolint:gosec // off_t
+	id = binary.LittleEndian.Uint64(buf[12:20])
+	return id, private, id != 0
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package sharing
+
+// cloneInfo reports APFS clones, which exist only on macOS.
+func cloneInfo(string) (id uint64, private int64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,140 @@
+// Package sharing finds files that share storage with other files, through
+// hard links or APFS clones, so totals can tell apparent size (every path
+// counted in full) from actual disk usage (shared storage counted once).
+package sharing
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+// Info identifies the storage behind a file that shares it.
+type Info struct {
+	Dev     uint64 `json:"dev"`
+	Ino     uint64 `json:"ino"`
+	Links   uint64 `json:"links,omitempty"`    // Hard links to the inode
+	CloneID uint64 `json:"clone_id,omitempty"` // APFS clone ID; clones with the same ID share blocks
+	Private int64  `json:"private,omitempty"`  // Bytes of a clone that are its own
+}
+
+// FromFileInfo returns the hard link information in fi, or nil if the file
+// has a single link or the platform doesn't report links.
+func FromFileInfo(fi fs.FileInfo) *Info {
+	dev, ino, links, ok := identity(fi)
+	if !ok || links < 2 {
+		return nil
+	}
+	return &Info{Dev: dev, Ino: ino, Links: links}
+}
+
+// Stat is FromFileInfo that also detects APFS clones on macOS, at the
+// cost of a system call per file.
+func Stat(path string, fi fs.FileInfo) *Info {
+	info := FromFileInfo(fi)
+	cloneID, private, ok := cloneInfo(path)
+	if !ok {
+		return info
+	}
+	if info == nil {
+		dev, ino, links, _ := identity(fi)
+		info = &Info{Dev: dev, Ino: ino, Links: links}
+	}
+	info.CloneID = cloneID
+	info.Private = private
+	return info
+}
+
+// HardLinked reports whether other paths link to the same inode.
+func (i *Info) HardLinked() bool {
+	return i != nil && i.Links > 1
+}
+
+// Clone reports whether the file may share blocks with APFS clones.
+func (i *Info) Clone() bool {
+	return i != nil && i.CloneID != 0
+}
+
+// String describes the sharing, e.g. "3 hard links" or "APFS clone".
+func (i *Info) String() string {
+	var parts []string
+	if i.Clone() {
+		parts = append(parts, "APFS clone")
+	}
+	if i.HardLinked() {
+		parts = append(parts, fmt.Sprintf("%d hard links", i.Links))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type inodeKey struct{ dev, ino uint64 }
+
+type cloneKey struct{ dev, id uint64 }
+
+// Counter totals file sizes, counting shared storage once. The zero value
+// is ready to use and it is safe for concurrent use.
+type Counter struct {
+	mu       sync.Mutex
+	inodes   map[inodeKey]struct{}
+	clones   map[cloneKey]struct{}
+	apparent int64
+	actual   int64
+}
+
+// Add counts a file of the given size and returns the bytes it adds to
+// actual disk usage: its size the first time its storage is seen, nothing
+// for further hard links, and only its private bytes for further clones.
+func (c *Counter) Add(info *Info, size int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apparent += size
+
+	added := size
+	switch {
+	case info.HardLinked() && c.seenInode(info):
+		added = 0
+	case info.Clone() && c.seenClone(info):
+		added = min(info.Private, size)
+	}
+	c.actual += added
+	return added
+}
+
+func (c *Counter) seenInode(info *Info) bool {
+	key := inodeKey{info.Dev, info.Ino}
+	if _, ok := c.inodes[key]; ok {
+		return true
+	}
+	if c.inodes == nil {
+		c.inodes = make(map[inodeKey]struct{})
+	}
+	c.inodes[key] = struct{}{}
+	return false
+}
+
+func (c *Counter) seenClone(info *Info) bool {
+	key := cloneKey{info.Dev, info.CloneID}
+	if _, ok := c.clones[key]; ok {
+		return true
+	}
+	if c.clones == nil {
+		c.clones = make(map[cloneKey]struct{})
+	}
+	c.clones[key] = struct{}{}
+	return false
+}
+
+// Apparent returns the total size of the files counted.
+func (c *Counter) Apparent() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.apparent
+}
+
+// Actual returns the total with shared storage counted once.
+func (c *Counter) Actual() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.actual
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package sharing
+
+import "io/fs"
+
+// identity is unavailable on this platform, so no file shares storage.
+func identity(fs.FileInfo) (dev, ino, links uint64, ok bool) {
+	return 0, 0, 0, false
+}
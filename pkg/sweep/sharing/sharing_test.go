@@ -0,0 +1,54 @@
+package sharing
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHardLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hard links are not reported on Windows")
+	}
+	dir := t.TempDir()
+	orig, link, other := filepath.Join(dir, "a.iso"), filepath.Join(dir, "b.iso"), filepath.Join(dir, "c.iso")
+	require.NoError(t, os.WriteFile(orig, make([]byte, 100), 0o644))
+	require.NoError(t, os.Link(orig, link))
+	require.NoError(t, os.WriteFile(other, make([]byte, 10), 0o644))
+
+	stat := func(path string) *Info {
+		fi, err := os.Lstat(path)
+		require.NoError(t, err)
+		return Stat(path, fi)
+	}
+	a, b := stat(orig), stat(link)
+	require.NotNil(t, a)
+	assert.Equal(t, uint64(2), a.Links)
+	assert.Equal(t, a.Ino, b.Ino)
+	assert.Equal(t, "2 hard links", a.String())
+	assert.Nil(t, stat(other), "a single link shares nothing")
+
+	var c Counter
+	assert.Equal(t, int64(100), c.Add(a, 100))
+	assert.Equal(t, int64(0), c.Add(b, 100))
+	assert.Equal(t, int64(10), c.Add(nil, 10))
+	assert.Equal(t, int64(210), c.Apparent())
+	assert.Equal(t, int64(110), c.Actual())
+}
+
+func TestCounterClones(t *testing.T) {
+	var c Counter
+	first := &Info{Dev: 1, Ino: 10, Links: 1, CloneID: 7, Private: 0}
+	edited := &Info{Dev: 1, Ino: 11, Links: 1, CloneID: 7, Private: 30}
+	elsewhere := &Info{Dev: 2, Ino: 11, Links: 1, CloneID: 7}
+
+	assert.Equal(t, int64(100), c.Add(first, 100), "the first clone owns the shared blocks")
+	assert.Equal(t, int64(30), c.Add(edited, 100), "later clones add their own blocks")
+	assert.Equal(t, int64(100), c.Add(elsewhere, 100), "clone IDs are per volume")
+	assert.Equal(t, int64(230), c.Actual())
+	assert.Equal(t, "APFS clone", first.String())
+}
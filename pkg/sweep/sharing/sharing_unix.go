@@ -0,0 +1,17 @@
+//go:build unix
+
+package sharing
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// identity returns the device, inode, and link count of a file.
+func identity(fi fs.FileInfo) (dev, ino, links uint64, ok bool) {
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return uint64(stat.Dev), uint64(stat.Ino), uint64(stat.Nlink), true //nolint:unconvert // Field types vary by platform
+}
@@ -0,0 +1,138 @@
+// Package sizediff compares disk usage at two points in time, for
+// 'sweep diff'.
+package sizediff
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Change is how much a directory or file grew or shrank.
+type Change struct {
+	Path   string `json:"path"`
+	Before int64  `json:"before"` // 0 for paths that are new
+	After  int64  `json:"after"`  // 0 for paths that are gone
+	Delta  int64  `json:"delta"`  // Negative when the path shrank
+}
+
+// NewChange returns the change from before to after.
+func NewChange(path string, before, after int64) Change {
+	return Change{Path: path, Before: before, After: after, Delta: after - before}
+}
+
+// Compare returns the paths whose size differs between before and after,
+// largest change first whichever its direction. Paths missing from one
+// side count as size 0 there.
+func Compare(before, after map[string]int64) []Change {
+	var changes []Change
+	for path, size := range after {
+		if old := before[path]; old != size {
+			changes = append(changes, NewChange(path, old, size))
+		}
+	}
+	for path, size := range before {
+		if _, ok := after[path]; !ok && size != 0 {
+			changes = append(changes, NewChange(path, size, 0))
+		}
+	}
+	Sort(changes)
+	return changes
+}
+
+// Sort orders changes largest first, whichever their direction, then by
+// path.
+func Sort(changes []Change) {
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(abs(b.Delta), abs(a.Delta)), strings.Compare(a.Path, b.Path))
+	})
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Report is the result of 'sweep diff'.
+type Report struct {
+	Root  string    `json:"root"`
+	Since time.Time `json:"since"` // When the snapshot compared against was taken
+	Dirs  []Change  `json:"dirs"`
+	Files []Change  `json:"files"` // Large files
+}
+
+// Write renders a report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+	})
+}
+
+// WriteText renders the changes as tables of directories and files in
+// report order.
+func WriteText(w io.Writer, r Report) error {
+	fmt.Fprintf(w, "Changes under %s since %s\n", r.Root, r.Since.Local().Format("2006-01-02 15:04"))
+	if len(r.Dirs) == 0 && len(r.Files) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo changes.")
+		return err
+	}
+	for _, section := range []struct {
+		title   string
+		changes []Change
+	}{{"DIRECTORY", r.Dirs}, {"FILE", r.Files}} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(tw, "CHANGE\tBEFORE\tAFTER\t\t%s\n", section.title)
+		for _, c := range section.changes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t\t%s\n", FormatDelta(c.Delta), formatSide(c.Before), formatSide(c.After), c.Path)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatDelta formats a size change with its sign, e.g. "+1.2 GiB".
+func FormatDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + types.FormatSize(-delta)
+	}
+	return "+" + types.FormatSize(delta)
+}
+
+// formatSide formats one side of a change, with "-" for a path that didn't
+// exist.
+func formatSide(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+	return types.FormatSize(size)
+}
+
+// WriteJSON renders the report as JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Dirs == nil {
+		r.Dirs = []Change{}
+	}
+	if r.Files == nil {
+		r.Files = []Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
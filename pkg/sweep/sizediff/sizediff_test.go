@@ -0,0 +1,56 @@
+package sizediff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+func TestCompare(t *testing.T) {
+	before := map[string]int64{"/data": 100, "/data/old": 40, "/data/same": 10, "/data/shrunk": 50}
+	after := map[string]int64{"/data": 160, "/data/new": 90, "/data/same": 10, "/data/shrunk": 20}
+
+	changes := Compare(before, after)
+	assert.Equal(t, []Change{
+		{Path: "/data/new", Before: 0, After: 90, Delta: 90},
+		{Path: "/data", Before: 100, After: 160, Delta: 60},
+		{Path: "/data/old", Before: 40, After: 0, Delta: -40},
+		{Path: "/data/shrunk", Before: 50, After: 20, Delta: -30},
+	}, changes, "unchanged paths are left out and the largest change in either direction comes first")
+}
+
+func TestWrite(t *testing.T) {
+	r := Report{
+		Root:  "/data",
+		Since: time.Date(2026, 1, 10, 12, 0, 0, 0, time.Local),
+		Dirs:  []Change{NewChange("/data/videos", types.GiB, 3*types.GiB)},
+		Files: []Change{NewChange("/data/videos/old.mkv", types.GiB, 0)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "pretty", r))
+	out := buf.String()
+	assert.Contains(t, out, "since 2026-01-10 12:00")
+	assert.Contains(t, out, "+2.0 GiB")
+	assert.Contains(t, out, "-1.0 GiB")
+	assert.Contains(t, out, "DIRECTORY")
+	assert.Contains(t, out, "FILE")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, Report{Root: "/data"}))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, []any{}, decoded["dirs"], "empty lists are arrays, not null")
+
+	err := Write(&buf, "csv", r)
+	assert.ErrorIs(t, err, output.ErrUnknownReportFormat)
+	assert.True(t, strings.Contains(err.Error(), "json"))
+}
@@ -0,0 +1,107 @@
+package summary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/jamesainslie/sweep/pkg/sweep/types"
+)
+
+// Report is the result of 'sweep report'.
+type Report struct {
+	Root   string  `json:"root,omitempty"` // Empty for every indexed path
+	By     string  `json:"by"`
+	Files  int64   `json:"files"` // Over all groups, including any left out
+	Size   int64   `json:"size"`
+	Groups []Group `json:"groups"`
+}
+
+// Report returns the groups with their totals, under root.
+func (t *Totals) Report(root string) Report {
+	r := Report{Root: root, By: t.by, Groups: t.Groups()}
+	for _, g := range r.Groups {
+		r.Files += g.Files
+		r.Size += g.Size
+	}
+	return r
+}
+
+// Write renders a report in the given format.
+func Write(w io.Writer, format string, r Report) error {
+	return output.WriteReport(w, format, r, output.ReportWriters[Report]{
+		output.ReportText: WriteText,
+		output.ReportJSON: WriteJSON,
+		output.ReportCSV:  WriteCSV,
+	})
+}
+
+// WriteText renders the groups as a table in report order, with a total.
+func WriteText(w io.Writer, r Report) error {
+	if len(r.Groups) == 0 {
+		_, err := fmt.Fprintln(w, "No indexed files.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tFILES\tSIZE\tSHARE\n", heading(r.By))
+	for _, g := range r.Groups {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", g.Key, g.Files, types.FormatSize(g.Size), share(g.Size, r.Size))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d file(s), %s\n", r.Files, types.FormatSize(r.Size))
+	return err
+}
+
+// heading returns the table heading of the groups' keys.
+func heading(by string) string {
+	if by == ByAge {
+		return "AGE"
+	}
+	return strings.ToUpper(by)
+}
+
+// share formats size as a percentage of total.
+func share(size, total int64) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(size)*100/float64(total))
+}
+
+// WriteJSON renders the report as JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	if r.Groups == nil {
+		r.Groups = []Group{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// csvHeader names the CSV columns, matching the JSON field names of a
+// group. Sizes are in bytes, with a human-readable copy for spreadsheets.
+var csvHeader = []string{"key", "files", "size", "size_human"}
+
+// WriteCSV renders the groups as CSV, one row per group in report order.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, g := range r.Groups {
+		row := []string{g.Key, strconv.FormatInt(g.Files, 10), strconv.FormatInt(g.Size, 10), types.FormatSize(g.Size)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,161 @@
+// Package summary totals indexed files by extension, type, owner, or age
+// for 'sweep report', so capacity reports can say "Video: 120 files,
+// 840 GB" instead of listing files.
+package summary
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/filetype"
+)
+
+// Groupings.
+const (
+	ByExtension = "extension"
+	ByType      = "type"
+	ByOwner     = "owner"
+	ByAge       = "age-bucket"
+)
+
+// Groupings lists the supported groupings.
+var Groupings = []string{ByExtension, ByType, ByOwner, ByAge}
+
+// ErrUnknownGrouping is returned by NewTotals for unsupported groupings.
+var ErrUnknownGrouping = errors.New("unknown grouping")
+
+// Keys of files a grouping has nothing for.
+const (
+	NoExtension  = "(none)"
+	UnknownOwner = "(unknown)"
+)
+
+// AgeBucket is a range of file ages, by modification time.
+type AgeBucket struct {
+	Name   string
+	MaxAge time.Duration // Files younger than this; 0 for no limit
+}
+
+const day = 24 * time.Hour
+
+// AgeBuckets are the buckets ByAge groups files in, youngest first. A file
+// falls in the first bucket it is younger than.
+var AgeBuckets = []AgeBucket{
+	{"< 1 week", 7 * day},
+	{"1 week - 1 month", 30 * day},
+	{"1 - 6 months", 182 * day},
+	{"6 - 12 months", 365 * day},
+	{"1 - 2 years", 2 * 365 * day},
+	{"2 - 5 years", 5 * 365 * day},
+	{"> 5 years", 0},
+}
+
+// File is the part of an indexed file the groupings look at.
+type File struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	Owner   string // Username or UID; empty if unknown
+	Type    string // From filetype; empty to use the extension's
+}
+
+// Group is the total of the files with the same key.
+type Group struct {
+	Key   string `json:"key"`
+	Files int64  `json:"files"`
+	Size  int64  `json:"size"`
+}
+
+// Totals adds up files into groups. It is not safe for concurrent use.
+type Totals struct {
+	by     string
+	now    time.Time
+	groups map[string]*Group
+	order  map[string]int // Age bucket positions, for ByAge
+}
+
+// NewTotals creates totals grouping files by one of Groupings. Ages are
+// measured from now.
+func NewTotals(by string, now time.Time) (*Totals, error) {
+	t := &Totals{by: by, now: now, groups: make(map[string]*Group)}
+	switch by {
+	case ByExtension, ByType, ByOwner:
+	case ByAge:
+		t.order = make(map[string]int, len(AgeBuckets))
+		for i, b := range AgeBuckets {
+			t.order[b.Name] = i
+		}
+	default:
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownGrouping, by, strings.Join(Groupings, ", "))
+	}
+	return t, nil
+}
+
+// Add counts a file in its group.
+func (t *Totals) Add(f File) {
+	key := t.key(f)
+	g, ok := t.groups[key]
+	if !ok {
+		g = &Group{Key: key}
+		t.groups[key] = g
+	}
+	g.Files++
+	g.Size += f.Size
+}
+
+// key returns the group f belongs to.
+func (t *Totals) key(f File) string {
+	switch t.by {
+	case ByExtension:
+		if ext := strings.ToLower(filepath.Ext(f.Path)); ext != "" {
+			return ext
+		}
+		return NoExtension
+	case ByType:
+		if f.Type != "" {
+			return f.Type
+		}
+		return filetype.FromExtension(f.Path)
+	case ByOwner:
+		if f.Owner != "" {
+			return f.Owner
+		}
+		return UnknownOwner
+	default:
+		return ageBucket(t.now.Sub(f.ModTime))
+	}
+}
+
+// ageBucket returns the name of the bucket a file of age falls in. Files
+// from the future count as new.
+func ageBucket(age time.Duration) string {
+	for _, b := range AgeBuckets {
+		if b.MaxAge == 0 || age < b.MaxAge {
+			return b.Name
+		}
+	}
+	return AgeBuckets[len(AgeBuckets)-1].Name
+}
+
+// Groups returns the groups, largest first, or youngest first for ByAge.
+func (t *Totals) Groups() []Group {
+	groups := make([]Group, 0, len(t.groups))
+	for _, g := range t.groups {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if t.order != nil {
+			return t.order[a.Key] < t.order[b.Key]
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return a.Key < b.Key
+	})
+	return groups
+}
@@ -0,0 +1,99 @@
+package summary
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jamesainslie/sweep/pkg/sweep/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var files = []File{
+	{Path: "/m/a.MKV", Size: 800, ModTime: now.Add(-2 * day), Owner: "alice", Type: "Video"},
+	{Path: "/m/b.mkv", Size: 400, ModTime: now.Add(-400 * day), Owner: "alice"},
+	{Path: "/m/notes.txt", Size: 100, ModTime: now.Add(-40 * day), Owner: "bob"},
+	{Path: "/m/Makefile", Size: 100, ModTime: now.Add(-10 * 365 * day)},
+}
+
+func groups(t *testing.T, by string) []Group {
+	t.Helper()
+	totals, err := NewTotals(by, now)
+	require.NoError(t, err)
+	for _, f := range files {
+		totals.Add(f)
+	}
+	return totals.Groups()
+}
+
+func TestGroups(t *testing.T) {
+	assert.Equal(t, []Group{
+		{Key: ".mkv", Files: 2, Size: 1200},
+		{Key: NoExtension, Files: 1, Size: 100},
+		{Key: ".txt", Files: 1, Size: 100},
+	}, groups(t, ByExtension), "extensions are case-insensitive, ties by key")
+
+	assert.Equal(t, []Group{
+		{Key: "Video", Files: 2, Size: 1200},
+		{Key: "File", Files: 1, Size: 100},
+		{Key: "Text", Files: 1, Size: 100},
+	}, groups(t, ByType), "a missing type comes from the extension")
+
+	assert.Equal(t, []Group{
+		{Key: "alice", Files: 2, Size: 1200},
+		{Key: UnknownOwner, Files: 1, Size: 100},
+		{Key: "bob", Files: 1, Size: 100},
+	}, groups(t, ByOwner))
+
+	assert.Equal(t, []Group{
+		{Key: "< 1 week", Files: 1, Size: 800},
+		{Key: "1 - 6 months", Files: 1, Size: 100},
+		{Key: "1 - 2 years", Files: 1, Size: 400},
+		{Key: "> 5 years", Files: 1, Size: 100},
+	}, groups(t, ByAge), "age buckets are in age order")
+}
+
+func TestAgeBucket(t *testing.T) {
+	assert.Equal(t, "< 1 week", ageBucket(-time.Hour), "files from the future count as new")
+	assert.Equal(t, "1 week - 1 month", ageBucket(7*day))
+	assert.Equal(t, "> 5 years", ageBucket(50*365*day))
+}
+
+func TestNewTotalsUnknownGrouping(t *testing.T) {
+	_, err := NewTotals("size", now)
+	assert.ErrorIs(t, err, ErrUnknownGrouping)
+}
+
+func TestWrite(t *testing.T) {
+	totals, err := NewTotals(ByType, now)
+	require.NoError(t, err)
+	for _, f := range files {
+		totals.Add(f)
+	}
+	report := totals.Report("/m")
+	assert.Equal(t, int64(4), report.Files)
+	assert.Equal(t, int64(1400), report.Size)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, output.ReportText, report))
+	assert.Contains(t, buf.String(), "TYPE")
+	assert.Contains(t, buf.String(), "85.7%")
+	assert.Contains(t, buf.String(), "4 file(s), 1.4 KiB")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportCSV, report))
+	assert.Equal(t, "key,files,size,size_human\nVideo,2,1200,1.2 KiB\nFile,1,100,100 B\nText,1,100,100 B\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, output.ReportJSON, Report{By: ByOwner}))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "owner", decoded["by"])
+	assert.Equal(t, []any{}, decoded["groups"])
+
+	assert.ErrorIs(t, Write(&buf, "yaml", report), output.ErrUnknownReportFormat)
+}
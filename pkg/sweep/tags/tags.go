@@ -0,0 +1,256 @@
+// Package tags provides user-defined virtual groupings of files and directories.
+// Tags are persisted to a JSON file in the state directory so that a set of
+// paths can be collected across multiple sessions before acting on them.
+package tags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrEmptyTag is returned when a tag name is empty or whitespace.
+var ErrEmptyTag = errors.New("tag name cannot be empty")
+
+// Summary describes a single tag and the paths assigned to it.
+type Summary struct {
+	Tag   string   `json:"tag"`
+	Paths []string `json:"paths"`
+}
+
+// Store manages tag assignments persisted to a JSON file.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	tags map[string][]string // tag -> sorted, deduplicated paths
+}
+
+// fileFormat is the on-disk representation of the tag store.
+type fileFormat struct {
+	Tags map[string][]string `json:"tags"`
+}
+
+// Open loads the tag store from path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("tags file path cannot be empty")
+	}
+
+	s := &Store{path: path, tags: make(map[string][]string)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read tags file: %w", err)
+	}
+
+	var ff fileFormat
+	if err := json.Unmarshal(data, &ff); err != nil {
+		return nil, fmt.Errorf("failed to parse tags file: %w", err)
+	}
+	for tag, paths := range ff.Tags {
+		if len(paths) > 0 {
+			s.tags[tag] = normalizePaths(paths)
+		}
+	}
+
+	return s, nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add assigns tag to each of paths and persists the store.
+func (s *Store) Add(tag string, paths ...string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tags[tag] = normalizePaths(append(s.tags[tag], paths...))
+	return s.save()
+}
+
+// Remove unassigns tag from each of paths and persists the store.
+// The tag itself is dropped once it has no remaining paths.
+func (s *Store) Remove(tag string, paths ...string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drop := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		drop[filepath.Clean(p)] = true
+	}
+
+	var kept []string
+	for _, p := range s.tags[tag] {
+		if !drop[p] {
+			kept = append(kept, p)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(s.tags, tag)
+	} else {
+		s.tags[tag] = kept
+	}
+	return s.save()
+}
+
+// Delete removes a tag and all of its assignments.
+func (s *Store) Delete(tag string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tags[tag]; !ok {
+		return fmt.Errorf("tag not found: %s", tag)
+	}
+	delete(s.tags, tag)
+	return s.save()
+}
+
+// Names returns all tag names in alphabetical order.
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.tags))
+	for tag := range s.tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Paths returns the paths directly assigned to tag.
+func (s *Store) Paths(tag string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := s.tags[strings.TrimSpace(tag)]
+	out := make([]string, len(paths))
+	copy(out, paths)
+	return out
+}
+
+// TagsFor returns the tags that apply to path, either because path itself is
+// tagged or because one of its ancestor directories is.
+func (s *Store) TagsFor(path string) []string {
+	path = filepath.Clean(path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []string
+	for tag, paths := range s.tags {
+		for _, p := range paths {
+			if Covers(p, path) {
+				result = append(result, tag)
+				break
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Summary returns every tag with its assigned paths, sorted by tag name.
+func (s *Store) Summary() []Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Summary, 0, len(s.tags))
+	for tag, paths := range s.tags {
+		cp := make([]string, len(paths))
+		copy(cp, paths)
+		result = append(result, Summary{Tag: tag, Paths: cp})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// save writes the store to disk atomically. Caller must hold s.mu.
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create tags directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Tags: s.tags}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// Covers reports whether tagged is path itself or one of its ancestors.
+func Covers(tagged, path string) bool {
+	if tagged == path {
+		return true
+	}
+	prefix := tagged
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, prefix)
+}
+
+// normalizeTag trims whitespace and rejects empty names.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", ErrEmptyTag
+	}
+	return tag, nil
+}
+
+// normalizePaths cleans, deduplicates, and sorts paths.
+func normalizePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,144 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file yields empty store", func(t *testing.T) {
+		t.Parallel()
+		s, err := Open(filepath.Join(t.TempDir(), "tags.json"))
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if got := s.Names(); len(got) != 0 {
+			t.Errorf("Names() = %v, want empty", got)
+		}
+	})
+
+	t.Run("empty path is an error", func(t *testing.T) {
+		t.Parallel()
+		if _, err := Open(""); err == nil {
+			t.Fatal("Open(\"\") error = nil, want error")
+		}
+	})
+
+	t.Run("corrupt file is an error", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "tags.json")
+		if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(path); err == nil {
+			t.Fatal("Open() error = nil, want parse error")
+		}
+	})
+}
+
+func TestStore_AddPersists(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "state", "tags.json")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := s.Add("to-review", "/data/b.iso", "/data/a.iso", "/data/a.iso/"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	want := []string{"/data/a.iso", "/data/b.iso"}
+	if got := reopened.Paths("to-review"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestStore_AddEmptyTag(t *testing.T) {
+	t.Parallel()
+	s, _ := Open(filepath.Join(t.TempDir(), "tags.json"))
+	if err := s.Add("  ", "/x"); err != ErrEmptyTag {
+		t.Errorf("Add() error = %v, want ErrEmptyTag", err)
+	}
+}
+
+func TestStore_Remove(t *testing.T) {
+	t.Parallel()
+	s, _ := Open(filepath.Join(t.TempDir(), "tags.json"))
+	_ = s.Add("old", "/a", "/b")
+
+	if err := s.Remove("old", "/a"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := s.Paths("old"); !reflect.DeepEqual(got, []string{"/b"}) {
+		t.Errorf("Paths() = %v, want [/b]", got)
+	}
+
+	if err := s.Remove("old", "/b"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got := s.Names(); len(got) != 0 {
+		t.Errorf("Names() = %v, want tag dropped once empty", got)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	t.Parallel()
+	s, _ := Open(filepath.Join(t.TempDir(), "tags.json"))
+	_ = s.Add("x", "/a")
+
+	if err := s.Delete("x"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("x"); err == nil {
+		t.Error("Delete() of missing tag error = nil, want error")
+	}
+}
+
+func TestStore_TagsFor(t *testing.T) {
+	t.Parallel()
+	s, _ := Open(filepath.Join(t.TempDir(), "tags.json"))
+	_ = s.Add("project", "/work/proj")
+	_ = s.Add("video", "/work/proj/clip.mp4")
+	_ = s.Add("other", "/work/projector")
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/work/proj/clip.mp4", []string{"project", "video"}},
+		{"/work/proj/sub/file.bin", []string{"project"}},
+		{"/work/proj", []string{"project"}},
+		{"/work/projector/x", []string{"other"}},
+		{"/elsewhere", nil},
+	}
+	for _, tt := range tests {
+		if got := s.TagsFor(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TagsFor(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestStore_Summary(t *testing.T) {
+	t.Parallel()
+	s, _ := Open(filepath.Join(t.TempDir(), "tags.json"))
+	_ = s.Add("b", "/2")
+	_ = s.Add("a", "/1", "/3")
+
+	got := s.Summary()
+	want := []Summary{
+		{Tag: "a", Paths: []string{"/1", "/3"}},
+		{Tag: "b", Paths: []string{"/2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Delete backends, chosen per operation with --backend or for all of them
+// with trash.backend.
+const (
+	BackendTrash     = "trash"     // The system trash (default)
+	BackendPermanent = "permanent" // Removed without the trash
+	BackendStaging   = "staging"   // Kept in a staging directory, then purged
+	BackendUpload    = "upload"    // Removed once a command has uploaded it
+)
+
+// ErrUnknownBackend is returned by NewBackend for unsupported backends.
+var ErrUnknownBackend = errors.New("unknown delete backend")
+
+// Backend deletes files in one way. DeleteMany returns results in the
+// order of paths and calls onDone, if set, once per path as each finishes;
+// calls are serialized. Paths not yet processed when ctx is cancelled fail
+// with the context's error.
+type Backend interface {
+	Name() string
+	DeleteMany(ctx context.Context, paths []string, onDone func(Result)) []Result
+}
+
+// BackendOptions configure the backends that need more than a name.
+type BackendOptions struct {
+	Staging Staging // Where the staging backend keeps files, and how long
+	Upload  Upload  // The command the upload backend runs per file
+
+	// PermanentRoots are the only directories the permanent backend
+	// deletes in; it refuses every other path.
+	PermanentRoots PermanentRoots
+}
+
+// ParseBackend checks a backend name, returning it in lower case; "" is
+// the trash.
+func ParseBackend(name string) (string, error) {
+	switch name := strings.ToLower(name); name {
+	case "":
+		return BackendTrash, nil
+	case BackendTrash, BackendPermanent, BackendStaging, BackendUpload:
+		return name, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: %s, %s, %s, %s)", ErrUnknownBackend, name,
+			BackendTrash, BackendPermanent, BackendStaging, BackendUpload)
+	}
+}
+
+// NewBackend returns the named backend; "" is the trash. Backends that
+// need options fail until they are set.
+func NewBackend(name string, opts BackendOptions) (Backend, error) {
+	name, err := ParseBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case BackendTrash:
+		return funcBackend{name: BackendTrash, deleteMany: MoveManyToTrash}, nil
+	case BackendPermanent:
+		if len(opts.PermanentRoots) == 0 {
+			return nil, errors.New("the permanent backend needs trash.permanent_roots in the config to say where files may be deleted permanently")
+		}
+		return Permanent{Roots: opts.PermanentRoots}, nil
+	case BackendStaging:
+		if opts.Staging.Dir == "" {
+			return nil, errors.New("the staging backend has no directory: set trash.staging.dir in the config")
+		}
+		return opts.Staging, nil
+	case BackendUpload:
+		if opts.Upload.Command == "" {
+			return nil, errors.New("the upload backend needs trash.upload.command in the config")
+		}
+		return opts.Upload, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownBackend, name)
+}
+
+// funcBackend adapts MoveManyToTrash to Backend.
+type funcBackend struct {
+	name       string
+	deleteMany func(context.Context, []string, func(Result)) []Result
+}
+
+// Name returns the backend's name.
+func (b funcBackend) Name() string { return b.name }
+
+// DeleteMany deletes paths with the backend's function.
+func (b funcBackend) DeleteMany(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	return b.deleteMany(ctx, paths, onDone)
+}
+
+// deleteEach deletes paths one at a time with del, as Backend.DeleteMany
+// requires.
+func deleteEach(ctx context.Context, paths []string, onDone func(Result), del func(path string) Result) []Result {
+	results := make([]Result, len(paths))
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			results[i] = Result{Path: path, Err: err}
+		} else {
+			results[i] = del(path)
+		}
+		if onDone != nil {
+			onDone(results[i])
+		}
+	}
+	return results
+}
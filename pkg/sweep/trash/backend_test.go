@@ -0,0 +1,131 @@
+package trash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBackend(t *testing.T) {
+	roots := NewPermanentRoots([]string{t.TempDir()})
+	for _, name := range []string{"", "Trash", "permanent"} {
+		b, err := NewBackend(name, BackendOptions{PermanentRoots: roots})
+		require.NoError(t, err, name)
+		want, _ := ParseBackend(name)
+		assert.Equal(t, want, b.Name())
+	}
+
+	_, err := NewBackend("s3", BackendOptions{})
+	assert.ErrorIs(t, err, ErrUnknownBackend)
+	_, err = NewBackend(BackendStaging, BackendOptions{})
+	assert.ErrorContains(t, err, "trash.staging.dir")
+	_, err = NewBackend(BackendUpload, BackendOptions{})
+	assert.ErrorContains(t, err, "trash.upload.command")
+	_, err = NewBackend(BackendPermanent, BackendOptions{})
+	assert.ErrorContains(t, err, "trash.permanent_roots")
+
+	b, err := NewBackend(BackendStaging, BackendOptions{Staging: Staging{Dir: t.TempDir()}})
+	require.NoError(t, err)
+	assert.Equal(t, BackendStaging, b.Name())
+}
+
+func TestStaging(t *testing.T) {
+	dir := t.TempDir()
+	staging := Staging{Dir: filepath.Join(dir, "staging"), Days: 7}
+	file := filepath.Join(dir, "home", "disk.iso")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+
+	var done []Result
+	results := staging.DeleteMany(context.Background(), []string{file, filepath.Join(dir, "missing")}, func(r Result) {
+		done = append(done, r)
+	})
+	require.Len(t, results, 2)
+	assert.Equal(t, results, done)
+	require.NoError(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	assert.NoFileExists(t, file)
+
+	day := filepath.Join(staging.Dir, time.Now().Format(stagingDay))
+	assert.Equal(t, filepath.Join(day, stagedRel(file)), results[0].Location)
+	assert.FileExists(t, results[0].Location)
+
+	// A second file with the same path doesn't overwrite the first
+	require.NoError(t, os.WriteFile(file, []byte("more"), 0o644))
+	again := staging.DeleteMany(context.Background(), []string{file}, nil)
+	require.NoError(t, again[0].Err)
+	assert.Equal(t, results[0].Location+".2", again[0].Location)
+
+	// Files already staged are refused
+	refused := staging.DeleteMany(context.Background(), []string{again[0].Location}, nil)
+	assert.ErrorContains(t, refused[0].Err, "already in the staging directory")
+
+	// Staged files restore to where they were
+	it, err := StagedItem(file, results[0].Location, time.Now())
+	require.NoError(t, err)
+	assert.True(t, it.Staged)
+	assert.Equal(t, int64(4), it.Size)
+	require.NoError(t, Restore(it, file))
+	got, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(got))
+
+	_, err = StagedItem(file, results[0].Location, time.Now())
+	assert.ErrorIs(t, err, ErrNotInTrash)
+}
+
+func TestPurgeStaging(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	for _, day := range []string{"2026-10-08", "2026-10-10", "2026-10-15"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, day, "home"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, day, "home", "a.bin"), []byte("12345"), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o644))
+
+	freed, err := PurgeStaging(dir, 0, now)
+	require.NoError(t, err)
+	assert.Zero(t, freed, "0 days keeps everything")
+
+	freed, err = PurgeStaging(dir, 7, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), freed)
+	assert.NoDirExists(t, filepath.Join(dir, "2026-10-08"), "expired after its 7 days")
+	assert.DirExists(t, filepath.Join(dir, "2026-10-10"))
+	assert.DirExists(t, filepath.Join(dir, "2026-10-15"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	freed, err = PurgeStaging(filepath.Join(dir, "missing"), 7, now)
+	require.NoError(t, err)
+	assert.Zero(t, freed)
+}
+
+func TestUpload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "archive")
+	require.NoError(t, os.Mkdir(archive, 0o755))
+	file := filepath.Join(dir, "disk.iso")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+
+	upload := Upload{Command: `test "$SWEEP_SIZE" = 4 && cp "$SWEEP_PATH" ` + archive}
+	results := upload.DeleteMany(context.Background(), []string{file}, nil)
+	require.NoError(t, results[0].Err)
+	assert.NoFileExists(t, file)
+	assert.FileExists(t, filepath.Join(archive, "disk.iso"))
+
+	// A failed upload keeps the file
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+	failing := Upload{Command: "echo no network >&2; exit 1"}
+	results = failing.DeleteMany(context.Background(), []string{file}, nil)
+	assert.ErrorContains(t, results[0].Err, "no network")
+	assert.FileExists(t, file)
+}
@@ -0,0 +1,173 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxBatchSize caps the number of paths passed to one trash command,
+	// keeping command lines and AppleScript lists reasonably sized.
+	maxBatchSize = 200
+
+	// batchWorkers is the number of directories trashed concurrently.
+	batchWorkers = 4
+)
+
+// errNoBatch reports that the platform has no batch trash command.
+var errNoBatch = errors.New("batch trash not supported")
+
+// Result is the outcome of trashing one path.
+type Result struct {
+	Path     string
+	Err      error
+	Location string // Where the staging backend moved the path
+}
+
+// batch is a group of paths sharing a parent directory.
+type batch struct {
+	indexes []int    // Positions in the caller's path list
+	paths   []string // Absolute paths
+}
+
+// MoveManyToTrash moves paths to the system trash, issuing one trash command
+// per parent directory instead of one per file and working on several
+// directories at once. This avoids the per-call overhead of Finder and gio,
+// which dominates when deleting hundreds of files. On Windows each batch
+// is one shell operation.
+//
+// Results are returned in the order of paths. If onDone is set, it is called
+// once per path as each finishes; calls are serialized. Paths not yet
+// processed when ctx is cancelled fail with the context's error.
+func MoveManyToTrash(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	results := make([]Result, len(paths))
+	var mu sync.Mutex
+	finish := func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = Result{Path: paths[i], Err: err}
+		if onDone != nil {
+			onDone(results[i])
+		}
+	}
+
+	batches := groupBatches(paths, finish)
+
+	work := make(chan batch)
+	var wg sync.WaitGroup
+	for range min(batchWorkers, len(batches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range work {
+				if err := ctx.Err(); err != nil {
+					for _, i := range b.indexes {
+						finish(i, err)
+					}
+					continue
+				}
+				trashBatch(ctx, b, finish)
+			}
+		}()
+	}
+	for _, b := range batches {
+		work <- b
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
+
+// groupBatches groups paths by parent directory, in order of first
+// appearance, splitting large directories into batches of maxBatchSize.
+// Paths that do not exist or cannot be resolved are finished immediately.
+func groupBatches(paths []string, finish func(int, error)) []batch {
+	var batches []batch
+	open := make(map[string]int) // Parent directory -> index of its open batch
+
+	for i, path := range paths {
+		if _, err := os.Lstat(path); err != nil {
+			finish(i, fmt.Errorf("cannot trash %q: %w", path, err))
+			continue
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			finish(i, fmt.Errorf("cannot resolve absolute path for %q: %w", path, err))
+			continue
+		}
+
+		dir := filepath.Dir(absPath)
+		n, ok := open[dir]
+		if !ok || len(batches[n].paths) >= maxBatchSize {
+			batches = append(batches, batch{})
+			n = len(batches) - 1
+			open[dir] = n
+		}
+		batches[n].indexes = append(batches[n].indexes, i)
+		batches[n].paths = append(batches[n].paths, absPath)
+	}
+	return batches
+}
+
+// trashBatch trashes one batch with a single command. If the command fails
+// or is unavailable, paths still present are trashed one at a time so each
+// gets an accurate result.
+func trashBatch(ctx context.Context, b batch, finish func(int, error)) {
+	if len(b.paths) > 1 {
+		if err := runBatchCommand(ctx, b.paths); err == nil {
+			for _, i := range b.indexes {
+				finish(i, nil)
+			}
+			return
+		}
+	}
+
+	for n, i := range b.indexes {
+		if _, err := os.Lstat(b.paths[n]); os.IsNotExist(err) {
+			// Trashed by a partially successful batch command.
+			finish(i, nil)
+			continue
+		}
+		finish(i, MoveToTrash(b.paths[n]))
+	}
+}
+
+// runBatchCommand trashes several paths with one platform command.
+func runBatchCommand(ctx context.Context, paths []string) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	switch runtime.GOOS {
+	case "darwin":
+		files := make([]string, len(paths))
+		for i, p := range paths {
+			files[i] = fmt.Sprintf("POSIX file %q", p)
+		}
+		script := fmt.Sprintf(`tell application "Finder" to delete {%s}`, strings.Join(files, ", "))
+		return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+	case "linux":
+		if gioPath, err := exec.LookPath("gio"); err == nil {
+			args := append([]string{"trash", "--"}, paths...)
+			if err := exec.CommandContext(ctx, gioPath, args...).Run(); err == nil {
+				return nil
+			}
+		}
+		if trashPath, err := exec.LookPath("trash-put"); err == nil {
+			args := append([]string{"--"}, paths...)
+			return exec.CommandContext(ctx, trashPath, args...).Run()
+		}
+		return errNoBatch
+	case "windows":
+		return moveToRecycleBin(paths...)
+	default:
+		return errNoBatch
+	}
+}
@@ -0,0 +1,91 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, n int) []string {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	paths := make([]string, n)
+	for i := range n {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("file%03d.bin", i))
+		require.NoError(t, os.WriteFile(paths[i], []byte("data"), 0644))
+	}
+	return paths
+}
+
+func TestGroupBatches(t *testing.T) {
+	root := t.TempDir()
+	a := writeFiles(t, filepath.Join(root, "a"), maxBatchSize+5)
+	b := writeFiles(t, filepath.Join(root, "b"), 2)
+	missing := filepath.Join(root, "a", "missing.bin")
+
+	paths := []string{b[0], missing}
+	paths = append(paths, a...)
+	paths = append(paths, b[1])
+
+	var finished []int
+	batches := groupBatches(paths, func(i int, err error) {
+		assert.Error(t, err)
+		finished = append(finished, i)
+	})
+
+	assert.Equal(t, []int{1}, finished, "missing path should finish immediately")
+	require.Len(t, batches, 3)
+	assert.Equal(t, []int{0, len(paths) - 1}, batches[0].indexes, "b groups first, in order")
+	assert.Len(t, batches[1].paths, maxBatchSize)
+	assert.Len(t, batches[2].paths, 5)
+	assert.Equal(t, a[maxBatchSize], batches[2].paths[0])
+}
+
+func TestMoveManyToTrash(t *testing.T) {
+	root := t.TempDir()
+	paths := writeFiles(t, filepath.Join(root, "a"), 5)
+	paths = append(paths, writeFiles(t, filepath.Join(root, "b"), 3)...)
+	missing := filepath.Join(root, "gone.bin")
+	paths = append(paths, missing)
+
+	var done []string
+	results := MoveManyToTrash(context.Background(), paths, func(r Result) {
+		done = append(done, r.Path)
+	})
+
+	require.Len(t, results, len(paths))
+	assert.Len(t, done, len(paths), "onDone should be called once per path")
+	for i, r := range results {
+		assert.Equal(t, paths[i], r.Path)
+		if r.Path == missing {
+			assert.Error(t, r.Err)
+			continue
+		}
+		assert.NoError(t, r.Err)
+		_, err := os.Stat(r.Path)
+		assert.True(t, os.IsNotExist(err), "%s should be gone", r.Path)
+	}
+}
+
+func TestMoveManyToTrash_Cancelled(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := MoveManyToTrash(ctx, paths, nil)
+	for _, r := range results {
+		assert.ErrorIs(t, r.Err, context.Canceled)
+		_, err := os.Stat(r.Path)
+		assert.NoError(t, err, "cancelled paths should be left in place")
+	}
+}
+
+func TestMoveManyToTrash_Empty(t *testing.T) {
+	assert.Empty(t, MoveManyToTrash(context.Background(), nil, nil))
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package trash
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// changeTime returns the inode change time of a file, which moving a file
+// into the trash updates.
+func changeTime(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	return time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec)
+}
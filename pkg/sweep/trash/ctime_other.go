@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package trash
+
+import (
+	"io/fs"
+	"time"
+)
+
+// changeTime falls back to the modification time where the change time is
+// not available.
+func changeTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
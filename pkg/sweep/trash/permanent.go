@@ -0,0 +1,101 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotPermanentRoot is returned by the permanent backend for paths
+// outside its roots, which it refuses to delete.
+var ErrNotPermanentRoot = errors.New("not under trash.permanent_roots, refusing to delete permanently")
+
+// PermanentRoots are the directories under which files may be deleted
+// permanently instead of trashed (trash.permanent_roots in the config).
+// Huge files there would only bloat the trash volume until it is emptied.
+type PermanentRoots []string
+
+// NewPermanentRoots cleans the configured roots and resolves their
+// symlinks. Roots must be absolute; expand ~ before calling.
+func NewPermanentRoots(roots []string) PermanentRoots {
+	cleaned := make(PermanentRoots, 0, len(roots))
+	for _, root := range roots {
+		if root != "" && filepath.IsAbs(root) {
+			cleaned = append(cleaned, resolvePath(filepath.Clean(root)))
+		}
+	}
+	return cleaned
+}
+
+// Allows reports whether path is inside one of the roots. A root itself
+// can't be deleted permanently, only what is in it. Symlinks in path's
+// directory are resolved first, so a link inside a root to a directory
+// outside it doesn't let what is there be deleted permanently; a link
+// named by path itself is only the link.
+func (r PermanentRoots) Allows(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	path = filepath.Join(resolvePath(filepath.Dir(path)), filepath.Base(path))
+	for _, root := range r {
+		prefix := root
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permanent is the permanent backend: files under Roots are deleted
+// without the trash, and all others are refused.
+type Permanent struct {
+	Roots PermanentRoots
+}
+
+// Name returns BackendPermanent.
+func (p Permanent) Name() string { return BackendPermanent }
+
+// DeleteMany permanently deletes the paths under the roots, failing the
+// rest with ErrNotPermanentRoot.
+func (p Permanent) DeleteMany(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	return deleteEach(ctx, paths, onDone, func(path string) Result {
+		if !p.Roots.Allows(path) {
+			return Result{Path: path, Err: fmt.Errorf("%s: %w", path, ErrNotPermanentRoot)}
+		}
+		return Result{Path: path, Err: Remove(path)}
+	})
+}
+
+// resolvePath resolves the symlinks in path's longest existing ancestor,
+// keeping the rest, which doesn't exist and so holds no links, as it is.
+func resolvePath(path string) string {
+	var rest []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			for i := len(rest) - 1; i >= 0; i-- {
+				resolved = filepath.Join(resolved, rest[i])
+			}
+			return resolved
+		}
+		if filepath.Dir(dir) == dir {
+			return path
+		}
+		rest = append(rest, filepath.Base(dir))
+	}
+}
+
+// RemoveMany permanently deletes paths, bypassing the trash. It mirrors
+// MoveManyToTrash: results are returned in the order of paths, onDone is
+// called once per path, and paths not yet deleted when ctx is cancelled
+// fail with the context's error.
+func RemoveMany(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	return deleteEach(ctx, paths, onDone, func(path string) Result {
+		return Result{Path: path, Err: Remove(path)}
+	})
+}
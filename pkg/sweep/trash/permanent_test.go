@@ -0,0 +1,78 @@
+package trash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermanentRootsAllows(t *testing.T) {
+	roots := NewPermanentRoots([]string{"/scratch/", "/data/isos", "relative", ""})
+	assert.Equal(t, PermanentRoots{"/scratch", "/data/isos"}, roots, "relative and empty roots are dropped")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/scratch/render.mov", true},
+		{"/data/isos/old/disc.iso", true},
+		{"/data/isos", false},
+		{"/data/isos2/disc.iso", false},
+		{"/data/isos/../photos/a.raw", false},
+		{"scratch/render.mov", false},
+		{"/home/me/render.mov", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, roots.Allows(tt.path), tt.path)
+	}
+	assert.False(t, PermanentRoots(nil).Allows("/scratch/render.mov"))
+}
+
+func TestPermanentRootsAllowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	root, home := filepath.Join(dir, "scratch"), filepath.Join(dir, "home")
+	require.NoError(t, os.Mkdir(root, 0o755))
+	require.NoError(t, os.Mkdir(home, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "thesis.doc"), []byte("data"), 0o644))
+	require.NoError(t, os.Symlink(home, filepath.Join(root, "link")))
+	require.NoError(t, os.Symlink(root, filepath.Join(dir, "scratch-link")))
+
+	roots := NewPermanentRoots([]string{root})
+	assert.False(t, roots.Allows(filepath.Join(root, "link", "thesis.doc")), "a linked directory outside the root")
+	assert.True(t, roots.Allows(filepath.Join(root, "link")), "the link itself is inside the root")
+	assert.True(t, roots.Allows(filepath.Join(root, "render.mov")))
+	assert.True(t, roots.Allows(filepath.Join(dir, "scratch-link", "render.mov")), "a link to the root")
+
+	linked := NewPermanentRoots([]string{filepath.Join(dir, "scratch-link")})
+	assert.True(t, linked.Allows(filepath.Join(root, "render.mov")), "a root that is a link")
+	assert.False(t, linked.Allows(filepath.Join(home, "thesis.doc")))
+}
+
+func TestRemoveMany(t *testing.T) {
+	dir := t.TempDir()
+	file, sub := filepath.Join(dir, "big.iso"), filepath.Join(dir, "build")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(sub, "obj"), 0o755))
+
+	var done []string
+	results := RemoveMany(context.Background(), []string{file, sub}, func(r Result) {
+		done = append(done, r.Path)
+	})
+	assert.Equal(t, []string{file, sub}, done)
+	for _, r := range results {
+		assert.NoError(t, r.Err)
+		assert.NoFileExists(t, r.Path)
+		assert.NoDirExists(t, r.Path)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o644))
+	results = RemoveMany(ctx, []string{file}, nil)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+	assert.FileExists(t, file)
+}
@@ -0,0 +1,97 @@
+package trash
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// Quota limits how large each volume's trash may grow. Zero means unlimited.
+type Quota struct {
+	Default int64            // Applies to every volume without an override
+	Volumes map[string]int64 // Overrides by mount point
+}
+
+// Enabled reports whether any volume has a limit.
+func (q Quota) Enabled() bool {
+	if q.Default > 0 {
+		return true
+	}
+	for _, limit := range q.Volumes {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns the quota of a volume, or 0 if it is unlimited.
+func (q Quota) Limit(v Volume) int64 {
+	for mount, limit := range q.Volumes {
+		if filepath.Clean(mount) == v.Mount {
+			return limit
+		}
+	}
+	return q.Default
+}
+
+// Overage describes a volume whose trash would exceed its quota if the
+// pending files were trashed.
+type Overage struct {
+	Volume   Volume
+	Used     int64    // Current size of the trash
+	Incoming int64    // Size of the pending files on this volume
+	Limit    int64    // The volume's quota
+	Paths    []string // Pending files on this volume
+}
+
+// Excess returns how far over quota the trash would be.
+func (o Overage) Excess() int64 {
+	return o.Used + o.Incoming - o.Limit
+}
+
+// Purgeable reports whether purging old trash can make room for the
+// pending files. When it can't, they can only be deleted permanently.
+func (o Overage) Purgeable() bool {
+	return o.Incoming <= o.Limit
+}
+
+// CheckQuota groups the files about to be trashed by volume and returns the
+// volumes whose trash would exceed the quota, ordered by mount point.
+// Files whose volume cannot be determined are not checked.
+func CheckQuota(q Quota, targets []Snapshot) ([]Overage, error) {
+	if !q.Enabled() {
+		return nil, nil
+	}
+
+	byDir := make(map[string]*Overage)
+	for _, t := range targets {
+		v, err := VolumeOf(t.Path)
+		if err != nil {
+			continue
+		}
+		o, ok := byDir[v.Dir]
+		if !ok {
+			o = &Overage{Volume: v, Limit: q.Limit(v)}
+			byDir[v.Dir] = o
+		}
+		o.Incoming += t.Size
+		o.Paths = append(o.Paths, t.Path)
+	}
+
+	var over []Overage
+	for _, o := range byDir {
+		if o.Limit <= 0 {
+			continue
+		}
+		used, err := Usage(o.Volume)
+		if err != nil {
+			return nil, err
+		}
+		o.Used = used
+		if o.Excess() > 0 {
+			over = append(over, *o)
+		}
+	}
+	sort.Slice(over, func(i, j int) bool { return over[i].Volume.Mount < over[j].Volume.Mount })
+	return over, nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package trash
+
+import "errors"
+
+// moveToRecycleBin is only available on Windows.
+func moveToRecycleBin(...string) error {
+	return errors.New("recycle bin not supported on this platform")
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// SHFileOperationW operations and flags.
+const (
+	foDelete          = 0x0003
+	fofSilent         = 0x0004
+	fofNoConfirmation = 0x0010
+	fofAllowUndo      = 0x0040
+	fofNoErrorUI      = 0x0400
+)
+
+var procSHFileOperationW = windows.NewLazySystemDLL("shell32.dll").NewProc("SHFileOperationW")
+
+// shFileOpStruct is SHFILEOPSTRUCTW.
+type shFileOpStruct struct {
+	hwnd                  uintptr
+	wFunc                 uint32
+	pFrom                 *uint16
+	pTo                   *uint16
+	fFlags                uint16
+	fAnyOperationsAborted int32
+	hNameMappings         uintptr
+	lpszProgressTitle     *uint16
+}
+
+// moveToRecycleBin moves paths to the Recycle Bin with one shell operation,
+// as deleting them in Explorer does, without showing any dialogs. Like
+// Explorer, the shell deletes permanently what the Recycle Bin can't hold,
+// such as files on network drives.
+func moveToRecycleBin(paths ...string) error {
+	if err := procSHFileOperationW.Find(); err != nil {
+		return err
+	}
+
+	// The paths are passed as one list, each ended by a NUL and the list
+	// by another
+	var from []uint16
+	for _, p := range paths {
+		u, err := windows.UTF16FromString(p)
+		if err != nil {
+			return fmt.Errorf("cannot trash %q: %w", p, err)
+		}
+		from = append(from, u...)
+	}
+	from = append(from, 0)
+
+	op := shFileOpStruct{
+		wFunc:  foDelete,
+		pFrom:  &from[0],
+		fFlags: fofAllowUndo | fofNoConfirmation | fofNoErrorUI | fofSilent,
+	}
+	if r, _, _ := procSHFileOperationW.Call(uintptr(unsafe.Pointer(&op))); r != 0 {
+		return fmt.Errorf("recycle bin: shell error %#x", r)
+	}
+	if op.fAnyOperationsAborted != 0 {
+		return errors.New("recycle bin: operation aborted")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotInTrash is returned when a deleted file cannot be found in its
+// volume's trash, for example because it was deleted permanently or the
+// trash has been emptied.
+var ErrNotInTrash = errors.New("not found in trash")
+
+// Find returns the trash item for a file that was at original before it
+// was trashed. When several items match, the one deleted closest to
+// deleted wins; a zero deleted picks the most recent.
+//
+// XDG trashes record each item's original path. The macOS trash does not,
+// so there an item matches by name and, when size is positive, by size.
+func Find(original string, size int64, deleted time.Time) (Item, error) {
+	// The file is gone, and its directory may be too, so find the volume
+	// from the closest directory that still exists
+	dir := existingAncestor(filepath.Dir(original))
+	v, err := VolumeOf(filepath.Join(dir, filepath.Base(original)))
+	if err != nil {
+		return Item{}, err
+	}
+	items, err := Items(v)
+	if err != nil {
+		return Item{}, err
+	}
+
+	closer := func(a, b Item) bool {
+		if deleted.IsZero() {
+			return a.Deleted.After(b.Deleted)
+		}
+		return a.Deleted.Sub(deleted).Abs() < b.Deleted.Sub(deleted).Abs()
+	}
+	var best Item
+	found := false
+	for _, it := range items {
+		if matches(it, original, size) && (!found || closer(it, best)) {
+			best, found = it, true
+		}
+	}
+	if !found {
+		return Item{}, fmt.Errorf("%s: %w", original, ErrNotInTrash)
+	}
+	return best, nil
+}
+
+// matches reports whether a trash item is the file that was at original.
+func matches(it Item, original string, size int64) bool {
+	if it.Original != "" {
+		return it.Original == original
+	}
+	// The macOS trash adds a suffix such as " 2" to names already in use
+	base := filepath.Base(original)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	name := it.Name
+	if name != base && !(strings.HasPrefix(name, stem+" ") && strings.HasSuffix(name, ext)) {
+		return false
+	}
+	return size <= 0 || it.Size == size
+}
+
+// Restore moves a trash item to dest, recreating dest's directory if it
+// was removed, and drops the item's .trashinfo record. It never replaces an
+// existing file: if dest exists it fails with fs.ErrExist.
+func Restore(it Item, dest string) error {
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("cannot restore to %q: %w", dest, os.ErrExist)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("cannot restore to %q: %w", dest, err)
+	}
+	if err := os.Rename(it.Path, dest); err != nil {
+		return fmt.Errorf("cannot restore %q: %w", it.Name, err)
+	}
+	if info := trashInfoPath(it); info != "" && !it.Staged {
+		_ = os.Remove(info)
+	}
+	return nil
+}
+
+// trashInfoPath returns the .trashinfo file for an item in an XDG trash,
+// or "" when the trash has none.
+func trashInfoPath(it Item) string {
+	filesDir := filepath.Dir(it.Path)
+	if filepath.Base(filesDir) != "files" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(filesDir), "info", it.Name+".trashinfo")
+}
@@ -0,0 +1,83 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAndRestore(t *testing.T) {
+	homeVolume(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "report.pdf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0o644))
+	require.NoError(t, moveToVolumeTrash(path))
+	// The file's directory may have been cleaned up since
+	require.NoError(t, os.Remove(filepath.Dir(path)))
+
+	it, err := Find(path, 7, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, path, it.Original)
+
+	require.NoError(t, Restore(it, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+
+	_, err = os.Stat(trashInfoPath(it))
+	assert.True(t, os.IsNotExist(err), "trashinfo should be removed")
+	_, err = Find(path, 7, time.Time{})
+	assert.ErrorIs(t, err, ErrNotInTrash)
+}
+
+func TestFindPicksClosestDeletion(t *testing.T) {
+	v := homeVolume(t)
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	for range 2 {
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o644))
+		require.NoError(t, moveToVolumeTrash(path))
+	}
+	items, err := Items(v)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	for _, want := range items {
+		it, err := Find(path, 0, want.Deleted)
+		require.NoError(t, err)
+		if !items[0].Deleted.Equal(items[1].Deleted) {
+			assert.Equal(t, want.Path, it.Path)
+		}
+	}
+}
+
+func TestRestoreRefusesExistingFile(t *testing.T) {
+	homeVolume(t)
+	path := filepath.Join(t.TempDir(), "a.txt")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o644))
+	require.NoError(t, moveToVolumeTrash(path))
+	require.NoError(t, os.WriteFile(path, []byte("new"), 0o644))
+
+	it, err := Find(path, 0, time.Time{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, Restore(it, path), os.ErrExist)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data), "existing file must be left alone")
+}
+
+func TestMatchesByName(t *testing.T) {
+	it := Item{Name: "photo 2.jpg", Size: 10}
+	assert.True(t, matches(it, "/Users/me/photo.jpg", 10))
+	assert.True(t, matches(it, "/Users/me/photo.jpg", 0))
+	assert.False(t, matches(it, "/Users/me/photo.jpg", 11))
+	assert.False(t, matches(it, "/Users/me/other.jpg", 10))
+
+	recorded := Item{Name: "photo.jpg", Original: "/a/photo.jpg"}
+	assert.False(t, matches(recorded, "/b/photo.jpg", 0))
+}
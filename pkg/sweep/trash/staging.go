@@ -0,0 +1,149 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// stagingDay is the layout of the per-day directories of a staging
+// directory.
+const stagingDay = "2006-01-02"
+
+// Staging is the staging backend: files are moved into a quarantine
+// directory instead of deleted, under a directory for the day they were
+// staged and at their original path, e.g.
+// <Dir>/2026-10-16/home/me/disk.iso. Days they were staged more than Days
+// days ago are purged before each delete; 0 keeps them until PurgeStaging
+// is run with a limit.
+//
+// Files are only renamed, never copied, so the directory must be on the
+// same volume as the files staged.
+type Staging struct {
+	Dir  string
+	Days int
+}
+
+// Name returns BackendStaging.
+func (s Staging) Name() string { return BackendStaging }
+
+// DeleteMany moves paths into the staging directory, purging expired days
+// first. Each result's Location is where the path was moved.
+func (s Staging) DeleteMany(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	now := time.Now()
+	// A purge that fails is tried again on the next delete
+	_, _ = PurgeStaging(s.Dir, s.Days, now)
+
+	day := filepath.Join(s.Dir, now.Format(stagingDay))
+	return deleteEach(ctx, paths, onDone, func(path string) Result {
+		dest, err := stage(s.Dir, day, path)
+		return Result{Path: path, Err: err, Location: dest}
+	})
+}
+
+// stage moves path to its place under day, returning where it went. A name
+// already taken gets a ".2", ".3", ... suffix.
+func stage(dir, day, path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve absolute path for %q: %w", path, err)
+	}
+	if _, err := os.Lstat(absPath); err != nil {
+		return "", fmt.Errorf("cannot stage %q: %w", path, err)
+	}
+	if absDir, err := filepath.Abs(dir); err == nil && (absPath == absDir || strings.HasPrefix(absPath, absDir+string(filepath.Separator))) {
+		return "", fmt.Errorf("%s is already in the staging directory", path)
+	}
+
+	base := filepath.Join(day, stagedRel(absPath))
+	if err := os.MkdirAll(filepath.Dir(base), 0o700); err != nil {
+		return "", fmt.Errorf("cannot stage %q: %w", path, err)
+	}
+	for n := 1; n < 1000; n++ {
+		dest := base
+		if n > 1 {
+			dest = fmt.Sprintf("%s.%d", base, n)
+		}
+		if _, err := os.Lstat(dest); err == nil {
+			continue
+		}
+		err := os.Rename(absPath, dest)
+		if errors.Is(err, syscall.EXDEV) {
+			return "", fmt.Errorf("cannot stage %q: the staging directory %s is on another volume", path, dir)
+		}
+		if err != nil {
+			return "", fmt.Errorf("cannot stage %q: %w", path, err)
+		}
+		return dest, nil
+	}
+	return "", fmt.Errorf("no free name in the staging directory for %q", path)
+}
+
+// stagedRel returns an absolute path relative to a staging day, keeping
+// Windows drive letters as a directory.
+func stagedRel(absPath string) string {
+	vol := filepath.VolumeName(absPath)
+	rel := strings.TrimLeft(absPath[len(vol):], `/\`)
+	if vol != "" {
+		rel = filepath.Join(strings.TrimSuffix(vol, ":"), rel)
+	}
+	return rel
+}
+
+// PurgeStaging permanently deletes the days in a staging directory staged
+// more than days days before now, returning the bytes freed. days 0 purges
+// nothing; a missing directory has nothing to purge.
+func PurgeStaging(dir string, days int, now time.Time) (int64, error) {
+	if dir == "" || days <= 0 {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := now.AddDate(0, 0, -days)
+	var freed int64
+	for _, e := range entries {
+		staged, err := time.ParseInLocation(stagingDay, e.Name(), now.Location())
+		if err != nil || !e.IsDir() || !staged.AddDate(0, 0, 1).Before(cutoff) {
+			continue // Not a day, or one not yet expired
+		}
+		path := filepath.Join(dir, e.Name())
+		size := treeSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			return freed, fmt.Errorf("failed to purge %s: %w", path, err)
+		}
+		freed += size
+	}
+	return freed, nil
+}
+
+// StagedItem returns the item the staging backend moved original to, at
+// location. It fails with ErrNotInTrash once the item has been purged.
+func StagedItem(original, location string, deleted time.Time) (Item, error) {
+	info, err := os.Lstat(location)
+	if err != nil {
+		return Item{}, fmt.Errorf("%s: %w", original, ErrNotInTrash)
+	}
+	size := info.Size()
+	if info.IsDir() {
+		size = treeSize(location)
+	}
+	return Item{
+		Name:     filepath.Base(location),
+		Path:     location,
+		Original: original,
+		Size:     size,
+		Deleted:  deleted,
+		Staged:   true,
+	}, nil
+}
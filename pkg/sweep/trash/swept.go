@@ -0,0 +1,96 @@
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Record is a file sweep moved to the trash, as recorded in the history.
+type Record struct {
+	Path    string    // Where the file was
+	Size    int64     // Size when deleted
+	Deleted time.Time // When it was deleted
+	Entry   string    // ID of the history entry that recorded it
+}
+
+// Trashed is an item sweep moved to the trash that is still there.
+type Trashed struct {
+	Item
+	Volume Volume // The volume whose trash holds the item
+	Entry  string // ID of the history entry that recorded the deletion
+}
+
+// FindTrashed returns the items still in the trash for records, oldest
+// first. Each volume's trash is read once, and each item is matched to at
+// most one record, the one deleted closest to it, so a path deleted twice
+// finds both copies. Records whose item is gone, because it was restored,
+// emptied, or deleted permanently, are left out. Items sweep didn't put in
+// the trash are never returned.
+func FindTrashed(records []Record) ([]Trashed, error) {
+	type volumeItems struct {
+		volume  Volume
+		items   []Item
+		claimed []bool
+	}
+	volumes := make(map[string]*volumeItems) // By trash directory
+
+	var found []Trashed
+	for _, rec := range records {
+		// The file is gone, and its directory may be too
+		dir := existingAncestor(filepath.Dir(rec.Path))
+		v, err := VolumeOf(filepath.Join(dir, filepath.Base(rec.Path)))
+		if errors.Is(err, ErrNoVolumeTrash) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		vi, ok := volumes[v.Dir]
+		if !ok {
+			items, err := Items(v)
+			if err != nil {
+				return nil, err
+			}
+			vi = &volumeItems{volume: v, items: items, claimed: make([]bool, len(items))}
+			volumes[v.Dir] = vi
+		}
+
+		best := -1
+		for i, it := range vi.items {
+			if vi.claimed[i] || !matches(it, rec.Path, rec.Size) {
+				continue
+			}
+			if best < 0 || it.Deleted.Sub(rec.Deleted).Abs() < vi.items[best].Deleted.Sub(rec.Deleted).Abs() {
+				best = i
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		vi.claimed[best] = true
+		it := vi.items[best]
+		if it.Original == "" {
+			it.Original = rec.Path
+		}
+		found = append(found, Trashed{Item: it, Volume: vi.volume, Entry: rec.Entry})
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Deleted.Before(found[j].Deleted) })
+	return found, nil
+}
+
+// Erase permanently deletes an item from the trash, along with its
+// .trashinfo record.
+func Erase(it Item) error {
+	if err := os.RemoveAll(it.Path); err != nil {
+		return fmt.Errorf("failed to delete %q from trash: %w", it.Name, err)
+	}
+	if info := trashInfoPath(it); info != "" {
+		_ = os.Remove(info)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTrashed(t *testing.T) {
+	v := homeVolume(t)
+	dir := t.TempDir()
+	put := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, moveToVolumeTrash(path))
+		return path
+	}
+	now := time.Now()
+	iso := put("disc.iso", "12345")
+	// The same path deleted twice leaves two items
+	put("log.txt", "first")
+	log := put("log.txt", "second")
+	// Trashed by something else
+	put("mine.txt", "not sweep's")
+	restored := filepath.Join(dir, "restored.bin")
+
+	found, err := FindTrashed([]Record{
+		{Path: iso, Size: 5, Deleted: now, Entry: "delete-1"},
+		{Path: log, Size: 5, Deleted: now, Entry: "delete-1"},
+		{Path: log, Size: 6, Deleted: now, Entry: "rule-2"},
+		{Path: restored, Size: 3, Deleted: now, Entry: "rule-2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	var originals []string
+	for _, f := range found {
+		originals = append(originals, f.Original)
+		assert.Equal(t, v.Dir, f.Volume.Dir)
+		assert.NotEmpty(t, f.Entry)
+	}
+	assert.ElementsMatch(t, []string{iso, log, log}, originals)
+
+	require.NoError(t, Erase(found[0].Item))
+	assert.NoFileExists(t, found[0].Path)
+	assert.NoFileExists(t, trashInfoPath(found[0].Item))
+	items, err := Items(v)
+	require.NoError(t, err)
+	assert.Len(t, items, 3, "only the erased item is gone")
+}
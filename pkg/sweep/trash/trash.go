@@ -18,7 +18,8 @@ const commandTimeout = 30 * time.Second
 
 // MoveToTrash moves a file or directory to the system trash.
 // On macOS: uses AppleScript to move to Trash.
-// On Linux: uses gio trash or trash-cli.
+// On Linux: uses gio trash or trash-cli, then the volume's XDG trash directory.
+// On Windows: moves it to the Recycle Bin through the shell.
 // Falls back to permanent delete if no trash available.
 func MoveToTrash(path string) error {
 	// Verify the path exists before attempting to trash it
@@ -37,6 +38,11 @@ func MoveToTrash(path string) error {
 		return moveToTrashMacOS(absPath)
 	case "linux":
 		return moveToTrashLinux(absPath)
+	case "windows":
+		if err := moveToRecycleBin(absPath); err != nil {
+			return fallbackDelete(absPath)
+		}
+		return nil
 	default:
 		return fallbackDelete(absPath)
 	}
@@ -79,7 +85,16 @@ func moveToTrashLinux(path string) error {
 		}
 	}
 
-	// Fall back to permanent delete if no trash tools available
+	// Move into the volume's trash ourselves, which also covers drives the
+	// tools refuse; delete permanently only if that fails too.
+	if err := moveToVolumeTrash(path); err == nil {
+		return nil
+	}
+	return fallbackDelete(path)
+}
+
+// Remove permanently deletes a file or directory, bypassing the trash.
+func Remove(path string) error {
 	return fallbackDelete(path)
 }
 
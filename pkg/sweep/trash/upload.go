@@ -0,0 +1,62 @@
+package trash
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// Upload is the upload backend: each file is deleted permanently once
+// Command, run with the shell (cmd on Windows), has exited successfully.
+// The command gets the path in $SWEEP_PATH and its size in $SWEEP_SIZE,
+// e.g. rclone copyto "$SWEEP_PATH" "archive:$SWEEP_PATH". A file the
+// command fails for is kept. Directories are passed as they are; the
+// command must upload everything in them.
+type Upload struct {
+	Command string
+}
+
+// Name returns BackendUpload.
+func (u Upload) Name() string { return BackendUpload }
+
+// DeleteMany uploads and then removes each path in turn.
+func (u Upload) DeleteMany(ctx context.Context, paths []string, onDone func(Result)) []Result {
+	return deleteEach(ctx, paths, onDone, func(path string) Result {
+		if err := u.upload(ctx, path); err != nil {
+			return Result{Path: path, Err: err}
+		}
+		return Result{Path: path, Err: Remove(path)}
+	})
+}
+
+// upload runs the command for path. Uploads take as long as they take, so
+// only ctx bounds them.
+func (u Upload) upload(ctx context.Context, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("cannot upload %q: %w", path, err)
+	}
+	size := info.Size()
+	if info.IsDir() {
+		size = treeSize(path)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", u.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", u.Command)
+	}
+	cmd.Env = append(os.Environ(), "SWEEP_PATH="+path, "SWEEP_SIZE="+strconv.FormatInt(size, 10))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if out = bytes.TrimSpace(out); len(out) > 0 {
+			return fmt.Errorf("upload of %q failed, keeping it: %w: %s", path, err, out)
+		}
+		return fmt.Errorf("upload of %q failed, keeping it: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrChanged is returned by Verify when a file no longer matches its snapshot,
+// which usually means it is still being written (e.g., an in-progress download).
+var ErrChanged = errors.New("file changed since selection")
+
+// Snapshot records the size and modification time of a file when it was
+// selected for deletion.
+type Snapshot struct {
+	Path    string
+	Size    int64
+	ModTime time.Time // Zero skips the modification time check
+}
+
+// Verify stats the file and reports ErrChanged if its size or modification
+// time differs from the snapshot. Times are compared at second resolution
+// because index-backed results do not carry sub-second precision.
+// Directories are not checked.
+func Verify(s Snapshot) error {
+	info, err := os.Lstat(s.Path)
+	if err != nil {
+		return fmt.Errorf("cannot verify %q: %w", s.Path, err)
+	}
+	if info.IsDir() {
+		return nil
+	}
+	if info.Size() != s.Size {
+		return fmt.Errorf("%w: %s (size %d -> %d)", ErrChanged, s.Path, s.Size, info.Size())
+	}
+	if !s.ModTime.IsZero() && info.ModTime().Unix() != s.ModTime.Unix() {
+		return fmt.Errorf("%w: %s (modified %s)", ErrChanged, s.Path, info.ModTime().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "download.part")
+	require.NoError(t, os.WriteFile(path, []byte("1234"), 0644))
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+
+	t.Run("unchanged", func(t *testing.T) {
+		assert.NoError(t, Verify(Snapshot{Path: path, Size: 4, ModTime: mtime}))
+	})
+
+	t.Run("zero mtime skips time check", func(t *testing.T) {
+		assert.NoError(t, Verify(Snapshot{Path: path, Size: 4}))
+	})
+
+	t.Run("size changed", func(t *testing.T) {
+		err := Verify(Snapshot{Path: path, Size: 2, ModTime: mtime})
+		assert.True(t, errors.Is(err, ErrChanged), "got %v", err)
+	})
+
+	t.Run("mtime changed", func(t *testing.T) {
+		err := Verify(Snapshot{Path: path, Size: 4, ModTime: mtime.Add(-time.Minute)})
+		assert.True(t, errors.Is(err, ErrChanged), "got %v", err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := Verify(Snapshot{Path: path + ".gone", Size: 4})
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrChanged))
+	})
+}
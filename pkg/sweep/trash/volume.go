@@ -0,0 +1,328 @@
+package trash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trashInfoTime is the DeletionDate layout of XDG .trashinfo files.
+const trashInfoTime = "2006-01-02T15:04:05"
+
+// ErrNoVolumeTrash is returned on platforms without per-volume trash
+// directories.
+var ErrNoVolumeTrash = errors.New("per-volume trash not supported")
+
+// Volume is a mounted filesystem and the trash directory that files on it
+// are moved to. Trashing never copies across filesystems, so each volume
+// has its own trash: the home trash for the volume holding the home
+// directory, and a hidden directory at the top of every other volume.
+type Volume struct {
+	Mount    string // Mount point
+	Dir      string // Trash directory
+	FilesDir string // Where trashed files are kept
+	InfoDir  string // XDG .trashinfo files; empty on macOS
+	Home     bool   // The user's home trash
+}
+
+// Item is a file or directory in a volume's trash.
+type Item struct {
+	Name     string    // Name inside the trash
+	Path     string    // Current path inside the trash
+	Original string    // Path before trashing, when recorded
+	Size     int64     // Total size, including directory contents
+	Deleted  time.Time // When it was trashed
+	Staged   bool      // In a staging directory rather than a trash
+}
+
+// VolumeOf returns the volume holding path and its trash directory.
+// The trash directory need not exist yet.
+func VolumeOf(path string) (Volume, error) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return Volume{}, ErrNoVolumeTrash
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	// The item moves within its parent's filesystem, which matters when
+	// path is itself a mount point.
+	mount, dev, err := mountOf(filepath.Dir(abs))
+	if err != nil {
+		return Volume{}, err
+	}
+
+	if home, err := homeTrash(); err == nil {
+		if homeMount, homeDev, err := mountOf(existingAncestor(home.Dir)); err == nil && homeDev == dev {
+			home.Mount = homeMount
+			return home, nil
+		}
+	}
+	return topTrash(mount), nil
+}
+
+// homeTrash returns the user's home trash.
+func homeTrash() (Volume, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Volume{}, err
+	}
+	if runtime.GOOS == "darwin" {
+		dir := filepath.Join(home, ".Trash")
+		return Volume{Dir: dir, FilesDir: dir, Home: true}, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "Trash")
+	return Volume{
+		Dir:      dir,
+		FilesDir: filepath.Join(dir, "files"),
+		InfoDir:  filepath.Join(dir, "info"),
+		Home:     true,
+	}, nil
+}
+
+// topTrash returns the trash at the top of a volume other than the home
+// volume, following the XDG trash specification on Linux.
+func topTrash(mount string) Volume {
+	uid := strconv.Itoa(os.Getuid())
+	if runtime.GOOS == "darwin" {
+		dir := filepath.Join(mount, ".Trashes", uid)
+		return Volume{Mount: mount, Dir: dir, FilesDir: dir}
+	}
+
+	// An administrator-created, sticky $topdir/.Trash takes precedence.
+	dir := filepath.Join(mount, ".Trash-"+uid)
+	shared := filepath.Join(mount, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dir = filepath.Join(shared, uid)
+	}
+	return Volume{
+		Mount:    mount,
+		Dir:      dir,
+		FilesDir: filepath.Join(dir, "files"),
+		InfoDir:  filepath.Join(dir, "info"),
+	}
+}
+
+// mountOf returns the mount point holding dir and the volume's device ID.
+func mountOf(dir string) (string, uint64, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", 0, err
+	}
+	dev, ok := device(info)
+	if !ok {
+		return "", 0, ErrNoVolumeTrash
+	}
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, dev, nil
+		}
+		info, err := os.Stat(parent)
+		if err != nil {
+			return "", 0, err
+		}
+		if d, _ := device(info); d != dev {
+			return dir, dev, nil
+		}
+		dir = parent
+	}
+}
+
+// existingAncestor returns path or its closest existing parent.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// Usage returns the total size of the files in a volume's trash.
+// A trash that does not exist yet is empty.
+func Usage(v Volume) (int64, error) {
+	items, err := Items(v)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, it := range items {
+		total += it.Size
+	}
+	return total, nil
+}
+
+// Items lists the contents of a volume's trash, oldest first.
+func Items(v Volume) ([]Item, error) {
+	entries, err := os.ReadDir(v.FilesDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read trash %q: %w", v.FilesDir, err)
+	}
+
+	items := make([]Item, 0, len(entries))
+	for _, e := range entries {
+		if v.InfoDir == "" && e.Name() == ".DS_Store" {
+			continue
+		}
+		it := Item{Name: e.Name(), Path: filepath.Join(v.FilesDir, e.Name()), Size: treeSize(filepath.Join(v.FilesDir, e.Name()))}
+		if info, err := e.Info(); err == nil {
+			it.Deleted = changeTime(info)
+		}
+		if v.InfoDir != "" {
+			readTrashInfo(v, &it)
+		}
+		items = append(items, it)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Deleted.Before(items[j].Deleted) })
+	return items, nil
+}
+
+// Purge removes the oldest items from a volume's trash until at least need
+// bytes are freed or the trash is empty. It returns the bytes freed.
+func Purge(v Volume, need int64) (int64, error) {
+	items, err := Items(v)
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	for _, it := range items {
+		if freed >= need {
+			break
+		}
+		if err := Erase(it); err != nil {
+			return freed, err
+		}
+		freed += it.Size
+	}
+	return freed, nil
+}
+
+// readTrashInfo fills in the original path and deletion date of an item
+// from its XDG .trashinfo file, when present.
+func readTrashInfo(v Volume, it *Item) {
+	path := filepath.Join(v.InfoDir, it.Name+".trashinfo")
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			if p, err := url.PathUnescape(value); err == nil {
+				value = p
+			}
+			if !filepath.IsAbs(value) {
+				value = filepath.Join(v.Mount, value)
+			}
+			it.Original = value
+		case "DeletionDate":
+			if t, err := time.ParseInLocation(trashInfoTime, value, time.Local); err == nil {
+				it.Deleted = t
+			}
+		}
+	}
+}
+
+// moveToVolumeTrash moves path into its volume's trash following the XDG
+// trash specification. It is the fallback when no trash tool is installed
+// or the tool refuses a volume, such as a removable drive.
+func moveToVolumeTrash(path string) error {
+	v, err := VolumeOf(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(v.FilesDir, 0o700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(v.InfoDir, 0o700); err != nil {
+		return err
+	}
+
+	// Paths on the home volume are recorded in full, others relative to
+	// the volume so the record survives the drive being mounted elsewhere.
+	recorded := path
+	if !v.Home {
+		if rel, err := filepath.Rel(v.Mount, path); err == nil {
+			recorded = rel
+		}
+	}
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: recorded}).EscapedPath(), time.Now().Format(trashInfoTime))
+
+	base := filepath.Base(path)
+	for n := 1; n < 1000; n++ {
+		name := base
+		if n > 1 {
+			name = fmt.Sprintf("%s.%d", base, n)
+		}
+		infoPath := filepath.Join(v.InfoDir, name+".trashinfo")
+
+		// Creating the info file exclusively reserves the name.
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, werr := f.WriteString(info)
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr == nil {
+			werr = os.Rename(path, filepath.Join(v.FilesDir, name))
+		}
+		if werr != nil {
+			_ = os.Remove(infoPath)
+			return werr
+		}
+		return nil
+	}
+	return fmt.Errorf("no free name in trash for %q", base)
+}
+
+// treeSize returns the total size of the regular files under path.
+func treeSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
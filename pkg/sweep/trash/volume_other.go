@@ -0,0 +1,10 @@
+//go:build !unix
+
+package trash
+
+import "io/fs"
+
+// device is unavailable on platforms without Unix device IDs.
+func device(fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,150 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain points the home trash at a temporary directory so tests that
+// fall back to the XDG trash don't fill the real one.
+func TestMain(m *testing.M) {
+	dataHome, err := os.MkdirTemp("", "sweep-trash-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_DATA_HOME", dataHome)
+	code := m.Run()
+	os.RemoveAll(dataHome)
+	os.Exit(code)
+}
+
+// homeVolume returns a fresh home trash on the same volume as t.TempDir.
+func homeVolume(t *testing.T) Volume {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG trash is Linux only")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	v, err := VolumeOf(filepath.Join(t.TempDir(), "file"))
+	require.NoError(t, err)
+	require.True(t, v.Home, "temp dirs should share the home trash's volume")
+	return v
+}
+
+func TestVolumeOf(t *testing.T) {
+	v := homeVolume(t)
+	assert.Equal(t, filepath.Join(os.Getenv("XDG_DATA_HOME"), "Trash"), v.Dir)
+	assert.Equal(t, filepath.Join(v.Dir, "files"), v.FilesDir)
+	assert.Equal(t, filepath.Join(v.Dir, "info"), v.InfoDir)
+	assert.NotEmpty(t, v.Mount)
+}
+
+func TestTopTrash(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG trash is Linux only")
+	}
+	mount := t.TempDir()
+	uid := strconv.Itoa(os.Getuid())
+
+	v := topTrash(mount)
+	assert.Equal(t, filepath.Join(mount, ".Trash-"+uid), v.Dir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(mount, ".Trash"), 0o777|os.ModeSticky))
+	require.NoError(t, os.Chmod(filepath.Join(mount, ".Trash"), 0o777|os.ModeSticky))
+	v = topTrash(mount)
+	assert.Equal(t, filepath.Join(mount, ".Trash", uid), v.Dir, "a sticky shared .Trash takes precedence")
+}
+
+func TestMoveToVolumeTrash(t *testing.T) {
+	v := homeVolume(t)
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a", "report.pdf")
+	second := filepath.Join(dir, "b", "report.pdf")
+	for _, p := range []string{first, second} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte("12345"), 0o644))
+	}
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, moveToVolumeTrash(first))
+	require.NoError(t, moveToVolumeTrash(second))
+
+	_, err := os.Stat(first)
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, filepath.Join(v.FilesDir, "report.pdf"))
+	assert.FileExists(t, filepath.Join(v.FilesDir, "report.pdf.2"), "name collisions get a suffix")
+
+	items, err := Items(v)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	originals := []string{items[0].Original, items[1].Original}
+	assert.ElementsMatch(t, []string{first, second}, originals)
+	assert.False(t, items[0].Deleted.Before(before))
+
+	used, err := Usage(v)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), used)
+}
+
+func TestPurge(t *testing.T) {
+	v := homeVolume(t)
+	require.NoError(t, os.MkdirAll(v.FilesDir, 0o700))
+	require.NoError(t, os.MkdirAll(v.InfoDir, 0o700))
+
+	put := func(name string, size int, deleted string) {
+		require.NoError(t, os.WriteFile(filepath.Join(v.FilesDir, name), make([]byte, size), 0o600))
+		info := "[Trash Info]\nPath=/old/" + name + "\nDeletionDate=" + deleted + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(v.InfoDir, name+".trashinfo"), []byte(info), 0o600))
+	}
+	put("newest", 100, "2025-03-01T10:00:00")
+	put("oldest", 100, "2024-01-01T10:00:00")
+	put("middle", 100, "2024-06-01T10:00:00")
+
+	freed, err := Purge(v, 150)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), freed)
+
+	items, err := Items(v)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "newest", items[0].Name)
+	assert.Equal(t, "/old/newest", items[0].Original)
+	assert.NoFileExists(t, filepath.Join(v.InfoDir, "oldest.trashinfo"))
+}
+
+func TestCheckQuota(t *testing.T) {
+	v := homeVolume(t)
+	require.NoError(t, os.MkdirAll(v.FilesDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(v.FilesDir, "old"), make([]byte, 800), 0o600))
+
+	target := Snapshot{Path: filepath.Join(t.TempDir(), "big"), Size: 500}
+
+	over, err := CheckQuota(Quota{}, []Snapshot{target})
+	require.NoError(t, err)
+	assert.Empty(t, over, "no quota, no overage")
+
+	over, err = CheckQuota(Quota{Default: 1000}, []Snapshot{target})
+	require.NoError(t, err)
+	require.Len(t, over, 1)
+	assert.Equal(t, int64(800), over[0].Used)
+	assert.Equal(t, int64(500), over[0].Incoming)
+	assert.Equal(t, int64(300), over[0].Excess())
+	assert.True(t, over[0].Purgeable())
+	assert.Equal(t, []string{target.Path}, over[0].Paths)
+
+	over, err = CheckQuota(Quota{Default: 1000, Volumes: map[string]int64{v.Mount: 400}}, []Snapshot{target})
+	require.NoError(t, err)
+	require.Len(t, over, 1)
+	assert.False(t, over[0].Purgeable(), "files larger than the quota can't be trashed")
+
+	over, err = CheckQuota(Quota{Default: 2000}, []Snapshot{target})
+	require.NoError(t, err)
+	assert.Empty(t, over)
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package trash
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// device returns the ID of the device holding a file.
+func device(info fs.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true //nolint:unconvert // Dev is int32 on darwin
+}
@@ -19,6 +19,18 @@ const (
 	maxQueueSize = 100000
 )
 
+// Worker limits on network file systems, where each stat is a round trip
+// to the server, so the CPU-based counts would flood it with requests.
+const (
+	// DefaultNetworkDirWorkers is the directory workers scanning a network
+	// file system use.
+	DefaultNetworkDirWorkers = 4
+
+	// DefaultNetworkFileWorkers is the file workers scanning a network
+	// file system use.
+	DefaultNetworkFileWorkers = 2
+)
+
 // Memory-based queue sizing constants.
 const (
 	// bytesPerQueueEntry estimates memory per queue entry.
@@ -98,6 +110,22 @@ func CalculateWithOverrides(resources SystemResources, workerOverride int) Optim
 	return config
 }
 
+// ForNetwork lowers the workers in config to dirWorkers and fileWorkers
+// for scanning a network file system, using DefaultNetworkDirWorkers and
+// DefaultNetworkFileWorkers for those that are 0 or negative. Counts
+// already lower are kept.
+func (c OptimalConfig) ForNetwork(dirWorkers, fileWorkers int) OptimalConfig {
+	if dirWorkers <= 0 {
+		dirWorkers = DefaultNetworkDirWorkers
+	}
+	if fileWorkers <= 0 {
+		fileWorkers = DefaultNetworkFileWorkers
+	}
+	c.DirWorkers = min(c.DirWorkers, dirWorkers)
+	c.FileWorkers = min(c.FileWorkers, fileWorkers)
+	return c
+}
+
 // calculateQueueSize determines queue size based on available memory.
 func calculateQueueSize(availableRAM int64) int {
 	// Calculate how much memory we can dedicate to queues
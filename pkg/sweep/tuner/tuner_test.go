@@ -239,6 +239,33 @@ func TestCalculateWithOverrides(t *testing.T) {
 	}
 }
 
+func TestForNetwork(t *testing.T) {
+	config := Calculate(SystemResources{CPUCores: 8, AvailableRAM: 8 * 1024 * 1024 * 1024})
+
+	tests := []struct {
+		name            string
+		dir, file       int
+		wantDirWorkers  int
+		wantFileWorkers int
+	}{
+		{name: "defaults", wantDirWorkers: DefaultNetworkDirWorkers, wantFileWorkers: DefaultNetworkFileWorkers},
+		{name: "configured", dir: 6, file: 3, wantDirWorkers: 6, wantFileWorkers: 3},
+		{name: "never raised", dir: 100, file: 100, wantDirWorkers: 8, wantFileWorkers: 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := config.ForNetwork(tt.dir, tt.file)
+			if got.DirWorkers != tt.wantDirWorkers || got.FileWorkers != tt.wantFileWorkers {
+				t.Errorf("ForNetwork(%d, %d) workers = %d, %d; want %d, %d",
+					tt.dir, tt.file, got.DirWorkers, got.FileWorkers, tt.wantDirWorkers, tt.wantFileWorkers)
+			}
+			if got.DirQueueSize != config.DirQueueSize {
+				t.Errorf("ForNetwork changed the queue size to %d", got.DirQueueSize)
+			}
+		})
+	}
+}
+
 func TestCalculate_Integration(t *testing.T) {
 	// Use actual detected resources
 	resources, err := Detect()
@@ -13,6 +13,9 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/jamesainslie/sweep/pkg/sweep/cloud"
+	"github.com/jamesainslie/sweep/pkg/sweep/owner"
+	"github.com/jamesainslie/sweep/pkg/sweep/sharing"
 )
 
 // Size constants for binary (IEC) units.
@@ -46,7 +49,18 @@ type FileInfo struct {
 	Owner string `json:"owner"`
 
 	// Group is the group name of the file's group.
+	//
+	// Scans leave Owner and Group empty; scanner.LookupOwners resolves
+	// them for the files a caller keeps.
 	Group string `json:"group"`
+
+	// Sharing is set when the file shares storage with other files through
+	// hard links or APFS clones, so deleting it may free less than Size.
+	Sharing *sharing.Info `json:"sharing,omitempty"`
+
+	// Cloud is set when the file is an online-only placeholder of a cloud
+	// storage provider, which stores little or none of Size locally.
+	Cloud *cloud.Info `json:"cloud,omitempty"`
 }
 
 // HumanSize returns the file size formatted as a human-readable string.
@@ -71,11 +85,86 @@ type ScanResult struct {
 	// TotalSize is the sum of all file sizes in bytes.
 	TotalSize int64 `json:"total_size"`
 
+	// ActualSize is TotalSize with storage shared through hard links and
+	// APFS clones counted once, and online-only cloud files counted at what
+	// they store locally.
+	ActualSize int64 `json:"actual_size"`
+
 	// Elapsed is the total time taken to complete the scan.
 	Elapsed time.Duration `json:"elapsed"`
 
 	// Errors contains any errors encountered during scanning.
 	Errors []ScanError `json:"errors,omitempty"`
+
+	// Skipped counts the files and directories left out of Files, by
+	// reason. A skipped directory counts once, whatever it holds.
+	Skipped map[SkipReason]int64 `json:"skipped,omitempty"`
+
+	// SkippedPaths lists each skipped file and directory with its reason,
+	// when the scan was asked to record them.
+	SkippedPaths []SkippedPath `json:"skipped_paths,omitempty"`
+}
+
+// SkipReason says why a scan left a file or directory out of its results.
+type SkipReason string
+
+// Reasons a scan skips files and directories.
+const (
+	SkipExcluded       SkipReason = "excluded"          // Matched an exclude pattern
+	SkipBelowMinSize   SkipReason = "below_min_size"    // A file smaller than the minimum size
+	SkipPermission     SkipReason = "permission_denied" // Could not be read
+	SkipSymlink        SkipReason = "symlink"           // A symlink not followed: to a file, dangling, or by policy
+	SkipSymlinkLoop    SkipReason = "symlink_loop"      // A symlink to an ancestor, or to a tree already followed
+	SkipOutsideRoot    SkipReason = "outside_root"      // A symlink out of the root, under the within-root policy
+	SkipDeviceBoundary SkipReason = "device_boundary"   // A mount point not entered
+	SkipOwner          SkipReason = "owner"             // Not owned by, or not enterable by, the owner filter's user
+	SkipSpecial        SkipReason = "special"           // Not a regular file: a socket, pipe, or device
+	SkipSnapshot       SkipReason = "snapshot"          // A filesystem snapshot not entered
+)
+
+// SkipReasons lists every SkipReason, in the order breakdowns show them.
+var SkipReasons = []SkipReason{
+	SkipBelowMinSize, SkipExcluded, SkipPermission, SkipSymlink,
+	SkipSymlinkLoop, SkipOutsideRoot, SkipDeviceBoundary, SkipSnapshot,
+	SkipOwner, SkipSpecial,
+}
+
+// Describe returns the reason in words, e.g. "below min size".
+func (r SkipReason) Describe() string {
+	return strings.ReplaceAll(string(r), "_", " ")
+}
+
+// SymlinkPolicy says which symlinks to directories a scan follows. Symlinks
+// to files are never followed, as the files are counted where they are.
+type SymlinkPolicy string
+
+// Symlink policies.
+const (
+	SymlinksSkip       SymlinkPolicy = "skip"        // Follow none (default)
+	SymlinksWithinRoot SymlinkPolicy = "within-root" // Follow those to directories under the root
+	SymlinksFollow     SymlinkPolicy = "follow"      // Follow all
+)
+
+// ErrUnknownSymlinkPolicy is returned by ParseSymlinkPolicy for unsupported
+// policies.
+var ErrUnknownSymlinkPolicy = errors.New("unknown symlink policy")
+
+// ParseSymlinkPolicy parses a symlink policy; "" means SymlinksSkip.
+func ParseSymlinkPolicy(s string) (SymlinkPolicy, error) {
+	switch p := SymlinkPolicy(s); p {
+	case "":
+		return SymlinksSkip, nil
+	case SymlinksSkip, SymlinksWithinRoot, SymlinksFollow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (available: skip, within-root, follow)", ErrUnknownSymlinkPolicy, s)
+	}
+}
+
+// SkippedPath is a file or directory a scan skipped, and why.
+type SkippedPath struct {
+	Path   string     `json:"path"`
+	Reason SkipReason `json:"reason"`
 }
 
 // ScanError represents an error encountered during scanning.
@@ -107,6 +196,30 @@ type ScanOptions struct {
 
 	// FileWorkers is the number of concurrent workers for file stat operations.
 	FileWorkers int `json:"file_workers"`
+
+	// Owner, if set, limits results to files owned by one user.
+	Owner *owner.Filter `json:"-"`
+
+	// SkipMounts are mount points not to enter, such as bind mounts whose
+	// content is reachable elsewhere under Root.
+	SkipMounts []string `json:"skip_mounts,omitempty"`
+
+	// OneFileSystem keeps the scan on Root's file system, not entering
+	// others mounted under it, except at CrossMounts.
+	OneFileSystem bool     `json:"one_file_system,omitempty"`
+	CrossMounts   []string `json:"cross_mounts,omitempty"`
+
+	// IncludeSnapshots enters filesystem snapshot directories, such as
+	// .zfs/snapshot, which are otherwise skipped as copies of data counted
+	// elsewhere.
+	IncludeSnapshots bool `json:"include_snapshots,omitempty"`
+
+	// RecordSkipped lists each skipped path in the result, not just the
+	// counts.
+	RecordSkipped bool `json:"-"`
+
+	// Symlinks says which symlinked directories to follow.
+	Symlinks SymlinkPolicy `json:"symlinks,omitempty"`
 }
 
 // ScanProgress reports real-time scan progress.
@@ -130,6 +243,10 @@ type ScanProgress struct {
 	// WalkComplete indicates that directory traversal is finished.
 	// The TUI uses this to freeze the displayed elapsed time.
 	WalkComplete bool `json:"walk_complete,omitempty"`
+
+	// EntriesExpected is how many directories and files the scan expects
+	// to examine in all, or 0 if it can't tell.
+	EntriesExpected int64 `json:"entries_expected,omitempty"`
 }
 
 // sizePattern matches size strings like "100M", "2G", "500K", "1.5GB", etc.
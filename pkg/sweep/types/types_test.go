@@ -1,6 +1,7 @@
 package types
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -121,3 +122,15 @@ func TestFileInfo_HumanSize(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSymlinkPolicy(t *testing.T) {
+	for in, want := range map[string]SymlinkPolicy{"": SymlinksSkip, "skip": SymlinksSkip, "within-root": SymlinksWithinRoot, "follow": SymlinksFollow} {
+		got, err := ParseSymlinkPolicy(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSymlinkPolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSymlinkPolicy("all"); !errors.Is(err, ErrUnknownSymlinkPolicy) {
+		t.Errorf("ParseSymlinkPolicy(all) error = %v, want ErrUnknownSymlinkPolicy", err)
+	}
+}
@@ -32,6 +32,7 @@ const (
 	daemonBinaryName = "sweepd"
 	mainPkg          = "./cmd/sweep"
 	daemonPkg        = "./cmd/sweepd"
+	liteBinaryName   = "sweep-lite"
 	binDir           = "bin"
 )
 
@@ -117,6 +118,39 @@ func BuildDaemon() error {
 	return sh.RunV("go", "build", "-ldflags", ldflags, "-o", output, daemonPkg)
 }
 
+// BuildDaemonSQLite compiles sweepd with the SQLite store backend
+// (daemon.store_backend: sqlite), which needs cgo and a C compiler.
+func BuildDaemonSQLite() error {
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		return fmt.Errorf("creating bin directory: %w", err)
+	}
+
+	ldflags := buildLdflags()
+	output := filepath.Join(binDir, daemonBinaryName)
+	if runtime.GOOS == "windows" {
+		output += ".exe"
+	}
+
+	env := map[string]string{"CGO_ENABLED": "1"}
+	return sh.RunWithV(env, "go", "build", "-tags", "sqlite", "-ldflags", ldflags, "-o", output, daemonPkg)
+}
+
+// BuildLite compiles sweep-lite, a TUI-only sweep without daemon, gRPC, or
+// index store support, for rescue images and containers.
+func BuildLite() error {
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		return fmt.Errorf("creating bin directory: %w", err)
+	}
+
+	ldflags := buildLdflags()
+	output := filepath.Join(binDir, liteBinaryName)
+	if runtime.GOOS == "windows" {
+		output += ".exe"
+	}
+
+	return sh.RunV("go", "build", "-tags", "lite", "-ldflags", ldflags, "-o", output, mainPkg)
+}
+
 // Install builds and installs both sweep and sweepd to ~/.local/bin (or GOBIN if set).
 func Install() error {
 	st.Deps(InstallCLI, InstallDaemon)